@@ -0,0 +1,26 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adminext
+
+import (
+	// the OpenAPI description is embedded
+	_ "embed"
+)
+
+// Swagger is the OpenAPI 2.0 description of the REST mapping of the service, generated from the
+// google.api.http annotations of adminext.proto
+//
+//go:embed adminext.swagger.json
+var Swagger []byte
@@ -0,0 +1,12 @@
+#!/bin/sh
+
+proto_imports=".:${GOPATH}/src/github.com/gogo/protobuf/protobuf:${GOPATH}/src/github.com/gogo/protobuf:${GOPATH}/src/github.com/grpc-ecosystem/grpc-gateway/third_party/googleapis:${GOPATH}/src"
+
+protoc -I=$proto_imports --gogofaster_out=import_path=github.com/onosproject/onos-config/api/adminext,Mgoogle/protobuf/timestamp.proto=github.com/gogo/protobuf/types,Mgoogle/protobuf/duration.proto=github.com/gogo/protobuf/types,Mgoogle/protobuf/wrappers.proto=github.com/gogo/protobuf/types,plugins=grpc:. api/adminext/*.proto
+
+protoc -I=$proto_imports --gogofaster_out=import_path=github.com/onosproject/onos-config/api/admin/v2,Mgoogle/protobuf/timestamp.proto=github.com/gogo/protobuf/types,Mgoogle/protobuf/duration.proto=github.com/gogo/protobuf/types,plugins=grpc:. api/admin/v2/*.proto
+
+# The REST mapping of the services to the resources of the gateway, and its OpenAPI description
+protoc -I=$proto_imports --grpc-gateway_out=logtostderr=true,import_path=github.com/onosproject/onos-config/api/adminext:. --swagger_out=logtostderr=true:. api/adminext/*.proto
+
+protoc -I=$proto_imports --grpc-gateway_out=logtostderr=true,import_path=github.com/onosproject/onos-config/api/admin/v2:. --swagger_out=logtostderr=true:. api/admin/v2/*.proto
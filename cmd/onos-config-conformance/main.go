@@ -0,0 +1,120 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package onos-config-conformance runs the gNMI conformance suite against a running northbound
+endpoint and reports the outcome of each check. It exits with a non-zero status if any check fails.
+
+Arguments
+-address <the address of the gNMI endpoint>
+
+-caPath <the location of a CA certificate>
+
+-keyPath <the location of a client private key>
+
+-certPath <the location of a client certificate>
+
+-insecure <skip verification of the endpoint's certificate>
+
+-token <a bearer token sent with every request>
+
+-target <the device the checks get, set and subscribe to>
+
+-path <a writable string leaf of the target; the checks that set a value are skipped without it>
+
+-value <the value the checks set on the path>
+
+-timeout <how long each check may take>
+
+-output <the format of the report: text or json>
+
+See ../../docs/gnmi.md for how the suite is run.
+*/
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"time"
+
+	"github.com/onosproject/onos-config/pkg/conformance"
+	"github.com/onosproject/onos-lib-go/pkg/certs"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var log = logging.GetLogger("main")
+
+// The main entry point
+func main() {
+	address := flag.String("address", "onos-config:5150", "address of the gNMI endpoint")
+	caPath := flag.String("caPath", "", "path to CA certificate")
+	keyPath := flag.String("keyPath", "", "path to client private key")
+	certPath := flag.String("certPath", "", "path to client certificate")
+	insecure := flag.Bool("insecure", false, "skip verification of the endpoint's certificate")
+	token := flag.String("token", "", "bearer token sent with every request")
+	target := flag.String("target", "", "device the checks get, set and subscribe to")
+	path := flag.String("path", "", "writable string leaf of the target; the checks that set a value are skipped without it")
+	value := flag.String("value", "onos-config-conformance", "value the checks set on the path")
+	timeout := flag.Duration("timeout", 10*time.Second, "how long each check may take")
+	output := flag.String("output", "text", "format of the report: text or json")
+	flag.Parse()
+
+	if *output != "text" && *output != "json" {
+		log.Fatalf("Invalid output %s; expected text or json", *output)
+	}
+
+	opts, err := certs.HandleCertPaths(*caPath, *keyPath, *certPath, *insecure)
+	if err != nil {
+		log.Fatal(err)
+	}
+	conn, err := grpc.Dial(*address, opts...)
+	if err != nil {
+		log.Fatalf("Cannot connect to %s: %v", *address, err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	if *token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "bearer "+*token)
+	}
+	report, err := conformance.Run(ctx, gnmi.NewGNMIClient(conn), conformance.Config{
+		Target:  *target,
+		Path:    *path,
+		Value:   *value,
+		Timeout: *timeout,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !report.OK() {
+		conn.Close()
+		os.Exit(1)
+	}
+}
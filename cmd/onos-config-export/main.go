@@ -0,0 +1,143 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Package onos-config-export exports the configuration history of devices from a running onos-config
+as archives of gNMI notifications, one file per device, that gNMI tooling can replay.
+
+Arguments
+-address <the address of the onos-config admin endpoint>
+
+-caPath <the location of a CA certificate>
+
+-keyPath <the location of a client private key>
+
+-certPath <the location of a client certificate>
+
+-insecure <skip verification of the endpoint's certificate>
+
+-token <a bearer token sent with every request>
+
+-devices <comma separated devices to export, each optionally followed by :version>
+
+-output <the directory the archives are written to>
+
+See ../../docs/adminext.md for the format of the archives.
+*/
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/archive"
+	"github.com/onosproject/onos-lib-go/pkg/certs"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var log = logging.GetLogger("main")
+
+// The main entry point
+func main() {
+	address := flag.String("address", "onos-config:5150", "address of the onos-config admin endpoint")
+	caPath := flag.String("caPath", "", "path to CA certificate")
+	keyPath := flag.String("keyPath", "", "path to client private key")
+	certPath := flag.String("certPath", "", "path to client certificate")
+	insecure := flag.Bool("insecure", false, "skip verification of the endpoint's certificate")
+	token := flag.String("token", "", "bearer token sent with every request")
+	devices := flag.String("devices", "", "comma separated devices to export, each optionally followed by :version")
+	output := flag.String("output", ".", "directory the archives are written to")
+	flag.Parse()
+
+	if *devices == "" {
+		log.Fatal("No device to export; expected -devices")
+	}
+
+	opts, err := certs.HandleCertPaths(*caPath, *keyPath, *certPath, *insecure)
+	if err != nil {
+		log.Fatal(err)
+	}
+	conn, err := grpc.Dial(*address, opts...)
+	if err != nil {
+		log.Fatalf("Cannot connect to %s: %v", *address, err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	if *token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "bearer "+*token)
+	}
+	client := adminext.NewConfigAdminExtServiceClient(conn)
+	failed := false
+	for _, device := range strings.Split(*devices, ",") {
+		id, version := device, ""
+		if i := strings.LastIndex(device, ":"); i >= 0 {
+			id, version = device[:i], device[i+1:]
+		}
+		file := filepath.Join(*output, strings.ReplaceAll(device, ":", "-")+".gnmi")
+		count, err := export(ctx, client, id, version, file)
+		if err != nil {
+			log.Errorf("Cannot export device %s: %v", device, err)
+			failed = true
+			continue
+		}
+		log.Infof("Exported %d notifications of device %s to %s", count, device, file)
+	}
+	if failed {
+		conn.Close()
+		os.Exit(1)
+	}
+}
+
+// export writes the archive of the history of a device to a file, returning the number of
+// notifications written
+func export(ctx context.Context, client adminext.ConfigAdminExtServiceClient, id string, version string, file string) (int, error) {
+	stream, err := client.ExportDeviceChanges(ctx, &adminext.ExportDeviceChangesRequest{
+		DeviceId:      id,
+		DeviceVersion: version,
+	})
+	if err != nil {
+		return 0, err
+	}
+	// the first response tells whether the export failed, before the file is created
+	notification, recvErr := stream.Recv()
+	if recvErr != nil && recvErr != io.EOF {
+		return 0, recvErr
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	writer := archive.NewWriter(f)
+	count := 0
+	for ; recvErr == nil; notification, recvErr = stream.Recv() {
+		if err := writer.WriteEncoded(notification.Notification); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if recvErr != io.EOF {
+		return count, recvErr
+	}
+	return count, f.Close()
+}
@@ -30,6 +30,98 @@ Arguments
 
 -certPath <the location of a client certificate>
 
+-graphqlPort <the port of the optional GraphQL query endpoint; disabled if 0>
+
+-gatewayPort <the port of the optional REST/JSON gateway to the admin services; disabled if 0>
+
+-metricsPort <the port of the optional HTTP endpoint of the Prometheus metrics, at /metrics; disabled if 0>
+
+-sensitivePaths <comma separated paths whose values are redacted from northbound Get and Subscribe>
+
+-protectedPaths <comma separated subtrees that only the change-protected-paths group may change with gNMI Set>
+
+-signingKeysPath <a directory of PEM encoded public keys that Set request signatures are verified against>
+
+-requireSignedChanges <reject Set requests that are not signed>
+
+-authInterceptors <comma separated, ordered chain of northbound interceptors: jwt, mtls, apikey, authz>
+
+-apiKeysPath <the location of the YAML file of API keys used by the apikey interceptor>
+
+-certIdentitiesPath <the location of the YAML file of the groups of client certificates used by the mtls interceptor>
+
+-authzURL <the URL of the external authorization service used by the authz interceptor>
+
+-trustBundleKeyPath <the location of the base64 encoded key that the private keys of trust bundles are encrypted with>
+
+-readThroughGet <read paths that have no value in the stores from the device itself on Get>
+
+-deviceGroupsPath <the location of the YAML file of device groups that snapshots can be scoped to>
+
+-deviceTypesPath <the location of the YAML file tuning the gNMI features of the device types>
+
+-translationsPath <the location of the YAML file mapping vendor-neutral paths to the native models of the device types>
+
+-closedLoopRulesPath <the location of the YAML file of the rules changing the configuration of the devices whose state meets a condition>
+
+-externalValidatorsPath <the location of the YAML file of the external services asked to validate the changes before they are made>
+
+-squashChanges <store only the final value of each path a gNMI Set writes, auditing the values it replaced>
+
+-recordNoOpSets <create a network change for a gNMI Set that leaves the configuration as it is>
+
+-setValidation <how strictly a gNMI Set is validated against the model: strict, schema-only or none>
+
+-setAtomicity <what a gNMI Set does when some of its devices reject it: all-or-nothing or best-effort>
+
+-maxUpdatesPerNotification <the most updates of a notification of a gNMI Subscribe with mode ONCE or POLL>
+
+-maxGetResponseBytes <the largest gNMI Get response that is not paged, in bytes>
+
+-snapshotDeltas <the number of snapshots of a device that only store what changed after a full snapshot; 0 stores every snapshot in full>
+
+-stuckChangeTimeout <how long a pending network change may make no progress before the watchdog escalates it; disabled if 0>
+
+-stuckChangeAction <what the watchdog does with a stuck network change: flag, retry or cancel>
+
+-maxDevices <the most devices onos-config connects to; unlimited if 0>
+
+-maxPendingChanges <the most network changes pending at once, beyond which gNMI Set is rejected; unlimited if 0>
+
+-maxStoredChanges <the most network changes stored until compacted, beyond which gNMI Set is rejected; unlimited if 0>
+
+-maxConcurrentSets <the most gNMI Sets issued to the devices at once, granted round-robin across the devices; unlimited if 0>
+
+-targetConcurrency <the most gNMI requests issued to a device at once, the others waiting in its queue; unlimited if 0>
+
+-targetRequestTimeout <how long a gNMI request to a device may take, waiting in its queue included; only bounded by its caller if 0>
+
+-reconnectInterval <how long to wait before reconnecting to a device whose connection dropped, doubling after each failed attempt>
+
+-maxReconnectInterval <the longest wait between two attempts to reconnect to a device>
+
+-latencySLO <how long a network change should take to complete on each device; changes taking longer count against the device>
+
+-anomalyRatio <how many times more often than usual a path must change to be flagged as anomalous; no detection if 0>
+
+-modelCheckInterval <how often the models reported by the connected devices are compared with their plugins; only when they connect if 0>
+
+-resolveInterval <how often the addresses of the connected devices are resolved again, to reconnect to the devices that moved; only when they connect if 0>
+
+-storeProbeInterval <how often the Atomix primitives of the stores are read to check their health; only by their own operations if 0>
+
+-zone <the zone of this replica, for the devices preferring their master in a zone; defaults to $ZONE>
+
+-recordRequests <the number of the last gNMI Set and Get requests recorded with their responses for replay; disabled if 0>
+-deviceOperations <the number of the last gNMI Sets and Gets issued to each device kept in its operation log; disabled if 0>
+-stateShards <the number of shards of the operational state of the devices, each with its own lock and worker; defaults to the number of CPUs if 0>
+-subscriptionQueue <the number of operational state events queued for each northbound gNMI subscription, beyond which its events are dropped rather than holding up the others; unqueued if 0>
+
+-stateBudgetMiB <the memory budget of the operational state cache in MiB, over which the state read least recently is evicted; unbounded if 0>
+
+-storageKeysPath <a directory of base64 encoded keys that the stored changes and snapshots are encrypted with; stored in the clear if empty>
+
+-storageKeyID <the ID of the key of storageKeysPath that changes and snapshots are encrypted with; optional if it holds a single key>
 
 See ../../docs/run.md for how to run the application.
 */
@@ -37,28 +129,71 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"github.com/onosproject/onos-config/pkg/modelregistry"
 	"github.com/onosproject/onos-lib-go/pkg/cluster"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/atomix/atomix-go-client/pkg/atomix"
+	"github.com/onosproject/onos-config/pkg/capacity"
+	"github.com/onosproject/onos-config/pkg/closedloop"
+	"github.com/onosproject/onos-config/pkg/controller/change/anomaly"
+	"github.com/onosproject/onos-config/pkg/controller/change/watchdog"
+	"github.com/onosproject/onos-config/pkg/devicegroup"
 	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound"
 	"github.com/onosproject/onos-config/pkg/northbound/admin"
 	"github.com/onosproject/onos-config/pkg/northbound/diags"
+	"github.com/onosproject/onos-config/pkg/northbound/gateway"
 	"github.com/onosproject/onos-config/pkg/northbound/gnmi"
+	"github.com/onosproject/onos-config/pkg/northbound/graphql"
+	"github.com/onosproject/onos-config/pkg/northbound/grpcerrors"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/northbound/readonly"
+	"github.com/onosproject/onos-config/pkg/northbound/recorder"
+	"github.com/onosproject/onos-config/pkg/protected"
+	"github.com/onosproject/onos-config/pkg/secrets"
+	"github.com/onosproject/onos-config/pkg/signing"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-config/pkg/southbound/features"
+	"github.com/onosproject/onos-config/pkg/store/annotation"
+	"github.com/onosproject/onos-config/pkg/store/change/atomicity"
+	"github.com/onosproject/onos-config/pkg/store/change/confirmation"
 	"github.com/onosproject/onos-config/pkg/store/change/device"
 	"github.com/onosproject/onos-config/pkg/store/change/device/state"
+	"github.com/onosproject/onos-config/pkg/store/change/environment"
 	"github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/onosproject/onos-config/pkg/store/change/pause"
+	"github.com/onosproject/onos-config/pkg/store/change/provenance"
+	"github.com/onosproject/onos-config/pkg/store/change/push"
+	"github.com/onosproject/onos-config/pkg/store/change/signature"
 	devicestore "github.com/onosproject/onos-config/pkg/store/device"
 	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/store/health"
 	"github.com/onosproject/onos-config/pkg/store/leadership"
+	"github.com/onosproject/onos-config/pkg/store/location"
+	"github.com/onosproject/onos-config/pkg/store/maintenance"
 	"github.com/onosproject/onos-config/pkg/store/mastership"
+	mergestore "github.com/onosproject/onos-config/pkg/store/merge"
+	"github.com/onosproject/onos-config/pkg/store/oplog"
+	"github.com/onosproject/onos-config/pkg/store/ownership"
+	"github.com/onosproject/onos-config/pkg/store/quarantine"
+	"github.com/onosproject/onos-config/pkg/store/sampling"
+	"github.com/onosproject/onos-config/pkg/store/schema"
 	devicesnap "github.com/onosproject/onos-config/pkg/store/snapshot/device"
 	networksnap "github.com/onosproject/onos-config/pkg/store/snapshot/network"
+	transformstore "github.com/onosproject/onos-config/pkg/store/transform"
+	"github.com/onosproject/onos-config/pkg/store/trust"
+	"github.com/onosproject/onos-config/pkg/store/tuning"
+	"github.com/onosproject/onos-config/pkg/validators"
+	"github.com/onosproject/onos-config/pkg/version"
 	"github.com/onosproject/onos-lib-go/pkg/certs"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
-	"github.com/onosproject/onos-lib-go/pkg/northbound"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
 )
 
 // OIDCServerURL - address of an OpenID Connect server
@@ -73,25 +208,94 @@ func main() {
 	keyPath := flag.String("keyPath", "", "path to client private key")
 	certPath := flag.String("certPath", "", "path to client certificate")
 	topoEndpoint := flag.String("topoEndpoint", "onos-topo:5150", "topology service endpoint")
+	graphqlPort := flag.Int("graphqlPort", 0, "port of the optional GraphQL query endpoint; disabled if 0")
+	gatewayPort := flag.Int("gatewayPort", 0, "port of the optional REST/JSON gateway to the admin services; disabled if 0")
+	metricsPort := flag.Int("metricsPort", 0, "port of the optional HTTP endpoint of the Prometheus metrics, at /metrics; disabled if 0")
+	sensitivePaths := flag.String("sensitivePaths", "", "comma separated paths whose values are redacted from northbound Get and Subscribe")
+	protectedPaths := flag.String("protectedPaths", "", "comma separated subtrees that only the change-protected-paths group may change with gNMI Set")
+	signingKeysPath := flag.String("signingKeysPath", "", "directory of PEM encoded public keys that Set request signatures are verified against")
+	requireSignedChanges := flag.Bool("requireSignedChanges", false, "reject Set requests that are not signed")
+	authInterceptors := flag.String("authInterceptors", "", "comma separated, ordered chain of northbound interceptors: jwt, mtls, apikey, authz")
+	apiKeysPath := flag.String("apiKeysPath", "", "path to the YAML file of API keys used by the apikey interceptor")
+	certIdentitiesPath := flag.String("certIdentitiesPath", "", "path to the YAML file of the groups of client certificates used by the mtls interceptor")
+	authzURL := flag.String("authzURL", "", "URL of the external authorization service used by the authz interceptor")
+	trustBundleKeyPath := flag.String("trustBundleKeyPath", "", "path to the base64 encoded key the private keys of trust bundles are encrypted with; client bundles are refused without it")
+	readThroughGet := flag.Bool("readThroughGet", false, "read paths that have no value in the stores from the device itself on Get")
+	deviceGroupsPath := flag.String("deviceGroupsPath", "", "path to the YAML file of device groups that snapshots can be scoped to")
+	translationsPath := flag.String("translationsPath", "", "path to the YAML file mapping the paths of vendor-neutral models, e.g. OpenConfig, to the native models of the device types")
+	deviceTypesPath := flag.String("deviceTypesPath", "", "path to the YAML file tuning the gNMI features of the device types, e.g. the most paths per Set")
+	closedLoopRulesPath := flag.String("closedLoopRulesPath", "", "path to the YAML file of the closed loop rules, which change the configuration of the devices whose operational state meets a condition")
+	externalValidatorsPath := flag.String("externalValidatorsPath", "", "path to the YAML file of the external validators, e.g. a Batfish-style analysis, asked whether the changes of a gNMI Set may be made")
+	squashChanges := flag.Bool("squashChanges", false, "store only the final value of each path a gNMI Set writes, auditing the values it replaced")
+	recordNoOpSets := flag.Bool("recordNoOpSets", false, "create a network change for a gNMI Set that leaves the configuration as it is")
+	setValidation := flag.String("setValidation", string(gnmi.ValidationStrict), "how strictly a gNMI Set is validated against the model: strict, schema-only or none")
+	maxUpdatesPerNotification := flag.Int("maxUpdatesPerNotification", 0, "most updates of a notification of a gNMI Subscribe with mode ONCE or POLL, a larger tree being streamed with several; unlimited if 0")
+	maxGetResponseBytes := flag.Int("maxGetResponseBytes", 0, "largest gNMI Get response that is not paged, in bytes, a larger one failing the Get; unlimited if 0")
+	setAtomicity := flag.String("setAtomicity", string(atomicity.ModeAllOrNothing), "what a gNMI Set does when some of its devices reject it: all-or-nothing or best-effort")
+	snapshotDeltas := flag.Int("snapshotDeltas", 0, "number of snapshots of a device that only store what changed after a full snapshot; 0 stores every snapshot in full")
+	stuckChangeTimeout := flag.Duration("stuckChangeTimeout", 0, "how long a pending network change may make no progress before the watchdog escalates it; disabled if 0")
+	stuckChangeAction := flag.String("stuckChangeAction", "flag", "what the watchdog does with a stuck network change: flag, retry or cancel")
+	maxDevices := flag.Int("maxDevices", 0, "most devices onos-config connects to; unlimited if 0")
+	maxPendingChanges := flag.Int("maxPendingChanges", 0, "most network changes pending at once, beyond which gNMI Set is rejected; unlimited if 0")
+	maxStoredChanges := flag.Int("maxStoredChanges", 0, "most network changes stored until compacted, beyond which gNMI Set is rejected; unlimited if 0")
+	maxConcurrentSets := flag.Int("maxConcurrentSets", 0, "most gNMI Sets issued to the devices at once, granted round-robin across the devices; unlimited if 0")
+	targetConcurrency := flag.Int("targetConcurrency", southbound.DefaultConcurrency, "most gNMI requests issued to a device at once, the others waiting in its queue; unlimited if 0")
+	targetRequestTimeout := flag.Duration("targetRequestTimeout", 0, "how long a gNMI request to a device may take, waiting in its queue included; only bounded by its caller if 0")
+	reconnectInterval := flag.Duration("reconnectInterval", southbound.DefaultReconnectInterval, "how long to wait before reconnecting to a device whose connection dropped, doubling after each failed attempt")
+	maxReconnectInterval := flag.Duration("maxReconnectInterval", southbound.DefaultMaxReconnectInterval, "longest wait between two attempts to reconnect to a device")
+	anomalyRatio := flag.Float64("anomalyRatio", anomaly.DefaultRatio, "how many times more often than usual a path must change to be flagged as anomalous; no detection if 0")
+	latencySLO := flag.Duration("latencySLO", 0, "how long a network change should take to complete on each device; changes taking longer count against the device")
+	modelCheckInterval := flag.Duration("modelCheckInterval", 0, "how often the models reported by the connected devices are compared with their plugins; only when they connect if 0")
+	resolveInterval := flag.Duration("resolveInterval", 0, "how often the addresses of the connected devices are resolved again, to reconnect to the devices that moved; only when they connect if 0")
+	storeProbeInterval := flag.Duration("storeProbeInterval", 0, "how often the Atomix primitives of the stores are read to check their health; only by their own operations if 0")
+	zone := flag.String("zone", os.Getenv("ZONE"), "zone of this replica, for the devices preferring their master in a zone")
+	recordRequests := flag.Int("recordRequests", 0, "number of the last gNMI Set and Get requests recorded with their responses for replay; disabled if 0")
+	deviceOperations := flag.Int("deviceOperations", 100, "number of the last gNMI Sets and Gets issued to each device kept in its operation log; disabled if 0")
+	stateShards := flag.Int("stateShards", 0, "number of shards of the operational state of the devices, each with its own lock and worker; defaults to the number of CPUs if 0")
+	subscriptionQueue := flag.Int("subscriptionQueue", 0, "number of operational state events queued for each northbound gNMI subscription, beyond which its events are dropped rather than holding up the others; unqueued if 0")
+	stateBudgetMiB := flag.Int64("stateBudgetMiB", 0, "memory budget of the operational state cache in MiB, over which the state read least recently is evicted; unbounded if 0")
+	storageKeysPath := flag.String("storageKeysPath", "", "directory of base64 encoded keys that the stored changes and snapshots are encrypted with; stored in the clear if empty")
+	storageKeyID := flag.String("storageKeyID", "", "ID of the key of storageKeysPath that changes and snapshots are encrypted with; optional if it holds a single key")
 	//This flag is used in logging.init()
 	flag.Bool("debug", false, "enable debug logging")
 	flag.Parse()
 
-	log.Info("Starting onos-config")
+	log.Infof("Starting onos-config %s", version.Version)
+
+	validationLevel, err := gnmi.ParseValidationLevel(*setValidation)
+	if err != nil {
+		log.Fatal(err)
+	}
+	atomicityMode, err := atomicity.ParseMode(*setAtomicity)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	opts, err := certs.HandleCertPaths(*caPath, *keyPath, *certPath, true)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	atomixClient := atomix.NewClient(atomix.WithClientID(os.Getenv("POD_NAME")))
+	if *storageKeysPath != "" {
+		keyring, err := schema.LoadKeyring(*storageKeysPath, *storageKeyID)
+		if err != nil {
+			log.Fatal("Cannot load the storage encryption keys ", err)
+		}
+		schema.SetEncryptor(keyring)
+		log.Infof("Encrypting stored changes and snapshots with key %s", keyring.KeyID())
+	}
+
+	atomixClient := health.WrapClient(atomix.NewClient(atomix.WithClientID(os.Getenv("POD_NAME"))))
+	if *storeProbeInterval > 0 {
+		health.StartProbe(*storeProbeInterval)
+	}
 
 	leadershipStore, err := leadership.NewAtomixStore(atomixClient)
 	if err != nil {
 		log.Fatal("Cannot load leadership atomix store ", err)
 	}
 
-	mastershipStore, err := mastership.NewAtomixStore(atomixClient, cluster.NodeID(os.Getenv("POD_NAME")))
+	mastershipStore, err := mastership.NewAtomixStore(atomixClient, cluster.NodeID(os.Getenv("POD_NAME")), mastership.WithZone(*zone))
 	if err != nil {
 		log.Fatal("Cannot load mastership atomix store ", err)
 	}
@@ -111,11 +315,111 @@ func main() {
 		log.Fatal("Cannot load network snapshot atomix store ", err)
 	}
 
-	deviceSnapshotStore, err := devicesnap.NewAtomixStore(atomixClient)
+	deviceSnapshotStore, err := devicesnap.NewAtomixStore(atomixClient, devicesnap.WithMaxDeltas(*snapshotDeltas))
 	if err != nil {
 		log.Fatal("Cannot load network atomix store ", err)
 	}
 
+	signatureStore, err := signature.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load change signature atomix store ", err)
+	}
+
+	provenanceStore, err := provenance.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load change provenance atomix store ", err)
+	}
+
+	ownershipStore, err := ownership.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load ownership atomix store ", err)
+	}
+
+	var trustBundleKeys *trust.KeyCipher
+	if *trustBundleKeyPath != "" {
+		if trustBundleKeys, err = trust.LoadKeyCipher(*trustBundleKeyPath); err != nil {
+			log.Fatal("Cannot load the trust bundle key ", err)
+		}
+	}
+	trustStore, err := trust.NewAtomixStore(atomixClient, trustBundleKeys)
+	if err != nil {
+		log.Fatal("Cannot load trust bundle atomix store ", err)
+	}
+
+	locationStore, err := location.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load device location atomix store ", err)
+	}
+
+	atomicityStore, err := atomicity.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load change atomicity atomix store ", err)
+	}
+
+	quarantineStore, err := quarantine.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load device quarantine atomix store ", err)
+	}
+
+	pauseStore, err := pause.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load paused change atomix store ", err)
+	}
+
+	confirmationStore, err := confirmation.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load change confirmation atomix store ", err)
+	}
+
+	pushStore, err := push.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load device push atomix store ", err)
+	}
+
+	if *deviceOperations > 0 {
+		operationLog, err := oplog.NewAtomixStore(atomixClient, *deviceOperations)
+		if err != nil {
+			log.Fatal("Cannot load device operation log atomix store ", err)
+		}
+		southbound.SetOperationLog(operationLog)
+		log.Infof("Logging the last %d gNMI Sets and Gets issued to each device", *deviceOperations)
+	}
+
+	transformStore, err := transformstore.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load transform rule atomix store ", err)
+	}
+
+	mergeStore, err := mergestore.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load merge rule atomix store ", err)
+	}
+
+	environmentStore, err := environment.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load change environment atomix store ", err)
+	}
+
+	tuningStore, err := tuning.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load controller tuning atomix store ", err)
+	}
+
+	sampleIntervalStore, err := sampling.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load sample interval atomix store ", err)
+	}
+
+	annotationStore, err := annotation.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load annotation atomix store ", err)
+	}
+
+	maintenanceStore, err := maintenance.NewAtomixStore(atomixClient)
+	if err != nil {
+		log.Fatal("Cannot load maintenance mode atomix store ", err)
+	}
+
 	deviceStateStore, err := state.NewStore(networkChangesStore, deviceSnapshotStore)
 	if err != nil {
 		log.Fatal("Cannot load device store with address %s:", *topoEndpoint, err)
@@ -143,14 +447,113 @@ func main() {
 		log.Infof("Authorization not enabled %s", os.Getenv(OIDCServerURL))
 	}
 
+	if *sensitivePaths != "" {
+		secrets.GetRegistry().Register(strings.Split(*sensitivePaths, ",")...)
+		log.Infof("Redacting sensitive paths %v", secrets.GetRegistry().Paths())
+	}
+
+	if *protectedPaths != "" {
+		protected.GetRegistry().Register(strings.Split(*protectedPaths, ",")...)
+		log.Infof("Protecting paths %v", protected.GetRegistry().Paths())
+	}
+
+	if *signingKeysPath != "" {
+		if err := signing.GetKeyRegistry().LoadDir(*signingKeysPath); err != nil {
+			log.Fatal("Cannot load signing keys from ", *signingKeysPath, err)
+		}
+	}
+	signing.GetKeyRegistry().SetRequired(*requireSignedChanges)
+
+	if *recordRequests > 0 {
+		recorder.GetRecorder().SetCapacity(*recordRequests)
+		log.Infof("Recording the last %d gNMI Set and Get requests", *recordRequests)
+	}
+
+	if *deviceGroupsPath != "" {
+		if err := devicegroup.GetRegistry().Load(*deviceGroupsPath); err != nil {
+			log.Fatal("Cannot load device groups from ", *deviceGroupsPath, err)
+		}
+	}
+
+	if *deviceTypesPath != "" {
+		if err := features.GetRegistry().Load(*deviceTypesPath); err != nil {
+			log.Fatal("Cannot load device types from ", *deviceTypesPath, err)
+		}
+	}
+
+	if *closedLoopRulesPath != "" {
+		if err := closedloop.GetEngine().Load(*closedLoopRulesPath); err != nil {
+			log.Fatal("Cannot load closed loop rules from ", *closedLoopRulesPath, err)
+		}
+	}
+
+	if *externalValidatorsPath != "" {
+		if err := validators.GetRegistry().Load(*externalValidatorsPath); err != nil {
+			log.Fatal("Cannot load external validators from ", *externalValidatorsPath, err)
+		}
+	}
+
 	modelRegistry, err := modelregistry.NewModelRegistry(modelregistry.Config{})
 	if err != nil {
 		log.Fatal("Failed to load model registry:", err)
 	}
+	if *translationsPath != "" {
+		if err := modelRegistry.LoadTranslations(*translationsPath); err != nil {
+			log.Fatal("Cannot load translations from ", *translationsPath, err)
+		}
+	}
 
 	mgr := manager.NewManager(leadershipStore, mastershipStore, deviceChangesStore,
 		deviceStateStore, deviceStore, deviceCache, networkChangesStore, networkSnapshotStore,
 		deviceSnapshotStore, *allowUnvalidatedConfig, modelRegistry)
+	mgr.SignatureStore = signatureStore
+	mgr.SetProvenanceStore(provenanceStore)
+	mgr.TransformStore = transformStore
+	mgr.SetTrustStore(trustStore)
+	mgr.SetLocationStore(locationStore)
+	mgr.SetAtomicityStore(atomicityStore)
+	mgr.SetQuarantineStore(quarantineStore)
+	mgr.SetPauseStore(pauseStore)
+	mgr.SetConfirmationStore(confirmationStore)
+	mgr.SetPushStore(pushStore)
+	mgr.SetTuningStore(tuningStore)
+	mgr.SetSampleIntervalStore(sampleIntervalStore)
+	mgr.SetAnnotationStore(annotationStore)
+	mgr.SetMaintenanceStore(maintenanceStore)
+	mgr.SetOwnershipStore(ownershipStore)
+	mgr.SetMergeStore(mergeStore)
+	mgr.SetEnvironmentStore(environmentStore)
+	mgr.SetReadThrough(*readThroughGet)
+	if *stateShards > 0 {
+		mgr.SetStateShards(*stateShards)
+	}
+	mgr.SetStateBudget(*stateBudgetMiB << 20)
+	mgr.Dispatcher.SetListenerQueue(*subscriptionQueue)
+	if *stuckChangeTimeout > 0 {
+		action, err := watchdog.ParseAction(*stuckChangeAction)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := mgr.SetWatchdog(watchdog.Policy{StuckAfter: *stuckChangeTimeout, Action: action}); err != nil {
+			log.Fatal("Cannot create the watchdog of stuck changes ", err)
+		}
+	}
+	mgr.SetLatencyTracker(*latencySLO)
+	mgr.SetAnomalyDetector(*anomalyRatio)
+	mgr.SetModelCheckInterval(*modelCheckInterval)
+	mgr.SetResolveInterval(*resolveInterval)
+	capacity.GetGuard().SetLimits(capacity.Limits{
+		MaxDevices:        *maxDevices,
+		MaxPendingChanges: *maxPendingChanges,
+		MaxStoredChanges:  *maxStoredChanges,
+	})
+	southbound.SetMaxConcurrentSets(*maxConcurrentSets)
+	southbound.SetTargetConcurrency(*targetConcurrency)
+	southbound.SetTargetRequestTimeout(*targetRequestTimeout)
+	southbound.SetReconnectBackoff(*reconnectInterval, *maxReconnectInterval)
+	if err := capacity.GetGuard().Watch(mgr.NetworkChangesStore); err != nil {
+		log.Fatal("Cannot count the network changes ", err)
+	}
 	log.Info("Manager created")
 
 	defer func() {
@@ -160,23 +563,98 @@ func main() {
 	}()
 
 	mgr.Run()
+	if *closedLoopRulesPath != "" {
+		if err := mgr.RunClosedLoop(closedloop.GetEngine()); err != nil {
+			log.Fatal("Cannot start the closed loop ", err)
+		}
+	}
 
-	err = startServer(*caPath, *keyPath, *certPath, authorization)
+	chain, err := buildChain(*authInterceptors, authorization, interceptors.Config{
+		APIKeysPath:        *apiKeysPath,
+		CertIdentitiesPath: *certIdentitiesPath,
+		AuthzURL:           *authzURL,
+	})
+	if err != nil {
+		log.Fatal("Cannot build the northbound interceptor chain ", err)
+	}
+
+	if *graphqlPort != 0 {
+		graphqlServer := graphql.NewServer(*graphqlPort, *caPath, *certPath, *keyPath, chain)
+		go func() {
+			if err := graphqlServer.Serve(); err != nil {
+				log.Error("GraphQL server stopped ", err)
+			}
+		}()
+	}
+
+	serverOpts := serverOptions(chain, readonly.NewGuard(mgr.MaintenanceStore))
+	if *gatewayPort != 0 {
+		gatewayServer := gateway.NewServer(*gatewayPort, *caPath, *certPath, *keyPath, chain, serverOpts...)
+		go func() {
+			if err := gatewayServer.Serve(); err != nil {
+				log.Error("REST gateway stopped ", err)
+			}
+		}()
+	}
+
+	if *metricsPort != 0 {
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", promhttp.Handler())
+			log.Infof("Serving the metrics on :%d/metrics", *metricsPort)
+			if err := http.ListenAndServe(fmt.Sprintf(":%d", *metricsPort), mux); err != nil {
+				log.Error("Metrics endpoint stopped ", err)
+			}
+		}()
+	}
+
+	err = startServer(*caPath, *keyPath, *certPath, serverOpts, gnmi.Service{
+		SquashChanges:             *squashChanges,
+		RecordNoOpSets:            *recordNoOpSets,
+		ValidationLevel:           validationLevel,
+		Atomicity:                 atomicityMode,
+		MaxUpdatesPerNotification: *maxUpdatesPerNotification,
+		MaxGetResponseBytes:       *maxGetResponseBytes,
+	})
 	if err != nil {
 		log.Fatal("Unable to start onos-config ", err)
 	}
 }
 
+// buildChain builds the northbound interceptor chain. Without interceptors configured, callers
+// are authenticated by their OIDC token if authorization is enabled, and are anonymous otherwise.
+func buildChain(names string, authorization bool, config interceptors.Config) (*interceptors.Chain, error) {
+	if names == "" && authorization {
+		names = interceptors.InterceptorJWT
+	}
+	chain, err := interceptors.BuildChain(strings.Split(names, ","), config)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Northbound interceptors %v", chain.Names())
+	return chain, nil
+}
+
+// serverOptions returns the options of the northbound gRPC server, which the REST gateway serves
+// its calls with too.
+// The errors of every call are mapped to gRPC statuses first, including those of the
+// interceptors. The interceptor chain guards every call, even if it is empty: it removes any
+// identity claimed by the clients themselves. The request recorder follows it, so that it knows
+// the callers and records the calls the read-only guard rejects.
+func serverOptions(chain *interceptors.Chain, guard *readonly.Guard) []grpc.ServerOption {
+	opts := append(grpcerrors.ServerOptions(), chain.ServerOptions()...)
+	opts = append(opts, recorder.GetRecorder().ServerOptions()...)
+	opts = append(opts, guard.ServerOptions()...)
+	opts = append(opts, capacity.GetGuard().ServerOptions()...)
+	return opts
+}
+
 // Creates gRPC server and registers various services; then serves.
-func startServer(caPath string, keyPath string, certPath string, authorization bool) error {
-	s := northbound.NewServer(northbound.NewServerCfg(caPath, keyPath, certPath, 5150, true,
-		northbound.SecurityConfig{
-			AuthenticationEnabled: authorization,
-			AuthorizationEnabled:  authorization,
-		}))
+func startServer(caPath string, keyPath string, certPath string, opts []grpc.ServerOption, gnmiService gnmi.Service) error {
+	s := northbound.NewServer(caPath, keyPath, certPath, 5150, opts...)
 	s.AddService(admin.Service{})
 	s.AddService(diags.Service{})
-	s.AddService(gnmi.Service{})
+	s.AddService(gnmiService)
 	s.AddService(logging.Service{})
 
 	return s.Serve(func(started string) {
@@ -0,0 +1,96 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package archive exports the configuration history of a device as gNMI notifications, and reads
+// and writes them as archives that gNMI tooling can replay, e.g. to reproduce the evolution of the
+// configuration of a device in a lab.
+//
+// An archive is a series of gnmi.Notification protos, each prefixed with its uvarint encoded
+// length, in the order they were applied to the device.
+package archive
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// maxNotificationSize is the size over which a notification of an archive is taken as corrupted
+const maxNotificationSize = 64 << 20
+
+// Writer writes the notifications of an archive
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter returns a writer of an archive
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write appends a notification to the archive
+func (w *Writer) Write(notification *gnmi.Notification) error {
+	bytes, err := proto.Marshal(notification)
+	if err != nil {
+		return errors.NewInvalid("cannot encode the notification: %v", err)
+	}
+	return w.WriteEncoded(bytes)
+}
+
+// WriteEncoded appends an encoded notification to the archive
+func (w *Writer) WriteEncoded(notification []byte) error {
+	length := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(length, uint64(len(notification)))
+	if _, err := w.w.Write(length[:n]); err != nil {
+		return err
+	}
+	_, err := w.w.Write(notification)
+	return err
+}
+
+// Reader reads the notifications of an archive
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader returns a reader of an archive
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read returns the next notification of the archive, or io.EOF at its end
+func (r *Reader) Read() (*gnmi.Notification, error) {
+	length, err := binary.ReadUvarint(r.r)
+	if err == io.EOF {
+		return nil, io.EOF
+	} else if err != nil {
+		return nil, errors.NewInvalid("malformed notification length: %v", err)
+	}
+	if length > maxNotificationSize {
+		return nil, errors.NewInvalid("notification of %d bytes exceeds the maximum of %d", length, maxNotificationSize)
+	}
+	bytes := make([]byte, length)
+	if _, err := io.ReadFull(r.r, bytes); err != nil {
+		return nil, errors.NewInvalid("truncated notification: %v", err)
+	}
+	notification := &gnmi.Notification{}
+	if err := proto.Unmarshal(bytes, notification); err != nil {
+		return nil, errors.NewInvalid("malformed notification: %v", err)
+	}
+	return notification, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"gotest.tools/assert"
+)
+
+func stringUpdate(name string, value string) *gnmi.Update {
+	return &gnmi.Update{
+		Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: name}}},
+		Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: value}},
+	}
+}
+
+func Test_WriterReader(t *testing.T) {
+	notifications := []*gnmi.Notification{
+		{Timestamp: 1, Prefix: &gnmi.Path{Target: "device-1"}, Update: []*gnmi.Update{stringUpdate("a", "1")}},
+		{Timestamp: 2, Prefix: &gnmi.Path{Target: "device-1"}, Delete: []*gnmi.Path{{Elem: []*gnmi.PathElem{{Name: "a"}}}}},
+	}
+	var buffer bytes.Buffer
+	writer := NewWriter(&buffer)
+	for _, notification := range notifications {
+		assert.NilError(t, writer.Write(notification))
+	}
+	archived := buffer.Bytes()
+
+	reader := NewReader(bytes.NewReader(archived))
+	for _, expected := range notifications {
+		notification, err := reader.Read()
+		assert.NilError(t, err)
+		assert.Assert(t, proto.Equal(expected, notification), notification)
+	}
+	_, err := reader.Read()
+	assert.Equal(t, io.EOF, err)
+
+	// a truncated archive fails on its last notification
+	reader = NewReader(bytes.NewReader(archived[:len(archived)-1]))
+	_, err = reader.Read()
+	assert.NilError(t, err)
+	_, err = reader.Read()
+	assert.Assert(t, errors.IsInvalid(err), err)
+}
+
+func Test_History(t *testing.T) {
+	created := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	value := func(path string, value string, removed bool) *devicechange.ChangeValue {
+		return &devicechange.ChangeValue{Path: path, Value: devicechange.NewTypedValueString(value), Removed: removed}
+	}
+	deviceChange := func(index devicechange.Index, phase changetypes.Phase, state changetypes.State, values ...*devicechange.ChangeValue) *devicechange.DeviceChange {
+		return &devicechange.DeviceChange{
+			Index:         index,
+			NetworkChange: devicechange.NetworkChangeRef{ID: "change"},
+			Change:        &devicechange.Change{DeviceID: "device-1", DeviceVersion: "1.0.0", Values: values},
+			Status:        changetypes.Status{Phase: phase, State: state},
+			Created:       created.Add(time.Duration(index) * time.Minute),
+			Updated:       created.Add(time.Hour),
+		}
+	}
+	snapshot := &devicesnapshot.Snapshot{
+		DeviceID:    "device-1",
+		ChangeIndex: 1,
+		Values:      []*devicechange.PathValue{{Path: "/a", Value: devicechange.NewTypedValueString("1")}},
+	}
+	changes := []*devicechange.DeviceChange{
+		deviceChange(4, changetypes.Phase_ROLLBACK, changetypes.State_COMPLETE, value("/a", "3", false), value("/b", "", true)),
+		deviceChange(1, changetypes.Phase_CHANGE, changetypes.State_COMPLETE, value("/a", "1", false)),
+		deviceChange(3, changetypes.Phase_CHANGE, changetypes.State_FAILED, value("/c", "failed", false)),
+		deviceChange(2, changetypes.Phase_CHANGE, changetypes.State_COMPLETE, value("/a", "2", false), value("/b", "x", false)),
+	}
+
+	history, err := History("device-1", snapshot, changes)
+	assert.NilError(t, err)
+	assert.Equal(t, len(history), 4)
+
+	// the changes compacted into the snapshot are replaced by its values
+	assert.Equal(t, history[0].Index, devicechange.Index(0))
+	assert.Equal(t, history[0].Notification.Timestamp, int64(0))
+	assert.Equal(t, history[0].Notification.Prefix.Target, "device-1")
+	assert.Assert(t, proto.Equal(history[0].Notification.Update[0], stringUpdate("a", "1")))
+
+	assert.Equal(t, history[1].Index, devicechange.Index(2))
+	assert.Equal(t, history[1].NetworkChange, "change")
+	assert.Equal(t, history[1].Notification.Timestamp, created.Add(2*time.Minute).UnixNano())
+	assert.Equal(t, len(history[1].Notification.Update), 2)
+
+	// a rolled back change is applied, then reverted to the values before it
+	assert.Equal(t, history[2].Index, devicechange.Index(4))
+	assert.Assert(t, !history[2].Rollback)
+	assert.Assert(t, proto.Equal(history[2].Notification.Update[0], stringUpdate("a", "3")))
+	assert.Equal(t, history[2].Notification.Delete[0].Elem[0].Name, "b")
+	assert.Equal(t, history[3].Index, devicechange.Index(4))
+	assert.Assert(t, history[3].Rollback)
+	assert.Equal(t, history[3].Notification.Timestamp, created.Add(time.Hour).UnixNano())
+	assert.Equal(t, len(history[3].Notification.Update), 2)
+	assert.Assert(t, proto.Equal(history[3].Notification.Update[0], stringUpdate("a", "2")))
+	assert.Assert(t, proto.Equal(history[3].Notification.Update[1], stringUpdate("b", "x")))
+	assert.Equal(t, len(history[3].Notification.Delete), 0)
+
+	history, err = History("device-1", nil, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(history), 0)
+}
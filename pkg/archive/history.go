@@ -0,0 +1,159 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-api/go/onos/config/device"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	devicechangeutils "github.com/onosproject/onos-config/pkg/store/change/device/utils"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-config/pkg/utils/values"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Entry is a notification of the configuration history of a device
+type Entry struct {
+	// Index is the index of the device change of the notification, 0 for the snapshot the history
+	// starts from
+	Index devicechange.Index
+	// NetworkChange is the ID of the network change of the device change
+	NetworkChange string
+	// Rollback is set for the notification reverting a rolled back change
+	Rollback bool
+	// Notification is the notification, with the device as the target of its prefix
+	Notification *gnmi.Notification
+}
+
+// History returns the configuration history of a device, in the order it was applied: the values
+// of the snapshot its earlier changes were compacted into, if any, then each change applied to the
+// device, followed by its reversal if it was rolled back. Pending and failed changes are left out.
+func History(deviceID device.ID, snapshot *devicesnapshot.Snapshot, changes []*devicechange.DeviceChange) ([]Entry, error) {
+	config := make(map[string]*devicechange.TypedValue)
+	var history []Entry
+	if snapshot != nil && len(snapshot.Values) > 0 {
+		snapshotValues := make([]*devicechange.ChangeValue, 0, len(snapshot.Values))
+		for _, value := range snapshot.Values {
+			snapshotValues = append(snapshotValues, &devicechange.ChangeValue{Path: value.Path, Value: value.Value})
+		}
+		notification, err := newNotification(deviceID, time.Time{}, snapshotValues)
+		if err != nil {
+			return nil, err
+		}
+		apply(config, snapshotValues)
+		history = append(history, Entry{Notification: notification})
+	}
+
+	sorted := make([]*devicechange.DeviceChange, len(changes))
+	copy(sorted, changes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Index < sorted[j].Index
+	})
+	for _, change := range sorted {
+		if change.Change == nil || snapshot != nil && change.Index <= snapshot.ChangeIndex {
+			continue
+		}
+		applied := change.Status.State == changetypes.State_COMPLETE || change.Status.Phase == changetypes.Phase_ROLLBACK
+		if !applied {
+			continue
+		}
+		notification, err := newNotification(deviceID, change.Created, change.Change.Values)
+		if err != nil {
+			return nil, errors.NewInvalid("device change %s: %v", change.ID, err)
+		}
+		history = append(history, Entry{
+			Index:         change.Index,
+			NetworkChange: string(change.NetworkChange.ID),
+			Notification:  notification,
+		})
+
+		rollback := devicechangeutils.ComputeRollback(change.Change, pathValues(config))
+		apply(config, change.Change.Values)
+		if change.Status.Phase != changetypes.Phase_ROLLBACK || change.Status.State != changetypes.State_COMPLETE {
+			continue
+		}
+		notification, err = newNotification(deviceID, change.Updated, rollback.Values)
+		if err != nil {
+			return nil, errors.NewInvalid("rollback of device change %s: %v", change.ID, err)
+		}
+		apply(config, rollback.Values)
+		history = append(history, Entry{
+			Index:         change.Index,
+			NetworkChange: string(change.NetworkChange.ID),
+			Rollback:      true,
+			Notification:  notification,
+		})
+	}
+	return history, nil
+}
+
+// newNotification returns the notification setting and deleting the values of a change
+func newNotification(deviceID device.ID, timestamp time.Time, changeValues []*devicechange.ChangeValue) (*gnmi.Notification, error) {
+	notification := &gnmi.Notification{
+		Prefix: &gnmi.Path{Target: string(deviceID)},
+	}
+	if !timestamp.IsZero() {
+		notification.Timestamp = timestamp.UnixNano()
+	}
+	for _, changeValue := range changeValues {
+		path, err := utils.ParseGNMIElements(utils.SplitPath(changeValue.Path))
+		if err != nil {
+			return nil, errors.NewInvalid("invalid path %s: %v", changeValue.Path, err)
+		}
+		if changeValue.Removed {
+			notification.Delete = append(notification.Delete, path)
+			continue
+		}
+		value, err := values.NativeTypeToGnmiTypedValue(changeValue.Value)
+		if err != nil {
+			return nil, errors.NewInvalid("invalid value of %s: %v", changeValue.Path, err)
+		}
+		notification.Update = append(notification.Update, &gnmi.Update{Path: path, Val: value})
+	}
+	return notification, nil
+}
+
+// apply applies the values of a change to a configuration; a removed path removes its subtree
+func apply(config map[string]*devicechange.TypedValue, changeValues []*devicechange.ChangeValue) {
+	for _, changeValue := range changeValues {
+		if !changeValue.Removed {
+			config[changeValue.Path] = changeValue.Value
+			continue
+		}
+		for path := range config {
+			if path == changeValue.Path || strings.HasPrefix(path, changeValue.Path+"/") {
+				delete(config, path)
+			}
+		}
+	}
+}
+
+// pathValues returns the values of a configuration sorted by path
+func pathValues(config map[string]*devicechange.TypedValue) []*devicechange.PathValue {
+	pathValues := make([]*devicechange.PathValue, 0, len(config))
+	for path, value := range config {
+		pathValues = append(pathValues, &devicechange.PathValue{Path: path, Value: value})
+	}
+	sort.Slice(pathValues, func(i, j int) bool {
+		return pathValues[i].Path < pathValues[j].Path
+	})
+	return pathValues
+}
@@ -0,0 +1,89 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit records security relevant actions taken by or on behalf of northbound callers.
+//
+// Entries are written to the "audit" logger, so that they can be routed separately from the
+// rest of the onos-config logs, and the most recent ones are kept in memory for inspection.
+package audit
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+)
+
+var log = logging.GetLogger("audit")
+
+// MaxEntries is the number of most recent entries kept in memory
+const MaxEntries = 1000
+
+// Entry is a single audited action
+type Entry struct {
+	// Time is when the action was taken
+	Time time.Time
+	// User is the name of the caller, if known
+	User string
+	// Action is what was done e.g. "reveal-secrets"
+	Action string
+	// Target is the device or change the action was taken on
+	Target string
+	// Paths are the configuration paths concerned, if any
+	Paths []string
+	// Message is a free form description
+	Message string
+}
+
+var (
+	mu      sync.RWMutex
+	entries = make([]Entry, 0)
+)
+
+// Record records an audit entry. If the entry has no time it is set to now.
+func Record(entry Entry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	user := entry.User
+	if user == "" {
+		user = "<anonymous>"
+	}
+	log.Infof("%s by '%s' on '%s' paths [%s] %s", entry.Action, user, entry.Target,
+		strings.Join(entry.Paths, ","), entry.Message)
+
+	mu.Lock()
+	defer mu.Unlock()
+	entries = append(entries, entry)
+	if len(entries) > MaxEntries {
+		entries = entries[len(entries)-MaxEntries:]
+	}
+}
+
+// Entries returns the most recent audit entries, oldest first
+func Entries() []Entry {
+	mu.RLock()
+	defer mu.RUnlock()
+	result := make([]Entry, len(entries))
+	copy(result, entries)
+	return result
+}
+
+// Clear removes all the entries kept in memory
+func Clear() {
+	mu.Lock()
+	defer mu.Unlock()
+	entries = make([]Entry, 0)
+}
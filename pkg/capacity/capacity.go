@@ -0,0 +1,353 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package capacity guards onos-config and its Atomix cluster against unbounded growth. It limits
+// the devices onos-config connects to, the network changes pending and the network changes stored;
+// once a limit is reached, the calls that would grow past it are rejected with RESOURCE_EXHAUSTED
+// until it is no longer reached, and an alert is raised in the log and the metrics.
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var log = logging.GetLogger("capacity")
+
+// Resources that are limited
+const (
+	// ResourceDevices are the devices onos-config connects to
+	ResourceDevices = "devices"
+	// ResourcePendingChanges are the network changes not yet complete or failed
+	ResourcePendingChanges = "pending-changes"
+	// ResourceStoredChanges are the network changes in the store, until they are compacted
+	ResourceStoredChanges = "stored-changes"
+)
+
+// admittedMethods are the northbound methods that create network changes
+var admittedMethods = map[string]bool{
+	"/gnmi.gNMI/Set": true,
+	"/onos.config.adminext.ConfigAdminExtService/AdoptConfig":         true,
+	"/onos.config.adminext.ConfigAdminExtService/MoveListEntry":       true,
+	"/onos.config.adminext.ConfigAdminExtService/DeleteSubtree":       true,
+	"/onos.config.adminext.ConfigAdminExtService/ReplaceDeviceConfig": true,
+}
+
+var (
+	usageGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "onos_config_capacity_usage",
+		Help: "Number of the devices, pending network changes and stored network changes",
+	}, []string{"resource"})
+	limitGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "onos_config_capacity_limit",
+		Help: "Limit of the devices, pending network changes and stored network changes; 0 if unlimited",
+	}, []string{"resource"})
+	reachedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "onos_config_capacity_limit_reached",
+		Help: "1 while the limit of a resource is reached and the calls growing it are rejected",
+	}, []string{"resource"})
+	rejectionsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "onos_config_capacity_rejections_total",
+		Help: "Number of the devices and calls rejected because the limit of a resource was reached",
+	}, []string{"resource"})
+)
+
+func init() {
+	prometheus.MustRegister(usageGauge, limitGauge, reachedGauge, rejectionsCounter)
+}
+
+// Limits are the most devices and network changes onos-config admits; 0 is no limit
+type Limits struct {
+	MaxDevices        int
+	MaxPendingChanges int
+	MaxStoredChanges  int
+}
+
+// Usage is how many devices and network changes onos-config has
+type Usage struct {
+	Devices        int
+	PendingChanges int
+	StoredChanges  int
+}
+
+// Alert is a limit that is reached
+type Alert struct {
+	Resource string
+	// Since is when the limit was reached
+	Since time.Time
+	Usage int
+	Limit int
+}
+
+// Guard admits the devices and the network changes within the limits
+type Guard struct {
+	mu         sync.RWMutex
+	limits     Limits
+	devices    map[devicetype.ID]bool
+	pending    map[networkchange.ID]bool
+	stored     map[networkchange.ID]bool
+	alerts     map[string]time.Time
+	rejections map[string]uint64
+	watch      stream.Context
+}
+
+var guard = NewGuard()
+
+// GetGuard returns the guard of the limits of this node
+func GetGuard() *Guard {
+	return guard
+}
+
+// NewGuard creates a guard without limits
+func NewGuard() *Guard {
+	return &Guard{
+		devices:    make(map[devicetype.ID]bool),
+		pending:    make(map[networkchange.ID]bool),
+		stored:     make(map[networkchange.ID]bool),
+		alerts:     make(map[string]time.Time),
+		rejections: make(map[string]uint64),
+	}
+}
+
+// SetLimits sets the limits; a negative limit is no limit
+func (g *Guard) SetLimits(limits Limits) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.limits = Limits{
+		MaxDevices:        nonNegative(limits.MaxDevices),
+		MaxPendingChanges: nonNegative(limits.MaxPendingChanges),
+		MaxStoredChanges:  nonNegative(limits.MaxStoredChanges),
+	}
+	limitGauge.WithLabelValues(ResourceDevices).Set(float64(g.limits.MaxDevices))
+	limitGauge.WithLabelValues(ResourcePendingChanges).Set(float64(g.limits.MaxPendingChanges))
+	limitGauge.WithLabelValues(ResourceStoredChanges).Set(float64(g.limits.MaxStoredChanges))
+	g.updateAlerts()
+}
+
+func nonNegative(limit int) int {
+	if limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// Limits returns the limits
+func (g *Guard) Limits() Limits {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.limits
+}
+
+// Usage returns how many devices and network changes onos-config has
+func (g *Guard) Usage() Usage {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.usage()
+}
+
+func (g *Guard) usage() Usage {
+	return Usage{
+		Devices:        len(g.devices),
+		PendingChanges: len(g.pending),
+		StoredChanges:  len(g.stored),
+	}
+}
+
+// Alerts returns the limits that are reached, by resource
+func (g *Guard) Alerts() []Alert {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	alerts := make([]Alert, 0, len(g.alerts))
+	for _, resource := range []string{ResourceDevices, ResourcePendingChanges, ResourceStoredChanges} {
+		if since, ok := g.alerts[resource]; ok {
+			usage, limit := g.usageOf(resource)
+			alerts = append(alerts, Alert{Resource: resource, Since: since, Usage: usage, Limit: limit})
+		}
+	}
+	return alerts
+}
+
+// Rejections returns the number of the devices and calls rejected since this node started, by
+// resource
+func (g *Guard) Rejections() map[string]uint64 {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	rejections := make(map[string]uint64, len(g.rejections))
+	for resource, count := range g.rejections {
+		rejections[resource] = count
+	}
+	return rejections
+}
+
+// usageOf returns the usage and the limit of a resource
+func (g *Guard) usageOf(resource string) (int, int) {
+	switch resource {
+	case ResourceDevices:
+		return len(g.devices), g.limits.MaxDevices
+	case ResourcePendingChanges:
+		return len(g.pending), g.limits.MaxPendingChanges
+	default:
+		return len(g.stored), g.limits.MaxStoredChanges
+	}
+}
+
+// updateAlerts raises the alerts of the limits newly reached and clears those of the limits no
+// longer reached
+func (g *Guard) updateAlerts() {
+	for _, resource := range []string{ResourceDevices, ResourcePendingChanges, ResourceStoredChanges} {
+		usage, limit := g.usageOf(resource)
+		usageGauge.WithLabelValues(resource).Set(float64(usage))
+		_, raised := g.alerts[resource]
+		reached := limit > 0 && usage >= limit
+		if reached && !raised {
+			g.alerts[resource] = time.Now()
+			reachedGauge.WithLabelValues(resource).Set(1)
+			log.Warnf("The limit of %d %s is reached: new ones are rejected", limit, resource)
+		} else if !reached && raised {
+			delete(g.alerts, resource)
+			reachedGauge.WithLabelValues(resource).Set(0)
+			log.Infof("The limit of %d %s is no longer reached", limit, resource)
+		}
+	}
+}
+
+// reject counts a rejection and returns the error it is rejected with
+func (g *Guard) reject(resource string, limit int, what string) error {
+	g.rejections[resource]++
+	rejectionsCounter.WithLabelValues(resource).Inc()
+	return status.Errorf(codes.ResourceExhausted, "%s rejected: onos-config has reached its limit of %d %s", what, limit, resource)
+}
+
+// AdmitDevice admits a device to be connected to, unless the limit of the devices is reached; a
+// device already admitted is admitted again
+func (g *Guard) AdmitDevice(deviceID devicetype.ID) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.devices[deviceID] {
+		return nil
+	}
+	if g.limits.MaxDevices > 0 && len(g.devices) >= g.limits.MaxDevices {
+		return g.reject(ResourceDevices, g.limits.MaxDevices, fmt.Sprintf("device %s", deviceID))
+	}
+	g.devices[deviceID] = true
+	g.updateAlerts()
+	return nil
+}
+
+// ReleaseDevice releases a device that is no longer connected to
+func (g *Guard) ReleaseDevice(deviceID devicetype.ID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.devices, deviceID)
+	g.updateAlerts()
+}
+
+// AdmitChange returns a RESOURCE_EXHAUSTED error if the limit of the pending or of the stored
+// network changes is reached
+func (g *Guard) AdmitChange(method string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.limits.MaxPendingChanges > 0 && len(g.pending) >= g.limits.MaxPendingChanges {
+		return g.reject(ResourcePendingChanges, g.limits.MaxPendingChanges, method)
+	}
+	if g.limits.MaxStoredChanges > 0 && len(g.stored) >= g.limits.MaxStoredChanges {
+		return g.reject(ResourceStoredChanges, g.limits.MaxStoredChanges, method)
+	}
+	return nil
+}
+
+// Watch counts the pending and the stored network changes of a store
+func (g *Guard) Watch(networkChanges networkchangestore.Store) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.watch != nil {
+		return nil
+	}
+	ch := make(chan stream.Event)
+	ctx, err := networkChanges.Watch(ch, networkchangestore.WithReplay())
+	if err != nil {
+		return err
+	}
+	g.watch = ctx
+	go func() {
+		for event := range ch {
+			if change, ok := event.Object.(*networkchange.NetworkChange); ok {
+				g.update(change, event.Type == stream.Deleted)
+			}
+		}
+	}()
+	return nil
+}
+
+// update counts a network change as stored, unless it was deleted, and as pending while it is
+func (g *Guard) update(change *networkchange.NetworkChange, deleted bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if deleted {
+		delete(g.stored, change.ID)
+	} else {
+		g.stored[change.ID] = true
+	}
+	if !deleted && !change.Deleted && change.Status.State == changetypes.State_PENDING {
+		g.pending[change.ID] = true
+	} else {
+		delete(g.pending, change.ID)
+	}
+	g.updateAlerts()
+}
+
+// Stop stops counting the network changes
+func (g *Guard) Stop() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.watch != nil {
+		g.watch.Close()
+		g.watch = nil
+	}
+}
+
+// ServerOptions returns the options that install the guard on a gRPC server. They must follow
+// the interceptor chain, so that the callers are authenticated when the guard logs them.
+func (g *Guard) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(g.UnaryServerInterceptor()),
+	}
+}
+
+// UnaryServerInterceptor returns the guard as a unary interceptor, rejecting the calls that create
+// network changes while a limit of the network changes is reached
+func (g *Guard) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if admittedMethods[info.FullMethod] {
+			if err := g.AdmitChange(info.FullMethod); err != nil {
+				log.Infof("%s rejected: %v", info.FullMethod, err)
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
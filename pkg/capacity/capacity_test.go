@@ -0,0 +1,147 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package capacity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGuard_Devices(t *testing.T) {
+	guard := NewGuard()
+	assert.NoError(t, guard.AdmitDevice("device-1"))
+	assert.NoError(t, guard.AdmitDevice("device-2"))
+	assert.Empty(t, guard.Alerts())
+
+	guard.SetLimits(Limits{MaxDevices: 2})
+	alerts := guard.Alerts()
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, ResourceDevices, alerts[0].Resource)
+	assert.Equal(t, 2, alerts[0].Usage)
+	assert.Equal(t, 2, alerts[0].Limit)
+
+	// A device already admitted is admitted again, a new one is rejected
+	assert.NoError(t, guard.AdmitDevice("device-1"))
+	err := guard.AdmitDevice("device-3")
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, err.Error(), "limit of 2 devices")
+	assert.Equal(t, uint64(1), guard.Rejections()[ResourceDevices])
+
+	guard.ReleaseDevice("device-1")
+	assert.Empty(t, guard.Alerts())
+	assert.NoError(t, guard.AdmitDevice("device-3"))
+	assert.Equal(t, 2, guard.Usage().Devices)
+}
+
+func TestGuard_Changes(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+	atomixClient, err := test.NewClient("test")
+	assert.NoError(t, err)
+	networkChanges, err := networkchangestore.NewAtomixStore(atomixClient)
+	assert.NoError(t, err)
+	defer networkChanges.Close()
+
+	newChange := func(id networkchange.ID) *networkchange.NetworkChange {
+		change := &networkchange.NetworkChange{
+			ID: id,
+			Changes: []*devicechange.Change{{
+				DeviceID:      "device-1",
+				DeviceVersion: "1.0.0",
+				Values:        []*devicechange.ChangeValue{{Path: "/system/config/hostname", Removed: true}},
+			}},
+		}
+		assert.NoError(t, networkChanges.Create(change))
+		return change
+	}
+	change1 := newChange("change-1")
+
+	guard := NewGuard()
+	guard.SetLimits(Limits{MaxPendingChanges: 2, MaxStoredChanges: 3})
+	assert.NoError(t, guard.Watch(networkChanges))
+	defer guard.Stop()
+	waitForUsage := func(usage Usage) {
+		assert.Eventually(t, func() bool {
+			return guard.Usage() == usage
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+	waitForUsage(Usage{PendingChanges: 1, StoredChanges: 1})
+
+	set := func() error {
+		_, err := guard.UnaryServerInterceptor()(context.Background(), &gnmi.SetRequest{},
+			&grpc.UnaryServerInfo{FullMethod: "/gnmi.gNMI/Set"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return &gnmi.SetResponse{}, nil
+			})
+		return err
+	}
+	assert.NoError(t, set())
+
+	// The Sets are rejected while the limit of the pending changes is reached
+	newChange("change-2")
+	waitForUsage(Usage{PendingChanges: 2, StoredChanges: 2})
+	err = set()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, err.Error(), "limit of 2 pending-changes")
+	assert.Len(t, guard.Alerts(), 1)
+
+	// The calls that create no network changes are not rejected
+	_, err = guard.UnaryServerInterceptor()(context.Background(), &gnmi.GetRequest{},
+		&grpc.UnaryServerInfo{FullMethod: "/gnmi.gNMI/Get"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &gnmi.GetResponse{}, nil
+		})
+	assert.NoError(t, err)
+
+	change1.Status.State = changetypes.State_COMPLETE
+	assert.NoError(t, networkChanges.Update(change1))
+	waitForUsage(Usage{PendingChanges: 1, StoredChanges: 2})
+	assert.Empty(t, guard.Alerts())
+	assert.NoError(t, set())
+
+	// Then while the limit of the stored changes is reached, until a change is compacted
+	newChange("change-3")
+	waitForUsage(Usage{PendingChanges: 2, StoredChanges: 3})
+	change1, err = networkChanges.Get("change-1")
+	assert.NoError(t, err)
+	assert.NoError(t, networkChanges.Delete(change1))
+	waitForUsage(Usage{PendingChanges: 2, StoredChanges: 2})
+	guard.SetLimits(Limits{MaxStoredChanges: 2})
+	err = set()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, err.Error(), "limit of 2 stored-changes")
+	assert.Equal(t, uint64(1), guard.Rejections()[ResourceStoredChanges])
+
+	guard.SetLimits(Limits{})
+	assert.Empty(t, guard.Alerts())
+	assert.NoError(t, set())
+}
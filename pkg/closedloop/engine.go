@@ -0,0 +1,411 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package closedloop
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+)
+
+var log = logging.GetLogger("closedloop")
+
+// MaxTriggers is the number of most recent triggers kept
+const MaxTriggers = 1000
+
+// State is the state of the action of a trigger
+type State string
+
+const (
+	// StatePending is an action waiting for an operator to approve or reject it
+	StatePending State = "pending"
+	// StateApplying is an action whose configuration change is being made
+	StateApplying State = "applying"
+	// StateApplied is an action whose configuration change was made
+	StateApplied State = "applied"
+	// StateFailed is an action whose configuration change could not be made
+	StateFailed State = "failed"
+	// StateRejected is an action an operator rejected
+	StateRejected State = "rejected"
+	// StateSuppressed is an action beyond the rate limit of its rule, not acted on
+	StateSuppressed State = "suppressed"
+)
+
+// Trigger is the condition of a rule met by the state of a device, and the action it triggered
+type Trigger struct {
+	ID       string        `json:"id"`
+	Rule     string        `json:"rule"`
+	DeviceID devicetype.ID `json:"deviceId"`
+	// Path and Value are the leaf of the state meeting the condition
+	Path  string `json:"path"`
+	Value string `json:"value"`
+	// Updates and Deletes are the action, rendered for the trigger
+	Updates   map[string]string `json:"updates,omitempty"`
+	Deletes   []string          `json:"deletes,omitempty"`
+	State     State             `json:"state"`
+	Triggered time.Time         `json:"triggered"`
+	// Decided is when the action was approved or rejected, by User
+	Decided time.Time `json:"decided,omitempty"`
+	User    string    `json:"user,omitempty"`
+	// ChangeID is the network change of an applied action
+	ChangeID networkchange.ID `json:"changeId,omitempty"`
+	// Reason is why the action failed, was rejected or suppressed
+	Reason string `json:"reason,omitempty"`
+}
+
+// Paths returns the paths of the action of the trigger, sorted
+func (t *Trigger) Paths() []string {
+	paths := make([]string, 0, len(t.Updates)+len(t.Deletes))
+	for path := range t.Updates {
+		paths = append(paths, path)
+	}
+	paths = append(paths, t.Deletes...)
+	sort.Strings(paths)
+	return paths
+}
+
+// ApplyFunc makes the configuration change of the action of a trigger
+type ApplyFunc func(trigger *Trigger) (networkchange.ID, error)
+
+// Engine evaluates the rules on the state of the devices and acts on their triggers
+type Engine struct {
+	mu    sync.Mutex
+	rules []*Rule
+	apply ApplyFunc
+	// instances are the leaves of the state matching the condition of a rule, by rule, device and path
+	instances map[string]*instance
+	// actions are the times of the recent actions of each rule, within its rate limit
+	actions  map[string][]time.Time
+	triggers []*Trigger
+	nextID   uint64
+	now      func() time.Time
+}
+
+// instance is a leaf of the state of a device matching the condition of a rule
+type instance struct {
+	// met is whether the leaf meets the condition: a rule triggers when it becomes met
+	met     bool
+	samples []sample
+}
+
+// sample is a numeric value of a leaf over the window of a condition
+type sample struct {
+	time  time.Time
+	value float64
+}
+
+var engine = NewEngine(nil)
+
+// GetEngine returns the engine of the closed loop rules of onos-config
+func GetEngine() *Engine {
+	return engine
+}
+
+// NewEngine creates an engine without rules, applying the actions with apply
+func NewEngine(apply ApplyFunc) *Engine {
+	return &Engine{
+		apply:     apply,
+		instances: make(map[string]*instance),
+		actions:   make(map[string][]time.Time),
+		triggers:  make([]*Trigger, 0),
+		now:       time.Now,
+	}
+}
+
+// SetApply sets the function applying the actions
+func (e *Engine) SetApply(apply ApplyFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.apply = apply
+}
+
+// SetRules replaces the rules of the engine, checking them; the conditions met are forgotten
+func (e *Engine) SetRules(rules []*Rule) error {
+	names := make(map[string]bool)
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+		if names[rule.Name] {
+			return errors.NewInvalid("duplicate closed loop rule '%s'", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = rules
+	e.instances = make(map[string]*instance)
+	return nil
+}
+
+// Load sets the rules of a YAML file
+func (e *Engine) Load(path string) error {
+	rules, err := LoadRules(path)
+	if err != nil {
+		return err
+	}
+	return e.SetRules(rules)
+}
+
+// Rules returns the rules of the engine, in the order they were given
+func (e *Engine) Rules() []*Rule {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	rules := make([]*Rule, len(e.rules))
+	copy(rules, e.rules)
+	return rules
+}
+
+// Process evaluates the rules on a leaf of the state of a device, nil if the leaf was deleted, and
+// acts on the triggers; it returns the triggers
+func (e *Engine) Process(deviceID devicetype.ID, path string, value *devicechange.TypedValue) []*Trigger {
+	e.mu.Lock()
+	now := e.now()
+	triggered := make([]*Trigger, 0)
+	for _, rule := range e.rules {
+		if !rule.AppliesTo(deviceID) {
+			continue
+		}
+		variables, ok := rule.match(path)
+		if !ok {
+			continue
+		}
+		id := strings.Join([]string{rule.Name, string(deviceID), path}, "\x00")
+		if value == nil {
+			delete(e.instances, id)
+			continue
+		}
+		inst, ok := e.instances[id]
+		if !ok {
+			inst = &instance{}
+			e.instances[id] = inst
+		}
+		met := inst.evaluate(rule, value, now)
+		if !met || inst.met {
+			inst.met = met
+			continue
+		}
+		inst.met = true
+		variables[VariableDevice] = string(deviceID)
+		variables[VariableValue] = value.ValueToString()
+		triggered = append(triggered, e.trigger(rule, deviceID, path, variables, now))
+	}
+	apply := e.apply
+	e.mu.Unlock()
+
+	for _, trigger := range triggered {
+		message := fmt.Sprintf("%s is %s: action %s", trigger.Path, trigger.Value, trigger.State)
+		if trigger.Reason != "" {
+			message = fmt.Sprintf("%s, %s", message, trigger.Reason)
+		}
+		audit.Record(audit.Entry{
+			Time:    trigger.Triggered,
+			User:    "closed-loop/" + trigger.Rule,
+			Action:  "closed-loop-trigger",
+			Target:  string(trigger.DeviceID),
+			Paths:   trigger.Paths(),
+			Message: message,
+		})
+		if trigger.State == StateApplying {
+			e.applyTrigger(apply, trigger)
+		}
+	}
+	return triggered
+}
+
+// evaluate returns whether a value of the leaf meets the condition of a rule
+func (i *instance) evaluate(rule *Rule, value *devicechange.TypedValue, now time.Time) bool {
+	number := numericValue(value)
+	if rule.Condition.Window == 0 || number == nil {
+		i.samples = nil
+		return rule.compare(value.ValueToString(), number)
+	}
+	// The increase is from the oldest sample still in the window
+	i.samples = append(i.samples, sample{time: now, value: *number})
+	start := now.Add(-rule.Condition.Window)
+	for len(i.samples) > 1 && i.samples[1].time.Before(start) {
+		i.samples = i.samples[1:]
+	}
+	increase := *number - i.samples[0].value
+	return rule.compare(fmt.Sprintf("%g", increase), &increase)
+}
+
+// trigger records the trigger of a rule, deciding what to do with its action; it is called with
+// the lock held. The actions to apply are given the state applying.
+func (e *Engine) trigger(rule *Rule, deviceID devicetype.ID, path string, variables map[string]string, now time.Time) *Trigger {
+	e.nextID++
+	updates, deletes := rule.render(variables)
+	trigger := &Trigger{
+		ID:        fmt.Sprintf("%s-%d", rule.Name, e.nextID),
+		Rule:      rule.Name,
+		DeviceID:  deviceID,
+		Path:      path,
+		Value:     variables[VariableValue],
+		Updates:   updates,
+		Deletes:   deletes,
+		Triggered: now,
+	}
+	switch {
+	case !e.allow(rule, now):
+		trigger.State = StateSuppressed
+		trigger.Reason = fmt.Sprintf("more than %d actions in %s", rule.RateLimit.Max, rule.RateLimit.Per)
+	case rule.Approval:
+		trigger.State = StatePending
+	default:
+		trigger.State = StateApplying
+	}
+	e.triggers = append(e.triggers, trigger)
+	if len(e.triggers) > MaxTriggers {
+		e.triggers = e.triggers[len(e.triggers)-MaxTriggers:]
+	}
+	return trigger
+}
+
+// allow returns whether a rule may act again within its rate limit, counting the action if so
+func (e *Engine) allow(rule *Rule, now time.Time) bool {
+	if rule.RateLimit.Max == 0 {
+		return true
+	}
+	start := now.Add(-rule.RateLimit.Per)
+	recent := e.actions[rule.Name]
+	for len(recent) > 0 && !recent[0].After(start) {
+		recent = recent[1:]
+	}
+	if len(recent) >= rule.RateLimit.Max {
+		e.actions[rule.Name] = recent
+		return false
+	}
+	e.actions[rule.Name] = append(recent, now)
+	return true
+}
+
+// applyTrigger makes the configuration change of a trigger, without the lock held
+func (e *Engine) applyTrigger(apply ApplyFunc, trigger *Trigger) {
+	var changeID networkchange.ID
+	err := errors.NewUnavailable("closed loop actions are not applied on this node")
+	if apply != nil {
+		changeID, err = apply(trigger)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err != nil {
+		log.Warnf("Failed to apply the action of %s on %s: %v", trigger.ID, trigger.DeviceID, err)
+		trigger.State = StateFailed
+		trigger.Reason = err.Error()
+		return
+	}
+	log.Infof("Applied the action of %s on %s in change %s", trigger.ID, trigger.DeviceID, changeID)
+	trigger.State = StateApplied
+	trigger.ChangeID = changeID
+}
+
+// Approve applies the pending action of a trigger on behalf of a user
+func (e *Engine) Approve(id string, user string) (*Trigger, error) {
+	e.mu.Lock()
+	trigger, err := e.decide(id, user)
+	apply := e.apply
+	e.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	audit.Record(audit.Entry{
+		User:    user,
+		Action:  "closed-loop-approve",
+		Target:  string(trigger.DeviceID),
+		Paths:   trigger.Paths(),
+		Message: fmt.Sprintf("action of %s approved", trigger.ID),
+	})
+	e.applyTrigger(apply, trigger)
+	return e.Get(id)
+}
+
+// Reject drops the pending action of a trigger on behalf of a user
+func (e *Engine) Reject(id string, user string, reason string) (*Trigger, error) {
+	e.mu.Lock()
+	trigger, err := e.decide(id, user)
+	if err == nil {
+		trigger.State = StateRejected
+		trigger.Reason = reason
+	}
+	e.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	audit.Record(audit.Entry{
+		User:    user,
+		Action:  "closed-loop-reject",
+		Target:  string(trigger.DeviceID),
+		Paths:   trigger.Paths(),
+		Message: fmt.Sprintf("action of %s rejected: %s", trigger.ID, reason),
+	})
+	return e.Get(id)
+}
+
+// decide records the decision of a user on a pending trigger; it is called with the lock held
+func (e *Engine) decide(id string, user string) (*Trigger, error) {
+	trigger := e.find(id)
+	if trigger == nil {
+		return nil, errors.NewNotFound("closed loop trigger %s not found", id)
+	} else if trigger.State != StatePending {
+		return nil, errors.NewConflict("the action of %s is %s, not %s", id, trigger.State, StatePending)
+	}
+	// Taken off the pending ones, so that it is decided once
+	trigger.State = StateApplying
+	trigger.Decided = e.now()
+	trigger.User = user
+	return trigger, nil
+}
+
+func (e *Engine) find(id string) *Trigger {
+	for _, trigger := range e.triggers {
+		if trigger.ID == id {
+			return trigger
+		}
+	}
+	return nil
+}
+
+// Get returns a copy of a trigger
+func (e *Engine) Get(id string) (*Trigger, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	trigger := e.find(id)
+	if trigger == nil {
+		return nil, errors.NewNotFound("closed loop trigger %s not found", id)
+	}
+	copied := *trigger
+	return &copied, nil
+}
+
+// Triggers returns copies of the most recent triggers, oldest first
+func (e *Engine) Triggers() []*Trigger {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	triggers := make([]*Trigger, 0, len(e.triggers))
+	for _, trigger := range e.triggers {
+		copied := *trigger
+		triggers = append(triggers, &copied)
+	}
+	return triggers
+}
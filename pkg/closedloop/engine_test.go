@@ -0,0 +1,156 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package closedloop
+
+import (
+	"testing"
+	"time"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+const transitions = "/interfaces/interface[name=eth1]/state/counters/carrier-transitions"
+
+// testEngine returns an engine of a rule with a clock under the control of the test, and the
+// triggers it applied
+func testEngine(t *testing.T, rule *Rule) (*Engine, *time.Time, *[]*Trigger) {
+	applied := make([]*Trigger, 0)
+	engine := NewEngine(func(trigger *Trigger) (networkchange.ID, error) {
+		applied = append(applied, trigger)
+		return networkchange.ID(trigger.ID), nil
+	})
+	now := time.Unix(1000, 0)
+	engine.now = func() time.Time { return now }
+	assert.NoError(t, engine.SetRules([]*Rule{rule}))
+	return engine, &now, &applied
+}
+
+func counter(value uint) *devicechange.TypedValue {
+	return devicechange.NewTypedValueUint(value, devicechange.WidthSixtyFour)
+}
+
+func Test_ProcessWindow(t *testing.T) {
+	audit.Clear()
+	engine, now, applied := testEngine(t, flappingRule())
+
+	assert.Empty(t, engine.Process("leaf-1", transitions, counter(100)))
+	*now = now.Add(30 * time.Second)
+	assert.Empty(t, engine.Process("leaf-1", transitions, counter(104)))
+	// Other devices and leaves do not count
+	assert.Empty(t, engine.Process("spine-1", transitions, counter(200)))
+	assert.Empty(t, engine.Process("leaf-1", "/interfaces/interface[name=eth1]/state/counters/in-errors", counter(200)))
+
+	*now = now.Add(20 * time.Second)
+	triggers := engine.Process("leaf-1", transitions, counter(106))
+	assert.Len(t, triggers, 1)
+	assert.Equal(t, "106", triggers[0].Value)
+	assert.Len(t, *applied, 1)
+	trigger, err := engine.Get(triggers[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StateApplied, trigger.State)
+	assert.Equal(t, networkchange.ID(trigger.ID), trigger.ChangeID)
+	assert.Equal(t, map[string]string{"/interfaces/interface[name=eth1]/config/enabled": "false"}, trigger.Updates)
+
+	// Still met: it does not trigger again until the condition is no longer met
+	*now = now.Add(5 * time.Second)
+	assert.Empty(t, engine.Process("leaf-1", transitions, counter(107)))
+	*now = now.Add(2 * time.Minute)
+	assert.Empty(t, engine.Process("leaf-1", transitions, counter(107)))
+	*now = now.Add(10 * time.Second)
+	assert.Len(t, engine.Process("leaf-1", transitions, counter(120)), 1)
+	assert.Len(t, *applied, 2)
+
+	entries := audit.Entries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "closed-loop-trigger", entries[0].Action)
+	assert.Equal(t, "leaf-1", entries[0].Target)
+}
+
+func Test_ProcessRateLimit(t *testing.T) {
+	rule := &Rule{
+		Name:      "down",
+		Condition: Condition{Path: "/interfaces/interface[name={name}]/state/oper-status", Operator: OperatorEqual, Value: "DOWN"},
+		Action:    Action{Updates: map[string]string{"/interfaces/interface[name={name}]/config/enabled": "false"}},
+		RateLimit: RateLimit{Max: 2, Per: time.Hour},
+	}
+	engine, now, applied := testEngine(t, rule)
+	down := devicechange.NewTypedValueString("DOWN")
+
+	for _, name := range []string{"eth1", "eth2", "eth3"} {
+		engine.Process("leaf-1", "/interfaces/interface[name="+name+"]/state/oper-status", down)
+	}
+	assert.Len(t, *applied, 2)
+	triggers := engine.Triggers()
+	assert.Len(t, triggers, 3)
+	assert.Equal(t, StateSuppressed, triggers[2].State)
+
+	*now = now.Add(time.Hour + time.Second)
+	engine.Process("leaf-1", "/interfaces/interface[name=eth4]/state/oper-status", down)
+	assert.Len(t, *applied, 3)
+
+	// A deleted leaf is forgotten, so that it triggers again when it comes back
+	engine.Process("leaf-1", "/interfaces/interface[name=eth4]/state/oper-status", nil)
+	engine.Process("leaf-1", "/interfaces/interface[name=eth4]/state/oper-status", down)
+	assert.Len(t, *applied, 4)
+}
+
+func Test_Approval(t *testing.T) {
+	rule := flappingRule()
+	rule.Condition.Window = 0
+	rule.Approval = true
+	engine, _, applied := testEngine(t, rule)
+
+	triggers := engine.Process("leaf-1", transitions, counter(6))
+	assert.Len(t, triggers, 1)
+	assert.Equal(t, StatePending, triggers[0].State)
+	assert.Empty(t, *applied)
+
+	trigger, err := engine.Approve(triggers[0].ID, "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, StateApplied, trigger.State)
+	assert.Equal(t, "alice", trigger.User)
+	assert.Len(t, *applied, 1)
+
+	_, err = engine.Approve(triggers[0].ID, "bob")
+	assert.True(t, errors.IsConflict(err))
+	_, err = engine.Reject("unknown", "bob", "")
+	assert.True(t, errors.IsNotFound(err))
+
+	engine.Process("leaf-1", transitions, counter(0))
+	triggers = engine.Process("leaf-1", transitions, counter(9))
+	trigger, err = engine.Reject(triggers[0].ID, "bob", "maintenance window")
+	assert.NoError(t, err)
+	assert.Equal(t, StateRejected, trigger.State)
+	assert.Equal(t, "maintenance window", trigger.Reason)
+	assert.Len(t, *applied, 1)
+}
+
+func Test_ApplyFailure(t *testing.T) {
+	rule := flappingRule()
+	rule.Condition.Window = 0
+	engine := NewEngine(func(trigger *Trigger) (networkchange.ID, error) {
+		return "", errors.NewInvalid("leaf-1 is not known")
+	})
+	assert.NoError(t, engine.SetRules([]*Rule{rule}))
+	triggers := engine.Process("leaf-1", transitions, counter(6))
+	trigger, err := engine.Get(triggers[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StateFailed, trigger.State)
+	assert.Contains(t, trigger.Reason, "not known")
+}
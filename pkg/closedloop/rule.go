@@ -0,0 +1,331 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package closedloop triggers configuration changes on the devices whose operational state meets
+// the condition of a rule, e.g. disables an interface whose carrier flaps more than a threshold.
+//
+// The actions of a rule are templates of the paths and values to set or delete, given the
+// variables bound by the path of the condition. The actions are rate limited, recorded to the audit
+// trail and, for the rules requiring it, held until an operator approves them.
+package closedloop
+
+import (
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/devicegroup"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"gopkg.in/yaml.v2"
+)
+
+// The operators of a condition
+const (
+	OperatorGreater      = ">"
+	OperatorGreaterEqual = ">="
+	OperatorLess         = "<"
+	OperatorLessEqual    = "<="
+	OperatorEqual        = "=="
+	OperatorNotEqual     = "!="
+)
+
+// The variables every action template may use, besides those bound by the condition
+const (
+	// VariableDevice is the ID of the device whose state meets the condition
+	VariableDevice = "device"
+	// VariableValue is the value of the state meeting the condition
+	VariableValue = "value"
+)
+
+// rVariable matches a variable of the key values of the path of a condition, e.g. {name}
+var rVariable = regexp.MustCompile(`^{([a-zA-Z0-9_-]+)}$`)
+
+// rVariableUse matches the uses of the variables in the templates of an action
+var rVariableUse = regexp.MustCompile(`{([^}]*)}`)
+
+// Rule triggers an action on the devices whose state meets a condition, e.g.
+//
+//	name: disable-flapping-interfaces
+//	devices: [leaf-*]
+//	condition:
+//	  path: /interfaces/interface[name={name}]/state/counters/carrier-transitions
+//	  operator: ">"
+//	  value: "5"
+//	  window: 1m
+//	action:
+//	  updates:
+//	    /interfaces/interface[name={name}]/config/enabled: "false"
+//	rateLimit:
+//	  max: 10
+//	  per: 1h
+//	approval: true
+type Rule struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Devices are the patterns of the IDs of the devices the rule applies to, all if none
+	Devices   []string  `yaml:"devices,omitempty" json:"devices,omitempty"`
+	Condition Condition `yaml:"condition" json:"condition"`
+	Action    Action    `yaml:"action" json:"action"`
+	RateLimit RateLimit `yaml:"rateLimit,omitempty" json:"rateLimit,omitempty"`
+	// Approval holds the actions of the rule until an operator approves them
+	Approval bool `yaml:"approval,omitempty" json:"approval,omitempty"`
+	// devices are the devices the rule applies to
+	devices *devicegroup.Group
+	// path are the elements of the path of the condition
+	path []*gnmi.PathElem
+	// threshold is the numeric value of the condition, if it is a number
+	threshold *float64
+}
+
+// Condition is met by a leaf of the state of a device. A key value of the path in braces is a
+// variable, e.g. /interfaces/interface[name={name}]/state/oper-status, which matches any key
+// value and binds it for the action; '*' matches any key value or element without binding it.
+type Condition struct {
+	Path     string `yaml:"path" json:"path"`
+	Operator string `yaml:"operator" json:"operator"`
+	Value    string `yaml:"value" json:"value"`
+	// Window compares the increase of a numeric leaf, e.g. a counter, over the window instead of
+	// the value of the leaf
+	Window time.Duration `yaml:"window,omitempty" json:"window,omitempty"`
+}
+
+// Action is the configuration change a rule triggers. Its paths and values may use the variables
+// of the condition, e.g. {name}, and {device} and {value}. The values are converted to the types
+// of the leaves in the model of the device.
+type Action struct {
+	Updates map[string]string `yaml:"updates,omitempty" json:"updates,omitempty"`
+	Deletes []string          `yaml:"deletes,omitempty" json:"deletes,omitempty"`
+}
+
+// RateLimit limits the actions a rule triggers to Max in any period of Per; the triggers beyond
+// the limit are recorded but not acted on. A zero Max does not limit the actions.
+type RateLimit struct {
+	Max int           `yaml:"max,omitempty" json:"max,omitempty"`
+	Per time.Duration `yaml:"per,omitempty" json:"per,omitempty"`
+}
+
+// Validate checks the rule and compiles its condition
+func (r *Rule) Validate() error {
+	if r.Name == "" {
+		return errors.NewInvalid("closed loop rule has no name")
+	}
+	if len(r.Devices) > 0 {
+		group := &devicegroup.Group{Name: r.Name, Devices: r.Devices}
+		if err := group.Validate(); err != nil {
+			return err
+		}
+		r.devices = group
+	}
+
+	condition := r.Condition
+	if !strings.HasPrefix(condition.Path, "/") {
+		return errors.NewInvalid("rule '%s': invalid condition path '%s'", r.Name, condition.Path)
+	}
+	path, err := utils.ParseGNMIElements(utils.SplitPath(condition.Path))
+	if err != nil {
+		return errors.NewInvalid("rule '%s': invalid condition path %s: %v", r.Name, condition.Path, err)
+	}
+	variables := map[string]bool{VariableDevice: true, VariableValue: true}
+	for _, elem := range path.Elem {
+		for _, value := range elem.Key {
+			if match := rVariable.FindStringSubmatch(value); match != nil {
+				variables[match[1]] = true
+			}
+		}
+	}
+	switch condition.Operator {
+	case OperatorGreater, OperatorGreaterEqual, OperatorLess, OperatorLessEqual:
+	case OperatorEqual, OperatorNotEqual:
+	default:
+		return errors.NewInvalid("rule '%s': unknown operator '%s': expected one of %s %s %s %s %s %s", r.Name,
+			condition.Operator, OperatorGreater, OperatorGreaterEqual, OperatorLess, OperatorLessEqual, OperatorEqual, OperatorNotEqual)
+	}
+	var threshold *float64
+	if number, err := strconv.ParseFloat(condition.Value, 64); err == nil {
+		threshold = &number
+	} else if condition.Operator != OperatorEqual && condition.Operator != OperatorNotEqual {
+		return errors.NewInvalid("rule '%s': operator %s needs a number, not '%s'", r.Name, condition.Operator, condition.Value)
+	}
+	if condition.Window < 0 {
+		return errors.NewInvalid("rule '%s': negative window %s", r.Name, condition.Window)
+	} else if condition.Window > 0 && threshold == nil {
+		return errors.NewInvalid("rule '%s': a window needs a number, not '%s'", r.Name, condition.Value)
+	}
+
+	if len(r.Action.Updates) == 0 && len(r.Action.Deletes) == 0 {
+		return errors.NewInvalid("rule '%s' has no action", r.Name)
+	}
+	templates := make([]string, 0, 2*len(r.Action.Updates)+len(r.Action.Deletes))
+	for path, value := range r.Action.Updates {
+		templates = append(templates, path, value)
+	}
+	templates = append(templates, r.Action.Deletes...)
+	for _, template := range templates {
+		for _, match := range rVariableUse.FindAllStringSubmatch(template, -1) {
+			if !variables[match[1]] {
+				return errors.NewInvalid("rule '%s': unknown variable '%s' in '%s'", r.Name, match[1], template)
+			}
+		}
+	}
+	for path := range r.Action.Updates {
+		if !strings.HasPrefix(path, "/") {
+			return errors.NewInvalid("rule '%s': invalid action path '%s'", r.Name, path)
+		}
+	}
+	for _, path := range r.Action.Deletes {
+		if !strings.HasPrefix(path, "/") {
+			return errors.NewInvalid("rule '%s': invalid action path '%s'", r.Name, path)
+		}
+	}
+
+	if r.RateLimit.Max < 0 || r.RateLimit.Per < 0 {
+		return errors.NewInvalid("rule '%s': negative rate limit", r.Name)
+	} else if r.RateLimit.Max > 0 && r.RateLimit.Per == 0 {
+		return errors.NewInvalid("rule '%s': the rate limit of %d actions has no period", r.Name, r.RateLimit.Max)
+	}
+	r.path = path.Elem
+	r.threshold = threshold
+	return nil
+}
+
+// AppliesTo returns whether the rule applies to a device
+func (r *Rule) AppliesTo(deviceID devicetype.ID) bool {
+	return r.devices == nil || r.devices.Contains(deviceID, "", "")
+}
+
+// match returns the variables the path of the condition binds on a path of the state, or false
+// if the path of the condition does not match it
+func (r *Rule) match(path string) (map[string]string, bool) {
+	elems, err := utils.ParseGNMIElements(utils.SplitPath(path))
+	if err != nil || len(elems.Elem) != len(r.path) {
+		return nil, false
+	}
+	variables := make(map[string]string)
+	for i, conditionElem := range r.path {
+		elem := elems.Elem[i]
+		if conditionElem.Name != "*" && conditionElem.Name != elem.Name {
+			return nil, false
+		} else if len(elem.Key) != len(conditionElem.Key) {
+			return nil, false
+		}
+		for key, conditionValue := range conditionElem.Key {
+			value, ok := elem.Key[key]
+			if !ok {
+				return nil, false
+			}
+			if match := rVariable.FindStringSubmatch(conditionValue); match != nil {
+				variables[match[1]] = value
+			} else if conditionValue != "*" && conditionValue != value {
+				return nil, false
+			}
+		}
+	}
+	return variables, true
+}
+
+// compare returns whether a value of the state, or its increase over the window, meets the
+// condition
+func (r *Rule) compare(value string, number *float64) bool {
+	condition := r.Condition
+	if r.threshold == nil || number == nil {
+		switch condition.Operator {
+		case OperatorEqual:
+			return value == condition.Value
+		case OperatorNotEqual:
+			return value != condition.Value
+		default:
+			return false
+		}
+	}
+	switch condition.Operator {
+	case OperatorGreater:
+		return *number > *r.threshold
+	case OperatorGreaterEqual:
+		return *number >= *r.threshold
+	case OperatorLess:
+		return *number < *r.threshold
+	case OperatorLessEqual:
+		return *number <= *r.threshold
+	case OperatorEqual:
+		return *number == *r.threshold
+	default:
+		return *number != *r.threshold
+	}
+}
+
+// render renders the updates and the deletes of the action given the variables of a trigger
+func (r *Rule) render(variables map[string]string) (map[string]string, []string) {
+	expand := func(template string) string {
+		return rVariableUse.ReplaceAllStringFunc(template, func(use string) string {
+			return variables[use[1:len(use)-1]]
+		})
+	}
+	updates := make(map[string]string, len(r.Action.Updates))
+	for path, value := range r.Action.Updates {
+		updates[expand(path)] = expand(value)
+	}
+	deletes := make([]string, 0, len(r.Action.Deletes))
+	for _, path := range r.Action.Deletes {
+		deletes = append(deletes, expand(path))
+	}
+	sort.Strings(deletes)
+	return updates, deletes
+}
+
+// LoadRules reads the rules of a YAML file
+func LoadRules(path string) ([]*Rule, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]*Rule, 0)
+	if err := yaml.UnmarshalStrict(data, &rules); err != nil {
+		return nil, errors.NewInvalid("cannot parse closed loop rules file %s: %v", path, err)
+	}
+	return rules, nil
+}
+
+// numericValue returns the number of a value of the state, if it is a number
+func numericValue(value *devicechange.TypedValue) *float64 {
+	if value == nil {
+		return nil
+	}
+	var number float64
+	switch value.Type {
+	case devicechange.ValueType_INT:
+		number = float64((*devicechange.TypedInt)(value).Int())
+	case devicechange.ValueType_UINT:
+		number = float64((*devicechange.TypedUint)(value).Uint())
+	case devicechange.ValueType_DECIMAL:
+		number = (*devicechange.TypedDecimal)(value).Float()
+	case devicechange.ValueType_FLOAT:
+		number = float64((*devicechange.TypedFloat)(value).Float32())
+	case devicechange.ValueType_STRING:
+		parsed, err := strconv.ParseFloat(value.ValueToString(), 64)
+		if err != nil {
+			return nil
+		}
+		number = parsed
+	default:
+		return nil
+	}
+	return &number
+}
@@ -0,0 +1,126 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package closedloop
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func flappingRule() *Rule {
+	return &Rule{
+		Name:    "flapping",
+		Devices: []string{"leaf-*"},
+		Condition: Condition{
+			Path:     "/interfaces/interface[name={name}]/state/counters/carrier-transitions",
+			Operator: OperatorGreater,
+			Value:    "5",
+			Window:   time.Minute,
+		},
+		Action: Action{
+			Updates: map[string]string{"/interfaces/interface[name={name}]/config/enabled": "false"},
+		},
+	}
+}
+
+func Test_ValidateRule(t *testing.T) {
+	rule := flappingRule()
+	assert.NoError(t, rule.Validate())
+	assert.True(t, rule.AppliesTo("leaf-1"))
+	assert.False(t, rule.AppliesTo("spine-1"))
+
+	variables, ok := rule.match("/interfaces/interface[name=eth1]/state/counters/carrier-transitions")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"name": "eth1"}, variables)
+	_, ok = rule.match("/interfaces/interface[name=eth1]/state/counters/in-errors")
+	assert.False(t, ok)
+
+	rule = flappingRule()
+	rule.Action.Updates["/interfaces/interface[name={ifname}]/config/description"] = "flapping"
+	err := rule.Validate()
+	assert.True(t, errors.IsInvalid(err))
+	assert.Contains(t, err.Error(), "unknown variable 'ifname'")
+
+	rule = flappingRule()
+	rule.Condition.Operator = "~"
+	assert.True(t, errors.IsInvalid(rule.Validate()))
+
+	rule = flappingRule()
+	rule.Condition.Value = "DOWN"
+	assert.Contains(t, rule.Validate().Error(), "needs a number")
+
+	rule = flappingRule()
+	rule.RateLimit = RateLimit{Max: 3}
+	assert.Contains(t, rule.Validate().Error(), "has no period")
+
+	rule = flappingRule()
+	rule.Action = Action{}
+	assert.Contains(t, rule.Validate().Error(), "has no action")
+}
+
+func Test_RenderAction(t *testing.T) {
+	rule := &Rule{
+		Name:      "down",
+		Condition: Condition{Path: "/interfaces/interface[name={name}]/state/oper-status", Operator: OperatorEqual, Value: "DOWN"},
+		Action: Action{
+			Updates: map[string]string{"/interfaces/interface[name={name}]/config/description": "{device} {name} is {value}"},
+			Deletes: []string{"/interfaces/interface[name={name}]/config/mtu"},
+		},
+	}
+	assert.NoError(t, rule.Validate())
+	updates, deletes := rule.render(map[string]string{"name": "eth2", "device": "leaf-1", "value": "DOWN"})
+	assert.Equal(t, map[string]string{"/interfaces/interface[name=eth2]/config/description": "leaf-1 eth2 is DOWN"}, updates)
+	assert.Equal(t, []string{"/interfaces/interface[name=eth2]/config/mtu"}, deletes)
+}
+
+func Test_LoadRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "closedloop")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rules.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`
+- name: disable-flapping-interfaces
+  devices: [leaf-*]
+  condition:
+    path: /interfaces/interface[name={name}]/state/counters/carrier-transitions
+    operator: ">"
+    value: "5"
+    window: 1m
+  action:
+    updates:
+      /interfaces/interface[name={name}]/config/enabled: "false"
+  rateLimit:
+    max: 10
+    per: 1h
+  approval: true
+`), 0644))
+
+	engine := NewEngine(nil)
+	assert.NoError(t, engine.Load(path))
+	rules := engine.Rules()
+	assert.Len(t, rules, 1)
+	assert.Equal(t, time.Minute, rules[0].Condition.Window)
+	assert.Equal(t, RateLimit{Max: 10, Per: time.Hour}, rules[0].RateLimit)
+	assert.True(t, rules[0].Approval)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("- name: typo\n  conditon: {}\n"), 0644))
+	assert.True(t, errors.IsInvalid(engine.Load(path)))
+}
@@ -0,0 +1,183 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package watch provides the filtering and formatting of the change and operational state
+// events of onos-config itself, for clients of the diags services such as a 'watch' command
+// of the onos CLI.
+package watch
+
+import (
+	"regexp"
+	"strings"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Event kinds
+const (
+	KindNetworkChange = "network-change"
+	KindDeviceChange  = "device-change"
+	KindOpState       = "opstate"
+)
+
+// Value is a single path and value carried by an event
+type Value struct {
+	Path    string `json:"path"`
+	Value   string `json:"value,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Removed bool   `json:"removed,omitempty"`
+}
+
+// Event is a single change or state event for one device, reduced to the values
+// that passed the filter
+type Event struct {
+	Kind          string  `json:"kind"`
+	Type          string  `json:"type"`
+	ChangeID      string  `json:"changeId,omitempty"`
+	Index         uint64  `json:"index,omitempty"`
+	DeviceID      string  `json:"deviceId"`
+	DeviceVersion string  `json:"deviceVersion,omitempty"`
+	Phase         string  `json:"phase,omitempty"`
+	State         string  `json:"state,omitempty"`
+	Values        []Value `json:"values"`
+}
+
+// Filter selects events by device ID and path prefix. Both may contain the
+// wildcards used elsewhere in onos-config: '*' and '?' in device IDs and
+// '*' and '...' in paths. The path prefix matches whole path elements, i.e.
+// "/system/config" selects "/system/config/hostname" but not "/system/configuration".
+type Filter struct {
+	device     string
+	pathPrefix string
+	deviceRe   *regexp.Regexp
+	pathRe     *regexp.Regexp
+}
+
+// NewFilter creates a filter; an empty device or path prefix matches everything
+func NewFilter(device string, pathPrefix string) (*Filter, error) {
+	deviceRe, err := utils.CompileWildcardChNameRegexp(device, true)
+	if err != nil {
+		return nil, errors.NewInvalid("invalid device '%s': %v", device, err)
+	}
+	pathPrefix = strings.TrimSuffix(pathPrefix, "/")
+	prefixRe, err := utils.CompileWildcardRegexp(pathPrefix, false)
+	if err != nil {
+		return nil, errors.NewInvalid("invalid path prefix '%s': %v", pathPrefix, err)
+	}
+	// The prefix must end where a path element, or the keys of a list element, start
+	pathRe, err := regexp.Compile(prefixRe.String() + `(/|\[|$)`)
+	if err != nil {
+		return nil, errors.NewInvalid("invalid path prefix '%s': %v", pathPrefix, err)
+	}
+	return &Filter{
+		device:     device,
+		pathPrefix: pathPrefix,
+		deviceRe:   deviceRe,
+		pathRe:     pathRe,
+	}, nil
+}
+
+// MatchDevice returns true if the given device passes the filter
+func (f *Filter) MatchDevice(deviceID string) bool {
+	return f.device == "" || f.deviceRe.MatchString(deviceID)
+}
+
+// MatchPath returns true if the given path passes the filter
+func (f *Filter) MatchPath(path string) bool {
+	return f.pathPrefix == "" || f.pathRe.MatchString(path)
+}
+
+// NetworkChangeEvents converts a network change into one event per matching device.
+// Devices with no values under the path prefix are left out.
+func (f *Filter) NetworkChangeEvents(eventType string, change *networkchange.NetworkChange) []*Event {
+	events := make([]*Event, 0, len(change.Changes))
+	for _, deviceChange := range change.Changes {
+		if !f.MatchDevice(string(deviceChange.DeviceID)) {
+			continue
+		}
+		values := f.changeValues(deviceChange.Values)
+		if len(values) == 0 {
+			continue
+		}
+		events = append(events, &Event{
+			Kind:          KindNetworkChange,
+			Type:          eventType,
+			ChangeID:      string(change.ID),
+			Index:         uint64(change.Index),
+			DeviceID:      string(deviceChange.DeviceID),
+			DeviceVersion: string(deviceChange.DeviceVersion),
+			Phase:         change.Status.Phase.String(),
+			State:         change.Status.State.String(),
+			Values:        values,
+		})
+	}
+	return events
+}
+
+// DeviceChangeEvent converts a device change into an event, or returns nil if it does not match
+func (f *Filter) DeviceChangeEvent(eventType string, change *devicechange.DeviceChange) *Event {
+	if change.Change == nil || !f.MatchDevice(string(change.Change.DeviceID)) {
+		return nil
+	}
+	values := f.changeValues(change.Change.Values)
+	if len(values) == 0 {
+		return nil
+	}
+	return &Event{
+		Kind:          KindDeviceChange,
+		Type:          eventType,
+		ChangeID:      string(change.ID),
+		Index:         uint64(change.Index),
+		DeviceID:      string(change.Change.DeviceID),
+		DeviceVersion: string(change.Change.DeviceVersion),
+		Phase:         change.Status.Phase.String(),
+		State:         change.Status.State.String(),
+		Values:        values,
+	}
+}
+
+// OpStateEvent converts an operational state update into an event, or returns nil if it does not match
+func (f *Filter) OpStateEvent(eventType string, deviceID string, pathValue *devicechange.PathValue) *Event {
+	if pathValue == nil || !f.MatchDevice(deviceID) || !f.MatchPath(pathValue.Path) {
+		return nil
+	}
+	return &Event{
+		Kind:     KindOpState,
+		Type:     eventType,
+		DeviceID: deviceID,
+		Values:   []Value{newValue(pathValue.Path, pathValue.Value, false)},
+	}
+}
+
+func (f *Filter) changeValues(changeValues []*devicechange.ChangeValue) []Value {
+	values := make([]Value, 0, len(changeValues))
+	for _, changeValue := range changeValues {
+		if f.MatchPath(changeValue.Path) {
+			values = append(values, newValue(changeValue.Path, changeValue.Value, changeValue.Removed))
+		}
+	}
+	return values
+}
+
+func newValue(path string, typedValue *devicechange.TypedValue, removed bool) Value {
+	value := Value{Path: path, Removed: removed}
+	if typedValue != nil && !removed {
+		value.Value = typedValue.ValueToString()
+		value.Type = typedValue.Type.String()
+	}
+	return value
+}
@@ -0,0 +1,91 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Output formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Formatter writes events to an output stream
+type Formatter interface {
+	Write(event *Event) error
+}
+
+// NewFormatter creates a formatter for the named format writing to w
+func NewFormatter(format string, w io.Writer) (Formatter, error) {
+	switch format {
+	case "", FormatText:
+		return &textFormatter{w: w}, nil
+	case FormatJSON:
+		return &jsonFormatter{encoder: json.NewEncoder(w)}, nil
+	}
+	return nil, errors.NewInvalid("unsupported output format '%s'; expected '%s' or '%s'", format, FormatText, FormatJSON)
+}
+
+// textFormatter pretty-prints events for a terminal, e.g.
+//
+//	ADDED network-change change-1 (index 3) device-1:1.0.0 CHANGE/PENDING
+//	  /system/config/hostname = "switch1" (STRING)
+type textFormatter struct {
+	w io.Writer
+}
+
+func (f *textFormatter) Write(event *Event) error {
+	device := event.DeviceID
+	if event.DeviceVersion != "" {
+		device = fmt.Sprintf("%s:%s", event.DeviceID, event.DeviceVersion)
+	}
+	var header string
+	if event.ChangeID != "" {
+		header = fmt.Sprintf("%s %s %s (index %d) %s %s/%s\n", event.Type, event.Kind, event.ChangeID,
+			event.Index, device, event.Phase, event.State)
+	} else {
+		header = fmt.Sprintf("%s %s %s\n", event.Type, event.Kind, device)
+	}
+	if _, err := io.WriteString(f.w, header); err != nil {
+		return err
+	}
+	for _, value := range event.Values {
+		var line string
+		if value.Removed {
+			line = fmt.Sprintf("  %s (removed)\n", value.Path)
+		} else {
+			line = fmt.Sprintf("  %s = %q (%s)\n", value.Path, value.Value, value.Type)
+		}
+		if _, err := io.WriteString(f.w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonFormatter streams events as newline delimited JSON objects
+type jsonFormatter struct {
+	encoder *json.Encoder
+}
+
+func (f *jsonFormatter) Write(event *Event) error {
+	return f.encoder.Encode(event)
+}
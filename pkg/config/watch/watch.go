@@ -0,0 +1,116 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"context"
+	"io"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-api/go/onos/config/diags"
+)
+
+// NetworkChanges subscribes to network changes and writes those matching the filter
+// until the stream ends or the context is cancelled. If replay is true the existing
+// changes are written first.
+func NetworkChanges(ctx context.Context, client diags.ChangeServiceClient, filter *Filter, formatter Formatter, replay bool) error {
+	stream, err := client.ListNetworkChanges(ctx, &diags.ListNetworkChangeRequest{
+		Subscribe:     true,
+		WithoutReplay: !replay,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return ignoreCancelled(ctx, err)
+		}
+		if response.Change == nil {
+			continue
+		}
+		for _, event := range filter.NetworkChangeEvents(response.Type.String(), response.Change) {
+			if err := formatter.Write(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// DeviceChanges subscribes to the changes of a single device and writes those matching
+// the filter's path prefix until the stream ends or the context is cancelled
+func DeviceChanges(ctx context.Context, client diags.ChangeServiceClient, deviceID devicetype.ID, version devicetype.Version,
+	filter *Filter, formatter Formatter, replay bool) error {
+	stream, err := client.ListDeviceChanges(ctx, &diags.ListDeviceChangeRequest{
+		DeviceID:      deviceID,
+		DeviceVersion: version,
+		Subscribe:     true,
+		WithoutReplay: !replay,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return ignoreCancelled(ctx, err)
+		}
+		if response.Change == nil {
+			continue
+		}
+		if event := filter.DeviceChangeEvent(response.Type.String(), response.Change); event != nil {
+			if err := formatter.Write(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// OpState subscribes to the operational state of a single device and writes the
+// updates matching the filter's path prefix until the stream ends or the context is cancelled
+func OpState(ctx context.Context, client diags.OpStateDiagsClient, deviceID devicetype.ID, filter *Filter, formatter Formatter) error {
+	stream, err := client.GetOpState(ctx, &diags.OpStateRequest{
+		DeviceId:  string(deviceID),
+		Subscribe: true,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return ignoreCancelled(ctx, err)
+		}
+		if event := filter.OpStateEvent(response.Type.String(), string(deviceID), response.Pathvalue); event != nil {
+			if err := formatter.Write(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ignoreCancelled treats the stream failing because the watch was stopped as a clean exit
+func ignoreCancelled(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,150 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watch
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"gotest.tools/assert"
+)
+
+func testNetworkChange() *networkchange.NetworkChange {
+	return &networkchange.NetworkChange{
+		ID:    "change-1",
+		Index: 3,
+		Changes: []*devicechange.Change{
+			{
+				DeviceID:      "device-1",
+				DeviceVersion: "1.0.0",
+				Values: []*devicechange.ChangeValue{
+					{Path: "/system/config/hostname", Value: devicechange.NewTypedValueString("switch1")},
+					{Path: "/interfaces/interface[name=eth1]/config/mtu", Value: devicechange.NewTypedValueUint(9000, 16)},
+				},
+			},
+			{
+				DeviceID:      "device-2",
+				DeviceVersion: "1.0.0",
+				Values: []*devicechange.ChangeValue{
+					{Path: "/system/config/hostname", Removed: true},
+				},
+			},
+		},
+	}
+}
+
+func newFilter(t *testing.T, device string, pathPrefix string) *Filter {
+	filter, err := NewFilter(device, pathPrefix)
+	assert.NilError(t, err)
+	return filter
+}
+
+func Test_FilterNetworkChange(t *testing.T) {
+	events := newFilter(t, "", "").NetworkChangeEvents("ADDED", testNetworkChange())
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, 2, len(events[0].Values))
+
+	events = newFilter(t, "device-2", "").NetworkChangeEvents("ADDED", testNetworkChange())
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, "device-2", events[0].DeviceID)
+	assert.Assert(t, events[0].Values[0].Removed)
+
+	events = newFilter(t, "device-*", "/interfaces").NetworkChangeEvents("UPDATED", testNetworkChange())
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, "device-1", events[0].DeviceID)
+	assert.Equal(t, "/interfaces/interface[name=eth1]/config/mtu", events[0].Values[0].Path)
+	assert.Equal(t, "9000", events[0].Values[0].Value)
+
+	events = newFilter(t, "device-3", "").NetworkChangeEvents("ADDED", testNetworkChange())
+	assert.Equal(t, 0, len(events))
+}
+
+func Test_FilterOpState(t *testing.T) {
+	filter := newFilter(t, "device-1", "/system/state")
+	pathValue := &devicechange.PathValue{
+		Path:  "/system/state/current-datetime",
+		Value: devicechange.NewTypedValueString("2021-05-01T00:00:00Z"),
+	}
+	event := filter.OpStateEvent("ADDED", "device-1", pathValue)
+	assert.Assert(t, event != nil)
+	assert.Equal(t, KindOpState, event.Kind)
+	assert.Assert(t, filter.OpStateEvent("ADDED", "device-2", pathValue) == nil)
+	pathValue.Path = "/system/config/hostname"
+	assert.Assert(t, filter.OpStateEvent("ADDED", "device-1", pathValue) == nil)
+}
+
+func Test_FilterPathElements(t *testing.T) {
+	filter := newFilter(t, "", "/system/config/")
+	assert.Assert(t, filter.MatchPath("/system/config"))
+	assert.Assert(t, filter.MatchPath("/system/config/hostname"))
+	assert.Assert(t, !filter.MatchPath("/system/configuration/hostname"))
+
+	filter = newFilter(t, "", "/interfaces/interface")
+	assert.Assert(t, filter.MatchPath("/interfaces/interface[name=eth1]/config/mtu"))
+	assert.Assert(t, !filter.MatchPath("/interfaces/interfaces"))
+
+	filter = newFilter(t, "", "/interfaces/*/config")
+	assert.Assert(t, filter.MatchPath("/interfaces/interface/config/mtu"))
+	assert.Assert(t, !filter.MatchPath("/interfaces/interface/configs"))
+}
+
+func Test_FilterInvalid(t *testing.T) {
+	_, err := NewFilter("device-[", "")
+	assert.Assert(t, errors.IsInvalid(err))
+	_, err = NewFilter("", "/system/(config")
+	assert.Assert(t, errors.IsInvalid(err))
+}
+
+func Test_TextFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	formatter, err := NewFormatter(FormatText, &buf)
+	assert.NilError(t, err)
+	for _, event := range newFilter(t, "", "").NetworkChangeEvents("ADDED", testNetworkChange()) {
+		assert.NilError(t, formatter.Write(event))
+	}
+	assert.Equal(t, `ADDED network-change change-1 (index 3) device-1:1.0.0 CHANGE/PENDING
+  /system/config/hostname = "switch1" (STRING)
+  /interfaces/interface[name=eth1]/config/mtu = "9000" (UINT)
+ADDED network-change change-1 (index 3) device-2:1.0.0 CHANGE/PENDING
+  /system/config/hostname (removed)
+`, buf.String())
+}
+
+func Test_JSONFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	formatter, err := NewFormatter(FormatJSON, &buf)
+	assert.NilError(t, err)
+	for _, event := range newFilter(t, "", "").NetworkChangeEvents("ADDED", testNetworkChange()) {
+		assert.NilError(t, formatter.Write(event))
+	}
+	decoder := json.NewDecoder(&buf)
+	count := 0
+	for decoder.More() {
+		event := &Event{}
+		assert.NilError(t, decoder.Decode(event))
+		assert.Equal(t, "change-1", event.ChangeID)
+		count++
+	}
+	assert.Equal(t, 2, count)
+}
+
+func Test_BadFormat(t *testing.T) {
+	_, err := NewFormatter("yaml", &bytes.Buffer{})
+	assert.ErrorContains(t, err, "unsupported output format")
+}
@@ -0,0 +1,504 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Sections of the gNMI specification the checks verify
+const (
+	capabilitiesSection = "3.2 Capability Discovery"
+	getSection          = "3.3 Retrieving Snapshots of State Information"
+	setSection          = "3.4 Modifying State"
+	subscribeSection    = "3.5 Subscribing to Telemetry Updates"
+)
+
+// unknownTarget is a target no endpoint is expected to know
+const unknownTarget = "conformance-unknown-target"
+
+// unknownLeaf is appended to the configured path to make a path no schema is expected to have
+const unknownLeaf = "conformance-unknown-leaf"
+
+// onChangeSuffix is appended to the configured value to trigger an ON_CHANGE update
+const onChangeSuffix = "-on-change"
+
+// check is a conformance check
+type check struct {
+	name        string
+	section     string
+	description string
+	// needsPath marks the checks that are skipped when no path is configured
+	needsPath bool
+	run       func(ctx context.Context, s *suite) error
+}
+
+// checks are run in order; the Get and Subscribe checks rely on the value set by "set-update"
+// and "restore" sets back the value the path had before "set-update"
+var checks = []check{
+	{
+		name:        "capabilities",
+		section:     capabilitiesSection,
+		description: "Capabilities returns the gNMI version, supported encodings and named models",
+		run:         checkCapabilities,
+	},
+	{
+		name:        "get-unsupported-encoding",
+		section:     getSection,
+		description: "Get with an unsupported encoding fails with Unimplemented",
+		run:         checkGetUnsupportedEncoding,
+	},
+	{
+		name:        "get-unknown-target",
+		section:     getSection,
+		description: "Get of an unknown target fails with NotFound or InvalidArgument",
+		run:         checkGetUnknownTarget,
+	},
+	{
+		name:        "set-update",
+		section:     setSection,
+		description: "Set update returns a timestamp and an UPDATE result for the path",
+		needsPath:   true,
+		run:         checkSetUpdate,
+	},
+	{
+		name:        "get-value",
+		section:     getSection,
+		description: "Get returns the value set",
+		needsPath:   true,
+		run:         checkGetValue,
+	},
+	{
+		name:        "set-unknown-path",
+		section:     setSection,
+		description: "Set of a path outside the schema fails with InvalidArgument or NotFound",
+		needsPath:   true,
+		run:         checkSetUnknownPath,
+	},
+	{
+		name:        "subscribe-once",
+		section:     subscribeSection,
+		description: "ONCE subscription sends the value and a sync_response, then closes the stream",
+		needsPath:   true,
+		run:         checkSubscribeOnce,
+	},
+	{
+		name:        "subscribe-poll",
+		section:     subscribeSection,
+		description: "POLL subscription sends the value and a sync_response on subscribe and on each poll",
+		needsPath:   true,
+		run:         checkSubscribePoll,
+	},
+	{
+		name:        "subscribe-stream",
+		section:     subscribeSection,
+		description: "STREAM subscription sends the value and a sync_response, then an update on change",
+		needsPath:   true,
+		run:         checkSubscribeStream,
+	},
+	{
+		name:        "set-delete",
+		section:     setSection,
+		description: "Set delete returns a DELETE result for the path and removes its value",
+		needsPath:   true,
+		run:         checkSetDelete,
+	},
+	{
+		name:        "restore",
+		section:     setSection,
+		description: "Set restores the value the path had before the run",
+		needsPath:   true,
+		run:         checkRestore,
+	},
+}
+
+// suite is the state shared by the checks of a run
+type suite struct {
+	client gnmi.GNMIClient
+	config Config
+	// path is the configured path, including the target
+	path *gnmi.Path
+	// encodings are the encodings advertised by the endpoint
+	encodings []gnmi.Encoding
+	// original is the value of the path before the run; nil if it had none
+	original *gnmi.TypedValue
+	// restore is set once the original value has been read
+	restore bool
+}
+
+func checkCapabilities(ctx context.Context, s *suite) error {
+	resp, err := s.client.Capabilities(ctx, &gnmi.CapabilityRequest{})
+	if err != nil {
+		return fmt.Errorf("Capabilities failed: %v", err)
+	}
+	if resp.GNMIVersion == "" {
+		return fmt.Errorf("no gNMI version")
+	}
+	if len(resp.SupportedEncodings) == 0 {
+		return fmt.Errorf("no supported encoding")
+	}
+	for i, model := range resp.SupportedModels {
+		if model.Name == "" {
+			return fmt.Errorf("model %d has no name", i)
+		}
+	}
+	s.encodings = resp.SupportedEncodings
+	return nil
+}
+
+func checkGetUnsupportedEncoding(ctx context.Context, s *suite) error {
+	if len(s.encodings) == 0 {
+		return skipf("no supported encodings known")
+	}
+	encoding, ok := s.unsupportedEncoding()
+	if !ok {
+		return skipf("every encoding is supported")
+	}
+	_, err := s.client.Get(ctx, &gnmi.GetRequest{
+		Path:     []*gnmi.Path{{Target: s.config.Target}},
+		Encoding: encoding,
+	})
+	return expectCode(fmt.Sprintf("Get with encoding %s", encoding), err, codes.Unimplemented)
+}
+
+func checkGetUnknownTarget(ctx context.Context, s *suite) error {
+	path := &gnmi.Path{Target: unknownTarget}
+	if s.path != nil {
+		path.Elem = s.path.Elem
+	}
+	_, err := s.client.Get(ctx, &gnmi.GetRequest{
+		Path:     []*gnmi.Path{path},
+		Encoding: s.encoding(),
+	})
+	return expectCode("Get of an unknown target", err, codes.NotFound, codes.InvalidArgument)
+}
+
+func checkSetUpdate(ctx context.Context, s *suite) error {
+	original, err := s.get(ctx)
+	if err != nil {
+		return err
+	}
+	s.original = original
+	s.restore = true
+
+	resp, err := s.set(ctx, s.config.Value)
+	if err != nil {
+		return fmt.Errorf("Set failed: %v", err)
+	}
+	if resp.Timestamp == 0 {
+		return fmt.Errorf("no timestamp in the SetResponse")
+	}
+	return s.expectResult(resp, gnmi.UpdateResult_UPDATE)
+}
+
+func checkGetValue(ctx context.Context, s *suite) error {
+	value, err := s.get(ctx)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return fmt.Errorf("no value for %s", s.pathString())
+	}
+	if got := valueString(value); got != s.config.Value {
+		return fmt.Errorf("got %q for %s, expected %q", got, s.pathString(), s.config.Value)
+	}
+	return nil
+}
+
+func checkSetUnknownPath(ctx context.Context, s *suite) error {
+	path := &gnmi.Path{
+		Target: s.path.Target,
+		Elem:   append(append([]*gnmi.PathElem{}, s.path.Elem...), &gnmi.PathElem{Name: unknownLeaf}),
+	}
+	_, err := s.client.Set(ctx, &gnmi.SetRequest{
+		Update: []*gnmi.Update{{Path: path, Val: stringVal(s.config.Value)}},
+	})
+	return expectCode("Set of an unknown path", err, codes.InvalidArgument, codes.NotFound)
+}
+
+func checkSubscribeOnce(ctx context.Context, s *suite) error {
+	stream, err := s.subscribe(ctx, gnmi.SubscriptionList_ONCE, gnmi.SubscriptionMode_TARGET_DEFINED)
+	if err != nil {
+		return err
+	}
+	if err := s.expectSync(stream, s.config.Value); err != nil {
+		return err
+	}
+	resp, err := stream.Recv()
+	if err == nil {
+		return fmt.Errorf("received %v after the sync_response, expected the stream to be closed", resp)
+	} else if err != io.EOF {
+		return fmt.Errorf("stream not closed after the sync_response: %v", err)
+	}
+	return nil
+}
+
+func checkSubscribePoll(ctx context.Context, s *suite) error {
+	stream, err := s.subscribe(ctx, gnmi.SubscriptionList_POLL, gnmi.SubscriptionMode_TARGET_DEFINED)
+	if err != nil {
+		return err
+	}
+	if err := s.expectSync(stream, s.config.Value); err != nil {
+		return err
+	}
+	err = stream.Send(&gnmi.SubscribeRequest{
+		Request: &gnmi.SubscribeRequest_Poll{Poll: &gnmi.Poll{}},
+	})
+	if err != nil {
+		return fmt.Errorf("poll failed: %v", err)
+	}
+	if err := s.expectSync(stream, s.config.Value); err != nil {
+		return fmt.Errorf("after poll: %v", err)
+	}
+	return nil
+}
+
+func checkSubscribeStream(ctx context.Context, s *suite) error {
+	stream, err := s.subscribe(ctx, gnmi.SubscriptionList_STREAM, gnmi.SubscriptionMode_ON_CHANGE)
+	if err != nil {
+		return err
+	}
+	if err := s.expectSync(stream, s.config.Value); err != nil {
+		return err
+	}
+	value := s.config.Value + onChangeSuffix
+	if _, err := s.set(ctx, value); err != nil {
+		return fmt.Errorf("Set failed: %v", err)
+	}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("no update to %q: %v", value, err)
+		}
+		if s.hasValue(resp.GetUpdate(), value) {
+			return nil
+		}
+	}
+}
+
+func checkSetDelete(ctx context.Context, s *suite) error {
+	resp, err := s.client.Set(ctx, &gnmi.SetRequest{
+		Delete: []*gnmi.Path{s.path},
+	})
+	if err != nil {
+		return fmt.Errorf("Set failed: %v", err)
+	}
+	if err := s.expectResult(resp, gnmi.UpdateResult_DELETE); err != nil {
+		return err
+	}
+	value, err := s.get(ctx)
+	if err != nil {
+		return err
+	}
+	if value != nil && valueString(value) != "" {
+		return fmt.Errorf("%s still has value %q after delete", s.pathString(), valueString(value))
+	}
+	return nil
+}
+
+func checkRestore(ctx context.Context, s *suite) error {
+	if !s.restore {
+		return skipf("original value not read")
+	}
+	var request *gnmi.SetRequest
+	if s.original == nil {
+		request = &gnmi.SetRequest{Delete: []*gnmi.Path{s.path}}
+	} else {
+		request = &gnmi.SetRequest{Update: []*gnmi.Update{{Path: s.path, Val: s.original}}}
+	}
+	if _, err := s.client.Set(ctx, request); err != nil {
+		return fmt.Errorf("Set failed: %v", err)
+	}
+	return nil
+}
+
+// encoding returns the encoding the checks get and subscribe with
+func (s *suite) encoding() gnmi.Encoding {
+	for _, preferred := range []gnmi.Encoding{gnmi.Encoding_PROTO, gnmi.Encoding_JSON_IETF, gnmi.Encoding_JSON} {
+		for _, encoding := range s.encodings {
+			if encoding == preferred {
+				return encoding
+			}
+		}
+	}
+	return gnmi.Encoding_PROTO
+}
+
+// unsupportedEncoding returns an encoding the endpoint does not advertise
+func (s *suite) unsupportedEncoding() (gnmi.Encoding, bool) {
+	for _, candidate := range []gnmi.Encoding{gnmi.Encoding_ASCII, gnmi.Encoding_BYTES, gnmi.Encoding_JSON,
+		gnmi.Encoding_JSON_IETF, gnmi.Encoding_PROTO} {
+		supported := false
+		for _, encoding := range s.encodings {
+			if encoding == candidate {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return candidate, true
+		}
+	}
+	return 0, false
+}
+
+// get returns the value of the configured path, or nil if it has none
+func (s *suite) get(ctx context.Context) (*gnmi.TypedValue, error) {
+	resp, err := s.client.Get(ctx, &gnmi.GetRequest{
+		Path:     []*gnmi.Path{s.path},
+		Encoding: s.encoding(),
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Get failed: %v", err)
+	}
+	for _, notification := range resp.Notification {
+		for _, update := range notification.Update {
+			if pathString(notification.Prefix, update.Path) == s.pathString() {
+				return update.Val, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// set sets the configured path to the given value
+func (s *suite) set(ctx context.Context, value string) (*gnmi.SetResponse, error) {
+	return s.client.Set(ctx, &gnmi.SetRequest{
+		Update: []*gnmi.Update{{Path: s.path, Val: stringVal(value)}},
+	})
+}
+
+// subscribe subscribes to the configured path
+func (s *suite) subscribe(ctx context.Context, mode gnmi.SubscriptionList_Mode, subscriptionMode gnmi.SubscriptionMode) (gnmi.GNMI_SubscribeClient, error) {
+	stream, err := s.client.Subscribe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Subscribe failed: %v", err)
+	}
+	err = stream.Send(&gnmi.SubscribeRequest{
+		Request: &gnmi.SubscribeRequest_Subscribe{
+			Subscribe: &gnmi.SubscriptionList{
+				Mode:     mode,
+				Encoding: s.encoding(),
+				Subscription: []*gnmi.Subscription{
+					{Path: s.path, Mode: subscriptionMode},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Subscribe failed: %v", err)
+	}
+	return stream, nil
+}
+
+// expectSync receives from the stream until a sync_response, expecting an update of the
+// configured path to the given value before it
+func (s *suite) expectSync(stream gnmi.GNMI_SubscribeClient, value string) error {
+	found := false
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("no sync_response: %v", err)
+		}
+		if resp.GetSyncResponse() {
+			if !found {
+				return fmt.Errorf("no update of %s to %q before the sync_response", s.pathString(), value)
+			}
+			return nil
+		}
+		if s.hasValue(resp.GetUpdate(), value) {
+			found = true
+		}
+	}
+}
+
+// hasValue returns whether the notification updates the configured path to the given value
+func (s *suite) hasValue(notification *gnmi.Notification, value string) bool {
+	for _, update := range notification.GetUpdate() {
+		if pathString(notification.Prefix, update.Path) == s.pathString() && valueString(update.Val) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// expectResult checks the response has a result of the given operation for the configured path
+func (s *suite) expectResult(resp *gnmi.SetResponse, op gnmi.UpdateResult_Operation) error {
+	for _, result := range resp.Response {
+		if result.Op == op && pathString(resp.Prefix, result.Path) == s.pathString() {
+			return nil
+		}
+	}
+	return fmt.Errorf("no %s result for %s", op, s.pathString())
+}
+
+// pathString returns the configured path without its target
+func (s *suite) pathString() string {
+	return pathString(nil, s.path)
+}
+
+// expectCode checks that a request failed with one of the given codes
+func expectCode(request string, err error, expected ...codes.Code) error {
+	if err == nil {
+		return fmt.Errorf("%s succeeded, expected %v", request, expected)
+	}
+	code := status.Code(err)
+	for _, c := range expected {
+		if code == c {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s failed with %s, expected %v: %v", request, code, expected, err)
+}
+
+// pathString returns the elements of a path appended to those of its prefix
+func pathString(prefix, path *gnmi.Path) string {
+	elems := append(append([]*gnmi.PathElem{}, prefix.GetElem()...), path.GetElem()...)
+	return utils.StrPathElem(elems)
+}
+
+// valueString returns a scalar value as a string, decoding JSON strings
+func valueString(val *gnmi.TypedValue) string {
+	var raw []byte
+	switch v := val.GetValue().(type) {
+	case nil:
+		return ""
+	case *gnmi.TypedValue_JsonVal:
+		raw = v.JsonVal
+	case *gnmi.TypedValue_JsonIetfVal:
+		raw = v.JsonIetfVal
+	default:
+		return utils.StrVal(val)
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+func stringVal(value string) *gnmi.TypedValue {
+	return &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: value}}
+}
@@ -0,0 +1,180 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conformance checks that a gNMI endpoint behaves as the gNMI specification requires. It
+// runs a suite of Capabilities, Get, Set and Subscribe checks, covering their semantics and error
+// codes, against a running endpoint and reports the outcome of each check.
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// defaultTimeout bounds each check unless configured otherwise
+const defaultTimeout = 10 * time.Second
+
+// Config configures a conformance run
+type Config struct {
+	// Target is the device the checks get, set and subscribe to
+	Target string
+	// Path is a writable string leaf of the target, e.g. /system/config/motd-banner. The checks
+	// that set a value are skipped without it. Its value is restored at the end of the run.
+	Path string
+	// Value is the value the checks set on Path
+	Value string
+	// Timeout bounds each check
+	Timeout time.Duration
+}
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	// Passed means the endpoint behaved as the specification requires
+	Passed Status = "PASS"
+	// Failed means the endpoint deviated from the specification
+	Failed Status = "FAIL"
+	// Skipped means the check could not be run, e.g. because the configuration lacks a path
+	Skipped Status = "SKIP"
+)
+
+// Result is the outcome of a check
+type Result struct {
+	Name        string        `json:"name"`
+	Section     string        `json:"section"`
+	Description string        `json:"description"`
+	Status      Status        `json:"status"`
+	Message     string        `json:"message,omitempty"`
+	Duration    time.Duration `json:"duration"`
+}
+
+// Report is the outcome of a conformance run
+type Report struct {
+	Target  string    `json:"target"`
+	Started time.Time `json:"started"`
+	Results []Result  `json:"results"`
+	Passed  int       `json:"passed"`
+	Failed  int       `json:"failed"`
+	Skipped int       `json:"skipped"`
+}
+
+// OK returns whether no check failed
+func (r *Report) OK() bool {
+	return r.Failed == 0
+}
+
+// WriteText writes the report as a table, one check per line
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "gNMI conformance of target %s, started %s\n", r.Target, r.Started.UTC().Format(time.RFC3339))
+	for _, result := range r.Results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", result.Status, result.Name, result.Section,
+			result.Duration.Round(time.Millisecond), result.Message)
+	}
+	fmt.Fprintf(tw, "%d passed, %d failed, %d skipped\n", r.Passed, r.Failed, r.Skipped)
+	return tw.Flush()
+}
+
+// Run runs the conformance suite against a gNMI endpoint. The checks run in order, as later
+// ones rely on the values set by earlier ones.
+func Run(ctx context.Context, client gnmi.GNMIClient, config Config) (*Report, error) {
+	if config.Target == "" {
+		return nil, fmt.Errorf("no target given")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+	s := &suite{
+		client: client,
+		config: config,
+	}
+	if config.Path != "" {
+		path, err := utils.ParseGNMIElements(utils.SplitPath(config.Path))
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %s: %v", config.Path, err)
+		}
+		path.Target = config.Target
+		s.path = path
+	}
+
+	report := &Report{
+		Target:  config.Target,
+		Started: time.Now(),
+	}
+	for _, check := range checks {
+		result := s.run(ctx, check)
+		switch result.Status {
+		case Passed:
+			report.Passed++
+		case Failed:
+			report.Failed++
+		case Skipped:
+			report.Skipped++
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// run runs a check within the configured timeout
+func (s *suite) run(ctx context.Context, check check) Result {
+	result := Result{
+		Name:        check.name,
+		Section:     check.section,
+		Description: check.description,
+	}
+	start := time.Now()
+	var err error
+	if check.needsPath && s.path == nil {
+		err = skipf("no path to set given")
+	} else {
+		checkCtx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+		err = check.run(checkCtx, s)
+		cancel()
+	}
+	result.Duration = time.Since(start)
+
+	switch err.(type) {
+	case nil:
+		result.Status = Passed
+	case *skipError:
+		result.Status = Skipped
+		result.Message = err.Error()
+	default:
+		result.Status = Failed
+		result.Message = err.Error()
+	}
+	return result
+}
+
+// skipError is returned by the checks that cannot be run
+type skipError struct {
+	reason string
+}
+
+func (e *skipError) Error() string {
+	return e.reason
+}
+
+// skipf returns an error skipping a check
+func skipf(format string, args ...interface{}) error {
+	return &skipError{reason: fmt.Sprintf(format, args...)}
+}
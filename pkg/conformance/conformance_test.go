@@ -0,0 +1,263 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gotest.tools/assert"
+)
+
+const (
+	testTarget = "device-1"
+	testPath   = "/system/config/motd-banner"
+)
+
+// fakeServer is a gNMI server keeping string leaves of a single target in memory
+type fakeServer struct {
+	gnmi.UnimplementedGNMIServer
+	// noStreamSync makes STREAM subscriptions omit the sync_response
+	noStreamSync bool
+	mu           sync.Mutex
+	values       map[string]*gnmi.TypedValue
+	watchers     []chan string
+}
+
+func (s *fakeServer) Capabilities(ctx context.Context, req *gnmi.CapabilityRequest) (*gnmi.CapabilityResponse, error) {
+	return &gnmi.CapabilityResponse{
+		SupportedModels:    []*gnmi.ModelData{{Name: "openconfig-system", Version: "0.1.0"}},
+		SupportedEncodings: []gnmi.Encoding{gnmi.Encoding_JSON, gnmi.Encoding_PROTO},
+		GNMIVersion:        "0.7.0",
+	}, nil
+}
+
+func (s *fakeServer) Get(ctx context.Context, req *gnmi.GetRequest) (*gnmi.GetResponse, error) {
+	if req.Encoding != gnmi.Encoding_JSON && req.Encoding != gnmi.Encoding_PROTO {
+		return nil, status.Errorf(codes.Unimplemented, "unsupported encoding %s", req.Encoding)
+	}
+	resp := &gnmi.GetResponse{}
+	for _, path := range req.Path {
+		if path.Target != testTarget {
+			return nil, status.Errorf(codes.NotFound, "unknown target %s", path.Target)
+		}
+		resp.Notification = append(resp.Notification, s.notification(path))
+	}
+	return resp, nil
+}
+
+func (s *fakeServer) Set(ctx context.Context, req *gnmi.SetRequest) (*gnmi.SetResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp := &gnmi.SetResponse{Timestamp: time.Now().UnixNano()}
+	var changed []string
+	for _, path := range req.Delete {
+		delete(s.values, utils.StrPath(path))
+		changed = append(changed, utils.StrPath(path))
+		resp.Response = append(resp.Response, &gnmi.UpdateResult{Path: path, Op: gnmi.UpdateResult_DELETE})
+	}
+	for _, update := range req.Update {
+		if utils.StrPath(update.Path) != testPath {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown path %s", utils.StrPath(update.Path))
+		}
+		s.values[utils.StrPath(update.Path)] = update.Val
+		changed = append(changed, utils.StrPath(update.Path))
+		resp.Response = append(resp.Response, &gnmi.UpdateResult{Path: update.Path, Op: gnmi.UpdateResult_UPDATE})
+	}
+	for _, watcher := range s.watchers {
+		for _, path := range changed {
+			watcher <- path
+		}
+	}
+	return resp, nil
+}
+
+func (s *fakeServer) Subscribe(stream gnmi.GNMI_SubscribeServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	list := req.GetSubscribe()
+	sync := func() error {
+		for _, subscription := range list.Subscription {
+			if err := stream.Send(&gnmi.SubscribeResponse{
+				Response: &gnmi.SubscribeResponse_Update{Update: s.notification(subscription.Path)},
+			}); err != nil {
+				return err
+			}
+		}
+		if list.Mode == gnmi.SubscriptionList_STREAM && s.noStreamSync {
+			return nil
+		}
+		return stream.Send(&gnmi.SubscribeResponse{
+			Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true},
+		})
+	}
+	if err := sync(); err != nil {
+		return err
+	}
+
+	switch list.Mode {
+	case gnmi.SubscriptionList_ONCE:
+		return nil
+	case gnmi.SubscriptionList_POLL:
+		for {
+			if _, err := stream.Recv(); err != nil {
+				return nil
+			}
+			if err := sync(); err != nil {
+				return err
+			}
+		}
+	default:
+		watcher := make(chan string, 10)
+		s.mu.Lock()
+		s.watchers = append(s.watchers, watcher)
+		s.mu.Unlock()
+		for {
+			select {
+			case path := <-watcher:
+				for _, subscription := range list.Subscription {
+					if utils.StrPath(subscription.Path) != path {
+						continue
+					}
+					if err := stream.Send(&gnmi.SubscribeResponse{
+						Response: &gnmi.SubscribeResponse_Update{Update: s.notification(subscription.Path)},
+					}); err != nil {
+						return err
+					}
+				}
+			case <-stream.Context().Done():
+				return nil
+			}
+		}
+	}
+}
+
+// notification returns the value of a path
+func (s *fakeServer) notification(path *gnmi.Path) *gnmi.Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	notification := &gnmi.Notification{Timestamp: time.Now().UnixNano()}
+	if value, ok := s.values[utils.StrPath(path)]; ok {
+		notification.Update = []*gnmi.Update{{Path: path, Val: value}}
+	}
+	return notification
+}
+
+// value returns the value of a path
+func (s *fakeServer) value(path string) (*gnmi.TypedValue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[path]
+	return value, ok
+}
+
+func newClient(t *testing.T, server *fakeServer) gnmi.GNMIClient {
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	gnmi.RegisterGNMIServer(s, server)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	dialer := func(ctx context.Context, address string) (net.Conn, error) {
+		return lis.Dial()
+	}
+	conn, err := grpc.DialContext(context.Background(), "bufnet", grpc.WithContextDialer(dialer), grpc.WithInsecure())
+	assert.NilError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return gnmi.NewGNMIClient(conn)
+}
+
+func Test_Conformant(t *testing.T) {
+	server := &fakeServer{
+		values: map[string]*gnmi.TypedValue{
+			testPath: stringVal("original"),
+		},
+	}
+	report, err := Run(context.Background(), newClient(t, server), Config{
+		Target:  testTarget,
+		Path:    testPath,
+		Value:   "conformance",
+		Timeout: time.Second,
+	})
+	assert.NilError(t, err)
+	for _, result := range report.Results {
+		assert.Equal(t, result.Status, Passed, "%s: %s", result.Name, result.Message)
+	}
+	assert.Equal(t, report.Passed, len(checks))
+	assert.Assert(t, report.OK())
+	value, _ := server.value(testPath)
+	assert.Equal(t, valueString(value), "original")
+
+	var text bytes.Buffer
+	assert.NilError(t, report.WriteText(&text))
+	assert.Assert(t, bytes.Contains(text.Bytes(), []byte("11 passed, 0 failed, 0 skipped")), text.String())
+}
+
+func Test_NoStreamSync(t *testing.T) {
+	server := &fakeServer{
+		noStreamSync: true,
+		values:       map[string]*gnmi.TypedValue{},
+	}
+	report, err := Run(context.Background(), newClient(t, server), Config{
+		Target:  testTarget,
+		Path:    testPath,
+		Value:   "conformance",
+		Timeout: 100 * time.Millisecond,
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, !report.OK())
+	assert.Equal(t, report.Failed, 1)
+	for _, result := range report.Results {
+		if result.Name == "subscribe-stream" {
+			assert.Equal(t, result.Status, Failed)
+		} else {
+			assert.Equal(t, result.Status, Passed, "%s: %s", result.Name, result.Message)
+		}
+	}
+	_, ok := server.value(testPath)
+	assert.Assert(t, !ok)
+}
+
+func Test_NoPath(t *testing.T) {
+	server := &fakeServer{
+		values: map[string]*gnmi.TypedValue{},
+	}
+	report, err := Run(context.Background(), newClient(t, server), Config{
+		Target: testTarget,
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, report.OK())
+	assert.Equal(t, report.Passed, 3)
+	assert.Equal(t, report.Skipped, len(checks)-3)
+
+	_, err = Run(context.Background(), newClient(t, server), Config{})
+	assert.ErrorContains(t, err, "no target")
+}
@@ -0,0 +1,356 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package anomaly tracks how often each path of each device is changed, and flags the paths that
+// suddenly change far more often than they used to, e.g. a leaf that is normally changed monthly
+// being set every minute by a runaway controller.
+package anomaly
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var log = logging.GetLogger("controller", "change", "anomaly")
+
+const (
+	// DefaultRatio is how many times more often than usual a path must change to be anomalous
+	DefaultRatio = 10
+	// minIntervals is the number of the intervals between the changes of a path its baseline is
+	// learnt from before the path can be anomalous
+	minIntervals = 5
+	// baselineWeight is the weight of each interval in the baseline of a path, which follows the
+	// rate of the path slowly, so that a rate that lasts becomes its new normal
+	baselineWeight = 0.05
+	// recentWeight is the weight of each interval in the recent interval of a path, which follows
+	// the rate of the path within a few changes
+	recentWeight = 0.5
+	// maxCleared is the number of the anomalies that cleared the detector remembers
+	maxCleared = 100
+	// sweepInterval is how often the anomalies of the paths that stopped changing are cleared
+	sweepInterval = time.Minute
+)
+
+var (
+	anomaliesGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "onos_config_path_change_anomalies",
+		Help: "Number of the paths of a device changing anomalously more often than usual",
+	}, []string{"device_id"})
+	anomaliesCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "onos_config_path_change_anomalies_total",
+		Help: "Number of the times a path of a device started changing anomalously more often than usual",
+	}, []string{"device_id"})
+)
+
+func init() {
+	prometheus.MustRegister(anomaliesGauge, anomaliesCounter)
+}
+
+// Anomaly is a path of a device changing far more often than usual
+type Anomaly struct {
+	DeviceID devicetype.ID
+	Path     string
+	Started  time.Time
+	// Cleared is when the path changed as usual again, zero while the anomaly is ongoing
+	Cleared time.Time
+	// Baseline is the usual interval between the changes of the path, and Recent the interval
+	// between its latest changes
+	Baseline time.Duration
+	Recent   time.Duration
+	// Changes is the number of the changes of the path since the anomaly started
+	Changes int
+	// NetworkChange is the latest network change of the path
+	NetworkChange networkchange.ID
+}
+
+// EventType is the type of the events of the anomalies
+type EventType int
+
+const (
+	// EventStarted is an anomaly that started
+	EventStarted EventType = iota
+	// EventCleared is an anomaly that cleared
+	EventCleared
+)
+
+// Event is an anomaly that started or cleared
+type Event struct {
+	Type    EventType
+	Anomaly Anomaly
+}
+
+type pathKey struct {
+	deviceID devicetype.ID
+	path     string
+}
+
+// pathStats are the intervals between the changes of a path, as moving averages
+type pathStats struct {
+	last      time.Time
+	intervals int
+	baseline  float64
+	recent    float64
+	anomaly   *Anomaly
+}
+
+// Detector watches the network changes and the intervals between the changes of each path of each
+// device. A path is anomalous once its recent interval is ratio times shorter than its baseline,
+// and the anomaly clears once the path changes at most half as often, or stops changing. The
+// changes that completed before it started are replayed from the store, so that the paths start
+// with their baseline; only the anomalies starting afterwards are counted in the metrics.
+type Detector struct {
+	ratio          float64
+	networkChanges networkchangestore.Store
+	started        time.Time
+	mu             sync.RWMutex
+	recorded       map[networkchange.ID]bool
+	paths          map[pathKey]*pathStats
+	cleared        []Anomaly
+	watchers       map[chan<- Event]bool
+	watch          stream.Context
+	stop           chan struct{}
+}
+
+// NewDetector creates a detector of the paths changing ratio times more often than usual
+func NewDetector(ratio float64, networkChanges networkchangestore.Store) *Detector {
+	return &Detector{
+		ratio:          ratio,
+		networkChanges: networkChanges,
+		recorded:       make(map[networkchange.ID]bool),
+		paths:          make(map[pathKey]*pathStats),
+		watchers:       make(map[chan<- Event]bool),
+	}
+}
+
+// Ratio returns how many times more often than usual a path must change to be anomalous
+func (d *Detector) Ratio() float64 {
+	return d.ratio
+}
+
+// Start starts watching the network changes
+func (d *Detector) Start() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.watch != nil {
+		return nil
+	}
+	d.started = time.Now()
+	ch := make(chan stream.Event)
+	ctx, err := d.networkChanges.Watch(ch, networkchangestore.WithReplay())
+	if err != nil {
+		return err
+	}
+	d.watch = ctx
+	d.stop = make(chan struct{})
+	go func() {
+		for event := range ch {
+			change, ok := event.Object.(*networkchange.NetworkChange)
+			if !ok {
+				continue
+			}
+			if event.Type == stream.Deleted {
+				d.mu.Lock()
+				delete(d.recorded, change.ID)
+				d.mu.Unlock()
+				continue
+			}
+			d.Record(change)
+		}
+	}()
+	go func(stop <-chan struct{}) {
+		ticker := time.NewTicker(sweepInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				d.sweep(now)
+			case <-stop:
+				return
+			}
+		}
+	}(d.stop)
+	return nil
+}
+
+// Stop stops watching the network changes
+func (d *Detector) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.watch != nil {
+		d.watch.Close()
+		d.watch = nil
+		close(d.stop)
+	}
+}
+
+// Record records the changes of the paths of a network change once it completed; a change is
+// only recorded once
+func (d *Detector) Record(change *networkchange.NetworkChange) {
+	if change.Status.Phase != changetypes.Phase_CHANGE || change.Status.State != changetypes.State_COMPLETE {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.recorded[change.ID] {
+		return
+	}
+	d.recorded[change.ID] = true
+	live := !d.started.IsZero() && change.Updated.After(d.started)
+	for _, deviceChange := range change.Changes {
+		for _, value := range deviceChange.Values {
+			d.observe(pathKey{deviceID: deviceChange.DeviceID, path: value.Path}, change.Created, change.ID, live)
+		}
+	}
+}
+
+// observe records a change of a path at the given time
+func (d *Detector) observe(key pathKey, at time.Time, changeID networkchange.ID, live bool) {
+	stats, ok := d.paths[key]
+	if !ok {
+		d.paths[key] = &pathStats{last: at}
+		return
+	}
+	if stats.anomaly != nil {
+		stats.anomaly.Changes++
+		stats.anomaly.NetworkChange = changeID
+	}
+	if !at.After(stats.last) {
+		return
+	}
+	interval := float64(at.Sub(stats.last))
+	stats.last = at
+	stats.intervals++
+	if stats.intervals == 1 {
+		stats.baseline = interval
+		stats.recent = interval
+		return
+	}
+	stats.recent += recentWeight * (interval - stats.recent)
+
+	if stats.anomaly == nil && stats.intervals > minIntervals && stats.recent*d.ratio <= stats.baseline {
+		stats.anomaly = &Anomaly{
+			DeviceID:      key.deviceID,
+			Path:          key.path,
+			Started:       at,
+			Baseline:      time.Duration(stats.baseline),
+			Recent:        time.Duration(stats.recent),
+			Changes:       1,
+			NetworkChange: changeID,
+		}
+		log.Warnf("Path %s of %s changes every %v, where it used to change every %v",
+			key.path, key.deviceID, stats.anomaly.Recent, stats.anomaly.Baseline)
+		anomaliesGauge.WithLabelValues(string(key.deviceID)).Inc()
+		if live {
+			anomaliesCounter.WithLabelValues(string(key.deviceID)).Inc()
+		}
+		d.notify(Event{Type: EventStarted, Anomaly: *stats.anomaly})
+	} else if stats.anomaly != nil {
+		stats.anomaly.Recent = time.Duration(stats.recent)
+		if stats.recent*d.ratio/2 > stats.baseline {
+			d.clear(stats, at)
+		}
+	}
+	stats.baseline += baselineWeight * (interval - stats.baseline)
+}
+
+// sweep clears the anomalies of the paths that stopped changing, i.e. that have not changed for
+// half the time the ratio allows them to
+func (d *Detector) sweep(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, stats := range d.paths {
+		if stats.anomaly != nil && float64(now.Sub(stats.last))*d.ratio/2 > stats.baseline {
+			d.clear(stats, now)
+		}
+	}
+}
+
+// clear clears the anomaly of a path
+func (d *Detector) clear(stats *pathStats, at time.Time) {
+	anomaly := *stats.anomaly
+	stats.anomaly = nil
+	anomaly.Cleared = at
+	log.Infof("Path %s of %s changes as usual again", anomaly.Path, anomaly.DeviceID)
+	anomaliesGauge.WithLabelValues(string(anomaly.DeviceID)).Dec()
+	d.cleared = append(d.cleared, anomaly)
+	if len(d.cleared) > maxCleared {
+		d.cleared = d.cleared[len(d.cleared)-maxCleared:]
+	}
+	d.notify(Event{Type: EventCleared, Anomaly: anomaly})
+}
+
+// notify tells the watchers of an event; a watcher that is not ready misses it
+func (d *Detector) notify(event Event) {
+	for watcher := range d.watchers {
+		select {
+		case watcher <- event:
+		default:
+		}
+	}
+}
+
+// Anomalies returns the ongoing anomalies, sorted by device and path
+func (d *Detector) Anomalies() []Anomaly {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	anomalies := make([]Anomaly, 0)
+	for _, stats := range d.paths {
+		if stats.anomaly != nil {
+			anomalies = append(anomalies, *stats.anomaly)
+		}
+	}
+	sort.Slice(anomalies, func(i, j int) bool {
+		if anomalies[i].DeviceID != anomalies[j].DeviceID {
+			return anomalies[i].DeviceID < anomalies[j].DeviceID
+		}
+		return anomalies[i].Path < anomalies[j].Path
+	})
+	return anomalies
+}
+
+// Cleared returns the anomalies that cleared recently, latest first
+func (d *Detector) Cleared() []Anomaly {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	cleared := make([]Anomaly, 0, len(d.cleared))
+	for i := len(d.cleared) - 1; i >= 0; i-- {
+		cleared = append(cleared, d.cleared[i])
+	}
+	return cleared
+}
+
+// Watch sends the anomalies that start and clear to the given channel until the context is done.
+// It returns the ongoing anomalies, so that no event is missed in between.
+func (d *Detector) Watch(ctx context.Context, ch chan<- Event) []Anomaly {
+	d.mu.Lock()
+	d.watchers[ch] = true
+	d.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		d.mu.Lock()
+		delete(d.watchers, ch)
+		d.mu.Unlock()
+	}()
+	return d.Anomalies()
+}
@@ -0,0 +1,121 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/stretchr/testify/assert"
+)
+
+// newPathChange creates a complete network change of a path of device-1 created at the given time
+func newPathChange(i int, path string, created time.Time) *networkchange.NetworkChange {
+	return &networkchange.NetworkChange{
+		ID:      networkchange.ID(fmt.Sprintf("change-%d", i)),
+		Created: created,
+		Updated: created,
+		Status: changetypes.Status{
+			Phase: changetypes.Phase_CHANGE,
+			State: changetypes.State_COMPLETE,
+		},
+		Changes: []*devicechange.Change{{
+			DeviceID: "device-1",
+			Values:   []*devicechange.ChangeValue{{Path: path}},
+		}},
+	}
+}
+
+func TestDetector_Anomaly(t *testing.T) {
+	detector := NewDetector(DefaultRatio, nil)
+	events := make(chan Event, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.Empty(t, detector.Watch(ctx, events))
+
+	// The path usually changes daily, and another one every minute
+	now := time.Now().Add(-365 * 24 * time.Hour)
+	i := 0
+	for ; i < 30; i++ {
+		detector.Record(newPathChange(i, "/a/b", now))
+		detector.Record(newPathChange(1000+i, "/a/c", now.Add(time.Duration(i)*time.Minute)))
+		now = now.Add(24 * time.Hour)
+	}
+	assert.Empty(t, detector.Anomalies())
+
+	// A change is recorded once, and only once complete
+	pending := newPathChange(i, "/a/b", now)
+	pending.Status.State = changetypes.State_PENDING
+	detector.Record(pending)
+	detector.Record(newPathChange(i-1, "/a/b", now))
+	assert.Empty(t, detector.Anomalies())
+
+	// Then it changes every minute
+	for ; i < 35; i++ {
+		now = now.Add(time.Minute)
+		detector.Record(newPathChange(i, "/a/b", now))
+	}
+	anomalies := detector.Anomalies()
+	assert.Len(t, anomalies, 1)
+	assert.Equal(t, "/a/b", anomalies[0].Path)
+	assert.Equal(t, "device-1", string(anomalies[0].DeviceID))
+	assert.True(t, anomalies[0].Baseline > 12*time.Hour)
+	assert.True(t, anomalies[0].Recent*DefaultRatio <= anomalies[0].Baseline)
+	assert.True(t, anomalies[0].Changes > 0)
+	assert.Equal(t, networkchange.ID("change-34"), anomalies[0].NetworkChange)
+	event := <-events
+	assert.Equal(t, EventStarted, event.Type)
+	assert.Equal(t, "/a/b", event.Anomaly.Path)
+
+	// It clears once the path stops changing
+	detector.sweep(now.Add(time.Minute))
+	assert.Len(t, detector.Anomalies(), 1)
+	detector.sweep(now.Add(12 * time.Hour))
+	assert.Empty(t, detector.Anomalies())
+	event = <-events
+	assert.Equal(t, EventCleared, event.Type)
+	cleared := detector.Cleared()
+	assert.Len(t, cleared, 1)
+	assert.Equal(t, "/a/b", cleared[0].Path)
+	assert.False(t, cleared[0].Cleared.IsZero())
+}
+
+func TestDetector_ClearOnSlowdown(t *testing.T) {
+	detector := NewDetector(DefaultRatio, nil)
+	now := time.Now()
+	i := 0
+	for ; i < 10; i++ {
+		detector.Record(newPathChange(i, "/a/b", now))
+		now = now.Add(time.Hour)
+	}
+	for ; i < 20; i++ {
+		now = now.Add(time.Second)
+		detector.Record(newPathChange(i, "/a/b", now))
+	}
+	assert.Len(t, detector.Anomalies(), 1)
+
+	// The path changes hourly again
+	for ; i < 25; i++ {
+		now = now.Add(time.Hour)
+		detector.Record(newPathChange(i, "/a/b", now))
+	}
+	assert.Empty(t, detector.Anomalies())
+	assert.Len(t, detector.Cleared(), 1)
+}
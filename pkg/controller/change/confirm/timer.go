@@ -0,0 +1,203 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package confirm rolls back the network changes of commit-confirmed Sets that are not confirmed
+// before their deadline.
+package confirm
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/audit"
+	networkchangectl "github.com/onosproject/onos-config/pkg/controller/change/network"
+	"github.com/onosproject/onos-config/pkg/store/change/confirmation"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+	leadershipstore "github.com/onosproject/onos-config/pkg/store/leadership"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+)
+
+var log = logging.GetLogger("controller", "change", "confirm")
+
+// DefaultInterval is how often the deadlines are checked
+const DefaultInterval = time.Second
+
+// maxRetryDelay bounds the delay between the attempts to roll back a change that cannot be rolled back yet
+const maxRetryDelay = time.Minute
+
+// retry is when a change that could not be rolled back is tried again
+type retry struct {
+	next  time.Time
+	delay time.Duration
+}
+
+// Timer periodically checks the deadlines of the awaited confirmations on the leader, and rolls
+// back the network changes whose deadline has passed. The confirmations are kept in their store
+// until their change is rolled back, so a new leader rolls back the changes the previous one was
+// waiting for, and a change that cannot be rolled back yet is tried again.
+type Timer struct {
+	interval       time.Duration
+	leadership     leadershipstore.Store
+	confirmations  confirmation.Store
+	networkChanges networkchangestore.Store
+	mu             sync.Mutex
+	stop           chan struct{}
+	checkMu        sync.Mutex
+	retries        map[networkchange.ID]*retry
+}
+
+// NewTimer creates a timer checking the deadlines every interval, DefaultInterval if zero
+func NewTimer(interval time.Duration, leadership leadershipstore.Store, confirmations confirmation.Store,
+	networkChanges networkchangestore.Store) *Timer {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Timer{
+		interval:       interval,
+		leadership:     leadership,
+		confirmations:  confirmations,
+		networkChanges: networkChanges,
+		retries:        make(map[networkchange.ID]*retry),
+	}
+}
+
+// Start starts checking the deadlines periodically
+func (t *Timer) Start() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stop != nil {
+		return
+	}
+	t.stop = make(chan struct{})
+	go t.run(t.stop)
+	log.Infof("Confirmation timer started")
+}
+
+// Stop stops the timer
+func (t *Timer) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stop != nil {
+		close(t.stop)
+		t.stop = nil
+	}
+}
+
+func (t *Timer) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if leader, err := t.leadership.IsLeader(); err != nil || !leader {
+				continue
+			}
+			if err := t.Check(time.Now()); err != nil {
+				log.Warnf("Confirmation timer could not check the deadlines: %v", err)
+			}
+		}
+	}
+}
+
+// Check rolls back the network changes whose confirmation deadline has passed. A confirmation is
+// only deleted once its change is rolled back or gone: a change that cannot be rolled back, e.g.
+// because a later change was made, is tried again at later checks, less and less often.
+func (t *Timer) Check(now time.Time) error {
+	t.checkMu.Lock()
+	defer t.checkMu.Unlock()
+	confirmations, err := t.confirmations.List()
+	if err != nil {
+		return err
+	}
+	expired := make(map[networkchange.ID]bool)
+	for _, c := range confirmations {
+		// The confirmations are sorted by deadline
+		if now.Before(c.Deadline) {
+			break
+		}
+		expired[c.NetworkChangeID] = true
+		r, retrying := t.retries[c.NetworkChangeID]
+		if retrying && now.Before(r.next) {
+			continue
+		}
+
+		message := fmt.Sprintf("not confirmed within %s, rolled back", c.Timeout)
+		if err := t.expire(c, message); errors.IsNotFound(err) {
+			message = fmt.Sprintf("not confirmed within %s, already deleted", c.Timeout)
+		} else if err != nil {
+			// The confirmation is kept for the change to be rolled back at a later check
+			if !retrying {
+				r = &retry{delay: t.interval}
+				t.retries[c.NetworkChangeID] = r
+				audit.Record(audit.Entry{
+					User:    c.User,
+					Action:  "confirmation-expired",
+					Target:  string(c.NetworkChangeID),
+					Message: fmt.Sprintf("not confirmed within %s, cannot be rolled back yet: %v", c.Timeout, err),
+				})
+			} else {
+				r.delay *= 2
+				if r.delay > maxRetryDelay {
+					r.delay = maxRetryDelay
+				}
+			}
+			r.next = now.Add(r.delay)
+			log.Warnf("Cannot roll back NetworkChange %s not confirmed within %s, retrying in %s: %v", c.NetworkChangeID, c.Timeout, r.delay, err)
+			continue
+		}
+
+		if err := t.confirmations.Delete(c.NetworkChangeID); err != nil && !errors.IsNotFound(err) {
+			log.Warnf("Cannot delete the confirmation of NetworkChange %s: %v", c.NetworkChangeID, err)
+		}
+		delete(t.retries, c.NetworkChangeID)
+		audit.Record(audit.Entry{
+			User:    c.User,
+			Action:  "confirmation-expired",
+			Target:  string(c.NetworkChangeID),
+			Message: message,
+		})
+	}
+
+	// The confirmations deleted elsewhere are not retried
+	for id := range t.retries {
+		if !expired[id] {
+			delete(t.retries, id)
+		}
+	}
+	return nil
+}
+
+// expire rolls back a network change that was not confirmed
+func (t *Timer) expire(c *confirmation.Confirmation, message string) error {
+	change, err := t.networkChanges.Get(c.NetworkChangeID)
+	if err != nil {
+		return err
+	} else if change == nil || change.Deleted {
+		return errors.NewNotFound("network change '%s' not found", c.NetworkChangeID)
+	}
+	// A change already rolled back, e.g. by an administrator, is left as it is
+	if change.Status.Phase == changetypes.Phase_ROLLBACK {
+		return nil
+	}
+	if err := networkchangectl.CheckLast(t.networkChanges, change); err != nil {
+		return err
+	}
+	return networkchangectl.Rollback(t.networkChanges, change, "Commit-confirmed change "+message)
+}
@@ -0,0 +1,137 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confirm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/store/change/confirmation"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newChange(id networkchange.ID) *networkchange.NetworkChange {
+	return &networkchange.NetworkChange{
+		ID: id,
+		Changes: []*devicechange.Change{{
+			DeviceID:      "device-1",
+			DeviceVersion: "1.0.0",
+			DeviceType:    "Stratum",
+			Values: []*devicechange.ChangeValue{{
+				Path:  "/system/config/hostname",
+				Value: devicechange.NewTypedValueString(string(id)),
+			}},
+		}},
+		Status: changetypes.Status{Incarnation: 1, State: changetypes.State_COMPLETE},
+	}
+}
+
+func TestTimer_Check(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+	atomixClient, err := test.NewClient("test")
+	assert.NoError(t, err)
+
+	networkChanges, err := networkchangestore.NewAtomixStore(atomixClient)
+	assert.NoError(t, err)
+	defer networkChanges.Close()
+	confirmations := confirmation.NewLocalStore()
+	timer := NewTimer(0, nil, confirmations, networkChanges)
+
+	now := time.Now()
+	assert.NoError(t, networkChanges.Create(newChange("change-1")))
+	assert.NoError(t, confirmations.Create(&confirmation.Confirmation{
+		NetworkChangeID: "change-1",
+		User:            "alice",
+		Timeout:         time.Minute,
+		Deadline:        now.Add(time.Minute),
+	}))
+
+	// Nothing happens before the deadline
+	assert.NoError(t, timer.Check(now.Add(30*time.Second)))
+	change, err := networkChanges.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.Phase_CHANGE, change.Status.Phase)
+	_, err = confirmations.Get("change-1")
+	assert.NoError(t, err)
+
+	// The change is rolled back once the deadline has passed
+	assert.NoError(t, timer.Check(now.Add(time.Minute)))
+	change, err = networkChanges.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.Phase_ROLLBACK, change.Status.Phase)
+	assert.Equal(t, changetypes.State_PENDING, change.Status.State)
+	assert.Equal(t, uint64(2), change.Status.Incarnation)
+	assert.Contains(t, change.Status.Message, "not confirmed within 1m0s")
+	_, err = confirmations.Get("change-1")
+	assert.True(t, errors.IsNotFound(err))
+
+	// A change followed by another one cannot be rolled back yet; its confirmation is kept
+	change.Status.Phase = changetypes.Phase_ROLLBACK
+	change.Status.State = changetypes.State_COMPLETE
+	assert.NoError(t, networkChanges.Update(change))
+	assert.NoError(t, networkChanges.Create(newChange("change-2")))
+	assert.NoError(t, networkChanges.Create(newChange("change-3")))
+	assert.NoError(t, confirmations.Create(&confirmation.Confirmation{
+		NetworkChangeID: "change-2",
+		Timeout:         time.Minute,
+		Deadline:        now.Add(time.Minute),
+	}))
+	assert.NoError(t, timer.Check(now.Add(2*time.Minute)))
+	change, err = networkChanges.Get("change-2")
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.Phase_CHANGE, change.Status.Phase)
+	_, err = confirmations.Get("change-2")
+	assert.NoError(t, err)
+
+	// It is tried again once the retry delay has passed, and rolled back once the later change is
+	change3, err := networkChanges.Get("change-3")
+	assert.NoError(t, err)
+	change3.Status.Phase = changetypes.Phase_ROLLBACK
+	change3.Status.State = changetypes.State_COMPLETE
+	assert.NoError(t, networkChanges.Update(change3))
+	assert.NoError(t, timer.Check(now.Add(2*time.Minute)))
+	change, err = networkChanges.Get("change-2")
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.Phase_CHANGE, change.Status.Phase)
+	assert.NoError(t, timer.Check(now.Add(2*time.Minute+DefaultInterval)))
+	change, err = networkChanges.Get("change-2")
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.Phase_ROLLBACK, change.Status.Phase)
+	_, err = confirmations.Get("change-2")
+	assert.True(t, errors.IsNotFound(err))
+	assert.Len(t, timer.retries, 0)
+
+	// The confirmation of a change that is gone is dropped
+	assert.NoError(t, confirmations.Create(&confirmation.Confirmation{
+		NetworkChangeID: "change-4",
+		Timeout:         time.Minute,
+		Deadline:        now.Add(time.Minute),
+	}))
+	assert.NoError(t, timer.Check(now.Add(3*time.Minute)))
+	_, err = confirmations.Get("change-4")
+	assert.True(t, errors.IsNotFound(err))
+}
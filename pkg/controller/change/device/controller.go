@@ -18,20 +18,21 @@ import (
 	"fmt"
 	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
 	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
 	"github.com/onosproject/onos-api/go/onos/topo"
 	configcontroller "github.com/onosproject/onos-config/pkg/controller"
 	topodevice "github.com/onosproject/onos-config/pkg/device"
 	"github.com/onosproject/onos-config/pkg/southbound"
 	changestore "github.com/onosproject/onos-config/pkg/store/change/device"
 	devicechangeutils "github.com/onosproject/onos-config/pkg/store/change/device/utils"
+	"github.com/onosproject/onos-config/pkg/store/change/push"
 	devicestore "github.com/onosproject/onos-config/pkg/store/device"
 	"github.com/onosproject/onos-config/pkg/store/device/cache"
 	mastershipstore "github.com/onosproject/onos-config/pkg/store/mastership"
-	"github.com/onosproject/onos-config/pkg/utils/values"
 	"github.com/onosproject/onos-lib-go/pkg/controller"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
-	"strings"
+	"sync"
 )
 
 var log = logging.GetLogger("controller", "change", "device")
@@ -41,19 +42,21 @@ func NewController(mastership mastershipstore.Store, devices devicestore.Store,
 	cache cache.Cache, changes changestore.Store) *controller.Controller {
 
 	c := controller.NewController("DeviceChange")
-	c.Filter(&configcontroller.MastershipFilter{
+	queue := configcontroller.NewQueue("DeviceChange", &Partitioner{})
+	c.Filter(queue.Filter(&configcontroller.MastershipFilter{
 		Store:    mastership,
 		Resolver: &Resolver{},
-	})
+	}))
 	c.Partition(&Partitioner{})
 	c.Watch(&Watcher{
 		DeviceCache: cache,
 		ChangeStore: changes,
 	})
-	c.Reconcile(&Reconciler{
+	c.Reconcile(queue.Reconcile(&Reconciler{
 		devices: devices,
 		changes: changes,
-	})
+		order:   newChangeOrder(changes),
+	}))
 	return c
 }
 
@@ -68,8 +71,20 @@ func (r *Resolver) Resolve(id controller.ID) (topodevice.ID, error) {
 
 // Reconciler is a device change reconciler
 type Reconciler struct {
-	devices devicestore.Store
-	changes changestore.Store
+	devices   devicestore.Store
+	changes   changestore.Store
+	order     *changeOrder
+	orderOnce sync.Once
+}
+
+// getOrder returns the index of the changes to each device, creating it on first use
+func (r *Reconciler) getOrder() *changeOrder {
+	r.orderOnce.Do(func() {
+		if r.order == nil {
+			r.order = newChangeOrder(r.changes)
+		}
+	})
+	return r.order
 }
 
 // Reconcile reconciles the state of a device change
@@ -85,6 +100,7 @@ func (r *Reconciler) Reconcile(id controller.ID) (controller.Result, error) {
 
 	log.Infof("Reconciling DeviceChange %s", change.ID)
 	log.Debug(change)
+	r.getOrder().update(change)
 
 	// The device controller only needs to handle changes in the RUNNING state
 	if change.Status.Incarnation == 0 || change.Status.State != changetypes.State_PENDING {
@@ -100,20 +116,57 @@ func (r *Reconciler) Reconcile(id controller.ID) (controller.Result, error) {
 		return controller.Result{}, errors.NewNotFound("device '%s' is not connected", change.Change.DeviceID)
 	}
 
+	// No change is pushed to a device whose capabilities do not match its model, until it is
+	// bound to a matching model
+	if quarantines := southbound.GetQuarantineStore(); quarantines != nil {
+		if _, err := quarantines.Get(change.Change.DeviceID); err == nil {
+			return controller.Result{}, errors.NewUnavailable("device '%s' is quarantined", change.Change.DeviceID)
+		} else if !errors.IsNotFound(err) {
+			return controller.Result{}, err
+		}
+	}
+
 	// Handle the change for each phase
 	switch change.Status.Phase {
 	case changetypes.Phase_CHANGE:
-		return r.reconcileChange(change)
+		// No change of a paused network change is pushed until the network change is resumed.
+		// Rollbacks are not paused, so that a paused network change can still be rolled back.
+		if pauses := GetPauseStore(); pauses != nil {
+			if _, err := pauses.Get(networkchange.ID(change.NetworkChange.ID)); err == nil {
+				return controller.Result{}, errors.NewUnavailable("network change '%s' is paused", change.NetworkChange.ID)
+			} else if !errors.IsNotFound(err) {
+				return controller.Result{}, err
+			}
+		}
+		// Changes are applied to a device in network change index order, whichever node applies
+		// them, so a change waits for the changes to the device that precede it to be applied,
+		// including those not started yet or paused.
+		preceding, err := r.getOrder().preceding(change)
+		if err != nil {
+			return controller.Result{}, err
+		} else if preceding != nil {
+			return controller.Result{}, errors.NewUnavailable("device change %s waits for %s to be applied", change.ID, preceding.ID)
+		}
+		return r.reconcileChange(device, change)
 	case changetypes.Phase_ROLLBACK:
-		return r.reconcileRollback(change)
+		return r.reconcileRollback(device, change)
 	}
 	return controller.Result{}, nil
 }
 
 // reconcileChange reconciles a CHANGE in the RUNNING state
-func (r *Reconciler) reconcileChange(change *devicechange.DeviceChange) (controller.Result, error) {
+func (r *Reconciler) reconcileChange(device *topodevice.Device, change *devicechange.DeviceChange) (controller.Result, error) {
+	// A change the device already applied is not pushed to it again, e.g. after a restart
+	pushed, err := r.beginPush(change)
+	if err != nil {
+		return controller.Result{}, err
+	}
+
 	// Attempt to apply the change to the device and update the change with the result
-	if err := r.doChange(change); err != nil {
+	if pushed {
+		change.Status.State = changetypes.State_COMPLETE
+		log.Infof("Completing DeviceChange %s", change.ID)
+	} else if err := r.doChange(device, change); err != nil {
 		change.Status.State = changetypes.State_FAILED
 		change.Status.Reason = changetypes.Reason_ERROR
 		change.Status.Message = err.Error()
@@ -129,20 +182,30 @@ func (r *Reconciler) reconcileChange(change *devicechange.DeviceChange) (control
 		log.Warnf("error updating device change %s %v", err.Error(), change)
 		return controller.Result{}, err
 	}
+	r.getOrder().update(change)
 	return controller.Result{}, nil
 }
 
 // doChange pushes the given change to the device
-func (r *Reconciler) doChange(change *devicechange.DeviceChange) error {
+func (r *Reconciler) doChange(device *topodevice.Device, change *devicechange.DeviceChange) error {
 	log.Infof("Applying change %v ", change.ID)
 	log.Debugf("%v ", change.Change)
-	return r.translateAndSendChange(change.Change)
+	return r.translateAndSendChange(device, change, change.Change)
 }
 
 // reconcileRollback reconciles a ROLLBACK in the RUNNING state
-func (r *Reconciler) reconcileRollback(change *devicechange.DeviceChange) (controller.Result, error) {
+func (r *Reconciler) reconcileRollback(device *topodevice.Device, change *devicechange.DeviceChange) (controller.Result, error) {
+	// A rollback the device already applied is not pushed to it again, e.g. after a restart
+	pushed, err := r.beginPush(change)
+	if err != nil {
+		return controller.Result{}, err
+	}
+
 	// Attempt to roll back the change to the device and update the change with the result
-	if err := r.doRollback(change); err != nil {
+	if pushed {
+		change.Status.State = changetypes.State_COMPLETE
+		log.Infof("Completing DeviceChange %v", change.ID)
+	} else if err := r.doRollback(device, change); err != nil {
 		change.Status.State = changetypes.State_FAILED
 		change.Status.Reason = changetypes.Reason_ERROR
 		change.Status.Message = err.Error()
@@ -158,11 +221,12 @@ func (r *Reconciler) reconcileRollback(change *devicechange.DeviceChange) (contr
 		log.Warnf("error updating device change %s %v", err.Error(), change)
 		return controller.Result{}, err
 	}
+	r.getOrder().update(change)
 	return controller.Result{}, nil
 }
 
 // doRollback rolls back a change on the device
-func (r *Reconciler) doRollback(change *devicechange.DeviceChange) error {
+func (r *Reconciler) doRollback(device *topodevice.Device, change *devicechange.DeviceChange) error {
 	log.Infof("Executing Rollback for %s", change.ID)
 	log.Debug(change)
 	deltaChange, err := r.computeRollback(change)
@@ -171,15 +235,20 @@ func (r *Reconciler) doRollback(change *devicechange.DeviceChange) error {
 	}
 	log.Infof("Rolling back %s with %v", change.ID, deltaChange)
 	log.Debugf("%v", change)
-	return r.translateAndSendChange(deltaChange)
+	return r.translateAndSendChange(device, change, deltaChange)
 }
 
-func (r *Reconciler) translateAndSendChange(change *devicechange.Change) error {
-	setRequest, err := values.NativeChangeToGnmiChange(change)
+// translateAndSendChange pushes a change for a device change to its device, with the idempotency
+// key of the push, and records that the device acknowledged it. A change exceeding the limits of
+// the Sets of the device is pushed with a series of Sets, resuming from the first Set not
+// acknowledged when a push was interrupted; if one of them is rejected, the Sets accepted before it
+// are undone.
+func (r *Reconciler) translateAndSendChange(device *topodevice.Device, deviceChange *devicechange.DeviceChange, change *devicechange.Change) error {
+	key := push.NewKey(deviceChange)
+	chunks, err := splitChange(device, change, key)
 	if err != nil {
 		return err
 	}
-	log.Infof("Reconciler set request for %s:%s, %v", change.DeviceID, change.DeviceVersion, setRequest)
 	deviceTarget, err := southbound.GetTarget(change.GetVersionedDeviceID())
 	if err != nil {
 		log.Infof("Device %s:%s (%s) is not connected, accepting change",
@@ -187,12 +256,33 @@ func (r *Reconciler) translateAndSendChange(change *devicechange.Change) error {
 		return fmt.Errorf("device not connected %s:%s, error %s", change.DeviceID, change.DeviceVersion, err.Error())
 	}
 	log.Infof("Target for device %s:%s %v %v", change.DeviceID, change.DeviceVersion, deviceTarget, deviceTarget.Context())
-	setResponse, err := deviceTarget.Set(*deviceTarget.Context(), setRequest)
-	if err != nil {
-		log.Warn("Error while doing set: ", err)
-		return err
+	first := r.acknowledgedSets(deviceChange, len(chunks))
+	if first > 0 {
+		log.Infof("Resuming the push of %s to %s from Set %d of %d", deviceChange.ID, change.DeviceID, first+1, len(chunks))
+	} else if len(chunks) > 1 {
+		log.Infof("Pushing %s to %s with %d Sets", deviceChange.ID, change.DeviceID, len(chunks))
 	}
-	log.Info(change.DeviceID, " SetResponse ", setResponse)
+	for i := first; i < len(chunks); i++ {
+		chunk := chunks[i]
+		log.Infof("Reconciler set request for %s:%s, %v", change.DeviceID, change.DeviceVersion, chunk.request)
+		setResponse, err := deviceTarget.Set(*deviceTarget.Context(), chunk.request)
+		if err != nil {
+			log.Warn("Error while doing set: ", err)
+			nack := southbound.NewNack(err, chunk.request)
+			log.Infof("Device %s rejected %s: %s (%s)", change.DeviceID, deviceChange.ID, nack.Message, nack.Category)
+			r.rejectPush(deviceChange, nack)
+			if i == 0 {
+				return err
+			}
+			return r.undoSets(device, deviceTarget, deviceChange, change, chunks[:i],
+				fmt.Errorf("set %d of %d rejected: %v", i+1, len(chunks), err))
+		}
+		log.Info(change.DeviceID, " SetResponse ", setResponse)
+		if len(chunks) > 1 {
+			r.acknowledgeSets(deviceChange, i+1, len(chunks))
+		}
+	}
+	r.endPush(deviceChange)
 	return nil
 }
 
@@ -213,40 +303,12 @@ func getProtocolState(device *topodevice.Device) topo.ChannelState {
 
 // computeRollback returns a change containing the previous value for each path of the rollbackChange
 func (r *Reconciler) computeRollback(deviceChange *devicechange.DeviceChange) (*devicechange.Change, error) {
-	//TODO We might want to consider doing reverse iteration to get the previous value for a path instead of
-	// reading up to the previous change for the target. see comments on PR #805
-	previousValues := make([]*devicechange.ChangeValue, 0)
 	prevValues, err := devicechangeutils.ExtractFullConfig(deviceChange.Change.GetVersionedDeviceID(), nil, r.changes, 0)
 	if err != nil {
 		return nil, fmt.Errorf("can't get last config on network config %s for target %s, %s",
 			string(deviceChange.ID), deviceChange.Change.DeviceID, err)
 	}
-	rollbackChange := deviceChange.Change
-	alreadyUpdated := make(map[string]struct{})
-	for _, rbValue := range rollbackChange.Values {
-		for _, prevVal := range prevValues {
-			if prevVal.Path == rbValue.Path ||
-				rbValue.Removed && strings.HasPrefix(prevVal.Path, rbValue.Path) {
-				alreadyUpdated[rbValue.Path] = struct{}{}
-				previousValues = append(previousValues, &devicechange.ChangeValue{
-					Path:  prevVal.Path,
-					Value: prevVal.Value,
-				})
-			}
-		}
-		if _, ok := alreadyUpdated[rbValue.Path]; !ok {
-			previousValues = append(previousValues, &devicechange.ChangeValue{
-				Path:    rbValue.Path,
-				Removed: true,
-			})
-		}
-	}
-	deltaChange := &devicechange.Change{
-		DeviceID:      rollbackChange.DeviceID,
-		DeviceVersion: rollbackChange.DeviceVersion,
-		Values:        previousValues,
-	}
-	return deltaChange, nil
+	return devicechangeutils.ComputeRollback(deviceChange.Change, prevValues), nil
 }
 
 var _ controller.Reconciler = &Reconciler{}
@@ -23,6 +23,7 @@ import (
 	types "github.com/onosproject/onos-api/go/onos/config"
 	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
 	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
 	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
 	"github.com/onosproject/onos-api/go/onos/topo"
 	configmodel "github.com/onosproject/onos-config-model/pkg/model"
@@ -33,7 +34,10 @@ import (
 	"github.com/onosproject/onos-config/pkg/southbound/synchronizer"
 	devicechanges "github.com/onosproject/onos-config/pkg/store/change/device"
 	devicechangeutils "github.com/onosproject/onos-config/pkg/store/change/device/utils"
+	"github.com/onosproject/onos-config/pkg/store/change/pause"
+	"github.com/onosproject/onos-config/pkg/store/change/push"
 	devicestore "github.com/onosproject/onos-config/pkg/store/device"
+	"github.com/onosproject/onos-config/pkg/store/quarantine"
 	"github.com/onosproject/onos-config/pkg/store/stream"
 	"github.com/onosproject/onos-config/pkg/test/mocks"
 	southboundmock "github.com/onosproject/onos-config/pkg/test/mocks/southbound"
@@ -43,6 +47,8 @@ import (
 	"github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"io"
 	"sync"
 	"testing"
@@ -155,6 +161,364 @@ func TestReconcilerChangeSuccess(t *testing.T) {
 	assert.Equal(t, changetypes.State_COMPLETE, deviceChange2.Status.State)
 }
 
+func TestReconcilerQuarantinedDevice(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	devices, deviceChanges := newStores(t, test)
+	defer deviceChanges.Close()
+
+	quarantines := quarantine.NewLocalStore()
+	southbound.SetQuarantineStore(quarantines)
+	defer southbound.SetQuarantineStore(nil)
+	assert.NoError(t, quarantines.Put(&quarantine.Quarantine{DeviceID: device1, DeviceVersion: v1}))
+
+	reconciler := &Reconciler{
+		devices: devices,
+		changes: deviceChanges,
+	}
+
+	deviceChange1 := newChange(1, device1, v1)
+	deviceChange1.Status.Incarnation = 1
+	err := deviceChanges.Create(deviceChange1)
+	assert.NoError(t, err)
+
+	// The change is not pushed to the quarantined device
+	_, err = reconciler.Reconcile(controller.NewID(string(deviceChange1.ID)))
+	assert.True(t, errors.IsUnavailable(err))
+	deviceChange1, err = deviceChanges.Get(change1)
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.State_PENDING, deviceChange1.Status.State)
+
+	// It is pushed once the quarantine is lifted
+	assert.NoError(t, quarantines.Delete(device1))
+	_, err = reconciler.Reconcile(controller.NewID(string(deviceChange1.ID)))
+	assert.NoError(t, err)
+	deviceChange1, err = deviceChanges.Get(change1)
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.State_COMPLETE, deviceChange1.Status.State)
+}
+
+func TestReconcilerPausedChange(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	devices, deviceChanges := newStores(t, test)
+	defer deviceChanges.Close()
+
+	pauses := pause.NewLocalStore()
+	SetPauseStore(pauses)
+	defer SetPauseStore(nil)
+
+	reconciler := &Reconciler{
+		devices: devices,
+		changes: deviceChanges,
+	}
+
+	deviceChange1 := newChange(1, device1, v1)
+	deviceChange1.Status.Incarnation = 1
+	err := deviceChanges.Create(deviceChange1)
+	assert.NoError(t, err)
+	assert.NoError(t, pauses.Put(&pause.Pause{NetworkChangeID: networkchange.ID(deviceChange1.NetworkChange.ID)}))
+
+	// The change is not pushed while its network change is paused
+	_, err = reconciler.Reconcile(controller.NewID(string(deviceChange1.ID)))
+	assert.True(t, errors.IsUnavailable(err))
+	deviceChange1, err = deviceChanges.Get(change1)
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.State_PENDING, deviceChange1.Status.State)
+
+	// It is pushed once the network change is resumed
+	assert.NoError(t, pauses.Delete(networkchange.ID(deviceChange1.NetworkChange.ID)))
+	_, err = reconciler.Reconcile(controller.NewID(string(deviceChange1.ID)))
+	assert.NoError(t, err)
+	deviceChange1, err = deviceChanges.Get(change1)
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.State_COMPLETE, deviceChange1.Status.State)
+}
+
+func TestReconcilerChangeOrder(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	devices, deviceChanges := newStores(t, test)
+	defer deviceChanges.Close()
+
+	reconciler := &Reconciler{
+		devices: devices,
+		changes: deviceChanges,
+	}
+
+	// The first change to device-1 is not applied yet when the second one is
+	first := newChange(1, device1, v1)
+	first.NetworkChange.ID = "change-1"
+	first.Status.Incarnation = 1
+	assert.NoError(t, deviceChanges.Create(first))
+	second := newChange(2, device1, v1)
+	second.NetworkChange.ID = "change-2"
+	second.Status.Incarnation = 1
+	assert.NoError(t, deviceChanges.Create(second))
+
+	// The second change waits for the first one
+	_, err := reconciler.Reconcile(controller.NewID(string(second.ID)))
+	assert.True(t, errors.IsUnavailable(err))
+	second, err = deviceChanges.Get(second.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.State_PENDING, second.Status.State)
+
+	// Once the first change is applied, the second one is applied too
+	_, err = reconciler.Reconcile(controller.NewID(string(first.ID)))
+	assert.NoError(t, err)
+	_, err = reconciler.Reconcile(controller.NewID(string(second.ID)))
+	assert.NoError(t, err)
+	second, err = deviceChanges.Get(second.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.State_COMPLETE, second.Status.State)
+
+	applied, err := devicechangeutils.GetAppliedIndex(second.Change.GetVersionedDeviceID(), deviceChanges)
+	assert.NoError(t, err)
+	assert.Equal(t, devicechange.Index(2), applied.Index)
+	assert.Equal(t, networkchange.ID("change-2"), applied.NetworkChange)
+	assert.Empty(t, applied.Pending)
+}
+
+func TestReconcilerChangeOrderBlocked(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	devices, deviceChanges := newStores(t, test)
+	defer deviceChanges.Close()
+
+	pauses := pause.NewLocalStore()
+	SetPauseStore(pauses)
+	defer SetPauseStore(nil)
+
+	reconciler := &Reconciler{
+		devices: devices,
+		changes: deviceChanges,
+	}
+
+	// A changes device-1 but is not started yet, B changes device-2, and C changes device-1 again
+	changeA := newChange(1, device1, v1)
+	changeA.NetworkChange.ID = "change-a"
+	assert.NoError(t, deviceChanges.Create(changeA))
+	changeB := newChange(2, device2, v1)
+	changeB.NetworkChange.ID = "change-b"
+	changeB.Status.Incarnation = 1
+	assert.NoError(t, deviceChanges.Create(changeB))
+	changeC := newChange(3, device1, v1)
+	changeC.NetworkChange.ID = "change-c"
+	changeC.Status.Incarnation = 1
+	assert.NoError(t, deviceChanges.Create(changeC))
+
+	// C is not pushed before A, which would overwrite it once started
+	_, err := reconciler.Reconcile(controller.NewID(string(changeC.ID)))
+	assert.True(t, errors.IsUnavailable(err))
+	assert.Contains(t, err.Error(), string(changeA.ID))
+
+	// B does not wait, as it changes another device
+	_, err = reconciler.Reconcile(controller.NewID(string(changeB.ID)))
+	assert.NoError(t, err)
+
+	// Nor is C pushed while A is started but paused
+	changeA, err = deviceChanges.Get(changeA.ID)
+	assert.NoError(t, err)
+	changeA.Status.Incarnation = 1
+	assert.NoError(t, deviceChanges.Update(changeA))
+	assert.NoError(t, pauses.Put(&pause.Pause{NetworkChangeID: "change-a"}))
+	_, err = reconciler.Reconcile(controller.NewID(string(changeC.ID)))
+	assert.True(t, errors.IsUnavailable(err))
+	assert.Contains(t, err.Error(), string(changeA.ID))
+
+	// Once A is resumed and applied, C is applied after it
+	assert.NoError(t, pauses.Delete("change-a"))
+	_, err = reconciler.Reconcile(controller.NewID(string(changeA.ID)))
+	assert.NoError(t, err)
+	_, err = reconciler.Reconcile(controller.NewID(string(changeC.ID)))
+	assert.NoError(t, err)
+	changeC, err = deviceChanges.Get(changeC.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.State_COMPLETE, changeC.Status.State)
+
+	applied, err := devicechangeutils.GetAppliedIndex(changeC.Change.GetVersionedDeviceID(), deviceChanges)
+	assert.NoError(t, err)
+	assert.Equal(t, devicechange.Index(3), applied.Index)
+	assert.Equal(t, networkchange.ID("change-c"), applied.NetworkChange)
+}
+
+func TestReconcilerPushOnce(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	devices, deviceChanges := newStores(t, test)
+	defer deviceChanges.Close()
+
+	pushes := push.NewLocalStore()
+	SetPushStore(pushes)
+	defer SetPushStore(nil)
+	defer acceptIdempotencyKeys(t, stratumType)()
+
+	// The device reports the value of the change only once asked for the second time
+	ctrl := gomock.NewController(t)
+	target := southboundmock.NewMockTargetIf(ctrl)
+	targetCtx := context.TODO()
+	target.EXPECT().Context().Return(&targetCtx).AnyTimes()
+	sets := 0
+	target.EXPECT().Set(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, request *gnmi.SetRequest) (*gnmi.SetResponse, error) {
+		sets++
+		assert.Len(t, request.Extension, 1)
+		assert.Equal(t, int32(GnmiExtensionIdempotencyKey), int32(request.Extension[0].GetRegisteredExt().Id))
+		assert.Equal(t, string(change1)+"/1/CHANGE", string(request.Extension[0].GetRegisteredExt().Msg))
+		return &gnmi.SetResponse{}, nil
+	}).AnyTimes()
+	target.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&gnmi.GetResponse{}, nil)
+	target.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&gnmi.GetResponse{
+		Notification: []*gnmi.Notification{{
+			Update: []*gnmi.Update{{Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "Hello world!"}}}},
+		}},
+	}, nil)
+	southbound.NewTargetItem(devicetype.NewVersionedID(device1, v1), target)
+
+	reconciler := &Reconciler{
+		devices: devices,
+		changes: deviceChanges,
+	}
+	deviceChange1 := newChange(1, device1, v1)
+	deviceChange1.Status.Incarnation = 1
+	assert.NoError(t, deviceChanges.Create(deviceChange1))
+
+	reconcile := func() {
+		_, err := reconciler.Reconcile(controller.NewID(string(change1)))
+		assert.NoError(t, err)
+		deviceChange1, err = deviceChanges.Get(change1)
+		assert.NoError(t, err)
+		assert.Equal(t, changetypes.State_COMPLETE, deviceChange1.Status.State)
+	}
+	setPending := func() {
+		deviceChange1.Status.State = changetypes.State_PENDING
+		assert.NoError(t, deviceChanges.Update(deviceChange1))
+	}
+
+	reconcile()
+	assert.Equal(t, 1, sets)
+
+	// The state of the change was not updated, e.g. because of a restart: it is not pushed again
+	setPending()
+	reconcile()
+	assert.Equal(t, 1, sets)
+
+	// A push that was sent but not acknowledged is sent again if the device does not apply it
+	setPending()
+	assert.NoError(t, pushes.Put(&push.Push{DeviceChangeID: change1, Key: push.NewKey(deviceChange1), State: push.StateSent}))
+	reconcile()
+	assert.Equal(t, 2, sets)
+
+	// and not if it does
+	setPending()
+	assert.NoError(t, pushes.Put(&push.Push{DeviceChangeID: change1, Key: push.NewKey(deviceChange1), State: push.StateSent}))
+	reconcile()
+	assert.Equal(t, 2, sets)
+	pushed, err := pushes.Get(change1)
+	assert.NoError(t, err)
+	assert.Equal(t, push.StateAcknowledged, pushed.State)
+}
+
+func TestReconcilerPushRejected(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	devices, deviceChanges := newStores(t, test)
+	defer deviceChanges.Close()
+
+	pushes := push.NewLocalStore()
+	SetPushStore(pushes)
+	defer SetPushStore(nil)
+
+	ctrl := gomock.NewController(t)
+	target := southboundmock.NewMockTargetIf(ctrl)
+	targetCtx := context.TODO()
+	target.EXPECT().Context().Return(&targetCtx).AnyTimes()
+	target.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.InvalidArgument, "% Invalid input detected"))
+	southbound.NewTargetItem(devicetype.NewVersionedID(device1, v1), target)
+
+	reconciler := &Reconciler{
+		devices: devices,
+		changes: deviceChanges,
+	}
+	deviceChange1 := newChange(1, device1, v1)
+	deviceChange1.Status.Incarnation = 1
+	assert.NoError(t, deviceChanges.Create(deviceChange1))
+
+	_, err := reconciler.Reconcile(controller.NewID(string(change1)))
+	assert.NoError(t, err)
+	deviceChange1, err = deviceChanges.Get(change1)
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.State_FAILED, deviceChange1.Status.State)
+
+	// The rejection is recorded with the push
+	pushed, err := pushes.Get(change1)
+	assert.NoError(t, err)
+	assert.Equal(t, push.StateRejected, pushed.State)
+	if assert.NotNil(t, pushed.Nack) {
+		assert.Equal(t, push.CategorySyntax, pushed.Nack.Category)
+		assert.Equal(t, "InvalidArgument", pushed.Nack.Code)
+		assert.Equal(t, "% Invalid input detected", pushed.Nack.Message)
+		assert.Equal(t, "/foo", pushed.Nack.Path)
+	}
+}
+
+func TestReconcilerReadBackRemoved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	target := southboundmock.NewMockTargetIf(ctrl)
+	targetCtx := context.TODO()
+	target.EXPECT().Context().Return(&targetCtx).AnyTimes()
+	southbound.NewTargetItem(devicetype.NewVersionedID(device1, v1), target)
+	reconciler := &Reconciler{}
+
+	// A device answering NOT_FOUND for a removed path, as the southbound wraps it or not, removed it
+	deviceChange := newChange(1, device1, v1)
+	deviceChange.Change.Values[0].Removed = true
+	notFound := status.Error(codes.NotFound, "no such path")
+	target.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, notFound)
+	assert.True(t, reconciler.readBack(deviceChange))
+	target.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("target returned RPC error: %w", notFound))
+	assert.True(t, reconciler.readBack(deviceChange))
+	target.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&gnmi.GetResponse{Notification: []*gnmi.Notification{{}}}, nil)
+	assert.True(t, reconciler.readBack(deviceChange))
+
+	// Any other failure is not
+	target.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.Unavailable, "connection refused"))
+	assert.False(t, reconciler.readBack(deviceChange))
+
+	// A value pushed is not applied if the device has none
+	target.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, notFound)
+	assert.False(t, reconciler.readBack(newChange(1, device1, v1)))
+}
+
 func TestReconcilerRollbackSuccess(t *testing.T) {
 	test := test.NewTest(
 		rsm.NewProtocol(),
@@ -0,0 +1,170 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"sync"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	changestore "github.com/onosproject/onos-config/pkg/store/change/device"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+)
+
+// changeOrder indexes the changes still to be applied to each device, so that a change can wait
+// for the changes to its device that precede it without listing them on each reconciliation. The
+// changes of a device are listed the first time one of them is reconciled, then followed with a
+// watch of the store.
+type changeOrder struct {
+	changes changestore.Store
+	mu      sync.Mutex
+	devices map[devicetype.VersionedID]*deviceOrder
+}
+
+// deviceOrder is the index of the changes to a device
+type deviceOrder struct {
+	ready chan struct{}
+	err   error
+	// pending are the changes to the device still to be applied
+	pending map[devicechange.ID]*devicechange.DeviceChange
+	// revisions are the latest revisions seen of the changes, so that a version listed is not
+	// taken over a later one watched
+	revisions map[devicechange.ID]devicechange.Revision
+	// deleted are the changes deleted while the changes are listed, so that they are not added
+	// back from the listing
+	deleted map[devicechange.ID]devicechange.Revision
+}
+
+func newChangeOrder(changes changestore.Store) *changeOrder {
+	return &changeOrder{
+		changes: changes,
+		devices: make(map[devicetype.VersionedID]*deviceOrder),
+	}
+}
+
+// preceding returns the first change to the device of a change that precedes it in network change
+// index order and is still to be applied, or nil if there is none. Every such change is waited for,
+// even if it is not started yet or its network change is paused, so that a later change is never
+// pushed before it and then overwritten by it.
+func (o *changeOrder) preceding(change *devicechange.DeviceChange) (*devicechange.DeviceChange, error) {
+	device, err := o.device(change.Change.GetVersionedDeviceID())
+	if err != nil {
+		return nil, err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var preceding *devicechange.DeviceChange
+	for _, pending := range device.pending {
+		if pending.Index < change.Index && (preceding == nil || pending.Index < preceding.Index) {
+			preceding = pending
+		}
+	}
+	return preceding, nil
+}
+
+// update indexes a version of a change, unless a later version is indexed
+func (o *changeOrder) update(change *devicechange.DeviceChange) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if device, ok := o.devices[change.Change.GetVersionedDeviceID()]; ok {
+		device.update(change)
+	}
+}
+
+// device returns the index of the changes to a device, listing them the first time
+func (o *changeOrder) device(deviceID devicetype.VersionedID) (*deviceOrder, error) {
+	o.mu.Lock()
+	device, ok := o.devices[deviceID]
+	if ok {
+		o.mu.Unlock()
+		<-device.ready
+		return device, device.err
+	}
+	device = &deviceOrder{
+		ready:     make(chan struct{}),
+		pending:   make(map[devicechange.ID]*devicechange.DeviceChange),
+		revisions: make(map[devicechange.ID]devicechange.Revision),
+		deleted:   make(map[devicechange.ID]devicechange.Revision),
+	}
+	o.devices[deviceID] = device
+	o.mu.Unlock()
+
+	err := o.load(deviceID, device)
+	o.mu.Lock()
+	device.deleted = nil
+	if err != nil {
+		device.err = err
+		delete(o.devices, deviceID)
+	}
+	o.mu.Unlock()
+	close(device.ready)
+	return device, err
+}
+
+// load watches the changes to a device, then lists them
+func (o *changeOrder) load(deviceID devicetype.VersionedID, device *deviceOrder) error {
+	events := make(chan stream.Event, queueSize)
+	watchCtx, err := o.changes.Watch(deviceID, events)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for event := range events {
+			change := event.Object.(*devicechange.DeviceChange)
+			o.mu.Lock()
+			if event.Type == stream.Deleted {
+				device.delete(change)
+			} else {
+				device.update(change)
+			}
+			o.mu.Unlock()
+		}
+	}()
+
+	changes := make(chan *devicechange.DeviceChange)
+	if _, err := o.changes.List(deviceID, changes); err != nil {
+		watchCtx.Close()
+		return err
+	}
+	for change := range changes {
+		o.mu.Lock()
+		if revision, ok := device.deleted[change.ID]; !ok || change.Revision > revision {
+			device.update(change)
+		}
+		o.mu.Unlock()
+	}
+	return nil
+}
+
+func (d *deviceOrder) update(change *devicechange.DeviceChange) {
+	if revision, ok := d.revisions[change.ID]; ok && change.Revision <= revision {
+		return
+	}
+	d.revisions[change.ID] = change.Revision
+	if change.Status.Phase == changetypes.Phase_CHANGE && change.Status.State == changetypes.State_PENDING {
+		d.pending[change.ID] = change
+	} else {
+		delete(d.pending, change.ID)
+	}
+}
+
+func (d *deviceOrder) delete(change *devicechange.DeviceChange) {
+	delete(d.revisions, change.ID)
+	delete(d.pending, change.ID)
+	if d.deleted != nil {
+		d.deleted[change.ID] = change.Revision
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"sync"
+
+	"github.com/onosproject/onos-config/pkg/store/change/pause"
+)
+
+// pauseStore holds the network changes whose device changes are not pushed
+var pauseStore pause.Store
+var pauseStoreMu = &sync.RWMutex{}
+
+// SetPauseStore sets the store of the network changes whose propagation is paused
+func SetPauseStore(store pause.Store) {
+	pauseStoreMu.Lock()
+	defer pauseStoreMu.Unlock()
+	pauseStore = store
+}
+
+// GetPauseStore returns the store of the paused network changes, nil if none is set
+func GetPauseStore() pause.Store {
+	pauseStoreMu.RLock()
+	defer pauseStoreMu.RUnlock()
+	return pauseStore
+}
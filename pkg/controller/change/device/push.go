@@ -0,0 +1,250 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	goerrors "errors"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-config/pkg/southbound/features"
+	"github.com/onosproject/onos-config/pkg/store/change/push"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-config/pkg/utils/values"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GnmiExtensionIdempotencyKey is the extension carrying the idempotency key of a push in the Set
+// requests sent to devices. It is numbered after the extensions of the northbound gNMI service.
+const GnmiExtensionIdempotencyKey = 107
+
+// pushStore holds the pushes of device changes to their devices
+var pushStore push.Store
+var pushStoreMu = &sync.RWMutex{}
+
+// SetPushStore sets the store of the pushes of device changes, which keeps a push acknowledged by a
+// device from being sent to it again
+func SetPushStore(store push.Store) {
+	pushStoreMu.Lock()
+	defer pushStoreMu.Unlock()
+	pushStore = store
+}
+
+// GetPushStore returns the store of the pushes of device changes, nil if none is set
+func GetPushStore() push.Store {
+	pushStoreMu.RLock()
+	defer pushStoreMu.RUnlock()
+	return pushStore
+}
+
+// beginPush returns whether the device of a device change already applied it in its current
+// incarnation and phase. If it did not, the push about to be sent is recorded.
+func (r *Reconciler) beginPush(deviceChange *devicechange.DeviceChange) (bool, error) {
+	pushes := GetPushStore()
+	if pushes == nil {
+		return false, nil
+	}
+	key := push.NewKey(deviceChange)
+	last, err := pushes.Get(deviceChange.ID)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	if last != nil && last.Key == key {
+		if last.State == push.StateAcknowledged {
+			log.Infof("Not pushing %s again: %s was acknowledged", deviceChange.ID, key)
+			return true, nil
+		}
+		// The push was sent but its result is unknown, so what the device reports decides.
+		// A push the device rejected is sent again.
+		if last.State == push.StateSent {
+			if r.readBack(deviceChange) {
+				log.Infof("Not pushing %s again: %s was applied", deviceChange.ID, key)
+				r.endPush(deviceChange)
+				return true, nil
+			}
+			// The record is kept, for a split push to resume from the first Set not acknowledged
+			log.Infof("Pushing %s again: %s is not applied", deviceChange.ID, key)
+			return false, nil
+		}
+		log.Infof("Pushing %s again: %s is not applied", deviceChange.ID, key)
+	}
+	return false, pushes.Put(&push.Push{
+		DeviceChangeID: deviceChange.ID,
+		Key:            key,
+		State:          push.StateSent,
+		Updated:        time.Now(),
+	})
+}
+
+// endPush records that the device of a device change acknowledged its push
+func (r *Reconciler) endPush(deviceChange *devicechange.DeviceChange) {
+	pushes := GetPushStore()
+	if pushes == nil {
+		return
+	}
+	err := pushes.Put(&push.Push{
+		DeviceChangeID: deviceChange.ID,
+		Key:            push.NewKey(deviceChange),
+		State:          push.StateAcknowledged,
+		Updated:        time.Now(),
+	})
+	if err != nil {
+		log.Warnf("Could not record the push of %s: %v", deviceChange.ID, err)
+	}
+}
+
+// acknowledgedSets returns the number of Sets of the series a push of a device change is split into
+// that the device acknowledged before the push was interrupted, e.g. by a restart, so that the push
+// resumes from the first Set not acknowledged. It is 0 if the push was not interrupted, or if it is
+// split differently, e.g. because the limits of the device changed.
+func (r *Reconciler) acknowledgedSets(deviceChange *devicechange.DeviceChange, sets int) int {
+	pushes := GetPushStore()
+	if pushes == nil || sets <= 1 {
+		return 0
+	}
+	last, err := pushes.Get(deviceChange.ID)
+	if err != nil || last.Key != push.NewKey(deviceChange) || last.State != push.StateSent || last.Sets != sets {
+		return 0
+	}
+	return last.AcknowledgedSets
+}
+
+// acknowledgeSets records that the device of a device change acknowledged the first Sets of the
+// series its push is split into
+func (r *Reconciler) acknowledgeSets(deviceChange *devicechange.DeviceChange, acknowledged int, sets int) {
+	pushes := GetPushStore()
+	if pushes == nil {
+		return
+	}
+	err := pushes.Put(&push.Push{
+		DeviceChangeID:   deviceChange.ID,
+		Key:              push.NewKey(deviceChange),
+		State:            push.StateSent,
+		Updated:          time.Now(),
+		Sets:             sets,
+		AcknowledgedSets: acknowledged,
+	})
+	if err != nil {
+		log.Warnf("Could not record the Sets of %s acknowledged: %v", deviceChange.ID, err)
+	}
+}
+
+// rejectPush records that the device of a device change rejected its push
+func (r *Reconciler) rejectPush(deviceChange *devicechange.DeviceChange, nack *push.Nack) {
+	pushes := GetPushStore()
+	if pushes == nil {
+		return
+	}
+	err := pushes.Put(&push.Push{
+		DeviceChangeID: deviceChange.ID,
+		Key:            push.NewKey(deviceChange),
+		State:          push.StateRejected,
+		Updated:        time.Now(),
+		Nack:           nack,
+	})
+	if err != nil {
+		log.Warnf("Could not record the rejection of %s: %v", deviceChange.ID, err)
+	}
+}
+
+// acceptsIdempotencyKeys returns whether a device accepts the idempotency keys of the pushes, as
+// declared by the features of its type
+func acceptsIdempotencyKeys(device *topodevice.Device) bool {
+	return device != nil && features.GetRegistry().Get(devicetype.Type(device.Type)).IdempotencyKeys
+}
+
+// withIdempotencyKey adds the idempotency key of a push of a device change to a Set request, none
+// if the key is empty
+func withIdempotencyKey(setRequest *gnmi.SetRequest, key string) {
+	if key == "" {
+		return
+	}
+	setRequest.Extension = append(setRequest.Extension, &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  GnmiExtensionIdempotencyKey,
+				Msg: []byte(key),
+			},
+		},
+	})
+}
+
+// readBack returns whether the device of a device change reports every value it pushes, and none
+// of the paths it removes. A path the device answers NOT_FOUND for, or reports no value of, is
+// absent. Any value the device does not report as expected, e.g. because it does not support the
+// PROTO encoding, counts as not applied.
+func (r *Reconciler) readBack(deviceChange *devicechange.DeviceChange) bool {
+	change := deviceChange.Change
+	if deviceChange.Status.Phase == changetypes.Phase_ROLLBACK {
+		rollback, err := r.computeRollback(deviceChange)
+		if err != nil {
+			return false
+		}
+		change = rollback
+	}
+	deviceTarget, err := southbound.GetTarget(change.GetVersionedDeviceID())
+	if err != nil {
+		return false
+	}
+	for _, changeValue := range change.Values {
+		path, err := utils.ParseGNMIElements(utils.SplitPath(changeValue.Path))
+		if err != nil {
+			return false
+		}
+		response, err := deviceTarget.Get(*deviceTarget.Context(), &gnmi.GetRequest{
+			Path:     []*gnmi.Path{{Elem: path.Elem}},
+			Encoding: gnmi.Encoding_PROTO,
+		})
+		if err != nil {
+			if changeValue.Removed && isNotFound(err) {
+				continue
+			}
+			return false
+		}
+		var reported *gnmi.TypedValue
+		for _, notification := range response.Notification {
+			for _, update := range notification.Update {
+				reported = update.Val
+			}
+		}
+		if changeValue.Removed {
+			if reported != nil {
+				return false
+			}
+			continue
+		}
+		expected, err := values.NativeTypeToGnmiTypedValue(changeValue.Value)
+		if err != nil || reported == nil || !proto.Equal(expected, reported) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNotFound returns whether a request to a device failed with NOT_FOUND
+func isNotFound(err error) bool {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	return goerrors.As(err, &grpcErr) && grpcErr.GRPCStatus().Code() == codes.NotFound
+}
@@ -0,0 +1,182 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-config/pkg/southbound/features"
+	devicechangeutils "github.com/onosproject/onos-config/pkg/store/change/device/utils"
+	"github.com/onosproject/onos-config/pkg/store/change/push"
+	"github.com/onosproject/onos-config/pkg/utils/values"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// setChunk is one of the Set requests a change is pushed to a device with
+type setChunk struct {
+	values  []*devicechange.ChangeValue
+	request *gnmi.SetRequest
+}
+
+// splitChange splits a change into the Set requests that push it to a device within the limits of
+// the device, with the idempotency key given if the device accepts it. A change within the limits
+// is pushed with a single Set; otherwise each Set has the key suffixed with its position in the
+// series. The deletes of the change come first, as they would be applied first by a single Set.
+func splitChange(device *topodevice.Device, change *devicechange.Change, key string) ([]*setChunk, error) {
+	if !acceptsIdempotencyKeys(device) {
+		key = ""
+	}
+	if device == nil || maxSetUpdates(device) <= 0 && device.MaxSetBytes <= 0 {
+		request, err := values.NativeChangeToGnmiChange(change)
+		if err != nil {
+			return nil, err
+		}
+		withIdempotencyKey(request, key)
+		return []*setChunk{{values: change.Values, request: request}}, nil
+	}
+
+	changeValues := make([]*devicechange.ChangeValue, len(change.Values))
+	copy(changeValues, change.Values)
+	sort.SliceStable(changeValues, func(i, j int) bool {
+		return changeValues[i].Removed && !changeValues[j].Removed
+	})
+
+	// The chunks are sized with the longest key a Set of the series may have
+	var sizingKey string
+	if key != "" {
+		sizingKey = fmt.Sprintf("%s/%d-%d", key, len(changeValues), len(changeValues))
+	}
+	chunks := make([]*setChunk, 0)
+	var current *setChunk
+	for _, changeValue := range changeValues {
+		chunk, err := newSetChunk(change, current, changeValue, sizingKey)
+		if err != nil {
+			return nil, err
+		}
+		if !withinLimits(device, chunk) && current != nil {
+			chunks = append(chunks, current)
+			if chunk, err = newSetChunk(change, nil, changeValue, sizingKey); err != nil {
+				return nil, err
+			}
+		}
+		if !withinLimits(device, chunk) {
+			return nil, errors.NewInvalid("the Set of %s alone exceeds the limits of device %s", changeValue.Path, device.ID)
+		}
+		current = chunk
+	}
+	if current != nil {
+		chunks = append(chunks, current)
+	}
+
+	for i, chunk := range chunks {
+		chunk.request.Extension = nil
+		if len(chunks) > 1 && key != "" {
+			withIdempotencyKey(chunk.request, fmt.Sprintf("%s/%d-%d", key, i+1, len(chunks)))
+		} else {
+			withIdempotencyKey(chunk.request, key)
+		}
+	}
+	return chunks, nil
+}
+
+// newSetChunk returns a chunk with the values of another one, if any, and a value
+func newSetChunk(change *devicechange.Change, chunk *setChunk, changeValue *devicechange.ChangeValue, key string) (*setChunk, error) {
+	chunkValues := make([]*devicechange.ChangeValue, 0)
+	if chunk != nil {
+		chunkValues = append(chunkValues, chunk.values...)
+	}
+	chunkValues = append(chunkValues, changeValue)
+	request, err := values.NativeChangeToGnmiChange(&devicechange.Change{
+		DeviceID:      change.DeviceID,
+		DeviceVersion: change.DeviceVersion,
+		DeviceType:    change.DeviceType,
+		Values:        chunkValues,
+	})
+	if err != nil {
+		return nil, err
+	}
+	withIdempotencyKey(request, key)
+	return &setChunk{values: chunkValues, request: request}, nil
+}
+
+// maxSetUpdates returns the most paths a Set to a device updates or deletes, as limited by the device
+// or else by its type; unlimited if 0
+func maxSetUpdates(device *topodevice.Device) int {
+	if device.MaxSetUpdates > 0 {
+		return device.MaxSetUpdates
+	}
+	return features.GetRegistry().Get(devicetype.Type(device.Type)).MaxPathsPerSet
+}
+
+// withinLimits returns whether the Set request of a chunk is within the limits of a device
+func withinLimits(device *topodevice.Device, chunk *setChunk) bool {
+	if limit := maxSetUpdates(device); limit > 0 && len(chunk.values) > limit {
+		return false
+	}
+	return device.MaxSetBytes <= 0 || proto.Size(chunk.request) <= device.MaxSetBytes
+}
+
+// undoChunks returns the change restoring the values the chunks of a change pushed to the device of
+// a device change replaced
+func (r *Reconciler) undoChunks(deviceChange *devicechange.DeviceChange, change *devicechange.Change, chunks []*setChunk) (*devicechange.Change, error) {
+	config, err := devicechangeutils.ExtractConfigBefore(deviceChange.Change.GetVersionedDeviceID(), deviceChange.Index, r.changes)
+	if err != nil {
+		return nil, err
+	}
+	// A rollback is pushed to a device that has the values of the change rolled back
+	if deviceChange.Status.Phase == changetypes.Phase_ROLLBACK {
+		config = devicechangeutils.ApplyChange(config, deviceChange.Change)
+	}
+	pushed := &devicechange.Change{
+		DeviceID:      change.DeviceID,
+		DeviceVersion: change.DeviceVersion,
+		DeviceType:    change.DeviceType,
+	}
+	for _, chunk := range chunks {
+		pushed.Values = append(pushed.Values, chunk.values...)
+	}
+	return devicechangeutils.ComputeRollback(pushed, config), nil
+}
+
+// undoSets pushes the change restoring the values replaced by the Sets of a change that a device
+// accepted before rejecting a later one, returning the rejection
+func (r *Reconciler) undoSets(device *topodevice.Device, target southbound.TargetIf, deviceChange *devicechange.DeviceChange,
+	change *devicechange.Change, accepted []*setChunk, rejection error) error {
+	undo, err := r.undoChunks(deviceChange, change, accepted)
+	if err == nil {
+		var chunks []*setChunk
+		if chunks, err = splitChange(device, undo, push.NewKey(deviceChange)+"/undo"); err == nil {
+			for _, chunk := range chunks {
+				if _, err = target.Set(*target.Context(), chunk.request); err != nil {
+					break
+				}
+			}
+		}
+	}
+	if err != nil {
+		log.Errorf("Could not undo the %d Sets of %s accepted by %s: %v", len(accepted), deviceChange.ID, change.DeviceID, err)
+		return fmt.Errorf("%v; the %d sets accepted before could not be undone: %v", rejection, len(accepted), err)
+	}
+	log.Infof("Undid the %d Sets of %s accepted by %s", len(accepted), deviceChange.ID, change.DeviceID)
+	return fmt.Errorf("%v; the %d sets accepted before were undone", rejection, len(accepted))
+}
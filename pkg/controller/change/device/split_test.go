@@ -0,0 +1,283 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package device
+
+import (
+	"context"
+	"testing"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	types "github.com/onosproject/onos-api/go/onos/config"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-config/pkg/southbound/features"
+	"github.com/onosproject/onos-config/pkg/store/change/push"
+	southboundmock "github.com/onosproject/onos-config/pkg/test/mocks/southbound"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// acceptIdempotencyKeys makes the devices of a type accept the idempotency keys of the pushes,
+// returning the function restoring the features of the type
+func acceptIdempotencyKeys(t *testing.T, deviceType devicetype.Type) func() {
+	typeFeatures := features.GetRegistry().Get(deviceType)
+	keyed := typeFeatures
+	keyed.IdempotencyKeys = true
+	assert.NoError(t, features.GetRegistry().Set(deviceType, keyed))
+	return func() {
+		assert.NoError(t, features.GetRegistry().Set(deviceType, typeFeatures))
+	}
+}
+
+func Test_SplitChange(t *testing.T) {
+	defer acceptIdempotencyKeys(t, "KeyedDevice")()
+	change := &devicechange.Change{
+		DeviceID:      device1,
+		DeviceVersion: v1,
+		Values: []*devicechange.ChangeValue{
+			{Path: eth1Name, Value: devicechange.NewTypedValueString(eth1)},
+			{Path: eth1Hi, Value: devicechange.NewTypedValueString(healthUp)},
+			{Path: eth2Name, Value: devicechange.NewTypedValueString(eth2)},
+			{Path: eth1Desc, Removed: true},
+		},
+	}
+	key := "change-1/1/CHANGE"
+	keyOf := func(chunk *setChunk) string {
+		return string(chunk.request.Extension[0].GetRegisteredExt().Msg)
+	}
+
+	// A device without limits gets the change with one Set
+	chunks, err := splitChange(&topodevice.Device{ID: topodevice.ID(device1), Type: "KeyedDevice"}, change, key)
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 1)
+	assert.Equal(t, key, keyOf(chunks[0]))
+	assert.Len(t, chunks[0].request.Update, 3)
+	assert.Len(t, chunks[0].request.Delete, 1)
+
+	// The deletes come first, and each Set has its own key
+	chunks, err = splitChange(&topodevice.Device{ID: topodevice.ID(device1), Type: "KeyedDevice", MaxSetUpdates: 2}, change, key)
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 2)
+	assert.Equal(t, key+"/1-2", keyOf(chunks[0]))
+	assert.Len(t, chunks[0].request.Delete, 1)
+	assert.Equal(t, eth1Name, utils.StrPath(chunks[0].request.Update[0].Path))
+	assert.Equal(t, key+"/2-2", keyOf(chunks[1]))
+	assert.Len(t, chunks[1].request.Update, 2)
+
+	// A device whose type does not accept the keys gets none
+	chunks, err = splitChange(&topodevice.Device{ID: topodevice.ID(device1), MaxSetUpdates: 2}, change, key)
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 2)
+	for _, chunk := range chunks {
+		assert.Empty(t, chunk.request.Extension)
+	}
+
+	// The Sets of a change within the limits in bytes are kept within them
+	chunks, err = splitChange(&topodevice.Device{ID: topodevice.ID(device1), MaxSetBytes: 200}, change, key)
+	assert.NoError(t, err)
+	assert.True(t, len(chunks) > 1)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, proto.Size(chunk.request), 200)
+	}
+
+	// A value that cannot be pushed within the limits fails the change
+	_, err = splitChange(&topodevice.Device{ID: topodevice.ID(device1), MaxSetBytes: 10}, change, key)
+	assert.True(t, errors.IsInvalid(err), "expected invalid, got %v", err)
+
+	// The limit of the type of a device applies unless the device has its own
+	assert.NoError(t, features.GetRegistry().Set("SplitDevice", features.Features{MaxPathsPerSet: 3}))
+	chunks, err = splitChange(&topodevice.Device{ID: topodevice.ID(device1), Type: "SplitDevice"}, change, key)
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 2)
+	chunks, err = splitChange(&topodevice.Device{ID: topodevice.ID(device1), Type: "SplitDevice", MaxSetUpdates: 10}, change, key)
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 1)
+}
+
+func TestReconcilerSplitPushUndone(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	devices, deviceChanges := newStores(t, test)
+	defer deviceChanges.Close()
+
+	pushes := push.NewLocalStore()
+	SetPushStore(pushes)
+	defer SetPushStore(nil)
+	defer acceptIdempotencyKeys(t, "KeyedDevice")()
+
+	// The device accepts the first two Sets of the change and rejects the third
+	ctrl := gomock.NewController(t)
+	target := southboundmock.NewMockTargetIf(ctrl)
+	targetCtx := context.TODO()
+	target.EXPECT().Context().Return(&targetCtx).AnyTimes()
+	requests := make([]*gnmi.SetRequest, 0)
+	target.EXPECT().Set(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, request *gnmi.SetRequest) (*gnmi.SetResponse, error) {
+		requests = append(requests, request)
+		if len(requests) == 3 {
+			return nil, status.Error(codes.ResourceExhausted, "too many updates")
+		}
+		return &gnmi.SetResponse{}, nil
+	}).Times(5)
+	southbound.NewTargetItem(devicetype.NewVersionedID(device1, v1), target)
+
+	reconciler := &Reconciler{
+		devices: devices,
+		changes: deviceChanges,
+	}
+	deviceChange1 := newChangeInterface(1, device1, v1, 1)
+	deviceChange1.Status.State = changetypes.State_COMPLETE
+	assert.NoError(t, deviceChanges.Create(deviceChange1))
+	deviceChange2 := &devicechange.DeviceChange{
+		Index:         2,
+		NetworkChange: devicechange.NetworkChangeRef{ID: types.ID("device-1-split"), Index: 2},
+		Change: &devicechange.Change{
+			DeviceID:      device1,
+			DeviceVersion: v1,
+			DeviceType:    stratumType,
+			Values: []*devicechange.ChangeValue{
+				{Path: eth1Hi, Value: devicechange.NewTypedValueString(healthDown)},
+				{Path: eth1Desc, Value: devicechange.NewTypedValueString("uplink")},
+				{Path: eth2Name, Value: devicechange.NewTypedValueString(eth2)},
+			},
+		},
+		Status: changetypes.Status{Incarnation: 1},
+	}
+	assert.NoError(t, deviceChanges.Create(deviceChange2))
+
+	device := &topodevice.Device{ID: topodevice.ID(device1), Version: v1, Type: "KeyedDevice", MaxSetUpdates: 1}
+	err := reconciler.translateAndSendChange(device, deviceChange2, deviceChange2.Change)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "set 3 of 3 rejected")
+	assert.Contains(t, err.Error(), "the 2 sets accepted before were undone")
+	assert.Len(t, requests, 5)
+
+	key := push.NewKey(deviceChange2)
+	for i, suffix := range []string{"/1-3", "/2-3", "/3-3"} {
+		assert.Equal(t, key+suffix, string(requests[i].Extension[0].GetRegisteredExt().Msg))
+	}
+
+	// The description the change added is deleted and the health indicator it changed restored
+	assert.Equal(t, key+"/undo/1-2", string(requests[3].Extension[0].GetRegisteredExt().Msg))
+	assert.Equal(t, eth1Desc, utils.StrPath(requests[3].Delete[0]))
+	assert.Equal(t, eth1Hi, utils.StrPath(requests[4].Update[0].Path))
+	assert.Equal(t, healthUp, requests[4].Update[0].Val.GetStringVal())
+
+	pushed, err := pushes.Get(deviceChange2.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, push.StateRejected, pushed.State)
+}
+
+func TestReconcilerSplitPushResumed(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	devices, deviceChanges := newStores(t, test)
+	defer deviceChanges.Close()
+
+	pushes := push.NewLocalStore()
+	SetPushStore(pushes)
+	defer SetPushStore(nil)
+	defer acceptIdempotencyKeys(t, "KeyedDevice")()
+
+	// The push is interrupted after the device acknowledged the first Set of the change, so the
+	// device reports none of the values of the change
+	ctrl := gomock.NewController(t)
+	target := southboundmock.NewMockTargetIf(ctrl)
+	targetCtx := context.TODO()
+	target.EXPECT().Context().Return(&targetCtx).AnyTimes()
+	target.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&gnmi.GetResponse{}, nil).AnyTimes()
+	requests := make([]*gnmi.SetRequest, 0)
+	acknowledged := make([]int, 0)
+	target.EXPECT().Set(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, request *gnmi.SetRequest) (*gnmi.SetResponse, error) {
+		requests = append(requests, request)
+		last, err := GetPushStore().Get(devicechange.ID("device-1-resumed:device-1:1.0.0"))
+		assert.NoError(t, err)
+		acknowledged = append(acknowledged, last.AcknowledgedSets)
+		return &gnmi.SetResponse{}, nil
+	}).Times(2)
+	southbound.NewTargetItem(devicetype.NewVersionedID(device1, v1), target)
+
+	reconciler := &Reconciler{
+		devices: devices,
+		changes: deviceChanges,
+	}
+	deviceChange1 := &devicechange.DeviceChange{
+		Index:         1,
+		NetworkChange: devicechange.NetworkChangeRef{ID: types.ID("device-1-resumed"), Index: 1},
+		Change: &devicechange.Change{
+			DeviceID:      device1,
+			DeviceVersion: v1,
+			DeviceType:    stratumType,
+			Values: []*devicechange.ChangeValue{
+				{Path: eth1Hi, Value: devicechange.NewTypedValueString(healthDown)},
+				{Path: eth1Desc, Value: devicechange.NewTypedValueString("uplink")},
+				{Path: eth2Name, Value: devicechange.NewTypedValueString(eth2)},
+			},
+		},
+		Status: changetypes.Status{Incarnation: 1},
+	}
+	assert.NoError(t, deviceChanges.Create(deviceChange1))
+
+	device := &topodevice.Device{ID: topodevice.ID(device1), Version: v1, Type: "KeyedDevice", MaxSetUpdates: 1}
+	key := push.NewKey(deviceChange1)
+	assert.NoError(t, pushes.Put(&push.Push{
+		DeviceChangeID:   deviceChange1.ID,
+		Key:              key,
+		State:            push.StateSent,
+		Sets:             3,
+		AcknowledgedSets: 1,
+	}))
+
+	// The record of the interrupted push is kept when it is pushed again
+	pushed, err := reconciler.beginPush(deviceChange1)
+	assert.NoError(t, err)
+	assert.False(t, pushed)
+	last, err := pushes.Get(deviceChange1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, last.AcknowledgedSets)
+
+	// Only the Sets not acknowledged are sent, and each one is recorded once acknowledged
+	assert.NoError(t, reconciler.translateAndSendChange(device, deviceChange1, deviceChange1.Change))
+	assert.Len(t, requests, 2)
+	assert.Equal(t, []int{1, 2}, acknowledged)
+	for i, suffix := range []string{"/2-3", "/3-3"} {
+		assert.Equal(t, key+suffix, string(requests[i].Extension[0].GetRegisteredExt().Msg))
+	}
+	assert.Equal(t, eth1Desc, utils.StrPath(requests[0].Update[0].Path))
+	assert.Equal(t, eth2Name, utils.StrPath(requests[1].Update[0].Path))
+
+	last, err = pushes.Get(deviceChange1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, push.StateAcknowledged, last.State)
+}
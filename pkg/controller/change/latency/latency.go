@@ -0,0 +1,421 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package latency tracks how long network changes take from the gNMI Set that created them to
+// their completion, as a whole and on each device, to find the devices that hold up rollouts. It
+// also tracks the pending changes of each device, to tell a client how far back in the backlog
+// of its devices a new change is.
+package latency
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	devicechangestore "github.com/onosproject/onos-config/pkg/store/change/device"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var log = logging.GetLogger("controller", "change", "latency")
+
+// maxSamples is the number of the latest latencies the percentiles of a series are computed from
+const maxSamples = 1000
+
+// maxSlowest is the number of the slowest changes the tracker remembers
+const maxSlowest = 10
+
+var (
+	changeLatencySummary = prometheus.NewSummary(prometheus.SummaryOpts{
+		Name:       "onos_config_change_latency_seconds",
+		Help:       "Time from the gNMI Set that created a network change to its completion on all of its devices",
+		Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+	})
+	deviceLatencySummary = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Name:       "onos_config_device_change_latency_seconds",
+		Help:       "Time from the gNMI Set that created a network change to its completion on a device",
+		Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+	}, []string{"device_id", "device_type"})
+	deviceOverSLOCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "onos_config_device_changes_over_slo_total",
+		Help: "Number of the changes of a device that completed later than the latency SLO",
+	}, []string{"device_id", "device_type"})
+)
+
+func init() {
+	prometheus.MustRegister(changeLatencySummary, deviceLatencySummary, deviceOverSLOCounter)
+}
+
+// Stats are the percentiles of the latest latencies of a series
+type Stats struct {
+	// Count is the number of changes the percentiles are computed from
+	Count int
+	P50   time.Duration
+	P90   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+	// OverSLO is the number of these changes that completed later than the SLO
+	OverSLO int
+}
+
+// DeviceStats are the latency stats of the changes of a device
+type DeviceStats struct {
+	Stats
+	DeviceID   devicetype.ID
+	DeviceType devicetype.Type
+}
+
+// ChangeLatency is the latency of a network change
+type ChangeLatency struct {
+	NetworkChange networkchange.ID
+	Completed     time.Time
+	Latency       time.Duration
+	// SlowestDevice is the device the change completed on last
+	SlowestDevice devicetype.ID
+}
+
+// DeviceBacklog is the backlog of the pending network changes of a device ahead of a change
+type DeviceBacklog struct {
+	DeviceID devicetype.ID
+	// Position is the number of pending changes of the device ahead of the change
+	Position int
+	// ETA estimates how long the change takes to complete on the device, from the median latency
+	// of the changes of the device; zero if no change of the device completed yet
+	ETA time.Duration
+}
+
+// pendingChange is a pending network change, by the index it is applied in and its devices
+type pendingChange struct {
+	index   networkchange.Index
+	devices []devicetype.ID
+}
+
+// series keeps the latest latencies of a series, oldest first
+type series []time.Duration
+
+func (s series) add(latency time.Duration) series {
+	s = append(s, latency)
+	if len(s) > maxSamples {
+		s = s[len(s)-maxSamples:]
+	}
+	return s
+}
+
+func (s series) stats(slo time.Duration) Stats {
+	stats := Stats{Count: len(s)}
+	if len(s) == 0 {
+		return stats
+	}
+	sorted := make([]time.Duration, len(s))
+	copy(sorted, s)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	stats.P50 = percentile(sorted, 50)
+	stats.P90 = percentile(sorted, 90)
+	stats.P99 = percentile(sorted, 99)
+	stats.Max = sorted[len(sorted)-1]
+	if slo > 0 {
+		stats.OverSLO = len(sorted) - sort.Search(len(sorted), func(i int) bool {
+			return sorted[i] > slo
+		})
+	}
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Tracker watches the network changes and records the latency of those that complete. The
+// changes that completed before it started are replayed from the store, so every node reports
+// the same percentiles; only those completing afterwards are exported as metrics.
+type Tracker struct {
+	slo            time.Duration
+	networkChanges networkchangestore.Store
+	deviceChanges  devicechangestore.Store
+	started        time.Time
+	mu             sync.RWMutex
+	recorded       map[networkchange.ID]bool
+	changes        series
+	devices        map[devicetype.ID]series
+	deviceTypes    map[devicetype.ID]devicetype.Type
+	types          map[devicetype.Type]series
+	slowest        []ChangeLatency
+	pending        map[networkchange.ID]pendingChange
+	watch          stream.Context
+}
+
+// NewTracker creates a tracker; a change that takes longer than the slo on a device counts against
+// it, unless the slo is zero
+func NewTracker(slo time.Duration, networkChanges networkchangestore.Store, deviceChanges devicechangestore.Store) *Tracker {
+	return &Tracker{
+		slo:            slo,
+		networkChanges: networkChanges,
+		deviceChanges:  deviceChanges,
+		recorded:       make(map[networkchange.ID]bool),
+		devices:        make(map[devicetype.ID]series),
+		deviceTypes:    make(map[devicetype.ID]devicetype.Type),
+		types:          make(map[devicetype.Type]series),
+		pending:        make(map[networkchange.ID]pendingChange),
+	}
+}
+
+// SLO returns the latency a change should complete within on each device; zero if none is set
+func (t *Tracker) SLO() time.Duration {
+	return t.slo
+}
+
+// Start starts watching the network changes
+func (t *Tracker) Start() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.watch != nil {
+		return nil
+	}
+	t.started = time.Now()
+	ch := make(chan stream.Event)
+	ctx, err := t.networkChanges.Watch(ch, networkchangestore.WithReplay())
+	if err != nil {
+		return err
+	}
+	t.watch = ctx
+	go func() {
+		for event := range ch {
+			change, ok := event.Object.(*networkchange.NetworkChange)
+			if !ok {
+				continue
+			}
+			if event.Type == stream.Deleted {
+				t.mu.Lock()
+				delete(t.recorded, change.ID)
+				delete(t.pending, change.ID)
+				t.mu.Unlock()
+				continue
+			}
+			t.track(change)
+			t.Record(change)
+		}
+	}()
+	return nil
+}
+
+// Stop stops watching the network changes
+func (t *Tracker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.watch != nil {
+		t.watch.Close()
+		t.watch = nil
+	}
+}
+
+// Record records the latency of a network change and of its device changes once it completed;
+// a change is only recorded once
+func (t *Tracker) Record(change *networkchange.NetworkChange) {
+	if change.Status.Phase != changetypes.Phase_CHANGE || change.Status.State != changetypes.State_COMPLETE {
+		return
+	}
+	t.mu.RLock()
+	recorded := t.recorded[change.ID]
+	t.mu.RUnlock()
+	if recorded {
+		return
+	}
+
+	deviceChanges := make([]*devicechange.DeviceChange, 0, len(change.Refs))
+	for _, ref := range change.Refs {
+		deviceChange, err := t.deviceChanges.Get(ref.DeviceChangeID)
+		if err != nil || deviceChange == nil {
+			log.Debugf("Device change %s of %s not found, its latency is not recorded", ref.DeviceChangeID, change.ID)
+			continue
+		}
+		if deviceChange.Status.State == changetypes.State_COMPLETE {
+			deviceChanges = append(deviceChanges, deviceChange)
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.recorded[change.ID] {
+		return
+	}
+	t.recorded[change.ID] = true
+	live := !t.started.IsZero() && change.Updated.After(t.started)
+
+	latency := ChangeLatency{
+		NetworkChange: change.ID,
+		Completed:     change.Updated,
+		Latency:       change.Updated.Sub(change.Created),
+	}
+	var slowest time.Duration
+	for _, deviceChange := range deviceChanges {
+		deviceID := deviceChange.Change.DeviceID
+		deviceType := deviceChange.Change.DeviceType
+		deviceLatency := deviceChange.Updated.Sub(change.Created)
+		if deviceLatency >= slowest {
+			slowest = deviceLatency
+			latency.SlowestDevice = deviceID
+		}
+		t.devices[deviceID] = t.devices[deviceID].add(deviceLatency)
+		t.deviceTypes[deviceID] = deviceType
+		t.types[deviceType] = t.types[deviceType].add(deviceLatency)
+		if live {
+			observeDevice(deviceID, deviceType, deviceLatency, t.slo)
+		}
+	}
+	t.changes = t.changes.add(latency.Latency)
+	if live {
+		changeLatencySummary.Observe(latency.Latency.Seconds())
+	}
+	t.addSlowest(latency)
+}
+
+// track keeps the pending network changes, in their change or their rollback phase
+func (t *Tracker) track(change *networkchange.NetworkChange) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if change.Status.State != changetypes.State_PENDING {
+		delete(t.pending, change.ID)
+		return
+	}
+	devices := make([]devicetype.ID, 0, len(change.Changes))
+	for _, deviceChange := range change.Changes {
+		devices = append(devices, deviceChange.DeviceID)
+	}
+	t.pending[change.ID] = pendingChange{
+		index:   change.Index,
+		devices: devices,
+	}
+}
+
+// Backlog returns the backlog of the pending changes ahead of a network change on each of its
+// devices, sorted by device: the changes pending with a lower index, which are applied to the
+// device before it
+func (t *Tracker) Backlog(change *networkchange.NetworkChange) []DeviceBacklog {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	positions := make(map[devicetype.ID]int, len(change.Changes))
+	for _, deviceChange := range change.Changes {
+		positions[deviceChange.DeviceID] = 0
+	}
+	for id, pending := range t.pending {
+		if id == change.ID || pending.index >= change.Index {
+			continue
+		}
+		for _, deviceID := range pending.devices {
+			if position, ok := positions[deviceID]; ok {
+				positions[deviceID] = position + 1
+			}
+		}
+	}
+	backlog := make([]DeviceBacklog, 0, len(positions))
+	for deviceID, position := range positions {
+		deviceBacklog := DeviceBacklog{
+			DeviceID: deviceID,
+			Position: position,
+		}
+		if stats := t.devices[deviceID].stats(t.slo); stats.Count > 0 {
+			deviceBacklog.ETA = time.Duration(position+1) * stats.P50
+		}
+		backlog = append(backlog, deviceBacklog)
+	}
+	sort.Slice(backlog, func(i, j int) bool {
+		return backlog[i].DeviceID < backlog[j].DeviceID
+	})
+	return backlog
+}
+
+// observeDevice exports the latency of a change on a device as metrics
+func observeDevice(deviceID devicetype.ID, deviceType devicetype.Type, latency time.Duration, slo time.Duration) {
+	deviceLatencySummary.WithLabelValues(string(deviceID), string(deviceType)).Observe(latency.Seconds())
+	if slo > 0 && latency > slo {
+		deviceOverSLOCounter.WithLabelValues(string(deviceID), string(deviceType)).Inc()
+	}
+}
+
+// addSlowest keeps a change among the slowest ones, slowest first
+func (t *Tracker) addSlowest(latency ChangeLatency) {
+	i := sort.Search(len(t.slowest), func(i int) bool {
+		return t.slowest[i].Latency < latency.Latency
+	})
+	if i == maxSlowest {
+		return
+	}
+	t.slowest = append(t.slowest, ChangeLatency{})
+	copy(t.slowest[i+1:], t.slowest[i:])
+	t.slowest[i] = latency
+	if len(t.slowest) > maxSlowest {
+		t.slowest = t.slowest[:maxSlowest]
+	}
+}
+
+// ChangeStats returns the stats of the latency of the network changes
+func (t *Tracker) ChangeStats() Stats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.changes.stats(0)
+}
+
+// SlowestChanges returns the network changes that took the longest, slowest first
+func (t *Tracker) SlowestChanges() []ChangeLatency {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	slowest := make([]ChangeLatency, len(t.slowest))
+	copy(slowest, t.slowest)
+	return slowest
+}
+
+// DeviceStats returns the stats of the latency of the changes of each device, slowest first
+func (t *Tracker) DeviceStats() []DeviceStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	devices := make([]DeviceStats, 0, len(t.devices))
+	for deviceID, latencies := range t.devices {
+		devices = append(devices, DeviceStats{
+			Stats:      latencies.stats(t.slo),
+			DeviceID:   deviceID,
+			DeviceType: t.deviceTypes[deviceID],
+		})
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].P90 != devices[j].P90 {
+			return devices[i].P90 > devices[j].P90
+		}
+		return devices[i].DeviceID < devices[j].DeviceID
+	})
+	return devices
+}
+
+// TypeStats returns the stats of the latency of the changes of the devices of each type
+func (t *Tracker) TypeStats() map[devicetype.Type]Stats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	types := make(map[devicetype.Type]Stats, len(t.types))
+	for deviceType, latencies := range t.types {
+		types[deviceType] = latencies.stats(t.slo)
+	}
+	return types
+}
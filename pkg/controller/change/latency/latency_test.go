@@ -0,0 +1,198 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	types "github.com/onosproject/onos-api/go/onos/config"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	devicechangestore "github.com/onosproject/onos-config/pkg/store/change/device"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SeriesStats(t *testing.T) {
+	var latencies series
+	for i := 100; i > 0; i-- {
+		latencies = latencies.add(time.Duration(i) * time.Millisecond)
+	}
+	stats := latencies.stats(95 * time.Millisecond)
+	assert.Equal(t, 100, stats.Count)
+	assert.Equal(t, 50*time.Millisecond, stats.P50)
+	assert.Equal(t, 90*time.Millisecond, stats.P90)
+	assert.Equal(t, 99*time.Millisecond, stats.P99)
+	assert.Equal(t, 100*time.Millisecond, stats.Max)
+	assert.Equal(t, 5, stats.OverSLO)
+
+	// Only the latest latencies are kept
+	for i := 0; i < maxSamples; i++ {
+		latencies = latencies.add(time.Second)
+	}
+	stats = latencies.stats(0)
+	assert.Equal(t, maxSamples, stats.Count)
+	assert.Equal(t, time.Second, stats.P50)
+	assert.Equal(t, 0, stats.OverSLO)
+}
+
+// newCompleteChange creates a network change created at the given time, with a complete device
+// change on each of the devices
+func newCompleteChange(t *testing.T, deviceChanges devicechangestore.Store, id networkchange.ID,
+	index networkchange.Index, created time.Time, devices map[devicetype.ID]devicetype.Type) *networkchange.NetworkChange {
+	networkChange := &networkchange.NetworkChange{
+		ID:      id,
+		Index:   index,
+		Created: created,
+		Updated: time.Now(),
+		Status: changetypes.Status{
+			Phase: changetypes.Phase_CHANGE,
+			State: changetypes.State_COMPLETE,
+		},
+	}
+	for deviceID, deviceType := range devices {
+		deviceChange := &devicechange.DeviceChange{
+			Index: devicechange.Index(index),
+			NetworkChange: devicechange.NetworkChangeRef{
+				ID:    types.ID(id),
+				Index: types.Index(index),
+			},
+			Change: &devicechange.Change{
+				DeviceID:      deviceID,
+				DeviceVersion: "1.0.0",
+				DeviceType:    deviceType,
+				Values: []*devicechange.ChangeValue{{
+					Path:  "/system/config/hostname",
+					Value: devicechange.NewTypedValueString(string(deviceID)),
+				}},
+			},
+		}
+		assert.NoError(t, deviceChanges.Create(deviceChange))
+		deviceChange.Status.State = changetypes.State_COMPLETE
+		assert.NoError(t, deviceChanges.Update(deviceChange))
+		networkChange.Refs = append(networkChange.Refs, &networkchange.DeviceChangeRef{DeviceChangeID: deviceChange.ID})
+	}
+	return networkChange
+}
+
+func TestTracker(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+	atomixClient, err := test.NewClient("test")
+	assert.NoError(t, err)
+
+	networkChanges, err := networkchangestore.NewAtomixStore(atomixClient)
+	assert.NoError(t, err)
+	defer networkChanges.Close()
+	deviceChanges, err := devicechangestore.NewAtomixStore(atomixClient)
+	assert.NoError(t, err)
+	defer deviceChanges.Close()
+
+	tracker := NewTracker(90*time.Minute, networkChanges, deviceChanges)
+	assert.Equal(t, 90*time.Minute, tracker.SLO())
+
+	now := time.Now()
+	change1 := newCompleteChange(t, deviceChanges, "change-1", 1, now.Add(-time.Hour),
+		map[devicetype.ID]devicetype.Type{"device-1": "Devicesim", "device-2": "Stratum"})
+	change2 := newCompleteChange(t, deviceChanges, "change-2", 2, now.Add(-2*time.Hour),
+		map[devicetype.ID]devicetype.Type{"device-2": "Stratum"})
+	tracker.Record(change1)
+	tracker.Record(change2)
+	// A change is only recorded once
+	tracker.Record(change1)
+
+	// A pending change is not recorded
+	change3 := newCompleteChange(t, deviceChanges, "change-3", 3, now.Add(-3*time.Hour),
+		map[devicetype.ID]devicetype.Type{"device-3": "Devicesim"})
+	change3.Status.State = changetypes.State_PENDING
+	tracker.Record(change3)
+
+	changes := tracker.ChangeStats()
+	assert.Equal(t, 2, changes.Count)
+	assert.True(t, changes.Max >= 2*time.Hour)
+
+	slowest := tracker.SlowestChanges()
+	assert.Len(t, slowest, 2)
+	assert.Equal(t, networkchange.ID("change-2"), slowest[0].NetworkChange)
+	assert.Equal(t, devicetype.ID("device-2"), slowest[0].SlowestDevice)
+	assert.Equal(t, networkchange.ID("change-1"), slowest[1].NetworkChange)
+
+	// The slowest device comes first, with the change over the SLO counted against it
+	devices := tracker.DeviceStats()
+	assert.Len(t, devices, 2)
+	assert.Equal(t, devicetype.ID("device-2"), devices[0].DeviceID)
+	assert.Equal(t, devicetype.Type("Stratum"), devices[0].DeviceType)
+	assert.Equal(t, 2, devices[0].Count)
+	assert.True(t, devices[0].P90 >= 2*time.Hour)
+	assert.Equal(t, 1, devices[0].OverSLO)
+	assert.Equal(t, devicetype.ID("device-1"), devices[1].DeviceID)
+	assert.Equal(t, 1, devices[1].Count)
+	assert.Equal(t, 0, devices[1].OverSLO)
+
+	deviceTypes := tracker.TypeStats()
+	assert.Len(t, deviceTypes, 2)
+	assert.Equal(t, 2, deviceTypes["Stratum"].Count)
+	assert.Equal(t, 1, deviceTypes["Devicesim"].Count)
+}
+
+func TestTrackerBacklog(t *testing.T) {
+	tracker := NewTracker(0, nil, nil)
+	tracker.devices["device-1"] = series{}.add(10 * time.Second).add(30 * time.Second).add(20 * time.Second)
+
+	newChange := func(id networkchange.ID, index networkchange.Index, devices ...devicetype.ID) *networkchange.NetworkChange {
+		change := &networkchange.NetworkChange{
+			ID:     id,
+			Index:  index,
+			Status: changetypes.Status{Phase: changetypes.Phase_CHANGE, State: changetypes.State_PENDING},
+		}
+		for _, deviceID := range devices {
+			change.Changes = append(change.Changes, &devicechange.Change{DeviceID: deviceID})
+		}
+		return change
+	}
+	change1 := newChange("change-1", 1, "device-1", "device-2")
+	tracker.track(change1)
+	tracker.track(newChange("change-2", 2, "device-1"))
+	tracker.track(newChange("change-3", 3, "device-3"))
+	change4 := newChange("change-4", 4, "device-1", "device-2")
+	tracker.track(change4)
+
+	// Only the pending changes of its devices with a lower index are ahead of a change
+	backlog := tracker.Backlog(change4)
+	assert.Len(t, backlog, 2)
+	assert.Equal(t, devicetype.ID("device-1"), backlog[0].DeviceID)
+	assert.Equal(t, 2, backlog[0].Position)
+	assert.Equal(t, 60*time.Second, backlog[0].ETA)
+	assert.Equal(t, devicetype.ID("device-2"), backlog[1].DeviceID)
+	assert.Equal(t, 1, backlog[1].Position)
+	assert.Equal(t, time.Duration(0), backlog[1].ETA, "no change of device-2 completed")
+
+	// A change no longer pending is out of the backlog
+	change1.Status.State = changetypes.State_COMPLETE
+	tracker.track(change1)
+	backlog = tracker.Backlog(change4)
+	assert.Equal(t, 1, backlog[0].Position)
+	assert.Equal(t, 0, backlog[1].Position)
+	assert.Equal(t, 0, tracker.Backlog(change1)[0].Position)
+}
@@ -0,0 +1,89 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"fmt"
+	"sync"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/store/change/atomicity"
+	"github.com/onosproject/onos-lib-go/pkg/controller"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// atomicityStore holds the atomicity modes of the network changes that are not all-or-nothing
+var atomicityStore atomicity.Store
+var atomicityStoreMu = &sync.RWMutex{}
+
+// SetAtomicityStore sets the store of the atomicity modes of the network changes
+func SetAtomicityStore(store atomicity.Store) {
+	atomicityStoreMu.Lock()
+	defer atomicityStoreMu.Unlock()
+	atomicityStore = store
+}
+
+// GetAtomicityStore returns the store of the atomicity modes, nil if none is set
+func GetAtomicityStore() atomicity.Store {
+	atomicityStoreMu.RLock()
+	defer atomicityStoreMu.RUnlock()
+	return atomicityStore
+}
+
+// getAtomicityMode returns the atomicity mode of a network change, all-or-nothing if no store is set
+func getAtomicityMode(id networkchange.ID) (atomicity.Mode, error) {
+	store := GetAtomicityStore()
+	if store == nil {
+		return atomicity.ModeAllOrNothing, nil
+	}
+	return store.Get(id)
+}
+
+// failBestEffort fails a best-effort network change that a device rejected once all of its device
+// changes are done, without rolling it back on the devices that applied it. The rejected device
+// changes are moved to the ROLLBACK phase as never applied, as those of a cancelled change are, for
+// them to be left out of the configuration of their devices until the change is retried.
+func (r *Reconciler) failBestEffort(change *networkchange.NetworkChange, deviceChanges []*devicechange.DeviceChange) (controller.Result, error) {
+	rejected := make([]*devicechange.DeviceChange, 0)
+	for _, deviceChange := range deviceChanges {
+		switch deviceChange.Status.State {
+		case changetypes.State_PENDING:
+			return controller.Result{}, errors.NewInternal("waiting for device change(s) to complete %s", change.ID)
+		case changetypes.State_FAILED:
+			rejected = append(rejected, deviceChange)
+		}
+	}
+
+	for _, deviceChange := range rejected {
+		deviceChange.Status.Phase = changetypes.Phase_ROLLBACK
+		deviceChange.Status.State = changetypes.State_COMPLETE
+		log.Infof("Leaving out rejected DeviceChange %s of best-effort NetworkChange %s", deviceChange.ID, change.ID)
+		if err := r.deviceChanges.Update(deviceChange); err != nil {
+			log.Warnf("error updating device change %s %v", err.Error(), deviceChange)
+			return controller.Result{}, err
+		}
+	}
+	change.Status.State = changetypes.State_FAILED
+	change.Status.Reason = changetypes.Reason_ERROR
+	change.Status.Message = fmt.Sprintf("change rejected by %d of %d device(s), kept on the others", len(rejected), len(deviceChanges))
+	log.Infof("Failing best-effort NetworkChange %s: %s", change.ID, change.Status.Message)
+	if err := r.networkChanges.Update(change); err != nil {
+		log.Warnf("error updating network change %s %v", err.Error(), change)
+		return controller.Result{}, err
+	}
+	return controller.Result{}, nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	"github.com/golang/mock/gomock"
+	"github.com/onosproject/onos-api/go/onos/config/change"
+	"github.com/onosproject/onos-config/pkg/store/change/atomicity"
+	"github.com/onosproject/onos-lib-go/pkg/controller"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcilerBestEffort(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	atomixClient, err := test.NewClient("test")
+	assert.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	networkChanges, deviceChanges, devices := newStores(t, ctrl, atomixClient)
+	defer networkChanges.Close()
+	defer deviceChanges.Close()
+
+	modes := atomicity.NewLocalStore()
+	SetAtomicityStore(modes)
+	defer SetAtomicityStore(nil)
+	assert.NoError(t, modes.Create(change1, atomicity.ModeBestEffort))
+
+	reconciler := &Reconciler{
+		networkChanges: networkChanges,
+		deviceChanges:  deviceChanges,
+		devices:        devices,
+	}
+
+	// Create the device changes, apply the network change and make the device changes pending
+	networkChange := newChange(change1, device1, device2)
+	assert.NoError(t, networkChanges.Create(networkChange))
+	for i := 0; i < 3; i++ {
+		_, err = reconciler.Reconcile(controller.NewID(string(networkChange.ID)))
+		assert.NoError(t, err)
+	}
+
+	// The network change waits for all of its devices once one rejected its change
+	deviceChange2, err := deviceChanges.Get("change-1:device-2:1.0.0")
+	assert.NoError(t, err)
+	deviceChange2.Status.State = change.State_FAILED
+	deviceChange2.Status.Reason = change.Reason_ERROR
+	deviceChange2.Status.Message = "failed for test"
+	assert.NoError(t, deviceChanges.Update(deviceChange2))
+	_, err = reconciler.Reconcile(controller.NewID(string(networkChange.ID)))
+	assert.EqualError(t, err, "waiting for device change(s) to complete change-1")
+
+	// Then fails, without rolling back the devices that applied the change
+	deviceChange1, err := deviceChanges.Get("change-1:device-1:1.0.0")
+	assert.NoError(t, err)
+	deviceChange1.Status.State = change.State_COMPLETE
+	assert.NoError(t, deviceChanges.Update(deviceChange1))
+	_, err = reconciler.Reconcile(controller.NewID(string(networkChange.ID)))
+	assert.NoError(t, err)
+
+	networkChange, err = networkChanges.Get(change1)
+	assert.NoError(t, err)
+	assert.Equal(t, change.Phase_CHANGE, networkChange.Status.Phase)
+	assert.Equal(t, change.State_FAILED, networkChange.Status.State)
+	assert.Equal(t, change.Reason_ERROR, networkChange.Status.Reason)
+	assert.Equal(t, "change rejected by 1 of 2 device(s), kept on the others", networkChange.Status.Message)
+
+	deviceChange1, err = deviceChanges.Get("change-1:device-1:1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, change.Phase_CHANGE, deviceChange1.Status.Phase)
+	assert.Equal(t, change.State_COMPLETE, deviceChange1.Status.State)
+	deviceChange2, err = deviceChanges.Get("change-1:device-2:1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, change.Phase_ROLLBACK, deviceChange2.Status.Phase)
+	assert.Equal(t, change.State_COMPLETE, deviceChange2.Status.State)
+	assert.Equal(t, "failed for test", deviceChange2.Status.Message)
+
+	// The rejected device change is pushed again on its own when the change is retried
+	retried, err := Retry(networkChanges, deviceChanges, networkChange, true, "device-2 fixed")
+	assert.NoError(t, err)
+	assert.Len(t, retried, 1)
+	assert.Equal(t, deviceChange2.ID, retried[0].ID)
+}
@@ -0,0 +1,68 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"fmt"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicechangestore "github.com/onosproject/onos-config/pkg/store/change/device"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+)
+
+// CancelledPrefix starts the status message of a cancelled network change
+const CancelledPrefix = "Cancelled"
+
+// Cancel stops a network change in progress. Its device changes that are not applied are moved to
+// the ROLLBACK phase as never applied, so they are neither pushed to their devices nor part of their
+// configuration any more. Without rollback the network change is FAILED and what its device changes
+// applied stays; with rollback it enters the ROLLBACK phase, so that the controller rolls back what
+// they applied. The message is recorded on the changes, after CancelledPrefix.
+func Cancel(networkChanges networkchangestore.Store, deviceChanges devicechangestore.Store,
+	change *networkchange.NetworkChange, rollback bool, message string) error {
+	message = fmt.Sprintf("%s: %s", CancelledPrefix, message)
+	if rollback {
+		change.Status.Incarnation++
+		change.Status.Phase = changetypes.Phase_ROLLBACK
+		change.Status.State = changetypes.State_PENDING
+		change.Status.Reason = changetypes.Reason_NONE
+		change.Status.Message = message
+		log.Infof("Cancelling NetworkChange %s, rolling it back", change.ID)
+		return networkChanges.Update(change)
+	}
+
+	for _, ref := range change.Refs {
+		deviceChange, err := deviceChanges.Get(ref.DeviceChangeID)
+		if err != nil {
+			return err
+		}
+		if deviceChange.Status.Phase != changetypes.Phase_CHANGE || deviceChange.Status.State == changetypes.State_COMPLETE {
+			continue
+		}
+		deviceChange.Status.Phase = changetypes.Phase_ROLLBACK
+		deviceChange.Status.State = changetypes.State_COMPLETE
+		deviceChange.Status.Reason = changetypes.Reason_NONE
+		deviceChange.Status.Message = message
+		if err := deviceChanges.Update(deviceChange); err != nil {
+			return err
+		}
+	}
+	change.Status.State = changetypes.State_FAILED
+	change.Status.Reason = changetypes.Reason_NONE
+	change.Status.Message = message
+	log.Infof("Cancelling NetworkChange %s", change.ID)
+	return networkChanges.Update(change)
+}
@@ -0,0 +1,93 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	types "github.com/onosproject/onos-api/go/onos/config"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicechangestore "github.com/onosproject/onos-config/pkg/store/change/device"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancel(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+	atomixClient, err := test.NewClient("test")
+	assert.NoError(t, err)
+
+	networkChanges, err := networkchangestore.NewAtomixStore(atomixClient)
+	assert.NoError(t, err)
+	defer networkChanges.Close()
+	deviceChanges, err := devicechangestore.NewAtomixStore(atomixClient)
+	assert.NoError(t, err)
+	defer deviceChanges.Close()
+
+	change := &networkchange.NetworkChange{
+		ID:      "change-1",
+		Changes: []*devicechange.Change{&deviceChange1, &deviceChange2},
+		Status:  changetypes.Status{Incarnation: 1},
+	}
+	assert.NoError(t, networkChanges.Create(change))
+	states := []changetypes.State{changetypes.State_COMPLETE, changetypes.State_PENDING}
+	for i, c := range change.Changes {
+		deviceChange := &devicechange.DeviceChange{
+			Index:         devicechange.Index(change.Index),
+			NetworkChange: devicechange.NetworkChangeRef{ID: types.ID(change.ID), Index: types.Index(change.Index)},
+			Change:        c,
+			Status:        changetypes.Status{Incarnation: 1, State: states[i]},
+		}
+		assert.NoError(t, deviceChanges.Create(deviceChange))
+		change.Refs = append(change.Refs, &networkchange.DeviceChangeRef{DeviceChangeID: deviceChange.ID})
+	}
+	assert.NoError(t, networkChanges.Update(change))
+
+	assert.NoError(t, Cancel(networkChanges, deviceChanges, change, false, "by 'admin'"))
+	change, err = networkChanges.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.Phase_CHANGE, change.Status.Phase)
+	assert.Equal(t, changetypes.State_FAILED, change.Status.State)
+	assert.Equal(t, "Cancelled: by 'admin'", change.Status.Message)
+
+	// What was applied stays, what was not is never applied
+	applied, err := deviceChanges.Get(change.Refs[0].DeviceChangeID)
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.Phase_CHANGE, applied.Status.Phase)
+	assert.Equal(t, changetypes.State_COMPLETE, applied.Status.State)
+	notApplied, err := deviceChanges.Get(change.Refs[1].DeviceChangeID)
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.Phase_ROLLBACK, notApplied.Status.Phase)
+	assert.Equal(t, changetypes.State_COMPLETE, notApplied.Status.State)
+	assert.Equal(t, "Cancelled: by 'admin'", notApplied.Status.Message)
+
+	change.Status.Phase = changetypes.Phase_CHANGE
+	change.Status.State = changetypes.State_PENDING
+	assert.NoError(t, Cancel(networkChanges, deviceChanges, change, true, "by 'admin'"))
+	change, err = networkChanges.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.Phase_ROLLBACK, change.Status.Phase)
+	assert.Equal(t, changetypes.State_PENDING, change.Status.State)
+	assert.Equal(t, uint64(2), change.Status.Incarnation)
+}
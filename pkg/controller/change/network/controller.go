@@ -22,6 +22,7 @@ import (
 	"github.com/onosproject/onos-api/go/onos/topo"
 	configcontroller "github.com/onosproject/onos-config/pkg/controller"
 	devicetopo "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/store/change/atomicity"
 	devicechangestore "github.com/onosproject/onos-config/pkg/store/change/device"
 	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
 	devicestore "github.com/onosproject/onos-config/pkg/store/device"
@@ -39,6 +40,8 @@ var log = logging.GetLogger("controller", "change", "network")
 // NewController returns a new config controller
 func NewController(leadership leadershipstore.Store, deviceCache cache.Cache, devices devicestore.Store, networkChanges networkchangestore.Store, deviceChanges devicechangestore.Store) *controller.Controller {
 	c := controller.NewController("NetworkChange")
+	queue := configcontroller.NewQueue("NetworkChange", nil)
+	c.Filter(queue.Filter(nil))
 	c.Activate(&configcontroller.LeadershipActivator{
 		Store: leadership,
 	})
@@ -50,11 +53,11 @@ func NewController(leadership leadershipstore.Store, deviceCache cache.Cache, de
 		DeviceStore: devices,
 		ChangeStore: deviceChanges,
 	})
-	c.Reconcile(&Reconciler{
+	c.Reconcile(queue.Reconcile(&Reconciler{
 		networkChanges: networkChanges,
 		deviceChanges:  deviceChanges,
 		devices:        devices,
-	})
+	}))
 	return c
 }
 
@@ -154,9 +157,15 @@ func (r *Reconciler) reconcilePendingChange(change *networkchange.NetworkChange)
 		return controller.Result{}, nil
 	}
 	log.Debugf("checking device changes are failed %s", change.ID)
-	// If any device change has failed, roll back all device changes
+	// If any device change has failed, roll back all device changes, unless the change is best-effort
 	if r.isDeviceChangesFailed(change, deviceChanges) {
-		_, err := r.ensureDeviceChangeRollbacks(change, deviceChanges)
+		mode, err := getAtomicityMode(change.ID)
+		if err != nil {
+			return controller.Result{}, err
+		} else if mode == atomicity.ModeBestEffort {
+			return r.failBestEffort(change, deviceChanges)
+		}
+		_, err = r.ensureDeviceChangeRollbacks(change, deviceChanges)
 		if err != nil {
 			return controller.Result{}, err
 		}
@@ -0,0 +1,67 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"fmt"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicechangestore "github.com/onosproject/onos-config/pkg/store/change/device"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+)
+
+// RetriedPrefix starts the status message of a retried network change
+const RetriedPrefix = "Retried"
+
+// Retry re-drives a network change that failed, i.e. that was rolled back after a device rejected
+// it or that was cancelled. Its device changes are pushed to their devices again under a new
+// incarnation; with failedOnly, the device changes that are applied already are left as they are.
+// The message is recorded on the network change, after RetriedPrefix. Retry returns the device
+// changes that are pushed again.
+func Retry(networkChanges networkchangestore.Store, deviceChanges devicechangestore.Store,
+	change *networkchange.NetworkChange, failedOnly bool, message string) ([]*devicechange.DeviceChange, error) {
+	incarnation := change.Status.Incarnation + 1
+	retried := make([]*devicechange.DeviceChange, 0, len(change.Refs))
+	for _, ref := range change.Refs {
+		deviceChange, err := deviceChanges.Get(ref.DeviceChangeID)
+		if err != nil {
+			return nil, err
+		}
+		deviceChange.Status.Incarnation = incarnation
+		if !failedOnly || deviceChange.Status.Phase != changetypes.Phase_CHANGE ||
+			deviceChange.Status.State != changetypes.State_COMPLETE {
+			deviceChange.Status.Phase = changetypes.Phase_CHANGE
+			deviceChange.Status.State = changetypes.State_PENDING
+			deviceChange.Status.Reason = changetypes.Reason_NONE
+			deviceChange.Status.Message = ""
+			retried = append(retried, deviceChange)
+		}
+		if err := deviceChanges.Update(deviceChange); err != nil {
+			return nil, err
+		}
+	}
+	change.Status.Incarnation = incarnation
+	change.Status.Phase = changetypes.Phase_CHANGE
+	change.Status.State = changetypes.State_PENDING
+	change.Status.Reason = changetypes.Reason_NONE
+	change.Status.Message = fmt.Sprintf("%s: %s", RetriedPrefix, message)
+	log.Infof("Retrying NetworkChange %s on %d device(s)", change.ID, len(retried))
+	if err := networkChanges.Update(change); err != nil {
+		return nil, err
+	}
+	return retried, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	types "github.com/onosproject/onos-api/go/onos/config"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicechangestore "github.com/onosproject/onos-config/pkg/store/change/device"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+	atomixClient, err := test.NewClient("test")
+	assert.NoError(t, err)
+
+	networkChanges, err := networkchangestore.NewAtomixStore(atomixClient)
+	assert.NoError(t, err)
+	defer networkChanges.Close()
+	deviceChanges, err := devicechangestore.NewAtomixStore(atomixClient)
+	assert.NoError(t, err)
+	defer deviceChanges.Close()
+
+	// A change cancelled after it was applied to the first device only
+	change := &networkchange.NetworkChange{
+		ID:      "change-1",
+		Changes: []*devicechange.Change{&deviceChange1, &deviceChange2},
+		Status:  changetypes.Status{Incarnation: 1, State: changetypes.State_FAILED, Message: "Cancelled: by 'admin'"},
+	}
+	assert.NoError(t, networkChanges.Create(change))
+	phases := []changetypes.Phase{changetypes.Phase_CHANGE, changetypes.Phase_ROLLBACK}
+	for i, c := range change.Changes {
+		deviceChange := &devicechange.DeviceChange{
+			Index:         devicechange.Index(change.Index),
+			NetworkChange: devicechange.NetworkChangeRef{ID: types.ID(change.ID), Index: types.Index(change.Index)},
+			Change:        c,
+			Status:        changetypes.Status{Incarnation: 1, Phase: phases[i], State: changetypes.State_COMPLETE},
+		}
+		assert.NoError(t, deviceChanges.Create(deviceChange))
+		change.Refs = append(change.Refs, &networkchange.DeviceChangeRef{DeviceChangeID: deviceChange.ID})
+	}
+	assert.NoError(t, networkChanges.Update(change))
+
+	retried, err := Retry(networkChanges, deviceChanges, change, true, "by 'admin'")
+	assert.NoError(t, err)
+	assert.Len(t, retried, 1)
+	assert.Equal(t, change.Refs[1].DeviceChangeID, retried[0].ID)
+	change, err = networkChanges.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.Phase_CHANGE, change.Status.Phase)
+	assert.Equal(t, changetypes.State_PENDING, change.Status.State)
+	assert.Equal(t, uint64(2), change.Status.Incarnation)
+	assert.Equal(t, "Retried: by 'admin'", change.Status.Message)
+
+	// The applied device change is counted as complete for the new incarnation
+	applied, err := deviceChanges.Get(change.Refs[0].DeviceChangeID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), applied.Status.Incarnation)
+	assert.Equal(t, changetypes.State_COMPLETE, applied.Status.State)
+	notApplied, err := deviceChanges.Get(change.Refs[1].DeviceChangeID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), notApplied.Status.Incarnation)
+	assert.Equal(t, changetypes.Phase_CHANGE, notApplied.Status.Phase)
+	assert.Equal(t, changetypes.State_PENDING, notApplied.Status.State)
+
+	// Without failedOnly every device change is pushed again
+	retried, err = Retry(networkChanges, deviceChanges, change, false, "by 'admin'")
+	assert.NoError(t, err)
+	assert.Len(t, retried, 2)
+	applied, err = deviceChanges.Get(change.Refs[0].DeviceChangeID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), applied.Status.Incarnation)
+	assert.Equal(t, changetypes.State_PENDING, applied.Status.State)
+}
@@ -0,0 +1,54 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// CheckLast checks that a network change is the last one on the stack of changes, i.e. that every
+// change after it is rolled back, as only the last change can be rolled back
+func CheckLast(networkChanges networkchangestore.Store, change *networkchange.NetworkChange) error {
+	next, err := networkChanges.GetNext(change.Index)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.NewInternal("Error on get next change during rollback %v", err)
+	}
+	// if the error is nil and the change is nil the requested one is the last one thus we proceed.
+	// if there is a next change but the phase is different from ROLLBACK and the status is different from COMPLETE we
+	// fail the operation because there is a need to rollback the previous one.
+	if next != nil && (next.Status.Phase != changetypes.Phase_ROLLBACK ||
+		(next.Status.Phase == changetypes.Phase_ROLLBACK && next.Status.State != changetypes.State_COMPLETE)) {
+		return errors.NewInternal("change %s is not the last active on the stack of changes", change.ID)
+	}
+	return nil
+}
+
+// Rollback moves a network change to the ROLLBACK phase, so that the controller rolls back what its
+// device changes applied. The message is recorded on the change; the caller checks that the change
+// is the last one with CheckLast.
+func Rollback(networkChanges networkchangestore.Store, change *networkchange.NetworkChange, message string) error {
+	change.Status.Incarnation++
+	change.Status.Phase = changetypes.Phase_ROLLBACK
+	change.Status.State = changetypes.State_PENDING
+	change.Status.Reason = changetypes.Reason_NONE
+	change.Status.Message = message
+	log.Infof("Rolling back NetworkChange %s: %s", change.ID, message)
+	return networkChanges.Update(change)
+}
@@ -0,0 +1,73 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollback(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+	atomixClient, err := test.NewClient("test")
+	assert.NoError(t, err)
+
+	networkChanges, err := networkchangestore.NewAtomixStore(atomixClient)
+	assert.NoError(t, err)
+	defer networkChanges.Close()
+
+	change1 := &networkchange.NetworkChange{
+		ID:      "change-1",
+		Changes: []*devicechange.Change{&deviceChange1},
+		Status:  changetypes.Status{Incarnation: 1, State: changetypes.State_COMPLETE},
+	}
+	assert.NoError(t, networkChanges.Create(change1))
+	assert.NoError(t, CheckLast(networkChanges, change1))
+
+	change2 := &networkchange.NetworkChange{
+		ID:      "change-2",
+		Changes: []*devicechange.Change{&deviceChange2},
+		Status:  changetypes.Status{Incarnation: 1, State: changetypes.State_COMPLETE},
+	}
+	assert.NoError(t, networkChanges.Create(change2))
+	assert.Error(t, CheckLast(networkChanges, change1))
+
+	assert.NoError(t, CheckLast(networkChanges, change2))
+	assert.NoError(t, Rollback(networkChanges, change2, "not wanted"))
+	change2, err = networkChanges.Get("change-2")
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.Phase_ROLLBACK, change2.Status.Phase)
+	assert.Equal(t, changetypes.State_PENDING, change2.Status.State)
+	assert.Equal(t, uint64(2), change2.Status.Incarnation)
+	assert.Equal(t, "not wanted", change2.Status.Message)
+
+	// Once the change after it is rolled back, a change is the last one again
+	assert.Error(t, CheckLast(networkChanges, change1))
+	change2.Status.State = changetypes.State_COMPLETE
+	assert.NoError(t, networkChanges.Update(change2))
+	assert.NoError(t, CheckLast(networkChanges, change1))
+}
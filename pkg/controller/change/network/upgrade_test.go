@@ -0,0 +1,133 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/mock/gomock"
+	types "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	leadershipstore "github.com/onosproject/onos-config/pkg/store/leadership"
+	mastershipstore "github.com/onosproject/onos-config/pkg/store/mastership"
+	"github.com/onosproject/onos-config/pkg/store/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// A network change made through a node of the previous release, which joins the election without
+// advertising its capabilities, is reconciled by an upgraded node. While both run, the upgraded
+// node writes the changes as the previous release reads them.
+func Test_MixedVersionReconcile(t *testing.T) {
+	defer schema.SetWriteVersions(nil)
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	atomixClient, err := test.NewClient("test")
+	assert.NoError(t, err)
+	legacyClient, err := test.NewClient("legacy")
+	assert.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	networkChanges, deviceChanges, devices := newStores(t, ctrl, atomixClient)
+	defer networkChanges.Close()
+	defer deviceChanges.Close()
+
+	deviceCache := newDeviceCache(ctrl, device1)
+	defer deviceCache.Close()
+
+	leadershipStore, err := leadershipstore.NewAtomixStore(atomixClient)
+	assert.NoError(t, err)
+	defer leadershipStore.Close()
+
+	legacyElection, err := legacyClient.GetElection(context.Background(), "onos-config-leaderships")
+	assert.NoError(t, err)
+	_, err = legacyElection.Enter(context.Background())
+	assert.NoError(t, err)
+
+	// The upgraded node writes the records the node of the previous release reads, as the manager has it
+	assert.Eventually(t, func() bool {
+		capabilities, err := leadershipStore.Capabilities()
+		return err == nil && capabilities.Schemas[schema.NetworkChange] == schema.Unversioned
+	}, 5*time.Second, 10*time.Millisecond)
+	capabilities, err := leadershipStore.Capabilities()
+	assert.NoError(t, err)
+	schema.SetWriteVersions(capabilities.Schemas)
+
+	mastershipStore, err := mastershipstore.NewAtomixStore(atomixClient, "test")
+	assert.NoError(t, err)
+	defer mastershipStore.Close()
+
+	networkChangeController, deviceChangeController := setupControllers(t, networkChanges, deviceChanges, devices,
+		deviceCache, leadershipStore, mastershipStore)
+
+	mockTargetDevice1, cancel1 := newMockTarget(t, ctrl, devicetype.NewVersionedID(device1, v1))
+	defer cancel1()
+	mockTargetDevice1.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+
+	assert.NoError(t, networkChangeController.Start())
+	defer networkChangeController.Stop()
+	assert.NoError(t, deviceChangeController.Start())
+	defer deviceChangeController.Stop()
+
+	// The node of the previous release stores the change as a plain proto
+	legacyChanges, err := legacyClient.GetIndexedMap(context.Background(), "onos-config-network-changes")
+	assert.NoError(t, err)
+	defer legacyChanges.Close(context.Background())
+	bytes, err := proto.Marshal(&networkchange.NetworkChange{
+		ID:      "change-1",
+		Changes: []*devicechange.Change{&deviceChange1},
+	})
+	assert.NoError(t, err)
+	_, err = legacyChanges.Append(context.Background(), "change-1", bytes)
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		change, err := networkChanges.Get("change-1")
+		return err == nil && change.Status.State == types.State_COMPLETE
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The node of the previous release reads the changes written by the upgraded node
+	entry, err := legacyChanges.Get(context.Background(), "change-1")
+	assert.NoError(t, err)
+	networkChange := &networkchange.NetworkChange{}
+	assert.NoError(t, proto.Unmarshal(entry.Value, networkChange))
+	assert.Equal(t, types.State_COMPLETE, networkChange.Status.State)
+
+	legacyDeviceChanges, err := legacyClient.GetIndexedMap(context.Background(), "onos-config-device-changes",
+		primitive.WithClusterKey("device-changes-"+string(devicetype.NewVersionedID(device1, v1))))
+	assert.NoError(t, err)
+	defer legacyDeviceChanges.Close(context.Background())
+	entry, err = legacyDeviceChanges.Get(context.Background(), "change-1:device-1:1.0.0")
+	assert.NoError(t, err)
+	deviceChange := &devicechange.DeviceChange{}
+	assert.NoError(t, proto.Unmarshal(entry.Value, deviceChange))
+	assert.Equal(t, types.State_COMPLETE, deviceChange.Status.State)
+	version, err := schema.VersionOf(entry.Value)
+	assert.NoError(t, err)
+	assert.Equal(t, schema.Unversioned, version)
+}
@@ -0,0 +1,360 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package watchdog detects network changes that make no progress and escalates them by policy.
+package watchdog
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicechangectl "github.com/onosproject/onos-config/pkg/controller/change/device"
+	networkchangectl "github.com/onosproject/onos-config/pkg/controller/change/network"
+	devicechangestore "github.com/onosproject/onos-config/pkg/store/change/device"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+	leadershipstore "github.com/onosproject/onos-config/pkg/store/leadership"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+)
+
+var log = logging.GetLogger("controller", "change", "watchdog")
+
+// maxDecisions is the number of decisions the watchdog remembers
+const maxDecisions = 100
+
+// notePrefix starts the note a decision of the watchdog leaves in the status message of a change
+const notePrefix = "[watchdog: "
+
+// Action is what the watchdog does with a stuck change
+type Action string
+
+const (
+	// ActionFlag only records that the change is stuck
+	ActionFlag Action = "flag"
+	// ActionRetry reconciles the change and its device changes again, clearing the error of a
+	// change that was rolled back
+	ActionRetry Action = "retry"
+	// ActionCancel cancels the change without rolling it back
+	ActionCancel Action = "cancel"
+)
+
+// ParseAction parses the name of an action
+func ParseAction(name string) (Action, error) {
+	switch action := Action(name); action {
+	case ActionFlag, ActionRetry, ActionCancel:
+		return action, nil
+	}
+	return "", errors.NewInvalid("unknown watchdog action '%s'; expected flag, retry or cancel", name)
+}
+
+// Policy configures the watchdog
+type Policy struct {
+	// StuckAfter is how long a pending change may make no progress before it is stuck
+	StuckAfter time.Duration
+	// Interval is how often the changes are checked; StuckAfter/4 if zero
+	Interval time.Duration
+	Action   Action
+}
+
+// Decision is what the watchdog did with a stuck change
+type Decision struct {
+	Time          time.Time
+	NetworkChange networkchange.ID
+	// DeviceChanges are the pending device changes of the change
+	DeviceChanges []devicechange.ID
+	// Stuck is how long the change made no progress
+	Stuck  time.Duration
+	Action Action
+	// Error is why the decision could not be recorded on the change, if it could not
+	Error string
+}
+
+// Stats counts the decisions of the watchdog since it started
+type Stats struct {
+	Stuck     uint64
+	Retried   uint64
+	Cancelled uint64
+	Failed    uint64
+}
+
+// observation is the revision of a change and its device changes when first seen
+type observation struct {
+	revision string
+	since    time.Time
+}
+
+// Watchdog periodically checks the pending network changes on the leader. A change is stuck
+// once neither it nor any of its device changes has been updated for StuckAfter; the watchdog
+// then applies the action of its policy and records its decision in the status message of the
+// change and its pending device changes. Progress is tracked in memory, so a new leader only
+// finds a change stuck StuckAfter after it took over.
+type Watchdog struct {
+	policy         Policy
+	leadership     leadershipstore.Store
+	networkChanges networkchangestore.Store
+	deviceChanges  devicechangestore.Store
+	mu             sync.RWMutex
+	observed       map[networkchange.ID]observation
+	decisions      []Decision
+	stats          Stats
+	stop           chan struct{}
+}
+
+// NewWatchdog creates a watchdog
+func NewWatchdog(policy Policy, leadership leadershipstore.Store, networkChanges networkchangestore.Store,
+	deviceChanges devicechangestore.Store) (*Watchdog, error) {
+	if policy.StuckAfter <= 0 {
+		return nil, errors.NewInvalid("the watchdog needs a positive duration after which changes are stuck")
+	}
+	if _, err := ParseAction(string(policy.Action)); err != nil {
+		return nil, err
+	}
+	if policy.Interval <= 0 {
+		policy.Interval = policy.StuckAfter / 4
+	}
+	return &Watchdog{
+		policy:         policy,
+		leadership:     leadership,
+		networkChanges: networkChanges,
+		deviceChanges:  deviceChanges,
+		observed:       make(map[networkchange.ID]observation),
+	}, nil
+}
+
+// Policy returns the policy of the watchdog
+func (w *Watchdog) Policy() Policy {
+	return w.policy
+}
+
+// Start starts checking the changes periodically
+func (w *Watchdog) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stop != nil {
+		return
+	}
+	w.stop = make(chan struct{})
+	go w.run(w.stop)
+	log.Infof("Watchdog started: changes are stuck after %s, action %s", w.policy.StuckAfter, w.policy.Action)
+}
+
+// Stop stops the watchdog
+func (w *Watchdog) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+	}
+}
+
+func (w *Watchdog) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(w.policy.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if leader, err := w.leadership.IsLeader(); err != nil || !leader {
+				w.mu.Lock()
+				w.observed = make(map[networkchange.ID]observation)
+				w.mu.Unlock()
+				continue
+			}
+			if err := w.Check(time.Now()); err != nil {
+				log.Warnf("Watchdog could not check the changes: %v", err)
+			}
+		}
+	}
+}
+
+// Decisions returns the latest decisions of the watchdog, oldest first
+func (w *Watchdog) Decisions() []Decision {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	decisions := make([]Decision, len(w.decisions))
+	copy(decisions, w.decisions)
+	return decisions
+}
+
+// Stats returns the counts of the decisions of the watchdog
+func (w *Watchdog) Stats() Stats {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.stats
+}
+
+// Check checks the pending network changes once, applying the policy to those that are stuck
+func (w *Watchdog) Check(now time.Time) error {
+	ch := make(chan *networkchange.NetworkChange)
+	ctx, err := w.networkChanges.List(ch)
+	if err != nil {
+		return err
+	}
+	defer ctx.Close()
+
+	pending := make([]*networkchange.NetworkChange, 0)
+	for change := range ch {
+		if change.Status.State == changetypes.State_PENDING && !change.Deleted {
+			pending = append(pending, change)
+		}
+	}
+
+	observed := make(map[networkchange.ID]observation)
+	for _, change := range pending {
+		// A paused change makes no progress on purpose, so it is not observed until it is resumed
+		if w.isPaused(change.ID) {
+			continue
+		}
+		deviceChanges := w.getDeviceChanges(change)
+		revision := revisionOf(change, deviceChanges)
+		w.mu.RLock()
+		previous, ok := w.observed[change.ID]
+		w.mu.RUnlock()
+		if !ok || previous.revision != revision {
+			observed[change.ID] = observation{revision: revision, since: now}
+			continue
+		}
+		if stuck := now.Sub(previous.since); stuck >= w.policy.StuckAfter {
+			decision := w.escalate(change, deviceChanges, stuck, now)
+			w.record(decision)
+			// The decision updated the change, so its progress is observed anew
+			observed[change.ID] = observation{revision: revisionOf(change, deviceChanges), since: now}
+			continue
+		}
+		observed[change.ID] = previous
+	}
+
+	w.mu.Lock()
+	w.observed = observed
+	w.mu.Unlock()
+	return nil
+}
+
+// isPaused returns whether the propagation of a network change is paused
+func (w *Watchdog) isPaused(id networkchange.ID) bool {
+	pauses := devicechangectl.GetPauseStore()
+	if pauses == nil {
+		return false
+	}
+	_, err := pauses.Get(id)
+	return err == nil
+}
+
+// getDeviceChanges gets the device changes of a network change that exist
+func (w *Watchdog) getDeviceChanges(change *networkchange.NetworkChange) []*devicechange.DeviceChange {
+	deviceChanges := make([]*devicechange.DeviceChange, 0, len(change.Refs))
+	for _, ref := range change.Refs {
+		deviceChange, err := w.deviceChanges.Get(ref.DeviceChangeID)
+		if err != nil || deviceChange == nil {
+			continue
+		}
+		deviceChanges = append(deviceChanges, deviceChange)
+	}
+	return deviceChanges
+}
+
+// revisionOf identifies the state of a network change and its device changes
+func revisionOf(change *networkchange.NetworkChange, deviceChanges []*devicechange.DeviceChange) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d", change.Revision)
+	for _, deviceChange := range deviceChanges {
+		fmt.Fprintf(&b, ":%d", deviceChange.Revision)
+	}
+	return b.String()
+}
+
+// escalate applies the action of the policy to a stuck change and records it on the change
+func (w *Watchdog) escalate(change *networkchange.NetworkChange, deviceChanges []*devicechange.DeviceChange,
+	stuck time.Duration, now time.Time) Decision {
+	decision := Decision{
+		Time:          now,
+		NetworkChange: change.ID,
+		Stuck:         stuck,
+		Action:        w.policy.Action,
+	}
+	note := fmt.Sprintf("%sstuck for %s, %s at %s]", notePrefix, stuck.Round(time.Second), w.policy.Action, now.UTC().Format(time.RFC3339))
+	log.Warnf("NetworkChange %s is stuck for %s: %s", change.ID, stuck.Round(time.Second), w.policy.Action)
+
+	for _, deviceChange := range deviceChanges {
+		if deviceChange.Status.State == changetypes.State_PENDING {
+			decision.DeviceChanges = append(decision.DeviceChanges, deviceChange.ID)
+		}
+	}
+	if w.policy.Action == ActionCancel {
+		if err := networkchangectl.Cancel(w.networkChanges, w.deviceChanges, change, false, note); err != nil {
+			decision.Error = fmt.Sprintf("cancelling %s: %v", change.ID, err)
+		}
+		return decision
+	}
+
+	for _, deviceChange := range deviceChanges {
+		if deviceChange.Status.State != changetypes.State_PENDING {
+			continue
+		}
+		deviceChange.Status.Message = withNote(deviceChange.Status.Message, note)
+		if err := w.deviceChanges.Update(deviceChange); err != nil {
+			decision.Error = fmt.Sprintf("updating %s: %v", deviceChange.ID, err)
+			return decision
+		}
+	}
+
+	if w.policy.Action == ActionRetry {
+		change.Status.Reason = changetypes.Reason_NONE
+	}
+	change.Status.Message = withNote(change.Status.Message, note)
+	if err := w.networkChanges.Update(change); err != nil {
+		decision.Error = fmt.Sprintf("updating %s: %v", change.ID, err)
+	}
+	return decision
+}
+
+// withNote replaces the note of the watchdog in a status message
+func withNote(message string, note string) string {
+	if i := strings.Index(message, notePrefix); i >= 0 {
+		message = strings.TrimSpace(message[:i])
+	}
+	if message == "" {
+		return note
+	}
+	return message + " " + note
+}
+
+// record records a decision and counts it
+func (w *Watchdog) record(decision Decision) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.decisions = append(w.decisions, decision)
+	if len(w.decisions) > maxDecisions {
+		w.decisions = w.decisions[len(w.decisions)-maxDecisions:]
+	}
+	w.stats.Stuck++
+	if decision.Error != "" {
+		w.stats.Failed++
+		log.Warnf("Watchdog could not %s NetworkChange %s: %s", decision.Action, decision.NetworkChange, decision.Error)
+		return
+	}
+	switch decision.Action {
+	case ActionRetry:
+		w.stats.Retried++
+	case ActionCancel:
+		w.stats.Cancelled++
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchdog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	types "github.com/onosproject/onos-api/go/onos/config"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicechangectl "github.com/onosproject/onos-config/pkg/controller/change/device"
+	devicechangestore "github.com/onosproject/onos-config/pkg/store/change/device"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/onosproject/onos-config/pkg/store/change/pause"
+	"github.com/stretchr/testify/assert"
+)
+
+func newChange(t *testing.T, networkChanges networkchangestore.Store, deviceChanges devicechangestore.Store,
+	id networkchange.ID) (*networkchange.NetworkChange, *devicechange.DeviceChange) {
+	change := &devicechange.Change{
+		DeviceID:      "device-1",
+		DeviceVersion: "1.0.0",
+		DeviceType:    "Devicesim",
+		Values: []*devicechange.ChangeValue{{
+			Path:  "/system/config/hostname",
+			Value: devicechange.NewTypedValueString("switch1"),
+		}},
+	}
+	networkChange := &networkchange.NetworkChange{
+		ID:      id,
+		Changes: []*devicechange.Change{change},
+	}
+	assert.NoError(t, networkChanges.Create(networkChange))
+	deviceChange := &devicechange.DeviceChange{
+		Index: devicechange.Index(networkChange.Index),
+		NetworkChange: devicechange.NetworkChangeRef{
+			ID:    types.ID(networkChange.ID),
+			Index: types.Index(networkChange.Index),
+		},
+		Change: change,
+	}
+	assert.NoError(t, deviceChanges.Create(deviceChange))
+	networkChange.Refs = []*networkchange.DeviceChangeRef{{DeviceChangeID: deviceChange.ID}}
+	assert.NoError(t, networkChanges.Update(networkChange))
+	return networkChange, deviceChange
+}
+
+func TestWatchdog(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+	atomixClient, err := test.NewClient("test")
+	assert.NoError(t, err)
+
+	networkChanges, err := networkchangestore.NewAtomixStore(atomixClient)
+	assert.NoError(t, err)
+	defer networkChanges.Close()
+	deviceChanges, err := devicechangestore.NewAtomixStore(atomixClient)
+	assert.NoError(t, err)
+	defer deviceChanges.Close()
+
+	_, err = NewWatchdog(Policy{StuckAfter: time.Minute, Action: "reboot"}, nil, networkChanges, deviceChanges)
+	assert.Error(t, err)
+	_, err = NewWatchdog(Policy{Action: ActionFlag}, nil, networkChanges, deviceChanges)
+	assert.Error(t, err)
+
+	watchdog, err := NewWatchdog(Policy{StuckAfter: 10 * time.Minute, Action: ActionFlag}, nil, networkChanges, deviceChanges)
+	assert.NoError(t, err)
+	assert.Equal(t, 150*time.Second, watchdog.Policy().Interval)
+	change1, deviceChange1 := newChange(t, networkChanges, deviceChanges, "change-1")
+
+	start := time.Date(2021, 6, 2, 9, 0, 0, 0, time.UTC)
+	assert.NoError(t, watchdog.Check(start))
+	assert.NoError(t, watchdog.Check(start.Add(9*time.Minute)))
+	assert.Empty(t, watchdog.Decisions())
+
+	assert.NoError(t, watchdog.Check(start.Add(10*time.Minute)))
+	decisions := watchdog.Decisions()
+	assert.Len(t, decisions, 1)
+	assert.Equal(t, change1.ID, decisions[0].NetworkChange)
+	assert.Equal(t, []devicechange.ID{deviceChange1.ID}, decisions[0].DeviceChanges)
+	assert.Equal(t, 10*time.Minute, decisions[0].Stuck)
+	assert.Empty(t, decisions[0].Error)
+
+	change1, err = networkChanges.Get(change1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.State_PENDING, change1.Status.State)
+	assert.Equal(t, "[watchdog: stuck for 10m0s, flag at 2021-06-02T09:10:00Z]", change1.Status.Message)
+	deviceChange1, err = deviceChanges.Get(deviceChange1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, change1.Status.Message, deviceChange1.Status.Message)
+
+	// The decision is recorded as progress, so the change is only flagged again after another period
+	assert.NoError(t, watchdog.Check(start.Add(15*time.Minute)))
+	assert.Len(t, watchdog.Decisions(), 1)
+
+	// A change that was rolled back is retried
+	change1.Status.Reason = changetypes.Reason_ERROR
+	change1.Status.Message = "change rejected by device " + change1.Status.Message
+	assert.NoError(t, networkChanges.Update(change1))
+	watchdog.policy.Action = ActionRetry
+	assert.NoError(t, watchdog.Check(start.Add(20*time.Minute)))
+	assert.NoError(t, watchdog.Check(start.Add(30*time.Minute)))
+	change1, err = networkChanges.Get(change1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.Reason_NONE, change1.Status.Reason)
+	assert.Equal(t, "change rejected by device [watchdog: stuck for 10m0s, retry at 2021-06-02T09:30:00Z]", change1.Status.Message)
+
+	watchdog.policy.Action = ActionCancel
+	assert.NoError(t, watchdog.Check(start.Add(40*time.Minute)))
+	change1, err = networkChanges.Get(change1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.State_FAILED, change1.Status.State)
+	assert.Equal(t, "Cancelled: [watchdog: stuck for 10m0s, cancel at 2021-06-02T09:40:00Z]", change1.Status.Message)
+	deviceChange1, err = deviceChanges.Get(deviceChange1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, changetypes.Phase_ROLLBACK, deviceChange1.Status.Phase)
+	assert.Equal(t, changetypes.State_COMPLETE, deviceChange1.Status.State)
+
+	// A change that is no longer pending is not checked again
+	assert.NoError(t, watchdog.Check(start.Add(60*time.Minute)))
+	assert.Len(t, watchdog.Decisions(), 3)
+	assert.Equal(t, Stats{Stuck: 3, Retried: 1, Cancelled: 1}, watchdog.Stats())
+
+	// A paused change is not stuck
+	pauses := pause.NewLocalStore()
+	devicechangectl.SetPauseStore(pauses)
+	defer devicechangectl.SetPauseStore(nil)
+	change2, _ := newChange(t, networkChanges, deviceChanges, "change-2")
+	assert.NoError(t, pauses.Put(&pause.Pause{NetworkChangeID: change2.ID}))
+	watchdog.policy.Action = ActionFlag
+	assert.NoError(t, watchdog.Check(start.Add(70*time.Minute)))
+	assert.NoError(t, watchdog.Check(start.Add(90*time.Minute)))
+	assert.Len(t, watchdog.Decisions(), 3)
+
+	// Its progress is observed anew once it is resumed
+	assert.NoError(t, pauses.Delete(change2.ID))
+	assert.NoError(t, watchdog.Check(start.Add(95*time.Minute)))
+	assert.Len(t, watchdog.Decisions(), 3)
+	assert.NoError(t, watchdog.Check(start.Add(105*time.Minute)))
+	assert.Len(t, watchdog.Decisions(), 4)
+}
+
+func TestWithNote(t *testing.T) {
+	assert.Equal(t, "[watchdog: b]", withNote("", "[watchdog: b]"))
+	assert.Equal(t, "rejected [watchdog: b]", withNote("rejected", "[watchdog: b]"))
+	assert.Equal(t, "rejected [watchdog: b]", withNote("rejected [watchdog: a]", "[watchdog: b]"))
+}
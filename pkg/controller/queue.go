@@ -0,0 +1,372 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	libcontroller "github.com/onosproject/onos-lib-go/pkg/controller"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+)
+
+var log = logging.GetLogger("controller")
+
+// The retry delays of the controllers after a reconciliation error, as computed by the controller library
+const (
+	maxRetryDelay = 5 * time.Second
+	retryStep     = 10 * time.Millisecond
+)
+
+// QueueState is the state of a request in the queue of a controller
+type QueueState string
+
+const (
+	// QueuePending is a request waiting to be reconciled
+	QueuePending QueueState = "pending"
+	// QueueReconciling is a request being reconciled
+	QueueReconciling QueueState = "reconciling"
+	// QueueRetrying is a request whose reconciliation failed, to be retried once its backoff expires
+	QueueRetrying QueueState = "retrying"
+	// QueueWaiting is a request the reconciler asked to be reconciled again after a delay
+	QueueWaiting QueueState = "waiting"
+)
+
+// QueuedRequest describes a request in the queue of a controller
+type QueuedRequest struct {
+	ID string
+	// Partition is the partition the request is reconciled in, e.g. its device
+	Partition string
+	State     QueueState
+	// Attempts counts the reconciliations of the request since it last succeeded
+	Attempts int
+	// Queued is when the request was first queued since it last succeeded
+	Queued time.Time
+	// RetryAt is when a retrying or waiting request is reconciled again
+	RetryAt time.Time
+	// LastError is the error of the last reconciliation, if it failed
+	LastError string
+	// events counts the events received for the request while it was not pending
+	events int
+	// holdUntil is when the batch window of a held request expires
+	holdUntil time.Time
+}
+
+// Queue tracks the requests of a controller, so that operators can see why a change is stuck.
+// It wraps the filter and the reconciler of the controller: a request is queued when the filter
+// accepts it and leaves the queue when it is reconciled without being requeued. The queue also
+// applies the Tuning of the controller to the requests it reconciles.
+type Queue struct {
+	name        string
+	partitioner libcontroller.WorkPartitioner
+	mu          sync.RWMutex
+	requests    map[string]*QueuedRequest
+	runMu       sync.Mutex
+	runCond     *sync.Cond
+	running     int
+}
+
+var (
+	queuesMu sync.RWMutex
+	queues   = make(map[string]*Queue)
+)
+
+// NewQueue creates the queue of the named controller and registers it, replacing any queue
+// of the same name. The partitioner, if any, is the one of the controller.
+func NewQueue(name string, partitioner libcontroller.WorkPartitioner) *Queue {
+	queue := &Queue{
+		name:        name,
+		partitioner: partitioner,
+		requests:    make(map[string]*QueuedRequest),
+	}
+	queue.runCond = sync.NewCond(&queue.runMu)
+	queuesMu.Lock()
+	queues[name] = queue
+	queuesMu.Unlock()
+	return queue
+}
+
+// Queues returns the registered queues, sorted by name
+func Queues() []*Queue {
+	queuesMu.RLock()
+	defer queuesMu.RUnlock()
+	list := make([]*Queue, 0, len(queues))
+	for _, queue := range queues {
+		list = append(list, queue)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].name < list[j].name
+	})
+	return list
+}
+
+// Name returns the name of the controller of the queue
+func (q *Queue) Name() string {
+	return q.name
+}
+
+// Requests returns the requests in the queue, sorted by partition, time queued and ID
+func (q *Queue) Requests() []QueuedRequest {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	requests := make([]QueuedRequest, 0, len(q.requests))
+	for _, request := range q.requests {
+		requests = append(requests, *request)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		if requests[i].Partition != requests[j].Partition {
+			return requests[i].Partition < requests[j].Partition
+		}
+		if !requests[i].Queued.Equal(requests[j].Queued) {
+			return requests[i].Queued.Before(requests[j].Queued)
+		}
+		return requests[i].ID < requests[j].ID
+	})
+	return requests
+}
+
+// Filter wraps the filter of the controller, queueing the requests it accepts. A nil filter
+// accepts every request.
+func (q *Queue) Filter(filter libcontroller.Filter) libcontroller.Filter {
+	return &queueFilter{queue: q, filter: filter}
+}
+
+// Reconcile wraps the reconciler of the controller, following the requests it reconciles
+func (q *Queue) Reconcile(reconciler libcontroller.Reconciler) libcontroller.Reconciler {
+	return &queueReconciler{queue: q, reconciler: reconciler}
+}
+
+// queue queues a request, unless it is already
+func (q *Queue) queue(id libcontroller.ID, now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	request, ok := q.requests[id.String()]
+	if !ok {
+		q.requests[id.String()] = &QueuedRequest{
+			ID:        id.String(),
+			Partition: q.partition(id),
+			State:     QueuePending,
+			Queued:    now,
+		}
+		return
+	}
+	if request.State != QueuePending {
+		request.events++
+	}
+}
+
+func (q *Queue) partition(id libcontroller.ID) string {
+	if q.partitioner == nil {
+		return ""
+	}
+	key, err := q.partitioner.Partition(id)
+	if err != nil {
+		return ""
+	}
+	return string(key)
+}
+
+// hold holds a newly queued request until the batch window since it was queued expires. It
+// returns how long the request must still wait, and whether a wait is already scheduled for the
+// request, in which case the event is coalesced with it.
+func (q *Queue) hold(id libcontroller.ID, window time.Duration, now time.Time) (time.Duration, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	request, ok := q.requests[id.String()]
+	if !ok {
+		return 0, false
+	}
+	if !request.holdUntil.IsZero() {
+		if now.Before(request.holdUntil) {
+			if request.events > 0 {
+				request.events--
+			}
+			return 0, true
+		}
+		request.holdUntil = time.Time{}
+		return 0, false
+	}
+	if window <= 0 || request.Attempts > 0 {
+		return 0, false
+	}
+	until := request.Queued.Add(window)
+	if !now.Before(until) {
+		return 0, false
+	}
+	request.holdUntil = until
+	request.State = QueueWaiting
+	request.RetryAt = until
+	return until.Sub(now), false
+}
+
+// acquire waits until the parallelism of the controller allows another reconciliation
+func (q *Queue) acquire() {
+	q.runMu.Lock()
+	defer q.runMu.Unlock()
+	for {
+		parallelism := GetTuning(q.name).Parallelism
+		if parallelism <= 0 || q.running < parallelism {
+			break
+		}
+		q.runCond.Wait()
+	}
+	q.running++
+}
+
+// release ends a reconciliation started by acquire
+func (q *Queue) release() {
+	q.runMu.Lock()
+	q.running--
+	q.runMu.Unlock()
+	q.runCond.Broadcast()
+}
+
+// tuned wakes the reconciliations waiting on the previous parallelism of the controller
+func (q *Queue) tuned() {
+	q.runMu.Lock()
+	q.runMu.Unlock()
+	q.runCond.Broadcast()
+}
+
+// start marks a request as being reconciled
+func (q *Queue) start(id libcontroller.ID, now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	request, ok := q.requests[id.String()]
+	if !ok {
+		request = &QueuedRequest{
+			ID:        id.String(),
+			Partition: q.partition(id),
+			Queued:    now,
+		}
+		q.requests[id.String()] = request
+	} else if request.events > 0 {
+		request.events--
+	}
+	request.State = QueueReconciling
+	request.RetryAt = time.Time{}
+	request.Attempts++
+}
+
+// done records the outcome of the reconciliation of a request. If the reconciliation failed, it
+// returns the backoff of the request under the given tuning.
+func (q *Queue) done(id libcontroller.ID, result libcontroller.Result, err error, tuning Tuning, now time.Time) time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	request, ok := q.requests[id.String()]
+	if !ok {
+		if err != nil {
+			return tuning.retryDelay(1)
+		}
+		return 0
+	}
+	switch {
+	case err != nil:
+		delay := tuning.retryDelay(request.Attempts)
+		request.State = QueueRetrying
+		request.RetryAt = now.Add(delay)
+		request.LastError = err.Error()
+		return delay
+	case result.RequeueAfter > 0 && (result.Requeue.Value == nil || result.Requeue.String() == request.ID):
+		request.State = QueueWaiting
+		request.RetryAt = now.Add(result.RequeueAfter)
+		request.LastError = ""
+		return 0
+	case result.Requeue.Value != nil && result.Requeue.String() == request.ID:
+		request.State = QueuePending
+		request.LastError = ""
+		return 0
+	}
+
+	if request.events > 0 {
+		request.State = QueuePending
+		request.Attempts = 0
+		request.Queued = now
+		request.LastError = ""
+	} else {
+		delete(q.requests, request.ID)
+	}
+	if result.Requeue.Value == nil {
+		return 0
+	}
+	requeued, ok := q.requests[result.Requeue.String()]
+	if !ok {
+		requeued = &QueuedRequest{
+			ID:        result.Requeue.String(),
+			Partition: q.partition(result.Requeue),
+			State:     QueuePending,
+			Queued:    now,
+		}
+		q.requests[requeued.ID] = requeued
+	} else if requeued.State != QueuePending {
+		requeued.events++
+	}
+	if result.RequeueAfter > 0 && requeued.State == QueuePending {
+		requeued.State = QueueWaiting
+		requeued.RetryAt = now.Add(result.RequeueAfter)
+	}
+	return 0
+}
+
+// retryDelay returns the backoff of the controller library after the given number of attempts
+func retryDelay(attempts int) time.Duration {
+	return Tuning{}.retryDelay(attempts)
+}
+
+type queueFilter struct {
+	queue  *Queue
+	filter libcontroller.Filter
+}
+
+func (f *queueFilter) Accept(id libcontroller.ID) bool {
+	if f.filter != nil && !f.filter.Accept(id) {
+		return false
+	}
+	f.queue.queue(id, time.Now())
+	return true
+}
+
+var _ libcontroller.Filter = &queueFilter{}
+
+type queueReconciler struct {
+	queue      *Queue
+	reconciler libcontroller.Reconciler
+}
+
+func (r *queueReconciler) Reconcile(id libcontroller.ID) (libcontroller.Result, error) {
+	tuning := GetTuning(r.queue.name)
+	wait, coalesced := r.queue.hold(id, tuning.BatchWindow, time.Now())
+	if coalesced {
+		return libcontroller.Result{}, nil
+	} else if wait > 0 {
+		return libcontroller.Result{RequeueAfter: wait}, nil
+	}
+
+	r.queue.acquire()
+	r.queue.start(id, time.Now())
+	result, err := r.reconciler.Reconcile(id)
+	r.queue.release()
+	delay := r.queue.done(id, result, err, tuning, time.Now())
+
+	// The controller library cannot be tuned: a tuned backoff requeues the request itself
+	if err != nil && tuning.backoff() {
+		log.Infof("error during reconciliation of %s. Retrying after %s: %s", id.String(), delay, err)
+		return libcontroller.Result{RequeueAfter: delay}, nil
+	}
+	return result, err
+}
+
+var _ libcontroller.Reconciler = &queueReconciler{}
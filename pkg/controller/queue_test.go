@@ -0,0 +1,103 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/controller"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPartitioner struct {
+}
+
+func (p testPartitioner) Partition(id controller.ID) (controller.PartitionKey, error) {
+	return controller.PartitionKey(strings.Split(id.String(), ":")[0]), nil
+}
+
+type testReconciler struct {
+	results map[string]controller.Result
+	errors  map[string]error
+}
+
+func (r testReconciler) Reconcile(id controller.ID) (controller.Result, error) {
+	return r.results[id.String()], r.errors[id.String()]
+}
+
+type rejectFilter struct {
+}
+
+func (f rejectFilter) Accept(id controller.ID) bool {
+	return id.String() != "device-3:1"
+}
+
+func TestQueue(t *testing.T) {
+	queue := NewQueue("TestQueue", testPartitioner{})
+	filter := queue.Filter(rejectFilter{})
+	reconciler := queue.Reconcile(testReconciler{
+		results: map[string]controller.Result{
+			"device-1:2": {RequeueAfter: time.Minute},
+			"device-2:1": {Requeue: controller.NewID("device-2:2")},
+		},
+		errors: map[string]error{
+			"device-1:1": errors.New("device-1 is not reachable"),
+		},
+	})
+	assert.Contains(t, Queues(), queue)
+
+	for _, id := range []string{"device-2:1", "device-1:1", "device-1:2", "device-3:1", "device-1:1"} {
+		filter.Accept(controller.NewID(id))
+	}
+	requests := queue.Requests()
+	assert.Len(t, requests, 3)
+	assert.Equal(t, "device-1:1", requests[0].ID)
+	assert.Equal(t, "device-1", requests[0].Partition)
+	assert.Equal(t, QueuePending, requests[0].State)
+	assert.Equal(t, "device-1:2", requests[1].ID)
+	assert.Equal(t, "device-2:1", requests[2].ID)
+
+	before := time.Now()
+	_, err := reconciler.Reconcile(controller.NewID("device-1:1"))
+	assert.Error(t, err)
+	_, err = reconciler.Reconcile(controller.NewID("device-1:2"))
+	assert.NoError(t, err)
+	_, err = reconciler.Reconcile(controller.NewID("device-2:1"))
+	assert.NoError(t, err)
+
+	requests = queue.Requests()
+	assert.Len(t, requests, 3)
+	assert.Equal(t, QueueRetrying, requests[0].State)
+	assert.Equal(t, 1, requests[0].Attempts)
+	assert.Equal(t, "device-1 is not reachable", requests[0].LastError)
+	assert.True(t, requests[0].RetryAt.After(before))
+	assert.Equal(t, QueueWaiting, requests[1].State)
+	assert.True(t, requests[1].RetryAt.After(before.Add(59*time.Second)))
+	assert.Equal(t, "device-2:2", requests[2].ID)
+	assert.Equal(t, QueuePending, requests[2].State)
+
+	_, err = reconciler.Reconcile(controller.NewID("device-2:2"))
+	assert.NoError(t, err)
+	assert.Len(t, queue.Requests(), 2)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 20*time.Millisecond, retryDelay(1))
+	assert.Equal(t, 80*time.Millisecond, retryDelay(3))
+	assert.Equal(t, maxRetryDelay, retryDelay(20))
+}
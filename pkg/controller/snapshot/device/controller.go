@@ -23,6 +23,7 @@ import (
 	snaptype "github.com/onosproject/onos-api/go/onos/config/snapshot"
 	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
 	configcontroller "github.com/onosproject/onos-config/pkg/controller"
+	devicechangectl "github.com/onosproject/onos-config/pkg/controller/change/device"
 	topodevice "github.com/onosproject/onos-config/pkg/device"
 	changestore "github.com/onosproject/onos-config/pkg/store/change/device"
 	mastershipstore "github.com/onosproject/onos-config/pkg/store/mastership"
@@ -36,18 +37,19 @@ var log = logging.GetLogger("controller", "snapshot", "device")
 // NewController returns a new device snapshot controller
 func NewController(mastership mastershipstore.Store, changes changestore.Store, snapshots snapstore.Store) *controller.Controller {
 	c := controller.NewController("DeviceSnapshot")
-	c.Filter(&configcontroller.MastershipFilter{
+	queue := configcontroller.NewQueue("DeviceSnapshot", &Partitioner{})
+	c.Filter(queue.Filter(&configcontroller.MastershipFilter{
 		Store:    mastership,
 		Resolver: &Resolver{snapshots: snapshots},
-	})
+	}))
 	c.Partition(&Partitioner{})
 	c.Watch(&Watcher{
 		Store: snapshots,
 	})
-	c.Reconcile(&Reconciler{
+	c.Reconcile(queue.Reconcile(&Reconciler{
 		changes:   changes,
 		snapshots: snapshots,
-	})
+	}))
 	return c
 }
 
@@ -222,6 +224,12 @@ func (r *Reconciler) reconcileDelete(deviceSnapshot *devicesnapshot.DeviceSnapsh
 			if err := r.changes.Delete(change); err != nil {
 				return controller.Result{}, err
 			}
+			// The record of its pushes is only needed while it may be pushed
+			if pushes := devicechangectl.GetPushStore(); pushes != nil {
+				if err := pushes.Delete(change.ID); err != nil && !errors.IsNotFound(err) {
+					return controller.Result{}, err
+				}
+			}
 			count++
 		}
 	}
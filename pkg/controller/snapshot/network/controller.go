@@ -28,6 +28,7 @@ import (
 	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
 	networksnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/network"
 	configcontroller "github.com/onosproject/onos-config/pkg/controller"
+	"github.com/onosproject/onos-config/pkg/devicegroup"
 	devicechangestore "github.com/onosproject/onos-config/pkg/store/change/device"
 	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
 	leadershipstore "github.com/onosproject/onos-config/pkg/store/leadership"
@@ -46,6 +47,8 @@ func NewController(leadership leadershipstore.Store, networkChanges networkchang
 	deviceChanges devicechangestore.Store) *controller.Controller {
 
 	c := controller.NewController("NetworkSnapshot")
+	queue := configcontroller.NewQueue("NetworkSnapshot", nil)
+	c.Filter(queue.Filter(nil))
 	c.Activate(&configcontroller.LeadershipActivator{
 		Store: leadership,
 	})
@@ -55,12 +58,12 @@ func NewController(leadership leadershipstore.Store, networkChanges networkchang
 	c.Watch(&DeviceWatcher{
 		Store: deviceSnapshots,
 	})
-	c.Reconcile(&Reconciler{
+	c.Reconcile(queue.Reconcile(&Reconciler{
 		networkChanges:   networkChanges,
 		deviceChanges:    deviceChanges,
 		networkSnapshots: networkSnapshots,
 		deviceSnapshots:  deviceSnapshots,
-	})
+	}))
 	return c
 }
 
@@ -151,6 +154,26 @@ func (r *Reconciler) reconcileRunningMark(snapshot *networksnapshot.NetworkSnaps
 
 // createDeviceSnapshots marks NetworkChanges for deletion and creates device snapshots
 func (r *Reconciler) createDeviceSnapshots(snapshot *networksnapshot.NetworkSnapshot) (controller.Result, error) {
+	// A partitioned snapshot only covers the devices of its device group
+	var group *devicegroup.Group
+	partition, err := r.networkSnapshots.GetPartition(snapshot.ID)
+	if err != nil {
+		return controller.Result{}, err
+	}
+	if partition != "" {
+		group, err = devicegroup.GetRegistry().Resolve(partition)
+		if err != nil {
+			// The group may have been removed since the snapshot was requested
+			log.Warnf("Cannot resolve the partition of NetworkSnapshot %s, completing it without taking any device snapshot: %v", snapshot.ID, err)
+			snapshot.Status.Phase = snaptypes.Phase_DELETE
+			snapshot.Status.State = snaptypes.State_COMPLETE
+			if err := r.networkSnapshots.Update(snapshot); err != nil {
+				return controller.Result{}, err
+			}
+			return controller.Result{}, nil
+		}
+	}
+
 	// Iterate through network changes
 	deviceChanges := make(map[devicebase.VersionedID]networkchange.Index)
 	deviceMaxChanges := make(map[devicebase.VersionedID]networkchange.Index)
@@ -178,17 +201,40 @@ func (r *Reconciler) createDeviceSnapshots(snapshot *networksnapshot.NetworkSnap
 			break
 		}
 
+		// Record the types of the devices in the change, and whether they are in the partition
+		inPartition := true
+		for _, device := range change.Refs {
+			deviceID := device.DeviceChangeID.GetDeviceVersionedID()
+			if _, ok := deviceTypes[deviceID]; !ok {
+				deviceTypes[deviceID] =
+					r.deviceChangeType(change.ID, device.GetDeviceChangeID().GetDeviceID(), device.GetDeviceChangeID().GetDeviceVersion())
+			}
+			if group != nil && !group.Contains(deviceID.GetID(), deviceID.GetVersion(), deviceTypes[deviceID]) {
+				inPartition = false
+			}
+		}
+
+		// In a partitioned snapshot, a change that also touches devices outside the partition
+		// cannot be deleted, and neither can any later change of the devices it touches
+		if group != nil && inPartition {
+			for _, device := range change.Refs {
+				if _, ok := deviceMaxChanges[device.DeviceChangeID.GetDeviceVersionedID()]; ok {
+					inPartition = false
+				}
+			}
+		}
+
 		// If the change is still pending, ensure snapshots are not taken of devices following this change
-		if change.Status.State == changetypes.State_PENDING {
+		if change.Status.State == changetypes.State_PENDING || !inPartition {
 			// Record max device changes if necessary
 			for _, device := range change.Refs {
-				if _, ok := deviceMaxChanges[device.DeviceChangeID.GetDeviceVersionedID()]; !ok {
-					prevChangeIndex := deviceChanges[device.DeviceChangeID.GetDeviceVersionedID()]
-					deviceMaxChanges[device.DeviceChangeID.GetDeviceVersionedID()] = prevChangeIndex
+				deviceID := device.DeviceChangeID.GetDeviceVersionedID()
+				if group != nil && !group.Contains(deviceID.GetID(), deviceID.GetVersion(), deviceTypes[deviceID]) {
+					continue
 				}
-				if _, ok := deviceTypes[device.DeviceChangeID.GetDeviceVersionedID()]; !ok {
-					deviceTypes[device.DeviceChangeID.GetDeviceVersionedID()] =
-						r.deviceChangeType(change.ID, device.GetDeviceChangeID().GetDeviceID(), device.GetDeviceChangeID().GetDeviceVersion())
+				if _, ok := deviceMaxChanges[deviceID]; !ok {
+					prevChangeIndex := deviceChanges[deviceID]
+					deviceMaxChanges[deviceID] = prevChangeIndex
 				}
 			}
 		} else {
@@ -201,10 +247,6 @@ func (r *Reconciler) createDeviceSnapshots(snapshot *networksnapshot.NetworkSnap
 			// Record the change ID for each device in the change
 			for _, device := range change.Refs {
 				deviceChanges[device.DeviceChangeID.GetDeviceVersionedID()] = change.Index
-				if _, ok := deviceTypes[device.DeviceChangeID.GetDeviceVersionedID()]; !ok {
-					deviceTypes[device.DeviceChangeID.GetDeviceVersionedID()] =
-						r.deviceChangeType(change.ID, device.GetDeviceChangeID().GetDeviceID(), device.GetDeviceChangeID().GetDeviceVersion())
-				}
 			}
 		}
 	}
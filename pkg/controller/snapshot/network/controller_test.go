@@ -27,6 +27,7 @@ import (
 	"github.com/onosproject/onos-api/go/onos/config/snapshot"
 	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
 	networksnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/network"
+	"github.com/onosproject/onos-config/pkg/devicegroup"
 	devicechangestore "github.com/onosproject/onos-config/pkg/store/change/device"
 	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
 	devicesnapstore "github.com/onosproject/onos-config/pkg/store/snapshot/device"
@@ -334,6 +335,151 @@ func TestReconcileNetworkSnapshotPhaseState(t *testing.T) {
 	assert.Nil(t, networkChange4)
 }
 
+func TestReconcilePartitionedNetworkSnapshot(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1),
+		test.WithDebugLogs())
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	atomixClient, err := test.NewClient("test")
+	assert.NoError(t, err)
+
+	networkChanges, networkSnapshots, deviceSnapshots, deviceChanges := newStores(t, atomixClient)
+	defer networkChanges.Close()
+	defer networkSnapshots.Close()
+	defer deviceSnapshots.Close()
+	defer deviceChanges.Close()
+
+	reconciler := &Reconciler{
+		networkChanges:   networkChanges,
+		networkSnapshots: networkSnapshots,
+		deviceSnapshots:  deviceSnapshots,
+		deviceChanges:    deviceChanges,
+	}
+
+	// The partition holds device-1 and device-2 but not device-3
+	err = devicegroup.GetRegistry().Add(&devicegroup.Group{
+		Name:    "partition-test",
+		Devices: []string{string(device1), string(device2)},
+	})
+	assert.NoError(t, err)
+
+	// Create network and device changes in the completed state
+	networkChange1 := newNetworkChange("change-1", changetypes.Phase_CHANGE, changetypes.State_COMPLETE, device1)
+	err = networkChanges.Create(networkChange1)
+	assert.NoError(t, err)
+
+	networkChange2 := newNetworkChange("change-2", changetypes.Phase_CHANGE, changetypes.State_COMPLETE, device2, device3)
+	err = networkChanges.Create(networkChange2)
+	assert.NoError(t, err)
+
+	networkChange3 := newNetworkChange("change-3", changetypes.Phase_CHANGE, changetypes.State_COMPLETE, device2)
+	err = networkChanges.Create(networkChange3)
+	assert.NoError(t, err)
+
+	err = deviceChanges.Create(newDeviceChange(1, networkChange1.GetID(), device1, v1, devicesim))
+	assert.NoError(t, err)
+	err = deviceChanges.Create(newDeviceChange(2, networkChange2.GetID(), device2, v1, devicesim))
+	assert.NoError(t, err)
+	err = deviceChanges.Create(newDeviceChange(3, networkChange2.GetID(), device3, v1, devicesim))
+	assert.NoError(t, err)
+	err = deviceChanges.Create(newDeviceChange(4, networkChange3.GetID(), device2, v1, devicesim))
+	assert.NoError(t, err)
+
+	// Create a network snapshot request scoped to the partition
+	networkSnapshot := &networksnapshot.NetworkSnapshot{}
+	err = networkSnapshots.CreatePartitioned(networkSnapshot, "partition-test")
+	assert.NoError(t, err)
+
+	// Reconcile the network snapshot twice, to start it and then to take the device snapshots
+	_, err = reconciler.Reconcile(controller.NewID(string(networkSnapshot.ID)))
+	assert.NoError(t, err)
+	_, err = reconciler.Reconcile(controller.NewID(string(networkSnapshot.ID)))
+	assert.NoError(t, err)
+
+	// Verify only the change confined to the partition was marked for deletion; change-3
+	// follows change-2 on device-2 so it is kept too
+	networkChange1, err = networkChanges.Get(networkChange1.ID)
+	assert.NoError(t, err)
+	assert.True(t, networkChange1.Deleted)
+	networkChange2, err = networkChanges.Get(networkChange2.ID)
+	assert.NoError(t, err)
+	assert.False(t, networkChange2.Deleted)
+	networkChange3, err = networkChanges.Get(networkChange3.ID)
+	assert.NoError(t, err)
+	assert.False(t, networkChange3.Deleted)
+
+	// Verify device snapshots were only created for the devices of the partition
+	deviceSnapshot1, err := deviceSnapshots.Get(devicesnapshot.GetSnapshotID(types.ID(networkSnapshot.ID), device1, v1))
+	assert.NoError(t, err)
+	assert.Equal(t, types.Index(networkChange1.Index), deviceSnapshot1.MaxNetworkChangeIndex)
+	deviceSnapshot2, err := deviceSnapshots.Get(devicesnapshot.GetSnapshotID(types.ID(networkSnapshot.ID), device2, v1))
+	assert.NoError(t, err)
+	assert.Equal(t, types.Index(0), deviceSnapshot2.MaxNetworkChangeIndex)
+	deviceSnapshot3, err := deviceSnapshots.Get(devicesnapshot.GetSnapshotID(types.ID(networkSnapshot.ID), device3, v1))
+	assert.True(t, errors.IsNotFound(err))
+	assert.Nil(t, deviceSnapshot3)
+
+	networkSnapshot, err = networkSnapshots.Get(networkSnapshot.ID)
+	assert.NoError(t, err)
+	assert.Len(t, networkSnapshot.Refs, 2)
+}
+
+func TestReconcileUnknownPartition(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1),
+		test.WithDebugLogs())
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	atomixClient, err := test.NewClient("test")
+	assert.NoError(t, err)
+
+	networkChanges, networkSnapshots, deviceSnapshots, deviceChanges := newStores(t, atomixClient)
+	defer networkChanges.Close()
+	defer networkSnapshots.Close()
+	defer deviceSnapshots.Close()
+	defer deviceChanges.Close()
+
+	reconciler := &Reconciler{
+		networkChanges:   networkChanges,
+		networkSnapshots: networkSnapshots,
+		deviceSnapshots:  deviceSnapshots,
+		deviceChanges:    deviceChanges,
+	}
+
+	networkChange1 := newNetworkChange("change-1", changetypes.Phase_CHANGE, changetypes.State_COMPLETE, device1)
+	err = networkChanges.Create(networkChange1)
+	assert.NoError(t, err)
+	err = deviceChanges.Create(newDeviceChange(1, networkChange1.GetID(), device1, v1, devicesim))
+	assert.NoError(t, err)
+
+	// A snapshot of a group that is not (or no longer) known completes without deleting anything
+	networkSnapshot := &networksnapshot.NetworkSnapshot{}
+	err = networkSnapshots.CreatePartitioned(networkSnapshot, "no-such-group")
+	assert.NoError(t, err)
+
+	_, err = reconciler.Reconcile(controller.NewID(string(networkSnapshot.ID)))
+	assert.NoError(t, err)
+	_, err = reconciler.Reconcile(controller.NewID(string(networkSnapshot.ID)))
+	assert.NoError(t, err)
+
+	networkSnapshot, err = networkSnapshots.Get(networkSnapshot.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot.Phase_DELETE, networkSnapshot.Status.Phase)
+	assert.Equal(t, snapshot.State_COMPLETE, networkSnapshot.Status.State)
+	assert.Len(t, networkSnapshot.Refs, 0)
+
+	networkChange1, err = networkChanges.Get(networkChange1.ID)
+	assert.NoError(t, err)
+	assert.False(t, networkChange1.Deleted)
+}
+
 func newStores(t *testing.T, client atomix.Client) (networkchangestore.Store, networksnapstore.Store, devicesnapstore.Store, devicechangestore.Store) {
 	networkChanges, err := networkchangestore.NewAtomixStore(client)
 	assert.NoError(t, err)
@@ -0,0 +1,115 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Tuning holds the runtime-tunable parameters of a controller. The zero value keeps the
+// behaviour of the controller library.
+type Tuning struct {
+	// BatchWindow is how long a newly queued request is held before it is reconciled, so that
+	// the events received for it in the meantime are reconciled once
+	BatchWindow time.Duration
+	// Parallelism is how many requests of the controller may be reconciled at once across its
+	// partitions; unlimited if 0
+	Parallelism int
+	// RetryStep is the backoff after a failed reconciliation, doubled after each further failure
+	RetryStep time.Duration
+	// MaxRetryDelay caps the backoff after a failed reconciliation
+	MaxRetryDelay time.Duration
+}
+
+// Validate checks the parameters of the tuning
+func (t Tuning) Validate() error {
+	if t.BatchWindow < 0 {
+		return errors.NewInvalid("batch window must not be negative")
+	}
+	if t.Parallelism < 0 {
+		return errors.NewInvalid("parallelism must not be negative")
+	}
+	if t.RetryStep < 0 || t.MaxRetryDelay < 0 {
+		return errors.NewInvalid("retry backoff must not be negative")
+	}
+	if t.MaxRetryDelay > 0 && t.MaxRetryDelay < t.retryStep() {
+		return errors.NewInvalid("maximum retry delay %s is less than the retry step %s", t.MaxRetryDelay, t.retryStep())
+	}
+	return nil
+}
+
+// backoff returns whether the tuning replaces the backoff of the controller library
+func (t Tuning) backoff() bool {
+	return t.RetryStep > 0 || t.MaxRetryDelay > 0
+}
+
+func (t Tuning) retryStep() time.Duration {
+	if t.RetryStep > 0 {
+		return t.RetryStep
+	}
+	return retryStep
+}
+
+func (t Tuning) maxRetryDelay() time.Duration {
+	if t.MaxRetryDelay > 0 {
+		return t.MaxRetryDelay
+	}
+	return maxRetryDelay
+}
+
+// retryDelay returns the backoff after the given number of attempts: the retry step doubled
+// with each attempt, up to the maximum retry delay
+func (t Tuning) retryDelay(attempts int) time.Duration {
+	delay := float64(t.retryStep()) * math.Pow(2, float64(attempts))
+	if delay >= float64(t.maxRetryDelay()) {
+		return t.maxRetryDelay()
+	}
+	return time.Duration(delay)
+}
+
+var (
+	tuningsMu sync.RWMutex
+	tunings   = make(map[string]Tuning)
+)
+
+// SetTuning tunes the named controller. It applies to the requests reconciled from then on;
+// the zero Tuning restores the defaults of the controller.
+func SetTuning(name string, tuning Tuning) {
+	tuningsMu.Lock()
+	if tuning == (Tuning{}) {
+		delete(tunings, name)
+	} else {
+		tunings[name] = tuning
+	}
+	tuningsMu.Unlock()
+
+	queuesMu.RLock()
+	queue, ok := queues[name]
+	queuesMu.RUnlock()
+	if ok {
+		queue.tuned()
+	}
+}
+
+// GetTuning returns the tuning of the named controller
+func GetTuning(name string) Tuning {
+	tuningsMu.RLock()
+	defer tuningsMu.RUnlock()
+	return tunings[name]
+}
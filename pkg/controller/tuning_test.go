@@ -0,0 +1,126 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/controller"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingReconciler struct {
+	reconciles int
+}
+
+func (r *countingReconciler) Reconcile(id controller.ID) (controller.Result, error) {
+	r.reconciles++
+	return controller.Result{}, nil
+}
+
+func TestTuningValidate(t *testing.T) {
+	assert.NoError(t, Tuning{}.Validate())
+	assert.NoError(t, Tuning{BatchWindow: time.Second, Parallelism: 4, RetryStep: time.Second}.Validate())
+	assert.Error(t, Tuning{BatchWindow: -time.Second}.Validate())
+	assert.Error(t, Tuning{Parallelism: -1}.Validate())
+	assert.Error(t, Tuning{RetryStep: -time.Second}.Validate())
+	assert.Error(t, Tuning{MaxRetryDelay: time.Millisecond}.Validate())
+	assert.Error(t, Tuning{RetryStep: time.Second, MaxRetryDelay: 500 * time.Millisecond}.Validate())
+}
+
+func TestTuningRetryDelay(t *testing.T) {
+	tuning := Tuning{RetryStep: 100 * time.Millisecond, MaxRetryDelay: time.Second}
+	assert.Equal(t, 200*time.Millisecond, tuning.retryDelay(1))
+	assert.Equal(t, 800*time.Millisecond, tuning.retryDelay(3))
+	assert.Equal(t, time.Second, tuning.retryDelay(20))
+	assert.Equal(t, retryDelay(3), Tuning{BatchWindow: time.Second}.retryDelay(3))
+}
+
+func TestTuningBatchWindow(t *testing.T) {
+	queue := NewQueue("TestTuningBatchWindow", nil)
+	SetTuning("TestTuningBatchWindow", Tuning{BatchWindow: time.Minute})
+	defer SetTuning("TestTuningBatchWindow", Tuning{})
+	counter := &countingReconciler{}
+	reconciler := queue.Reconcile(counter)
+
+	filter := queue.Filter(nil)
+	filter.Accept(controller.NewID("change-1"))
+	result, err := reconciler.Reconcile(controller.NewID("change-1"))
+	assert.NoError(t, err)
+	assert.True(t, result.RequeueAfter > 59*time.Second)
+	assert.Equal(t, QueueWaiting, queue.Requests()[0].State)
+
+	// An event received during the batch window is coalesced with the held request
+	filter.Accept(controller.NewID("change-1"))
+	result, err = reconciler.Reconcile(controller.NewID("change-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, controller.Result{}, result)
+	assert.Equal(t, 0, counter.reconciles)
+	assert.Len(t, queue.Requests(), 1)
+
+	// Once the window expires, the request is reconciled once
+	queue.mu.Lock()
+	queue.requests["change-1"].holdUntil = time.Now()
+	queue.mu.Unlock()
+	_, err = reconciler.Reconcile(controller.NewID("change-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, counter.reconciles)
+	assert.Len(t, queue.Requests(), 0)
+}
+
+func TestTuningRetryBackoff(t *testing.T) {
+	queue := NewQueue("TestTuningRetryBackoff", nil)
+	SetTuning("TestTuningRetryBackoff", Tuning{RetryStep: time.Second, MaxRetryDelay: time.Minute})
+	defer SetTuning("TestTuningRetryBackoff", Tuning{})
+	reconciler := queue.Reconcile(testReconciler{
+		errors: map[string]error{
+			"change-1": errors.New("device-1 is not reachable"),
+		},
+	})
+
+	queue.Filter(nil).Accept(controller.NewID("change-1"))
+	result, err := reconciler.Reconcile(controller.NewID("change-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Second, result.RequeueAfter)
+	requests := queue.Requests()
+	assert.Equal(t, QueueRetrying, requests[0].State)
+	assert.Equal(t, "device-1 is not reachable", requests[0].LastError)
+}
+
+func TestTuningParallelism(t *testing.T) {
+	queue := NewQueue("TestTuningParallelism", nil)
+	SetTuning("TestTuningParallelism", Tuning{Parallelism: 1})
+	defer SetTuning("TestTuningParallelism", Tuning{})
+
+	queue.acquire()
+	var acquired int32
+	go func() {
+		queue.acquire()
+		atomic.StoreInt32(&acquired, 1)
+		queue.release()
+	}()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&acquired))
+
+	// Raising the parallelism lets the waiting reconciliation start
+	SetTuning("TestTuningParallelism", Tuning{Parallelism: 2})
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&acquired) == 1
+	}, time.Second, 10*time.Millisecond)
+	queue.release()
+}
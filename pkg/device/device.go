@@ -21,9 +21,50 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/onosproject/onos-api/go/onos/topo"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// LabelAllowUnknownPaths is the label of the topo entity of a device that, when "true", allows Sets
+// of paths that are not in the model of the device; they are pushed to the device unvalidated
+const LabelAllowUnknownPaths = "onos-config/allow-unknown-paths"
+
+// LabelPreferredMaster is the label of the topo entity of a device naming the onos-config node
+// preferred as the master of the device
+const LabelPreferredMaster = "onos-config/preferred-master"
+
+// LabelPreferredZone is the label of the topo entity of a device naming the zone the master of the
+// device is preferably in, when the preferred master is not given or not running
+const LabelPreferredZone = "onos-config/preferred-zone"
+
+// LabelWarmStandby is the label of the topo entity of a device that, when "true", makes the replicas
+// that are not the master of the device keep a connection to it, for a new master to resume the
+// changes to the device without connecting first
+const LabelWarmStandby = "onos-config/warm-standby"
+
+// LabelSerialNumber is the label of the topo entity of a device giving its serial number. The device
+// is connected to at the address registered for its serial number, if any, rather than at the
+// address of the topo entity, e.g. for a device given a new IP address by DHCP as it restarts.
+const LabelSerialNumber = "onos-config/serial-number"
+
+// LabelSubTargets is the label of the topo entity of a device naming, separated by commas, the gNMI
+// targets behind the address of the device, e.g. the members of a stacked or virtual chassis. Each
+// one is a device of its own, its sub-target, sharing the connection to the device.
+const LabelSubTargets = "onos-config/sub-targets"
+
+// SubTargetSeparator separates the ID of a device from the name of one of its targets in the ID of
+// the sub-target
+const SubTargetSeparator = "/"
+
+// LabelMaxSetUpdates is the label of the topo entity of a device giving the most paths a Set request
+// pushed to the device may update or delete; a larger change is pushed as a series of Sets
+const LabelMaxSetUpdates = "onos-config/max-set-updates"
+
+// LabelMaxSetBytes is the label of the topo entity of a device giving the largest size in bytes of
+// a Set request pushed to the device; a larger change is pushed as a series of Sets
+const LabelMaxSetBytes = "onos-config/max-set-bytes"
+
 // ID represents device globally unique ID
 type ID topo.ID
 
@@ -61,6 +102,32 @@ type Device struct {
 	// user-friendly tag
 	Displayname string
 
+	// whether Sets may hold paths that are not in the model of the device; from LabelAllowUnknownPaths
+	AllowUnknownPaths bool
+
+	// the node and the zone preferred for the master of the device; from LabelPreferredMaster and
+	// LabelPreferredZone
+	PreferredMaster string
+	PreferredZone   string
+
+	// whether the replicas that are not the master of the device keep a connection to it; from
+	// LabelWarmStandby
+	WarmStandby bool
+
+	// the serial number identifying the device wherever it is located; from LabelSerialNumber
+	SerialNumber string
+
+	// the names of the gNMI targets behind the address of the device, each one a sub-target; from
+	// LabelSubTargets
+	SubTargets []string
+	// the device a sub-target is behind, empty if the device is not a sub-target
+	Chassis ID
+
+	// the most paths and bytes of a Set request pushed to the device, 0 for no limit; from
+	// LabelMaxSetUpdates and LabelMaxSetBytes
+	MaxSetUpdates int
+	MaxSetBytes   int
+
 	// Mastership state
 	MastershipTerm uint64
 	MasterKey      string
@@ -177,9 +244,130 @@ func ToObject(device *Device) *topo.Object {
 		Term:   device.MastershipTerm,
 		NodeId: device.MasterKey,
 	})
+
+	if device.AllowUnknownPaths {
+		if o.Labels == nil {
+			o.Labels = make(map[string]string)
+		}
+		o.Labels[LabelAllowUnknownPaths] = "true"
+	} else {
+		delete(o.Labels, LabelAllowUnknownPaths)
+	}
+	if device.WarmStandby {
+		setLabel(o, LabelWarmStandby, "true")
+	} else {
+		delete(o.Labels, LabelWarmStandby)
+	}
+	setLabel(o, LabelSerialNumber, device.SerialNumber)
+	setLabel(o, LabelSubTargets, strings.Join(device.SubTargets, ","))
+	setLabel(o, LabelPreferredMaster, device.PreferredMaster)
+	setLabel(o, LabelPreferredZone, device.PreferredZone)
+	setLimitLabel(o, LabelMaxSetUpdates, device.MaxSetUpdates)
+	setLimitLabel(o, LabelMaxSetBytes, device.MaxSetBytes)
 	return o
 }
 
+// setLabel sets a label of a topo object, or removes it if the value is empty
+func setLabel(o *topo.Object, label string, value string) {
+	if value == "" {
+		delete(o.Labels, label)
+		return
+	}
+	if o.Labels == nil {
+		o.Labels = make(map[string]string)
+	}
+	o.Labels[label] = value
+}
+
+// setLimitLabel sets a label of a topo object to a limit, or removes it if there is no limit
+func setLimitLabel(o *topo.Object, label string, limit int) {
+	value := ""
+	if limit > 0 {
+		value = strconv.Itoa(limit)
+	}
+	setLabel(o, label, value)
+}
+
+// getLimitLabel returns the limit of a label of a topo object, 0 if it has none
+func getLimitLabel(object *topo.Object, label string) (int, error) {
+	value, ok := object.Labels[label]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return 0, errors.NewInvalid("topo entity %s has an invalid '%s' label: %s", object.ID, label, value)
+	}
+	return limit, nil
+}
+
+// getListLabel returns the values of a label of a topo object separated by commas, nil if it has none
+func getListLabel(object *topo.Object, label string) []string {
+	var values []string
+	for _, value := range strings.Split(object.Labels[label], ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// SubTargetID returns the ID of the sub-target of a device for one of its gNMI targets
+func SubTargetID(chassis ID, target string) ID {
+	return ID(string(chassis) + SubTargetSeparator + target)
+}
+
+// ParseSubTargetID returns the device and the target named by the ID of a sub-target, and false if
+// the ID names no sub-target
+func ParseSubTargetID(id ID) (ID, string, bool) {
+	i := strings.LastIndex(string(id), SubTargetSeparator)
+	if i <= 0 || i == len(id)-1 {
+		return "", "", false
+	}
+	return id[:i], string(id[i+1:]), true
+}
+
+// SubTarget returns the sub-target of the device for one of its gNMI targets, nil if the device has
+// no such target. The sub-target has the address, the credentials and the model of the device.
+func (d *Device) SubTarget(target string) *Device {
+	for _, t := range d.SubTargets {
+		if t != target {
+			continue
+		}
+		return &Device{
+			ID:                SubTargetID(d.ID, target),
+			Address:           d.Address,
+			Target:            target,
+			Version:           d.Version,
+			Timeout:           d.Timeout,
+			Credentials:       d.Credentials,
+			TLS:               d.TLS,
+			Type:              d.Type,
+			Role:              d.Role,
+			Displayname:       d.Displayname,
+			AllowUnknownPaths: d.AllowUnknownPaths,
+			PreferredMaster:   d.PreferredMaster,
+			PreferredZone:     d.PreferredZone,
+			WarmStandby:       d.WarmStandby,
+			SerialNumber:      d.SerialNumber,
+			MaxSetUpdates:     d.MaxSetUpdates,
+			MaxSetBytes:       d.MaxSetBytes,
+			Chassis:           d.ID,
+			Revision:          d.Revision,
+		}
+	}
+	return nil
+}
+
+// ExpandSubTargets returns the device followed by its sub-targets
+func ExpandSubTargets(d *Device) []*Device {
+	devices := []*Device{d}
+	for _, target := range d.SubTargets {
+		devices = append(devices, d.SubTarget(target))
+	}
+	return devices
+}
+
 // ToDevice converts topology object entity to a local device object
 func ToDevice(object *topo.Object) (*Device, error) {
 	if object.Type != topo.Object_ENTITY {
@@ -224,6 +412,15 @@ func ToDevice(object *topo.Object) (*Device, error) {
 		protocolStates = protocols.State
 	}
 
+	maxSetUpdates, err := getLimitLabel(object, LabelMaxSetUpdates)
+	if err != nil {
+		return nil, err
+	}
+	maxSetBytes, err := getLimitLabel(object, LabelMaxSetBytes)
+	if err != nil {
+		return nil, err
+	}
+
 	timeout := time.Millisecond * time.Duration(configurable.Timeout)
 
 	d := &Device{
@@ -244,9 +441,17 @@ func ToDevice(object *topo.Object) (*Device, error) {
 			CaCert:   tlsOptions.CaCert,
 			Key:      tlsOptions.Key,
 		},
-		MastershipTerm: mastership.Term,
-		MasterKey:      mastership.NodeId,
-		Object:         object,
+		MastershipTerm:    mastership.Term,
+		MasterKey:         mastership.NodeId,
+		AllowUnknownPaths: object.Labels[LabelAllowUnknownPaths] == "true",
+		PreferredMaster:   object.Labels[LabelPreferredMaster],
+		PreferredZone:     object.Labels[LabelPreferredZone],
+		WarmStandby:       object.Labels[LabelWarmStandby] == "true",
+		SerialNumber:      object.Labels[LabelSerialNumber],
+		SubTargets:        getListLabel(object, LabelSubTargets),
+		MaxSetUpdates:     maxSetUpdates,
+		MaxSetBytes:       maxSetBytes,
+		Object:            object,
 	}
 	if configurable.Type == "" {
 		d.Type = typeKindID
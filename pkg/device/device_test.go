@@ -16,6 +16,7 @@ package device
 
 import (
 	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -78,7 +79,30 @@ func Test_ObjectToDevice(t *testing.T) {
 	assert.Equal(t, 5000, int(device.Timeout.Milliseconds()))
 	assert.True(t, device.TLS.Plain)
 	assert.True(t, device.TLS.Insecure)
+	assert.False(t, device.AllowUnknownPaths)
 
+	assert.False(t, device.WarmStandby)
+
+	deviceAsObject.Labels = map[string]string{LabelAllowUnknownPaths: "true", LabelPreferredZone: "zone-a", LabelWarmStandby: "true",
+		LabelSerialNumber: "SN-1234"}
+	device, err = ToDevice(&deviceAsObject)
+	assert.NoError(t, err)
+	assert.True(t, device.AllowUnknownPaths)
+	assert.True(t, device.WarmStandby)
+	assert.Equal(t, "SN-1234", device.SerialNumber)
+	assert.Equal(t, "zone-a", device.PreferredZone)
+	assert.Empty(t, device.PreferredMaster)
+	assert.Equal(t, 0, device.MaxSetUpdates)
+
+	deviceAsObject.Labels = map[string]string{LabelMaxSetUpdates: "100", LabelMaxSetBytes: "65536"}
+	device, err = ToDevice(&deviceAsObject)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, device.MaxSetUpdates)
+	assert.Equal(t, 65536, device.MaxSetBytes)
+
+	deviceAsObject.Labels = map[string]string{LabelMaxSetBytes: "64k"}
+	_, err = ToDevice(&deviceAsObject)
+	assert.True(t, errors.IsInvalid(err), "expected invalid, got %v", err)
 }
 
 func Test_ObjectToDevice_error(t *testing.T) {
@@ -132,4 +156,62 @@ func Test_DeviceToObject(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, tlsOptions.Plain)
 	assert.True(t, tlsOptions.Insecure)
+	assert.Empty(t, deviceObject.Labels[LabelAllowUnknownPaths])
+
+	d.AllowUnknownPaths = true
+	d.PreferredMaster = "onos-config-1"
+	deviceObject = ToObject(d)
+	assert.Equal(t, "true", deviceObject.Labels[LabelAllowUnknownPaths])
+	assert.Equal(t, "onos-config-1", deviceObject.Labels[LabelPreferredMaster])
+	_, ok := deviceObject.Labels[LabelPreferredZone]
+	assert.False(t, ok)
+	_, ok = deviceObject.Labels[LabelMaxSetUpdates]
+	assert.False(t, ok)
+	_, ok = deviceObject.Labels[LabelWarmStandby]
+	assert.False(t, ok)
+
+	d.MaxSetUpdates = 100
+	d.WarmStandby = true
+	d.SerialNumber = "SN-1234"
+	deviceObject = ToObject(d)
+	assert.Equal(t, "100", deviceObject.Labels[LabelMaxSetUpdates])
+	assert.Equal(t, "true", deviceObject.Labels[LabelWarmStandby])
+	assert.Equal(t, "SN-1234", deviceObject.Labels[LabelSerialNumber])
+}
+
+func Test_SubTargets(t *testing.T) {
+	chassis := &Device{
+		ID:         "chassis-1",
+		Address:    deviceAddress,
+		Version:    deviceVersion,
+		Type:       deviceType,
+		SubTargets: []string{"member-1", "member-2"},
+	}
+	object := ToObject(chassis)
+	assert.Equal(t, "member-1,member-2", object.Labels[LabelSubTargets])
+	object.Labels[LabelSubTargets] = " member-1, ,member-2 "
+	d, err := ToDevice(object)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"member-1", "member-2"}, d.SubTargets)
+
+	devices := ExpandSubTargets(d)
+	assert.Len(t, devices, 3)
+	assert.Equal(t, ID("chassis-1"), devices[0].ID)
+	member := devices[2]
+	assert.Equal(t, ID("chassis-1/member-2"), member.ID)
+	assert.Equal(t, "member-2", member.Target)
+	assert.Equal(t, ID("chassis-1"), member.Chassis)
+	assert.Equal(t, deviceAddress, member.Address)
+	assert.Equal(t, deviceVersion, member.Version)
+	assert.Empty(t, member.SubTargets)
+	assert.Nil(t, d.SubTarget("member-3"))
+
+	chassisID, target, ok := ParseSubTargetID(member.ID)
+	assert.True(t, ok)
+	assert.Equal(t, ID("chassis-1"), chassisID)
+	assert.Equal(t, "member-2", target)
+	_, _, ok = ParseSubTargetID("chassis-1")
+	assert.False(t, ok)
+	_, _, ok = ParseSubTargetID("chassis-1/")
+	assert.False(t, ok)
 }
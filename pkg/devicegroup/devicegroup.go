@@ -0,0 +1,191 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package devicegroup keeps the named groups of devices that operations such as snapshots
+// can be scoped to, e.g. the devices of a tenant or a site.
+//
+// A group selects devices by ID pattern, with the '*' and '?' wildcards, and/or by
+// attribute. Where a group is expected, an inline selector such as
+// "type=Devicesim,version=1.0.0" may be given instead of a group name.
+package devicegroup
+
+import (
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Attributes that devices can be selected by
+const (
+	AttributeType    = "type"
+	AttributeVersion = "version"
+)
+
+// Group is a named set of devices. A device belongs to the group if its ID matches one of
+// the Devices patterns, if any are given, and it has all the attributes of the Selector.
+type Group struct {
+	Name     string            `yaml:"name" json:"name"`
+	Devices  []string          `yaml:"devices,omitempty" json:"devices,omitempty"`
+	Selector map[string]string `yaml:"selector,omitempty" json:"selector,omitempty"`
+	matchers []*regexp.Regexp
+}
+
+// Validate checks the group and compiles its device patterns
+func (g *Group) Validate() error {
+	if len(g.Devices) == 0 && len(g.Selector) == 0 {
+		return errors.NewInvalid("device group '%s' selects no devices", g.Name)
+	}
+	for attribute := range g.Selector {
+		if attribute != AttributeType && attribute != AttributeVersion {
+			return errors.NewInvalid("device group '%s': unknown attribute '%s'", g.Name, attribute)
+		}
+	}
+	g.matchers = make([]*regexp.Regexp, 0, len(g.Devices))
+	for _, pattern := range g.Devices {
+		g.matchers = append(g.matchers, utils.MatchWildcardChNameRegexp(pattern, true))
+	}
+	return nil
+}
+
+// Contains returns true if the device belongs to the group
+func (g *Group) Contains(id devicetype.ID, version devicetype.Version, deviceType devicetype.Type) bool {
+	if value, ok := g.Selector[AttributeType]; ok && value != string(deviceType) {
+		return false
+	}
+	if value, ok := g.Selector[AttributeVersion]; ok && value != string(version) {
+		return false
+	}
+	if len(g.matchers) == 0 {
+		return true
+	}
+	for _, matcher := range g.matchers {
+		if matcher.MatchString(string(id)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSelector parses an inline selector of comma separated attribute=value pairs
+func ParseSelector(selector string) (*Group, error) {
+	group := &Group{
+		Name:     selector,
+		Selector: make(map[string]string),
+	}
+	for _, term := range strings.Split(selector, ",") {
+		parts := strings.SplitN(strings.TrimSpace(term), "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.NewInvalid("invalid selector term '%s' in '%s'", term, selector)
+		}
+		group.Selector[parts[0]] = parts[1]
+	}
+	if err := group.Validate(); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// Registry is a set of device groups
+type Registry struct {
+	mu     sync.RWMutex
+	groups map[string]*Group
+}
+
+var registry = NewRegistry()
+
+// GetRegistry returns the device groups known to onos-config
+func GetRegistry() *Registry {
+	return registry
+}
+
+// NewRegistry creates a new empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		groups: make(map[string]*Group),
+	}
+}
+
+// Add adds a group, replacing any group of the same name
+func (r *Registry) Add(group *Group) error {
+	if group.Name == "" {
+		return errors.NewInvalid("device group has no name")
+	} else if strings.Contains(group.Name, "=") {
+		return errors.NewInvalid("device group name '%s' cannot contain '='", group.Name)
+	}
+	if err := group.Validate(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.groups[group.Name] = group
+	return nil
+}
+
+// Load adds the groups of a YAML file
+func (r *Registry) Load(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	groups := make([]*Group, 0)
+	if err := yaml.Unmarshal(data, &groups); err != nil {
+		return errors.NewInvalid("cannot parse device groups file %s: %v", path, err)
+	}
+	for _, group := range groups {
+		if err := r.Add(group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get gets a group by name
+func (r *Registry) Get(name string) (*Group, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	group, ok := r.groups[name]
+	if !ok {
+		return nil, errors.NewNotFound("device group '%s' not found", name)
+	}
+	return group, nil
+}
+
+// List lists the groups, sorted by name
+func (r *Registry) List() []*Group {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	groups := make([]*Group, 0, len(r.groups))
+	for _, group := range r.groups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// Resolve returns the group of the given name, or the group of an inline selector
+func (r *Registry) Resolve(nameOrSelector string) (*Group, error) {
+	if strings.Contains(nameOrSelector, "=") {
+		return ParseSelector(nameOrSelector)
+	}
+	return r.Get(nameOrSelector)
+}
@@ -0,0 +1,83 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devicegroup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GroupContains(t *testing.T) {
+	group := &Group{
+		Name:     "site-a",
+		Devices:  []string{"site-a-*", "spine?"},
+		Selector: map[string]string{AttributeType: "Devicesim"},
+	}
+	assert.NoError(t, group.Validate())
+	assert.True(t, group.Contains("site-a-leaf1", "1.0.0", "Devicesim"))
+	assert.True(t, group.Contains("spine1", "1.0.0", "Devicesim"))
+	assert.False(t, group.Contains("spine10", "1.0.0", "Devicesim"))
+	assert.False(t, group.Contains("site-b-leaf1", "1.0.0", "Devicesim"))
+	assert.False(t, group.Contains("site-a-leaf1", "1.0.0", "TestDevice"))
+
+	assert.Error(t, (&Group{Name: "empty"}).Validate())
+	assert.Error(t, (&Group{Name: "bad", Selector: map[string]string{"colour": "red"}}).Validate())
+}
+
+func Test_ParseSelector(t *testing.T) {
+	group, err := ParseSelector("type=Devicesim, version=1.0.0")
+	assert.NoError(t, err)
+	assert.True(t, group.Contains("any", "1.0.0", "Devicesim"))
+	assert.False(t, group.Contains("any", "2.0.0", "Devicesim"))
+
+	_, err = ParseSelector("type")
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func Test_Registry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devicegroup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "groups.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`
+- name: site-a
+  devices: ["site-a-*"]
+- name: sims
+  selector:
+    type: Devicesim
+`), 0600))
+
+	registry := NewRegistry()
+	assert.NoError(t, registry.Load(path))
+	groups := registry.List()
+	assert.Len(t, groups, 2)
+	assert.Equal(t, "sims", groups[0].Name)
+
+	group, err := registry.Resolve("site-a")
+	assert.NoError(t, err)
+	assert.True(t, group.Contains("site-a-leaf1", "1.0.0", "Devicesim"))
+	group, err = registry.Resolve("version=2.0.0")
+	assert.NoError(t, err)
+	assert.True(t, group.Contains("site-b-leaf1", "2.0.0", "Devicesim"))
+	_, err = registry.Resolve("site-b")
+	assert.True(t, errors.IsNotFound(err))
+
+	assert.Error(t, registry.Add(&Group{Name: "a=b", Devices: []string{"*"}}))
+}
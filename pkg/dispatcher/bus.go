@@ -0,0 +1,338 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Topic names a stream of events of one type, read with the accessor of its type on Record
+type Topic string
+
+// TopicConfig configures a topic of the bus
+type TopicConfig struct {
+	// Partitions is the number of partitions of the topic, at least one. The records of a key are
+	// all in the same partition, in the order they were published.
+	Partitions int
+	// Retention is the number of records a partition retains for replay, the oldest being dropped
+	// first; 0 retains them all
+	Retention int
+	// Compacted topics only retain the latest record of each ID, and drop the deleted IDs once
+	// every consumer group has read past their deletion: replaying them from the earliest offset
+	// gives the current state of every ID, in the order of their latest update
+	Compacted bool
+}
+
+// Record is an event published to a topic, at an offset of a partition
+type Record struct {
+	Topic     Topic
+	Partition int
+	// Offset is the position of the record in its partition, increasing from 0
+	Offset uint64
+	// Key partitions the records, e.g. by device
+	Key string
+	// ID identifies the object of the record in compacted topics
+	ID string
+	// Type is the change of the object of the record
+	Type  stream.EventType
+	value interface{}
+	// removed marks a record compacted away
+	removed bool
+}
+
+// TopicStats are the statistics of a topic
+type TopicStats struct {
+	Topic Topic
+	// Records are the records retained by the topic
+	Records int
+	// Published are the records published to the topic
+	Published uint64
+	// Groups are the consumer groups of the topic
+	Groups []GroupStats
+}
+
+// Bus is a partitioned event bus: the records published to a topic are retained by its partitions,
+// to be read by consumer groups from a cursor, so that a consumer joining late can replay the
+// records it missed instead of only seeing the records published from then on
+type Bus struct {
+	mu     sync.RWMutex
+	topics map[Topic]*topic
+}
+
+// topic is a topic of the bus with its partitions and consumer groups
+type topic struct {
+	name       Topic
+	config     TopicConfig
+	partitions []*partition
+	mu         sync.Mutex
+	groups     map[string]*group
+	consumers  uint64
+}
+
+// partition is the log of the records of a partition of a topic
+type partition struct {
+	mu sync.RWMutex
+	// records are ordered by offset; those compacted away are marked removed until the next
+	// compaction of the log
+	records []*Record
+	removed int
+	// latest are the latest records of each ID of a compacted topic
+	latest map[string]*Record
+	next   uint64
+	// appended is closed, and replaced, when a record is appended
+	appended chan struct{}
+}
+
+// NewBus creates an event bus without topics
+func NewBus() *Bus {
+	return &Bus{
+		topics: make(map[Topic]*topic),
+	}
+}
+
+// CreateTopic creates a topic of the bus
+func (b *Bus) CreateTopic(name Topic, config TopicConfig) error {
+	if config.Partitions < 1 {
+		config.Partitions = 1
+	}
+	if config.Retention < 0 {
+		return errors.NewInvalid("negative retention %d of topic %s", config.Retention, name)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.topics[name]; ok {
+		return errors.NewAlreadyExists("topic %s already exists", name)
+	}
+	t := &topic{
+		name:       name,
+		config:     config,
+		partitions: make([]*partition, config.Partitions),
+		groups:     make(map[string]*group),
+	}
+	for i := range t.partitions {
+		t.partitions[i] = &partition{
+			latest:   make(map[string]*Record),
+			appended: make(chan struct{}),
+		}
+	}
+	b.topics[name] = t
+	return nil
+}
+
+// Partitions returns the number of partitions of a topic, 0 if there is no such topic
+func (b *Bus) Partitions(name Topic) int {
+	t, err := b.topic(name)
+	if err != nil {
+		return 0
+	}
+	return len(t.partitions)
+}
+
+func (b *Bus) topic(name Topic) (*topic, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	t, ok := b.topics[name]
+	if !ok {
+		return nil, errors.NewNotFound("topic %s not found", name)
+	}
+	return t, nil
+}
+
+// publish appends a record to the partition of its key
+func (b *Bus) publish(name Topic, key string, id string, eventType stream.EventType, value interface{}) (*Record, error) {
+	t, err := b.topic(name)
+	if err != nil {
+		return nil, err
+	}
+	record := &Record{
+		Topic:     name,
+		Partition: partitionOf(key, len(t.partitions)),
+		Key:       key,
+		ID:        id,
+		Type:      eventType,
+		value:     value,
+	}
+	p := t.partitions[record.Partition]
+	p.mu.Lock()
+	record.Offset = p.next
+	p.next++
+	p.records = append(p.records, record)
+	if t.config.Compacted && id != "" {
+		if previous, ok := p.latest[id]; ok {
+			previous.removed = true
+			p.removed++
+		}
+		p.latest[id] = record
+	}
+	if t.config.Retention > 0 {
+		for len(p.records)-p.removed > t.config.Retention {
+			p.dropOldest()
+		}
+	}
+	appended := p.appended
+	p.appended = make(chan struct{})
+	p.mu.Unlock()
+	close(appended)
+
+	if t.config.Compacted && eventType == stream.Deleted {
+		t.dropTombstones(record.Partition)
+	}
+	p.compact()
+	return record, nil
+}
+
+// partitionOf returns the partition of a key
+func partitionOf(key string, partitions int) int {
+	if partitions <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(partitions))
+}
+
+// Fetch returns the first record of a partition of a topic at or after an offset; if there is
+// none yet, it returns the channel closed when one is published
+func (b *Bus) Fetch(name Topic, partition int, offset uint64) (*Record, <-chan struct{}, error) {
+	t, err := b.topic(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if partition < 0 || partition >= len(t.partitions) {
+		return nil, nil, errors.NewNotFound("partition %d of topic %s not found", partition, name)
+	}
+	record, appended := t.partitions[partition].fetch(offset)
+	return record, appended, nil
+}
+
+// Offset returns the offset of the next record of a partition of a topic
+func (b *Bus) Offset(name Topic, partition int) uint64 {
+	t, err := b.topic(name)
+	if err != nil || partition < 0 || partition >= len(t.partitions) {
+		return 0
+	}
+	p := t.partitions[partition]
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.next
+}
+
+// Stats returns the statistics of the topics, sorted by name
+func (b *Bus) Stats() []TopicStats {
+	b.mu.RLock()
+	topics := make([]*topic, 0, len(b.topics))
+	for _, t := range b.topics {
+		topics = append(topics, t)
+	}
+	b.mu.RUnlock()
+	sort.Slice(topics, func(i, j int) bool {
+		return topics[i].name < topics[j].name
+	})
+
+	stats := make([]TopicStats, 0, len(topics))
+	for _, t := range topics {
+		topicStats := TopicStats{Topic: t.name}
+		for _, p := range t.partitions {
+			p.mu.RLock()
+			topicStats.Records += len(p.records) - p.removed
+			topicStats.Published += p.next
+			p.mu.RUnlock()
+		}
+		topicStats.Groups = t.groupStats()
+		stats = append(stats, topicStats)
+	}
+	return stats
+}
+
+func (p *partition) fetch(offset uint64) (*Record, <-chan struct{}) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	i := sort.Search(len(p.records), func(i int) bool {
+		return p.records[i].Offset >= offset
+	})
+	for ; i < len(p.records); i++ {
+		if !p.records[i].removed {
+			return p.records[i], nil
+		}
+	}
+	return nil, p.appended
+}
+
+// dropOldest drops the oldest record retained by the partition
+func (p *partition) dropOldest() {
+	for len(p.records) > 0 {
+		record := p.records[0]
+		p.records[0] = nil
+		p.records = p.records[1:]
+		if record.removed {
+			p.removed--
+			continue
+		}
+		if p.latest[record.ID] == record {
+			delete(p.latest, record.ID)
+		}
+		return
+	}
+}
+
+// compact drops the records compacted away from the log of the partition, once they are most of it
+func (p *partition) compact() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.removed == 0 || p.removed < len(p.records)/2 {
+		return
+	}
+	records := make([]*Record, 0, len(p.records)-p.removed)
+	for _, record := range p.records {
+		if !record.removed {
+			records = append(records, record)
+		}
+	}
+	p.records = records
+	p.removed = 0
+}
+
+// dropTombstones drops the deletions of a partition of a compacted topic that every consumer
+// group has read past
+func (t *topic) dropTombstones(partition int) {
+	read := t.readOffset(partition)
+	p := t.partitions[partition]
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, record := range p.records {
+		if record.Offset >= read {
+			break
+		}
+		if !record.removed && record.Type == stream.Deleted {
+			record.removed = true
+			p.removed++
+			if p.latest[record.ID] == record {
+				delete(p.latest, record.ID)
+			}
+		}
+	}
+}
+
+// deleteTopic deletes a topic of the bus with its consumer groups
+func (b *Bus) deleteTopic(name Topic) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.topics, name)
+}
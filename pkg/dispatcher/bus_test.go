@@ -0,0 +1,197 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-config/pkg/events"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"gotest.tools/assert"
+)
+
+const testTopic Topic = "test"
+
+func publishTest(t *testing.T, b *Bus, key string, id string, eventType stream.EventType, value string) *Record {
+	record, err := b.publish(testTopic, key, id, eventType, value)
+	assert.NilError(t, err)
+	return record
+}
+
+func nextRecord(t *testing.T, consumer *Consumer) *Record {
+	select {
+	case record := <-consumer.Records():
+		return record
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no record delivered to %s", consumer.ID)
+		return nil
+	}
+}
+
+func Test_Topics(t *testing.T) {
+	b := NewBus()
+	assert.NilError(t, b.CreateTopic(testTopic, TopicConfig{}))
+	assert.Assert(t, errors.IsAlreadyExists(b.CreateTopic(testTopic, TopicConfig{})))
+	assert.Assert(t, errors.IsInvalid(b.CreateTopic("other", TopicConfig{Retention: -1})))
+	assert.Equal(t, b.Partitions(testTopic), 1)
+	assert.Equal(t, b.Partitions("other"), 0)
+
+	_, err := b.publish("other", "key", "", stream.Created, "value")
+	assert.Assert(t, errors.IsNotFound(err))
+	_, err = b.Subscribe("other", "", Earliest())
+	assert.Assert(t, errors.IsNotFound(err))
+	_, _, err = b.Fetch(testTopic, 1, 0)
+	assert.Assert(t, errors.IsNotFound(err))
+}
+
+func Test_Retention(t *testing.T) {
+	b := NewBus()
+	assert.NilError(t, b.CreateTopic(testTopic, TopicConfig{Retention: 3}))
+	for i := 0; i < 5; i++ {
+		record := publishTest(t, b, "key", "", stream.Created, fmt.Sprint(i))
+		assert.Equal(t, record.Offset, uint64(i))
+	}
+
+	// The records dropped from the topic are skipped
+	record, _, err := b.Fetch(testTopic, 0, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, record.Offset, uint64(2))
+	record, appended, err := b.Fetch(testTopic, 0, 5)
+	assert.NilError(t, err)
+	assert.Assert(t, record == nil)
+	publishTest(t, b, "key", "", stream.Created, "5")
+	<-appended
+	assert.Equal(t, b.Offset(testTopic, 0), uint64(6))
+
+	stats := b.Stats()
+	assert.Equal(t, len(stats), 1)
+	assert.Equal(t, stats[0].Records, 3)
+	assert.Equal(t, stats[0].Published, uint64(6))
+}
+
+func Test_Compaction(t *testing.T) {
+	b := NewBus()
+	assert.NilError(t, b.CreateTopic(testTopic, TopicConfig{Compacted: true}))
+	publishTest(t, b, "key", "change-1", stream.Created, "1")
+	publishTest(t, b, "key", "change-2", stream.Created, "2")
+	publishTest(t, b, "key", "change-1", stream.Updated, "1'")
+	publishTest(t, b, "key", "change-3", stream.Created, "3")
+
+	// Replaying the topic gives the latest record of each ID, in the order of their update
+	consumer, err := b.Subscribe(testTopic, "", Earliest())
+	assert.NilError(t, err)
+	for _, value := range []string{"2", "1'", "3"} {
+		assert.Equal(t, nextRecord(t, consumer).value, value)
+	}
+
+	// The deletions are retained until every group has read them
+	publishTest(t, b, "key", "change-2", stream.Deleted, "2")
+	record := nextRecord(t, consumer)
+	assert.Equal(t, record.Type, stream.Deleted)
+	consumer.Close()
+	publishTest(t, b, "key", "change-3", stream.Deleted, "3")
+	replay, err := b.Subscribe(testTopic, "", Earliest())
+	assert.NilError(t, err)
+	assert.Equal(t, nextRecord(t, replay).value, "1'")
+	replay.Close()
+	assert.Equal(t, b.Stats()[0].Records, 1)
+}
+
+func Test_ConsumerGroups(t *testing.T) {
+	b := NewBus()
+	assert.NilError(t, b.CreateTopic(testTopic, TopicConfig{Partitions: 2}))
+	keys := []string{"a", "b", "c", "d"}
+	partitions := make(map[int]bool)
+	for _, key := range keys {
+		partitions[publishTest(t, b, key, "", stream.Created, key).Partition] = true
+	}
+	assert.Equal(t, len(partitions), 2)
+
+	// The partitions are shared between the members of a group
+	first, err := b.Subscribe(testTopic, "gui", Earliest())
+	assert.NilError(t, err)
+	second, err := b.Subscribe(testTopic, "gui", Latest())
+	assert.NilError(t, err)
+	received := make(map[string]int)
+	for range keys {
+		select {
+		case record := <-first.Records():
+			assert.Equal(t, record.Partition, 0)
+			received[record.Key] = record.Partition
+		case record := <-second.Records():
+			assert.Equal(t, record.Partition, 1)
+			received[record.Key] = record.Partition
+		case <-time.After(5 * time.Second):
+			t.Fatal("no record delivered")
+		}
+	}
+	assert.Equal(t, len(received), len(keys))
+
+	// The partitions of a member leaving are assigned to the others
+	second.Close()
+	_, ok := <-second.Records()
+	assert.Assert(t, !ok)
+	for _, key := range keys {
+		publishTest(t, b, key, "", stream.Updated, key)
+	}
+	for range keys {
+		nextRecord(t, first)
+	}
+
+	// A named group reads on from where its members left off
+	first.Close()
+	cursor := first.Cursor()
+	publishTest(t, b, "a", "", stream.Updated, "after")
+	resumed, err := b.Subscribe(testTopic, "gui", Earliest())
+	assert.NilError(t, err)
+	assert.Equal(t, nextRecord(t, resumed).value, "after")
+
+	// A new group can start where another one was
+	ephemeral, err := b.Subscribe(testTopic, "", At(cursor.Offsets()))
+	assert.NilError(t, err)
+	assert.Equal(t, nextRecord(t, ephemeral).value, "after")
+	stats := b.Stats()[0].Groups
+	assert.Equal(t, len(stats), 2)
+	ephemeral.Close()
+	stats = b.Stats()[0].Groups
+	assert.Equal(t, len(stats), 1)
+	assert.Equal(t, stats[0].Group, "gui")
+	assert.Equal(t, stats[0].Members, 1)
+	assert.Equal(t, stats[0].Delivered, uint64(9))
+	assert.Equal(t, stats[0].Lag, uint64(0))
+	resumed.Close()
+}
+
+func Test_Latest(t *testing.T) {
+	b := NewBus()
+	assert.NilError(t, b.CreateTopic(OperationalStateTopic, TopicConfig{}))
+	_, err := b.PublishOperationalState(events.NewOperationalStateEvent("device-1", "/path",
+		devicechange.NewTypedValueString("old"), events.EventItemUpdated))
+	assert.NilError(t, err)
+	consumer, err := b.Subscribe(OperationalStateTopic, "", Latest())
+	assert.NilError(t, err)
+	defer consumer.Close()
+	_, err = b.PublishOperationalState(events.NewOperationalStateEvent("device-1", "/path",
+		devicechange.NewTypedValueString("new"), events.EventItemUpdated))
+	assert.NilError(t, err)
+	record := nextRecord(t, consumer)
+	assert.Equal(t, record.OperationalState().Value().ValueToString(), "new")
+	assert.Assert(t, record.NetworkChange() == nil)
+	assert.Assert(t, record.DeviceChange() == nil)
+}
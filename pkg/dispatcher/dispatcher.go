@@ -13,18 +13,32 @@
 // limitations under the License.
 
 /*
-Package dispatcher enables registering and unregistering of listeners for different Events.
+Package dispatcher is a typed, partitioned event bus, forwarding the operational state events of
+the devices and the changes of the change stores to their consumers.
 
-The channel system here is a two tier affair that forwards changes from the core
-configuration store to NBI listeners and to any registered device listeners
-This is so that the Configuration system does not have to be aware of the presence
-or lack of NBI, Device synchronizers etc.
+Each topic of the Bus carries the events of one type, read with the accessor of that type on a
+Record. The records of a topic are kept in partitions, by device or change, and retained so that
+consumer groups can read them from a Cursor: a consumer joining late, a GUI session or a
+controller started on a new node, replays the records it missed, in a deterministic order,
+instead of only seeing the events from then on. The change topics are compacted: replaying them
+from the Earliest cursor gives the current changes of the stores, without their whole history.
+
+On top of the bus, the Dispatcher keeps forwarding the operational state events to NBI listeners
+by priority, so that the Configuration system does not have to be aware of the presence or lack
+of NBI, Device synchronizers etc. The listeners of each priority are sent the events by a worker
+of their own, so that slow listeners do not hold up those of another priority. The events are
+sent once the listeners are looked up, outside of the lock of the Dispatcher, and a listener that
+does not read its events is disconnected or has its events dropped, so that it never holds up the
+others, nor the registering and unregistering of listeners.
 */
 package dispatcher
 
 import (
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/onosproject/onos-config/pkg/events"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
@@ -32,34 +46,351 @@ import (
 
 var log = logging.GetLogger("dispatcher")
 
-// Dispatcher manages SB and NB configuration event listeners
+// MaxPriority is the highest priority of a listener, that of the highest DSCP marking of a gNMI
+// subscription
+const MaxPriority = 63
+
+// laneQueueSize is the number of operational state events waiting to be sent to the listeners of
+// a priority
+const laneQueueSize = 1000
+
+// listenerTimeout is how long an nbi listener whose events are not queued is waited for to read an
+// event, beyond which it is disconnected
+const listenerTimeout = 10 * time.Second
+
+// Dispatcher manages SB and NB configuration event listeners over the event bus
 type Dispatcher struct {
+	bus                     *Bus
 	nbiOpStateListenersLock sync.RWMutex
-	nbiOpStateListeners     map[string]chan events.OperationalStateEvent
+	nbiOpStateListeners     map[string]*listener
+	nbiOpStatePriorities    map[string]uint32
+	nbiOpStateStats         map[string]*listenerStats
+	// nbiOpStateLanes are the lanes of the priorities of the listeners, by decreasing priority
+	nbiOpStateLanes []*lane
+	// lanes are the lanes of every priority a listener has had, whose workers keep running
+	lanes map[uint32]*lane
+	// nbiOpStateQueue is the number of events queued for each listener, 0 if they are not queued
+	nbiOpStateQueue int
+	// nbiOpStateTimeout is how long a listener whose events are not queued is waited for
+	nbiOpStateTimeout time.Duration
+	shards            []*shard
+}
+
+// listener is the channel of an nbi listener of the operational state events
+type listener struct {
+	channel chan events.OperationalStateEvent
+	// done is closed once the listener is unregistered, for the events sent to it to be given up
+	done chan struct{}
+	// mu keeps the channel from being closed while an event is sent on it
+	mu sync.RWMutex
+}
+
+// lane sends the operational state events to the listeners of a priority, in the order they are
+// dispatched. Each lane has its own worker, so that the listeners of a priority are neither held up
+// by nor compete with those of other priorities.
+type lane struct {
+	priority uint32
+	events   chan events.OperationalStateEvent
+	// listeners are the listeners of the priority, sorted by name
+	listeners []string
+	// pending are the events queued for the lane and not sent yet
+	pending sync.WaitGroup
+}
+
+// listenerStats are the number of operational state events delivered to a listener and of those
+// dropped because its queue was full
+type listenerStats struct {
+	delivered uint64
+	dropped   uint64
+}
+
+// ListenerStats are the statistics of an nbi listener of the operational state events
+type ListenerStats struct {
+	Listener string
+	Priority uint32
+	// Queued are the events queued for the listener, not read by it yet
+	Queued    int
+	Delivered uint64
+	// Dropped are the events dropped because the queue of the listener was full
+	Dropped uint64
 }
 
-// NewDispatcher creates and initializes a new event dispatcher
+// shard is the worker dispatching the operational state events of a partition of the
+// OperationalStateTopic
+type shard struct {
+	// offset is the offset of the next record of the partition to dispatch
+	offset  uint64
+	events  uint64
+	skipped uint64
+}
+
+// ShardStats are the number of operational state events dispatched by the worker of a shard, of
+// those queued for it, and of those dropped by the bus before it could dispatch them
+type ShardStats struct {
+	Shard   int
+	Events  uint64
+	Queued  int
+	Skipped uint64
+}
+
+// NewDispatcher creates and initializes a new event dispatcher with the topics of its bus
 func NewDispatcher() *Dispatcher {
-	return &Dispatcher{
-		nbiOpStateListeners: make(map[string]chan events.OperationalStateEvent),
+	d := &Dispatcher{
+		bus:                  NewBus(),
+		nbiOpStateListeners:  make(map[string]*listener),
+		nbiOpStatePriorities: make(map[string]uint32),
+		nbiOpStateStats:      make(map[string]*listenerStats),
+		lanes:                make(map[uint32]*lane),
+		nbiOpStateTimeout:    listenerTimeout,
 	}
+	_ = d.bus.CreateTopic(NetworkChangeTopic, TopicConfig{Partitions: 1, Compacted: true})
+	_ = d.bus.CreateTopic(DeviceChangeTopic, TopicConfig{Partitions: deviceChangePartitions, Compacted: true})
+	d.SetShards(1)
+	return d
+}
+
+// Bus returns the event bus of the dispatcher
+func (d *Dispatcher) Bus() *Bus {
+	return d.bus
+}
+
+// SetShards sets the number of workers dispatching the operational state events, at least one:
+// the OperationalStateTopic has a partition for each. It must be called before
+// ListenOperationalState and before consuming the topic.
+func (d *Dispatcher) SetShards(shards int) {
+	if shards < 1 {
+		shards = 1
+	}
+	d.bus.deleteTopic(OperationalStateTopic)
+	_ = d.bus.CreateTopic(OperationalStateTopic, TopicConfig{Partitions: shards, Retention: operationalStateRetention})
+	d.shards = make([]*shard, shards)
+	for i := range d.shards {
+		d.shards[i] = &shard{}
+	}
+}
+
+// Stats returns the statistics of the worker of each shard
+func (d *Dispatcher) Stats() []ShardStats {
+	stats := make([]ShardStats, len(d.shards))
+	for i, s := range d.shards {
+		offset := atomic.LoadUint64(&s.offset)
+		stats[i] = ShardStats{
+			Shard:   i,
+			Events:  atomic.LoadUint64(&s.events),
+			Queued:  int(d.bus.Offset(OperationalStateTopic, i) - offset),
+			Skipped: atomic.LoadUint64(&s.skipped),
+		}
+	}
+	return stats
 }
 
 // ListenOperationalState is a go routine function that listens out for changes made in the
-// configuration and distributes this to registered deviceListeners on the
-// Southbound and registered nbiListeners on the northbound
-// Southbound listeners are only sent the events that matter to them
-// All events.Events are sent to northbound listeners
+// configuration and publishes them to the OperationalStateTopic, from which they are
+// distributed to the registered nbiListeners on the northbound
+// All events.Events are sent to northbound listeners, through the lane of their priority
+// The events are partitioned by device, so that the events of a device are sent in order and
+// a slow device only holds up the devices of its shard. A shard falling behind by more than the
+// retention of the topic skips the events dropped from it. Once the events are no longer listened
+// to, it returns after the events dispatched are sent to the listeners.
 func (d *Dispatcher) ListenOperationalState(operationalStateChannel <-chan events.OperationalStateEvent) {
-	log.Info("Operational State Event listener initialized")
+	log.Infof("Operational State Event listener initialized with %d shards", len(d.shards))
 
+	wg := sync.WaitGroup{}
+	closed := make(chan struct{})
+	for i, s := range d.shards {
+		wg.Add(1)
+		go func(partition int, s *shard) {
+			defer wg.Done()
+			d.dispatchOperationalState(partition, s, closed)
+		}(i, s)
+	}
 	for operationalStateEvent := range operationalStateChannel {
+		if _, err := d.bus.PublishOperationalState(operationalStateEvent); err != nil {
+			log.Warnf("Failed to publish operational state event %v: %v", operationalStateEvent, err)
+		}
+	}
+	close(closed)
+	wg.Wait()
+
+	d.nbiOpStateListenersLock.RLock()
+	lanes := make([]*lane, 0, len(d.lanes))
+	for _, l := range d.lanes {
+		lanes = append(lanes, l)
+	}
+	d.nbiOpStateListenersLock.RUnlock()
+	for _, l := range lanes {
+		l.pending.Wait()
+	}
+}
+
+// dispatchOperationalState sends the records of a partition to the listeners until the events
+// are no longer listened to and the partition is drained
+func (d *Dispatcher) dispatchOperationalState(partition int, s *shard, closed <-chan struct{}) {
+	offset := atomic.LoadUint64(&s.offset)
+	for {
+		record, appended, err := d.bus.Fetch(OperationalStateTopic, partition, offset)
+		if err != nil {
+			log.Warnf("Failed to dispatch shard %d: %v", partition, err)
+			return
+		}
+		if record == nil {
+			if closed == nil {
+				return
+			}
+			select {
+			case <-appended:
+			case <-closed:
+				closed = nil
+			}
+			continue
+		}
+		d.queueOpState(record.OperationalState())
+		atomic.AddUint64(&s.skipped, record.Offset-offset)
+		offset = record.Offset + 1
+		atomic.StoreUint64(&s.offset, offset)
+		atomic.AddUint64(&s.events, 1)
+	}
+}
+
+// queueOpState queues an event for the lanes of the listeners, those of higher priority first.
+// The event is dropped for the listeners of a lane whose queue is full, so that a lane falling
+// behind does not hold up the others.
+func (d *Dispatcher) queueOpState(event events.OperationalStateEvent) {
+	d.nbiOpStateListenersLock.RLock()
+	lanes := d.nbiOpStateLanes
+	d.nbiOpStateListenersLock.RUnlock()
+	for _, l := range lanes {
+		l.pending.Add(1)
+		select {
+		case l.events <- event:
+		default:
+			l.pending.Done()
+			d.nbiOpStateListenersLock.RLock()
+			for _, subscriber := range l.listeners {
+				atomic.AddUint64(&d.nbiOpStateStats[subscriber].dropped, 1)
+			}
+			d.nbiOpStateListenersLock.RUnlock()
+		}
+	}
+}
+
+// recipient is a listener of a lane looked up to be sent an event
+type recipient struct {
+	subscriber string
+	listener   *listener
+	stats      *listenerStats
+}
+
+// sendOpStates is the worker of a lane, sending its events to its listeners. The listeners are
+// looked up under the lock and sent the event once it is released, so that a listener that does not
+// read its events does not hold up the registering and unregistering of listeners.
+func (d *Dispatcher) sendOpStates(l *lane) {
+	for event := range l.events {
 		d.nbiOpStateListenersLock.RLock()
-		for _, nbiChan := range d.nbiOpStateListeners {
-			nbiChan <- operationalStateEvent
+		recipients := make([]recipient, 0, len(l.listeners))
+		for _, subscriber := range l.listeners {
+			recipients = append(recipients, recipient{
+				subscriber: subscriber,
+				listener:   d.nbiOpStateListeners[subscriber],
+				stats:      d.nbiOpStateStats[subscriber],
+			})
 		}
+		queued := d.nbiOpStateQueue > 0
+		timeout := d.nbiOpStateTimeout
 		d.nbiOpStateListenersLock.RUnlock()
+
+		for _, r := range recipients {
+			if !r.listener.send(event, r.stats, queued, timeout) {
+				log.Warnf("NBI operational state %s read no event for %s, disconnecting it", r.subscriber, timeout)
+				d.unregister(r.subscriber, r.listener)
+			}
+		}
+		l.pending.Done()
+	}
+}
+
+// send sends an event to a listener. With queues, the event is dropped if the queue of the
+// listener is full, so that a slow listener does not hold up the others; without, the listener is
+// waited for up to timeout. It returns false if the listener was not waited for, to be disconnected.
+func (l *listener) send(event events.OperationalStateEvent, stats *listenerStats, queued bool, timeout time.Duration) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	select {
+	case <-l.done:
+		return true
+	default:
+	}
+	if queued {
+		select {
+		case l.channel <- event:
+			atomic.AddUint64(&stats.delivered, 1)
+		default:
+			atomic.AddUint64(&stats.dropped, 1)
+		}
+		return true
 	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case l.channel <- event:
+		atomic.AddUint64(&stats.delivered, 1)
+		return true
+	case <-l.done:
+		return true
+	case <-timer.C:
+		atomic.AddUint64(&stats.dropped, 1)
+		return false
+	}
+}
+
+// close closes the channel of a listener, once the events being sent to it are given up
+func (l *listener) close() {
+	close(l.done)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	close(l.channel)
+}
+
+// SetListenerQueue sets the number of operational state events queued for each nbi listener,
+// beyond which the events are dropped for the listener instead of holding up the others; with 0,
+// the default, the events are not queued and each listener is waited for, up to 10 seconds
+// beyond which it is disconnected. It must be called before the listeners are registered.
+func (d *Dispatcher) SetListenerQueue(size int) {
+	if size < 0 {
+		size = 0
+	}
+	d.nbiOpStateListenersLock.Lock()
+	defer d.nbiOpStateListenersLock.Unlock()
+	d.nbiOpStateQueue = size
+}
+
+// ListenerQueue returns the number of operational state events queued for each nbi listener, 0
+// if they are not queued
+func (d *Dispatcher) ListenerQueue() int {
+	d.nbiOpStateListenersLock.RLock()
+	defer d.nbiOpStateListenersLock.RUnlock()
+	return d.nbiOpStateQueue
+}
+
+// ListenerStats returns the statistics of the registered listeners, sorted by name
+func (d *Dispatcher) ListenerStats() []ListenerStats {
+	d.nbiOpStateListenersLock.RLock()
+	defer d.nbiOpStateListenersLock.RUnlock()
+	stats := make([]ListenerStats, 0, len(d.nbiOpStateListeners))
+	for subscriber, l := range d.nbiOpStateListeners {
+		listener := d.nbiOpStateStats[subscriber]
+		stats = append(stats, ListenerStats{
+			Listener:  subscriber,
+			Priority:  d.nbiOpStatePriorities[subscriber],
+			Queued:    len(l.channel),
+			Delivered: atomic.LoadUint64(&listener.delivered),
+			Dropped:   atomic.LoadUint64(&listener.dropped),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Listener < stats[j].Listener
+	})
+	return stats
 }
 
 // RegisterOpState is a way for nbi instances to register for
@@ -70,22 +401,87 @@ func (d *Dispatcher) RegisterOpState(subscriber string) (chan events.Operational
 	if _, ok := d.nbiOpStateListeners[subscriber]; ok {
 		return nil, fmt.Errorf("NBI operational state %s is already registered", subscriber)
 	}
-	channel := make(chan events.OperationalStateEvent)
-	d.nbiOpStateListeners[subscriber] = channel
+	channel := make(chan events.OperationalStateEvent, d.nbiOpStateQueue)
+	d.nbiOpStateListeners[subscriber] = &listener{
+		channel: channel,
+		done:    make(chan struct{}),
+	}
+	d.nbiOpStateStats[subscriber] = &listenerStats{}
+	d.orderOpStateListeners()
 	return channel, nil
 }
 
+// SetOpStatePriority sets the priority, from 0 to MaxPriority, of a registered nbi instance: the
+// instances of each priority are sent the events by a lane of their own, queued for the lanes of
+// higher priority first, so that e.g. the subscribers to alarms keep getting the events while
+// those collecting counters fall behind. Instances are registered with priority 0.
+func (d *Dispatcher) SetOpStatePriority(subscriber string, priority uint32) error {
+	if priority > MaxPriority {
+		return fmt.Errorf("priority %d of NBI operational state %s is above %d", priority, subscriber, MaxPriority)
+	}
+	d.nbiOpStateListenersLock.Lock()
+	defer d.nbiOpStateListenersLock.Unlock()
+	if _, ok := d.nbiOpStateListeners[subscriber]; !ok {
+		return fmt.Errorf("NBI operational state %s is not registered", subscriber)
+	}
+	d.nbiOpStatePriorities[subscriber] = priority
+	d.orderOpStateListeners()
+	return nil
+}
+
+// orderOpStateListeners assigns the listeners to the lanes of their priorities, starting the lanes
+// of new priorities, and sorts the lanes by decreasing priority
+func (d *Dispatcher) orderOpStateListeners() {
+	for _, l := range d.lanes {
+		l.listeners = nil
+	}
+	for subscriber := range d.nbiOpStateListeners {
+		priority := d.nbiOpStatePriorities[subscriber]
+		l, ok := d.lanes[priority]
+		if !ok {
+			l = &lane{
+				priority: priority,
+				events:   make(chan events.OperationalStateEvent, laneQueueSize),
+			}
+			d.lanes[priority] = l
+			go d.sendOpStates(l)
+		}
+		l.listeners = append(l.listeners, subscriber)
+	}
+	lanes := make([]*lane, 0, len(d.lanes))
+	for _, l := range d.lanes {
+		if len(l.listeners) > 0 {
+			sort.Strings(l.listeners)
+			lanes = append(lanes, l)
+		}
+	}
+	sort.Slice(lanes, func(i, j int) bool {
+		return lanes[i].priority > lanes[j].priority
+	})
+	d.nbiOpStateLanes = lanes
+}
+
 // UnregisterOperationalState closes the device channel and removes it from the deviceListeners
 func (d *Dispatcher) UnregisterOperationalState(subscriber string) {
-	d.nbiOpStateListenersLock.RLock()
-	defer d.nbiOpStateListenersLock.RUnlock()
-	channel, ok := d.nbiOpStateListeners[subscriber]
-	if !ok {
+	d.unregister(subscriber, nil)
+}
+
+// unregister removes a listener and closes its channel, if it is still registered as l when l
+// is given. The channel is closed once the lock is released, as events may be being sent on it.
+func (d *Dispatcher) unregister(subscriber string, l *listener) {
+	d.nbiOpStateListenersLock.Lock()
+	registered, ok := d.nbiOpStateListeners[subscriber]
+	if !ok || (l != nil && registered != l) {
+		d.nbiOpStateListenersLock.Unlock()
 		log.Infof("Subscriber %s had not been registered", subscriber)
 		return
 	}
 	delete(d.nbiOpStateListeners, subscriber)
-	close(channel)
+	delete(d.nbiOpStatePriorities, subscriber)
+	delete(d.nbiOpStateStats, subscriber)
+	d.orderOpStateListeners()
+	d.nbiOpStateListenersLock.Unlock()
+	registered.close()
 }
 
 // GetListeners returns a list of registered listeners names
@@ -21,9 +21,11 @@ import (
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 var (
@@ -113,3 +115,182 @@ func testSyncOpState(testChan <-chan events.OperationalStateEvent, callback func
 		log.Info("OperationalState change for Test ", opStateChange)
 	}
 }
+
+func Test_listen_operational_priority(t *testing.T) {
+	d := NewDispatcher()
+	bulk, err := d.RegisterOpState("bulk")
+	assert.NilError(t, err)
+	alarms, err := d.RegisterOpState("alarms")
+	assert.NilError(t, err)
+	assert.NilError(t, d.SetOpStatePriority("alarms", 46))
+	assert.ErrorContains(t, d.SetOpStatePriority("alarms", MaxPriority+1), "above")
+	assert.ErrorContains(t, d.SetOpStatePriority("unknown", 1), "not registered")
+
+	opStateCh := make(chan events.OperationalStateEvent)
+	done := make(chan struct{})
+	go func() {
+		d.ListenOperationalState(opStateCh)
+		close(done)
+	}()
+
+	// The listener of lower priority reads nothing, but the one of higher priority still gets
+	// every event, in order
+	for i := 0; i < 10; i++ {
+		opStateCh <- events.NewOperationalStateEvent("foobar", "testpath",
+			devicechange.NewTypedValueString(strconv.Itoa(i)), events.EventItemUpdated)
+	}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, strconv.Itoa(i), (<-alarms).Value().ValueToString())
+	}
+	stats := d.ListenerStats()
+	assert.Equal(t, "bulk", stats[1].Listener)
+	assert.Equal(t, uint64(0), stats[1].Delivered)
+
+	// The events of the listener of lower priority wait for it
+	close(opStateCh)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, strconv.Itoa(i), (<-bulk).Value().ValueToString())
+	}
+	<-done
+
+	d.UnregisterOperationalState("alarms")
+	d.UnregisterOperationalState("bulk")
+	assert.Equal(t, 0, len(d.GetListeners()))
+}
+
+func Test_listen_operational_shards(t *testing.T) {
+	d := NewDispatcher()
+	d.SetShards(4)
+	ch, err := d.RegisterOpState("nbiOpState")
+	assert.NilError(t, err)
+
+	opStateCh := make(chan events.OperationalStateEvent)
+	done := make(chan struct{})
+	go func() {
+		d.ListenOperationalState(opStateCh)
+		close(done)
+	}()
+	go func() {
+		for i := 0; i < 10; i++ {
+			for _, device := range []topodevice.Device{device1, device2, device3} {
+				opStateCh <- events.NewOperationalStateEvent(string(device.ID), "testpath",
+					devicechange.NewTypedValueString(strconv.Itoa(i)), events.EventItemUpdated)
+			}
+		}
+		close(opStateCh)
+	}()
+
+	// The events of each device are received in order
+	last := make(map[string]int)
+	for i := 0; i < 30; i++ {
+		event := <-ch
+		value, err := strconv.Atoi(event.Value().ValueToString())
+		assert.NilError(t, err)
+		if previous, ok := last[event.Subject()]; ok {
+			assert.Equal(t, previous+1, value, "event of %s out of order", event.Subject())
+		}
+		last[event.Subject()] = value
+	}
+	<-done
+
+	var dispatched uint64
+	for _, stats := range d.Stats() {
+		assert.Equal(t, 0, stats.Queued)
+		dispatched += stats.Events
+	}
+	assert.Equal(t, uint64(30), dispatched)
+	assert.Equal(t, 4, len(d.Stats()))
+	d.UnregisterOperationalState("nbiOpState")
+}
+
+func Test_listen_operational_queue(t *testing.T) {
+	d := NewDispatcher()
+	d.SetListenerQueue(2)
+	slow, err := d.RegisterOpState("slow")
+	assert.NilError(t, err)
+	fast, err := d.RegisterOpState("fast")
+	assert.NilError(t, err)
+	assert.NilError(t, d.SetOpStatePriority("fast", 10))
+
+	opStateCh := make(chan events.OperationalStateEvent)
+	done := make(chan struct{})
+	go func() {
+		d.ListenOperationalState(opStateCh)
+		close(done)
+	}()
+	for i := 0; i < 5; i++ {
+		opStateCh <- events.NewOperationalStateEvent("foobar", "testpath",
+			devicechange.NewTypedValueString(strconv.Itoa(i)), events.EventItemUpdated)
+		assert.Equal(t, strconv.Itoa(i), (<-fast).Value().ValueToString())
+	}
+	close(opStateCh)
+	<-done
+
+	// The listener that reads nothing is not waited for: the events beyond its queue are dropped
+	stats := d.ListenerStats()
+	assert.Equal(t, 2, len(stats))
+	assert.Equal(t, "fast", stats[0].Listener)
+	assert.Equal(t, uint32(10), stats[0].Priority)
+	assert.Equal(t, uint64(5), stats[0].Delivered)
+	assert.Equal(t, uint64(0), stats[0].Dropped)
+	assert.Equal(t, "slow", stats[1].Listener)
+	assert.Equal(t, 2, stats[1].Queued)
+	assert.Equal(t, uint64(2), stats[1].Delivered)
+	assert.Equal(t, uint64(3), stats[1].Dropped)
+	assert.Equal(t, "0", (<-slow).Value().ValueToString())
+
+	d.UnregisterOperationalState("slow")
+	d.UnregisterOperationalState("fast")
+	assert.Equal(t, 0, len(d.ListenerStats()))
+}
+
+func Test_listen_operational_stuck(t *testing.T) {
+	d := NewDispatcher()
+	d.nbiOpStateTimeout = 200 * time.Millisecond
+	stuck, err := d.RegisterOpState("stuck")
+	assert.NilError(t, err)
+	reader, err := d.RegisterOpState("reader")
+	assert.NilError(t, err)
+
+	opStateCh := make(chan events.OperationalStateEvent)
+	done := make(chan struct{})
+	go func() {
+		d.ListenOperationalState(opStateCh)
+		close(done)
+	}()
+	opStateCh <- events.NewOperationalStateEvent("foobar", "testpath",
+		devicechange.NewTypedValueString("0"), events.EventItemUpdated)
+	assert.Equal(t, "0", (<-reader).Value().ValueToString())
+
+	// The listener that reads nothing does not hold up the registering of the others
+	registered := make(chan struct{})
+	go func() {
+		_, err := d.RegisterOpState("other")
+		assert.NilError(t, err)
+		assert.NilError(t, d.SetOpStatePriority("other", 1))
+		d.UnregisterOperationalState("other")
+		close(registered)
+	}()
+	select {
+	case <-registered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("registering a listener is held up by a listener that reads nothing")
+	}
+
+	// It is disconnected once it is not waited for anymore, and the others still get the events
+	opStateCh <- events.NewOperationalStateEvent("foobar", "testpath",
+		devicechange.NewTypedValueString("1"), events.EventItemUpdated)
+	assert.Equal(t, "1", (<-reader).Value().ValueToString())
+	_, ok := <-stuck
+	assert.Assert(t, !ok, "the listener that reads nothing is not disconnected")
+	stats := d.ListenerStats()
+	assert.Equal(t, 1, len(stats))
+	assert.Equal(t, "reader", stats[0].Listener)
+	assert.Equal(t, uint64(2), stats[0].Delivered)
+
+	close(opStateCh)
+	<-done
+	d.UnregisterOperationalState("stuck")
+	d.UnregisterOperationalState("reader")
+	assert.Equal(t, 0, len(d.GetListeners()))
+}
@@ -0,0 +1,334 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// Cursor is where a consumer group starts reading a topic
+type Cursor struct {
+	latest  bool
+	offsets map[int]uint64
+}
+
+// Earliest is the cursor replaying the records retained by a topic
+func Earliest() Cursor {
+	return Cursor{}
+}
+
+// Latest is the cursor reading the records published from now on
+func Latest() Cursor {
+	return Cursor{latest: true}
+}
+
+// At is the cursor starting at the given offset of each partition, at the earliest offset of the
+// other partitions, e.g. the cursor of a consumer to resume from
+func At(offsets map[int]uint64) Cursor {
+	return Cursor{offsets: offsets}
+}
+
+// Offsets returns the offsets a cursor starts at by partition, nil for Earliest and Latest
+func (c Cursor) Offsets() map[int]uint64 {
+	return c.offsets
+}
+
+// GroupStats are the statistics of a consumer group of a topic
+type GroupStats struct {
+	Group   string
+	Members int
+	// Delivered are the records delivered to the members of the group
+	Delivered uint64
+	// Lag are the records published not yet delivered to the group
+	Lag uint64
+	// Skipped are the records dropped by the topic before the group read them, not counting
+	// those compacted away
+	Skipped uint64
+}
+
+// Consumer is a member of a consumer group of a topic. The partitions of the topic are shared
+// between the members of the group: each record is delivered to one of them, in order, and
+// the group reads on from where its members left off.
+type Consumer struct {
+	// ID identifies the consumer in its group
+	ID      string
+	group   *group
+	records chan *Record
+	once    sync.Once
+}
+
+// group is a consumer group of a topic, reading each partition from its own offset
+type group struct {
+	name      string
+	topic     *topic
+	ephemeral bool
+	mu        sync.Mutex
+	// cond is signalled when a partition worker sees a new assignment
+	cond    *sync.Cond
+	members []*Consumer
+	// owners are the members the partitions are assigned to
+	owners    []*Consumer
+	offsets   []uint64
+	delivered uint64
+	skipped   uint64
+	// generation is incremented on every assignment, and seen by the partition workers once
+	// they deliver to the new owners only
+	generation uint64
+	seen       []uint64
+	assigned   chan struct{}
+	done       chan struct{}
+	wg         sync.WaitGroup
+}
+
+// Subscribe adds a consumer to a group of a topic. A group reads the topic from the cursor it is
+// created with, and from where its members left off after that. An empty group is an ephemeral
+// group of the consumer only, removed when it is closed; named groups keep their offsets when
+// all their members leave.
+func (b *Bus) Subscribe(name Topic, groupName string, cursor Cursor) (*Consumer, error) {
+	t, err := b.topic(name)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.consumers++
+	ephemeral := groupName == ""
+	if ephemeral {
+		groupName = fmt.Sprintf("consumer-%d", t.consumers)
+	}
+	g, ok := t.groups[groupName]
+	if !ok {
+		g = t.newGroup(groupName, ephemeral, cursor)
+		t.groups[groupName] = g
+	}
+	consumer := &Consumer{
+		ID:      fmt.Sprintf("%s-%d", groupName, t.consumers),
+		group:   g,
+		records: make(chan *Record),
+	}
+	g.join(consumer)
+	return consumer, nil
+}
+
+// Records returns the channel of the records delivered to the consumer, closed when it is closed
+func (c *Consumer) Records() <-chan *Record {
+	return c.records
+}
+
+// Group returns the name of the group of the consumer
+func (c *Consumer) Group() string {
+	return c.group.name
+}
+
+// Cursor returns the cursor of the group of the consumer, from which a new group can read on
+// where this one is. Taken while the group is being delivered records, it may deliver again the
+// records being delivered; taken once the group has no members left, it is exact.
+func (c *Consumer) Cursor() Cursor {
+	c.group.mu.Lock()
+	defer c.group.mu.Unlock()
+	offsets := make(map[int]uint64, len(c.group.offsets))
+	for partition, offset := range c.group.offsets {
+		offsets[partition] = offset
+	}
+	return At(offsets)
+}
+
+// Close removes the consumer from its group, whose partitions are assigned to the other members
+func (c *Consumer) Close() {
+	c.once.Do(func() {
+		t := c.group.topic
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if c.group.leave(c) && c.group.ephemeral {
+			delete(t.groups, c.group.name)
+		}
+		close(c.records)
+	})
+}
+
+func (t *topic) newGroup(name string, ephemeral bool, cursor Cursor) *group {
+	g := &group{
+		name:      name,
+		topic:     t,
+		ephemeral: ephemeral,
+		owners:    make([]*Consumer, len(t.partitions)),
+		offsets:   make([]uint64, len(t.partitions)),
+		seen:      make([]uint64, len(t.partitions)),
+	}
+	g.cond = sync.NewCond(&g.mu)
+	for i, p := range t.partitions {
+		if cursor.latest {
+			p.mu.RLock()
+			g.offsets[i] = p.next
+			p.mu.RUnlock()
+		} else if offset, ok := cursor.offsets[i]; ok {
+			g.offsets[i] = offset
+		}
+	}
+	return g
+}
+
+// join adds a member to the group, starting its partition workers if it is the first one
+func (g *group) join(consumer *Consumer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.members = append(g.members, consumer)
+	if len(g.members) == 1 {
+		g.done = make(chan struct{})
+		g.assigned = make(chan struct{})
+		for partition := range g.owners {
+			g.wg.Add(1)
+			go g.deliver(partition, g.done)
+		}
+	}
+	g.assign()
+}
+
+// leave removes a member from the group once no partition worker delivers to it anymore, and
+// stops the workers if it was the last one; it returns whether the group is left empty
+func (g *group) leave(consumer *Consumer) bool {
+	g.mu.Lock()
+	for i, member := range g.members {
+		if member == consumer {
+			g.members = append(g.members[:i], g.members[i+1:]...)
+			break
+		}
+	}
+	if len(g.members) == 0 {
+		close(g.done)
+		g.mu.Unlock()
+		g.wg.Wait()
+		return true
+	}
+	generation := g.assign()
+	for !g.seenBy(generation) {
+		g.cond.Wait()
+	}
+	g.mu.Unlock()
+	return false
+}
+
+// assign shares the partitions between the members in the order they joined
+func (g *group) assign() uint64 {
+	for partition := range g.owners {
+		g.owners[partition] = g.members[partition%len(g.members)]
+	}
+	g.generation++
+	close(g.assigned)
+	g.assigned = make(chan struct{})
+	return g.generation
+}
+
+// seenBy returns whether every partition worker has seen an assignment
+func (g *group) seenBy(generation uint64) bool {
+	for _, seen := range g.seen {
+		if seen < generation {
+			return false
+		}
+	}
+	return true
+}
+
+// deliver delivers the records of a partition to its owner until the group is done
+func (g *group) deliver(partition int, done <-chan struct{}) {
+	defer g.wg.Done()
+	p := g.topic.partitions[partition]
+	for {
+		g.mu.Lock()
+		owner, offset, assigned := g.owners[partition], g.offsets[partition], g.assigned
+		if g.seen[partition] != g.generation {
+			g.seen[partition] = g.generation
+			g.cond.Broadcast()
+		}
+		g.mu.Unlock()
+
+		record, appended := p.fetch(offset)
+		if record == nil {
+			select {
+			case <-appended:
+			case <-assigned:
+			case <-done:
+				return
+			}
+			continue
+		}
+		select {
+		case owner.records <- record:
+			g.mu.Lock()
+			g.offsets[partition] = record.Offset + 1
+			g.delivered++
+			if !g.topic.config.Compacted {
+				g.skipped += record.Offset - offset
+			}
+			g.mu.Unlock()
+		case <-assigned:
+		case <-done:
+			return
+		}
+	}
+}
+
+// readOffset returns the lowest offset of a partition the consumer groups of the topic are at
+func (t *topic) readOffset(partition int) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var offset uint64 = math.MaxUint64
+	for _, g := range t.groups {
+		g.mu.Lock()
+		if g.offsets[partition] < offset {
+			offset = g.offsets[partition]
+		}
+		g.mu.Unlock()
+	}
+	return offset
+}
+
+// groupStats returns the statistics of the consumer groups of the topic, sorted by name
+func (t *topic) groupStats() []GroupStats {
+	t.mu.Lock()
+	groups := make([]*group, 0, len(t.groups))
+	for _, g := range t.groups {
+		groups = append(groups, g)
+	}
+	t.mu.Unlock()
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].name < groups[j].name
+	})
+
+	stats := make([]GroupStats, 0, len(groups))
+	for _, g := range groups {
+		g.mu.Lock()
+		groupStats := GroupStats{
+			Group:     g.name,
+			Members:   len(g.members),
+			Delivered: g.delivered,
+			Skipped:   g.skipped,
+		}
+		offsets := append([]uint64(nil), g.offsets...)
+		g.mu.Unlock()
+		for partition, p := range t.partitions {
+			p.mu.RLock()
+			if p.next > offsets[partition] {
+				groupStats.Lag += p.next - offsets[partition]
+			}
+			p.mu.RUnlock()
+		}
+		stats = append(stats, groupStats)
+	}
+	return stats
+}
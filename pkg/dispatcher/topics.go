@@ -0,0 +1,131 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/events"
+	devicechanges "github.com/onosproject/onos-config/pkg/store/change/device"
+	networkchanges "github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+)
+
+const (
+	// OperationalStateTopic is the topic of the operational state events of the devices, keyed by
+	// device, see Record.OperationalState
+	OperationalStateTopic Topic = "operational-state"
+	// NetworkChangeTopic is the compacted topic of the network changes, in a single partition so
+	// that they are read in the order of the store, see Record.NetworkChange
+	NetworkChangeTopic Topic = "network-changes"
+	// DeviceChangeTopic is the compacted topic of the device changes, keyed by device, see
+	// Record.DeviceChange
+	DeviceChangeTopic Topic = "device-changes"
+)
+
+// operationalStateRetention is the number of operational state events a partition retains
+const operationalStateRetention = 1024
+
+// deviceChangePartitions is the number of partitions of the device changes
+const deviceChangePartitions = 4
+
+// OperationalState returns the event of a record of the OperationalStateTopic, nil for the
+// records of other topics
+func (r *Record) OperationalState() events.OperationalStateEvent {
+	event, _ := r.value.(events.OperationalStateEvent)
+	return event
+}
+
+// NetworkChange returns the change of a record of the NetworkChangeTopic, nil for the records
+// of other topics
+func (r *Record) NetworkChange() *networkchange.NetworkChange {
+	change, _ := r.value.(*networkchange.NetworkChange)
+	return change
+}
+
+// DeviceChange returns the change of a record of the DeviceChangeTopic, nil for the records of
+// other topics
+func (r *Record) DeviceChange() *devicechange.DeviceChange {
+	change, _ := r.value.(*devicechange.DeviceChange)
+	return change
+}
+
+// PublishOperationalState publishes an operational state event of a device
+func (b *Bus) PublishOperationalState(event events.OperationalStateEvent) (*Record, error) {
+	return b.publish(OperationalStateTopic, event.Subject(), "", stream.Updated, event)
+}
+
+// PublishNetworkChange publishes an event of the network change store
+func (b *Bus) PublishNetworkChange(eventType stream.EventType, change *networkchange.NetworkChange) (*Record, error) {
+	return b.publish(NetworkChangeTopic, string(change.ID), string(change.ID), eventType, change)
+}
+
+// PublishDeviceChange publishes an event of the device change store
+func (b *Bus) PublishDeviceChange(eventType stream.EventType, change *devicechange.DeviceChange) (*Record, error) {
+	return b.publish(DeviceChangeTopic, string(change.Change.DeviceID), string(change.ID), eventType, change)
+}
+
+// WatchChanges publishes the changes of the network and device change stores, past ones first,
+// to the NetworkChangeTopic and the DeviceChangeTopic, until the network changes are no longer
+// watched. The device changes of a device are watched from its first network change on.
+func (b *Bus) WatchChanges(networkChanges networkchanges.Store, deviceChanges devicechanges.Store) error {
+	ch := make(chan stream.Event)
+	if _, err := networkChanges.Watch(ch, networkchanges.WithReplay()); err != nil {
+		return err
+	}
+	go func() {
+		watched := make(map[device.VersionedID]bool)
+		for event := range ch {
+			change, ok := event.Object.(*networkchange.NetworkChange)
+			if !ok {
+				continue
+			}
+			for _, deviceChange := range change.Changes {
+				deviceID := deviceChange.GetVersionedDeviceID()
+				if watched[deviceID] {
+					continue
+				}
+				if err := b.watchDeviceChanges(deviceChanges, deviceID); err != nil {
+					log.Warnf("Failed to watch the device changes of %s: %v", deviceID, err)
+					continue
+				}
+				watched[deviceID] = true
+			}
+			if _, err := b.PublishNetworkChange(event.Type, change); err != nil {
+				log.Warnf("Failed to publish network change %s: %v", change.ID, err)
+			}
+		}
+	}()
+	return nil
+}
+
+// watchDeviceChanges publishes the changes of a device, past ones first
+func (b *Bus) watchDeviceChanges(deviceChanges devicechanges.Store, deviceID device.VersionedID) error {
+	ch := make(chan stream.Event)
+	if _, err := deviceChanges.Watch(deviceID, ch, devicechanges.WithReplay()); err != nil {
+		return err
+	}
+	go func() {
+		for event := range ch {
+			if change, ok := event.Object.(*devicechange.DeviceChange); ok {
+				if _, err := b.PublishDeviceChange(event.Type, change); err != nil {
+					log.Warnf("Failed to publish device change %s: %v", change.ID, err)
+				}
+			}
+		}
+	}()
+	return nil
+}
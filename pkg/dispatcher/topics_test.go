@@ -0,0 +1,87 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	types "github.com/onosproject/onos-api/go/onos/config"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicechanges "github.com/onosproject/onos-config/pkg/store/change/device"
+	networkchanges "github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	"gotest.tools/assert"
+)
+
+func newTestChange(id string) *networkchange.NetworkChange {
+	return &networkchange.NetworkChange{
+		ID: networkchange.ID(id),
+		Changes: []*devicechange.Change{
+			{
+				DeviceID:      "device-1",
+				DeviceType:    "Stratum",
+				DeviceVersion: "1.0.0",
+				Values: []*devicechange.ChangeValue{
+					{Path: "/foo", Value: devicechange.NewTypedValueString(id)},
+				},
+			},
+		},
+	}
+}
+
+func Test_WatchChanges(t *testing.T) {
+	atomixTest := test.NewTest(rsm.NewProtocol(), test.WithReplicas(1), test.WithPartitions(1))
+	assert.NilError(t, atomixTest.Start())
+	defer atomixTest.Stop()
+	client, err := atomixTest.NewClient("node-1")
+	assert.NilError(t, err)
+	networkChanges, err := networkchanges.NewAtomixStore(client)
+	assert.NilError(t, err)
+	deviceChanges, err := devicechanges.NewAtomixStore(client)
+	assert.NilError(t, err)
+
+	// The changes made before the bus watches the stores are replayed
+	change1 := newTestChange("change-1")
+	assert.NilError(t, networkChanges.Create(change1))
+	assert.NilError(t, deviceChanges.Create(&devicechange.DeviceChange{
+		Index:         1,
+		NetworkChange: devicechange.NetworkChangeRef{ID: types.ID(change1.ID), Index: types.Index(change1.Index)},
+		Change:        change1.Changes[0],
+	}))
+
+	d := NewDispatcher()
+	assert.NilError(t, d.Bus().WatchChanges(networkChanges, deviceChanges))
+	networkConsumer, err := d.Bus().Subscribe(NetworkChangeTopic, "", Earliest())
+	assert.NilError(t, err)
+	defer networkConsumer.Close()
+	deviceConsumer, err := d.Bus().Subscribe(DeviceChangeTopic, "", Earliest())
+	assert.NilError(t, err)
+	defer deviceConsumer.Close()
+
+	record := nextRecord(t, networkConsumer)
+	assert.Equal(t, record.NetworkChange().ID, networkchange.ID("change-1"))
+	record = nextRecord(t, deviceConsumer)
+	assert.Equal(t, record.DeviceChange().ID, devicechange.ID("change-1:device-1:1.0.0"))
+	assert.Equal(t, record.Key, "device-1")
+
+	// Then the changes made from then on
+	assert.NilError(t, networkChanges.Create(newTestChange("change-2")))
+	record = nextRecord(t, networkConsumer)
+	assert.Equal(t, record.NetworkChange().ID, networkchange.ID("change-2"))
+	assert.Equal(t, record.Type, stream.Created)
+}
@@ -39,6 +39,7 @@ const ( // For event types
 	EventTypeErrorTranslation
 	EventTypeErrorGetWithRoPaths
 	EventTypeTopoUpdate
+	EventTypeErrorModelMismatch
 )
 
 // EventAction is an enumerated type
@@ -57,8 +58,8 @@ func (et EventType) String() string {
 		"EventTypeErrorParseConfig", "EventTypeErrorDeviceConnect",
 		"EventTypeErrorDeviceCapabilities", "EventTypeErrorDeviceConnectInitialConfigSync",
 		"EventTypeErrorDeviceDisconnect",
-		"EventTypeErrorSubscribe, EventTypeErrorMissingModelPlugin, EventTypeErrorTranslation",
-		"EventTypeErrorGetWithRoPaths", "EventTypeTopoUpdate"}[et]
+		"EventTypeErrorSubscribe", "EventTypeErrorMissingModelPlugin", "EventTypeErrorTranslation",
+		"EventTypeErrorGetWithRoPaths", "EventTypeTopoUpdate", "EventTypeErrorModelMismatch"}[et]
 }
 
 // Event is a general purpose base type of event
@@ -70,3 +70,8 @@ func Test_errorEventBoChangeIDConstruction(t *testing.T) {
 	assert.Equal(t, event.Response(), "")
 	assert.Error(t, event.Error(), testResponse, "expected an error")
 }
+
+func Test_eventTypeString(t *testing.T) {
+	assert.Equal(t, EventTypeErrorTranslation.String(), "EventTypeErrorTranslation")
+	assert.Equal(t, EventTypeErrorModelMismatch.String(), "EventTypeErrorModelMismatch")
+}
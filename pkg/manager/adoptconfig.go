@@ -0,0 +1,162 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	types "github.com/onosproject/onos-api/go/onos/config"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// AdoptConfig reads the whole running configuration of a device and records it as its intended
+// configuration, so that later changes and rollbacks start from what the device actually has.
+// The configuration is validated against the model of the device and stored as a network change
+// and device change that are COMPLETE from the start: they are not sent back to the device.
+// Adoption is refused if the device already has configuration.
+func (m *Manager) AdoptConfig(deviceID devicetype.ID, version devicetype.Version,
+	deviceType devicetype.Type) (*networkchange.NetworkChange, error) {
+
+	configValues, err := m.DeviceStateStore.Get(devicetype.NewVersionedID(deviceID, version), 0)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	} else if len(configValues) > 0 {
+		return nil, errors.NewAlreadyExists("%s:%s already has %d configuration values", deviceID, version, len(configValues))
+	}
+
+	modelName := utils.ToModelName(deviceType, version)
+	plugin, err := m.ModelRegistry.GetPlugin(modelName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NewNotFound("no model %s available as a plugin to adopt the configuration with", modelName)
+		}
+		return nil, err
+	}
+
+	deviceValues, err := m.getDeviceValues(deviceID, version, plugin, "/", gnmi.GetRequest_CONFIG)
+	if err != nil {
+		return nil, errors.NewUnavailable("reading the configuration of %s failed: %v", deviceID, err)
+	}
+	if len(deviceValues) == 0 {
+		return nil, errors.NewNotFound("%s has no configuration to adopt", deviceID)
+	}
+	updates := make(devicechange.TypedValueMap)
+	for _, cv := range deviceValues {
+		updates[cv.Path] = cv.Value
+	}
+	if err := m.ValidateNetworkConfig(deviceID, version, deviceType, updates, nil, 0); err != nil {
+		return nil, err
+	}
+	return m.storeAdoptedConfig(deviceID, version, deviceType, updates)
+}
+
+// storeAdoptedConfig stores the configuration values read from a device as a COMPLETE network change
+func (m *Manager) storeAdoptedConfig(deviceID devicetype.ID, version devicetype.Version,
+	deviceType devicetype.Type, updates devicechange.TypedValueMap) (*networkchange.NetworkChange, error) {
+
+	deviceChanges, err := m.computeNetworkConfig(
+		map[devicetype.ID]devicechange.TypedValueMap{deviceID: updates},
+		map[devicetype.ID][]string{},
+		map[devicetype.ID]cache.Info{deviceID: {DeviceID: deviceID, Type: deviceType, Version: version}},
+		"")
+	if err != nil {
+		return nil, err
+	}
+	adoptedChange, err := networkchange.NewNetworkChange(string(networkchangestore.NewAdoptedChangeID(deviceID, version)), deviceChanges)
+	if err != nil {
+		return nil, err
+	}
+	// The values came from the device, so there is nothing to apply
+	adoptedChange.Status.State = changetypes.State_COMPLETE
+	if err := m.NetworkChangesStore.Create(adoptedChange); err != nil {
+		if errors.IsConflict(err) || errors.IsAlreadyExists(err) {
+			return nil, errors.NewAlreadyExists("%s:%s is already adopted", deviceID, version)
+		}
+		return nil, err
+	}
+
+	// Another change to the device may have been made since its configuration was checked. The
+	// device state store ignores an adopted change that comes after other configuration, so the
+	// adoption is withdrawn in that case.
+	first, err := m.isFirstChange(adoptedChange, devicetype.NewVersionedID(deviceID, version))
+	if err != nil || !first {
+		if errDelete := m.NetworkChangesStore.Delete(adoptedChange); errDelete != nil {
+			log.Warnf("Withdrawing the adoption %s failed: %v", adoptedChange.ID, errDelete)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return nil, errors.NewAlreadyExists("%s:%s was changed while being adopted", deviceID, version)
+	}
+
+	refs := make([]*networkchange.DeviceChangeRef, 0, len(adoptedChange.Changes))
+	for _, change := range adoptedChange.Changes {
+		deviceChange := &devicechange.DeviceChange{
+			Index: devicechange.Index(adoptedChange.Index),
+			NetworkChange: devicechange.NetworkChangeRef{
+				ID:    types.ID(adoptedChange.ID),
+				Index: types.Index(adoptedChange.Index),
+			},
+			Change: change,
+			Status: changetypes.Status{
+				Phase: changetypes.Phase_CHANGE,
+				State: changetypes.State_COMPLETE,
+			},
+		}
+		if err := m.DeviceChangesStore.Create(deviceChange); err != nil {
+			return nil, err
+		}
+		refs = append(refs, &networkchange.DeviceChangeRef{DeviceChangeID: deviceChange.ID})
+	}
+	adoptedChange.Refs = refs
+	if err := m.NetworkChangesStore.Update(adoptedChange); err != nil {
+		return nil, err
+	}
+	m.RecordEnvironment(adoptedChange, validationStrict, nil)
+	log.Infof("Adopted %d configuration values of %s as change %s", len(updates), deviceID, adoptedChange.ID)
+	return adoptedChange, nil
+}
+
+// isFirstChange returns true if no other configuration of the device precedes the change
+func (m *Manager) isFirstChange(change *networkchange.NetworkChange, id devicetype.VersionedID) (bool, error) {
+	if _, err := m.DeviceSnapshotStore.Load(id); err == nil {
+		return false, nil
+	} else if !errors.IsNotFound(err) {
+		return false, err
+	}
+	for index := change.Index; ; {
+		prevChange, err := m.NetworkChangesStore.GetPrev(index)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		} else if prevChange == nil {
+			return true, nil
+		}
+		for _, deviceChange := range prevChange.Changes {
+			if deviceChange.GetVersionedDeviceID() == id {
+				return false, nil
+			}
+		}
+		index = prevChange.Index
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"time"
+
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/store/annotation"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// maxNoteLength is the longest note an annotation may have, in bytes
+const maxNoteLength = 1024
+
+// Annotate attaches a note of the given author to a device or a network change
+func (m *Manager) Annotate(kind annotation.Kind, target string, note string, author string) (*annotation.Annotation, error) {
+	if note == "" {
+		return nil, errors.NewInvalid("no note given")
+	} else if len(note) > maxNoteLength {
+		return nil, errors.NewInvalid("note is longer than %d bytes", maxNoteLength)
+	}
+	switch kind {
+	case annotation.Device:
+		if err := m.checkDevice(devicetype.ID(target)); err != nil {
+			return nil, err
+		}
+	case annotation.NetworkChange:
+		if target == "" {
+			return nil, errors.NewInvalid("no network change given")
+		}
+		change, err := m.NetworkChangesStore.Get(networkchange.ID(target))
+		if err != nil {
+			return nil, err
+		} else if change == nil {
+			return nil, errors.NewNotFound("network change %s not found", target)
+		}
+	default:
+		return nil, errors.NewInvalid("unknown annotation kind '%s'", kind)
+	}
+	a := &annotation.Annotation{
+		Kind:    kind,
+		Target:  target,
+		Note:    note,
+		Author:  author,
+		Created: time.Now(),
+	}
+	if err := m.AnnotationStore.Add(a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// RemoveAnnotation deletes an annotation, and returns it
+func (m *Manager) RemoveAnnotation(id string) (*annotation.Annotation, error) {
+	if id == "" {
+		return nil, errors.NewInvalid("no annotation given")
+	}
+	a, err := m.AnnotationStore.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.AnnotationStore.Delete(id); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// checkDevice checks that a device is configured or registered in topo
+func (m *Manager) checkDevice(deviceID devicetype.ID) error {
+	if deviceID == "" {
+		return errors.NewInvalid("no device given")
+	}
+	if len(m.DeviceCache.GetDevicesByID(deviceID)) > 0 {
+		return nil
+	}
+	device, err := m.DeviceStore.Get(topodevice.ID(deviceID))
+	if err != nil {
+		return fromTopoError(err)
+	} else if device == nil {
+		return errors.NewNotFound("device '%s' not found", deviceID)
+	}
+	return nil
+}
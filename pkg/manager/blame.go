@@ -0,0 +1,105 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"sort"
+	"time"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/change/provenance"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// BlamedValue is an intended value of a device along with the change that set it and the artifact
+// that change originates from
+type BlamedValue struct {
+	Path  string
+	Value *devicechange.TypedValue
+	// SetAt is when the change that set the value completed, zero for a value of a snapshot
+	SetAt time.Time
+	// NetworkChange is the network change that set the value, empty for a value of a snapshot
+	NetworkChange string
+	// Provenance is the template, intent or GitOps sync managing the value, nil if it was set
+	// directly
+	Provenance *provenance.Provenance
+}
+
+// ManagedBy returns where a value managed by an artifact must be edited, empty if it is not
+func (v *BlamedValue) ManagedBy() string {
+	if v.Provenance == nil {
+		return ""
+	}
+	return "managed by " + string(v.Provenance.Kind) + " " + v.Provenance.Artifact + ": edit there instead"
+}
+
+// BlameConfig returns the intended values of a device under a path, sorted by path, each with the
+// network change that last set it and the artifact that change originates from, if any
+func (m *Manager) BlameConfig(deviceID devicetype.ID, version devicetype.Version, path string) ([]*BlamedValue, error) {
+	if path == "" {
+		path = "/"
+	}
+	_, version, err := m.CheckCacheForDevice(deviceID, "", version)
+	if err != nil {
+		return nil, err
+	}
+	versionedID := devicetype.NewVersionedID(deviceID, version)
+	intended, err := m.DeviceStateStore.Get(versionedID, 0)
+	if err != nil {
+		return nil, err
+	}
+	setBy, err := m.intendedValues(versionedID)
+	if err != nil {
+		return nil, err
+	}
+
+	pathMatcher := utils.MatchPath(path)
+	provenances := make(map[string]*provenance.Provenance)
+	values := make([]*BlamedValue, 0)
+	for _, value := range intended {
+		if !pathMatcher.MatchString(value.Path) {
+			continue
+		}
+		blamed := &BlamedValue{Path: value.Path, Value: value.Value}
+		if last, ok := setBy[value.Path]; ok && last.value.ValueToString() == value.Value.ValueToString() {
+			blamed.SetAt = last.at
+			blamed.NetworkChange = last.networkChange
+			if blamed.Provenance, err = m.changeProvenance(last.networkChange, provenances); err != nil {
+				return nil, err
+			}
+		}
+		values = append(values, blamed)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Path < values[j].Path
+	})
+	return values, nil
+}
+
+// changeProvenance returns the provenance of a network change, nil if it has none, caching it
+func (m *Manager) changeProvenance(changeID string, cached map[string]*provenance.Provenance) (*provenance.Provenance, error) {
+	if p, ok := cached[changeID]; ok {
+		return p, nil
+	}
+	p, err := m.ProvenanceStore.Get(networkchange.ID(changeID))
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	cached[changeID] = p
+	return p, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/change/provenance"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	mockcache "github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_BlameConfig(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+	ctrl := gomock.NewController(t)
+
+	mockDeviceCache := mockcache.NewMockCache(ctrl)
+	mockDeviceCache.EXPECT().GetDevicesByID(gomock.Any()).DoAndReturn(func(id devicetype.ID) []*cache.Info {
+		return []*cache.Info{{DeviceID: id, Type: deviceTypeTd, Version: deviceVersion1}}
+	}).AnyTimes()
+	mgrTest.DeviceCache = mockDeviceCache
+	mgrTest.DeviceStore.(*mockstore.MockDeviceStore).EXPECT().Get(gomock.Any()).
+		Return(nil, errors.NewNotFound("not found")).AnyTimes()
+
+	// leaf1a was set by a template, then leaf2a directly; leaf2b comes from a snapshot
+	mockDeviceStateStore := mockstore.NewMockDeviceStateStore(ctrl)
+	mockDeviceStateStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]*devicechange.PathValue{
+		{Path: "/cont1a/leaf1a", Value: devicechange.NewTypedValueString("templated")},
+		{Path: test1Cont1ACont2ALeaf2A, Value: devicechange.NewTypedValueUint(12, 8)},
+		{Path: test1Cont1ACont2ALeaf2B, Value: devicechange.NewTypedValueString("from-snapshot")},
+	}, nil).AnyTimes()
+	mgrTest.DeviceStateStore = mockDeviceStateStore
+	mockDeviceChangesStore := mockstore.NewMockDeviceChangesStore(ctrl)
+	mockDeviceChangesStore.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(id devicetype.VersionedID, ch chan<- *devicechange.DeviceChange) (stream.Context, error) {
+			go func() {
+				ch <- &devicechange.DeviceChange{
+					Index:         1,
+					NetworkChange: devicechange.NetworkChangeRef{ID: "change-1"},
+					Change: &devicechange.Change{Values: []*devicechange.ChangeValue{
+						{Path: "/cont1a/leaf1a", Value: devicechange.NewTypedValueString("templated")},
+						{Path: test1Cont1ACont2ALeaf2A, Value: devicechange.NewTypedValueUint(11, 8)},
+					}},
+					Status: changetypes.Status{State: changetypes.State_COMPLETE},
+				}
+				ch <- &devicechange.DeviceChange{
+					Index:         2,
+					NetworkChange: devicechange.NetworkChangeRef{ID: "change-2"},
+					Change: &devicechange.Change{Values: []*devicechange.ChangeValue{
+						{Path: test1Cont1ACont2ALeaf2A, Value: devicechange.NewTypedValueUint(12, 8)},
+					}},
+					Status: changetypes.Status{State: changetypes.State_COMPLETE},
+				}
+				close(ch)
+			}()
+			return stream.NewContext(func() {}), nil
+		}).AnyTimes()
+	mgrTest.DeviceChangesStore = mockDeviceChangesStore
+	mgrTest.SetProvenanceStore(provenance.NewLocalStore())
+	assert.NoError(t, mgrTest.ProvenanceStore.Create(&provenance.Provenance{
+		ChangeID: "change-1",
+		Kind:     provenance.Template,
+		Artifact: "access-ports",
+	}))
+
+	values, err := mgrTest.BlameConfig("DeviceBlame", deviceVersion1, "")
+	assert.NoError(t, err)
+	assert.Len(t, values, 3)
+
+	assert.Equal(t, test1Cont1ACont2ALeaf2A, values[0].Path)
+	assert.Equal(t, "change-2", values[0].NetworkChange)
+	assert.Nil(t, values[0].Provenance)
+	assert.Equal(t, "", values[0].ManagedBy())
+
+	assert.Equal(t, test1Cont1ACont2ALeaf2B, values[1].Path)
+	assert.Equal(t, "", values[1].NetworkChange)
+	assert.Nil(t, values[1].Provenance)
+
+	assert.Equal(t, "/cont1a/leaf1a", values[2].Path)
+	assert.Equal(t, "change-1", values[2].NetworkChange)
+	assert.Equal(t, "template/access-ports", values[2].Provenance.String())
+	assert.Equal(t, "managed by template access-ports: edit there instead", values[2].ManagedBy())
+
+	values, err = mgrTest.BlameConfig("DeviceBlame", deviceVersion1, "/cont1a/cont2a/*")
+	assert.NoError(t, err)
+	assert.Len(t, values, 2)
+}
@@ -0,0 +1,58 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	networkchangectl "github.com/onosproject/onos-config/pkg/controller/change/network"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// CancelNetworkChange stops a pending network change from being pushed to more devices. With
+// rollback, what it applied to devices is rolled back too. The message says who cancelled it and why.
+func (m *Manager) CancelNetworkChange(networkChangeID networkchange.ID, rollback bool, message string) (*networkchange.NetworkChange, error) {
+	if networkChangeID == "" {
+		return nil, errors.NewInvalid("no network change given")
+	}
+	change, err := m.NetworkChangesStore.Get(networkChangeID)
+	if err != nil {
+		return nil, err
+	} else if change == nil {
+		return nil, errors.NewNotFound("network change %s not found", networkChangeID)
+	}
+	if change.Status.Phase != changetypes.Phase_CHANGE || change.Status.State != changetypes.State_PENDING {
+		return nil, errors.NewConflict("network change %s is not in progress: %s %s", networkChangeID,
+			change.Status.Phase, change.Status.State)
+	}
+	if rollback {
+		// Later changes on top of the change must be rolled back first, as for a rollback
+		next, err := m.NetworkChangesStore.GetNext(change.Index)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		if next != nil && (next.Status.Phase != changetypes.Phase_ROLLBACK || next.Status.State != changetypes.State_COMPLETE) {
+			return nil, errors.NewConflict("network change %s cannot be rolled back: %s was made after it", networkChangeID, next.ID)
+		}
+	}
+	if err := networkchangectl.Cancel(m.NetworkChangesStore, m.DeviceChangesStore, change, rollback, message); err != nil {
+		return nil, err
+	}
+	// A cancelled change is no longer paused; its rollback is never held back by a pause
+	if err := m.PauseStore.Delete(networkChangeID); err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	return change, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"github.com/onosproject/onos-config/pkg/store/leadership"
+	"github.com/onosproject/onos-config/pkg/store/schema"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// negotiateSchemas has the stores write their records at the schema versions every node of the
+// cluster reads, encrypted only if every node reads encrypted records, and follows the
+// capabilities of the cluster as nodes are upgraded
+func (m *Manager) negotiateSchemas() error {
+	ch := make(chan leadership.Leadership)
+	if err := m.LeadershipStore.Watch(ch); err != nil {
+		return err
+	}
+	capabilities, err := m.LeadershipStore.Capabilities()
+	if err != nil {
+		return err
+	}
+	setWriteCapabilities(capabilities)
+	go func() {
+		for leadership := range ch {
+			setWriteCapabilities(leadership.Capabilities)
+		}
+	}()
+	return nil
+}
+
+// setWriteCapabilities has the records written as the capabilities of the cluster allow
+func setWriteCapabilities(capabilities leadership.Capabilities) {
+	schema.SetWriteVersions(capabilities.Schemas)
+	schema.SetEncryptionEnabled(capabilities.Supports(leadership.StorageEncryption))
+}
+
+// checkFeature returns an error unless every node of the cluster supports a feature
+func (m *Manager) checkFeature(feature leadership.Feature) error {
+	capabilities, err := m.LeadershipStore.Capabilities()
+	if err != nil {
+		return err
+	}
+	if !capabilities.Supports(feature) {
+		return errors.NewUnavailable("%s is not supported by every node; retry once every node is upgraded", feature)
+	}
+	return nil
+}
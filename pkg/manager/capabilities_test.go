@@ -0,0 +1,117 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
+	"github.com/onosproject/onos-config/pkg/store/leadership"
+	"github.com/onosproject/onos-config/pkg/store/maintenance"
+	"github.com/onosproject/onos-config/pkg/store/schema"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_NegotiateSchemas(t *testing.T) {
+	defer schema.SetWriteVersions(nil)
+	ctrl := gomock.NewController(t)
+	leadershipStore := mockstore.NewMockLeadershipStore(ctrl)
+	var ch chan<- leadership.Leadership
+	leadershipStore.EXPECT().Watch(gomock.Any()).DoAndReturn(func(watchCh chan<- leadership.Leadership) error {
+		ch = watchCh
+		return nil
+	})
+	// A node of the previous release reads unversioned network changes only
+	leadershipStore.EXPECT().Capabilities().Return(leadership.Capabilities{
+		Schemas: map[schema.Kind]schema.Version{schema.NetworkChange: schema.Unversioned},
+	}, nil)
+	mgr := &Manager{LeadershipStore: leadershipStore}
+
+	assert.NoError(t, mgr.negotiateSchemas())
+	assert.Equal(t, schema.Unversioned, schema.WriteVersion(schema.NetworkChange))
+	assert.Equal(t, schema.CurrentVersion(schema.DeviceChange), schema.WriteVersion(schema.DeviceChange))
+
+	// Once it is upgraded, the current versions are written
+	ch <- leadership.Leadership{Capabilities: leadership.LocalCapabilities()}
+	assert.Eventually(t, func() bool {
+		return schema.WriteVersion(schema.NetworkChange) == schema.CurrentVersion(schema.NetworkChange)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestManager_NegotiateEncryptionMixedVersions(t *testing.T) {
+	defer schema.SetWriteVersions(nil)
+	defer schema.SetEncryptionEnabled(false)
+	ctrl := gomock.NewController(t)
+	leadershipStore := mockstore.NewMockLeadershipStore(ctrl)
+	var ch chan<- leadership.Leadership
+	leadershipStore.EXPECT().Watch(gomock.Any()).DoAndReturn(func(watchCh chan<- leadership.Leadership) error {
+		ch = watchCh
+		return nil
+	})
+	// A node of the previous release supports no feature, and cannot read encrypted records
+	leadershipStore.EXPECT().Capabilities().Return(leadership.Capabilities{}, nil)
+	mgr := &Manager{LeadershipStore: leadershipStore}
+	keyring, err := schema.NewKeyring("kek-1", map[string][]byte{"kek-1": bytes.Repeat([]byte{1}, 32)})
+	assert.NoError(t, err)
+	schema.SetEncryptor(keyring)
+	defer schema.SetEncryptor(nil)
+	assert.NoError(t, mgr.negotiateSchemas())
+
+	// The records are written in the clear, so that a node without the key still reads them
+	value, err := schema.Marshal(schema.NetworkChange, &types.StringValue{Value: "secret"})
+	assert.NoError(t, err)
+	keyID, err := schema.KeyIDOf(value)
+	assert.NoError(t, err)
+	assert.Empty(t, keyID)
+	schema.SetEncryptor(nil)
+	decoded := &types.StringValue{}
+	assert.NoError(t, schema.Unmarshal(schema.NetworkChange, value, decoded))
+	assert.Equal(t, "secret", decoded.Value)
+	schema.SetEncryptor(keyring)
+
+	// Once it is upgraded, the records are encrypted
+	ch <- leadership.Leadership{Capabilities: leadership.LocalCapabilities()}
+	assert.Eventually(t, func() bool {
+		value, err := schema.Marshal(schema.NetworkChange, &types.StringValue{Value: "secret"})
+		keyID, _ := schema.KeyIDOf(value)
+		return err == nil && keyID == "kek-1"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestManager_EnterMaintenanceMixedVersions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	leadershipStore := mockstore.NewMockLeadershipStore(ctrl)
+	leadershipStore.EXPECT().Capabilities().Return(leadership.Capabilities{}, nil)
+	leadershipStore.EXPECT().Capabilities().Return(leadership.LocalCapabilities(), nil)
+	mgr := &Manager{
+		LeadershipStore:  leadershipStore,
+		MaintenanceStore: maintenance.NewLocalStore(),
+	}
+
+	// The nodes that do not support maintenance mode would keep accepting writes
+	_, err := mgr.EnterMaintenance("admin", "upgrade")
+	assert.True(t, errors.IsUnavailable(err))
+	_, err = mgr.MaintenanceStore.Get()
+	assert.True(t, errors.IsNotFound(err))
+
+	mode, err := mgr.EnterMaintenance("admin", "upgrade")
+	assert.NoError(t, err)
+	assert.Equal(t, "upgrade", mode.Reason)
+}
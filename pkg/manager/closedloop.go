@@ -0,0 +1,150 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/closedloop"
+	"github.com/onosproject/onos-config/pkg/events"
+	"github.com/onosproject/onos-config/pkg/modelregistry"
+	"github.com/onosproject/onos-config/pkg/store/change/provenance"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// closedLoopListener is the name the closed loop engine listens to the operational state under
+const closedLoopListener = "closed-loop"
+
+// RunClosedLoop evaluates the rules of a closed loop engine on the operational state of the
+// devices of this node, and makes the configuration changes of the actions they trigger
+func (m *Manager) RunClosedLoop(engine *closedloop.Engine) error {
+	opStateChan, err := m.Dispatcher.RegisterOpState(closedLoopListener)
+	if err != nil {
+		return err
+	}
+	engine.SetApply(m.ApplyClosedLoopAction)
+	go func() {
+		for event := range opStateChan {
+			value := event.Value()
+			if event.ItemAction() == events.EventItemDeleted {
+				value = nil
+			}
+			engine.Process(devicetype.ID(event.Subject()), event.Path(), value)
+		}
+	}()
+	log.Infof("Closed loop started with %d rules", len(engine.Rules()))
+	return nil
+}
+
+// ApplyClosedLoopAction makes the configuration change of the action of a closed loop trigger in
+// a network change, whose provenance is the rule; no change is made in maintenance mode
+func (m *Manager) ApplyClosedLoopAction(trigger *closedloop.Trigger) (networkchange.ID, error) {
+	if mode, err := m.MaintenanceStore.Get(); err == nil {
+		return "", errors.NewUnavailable("in maintenance mode since %s: %s", mode.Started.UTC().Format(time.RFC3339), mode.Reason)
+	}
+	infos := m.DeviceCache.GetDevicesByID(trigger.DeviceID)
+	if len(infos) == 0 {
+		return "", errors.NewNotFound("%s has no configuration to change", trigger.DeviceID)
+	} else if len(infos) > 1 {
+		return "", errors.NewInvalid("%s has configuration in %d versions", trigger.DeviceID, len(infos))
+	}
+	info := infos[0]
+
+	var plugin *modelregistry.ModelPlugin
+	if m.ModelRegistry != nil {
+		plugin, _ = m.ModelRegistry.GetPlugin(utils.ToModelName(info.Type, info.Version))
+	}
+	updates := make(devicechange.TypedValueMap)
+	for path, value := range trigger.Updates {
+		typed, err := closedLoopValue(plugin, path, value)
+		if err != nil {
+			return "", errors.NewInvalid("cannot set %s to '%s': %v", path, value, err)
+		}
+		updates[path] = typed
+	}
+	removes := append([]string{}, trigger.Deletes...)
+	if err := m.ValidateNetworkConfig(info.DeviceID, info.Version, info.Type, updates, removes, 0); err != nil {
+		return "", err
+	}
+
+	change, err := m.SetNetworkConfig(
+		map[devicetype.ID]devicechange.TypedValueMap{info.DeviceID: updates},
+		map[devicetype.ID][]string{info.DeviceID: removes},
+		map[devicetype.ID]cache.Info{info.DeviceID: *info}, "")
+	if err != nil {
+		return "", err
+	}
+	m.RecordEnvironment(change, validationStrict, nil)
+	origin := &provenance.Provenance{
+		ChangeID: change.ID,
+		Kind:     provenance.ClosedLoop,
+		Artifact: trigger.Rule,
+		User:     trigger.User,
+		Created:  time.Now(),
+	}
+	if err := m.ProvenanceStore.Create(origin); err != nil {
+		log.Warnf("Failed to store the provenance of %s: %v", change.ID, err)
+	}
+	return change.ID, nil
+}
+
+// closedLoopValue converts a value of the action of a closed loop rule to the type of its leaf in
+// the model; the values of the leaves not in the model are strings
+func closedLoopValue(plugin *modelregistry.ModelPlugin, path string, value string) (*devicechange.TypedValue, error) {
+	if plugin == nil {
+		return devicechange.NewTypedValueString(value), nil
+	}
+	rwPath, ok := plugin.ReadWritePaths[modelregistry.RemovePathIndices(path)]
+	if !ok {
+		return devicechange.NewTypedValueString(value), nil
+	}
+	width := devicechange.WidthThirtyTwo
+	if len(rwPath.TypeOpts) > 0 {
+		width = devicechange.Width(rwPath.TypeOpts[0])
+	}
+	switch rwPath.ValueType {
+	case devicechange.ValueType_STRING:
+		return devicechange.NewTypedValueString(value), nil
+	case devicechange.ValueType_INT:
+		i, err := strconv.ParseInt(value, 10, int(width))
+		if err != nil {
+			return nil, err
+		}
+		return devicechange.NewTypedValueInt(int(i), width), nil
+	case devicechange.ValueType_UINT:
+		u, err := strconv.ParseUint(value, 10, int(width))
+		if err != nil {
+			return nil, err
+		}
+		return devicechange.NewTypedValueUint(uint(u), width), nil
+	case devicechange.ValueType_BOOL:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, err
+		}
+		return devicechange.NewTypedValueBool(b), nil
+	case devicechange.ValueType_EMPTY:
+		return devicechange.NewTypedValueEmpty(), nil
+	default:
+		return nil, fmt.Errorf("leaves of type %v cannot be set by closed loop rules", rwPath.ValueType)
+	}
+}
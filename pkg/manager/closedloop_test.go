@@ -0,0 +1,70 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/closedloop"
+	"github.com/onosproject/onos-config/pkg/store/change/provenance"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	mockcache "github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_ApplyClosedLoopAction(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+	ctrl := gomock.NewController(t)
+	mockDeviceCache := mockcache.NewMockCache(ctrl)
+	mockDeviceCache.EXPECT().GetDevicesByID(devicetype.ID(device1)).Return([]*cache.Info{
+		{DeviceID: devicetype.ID(device1), Type: deviceTypeTd, Version: deviceVersion1},
+	}).AnyTimes()
+	mockDeviceCache.EXPECT().GetDevicesByID(gomock.Any()).Return(nil).AnyTimes()
+	mgrTest.DeviceCache = mockDeviceCache
+
+	trigger := &closedloop.Trigger{
+		ID:       "tx-power-1",
+		Rule:     "tx-power",
+		DeviceID: devicetype.ID(device1),
+		Updates:  map[string]string{test1Cont1ACont2ALeaf2A: "3", "/cont1a/leaf1a": "lowered"},
+		User:     "alice",
+	}
+	changeID, err := mgrTest.ApplyClosedLoopAction(trigger)
+	assert.NoError(t, err)
+	change, err := mgrTest.NetworkChangesStore.Get(changeID)
+	assert.NoError(t, err)
+	assert.Len(t, change.Changes, 1)
+	for _, value := range change.Changes[0].Values {
+		if value.Path == test1Cont1ACont2ALeaf2A {
+			assert.Equal(t, devicechange.ValueType_UINT, value.Value.Type)
+		}
+	}
+	origin, err := mgrTest.ProvenanceStore.Get(changeID)
+	assert.NoError(t, err)
+	assert.Equal(t, provenance.ClosedLoop, origin.Kind)
+	assert.Equal(t, "tx-power", origin.Artifact)
+
+	trigger.Updates = map[string]string{test1Cont1ACont2ALeaf2A: "high"}
+	_, err = mgrTest.ApplyClosedLoopAction(trigger)
+	assert.True(t, errors.IsInvalid(err))
+
+	trigger.DeviceID = "unknown"
+	_, err = mgrTest.ApplyClosedLoopAction(trigger)
+	assert.True(t, errors.IsNotFound(err))
+}
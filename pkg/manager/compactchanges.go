@@ -0,0 +1,65 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/config/snapshot"
+	networksnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/network"
+	"github.com/onosproject/onos-config/pkg/devicegroup"
+	streams "github.com/onosproject/onos-config/pkg/store/stream"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// CompactChanges takes a snapshot of the devices and deletes the changes it covers, keeping the
+// changes of the retention window if given. If partition is given, the name of a device group
+// or an inline device selector, only the devices of the partition are snapshotted.
+// It returns once the snapshot is complete.
+func (m *Manager) CompactChanges(partition string, retainWindow *time.Duration) (*networksnapshot.NetworkSnapshot, error) {
+	snap := &networksnapshot.NetworkSnapshot{
+		Retention: snapshot.RetentionOptions{
+			RetainWindow: retainWindow,
+		},
+	}
+	if partition != "" {
+		if _, err := devicegroup.GetRegistry().Resolve(partition); err != nil {
+			return nil, err
+		}
+	}
+
+	ch := make(chan streams.Event)
+	stream, err := m.NetworkSnapshotStore.Watch(ch)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+	if partition != "" {
+		err = m.NetworkSnapshotStore.CreatePartitioned(snap, partition)
+	} else {
+		err = m.NetworkSnapshotStore.Create(snap)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for event := range ch {
+		eventSnapshot := event.Object.(*networksnapshot.NetworkSnapshot)
+		if snap.ID != "" && snap.ID == eventSnapshot.ID && eventSnapshot.Status.Phase == snapshot.Phase_DELETE && eventSnapshot.Status.State == snapshot.State_COMPLETE {
+			return eventSnapshot, nil
+		}
+	}
+	return nil, errors.NewInvalid("snapshot state unknown")
+}
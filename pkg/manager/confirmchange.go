@@ -0,0 +1,73 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"time"
+
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/store/change/confirmation"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+const (
+	// MinConfirmTimeout is the shortest time a commit-confirmed network change can be given to be confirmed
+	MinConfirmTimeout = 10 * time.Second
+	// MaxConfirmTimeout is the longest time a commit-confirmed network change can be given to be confirmed
+	MaxConfirmTimeout = 24 * time.Hour
+)
+
+// AwaitConfirmation makes a network change commit-confirmed: it is rolled back unless it is confirmed
+// with ConfirmNetworkChange within the timeout. The confirmation is awaited before the network
+// change is created, so that the change is never made without it. It fails with AlreadyExists if
+// the change already awaits a confirmation, whose deadline is never pushed back.
+func (m *Manager) AwaitConfirmation(networkChangeID networkchange.ID, user string, timeout time.Duration) (*confirmation.Confirmation, error) {
+	if networkChangeID == "" {
+		return nil, errors.NewInvalid("no network change given")
+	}
+	if timeout < MinConfirmTimeout || timeout > MaxConfirmTimeout {
+		return nil, errors.NewInvalid("confirmation timeout %s is not between %s and %s", timeout, MinConfirmTimeout, MaxConfirmTimeout)
+	}
+	c := &confirmation.Confirmation{
+		NetworkChangeID: networkChangeID,
+		User:            user,
+		Timeout:         timeout,
+		Deadline:        time.Now().Add(timeout),
+	}
+	if err := m.ConfirmationStore.Create(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ConfirmNetworkChange confirms a commit-confirmed network change, so that it is not rolled back,
+// and returns the confirmation it awaited. A change whose deadline has passed can no longer be
+// confirmed: its confirmation is kept until the change is rolled back.
+func (m *Manager) ConfirmNetworkChange(networkChangeID networkchange.ID) (*confirmation.Confirmation, error) {
+	if networkChangeID == "" {
+		return nil, errors.NewInvalid("no network change given")
+	}
+	c, err := m.ConfirmationStore.Get(networkChangeID)
+	if err != nil {
+		return nil, err
+	}
+	if !time.Now().Before(c.Deadline) {
+		return nil, errors.NewConflict("network change '%s' was not confirmed within %s and is rolled back", networkChangeID, c.Timeout)
+	}
+	if err := m.ConfirmationStore.Delete(networkChangeID); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
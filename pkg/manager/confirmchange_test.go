@@ -0,0 +1,60 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-config/pkg/store/change/confirmation"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_ConfirmNetworkChange(t *testing.T) {
+	mgrTest, _ := setUp(t)
+
+	_, err := mgrTest.AwaitConfirmation("change-1", "alice", time.Second)
+	assert.True(t, errors.IsInvalid(err))
+	_, err = mgrTest.AwaitConfirmation("change-1", "alice", 48*time.Hour)
+	assert.True(t, errors.IsInvalid(err))
+
+	before := time.Now()
+	awaited, err := mgrTest.AwaitConfirmation("change-1", "alice", 5*time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, awaited.Deadline.Before(before.Add(5*time.Minute)))
+
+	confirmed, err := mgrTest.ConfirmNetworkChange("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", confirmed.User)
+	assert.Equal(t, 5*time.Minute, confirmed.Timeout)
+
+	// A change is only confirmed once
+	_, err = mgrTest.ConfirmNetworkChange("change-1")
+	assert.True(t, errors.IsNotFound(err))
+	_, err = mgrTest.ConfirmNetworkChange("")
+	assert.True(t, errors.IsInvalid(err))
+
+	// A change whose deadline has passed is left to be rolled back
+	assert.NoError(t, mgrTest.ConfirmationStore.Create(&confirmation.Confirmation{
+		NetworkChangeID: "change-2",
+		Timeout:         time.Minute,
+		Deadline:        time.Now().Add(-time.Second),
+	}))
+	_, err = mgrTest.ConfirmNetworkChange("change-2")
+	assert.True(t, errors.IsConflict(err))
+	_, err = mgrTest.ConfirmationStore.Get("change-2")
+	assert.NoError(t, err)
+}
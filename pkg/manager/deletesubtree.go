@@ -0,0 +1,125 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"sort"
+	"strings"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/devicegroup"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// DeleteSubtree deletes the leaves under a path prefix, which may have the '*' wildcard, on the
+// devices of a partition, the name of a device group or an inline device selector, in one network
+// change. It returns the leaves removed from each device, sorted by device, and the network change.
+// With dryRun, the deletion is validated but not made, and no network change is returned.
+func (m *Manager) DeleteSubtree(partition string, prefix string, dryRun bool) ([]*devicechange.Change, *networkchange.NetworkChange, error) {
+	if partition == "" {
+		return nil, nil, errors.NewInvalid("no device group or selector given")
+	}
+	group, err := devicegroup.GetRegistry().Resolve(partition)
+	if err != nil {
+		return nil, nil, err
+	}
+	subtree, err := parseSubtree(prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	targetRemoves := make(map[devicetype.ID][]string)
+	deviceInfo := make(map[devicetype.ID]cache.Info)
+	for _, info := range m.DeviceCache.GetDevices() {
+		if !group.Contains(info.DeviceID, info.Version, info.Type) {
+			continue
+		}
+		config, err := m.DeviceStateStore.Get(devicetype.NewVersionedID(info.DeviceID, info.Version), 0)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, nil, err
+		}
+		removes := make([]string, 0)
+		for _, value := range config {
+			elems, err := utils.ParseGNMIElements(utils.SplitPath(value.Path))
+			if err == nil && utils.SubtreeContains(subtree.Elem, elems.Elem) {
+				removes = append(removes, value.Path)
+			}
+		}
+		if len(removes) == 0 {
+			continue
+		}
+		if other, ok := deviceInfo[info.DeviceID]; ok {
+			return nil, nil, errors.NewInvalid("%s has configuration under %s in versions %s and %s: select one of them",
+				info.DeviceID, prefix, other.Version, info.Version)
+		}
+		sort.Strings(removes)
+		targetRemoves[info.DeviceID] = removes
+		deviceInfo[info.DeviceID] = *info
+	}
+
+	deletions := make([]*devicechange.Change, 0, len(targetRemoves))
+	for deviceID, removes := range targetRemoves {
+		info := deviceInfo[deviceID]
+		if err := m.ValidateNetworkConfig(deviceID, info.Version, info.Type, nil, removes, 0); err != nil {
+			return nil, nil, err
+		}
+		deletion := &devicechange.Change{
+			DeviceID:      deviceID,
+			DeviceVersion: info.Version,
+			DeviceType:    info.Type,
+			Values:        make([]*devicechange.ChangeValue, 0, len(removes)),
+		}
+		for _, path := range removes {
+			deletion.Values = append(deletion.Values, &devicechange.ChangeValue{Path: path, Removed: true})
+		}
+		deletions = append(deletions, deletion)
+	}
+	sort.Slice(deletions, func(i, j int) bool {
+		return deletions[i].DeviceID < deletions[j].DeviceID
+	})
+	if dryRun {
+		return deletions, nil, nil
+	}
+	if len(deletions) == 0 {
+		return nil, nil, errors.NewNotFound("the devices of %s have no configuration under %s", partition, prefix)
+	}
+
+	change, err := m.SetNetworkConfig(map[devicetype.ID]devicechange.TypedValueMap{}, targetRemoves, deviceInfo, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	m.RecordEnvironment(change, validationStrict, nil)
+	log.Infof("Deleted %s from %d devices of %s in change %s", prefix, len(deletions), partition, change.ID)
+	return deletions, change, nil
+}
+
+// parseSubtree parses the path prefix of the subtree to delete
+func parseSubtree(prefix string) (*gnmi.Path, error) {
+	if !strings.HasPrefix(prefix, "/") || prefix == "/" {
+		return nil, errors.NewInvalid("invalid path prefix '%s': the root may not be deleted", prefix)
+	} else if strings.Contains(prefix, "...") {
+		return nil, errors.NewInvalid("the path prefix %s may not have the '...' wildcard", prefix)
+	}
+	subtree, err := utils.ParseGNMIElements(utils.SplitPath(prefix))
+	if err != nil {
+		return nil, errors.NewInvalid("invalid path prefix %s: %v", prefix, err)
+	}
+	return subtree, nil
+}
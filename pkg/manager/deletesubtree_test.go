@@ -0,0 +1,88 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	mockcache "github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_DeleteSubtree(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+	ctrl := gomock.NewController(t)
+
+	mockDeviceCache := mockcache.NewMockCache(ctrl)
+	mockDeviceCache.EXPECT().GetDevices().Return([]*cache.Info{
+		{DeviceID: "device-2", Type: deviceTypeTd, Version: deviceVersion1},
+		{DeviceID: "device-1", Type: deviceTypeTd, Version: deviceVersion1},
+		{DeviceID: "device-3", Type: deviceTypeTd, Version: deviceVersion1},
+		{DeviceID: "other-1", Type: "Devicesim", Version: deviceVersion1},
+	}).AnyTimes()
+	mgrTest.DeviceCache = mockDeviceCache
+	mockDeviceStateStore := mockstore.NewMockDeviceStateStore(ctrl)
+	mockDeviceStateStore.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(id devicetype.VersionedID, revision interface{}) ([]*devicechange.PathValue, error) {
+			switch id.GetID() {
+			case "device-1":
+				return []*devicechange.PathValue{
+					{Path: "/cont1a/leaf1a", Value: devicechange.NewTypedValueString("untouched")},
+					{Path: "/cont1a/list2a[name=first]/name", Value: devicechange.NewTypedValueString("first")},
+					{Path: "/cont1a/list2a[name=first]/tx-power", Value: devicechange.NewTypedValueUint(5, 16)},
+				}, nil
+			case "device-2":
+				return []*devicechange.PathValue{
+					{Path: "/cont1a/list2a[name=second]/tx-power", Value: devicechange.NewTypedValueUint(6, 16)},
+				}, nil
+			}
+			return nil, errors.NewNotFound("no configuration")
+		}).AnyTimes()
+	mgrTest.DeviceStateStore = mockDeviceStateStore
+
+	deletions, change, err := mgrTest.DeleteSubtree("type=TestDevice", "/cont1a/list2a", true)
+	assert.NoError(t, err)
+	assert.Nil(t, change)
+	assert.Len(t, deletions, 2)
+	assert.Equal(t, devicetype.ID("device-1"), deletions[0].DeviceID)
+	assert.Len(t, deletions[0].Values, 2)
+	assert.Equal(t, "/cont1a/list2a[name=first]/name", deletions[0].Values[0].Path)
+	assert.True(t, deletions[0].Values[0].Removed)
+	assert.Equal(t, devicetype.ID("device-2"), deletions[1].DeviceID)
+
+	deletions, change, err = mgrTest.DeleteSubtree("type=TestDevice", "/cont1a/list2a[name=*]/tx-power", false)
+	assert.NoError(t, err)
+	assert.Len(t, deletions, 2)
+	assert.NotNil(t, change)
+	assert.Len(t, change.Changes, 2)
+	for _, deviceChange := range change.Changes {
+		assert.Len(t, deviceChange.Values, 1)
+		assert.True(t, deviceChange.Values[0].Removed)
+	}
+
+	// A prefix that only matches the start of a leaf name matches nothing
+	_, _, err = mgrTest.DeleteSubtree("type=TestDevice", "/cont1a/leaf1", false)
+	assert.True(t, errors.IsNotFound(err))
+	_, _, err = mgrTest.DeleteSubtree("", "/cont1a", false)
+	assert.True(t, errors.IsInvalid(err))
+	_, _, err = mgrTest.DeleteSubtree("type=TestDevice", "/", false)
+	assert.True(t, errors.IsInvalid(err))
+}
@@ -0,0 +1,166 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// TwinValue is the intended and the observed value of a configuration path of a device
+type TwinValue struct {
+	Path string
+	// Intended is the value set by the changes of onos-config, nil for a path it does not manage
+	Intended *devicechange.TypedValue
+	// IntendedAt is when the change that set the intended value completed, zero for a value of
+	// a snapshot
+	IntendedAt time.Time
+	// NetworkChange is the network change that set the intended value
+	NetworkChange string
+	// Observed is the value the device reports, nil if it has none or could not be read
+	Observed *devicechange.TypedValue
+	// Drift is set when the device was read and has not the intended value of the path
+	Drift bool
+}
+
+// DeviceTwin is the intended configuration of a device next to the configuration it reports
+type DeviceTwin struct {
+	DeviceID devicetype.ID
+	Version  devicetype.Version
+	// Observed is when the configuration of the device was read, zero if it could not be
+	Observed time.Time
+	// ObservationError is why the configuration of the device could not be read
+	ObservationError error
+	// Values are the values of the paths, sorted by path
+	Values []*TwinValue
+}
+
+// intendedValue is the change that last set the intended value of a path
+type intendedValue struct {
+	value         *devicechange.TypedValue
+	at            time.Time
+	networkChange string
+}
+
+// GetDeviceTwin returns the intended configuration of a device under a path, along with the
+// configuration the device reports, read with a gNMI Get. A device that cannot be read does not
+// fail the call: its twin only has the intended values, with the reason in ObservationError.
+func (m *Manager) GetDeviceTwin(deviceID devicetype.ID, version devicetype.Version, path string) (*DeviceTwin, error) {
+	if strings.Contains(path, "*") || strings.Contains(path, "...") {
+		return nil, errors.NewInvalid("the twin of a wildcard path cannot be read: %s", path)
+	}
+	if path == "" {
+		path = "/"
+	}
+	deviceType, version, err := m.CheckCacheForDevice(deviceID, "", version)
+	if err != nil {
+		return nil, err
+	}
+	versionedID := devicetype.NewVersionedID(deviceID, version)
+	intended, err := m.DeviceStateStore.Get(versionedID, 0)
+	if err != nil {
+		return nil, err
+	}
+	setBy, err := m.intendedValues(versionedID)
+	if err != nil {
+		return nil, err
+	}
+
+	pathMatcher := utils.MatchPath(path)
+	values := make(map[string]*TwinValue)
+	for _, value := range intended {
+		if !pathMatcher.MatchString(value.Path) {
+			continue
+		}
+		twinValue := &TwinValue{Path: value.Path, Intended: value.Value}
+		if last, ok := setBy[value.Path]; ok && last.value.ValueToString() == value.Value.ValueToString() {
+			twinValue.IntendedAt = last.at
+			twinValue.NetworkChange = last.networkChange
+		}
+		values[value.Path] = twinValue
+	}
+
+	twin := &DeviceTwin{DeviceID: deviceID, Version: version}
+	plugin, err := m.ModelRegistry.GetPlugin(utils.ToModelName(deviceType, version))
+	if err == nil {
+		var observed []*devicechange.PathValue
+		if observed, err = m.getDeviceValues(deviceID, version, plugin, path, gnmi.GetRequest_CONFIG); err == nil {
+			twin.Observed = time.Now()
+			for _, value := range readThroughValues(plugin, observed, pathMatcher) {
+				twinValue, ok := values[value.Path]
+				if !ok {
+					twinValue = &TwinValue{Path: value.Path}
+					values[value.Path] = twinValue
+				}
+				twinValue.Observed = value.Value
+			}
+		}
+	}
+	if err != nil {
+		log.Warnf("Not reading the configuration of %s for its twin: %v", deviceID, err)
+		twin.ObservationError = err
+	}
+
+	for _, value := range values {
+		value.Drift = !twin.Observed.IsZero() && value.Intended != nil &&
+			(value.Observed == nil || value.Observed.ValueToString() != value.Intended.ValueToString())
+		twin.Values = append(twin.Values, value)
+	}
+	sort.Slice(twin.Values, func(i, j int) bool {
+		return twin.Values[i].Path < twin.Values[j].Path
+	})
+	return twin, nil
+}
+
+// intendedValues returns, by path, the last value set by a completed change of a device
+func (m *Manager) intendedValues(deviceID devicetype.VersionedID) (map[string]intendedValue, error) {
+	ch := make(chan *devicechange.DeviceChange)
+	ctx, err := m.DeviceChangesStore.List(deviceID, ch)
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Close()
+	changes := make([]*devicechange.DeviceChange, 0)
+	for change := range ch {
+		if change.Change != nil && change.Status.Phase == changetypes.Phase_CHANGE && change.Status.State == changetypes.State_COMPLETE {
+			changes = append(changes, change)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Index < changes[j].Index
+	})
+
+	setBy := make(map[string]intendedValue)
+	for _, change := range changes {
+		for _, value := range change.Change.Values {
+			if !value.Removed {
+				setBy[value.Path] = intendedValue{
+					value:         value.Value,
+					at:            change.Updated,
+					networkChange: string(change.NetworkChange.ID),
+				}
+			}
+		}
+	}
+	return setBy, nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	southboundmocks "github.com/onosproject/onos-config/pkg/test/mocks/southbound"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	mockcache "github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_GetDeviceTwin(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+	ctrl := gomock.NewController(t)
+	const (
+		deviceTwin        = devicetype.ID("DeviceTwin")
+		deviceUnreachable = devicetype.ID("DeviceUnreachable")
+	)
+	updated := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	mockDeviceCache := mockcache.NewMockCache(ctrl)
+	mockDeviceCache.EXPECT().GetDevicesByID(gomock.Any()).DoAndReturn(func(id devicetype.ID) []*cache.Info {
+		return []*cache.Info{{DeviceID: id, Type: deviceTypeTd, Version: deviceVersion1}}
+	}).AnyTimes()
+	mgrTest.DeviceCache = mockDeviceCache
+	mgrTest.DeviceStore.(*mockstore.MockDeviceStore).EXPECT().Get(gomock.Any()).
+		Return(nil, errors.NewNotFound("not found")).AnyTimes()
+
+	// The intended configuration was set by one change, then a value was added by another
+	mockDeviceStateStore := mockstore.NewMockDeviceStateStore(ctrl)
+	mockDeviceStateStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]*devicechange.PathValue{
+		{Path: "/cont1a/leaf1a", Value: devicechange.NewTypedValueString("intended")},
+		{Path: test1Cont1ACont2ALeaf2A, Value: devicechange.NewTypedValueUint(12, 8)},
+	}, nil).AnyTimes()
+	mgrTest.DeviceStateStore = mockDeviceStateStore
+	mockDeviceChangesStore := mockstore.NewMockDeviceChangesStore(ctrl)
+	mockDeviceChangesStore.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(id devicetype.VersionedID, ch chan<- *devicechange.DeviceChange) (stream.Context, error) {
+			go func() {
+				ch <- &devicechange.DeviceChange{
+					Index:         1,
+					NetworkChange: devicechange.NetworkChangeRef{ID: "change-1"},
+					Change: &devicechange.Change{Values: []*devicechange.ChangeValue{
+						{Path: "/cont1a/leaf1a", Value: devicechange.NewTypedValueString("intended")},
+					}},
+					Status:  changetypes.Status{State: changetypes.State_COMPLETE},
+					Updated: updated,
+				}
+				ch <- &devicechange.DeviceChange{
+					Index:         2,
+					NetworkChange: devicechange.NetworkChangeRef{ID: "change-2"},
+					Change: &devicechange.Change{Values: []*devicechange.ChangeValue{
+						{Path: test1Cont1ACont2ALeaf2A, Value: devicechange.NewTypedValueUint(12, 8)},
+					}},
+					Status:  changetypes.Status{State: changetypes.State_COMPLETE},
+					Updated: updated.Add(time.Minute),
+				}
+				close(ch)
+			}()
+			return stream.NewContext(func() {}), nil
+		}).AnyTimes()
+	mgrTest.DeviceChangesStore = mockDeviceChangesStore
+
+	// The device has drifted on leaf2a and has a value not set through onos-config
+	mockTarget := southboundmocks.NewMockTargetIf(ctrl)
+	southbound.NewTargetItem(devicetype.NewVersionedID(deviceTwin, deviceVersion1), mockTarget)
+	mockTarget.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&gnmi.GetResponse{
+		Notification: []*gnmi.Notification{{
+			Update: []*gnmi.Update{{
+				Path: &gnmi.Path{},
+				Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{
+					JsonIetfVal: []byte(`{"cont1a": {"leaf1a": "intended", "cont2a": {"leaf2a": 13}, "list2a": [{"name": "local", "tx-power": 5}]}}`),
+				}},
+			}},
+		}},
+	}, nil).Times(2)
+
+	twin, err := mgrTest.GetDeviceTwin(deviceTwin, deviceVersion1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, deviceTwin, twin.DeviceID)
+	assert.NoError(t, twin.ObservationError)
+	assert.False(t, twin.Observed.IsZero())
+	assert.Len(t, twin.Values, 3)
+
+	assert.Equal(t, "/cont1a/cont2a/leaf2a", twin.Values[0].Path)
+	assert.Equal(t, "12", twin.Values[0].Intended.ValueToString())
+	assert.Equal(t, "13", twin.Values[0].Observed.ValueToString())
+	assert.Equal(t, "change-2", twin.Values[0].NetworkChange)
+	assert.True(t, twin.Values[0].Drift)
+
+	assert.Equal(t, "/cont1a/leaf1a", twin.Values[1].Path)
+	assert.Equal(t, updated, twin.Values[1].IntendedAt)
+	assert.Equal(t, "change-1", twin.Values[1].NetworkChange)
+	assert.False(t, twin.Values[1].Drift)
+
+	assert.Equal(t, "/cont1a/list2a[name=local]/tx-power", twin.Values[2].Path)
+	assert.Nil(t, twin.Values[2].Intended)
+	assert.Equal(t, "5", twin.Values[2].Observed.ValueToString())
+	assert.False(t, twin.Values[2].Drift)
+
+	// The twin of a path only has the values under it
+	twin, err = mgrTest.GetDeviceTwin(deviceTwin, deviceVersion1, "/cont1a/leaf1a")
+	assert.NoError(t, err)
+	assert.Len(t, twin.Values, 1)
+	assert.Equal(t, "/cont1a/leaf1a", twin.Values[0].Path)
+
+	// A device that cannot be read has its intended values, none of them drifted
+	mockUnreachable := southboundmocks.NewMockTargetIf(ctrl)
+	southbound.NewTargetItem(devicetype.NewVersionedID(deviceUnreachable, deviceVersion1), mockUnreachable)
+	mockUnreachable.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("connection refused"))
+	twin, err = mgrTest.GetDeviceTwin(deviceUnreachable, deviceVersion1, "")
+	assert.NoError(t, err)
+	assert.True(t, errors.IsUnavailable(twin.ObservationError), "expected unavailable, got %v", twin.ObservationError)
+	assert.True(t, twin.Observed.IsZero())
+	assert.Len(t, twin.Values, 2)
+	for _, value := range twin.Values {
+		assert.NotNil(t, value.Intended)
+		assert.Nil(t, value.Observed)
+		assert.False(t, value.Drift)
+	}
+
+	_, err = mgrTest.GetDeviceTwin(deviceTwin, deviceVersion1, "/cont1a/*")
+	assert.True(t, errors.IsInvalid(err), "expected invalid, got %v", err)
+}
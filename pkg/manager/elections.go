@@ -0,0 +1,119 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/store/leadership"
+	"github.com/onosproject/onos-config/pkg/store/mastership"
+	"github.com/onosproject/onos-lib-go/pkg/cluster"
+)
+
+// maxRecentElections is the number of elections kept for RecentElections
+const maxRecentElections = 100
+
+// Election is the start of a term of the cluster leadership, or of the mastership of a device
+type Election struct {
+	Time time.Time
+	// Device is empty for the cluster leadership
+	Device topodevice.ID
+	Term   uint64
+	Leader cluster.NodeID
+}
+
+// elections keeps the recent elections seen by this node, and the watchers of the next ones
+type elections struct {
+	mu       sync.RWMutex
+	recent   []Election
+	watchers map[chan<- Election]bool
+}
+
+func newElections() *elections {
+	return &elections{
+		recent:   make([]Election, 0, maxRecentElections),
+		watchers: make(map[chan<- Election]bool),
+	}
+}
+
+// add records an election and tells the watchers; a watcher that is not ready misses it
+func (e *elections) add(election Election) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.recent) == maxRecentElections {
+		e.recent = append(e.recent[:0], e.recent[1:]...)
+	}
+	e.recent = append(e.recent, election)
+	for watcher := range e.watchers {
+		select {
+		case watcher <- election:
+		default:
+			log.Warnf("Dropping election %+v for a slow watcher", election)
+		}
+	}
+}
+
+// watchElections records the changes of the leadership and of the masterships
+func (m *Manager) watchElections() error {
+	leaderships := make(chan leadership.Leadership)
+	if err := m.LeadershipStore.Watch(leaderships); err != nil {
+		return err
+	}
+	masterships := make(chan mastership.Mastership)
+	if err := m.MastershipStore.WatchAll(masterships); err != nil {
+		return err
+	}
+	if current, err := m.LeadershipStore.GetLeadership(); err == nil {
+		m.elections.add(Election{Time: time.Now(), Term: uint64(current.Term), Leader: current.Leader})
+	}
+	go func() {
+		for l := range leaderships {
+			m.elections.add(Election{Time: time.Now(), Term: uint64(l.Term), Leader: l.Leader})
+		}
+	}()
+	go func() {
+		for ms := range masterships {
+			m.elections.add(Election{Time: time.Now(), Device: ms.Device, Term: uint64(ms.Term), Leader: ms.Master})
+		}
+	}()
+	return nil
+}
+
+// RecentElections returns the last elections seen by this node, from the oldest. A change of the
+// capabilities of the cluster is seen as an election of the same term.
+func (m *Manager) RecentElections() []Election {
+	m.elections.mu.RLock()
+	defer m.elections.mu.RUnlock()
+	recent := make([]Election, len(m.elections.recent))
+	copy(recent, m.elections.recent)
+	return recent
+}
+
+// WatchElections sends the next elections seen by this node to the channel, until the context is
+// done. The elections are dropped while the channel is full.
+func (m *Manager) WatchElections(ctx context.Context, ch chan<- Election) {
+	m.elections.mu.Lock()
+	m.elections.watchers[ch] = true
+	m.elections.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		m.elections.mu.Lock()
+		delete(m.elections.watchers, ch)
+		m.elections.mu.Unlock()
+	}()
+}
@@ -0,0 +1,78 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/store/leadership"
+	"github.com/onosproject/onos-config/pkg/store/mastership"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-lib-go/pkg/cluster"
+	"gotest.tools/assert"
+)
+
+func Test_Elections(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	leaderships := make(chan chan<- leadership.Leadership, 1)
+	leadershipStore := mockstore.NewMockLeadershipStore(ctrl)
+	leadershipStore.EXPECT().Watch(gomock.Any()).DoAndReturn(func(ch chan<- leadership.Leadership) error {
+		leaderships <- ch
+		return nil
+	})
+	leadershipStore.EXPECT().GetLeadership().Return(&leadership.Leadership{Term: 1, Leader: "onos-config-1"}, nil)
+	masterships := make(chan chan<- mastership.Mastership, 1)
+	mastershipStore := mockstore.NewMockMastershipStore(ctrl)
+	mastershipStore.EXPECT().WatchAll(gomock.Any()).DoAndReturn(func(ch chan<- mastership.Mastership) error {
+		masterships <- ch
+		return nil
+	})
+	m := &Manager{LeadershipStore: leadershipStore, MastershipStore: mastershipStore, elections: newElections()}
+	assert.NilError(t, m.watchElections())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watched := make(chan Election, 10)
+	m.WatchElections(ctx, watched)
+
+	(<-masterships) <- mastership.Mastership{Device: "device-1", Term: 3, Master: "onos-config-2"}
+	election := <-watched
+	assert.Equal(t, election.Device, topodevice.ID("device-1"))
+	assert.Equal(t, election.Term, uint64(3))
+	assert.Equal(t, election.Leader, cluster.NodeID("onos-config-2"))
+
+	(<-leaderships) <- leadership.Leadership{Term: 2, Leader: "onos-config-2"}
+	election = <-watched
+	assert.Equal(t, election.Device, topodevice.ID(""))
+	assert.Equal(t, election.Term, uint64(2))
+
+	recent := m.RecentElections()
+	assert.Equal(t, len(recent), 3)
+	assert.Equal(t, recent[0].Leader, cluster.NodeID("onos-config-1"))
+	assert.Equal(t, recent[1].Device, topodevice.ID("device-1"))
+	assert.Equal(t, recent[2].Leader, cluster.NodeID("onos-config-2"))
+
+	cancel()
+	for i := 0; i < maxRecentElections; i++ {
+		m.elections.add(Election{Time: time.Now(), Device: "device-2", Term: uint64(i)})
+	}
+	recent = m.RecentElections()
+	assert.Equal(t, len(recent), maxRecentElections)
+	assert.Equal(t, recent[0].Term, uint64(0))
+}
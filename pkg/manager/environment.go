@@ -0,0 +1,69 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/change/environment"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-config/pkg/version"
+)
+
+// validationStrict is the level the changes made by the manager itself are validated at
+const validationStrict = "strict"
+
+// RecordEnvironment captures the environment a network change was just created in, i.e. the
+// version of onos-config, the validation settings and the model plugins of its devices, and
+// stores it with the change. As the change is already created, a failure to store it is logged.
+func (m *Manager) RecordEnvironment(change *networkchange.NetworkChange, validationLevel string,
+	allowUnknownPaths func(devicetype.ID) bool) {
+	env := &environment.Environment{
+		ChangeID:               change.ID,
+		Created:                time.Now(),
+		ControllerVersion:      version.Version,
+		ValidationLevel:        validationLevel,
+		AllowUnvalidatedConfig: m.allowUnvalidatedConfig,
+		Devices:                make([]*environment.DeviceEnvironment, 0, len(change.Changes)),
+	}
+	for _, deviceChange := range change.Changes {
+		device := &environment.DeviceEnvironment{
+			DeviceID:      deviceChange.DeviceID,
+			DeviceType:    deviceChange.DeviceType,
+			DeviceVersion: deviceChange.DeviceVersion,
+		}
+		if m.ModelRegistry != nil {
+			plugin, err := m.ModelRegistry.GetPlugin(utils.ToModelName(deviceChange.DeviceType, deviceChange.DeviceVersion))
+			if err == nil {
+				device.Model = fmt.Sprintf("%s@%s", plugin.Info.Name, plugin.Info.Version)
+				device.Plugin = fmt.Sprintf("%s@%s", plugin.Info.Plugin.Name, plugin.Info.Plugin.Version)
+			}
+		}
+		if allowUnknownPaths != nil {
+			device.AllowUnknownPaths = allowUnknownPaths(deviceChange.DeviceID)
+		}
+		env.Devices = append(env.Devices, device)
+	}
+	sort.Slice(env.Devices, func(i, j int) bool {
+		return env.Devices[i].DeviceID < env.Devices[j].DeviceID
+	})
+	if err := m.EnvironmentStore.Create(env); err != nil {
+		log.Warnf("Unable to record the environment of change %s: %v", change.ID, err)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_RecordEnvironment(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+	mgrTest.allowUnvalidatedConfig = true
+
+	change := &networkchange.NetworkChange{
+		ID: "change-1",
+		Changes: []*devicechange.Change{
+			{DeviceID: device1, DeviceVersion: deviceVersion1, DeviceType: deviceTypeTd},
+			{DeviceID: "Device0", DeviceVersion: "2.0.0", DeviceType: "Unknown"},
+		},
+	}
+	mgrTest.RecordEnvironment(change, "schema-only", func(id devicetype.ID) bool {
+		return id == device1
+	})
+
+	env, err := mgrTest.EnvironmentStore.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, version.Version, env.ControllerVersion)
+	assert.Equal(t, "schema-only", env.ValidationLevel)
+	assert.True(t, env.AllowUnvalidatedConfig)
+	assert.Len(t, env.Devices, 2)
+	assert.Equal(t, devicetype.ID("Device0"), env.Devices[0].DeviceID)
+	assert.Empty(t, env.Devices[0].Model)
+	assert.False(t, env.Devices[0].AllowUnknownPaths)
+	assert.Equal(t, devicetype.ID(device1), env.Devices[1].DeviceID)
+	assert.Equal(t, "TestDevice@1.0.0", env.Devices[1].Model)
+	assert.NotEmpty(t, env.Devices[1].Plugin)
+	assert.True(t, env.Devices[1].AllowUnknownPaths)
+
+	// The environment of a change is recorded once
+	mgrTest.RecordEnvironment(change, "strict", nil)
+	env, err = mgrTest.EnvironmentStore.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "schema-only", env.ValidationLevel)
+}
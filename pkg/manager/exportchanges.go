@@ -0,0 +1,46 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/archive"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ExportDeviceChanges returns the configuration history of a device as gNMI notifications, from
+// the snapshot its earlier changes were compacted into, if any. See archive.History.
+func (m *Manager) ExportDeviceChanges(deviceID devicetype.VersionedID) ([]archive.Entry, error) {
+	snapshot, err := m.DeviceSnapshotStore.Load(deviceID)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	ch := make(chan *devicechange.DeviceChange)
+	ctx, err := m.DeviceChangesStore.List(deviceID, ch)
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Close()
+	changes := make([]*devicechange.DeviceChange, 0)
+	for change := range ch {
+		changes = append(changes, change)
+	}
+	if snapshot == nil && len(changes) == 0 {
+		return nil, errors.NewNotFound("device %s has no configuration history", deviceID)
+	}
+	return archive.History(deviceID.GetID(), snapshot, changes)
+}
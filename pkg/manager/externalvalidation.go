@@ -0,0 +1,100 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"sort"
+	"strings"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/validators"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ProposedDiffs returns the changes the given updates and deletes would make to the intended
+// configuration of every target, leaf by leaf, for the external validators: an update of a leaf
+// to the value it already has is left out, and a delete is expanded to the leaves it removes.
+func (m *Manager) ProposedDiffs(targetUpdates map[devicetype.ID]devicechange.TypedValueMap,
+	targetRemoves map[devicetype.ID][]string, deviceInfo map[devicetype.ID]cache.Info, lastWrite networkchange.Revision) ([]*validators.DeviceDiff, error) {
+	diffs := make([]*validators.DeviceDiff, 0, len(deviceInfo))
+	for target, info := range deviceInfo {
+		configValues, err := m.DeviceStateStore.Get(devicetype.NewVersionedID(target, info.Version), lastWrite)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+		current := make(devicechange.TypedValueMap)
+		for _, configValue := range configValues {
+			current[configValue.Path] = configValue.Value
+		}
+
+		paths := make(map[string]*validators.PathDiff)
+		for _, deletePath := range targetRemoves[target] {
+			for path, value := range current {
+				if path == deletePath || strings.HasPrefix(path, deletePath+"/") {
+					paths[path] = &validators.PathDiff{
+						Path:   path,
+						Type:   value.Type.String(),
+						Before: valueString(value),
+					}
+				}
+			}
+		}
+		for path, value := range targetUpdates[target] {
+			if sameValue(current[path], value) {
+				delete(paths, path)
+				continue
+			}
+			paths[path] = &validators.PathDiff{
+				Path:   path,
+				Type:   value.Type.String(),
+				Before: valueString(current[path]),
+				After:  valueString(value),
+			}
+		}
+		if len(paths) == 0 {
+			continue
+		}
+
+		diff := &validators.DeviceDiff{
+			DeviceID:      target,
+			DeviceType:    info.Type,
+			DeviceVersion: info.Version,
+			Paths:         make([]*validators.PathDiff, 0, len(paths)),
+		}
+		for _, path := range paths {
+			diff.Paths = append(diff.Paths, path)
+		}
+		sort.Slice(diff.Paths, func(i, j int) bool {
+			return diff.Paths[i].Path < diff.Paths[j].Path
+		})
+		diffs = append(diffs, diff)
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].DeviceID < diffs[j].DeviceID
+	})
+	return diffs, nil
+}
+
+// valueString returns a value as a string, nil if there is none
+func valueString(value *devicechange.TypedValue) *string {
+	if value == nil {
+		return nil
+	}
+	s := value.ValueToString()
+	return &s
+}
@@ -0,0 +1,69 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_ProposedDiffs(t *testing.T) {
+	mgrTest, _ := setUp(t)
+
+	// An update to the value a leaf already has is no change
+	updates := make(devicechange.TypedValueMap)
+	updates[test1Cont1ACont2ALeaf2A] = devicechange.NewTypedValueFloat(valueLeaf2B159)
+	updatesForDevice1, deletesForDevice1, deviceInfo := makeDeviceChanges(device1, updates, nil)
+	diffs, err := mgrTest.ProposedDiffs(updatesForDevice1, deletesForDevice1, deviceInfo, 0)
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 0)
+
+	updates[test1Cont1ACont2ALeaf2A] = devicechange.NewTypedValueUint(valueLeaf2A789, 16)
+	updates["/cont1a/leaf1a"] = devicechange.NewTypedValueString("added")
+	updatesForDevice1, deletesForDevice1, deviceInfo = makeDeviceChanges(device1, updates, nil)
+	diffs, err = mgrTest.ProposedDiffs(updatesForDevice1, deletesForDevice1, deviceInfo, 0)
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 1)
+	assert.Equal(t, devicetype.ID(device1), diffs[0].DeviceID)
+	assert.Len(t, diffs[0].Paths, 2)
+	paths := make(map[string]int)
+	for i, path := range diffs[0].Paths {
+		paths[path.Path] = i
+	}
+
+	// The updated leaf has its value before and after
+	leaf2A := diffs[0].Paths[paths[test1Cont1ACont2ALeaf2A]]
+	assert.Equal(t, "UINT", leaf2A.Type)
+	assert.NotNil(t, leaf2A.Before)
+	assert.Equal(t, "789", *leaf2A.After)
+
+	// An added leaf has no value before
+	leaf1A := diffs[0].Paths[paths["/cont1a/leaf1a"]]
+	assert.Nil(t, leaf1A.Before)
+	assert.Equal(t, "added", *leaf1A.After)
+
+	// The leaves of a deleted container have no value after
+	updatesForDevice1, deletesForDevice1, deviceInfo = makeDeviceChanges(device1, make(devicechange.TypedValueMap), []string{"/cont1a/cont2a"})
+	diffs, err = mgrTest.ProposedDiffs(updatesForDevice1, deletesForDevice1, deviceInfo, 0)
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 1)
+	assert.Len(t, diffs[0].Paths, 1)
+	assert.Equal(t, test1Cont1ACont2ALeaf2A, diffs[0].Paths[0].Path)
+	assert.NotNil(t, diffs[0].Paths[0].Before)
+	assert.Nil(t, diffs[0].Paths[0].After)
+}
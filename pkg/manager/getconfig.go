@@ -22,6 +22,7 @@ import (
 	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
 	"github.com/onosproject/onos-config/pkg/modelregistry/jsonvalues"
 	"github.com/onosproject/onos-config/pkg/store"
+	"github.com/onosproject/onos-config/pkg/store/change/device/state"
 	"github.com/onosproject/onos-config/pkg/utils"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"io/ioutil"
@@ -44,6 +45,19 @@ func (m *Manager) GetTargetConfig(deviceID devicetype.ID, version devicetype.Ver
 		log.Error("Error while extracting config", errGetTargetCfg)
 		return nil, errGetTargetCfg
 	}
+	pathMatcher := utils.MatchPath(path)
+	if m.readThrough && !anyPathMatches(configValues, pathMatcher) {
+		deviceValues, err := m.readThroughTargetConfig(deviceID, version, deviceType, path, pathMatcher)
+		if err != nil {
+			// The device may well be unreachable; answer with what the stores have
+			log.Warnf("Reading config through to %s failed: %v", deviceID, err)
+		} else {
+			// No stored value is under path, so the device values only add to the stored ones
+			merged := make([]*devicechange.PathValue, 0, len(configValues)+len(deviceValues))
+			merged = append(merged, configValues...)
+			configValues = append(merged, deviceValues...)
+		}
+	}
 	if len(configValues) == 0 {
 		return configValues, nil
 	}
@@ -60,9 +74,8 @@ func (m *Manager) GetTargetConfig(deviceID devicetype.ID, version devicetype.Ver
 	}
 
 	filteredValues := make([]*devicechange.PathValue, 0)
-	pathRegexp := utils.MatchWildcardRegexp(path, false)
 	for _, cv := range configValuesAllowed {
-		if pathRegexp.MatchString(cv.Path) {
+		if pathMatcher.MatchString(cv.Path) {
 			filteredValues = append(filteredValues, cv)
 		}
 	}
@@ -70,6 +83,24 @@ func (m *Manager) GetTargetConfig(deviceID devicetype.ID, version devicetype.Ver
 	return filteredValues, nil
 }
 
+func anyPathMatches(configValues []*devicechange.PathValue, pathMatcher utils.PathMatcher) bool {
+	for _, cv := range configValues {
+		if pathMatcher.MatchString(cv.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchValues finds every device path whose current intended value equals the given value,
+// or matches it as a regular expression if regex is true
+func (m *Manager) SearchValues(value string, regex bool) ([]*state.SearchResult, error) {
+	if value == "" {
+		return nil, errors.NewInvalid("search value is empty")
+	}
+	return m.DeviceStateStore.Search(value, regex)
+}
+
 // GetAllDeviceIds returns a list of just DeviceIDs from the device cache
 func (m *Manager) GetAllDeviceIds() *[]string {
 
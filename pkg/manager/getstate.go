@@ -15,29 +15,131 @@
 package manager
 
 import (
+	"time"
+
 	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
 	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/store/opstate"
 	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/openconfig/gnmi/proto/gnmi"
 )
 
+// stateBudgetInterval is how often the memory budget of the operational state cache is enforced
+const stateBudgetInterval = time.Second
+
+// StateShardStats are the statistics of a shard of the operational state: the number of devices
+// and paths it caches and their estimated memory, the number of subtrees and paths evicted from
+// it, and the number of events its worker dispatched and has queued
+type StateShardStats struct {
+	Shard        int
+	Devices      int
+	Paths        int
+	Bytes        int64
+	Evictions    uint64
+	EvictedPaths uint64
+	Events       uint64
+	Queued       int
+}
+
+// SetStateShards shards the operational state cache and the dispatching of the operational state
+// events in the given number of shards, at least one. It must be called before Run.
+func (m *Manager) SetStateShards(shards int) {
+	cache := opstate.NewCache(shards)
+	if m.OperationalStateCache != nil {
+		cache.SetBudget(m.OperationalStateCache.Budget())
+	}
+	m.OperationalStateCache = cache
+	m.Dispatcher.SetShards(shards)
+}
+
+// SetStateBudget sets the estimated memory, in bytes, the operational state cache may use; 0
+// leaves it unbounded. Over budget, the state subtrees read least recently are evicted and
+// fetched again from their device when they are read.
+func (m *Manager) SetStateBudget(budget int64) {
+	m.OperationalStateCache.SetBudget(budget)
+}
+
+// enforceStateBudget periodically evicts operational state to keep the cache within its budget
+func (m *Manager) enforceStateBudget() {
+	ticker := time.NewTicker(stateBudgetInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		m.OperationalStateCache.EnforceBudget()
+	}
+}
+
+// GetStateShards returns the statistics of the shards of the operational state
+func (m *Manager) GetStateShards() []StateShardStats {
+	cacheStats := m.OperationalStateCache.Stats()
+	dispatcherStats := m.Dispatcher.Stats()
+	stats := make([]StateShardStats, len(cacheStats))
+	for i, cs := range cacheStats {
+		stats[i] = StateShardStats{
+			Shard:        cs.Shard,
+			Devices:      cs.Devices,
+			Paths:        cs.Paths,
+			Bytes:        cs.Bytes,
+			Evictions:    cs.Evictions,
+			EvictedPaths: cs.EvictedPaths,
+		}
+		if i < len(dispatcherStats) {
+			stats[i].Events = dispatcherStats[i].Events
+			stats[i].Queued = dispatcherStats[i].Queued
+		}
+	}
+	return stats
+}
+
 // GetTargetState returns a set of state values given a target and a path.
 func (m *Manager) GetTargetState(target string, path string) []*devicechange.PathValue {
 	log.Info("Getting State for ", target, path)
 	configValues := make([]*devicechange.PathValue, 0)
 	//First check the cache, if it's not empty for this path we read that and return,
-	pathRegexp := utils.MatchWildcardRegexp(path, false)
-	m.OperationalStateCacheLock.RLock()
-	for pathCache, value := range m.OperationalStateCache[topodevice.ID(target)] {
-		if pathRegexp.MatchString(pathCache) {
+	pathMatcher := utils.MatchPath(path)
+	values, evicted, _ := m.OperationalStateCache.Read(topodevice.ID(target), path)
+	for _, subtree := range evicted {
+		for _, value := range m.refetchState(devicetype.ID(target), subtree) {
+			values[value.Path] = value.Value
+		}
+	}
+	for pathCache, value := range values {
+		if pathMatcher.MatchString(pathCache) {
 			configValues = append(configValues, &devicechange.PathValue{
 				Path:  pathCache,
 				Value: value,
 			})
 		}
 	}
-	m.OperationalStateCacheLock.RUnlock()
 	if len(configValues) == 0 {
 		log.Warnf("Path %s is not in the operational state cache of device %s", path, target)
 	}
 	return configValues
 }
+
+// refetchState gets an evicted subtree of the operational state of a device from the device, and
+// puts it back in the cache; nothing is returned if the device cannot be read
+func (m *Manager) refetchState(deviceID devicetype.ID, subtree string) []*devicechange.PathValue {
+	deviceType, version, err := m.CheckCacheForDevice(deviceID, "", "")
+	if err != nil {
+		log.Warnf("Not fetching %s of %s again: %v", subtree, deviceID, err)
+		return nil
+	}
+	plugin, err := m.ModelRegistry.GetPlugin(utils.ToModelName(deviceType, version))
+	if err != nil {
+		log.Warnf("Not fetching %s of %s again: %v", subtree, deviceID, err)
+		return nil
+	}
+	log.Infof("Fetching evicted %s of %s again", subtree, deviceID)
+	values := make([]*devicechange.PathValue, 0)
+	for _, dataType := range []gnmi.GetRequest_DataType{gnmi.GetRequest_STATE, gnmi.GetRequest_OPERATIONAL} {
+		deviceValues, err := m.getDeviceValues(deviceID, version, plugin, subtree, dataType)
+		if err != nil {
+			log.Warnf("Fetching %s of %s again failed: %v", subtree, deviceID, err)
+			return nil
+		}
+		values = append(values, deviceValues...)
+	}
+	m.OperationalStateCache.Refill(topodevice.ID(deviceID), subtree, values)
+	return values
+}
@@ -0,0 +1,234 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	snapshottypes "github.com/onosproject/onos-api/go/onos/config/snapshot"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	"github.com/onosproject/onos-config/pkg/devicegroup"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// driftConcurrency is the number of the devices of a group whose configuration is read at once to
+// find their drift
+const driftConcurrency = 10
+
+// SyncState is whether a device has the configuration onos-config intends for it
+type SyncState int
+
+const (
+	// SyncUnknown is a device whose configuration was not read
+	SyncUnknown SyncState = iota
+	// InSync is a device that has the intended value of every path
+	InSync
+	// Drifted is a device that lacks the intended value of some paths
+	Drifted
+	// Unreachable is a device whose configuration could not be read
+	Unreachable
+)
+
+// DeviceStatus is the configuration health of a device of a group
+type DeviceStatus struct {
+	DeviceID devicetype.ID
+	Version  devicetype.Version
+	Type     devicetype.Type
+	Sync     SyncState
+	// DriftedPaths is the number of the paths the device lacks the intended value of, and
+	// ObservationError why its configuration could not be read
+	DriftedPaths     int
+	ObservationError error
+	// PendingChanges and FailedChanges are the device changes of the device pending and failed
+	PendingChanges int
+	FailedChanges  int
+	// LastSnapshot is when the last complete snapshot of the device was taken, zero if none was
+	LastSnapshot time.Time
+}
+
+// GroupStatus is the configuration health of the devices of a group
+type GroupStatus struct {
+	Group string
+	// Devices are the statuses of the devices of the group, sorted by device and version
+	Devices []*DeviceStatus
+	// InSync, Drifted, Unreachable and Unknown are the numbers of the devices in each sync state
+	InSync      int
+	Drifted     int
+	Unreachable int
+	Unknown     int
+	// PendingChanges and FailedChanges are the device changes of the group pending and failed, and
+	// DevicesWithPending and DevicesWithFailed the devices that have some
+	PendingChanges     int
+	FailedChanges      int
+	DevicesWithPending int
+	DevicesWithFailed  int
+	// OldestSnapshot is the oldest of the last snapshots of the devices that have one, and
+	// WithoutSnapshot the number of the devices that have none
+	OldestSnapshot  time.Time
+	WithoutSnapshot int
+}
+
+// GetDeviceGroupStatus returns the configuration health of the devices of a partition, the name of a
+// device group or an inline device selector: the devices in sync with their intended configuration
+// and those that drifted from it, their pending and failed changes, and the age of their snapshots.
+// Finding the drift reads the configuration of every device of the group; with skipDrift it is not
+// read, and the sync state of the devices is unknown.
+func (m *Manager) GetDeviceGroupStatus(partition string, skipDrift bool) (*GroupStatus, error) {
+	if partition == "" {
+		return nil, errors.NewInvalid("no device group or selector given")
+	}
+	group, err := devicegroup.GetRegistry().Resolve(partition)
+	if err != nil {
+		return nil, err
+	}
+	status := &GroupStatus{
+		Group:   partition,
+		Devices: make([]*DeviceStatus, 0),
+	}
+	devices := make(map[devicetype.VersionedID]*DeviceStatus)
+	for _, info := range m.DeviceCache.GetDevices() {
+		if !group.Contains(info.DeviceID, info.Version, info.Type) {
+			continue
+		}
+		deviceStatus := &DeviceStatus{DeviceID: info.DeviceID, Version: info.Version, Type: info.Type}
+		if err := m.countDeviceChanges(info, deviceStatus); err != nil {
+			return nil, err
+		}
+		devices[devicetype.NewVersionedID(info.DeviceID, info.Version)] = deviceStatus
+		status.Devices = append(status.Devices, deviceStatus)
+	}
+	sort.Slice(status.Devices, func(i, j int) bool {
+		if status.Devices[i].DeviceID != status.Devices[j].DeviceID {
+			return status.Devices[i].DeviceID < status.Devices[j].DeviceID
+		}
+		return status.Devices[i].Version < status.Devices[j].Version
+	})
+
+	if err := m.lastDeviceSnapshots(devices); err != nil {
+		return nil, err
+	}
+	if !skipDrift && m.ModelRegistry != nil {
+		m.checkDrift(status.Devices)
+	}
+
+	for _, deviceStatus := range status.Devices {
+		switch deviceStatus.Sync {
+		case InSync:
+			status.InSync++
+		case Drifted:
+			status.Drifted++
+		case Unreachable:
+			status.Unreachable++
+		default:
+			status.Unknown++
+		}
+		status.PendingChanges += deviceStatus.PendingChanges
+		status.FailedChanges += deviceStatus.FailedChanges
+		if deviceStatus.PendingChanges > 0 {
+			status.DevicesWithPending++
+		}
+		if deviceStatus.FailedChanges > 0 {
+			status.DevicesWithFailed++
+		}
+		if deviceStatus.LastSnapshot.IsZero() {
+			status.WithoutSnapshot++
+		} else if status.OldestSnapshot.IsZero() || deviceStatus.LastSnapshot.Before(status.OldestSnapshot) {
+			status.OldestSnapshot = deviceStatus.LastSnapshot
+		}
+	}
+	return status, nil
+}
+
+// countDeviceChanges counts the pending and the failed changes of a device
+func (m *Manager) countDeviceChanges(info *cache.Info, deviceStatus *DeviceStatus) error {
+	ch := make(chan *devicechange.DeviceChange)
+	ctx, err := m.DeviceChangesStore.List(devicetype.NewVersionedID(info.DeviceID, info.Version), ch)
+	if err != nil {
+		return err
+	}
+	defer ctx.Close()
+	for change := range ch {
+		switch change.Status.State {
+		case changetypes.State_PENDING:
+			deviceStatus.PendingChanges++
+		case changetypes.State_FAILED:
+			deviceStatus.FailedChanges++
+		}
+	}
+	return nil
+}
+
+// lastDeviceSnapshots sets when the last complete snapshot of each of the devices was taken
+func (m *Manager) lastDeviceSnapshots(devices map[devicetype.VersionedID]*DeviceStatus) error {
+	ch := make(chan *devicesnapshot.DeviceSnapshot)
+	ctx, err := m.DeviceSnapshotStore.List(ch)
+	if err != nil {
+		return err
+	}
+	defer ctx.Close()
+	for snapshot := range ch {
+		if snapshot.Status.State != snapshottypes.State_COMPLETE {
+			continue
+		}
+		deviceStatus, ok := devices[snapshot.GetVersionedDeviceID()]
+		if ok && snapshot.Updated.After(deviceStatus.LastSnapshot) {
+			deviceStatus.LastSnapshot = snapshot.Updated
+		}
+	}
+	return nil
+}
+
+// checkDrift reads the configuration of the devices, driftConcurrency at once, to find whether they
+// have their intended configuration
+func (m *Manager) checkDrift(devices []*DeviceStatus) {
+	wg := &sync.WaitGroup{}
+	tokens := make(chan struct{}, driftConcurrency)
+	for _, deviceStatus := range devices {
+		wg.Add(1)
+		tokens <- struct{}{}
+		go func(deviceStatus *DeviceStatus) {
+			defer func() {
+				<-tokens
+				wg.Done()
+			}()
+			twin, err := m.GetDeviceTwin(deviceStatus.DeviceID, deviceStatus.Version, "/")
+			if err == nil {
+				err = twin.ObservationError
+			}
+			if err != nil {
+				deviceStatus.Sync = Unreachable
+				deviceStatus.ObservationError = err
+				return
+			}
+			for _, value := range twin.Values {
+				if value.Drift {
+					deviceStatus.DriftedPaths++
+				}
+			}
+			if deviceStatus.DriftedPaths > 0 {
+				deviceStatus.Sync = Drifted
+			} else {
+				deviceStatus.Sync = InSync
+			}
+		}(deviceStatus)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,117 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	southboundmocks "github.com/onosproject/onos-config/pkg/test/mocks/southbound"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	mockcache "github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_GetDeviceGroupStatus(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+	ctrl := gomock.NewController(t)
+	const (
+		deviceInSync      = devicetype.ID("GroupInSync")
+		deviceDrifted     = devicetype.ID("GroupDrifted")
+		deviceUnreachable = devicetype.ID("GroupUnreachable")
+	)
+
+	infos := []*cache.Info{
+		{DeviceID: deviceUnreachable, Type: deviceTypeTd, Version: deviceVersion1},
+		{DeviceID: deviceInSync, Type: deviceTypeTd, Version: deviceVersion1},
+		{DeviceID: deviceDrifted, Type: deviceTypeTd, Version: deviceVersion1},
+	}
+	mockDeviceCache := mockcache.NewMockCache(ctrl)
+	mockDeviceCache.EXPECT().GetDevices().Return(infos).AnyTimes()
+	mockDeviceCache.EXPECT().GetDevicesByID(gomock.Any()).DoAndReturn(func(id devicetype.ID) []*cache.Info {
+		return []*cache.Info{{DeviceID: id, Type: deviceTypeTd, Version: deviceVersion1}}
+	}).AnyTimes()
+	mgrTest.DeviceCache = mockDeviceCache
+	mgrTest.DeviceStore.(*mockstore.MockDeviceStore).EXPECT().Get(gomock.Any()).
+		Return(nil, errors.NewNotFound("not found")).AnyTimes()
+
+	mockDeviceStateStore := mockstore.NewMockDeviceStateStore(ctrl)
+	mockDeviceStateStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]*devicechange.PathValue{
+		{Path: "/cont1a/leaf1a", Value: devicechange.NewTypedValueString("intended")},
+	}, nil).AnyTimes()
+	mgrTest.DeviceStateStore = mockDeviceStateStore
+	mockDeviceChangesStore := mockstore.NewMockDeviceChangesStore(ctrl)
+	mockDeviceChangesStore.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(id devicetype.VersionedID, ch chan<- *devicechange.DeviceChange) (stream.Context, error) {
+			close(ch)
+			return stream.NewContext(func() {}), nil
+		}).AnyTimes()
+	mgrTest.DeviceChangesStore = mockDeviceChangesStore
+	mockDeviceSnapshotStore := mockstore.NewMockDeviceSnapshotStore(ctrl)
+	mockDeviceSnapshotStore.EXPECT().List(gomock.Any()).DoAndReturn(
+		func(ch chan<- *devicesnapshot.DeviceSnapshot) (stream.Context, error) {
+			close(ch)
+			return stream.NewContext(func() {}), nil
+		}).AnyTimes()
+	mgrTest.DeviceSnapshotStore = mockDeviceSnapshotStore
+
+	for deviceID, leaf1a := range map[devicetype.ID]string{deviceInSync: "intended", deviceDrifted: "local"} {
+		mockTarget := southboundmocks.NewMockTargetIf(ctrl)
+		southbound.NewTargetItem(devicetype.NewVersionedID(deviceID, deviceVersion1), mockTarget)
+		mockTarget.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&gnmi.GetResponse{
+			Notification: []*gnmi.Notification{{
+				Update: []*gnmi.Update{{
+					Path: &gnmi.Path{},
+					Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{
+						JsonIetfVal: []byte(`{"cont1a": {"leaf1a": "` + leaf1a + `"}}`),
+					}},
+				}},
+			}},
+		}, nil).Times(1)
+	}
+
+	status, err := mgrTest.GetDeviceGroupStatus("type=TestDevice", false)
+	assert.NoError(t, err)
+	assert.Len(t, status.Devices, 3)
+	assert.Equal(t, 1, status.InSync)
+	assert.Equal(t, 1, status.Drifted)
+	assert.Equal(t, 1, status.Unreachable)
+	assert.Equal(t, 3, status.WithoutSnapshot)
+	assert.Equal(t, deviceDrifted, status.Devices[0].DeviceID)
+	assert.Equal(t, Drifted, status.Devices[0].Sync)
+	assert.Equal(t, 1, status.Devices[0].DriftedPaths)
+	assert.Equal(t, InSync, status.Devices[1].Sync)
+	assert.Equal(t, Unreachable, status.Devices[2].Sync)
+	assert.True(t, errors.IsUnavailable(status.Devices[2].ObservationError))
+
+	// Without reading the devices, their sync state is unknown
+	status, err = mgrTest.GetDeviceGroupStatus("type=TestDevice", true)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, status.Unknown)
+
+	status, err = mgrTest.GetDeviceGroupStatus("type=Devicesim", true)
+	assert.NoError(t, err)
+	assert.Len(t, status.Devices, 0)
+	_, err = mgrTest.GetDeviceGroupStatus("", true)
+	assert.True(t, errors.IsInvalid(err))
+}
@@ -0,0 +1,61 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"strings"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// CountListEntries returns the number of the entries of a list in the intended configuration of a
+// device, e.g. /interfaces/interface. The keys given in the last element of the path only count the
+// entries that have them, e.g. /interfaces/interface[name=eth1] counts 1 if that entry exists.
+// The elements of the path may have the '*' wildcard, as the keys of the list.
+func (m *Manager) CountListEntries(deviceID devicetype.ID, version devicetype.Version, path string) (int, error) {
+	if !strings.HasPrefix(path, "/") || path == "/" {
+		return 0, errors.NewInvalid("invalid list path '%s'", path)
+	} else if strings.Contains(path, "...") {
+		return 0, errors.NewInvalid("the list path %s may not have the '...' wildcard", path)
+	}
+	list, err := utils.ParseGNMIElements(utils.SplitPath(path))
+	if err != nil {
+		return 0, errors.NewInvalid("invalid list path %s: %v", path, err)
+	}
+	_, version, err = m.CheckCacheForDevice(deviceID, "", version)
+	if err != nil {
+		return 0, err
+	}
+	config, err := m.DeviceStateStore.Get(devicetype.NewVersionedID(deviceID, version), 0)
+	if err != nil && !errors.IsNotFound(err) {
+		return 0, err
+	}
+
+	depth := len(list.Elem)
+	entries := make(map[string]bool)
+	for _, value := range config {
+		elems, err := utils.ParseGNMIElements(utils.SplitPath(value.Path))
+		if err != nil || len(elems.Elem) < depth || !utils.SubtreeContains(list.Elem, elems.Elem) {
+			continue
+		}
+		if len(elems.Elem[depth-1].Key) == 0 {
+			return 0, errors.NewInvalid("%s is not a list", path)
+		}
+		entries[utils.StrPathElem(elems.Elem[:depth])] = true
+	}
+	return len(entries), nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	mockcache "github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_CountListEntries(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+	ctrl := gomock.NewController(t)
+
+	mockDeviceCache := mockcache.NewMockCache(ctrl)
+	mockDeviceCache.EXPECT().GetDevicesByID(gomock.Any()).DoAndReturn(func(id devicetype.ID) []*cache.Info {
+		return []*cache.Info{{DeviceID: id, Type: deviceTypeTd, Version: deviceVersion1}}
+	}).AnyTimes()
+	mgrTest.DeviceCache = mockDeviceCache
+	mgrTest.DeviceStore.(*mockstore.MockDeviceStore).EXPECT().Get(gomock.Any()).
+		Return(nil, errors.NewNotFound("not found")).AnyTimes()
+
+	mockDeviceStateStore := mockstore.NewMockDeviceStateStore(ctrl)
+	mockDeviceStateStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]*devicechange.PathValue{
+		{Path: "/cont1a/leaf1a", Value: devicechange.NewTypedValueString("untouched")},
+		{Path: "/cont1a/list2a[name=first]/name", Value: devicechange.NewTypedValueString("first")},
+		{Path: "/cont1a/list2a[name=first]/tx-power", Value: devicechange.NewTypedValueUint(5, 16)},
+		{Path: "/cont1a/list2a[name=second]/name", Value: devicechange.NewTypedValueString("second")},
+	}, nil).AnyTimes()
+	mgrTest.DeviceStateStore = mockDeviceStateStore
+
+	for path, count := range map[string]int{
+		"/cont1a/list2a":               2,
+		"/cont1a/list2a[name=*]":       2,
+		"/*/list2a":                    2,
+		"/cont1a/list2a[name=first]":   1,
+		"/cont1a/list2a[name=missing]": 0,
+		"/cont1a/list2b":               0,
+	} {
+		n, err := mgrTest.CountListEntries(device1, deviceVersion1, path)
+		assert.NoError(t, err, path)
+		assert.Equal(t, count, n, path)
+	}
+
+	for _, path := range []string{"/cont1a/leaf1a", "/cont1a", "/cont1a/...", "cont1a/list2a", "/"} {
+		_, err := mgrTest.CountListEntries(device1, deviceVersion1, path)
+		assert.True(t, errors.IsInvalid(err), path)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"time"
+
+	"github.com/onosproject/onos-config/pkg/store/leadership"
+	"github.com/onosproject/onos-config/pkg/store/maintenance"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// EnterMaintenance places onos-config in read-only maintenance mode: the mutating northbound
+// calls are rejected on every node until ExitMaintenance is called
+func (m *Manager) EnterMaintenance(user string, reason string) (*maintenance.Mode, error) {
+	// The nodes that do not support the mode would keep accepting writes
+	if err := m.checkFeature(leadership.MaintenanceMode); err != nil {
+		return nil, err
+	}
+	if current, err := m.MaintenanceStore.Get(); err == nil {
+		return nil, errors.NewAlreadyExists("already in maintenance mode since %s, set by '%s'",
+			current.Started.UTC().Format(time.RFC3339), current.User)
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+	mode := &maintenance.Mode{
+		User:    user,
+		Reason:  reason,
+		Started: time.Now(),
+	}
+	if err := m.MaintenanceStore.Put(mode); err != nil {
+		return nil, err
+	}
+	log.Warnf("Maintenance mode entered by '%s': %s", user, reason)
+	return mode, nil
+}
+
+// ExitMaintenance takes onos-config out of maintenance mode, and returns the mode it lifted
+func (m *Manager) ExitMaintenance(user string) (*maintenance.Mode, error) {
+	mode, err := m.MaintenanceStore.Get()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.MaintenanceStore.Delete(); err != nil {
+		return nil, err
+	}
+	log.Warnf("Maintenance mode exited by '%s'", user)
+	return mode, nil
+}
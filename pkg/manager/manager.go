@@ -16,13 +16,16 @@
 package manager
 
 import (
-	"fmt"
-	"sync"
+	"runtime"
+	"time"
 
-	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
 	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/controller/change/anomaly"
+	"github.com/onosproject/onos-config/pkg/controller/change/confirm"
 	devicechangectl "github.com/onosproject/onos-config/pkg/controller/change/device"
+	"github.com/onosproject/onos-config/pkg/controller/change/latency"
 	networkchangectl "github.com/onosproject/onos-config/pkg/controller/change/network"
+	"github.com/onosproject/onos-config/pkg/controller/change/watchdog"
 	devicesnapshotctl "github.com/onosproject/onos-config/pkg/controller/snapshot/device"
 	networksnapshotctl "github.com/onosproject/onos-config/pkg/controller/snapshot/network"
 	topodevice "github.com/onosproject/onos-config/pkg/device"
@@ -31,19 +34,36 @@ import (
 	"github.com/onosproject/onos-config/pkg/modelregistry"
 	"github.com/onosproject/onos-config/pkg/southbound"
 	"github.com/onosproject/onos-config/pkg/southbound/synchronizer"
+	"github.com/onosproject/onos-config/pkg/store/annotation"
+	"github.com/onosproject/onos-config/pkg/store/change/atomicity"
+	"github.com/onosproject/onos-config/pkg/store/change/confirmation"
 	"github.com/onosproject/onos-config/pkg/store/change/device"
 	"github.com/onosproject/onos-config/pkg/store/change/device/state"
+	"github.com/onosproject/onos-config/pkg/store/change/environment"
 	"github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/onosproject/onos-config/pkg/store/change/pause"
+	"github.com/onosproject/onos-config/pkg/store/change/provenance"
+	"github.com/onosproject/onos-config/pkg/store/change/push"
+	"github.com/onosproject/onos-config/pkg/store/change/signature"
 	devicestore "github.com/onosproject/onos-config/pkg/store/device"
 	"github.com/onosproject/onos-config/pkg/store/device/cache"
 	"github.com/onosproject/onos-config/pkg/store/leadership"
+	"github.com/onosproject/onos-config/pkg/store/location"
+	"github.com/onosproject/onos-config/pkg/store/maintenance"
 	"github.com/onosproject/onos-config/pkg/store/mastership"
+	mergestore "github.com/onosproject/onos-config/pkg/store/merge"
+	"github.com/onosproject/onos-config/pkg/store/opstate"
+	"github.com/onosproject/onos-config/pkg/store/ownership"
+	"github.com/onosproject/onos-config/pkg/store/quarantine"
+	"github.com/onosproject/onos-config/pkg/store/sampling"
 	devicesnap "github.com/onosproject/onos-config/pkg/store/snapshot/device"
 	networksnap "github.com/onosproject/onos-config/pkg/store/snapshot/network"
+	transformstore "github.com/onosproject/onos-config/pkg/store/transform"
+	"github.com/onosproject/onos-config/pkg/store/trust"
+	"github.com/onosproject/onos-config/pkg/store/tuning"
 	"github.com/onosproject/onos-lib-go/pkg/controller"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 var mgr Manager
@@ -61,18 +81,42 @@ type Manager struct {
 	NetworkChangesStore       network.Store
 	NetworkSnapshotStore      networksnap.Store
 	DeviceSnapshotStore       devicesnap.Store
+	SignatureStore            signature.Store
+	ProvenanceStore           provenance.Store
+	TrustStore                trust.Store
+	LocationStore             location.Store
+	QuarantineStore           quarantine.Store
+	PauseStore                pause.Store
+	PushStore                 push.Store
+	TransformStore            transformstore.Store
+	TuningStore               tuning.Store
+	SampleIntervalStore       sampling.Store
+	AnnotationStore           annotation.Store
+	MaintenanceStore          maintenance.Store
+	OwnershipStore            ownership.Store
+	MergeStore                mergestore.Store
+	EnvironmentStore          environment.Store
+	AtomicityStore            atomicity.Store
+	ConfirmationStore         confirmation.Store
 	networkChangeController   *controller.Controller
 	deviceChangeController    *controller.Controller
 	networkSnapshotController *controller.Controller
 	deviceSnapshotController  *controller.Controller
+	Watchdog                  *watchdog.Watchdog
+	confirmTimer              *confirm.Timer
+	LatencyTracker            *latency.Tracker
+	AnomalyDetector           *anomaly.Detector
 	ModelRegistry             *modelregistry.ModelRegistry
 	TopoChannel               chan *topodevice.ListResponse
 	OperationalStateChannel   chan events.OperationalStateEvent
 	SouthboundErrorChan       chan events.DeviceResponse
 	Dispatcher                *dispatcher.Dispatcher
-	OperationalStateCache     map[topodevice.ID]devicechange.TypedValueMap
-	OperationalStateCacheLock *sync.RWMutex
+	OperationalStateCache     *opstate.Cache
 	allowUnvalidatedConfig    bool
+	readThrough               bool
+	modelCheckInterval        time.Duration
+	resolveInterval           time.Duration
+	elections                 *elections
 }
 
 // NewManager initializes the network config manager subsystem.
@@ -83,6 +127,7 @@ func NewManager(leadershipStore leadership.Store, mastershipStore mastership.Sto
 	log.Info("Creating Manager")
 
 	mgr = Manager{
+		LeadershipStore:           leadershipStore,
 		DeviceChangesStore:        deviceChangesStore,
 		DeviceStateStore:          deviceStateStore,
 		DeviceStore:               deviceStore,
@@ -91,6 +136,23 @@ func NewManager(leadershipStore leadership.Store, mastershipStore mastership.Sto
 		NetworkChangesStore:       networkChangesStore,
 		NetworkSnapshotStore:      networkSnapshotStore,
 		DeviceSnapshotStore:       deviceSnapshotStore,
+		SignatureStore:            signature.NewLocalStore(),
+		ProvenanceStore:           provenance.NewLocalStore(),
+		TrustStore:                trust.NewLocalStore(nil),
+		LocationStore:             location.NewLocalStore(),
+		QuarantineStore:           quarantine.NewLocalStore(),
+		PauseStore:                pause.NewLocalStore(),
+		PushStore:                 push.NewLocalStore(),
+		TransformStore:            transformstore.NewLocalStore(),
+		TuningStore:               tuning.NewLocalStore(),
+		SampleIntervalStore:       sampling.NewLocalStore(),
+		AnnotationStore:           annotation.NewLocalStore(),
+		MaintenanceStore:          maintenance.NewLocalStore(),
+		OwnershipStore:            ownership.NewLocalStore(),
+		MergeStore:                mergestore.NewLocalStore(),
+		EnvironmentStore:          environment.NewLocalStore(),
+		AtomicityStore:            atomicity.NewLocalStore(),
+		ConfirmationStore:         confirmation.NewLocalStore(),
 		networkChangeController:   networkchangectl.NewController(leadershipStore, deviceCache, deviceStore, networkChangesStore, deviceChangesStore),
 		deviceChangeController:    devicechangectl.NewController(mastershipStore, deviceStore, deviceCache, deviceChangesStore),
 		networkSnapshotController: networksnapshotctl.NewController(leadershipStore, networkChangesStore, networkSnapshotStore, deviceSnapshotStore, deviceChangesStore),
@@ -100,13 +162,141 @@ func NewManager(leadershipStore leadership.Store, mastershipStore mastership.Sto
 		OperationalStateChannel:   make(chan events.OperationalStateEvent),
 		SouthboundErrorChan:       make(chan events.DeviceResponse),
 		Dispatcher:                dispatcher.NewDispatcher(),
-		OperationalStateCache:     make(map[topodevice.ID]devicechange.TypedValueMap),
-		OperationalStateCacheLock: &sync.RWMutex{},
 		allowUnvalidatedConfig:    allowUnvalidatedConfig,
+		elections:                 newElections(),
 	}
+	mgr.SetStateShards(runtime.GOMAXPROCS(0))
+	southbound.SetTrustStore(mgr.TrustStore)
+	southbound.SetLocationStore(mgr.LocationStore)
+	southbound.SetQuarantineStore(mgr.QuarantineStore)
+	devicechangectl.SetPauseStore(mgr.PauseStore)
+	devicechangectl.SetPushStore(mgr.PushStore)
+	networkchangectl.SetAtomicityStore(mgr.AtomicityStore)
 	return &mgr
 }
 
+// SetTrustStore sets the store of the trust bundles used for device connections
+func (m *Manager) SetTrustStore(store trust.Store) {
+	m.TrustStore = store
+	southbound.SetTrustStore(store)
+}
+
+// SetLocationStore sets the store of the addresses the devices identified by their serial number
+// are located at
+func (m *Manager) SetLocationStore(store location.Store) {
+	m.LocationStore = store
+	southbound.SetLocationStore(store)
+}
+
+// SetProvenanceStore sets the store of the templates, intents and GitOps syncs network changes
+// originate from
+func (m *Manager) SetProvenanceStore(store provenance.Store) {
+	m.ProvenanceStore = store
+}
+
+// SetWatchdog sets the policy of the watchdog of stuck network changes, started by Run
+func (m *Manager) SetWatchdog(policy watchdog.Policy) error {
+	w, err := watchdog.NewWatchdog(policy, m.LeadershipStore, m.NetworkChangesStore, m.DeviceChangesStore)
+	if err != nil {
+		return err
+	}
+	m.Watchdog = w
+	return nil
+}
+
+// SetLatencyTracker sets up the tracking of the latency of the network changes, started by Run; a
+// change that takes longer than the slo on a device counts against it, unless the slo is zero
+func (m *Manager) SetLatencyTracker(slo time.Duration) {
+	m.LatencyTracker = latency.NewTracker(slo, m.NetworkChangesStore, m.DeviceChangesStore)
+}
+
+// SetAnomalyDetector sets up the detection of the paths changing ratio times more often than
+// usual, started by Run; there is none if the ratio is zero
+func (m *Manager) SetAnomalyDetector(ratio float64) {
+	if ratio <= 0 {
+		m.AnomalyDetector = nil
+		return
+	}
+	m.AnomalyDetector = anomaly.NewDetector(ratio, m.NetworkChangesStore)
+}
+
+// SetQuarantineStore sets the store of the devices whose capabilities do not match their model
+func (m *Manager) SetQuarantineStore(store quarantine.Store) {
+	m.QuarantineStore = store
+	southbound.SetQuarantineStore(store)
+}
+
+// SetPauseStore sets the store of the network changes whose propagation is paused
+func (m *Manager) SetPauseStore(store pause.Store) {
+	m.PauseStore = store
+	devicechangectl.SetPauseStore(store)
+}
+
+// SetConfirmationStore sets the store of the confirmations awaited by commit-confirmed network changes
+func (m *Manager) SetConfirmationStore(store confirmation.Store) {
+	m.ConfirmationStore = store
+}
+
+// SetPushStore sets the store of the pushes of device changes to their devices
+func (m *Manager) SetPushStore(store push.Store) {
+	m.PushStore = store
+	devicechangectl.SetPushStore(store)
+}
+
+// SetTuningStore sets the store of the runtime tuning of the controllers, applied by Run
+func (m *Manager) SetTuningStore(store tuning.Store) {
+	m.TuningStore = store
+}
+
+// SetSampleIntervalStore sets the store of the sample intervals of the state subscriptions, applied
+// by Run
+func (m *Manager) SetSampleIntervalStore(store sampling.Store) {
+	m.SampleIntervalStore = store
+}
+
+// SetAnnotationStore sets the store of the notes operators attach to devices and network changes
+func (m *Manager) SetAnnotationStore(store annotation.Store) {
+	m.AnnotationStore = store
+}
+
+// SetMaintenanceStore sets the store of the read-only maintenance mode
+func (m *Manager) SetMaintenanceStore(store maintenance.Store) {
+	m.MaintenanceStore = store
+}
+
+// SetOwnershipStore sets the store of the subtrees automation systems claim on devices
+func (m *Manager) SetOwnershipStore(store ownership.Store) {
+	m.OwnershipStore = store
+}
+
+// SetMergeStore sets the store of the merge strategies of the subtrees of the configuration
+func (m *Manager) SetMergeStore(store mergestore.Store) {
+	m.MergeStore = store
+}
+
+// SetModelCheckInterval sets how often the models reported by the connected devices are compared
+// again with their plugins; they are only compared when a device connects if 0
+func (m *Manager) SetModelCheckInterval(interval time.Duration) {
+	m.modelCheckInterval = interval
+}
+
+// SetResolveInterval sets how often the addresses of the connected devices are resolved again, to
+// reconnect to the devices whose address changed; they are only resolved as they connect if 0
+func (m *Manager) SetResolveInterval(interval time.Duration) {
+	m.resolveInterval = interval
+}
+
+// SetEnvironmentStore sets the store of the environments the network changes were created in
+func (m *Manager) SetEnvironmentStore(store environment.Store) {
+	m.EnvironmentStore = store
+}
+
+// SetAtomicityStore sets the store of the atomicity modes of the network changes
+func (m *Manager) SetAtomicityStore(store atomicity.Store) {
+	m.AtomicityStore = store
+	networkchangectl.SetAtomicityStore(store)
+}
+
 // setTargetGenerator is generally only called from test
 func (m *Manager) setTargetGenerator(targetGen func() southbound.TargetIf) {
 	southbound.TargetGenerator = targetGen
@@ -116,6 +306,28 @@ func (m *Manager) setTargetGenerator(targetGen func() southbound.TargetIf) {
 func (m *Manager) Run() {
 	log.Info("Starting Manager")
 
+	// Write the stored records at the schema versions every node reads before anything is written
+	if err := m.negotiateSchemas(); err != nil {
+		log.Error("Can't negotiate the schema versions of the stores ", err)
+	}
+
+	// Keep the recent elections for the operators
+	if err := m.watchElections(); err != nil {
+		log.Error("Can't watch the elections ", err)
+	}
+
+	// Tune the controllers before they start, and keep their tuning in sync with the store
+	if err := m.loadTuning(); err != nil {
+		log.Error("Can't load the tuning of the controllers ", err)
+	}
+	go m.refreshTuning(tuningRefreshInterval)
+
+	// Sample the state of the devices as set by the operators
+	if err := m.loadSampleIntervals(); err != nil {
+		log.Error("Can't load the sample intervals ", err)
+	}
+	go m.refreshSampleIntervals(sampleIntervalRefreshInterval)
+
 	// Start the NetworkChange controller
 	errNetworkCtrl := m.networkChangeController.Start()
 	if errNetworkCtrl != nil {
@@ -137,8 +349,35 @@ func (m *Manager) Run() {
 		log.Error("Can't start controller ", errDeviceSnapshotCtrl)
 	}
 
+	// Start the watchdog of stuck network changes
+	if m.Watchdog != nil {
+		m.Watchdog.Start()
+	}
+
+	// Start rolling back the commit-confirmed network changes that are not confirmed in time
+	m.confirmTimer = confirm.NewTimer(0, m.LeadershipStore, m.ConfirmationStore, m.NetworkChangesStore)
+	m.confirmTimer.Start()
+
+	// Start tracking the latency of the network changes
+	if m.LatencyTracker != nil {
+		if err := m.LatencyTracker.Start(); err != nil {
+			log.Error("Can't track the latency of the changes ", err)
+		}
+	}
+
+	// Start detecting the paths changing more often than usual
+	if m.AnomalyDetector != nil {
+		if err := m.AnomalyDetector.Start(); err != nil {
+			log.Error("Can't detect the anomalies of the changes ", err)
+		}
+	}
+
 	// Start the main dispatcher system
 	go m.Dispatcher.ListenOperationalState(m.OperationalStateChannel)
+	if err := m.Dispatcher.Bus().WatchChanges(m.NetworkChangesStore, m.DeviceChangesStore); err != nil {
+		log.Error("Can't publish the changes to the event bus ", err)
+	}
+	go m.enforceStateBudget()
 
 	sessionManager, err := synchronizer.NewSessionManager(
 		synchronizer.WithTopoChannel(m.TopoChannel),
@@ -147,11 +386,12 @@ func (m *Manager) Run() {
 		synchronizer.WithModelRegistry(m.ModelRegistry),
 		synchronizer.WithOperationalStateCache(m.OperationalStateCache),
 		synchronizer.WithNewTargetFn(southbound.TargetGenerator),
-		synchronizer.WithOperationalStateCacheLock(m.OperationalStateCacheLock),
 		synchronizer.WithDeviceChangeStore(m.DeviceChangesStore),
 		synchronizer.WithMastershipStore(m.MastershipStore),
 		synchronizer.WithDeviceStore(m.DeviceStore),
 		synchronizer.WithSessions(make(map[topodevice.ID]*synchronizer.Session)),
+		synchronizer.WithModelCheckInterval(m.modelCheckInterval),
+		synchronizer.WithResolveInterval(m.resolveInterval),
 	)
 
 	if err != nil {
@@ -196,8 +436,7 @@ func (m *Manager) CheckCacheForDevice(target devicetype.ID, deviceType devicetyp
 			if errTopoDevice == nil && topoDevice != nil {
 				return devicetype.Type(topoDevice.Type), devicetype.Version(topoDevice.Version), nil
 			}
-			return "", "", status.Error(codes.Internal,
-				fmt.Sprintf("target %s is not known. Need to supply a type and version through Extensions 101 and 102", target))
+			return "", "", errors.NewNotFound("target %s is not known. Need to supply a type and version through Extensions 101 and 102", target)
 		}
 		return deviceType, version, nil
 	} else if len(deviceInfos) == 1 {
@@ -220,13 +459,11 @@ func (m *Manager) CheckCacheForDevice(target devicetype.ID, deviceType devicetyp
 			log.Infof("Handling target %s as %s:%s", target, deviceType, version)
 			return deviceType, version, nil
 		} else if deviceType != "" && deviceType != deviceInfos[0].Type {
-			return "", "", status.Error(codes.Internal,
-				fmt.Sprintf("target %s type given %s does not match expected %s",
-					target, deviceType, deviceInfos[0].Type))
+			return "", "", errors.NewInvalid("target %s type given %s does not match expected %s",
+				target, deviceType, deviceInfos[0].Type)
 		}
 
-		return "", "", status.Error(codes.Internal,
-			fmt.Sprintf("target %s has %d versions. Specify 1 version with extension 102",
-				target, len(deviceInfos)))
+		return "", "", errors.NewInvalid("target %s has %d versions. Specify 1 version with extension 102",
+			target, len(deviceInfos))
 	}
 }
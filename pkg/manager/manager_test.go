@@ -23,9 +23,12 @@ import (
 	configmodel "github.com/onosproject/onos-config-model/pkg/model"
 	topodevice "github.com/onosproject/onos-config/pkg/device"
 	"github.com/onosproject/onos-config/pkg/modelregistry"
+	"github.com/onosproject/onos-config/pkg/southbound"
 	networkstore "github.com/onosproject/onos-config/pkg/store/change/network"
 	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/store/leadership"
 	"github.com/onosproject/onos-config/pkg/store/stream"
+	southboundmocks "github.com/onosproject/onos-config/pkg/test/mocks/southbound"
 	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
 	mockcache "github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
@@ -123,10 +126,14 @@ func setUp(t *testing.T) (*Manager, *AllMocks) {
 	mockLeadershipStore := mockstore.NewMockLeadershipStore(ctrl)
 	mockLeadershipStore.EXPECT().Watch(gomock.Any()).AnyTimes()
 	mockLeadershipStore.EXPECT().IsLeader().AnyTimes()
+	mockLeadershipStore.EXPECT().Capabilities().Return(leadership.LocalCapabilities(), nil).AnyTimes()
+	mockLeadershipStore.EXPECT().GetLeadership().Return(&leadership.Leadership{Term: 1, Leader: "onos-config-1"}, nil).AnyTimes()
 
 	// Mock Mastership Store
 	mockMastershipStore := mockstore.NewMockMastershipStore(ctrl)
 	mockMastershipStore.EXPECT().Watch(gomock.Any(), gomock.Any()).AnyTimes()
+	mockMastershipStore.EXPECT().WatchAll(gomock.Any()).AnyTimes()
+	mockMastershipStore.EXPECT().SetPreference(gomock.Any(), gomock.Any()).AnyTimes()
 
 	// Mock Network changes store
 	networkChangesList := make([]*networkchange.NetworkChange, 0)
@@ -395,6 +402,38 @@ func Test_SetNetworkConfig_NewConfig(t *testing.T) {
 	assert.Equal(t, value2C.Removed, true)
 }
 
+func Test_IsNoOpNetworkConfig(t *testing.T) {
+	mgrTest, _ := setUp(t)
+
+	// Writing the value the device already has changes nothing
+	updates := make(devicechange.TypedValueMap)
+	updates[test1Cont1ACont2ALeaf2A] = devicechange.NewTypedValueFloat(valueLeaf2B159)
+	deletes := []string{test1Cont1ACont2ALeaf2C}
+	updatesForDevice1, deletesForDevice1, deviceInfo := makeDeviceChanges(device1, updates, deletes)
+	noOp, err := mgrTest.IsNoOpNetworkConfig(updatesForDevice1, deletesForDevice1, deviceInfo, 0)
+	assert.NoError(t, err)
+	assert.True(t, noOp)
+
+	// A different value changes the configuration
+	updates[test1Cont1ACont2ALeaf2A] = devicechange.NewTypedValueUint(valueLeaf2A789, 16)
+	noOp, err = mgrTest.IsNoOpNetworkConfig(updatesForDevice1, deletesForDevice1, deviceInfo, 0)
+	assert.NoError(t, err)
+	assert.False(t, noOp)
+
+	// So does deleting a container holding a value
+	updatesForDevice1, deletesForDevice1, deviceInfo = makeDeviceChanges(device1, make(devicechange.TypedValueMap), []string{"/cont1a/cont2a"})
+	noOp, err = mgrTest.IsNoOpNetworkConfig(updatesForDevice1, deletesForDevice1, deviceInfo, 0)
+	assert.NoError(t, err)
+	assert.False(t, noOp)
+
+	// A device with no configuration yet only changes on an update
+	const deviceUnconfigured = devicetype.ID("DeviceUnconfigured")
+	updatesForDevice2, deletesForDevice2, deviceInfo2 := makeDeviceChanges(deviceUnconfigured, make(devicechange.TypedValueMap), []string{test1Cont1ACont2ALeaf2A})
+	noOp, err = mgrTest.IsNoOpNetworkConfig(updatesForDevice2, deletesForDevice2, deviceInfo2, 0)
+	assert.NoError(t, err)
+	assert.True(t, noOp)
+}
+
 func Test_SetMultipleSimilarNetworkConfig(t *testing.T) {
 
 	mgrTest, _ := setUp(t)
@@ -607,6 +646,44 @@ func TestManager_ComputeRollbackDelete(t *testing.T) {
 	}
 }
 
+func TestManager_PreviewRollback(t *testing.T) {
+	mgrTest, mocks := setUp(t)
+
+	updates := make(devicechange.TypedValueMap)
+	deletes := []string{test1Cont1ACont2ALeaf2A}
+	updates[test1Cont1ACont2ALeaf2B] = devicechange.NewTypedValueFloat(valueLeaf2B314)
+	updates[test1Cont1ACont2ALeaf2D] = devicechange.NewTypedValueFloat(valueLeaf2D123)
+
+	updatesForDevice1, deletesForDevice1, deviceInfo := makeDeviceChanges(device1, updates, deletes)
+	_, err := mgrTest.SetNetworkConfig(updatesForDevice1, deletesForDevice1, deviceInfo, "TestingPreview")
+	assert.NoError(t, err, "Can't create change")
+
+	testingPreview, err := mocks.MockStores.NetworkChangesStore.Get("TestingPreview")
+	assert.NoError(t, err, "Cant' retrieve Config")
+	mocks.MockStores.NetworkChangesStore.EXPECT().GetNext(testingPreview.Index).Return(nil, nil)
+
+	deltas, err := mgrTest.PreviewRollback("TestingPreview")
+	assert.NoError(t, err, "Can't preview rollback")
+	assert.Len(t, deltas, 1)
+	assert.Equal(t, device1, string(deltas[0].DeviceID))
+	assert.Len(t, deltas[0].Values, 3)
+	for _, v := range deltas[0].Values {
+		switch v.Path {
+		case test1Cont1ACont2ALeaf2A:
+			assert.False(t, v.Removed)
+			assert.Equal(t, "1.579000", v.Value.ValueToString())
+		case test1Cont1ACont2ALeaf2B, test1Cont1ACont2ALeaf2D:
+			assert.True(t, v.Removed)
+		default:
+			t.Errorf("Unexpected path %s", v.Path)
+		}
+	}
+
+	// The preview must leave the change untouched
+	previewed, _ := mgrTest.NetworkChangesStore.Get("TestingPreview")
+	assert.Equal(t, changetypes.Phase_CHANGE, previewed.Status.Phase)
+}
+
 func TestManager_GetTargetState(t *testing.T) {
 	const (
 		device1 = "device1"
@@ -625,9 +702,7 @@ func TestManager_GetTargetState(t *testing.T) {
 	change2 := devicechange.NewTypedValueString(value2)
 	device1ValueMap[path1] = change1
 	device1ValueMap[path2] = change2
-	mgrTest.OperationalStateCacheLock.RLock()
-	mgrTest.OperationalStateCache[device1] = device1ValueMap
-	mgrTest.OperationalStateCacheLock.RUnlock()
+	mgrTest.OperationalStateCache.Put(device1, device1ValueMap)
 
 	// Test fetching a known path from the cache
 	state1 := mgrTest.GetTargetState(device1, path1WC)
@@ -865,3 +940,112 @@ func TestManager_CheckCacheForDevice(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "DeviceTest3 is not known. Need to supply a type and version through Extensions 101 and 102")
 }
+
+func TestManager_SimulateChangeNoModel(t *testing.T) {
+	mgrTest, _ := setUp(t)
+
+	updates := make(devicechange.TypedValueMap)
+	updates[test1Cont1ACont2ALeaf2A] = devicechange.NewTypedValueUint(valueLeaf2A789, 16)
+	// Unvalidated configuration is allowed by setUp, but a simulation needs the model
+	_, err := mgrTest.SimulateChange(device1, deviceVersion1, deviceTypeTd, updates, nil)
+	assert.True(t, errors.IsNotFound(err), "expected not found, got %v", err)
+}
+
+func TestManager_GetReadThroughNoModel(t *testing.T) {
+	mgrTest, _ := setUp(t)
+	mgrTest.SetReadThrough(true)
+	defer mgrTest.SetReadThrough(false)
+
+	// Values in the store are returned without going to the device
+	result, err := mgrTest.GetTargetConfig(device1, deviceVersion1, deviceTypeTd, test1Cont1ACont2ALeaf2A, 0, nil)
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+
+	// Without a model the values of the device cannot be read, so the Get answers with the store
+	result, err = mgrTest.GetTargetConfig(device1, deviceVersion1, deviceTypeTd, test1Cont1ACont2ALeaf2C, 0, nil)
+	assert.NoError(t, err)
+	assert.Len(t, result, 0)
+}
+
+func TestManager_AdoptConfig(t *testing.T) {
+	mgrTest, _ := setUp(t)
+
+	// device1 already has configuration in the stores
+	_, err := mgrTest.AdoptConfig(device1, deviceVersion1, deviceTypeTd)
+	assert.True(t, errors.IsAlreadyExists(err), "expected already exists, got %v", err)
+
+	// The values of a device cannot be converted without its model
+	_, err = mgrTest.AdoptConfig("Device2", deviceVersion1, deviceTypeTd)
+	assert.True(t, errors.IsNotFound(err), "expected not found, got %v", err)
+}
+
+func TestManager_AdoptConfigDevice(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+	ctrl := gomock.NewController(t)
+	const deviceAdopted = devicetype.ID("DeviceAdopted")
+
+	// The device has nothing in the stores
+	mockDeviceStateStore := mockstore.NewMockDeviceStateStore(ctrl)
+	mockDeviceStateStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]*devicechange.PathValue{}, nil).AnyTimes()
+	mgrTest.DeviceStateStore = mockDeviceStateStore
+	mgrTest.DeviceSnapshotStore.(*mockstore.MockDeviceSnapshotStore).EXPECT().Load(gomock.Any()).
+		Return(nil, errors.NewNotFound("no snapshot")).AnyTimes()
+	mgrTest.NetworkChangesStore.(*mockstore.MockNetworkChangesStore).EXPECT().GetPrev(gomock.Any()).
+		Return(nil, errors.NewNotFound("no previous change")).AnyTimes()
+
+	mockTarget := southboundmocks.NewMockTargetIf(ctrl)
+	southbound.NewTargetItem(devicetype.NewVersionedID(deviceAdopted, deviceVersion1), mockTarget)
+	mockTarget.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&gnmi.GetResponse{
+		Notification: []*gnmi.Notification{{
+			Update: []*gnmi.Update{{
+				Path: &gnmi.Path{},
+				Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{
+					JsonIetfVal: []byte(`{"cont1a": {"leaf1a": "adopted", "cont2a": {"leaf2a": 12}}}`),
+				}},
+			}},
+		}},
+	}, nil).Times(1)
+
+	adoptedChange, err := mgrTest.AdoptConfig(deviceAdopted, deviceVersion1, deviceTypeTd)
+	assert.NoError(t, err)
+	assert.Equal(t, networkstore.NewAdoptedChangeID(deviceAdopted, deviceVersion1), adoptedChange.ID)
+	assert.Equal(t, changetypes.State_COMPLETE, adoptedChange.Status.State)
+	assert.Len(t, adoptedChange.Changes, 1)
+	assert.Len(t, adoptedChange.Changes[0].Values, 2)
+
+	// The device change is stored COMPLETE and referenced by the network change
+	assert.Len(t, adoptedChange.Refs, 1)
+	deviceChanges := make(chan *devicechange.DeviceChange)
+	_, err = mgrTest.DeviceChangesStore.List(devicetype.NewVersionedID(deviceAdopted, deviceVersion1), deviceChanges)
+	assert.NoError(t, err)
+	deviceChange := <-deviceChanges
+	assert.Equal(t, adoptedChange.Refs[0].DeviceChangeID, deviceChange.ID)
+	assert.Equal(t, changetypes.State_COMPLETE, deviceChange.Status.State)
+}
+
+func TestManager_AdoptConfigInvalid(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+	ctrl := gomock.NewController(t)
+	const deviceAdopted = devicetype.ID("DeviceInvalid")
+
+	mockDeviceStateStore := mockstore.NewMockDeviceStateStore(ctrl)
+	mockDeviceStateStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]*devicechange.PathValue{}, nil).AnyTimes()
+	mgrTest.DeviceStateStore = mockDeviceStateStore
+
+	mockTarget := southboundmocks.NewMockTargetIf(ctrl)
+	southbound.NewTargetItem(devicetype.NewVersionedID(deviceAdopted, deviceVersion1), mockTarget)
+	mockTarget.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&gnmi.GetResponse{
+		Notification: []*gnmi.Notification{{
+			Update: []*gnmi.Update{{
+				Path: &gnmi.Path{},
+				Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{
+					JsonIetfVal: []byte(`{"cont1a": {"cont2a": {"leaf2a": 789}}}`),
+				}},
+			}},
+		}},
+	}, nil).Times(1)
+
+	// A configuration that does not validate against the model is not adopted
+	_, err := mgrTest.AdoptConfig(deviceAdopted, deviceVersion1, deviceTypeTd)
+	assert.True(t, errors.IsInvalid(err), "expected invalid, got %v", err)
+}
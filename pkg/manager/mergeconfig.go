@@ -0,0 +1,68 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/merge"
+	mergestore "github.com/onosproject/onos-config/pkg/store/merge"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// MergeRules returns the merge strategies that apply to a device type and version: those declared
+// by its model plugin, overridden by the rules of the merge store
+func (m *Manager) MergeRules(deviceType devicetype.Type, version devicetype.Version) ([]*mergestore.Rule, error) {
+	rules, err := m.MergeStore.List()
+	if err != nil {
+		return nil, err
+	}
+	var declared map[string]string
+	if m.ModelRegistry != nil {
+		plugin, err := m.ModelRegistry.GetPlugin(utils.ToModelName(deviceType, version))
+		if err == nil {
+			declared = plugin.MergeStrategies
+		} else if !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return merge.Rules(declared, rules, deviceType)
+}
+
+// MergeConfig applies the merge strategies to the values written to a device and to the paths
+// deleted from it, against the configuration of the device as of lastWrite. It returns the paths
+// to delete instead, which include the leaves of the replaced subtrees that are not written. The
+// given slice is not modified.
+func (m *Manager) MergeConfig(deviceID devicetype.ID, deviceType devicetype.Type, version devicetype.Version,
+	updates devicechange.TypedValueMap, removes []string, lastWrite networkchange.Revision) ([]string, error) {
+	rules, err := m.MergeRules(deviceType, version)
+	if err != nil {
+		return nil, err
+	}
+	replace := false
+	for _, rule := range rules {
+		replace = replace || rule.Strategy == mergestore.Replace
+	}
+	if !replace || len(updates) == 0 {
+		return removes, nil
+	}
+	config, err := m.DeviceStateStore.Get(devicetype.NewVersionedID(deviceID, version), lastWrite)
+	if err != nil {
+		return nil, err
+	}
+	return merge.Apply(rules, updates, append([]string{}, removes...), config)
+}
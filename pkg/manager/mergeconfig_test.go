@@ -0,0 +1,62 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	mergestore "github.com/onosproject/onos-config/pkg/store/merge"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_MergeConfig(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+	ctrl := gomock.NewController(t)
+
+	mockDeviceStateStore := mockstore.NewMockDeviceStateStore(ctrl)
+	mockDeviceStateStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]*devicechange.PathValue{
+		{Path: "/cont1a/leaf1a", Value: devicechange.NewTypedValueString("kept")},
+		{Path: test1Cont1ACont2ALeaf2A, Value: devicechange.NewTypedValueUint(12, 8)},
+		{Path: test1Cont1ACont2ALeaf2B, Value: devicechange.NewTypedValueString("replaced")},
+	}, nil).AnyTimes()
+	mgrTest.DeviceStateStore = mockDeviceStateStore
+
+	updates := devicechange.TypedValueMap{
+		test1Cont1ACont2ALeaf2A: devicechange.NewTypedValueUint(13, 8),
+	}
+
+	// The values are merged by default
+	removes, err := mgrTest.MergeConfig(device1, deviceTypeTd, deviceVersion1, updates, nil, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, removes)
+
+	// The model plugin declares cont2a replaced wholesale
+	plugin, err := mgrTest.ModelRegistry.GetPlugin(utils.ToModelName(deviceTypeTd, deviceVersion1))
+	assert.NoError(t, err)
+	plugin.MergeStrategies = map[string]string{"/cont1a/cont2a": "replace"}
+	removes, err = mgrTest.MergeConfig(device1, deviceTypeTd, deviceVersion1, updates, []string{"/cont1a/list2a[name=a]"}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/cont1a/list2a[name=a]", test1Cont1ACont2ALeaf2B}, removes)
+
+	// A rule of the store overrides the model plugin
+	assert.NoError(t, mgrTest.MergeStore.Put(&mergestore.Rule{Path: "/cont1a/cont2a", DeviceType: deviceTypeTd, Strategy: mergestore.Merge}))
+	removes, err = mgrTest.MergeConfig(device1, deviceTypeTd, deviceVersion1, updates, nil, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, removes)
+}
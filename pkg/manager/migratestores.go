@@ -0,0 +1,64 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"sort"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/change/device"
+	"github.com/onosproject/onos-config/pkg/store/schema"
+)
+
+// MigrateStores rewrites the records stored at a previous schema version at the current version.
+// Records are otherwise upgraded lazily when read, and persisted at the current version only when
+// next written. During a rolling upgrade, records are only migrated up to the highest version
+// every node reads. A dry run only counts the outdated records. Stores that keep their records in
+// memory have nothing to migrate and are skipped.
+func (m *Manager) MigrateStores(dryRun bool) ([]schema.Report, error) {
+	var reports []schema.Report
+	for _, store := range []interface{}{m.NetworkChangesStore, m.NetworkSnapshotStore, m.DeviceSnapshotStore} {
+		if migrator, ok := store.(schema.Migrator); ok {
+			storeReports, err := migrator.Migrate(dryRun)
+			if err != nil {
+				return nil, err
+			}
+			reports = append(reports, storeReports...)
+		}
+	}
+
+	if migrator, ok := m.DeviceChangesStore.(device.Migrator); ok {
+		deviceIDs := make([]devicetype.VersionedID, 0)
+		for _, info := range m.DeviceCache.GetDevices() {
+			deviceIDs = append(deviceIDs, devicetype.NewVersionedID(info.DeviceID, info.Version))
+		}
+		sort.Slice(deviceIDs, func(i, j int) bool {
+			return deviceIDs[i] < deviceIDs[j]
+		})
+		for _, deviceID := range deviceIDs {
+			report, err := migrator.MigrateDevice(deviceID, dryRun)
+			if err != nil {
+				return nil, err
+			}
+			reports = append(reports, report)
+		}
+	}
+
+	for _, report := range reports {
+		log.Infof("Schema migration of %s to %s version %d: %d scanned, %d outdated, %d migrated, %d failed (dry run: %t)",
+			report.Store, report.Kind, report.Version, report.Scanned, report.Outdated, report.Migrated, report.Failed, dryRun)
+	}
+	return reports, nil
+}
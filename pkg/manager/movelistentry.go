@@ -0,0 +1,164 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"strconv"
+	"strings"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// MoveListEntry renames the keys of a list entry of a device: every value under the entry at
+// from is moved under the entry at to, which must be an entry of the same list, in one network
+// change. The leaves of the entry holding its keys, e.g. /interfaces/interface[name=eth1]/name,
+// are written with the new keys.
+func (m *Manager) MoveListEntry(deviceID devicetype.ID, version devicetype.Version, deviceType devicetype.Type,
+	from string, to string) (*networkchange.NetworkChange, error) {
+
+	from, to, renamedKeys, err := checkMove(from, to)
+	if err != nil {
+		return nil, err
+	}
+	config, err := m.DeviceStateStore.Get(devicetype.NewVersionedID(deviceID, version), 0)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	updates := make(devicechange.TypedValueMap)
+	removes := make([]string, 0)
+	for _, value := range config {
+		if isUnder(value.Path, to) {
+			return nil, errors.NewAlreadyExists("%s already has %s", deviceID, to)
+		}
+		if !isUnder(value.Path, from) {
+			continue
+		}
+		path := to + strings.TrimPrefix(value.Path, from)
+		movedValue := value.Value
+		leaf := path[strings.LastIndex(path, "/")+1:]
+		if keys, ok := renamedKeys[leaf]; ok && value.Value.ValueToString() == keys[0] {
+			if movedValue, err = renamedKey(value.Value, keys[1]); err != nil {
+				return nil, errors.NewInvalid("the key %s of %s cannot be renamed: %v", leaf, from, err)
+			}
+		}
+		updates[path] = movedValue
+		removes = append(removes, value.Path)
+	}
+	if len(removes) == 0 {
+		return nil, errors.NewNotFound("%s has no configuration under %s", deviceID, from)
+	}
+
+	if err := m.ValidateNetworkConfig(deviceID, version, deviceType, updates, removes, 0); err != nil {
+		return nil, err
+	}
+	change, err := m.SetNetworkConfig(
+		map[devicetype.ID]devicechange.TypedValueMap{deviceID: updates},
+		map[devicetype.ID][]string{deviceID: removes},
+		map[devicetype.ID]cache.Info{deviceID: {DeviceID: deviceID, Type: deviceType, Version: version}},
+		"")
+	if err != nil {
+		return nil, err
+	}
+	m.RecordEnvironment(change, validationStrict, nil)
+	log.Infof("Moved %d values of %s from %s to %s in change %s", len(removes), deviceID, from, to, change.ID)
+	return change, nil
+}
+
+// checkMove checks that both paths are entries of the same list, without wildcards. It returns
+// them with their keys sorted, as the paths are stored, and the old and the new value of each key
+// that is renamed, by key name.
+func checkMove(from string, to string) (string, string, map[string][2]string, error) {
+	fromPath, err := parseEntry(from)
+	if err != nil {
+		return "", "", nil, err
+	}
+	toPath, err := parseEntry(to)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if len(fromPath.Elem) != len(toPath.Elem) ||
+		utils.StrPathElem(fromPath.Elem[:len(fromPath.Elem)-1]) != utils.StrPathElem(toPath.Elem[:len(toPath.Elem)-1]) {
+		return "", "", nil, errors.NewInvalid("%s and %s are not entries of the same list", from, to)
+	}
+	fromEntry := fromPath.Elem[len(fromPath.Elem)-1]
+	toEntry := toPath.Elem[len(toPath.Elem)-1]
+	if fromEntry.Name != toEntry.Name || len(fromEntry.Key) != len(toEntry.Key) {
+		return "", "", nil, errors.NewInvalid("%s and %s are not entries of the same list", from, to)
+	}
+	renamedKeys := make(map[string][2]string)
+	for key, value := range fromEntry.Key {
+		toValue, ok := toEntry.Key[key]
+		if !ok {
+			return "", "", nil, errors.NewInvalid("%s and %s are not entries of the same list", from, to)
+		} else if toValue != value {
+			renamedKeys[key] = [2]string{value, toValue}
+		}
+	}
+	if len(renamedKeys) == 0 {
+		return "", "", nil, errors.NewInvalid("%s and %s are the same entry", from, to)
+	}
+	return utils.StrPathElem(fromPath.Elem), utils.StrPathElem(toPath.Elem), renamedKeys, nil
+}
+
+// parseEntry parses the path of a list entry
+func parseEntry(path string) (*gnmi.Path, error) {
+	if !strings.HasPrefix(path, "/") || strings.Contains(path, "*") || strings.Contains(path, "...") {
+		return nil, errors.NewInvalid("%s is not the path of a list entry without wildcards", path)
+	}
+	parsed, err := utils.ParseGNMIElements(utils.SplitPath(path))
+	if err != nil {
+		return nil, errors.NewInvalid("invalid path %s: %v", path, err)
+	} else if len(parsed.Elem) == 0 || len(parsed.Elem[len(parsed.Elem)-1].Key) == 0 {
+		return nil, errors.NewInvalid("%s is not the path of a list entry", path)
+	}
+	return parsed, nil
+}
+
+// isUnder returns true if the path is the entry or one of its descendants
+func isUnder(path string, entry string) bool {
+	return path == entry || strings.HasPrefix(path, entry+"/")
+}
+
+// renamedKey returns the value of a key leaf renamed to the given value, of the same type
+func renamedKey(value *devicechange.TypedValue, name string) (*devicechange.TypedValue, error) {
+	width := devicechange.WidthUnknown
+	if len(value.TypeOpts) > 0 {
+		width = devicechange.Width(value.TypeOpts[0])
+	}
+	switch value.Type {
+	case devicechange.ValueType_STRING:
+		return devicechange.NewTypedValueString(name), nil
+	case devicechange.ValueType_INT:
+		i, err := strconv.ParseInt(name, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return devicechange.NewTypedValueInt(int(i), width), nil
+	case devicechange.ValueType_UINT:
+		u, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return devicechange.NewTypedValueUint(uint(u), width), nil
+	}
+	return nil, errors.NewInvalid("keys of type %s are not supported", value.Type)
+}
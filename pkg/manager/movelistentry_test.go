@@ -0,0 +1,76 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_MoveListEntry(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+	ctrl := gomock.NewController(t)
+
+	mockDeviceStateStore := mockstore.NewMockDeviceStateStore(ctrl)
+	mockDeviceStateStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]*devicechange.PathValue{
+		{Path: "/cont1a/leaf1a", Value: devicechange.NewTypedValueString("untouched")},
+		{Path: "/cont1a/list2a[name=first]/name", Value: devicechange.NewTypedValueString("first")},
+		{Path: "/cont1a/list2a[name=first]/tx-power", Value: devicechange.NewTypedValueUint(5, 16)},
+		{Path: "/cont1a/list2a[name=firstly]/name", Value: devicechange.NewTypedValueString("firstly")},
+		{Path: "/cont1a/list2a[name=second]/name", Value: devicechange.NewTypedValueString("second")},
+	}, nil).AnyTimes()
+	mgrTest.DeviceStateStore = mockDeviceStateStore
+
+	change, err := mgrTest.MoveListEntry(device1, deviceVersion1, deviceTypeTd,
+		"/cont1a/list2a[name=first]", "/cont1a/list2a[name=renamed]")
+	assert.NoError(t, err)
+	assert.Len(t, change.Changes, 1)
+	values := make(map[string]*devicechange.ChangeValue)
+	for _, value := range change.Changes[0].Values {
+		values[value.Path] = value
+	}
+	assert.Len(t, values, 4)
+	assert.True(t, values["/cont1a/list2a[name=first]/name"].Removed)
+	assert.True(t, values["/cont1a/list2a[name=first]/tx-power"].Removed)
+	assert.Equal(t, "renamed", values["/cont1a/list2a[name=renamed]/name"].Value.ValueToString())
+	assert.Equal(t, "5", values["/cont1a/list2a[name=renamed]/tx-power"].Value.ValueToString())
+
+	_, err = mgrTest.MoveListEntry(device1, deviceVersion1, deviceTypeTd,
+		"/cont1a/list2a[name=first]", "/cont1a/list2a[name=second]")
+	assert.True(t, errors.IsAlreadyExists(err))
+	_, err = mgrTest.MoveListEntry(device1, deviceVersion1, deviceTypeTd,
+		"/cont1a/list2a[name=third]", "/cont1a/list2a[name=fourth]")
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestManager_MoveListEntryInvalid(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+
+	for _, paths := range [][2]string{
+		{"/cont1a/list2a[name=first]", "/cont1a/list2a[name=first]"},
+		{"/cont1a/list2a[name=first]", "/cont1a/list2b[name=second]"},
+		{"/cont1a/list2a[name=*]", "/cont1a/list2a[name=second]"},
+		{"/cont1a/leaf1a", "/cont1a/leaf1b"},
+		{"cont1a/list2a[name=first]", "cont1a/list2a[name=second]"},
+	} {
+		_, err := mgrTest.MoveListEntry(device1, deviceVersion1, deviceTypeTd, paths[0], paths[1])
+		assert.True(t, errors.IsInvalid(err), "%s to %s", paths[0], paths[1])
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"strings"
+	"time"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/ownership"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ClaimPaths claims the subtree under a path prefix on each of the devices for an owner, so that
+// only that owner may change it. The claim fails, on all the devices, if the subtree overlaps one
+// another owner claimed on any of them; a subtree the owner already claimed is claimed again.
+func (m *Manager) ClaimPaths(owner string, deviceIDs []devicetype.ID, prefix string, reason string) ([]*ownership.Claim, error) {
+	if owner == "" {
+		return nil, errors.NewInvalid("no owner given")
+	}
+	if len(deviceIDs) == 0 {
+		return nil, errors.NewInvalid("no device given")
+	}
+	if err := checkClaimPrefix(prefix); err != nil {
+		return nil, err
+	}
+
+	claims := make([]*ownership.Claim, 0, len(deviceIDs))
+	created := make([]*ownership.Claim, 0, len(deviceIDs))
+	for _, deviceID := range deviceIDs {
+		if err := m.checkDevice(deviceID); err != nil {
+			return nil, err
+		}
+		existing, err := m.OwnershipStore.List(deviceID)
+		if err != nil {
+			return nil, err
+		}
+		var claimed *ownership.Claim
+		for _, claim := range existing {
+			if claim.Prefix == prefix && claim.Owner == owner {
+				claimed = claim
+			} else if claim.Owner != owner && claim.Covers(prefix, true) {
+				return nil, errors.NewAlreadyExists("%s of %s overlaps %s, claimed by '%s'", prefix, deviceID, claim.Prefix, claim.Owner)
+			}
+		}
+		if claimed != nil {
+			claims = append(claims, claimed)
+			continue
+		}
+		claims = append(claims, &ownership.Claim{
+			DeviceID: deviceID,
+			Prefix:   prefix,
+			Owner:    owner,
+			Reason:   reason,
+			Created:  time.Now(),
+		})
+		created = append(created, claims[len(claims)-1])
+	}
+
+	for i, claim := range created {
+		if err := m.OwnershipStore.Create(claim); err != nil {
+			for _, undone := range created[:i] {
+				if err := m.OwnershipStore.Delete(undone.DeviceID, undone.Prefix); err != nil {
+					log.Errorf("Unable to undo the claim of %s of %s: %v", undone.Prefix, undone.DeviceID, err)
+				}
+			}
+			return nil, err
+		}
+	}
+	return claims, nil
+}
+
+// ReleasePaths releases the claims of a subtree on each of the devices. Only the owner of a claim
+// may release it, unless forced.
+func (m *Manager) ReleasePaths(caller string, deviceIDs []devicetype.ID, prefix string, force bool) ([]*ownership.Claim, error) {
+	if len(deviceIDs) == 0 {
+		return nil, errors.NewInvalid("no device given")
+	}
+	claims := make([]*ownership.Claim, 0, len(deviceIDs))
+	for _, deviceID := range deviceIDs {
+		claim, err := m.OwnershipStore.Get(deviceID, prefix)
+		if err != nil {
+			return nil, err
+		}
+		if claim.Owner != caller && !force {
+			return nil, errors.NewForbidden("%s of %s is claimed by '%s'; only it may release the claim, unless forced", prefix, deviceID, claim.Owner)
+		}
+		claims = append(claims, claim)
+	}
+	for _, claim := range claims {
+		if err := m.OwnershipStore.Delete(claim.DeviceID, claim.Prefix); err != nil {
+			return nil, err
+		}
+	}
+	return claims, nil
+}
+
+// checkClaimPrefix checks that a claimed prefix is a path without wildcards
+func checkClaimPrefix(prefix string) error {
+	if prefix == "" || !strings.HasPrefix(prefix, "/") {
+		return errors.NewInvalid("the path prefix '%s' is not an absolute path", prefix)
+	}
+	if strings.Contains(prefix, "*") || strings.Contains(prefix, "...") {
+		return errors.NewInvalid("the path prefix %s may not have wildcards", prefix)
+	}
+	if _, err := utils.ParseGNMIElements(utils.SplitPath(prefix)); err != nil {
+		return errors.NewInvalid("the path prefix %s is not a valid path: %v", prefix, err)
+	}
+	return nil
+}
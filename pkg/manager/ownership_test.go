@@ -0,0 +1,79 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/store/ownership"
+	mockcache "github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_ClaimPaths(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+	ctrl := gomock.NewController(t)
+	mockDeviceCache := mockcache.NewMockCache(ctrl)
+	mockDeviceCache.EXPECT().GetDevicesByID(gomock.Any()).DoAndReturn(func(id devicetype.ID) []*cache.Info {
+		return []*cache.Info{{DeviceID: id, Type: deviceTypeTd, Version: deviceVersion1}}
+	}).AnyTimes()
+	mgrTest.DeviceCache = mockDeviceCache
+	mgrTest.SetOwnershipStore(ownership.NewLocalStore())
+	devices := []devicetype.ID{"device-1", "device-2"}
+
+	claims, err := mgrTest.ClaimPaths("fabric-controller", devices, "/interfaces/interface[name=eth1]", "uplinks")
+	assert.NoError(t, err)
+	assert.Len(t, claims, 2)
+	assert.Equal(t, "uplinks", claims[1].Reason)
+
+	// Claiming again is a no-op, claiming an overlapping subtree for another owner fails on all devices
+	claims, err = mgrTest.ClaimPaths("fabric-controller", devices, "/interfaces/interface[name=eth1]", "uplinks")
+	assert.NoError(t, err)
+	assert.Len(t, claims, 2)
+	_, err = mgrTest.ClaimPaths("other-controller", []devicetype.ID{"device-3", "device-2"}, "/interfaces", "")
+	assert.True(t, errors.IsAlreadyExists(err), "expected already exists, got %v", err)
+	stored, err := mgrTest.OwnershipStore.List("device-3")
+	assert.NoError(t, err)
+	assert.Empty(t, stored)
+	_, err = mgrTest.ClaimPaths("other-controller", devices, "/interfaces/interface[name=eth1]/config/mtu", "")
+	assert.True(t, errors.IsAlreadyExists(err), "expected already exists, got %v", err)
+	_, err = mgrTest.ClaimPaths("other-controller", devices, "/interfaces/interface[name=eth2]", "")
+	assert.NoError(t, err)
+
+	_, err = mgrTest.ClaimPaths("", devices, "/system", "")
+	assert.True(t, errors.IsInvalid(err))
+	_, err = mgrTest.ClaimPaths("fabric-controller", nil, "/system", "")
+	assert.True(t, errors.IsInvalid(err))
+	_, err = mgrTest.ClaimPaths("fabric-controller", devices, "/interfaces/interface[name=*]", "")
+	assert.True(t, errors.IsInvalid(err))
+	_, err = mgrTest.ClaimPaths("fabric-controller", devices, "system", "")
+	assert.True(t, errors.IsInvalid(err))
+
+	// Only the owner releases its claims, unless forced
+	_, err = mgrTest.ReleasePaths("alice", devices, "/interfaces/interface[name=eth1]", false)
+	assert.True(t, errors.IsForbidden(err), "expected forbidden, got %v", err)
+	claims, err = mgrTest.ReleasePaths("fabric-controller", devices[:1], "/interfaces/interface[name=eth1]", false)
+	assert.NoError(t, err)
+	assert.Len(t, claims, 1)
+	claims, err = mgrTest.ReleasePaths("alice", devices[1:], "/interfaces/interface[name=eth1]", true)
+	assert.NoError(t, err)
+	assert.Len(t, claims, 1)
+	_, err = mgrTest.ReleasePaths("fabric-controller", devices, "/interfaces/interface[name=eth1]", false)
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -0,0 +1,73 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"time"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/store/change/pause"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// PauseNetworkChange stops a pending network change from being pushed to more devices until it is
+// resumed. What it already applied to devices stays applied.
+func (m *Manager) PauseNetworkChange(networkChangeID networkchange.ID, user string, reason string) (*pause.Pause, error) {
+	if networkChangeID == "" {
+		return nil, errors.NewInvalid("no network change given")
+	}
+	change, err := m.NetworkChangesStore.Get(networkChangeID)
+	if err != nil {
+		return nil, err
+	} else if change == nil {
+		return nil, errors.NewNotFound("network change %s not found", networkChangeID)
+	}
+	if change.Status.Phase != changetypes.Phase_CHANGE || change.Status.State != changetypes.State_PENDING {
+		return nil, errors.NewConflict("network change %s is not in progress: %s %s", networkChangeID,
+			change.Status.Phase, change.Status.State)
+	}
+	if _, err := m.PauseStore.Get(networkChangeID); err == nil {
+		return nil, errors.NewAlreadyExists("network change %s is already paused", networkChangeID)
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+	p := &pause.Pause{
+		NetworkChangeID: networkChangeID,
+		User:            user,
+		Reason:          reason,
+		Created:         time.Now(),
+	}
+	if err := m.PauseStore.Put(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ResumeNetworkChange lets a paused network change be pushed to the devices it has not reached,
+// and returns the pause it lifted
+func (m *Manager) ResumeNetworkChange(networkChangeID networkchange.ID) (*pause.Pause, error) {
+	if networkChangeID == "" {
+		return nil, errors.NewInvalid("no network change given")
+	}
+	p, err := m.PauseStore.Get(networkChangeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.PauseStore.Delete(networkChangeID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/modelregistry"
+	"github.com/onosproject/onos-config/pkg/modelregistry/jsonvalues"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-config/pkg/utils/values"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// readThroughTimeout bounds the Get sent to a device when reading through
+const readThroughTimeout = 15 * time.Second
+
+// SetReadThrough makes Gets for paths that have no value in the stores read through to the
+// device, for devices whose configuration predates onos-config. Nothing read through is stored;
+// see AdoptConfig for making the configuration of a device its baseline.
+func (m *Manager) SetReadThrough(readThrough bool) {
+	m.readThrough = readThrough
+}
+
+// readThroughTargetConfig gets the configuration under path from the device itself. Only the
+// values of read-write paths of the model that are under path are returned. Wildcard paths
+// are not read through, as the device would interpret the wildcards its own way.
+func (m *Manager) readThroughTargetConfig(deviceID devicetype.ID, version devicetype.Version,
+	deviceType devicetype.Type, path string, pathMatcher utils.PathMatcher) ([]*devicechange.PathValue, error) {
+	if strings.Contains(path, "*") || strings.Contains(path, "...") {
+		log.Debugf("Not reading wildcard path %s through to %s", path, deviceID)
+		return nil, nil
+	}
+	plugin, err := m.ModelRegistry.GetPlugin(utils.ToModelName(deviceType, version))
+	if err != nil {
+		log.Warnf("Not reading through to %s: %v", deviceID, err)
+		return nil, nil
+	}
+	if !isReadWritePrefix(plugin, path) {
+		return nil, errors.NewInvalid("%s is not a configuration path of %s:%s", path, deviceType, version)
+	}
+
+	log.Infof("Reading through to %s for %s", deviceID, path)
+	deviceValues, err := m.getDeviceValues(deviceID, version, plugin, path, gnmi.GetRequest_CONFIG)
+	if err != nil {
+		return nil, err
+	}
+	return readThroughValues(plugin, deviceValues, pathMatcher), nil
+}
+
+// isReadWritePrefix returns true if path is a read-write path of the model, or a container of one
+func isReadWritePrefix(plugin *modelregistry.ModelPlugin, path string) bool {
+	anonymized := modelregistry.AnonymizePathIndices(path)
+	if anonymized == "/" || anonymized == "" {
+		return true
+	}
+	for rwPath := range plugin.ReadWritePaths {
+		if rwPath == anonymized || strings.HasPrefix(rwPath, anonymized+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// readThroughValues keeps the values read from a device that are under the requested path and
+// at a read-write path of its model, so that a device cannot return values anywhere else
+func readThroughValues(plugin *modelregistry.ModelPlugin, deviceValues []*devicechange.PathValue,
+	pathMatcher utils.PathMatcher) []*devicechange.PathValue {
+	values := make([]*devicechange.PathValue, 0, len(deviceValues))
+	for _, value := range deviceValues {
+		if _, ok := plugin.ReadWritePaths[modelregistry.AnonymizePathIndices(value.Path)]; !ok {
+			log.Debugf("Dropping %s read through from the device: not a read-write path", value.Path)
+			continue
+		}
+		if pathMatcher.MatchString(value.Path) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getDeviceValues sends a gNMI Get for the values of the given type under path to the device; the
+// JSON values are decomposed along the read-write paths of the model for the configuration, and
+// along its read-only paths for the state
+func (m *Manager) getDeviceValues(deviceID devicetype.ID, version devicetype.Version,
+	plugin *modelregistry.ModelPlugin, path string, dataType gnmi.GetRequest_DataType) ([]*devicechange.PathValue, error) {
+	target, err := southbound.GetTarget(devicetype.NewVersionedID(deviceID, version))
+	if err != nil {
+		return nil, errors.NewUnavailable("%v", err)
+	}
+	gnmiPath, err := utils.ParseGNMIElements(utils.SplitPath(path))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), readThroughTimeout)
+	defer cancel()
+	response, err := target.Get(ctx, &gnmi.GetRequest{
+		Path:     []*gnmi.Path{gnmiPath},
+		Type:     dataType,
+		Encoding: gnmi.Encoding_JSON_IETF,
+	})
+	if err != nil {
+		return nil, errors.NewUnavailable("gNMI Get of %s failed: %v", deviceID, err)
+	}
+
+	deviceValues := make([]*devicechange.PathValue, 0)
+	for _, notification := range response.GetNotification() {
+		for _, update := range notification.GetUpdate() {
+			updatePath := utils.StrPath(update.GetPath())
+			if prefix := notification.GetPrefix(); prefix != nil && len(prefix.GetElem()) > 0 {
+				updatePath = utils.StrPath(prefix) + updatePath
+			}
+			jsonVal := update.GetVal().GetJsonIetfVal()
+			if jsonVal == nil {
+				jsonVal = update.GetVal().GetJsonVal()
+			}
+			if jsonVal != nil {
+				jsonPath := updatePath
+				if jsonPath == "/" {
+					// The configuration of the root is decomposed from the top, as by the synchronizer
+					jsonPath = ""
+				}
+				var pathValues []*devicechange.PathValue
+				if dataType == gnmi.GetRequest_CONFIG {
+					pathValues, err = jsonvalues.DecomposeJSONWithPaths(jsonPath, jsonVal, nil, plugin.ReadWritePaths)
+				} else {
+					pathValues, err = jsonvalues.DecomposeJSONWithPaths(jsonPath, jsonVal, plugin.ReadOnlyPaths, nil)
+				}
+				if err != nil {
+					return nil, err
+				}
+				for _, pathValue := range pathValues {
+					if elem, ok := plugin.ReadWritePaths[modelregistry.AnonymizePathIndices(pathValue.Path)]; ok && !elem.IsAKey {
+						pathValue.Value = values.NormalizeValue(elem.TypeName, pathValue.GetValue())
+					}
+				}
+				deviceValues = append(deviceValues, pathValues...)
+				continue
+			}
+			var rwPath *modelregistry.ReadWritePathElem
+			if elem, ok := plugin.ReadWritePaths[modelregistry.AnonymizePathIndices(updatePath)]; ok {
+				rwPath = &elem
+			}
+			value, err := values.GnmiTypedValueToNativeType(update.GetVal(), rwPath)
+			if err != nil {
+				return nil, err
+			}
+			if rwPath != nil && !rwPath.IsAKey {
+				value = values.NormalizeValue(rwPath.TypeName, value)
+			}
+			deviceValues = append(deviceValues, &devicechange.PathValue{Path: updatePath, Value: value})
+		}
+	}
+	return deviceValues, nil
+}
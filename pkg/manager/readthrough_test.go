@@ -0,0 +1,59 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_ReadThroughPaths(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+
+	// Wildcard paths are not sent to the device
+	values, err := mgrTest.readThroughTargetConfig(device1, deviceVersion1, deviceTypeTd, "/cont1a/*",
+		utils.MatchWildcardRegexp("/cont1a/*", false))
+	assert.NoError(t, err)
+	assert.Len(t, values, 0)
+
+	// Nor are paths that are not in the model
+	_, err = mgrTest.readThroughTargetConfig(device1, deviceVersion1, deviceTypeTd, "/cont1a/nosuchleaf",
+		utils.MatchWildcardRegexp("/cont1a/nosuchleaf", false))
+	assert.True(t, errors.IsInvalid(err), "expected invalid, got %v", err)
+}
+
+func TestManager_ReadThroughValues(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+	plugin, err := mgrTest.ModelRegistry.GetPlugin("TestDevice-1.0.0")
+	assert.NoError(t, err)
+
+	assert.True(t, isReadWritePrefix(plugin, "/"))
+	assert.True(t, isReadWritePrefix(plugin, "/cont1a"))
+	assert.True(t, isReadWritePrefix(plugin, test1Cont1ACont2ALeaf2A))
+	assert.False(t, isReadWritePrefix(plugin, "/cont1"))
+
+	deviceValues := []*devicechange.PathValue{
+		{Path: test1Cont1ACont2ALeaf2A, Value: devicechange.NewTypedValueUint(12, 8)},
+		{Path: "/cont1a/leaf1a", Value: devicechange.NewTypedValueString("outside")},
+		{Path: "/cont1a/cont2a/injected", Value: devicechange.NewTypedValueString("unknown")},
+	}
+	values := readThroughValues(plugin, deviceValues, utils.MatchWildcardRegexp("/cont1a/cont2a", false))
+	assert.Len(t, values, 1)
+	assert.Equal(t, test1Cont1ACont2ALeaf2A, values[0].Path)
+}
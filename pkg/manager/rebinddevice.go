@@ -0,0 +1,70 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/grpc/status"
+)
+
+// RebindDevice binds a device to another version, and optionally another type, of model, e.g. after
+// the software of the device was upgraded. The session to the device is reopened with the new model,
+// which lifts the quarantine of the device if the device reports the models of the new one.
+// It returns the updated device and the version it was bound to before.
+func (m *Manager) RebindDevice(deviceID devicetype.ID, version devicetype.Version,
+	deviceType devicetype.Type) (*topodevice.Device, devicetype.Version, error) {
+
+	if version == "" {
+		return nil, "", errors.NewInvalid("no device version given")
+	}
+	device, err := m.DeviceStore.Get(topodevice.ID(deviceID))
+	if err != nil {
+		return nil, "", fromTopoError(err)
+	} else if device == nil {
+		return nil, "", errors.NewNotFound("device '%s' not found", deviceID)
+	}
+	if deviceType == "" {
+		deviceType = devicetype.Type(device.Type)
+	}
+
+	modelName := utils.ToModelName(deviceType, version)
+	if _, err := m.ModelRegistry.GetPlugin(modelName); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, "", errors.NewInvalid("no model %s available as a plugin to bind %s to", modelName, deviceID)
+		}
+		return nil, "", err
+	}
+
+	previous := devicetype.Version(device.Version)
+	device.Version = string(version)
+	device.Type = topodevice.Type(deviceType)
+	updated, err := m.DeviceStore.Update(device)
+	if err != nil {
+		return nil, "", fromTopoError(err)
+	}
+	log.Infof("Device %s bound to model %s instead of version %s", deviceID, modelName, previous)
+	return updated, previous, nil
+}
+
+// fromTopoError types the gRPC errors of the topo service
+func fromTopoError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return errors.FromGRPC(err)
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestManager_RebindDevice(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+	deviceStore := mgrTest.DeviceStore.(*mockstore.MockDeviceStore)
+	deviceStore.EXPECT().Get(topodevice.ID(device1)).Return(&topodevice.Device{
+		ID:      device1,
+		Type:    deviceTypeTd,
+		Version: "0.9.0",
+	}, nil).AnyTimes()
+	deviceStore.EXPECT().Get(topodevice.ID("NoSuchDevice")).Return(nil, status.Error(codes.NotFound, "not found")).AnyTimes()
+	deviceStore.EXPECT().Update(gomock.Any()).DoAndReturn(func(device *topodevice.Device) (*topodevice.Device, error) {
+		return device, nil
+	}).Times(1)
+
+	device, previous, err := mgrTest.RebindDevice(device1, deviceVersion1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "0.9.0", string(previous))
+	assert.Equal(t, deviceVersion1, device.Version)
+	assert.Equal(t, deviceTypeTd, string(device.Type))
+
+	// A device can only be bound to a model that is loaded
+	_, _, err = mgrTest.RebindDevice(device1, "2.0.0", "")
+	assert.True(t, errors.IsInvalid(err), "expected invalid, got %v", err)
+	_, _, err = mgrTest.RebindDevice(device1, "", "")
+	assert.True(t, errors.IsInvalid(err), "expected invalid, got %v", err)
+
+	_, _, err = mgrTest.RebindDevice("NoSuchDevice", deviceVersion1, "")
+	assert.True(t, errors.IsNotFound(err), "expected not found, got %v", err)
+}
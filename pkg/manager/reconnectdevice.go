@@ -0,0 +1,38 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ReconnectDevice has the session to a device recreated, which opens a new connection to the device
+// and synchronizes it again. It returns the device the session is recreated for.
+func (m *Manager) ReconnectDevice(deviceID devicetype.ID) (*topodevice.Device, error) {
+	device, err := m.DeviceStore.Get(topodevice.ID(deviceID))
+	if err != nil {
+		return nil, fromTopoError(err)
+	} else if device == nil {
+		return nil, errors.NewNotFound("device '%s' not found", deviceID)
+	}
+	log.Infof("Reconnecting device %s", deviceID)
+	m.TopoChannel <- &topodevice.ListResponse{
+		Type:   topodevice.ListResponseNONE,
+		Device: device,
+	}
+	return device, nil
+}
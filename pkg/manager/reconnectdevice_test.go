@@ -0,0 +1,52 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestManager_ReconnectDevice(t *testing.T) {
+	mgrTest, _ := setUp(t)
+	deviceStore := mgrTest.DeviceStore.(*mockstore.MockDeviceStore)
+	deviceStore.EXPECT().Get(topodevice.ID(device1)).Return(&topodevice.Device{
+		ID:      device1,
+		Type:    deviceTypeTd,
+		Version: deviceVersion1,
+	}, nil).AnyTimes()
+	deviceStore.EXPECT().Get(topodevice.ID("NoSuchDevice")).Return(nil, status.Error(codes.NotFound, "not found")).AnyTimes()
+
+	// The session manager of the running manager is left out of the test
+	topoChannel := make(chan *topodevice.ListResponse, 1)
+	mgrTest.TopoChannel = topoChannel
+
+	device, err := mgrTest.ReconnectDevice(device1)
+	assert.NoError(t, err)
+	assert.Equal(t, topodevice.ID(device1), device.ID)
+	event := <-topoChannel
+	assert.Equal(t, topodevice.ListResponseNONE, event.Type)
+	assert.Equal(t, device, event.Device)
+
+	_, err = mgrTest.ReconnectDevice("NoSuchDevice")
+	assert.True(t, errors.IsNotFound(err), "expected not found, got %v", err)
+	assert.Len(t, topoChannel, 0)
+}
@@ -0,0 +1,73 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"strings"
+
+	types "github.com/onosproject/onos-api/go/onos/config"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/store/change/push"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Rejection is the rejection of the last push of a device change by its device
+type Rejection struct {
+	DeviceChangeID devicechange.ID
+	// Phase is the phase of the rejected push
+	Phase changetypes.Phase
+	Nack  *push.Nack
+}
+
+// ListChangeRejections returns a network change and the rejections of its last pushes by its
+// devices. A rejection is no longer listed once the change is pushed to the device again.
+func (m *Manager) ListChangeRejections(networkChangeID networkchange.ID) (*networkchange.NetworkChange, []*Rejection, error) {
+	if networkChangeID == "" {
+		return nil, nil, errors.NewInvalid("no network change given")
+	}
+	change, err := m.NetworkChangesStore.Get(networkChangeID)
+	if err != nil {
+		return nil, nil, err
+	} else if change == nil {
+		return nil, nil, errors.NewNotFound("network change %s not found", networkChangeID)
+	}
+
+	rejections := make([]*Rejection, 0)
+	if m.PushStore == nil {
+		return change, rejections, nil
+	}
+	for _, deviceChange := range change.Changes {
+		id := devicechange.NewID(types.ID(change.ID), deviceChange.DeviceID, deviceChange.DeviceVersion)
+		pushed, err := m.PushStore.Get(id)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, nil, err
+		}
+		if pushed.State != push.StateRejected || pushed.Nack == nil {
+			continue
+		}
+		// The phase is the last element of the idempotency key of the push
+		phase := pushed.Key[strings.LastIndex(pushed.Key, "/")+1:]
+		rejections = append(rejections, &Rejection{
+			DeviceChangeID: id,
+			Phase:          changetypes.Phase(changetypes.Phase_value[phase]),
+			Nack:           pushed.Nack,
+		})
+	}
+	return change, rejections, nil
+}
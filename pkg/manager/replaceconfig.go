@@ -0,0 +1,107 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"sort"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/modelregistry/jsonvalues"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ReplaceDeviceConfig makes a JSON document the whole intended configuration of a device: the
+// values of the document that differ from the configuration are set, and every value of the
+// configuration that is not in the document is removed, in one network change. It returns what
+// changes, sorted by path, and the network change; no network change is made if nothing changes.
+// With dryRun, the replacement is validated but not made.
+func (m *Manager) ReplaceDeviceConfig(deviceID devicetype.ID, version devicetype.Version, deviceType devicetype.Type,
+	document []byte, dryRun bool) (*devicechange.Change, *networkchange.NetworkChange, error) {
+
+	if len(document) == 0 {
+		return nil, nil, errors.NewInvalid("no configuration given for %s", deviceID)
+	}
+	modelName := utils.ToModelName(deviceType, version)
+	plugin, err := m.ModelRegistry.GetPlugin(modelName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil, errors.NewNotFound("no model %s available as a plugin to read the configuration with", modelName)
+		}
+		return nil, nil, err
+	}
+	pathValues, err := jsonvalues.DecomposeJSONWithKeyLeaves("", document, nil, plugin.ReadWritePaths)
+	if err != nil {
+		return nil, nil, errors.NewInvalid("invalid configuration for %s: %v", deviceID, err)
+	}
+	config, err := m.DeviceStateStore.Get(devicetype.NewVersionedID(deviceID, version), 0)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, nil, err
+	}
+
+	current := make(map[string]*devicechange.TypedValue, len(config))
+	for _, value := range config {
+		current[value.Path] = value.Value
+	}
+	delta := &devicechange.Change{
+		DeviceID:      deviceID,
+		DeviceVersion: version,
+		DeviceType:    deviceType,
+		Values:        make([]*devicechange.ChangeValue, 0),
+	}
+	updates := make(devicechange.TypedValueMap)
+	for _, pathValue := range pathValues {
+		value, ok := current[pathValue.Path]
+		delete(current, pathValue.Path)
+		if ok && value.Type == pathValue.Value.Type && value.ValueToString() == pathValue.Value.ValueToString() {
+			continue
+		}
+		updates[pathValue.Path] = pathValue.Value
+		delta.Values = append(delta.Values, &devicechange.ChangeValue{Path: pathValue.Path, Value: pathValue.Value})
+	}
+	removes := make([]string, 0, len(current))
+	for path := range current {
+		removes = append(removes, path)
+		delta.Values = append(delta.Values, &devicechange.ChangeValue{Path: path, Removed: true})
+	}
+	sort.Slice(delta.Values, func(i, j int) bool {
+		return delta.Values[i].Path < delta.Values[j].Path
+	})
+	if len(delta.Values) == 0 {
+		return delta, nil, nil
+	}
+
+	if err := m.ValidateNetworkConfig(deviceID, version, deviceType, updates, removes, 0); err != nil {
+		return nil, nil, err
+	}
+	if dryRun {
+		return delta, nil, nil
+	}
+	change, err := m.SetNetworkConfig(
+		map[devicetype.ID]devicechange.TypedValueMap{deviceID: updates},
+		map[devicetype.ID][]string{deviceID: removes},
+		map[devicetype.ID]cache.Info{deviceID: {DeviceID: deviceID, Type: deviceType, Version: version}},
+		"")
+	if err != nil {
+		return nil, nil, err
+	}
+	m.RecordEnvironment(change, validationStrict, nil)
+	log.Infof("Replaced the configuration of %s: %d values set and %d removed in change %s",
+		deviceID, len(updates), len(removes), change.ID)
+	return delta, change, nil
+}
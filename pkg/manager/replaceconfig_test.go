@@ -0,0 +1,70 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_ReplaceDeviceConfig(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+	ctrl := gomock.NewController(t)
+
+	mockDeviceStateStore := mockstore.NewMockDeviceStateStore(ctrl)
+	mockDeviceStateStore.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]*devicechange.PathValue{
+		{Path: "/cont1a/leaf1a", Value: devicechange.NewTypedValueString("untouched")},
+		{Path: "/cont1a/list2a[name=first]/name", Value: devicechange.NewTypedValueString("first")},
+		{Path: "/cont1a/list2a[name=first]/tx-power", Value: devicechange.NewTypedValueUint(5, 16)},
+		{Path: "/cont1a/list2a[name=second]/name", Value: devicechange.NewTypedValueString("second")},
+	}, nil).AnyTimes()
+	mgrTest.DeviceStateStore = mockDeviceStateStore
+
+	document := []byte(`{"cont1a": {"leaf1a": "untouched", "list2a": [{"name": "first", "tx-power": 7}]}}`)
+	delta, change, err := mgrTest.ReplaceDeviceConfig(device1, deviceVersion1, deviceTypeTd, document, true)
+	assert.NoError(t, err)
+	assert.Nil(t, change)
+	assert.Len(t, delta.Values, 2)
+	assert.Equal(t, "/cont1a/list2a[name=first]/tx-power", delta.Values[0].Path)
+	assert.Equal(t, "7", delta.Values[0].Value.ValueToString())
+	assert.Equal(t, "/cont1a/list2a[name=second]/name", delta.Values[1].Path)
+	assert.True(t, delta.Values[1].Removed)
+
+	delta, change, err = mgrTest.ReplaceDeviceConfig(device1, deviceVersion1, deviceTypeTd, document, false)
+	assert.NoError(t, err)
+	assert.NotNil(t, change)
+	assert.Len(t, change.Changes, 1)
+	assert.Len(t, change.Changes[0].Values, 2)
+	assert.Len(t, delta.Values, 2)
+
+	// A document that matches the configuration changes nothing
+	delta, change, err = mgrTest.ReplaceDeviceConfig(device1, deviceVersion1, deviceTypeTd,
+		[]byte(`{"cont1a": {"leaf1a": "untouched", "list2a": [{"name": "first", "tx-power": 5}, {"name": "second"}]}}`), false)
+	assert.NoError(t, err)
+	assert.Nil(t, change)
+	assert.Len(t, delta.Values, 0)
+
+	_, _, err = mgrTest.ReplaceDeviceConfig(device1, deviceVersion1, deviceTypeTd, []byte(`{"cont1a": {"leaf1a": "too long a value"}}`), false)
+	assert.True(t, errors.IsInvalid(err))
+	_, _, err = mgrTest.ReplaceDeviceConfig(device1, deviceVersion1, deviceTypeTd, []byte(`{"cont1a": `), false)
+	assert.True(t, errors.IsInvalid(err))
+	_, _, err = mgrTest.ReplaceDeviceConfig(device1, "2.0.0", deviceTypeTd, document, false)
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -0,0 +1,83 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	networkchangectl "github.com/onosproject/onos-config/pkg/controller/change/network"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// RetryNetworkChange re-drives a network change that failed, once the issue of its devices is fixed.
+// With failedOnly, it is only pushed again to the devices on which it is not applied. The message
+// says who retried it and why. It returns the retried change and the device changes pushed again.
+func (m *Manager) RetryNetworkChange(networkChangeID networkchange.ID, failedOnly bool, message string) (*networkchange.NetworkChange, []*devicechange.DeviceChange, error) {
+	if networkChangeID == "" {
+		return nil, nil, errors.NewInvalid("no network change given")
+	}
+	change, err := m.NetworkChangesStore.Get(networkChangeID)
+	if err != nil {
+		return nil, nil, err
+	} else if change == nil {
+		return nil, nil, errors.NewNotFound("network change %s not found", networkChangeID)
+	}
+	if change.Status.Phase != changetypes.Phase_CHANGE {
+		return nil, nil, errors.NewConflict("network change %s has not failed: %s %s", networkChangeID,
+			change.Status.Phase, change.Status.State)
+	}
+	if change.Status.State != changetypes.State_FAILED &&
+		(change.Status.State != changetypes.State_PENDING || change.Status.Reason != changetypes.Reason_ERROR) {
+		return nil, nil, errors.NewConflict("network change %s has not failed: %s %s", networkChangeID,
+			change.Status.Phase, change.Status.State)
+	}
+	// Changes are applied to a device in index order, so the change must not be applied again
+	// after a later change to one of its devices
+	if err := m.checkNotSuperseded(change); err != nil {
+		return nil, nil, err
+	}
+	retried, err := networkchangectl.Retry(m.NetworkChangesStore, m.DeviceChangesStore, change, failedOnly, message)
+	if err != nil {
+		return nil, nil, err
+	}
+	return change, retried, nil
+}
+
+// checkNotSuperseded checks that no change made after a change to the same devices is in effect
+func (m *Manager) checkNotSuperseded(change *networkchange.NetworkChange) error {
+	devices := make(map[string]bool)
+	for _, deviceChange := range change.Changes {
+		devices[string(deviceChange.DeviceID)] = true
+	}
+	index := change.Index
+	for {
+		next, err := m.NetworkChangesStore.GetNext(index)
+		if errors.IsNotFound(err) || (err == nil && next == nil) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if next.Status.Phase != changetypes.Phase_ROLLBACK || next.Status.State != changetypes.State_COMPLETE {
+			for _, deviceChange := range next.Changes {
+				if devices[string(deviceChange.DeviceID)] {
+					return errors.NewConflict("network change %s cannot be retried: %s was made after it to %s",
+						change.ID, next.ID, deviceChange.DeviceID)
+				}
+			}
+		}
+		index = next.Index
+	}
+}
@@ -17,7 +17,10 @@ package manager
 import (
 	"fmt"
 	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
 	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	networkchangectl "github.com/onosproject/onos-config/pkg/controller/change/network"
+	devicechangeutils "github.com/onosproject/onos-config/pkg/store/change/device/utils"
 	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
 	"github.com/onosproject/onos-config/pkg/store/stream"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
@@ -26,44 +29,58 @@ import (
 // RollbackTargetConfig rollbacks the last change for a given configuration on the target, by setting phase to
 // rollback and state to pending.
 func (m *Manager) RollbackTargetConfig(networkChangeID networkchange.ID) error {
+	changeRollback, err := m.getRollbackChange(networkChangeID)
+	if err != nil {
+		return err
+	}
+
+	errUpdate := networkchangectl.Rollback(m.NetworkChangesStore, changeRollback, "Administratively requested rollback")
+	if errUpdate != nil {
+		return errors.NewInternal("Error on setting change %s rollback: %s", networkChangeID, errUpdate)
+	}
+	return listenForChangeNotification(m, networkChangeID)
+}
+
+// PreviewRollback computes the per device changes that rolling back the given network change would
+// send to the devices, without applying the rollback. The same checks are made as for RollbackTargetConfig.
+func (m *Manager) PreviewRollback(networkChangeID networkchange.ID) ([]*devicechange.Change, error) {
+	changeRollback, err := m.getRollbackChange(networkChangeID)
+	if err != nil {
+		return nil, err
+	}
+
+	deltas := make([]*devicechange.Change, 0, len(changeRollback.Changes))
+	for _, change := range changeRollback.Changes {
+		prevValues, err := devicechangeutils.ExtractConfigWithout(change.GetVersionedDeviceID(), networkChangeID, m.DeviceChangesStore)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.NewInternal("can't get previous config of %s for rollback of %s: %v",
+				change.DeviceID, networkChangeID, err)
+		}
+		deltas = append(deltas, devicechangeutils.ComputeRollback(change, prevValues))
+	}
+	return deltas, nil
+}
 
+// getRollbackChange gets the network change to roll back, checking that it is the last one
+func (m *Manager) getRollbackChange(networkChangeID networkchange.ID) (*networkchange.NetworkChange, error) {
 	if networkChangeID == "" {
-		return errors.NewInvalid("error on rollback. networkChangeID is empty")
+		return nil, errors.NewInvalid("error on rollback. networkChangeID is empty")
 	}
 
 	changeRollback, errGet := m.NetworkChangesStore.Get(networkChangeID)
 	if errGet != nil {
-		return errors.NewInternal("error on get change '%s' for rollback: %v", networkChangeID, errGet)
+		return nil, errors.NewInternal("error on get change '%s' for rollback: %v", networkChangeID, errGet)
 	}
 
 	if changeRollback == nil {
-		return errors.NewInternal("error on rollback. No change found for networkChangeID '%s'", networkChangeID)
+		return nil, errors.NewInternal("error on rollback. No change found for networkChangeID '%s'", networkChangeID)
 	}
 
 	//Making sure that the change is the last one
-	next, errGetNext := m.NetworkChangesStore.GetNext(changeRollback.Index)
-	if errGetNext != nil {
-		return errors.NewInternal("Error on get next change during rollback %v", errGetNext)
-	}
-	// if the error is nil and the change is nil the requested one is the last one thus we proceed.
-	// if there is a next change but the phase is different from ROLLBACK and the status is different from COMPLETE we
-	// fail the operation because there is a need to rollback the previous one.
-	if next != nil && (next.Status.Phase != changetypes.Phase_ROLLBACK ||
-		(next.Status.Phase == changetypes.Phase_ROLLBACK && next.Status.State != changetypes.State_COMPLETE)) {
-		return errors.NewInternal("change %s is not the last active on the stack of changes", networkChangeID)
-	}
-
-	changeRollback.Status.Incarnation++
-	changeRollback.Status.Phase = changetypes.Phase_ROLLBACK
-	changeRollback.Status.State = changetypes.State_PENDING
-	changeRollback.Status.Reason = changetypes.Reason_NONE
-	changeRollback.Status.Message = "Administratively requested rollback"
-
-	errUpdate := m.NetworkChangesStore.Update(changeRollback)
-	if errUpdate != nil {
-		return errors.NewInternal("Error on setting change %s rollback: %s", networkChangeID, errUpdate)
+	if err := networkchangectl.CheckLast(m.NetworkChangesStore, changeRollback); err != nil {
+		return nil, err
 	}
-	return listenForChangeNotification(m, networkChangeID)
+	return changeRollback, nil
 }
 
 func listenForChangeNotification(mgr *Manager, changeID networkchange.ID) error {
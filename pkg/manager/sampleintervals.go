@@ -0,0 +1,104 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"time"
+
+	"github.com/onosproject/onos-config/pkg/southbound/synchronizer"
+	"github.com/onosproject/onos-config/pkg/store/sampling"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// sampleIntervalRefreshInterval is how often the sample intervals are reloaded from the store, so
+// that an interval set through another node is applied by this one too
+const sampleIntervalRefreshInterval = 10 * time.Second
+
+// SetSampleInterval persists the sample interval of a subtree of the state of a device, or of its
+// whole state if no prefix is given, and applies it to the subscription of the device on this node
+func (m *Manager) SetSampleInterval(interval *sampling.Interval) error {
+	if interval.DeviceID == "" {
+		return errors.NewInvalid("no device given")
+	} else if interval.SampleInterval <= 0 {
+		return errors.NewInvalid("the sample interval must be positive")
+	} else if interval.HeartbeatInterval < 0 {
+		return errors.NewInvalid("the heartbeat interval cannot be negative")
+	}
+	if interval.Prefix == "/" {
+		interval.Prefix = ""
+	} else if interval.Prefix != "" {
+		if _, err := utils.ParseGNMIElements(utils.SplitPath(interval.Prefix)); err != nil {
+			return errors.NewInvalid("invalid prefix %s: %v", interval.Prefix, err)
+		}
+	}
+	interval.Updated = time.Now()
+	if err := m.SampleIntervalStore.Put(interval); err != nil {
+		return err
+	}
+	return m.loadSampleIntervals()
+}
+
+// ResetSampleInterval leaves the sampling of a subtree of the state of a device to the device
+// again, and returns the interval it removed
+func (m *Manager) ResetSampleInterval(deviceID string, prefix string) (*sampling.Interval, error) {
+	if prefix == "/" {
+		prefix = ""
+	}
+	interval, err := m.SampleIntervalStore.Get(deviceID, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.SampleIntervalStore.Delete(deviceID, prefix); err != nil {
+		return nil, err
+	}
+	return interval, m.loadSampleIntervals()
+}
+
+// ListSampleIntervals lists the sample intervals, of a device if one is given
+func (m *Manager) ListSampleIntervals(deviceID string) ([]*sampling.Interval, error) {
+	intervals, err := m.SampleIntervalStore.List()
+	if err != nil || deviceID == "" {
+		return intervals, err
+	}
+	deviceIntervals := make([]*sampling.Interval, 0)
+	for _, interval := range intervals {
+		if interval.DeviceID == deviceID {
+			deviceIntervals = append(deviceIntervals, interval)
+		}
+	}
+	return deviceIntervals, nil
+}
+
+// loadSampleIntervals applies the persisted sample intervals to the subscriptions of this node
+func (m *Manager) loadSampleIntervals() error {
+	intervals, err := m.SampleIntervalStore.List()
+	if err != nil {
+		return err
+	}
+	synchronizer.SetSampleIntervals(intervals)
+	return nil
+}
+
+// refreshSampleIntervals reloads the sample intervals periodically
+func (m *Manager) refreshSampleIntervals(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := m.loadSampleIntervals(); err != nil {
+			log.Warnf("Failed to reload the sample intervals: %v", err)
+		}
+	}
+}
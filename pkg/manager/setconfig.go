@@ -15,7 +15,7 @@
 package manager
 
 import (
-	"fmt"
+	"bytes"
 	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
 	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
 	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
@@ -35,6 +35,12 @@ const SetConfigAlreadyApplied = "Already applied:"
 // for the specified target (Atomix Based)
 func (m *Manager) ValidateNetworkConfig(deviceName devicetype.ID, version devicetype.Version,
 	deviceType devicetype.Type, updates devicechange.TypedValueMap, deletes []string, lastWrite networkchange.Revision) error {
+	return m.validateNetworkConfig(deviceName, version, deviceType, updates, deletes, lastWrite, false)
+}
+
+func (m *Manager) validateNetworkConfig(deviceName devicetype.ID, version devicetype.Version,
+	deviceType devicetype.Type, updates devicechange.TypedValueMap, deletes []string, lastWrite networkchange.Revision,
+	unknownPaths bool) error {
 
 	modelName := utils.ToModelName(deviceType, version)
 	deviceModelYgotPlugin, err := m.ModelRegistry.GetPlugin(modelName)
@@ -42,18 +48,40 @@ func (m *Manager) ValidateNetworkConfig(deviceName devicetype.ID, version device
 		if errors.IsNotFound(err) {
 			log.Warn("No model ", modelName, " available as a plugin")
 			if !mgr.allowUnvalidatedConfig {
-				return fmt.Errorf("no model %s available as a plugin", modelName)
+				return errors.NewNotFound("no model %s available as a plugin", modelName)
 			}
 			return nil
 		}
 		return err
 	}
 
+	if _, err := m.validatedConfig(deviceName, version, deviceModelYgotPlugin, updates, deletes, lastWrite, unknownPaths); err != nil {
+		return err
+	}
+	log.Infof("New Configuration for %s, with version %s and type %s, is Valid according to model %s",
+		deviceName, version, deviceType, modelName)
+
+	return nil
+}
+
+// validatedConfig overlays the updates and deletes on the configuration of the target, then
+// unmarshals the result in to the model of the plugin and validates it. It returns the resulting
+// configuration, sorted by path. Only the failures of the configuration to validate are Invalid.
+// With unknownPaths, the values of the paths that are not in the model are not validated, and are
+// left out of the resulting configuration.
+func (m *Manager) validatedConfig(deviceName devicetype.ID, version devicetype.Version, plugin *modelregistry.ModelPlugin,
+	updates devicechange.TypedValueMap, deletes []string, lastWrite networkchange.Revision, unknownPaths bool) ([]*devicechange.PathValue, error) {
 	configValues, err := m.DeviceStateStore.Get(devicetype.NewVersionedID(deviceName, version), lastWrite)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return overlayAndValidate(configValues, plugin, updates, deletes, unknownPaths)
+}
 
+// overlayAndValidate overlays the updates and deletes on the given configuration, then validates
+// the result as validatedConfig does
+func overlayAndValidate(configValues []*devicechange.PathValue, plugin *modelregistry.ModelPlugin,
+	updates devicechange.TypedValueMap, deletes []string, unknownPaths bool) ([]*devicechange.PathValue, error) {
 	pathValues := make(devicechange.TypedValueMap)
 	// Feed the deviceChange store contents in to map
 	for _, configValue := range configValues {
@@ -64,7 +92,7 @@ func (m *Manager) ValidateNetworkConfig(deviceName devicetype.ID, version device
 		if len(changeValue.GetBytes()) == 0 &&
 			(changeValue.GetType() == devicechange.ValueType_STRING ||
 				changeValue.GetType() == devicechange.ValueType_BYTES) {
-			return errors.NewInvalid("Empty string not allowed. Delete attribute instead. %s", changePath)
+			return nil, errors.NewInvalid("Empty string not allowed. Delete attribute instead. %s", changePath)
 		}
 		pathValues[changePath] = changeValue
 	}
@@ -75,7 +103,7 @@ func (m *Manager) ValidateNetworkConfig(deviceName devicetype.ID, version device
 			deletePath = modelregistry.AddMissingIndexName(deletePath)[0]
 			deletePathAnonIdx = modelregistry.AnonymizePathIndices(deletePath)
 		}
-		modelEntry, ok := deviceModelYgotPlugin.ReadWritePaths[deletePathAnonIdx]
+		modelEntry, ok := plugin.ReadWritePaths[deletePathAnonIdx]
 		if ok && modelEntry.IsAKey { // Then delete all children
 			deletePathRoot := deletePath[:strings.LastIndex(deletePath, "/")]
 			for path := range pathValues {
@@ -94,6 +122,10 @@ func (m *Manager) ValidateNetworkConfig(deviceName devicetype.ID, version device
 		}
 	}
 
+	if unknownPaths {
+		pathValues = modeledValues(pathValues, plugin.ReadWritePaths)
+	}
+
 	configValues = make([]*devicechange.PathValue, 0, len(pathValues))
 	for path, value := range pathValues {
 		configValues = append(configValues, &devicechange.PathValue{
@@ -108,22 +140,62 @@ func (m *Manager) ValidateNetworkConfig(deviceName devicetype.ID, version device
 	jsonTree, err := store.BuildTree(configValues, true)
 	if err != nil {
 		log.Error("Error building JSON tree from Config Values ", err, jsonTree)
-		return err
+		return nil, err
 	}
 
-	ygotModel, err := deviceModelYgotPlugin.Model.Unmarshaler()(jsonTree)
+	ygotModel, err := plugin.Model.Unmarshaler()(jsonTree)
 	if err != nil {
 		log.Infof("Unmarshalling during validation failed. JSON tree %v", jsonTree)
-		return fmt.Errorf("unmarshaller error: %v", err)
+		return nil, errors.NewInvalid("unmarshaller error: %v", err)
 	}
-	err = deviceModelYgotPlugin.Model.Validator()(ygotModel)
+	err = plugin.Model.Validator()(ygotModel)
 	if err != nil {
-		return fmt.Errorf("validation error %s", err.Error())
+		return nil, validationError(err, updates, configValues)
 	}
-	log.Infof("New Configuration for %s, with version %s and type %s, is Valid according to model %s",
-		deviceName, version, deviceType, modelName)
+	return configValues, nil
+}
 
-	return nil
+// IsNoOpNetworkConfig returns true if the given updates and deletes would leave the intended
+// configuration of every target as it is: every updated path already has the value, and no
+// deleted path, nor any path beneath it, has a value.
+func (m *Manager) IsNoOpNetworkConfig(targetUpdates map[devicetype.ID]devicechange.TypedValueMap,
+	targetRemoves map[devicetype.ID][]string, deviceInfo map[devicetype.ID]cache.Info, lastWrite networkchange.Revision) (bool, error) {
+	for target, info := range deviceInfo {
+		configValues, err := m.DeviceStateStore.Get(devicetype.NewVersionedID(target, info.Version), lastWrite)
+		if err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+		current := make(devicechange.TypedValueMap)
+		for _, configValue := range configValues {
+			current[configValue.Path] = configValue.Value
+		}
+		for path, value := range targetUpdates[target] {
+			if !sameValue(current[path], value) {
+				return false, nil
+			}
+		}
+		for _, deletePath := range targetRemoves[target] {
+			for path := range current {
+				if path == deletePath || strings.HasPrefix(path, deletePath+"/") {
+					return false, nil
+				}
+			}
+		}
+	}
+	return true, nil
+}
+
+// sameValue returns true if both values are set and equal
+func sameValue(a *devicechange.TypedValue, b *devicechange.TypedValue) bool {
+	if a == nil || b == nil || a.Type != b.Type || !bytes.Equal(a.Bytes, b.Bytes) || len(a.TypeOpts) != len(b.TypeOpts) {
+		return false
+	}
+	for i := range a.TypeOpts {
+		if a.TypeOpts[i] != b.TypeOpts[i] {
+			return false
+		}
+	}
+	return true
 }
 
 // SetNetworkConfig creates and stores a new netork config for the given updates and deletes and targets
@@ -0,0 +1,77 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	"github.com/onosproject/onos-config/pkg/modelregistry"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// SimulateChange applies the given updates and deletes to an in-memory copy of the latest
+// configuration of the target and returns the resulting configuration, validated against the
+// model of the device and sorted by path. Nothing is stored, and unlike ValidateNetworkConfig a
+// model plugin is required even when unvalidated configuration is allowed. The error is Invalid
+// only if the resulting configuration does not validate.
+func (m *Manager) SimulateChange(deviceName devicetype.ID, version devicetype.Version,
+	deviceType devicetype.Type, updates devicechange.TypedValueMap, deletes []string) ([]*devicechange.PathValue, error) {
+
+	plugin, err := m.simulationPlugin(deviceType, version)
+	if err != nil {
+		return nil, err
+	}
+	config, err := m.validatedConfig(deviceName, version, plugin, updates, deletes, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Simulated change on %s, with version %s and type %s, is Valid according to model %s",
+		deviceName, version, deviceType, utils.ToModelName(deviceType, version))
+	return config, nil
+}
+
+// SimulateChangeOnSnapshot applies the given updates and deletes to an in-memory copy of the
+// configuration of a device captured by a snapshot, e.g. to tell whether a change would have been
+// valid at the time of the snapshot, and validates the result as SimulateChange does. The
+// configuration is validated against the model the device had in the snapshot.
+func (m *Manager) SimulateChangeOnSnapshot(snapshot *devicesnapshot.Snapshot, updates devicechange.TypedValueMap,
+	deletes []string) ([]*devicechange.PathValue, error) {
+	plugin, err := m.simulationPlugin(snapshot.DeviceType, snapshot.DeviceVersion)
+	if err != nil {
+		return nil, err
+	}
+	config, err := overlayAndValidate(snapshot.Values, plugin, updates, deletes, false)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Simulated change on %s as of snapshot %s, with version %s and type %s, is Valid according to model %s",
+		snapshot.DeviceID, snapshot.SnapshotID, snapshot.DeviceVersion, snapshot.DeviceType, utils.ToModelName(snapshot.DeviceType, snapshot.DeviceVersion))
+	return config, nil
+}
+
+// simulationPlugin returns the plugin of the model changes are simulated against
+func (m *Manager) simulationPlugin(deviceType devicetype.Type, version devicetype.Version) (*modelregistry.ModelPlugin, error) {
+	modelName := utils.ToModelName(deviceType, version)
+	plugin, err := m.ModelRegistry.GetPlugin(modelName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NewNotFound("no model %s available as a plugin to simulate the change on", modelName)
+		}
+		return nil, err
+	}
+	return plugin, nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"testing"
+
+	td1 "github.com/onosproject/config-models/modelplugin/testdevice-1.0.0/testdevice_1_0_0"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	configmodel "github.com/onosproject/onos-config-model/pkg/model"
+	"github.com/onosproject/onos-config/pkg/modelregistry"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/stretchr/testify/assert"
+)
+
+// testDeviceModel is the model of TestDevice 1.0.0, without the plugin loading machinery
+type testDeviceModel struct{}
+
+func (m testDeviceModel) Info() configmodel.ModelInfo {
+	return configmodel.ModelInfo{Name: deviceTypeTd, Version: deviceVersion1}
+}
+
+func (m testDeviceModel) Data() []*gnmi.ModelData {
+	return nil
+}
+
+func (m testDeviceModel) Schema() (map[string]*yang.Entry, error) {
+	return td1.UnzipSchema()
+}
+
+func (m testDeviceModel) GetStateMode() configmodel.GetStateMode {
+	return configmodel.GetStateNone
+}
+
+func (m testDeviceModel) Unmarshaler() configmodel.Unmarshaler {
+	return func(bytes []byte) (*ygot.ValidatedGoStruct, error) {
+		device := &td1.Device{}
+		vgs := ygot.ValidatedGoStruct(device)
+		if err := td1.Unmarshal(bytes, device); err != nil {
+			return nil, err
+		}
+		return &vgs, nil
+	}
+}
+
+func (m testDeviceModel) Validator() configmodel.Validator {
+	return func(model *ygot.ValidatedGoStruct, opts ...ygot.ValidationOption) error {
+		device, ok := (*model).(*td1.Device)
+		if !ok {
+			return fmt.Errorf("unable to convert model in to testdevice_1_0_0")
+		}
+		return device.Validate()
+	}
+}
+
+func setUpSimulation(t *testing.T) *Manager {
+	mgrTest, _ := setUp(t)
+	schema, err := td1.UnzipSchema()
+	assert.NoError(t, err)
+	_, rwPaths := modelregistry.ExtractPaths(schema["Device"], yang.TSUnset, "", "")
+	plugin := &modelregistry.ModelPlugin{
+		Info:           testDeviceModel{}.Info(),
+		Model:          testDeviceModel{},
+		ReadWritePaths: rwPaths,
+	}
+	registry, err := modelregistry.NewModelRegistry(modelregistry.Config{
+		ModPath:      "test/data/" + t.Name() + "/mod",
+		RegistryPath: "test/data/" + t.Name() + "/registry",
+		PluginPath:   "test/data/" + t.Name() + "/plugins",
+		ModTarget:    "github.com/onosproject/onos-config@master",
+	}, plugin)
+	assert.NoError(t, err)
+	mgrTest.ModelRegistry = registry
+	return mgrTest
+}
+
+func TestManager_SimulateChange(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+
+	updates := make(devicechange.TypedValueMap)
+	updates[test1Cont1ACont2ALeaf2A] = devicechange.NewTypedValueUint(12, 8)
+	updates["/cont1a/leaf1a"] = devicechange.NewTypedValueString("simulated")
+	config, err := mgrTest.SimulateChange(device1, deviceVersion1, deviceTypeTd, updates, nil)
+	assert.NoError(t, err)
+	assert.Len(t, config, 2)
+	assert.Equal(t, test1Cont1ACont2ALeaf2A, config[0].Path)
+	assert.Equal(t, "12", config[0].Value.ValueToString())
+	assert.Equal(t, "/cont1a/leaf1a", config[1].Path)
+
+	config, err = mgrTest.SimulateChange(device1, deviceVersion1, deviceTypeTd, nil, []string{test1Cont1ACont2ALeaf2A})
+	assert.NoError(t, err)
+	assert.Len(t, config, 0)
+}
+
+func TestManager_SimulateChangeInvalid(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+
+	updates := make(devicechange.TypedValueMap)
+	updates[test1Cont1ACont2ALeaf2A] = devicechange.NewTypedValueUint(valueLeaf2A789, 16)
+	_, err := mgrTest.SimulateChange(device1, deviceVersion1, deviceTypeTd, updates, nil)
+	assert.True(t, errors.IsInvalid(err), "expected invalid, got %v", err)
+
+	updates = make(devicechange.TypedValueMap)
+	updates["/cont1a/leaf1a"] = devicechange.NewTypedValueString("")
+	_, err = mgrTest.SimulateChange(device1, deviceVersion1, deviceTypeTd, updates, nil)
+	assert.True(t, errors.IsInvalid(err), "expected invalid, got %v", err)
+}
+
+func TestManager_SimulateChangeUnknownDevice(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+
+	updates := make(devicechange.TypedValueMap)
+	updates["/cont1a/leaf1a"] = devicechange.NewTypedValueString("simulated")
+	// The failure to read the configuration of the device is not a validation failure
+	_, err := mgrTest.SimulateChange("NoSuchDevice", deviceVersion1, deviceTypeTd, updates, nil)
+	assert.True(t, errors.IsNotFound(err), "expected not found, got %v", err)
+}
+
+func TestManager_SimulateChangeOnSnapshot(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+
+	// The change is applied to the configuration of the snapshot, not to the current one
+	snapshot := &devicesnapshot.Snapshot{
+		DeviceID:      device1,
+		DeviceVersion: deviceVersion1,
+		DeviceType:    deviceTypeTd,
+		SnapshotID:    "snapshot-1",
+		Values: []*devicechange.PathValue{
+			{Path: "/cont1a/leaf1a", Value: devicechange.NewTypedValueString("last-week")},
+		},
+	}
+	updates := make(devicechange.TypedValueMap)
+	updates[test1Cont1ACont2ALeaf2A] = devicechange.NewTypedValueUint(12, 8)
+	config, err := mgrTest.SimulateChangeOnSnapshot(snapshot, updates, nil)
+	assert.NoError(t, err)
+	assert.Len(t, config, 2)
+	assert.Equal(t, test1Cont1ACont2ALeaf2A, config[0].Path)
+	assert.Equal(t, "/cont1a/leaf1a", config[1].Path)
+	assert.Equal(t, "last-week", config[1].Value.ValueToString())
+
+	config, err = mgrTest.SimulateChangeOnSnapshot(snapshot, nil, []string{"/cont1a/leaf1a"})
+	assert.NoError(t, err)
+	assert.Len(t, config, 0)
+
+	updates[test1Cont1ACont2ALeaf2A] = devicechange.NewTypedValueUint(valueLeaf2A789, 16)
+	_, err = mgrTest.SimulateChangeOnSnapshot(snapshot, updates, nil)
+	assert.True(t, errors.IsInvalid(err), "expected invalid, got %v", err)
+
+	// The model the device had in the snapshot must be available
+	snapshot.DeviceVersion = "0.1.0"
+	_, err = mgrTest.SimulateChangeOnSnapshot(snapshot, nil, []string{"/cont1a/leaf1a"})
+	assert.True(t, errors.IsNotFound(err), "expected not found, got %v", err)
+}
@@ -0,0 +1,146 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"sort"
+
+	types "github.com/onosproject/onos-api/go/onos/config"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-api/go/onos/config/snapshot"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	networksnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/network"
+	devicesnapstore "github.com/onosproject/onos-config/pkg/store/snapshot/device"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ListSnapshotDevices returns a network snapshot and the device snapshots it is made of
+func (m *Manager) ListSnapshotDevices(snapshotID networksnapshot.ID) (*networksnapshot.NetworkSnapshot, []*devicesnapshot.DeviceSnapshot, error) {
+	networkSnapshot, err := m.NetworkSnapshotStore.Get(snapshotID)
+	if err != nil {
+		return nil, nil, err
+	}
+	deviceSnapshots := make([]*devicesnapshot.DeviceSnapshot, 0, len(networkSnapshot.Refs))
+	for _, ref := range networkSnapshot.Refs {
+		deviceSnapshot, err := m.DeviceSnapshotStore.Get(ref.DeviceSnapshotID)
+		if err != nil {
+			return nil, nil, err
+		}
+		deviceSnapshots = append(deviceSnapshots, deviceSnapshot)
+	}
+	sort.Slice(deviceSnapshots, func(i, j int) bool {
+		return deviceSnapshots[i].ID < deviceSnapshots[j].ID
+	})
+	return networkSnapshot, deviceSnapshots, nil
+}
+
+// GetSnapshotValues returns the values a network snapshot captured for a device, sorted by path.
+// The version may be omitted if the snapshot holds a single version of the device.
+//
+// The values of a snapshot are read from the chain of differential snapshots of the device as long
+// as it reaches back to the snapshot, and from the values archived when the chain was replaced by a
+// later full snapshot otherwise.
+func (m *Manager) GetSnapshotValues(snapshotID networksnapshot.ID, deviceID devicetype.ID, version devicetype.Version) (*devicesnapshot.Snapshot, error) {
+	networkSnapshot, deviceSnapshots, err := m.ListSnapshotDevices(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	var deviceSnapshot *devicesnapshot.DeviceSnapshot
+	for _, candidate := range deviceSnapshots {
+		if candidate.DeviceID != deviceID || (version != "" && candidate.DeviceVersion != version) {
+			continue
+		} else if deviceSnapshot != nil {
+			return nil, errors.NewInvalid("snapshot %s holds several versions of device %s, one must be given", snapshotID, deviceID)
+		}
+		deviceSnapshot = candidate
+	}
+	if deviceSnapshot == nil {
+		return nil, errors.NewNotFound("device %s is not in snapshot %s", deviceID, snapshotID)
+	}
+	if deviceSnapshot.Status.Phase == snapshot.Phase_MARK && deviceSnapshot.Status.State != snapshot.State_COMPLETE {
+		return nil, errors.NewUnavailable("the snapshot of device %s is still being taken", deviceID)
+	}
+
+	chain, err := m.DeviceSnapshotStore.LoadChain(deviceSnapshot.GetVersionedDeviceID())
+	if errors.IsNotFound(err) {
+		// The device had no change to capture
+		return &devicesnapshot.Snapshot{
+			ID:            devicesnapshot.ID(deviceSnapshot.DeviceID),
+			DeviceID:      deviceSnapshot.DeviceID,
+			DeviceVersion: deviceSnapshot.DeviceVersion,
+			DeviceType:    deviceSnapshot.DeviceType,
+			SnapshotID:    deviceSnapshot.ID,
+		}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Values are only stored when a snapshot captures new changes, so the values of this snapshot
+	// are those of the last entry of the chain stored by this snapshot or an earlier one
+	var values *devicesnapshot.Snapshot
+	for _, entry := range chain {
+		if entry.SnapshotID != deviceSnapshot.ID {
+			entrySnapshot, err := m.DeviceSnapshotStore.Get(entry.SnapshotID)
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, err
+			}
+			if entrySnapshot == nil || entrySnapshot.NetworkSnapshot.Index > types.Index(networkSnapshot.Index) {
+				break
+			}
+		}
+		values = devicesnapstore.ApplyDelta(values, entry)
+		if entry.SnapshotID == deviceSnapshot.ID {
+			break
+		}
+	}
+	if values == nil {
+		return m.getArchivedValues(networkSnapshot, deviceSnapshot)
+	}
+	return values, nil
+}
+
+// getArchivedValues returns the archived values of a device snapshot. A snapshot that captured no
+// new change stored no values of its own, so those of the last earlier snapshot of the device that
+// did are returned.
+func (m *Manager) getArchivedValues(networkSnapshot *networksnapshot.NetworkSnapshot, deviceSnapshot *devicesnapshot.DeviceSnapshot) (*devicesnapshot.Snapshot, error) {
+	ch := make(chan *devicesnapshot.DeviceSnapshot)
+	ctx, err := m.DeviceSnapshotStore.List(ch)
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Close()
+
+	candidates := []*devicesnapshot.DeviceSnapshot{deviceSnapshot}
+	for candidate := range ch {
+		if candidate.ID != deviceSnapshot.ID && candidate.GetVersionedDeviceID() == deviceSnapshot.GetVersionedDeviceID() &&
+			candidate.NetworkSnapshot.Index < types.Index(networkSnapshot.Index) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	sort.SliceStable(candidates[1:], func(i, j int) bool {
+		return candidates[i+1].NetworkSnapshot.Index > candidates[j+1].NetworkSnapshot.Index
+	})
+	for _, candidate := range candidates {
+		values, err := m.DeviceSnapshotStore.LoadArchived(candidate.ID)
+		if err == nil {
+			values.SnapshotID = deviceSnapshot.ID
+			return values, nil
+		} else if !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return nil, errors.NewNotFound("the values of device %s in snapshot %s are no longer kept",
+		deviceSnapshot.DeviceID, networkSnapshot.ID)
+}
@@ -0,0 +1,31 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/transform"
+)
+
+// TransformConfig applies the transformation rules to the values written to a device of the given
+// type, and returns the values to validate and store instead. The given map is not modified.
+func (m *Manager) TransformConfig(deviceType devicetype.Type, updates devicechange.TypedValueMap) (devicechange.TypedValueMap, error) {
+	rules, err := m.TransformStore.List()
+	if err != nil {
+		return nil, err
+	}
+	return transform.Apply(rules, deviceType, updates)
+}
@@ -0,0 +1,124 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"time"
+
+	configcontroller "github.com/onosproject/onos-config/pkg/controller"
+	"github.com/onosproject/onos-config/pkg/store/tuning"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// tuningRefreshInterval is how often the tuning of the controllers is reloaded from the store, so
+// that a controller tuned through another node is tuned on this one too
+const tuningRefreshInterval = 10 * time.Second
+
+// TuneController persists the tuning of a controller and applies it to the controller of this
+// node. Zero parameters keep the defaults of the controller.
+func (m *Manager) TuneController(t *tuning.Tuning) error {
+	if err := checkController(t.Controller); err != nil {
+		return err
+	}
+	if err := controllerTuning(t).Validate(); err != nil {
+		return err
+	}
+	t.Updated = time.Now()
+	if err := m.TuningStore.Put(t); err != nil {
+		return err
+	}
+	configcontroller.SetTuning(t.Controller, controllerTuning(t))
+	return nil
+}
+
+// ResetControllerTuning restores the defaults of a controller, and returns the tuning it removed
+func (m *Manager) ResetControllerTuning(name string) (*tuning.Tuning, error) {
+	if err := checkController(name); err != nil {
+		return nil, err
+	}
+	t, err := m.TuningStore.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.TuningStore.Delete(name); err != nil {
+		return nil, err
+	}
+	configcontroller.SetTuning(name, configcontroller.Tuning{})
+	return t, nil
+}
+
+// GetControllerTuning returns the tuning of a controller; the tuning of an untuned controller
+// has only its name
+func (m *Manager) GetControllerTuning(name string) (*tuning.Tuning, error) {
+	if err := checkController(name); err != nil {
+		return nil, err
+	}
+	t, err := m.TuningStore.Get(name)
+	if errors.IsNotFound(err) {
+		return &tuning.Tuning{Controller: name}, nil
+	}
+	return t, err
+}
+
+// loadTuning applies the persisted tuning to the controllers of this node, restoring the defaults
+// of the controllers that are no longer tuned
+func (m *Manager) loadTuning() error {
+	tunings, err := m.TuningStore.List()
+	if err != nil {
+		return err
+	}
+	tuned := make(map[string]configcontroller.Tuning)
+	for _, t := range tunings {
+		tuned[t.Controller] = controllerTuning(t)
+	}
+	for _, queue := range configcontroller.Queues() {
+		if configcontroller.GetTuning(queue.Name()) != tuned[queue.Name()] {
+			configcontroller.SetTuning(queue.Name(), tuned[queue.Name()])
+		}
+	}
+	return nil
+}
+
+// refreshTuning reloads the tuning of the controllers periodically
+func (m *Manager) refreshTuning(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := m.loadTuning(); err != nil {
+			log.Warnf("Failed to reload the tuning of the controllers: %v", err)
+		}
+	}
+}
+
+func checkController(name string) error {
+	if name == "" {
+		return errors.NewInvalid("no controller given")
+	}
+	for _, queue := range configcontroller.Queues() {
+		if queue.Name() == name {
+			return nil
+		}
+	}
+	return errors.NewNotFound("controller %s not found", name)
+}
+
+func controllerTuning(t *tuning.Tuning) configcontroller.Tuning {
+	return configcontroller.Tuning{
+		BatchWindow:   t.BatchWindow,
+		Parallelism:   t.Parallelism,
+		RetryStep:     t.RetryStep,
+		MaxRetryDelay: t.MaxRetryDelay,
+	}
+}
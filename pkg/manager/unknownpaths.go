@@ -0,0 +1,66 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/modelregistry"
+	"github.com/onosproject/onos-config/pkg/utils"
+)
+
+// AllowsUnknownPaths returns whether the configuration of a device may hold paths that are not in
+// its model, e.g. vendor extensions not modeled yet, as set by the allow-unknown-paths label of
+// its topo entity. It is false for a device that is not in topo.
+func (m *Manager) AllowsUnknownPaths(deviceID devicetype.ID) bool {
+	device, err := m.DeviceStore.Get(topodevice.ID(deviceID))
+	if err != nil || device == nil {
+		return false
+	}
+	return device.AllowUnknownPaths
+}
+
+// ExpandPath returns the paths of the model of a device type and version at or under a path with
+// wildcards; it fails if the model plugin is not loaded
+func (m *Manager) ExpandPath(deviceType devicetype.Type, version devicetype.Version, path string) ([]string, error) {
+	plugin, err := m.ModelRegistry.GetPlugin(utils.ToModelName(deviceType, version))
+	if err != nil {
+		return nil, err
+	}
+	return plugin.ExpandPath(path)
+}
+
+// ValidateModeledNetworkConfig validates the given updates and deletes like ValidateNetworkConfig,
+// for a device that allows unknown paths: the paths that are not in the model of the device, in the
+// updates or already in its configuration, are not validated.
+func (m *Manager) ValidateModeledNetworkConfig(deviceName devicetype.ID, version devicetype.Version,
+	deviceType devicetype.Type, updates devicechange.TypedValueMap, deletes []string, lastWrite networkchange.Revision) error {
+	return m.validateNetworkConfig(deviceName, version, deviceType, updates, deletes, lastWrite, true)
+}
+
+// modeledValues returns the values whose paths are in the model
+func modeledValues(pathValues devicechange.TypedValueMap, rwPaths modelregistry.ReadWritePathMap) devicechange.TypedValueMap {
+	modeled := make(devicechange.TypedValueMap)
+	for path, value := range pathValues {
+		if _, ok := rwPaths[modelregistry.AnonymizePathIndices(path)]; ok {
+			modeled[path] = value
+		} else {
+			log.Infof("Not validating %s: it is not in the model", path)
+		}
+	}
+	return modeled
+}
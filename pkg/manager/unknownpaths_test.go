@@ -0,0 +1,42 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_ValidateModeledNetworkConfig(t *testing.T) {
+	mgrTest := setUpSimulation(t)
+
+	updates := make(devicechange.TypedValueMap)
+	updates[test1Cont1ACont2ALeaf2A] = devicechange.NewTypedValueUint(12, 8)
+	updates["/cont1a/leaf1a"] = devicechange.NewTypedValueString("modeled")
+	updates["/cont1a/vendor-leaf"] = devicechange.NewTypedValueUint(9000, 32)
+	err := mgrTest.ValidateNetworkConfig(device1, deviceVersion1, deviceTypeTd, updates, nil, 0)
+	assert.True(t, errors.IsInvalid(err), "expected invalid, got %v", err)
+
+	err = mgrTest.ValidateModeledNetworkConfig(device1, deviceVersion1, deviceTypeTd, updates, nil, 0)
+	assert.NoError(t, err)
+
+	// The paths in the model are still validated
+	updates[test1Cont1ACont2ALeaf2A] = devicechange.NewTypedValueUint(valueLeaf2A789, 16)
+	err = mgrTest.ValidateModeledNetworkConfig(device1, deviceVersion1, deviceTypeTd, updates, nil, 0)
+	assert.True(t, errors.IsInvalid(err), "expected invalid, got %v", err)
+}
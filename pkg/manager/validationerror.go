@@ -0,0 +1,125 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-config/pkg/modelregistry"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/ygot/util"
+)
+
+// ygot names the schema path of the offending element e.g. "schema path /device/cont1a/list4/id",
+// or only its name e.g. `schema "leaf2a"`, and gives its value in various forms
+var (
+	rSchemaPath = regexp.MustCompile(`schema(?: path)? (/[^\s,]+)`)
+	rSchemaName = regexp.MustCompile(`(?:schema "([^"]+)"|for schema ([^\s,]+))`)
+	rValue      = regexp.MustCompile(`(?:value (?:with path \S+ from field \S+ value )?([^\s,]+)|^(?:schema "[^"]+": )?"([^"]*)")`)
+)
+
+// validationError annotates the errors of a configuration failing to validate against its model
+// with the gNMI paths of the values they are about. ygot tells the fields of its structs and the
+// schema paths of the model, without the keys of the list entries the values are in, which is of
+// little help to a client that wrote many of them. The updates of the request are searched first,
+// then the rest of the configuration, including the key leaves implied by the paths.
+func validationError(err error, updates devicechange.TypedValueMap, configValues []*devicechange.PathValue) error {
+	updateValues := make([]*devicechange.PathValue, 0, len(updates))
+	for path, value := range updates {
+		updateValues = append(updateValues, &devicechange.PathValue{Path: path, Value: value})
+	}
+	sort.Slice(updateValues, func(i, j int) bool {
+		return updateValues[i].Path < updateValues[j].Path
+	})
+
+	var errs []error
+	if ygotErrs, ok := err.(util.Errors); ok {
+		errs = ygotErrs
+	} else {
+		errs = []error{err}
+	}
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		if e == nil {
+			continue
+		}
+		paths := offendingPaths(e.Error(), updateValues)
+		if len(paths) == 0 {
+			paths = offendingPaths(e.Error(), configValues)
+		}
+		if len(paths) > 0 {
+			messages = append(messages, fmt.Sprintf("at %s: %s", strings.Join(paths, " "), e.Error()))
+		} else {
+			messages = append(messages, e.Error())
+		}
+	}
+	return errors.NewInvalid("validation error %s", strings.Join(messages, ", "))
+}
+
+// offendingPaths gives the paths of the values that the message of a ygot error is about
+func offendingPaths(message string, pathValues []*devicechange.PathValue) []string {
+	var schemaPath, schemaName, value string
+	if m := rSchemaPath.FindStringSubmatch(message); m != nil {
+		// The schema path starts with the root of the model e.g. /device
+		schemaPath = m[1]
+		if slash := strings.Index(schemaPath[1:], "/"); slash >= 0 {
+			schemaPath = schemaPath[slash+1:]
+		}
+	} else if m := rSchemaName.FindStringSubmatch(message); m != nil {
+		schemaName = m[1] + m[2]
+	} else {
+		return nil
+	}
+	if m := rValue.FindStringSubmatch(message); m != nil {
+		value = m[1] + m[2]
+	}
+
+	paths := make([]string, 0)
+	found := make(map[string]bool)
+	for _, pathValue := range pathValues {
+		candidates := listKeys(pathValue.Path)
+		candidates[pathValue.Path] = pathValue.GetValue().ValueToString()
+		for path, pathValueString := range candidates {
+			if found[path] || value != "" && pathValueString != value {
+				continue
+			}
+			if schemaPath != "" && modelregistry.RemovePathIndices(path) == schemaPath ||
+				schemaName != "" && path[strings.LastIndex(path, "/")+1:] == schemaName {
+				found[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// listKeys gives the key leaves of the list entries along a path, with their values
+func listKeys(path string) map[string]string {
+	keys := make(map[string]string)
+	elems := utils.SplitPath(path)
+	for i, elem := range elems[:len(elems)-1] {
+		indexNames, indexValues := modelregistry.ExtractIndexNames(elem)
+		for j, indexName := range indexNames {
+			keys[fmt.Sprintf("/%s/%s", strings.Join(elems[:i+1], "/"), indexName)] = indexValues[j]
+		}
+	}
+	return keys
+}
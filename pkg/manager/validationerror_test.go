@@ -0,0 +1,58 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/ygot/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validationError(t *testing.T) {
+	updates := devicechange.TypedValueMap{
+		"/cont1a/cont2a/leaf2a":               devicechange.NewTypedValueUint(150, 8),
+		"/cont1a/list4[id=second]/leaf4b":     devicechange.NewTypedValueString("4b"),
+		"/cont1a/list2a[name=first]/tx-power": devicechange.NewTypedValueUint(5, 16),
+	}
+	configValues := []*devicechange.PathValue{
+		{Path: "/cont1a/list2a[name=first]/tx-power", Value: devicechange.NewTypedValueUint(5, 16)},
+		{Path: "/cont1a/list2a[name=second]/tx-power", Value: devicechange.NewTypedValueUint(5, 16)},
+		{Path: "/cont1a/leaf1a", Value: devicechange.NewTypedValueString("test val")},
+	}
+
+	err := validationError(util.Errors{
+		fmt.Errorf(`schema "leaf2a": unsigned integer value 150 is outside specified ranges`),
+		fmt.Errorf("field name Id value second (string ptr) schema path /device/cont1a/list4/id has leafref path /cont1a/list2a/name not equal to any target nodes"),
+	}, updates, configValues)
+	assert.True(t, errors.IsInvalid(err))
+	assert.Equal(t, `validation error at /cont1a/cont2a/leaf2a: schema "leaf2a": unsigned integer value 150 is outside specified ranges, `+
+		`at /cont1a/list4[id=second]/id: field name Id value second (string ptr) schema path /device/cont1a/list4/id has leafref path /cont1a/list2a/name not equal to any target nodes`, err.Error())
+
+	// Values not written by the request are found in the configuration
+	err = validationError(fmt.Errorf(`schema "leaf1a": "test val" does not match regular expression pattern "^[a-z]*$"`), updates, configValues)
+	assert.Equal(t, `validation error at /cont1a/leaf1a: schema "leaf1a": "test val" does not match regular expression pattern "^[a-z]*$"`, err.Error())
+
+	// Values matching the schema path only
+	err = validationError(fmt.Errorf("schema path /device/cont1a/list2a/tx-power is wrong"), nil, configValues)
+	assert.Equal(t, "validation error at /cont1a/list2a[name=first]/tx-power /cont1a/list2a[name=second]/tx-power: schema path /device/cont1a/list2a/tx-power is wrong", err.Error())
+
+	// Errors that tell nothing of a value are left as they are
+	err = validationError(fmt.Errorf("mandatory field missing"), updates, configValues)
+	assert.Equal(t, "validation error mandatory field missing", err.Error())
+}
@@ -0,0 +1,155 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"sort"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+)
+
+// ChangeEvent is an event of a change store, sequenced for external consumers
+type ChangeEvent struct {
+	// Sequence is the revision of the version of the change in its store, the same on every node,
+	// so that a consumer can resume after the last event it processed on any of them. Deletions
+	// have the sequence of the version deleted.
+	Sequence uint64
+	// Type is None for the current version of a change, streamed before the events
+	Type          stream.EventType
+	NetworkChange *networkchange.NetworkChange
+	DeviceChange  *devicechange.DeviceChange
+}
+
+// WatchNetworkChanges sends the current network changes to the channel, by increasing sequence,
+// then the events of the network change store, until the context is done. The versions of the
+// changes at or before resumeAfter are skipped: a consumer resuming after the last event it
+// processed is resynchronized with the latest version of each change updated since, once. The
+// store keeps no history, so the intermediate versions of the changes updated while the consumer
+// was away, and the changes deleted meanwhile, are not sent: the deletions are only sent as they
+// happen. The channel is closed once the changes are no longer watched.
+func (m *Manager) WatchNetworkChanges(ctx context.Context, resumeAfter uint64, ch chan<- ChangeEvent) error {
+	events := make(chan stream.Event)
+	watchCtx, err := m.NetworkChangesStore.Watch(events)
+	if err != nil {
+		return err
+	}
+	changes := make(chan *networkchange.NetworkChange)
+	if _, err := m.NetworkChangesStore.List(changes); err != nil {
+		watchCtx.Close()
+		return err
+	}
+	current := make([]ChangeEvent, 0)
+	for change := range changes {
+		current = append(current, newChangeEvent(stream.None, change))
+	}
+	go sendChangeEvents(ctx, watchCtx, resumeAfter, current, events, ch)
+	return nil
+}
+
+// WatchDeviceChanges sends the current changes of a device to the channel, then the events of
+// the device change store, like WatchNetworkChanges. The sequence of the changes of each device
+// is its own.
+func (m *Manager) WatchDeviceChanges(ctx context.Context, deviceID devicetype.VersionedID, resumeAfter uint64, ch chan<- ChangeEvent) error {
+	events := make(chan stream.Event)
+	watchCtx, err := m.DeviceChangesStore.Watch(deviceID, events)
+	if err != nil {
+		return err
+	}
+	changes := make(chan *devicechange.DeviceChange)
+	if _, err := m.DeviceChangesStore.List(deviceID, changes); err != nil {
+		watchCtx.Close()
+		return err
+	}
+	current := make([]ChangeEvent, 0)
+	for change := range changes {
+		current = append(current, newChangeEvent(stream.None, change))
+	}
+	go sendChangeEvents(ctx, watchCtx, resumeAfter, current, events, ch)
+	return nil
+}
+
+func newChangeEvent(eventType stream.EventType, object interface{}) ChangeEvent {
+	event := ChangeEvent{Type: eventType}
+	switch change := object.(type) {
+	case *networkchange.NetworkChange:
+		event.Sequence = uint64(change.Revision)
+		event.NetworkChange = change
+	case *devicechange.DeviceChange:
+		event.Sequence = uint64(change.Revision)
+		event.DeviceChange = change
+	}
+	return event
+}
+
+// sendChangeEvents sends the current changes by increasing sequence, then the events with a
+// sequence above those already sent, and the deletions
+func sendChangeEvents(ctx context.Context, watchCtx stream.Context, resumeAfter uint64, current []ChangeEvent,
+	events <-chan stream.Event, ch chan<- ChangeEvent) {
+	defer close(ch)
+	defer func() {
+		watchCtx.Close()
+		go func() {
+			for range events {
+			}
+		}()
+	}()
+	send := func(event ChangeEvent) bool {
+		select {
+		case ch <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	sort.Slice(current, func(i, j int) bool {
+		return current[i].Sequence < current[j].Sequence
+	})
+	last := resumeAfter
+	for _, event := range current {
+		if event.Sequence <= last {
+			continue
+		}
+		if !send(event) {
+			return
+		}
+		last = event.Sequence
+	}
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			event := newChangeEvent(e.Type, e.Object)
+			if e.Type != stream.Deleted {
+				// The versions listed are watched too
+				if event.Sequence <= last {
+					continue
+				}
+				last = event.Sequence
+			}
+			if !send(event) {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
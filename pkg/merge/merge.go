@@ -0,0 +1,152 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package merge applies the strategies declared for subtrees of the configuration to the values
+// written by gNMI Set requests. By default the values written are merged leaf-wise into the
+// configuration; a subtree declared with the replace strategy, e.g. a full ACL list, is instead
+// replaced wholesale, its leaves that are not written being deleted. Strategies are declared by the
+// model plugins and by the rules of the merge rule store, which override them.
+package merge
+
+import (
+	"sort"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	mergestore "github.com/onosproject/onos-config/pkg/store/merge"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// subtree is a rule and the elements of its path
+type subtree struct {
+	rule  *mergestore.Rule
+	elems []*gnmi.PathElem
+}
+
+// Rules combines the strategies declared by the model plugin of a device type, by path, with the
+// rules of the store that apply to the device type. A rule overrides the declaration of the same
+// path by the plugin, and a rule of the device type the rule of the same path for all types.
+func Rules(declared map[string]string, rules []*mergestore.Rule, deviceType devicetype.Type) ([]*mergestore.Rule, error) {
+	byPath := make(map[string]*mergestore.Rule)
+	for path, name := range declared {
+		strategy, err := mergestore.ParseStrategy(name)
+		if err != nil {
+			return nil, errors.NewInvalid("the model plugin declares an invalid strategy for %s: %v", path, err)
+		}
+		byPath[path] = &mergestore.Rule{Path: path, DeviceType: deviceType, Strategy: strategy}
+	}
+	for _, rule := range rules {
+		if rule.DeviceType == "" {
+			byPath[rule.Path] = rule
+		}
+	}
+	for _, rule := range rules {
+		if rule.DeviceType == deviceType {
+			byPath[rule.Path] = rule
+		}
+	}
+	combined := make([]*mergestore.Rule, 0, len(byPath))
+	for _, rule := range byPath {
+		combined = append(combined, rule)
+	}
+	sort.Slice(combined, func(i, j int) bool {
+		return combined[i].Path < combined[j].Path
+	})
+	return combined, nil
+}
+
+// Apply applies the strategies of the rules to the values written to a device and to the paths
+// deleted from it, given the current configuration of the device. It returns the paths to delete
+// instead: those given, followed by the leaves of the configuration that are in a replaced subtree
+// the values are written to but are not written themselves. The subtree of a leaf is given by the
+// most specific rule matching it, so that a subtree merged leaf-wise may be nested in a replaced one.
+func Apply(rules []*mergestore.Rule, updates devicechange.TypedValueMap, removes []string,
+	config []*devicechange.PathValue) ([]string, error) {
+
+	subtrees := make([]subtree, 0, len(rules))
+	replace := false
+	for _, rule := range rules {
+		path, err := utils.ParseGNMIElements(utils.SplitPath(rule.Path))
+		if err != nil {
+			return nil, errors.NewInvalid("the subtree path %s is invalid: %v", rule.Path, err)
+		}
+		subtrees = append(subtrees, subtree{rule: rule, elems: path.Elem})
+		replace = replace || rule.Strategy == mergestore.Replace
+	}
+	if !replace || len(updates) == 0 {
+		return removes, nil
+	}
+
+	// The replaced subtrees written to, by their concrete path
+	replaced := make(map[string]bool)
+	for path := range updates {
+		if rule, prefix := match(subtrees, path); rule != nil && rule.Strategy == mergestore.Replace {
+			replaced[prefix] = true
+		}
+	}
+	if len(replaced) == 0 {
+		return removes, nil
+	}
+
+	removed := make(map[string]bool)
+	for _, path := range removes {
+		removed[path] = true
+	}
+	replacedPaths := make([]string, 0)
+	for _, value := range config {
+		if _, ok := updates[value.Path]; ok || removed[value.Path] {
+			continue
+		}
+		if rule, prefix := match(subtrees, value.Path); rule != nil && replaced[prefix] {
+			replacedPaths = append(replacedPaths, value.Path)
+		}
+	}
+	sort.Strings(replacedPaths)
+	return append(removes, replacedPaths...), nil
+}
+
+// match returns the most specific rule whose subtree contains the path, and the concrete path of
+// the subtree, i.e. the path truncated to the length of the path of the rule. Keys are only kept
+// where the rule has them: "/interfaces/interface" is the whole list of interfaces, while
+// "/interfaces/interface[name=*]" is each of its entries.
+func match(subtrees []subtree, path string) (*mergestore.Rule, string) {
+	parsed, err := utils.ParseGNMIElements(utils.SplitPath(path))
+	if err != nil {
+		return nil, ""
+	}
+	var matched *subtree
+	for i := range subtrees {
+		subtree := &subtrees[i]
+		if utils.SubtreeContains(subtree.elems, parsed.Elem) && (matched == nil || len(subtree.elems) > len(matched.elems)) {
+			matched = subtree
+		}
+	}
+	if matched == nil {
+		return nil, ""
+	}
+	prefix := make([]*gnmi.PathElem, len(matched.elems))
+	for i, subtreeElem := range matched.elems {
+		elem := &gnmi.PathElem{Name: parsed.Elem[i].Name}
+		if len(subtreeElem.Key) > 0 {
+			elem.Key = make(map[string]string)
+			for key := range subtreeElem.Key {
+				elem.Key[key] = parsed.Elem[i].Key[key]
+			}
+		}
+		prefix[i] = elem
+	}
+	return matched.rule, utils.StrPathElem(prefix)
+}
@@ -0,0 +1,103 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	mergestore "github.com/onosproject/onos-config/pkg/store/merge"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	aclA1    = "/acl/acl-sets/acl-set[name=a][type=ipv4]/acl-entries/acl-entry[sequence-id=1]/config/description"
+	aclA2    = "/acl/acl-sets/acl-set[name=a][type=ipv4]/acl-entries/acl-entry[sequence-id=2]/config/description"
+	aclAName = "/acl/acl-sets/acl-set[name=a][type=ipv4]/config/description"
+	aclB1    = "/acl/acl-sets/acl-set[name=b][type=ipv4]/acl-entries/acl-entry[sequence-id=1]/config/description"
+	ntp1     = "/system/ntp/servers/server[address=10.0.0.1]/config/port"
+	ntp2     = "/system/ntp/servers/server[address=10.0.0.2]/config/port"
+	hostname = "/system/config/hostname"
+)
+
+var config = []*devicechange.PathValue{
+	{Path: aclA1, Value: devicechange.NewTypedValueString("permit ssh")},
+	{Path: aclA2, Value: devicechange.NewTypedValueString("deny all")},
+	{Path: aclAName, Value: devicechange.NewTypedValueString("management")},
+	{Path: aclB1, Value: devicechange.NewTypedValueString("permit all")},
+	{Path: hostname, Value: devicechange.NewTypedValueString("leaf-1")},
+	{Path: ntp1, Value: devicechange.NewTypedValueUint(123, 16)},
+	{Path: ntp2, Value: devicechange.NewTypedValueUint(123, 16)},
+}
+
+func Test_Rules(t *testing.T) {
+	rules, err := Rules(map[string]string{
+		"/acl/acl-sets/acl-set[name=*][type=*]/acl-entries": "replace",
+		"/system/ntp/servers":                               "replace",
+	}, []*mergestore.Rule{
+		{Path: "/system/ntp/servers", Strategy: mergestore.Merge},
+		{Path: "/system/ntp/servers", DeviceType: "Devicesim", Strategy: mergestore.Replace},
+		{Path: "/interfaces", DeviceType: "Stratum", Strategy: mergestore.Replace},
+	}, "TestDevice")
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, mergestore.Replace, rules[0].Strategy)
+	assert.Equal(t, "/system/ntp/servers", rules[1].Path)
+	assert.Equal(t, mergestore.Merge, rules[1].Strategy)
+
+	_, err = Rules(map[string]string{"/system": "overwrite"}, nil, "TestDevice")
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func Test_ApplyMerge(t *testing.T) {
+	// Without any replaced subtree the values are merged
+	removes, err := Apply([]*mergestore.Rule{{Path: "/acl", Strategy: mergestore.Merge}},
+		devicechange.TypedValueMap{aclA1: devicechange.NewTypedValueString("permit all")}, []string{hostname}, config)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{hostname}, removes)
+}
+
+func Test_ApplyReplaceEntries(t *testing.T) {
+	// Each ACL set has its entries replaced; its other leaves and the other sets are left alone
+	rules := []*mergestore.Rule{
+		{Path: "/acl/acl-sets/acl-set[name=*][type=*]/acl-entries", Strategy: mergestore.Replace},
+	}
+	removes, err := Apply(rules, devicechange.TypedValueMap{
+		aclA1:    devicechange.NewTypedValueString("permit all"),
+		hostname: devicechange.NewTypedValueString("leaf-2"),
+	}, nil, config)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{aclA2}, removes)
+}
+
+func Test_ApplyReplaceList(t *testing.T) {
+	// A rule without keys replaces the whole list, and a more specific rule merges a nested subtree
+	rules := []*mergestore.Rule{
+		{Path: "/system/ntp/servers/server", Strategy: mergestore.Replace},
+		{Path: "/acl/acl-sets/acl-set", Strategy: mergestore.Replace},
+		{Path: "/acl/acl-sets/acl-set[name=*][type=*]/config", Strategy: mergestore.Merge},
+	}
+	removes, err := Apply(rules, devicechange.TypedValueMap{
+		"/system/ntp/servers/server[address=10.0.0.3]/config/port": devicechange.NewTypedValueUint(123, 16),
+		aclB1: devicechange.NewTypedValueString("deny all"),
+	}, []string{ntp1}, config)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{ntp1, aclA1, aclA2, ntp2}, removes)
+
+	_, err = Apply([]*mergestore.Rule{{Path: "/acl/acl-set[name=a", Strategy: mergestore.Replace}},
+		devicechange.TypedValueMap{aclA1: devicechange.NewTypedValueString("permit all")}, nil, config)
+	assert.True(t, errors.IsInvalid(err))
+}
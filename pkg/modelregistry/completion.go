@@ -0,0 +1,120 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modelregistry
+
+import (
+	"sort"
+	"strings"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+)
+
+// PathCompletion is an element of the model that may follow a partial path
+type PathCompletion struct {
+	// Name is the name of the element, without the keys of a list
+	Name string
+	// Keys are the names of the keys of a list
+	Keys []string
+	// Leaf is true for a leaf, whose type and values are then given
+	Leaf      bool
+	ReadOnly  bool
+	ValueType devicechange.ValueType
+	// Values are the values of an identityref or enumeration leaf
+	Values []string
+}
+
+// CompletePath returns the elements of the model that may follow a partial path, sorted by name:
+// the children of the path if it ends with a '/', otherwise the siblings of its last element
+// whose name starts like it. The keys of the lists in the path may be given values or wildcards.
+func (p *ModelPlugin) CompletePath(path string) []*PathCompletion {
+	parent, prefix := "", path
+	if i := strings.LastIndex(AnonymizePathIndices(path), "/"); i >= 0 {
+		anonymized := AnonymizePathIndices(path)
+		parent, prefix = anonymized[:i], anonymized[i+1:]
+	}
+	if strings.Contains(prefix, "[") {
+		prefix = prefix[:strings.Index(prefix, "[")]
+	}
+
+	completions := make(map[string]*PathCompletion)
+	complete := func(modelPath string, attrib ReadOnlyAttrib, readOnly bool) {
+		if !strings.HasPrefix(modelPath, parent+"/") {
+			return
+		}
+		rest := strings.SplitN(modelPath[len(parent)+1:], "/", 2)
+		name, keys := splitElem(rest[0])
+		if !strings.HasPrefix(name, prefix) {
+			return
+		}
+		completion, ok := completions[name]
+		if !ok {
+			completion = &PathCompletion{Name: name, Keys: keys, ReadOnly: readOnly}
+			completions[name] = completion
+		}
+		completion.ReadOnly = completion.ReadOnly && readOnly
+		if len(rest) == 1 {
+			completion.Leaf = true
+			completion.ValueType = attrib.ValueType
+			completion.Values = enumValues(attrib)
+		}
+	}
+	for rwPath, elem := range p.ReadWritePaths {
+		complete(rwPath, elem.ReadOnlyAttrib, false)
+	}
+	for roPath, subPaths := range p.ReadOnlyPaths {
+		for subPath, attrib := range subPaths {
+			if subPath == "/" {
+				complete(roPath, attrib, true)
+			} else {
+				complete(roPath+subPath, attrib, true)
+			}
+		}
+	}
+
+	result := make([]*PathCompletion, 0, len(completions))
+	for _, completion := range completions {
+		result = append(result, completion)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// splitElem splits an element of a model path in its name and the names of its keys
+func splitElem(elem string) (string, []string) {
+	i := strings.Index(elem, "[")
+	if i < 0 {
+		return elem, nil
+	}
+	keys := make([]string, 0)
+	for _, key := range strings.Split(strings.Trim(elem[i:], "[]"), "][") {
+		keys = append(keys, strings.TrimSuffix(key, "=*"))
+	}
+	return elem[:i], keys
+}
+
+// enumValues returns the values of an identityref or enumeration leaf, sorted; nil for other leaves
+func enumValues(attrib ReadOnlyAttrib) []string {
+	var values []string
+	for i, value := range attrib.Enum {
+		if i > 0 {
+			values = append(values, value)
+		}
+	}
+	values = append(values, attrib.Enumeration...)
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,80 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modelregistry
+
+import (
+	"testing"
+
+	td1 "github.com/onosproject/config-models/modelplugin/testdevice-1.0.0/testdevice_1_0_0"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/openconfig/goyang/pkg/yang"
+	"gotest.tools/assert"
+)
+
+func completionNames(completions []*PathCompletion) []string {
+	names := make([]string, 0, len(completions))
+	for _, completion := range completions {
+		names = append(names, completion.Name)
+	}
+	return names
+}
+
+func Test_CompletePath(t *testing.T) {
+	td1Schema, _ := td1.UnzipSchema()
+	readOnlyPaths, readWritePaths := ExtractPaths(td1Schema["Device"], yang.TSUnset, "", "")
+	plugin := &ModelPlugin{ReadOnlyPaths: readOnlyPaths, ReadWritePaths: readWritePaths}
+
+	top := plugin.CompletePath("/")
+	assert.DeepEqual(t, completionNames(top), []string{"cont1a", "cont1b-state", "leafAtTopLevel"})
+	assert.Assert(t, !top[0].Leaf && !top[0].ReadOnly)
+	assert.Assert(t, top[1].ReadOnly)
+	assert.Assert(t, top[2].Leaf)
+	assert.Equal(t, top[2].ValueType, devicechange.ValueType_STRING)
+
+	lists := plugin.CompletePath("/cont1a/li")
+	assert.DeepEqual(t, completionNames(lists), []string{"list2a", "list4", "list5"})
+	assert.DeepEqual(t, lists[0].Keys, []string{"name"})
+	assert.DeepEqual(t, lists[2].Keys, []string{"key1", "key2"})
+
+	leaves := plugin.CompletePath("/cont1a/cont2a/")
+	assert.Equal(t, len(leaves), 7)
+	assert.Equal(t, leaves[2].Name, "leaf2c")
+	assert.Assert(t, leaves[2].ReadOnly, "leaf2c is state")
+	assert.Assert(t, !leaves[0].ReadOnly)
+
+	// The keys may be given values
+	entry := plugin.CompletePath("/cont1a/list2a[name=first]/r")
+	assert.DeepEqual(t, completionNames(entry), []string{"range-max", "range-min", "ref2d"})
+
+	assert.Equal(t, len(plugin.CompletePath("/cont1a/leaf1a/")), 0)
+	assert.Equal(t, len(plugin.CompletePath("/unknown/")), 0)
+}
+
+func Test_CompletePathEnumValues(t *testing.T) {
+	plugin := &ModelPlugin{
+		ReadWritePaths: ReadWritePathMap{
+			"/system/config/mode": ReadWritePathElem{
+				ReadOnlyAttrib: ReadOnlyAttrib{ValueType: devicechange.ValueType_STRING, Enumeration: []string{"SERVER", "PEER"}},
+			},
+			"/system/config/event": ReadWritePathElem{
+				ReadOnlyAttrib: ReadOnlyAttrib{ValueType: devicechange.ValueType_STRING, Enum: map[int]string{0: "UNSET", 1: "LOGIN", 2: "COMMAND"}},
+			},
+		},
+	}
+	completions := plugin.CompletePath("/system/config/")
+	assert.DeepEqual(t, completionNames(completions), []string{"event", "mode"})
+	assert.DeepEqual(t, completions[0].Values, []string{"COMMAND", "LOGIN"})
+	assert.DeepEqual(t, completions[1].Values, []string{"PEER", "SERVER"})
+}
@@ -40,24 +40,59 @@ type indexValue struct {
 	order int
 }
 
+// PointerError is the failure to decompose an element of a JSON value, located by the JSON
+// pointer (RFC 6901) of the element within the value and by the path it is written to
+type PointerError struct {
+	// Pointer is the JSON pointer of the element e.g. /interface/0/config/mtu
+	Pointer string
+	// Path is the path of the element e.g. /interfaces/interface[name=eth1]/config/mtu
+	Path string
+	// Err is the reason the element could not be decomposed
+	Err error
+}
+
+func (e *PointerError) Error() string {
+	return fmt.Sprintf("error decomposing JSON at %s (JSON pointer %s): %v", e.Path, e.Pointer, e.Err)
+}
+
 // DecomposeJSONWithPaths - handling the decomposition and correction in one go
 func DecomposeJSONWithPaths(prefixPath string, genericJSON []byte, ropaths modelregistry.ReadOnlyPathMap,
 	rwpaths modelregistry.ReadWritePathMap) ([]*devicechange.PathValue, error) {
+	return decomposeJSON(prefixPath, genericJSON, ropaths, rwpaths, false)
+}
+
+// DecomposeJSONWithKeyLeaves is DecomposeJSONWithPaths also returning the leaves holding the keys
+// of the list entries, e.g. /interfaces/interface[name=eth1]/name, so that an entry that only has
+// its keys is not lost
+func DecomposeJSONWithKeyLeaves(prefixPath string, genericJSON []byte, ropaths modelregistry.ReadOnlyPathMap,
+	rwpaths modelregistry.ReadWritePathMap) ([]*devicechange.PathValue, error) {
+	return decomposeJSON(prefixPath, genericJSON, ropaths, rwpaths, true)
+}
+
+func decomposeJSON(prefixPath string, genericJSON []byte, ropaths modelregistry.ReadOnlyPathMap,
+	rwpaths modelregistry.ReadWritePathMap, keyLeaves bool) ([]*devicechange.PathValue, error) {
 
 	var f interface{}
 	err := json.Unmarshal(genericJSON, &f)
 	if err != nil {
 		return nil, err
 	}
+	pointer := ""
 	if fAsMap, ok := f.(map[string]interface{}); ok {
 		if fResult, isResult := fAsMap["result"]; isResult {
 			if fResultAsMap, ok := fResult.([]interface{}); ok {
 				f = fResultAsMap[0]
+				pointer = "/result/0"
 			}
 		}
 	}
-	values, err := extractValuesWithPaths(f, removeIndexNames(prefixPath), ropaths, rwpaths)
-	if err != nil {
+	parentPath := removeIndexNames(prefixPath)
+	values, err := extractValuesWithPaths(f, parentPath, pointer, ropaths, rwpaths, keyLeaves)
+	if pointerErr, ok := err.(*PointerError); ok {
+		// The keys of the prefix are given by name
+		pointerErr.Path = prefixPath + strings.TrimPrefix(pointerErr.Path, parentPath)
+		return nil, pointerErr
+	} else if err != nil {
 		return nil, fmt.Errorf("error decomposing JSON %v", err)
 	}
 	return values, nil
@@ -65,15 +100,15 @@ func DecomposeJSONWithPaths(prefixPath string, genericJSON []byte, ropaths model
 
 // extractValuesIntermediate recursively walks a JSON tree to create a flat set
 // of paths and values.
-func extractValuesWithPaths(f interface{}, parentPath string,
+func extractValuesWithPaths(f interface{}, parentPath string, pointer string,
 	modelROpaths modelregistry.ReadOnlyPathMap,
-	modelRWpaths modelregistry.ReadWritePathMap) ([]*devicechange.PathValue, error) {
+	modelRWpaths modelregistry.ReadWritePathMap, keyLeaves bool) ([]*devicechange.PathValue, error) {
 
 	changes := make([]*devicechange.PathValue, 0)
 
 	switch value := f.(type) {
 	case map[string]interface{}:
-		mapChanges, err := handleMap(value, parentPath, modelROpaths, modelRWpaths)
+		mapChanges, err := handleMap(value, parentPath, pointer, modelROpaths, modelRWpaths, keyLeaves)
 		if err != nil {
 			return nil, err
 		}
@@ -85,9 +120,14 @@ func extractValuesWithPaths(f interface{}, parentPath string,
 		for idx, v := range value {
 			indices := make([]indexValue, 0)
 			nonIndexPaths := make([]string, 0)
-			objs, err := extractValuesWithPaths(v, fmt.Sprintf("%s[%d]", parentPath, idx),
-				modelROpaths, modelRWpaths)
-			if err != nil {
+			entryPath := fmt.Sprintf("%s[%d]", parentPath, idx)
+			objs, err := extractValuesWithPaths(v, entryPath, fmt.Sprintf("%s/%d", pointer, idx),
+				modelROpaths, modelRWpaths, keyLeaves)
+			if pointerErr, ok := err.(*PointerError); ok {
+				// Locate the failure by the keys of the list entry rather than by its position
+				pointerErr.Path = parentPath + entryKeys(v, indexNames, idx) + strings.TrimPrefix(pointerErr.Path, entryPath)
+				return nil, pointerErr
+			} else if err != nil {
 				return nil, err
 			}
 			for _, obj := range objs {
@@ -99,7 +139,7 @@ func extractValuesWithPaths(f interface{}, parentPath string,
 						break
 					}
 				}
-				if !isIndex {
+				if !isIndex || keyLeaves {
 					nonIndexPaths = append(nonIndexPaths, obj.Path)
 				}
 			}
@@ -123,8 +163,7 @@ func extractValuesWithPaths(f interface{}, parentPath string,
 	default:
 		attr, err := handleAttribute(value, parentPath, modelROpaths, modelRWpaths)
 		if err != nil {
-			return nil, fmt.Errorf("error handling json attribute value %v. Parent %s. #RO:%d #RW:%d %s",
-				value, parentPath, len(modelROpaths), len(modelRWpaths), err.Error())
+			return nil, &PointerError{Pointer: pointer, Path: parentPath, Err: err}
 		}
 		if attr != nil {
 			changes = append(changes, attr)
@@ -134,15 +173,45 @@ func extractValuesWithPaths(f interface{}, parentPath string,
 	return changes, nil
 }
 
-func handleMap(value map[string]interface{}, parentPath string,
+// entryKeys formats the keys of a list entry as in a path e.g. [name=eth1], or as its
+// position in the list if they are missing
+func entryKeys(entry interface{}, indexNames []string, idx int) string {
+	entryMap, ok := entry.(map[string]interface{})
+	if !ok || len(indexNames) == 0 {
+		return fmt.Sprintf("[%d]", idx)
+	}
+	keys := make(map[string]interface{})
+	for key, v := range entryMap {
+		keys[stripNamespace(key)] = v
+	}
+	var b strings.Builder
+	for _, indexName := range indexNames {
+		keyValue, ok := keys[indexName]
+		if !ok {
+			return fmt.Sprintf("[%d]", idx)
+		}
+		if number, isNumber := keyValue.(float64); isNumber {
+			keyValue = strconv.FormatFloat(number, 'f', -1, 64)
+		}
+		fmt.Fprintf(&b, "[%s=%v]", indexName, keyValue)
+	}
+	return b.String()
+}
+
+// pointerToken escapes a member name as a reference token of a JSON pointer
+func pointerToken(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+func handleMap(value map[string]interface{}, parentPath string, pointer string,
 	modelROpaths modelregistry.ReadOnlyPathMap,
-	modelRWpaths modelregistry.ReadWritePathMap) ([]*devicechange.PathValue, error) {
+	modelRWpaths modelregistry.ReadWritePathMap, keyLeaves bool) ([]*devicechange.PathValue, error) {
 
 	changes := make([]*devicechange.PathValue, 0)
 
 	for key, v := range value {
 		objs, err := extractValuesWithPaths(v, fmt.Sprintf("%s/%s", parentPath, stripNamespace(key)),
-			modelROpaths, modelRWpaths)
+			fmt.Sprintf("%s/%s", pointer, pointerToken(key)), modelROpaths, modelRWpaths, keyLeaves)
 		if err != nil {
 			return nil, err
 		}
@@ -296,7 +365,11 @@ func handleAttribute(value interface{}, parentPath string, modelROpaths modelreg
 		}
 		typedValue = devicechange.NewTypedValueString(stringVal)
 	case devicechange.ValueType_BOOL:
-		typedValue = devicechange.NewTypedValueBool(value.(bool))
+		boolVal, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("unhandled conversion to %v %v", modeltype, value)
+		}
+		typedValue = devicechange.NewTypedValueBool(boolVal)
 	case devicechange.ValueType_INT:
 		var intVal int
 		switch valueTyped := value.(type) {
@@ -319,11 +392,12 @@ func handleAttribute(value interface{}, parentPath string, modelROpaths modelreg
 		var uintVal uint
 		switch valueTyped := value.(type) {
 		case string:
-			intVal, err := strconv.ParseInt(valueTyped, 10, int(typeOpts[0]))
+			// JSON_IETF encodes 64 bit integers as strings
+			uintVal64, err := strconv.ParseUint(valueTyped, 10, int(typeOpts[0]))
 			if err != nil {
 				return nil, fmt.Errorf("error converting to %v %s", modeltype, valueTyped)
 			}
-			uintVal = uint(intVal)
+			uintVal = uint(uintVal64)
 		case float64:
 			uintVal = uint(valueTyped)
 		default:
@@ -595,10 +669,15 @@ func replaceIndices(path string, ignoreAfter int, indices []indexValue) (string,
 
 func convertEnumIdx(valueTyped string, enum map[int]string,
 	parentPath string) (string, error) {
+	// JSON_IETF qualifies identities with the name of their module e.g. iana-if-type:ethernetCsmacd
+	unqualified := valueTyped
+	if colonPos := strings.Index(valueTyped, colon); colonPos > 0 {
+		unqualified = valueTyped[colonPos+1:]
+	}
 	var stringVal string
 	for k, v := range enum {
-		if v == valueTyped {
-			stringVal = valueTyped
+		if v == valueTyped || v == unqualified {
+			stringVal = v
 			break
 		} else if fmt.Sprintf("%d", k) == valueTyped {
 			stringVal = v
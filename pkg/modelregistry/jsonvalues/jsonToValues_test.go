@@ -236,3 +236,112 @@ func Test_removeIndexNames(t *testing.T) {
 	assert.Equal(t, samplePath2Remove, removeIndexNames(samplePath2))
 
 }
+
+func Test_DecomposeJSONIetf(t *testing.T) {
+	_, rwPaths := setUpRwPaths()
+	ietfJSON := []byte(`{
+  "openconfig-interfaces:interface": [
+    {
+      "name": "eth1",
+      "config": {
+        "name": "eth1",
+        "description": "uplink",
+        "mtu": 1500,
+        "enabled": true
+      }
+    }
+  ]
+}`)
+
+	pathValues, err := DecomposeJSONWithPaths("/interfaces", ietfJSON, nil, rwPaths)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(pathValues))
+	for _, pathValue := range pathValues {
+		switch pathValue.Path {
+		case "/interfaces/interface[name=eth1]/config/name":
+			assert.Equal(t, "eth1", pathValue.GetValue().ValueToString())
+		case "/interfaces/interface[name=eth1]/config/description":
+			assert.Equal(t, "uplink", pathValue.GetValue().ValueToString())
+		case "/interfaces/interface[name=eth1]/config/mtu":
+			assert.Equal(t, "1500", pathValue.GetValue().ValueToString())
+		case "/interfaces/interface[name=eth1]/config/enabled":
+			assert.Equal(t, "true", pathValue.GetValue().ValueToString())
+		default:
+			t.Errorf("unexpected path %s", pathValue.Path)
+		}
+	}
+
+	_, err = DecomposeJSONWithPaths("/interfaces", []byte(`{"interface": [{"name": "eth1", "config": {"enabled": "yes"}}]}`), nil, rwPaths)
+	assert.Error(t, err)
+}
+
+func Test_DecomposeJSONPointerError(t *testing.T) {
+	_, rwPaths := setUpRwPaths()
+	badJSON := []byte(`{
+  "openconfig-interfaces:interface": [
+    {"name": "eth1", "config": {"mtu": 1500}},
+    {"name": "eth2", "config": {"mtu": "big"}}
+  ]
+}`)
+	_, err := DecomposeJSONWithPaths("/interfaces", badJSON, nil, rwPaths)
+	pointerErr, ok := err.(*PointerError)
+	assert.True(t, ok, "expected a PointerError, got %v", err)
+	assert.Equal(t, "/openconfig-interfaces:interface/1/config/mtu", pointerErr.Pointer)
+	assert.Equal(t, "/interfaces/interface[name=eth2]/config/mtu", pointerErr.Path)
+	assert.Equal(t, "error decomposing JSON at /interfaces/interface[name=eth2]/config/mtu "+
+		"(JSON pointer /openconfig-interfaces:interface/1/config/mtu): error converting to UINT big", err.Error())
+
+	// Under a prefix with keys
+	_, err = DecomposeJSONWithPaths("/interfaces/interface[name=eth3]", []byte(`{"config": {"enabled": 1}}`), nil, rwPaths)
+	pointerErr, ok = err.(*PointerError)
+	assert.True(t, ok, "expected a PointerError, got %v", err)
+	assert.Equal(t, "/config/enabled", pointerErr.Pointer)
+	assert.Equal(t, "/interfaces/interface[name=eth3]/config/enabled", pointerErr.Path)
+}
+
+func Test_entryKeys(t *testing.T) {
+	entry := map[string]interface{}{"test1:key1": "abc", "key2": float64(1000000), "leaf5a": "x"}
+	assert.Equal(t, "[key1=abc][key2=1000000]", entryKeys(entry, []string{"key1", "key2"}, 3))
+	assert.Equal(t, "[3]", entryKeys(entry, []string{"key3"}, 3))
+	assert.Equal(t, "[3]", entryKeys("abc", []string{"key1"}, 3))
+	assert.Equal(t, "/a~1b~0c", "/"+pointerToken("a/b~c"))
+}
+
+func Test_convertEnumIdx(t *testing.T) {
+	enum := map[int]string{0: "UNSET", 1: "ethernetCsmacd", 2: "ieee8023adLag"}
+
+	value, err := convertEnumIdx("ethernetCsmacd", enum, "/type")
+	assert.NoError(t, err)
+	assert.Equal(t, "ethernetCsmacd", value)
+	value, err = convertEnumIdx("iana-if-type:ieee8023adLag", enum, "/type")
+	assert.NoError(t, err)
+	assert.Equal(t, "ieee8023adLag", value)
+	value, err = convertEnumIdx("1", enum, "/type")
+	assert.NoError(t, err)
+	assert.Equal(t, "ethernetCsmacd", value)
+	_, err = convertEnumIdx("iana-if-type:other", enum, "/type")
+	assert.Error(t, err)
+}
+
+func Test_DecomposeJSONWithKeyLeaves(t *testing.T) {
+	_, ds1RwPaths := setUpRwPaths()
+	tree := []byte(`{"interfaces": {"interface": [{"name": "eth1"}, {"name": "eth2", "config": {"mtu": 1500}}]}}`)
+
+	pathValues, err := DecomposeJSONWithPaths("", tree, nil, ds1RwPaths)
+	assert.NoError(t, err)
+	assert.Len(t, pathValues, 1)
+	assert.Equal(t, "/interfaces/interface[name=eth2]/config/mtu", pathValues[0].Path)
+
+	// The entry that only has its key is kept by its key leaf
+	pathValues, err = DecomposeJSONWithKeyLeaves("", tree, nil, ds1RwPaths)
+	assert.NoError(t, err)
+	paths := make([]string, 0, len(pathValues))
+	for _, pathValue := range pathValues {
+		paths = append(paths, pathValue.Path)
+	}
+	assert.ElementsMatch(t, []string{
+		"/interfaces/interface[name=eth1]/name",
+		"/interfaces/interface[name=eth2]/name",
+		"/interfaces/interface[name=eth2]/config/mtu",
+	}, paths)
+}
@@ -58,6 +58,10 @@ type ReadOnlyAttrib struct {
 	Enum        map[int]string
 	IsAKey      bool
 	AttrName    string
+	// TypeName is the name of the YANG type of the leaf, e.g. ipv4-address
+	TypeName string
+	// Enumeration are the names of the values of a leaf of an enumeration type
+	Enumeration []string
 }
 
 // ReadOnlySubPathMap abstracts the read only subpath
@@ -150,6 +154,15 @@ type ModelPlugin struct {
 	Model          configmodel.ConfigModel
 	ReadOnlyPaths  ReadOnlyPathMap
 	ReadWritePaths ReadWritePathMap
+	// MergeStrategies are the strategies the model declares for its subtrees, by path
+	MergeStrategies map[string]string
+}
+
+// MergeStrategyModel is implemented by the config models that declare how the values written to
+// their subtrees are merged, e.g. "replace" for a list that is only ever written as a whole
+type MergeStrategyModel interface {
+	// MergeStrategies returns the strategies of the subtrees, by path
+	MergeStrategies() map[string]string
 }
 
 // NewModelRegistry creates a new model registry
@@ -163,6 +176,7 @@ func NewModelRegistry(config Config, plugins ...*ModelPlugin) (*ModelRegistry, e
 		registry: modelregistry.NewConfigModelRegistry(modelregistry.Config{Path: config.RegistryPath}),
 		cache:    cache,
 		plugins:  make(map[string]*ModelPlugin),
+		translations: make(map[devicetype.Type]*Translation),
 	}
 	for _, plugin := range plugins {
 		modelName := utils.ToModelName(devicetype.Type(plugin.Info.Name), devicetype.Version(plugin.Info.Version))
@@ -177,6 +191,8 @@ type ModelRegistry struct {
 	registry *modelregistry.ConfigModelRegistry
 	plugins  map[string]*ModelPlugin
 	mu       sync.RWMutex
+	// translations map the paths of vendor-neutral models to the native models, by device type
+	translations map[devicetype.Type]*Translation
 }
 
 // GetPlugins gets a list of model plugins
@@ -302,11 +318,16 @@ func (r *ModelRegistry) loadPlugin(modelInfo configmodel.ModelInfo) (*ModelPlugi
 		log.Infof("Model %s %s loaded. %d read only paths. %d read write paths", modelInfo.Name, modelInfo.Version,
 			len(readOnlyPaths), len(readWritePaths))
 	}
+	var mergeStrategies map[string]string
+	if mergeStrategyModel, ok := model.(MergeStrategyModel); ok {
+		mergeStrategies = mergeStrategyModel.MergeStrategies()
+	}
 	return &ModelPlugin{
-		Info:           modelInfo,
-		Model:          model,
-		ReadOnlyPaths:  readOnlyPaths,
-		ReadWritePaths: readWritePaths,
+		Info:            modelInfo,
+		Model:           model,
+		ReadOnlyPaths:   readOnlyPaths,
+		ReadWritePaths:  readWritePaths,
+		MergeStrategies: mergeStrategies,
 	}, nil
 }
 
@@ -352,6 +373,7 @@ func ExtractPaths(deviceEntry *yang.Entry, parentState yang.TriState, parentPath
 				Description: dirEntry.Description,
 				Units:       dirEntry.Units,
 				AttrName:    dirEntry.Name,
+				TypeName:    dirEntry.Type.Name,
 			}
 			if err != nil {
 				log.Errorf(err.Error())
@@ -361,6 +383,9 @@ func ExtractPaths(deviceEntry *yang.Entry, parentState yang.TriState, parentPath
 				enum = handleIdentity(dirEntry.Type)
 			}
 			tObj.Enum = enum
+			if dirEntry.Type.Kind == yang.Yenum && dirEntry.Type.Enum != nil {
+				tObj.Enumeration = dirEntry.Type.Enum.Names()
+			}
 			// Check to see if this attribute is a key in a list
 			if dirEntry.Parent.IsList() {
 				keyNames := strings.Split(dirEntry.Parent.Key, " ")
@@ -75,6 +75,7 @@ func Test_SchemaTestDevice1(t *testing.T) {
 	assert.Equal(t, l2bLeaf3cVt.AttrName, "leaf3c")
 	assert.Assert(t, !l2bLeaf3cVt.IsAKey)
 	assert.Equal(t, l2bLeaf3cVt.ValueType, devicechange.ValueType_STRING)
+	assert.Equal(t, l2bLeaf3cVt.TypeName, "string")
 
 	////////////////////////////////////////////////////
 	/// Read write paths
@@ -0,0 +1,215 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modelregistry
+
+import (
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"gopkg.in/yaml.v2"
+)
+
+// rVariable matches a variable of the key values of the From path of a mapping, e.g. {name}
+var rVariable = regexp.MustCompile(`^{([a-zA-Z0-9_-]+)}$`)
+
+// rVariableUse matches the uses of the variables in the To path of a mapping
+var rVariableUse = regexp.MustCompile(`{([^}]*)}`)
+
+// PathMapping maps a path of a vendor-neutral model, e.g. OpenConfig, to the path of a native model.
+// A key value of From in braces is a variable, e.g. /interfaces/interface[name={name}]/config/mtu,
+// which To gives the value of, e.g. /ifmgr/if[ifname={name}]/mtu. The paths under From are mapped
+// to the same paths under To.
+type PathMapping struct {
+	From string `yaml:"from" json:"from"`
+	To   string `yaml:"to" json:"to"`
+	// Values maps the values written to From to the values written to To, e.g. the names of the
+	// enumerations of the native model; the values not mapped are written as they are
+	Values map[string]string `yaml:"values,omitempty" json:"values,omitempty"`
+	// from are the elements of From
+	from []*gnmi.PathElem
+}
+
+// compile checks the mapping and parses its From path
+func (m *PathMapping) compile() error {
+	if !strings.HasPrefix(m.From, "/") || !strings.HasPrefix(m.To, "/") {
+		return errors.NewInvalid("invalid mapping of '%s' to '%s'", m.From, m.To)
+	}
+	from, err := utils.ParseGNMIElements(utils.SplitPath(m.From))
+	if err != nil {
+		return errors.NewInvalid("invalid mapping of '%s': %v", m.From, err)
+	}
+	if _, err := utils.ParseGNMIElements(utils.SplitPath(m.To)); err != nil {
+		return errors.NewInvalid("invalid mapping to '%s': %v", m.To, err)
+	}
+	variables := make(map[string]bool)
+	for _, elem := range from.Elem {
+		for _, value := range elem.Key {
+			if match := rVariable.FindStringSubmatch(value); match != nil {
+				variables[match[1]] = true
+			}
+		}
+	}
+	for _, match := range rVariableUse.FindAllStringSubmatch(m.To, -1) {
+		if !variables[match[1]] {
+			return errors.NewInvalid("mapping of '%s' to '%s': unknown variable '%s'", m.From, m.To, match[1])
+		}
+	}
+	m.from = from.Elem
+	return nil
+}
+
+// mapPath returns the path a path maps to, and the number of the elements of the mapping that
+// matched; 0 if the mapping does not apply to it
+func (m *PathMapping) mapPath(elems []*gnmi.PathElem) (string, int) {
+	if len(elems) < len(m.from) {
+		return "", 0
+	}
+	to := m.To
+	for i, fromElem := range m.from {
+		elem := elems[i]
+		if elem.Name != fromElem.Name || len(elem.Key) != len(fromElem.Key) {
+			return "", 0
+		}
+		for key, fromValue := range fromElem.Key {
+			value, ok := elem.Key[key]
+			if !ok {
+				return "", 0
+			}
+			if match := rVariable.FindStringSubmatch(fromValue); match != nil {
+				to = strings.ReplaceAll(to, fromValue, value)
+			} else if fromValue != value {
+				return "", 0
+			}
+		}
+	}
+	suffix := utils.StrPath(&gnmi.Path{Elem: elems[len(m.from):]})
+	if suffix != "/" {
+		to += suffix
+	}
+	return to, len(m.from)
+}
+
+// Translation maps the paths of a vendor-neutral model to those of the native model of a device type
+type Translation struct {
+	Mappings []*PathMapping
+}
+
+// NewTranslation returns the translation of mappings, checking them
+func NewTranslation(mappings ...*PathMapping) (*Translation, error) {
+	for _, mapping := range mappings {
+		if err := mapping.compile(); err != nil {
+			return nil, err
+		}
+	}
+	return &Translation{Mappings: mappings}, nil
+}
+
+// TranslatePath returns the native path a path maps to, with the mapping applying to it, or nil
+// if none applies. The most specific mapping applies: the one matching the most elements of the
+// path, then the first one given.
+func (t *Translation) TranslatePath(path string) (string, *PathMapping, error) {
+	elems, err := utils.ParseGNMIElements(utils.SplitPath(path))
+	if err != nil {
+		return "", nil, errors.NewInvalid("invalid path %s: %v", path, err)
+	}
+	var translated string
+	var applied *PathMapping
+	matched := 0
+	for _, mapping := range t.Mappings {
+		if to, n := mapping.mapPath(elems.Elem); n > matched {
+			translated, applied, matched = to, mapping, n
+		}
+	}
+	return translated, applied, nil
+}
+
+// TranslateValue returns the native value of a value written to a path of a mapping
+func (m *PathMapping) TranslateValue(value string) string {
+	if native, ok := m.Values[value]; ok {
+		return native
+	}
+	return value
+}
+
+// RegisterTranslation sets the translation of the paths written to the devices of a type, replacing
+// any translation the type had; the translation is removed if it has no mappings
+func (r *ModelRegistry) RegisterTranslation(deviceType devicetype.Type, mappings ...*PathMapping) error {
+	translation, err := NewTranslation(mappings...)
+	if err != nil {
+		return errors.NewInvalid("device type %s: %v", deviceType, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.translations == nil {
+		r.translations = make(map[devicetype.Type]*Translation)
+	}
+	if len(mappings) == 0 {
+		delete(r.translations, deviceType)
+	} else {
+		r.translations[deviceType] = translation
+	}
+	return nil
+}
+
+// GetTranslation returns the translation of the paths written to the devices of a type, nil if it
+// has none
+func (r *ModelRegistry) GetTranslation(deviceType devicetype.Type) *Translation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.translations[deviceType]
+}
+
+// TranslatedTypes returns the device types that have a translation, sorted
+func (r *ModelRegistry) TranslatedTypes() []devicetype.Type {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]devicetype.Type, 0, len(r.translations))
+	for deviceType := range r.translations {
+		types = append(types, deviceType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+	return types
+}
+
+// LoadTranslations registers the translations of a YAML file mapping device types to their path
+// mappings, e.g.
+//
+//	VendorDevice:
+//	  - from: /interfaces/interface[name={name}]/config/mtu
+//	    to: /ifmgr/if[ifname={name}]/mtu
+func (r *ModelRegistry) LoadTranslations(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	translations := make(map[string][]*PathMapping)
+	if err := yaml.UnmarshalStrict(data, &translations); err != nil {
+		return errors.NewInvalid("cannot parse translations file %s: %v", path, err)
+	}
+	for deviceType, mappings := range translations {
+		if err := r.RegisterTranslation(devicetype.Type(deviceType), mappings...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modelregistry
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"gotest.tools/assert"
+)
+
+func Test_TranslatePath(t *testing.T) {
+	translation, err := NewTranslation(
+		&PathMapping{From: "/interfaces/interface[name={name}]/config", To: "/ifmgr/if[ifname={name}]"},
+		&PathMapping{
+			From:   "/interfaces/interface[name={name}]/config/enabled",
+			To:     "/ifmgr/if[ifname={name}]/admin-state",
+			Values: map[string]string{"true": "UP", "false": "DOWN"},
+		},
+		&PathMapping{From: "/system/config/hostname", To: "/sys/name"},
+	)
+	assert.NilError(t, err)
+
+	// The paths under a mapping are mapped under its native path
+	path, mapping, err := translation.TranslatePath("/interfaces/interface[name=eth1/1]/config/mtu")
+	assert.NilError(t, err)
+	assert.Equal(t, path, "/ifmgr/if[ifname=eth1/1]/mtu")
+	assert.Equal(t, mapping.TranslateValue("9000"), "9000")
+
+	// The most specific mapping applies
+	path, mapping, err = translation.TranslatePath("/interfaces/interface[name=eth1]/config/enabled")
+	assert.NilError(t, err)
+	assert.Equal(t, path, "/ifmgr/if[ifname=eth1]/admin-state")
+	assert.Equal(t, mapping.TranslateValue("true"), "UP")
+
+	path, _, err = translation.TranslatePath("/system/config/hostname")
+	assert.NilError(t, err)
+	assert.Equal(t, path, "/sys/name")
+
+	_, mapping, err = translation.TranslatePath("/system/config/domain-name")
+	assert.NilError(t, err)
+	assert.Assert(t, mapping == nil)
+
+	for _, mapping := range []*PathMapping{
+		{From: "interfaces", To: "/ifmgr"},
+		{From: "/interfaces/interface[name={name}]", To: "/ifmgr/if[ifname={id}]"},
+	} {
+		_, err := NewTranslation(mapping)
+		assert.Assert(t, errors.IsInvalid(err), mapping.From)
+	}
+}
+
+func Test_RegisterTranslation(t *testing.T) {
+	registry := &ModelRegistry{}
+	assert.Assert(t, registry.GetTranslation("VendorDevice") == nil)
+
+	dir, err := ioutil.TempDir("", "translations")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "translations.yaml")
+	assert.NilError(t, ioutil.WriteFile(path, []byte(`
+VendorDevice:
+  - from: /system/config/hostname
+    to: /sys/name
+`), 0644))
+	assert.NilError(t, registry.LoadTranslations(path))
+	assert.DeepEqual(t, registry.TranslatedTypes(), []devicetype.Type{"VendorDevice"})
+	translated, _, err := registry.GetTranslation("VendorDevice").TranslatePath("/system/config/hostname")
+	assert.NilError(t, err)
+	assert.Equal(t, translated, "/sys/name")
+
+	assert.NilError(t, registry.RegisterTranslation("VendorDevice"))
+	assert.Equal(t, len(registry.TranslatedTypes()), 0)
+
+	assert.NilError(t, ioutil.WriteFile(path, []byte("VendorDevice:\n  - form: /system\n"), 0644))
+	assert.Assert(t, errors.IsInvalid(registry.LoadTranslations(path)))
+}
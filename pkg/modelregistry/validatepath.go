@@ -0,0 +1,168 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modelregistry
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// ValidatedPath is a path found in the model
+type ValidatedPath struct {
+	// Path is the path parsed in gNMI elements
+	Path *gnmi.Path
+	// ModelPath is the path of the model it matches, e.g. /cont1a/list2a[name=*]/tx-power
+	ModelPath string
+	Leaf      bool
+	ReadOnly  bool
+	// ValueType is the type of a leaf
+	ValueType devicechange.ValueType
+}
+
+// ValidatePath checks that a path is in the model: each of its elements must be a child of the
+// previous one, the lists must be given all of their keys and no other, and the values of the keys
+// must be of the type of their key leaf, or a wildcard.
+func (p *ModelPlugin) ValidatePath(path string) (*ValidatedPath, error) {
+	gnmiPath, err := utils.ParseGNMIElements(utils.SplitPath(path))
+	if err != nil {
+		return nil, errors.NewInvalid("%s cannot be parsed: %v", path, err)
+	} else if len(gnmiPath.Elem) == 0 {
+		return nil, errors.NewInvalid("no path given")
+	}
+
+	validated := &ValidatedPath{Path: gnmiPath}
+	for i, elem := range gnmiPath.Elem {
+		if validated.Leaf {
+			return nil, errors.NewInvalid("%s is a leaf, it has no %s", validated.ModelPath, elem.Name)
+		}
+		var completion *PathCompletion
+		for _, c := range p.CompletePath(validated.ModelPath + "/" + elem.Name) {
+			if c.Name == elem.Name {
+				completion = c
+			}
+		}
+		if completion == nil {
+			return nil, errors.NewInvalid("%s is not in the model: %s has no %s",
+				utils.StrPathElem(gnmiPath.Elem[:i+1]), utils.StrPathElem(gnmiPath.Elem[:i]), elem.Name)
+		}
+
+		elemPath := elem.Name
+		givenKeys := make([]string, 0, len(elem.Key))
+		for key := range elem.Key {
+			givenKeys = append(givenKeys, key)
+		}
+		sort.Strings(givenKeys)
+		if strings.Join(givenKeys, ",") != strings.Join(completion.Keys, ",") {
+			if len(completion.Keys) == 0 {
+				return nil, errors.NewInvalid("%s is not a list, it has no keys", elem.Name)
+			}
+			return nil, errors.NewInvalid("list %s has the keys %s, %s were given",
+				elem.Name, strings.Join(completion.Keys, ","), strings.Join(givenKeys, ","))
+		}
+		for _, key := range completion.Keys {
+			elemPath = fmt.Sprintf("%s[%s=*]", elemPath, key)
+		}
+		for _, key := range completion.Keys {
+			keyPath := fmt.Sprintf("%s/%s/%s", validated.ModelPath, elemPath, key)
+			if err := p.checkKeyValue(keyPath, elem.Key[key]); err != nil {
+				return nil, errors.NewInvalid("key %s of %s: %v", key, elem.Name, err)
+			}
+		}
+
+		validated.ModelPath = validated.ModelPath + "/" + elemPath
+		validated.Leaf = completion.Leaf
+		validated.ReadOnly = completion.ReadOnly
+		validated.ValueType = completion.ValueType
+	}
+	return validated, nil
+}
+
+// checkKeyValue checks that the value given to a key is valid for the key leaf of the model
+func (p *ModelPlugin) checkKeyValue(keyPath string, value string) error {
+	if err := CheckPathIndexIsValid(value); err != nil {
+		return err
+	} else if value == "*" {
+		return nil
+	}
+	attrib, ok := p.attrib(keyPath)
+	if !ok {
+		return nil
+	}
+	width := devicechange.WidthThirtyTwo
+	if len(attrib.TypeOpts) > 0 {
+		width = devicechange.Width(attrib.TypeOpts[0])
+	}
+	var err error
+	switch attrib.ValueType {
+	case devicechange.ValueType_INT:
+		_, err = strconv.ParseInt(value, 10, int(width))
+	case devicechange.ValueType_UINT:
+		_, err = strconv.ParseUint(value, 10, int(width))
+	case devicechange.ValueType_BOOL:
+		_, err = strconv.ParseBool(value)
+	}
+	if err != nil {
+		return fmt.Errorf("'%s' is not a valid %s", value, attrib.ValueType)
+	}
+	return nil
+}
+
+// attrib returns the attributes of the leaf of a model path
+func (p *ModelPlugin) attrib(modelPath string) (ReadOnlyAttrib, bool) {
+	if elem, ok := p.ReadWritePaths[modelPath]; ok {
+		return elem.ReadOnlyAttrib, true
+	}
+	for roPath, subPaths := range p.ReadOnlyPaths {
+		if modelPath == roPath {
+			attrib, ok := subPaths["/"]
+			return attrib, ok
+		} else if strings.HasPrefix(modelPath, roPath+"/") {
+			if attrib, ok := subPaths[modelPath[len(roPath):]]; ok {
+				return attrib, true
+			}
+		}
+	}
+	return ReadOnlyAttrib{}, false
+}
+
+// ExpandPath returns the read-write and read-only leaf paths of the model at or under a path with
+// the wildcards '*' and '...', as the model has them, e.g. /cont1a/list2a[name=*]/tx-power, sorted.
+// Only the names of the elements of the path are matched: any value of a key matches the model.
+func (p *ModelPlugin) ExpandPath(path string) ([]string, error) {
+	parsed, err := utils.ParseGNMIElements(utils.SplitPath(path))
+	if err != nil {
+		return nil, errors.NewInvalid("invalid path %s: %v", path, err)
+	}
+	subtree := make([]*gnmi.PathElem, 0, len(parsed.Elem))
+	for _, elem := range parsed.Elem {
+		subtree = append(subtree, &gnmi.PathElem{Name: elem.Name})
+	}
+	expanded := make([]string, 0)
+	for _, modelPath := range append(p.ReadWritePaths.JustPaths(), p.ReadOnlyPaths.JustPaths()...) {
+		modelElems, err := utils.ParseGNMIElements(utils.SplitPath(modelPath))
+		if err == nil && utils.SubtreeContains(subtree, modelElems.Elem) {
+			expanded = append(expanded, modelPath)
+		}
+	}
+	sort.Strings(expanded)
+	return expanded, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modelregistry
+
+import (
+	"strings"
+	"testing"
+
+	td1 "github.com/onosproject/config-models/modelplugin/testdevice-1.0.0/testdevice_1_0_0"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/goyang/pkg/yang"
+	"gotest.tools/assert"
+)
+
+func Test_ValidatePath(t *testing.T) {
+	td1Schema, _ := td1.UnzipSchema()
+	readOnlyPaths, readWritePaths := ExtractPaths(td1Schema["Device"], yang.TSUnset, "", "")
+	plugin := &ModelPlugin{ReadOnlyPaths: readOnlyPaths, ReadWritePaths: readWritePaths}
+
+	validated, err := plugin.ValidatePath("/cont1a/list2a[name=first]/tx-power")
+	assert.NilError(t, err)
+	assert.Equal(t, validated.ModelPath, "/cont1a/list2a[name=*]/tx-power")
+	assert.Assert(t, validated.Leaf && !validated.ReadOnly)
+	assert.Equal(t, validated.ValueType, devicechange.ValueType_UINT)
+	assert.Equal(t, len(validated.Path.Elem), 3)
+	assert.Equal(t, validated.Path.Elem[1].Key["name"], "first")
+
+	validated, err = plugin.ValidatePath("/cont1a/list5[key2=2][key1=one]")
+	assert.NilError(t, err)
+	assert.Equal(t, validated.ModelPath, "/cont1a/list5[key1=*][key2=*]")
+	assert.Assert(t, !validated.Leaf)
+
+	validated, err = plugin.ValidatePath("/cont1a/cont2a/leaf2c")
+	assert.NilError(t, err)
+	assert.Assert(t, validated.ReadOnly)
+
+	validated, err = plugin.ValidatePath("/cont1b-state/list2b[index=*]/leaf3c")
+	assert.NilError(t, err)
+	assert.Assert(t, validated.Leaf && validated.ReadOnly)
+
+	for _, invalid := range []string{
+		"",
+		"/cont1a/unknown",
+		"/cont1a/leaf1a/below",
+		"/cont1a/list2a/tx-power",
+		"/cont1a/list2a[id=first]/tx-power",
+		"/cont1a/list5[key1=one]",
+		"/cont1a[name=first]",
+		"/cont1a/list5[key1=one][key2=two]",
+		"/cont1a/list2a[name=a b]",
+	} {
+		_, err = plugin.ValidatePath(invalid)
+		assert.Assert(t, errors.IsInvalid(err), invalid)
+	}
+}
+
+func Test_ExpandPath(t *testing.T) {
+	td1Schema, _ := td1.UnzipSchema()
+	readOnlyPaths, readWritePaths := ExtractPaths(td1Schema["Device"], yang.TSUnset, "", "")
+	plugin := &ModelPlugin{ReadOnlyPaths: readOnlyPaths, ReadWritePaths: readWritePaths}
+
+	expanded, err := plugin.ExpandPath("/cont1a/list2a[name=*]")
+	assert.NilError(t, err)
+	assert.Assert(t, len(expanded) > 0)
+	for _, path := range expanded {
+		assert.Assert(t, strings.HasPrefix(path, "/cont1a/list2a[name=*]/"), path)
+	}
+
+	// The key values are not matched, and the wildcards stand for whole elements
+	named, err := plugin.ExpandPath("/cont1a/list2a[name=eth1/1]")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, named, expanded)
+	expanded, err = plugin.ExpandPath("/*/list2a/tx-power")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, expanded, []string{"/cont1a/list2a[name=*]/tx-power"})
+	expanded, err = plugin.ExpandPath("/.../leaf3c")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, expanded, []string{"/cont1b-state/list2b[index=*]/leaf3c"})
+
+	expanded, err = plugin.ExpandPath("/cont1a/*/unknown")
+	assert.NilError(t, err)
+	assert.Equal(t, len(expanded), 0)
+}
@@ -18,14 +18,12 @@ package admin
 import (
 	"context"
 	"fmt"
+
+	"github.com/gogo/protobuf/types"
 	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
 	"github.com/onosproject/onos-api/go/onos/config/admin"
-	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
-	"github.com/onosproject/onos-api/go/onos/config/snapshot"
-	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
-	networksnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/network"
-	"github.com/onosproject/onos-config/pkg/manager"
-	streams "github.com/onosproject/onos-config/pkg/store/stream"
+	adminv2 "github.com/onosproject/onos-config/api/admin/v2"
+	"github.com/onosproject/onos-config/api/adminext"
 	"github.com/onosproject/onos-config/pkg/utils"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
@@ -44,6 +42,8 @@ type Service struct {
 func (s Service) Register(r *grpc.Server) {
 	server := Server{}
 	admin.RegisterConfigAdminServiceServer(r, server)
+	adminext.RegisterConfigAdminExtServiceServer(r, ExtServer{})
+	adminv2.RegisterConfigAdminServiceServer(r, V2Server{})
 }
 
 // Server implements the gRPC service for administrative facilities.
@@ -56,13 +56,28 @@ func (s Server) UploadRegisterModel(stream admin.ConfigAdminService_UploadRegist
 	return errors.NewNotSupported("not implemented")
 }
 
-// ListRegisteredModels lists the registered models..
+// ListRegisteredModels lists the registered models, by name and version, optionally filtered
+// by name and version. The read only and read write paths of the models are only listed when
+// verbose.
+// Deprecated: use ListModels of version 2
 func (s Server) ListRegisteredModels(req *admin.ListModelsRequest, stream admin.ConfigAdminService_ListRegisteredModelsServer) error {
-	return errors.NewNotSupported("not implemented")
+	deprecatedStream(stream, "ListRegisteredModels", "ListModels")
+	models, err := listModels(req.ModelName, req.ModelVersion, req.Verbose)
+	if err != nil {
+		return errors.Status(err).Err()
+	}
+	for _, model := range models {
+		if err := stream.Send(fromModel(model)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // RollbackNetworkChange rolls back a named atomix-based network change.
+// Deprecated: use RollbackNetworkChange of version 2
 func (s Server) RollbackNetworkChange(ctx context.Context, req *admin.RollbackRequest) (*admin.RollbackResponse, error) {
+	deprecated(ctx, "RollbackNetworkChange", "RollbackNetworkChange")
 	if md := metautils.ExtractIncoming(ctx); md != nil && md.Get("name") != "" {
 		log.Infof("admin RollbackNetworkChange() called by '%s (%s)'. Groups [%v]. Token %s",
 			md.Get("name"), md.Get("email"), md.Get("groups"), md.Get("at_hash"))
@@ -71,108 +86,57 @@ func (s Server) RollbackNetworkChange(ctx context.Context, req *admin.RollbackRe
 			return nil, err
 		}
 	}
-	errRollback := manager.GetManager().RollbackTargetConfig(networkchange.ID(req.Name))
-	if errRollback != nil {
-		return nil, errRollback
+	response, err := rollbackNetworkChange(ctx, &adminv2.RollbackNetworkChangeRequest{Id: req.Name, Comment: req.Comment})
+	if err != nil {
+		return nil, err
 	}
 	return &admin.RollbackResponse{
-		Message: fmt.Sprintf("Rolled back change '%s'", req.Name),
+		Message: fmt.Sprintf("Rolled back change '%s'", response.Id),
 	}, nil
 }
 
 // ListSnapshots lists snapshots for all devices
+// Deprecated: use ListSnapshots and WatchSnapshots of version 2
 func (s Server) ListSnapshots(r *admin.ListSnapshotsRequest, stream admin.ConfigAdminService_ListSnapshotsServer) error {
-	if stream.Context() != nil {
-		if md := metautils.ExtractIncoming(stream.Context()); md != nil && md.Get("name") != "" {
-			log.Infof("admin ListSnapshots() called by '%s (%s)'. Groups [%v]. Token %s",
-				md.Get("name"), md.Get("email"), md.Get("groups"), md.Get("at_hash"))
-		}
+	if md := metautils.ExtractIncoming(stream.Context()); md != nil && md.Get("name") != "" {
+		log.Infof("admin ListSnapshots() called by '%s (%s)'. Groups [%v]. Token %s",
+			md.Get("name"), md.Get("email"), md.Get("groups"), md.Get("at_hash"))
 	}
 	log.Infof("ListSnapshots called with %s. Subscribe %v", r.ID, r.Subscribe)
 
-	// There may be a wildcard given - we only want to reply with changes that match
-	matcher := utils.MatchWildcardChNameRegexp(string(r.ID), false)
-
+	// The ID of version 1 is a prefix, where version 2 matches the whole ID
+	filter := ""
+	if r.ID != "" {
+		filter = string(r.ID) + "*"
+	}
 	if r.Subscribe {
-		eventCh := make(chan streams.Event)
-		ctx, err := manager.GetManager().DeviceSnapshotStore.WatchAll(eventCh)
+		deprecatedStream(stream, "ListSnapshots", "WatchSnapshots")
+		err := watchSnapshots(stream.Context(), filter, func(_ adminv2.EventType, snapshot *adminv2.Snapshot) error {
+			return stream.Send(fromSnapshot(snapshot))
+		})
 		if err != nil {
-			log.Errorf("Error watching Network Changes %s", err)
-			return err
+			return errors.Status(err).Err()
 		}
-		defer ctx.Close()
-
-		for {
-			breakout := false
-			select { // Blocks until one of the following are received
-			case event, ok := <-eventCh:
-				if !ok { // Will happen at the end of stream
-					breakout = true
-					break
-				}
-
-				change := event.Object.(*devicesnapshot.Snapshot)
+		return nil
+	}
 
-				if matcher.MatchString(string(change.ID)) {
-					msg := change
-					log.Infof("Sending matching change %v", change.ID)
-					err := stream.Send(msg)
-					if err != nil {
-						log.Errorf("Error sending Snapshot %v %v", change.ID, err)
-						return err
-					}
-				}
-			case <-stream.Context().Done():
-				log.Infof("ListSnapshots remote client closed connection")
-				return nil
-			}
-			if breakout {
-				break
-			}
-		}
-	} else {
-		changeCh := make(chan *devicesnapshot.Snapshot)
-		ctx, err := manager.GetManager().DeviceSnapshotStore.LoadAll(changeCh)
-		if err != nil {
-			log.Errorf("Error ListSnapshots %s", err)
+	deprecatedStream(stream, "ListSnapshots", "ListSnapshots")
+	snapshots, err := listSnapshots(stream.Context(), filter)
+	if err != nil {
+		return errors.Status(err).Err()
+	}
+	for _, snapshot := range snapshots {
+		if err := stream.Send(fromSnapshot(snapshot)); err != nil {
 			return err
 		}
-		defer ctx.Close()
-
-		for {
-			breakout := false
-			select { // Blocks until one of the following are received
-			case change, ok := <-changeCh:
-				if !ok { // Will happen at the end of stream
-					breakout = true
-					break
-				}
-
-				if matcher.MatchString(string(change.ID)) {
-					msg := change
-					log.Infof("Sending matching change %v", change.ID)
-					err := stream.Send(msg)
-					if err != nil {
-						log.Errorf("Error sending Snapshot %v %v", change.ID, err)
-						return err
-					}
-				}
-			case <-stream.Context().Done():
-				log.Infof("ListSnapshots remote client closed connection")
-				return nil
-			}
-			if breakout {
-				break
-			}
-		}
 	}
-	log.Infof("Closing ListSnapshots for %s", r.ID)
-
 	return nil
 }
 
 // CompactChanges takes a snapshot of all devices
+// Deprecated: use CompactChanges of version 2
 func (s Server) CompactChanges(ctx context.Context, request *admin.CompactChangesRequest) (*admin.CompactChangesResponse, error) {
+	deprecated(ctx, "CompactChanges", "CompactChanges")
 	if md := metautils.ExtractIncoming(ctx); md != nil && md.Get("name") != "" {
 		log.Infof("admin CompactChanges() called by '%s (%s)'. Groups [%v]. Token %s",
 			md.Get("name"), md.Get("email"), md.Get("groups"), md.Get("at_hash"))
@@ -181,27 +145,12 @@ func (s Server) CompactChanges(ctx context.Context, request *admin.CompactChange
 			return nil, err
 		}
 	}
-	snap := &networksnapshot.NetworkSnapshot{
-		Retention: snapshot.RetentionOptions{
-			RetainWindow: request.RetentionPeriod,
-		},
-	}
-
-	ch := make(chan streams.Event)
-	stream, err := manager.GetManager().NetworkSnapshotStore.Watch(ch)
-	if err != nil {
-		return nil, err
+	req := &adminv2.CompactChangesRequest{}
+	if request.RetentionPeriod != nil {
+		req.RetentionPeriod = types.DurationProto(*request.RetentionPeriod)
 	}
-	defer stream.Close()
-	if err := manager.GetManager().NetworkSnapshotStore.Create(snap); err != nil {
+	if _, err := compactChanges(ctx, req); err != nil {
 		return nil, err
 	}
-
-	for event := range ch {
-		eventSnapshot := event.Object.(*networksnapshot.NetworkSnapshot)
-		if snap.ID != "" && snap.ID == eventSnapshot.ID && eventSnapshot.Status.Phase == snapshot.Phase_DELETE && eventSnapshot.Status.State == snapshot.State_COMPLETE {
-			return &admin.CompactChangesResponse{}, nil
-		}
-	}
-	return nil, errors.NewInvalid("snapshot state unknown")
+	return &admin.CompactChangesResponse{}, nil
 }
@@ -0,0 +1,144 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"sort"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/secrets"
+)
+
+// ExtServer implements the gRPC service for the administrative operations that are specific to
+// onos-config rather than part of the onos-api admin service. Every operation is restricted to
+// the administrators.
+type ExtServer struct {
+}
+
+// Rollback describes the per device operations rolling back a network change sends, and rolls
+// the change back if apply is set
+func (s ExtServer) Rollback(ctx context.Context, req *adminext.RollbackRequest) (*adminext.RollbackResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	deltas, err := manager.GetManager().PreviewRollback(networkchange.ID(req.Name))
+	if err != nil {
+		return nil, err
+	}
+	if req.Apply {
+		log.Infof("Rolling back change '%s' as requested by '%s'", req.Name, callerName(ctx))
+		if err := manager.GetManager().RollbackTargetConfig(networkchange.ID(req.Name)); err != nil {
+			return nil, rejectionError(err, networkchange.ID(req.Name))
+		}
+	}
+	devices := make([]*adminext.DeviceValues, 0, len(deltas))
+	for _, delta := range deltas {
+		devices = append(devices, changeValues(ctx, delta))
+	}
+	return &adminext.RollbackResponse{
+		Devices: devices,
+		Applied: req.Apply,
+	}, nil
+}
+
+// SearchValues finds the device paths whose current intended value equals the given value, or
+// matches it as a regular expression. Sensitive values are left out unless the caller may reveal them.
+func (s ExtServer) SearchValues(ctx context.Context, req *adminext.SearchValuesRequest) (*adminext.SearchValuesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	mgr := manager.GetManager()
+	results, err := mgr.SearchValues(req.Value, req.Regex)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make(map[devicetype.VersionedID]*adminext.DeviceValues)
+	for _, result := range results {
+		if !secrets.GetRegistry().Searchable(ctx, result.Path) {
+			continue
+		}
+		device, ok := devices[result.DeviceID]
+		if !ok {
+			device = &adminext.DeviceValues{
+				DeviceId:      string(result.DeviceID.GetID()),
+				DeviceVersion: string(result.DeviceID.GetVersion()),
+			}
+			for _, cached := range mgr.DeviceCache.GetDevicesByID(result.DeviceID.GetID()) {
+				if cached.Version == result.DeviceID.GetVersion() {
+					device.DeviceType = string(cached.Type)
+				}
+			}
+			devices[result.DeviceID] = device
+		}
+		device.Values = append(device.Values, pathValue(ctx, device.DeviceId, result.Path, result.Value, false))
+	}
+
+	response := &adminext.SearchValuesResponse{
+		Devices: make([]*adminext.DeviceValues, 0, len(devices)),
+	}
+	for _, device := range devices {
+		sort.Slice(device.Values, func(i, j int) bool {
+			return device.Values[i].Path < device.Values[j].Path
+		})
+		response.Devices = append(response.Devices, device)
+	}
+	sort.Slice(response.Devices, func(i, j int) bool {
+		if response.Devices[i].DeviceId != response.Devices[j].DeviceId {
+			return response.Devices[i].DeviceId < response.Devices[j].DeviceId
+		}
+		return response.Devices[i].DeviceVersion < response.Devices[j].DeviceVersion
+	})
+	return response, nil
+}
+
+// changeValues returns the values of a device change as they may be shown to the caller
+func changeValues(ctx context.Context, change *devicechange.Change) *adminext.DeviceValues {
+	values := make([]*adminext.PathValue, 0, len(change.Values))
+	for _, value := range change.Values {
+		values = append(values, pathValue(ctx, string(change.DeviceID), value.Path, value.Value, value.Removed))
+	}
+	return &adminext.DeviceValues{
+		DeviceId:      string(change.DeviceID),
+		DeviceVersion: string(change.DeviceVersion),
+		DeviceType:    string(change.DeviceType),
+		Values:        values,
+	}
+}
+
+// pathValue returns a value of a device as it may be shown to the caller
+func pathValue(ctx context.Context, deviceID string, path string, value *devicechange.TypedValue, removed bool) *adminext.PathValue {
+	pathValue := &adminext.PathValue{
+		Path:    path,
+		Removed: removed,
+	}
+	if value != nil && !removed {
+		value = secrets.GetRegistry().RedactValueFor(ctx, deviceID, path, value)
+		pathValue.Value = value.ValueToString()
+		pathValue.Type = value.Type.String()
+	}
+	return pathValue
+}
+
+func callerName(ctx context.Context) string {
+	name, _ := secrets.Caller(ctx)
+	return name
+}
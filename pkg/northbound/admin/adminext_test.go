@@ -0,0 +1,209 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/secrets"
+	"github.com/onosproject/onos-config/pkg/store/change/device/state"
+	networkstore "github.com/onosproject/onos-config/pkg/store/change/network"
+	devicecache "github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/store/leadership"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+// setUpExtServer creates a manager with mocked stores; the extended admin operations do not
+// need the model registry
+func setUpExtServer(t *testing.T) (*manager.Manager, context.Context) {
+	assert.NilError(t, os.Setenv(interceptors.AdminGroupsEnv, "AetherROCAdmin"))
+	t.Cleanup(func() { _ = os.Unsetenv(interceptors.AdminGroupsEnv) })
+
+	ctrl := gomock.NewController(t)
+	leadershipStore := mockstore.NewMockLeadershipStore(ctrl)
+	leadershipStore.EXPECT().Capabilities().Return(leadership.LocalCapabilities(), nil).AnyTimes()
+	mgrTest := manager.NewManager(
+		leadershipStore,
+		mockstore.NewMockMastershipStore(ctrl),
+		mockstore.NewMockDeviceChangesStore(ctrl),
+		mockstore.NewMockDeviceStateStore(ctrl),
+		mockstore.NewMockDeviceStore(ctrl),
+		cache.NewMockCache(ctrl),
+		mockstore.NewMockNetworkChangesStore(ctrl),
+		mockstore.NewMockNetworkSnapshotStore(ctrl),
+		mockstore.NewMockDeviceSnapshotStore(ctrl),
+		true,
+		nil)
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		interceptors.NameKey, "admin", interceptors.GroupsKey, "AetherROCAdmin"))
+	return mgrTest, adminCtx
+}
+
+// expectRollback sets up the stores for the rollback of change-2, whose previous change set /a/b
+func expectRollback(mgrTest *manager.Manager) *mockstore.MockNetworkChangesStore {
+	mockNwChStore := mgrTest.NetworkChangesStore.(*mockstore.MockNetworkChangesStore)
+	mockDevChStore := mgrTest.DeviceChangesStore.(*mockstore.MockDeviceChangesStore)
+
+	change := &networkchange.NetworkChange{
+		ID:    "change-2",
+		Index: 2,
+		Changes: []*devicechange.Change{
+			{
+				DeviceID:      "device-1",
+				DeviceVersion: "1.0.0",
+				DeviceType:    "Devicesim",
+				Values: []*devicechange.ChangeValue{
+					{Path: "/a/b", Value: devicechange.NewTypedValueString("new")},
+					{Path: "/a/c", Value: devicechange.NewTypedValueString("added")},
+				},
+			},
+		},
+	}
+	previous := &devicechange.DeviceChange{
+		ID:            "change-1:device-1:1.0.0",
+		NetworkChange: devicechange.NetworkChangeRef{ID: "change-1"},
+		Change: &devicechange.Change{
+			DeviceID:      "device-1",
+			DeviceVersion: "1.0.0",
+			Values: []*devicechange.ChangeValue{
+				{Path: "/a/b", Value: devicechange.NewTypedValueString("old")},
+			},
+		},
+	}
+
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-2")).Return(change, nil).AnyTimes()
+	mockNwChStore.EXPECT().GetNext(networkchange.Index(2)).Return(nil, nil).AnyTimes()
+	mockDevChStore.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(id device.VersionedID, ch chan<- *devicechange.DeviceChange) (stream.Context, error) {
+			go func() {
+				ch <- previous
+				close(ch)
+			}()
+			return stream.NewContext(func() {}), nil
+		})
+	return mockNwChStore
+}
+
+func Test_RollbackPreview(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	// No Update is expected; the rollback must not be applied
+	expectRollback(mgrTest)
+
+	response, err := ExtServer{}.Rollback(adminCtx, &adminext.RollbackRequest{Name: "change-2"})
+	assert.NilError(t, err)
+	assert.Assert(t, !response.Applied)
+	assert.Equal(t, 1, len(response.Devices))
+	assert.Equal(t, "device-1", response.Devices[0].DeviceId)
+	assert.Equal(t, "Devicesim", response.Devices[0].DeviceType)
+	assert.DeepEqual(t, []*adminext.PathValue{
+		{Path: "/a/b", Value: "old", Type: "STRING"},
+		{Path: "/a/c", Removed: true},
+	}, response.Devices[0].Values)
+}
+
+func Test_RollbackApply(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mockNwChStore := expectRollback(mgrTest)
+
+	var rolledBack *networkchange.NetworkChange
+	mockNwChStore.EXPECT().Update(gomock.Any()).DoAndReturn(func(change *networkchange.NetworkChange) error {
+		rolledBack = change
+		return nil
+	})
+	mockNwChStore.EXPECT().Watch(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ch chan<- stream.Event, opts ...networkstore.WatchOption) (stream.Context, error) {
+			go func() {
+				complete := *rolledBack
+				complete.Status.State = changetypes.State_COMPLETE
+				ch <- stream.Event{Type: stream.Updated, Object: &complete}
+				close(ch)
+			}()
+			return stream.NewContext(func() {}), nil
+		})
+
+	response, err := ExtServer{}.Rollback(adminCtx, &adminext.RollbackRequest{Name: "change-2", Apply: true})
+	assert.NilError(t, err)
+	assert.Assert(t, response.Applied)
+	assert.Equal(t, changetypes.Phase_ROLLBACK, rolledBack.Status.Phase)
+	assert.Equal(t, 2, len(response.Devices[0].Values))
+}
+
+func Test_RollbackUnauthorized(t *testing.T) {
+	setUpExtServer(t)
+	_, err := ExtServer{}.Rollback(context.Background(), &adminext.RollbackRequest{Name: "change-2", Apply: true})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	userCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		interceptors.NameKey, "user", interceptors.GroupsKey, "operators"))
+	_, err = ExtServer{}.Rollback(userCtx, &adminext.RollbackRequest{Name: "change-2"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func Test_SearchValues(t *testing.T) {
+	const secretPath = "/system/aaa/authentication/admin-user/config/admin-password"
+	secrets.GetRegistry().Register(secretPath)
+	mgrTest, adminCtx := setUpExtServer(t)
+	mockStateStore := mgrTest.DeviceStateStore.(*mockstore.MockDeviceStateStore)
+	mockCache := mgrTest.DeviceCache.(*cache.MockCache)
+
+	mockStateStore.EXPECT().Search("^s3", true).Return([]*state.SearchResult{
+		{DeviceID: device.NewVersionedID("device-2", "1.0.0"), Path: "/system/config/hostname", Value: devicechange.NewTypedValueString("s3-2")},
+		{DeviceID: device.NewVersionedID("device-1", "1.0.0"), Path: secretPath, Value: devicechange.NewTypedValueString("s3cr3t")},
+		{DeviceID: device.NewVersionedID("device-1", "1.0.0"), Path: "/system/config/hostname", Value: devicechange.NewTypedValueString("s3-1")},
+	}, nil).Times(2)
+	mockCache.EXPECT().GetDevicesByID(gomock.Any()).DoAndReturn(func(id device.ID) []*devicecache.Info {
+		return []*devicecache.Info{{DeviceID: id, Type: "Devicesim", Version: "1.0.0"}}
+	}).AnyTimes()
+
+	response, err := ExtServer{}.SearchValues(adminCtx, &adminext.SearchValuesRequest{Value: "^s3", Regex: true})
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(response.Devices))
+	assert.Equal(t, "device-1", response.Devices[0].DeviceId)
+	assert.Equal(t, "Devicesim", response.Devices[0].DeviceType)
+	assert.DeepEqual(t, []*adminext.PathValue{
+		{Path: "/system/config/hostname", Value: "s3-1", Type: "STRING"},
+	}, response.Devices[0].Values)
+	assert.Equal(t, "device-2", response.Devices[1].DeviceId)
+
+	// The sensitive value is only matched for the callers who may reveal it
+	revealCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		interceptors.NameKey, "admin", interceptors.GroupsKey, "AetherROCAdmin;"+secrets.RevealSecretsGroup))
+	response, err = ExtServer{}.SearchValues(revealCtx, &adminext.SearchValuesRequest{Value: "^s3", Regex: true})
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(response.Devices[0].Values))
+	assert.Equal(t, secretPath, response.Devices[0].Values[0].Path)
+	assert.Equal(t, "s3cr3t", response.Devices[0].Values[0].Value)
+
+	userCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		interceptors.NameKey, "user", interceptors.GroupsKey, "operators"))
+	_, err = ExtServer{}.SearchValues(userCtx, &adminext.SearchValuesRequest{Value: "^s3", Regex: true})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,37 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_AdoptConfigInvalid(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+	_, err := ExtServer{}.AdoptConfig(adminCtx, &adminext.AdoptConfigRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func Test_AdoptConfigUnauthenticated(t *testing.T) {
+	setUpExtServer(t)
+	_, err := ExtServer{}.AdoptConfig(context.Background(), &adminext.AdoptConfigRequest{DeviceId: "device-1"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
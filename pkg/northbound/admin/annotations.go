@@ -0,0 +1,139 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/annotation"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// AddAnnotation attaches a note of the caller to a device or a network change
+func (s ExtServer) AddAnnotation(ctx context.Context, req *adminext.AddAnnotationRequest) (*adminext.AddAnnotationResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	kind, target, err := annotationTarget(req.DeviceId, req.NetworkChange)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	} else if kind == "" {
+		return nil, errors.Status(errors.NewInvalid("no device or network change given")).Err()
+	}
+	a, err := manager.GetManager().Annotate(kind, target, req.Note, callerName(ctx))
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    a.Author,
+		Action:  "add-annotation",
+		Target:  target,
+		Message: a.Note,
+	})
+	return &adminext.AddAnnotationResponse{
+		Annotation: newAnnotation(a),
+	}, nil
+}
+
+// DeleteAnnotation deletes an annotation
+func (s ExtServer) DeleteAnnotation(ctx context.Context, req *adminext.DeleteAnnotationRequest) (*adminext.DeleteAnnotationResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	a, err := manager.GetManager().RemoveAnnotation(req.Id)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "delete-annotation",
+		Target:  a.Target,
+		Message: "deleted the note of " + a.Author + ": " + a.Note,
+	})
+	return &adminext.DeleteAnnotationResponse{
+		Annotation: newAnnotation(a),
+	}, nil
+}
+
+// ListAnnotations lists the annotations of a device or a network change, or all of them
+func (s ExtServer) ListAnnotations(ctx context.Context, req *adminext.ListAnnotationsRequest) (*adminext.ListAnnotationsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	kind, target, err := annotationTarget(req.DeviceId, req.NetworkChange)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	annotations, err := manager.GetManager().AnnotationStore.List(kind, target)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	response := &adminext.ListAnnotationsResponse{
+		Annotations: make([]*adminext.Annotation, 0, len(annotations)),
+	}
+	for _, a := range annotations {
+		response.Annotations = append(response.Annotations, newAnnotation(a))
+	}
+	return response, nil
+}
+
+// annotationTarget returns the object given by a request, if any
+func annotationTarget(deviceID string, networkChange string) (annotation.Kind, string, error) {
+	switch {
+	case deviceID != "" && networkChange != "":
+		return "", "", errors.NewInvalid("both a device and a network change given")
+	case deviceID != "":
+		return annotation.Device, deviceID, nil
+	case networkChange != "":
+		return annotation.NetworkChange, networkChange, nil
+	}
+	return "", "", nil
+}
+
+// listAnnotations returns the annotations of the objects of a kind, by object, for listings
+func listAnnotations(kind annotation.Kind) (map[string][]*adminext.Annotation, error) {
+	annotations, err := manager.GetManager().AnnotationStore.List(kind, "")
+	if err != nil {
+		return nil, err
+	}
+	byTarget := make(map[string][]*adminext.Annotation)
+	for _, a := range annotations {
+		byTarget[a.Target] = append(byTarget[a.Target], newAnnotation(a))
+	}
+	return byTarget, nil
+}
+
+func newAnnotation(a *annotation.Annotation) *adminext.Annotation {
+	result := &adminext.Annotation{
+		Id:     a.ID,
+		Note:   a.Note,
+		Author: a.Author,
+	}
+	switch a.Kind {
+	case annotation.Device:
+		result.DeviceId = a.Target
+	case annotation.NetworkChange:
+		result.NetworkChange = a.Target
+	}
+	if created, err := types.TimestampProto(a.Created); err == nil {
+		result.Created = created
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/store/change/pause"
+	devicecache "github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/store/quarantine"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_Annotations(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mockCache := mgrTest.DeviceCache.(*cache.MockCache)
+	mockDeviceStore := mgrTest.DeviceStore.(*mockstore.MockDeviceStore)
+	mockNwChStore := mgrTest.NetworkChangesStore.(*mockstore.MockNetworkChangesStore)
+	mockCache.EXPECT().GetDevicesByID(devicetype.ID("device-1")).Return([]*devicecache.Info{
+		{DeviceID: "device-1", Type: "Devicesim", Version: "1.0.0"},
+	}).AnyTimes()
+	mockCache.EXPECT().GetDevicesByID(devicetype.ID("device-9")).Return(nil).AnyTimes()
+	mockDeviceStore.EXPECT().Get(topodevice.ID("device-9")).Return(nil, errors.NewNotFound("device-9 not found")).AnyTimes()
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-3")).Return(&networkchange.NetworkChange{ID: "change-3", Index: 3}, nil).AnyTimes()
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-9")).Return(nil, nil).AnyTimes()
+
+	added, err := ExtServer{}.AddAnnotation(adminCtx, &adminext.AddAnnotationRequest{
+		DeviceId: "device-1",
+		Note:     "do not touch until Friday",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, added.Annotation.DeviceId, "device-1")
+	assert.Equal(t, added.Annotation.Author, "admin")
+	assert.Assert(t, added.Annotation.Id != "")
+	assert.Assert(t, added.Annotation.Created != nil)
+	entries := audit.Entries()
+	assert.Equal(t, entries[len(entries)-1].Action, "add-annotation")
+	assert.Equal(t, entries[len(entries)-1].Target, "device-1")
+	_, err = ExtServer{}.AddAnnotation(adminCtx, &adminext.AddAnnotationRequest{
+		NetworkChange: "change-3",
+		Note:          "for the maintenance of rack 4",
+	})
+	assert.NilError(t, err)
+
+	_, err = ExtServer{}.AddAnnotation(adminCtx, &adminext.AddAnnotationRequest{DeviceId: "device-9", Note: "spare"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = ExtServer{}.AddAnnotation(adminCtx, &adminext.AddAnnotationRequest{NetworkChange: "change-9", Note: "spare"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = ExtServer{}.AddAnnotation(adminCtx, &adminext.AddAnnotationRequest{DeviceId: "device-1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.AddAnnotation(adminCtx, &adminext.AddAnnotationRequest{Note: "spare"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.AddAnnotation(adminCtx, &adminext.AddAnnotationRequest{DeviceId: "device-1", NetworkChange: "change-3", Note: "spare"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	list, err := ExtServer{}.ListAnnotations(adminCtx, &adminext.ListAnnotationsRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(list.Annotations), 2)
+	list, err = ExtServer{}.ListAnnotations(adminCtx, &adminext.ListAnnotationsRequest{NetworkChange: "change-3"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(list.Annotations), 1)
+	assert.Equal(t, list.Annotations[0].Note, "for the maintenance of rack 4")
+
+	// Annotations are returned in the listings of devices and changes
+	assert.NilError(t, mgrTest.QuarantineStore.Put(&quarantine.Quarantine{DeviceID: "device-1"}))
+	quarantined, err := ExtServer{}.ListQuarantinedDevices(adminCtx, &adminext.ListQuarantinedDevicesRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(quarantined.Devices), 1)
+	assert.Equal(t, len(quarantined.Devices[0].Annotations), 1)
+	assert.Equal(t, quarantined.Devices[0].Annotations[0].Note, "do not touch until Friday")
+	assert.NilError(t, mgrTest.PauseStore.Put(&pause.Pause{NetworkChangeID: "change-3"}))
+	paused, err := ExtServer{}.ListPausedChanges(adminCtx, &adminext.ListPausedChangesRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(paused.Changes), 1)
+	assert.Equal(t, len(paused.Changes[0].Annotations), 1)
+	assert.Equal(t, paused.Changes[0].Annotations[0].NetworkChange, "change-3")
+
+	deleted, err := ExtServer{}.DeleteAnnotation(adminCtx, &adminext.DeleteAnnotationRequest{Id: added.Annotation.Id})
+	assert.NilError(t, err)
+	assert.Equal(t, deleted.Annotation.Note, "do not touch until Friday")
+	entries = audit.Entries()
+	assert.Equal(t, entries[len(entries)-1].Action, "delete-annotation")
+	_, err = ExtServer{}.DeleteAnnotation(adminCtx, &adminext.DeleteAnnotationRequest{Id: added.Annotation.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	list, err = ExtServer{}.ListAnnotations(adminCtx, &adminext.ListAnnotationsRequest{DeviceId: "device-1"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(list.Annotations), 0)
+
+	_, err = ExtServer{}.ListAnnotations(context.Background(), &adminext.ListAnnotationsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
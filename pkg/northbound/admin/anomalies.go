@@ -0,0 +1,117 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/controller/change/anomaly"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// anomaliesBuffer is the number of anomalies a WatchChangeAnomalies stream may fall behind by
+// before it misses some
+const anomaliesBuffer = 100
+
+// ListChangeAnomalies lists the paths changing far more often than usual, and those that did
+// recently
+func (s ExtServer) ListChangeAnomalies(ctx context.Context, req *adminext.ListChangeAnomaliesRequest) (*adminext.ListChangeAnomaliesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	response := &adminext.ListChangeAnomaliesResponse{
+		Ongoing: make([]*adminext.ChangeAnomaly, 0),
+		Cleared: make([]*adminext.ChangeAnomaly, 0),
+	}
+	detector := manager.GetManager().AnomalyDetector
+	if detector == nil {
+		return response, nil
+	}
+	response.Ratio = detector.Ratio()
+	for _, a := range detector.Anomalies() {
+		if req.DeviceId == "" || string(a.DeviceID) == req.DeviceId {
+			response.Ongoing = append(response.Ongoing, anomalyProto(a))
+		}
+	}
+	for _, a := range detector.Cleared() {
+		if req.DeviceId == "" || string(a.DeviceID) == req.DeviceId {
+			response.Cleared = append(response.Cleared, anomalyProto(a))
+		}
+	}
+	return response, nil
+}
+
+// WatchChangeAnomalies streams the ongoing anomalies, then the anomalies as they start and clear
+// until the client cancels the stream
+func (s ExtServer) WatchChangeAnomalies(req *adminext.WatchChangeAnomaliesRequest, stream adminext.ConfigAdminExtService_WatchChangeAnomaliesServer) error {
+	ctx := stream.Context()
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return err
+	}
+	detector := manager.GetManager().AnomalyDetector
+	if detector == nil {
+		return errors.Status(errors.NewUnavailable("the anomaly detection is disabled")).Err()
+	}
+	ch := make(chan anomaly.Event, anomaliesBuffer)
+	for _, a := range detector.Watch(ctx, ch) {
+		if req.DeviceId != "" && string(a.DeviceID) != req.DeviceId {
+			continue
+		}
+		if err := stream.Send(&adminext.ChangeAnomalyEvent{Type: adminext.ChangeAnomalyEventType_ONGOING, Anomaly: anomalyProto(a)}); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case event := <-ch:
+			if req.DeviceId != "" && string(event.Anomaly.DeviceID) != req.DeviceId {
+				continue
+			}
+			eventType := adminext.ChangeAnomalyEventType_STARTED
+			if event.Type == anomaly.EventCleared {
+				eventType = adminext.ChangeAnomalyEventType_CLEARED
+			}
+			if err := stream.Send(&adminext.ChangeAnomalyEvent{Type: eventType, Anomaly: anomalyProto(event.Anomaly)}); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func anomalyProto(a anomaly.Anomaly) *adminext.ChangeAnomaly {
+	result := &adminext.ChangeAnomaly{
+		DeviceId:         string(a.DeviceID),
+		Path:             a.Path,
+		BaselineInterval: types.DurationProto(a.Baseline),
+		RecentInterval:   types.DurationProto(a.Recent),
+		Changes:          uint32(a.Changes),
+		NetworkChangeId:  string(a.NetworkChange),
+	}
+	if started, err := types.TimestampProto(a.Started); err == nil {
+		result.Started = started
+	}
+	if !a.Cleared.IsZero() {
+		if cleared, err := types.TimestampProto(a.Cleared); err == nil {
+			result.Cleared = cleared
+		}
+	}
+	return result
+}
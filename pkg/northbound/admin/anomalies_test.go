@@ -0,0 +1,106 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+// anomaliesStream collects the events sent by WatchChangeAnomalies
+type anomaliesStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events []*adminext.ChangeAnomalyEvent
+}
+
+func (s *anomaliesStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *anomaliesStream) Send(event *adminext.ChangeAnomalyEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func Test_ChangeAnomalies(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	response, err := ExtServer{}.ListChangeAnomalies(adminCtx, &adminext.ListChangeAnomaliesRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Ratio, float64(0))
+	assert.Equal(t, len(response.Ongoing), 0)
+	err = ExtServer{}.WatchChangeAnomalies(&adminext.WatchChangeAnomaliesRequest{}, &anomaliesStream{ctx: adminCtx})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	mgrTest.SetAnomalyDetector(10)
+	defer func() { mgrTest.AnomalyDetector = nil }()
+
+	// A path of device-1 changing hourly, then every second
+	now := time.Now().Add(-24 * time.Hour)
+	for i := 0; i < 20; i++ {
+		if i < 10 {
+			now = now.Add(time.Hour)
+		} else {
+			now = now.Add(time.Second)
+		}
+		mgrTest.AnomalyDetector.Record(&networkchange.NetworkChange{
+			ID:      networkchange.ID(fmt.Sprintf("change-%d", i)),
+			Created: now,
+			Updated: now,
+			Status:  changetypes.Status{Phase: changetypes.Phase_CHANGE, State: changetypes.State_COMPLETE},
+			Changes: []*devicechange.Change{{
+				DeviceID: "device-1",
+				Values:   []*devicechange.ChangeValue{{Path: "/system/config/motd-banner"}},
+			}},
+		})
+	}
+
+	response, err = ExtServer{}.ListChangeAnomalies(adminCtx, &adminext.ListChangeAnomaliesRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Ratio, float64(10))
+	assert.Equal(t, len(response.Ongoing), 1)
+	assert.Equal(t, response.Ongoing[0].DeviceId, "device-1")
+	assert.Equal(t, response.Ongoing[0].Path, "/system/config/motd-banner")
+	assert.Equal(t, response.Ongoing[0].NetworkChangeId, "change-19")
+	assert.Assert(t, response.Ongoing[0].Cleared == nil)
+	assert.Equal(t, len(response.Cleared), 0)
+
+	response, err = ExtServer{}.ListChangeAnomalies(adminCtx, &adminext.ListChangeAnomaliesRequest{DeviceId: "device-2"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Ongoing), 0)
+
+	ctx, cancel := context.WithCancel(adminCtx)
+	cancel()
+	stream := &anomaliesStream{ctx: ctx}
+	assert.NilError(t, ExtServer{}.WatchChangeAnomalies(&adminext.WatchChangeAnomaliesRequest{}, stream))
+	assert.Equal(t, len(stream.events), 1)
+	assert.Equal(t, stream.events[0].Type, adminext.ChangeAnomalyEventType_ONGOING)
+
+	_, err = ExtServer{}.ListChangeAnomalies(context.Background(), &adminext.ListChangeAnomaliesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	err = ExtServer{}.WatchChangeAnomalies(&adminext.WatchChangeAnomaliesRequest{}, &anomaliesStream{ctx: context.Background()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,79 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"sort"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/annotation"
+	devicechangeutils "github.com/onosproject/onos-config/pkg/store/change/device/utils"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ListAppliedIndexes lists the high-water mark of the network changes applied to each device
+func (s ExtServer) ListAppliedIndexes(ctx context.Context, req *adminext.ListAppliedIndexesRequest) (*adminext.ListAppliedIndexesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	mgr := manager.GetManager()
+	var infos []*cache.Info
+	if req.DeviceId != "" {
+		infos = mgr.DeviceCache.GetDevicesByID(devicetype.ID(req.DeviceId))
+		if len(infos) == 0 {
+			return nil, errors.Status(errors.NewNotFound("device '%s' has no changes", req.DeviceId)).Err()
+		}
+	} else {
+		infos = mgr.DeviceCache.GetDevices()
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].DeviceID != infos[j].DeviceID {
+			return infos[i].DeviceID < infos[j].DeviceID
+		}
+		return infos[i].Version < infos[j].Version
+	})
+
+	annotations, err := listAnnotations(annotation.Device)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	response := &adminext.ListAppliedIndexesResponse{
+		Devices: make([]*adminext.DeviceAppliedIndex, 0, len(infos)),
+	}
+	for _, info := range infos {
+		applied, err := devicechangeutils.GetAppliedIndex(devicetype.NewVersionedID(info.DeviceID, info.Version), mgr.DeviceChangesStore)
+		if err != nil {
+			return nil, errors.Status(err).Err()
+		}
+		device := &adminext.DeviceAppliedIndex{
+			DeviceId:      string(info.DeviceID),
+			DeviceVersion: string(info.Version),
+			AppliedIndex:  uint64(applied.Index),
+			AppliedChange: string(applied.NetworkChange),
+			Pending:       make([]string, 0, len(applied.Pending)),
+			Annotations:   annotations[string(info.DeviceID)],
+		}
+		for _, deviceChange := range applied.Pending {
+			device.Pending = append(device.Pending, string(deviceChange.NetworkChange.ID))
+		}
+		response.Devices = append(response.Devices, device)
+	}
+	return response, nil
+}
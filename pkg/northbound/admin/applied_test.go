@@ -0,0 +1,75 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	types "github.com/onosproject/onos-api/go/onos/config"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	devicecache "github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_ListAppliedIndexes(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mockDevChStore := mgrTest.DeviceChangesStore.(*mockstore.MockDeviceChangesStore)
+	mockCache := mgrTest.DeviceCache.(*cache.MockCache)
+
+	mockCache.EXPECT().GetDevices().Return([]*devicecache.Info{
+		{DeviceID: "device-2", Type: "Devicesim", Version: "1.0.0"},
+		{DeviceID: "device-1", Type: "Devicesim", Version: "1.0.0"},
+	})
+	mockCache.EXPECT().GetDevicesByID(devicetype.ID("device-3")).Return(nil)
+	deviceChanges := map[devicetype.VersionedID][]*devicechange.DeviceChange{
+		"device-1:1.0.0": {
+			{Index: 1, NetworkChange: devicechange.NetworkChangeRef{ID: types.ID("change-1")},
+				Status: changetypes.Status{State: changetypes.State_COMPLETE}},
+			{Index: 3, NetworkChange: devicechange.NetworkChangeRef{ID: types.ID("change-3")}},
+		},
+	}
+	mockDevChStore.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(id devicetype.VersionedID, c chan<- *devicechange.DeviceChange) (stream.Context, error) {
+			go func() {
+				for _, deviceChange := range deviceChanges[id] {
+					c <- deviceChange
+				}
+				close(c)
+			}()
+			return stream.NewContext(func() {}), nil
+		}).Times(2)
+
+	response, err := ExtServer{}.ListAppliedIndexes(adminCtx, &adminext.ListAppliedIndexesRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Devices), 2)
+	assert.Equal(t, response.Devices[0].DeviceId, "device-1")
+	assert.Equal(t, response.Devices[0].AppliedIndex, uint64(1))
+	assert.Equal(t, response.Devices[0].AppliedChange, "change-1")
+	assert.DeepEqual(t, response.Devices[0].Pending, []string{"change-3"})
+	assert.Equal(t, response.Devices[1].DeviceId, "device-2")
+	assert.Equal(t, response.Devices[1].AppliedIndex, uint64(0))
+
+	_, err = ExtServer{}.ListAppliedIndexes(adminCtx, &adminext.ListAppliedIndexesRequest{DeviceId: "device-3"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
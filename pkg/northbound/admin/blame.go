@@ -0,0 +1,63 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// BlameConfig returns the intended values of a device with the change and the artifact they come from
+func (s ExtServer) BlameConfig(ctx context.Context, req *adminext.BlameConfigRequest) (*adminext.BlameConfigResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.DeviceId == "" {
+		return nil, errors.Status(errors.NewInvalid("no device given")).Err()
+	}
+	values, err := manager.GetManager().BlameConfig(devicetype.ID(req.DeviceId), devicetype.Version(req.DeviceVersion), req.Path)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+
+	response := &adminext.BlameConfigResponse{
+		Values: make([]*adminext.BlamedValue, 0, len(values)),
+	}
+	for _, value := range values {
+		blamed := &adminext.BlamedValue{
+			Value:           pathValue(ctx, req.DeviceId, value.Path, value.Value, false),
+			NetworkChangeId: value.NetworkChange,
+			ManagedBy:       value.ManagedBy(),
+		}
+		if !value.SetAt.IsZero() {
+			if setAt, err := types.TimestampProto(value.SetAt); err == nil {
+				blamed.SetAt = setAt
+			}
+		}
+		if value.Provenance != nil {
+			blamed.ProvenanceKind = string(value.Provenance.Kind)
+			blamed.ProvenanceArtifact = value.Provenance.Artifact
+			blamed.User = value.Provenance.User
+		}
+		response.Values = append(response.Values, blamed)
+	}
+	return response, nil
+}
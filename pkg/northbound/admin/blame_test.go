@@ -0,0 +1,99 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/store/change/provenance"
+	devicecache "github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_BlameConfig(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mgrTest.DeviceCache.(*cache.MockCache).EXPECT().GetDevicesByID(gomock.Any()).DoAndReturn(func(id device.ID) []*devicecache.Info {
+		return []*devicecache.Info{{DeviceID: id, Type: "Devicesim", Version: "1.0.0"}}
+	}).AnyTimes()
+	mgrTest.DeviceStore.(*mockstore.MockDeviceStore).EXPECT().Get(gomock.Any()).
+		Return(nil, errors.NewNotFound("not found")).AnyTimes()
+	mgrTest.DeviceStateStore.(*mockstore.MockDeviceStateStore).EXPECT().Get(gomock.Any(), gomock.Any()).
+		Return([]*devicechange.PathValue{
+			{Path: "/a/b", Value: devicechange.NewTypedValueString("templated")},
+			{Path: "/a/c", Value: devicechange.NewTypedValueString("direct")},
+		}, nil).AnyTimes()
+	mgrTest.DeviceChangesStore.(*mockstore.MockDeviceChangesStore).EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(id device.VersionedID, ch chan<- *devicechange.DeviceChange) (stream.Context, error) {
+			go func() {
+				ch <- &devicechange.DeviceChange{
+					Index:         1,
+					NetworkChange: devicechange.NetworkChangeRef{ID: "change-1"},
+					Change: &devicechange.Change{Values: []*devicechange.ChangeValue{
+						{Path: "/a/b", Value: devicechange.NewTypedValueString("templated")},
+					}},
+					Status: changetypes.Status{State: changetypes.State_COMPLETE},
+				}
+				ch <- &devicechange.DeviceChange{
+					Index:         2,
+					NetworkChange: devicechange.NetworkChangeRef{ID: "change-2"},
+					Change: &devicechange.Change{Values: []*devicechange.ChangeValue{
+						{Path: "/a/c", Value: devicechange.NewTypedValueString("direct")},
+					}},
+					Status: changetypes.Status{State: changetypes.State_COMPLETE},
+				}
+				close(ch)
+			}()
+			return stream.NewContext(func() {}), nil
+		}).AnyTimes()
+	assert.NilError(t, mgrTest.ProvenanceStore.Create(&provenance.Provenance{
+		ChangeID: "change-1",
+		Kind:     provenance.Template,
+		Artifact: "access-ports",
+		User:     "alice",
+	}))
+
+	response, err := ExtServer{}.BlameConfig(adminCtx, &adminext.BlameConfigRequest{DeviceId: "device-1"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Values), 2)
+	templated := response.Values[0]
+	assert.Equal(t, templated.Value.Path, "/a/b")
+	assert.Equal(t, templated.Value.Value, "templated")
+	assert.Equal(t, templated.NetworkChangeId, "change-1")
+	assert.Equal(t, templated.ProvenanceKind, "template")
+	assert.Equal(t, templated.ProvenanceArtifact, "access-ports")
+	assert.Equal(t, templated.User, "alice")
+	assert.Equal(t, templated.ManagedBy, "managed by template access-ports: edit there instead")
+	direct := response.Values[1]
+	assert.Equal(t, direct.NetworkChangeId, "change-2")
+	assert.Equal(t, direct.ProvenanceKind, "")
+	assert.Equal(t, direct.ManagedBy, "")
+
+	_, err = ExtServer{}.BlameConfig(adminCtx, &adminext.BlameConfigRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.BlameConfig(context.Background(), &adminext.BlameConfigRequest{DeviceId: "device-1"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
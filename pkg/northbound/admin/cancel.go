@@ -0,0 +1,62 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// CancelChange stops a pending network change, rolling back what it applied if requested
+func (s ExtServer) CancelChange(ctx context.Context, req *adminext.CancelChangeRequest) (*adminext.CancelChangeResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	user := callerName(ctx)
+	message := fmt.Sprintf("by '%s'", user)
+	if req.Reason != "" {
+		message = fmt.Sprintf("%s: %s", message, req.Reason)
+	}
+	change, err := manager.GetManager().CancelNetworkChange(networkchange.ID(req.Name), req.Rollback, message)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	auditMessage := "cancelled"
+	if req.Rollback {
+		auditMessage = "cancelled, rolling back"
+	}
+	if req.Reason != "" {
+		auditMessage = fmt.Sprintf("%s: %s", auditMessage, req.Reason)
+	}
+	audit.Record(audit.Entry{
+		User:    user,
+		Action:  "cancel-change",
+		Target:  req.Name,
+		Message: auditMessage,
+	})
+	return &adminext.CancelChangeResponse{
+		Name:    string(change.ID),
+		Phase:   change.Status.Phase.String(),
+		State:   change.Status.State.String(),
+		Message: change.Status.Message,
+	}, nil
+}
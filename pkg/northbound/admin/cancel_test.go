@@ -0,0 +1,71 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_CancelChange(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mockNwChStore := mgrTest.NetworkChangesStore.(*mockstore.MockNetworkChangesStore)
+	pending := &networkchange.NetworkChange{ID: "change-3", Index: 3, Revision: 1}
+	complete := &networkchange.NetworkChange{ID: "change-2", Index: 2, Revision: 1,
+		Status: changetypes.Status{State: changetypes.State_COMPLETE}}
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-3")).Return(pending, nil).AnyTimes()
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-2")).Return(complete, nil).AnyTimes()
+	mockNwChStore.EXPECT().Update(pending).Return(nil)
+
+	_, err := ExtServer{}.CancelChange(adminCtx, &adminext.CancelChangeRequest{Name: "change-2"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = ExtServer{}.CancelChange(adminCtx, &adminext.CancelChangeRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	response, err := ExtServer{}.CancelChange(adminCtx, &adminext.CancelChangeRequest{Name: "change-3", Reason: "bad rollout"})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Name, "change-3")
+	assert.Equal(t, response.Phase, "CHANGE")
+	assert.Equal(t, response.State, "FAILED")
+	assert.Equal(t, response.Message, "Cancelled: by 'admin': bad rollout")
+	entries := audit.Entries()
+	assert.Equal(t, entries[len(entries)-1].Action, "cancel-change")
+	assert.Equal(t, entries[len(entries)-1].Message, "cancelled: bad rollout")
+
+	_, err = ExtServer{}.CancelChange(context.Background(), &adminext.CancelChangeRequest{Name: "change-3"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_CancelChangeRollbackNotLast(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mockNwChStore := mgrTest.NetworkChangesStore.(*mockstore.MockNetworkChangesStore)
+	pending := &networkchange.NetworkChange{ID: "change-3", Index: 3, Revision: 1}
+	next := &networkchange.NetworkChange{ID: "change-4", Index: 4, Revision: 1}
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-3")).Return(pending, nil)
+	mockNwChStore.EXPECT().GetNext(networkchange.Index(3)).Return(next, nil)
+
+	_, err := ExtServer{}.CancelChange(adminCtx, &adminext.CancelChangeRequest{Name: "change-3", Rollback: true})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.ErrorContains(t, err, "change-4 was made after it")
+}
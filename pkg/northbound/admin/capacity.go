@@ -0,0 +1,55 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/capacity"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+)
+
+// GetCapacity returns the usage and the limits of the devices and of the network changes
+func (s ExtServer) GetCapacity(ctx context.Context, req *adminext.GetCapacityRequest) (*adminext.GetCapacityResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	guard := capacity.GetGuard()
+	limits := guard.Limits()
+	usage := guard.Usage()
+	rejections := guard.Rejections()
+	response := &adminext.GetCapacityResponse{
+		Resources: []*adminext.CapacityResource{
+			{Resource: capacity.ResourceDevices, Usage: uint32(usage.Devices), Limit: uint32(limits.MaxDevices)},
+			{Resource: capacity.ResourcePendingChanges, Usage: uint32(usage.PendingChanges), Limit: uint32(limits.MaxPendingChanges)},
+			{Resource: capacity.ResourceStoredChanges, Usage: uint32(usage.StoredChanges), Limit: uint32(limits.MaxStoredChanges)},
+		},
+	}
+	alerts := make(map[string]capacity.Alert)
+	for _, alert := range guard.Alerts() {
+		alerts[alert.Resource] = alert
+	}
+	for _, resource := range response.Resources {
+		resource.Rejections = rejections[resource.Resource]
+		if alert, ok := alerts[resource.Resource]; ok {
+			if since, err := types.TimestampProto(alert.Since); err == nil {
+				resource.LimitReachedSince = since
+			}
+		}
+	}
+	return response, nil
+}
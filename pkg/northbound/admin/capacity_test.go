@@ -0,0 +1,58 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/capacity"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_GetCapacity(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+	guard := capacity.GetGuard()
+	guard.SetLimits(capacity.Limits{MaxDevices: 1, MaxPendingChanges: 10})
+	assert.NilError(t, guard.AdmitDevice("device-1"))
+	assert.Equal(t, status.Code(guard.AdmitDevice("device-2")), codes.ResourceExhausted)
+	t.Cleanup(func() {
+		guard.ReleaseDevice("device-1")
+		guard.SetLimits(capacity.Limits{})
+	})
+
+	response, err := ExtServer{}.GetCapacity(adminCtx, &adminext.GetCapacityRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Resources), 3)
+	devices := response.Resources[0]
+	assert.Equal(t, devices.Resource, capacity.ResourceDevices)
+	assert.Equal(t, devices.Usage, uint32(1))
+	assert.Equal(t, devices.Limit, uint32(1))
+	assert.Assert(t, devices.LimitReachedSince != nil)
+	assert.Assert(t, devices.Rejections >= uint64(1))
+	pending := response.Resources[1]
+	assert.Equal(t, pending.Resource, capacity.ResourcePendingChanges)
+	assert.Equal(t, pending.Limit, uint32(10))
+	assert.Assert(t, pending.LimitReachedSince == nil)
+	stored := response.Resources[2]
+	assert.Equal(t, stored.Resource, capacity.ResourceStoredChanges)
+	assert.Equal(t, stored.Limit, uint32(0))
+
+	_, err = ExtServer{}.GetCapacity(context.Background(), &adminext.GetCapacityRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
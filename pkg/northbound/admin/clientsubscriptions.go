@@ -0,0 +1,115 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/dispatcher"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/northbound/subscriptions"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ListClientSubscriptions lists the active northbound gNMI subscriptions of this node
+func (s ExtServer) ListClientSubscriptions(ctx context.Context, req *adminext.ListClientSubscriptionsRequest) (*adminext.ListClientSubscriptionsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	mgr := manager.GetManager()
+	listeners := make(map[string]dispatcher.ListenerStats)
+	for _, stats := range mgr.Dispatcher.ListenerStats() {
+		listeners[stats.Listener] = stats
+	}
+	response := &adminext.ListClientSubscriptionsResponse{
+		Subscriptions: make([]*adminext.ClientSubscription, 0),
+		Queue:         uint32(mgr.Dispatcher.ListenerQueue()),
+	}
+	for _, subscription := range subscriptions.GetRegistry().List() {
+		if req.User != "" && subscription.User != req.User {
+			continue
+		}
+		if req.DeviceId != "" && !subscribesTo(subscription, req.DeviceId) {
+			continue
+		}
+		response.Subscriptions = append(response.Subscriptions, clientSubscription(subscription, listeners[subscription.ID]))
+	}
+	return response, nil
+}
+
+// TerminateClientSubscription ends an active northbound gNMI subscription of this node
+func (s ExtServer) TerminateClientSubscription(ctx context.Context, req *adminext.TerminateClientSubscriptionRequest) (*adminext.TerminateClientSubscriptionResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	user := callerName(ctx)
+	message := fmt.Sprintf("by '%s'", user)
+	if req.Reason != "" {
+		message = fmt.Sprintf("%s: %s", message, req.Reason)
+	}
+	subscription, err := subscriptions.GetRegistry().Terminate(req.Id, message)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    user,
+		Action:  "terminate-subscription",
+		Target:  req.Id,
+		Paths:   subscription.Paths,
+		Message: fmt.Sprintf("subscription of '%s' terminated: %s", subscription.User, req.Reason),
+	})
+	var listener dispatcher.ListenerStats
+	for _, stats := range manager.GetManager().Dispatcher.ListenerStats() {
+		if stats.Listener == req.Id {
+			listener = stats
+		}
+	}
+	return &adminext.TerminateClientSubscriptionResponse{
+		Subscription: clientSubscription(subscription, listener),
+	}, nil
+}
+
+// subscribesTo returns whether a subscription has paths of a device
+func subscribesTo(subscription subscriptions.Subscription, deviceID string) bool {
+	for _, device := range subscription.Devices {
+		if device == deviceID {
+			return true
+		}
+	}
+	return false
+}
+
+func clientSubscription(subscription subscriptions.Subscription, listener dispatcher.ListenerStats) *adminext.ClientSubscription {
+	client := &adminext.ClientSubscription{
+		Id:        subscription.ID,
+		User:      subscription.User,
+		Mode:      subscription.Mode,
+		Paths:     subscription.Paths,
+		Devices:   subscription.Devices,
+		Priority:  listener.Priority,
+		Queued:    uint32(listener.Queued),
+		Delivered: listener.Delivered,
+		Dropped:   listener.Dropped,
+	}
+	if timestamp, err := types.TimestampProto(subscription.Started); err == nil {
+		client.Started = timestamp
+	}
+	return client
+}
@@ -0,0 +1,67 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/northbound/subscriptions"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_ClientSubscriptions(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+
+	active, err := subscriptions.GetRegistry().Add("stream-1", "collector")
+	assert.NilError(t, err)
+	defer subscriptions.GetRegistry().Remove("stream-1")
+	active.Subscribed("STREAM", []string{"/interfaces/interface[name=*]/state/counters"}, []string{"device-1"})
+	_, err = mgrTest.Dispatcher.RegisterOpState("stream-1")
+	assert.NilError(t, err)
+	defer mgrTest.Dispatcher.UnregisterOperationalState("stream-1")
+	assert.NilError(t, mgrTest.Dispatcher.SetOpStatePriority("stream-1", 10))
+
+	response, err := ExtServer{}.ListClientSubscriptions(adminCtx, &adminext.ListClientSubscriptionsRequest{DeviceId: "device-1"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Subscriptions), 1)
+	assert.Equal(t, response.Subscriptions[0].Id, "stream-1")
+	assert.Equal(t, response.Subscriptions[0].User, "collector")
+	assert.Equal(t, response.Subscriptions[0].Mode, "STREAM")
+	assert.DeepEqual(t, response.Subscriptions[0].Paths, []string{"/interfaces/interface[name=*]/state/counters"})
+	assert.Equal(t, response.Subscriptions[0].Priority, uint32(10))
+	assert.Assert(t, response.Subscriptions[0].Started != nil)
+
+	response, err = ExtServer{}.ListClientSubscriptions(adminCtx, &adminext.ListClientSubscriptionsRequest{DeviceId: "device-2"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Subscriptions), 0)
+	response, err = ExtServer{}.ListClientSubscriptions(adminCtx, &adminext.ListClientSubscriptionsRequest{User: "other"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Subscriptions), 0)
+
+	terminated, err := ExtServer{}.TerminateClientSubscription(adminCtx, &adminext.TerminateClientSubscriptionRequest{Id: "stream-1", Reason: "too many counters"})
+	assert.NilError(t, err)
+	assert.Equal(t, terminated.Subscription.User, "collector")
+	<-active.Terminated()
+	assert.Equal(t, active.Message(), "by 'admin': too many counters")
+
+	_, err = ExtServer{}.TerminateClientSubscription(adminCtx, &adminext.TerminateClientSubscriptionRequest{Id: "stream-2"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = ExtServer{}.ListClientSubscriptions(context.Background(), &adminext.ListClientSubscriptionsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,101 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/closedloop"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ListClosedLoopTriggers lists the recent triggers of the closed loop rules of this node
+func (s ExtServer) ListClosedLoopTriggers(ctx context.Context, req *adminext.ListClosedLoopTriggersRequest) (*adminext.ListClosedLoopTriggersResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	triggers := closedloop.GetEngine().Triggers()
+	response := &adminext.ListClosedLoopTriggersResponse{
+		Triggers: make([]*adminext.ClosedLoopTrigger, 0),
+	}
+	for i := len(triggers) - 1; i >= 0; i-- {
+		trigger := triggers[i]
+		if req.Rule != "" && trigger.Rule != req.Rule {
+			continue
+		} else if req.DeviceId != "" && string(trigger.DeviceID) != req.DeviceId {
+			continue
+		} else if req.Pending && trigger.State != closedloop.StatePending {
+			continue
+		}
+		response.Triggers = append(response.Triggers, closedLoopTrigger(trigger))
+	}
+	return response, nil
+}
+
+// ApproveClosedLoopAction makes the configuration change of a closed loop action pending approval
+func (s ExtServer) ApproveClosedLoopAction(ctx context.Context, req *adminext.ApproveClosedLoopActionRequest) (*adminext.ApproveClosedLoopActionResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	trigger, err := closedloop.GetEngine().Approve(req.Id, callerName(ctx))
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	return &adminext.ApproveClosedLoopActionResponse{
+		Trigger: closedLoopTrigger(trigger),
+	}, nil
+}
+
+// RejectClosedLoopAction drops a closed loop action pending approval
+func (s ExtServer) RejectClosedLoopAction(ctx context.Context, req *adminext.RejectClosedLoopActionRequest) (*adminext.RejectClosedLoopActionResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	trigger, err := closedloop.GetEngine().Reject(req.Id, callerName(ctx), req.Reason)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	return &adminext.RejectClosedLoopActionResponse{
+		Trigger: closedLoopTrigger(trigger),
+	}, nil
+}
+
+func closedLoopTrigger(trigger *closedloop.Trigger) *adminext.ClosedLoopTrigger {
+	result := &adminext.ClosedLoopTrigger{
+		Id:       trigger.ID,
+		Rule:     trigger.Rule,
+		DeviceId: string(trigger.DeviceID),
+		Path:     trigger.Path,
+		Value:    trigger.Value,
+		Updates:  trigger.Updates,
+		Deletes:  trigger.Deletes,
+		State:    string(trigger.State),
+		User:     trigger.User,
+		ChangeId: string(trigger.ChangeID),
+		Reason:   trigger.Reason,
+	}
+	if timestamp, err := types.TimestampProto(trigger.Triggered); err == nil {
+		result.Triggered = timestamp
+	}
+	if !trigger.Decided.IsZero() {
+		if timestamp, err := types.TimestampProto(trigger.Decided); err == nil {
+			result.Decided = timestamp
+		}
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/closedloop"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_ClosedLoop(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+
+	engine := closedloop.GetEngine()
+	assert.NilError(t, engine.SetRules([]*closedloop.Rule{{
+		Name:      "admin-down",
+		Condition: closedloop.Condition{Path: "/interfaces/interface[name={name}]/state/oper-status", Operator: closedloop.OperatorEqual, Value: "DOWN"},
+		Action:    closedloop.Action{Updates: map[string]string{"/interfaces/interface[name={name}]/config/enabled": "false"}},
+		Approval:  true,
+	}}))
+	defer func() {
+		assert.NilError(t, engine.SetRules(nil))
+		engine.SetApply(nil)
+	}()
+	engine.SetApply(func(trigger *closedloop.Trigger) (networkchange.ID, error) {
+		return "change-1", nil
+	})
+	down := devicechange.NewTypedValueString("DOWN")
+	engine.Process("device-1", "/interfaces/interface[name=eth1]/state/oper-status", down)
+	engine.Process("device-2", "/interfaces/interface[name=eth2]/state/oper-status", down)
+
+	response, err := ExtServer{}.ListClosedLoopTriggers(adminCtx, &adminext.ListClosedLoopTriggersRequest{Rule: "admin-down", Pending: true})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Triggers), 2)
+	assert.Equal(t, response.Triggers[0].DeviceId, "device-2")
+	assert.Equal(t, response.Triggers[0].State, "pending")
+	assert.DeepEqual(t, response.Triggers[1].Updates, map[string]string{"/interfaces/interface[name=eth1]/config/enabled": "false"})
+
+	approved, err := ExtServer{}.ApproveClosedLoopAction(adminCtx, &adminext.ApproveClosedLoopActionRequest{Id: response.Triggers[1].Id})
+	assert.NilError(t, err)
+	assert.Equal(t, approved.Trigger.State, "applied")
+	assert.Equal(t, approved.Trigger.ChangeId, "change-1")
+	assert.Equal(t, approved.Trigger.User, "admin")
+	assert.Assert(t, approved.Trigger.Decided != nil)
+
+	rejected, err := ExtServer{}.RejectClosedLoopAction(adminCtx, &adminext.RejectClosedLoopActionRequest{Id: response.Triggers[0].Id, Reason: "expected"})
+	assert.NilError(t, err)
+	assert.Equal(t, rejected.Trigger.State, "rejected")
+
+	response, err = ExtServer{}.ListClosedLoopTriggers(adminCtx, &adminext.ListClosedLoopTriggersRequest{Pending: true})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Triggers), 0)
+	response, err = ExtServer{}.ListClosedLoopTriggers(adminCtx, &adminext.ListClosedLoopTriggersRequest{DeviceId: "device-1"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Triggers), 1)
+
+	_, err = ExtServer{}.ApproveClosedLoopAction(adminCtx, &adminext.ApproveClosedLoopActionRequest{Id: approved.Trigger.Id})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = ExtServer{}.RejectClosedLoopAction(adminCtx, &adminext.RejectClosedLoopActionRequest{Id: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = ExtServer{}.ListClosedLoopTriggers(context.Background(), &adminext.ListClosedLoopTriggersRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,64 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/modelregistry"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// CompletePath returns the elements of the model of a device type that may follow a partial path
+func (s ExtServer) CompletePath(ctx context.Context, req *adminext.CompletePathRequest) (*adminext.CompletePathResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	plugin, err := modelPlugin(req.DeviceType, req.DeviceVersion)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+
+	response := &adminext.CompletePathResponse{
+		Completions: make([]*adminext.PathCompletion, 0),
+	}
+	for _, completion := range plugin.CompletePath(req.Path) {
+		pathCompletion := &adminext.PathCompletion{
+			Name:       completion.Name,
+			Keys:       completion.Keys,
+			Leaf:       completion.Leaf,
+			ReadOnly:   completion.ReadOnly,
+			EnumValues: completion.Values,
+		}
+		if completion.Leaf {
+			pathCompletion.Type = completion.ValueType.String()
+		}
+		response.Completions = append(response.Completions, pathCompletion)
+	}
+	return response, nil
+}
+
+// modelPlugin returns the model plugin of a device type and version
+func modelPlugin(deviceType string, version string) (*modelregistry.ModelPlugin, error) {
+	if deviceType == "" || version == "" {
+		return nil, errors.NewInvalid("a device type and version must be given")
+	}
+	return manager.GetManager().ModelRegistry.GetPlugin(utils.ToModelName(devicetype.Type(deviceType), devicetype.Version(version)))
+}
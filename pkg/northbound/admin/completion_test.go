@@ -0,0 +1,74 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	td1 "github.com/onosproject/config-models/modelplugin/testdevice-1.0.0/testdevice_1_0_0"
+	configmodel "github.com/onosproject/onos-config-model/pkg/model"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/modelregistry"
+	"github.com/openconfig/goyang/pkg/yang"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+// setUpTestDeviceModel registers the model of TestDevice 1.0.0 with the manager
+func setUpTestDeviceModel(t *testing.T, mgrTest *manager.Manager) {
+	schema, err := td1.UnzipSchema()
+	assert.NilError(t, err)
+	roPaths, rwPaths := modelregistry.ExtractPaths(schema["Device"], yang.TSUnset, "", "")
+	registry, err := modelregistry.NewModelRegistry(modelregistry.Config{
+		ModPath:      t.TempDir(),
+		RegistryPath: t.TempDir(),
+		PluginPath:   t.TempDir(),
+		ModTarget:    "github.com/onosproject/onos-config@master",
+	}, &modelregistry.ModelPlugin{
+		Info:           configmodel.ModelInfo{Name: "TestDevice", Version: "1.0.0"},
+		ReadOnlyPaths:  roPaths,
+		ReadWritePaths: rwPaths,
+	})
+	assert.NilError(t, err)
+	mgrTest.ModelRegistry = registry
+}
+
+func Test_CompletePath(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	setUpTestDeviceModel(t, mgrTest)
+
+	response, err := ExtServer{}.CompletePath(adminCtx, &adminext.CompletePathRequest{
+		DeviceType: "TestDevice", DeviceVersion: "1.0.0", Path: "/cont1a/"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Completions), 5)
+	assert.DeepEqual(t, response.Completions[1], &adminext.PathCompletion{Name: "leaf1a", Leaf: true, Type: "STRING"})
+	assert.DeepEqual(t, response.Completions[4].Keys, []string{"key1", "key2"})
+	assert.Equal(t, response.Completions[4].Type, "")
+
+	response, err = ExtServer{}.CompletePath(adminCtx, &adminext.CompletePathRequest{
+		DeviceType: "TestDevice", DeviceVersion: "1.0.0", Path: "/cont1b"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Completions), 1)
+	assert.Assert(t, response.Completions[0].ReadOnly)
+
+	_, err = ExtServer{}.CompletePath(adminCtx, &adminext.CompletePathRequest{DeviceType: "TestDevice", Path: "/"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = ExtServer{}.CompletePath(context.Background(), &adminext.CompletePathRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
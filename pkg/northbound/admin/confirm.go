@@ -0,0 +1,80 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/change/confirmation"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ConfirmChange confirms a network change made by a commit-confirmed Set, so that it is not rolled
+// back when its confirmation timeout expires
+func (s ExtServer) ConfirmChange(ctx context.Context, req *adminext.ConfirmChangeRequest) (*adminext.ConfirmChangeResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	c, err := manager.GetManager().ConfirmNetworkChange(networkchange.ID(req.Name))
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "confirm-change",
+		Target:  req.Name,
+		Message: "confirmed",
+	})
+	return &adminext.ConfirmChangeResponse{
+		Change: newUnconfirmedChange(c),
+	}, nil
+}
+
+// ListUnconfirmedChanges lists the network changes awaiting their confirmation, the earliest
+// deadline first
+func (s ExtServer) ListUnconfirmedChanges(ctx context.Context, req *adminext.ListUnconfirmedChangesRequest) (*adminext.ListUnconfirmedChangesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	confirmations, err := manager.GetManager().ConfirmationStore.List()
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	response := &adminext.ListUnconfirmedChangesResponse{
+		Changes: make([]*adminext.UnconfirmedChange, 0, len(confirmations)),
+	}
+	for _, c := range confirmations {
+		response.Changes = append(response.Changes, newUnconfirmedChange(c))
+	}
+	return response, nil
+}
+
+func newUnconfirmedChange(c *confirmation.Confirmation) *adminext.UnconfirmedChange {
+	change := &adminext.UnconfirmedChange{
+		Name:    string(c.NetworkChangeID),
+		User:    c.User,
+		Timeout: types.DurationProto(c.Timeout),
+	}
+	if deadline, err := types.TimestampProto(c.Deadline); err == nil {
+		change.Deadline = deadline
+	}
+	return change
+}
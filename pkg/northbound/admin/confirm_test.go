@@ -0,0 +1,57 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_ConfirmChange(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	_, err := mgrTest.AwaitConfirmation("change-2", "alice", 10*time.Minute)
+	assert.NilError(t, err)
+	_, err = mgrTest.AwaitConfirmation("change-1", "bob", time.Minute)
+	assert.NilError(t, err)
+
+	list, err := ExtServer{}.ListUnconfirmedChanges(adminCtx, &adminext.ListUnconfirmedChangesRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(list.Changes), 2)
+	assert.Equal(t, list.Changes[0].Name, "change-1")
+	assert.Equal(t, list.Changes[0].User, "bob")
+	assert.Equal(t, list.Changes[0].Timeout.Seconds, int64(60))
+
+	confirmed, err := ExtServer{}.ConfirmChange(adminCtx, &adminext.ConfirmChangeRequest{Name: "change-2"})
+	assert.NilError(t, err)
+	assert.Equal(t, confirmed.Change.User, "alice")
+	entries := audit.Entries()
+	assert.Equal(t, entries[len(entries)-1].Action, "confirm-change")
+	assert.Equal(t, entries[len(entries)-1].Target, "change-2")
+
+	_, err = ExtServer{}.ConfirmChange(adminCtx, &adminext.ConfirmChangeRequest{Name: "change-2"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = ExtServer{}.ConfirmChange(adminCtx, &adminext.ConfirmChangeRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	list, err = ExtServer{}.ListUnconfirmedChanges(adminCtx, &adminext.ListUnconfirmedChangesRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(list.Changes), 1)
+}
@@ -0,0 +1,115 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-config/pkg/store/trust"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// TestConnection connects to a device step by step the way onos-config would, without keeping
+// the connection, and reports the outcome of each step
+func (s ExtServer) TestConnection(ctx context.Context, req *adminext.TestConnectionRequest) (*adminext.TestConnectionResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	device := &topodevice.Device{ID: topodevice.ID(req.DeviceId)}
+	if req.DeviceId != "" {
+		stored, err := manager.GetManager().DeviceStore.Get(topodevice.ID(req.DeviceId))
+		if err != nil {
+			return nil, errors.Status(err).Err()
+		} else if stored == nil {
+			return nil, errors.Status(errors.NewNotFound("device '%s' not found", req.DeviceId)).Err()
+		}
+		device = stored
+	}
+	if err := applyConnectionRequest(req, device); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	if device.Address == "" {
+		return nil, errors.Status(errors.NewInvalid("no device address given")).Err()
+	}
+
+	log.Infof("Testing connection to device '%s' at %s as requested by '%s'", device.ID, device.Address, callerName(ctx))
+	diagnostic := southbound.TestDeviceConnection(ctx, *device)
+	response := &adminext.TestConnectionResponse{
+		DeviceId: string(diagnostic.DeviceID),
+		Address:  diagnostic.Address,
+		Ok:       diagnostic.OK,
+		Steps:    make([]*adminext.ConnectionStep, 0, len(diagnostic.Steps)),
+	}
+	for _, step := range diagnostic.Steps {
+		response.Steps = append(response.Steps, &adminext.ConnectionStep{
+			Step:     step.Step,
+			Ok:       step.OK,
+			Skipped:  step.Skipped,
+			Duration: types.DurationProto(step.Duration),
+			Message:  step.Message,
+		})
+	}
+	return response, nil
+}
+
+// applyConnectionRequest overrides the fields of the device record that are given in the request.
+// The stored credentials and certificates are dropped when the address changes, so that they
+// are never sent to another address than the stored one.
+func applyConnectionRequest(req *adminext.TestConnectionRequest, device *topodevice.Device) error {
+	if req.Address != "" && req.Address != device.Address {
+		device.Address = req.Address
+		device.Credentials = topodevice.Credentials{}
+		device.TLS.CaCert = ""
+		device.TLS.Cert = ""
+		device.TLS.Key = ""
+	}
+	if req.Target != "" {
+		device.Target = req.Target
+	}
+	if req.Timeout != nil {
+		timeout, err := types.DurationFromProto(req.Timeout)
+		if err != nil || timeout <= 0 {
+			return errors.NewInvalid("invalid timeout %v", req.Timeout)
+		}
+		device.Timeout = &timeout
+	}
+	if req.User != "" {
+		device.Credentials.User = req.User
+		device.Credentials.Password = req.Password
+	} else if req.Password != "" {
+		return errors.NewInvalid("a password is given without a user")
+	}
+	// Only trust bundles may be given, as file paths would let callers probe the files of onos-config
+	if req.CaBundle != "" {
+		device.TLS.CaCert = trust.BundlePrefix + req.CaBundle
+	}
+	if req.ClientBundle != "" {
+		device.TLS.Cert = trust.BundlePrefix + req.ClientBundle
+		device.TLS.Key = ""
+	}
+	if req.Plain != nil {
+		device.TLS.Plain = req.Plain.Value
+	}
+	if req.Insecure != nil {
+		device.TLS.Insecure = req.Insecure.Value
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func storedDevice() *topodevice.Device {
+	timeout := time.Second
+	return &topodevice.Device{
+		ID:          "device-1",
+		Address:     "device-1:11161",
+		Timeout:     &timeout,
+		Credentials: topodevice.Credentials{User: "admin", Password: "secret"},
+		TLS:         topodevice.TLSConfig{CaCert: "/etc/onos/config/device-ca.crt", Cert: "bundle:lab-client"},
+	}
+}
+
+func Test_ApplyConnectionRequest(t *testing.T) {
+	device := storedDevice()
+	assert.NilError(t, applyConnectionRequest(&adminext.TestConnectionRequest{
+		Target:   "device-1",
+		Timeout:  types.DurationProto(3 * time.Second),
+		CaBundle: "lab-ca",
+		Plain:    &types.BoolValue{Value: true},
+	}, device))
+	assert.Equal(t, "device-1:11161", device.Address)
+	assert.Equal(t, 3*time.Second, *device.Timeout)
+	assert.Equal(t, "bundle:lab-ca", device.TLS.CaCert)
+	assert.Equal(t, "bundle:lab-client", device.TLS.Cert)
+	assert.Equal(t, "admin", device.Credentials.User)
+	assert.Assert(t, device.TLS.Plain)
+
+	assert.ErrorContains(t, applyConnectionRequest(&adminext.TestConnectionRequest{Timeout: types.DurationProto(-time.Second)}, storedDevice()), "timeout")
+	assert.ErrorContains(t, applyConnectionRequest(&adminext.TestConnectionRequest{Password: "guess"}, storedDevice()), "password")
+}
+
+func Test_ApplyConnectionRequestAddress(t *testing.T) {
+	device := storedDevice()
+	assert.NilError(t, applyConnectionRequest(&adminext.TestConnectionRequest{Address: "10.0.0.1:11161"}, device))
+	assert.Equal(t, "10.0.0.1:11161", device.Address)
+	assert.Equal(t, "", device.Credentials.User, "stored credentials must not be sent to another address")
+	assert.Equal(t, "", device.Credentials.Password)
+	assert.Equal(t, "", device.TLS.CaCert)
+	assert.Equal(t, "", device.TLS.Cert)
+
+	device = storedDevice()
+	assert.NilError(t, applyConnectionRequest(&adminext.TestConnectionRequest{
+		Address:      "10.0.0.1:11161",
+		User:         "operator",
+		Password:     "other",
+		ClientBundle: "other-client",
+	}, device))
+	assert.Equal(t, "operator", device.Credentials.User)
+	assert.Equal(t, "bundle:other-client", device.TLS.Cert)
+}
+
+func Test_TestConnection(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+
+	_, err := ExtServer{}.TestConnection(adminCtx, &adminext.TestConnectionRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	response, err := ExtServer{}.TestConnection(adminCtx, &adminext.TestConnectionRequest{Address: "no-port"})
+	assert.NilError(t, err)
+	assert.Assert(t, !response.Ok)
+	assert.Equal(t, 5, len(response.Steps))
+	assert.Equal(t, southbound.StepDNS, response.Steps[0].Step)
+	assert.Assert(t, !response.Steps[0].Ok)
+	assert.Assert(t, response.Steps[1].Skipped)
+}
+
+func Test_TestConnectionUnauthorized(t *testing.T) {
+	setUpExtServer(t)
+	_, err := ExtServer{}.TestConnection(context.Background(), &adminext.TestConnectionRequest{Address: "no-port"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,56 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// DeleteSubtree deletes a path prefix on the devices of a group or selector in one network change,
+// or only lists the leaves it would remove from each device
+func (s ExtServer) DeleteSubtree(ctx context.Context, req *adminext.DeleteSubtreeRequest) (*adminext.DeleteSubtreeResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	deletions, change, err := manager.GetManager().DeleteSubtree(req.Partition, req.Prefix, req.DryRun)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+
+	response := &adminext.DeleteSubtreeResponse{
+		Devices: make([]*adminext.DeviceValues, 0, len(deletions)),
+	}
+	for _, deletion := range deletions {
+		response.Devices = append(response.Devices, changeValues(ctx, deletion))
+	}
+	if change != nil {
+		response.ChangeId = string(change.ID)
+		audit.Record(audit.Entry{
+			User:    callerName(ctx),
+			Action:  "delete-subtree",
+			Target:  req.Partition,
+			Paths:   []string{req.Prefix},
+			Message: fmt.Sprintf("deleted from %d devices in %s", len(deletions), change.ID),
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onosproject/onos-config/api/adminext"
+	devicecache "github.com/onosproject/onos-config/pkg/store/device/cache"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_DeleteSubtreeNothing(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mgrTest.DeviceCache.(*cache.MockCache).EXPECT().GetDevices().Return([]*devicecache.Info{
+		{DeviceID: "device-1", Type: "Devicesim", Version: "1.0.0"},
+	}).AnyTimes()
+	mgrTest.DeviceStateStore.(*mockstore.MockDeviceStateStore).EXPECT().Get(gomock.Any(), gomock.Any()).
+		Return(nil, errors.NewNotFound("no configuration")).AnyTimes()
+
+	response, err := ExtServer{}.DeleteSubtree(adminCtx, &adminext.DeleteSubtreeRequest{
+		Partition: "type=Devicesim",
+		Prefix:    "/interfaces/interface[name=*]/config/description",
+		DryRun:    true,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, response.ChangeId, "")
+	assert.Equal(t, len(response.Devices), 0)
+
+	_, err = ExtServer{}.DeleteSubtree(adminCtx, &adminext.DeleteSubtreeRequest{
+		Partition: "type=Devicesim",
+		Prefix:    "/interfaces/interface[name=*]/config/description",
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func Test_DeleteSubtreeInvalid(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+
+	_, err := ExtServer{}.DeleteSubtree(adminCtx, &adminext.DeleteSubtreeRequest{Prefix: "/system"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.DeleteSubtree(adminCtx, &adminext.DeleteSubtreeRequest{Partition: "type=Devicesim", Prefix: "/"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = ExtServer{}.DeleteSubtree(context.Background(), &adminext.DeleteSubtreeRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
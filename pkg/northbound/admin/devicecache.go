@@ -0,0 +1,64 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// RebuildDeviceCache reconstructs the device cache of this node from the network changes and the
+// device snapshots, and returns the entries it added, removed and corrected
+func (s ExtServer) RebuildDeviceCache(ctx context.Context, req *adminext.RebuildDeviceCacheRequest) (*adminext.RebuildDeviceCacheResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	repair, err := manager.GetManager().DeviceCache.Rebuild()
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	if fixed := len(repair.Added) + len(repair.Removed) + len(repair.Updated); fixed > 0 {
+		audit.Record(audit.Entry{
+			User:   callerName(ctx),
+			Action: "rebuild-device-cache",
+			Message: fmt.Sprintf("%d added, %d removed, %d updated",
+				len(repair.Added), len(repair.Removed), len(repair.Updated)),
+		})
+	}
+	return &adminext.RebuildDeviceCacheResponse{
+		Added:   newCachedDevices(repair.Added),
+		Removed: newCachedDevices(repair.Removed),
+		Updated: newCachedDevices(repair.Updated),
+	}, nil
+}
+
+func newCachedDevices(infos []*cache.Info) []*adminext.CachedDevice {
+	devices := make([]*adminext.CachedDevice, 0, len(infos))
+	for _, info := range infos {
+		devices = append(devices, &adminext.CachedDevice{
+			DeviceId:      string(info.DeviceID),
+			DeviceVersion: string(info.Version),
+			DeviceType:    string(info.Type),
+		})
+	}
+	return devices
+}
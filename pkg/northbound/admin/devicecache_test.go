@@ -0,0 +1,48 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	devicecache "github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_RebuildDeviceCache(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mgrTest.DeviceCache.(*cache.MockCache).EXPECT().Rebuild().Return(&devicecache.Repair{
+		Added:   []*devicecache.Info{{DeviceID: "device-3", Type: "Devicesim", Version: "1.0.0"}},
+		Removed: []*devicecache.Info{{DeviceID: "device-2", Type: "Devicesim", Version: "1.0.0"}},
+	}, nil)
+
+	response, err := ExtServer{}.RebuildDeviceCache(adminCtx, &adminext.RebuildDeviceCacheRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Added), 1)
+	assert.Equal(t, response.Added[0].DeviceId, "device-3")
+	assert.Equal(t, response.Added[0].DeviceVersion, "1.0.0")
+	assert.Equal(t, response.Added[0].DeviceType, "Devicesim")
+	assert.Equal(t, len(response.Removed), 1)
+	assert.Equal(t, response.Removed[0].DeviceId, "device-2")
+	assert.Equal(t, len(response.Updated), 0)
+
+	_, err = ExtServer{}.RebuildDeviceCache(context.Background(), &adminext.RebuildDeviceCacheRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
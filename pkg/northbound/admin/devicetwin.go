@@ -0,0 +1,74 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// GetDeviceTwin returns the intended configuration of a device next to the one it reports
+func (s ExtServer) GetDeviceTwin(ctx context.Context, req *adminext.GetDeviceTwinRequest) (*adminext.GetDeviceTwinResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.DeviceId == "" {
+		return nil, errors.Status(errors.NewInvalid("no device given")).Err()
+	}
+	twin, err := manager.GetManager().GetDeviceTwin(devicetype.ID(req.DeviceId), devicetype.Version(req.DeviceVersion), req.Path)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+
+	response := &adminext.GetDeviceTwinResponse{
+		DeviceId:      string(twin.DeviceID),
+		DeviceVersion: string(twin.Version),
+		Values:        make([]*adminext.TwinValue, 0, len(twin.Values)),
+	}
+	if twin.ObservationError != nil {
+		response.ObservationError = twin.ObservationError.Error()
+	} else if observed, err := types.TimestampProto(twin.Observed); err == nil {
+		response.Observed = observed
+	}
+	for _, value := range twin.Values {
+		twinValue := &adminext.TwinValue{
+			Path:            value.Path,
+			NetworkChangeId: value.NetworkChange,
+			Drift:           value.Drift,
+		}
+		if value.Intended != nil {
+			twinValue.Intended = pathValue(ctx, req.DeviceId, value.Path, value.Intended, false)
+		}
+		if !value.IntendedAt.IsZero() {
+			if intendedAt, err := types.TimestampProto(value.IntendedAt); err == nil {
+				twinValue.IntendedAt = intendedAt
+			}
+		}
+		if value.Observed != nil {
+			twinValue.Observed = pathValue(ctx, req.DeviceId, value.Path, value.Observed, false)
+		}
+		if value.Drift {
+			response.Drifted++
+		}
+		response.Values = append(response.Values, twinValue)
+	}
+	return response, nil
+}
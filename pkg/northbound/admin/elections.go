@@ -0,0 +1,107 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// electionsBuffer is the number of elections a WatchElections stream may fall behind by before it
+// misses some
+const electionsBuffer = 100
+
+// GetElections returns the current leader, the masters of the devices and the recent elections
+func (s ExtServer) GetElections(ctx context.Context, req *adminext.GetElectionsRequest) (*adminext.GetElectionsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	mgr := manager.GetManager()
+	response := &adminext.GetElectionsResponse{
+		NodeId:      string(mgr.MastershipStore.NodeID()),
+		Masterships: make([]*adminext.Election, 0),
+		Recent:      make([]*adminext.Election, 0),
+	}
+	if req.DeviceId == "" {
+		leadership, err := mgr.LeadershipStore.GetLeadership()
+		if err != nil {
+			return nil, errors.Status(err).Err()
+		}
+		response.Leadership = &adminext.Election{Term: uint64(leadership.Term), Leader: string(leadership.Leader)}
+	}
+	masterships, err := mgr.MastershipStore.ListMasterships()
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	for _, mastership := range masterships {
+		if req.DeviceId == "" || string(mastership.Device) == req.DeviceId {
+			response.Masterships = append(response.Masterships, &adminext.Election{
+				DeviceId: string(mastership.Device),
+				Term:     uint64(mastership.Term),
+				Leader:   string(mastership.Master),
+			})
+		}
+	}
+	sort.Slice(response.Masterships, func(i, j int) bool {
+		return response.Masterships[i].DeviceId < response.Masterships[j].DeviceId
+	})
+	for _, election := range mgr.RecentElections() {
+		if req.DeviceId == "" || string(election.Device) == req.DeviceId {
+			response.Recent = append(response.Recent, electionProto(election))
+		}
+	}
+	return response, nil
+}
+
+// WatchElections streams the elections seen by this node until the client cancels the stream
+func (s ExtServer) WatchElections(req *adminext.WatchElectionsRequest, stream adminext.ConfigAdminExtService_WatchElectionsServer) error {
+	ctx := stream.Context()
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return err
+	}
+	ch := make(chan manager.Election, electionsBuffer)
+	manager.GetManager().WatchElections(ctx, ch)
+	for {
+		select {
+		case election := <-ch:
+			if req.DeviceId != "" && string(election.Device) != req.DeviceId {
+				continue
+			}
+			if err := stream.Send(electionProto(election)); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func electionProto(election manager.Election) *adminext.Election {
+	result := &adminext.Election{
+		DeviceId: string(election.Device),
+		Term:     election.Term,
+		Leader:   string(election.Leader),
+	}
+	if timestamp, err := types.TimestampProto(election.Time); err == nil {
+		result.Time = timestamp
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/store/leadership"
+	"github.com/onosproject/onos-config/pkg/store/mastership"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-lib-go/pkg/cluster"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+// electionsStream collects the elections sent by WatchElections
+type electionsStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	elections []*adminext.Election
+}
+
+func (s *electionsStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *electionsStream) Send(election *adminext.Election) error {
+	s.elections = append(s.elections, election)
+	return nil
+}
+
+func Test_GetElections(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	leadershipStore := mgrTest.LeadershipStore.(*mockstore.MockLeadershipStore)
+	leadershipStore.EXPECT().GetLeadership().Return(&leadership.Leadership{Term: 4, Leader: "onos-config-2"}, nil)
+	mastershipStore := mgrTest.MastershipStore.(*mockstore.MockMastershipStore)
+	mastershipStore.EXPECT().NodeID().Return(cluster.NodeID("onos-config-1")).AnyTimes()
+	mastershipStore.EXPECT().ListMasterships().Return([]*mastership.Mastership{
+		{Device: "device-2", Term: 1, Master: "onos-config-1"},
+		{Device: "device-1", Term: 3, Master: "onos-config-2"},
+	}, nil).Times(2)
+
+	response, err := ExtServer{}.GetElections(adminCtx, &adminext.GetElectionsRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, response.NodeId, "onos-config-1")
+	assert.DeepEqual(t, response.Leadership, &adminext.Election{Term: 4, Leader: "onos-config-2"})
+	assert.Equal(t, len(response.Masterships), 2)
+	assert.DeepEqual(t, response.Masterships[0], &adminext.Election{DeviceId: "device-1", Term: 3, Leader: "onos-config-2"})
+	assert.Equal(t, response.Masterships[1].DeviceId, "device-2")
+
+	response, err = ExtServer{}.GetElections(adminCtx, &adminext.GetElectionsRequest{DeviceId: "device-2"})
+	assert.NilError(t, err)
+	assert.Assert(t, response.Leadership == nil)
+	assert.Equal(t, len(response.Masterships), 1)
+	assert.Equal(t, response.Masterships[0].Leader, "onos-config-1")
+
+	_, err = ExtServer{}.GetElections(context.Background(), &adminext.GetElectionsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_WatchElections(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+	ctx, cancel := context.WithCancel(adminCtx)
+	cancel()
+	stream := &electionsStream{ctx: ctx}
+	assert.NilError(t, ExtServer{}.WatchElections(&adminext.WatchElectionsRequest{}, stream))
+	assert.Equal(t, len(stream.elections), 0)
+
+	err := ExtServer{}.WatchElections(&adminext.WatchElectionsRequest{}, &electionsStream{ctx: context.Background()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,62 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// GetChangeEnvironment returns the environment a network change was created in
+func (s ExtServer) GetChangeEnvironment(ctx context.Context, req *adminext.GetChangeEnvironmentRequest) (*adminext.GetChangeEnvironmentResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.ChangeId == "" {
+		return nil, errors.Status(errors.NewInvalid("a change ID is required")).Err()
+	}
+	env, err := manager.GetManager().EnvironmentStore.Get(networkchange.ID(req.ChangeId))
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+
+	environment := &adminext.ChangeEnvironment{
+		ChangeId:               string(env.ChangeID),
+		ControllerVersion:      env.ControllerVersion,
+		ValidationLevel:        env.ValidationLevel,
+		AllowUnvalidatedConfig: env.AllowUnvalidatedConfig,
+		Devices:                make([]*adminext.DeviceEnvironment, 0, len(env.Devices)),
+	}
+	if created, err := types.TimestampProto(env.Created); err == nil {
+		environment.Created = created
+	}
+	for _, device := range env.Devices {
+		environment.Devices = append(environment.Devices, &adminext.DeviceEnvironment{
+			DeviceId:          string(device.DeviceID),
+			DeviceType:        string(device.DeviceType),
+			DeviceVersion:     string(device.DeviceVersion),
+			Model:             device.Model,
+			Plugin:            device.Plugin,
+			AllowUnknownPaths: device.AllowUnknownPaths,
+		})
+	}
+	return &adminext.GetChangeEnvironmentResponse{Environment: environment}, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/store/change/environment"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_GetChangeEnvironment(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	assert.NilError(t, mgrTest.EnvironmentStore.Create(&environment.Environment{
+		ChangeID:          "change-1",
+		Created:           time.Now(),
+		ControllerVersion: "v0.10.0",
+		ValidationLevel:   "schema-only",
+		Devices: []*environment.DeviceEnvironment{{
+			DeviceID:          "device-1",
+			DeviceType:        "Devicesim",
+			DeviceVersion:     "1.0.0",
+			Model:             "Devicesim@1.0.0",
+			Plugin:            "devicesim@1.0.0",
+			AllowUnknownPaths: true,
+		}},
+	}))
+
+	response, err := ExtServer{}.GetChangeEnvironment(adminCtx, &adminext.GetChangeEnvironmentRequest{ChangeId: "change-1"})
+	assert.NilError(t, err)
+	env := response.Environment
+	assert.Equal(t, "change-1", env.ChangeId)
+	assert.Equal(t, "v0.10.0", env.ControllerVersion)
+	assert.Equal(t, "schema-only", env.ValidationLevel)
+	assert.Assert(t, env.Created != nil)
+	assert.Equal(t, 1, len(env.Devices))
+	assert.Equal(t, "device-1", env.Devices[0].DeviceId)
+	assert.Equal(t, "Devicesim@1.0.0", env.Devices[0].Model)
+	assert.Equal(t, "devicesim@1.0.0", env.Devices[0].Plugin)
+	assert.Assert(t, env.Devices[0].AllowUnknownPaths)
+
+	_, err = ExtServer{}.GetChangeEnvironment(adminCtx, &adminext.GetChangeEnvironmentRequest{ChangeId: "change-2"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = ExtServer{}.GetChangeEnvironment(adminCtx, &adminext.GetChangeEnvironmentRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func Test_GetChangeEnvironmentUnauthenticated(t *testing.T) {
+	setUpExtServer(t)
+	_, err := ExtServer{}.GetChangeEnvironment(context.Background(), &adminext.GetChangeEnvironmentRequest{ChangeId: "change-1"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
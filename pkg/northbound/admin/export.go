@@ -0,0 +1,61 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"github.com/golang/protobuf/proto"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ExportDeviceChanges streams the configuration history of a device as gNMI notifications
+func (s ExtServer) ExportDeviceChanges(req *adminext.ExportDeviceChangesRequest, stream adminext.ConfigAdminExtService_ExportDeviceChangesServer) error {
+	if err := interceptors.AuthorizeAdmin(stream.Context()); err != nil {
+		return err
+	}
+	if req.DeviceId == "" {
+		return errors.Status(errors.NewInvalid("no device given")).Err()
+	}
+	mgr := manager.GetManager()
+	_, version, err := mgr.CheckCacheForDevice(devicetype.ID(req.DeviceId), "", devicetype.Version(req.DeviceVersion))
+	if err != nil {
+		return errors.Status(err).Err()
+	}
+	history, err := mgr.ExportDeviceChanges(devicetype.NewVersionedID(devicetype.ID(req.DeviceId), version))
+	if err != nil {
+		return errors.Status(err).Err()
+	}
+	for _, entry := range history {
+		if req.AfterIndex > 0 && uint64(entry.Index) <= req.AfterIndex {
+			continue
+		}
+		notification, err := proto.Marshal(entry.Notification)
+		if err != nil {
+			return errors.Status(errors.NewInternal("failed to encode the notification of device change %d: %v", entry.Index, err)).Err()
+		}
+		if err := stream.Send(&adminext.ExportedNotification{
+			Index:           uint64(entry.Index),
+			NetworkChangeId: entry.NetworkChange,
+			Rollback:        entry.Rollback,
+			Notification:    notification,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	devicecache "github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+// exportStream collects the notifications sent by ExportDeviceChanges
+type exportStream struct {
+	grpc.ServerStream
+	ctx           context.Context
+	notifications []*adminext.ExportedNotification
+}
+
+func (s *exportStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *exportStream) Send(notification *adminext.ExportedNotification) error {
+	s.notifications = append(s.notifications, notification)
+	return nil
+}
+
+func Test_ExportDeviceChanges(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	deviceCache := mgrTest.DeviceCache.(*cache.MockCache)
+	deviceCache.EXPECT().GetDevicesByID(devicetype.ID("device-1")).Return([]*devicecache.Info{
+		{DeviceID: "device-1", Type: "Devicesim", Version: "1.0.0"},
+	}).Times(2)
+	deviceCache.EXPECT().GetDevicesByID(devicetype.ID("device-2")).Return(nil)
+	deviceStore := mgrTest.DeviceStore.(*mockstore.MockDeviceStore)
+	deviceStore.EXPECT().Get(gomock.Any()).Return(nil, errors.NewNotFound("not found")).AnyTimes()
+
+	deviceID := devicetype.NewVersionedID("device-1", "1.0.0")
+	deviceSnapshots := mgrTest.DeviceSnapshotStore.(*mockstore.MockDeviceSnapshotStore)
+	deviceSnapshots.EXPECT().Load(deviceID).Return(nil, errors.NewNotFound("no snapshot")).Times(2)
+	deviceChanges := mgrTest.DeviceChangesStore.(*mockstore.MockDeviceChangesStore)
+	deviceChanges.EXPECT().List(deviceID, gomock.Any()).DoAndReturn(func(id devicetype.VersionedID, ch chan<- *devicechange.DeviceChange) (stream.Context, error) {
+		go func() {
+			for index, state := range []changetypes.State{changetypes.State_COMPLETE, changetypes.State_PENDING, changetypes.State_COMPLETE} {
+				ch <- &devicechange.DeviceChange{
+					ID:            "change-1:device-1:1.0.0",
+					Index:         devicechange.Index(index + 1),
+					NetworkChange: devicechange.NetworkChangeRef{ID: "change-1"},
+					Change: &devicechange.Change{DeviceID: "device-1", DeviceVersion: "1.0.0", Values: []*devicechange.ChangeValue{
+						{Path: "/system/config/motd-banner", Value: devicechange.NewTypedValueString("hello")},
+					}},
+					Status: changetypes.Status{Phase: changetypes.Phase_CHANGE, State: state},
+				}
+			}
+			close(ch)
+		}()
+		return stream.NewContext(func() {}), nil
+	}).Times(2)
+
+	export := &exportStream{ctx: adminCtx}
+	assert.NilError(t, ExtServer{}.ExportDeviceChanges(&adminext.ExportDeviceChangesRequest{DeviceId: "device-1"}, export))
+	assert.Equal(t, len(export.notifications), 2)
+	assert.Equal(t, export.notifications[0].Index, uint64(1))
+	assert.Equal(t, export.notifications[0].NetworkChangeId, "change-1")
+	notification := &gnmi.Notification{}
+	assert.NilError(t, proto.Unmarshal(export.notifications[0].Notification, notification))
+	assert.Equal(t, notification.Prefix.Target, "device-1")
+	assert.Equal(t, len(notification.Update), 1)
+	assert.Equal(t, notification.Update[0].Path.Elem[2].Name, "motd-banner")
+	assert.Equal(t, notification.Update[0].Val.GetStringVal(), "hello")
+
+	export = &exportStream{ctx: adminCtx}
+	assert.NilError(t, ExtServer{}.ExportDeviceChanges(&adminext.ExportDeviceChangesRequest{DeviceId: "device-1", AfterIndex: 1}, export))
+	assert.Equal(t, len(export.notifications), 1)
+	assert.Equal(t, export.notifications[0].Index, uint64(3))
+
+	err := ExtServer{}.ExportDeviceChanges(&adminext.ExportDeviceChangesRequest{DeviceId: "device-2"}, &exportStream{ctx: adminCtx})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	err = ExtServer{}.ExportDeviceChanges(&adminext.ExportDeviceChangesRequest{}, &exportStream{ctx: adminCtx})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	err = ExtServer{}.ExportDeviceChanges(&adminext.ExportDeviceChangesRequest{DeviceId: "device-1"}, &exportStream{ctx: context.Background()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,93 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// GetDeviceGroupStatus returns the configuration health of the devices of a group, in one call
+func (s ExtServer) GetDeviceGroupStatus(ctx context.Context, req *adminext.GetDeviceGroupStatusRequest) (*adminext.GetDeviceGroupStatusResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	status, err := manager.GetManager().GetDeviceGroupStatus(req.Group, req.SkipDrift)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	response := &adminext.GetDeviceGroupStatusResponse{
+		Group:                  status.Group,
+		Devices:                uint32(len(status.Devices)),
+		InSync:                 uint32(status.InSync),
+		Drifted:                uint32(status.Drifted),
+		Unreachable:            uint32(status.Unreachable),
+		SyncUnknown:            uint32(status.Unknown),
+		PendingChanges:         uint32(status.PendingChanges),
+		FailedChanges:          uint32(status.FailedChanges),
+		DevicesWithPending:     uint32(status.DevicesWithPending),
+		DevicesWithFailed:      uint32(status.DevicesWithFailed),
+		DevicesWithoutSnapshot: uint32(status.WithoutSnapshot),
+		Members:                make([]*adminext.DeviceGroupMember, 0),
+	}
+	if !status.OldestSnapshot.IsZero() {
+		if oldest, err := types.TimestampProto(status.OldestSnapshot); err == nil {
+			response.OldestSnapshot = oldest
+		}
+		response.SnapshotAge = types.DurationProto(time.Since(status.OldestSnapshot))
+	}
+	if !req.IncludeDevices {
+		return response, nil
+	}
+	for _, device := range status.Devices {
+		member := &adminext.DeviceGroupMember{
+			DeviceId:       string(device.DeviceID),
+			DeviceVersion:  string(device.Version),
+			DeviceType:     string(device.Type),
+			Sync:           syncStateProto(device.Sync),
+			DriftedPaths:   uint32(device.DriftedPaths),
+			PendingChanges: uint32(device.PendingChanges),
+			FailedChanges:  uint32(device.FailedChanges),
+		}
+		if device.ObservationError != nil {
+			member.ObservationError = device.ObservationError.Error()
+		}
+		if !device.LastSnapshot.IsZero() {
+			if last, err := types.TimestampProto(device.LastSnapshot); err == nil {
+				member.LastSnapshot = last
+			}
+		}
+		response.Members = append(response.Members, member)
+	}
+	return response, nil
+}
+
+func syncStateProto(state manager.SyncState) adminext.DeviceSyncState {
+	switch state {
+	case manager.InSync:
+		return adminext.DeviceSyncState_IN_SYNC
+	case manager.Drifted:
+		return adminext.DeviceSyncState_DRIFTED
+	case manager.Unreachable:
+		return adminext.DeviceSyncState_UNREACHABLE
+	}
+	return adminext.DeviceSyncState_SYNC_UNKNOWN
+}
@@ -0,0 +1,109 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-api/go/onos/config/device"
+	snapshottypes "github.com/onosproject/onos-api/go/onos/config/snapshot"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	devicecache "github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	mockcache "github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_GetDeviceGroupStatus(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mgrTest.DeviceCache.(*mockcache.MockCache).EXPECT().GetDevices().Return([]*devicecache.Info{
+		{DeviceID: "device-2", Version: "1.0.0", Type: "Devicesim"},
+		{DeviceID: "device-1", Version: "1.0.0", Type: "Devicesim"},
+		{DeviceID: "other-1", Version: "1.0.0", Type: "Stratum"},
+	}).AnyTimes()
+	deviceChanges := map[device.ID][]changetypes.State{
+		"device-1": {changetypes.State_COMPLETE, changetypes.State_PENDING, changetypes.State_PENDING},
+		"device-2": {changetypes.State_FAILED, changetypes.State_COMPLETE},
+		"other-1":  {changetypes.State_FAILED},
+	}
+	mgrTest.DeviceChangesStore.(*mockstore.MockDeviceChangesStore).EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(id device.VersionedID, ch chan<- *devicechange.DeviceChange) (stream.Context, error) {
+			go func() {
+				for _, state := range deviceChanges[id.GetID()] {
+					ch <- &devicechange.DeviceChange{Status: changetypes.Status{State: state}}
+				}
+				close(ch)
+			}()
+			return stream.NewContext(func() {}), nil
+		}).AnyTimes()
+	lastSnapshot := time.Now().Add(-2 * time.Hour)
+	mgrTest.DeviceSnapshotStore.(*mockstore.MockDeviceSnapshotStore).EXPECT().List(gomock.Any()).DoAndReturn(
+		func(ch chan<- *devicesnapshot.DeviceSnapshot) (stream.Context, error) {
+			go func() {
+				ch <- &devicesnapshot.DeviceSnapshot{DeviceID: "device-1", DeviceVersion: "1.0.0",
+					Status: snapshottypes.Status{State: snapshottypes.State_COMPLETE}, Updated: lastSnapshot.Add(-time.Hour)}
+				ch <- &devicesnapshot.DeviceSnapshot{DeviceID: "device-1", DeviceVersion: "1.0.0",
+					Status: snapshottypes.Status{State: snapshottypes.State_COMPLETE}, Updated: lastSnapshot}
+				ch <- &devicesnapshot.DeviceSnapshot{DeviceID: "device-2", DeviceVersion: "1.0.0",
+					Status: snapshottypes.Status{State: snapshottypes.State_RUNNING}, Updated: time.Now()}
+				close(ch)
+			}()
+			return stream.NewContext(func() {}), nil
+		}).AnyTimes()
+
+	response, err := ExtServer{}.GetDeviceGroupStatus(adminCtx, &adminext.GetDeviceGroupStatusRequest{
+		Group:          "type=Devicesim",
+		IncludeDevices: true,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Group, "type=Devicesim")
+	assert.Equal(t, response.Devices, uint32(2))
+	// There is no model registry to read the configuration of the devices with
+	assert.Equal(t, response.SyncUnknown, uint32(2))
+	assert.Equal(t, response.PendingChanges, uint32(2))
+	assert.Equal(t, response.FailedChanges, uint32(1))
+	assert.Equal(t, response.DevicesWithPending, uint32(1))
+	assert.Equal(t, response.DevicesWithFailed, uint32(1))
+	assert.Equal(t, response.DevicesWithoutSnapshot, uint32(1))
+	assert.Equal(t, response.OldestSnapshot.Seconds, lastSnapshot.Unix())
+	assert.Assert(t, response.SnapshotAge.Seconds >= int64(2*time.Hour/time.Second))
+	assert.Equal(t, len(response.Members), 2)
+	assert.Equal(t, response.Members[0].DeviceId, "device-1")
+	assert.Equal(t, response.Members[0].PendingChanges, uint32(2))
+	assert.Equal(t, response.Members[0].LastSnapshot.Seconds, lastSnapshot.Unix())
+	assert.Equal(t, response.Members[1].DeviceId, "device-2")
+	assert.Equal(t, response.Members[1].FailedChanges, uint32(1))
+	assert.Assert(t, response.Members[1].LastSnapshot == nil)
+
+	response, err = ExtServer{}.GetDeviceGroupStatus(adminCtx, &adminext.GetDeviceGroupStatusRequest{Group: "type=Devicesim"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Members), 0)
+
+	_, err = ExtServer{}.GetDeviceGroupStatus(adminCtx, &adminext.GetDeviceGroupStatusRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.GetDeviceGroupStatus(adminCtx, &adminext.GetDeviceGroupStatusRequest{Group: "no-such-group"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = ExtServer{}.GetDeviceGroupStatus(context.Background(), &adminext.GetDeviceGroupStatusRequest{Group: "type=Devicesim"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
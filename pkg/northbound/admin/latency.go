@@ -0,0 +1,91 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/controller/change/latency"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+)
+
+// GetLatencyReport returns the percentiles of the latency of the network changes, overall and by
+// device and device type
+func (s ExtServer) GetLatencyReport(ctx context.Context, req *adminext.GetLatencyReportRequest) (*adminext.GetLatencyReportResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	response := &adminext.GetLatencyReportResponse{
+		Changes:        &adminext.LatencyStats{},
+		SlowestChanges: make([]*adminext.ChangeLatency, 0),
+		Devices:        make([]*adminext.DeviceLatency, 0),
+		DeviceTypes:    make(map[string]*adminext.LatencyStats),
+	}
+	tracker := manager.GetManager().LatencyTracker
+	if tracker == nil {
+		return response, nil
+	}
+	if slo := tracker.SLO(); slo > 0 {
+		response.Slo = types.DurationProto(slo)
+	}
+	response.Changes = newLatencyStats(tracker.ChangeStats())
+	for _, change := range tracker.SlowestChanges() {
+		changeLatency := &adminext.ChangeLatency{
+			NetworkChange: string(change.NetworkChange),
+			Latency:       types.DurationProto(change.Latency),
+			SlowestDevice: string(change.SlowestDevice),
+		}
+		if completed, err := types.TimestampProto(change.Completed); err == nil {
+			changeLatency.Completed = completed
+		}
+		response.SlowestChanges = append(response.SlowestChanges, changeLatency)
+	}
+	for _, device := range tracker.DeviceStats() {
+		if req.DeviceType != "" && string(device.DeviceType) != req.DeviceType {
+			continue
+		}
+		if req.Limit > 0 && len(response.Devices) == int(req.Limit) {
+			break
+		}
+		response.Devices = append(response.Devices, &adminext.DeviceLatency{
+			DeviceId:   string(device.DeviceID),
+			DeviceType: string(device.DeviceType),
+			Stats:      newLatencyStats(device.Stats),
+		})
+	}
+	for deviceType, stats := range tracker.TypeStats() {
+		if req.DeviceType == "" || string(deviceType) == req.DeviceType {
+			response.DeviceTypes[string(deviceType)] = newLatencyStats(stats)
+		}
+	}
+	return response, nil
+}
+
+func newLatencyStats(stats latency.Stats) *adminext.LatencyStats {
+	latencyStats := &adminext.LatencyStats{
+		Count:   uint32(stats.Count),
+		OverSlo: uint32(stats.OverSLO),
+	}
+	if stats.Count > 0 {
+		latencyStats.P50 = types.DurationProto(stats.P50)
+		latencyStats.P90 = types.DurationProto(stats.P90)
+		latencyStats.P99 = types.DurationProto(stats.P99)
+		latencyStats.Max = types.DurationProto(stats.Max)
+	}
+	return latencyStats
+}
@@ -0,0 +1,101 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_GetLatencyReport(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	response, err := ExtServer{}.GetLatencyReport(adminCtx, &adminext.GetLatencyReportRequest{})
+	assert.NilError(t, err)
+	assert.Assert(t, response.Slo == nil)
+	assert.Equal(t, response.Changes.Count, uint32(0))
+
+	mgrTest.SetLatencyTracker(time.Minute)
+	defer func() { mgrTest.LatencyTracker = nil }()
+
+	created := time.Now().Add(-time.Hour)
+	deviceChanges := map[devicechange.ID]*devicechange.DeviceChange{
+		"change-1:device-1:1.0.0": {
+			ID:      "change-1:device-1:1.0.0",
+			Change:  &devicechange.Change{DeviceID: "device-1", DeviceType: "Devicesim"},
+			Status:  changetypes.Status{State: changetypes.State_COMPLETE},
+			Updated: created.Add(30 * time.Second),
+		},
+		"change-1:device-2:1.0.0": {
+			ID:      "change-1:device-2:1.0.0",
+			Change:  &devicechange.Change{DeviceID: "device-2", DeviceType: "Stratum"},
+			Status:  changetypes.Status{State: changetypes.State_COMPLETE},
+			Updated: created.Add(2 * time.Minute),
+		},
+	}
+	mgrTest.DeviceChangesStore.(*mockstore.MockDeviceChangesStore).EXPECT().Get(gomock.Any()).DoAndReturn(
+		func(id devicechange.ID) (*devicechange.DeviceChange, error) {
+			return deviceChanges[id], nil
+		}).AnyTimes()
+	mgrTest.LatencyTracker.Record(&networkchange.NetworkChange{
+		ID:      "change-1",
+		Created: created,
+		Updated: created.Add(2 * time.Minute),
+		Status:  changetypes.Status{Phase: changetypes.Phase_CHANGE, State: changetypes.State_COMPLETE},
+		Refs: []*networkchange.DeviceChangeRef{
+			{DeviceChangeID: "change-1:device-1:1.0.0"},
+			{DeviceChangeID: "change-1:device-2:1.0.0"},
+		},
+	})
+
+	response, err = ExtServer{}.GetLatencyReport(adminCtx, &adminext.GetLatencyReportRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Slo.Seconds, int64(60))
+	assert.Equal(t, response.Changes.Count, uint32(1))
+	assert.Equal(t, response.Changes.P90.Seconds, int64(120))
+	assert.Equal(t, len(response.SlowestChanges), 1)
+	assert.Equal(t, response.SlowestChanges[0].SlowestDevice, "device-2")
+	assert.Equal(t, len(response.Devices), 2)
+	assert.Equal(t, response.Devices[0].DeviceId, "device-2")
+	assert.Equal(t, response.Devices[0].Stats.OverSlo, uint32(1))
+	assert.Equal(t, response.Devices[1].DeviceId, "device-1")
+	assert.Equal(t, response.Devices[1].Stats.P50.Seconds, int64(30))
+	assert.Equal(t, response.Devices[1].Stats.OverSlo, uint32(0))
+	assert.Equal(t, len(response.DeviceTypes), 2)
+
+	response, err = ExtServer{}.GetLatencyReport(adminCtx, &adminext.GetLatencyReportRequest{DeviceType: "Devicesim"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Devices), 1)
+	assert.Equal(t, response.Devices[0].DeviceId, "device-1")
+	assert.Equal(t, len(response.DeviceTypes), 1)
+
+	response, err = ExtServer{}.GetLatencyReport(adminCtx, &adminext.GetLatencyReportRequest{Limit: 1})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Devices), 1)
+	assert.Equal(t, response.Devices[0].DeviceId, "device-2")
+
+	_, err = ExtServer{}.GetLatencyReport(context.Background(), &adminext.GetLatencyReportRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,43 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// CountListEntries returns the number of the entries of a list of a device, or whether an entry exists
+func (s ExtServer) CountListEntries(ctx context.Context, req *adminext.CountListEntriesRequest) (*adminext.CountListEntriesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.DeviceId == "" {
+		return nil, errors.Status(errors.NewInvalid("no device given")).Err()
+	}
+	count, err := manager.GetManager().CountListEntries(devicetype.ID(req.DeviceId), devicetype.Version(req.DeviceVersion), req.Path)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	return &adminext.CountListEntriesResponse{
+		Count:  uint32(count),
+		Exists: count > 0,
+	}, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	devicecache "github.com/onosproject/onos-config/pkg/store/device/cache"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_CountListEntries(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mgrTest.DeviceCache.(*cache.MockCache).EXPECT().GetDevicesByID(devicetype.ID("device-1")).Return([]*devicecache.Info{
+		{DeviceID: "device-1", Type: "Devicesim", Version: "1.0.0"},
+	}).AnyTimes()
+	mgrTest.DeviceStore.(*mockstore.MockDeviceStore).EXPECT().Get(topodevice.ID("device-1")).
+		Return(nil, errors.NewNotFound("device-1 not found")).AnyTimes()
+	mgrTest.DeviceStateStore.(*mockstore.MockDeviceStateStore).EXPECT().Get(gomock.Any(), gomock.Any()).
+		Return([]*devicechange.PathValue{
+			{Path: "/interfaces/interface[name=eth1]/config/name", Value: devicechange.NewTypedValueString("eth1")},
+			{Path: "/interfaces/interface[name=eth1]/config/mtu", Value: devicechange.NewTypedValueUint(1500, 16)},
+			{Path: "/interfaces/interface[name=eth2]/config/name", Value: devicechange.NewTypedValueString("eth2")},
+		}, nil).AnyTimes()
+
+	response, err := ExtServer{}.CountListEntries(adminCtx, &adminext.CountListEntriesRequest{
+		DeviceId: "device-1",
+		Path:     "/interfaces/interface",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Count, uint32(2))
+	assert.Assert(t, response.Exists)
+
+	response, err = ExtServer{}.CountListEntries(adminCtx, &adminext.CountListEntriesRequest{
+		DeviceId: "device-1",
+		Path:     "/interfaces/interface[name=eth3]",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Count, uint32(0))
+	assert.Assert(t, !response.Exists)
+
+	_, err = ExtServer{}.CountListEntries(adminCtx, &adminext.CountListEntriesRequest{
+		DeviceId: "device-1",
+		Path:     "/interfaces/interface[name=eth1]/config",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.CountListEntries(adminCtx, &adminext.CountListEntriesRequest{Path: "/interfaces/interface"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func Test_CountListEntriesUnauthenticated(t *testing.T) {
+	setUpExtServer(t)
+	_, err := ExtServer{}.CountListEntries(context.Background(), &adminext.CountListEntriesRequest{DeviceId: "device-1"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,99 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/location"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// SetDeviceLocation registers the address the device with a serial number is located at
+func (s ExtServer) SetDeviceLocation(ctx context.Context, req *adminext.SetDeviceLocationRequest) (*adminext.SetDeviceLocationResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	loc := &location.Location{
+		SerialNumber: req.SerialNumber,
+		Address:      req.Address,
+		User:         callerName(ctx),
+		Updated:      time.Now(),
+	}
+	if err := manager.GetManager().LocationStore.Put(loc); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    loc.User,
+		Action:  "set-device-location",
+		Target:  req.SerialNumber,
+		Message: req.Address,
+	})
+	return &adminext.SetDeviceLocationResponse{
+		Location: deviceLocation(loc),
+	}, nil
+}
+
+// DeleteDeviceLocation deletes the location of the device with a serial number
+func (s ExtServer) DeleteDeviceLocation(ctx context.Context, req *adminext.DeleteDeviceLocationRequest) (*adminext.DeleteDeviceLocationResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := manager.GetManager().LocationStore.Delete(req.SerialNumber); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:   callerName(ctx),
+		Action: "delete-device-location",
+		Target: req.SerialNumber,
+	})
+	return &adminext.DeleteDeviceLocationResponse{}, nil
+}
+
+// ListDeviceLocations lists the locations of the devices identified by their serial number
+func (s ExtServer) ListDeviceLocations(ctx context.Context, req *adminext.ListDeviceLocationsRequest) (*adminext.ListDeviceLocationsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	locations, err := manager.GetManager().LocationStore.List()
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	response := &adminext.ListDeviceLocationsResponse{
+		Locations: make([]*adminext.DeviceLocation, 0, len(locations)),
+	}
+	for _, loc := range locations {
+		response.Locations = append(response.Locations, deviceLocation(loc))
+	}
+	return response, nil
+}
+
+func deviceLocation(loc *location.Location) *adminext.DeviceLocation {
+	result := &adminext.DeviceLocation{
+		SerialNumber: loc.SerialNumber,
+		Address:      loc.Address,
+		User:         loc.User,
+	}
+	if updated, err := types.TimestampProto(loc.Updated); err == nil {
+		result.Updated = updated
+	}
+	return result
+}
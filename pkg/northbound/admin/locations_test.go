@@ -0,0 +1,67 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/store/location"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_DeviceLocations(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mgrTest.SetLocationStore(location.NewLocalStore())
+
+	set, err := ExtServer{}.SetDeviceLocation(adminCtx, &adminext.SetDeviceLocationRequest{
+		SerialNumber: "SN-2",
+		Address:      "10.0.0.2:9339",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, "SN-2", set.Location.SerialNumber)
+	assert.Assert(t, set.Location.Updated != nil)
+	_, err = ExtServer{}.SetDeviceLocation(adminCtx, &adminext.SetDeviceLocationRequest{
+		SerialNumber: "SN-1",
+		Address:      "10.0.0.1:9339",
+	})
+	assert.NilError(t, err)
+
+	_, err = ExtServer{}.SetDeviceLocation(adminCtx, &adminext.SetDeviceLocationRequest{
+		SerialNumber: "SN-3",
+		Address:      "10.0.0.3",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	list, err := ExtServer{}.ListDeviceLocations(adminCtx, &adminext.ListDeviceLocationsRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(list.Locations))
+	assert.Equal(t, "SN-1", list.Locations[0].SerialNumber)
+	assert.Equal(t, "10.0.0.2:9339", list.Locations[1].Address)
+
+	_, err = ExtServer{}.DeleteDeviceLocation(adminCtx, &adminext.DeleteDeviceLocationRequest{SerialNumber: "SN-1"})
+	assert.NilError(t, err)
+	_, err = ExtServer{}.DeleteDeviceLocation(adminCtx, &adminext.DeleteDeviceLocationRequest{SerialNumber: "SN-1"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func Test_DeviceLocationsUnauthorized(t *testing.T) {
+	setUpExtServer(t)
+	_, err := ExtServer{}.ListDeviceLocations(context.Background(), &adminext.ListDeviceLocationsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,101 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/maintenance"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// EnterMaintenanceMode places onos-config in read-only maintenance mode
+func (s ExtServer) EnterMaintenanceMode(ctx context.Context, req *adminext.EnterMaintenanceModeRequest) (*adminext.EnterMaintenanceModeResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	user := callerName(ctx)
+	mode, err := manager.GetManager().EnterMaintenance(user, req.Reason)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	message := "entered maintenance mode"
+	if req.Reason != "" {
+		message += ": " + req.Reason
+	}
+	audit.Record(audit.Entry{
+		User:    user,
+		Action:  "enter-maintenance",
+		Message: message,
+	})
+	return &adminext.EnterMaintenanceModeResponse{
+		Mode: newMaintenanceMode(mode),
+	}, nil
+}
+
+// ExitMaintenanceMode takes onos-config out of maintenance mode
+func (s ExtServer) ExitMaintenanceMode(ctx context.Context, req *adminext.ExitMaintenanceModeRequest) (*adminext.ExitMaintenanceModeResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	user := callerName(ctx)
+	mode, err := manager.GetManager().ExitMaintenance(user)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    user,
+		Action:  "exit-maintenance",
+		Message: "exited maintenance mode entered by " + mode.User,
+	})
+	return &adminext.ExitMaintenanceModeResponse{
+		Mode: newMaintenanceMode(mode),
+	}, nil
+}
+
+// GetMaintenanceMode returns whether onos-config is in maintenance mode
+func (s ExtServer) GetMaintenanceMode(ctx context.Context, req *adminext.GetMaintenanceModeRequest) (*adminext.GetMaintenanceModeResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	mode, err := manager.GetManager().MaintenanceStore.Get()
+	if errors.IsNotFound(err) {
+		return &adminext.GetMaintenanceModeResponse{
+			Mode: &adminext.MaintenanceMode{},
+		}, nil
+	} else if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	return &adminext.GetMaintenanceModeResponse{
+		Mode: newMaintenanceMode(mode),
+	}, nil
+}
+
+func newMaintenanceMode(mode *maintenance.Mode) *adminext.MaintenanceMode {
+	maintenanceMode := &adminext.MaintenanceMode{
+		Enabled: true,
+		User:    mode.User,
+		Reason:  mode.Reason,
+	}
+	if started, err := types.TimestampProto(mode.Started); err == nil {
+		maintenanceMode.Started = started
+	}
+	return maintenanceMode
+}
@@ -0,0 +1,64 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_MaintenanceMode(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+
+	got, err := ExtServer{}.GetMaintenanceMode(adminCtx, &adminext.GetMaintenanceModeRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, got.Mode.Enabled, false)
+	_, err = ExtServer{}.ExitMaintenanceMode(adminCtx, &adminext.ExitMaintenanceModeRequest{})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	entered, err := ExtServer{}.EnterMaintenanceMode(adminCtx, &adminext.EnterMaintenanceModeRequest{Reason: "store migration"})
+	assert.NilError(t, err)
+	assert.Equal(t, entered.Mode.Enabled, true)
+	assert.Equal(t, entered.Mode.User, "admin")
+	assert.Equal(t, entered.Mode.Reason, "store migration")
+	entries := audit.Entries()
+	assert.Equal(t, entries[len(entries)-1].Action, "enter-maintenance")
+	assert.Equal(t, entries[len(entries)-1].Message, "entered maintenance mode: store migration")
+	_, err = ExtServer{}.EnterMaintenanceMode(adminCtx, &adminext.EnterMaintenanceModeRequest{})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	got, err = ExtServer{}.GetMaintenanceMode(adminCtx, &adminext.GetMaintenanceModeRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, got.Mode.Enabled, true)
+	assert.Equal(t, got.Mode.Reason, "store migration")
+
+	exited, err := ExtServer{}.ExitMaintenanceMode(adminCtx, &adminext.ExitMaintenanceModeRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, exited.Mode.Reason, "store migration")
+	entries = audit.Entries()
+	assert.Equal(t, entries[len(entries)-1].Action, "exit-maintenance")
+	got, err = ExtServer{}.GetMaintenanceMode(adminCtx, &adminext.GetMaintenanceModeRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, got.Mode.Enabled, false)
+
+	_, err = ExtServer{}.EnterMaintenanceMode(context.Background(), &adminext.EnterMaintenanceModeRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,113 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	mergestore "github.com/onosproject/onos-config/pkg/store/merge"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// PutMergeRule declares the merge strategy of a subtree, replacing the rule of the same subtree and
+// device type
+func (s ExtServer) PutMergeRule(ctx context.Context, req *adminext.PutMergeRuleRequest) (*adminext.PutMergeRuleResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.Rule == nil {
+		return nil, errors.Status(errors.NewInvalid("no rule given")).Err()
+	}
+	rule := &mergestore.Rule{
+		Path:       req.Rule.Path,
+		DeviceType: devicetype.Type(req.Rule.DeviceType),
+		Strategy:   mergestore.Strategy(req.Rule.Strategy),
+	}
+	if err := manager.GetManager().MergeStore.Put(rule); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "put-merge-rule",
+		Target:  string(rule.DeviceType),
+		Paths:   []string{rule.Path},
+		Message: string(rule.Strategy),
+	})
+	return &adminext.PutMergeRuleResponse{
+		Rule: mergeRule(rule, false),
+	}, nil
+}
+
+// DeleteMergeRule deletes the merge rule of a subtree
+func (s ExtServer) DeleteMergeRule(ctx context.Context, req *adminext.DeleteMergeRuleRequest) (*adminext.DeleteMergeRuleResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := manager.GetManager().MergeStore.Delete(devicetype.Type(req.DeviceType), req.Path); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:   callerName(ctx),
+		Action: "delete-merge-rule",
+		Target: req.DeviceType,
+		Paths:  []string{req.Path},
+	})
+	return &adminext.DeleteMergeRuleResponse{}, nil
+}
+
+// ListMergeRules lists the merge rules, or the strategies in effect for a device type
+func (s ExtServer) ListMergeRules(ctx context.Context, req *adminext.ListMergeRulesRequest) (*adminext.ListMergeRulesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	mgr := manager.GetManager()
+	stored, err := mgr.MergeStore.List()
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	rules := stored
+	if req.DeviceType != "" {
+		rules, err = mgr.MergeRules(devicetype.Type(req.DeviceType), devicetype.Version(req.DeviceVersion))
+		if err != nil {
+			return nil, errors.Status(err).Err()
+		}
+	}
+
+	isStored := make(map[mergestore.Rule]bool)
+	for _, rule := range stored {
+		isStored[*rule] = true
+	}
+	response := &adminext.ListMergeRulesResponse{
+		Rules: make([]*adminext.MergeRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		response.Rules = append(response.Rules, mergeRule(rule, !isStored[*rule]))
+	}
+	return response, nil
+}
+
+func mergeRule(rule *mergestore.Rule, plugin bool) *adminext.MergeRule {
+	return &adminext.MergeRule{
+		Path:       rule.Path,
+		DeviceType: string(rule.DeviceType),
+		Strategy:   string(rule.Strategy),
+		Plugin:     plugin,
+	}
+}
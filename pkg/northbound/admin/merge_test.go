@@ -0,0 +1,72 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_MergeRules(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+
+	rule := &adminext.MergeRule{
+		Path:       "/acl/acl-sets/acl-set[name=*][type=*]/acl-entries",
+		DeviceType: "Devicesim",
+		Strategy:   "replace",
+	}
+	response, err := ExtServer{}.PutMergeRule(adminCtx, &adminext.PutMergeRuleRequest{Rule: rule})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, response.Rule, rule)
+	_, err = ExtServer{}.PutMergeRule(adminCtx, &adminext.PutMergeRuleRequest{Rule: &adminext.MergeRule{
+		Path: "/system/ntp/servers", Strategy: "merge",
+	}})
+	assert.NilError(t, err)
+
+	rules, err := ExtServer{}.ListMergeRules(adminCtx, &adminext.ListMergeRulesRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(rules.Rules), 2)
+
+	// The strategies in effect for another device type leave out the rules of Devicesim
+	rules, err = ExtServer{}.ListMergeRules(adminCtx, &adminext.ListMergeRulesRequest{DeviceType: "Stratum", DeviceVersion: "1.0.0"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(rules.Rules), 1)
+	assert.Equal(t, rules.Rules[0].Path, "/system/ntp/servers")
+	assert.Equal(t, rules.Rules[0].Plugin, false)
+
+	_, err = ExtServer{}.DeleteMergeRule(adminCtx, &adminext.DeleteMergeRuleRequest{Path: rule.Path})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = ExtServer{}.DeleteMergeRule(adminCtx, &adminext.DeleteMergeRuleRequest{Path: rule.Path, DeviceType: "Devicesim"})
+	assert.NilError(t, err)
+}
+
+func Test_PutMergeRuleInvalid(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+
+	_, err := ExtServer{}.PutMergeRule(adminCtx, &adminext.PutMergeRuleRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.PutMergeRule(adminCtx, &adminext.PutMergeRuleRequest{Rule: &adminext.MergeRule{
+		Path: "/system/ntp/servers", Strategy: "overwrite",
+	}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = ExtServer{}.ListMergeRules(context.Background(), &adminext.ListMergeRulesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
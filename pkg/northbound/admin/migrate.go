@@ -0,0 +1,64 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// MigrateStores rewrites the records stored at a previous schema version at the current one
+func (s ExtServer) MigrateStores(ctx context.Context, req *adminext.MigrateStoresRequest) (*adminext.MigrateStoresResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	reports, err := manager.GetManager().MigrateStores(req.DryRun)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+
+	response := &adminext.MigrateStoresResponse{}
+	var outdated, migrated, failed uint64
+	for _, report := range reports {
+		migration := &adminext.StoreMigration{
+			Store:    report.Store,
+			Kind:     string(report.Kind),
+			Version:  uint64(report.Version),
+			Scanned:  uint64(report.Scanned),
+			Outdated: uint64(report.Outdated),
+			Migrated: uint64(report.Migrated),
+			Failed:   uint64(report.Failed),
+		}
+		outdated += migration.Outdated
+		migrated += migration.Migrated
+		failed += migration.Failed
+		response.Stores = append(response.Stores, migration)
+	}
+
+	if !req.DryRun {
+		audit.Record(audit.Entry{
+			User:    callerName(ctx),
+			Action:  "migrate-stores",
+			Message: fmt.Sprintf("migrated %d of %d outdated records in %d stores, %d failed", migrated, outdated, len(reports), failed),
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_MigrateStores(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+
+	// The stores of the test server keep their records in memory: there is nothing to migrate
+	before := len(audit.Entries())
+	got, err := ExtServer{}.MigrateStores(adminCtx, &adminext.MigrateStoresRequest{DryRun: true})
+	assert.NilError(t, err)
+	assert.Equal(t, len(got.Stores), 0)
+	assert.Equal(t, len(audit.Entries()), before)
+
+	got, err = ExtServer{}.MigrateStores(adminCtx, &adminext.MigrateStoresRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(got.Stores), 0)
+	entries := audit.Entries()
+	assert.Equal(t, entries[len(entries)-1].Action, "migrate-stores")
+	assert.Equal(t, entries[len(entries)-1].Message, "migrated 0 of 0 outdated records in 0 stores, 0 failed")
+
+	_, err = ExtServer{}.MigrateStores(context.Background(), &adminext.MigrateStoresRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,61 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// MoveListEntry renames the keys of a list entry of a device in one network change
+func (s ExtServer) MoveListEntry(ctx context.Context, req *adminext.MoveListEntryRequest) (*adminext.MoveListEntryResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.DeviceId == "" {
+		return nil, errors.Status(errors.NewInvalid("no device given")).Err()
+	}
+
+	mgr := manager.GetManager()
+	target := devicetype.ID(req.DeviceId)
+	deviceType, version, err := mgr.CheckCacheForDevice(target, devicetype.Type(req.DeviceType), devicetype.Version(req.DeviceVersion))
+	if err != nil {
+		return nil, errors.Status(errors.NewInvalid("%v", err)).Err()
+	}
+	change, err := mgr.MoveListEntry(target, version, deviceType, req.From, req.To)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+
+	response := &adminext.MoveListEntryResponse{ChangeId: string(change.ID)}
+	for _, deviceChange := range change.Changes {
+		response.Device = changeValues(ctx, deviceChange)
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "move-list-entry",
+		Target:  req.DeviceId,
+		Paths:   []string{req.From, req.To},
+		Message: fmt.Sprintf("moved in %s", change.ID),
+	})
+	return response, nil
+}
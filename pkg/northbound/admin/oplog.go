@@ -0,0 +1,66 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListDeviceOperations lists the gNMI Sets and Gets logged for a device
+func (s ExtServer) ListDeviceOperations(ctx context.Context, req *adminext.ListDeviceOperationsRequest) (*adminext.ListDeviceOperationsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.DeviceId == "" {
+		return nil, status.Error(codes.InvalidArgument, "no device ID given")
+	}
+
+	response := &adminext.ListDeviceOperationsResponse{
+		Operations: make([]*adminext.DeviceOperation, 0),
+	}
+	operations := southbound.GetOperationLog()
+	if operations == nil {
+		return response, nil
+	}
+	response.Capacity = uint32(operations.Capacity())
+	logged, err := operations.List(devicetype.ID(req.DeviceId), req.After)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	for _, operation := range logged {
+		deviceOperation := &adminext.DeviceOperation{
+			Sequence: operation.Sequence,
+			Method:   operation.Method,
+			Request:  operation.Request,
+			Latency:  types.DurationProto(operation.Latency),
+			Code:     operation.Code,
+			Error:    operation.Error,
+		}
+		if time, err := types.TimestampProto(operation.Time); err == nil {
+			deviceOperation.Time = time
+		}
+		response.Operations = append(response.Operations, deviceOperation)
+	}
+	return response, nil
+}
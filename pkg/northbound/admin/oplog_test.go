@@ -0,0 +1,82 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-config/pkg/store/oplog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_ListDeviceOperations(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+
+	// Nothing is listed when the operations are not logged
+	response, err := ExtServer{}.ListDeviceOperations(adminCtx, &adminext.ListDeviceOperationsRequest{DeviceId: "device-1"})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Capacity, uint32(0))
+	assert.Equal(t, len(response.Operations), 0)
+
+	operations := oplog.NewLocalStore(10)
+	southbound.SetOperationLog(operations)
+	t.Cleanup(func() { southbound.SetOperationLog(nil) })
+	assert.NilError(t, operations.Append(&oplog.Operation{
+		DeviceID: "device-1",
+		Time:     time.Now(),
+		Method:   oplog.MethodSet,
+		Request:  "update: /cont1a/leaf1a",
+		Latency:  20 * time.Millisecond,
+	}))
+	assert.NilError(t, operations.Append(&oplog.Operation{
+		DeviceID: "device-1",
+		Time:     time.Now(),
+		Method:   oplog.MethodGet,
+		Request:  "type ALL, encoding JSON, paths: /cont1a",
+		Code:     codes.Unavailable.String(),
+		Error:    "connection refused",
+	}))
+
+	response, err = ExtServer{}.ListDeviceOperations(adminCtx, &adminext.ListDeviceOperationsRequest{DeviceId: "device-1"})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Capacity, uint32(10))
+	assert.Equal(t, len(response.Operations), 2)
+	assert.Equal(t, response.Operations[0].Method, oplog.MethodSet)
+	assert.Equal(t, response.Operations[0].Request, "update: /cont1a/leaf1a")
+	assert.Equal(t, response.Operations[0].Latency.Nanos, int32(20*time.Millisecond))
+	assert.Assert(t, response.Operations[0].Time != nil)
+	assert.Equal(t, response.Operations[1].Code, "Unavailable")
+	assert.Equal(t, response.Operations[1].Error, "connection refused")
+
+	response, err = ExtServer{}.ListDeviceOperations(adminCtx, &adminext.ListDeviceOperationsRequest{
+		DeviceId: "device-1",
+		After:    response.Operations[0].Sequence,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Operations), 1)
+	assert.Equal(t, response.Operations[0].Method, oplog.MethodGet)
+
+	response, err = ExtServer{}.ListDeviceOperations(adminCtx, &adminext.ListDeviceOperationsRequest{DeviceId: "device-2"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Operations), 0)
+
+	_, err = ExtServer{}.ListDeviceOperations(adminCtx, &adminext.ListDeviceOperationsRequest{})
+	assert.Equal(t, status.Code(err), codes.InvalidArgument)
+}
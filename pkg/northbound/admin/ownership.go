@@ -0,0 +1,118 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogo/protobuf/types"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/ownership"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ClaimPaths claims the ownership of a subtree on devices for the caller, or the given owner
+func (s ExtServer) ClaimPaths(ctx context.Context, req *adminext.ClaimPathsRequest) (*adminext.ClaimPathsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	owner := req.Owner
+	if owner == "" {
+		owner = callerName(ctx)
+	}
+	claims, err := manager.GetManager().ClaimPaths(owner, deviceIDs(req.DeviceIds), req.Prefix, req.Reason)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	for _, claim := range claims {
+		audit.Record(audit.Entry{
+			User:    callerName(ctx),
+			Action:  "claim-paths",
+			Target:  string(claim.DeviceID),
+			Paths:   []string{claim.Prefix},
+			Message: fmt.Sprintf("claimed for '%s': %s", claim.Owner, claim.Reason),
+		})
+	}
+	return &adminext.ClaimPathsResponse{
+		Claims: newPathClaims(claims),
+	}, nil
+}
+
+// ReleasePaths releases the ownership of a subtree on devices
+func (s ExtServer) ReleasePaths(ctx context.Context, req *adminext.ReleasePathsRequest) (*adminext.ReleasePathsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	claims, err := manager.GetManager().ReleasePaths(callerName(ctx), deviceIDs(req.DeviceIds), req.Prefix, req.Force)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	for _, claim := range claims {
+		audit.Record(audit.Entry{
+			User:    callerName(ctx),
+			Action:  "release-paths",
+			Target:  string(claim.DeviceID),
+			Paths:   []string{claim.Prefix},
+			Message: fmt.Sprintf("released the claim of '%s'", claim.Owner),
+		})
+	}
+	return &adminext.ReleasePathsResponse{
+		Claims: newPathClaims(claims),
+	}, nil
+}
+
+// ListPathClaims lists the subtrees claimed on a device, or on all of them
+func (s ExtServer) ListPathClaims(ctx context.Context, req *adminext.ListPathClaimsRequest) (*adminext.ListPathClaimsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	claims, err := manager.GetManager().OwnershipStore.List(devicetype.ID(req.DeviceId))
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	return &adminext.ListPathClaimsResponse{
+		Claims: newPathClaims(claims),
+	}, nil
+}
+
+func deviceIDs(ids []string) []devicetype.ID {
+	deviceIDs := make([]devicetype.ID, 0, len(ids))
+	for _, id := range ids {
+		deviceIDs = append(deviceIDs, devicetype.ID(id))
+	}
+	return deviceIDs
+}
+
+func newPathClaims(claims []*ownership.Claim) []*adminext.PathClaim {
+	pathClaims := make([]*adminext.PathClaim, 0, len(claims))
+	for _, claim := range claims {
+		pathClaim := &adminext.PathClaim{
+			DeviceId: string(claim.DeviceID),
+			Prefix:   claim.Prefix,
+			Owner:    claim.Owner,
+			Reason:   claim.Reason,
+		}
+		if created, err := types.TimestampProto(claim.Created); err == nil {
+			pathClaim.Created = created
+		}
+		pathClaims = append(pathClaims, pathClaim)
+	}
+	return pathClaims
+}
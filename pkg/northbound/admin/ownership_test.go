@@ -0,0 +1,78 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	devicecache "github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_PathClaims(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mgrTest.DeviceCache.(*cache.MockCache).EXPECT().GetDevicesByID(gomock.Any()).DoAndReturn(func(id device.ID) []*devicecache.Info {
+		return []*devicecache.Info{{DeviceID: id, Type: "Devicesim", Version: "1.0.0"}}
+	}).AnyTimes()
+
+	// The subtree is claimed for the caller unless an owner is given
+	claimed, err := ExtServer{}.ClaimPaths(adminCtx, &adminext.ClaimPathsRequest{
+		DeviceIds: []string{"device-1", "device-2"},
+		Prefix:    "/interfaces/interface[name=eth1]",
+		Reason:    "uplinks",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(claimed.Claims), 2)
+	assert.Equal(t, claimed.Claims[0].Owner, "admin")
+	assert.Assert(t, claimed.Claims[0].Created != nil)
+	_, err = ExtServer{}.ClaimPaths(adminCtx, &adminext.ClaimPathsRequest{
+		DeviceIds: []string{"device-1"},
+		Prefix:    "/interfaces",
+		Owner:     "fabric-controller",
+	})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = ExtServer{}.ClaimPaths(adminCtx, &adminext.ClaimPathsRequest{
+		DeviceIds: []string{"device-1"},
+		Prefix:    "/system",
+		Owner:     "aaa-sync",
+	})
+	assert.NilError(t, err)
+
+	listed, err := ExtServer{}.ListPathClaims(adminCtx, &adminext.ListPathClaimsRequest{DeviceId: "device-1"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(listed.Claims), 2)
+	assert.Equal(t, listed.Claims[1].Prefix, "/system")
+	assert.Equal(t, listed.Claims[1].Owner, "aaa-sync")
+
+	_, err = ExtServer{}.ReleasePaths(adminCtx, &adminext.ReleasePathsRequest{DeviceIds: []string{"device-1"}, Prefix: "/system"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	released, err := ExtServer{}.ReleasePaths(adminCtx, &adminext.ReleasePathsRequest{DeviceIds: []string{"device-1"}, Prefix: "/system", Force: true})
+	assert.NilError(t, err)
+	assert.Equal(t, released.Claims[0].Owner, "aaa-sync")
+
+	listed, err = ExtServer{}.ListPathClaims(adminCtx, &adminext.ListPathClaimsRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(listed.Claims), 2)
+
+	_, err = ExtServer{}.ListPathClaims(context.Background(), &adminext.ListPathClaimsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
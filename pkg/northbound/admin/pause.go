@@ -0,0 +1,110 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/annotation"
+	"github.com/onosproject/onos-config/pkg/store/change/pause"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// PauseChange stops a pending network change from being pushed to more devices until it is resumed
+func (s ExtServer) PauseChange(ctx context.Context, req *adminext.PauseChangeRequest) (*adminext.PauseChangeResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	user := callerName(ctx)
+	p, err := manager.GetManager().PauseNetworkChange(networkchange.ID(req.Name), user, req.Reason)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	message := "paused"
+	if req.Reason != "" {
+		message = "paused: " + req.Reason
+	}
+	audit.Record(audit.Entry{
+		User:    user,
+		Action:  "pause-change",
+		Target:  req.Name,
+		Message: message,
+	})
+	return &adminext.PauseChangeResponse{
+		Change: newPausedChange(p),
+	}, nil
+}
+
+// ResumeChange lets a paused network change be pushed to the devices it has not reached
+func (s ExtServer) ResumeChange(ctx context.Context, req *adminext.ResumeChangeRequest) (*adminext.ResumeChangeResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	p, err := manager.GetManager().ResumeNetworkChange(networkchange.ID(req.Name))
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "resume-change",
+		Target:  req.Name,
+		Message: "resumed",
+	})
+	return &adminext.ResumeChangeResponse{
+		Change: newPausedChange(p),
+	}, nil
+}
+
+// ListPausedChanges lists the paused network changes
+func (s ExtServer) ListPausedChanges(ctx context.Context, req *adminext.ListPausedChangesRequest) (*adminext.ListPausedChangesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	pauses, err := manager.GetManager().PauseStore.List()
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	annotations, err := listAnnotations(annotation.NetworkChange)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	response := &adminext.ListPausedChangesResponse{
+		Changes: make([]*adminext.PausedChange, 0, len(pauses)),
+	}
+	for _, p := range pauses {
+		change := newPausedChange(p)
+		change.Annotations = annotations[string(p.NetworkChangeID)]
+		response.Changes = append(response.Changes, change)
+	}
+	return response, nil
+}
+
+func newPausedChange(p *pause.Pause) *adminext.PausedChange {
+	change := &adminext.PausedChange{
+		Name:   string(p.NetworkChangeID),
+		User:   p.User,
+		Reason: p.Reason,
+	}
+	if created, err := types.TimestampProto(p.Created); err == nil {
+		change.Created = created
+	}
+	return change
+}
@@ -0,0 +1,76 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_PauseResumeChange(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mockNwChStore := mgrTest.NetworkChangesStore.(*mockstore.MockNetworkChangesStore)
+	pending := &networkchange.NetworkChange{ID: "change-3", Index: 3, Revision: 1}
+	complete := &networkchange.NetworkChange{ID: "change-2", Index: 2, Revision: 1,
+		Status: changetypes.Status{State: changetypes.State_COMPLETE}}
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-3")).Return(pending, nil).AnyTimes()
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-2")).Return(complete, nil).AnyTimes()
+
+	_, err := ExtServer{}.PauseChange(adminCtx, &adminext.PauseChangeRequest{Name: "change-2"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = ExtServer{}.PauseChange(adminCtx, &adminext.PauseChangeRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	response, err := ExtServer{}.PauseChange(adminCtx, &adminext.PauseChangeRequest{Name: "change-3", Reason: "errors on spine-1"})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Change.Name, "change-3")
+	assert.Equal(t, response.Change.User, "admin")
+	assert.Equal(t, response.Change.Reason, "errors on spine-1")
+	entries := audit.Entries()
+	assert.Equal(t, entries[len(entries)-1].Action, "pause-change")
+	assert.Equal(t, entries[len(entries)-1].Message, "paused: errors on spine-1")
+
+	_, err = ExtServer{}.PauseChange(adminCtx, &adminext.PauseChangeRequest{Name: "change-3"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	list, err := ExtServer{}.ListPausedChanges(adminCtx, &adminext.ListPausedChangesRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(list.Changes), 1)
+	assert.Equal(t, list.Changes[0].Name, "change-3")
+
+	resumed, err := ExtServer{}.ResumeChange(adminCtx, &adminext.ResumeChangeRequest{Name: "change-3"})
+	assert.NilError(t, err)
+	assert.Equal(t, resumed.Change.Reason, "errors on spine-1")
+	entries = audit.Entries()
+	assert.Equal(t, entries[len(entries)-1].Action, "resume-change")
+
+	_, err = ExtServer{}.ResumeChange(adminCtx, &adminext.ResumeChangeRequest{Name: "change-3"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	list, err = ExtServer{}.ListPausedChanges(adminCtx, &adminext.ListPausedChangesRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(list.Changes), 0)
+
+	_, err = ExtServer{}.PauseChange(context.Background(), &adminext.PauseChangeRequest{Name: "change-3"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
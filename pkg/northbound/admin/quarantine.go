@@ -0,0 +1,88 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogo/protobuf/types"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/annotation"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ListQuarantinedDevices lists the devices whose capabilities do not match their model
+func (s ExtServer) ListQuarantinedDevices(ctx context.Context, req *adminext.ListQuarantinedDevicesRequest) (*adminext.ListQuarantinedDevicesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	quarantines, err := manager.GetManager().QuarantineStore.List()
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	annotations, err := listAnnotations(annotation.Device)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	response := &adminext.ListQuarantinedDevicesResponse{
+		Devices: make([]*adminext.QuarantinedDevice, 0, len(quarantines)),
+	}
+	for _, quarantine := range quarantines {
+		device := &adminext.QuarantinedDevice{
+			DeviceId:      string(quarantine.DeviceID),
+			DeviceVersion: string(quarantine.DeviceVersion),
+			DeviceType:    string(quarantine.DeviceType),
+			Mismatches:    quarantine.Mismatches,
+			Annotations:   annotations[string(quarantine.DeviceID)],
+		}
+		if created, err := types.TimestampProto(quarantine.Created); err == nil {
+			device.Created = created
+		}
+		response.Devices = append(response.Devices, device)
+	}
+	return response, nil
+}
+
+// RebindDevice binds a device to another version, and optionally another type, of model
+func (s ExtServer) RebindDevice(ctx context.Context, req *adminext.RebindDeviceRequest) (*adminext.RebindDeviceResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.DeviceId == "" {
+		return nil, errors.Status(errors.NewInvalid("no device given")).Err()
+	}
+	device, previous, err := manager.GetManager().RebindDevice(devicetype.ID(req.DeviceId),
+		devicetype.Version(req.DeviceVersion), devicetype.Type(req.DeviceType))
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "rebind-device",
+		Target:  req.DeviceId,
+		Message: fmt.Sprintf("bound to %s:%s instead of version %s", device.Type, device.Version, previous),
+	})
+	return &adminext.RebindDeviceResponse{
+		DeviceId:        string(device.ID),
+		PreviousVersion: string(previous),
+		DeviceVersion:   device.Version,
+		DeviceType:      string(device.Type),
+	}, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/store/quarantine"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_ListQuarantinedDevices(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	assert.NilError(t, mgrTest.QuarantineStore.Put(&quarantine.Quarantine{
+		DeviceID:      "device-1",
+		DeviceType:    "Devicesim",
+		DeviceVersion: "1.0.0",
+		Mismatches:    []string{"openconfig-interfaces 2.0.0 is reported as 2.4.3"},
+		Created:       time.Now(),
+	}))
+
+	response, err := ExtServer{}.ListQuarantinedDevices(adminCtx, &adminext.ListQuarantinedDevicesRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Devices), 1)
+	assert.Equal(t, response.Devices[0].DeviceId, "device-1")
+	assert.Equal(t, response.Devices[0].DeviceVersion, "1.0.0")
+	assert.DeepEqual(t, response.Devices[0].Mismatches, []string{"openconfig-interfaces 2.0.0 is reported as 2.4.3"})
+	assert.Assert(t, response.Devices[0].Created != nil)
+
+	_, err = ExtServer{}.ListQuarantinedDevices(context.Background(), &adminext.ListQuarantinedDevicesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_RebindDeviceInvalid(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mgrTest.DeviceStore.(*mockstore.MockDeviceStore).EXPECT().Get(topodevice.ID("device-2")).
+		Return(nil, status.Error(codes.NotFound, "device-2 not found"))
+
+	_, err := ExtServer{}.RebindDevice(adminCtx, &adminext.RebindDeviceRequest{DeviceVersion: "2.0.0"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.RebindDevice(adminCtx, &adminext.RebindDeviceRequest{DeviceId: "device-1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.RebindDevice(adminCtx, &adminext.RebindDeviceRequest{
+		DeviceId:      "device-2",
+		DeviceVersion: "2.0.0",
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = ExtServer{}.RebindDevice(context.Background(), &adminext.RebindDeviceRequest{DeviceId: "device-1", DeviceVersion: "2.0.0"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,75 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/controller"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+)
+
+var queueStates = map[controller.QueueState]adminext.QueueState{
+	controller.QueuePending:     adminext.QueueState_PENDING,
+	controller.QueueReconciling: adminext.QueueState_RECONCILING,
+	controller.QueueRetrying:    adminext.QueueState_RETRYING,
+	controller.QueueWaiting:     adminext.QueueState_WAITING,
+}
+
+// ListControllerQueues lists the requests queued in the controllers of this node
+func (s ExtServer) ListControllerQueues(ctx context.Context, req *adminext.ListControllerQueuesRequest) (*adminext.ListControllerQueuesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	response := &adminext.ListControllerQueuesResponse{}
+	for _, queue := range controller.Queues() {
+		if req.Controller != "" && queue.Name() != req.Controller {
+			continue
+		}
+		controllerQueue := &adminext.ControllerQueue{
+			Controller: queue.Name(),
+			Requests:   make([]*adminext.QueuedRequest, 0),
+		}
+		for _, request := range queue.Requests() {
+			if req.Partition != "" && request.Partition != req.Partition {
+				continue
+			}
+			controllerQueue.Requests = append(controllerQueue.Requests, queuedRequest(request))
+		}
+		response.Queues = append(response.Queues, controllerQueue)
+	}
+	return response, nil
+}
+
+func queuedRequest(request controller.QueuedRequest) *adminext.QueuedRequest {
+	queued := &adminext.QueuedRequest{
+		Id:        request.ID,
+		Partition: request.Partition,
+		State:     queueStates[request.State],
+		Attempts:  uint32(request.Attempts),
+		LastError: request.LastError,
+	}
+	if timestamp, err := types.TimestampProto(request.Queued); err == nil {
+		queued.Queued = timestamp
+	}
+	if !request.RetryAt.IsZero() {
+		if timestamp, err := types.TimestampProto(request.RetryAt); err == nil {
+			queued.RetryAt = timestamp
+		}
+	}
+	return queued
+}
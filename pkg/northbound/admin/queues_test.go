@@ -0,0 +1,63 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/controller"
+	libcontroller "github.com/onosproject/onos-lib-go/pkg/controller"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+type failingReconciler struct {
+}
+
+func (r failingReconciler) Reconcile(id libcontroller.ID) (libcontroller.Result, error) {
+	return libcontroller.Result{}, status.Error(codes.Unavailable, "device-1 is not connected")
+}
+
+func Test_ListControllerQueues(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+	queue := controller.NewQueue("TestQueue", nil)
+	queue.Filter(nil).Accept(libcontroller.NewID("device-1:1"))
+	queue.Filter(nil).Accept(libcontroller.NewID("device-1:2"))
+	_, _ = queue.Reconcile(failingReconciler{}).Reconcile(libcontroller.NewID("device-1:1"))
+
+	response, err := ExtServer{}.ListControllerQueues(adminCtx, &adminext.ListControllerQueuesRequest{Controller: "TestQueue"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Queues), 1)
+	assert.Equal(t, response.Queues[0].Controller, "TestQueue")
+	requests := response.Queues[0].Requests
+	assert.Equal(t, len(requests), 2)
+	assert.Equal(t, requests[0].Id, "device-1:1")
+	assert.Equal(t, requests[0].State, adminext.QueueState_RETRYING)
+	assert.Equal(t, requests[0].Attempts, uint32(1))
+	assert.Assert(t, requests[0].RetryAt != nil)
+	assert.Equal(t, requests[1].Id, "device-1:2")
+	assert.Equal(t, requests[1].State, adminext.QueueState_PENDING)
+	assert.Assert(t, requests[1].RetryAt == nil)
+
+	response, err = ExtServer{}.ListControllerQueues(adminCtx, &adminext.ListControllerQueuesRequest{Controller: "TestQueue", Partition: "device-2"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Queues[0].Requests), 0)
+
+	_, err = ExtServer{}.ListControllerQueues(context.Background(), &adminext.ListControllerQueuesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
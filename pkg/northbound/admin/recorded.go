@@ -0,0 +1,71 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/northbound/recorder"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListRecordedRequests lists the gNMI Set and Get requests recorded, with their responses
+func (s ExtServer) ListRecordedRequests(ctx context.Context, req *adminext.ListRecordedRequestsRequest) (*adminext.ListRecordedRequestsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	var method string
+	switch strings.ToLower(req.Method) {
+	case "":
+	case "set":
+		method = recorder.MethodSet
+	case "get":
+		method = recorder.MethodGet
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown method '%s', must be Set or Get", req.Method)
+	}
+
+	r := recorder.GetRecorder()
+	response := &adminext.ListRecordedRequestsResponse{
+		Capacity: uint32(r.Capacity()),
+		Requests: make([]*adminext.RecordedRequest, 0),
+	}
+	for _, record := range r.Records() {
+		if record.Sequence <= req.After || method != "" && record.Method != method {
+			continue
+		}
+		recorded := &adminext.RecordedRequest{
+			Sequence: record.Sequence,
+			Method:   record.Method,
+			User:     record.User,
+			Request:  record.Request,
+			Response: record.Response,
+			Error:    record.Error,
+		}
+		if record.Code != codes.OK {
+			recorded.Code = record.Code.String()
+		}
+		if time, err := types.TimestampProto(record.Time); err == nil {
+			recorded.Time = time
+		}
+		response.Requests = append(response.Requests, recorded)
+	}
+	return response, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/northbound/recorder"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_ListRecordedRequests(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+	r := recorder.GetRecorder()
+	r.SetCapacity(10)
+	t.Cleanup(func() { r.SetCapacity(0) })
+
+	interceptor := r.UnaryServerInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		if _, ok := req.(*gnmi.GetRequest); ok {
+			return nil, status.Error(codes.NotFound, "device-1 not found")
+		}
+		return &gnmi.SetResponse{}, nil
+	}
+	_, _ = interceptor(adminCtx, &gnmi.SetRequest{}, &grpc.UnaryServerInfo{FullMethod: recorder.MethodSet}, handler)
+	_, _ = interceptor(adminCtx, &gnmi.GetRequest{}, &grpc.UnaryServerInfo{FullMethod: recorder.MethodGet}, handler)
+
+	response, err := ExtServer{}.ListRecordedRequests(adminCtx, &adminext.ListRecordedRequestsRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Capacity, uint32(10))
+	assert.Equal(t, len(response.Requests), 2)
+	assert.Equal(t, response.Requests[0].Method, recorder.MethodSet)
+	assert.Equal(t, response.Requests[0].User, "admin")
+	assert.Equal(t, response.Requests[0].Code, "")
+	assert.Assert(t, response.Requests[0].Time != nil)
+	assert.Equal(t, response.Requests[1].Code, "NotFound")
+	assert.Equal(t, response.Requests[1].Error, "device-1 not found")
+
+	response, err = ExtServer{}.ListRecordedRequests(adminCtx, &adminext.ListRecordedRequestsRequest{Method: "get"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Requests), 1)
+	assert.Equal(t, response.Requests[0].Method, recorder.MethodGet)
+
+	response, err = ExtServer{}.ListRecordedRequests(adminCtx, &adminext.ListRecordedRequestsRequest{After: response.Requests[0].Sequence})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Requests), 0)
+
+	_, err = ExtServer{}.ListRecordedRequests(adminCtx, &adminext.ListRecordedRequestsRequest{Method: "Subscribe"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.ListRecordedRequests(context.Background(), &adminext.ListRecordedRequestsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
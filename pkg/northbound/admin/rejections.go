@@ -0,0 +1,93 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/grpcerrors"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/change/push"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rejectionCodes are the codes of the errors of the changes rejected by a device, by category
+var rejectionCodes = map[push.Category]codes.Code{
+	push.CategorySyntax:      codes.InvalidArgument,
+	push.CategoryResource:    codes.ResourceExhausted,
+	push.CategoryAuth:        codes.PermissionDenied,
+	push.CategoryUnsupported: codes.Unimplemented,
+	push.CategoryUnknown:     codes.Aborted,
+}
+
+// ListChangeRejections lists the rejections of a network change by its devices
+func (s ExtServer) ListChangeRejections(ctx context.Context, req *adminext.ListChangeRejectionsRequest) (*adminext.ListChangeRejectionsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	change, rejections, err := manager.GetManager().ListChangeRejections(networkchange.ID(req.Name))
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	response := &adminext.ListChangeRejectionsResponse{
+		Name:       string(change.ID),
+		Phase:      change.Status.Phase.String(),
+		State:      change.Status.State.String(),
+		Rejections: make([]*adminext.DeviceRejection, 0, len(rejections)),
+	}
+	for _, rejection := range rejections {
+		response.Rejections = append(response.Rejections, &adminext.DeviceRejection{
+			DeviceId:      string(rejection.DeviceChangeID.GetDeviceID()),
+			DeviceVersion: string(rejection.DeviceChangeID.GetDeviceVersion()),
+			Phase:         rejection.Phase.String(),
+			Category:      string(rejection.Nack.Category),
+			Code:          rejection.Nack.Code,
+			Message:       rejection.Nack.Message,
+			Path:          rejection.Nack.Path,
+		})
+	}
+	return response, nil
+}
+
+// rejectionError returns the error of a network change that failed, with the rejection of a device
+// as details if a device rejected it: its code is the one of the category of the rejection, and
+// the ErrorInfo detail carries the category, the error of the device as is and the rejected path.
+func rejectionError(err error, networkChangeID networkchange.ID) error {
+	_, rejections, listErr := manager.GetManager().ListChangeRejections(networkChangeID)
+	if listErr != nil || len(rejections) == 0 {
+		return err
+	}
+	rejection := rejections[0]
+	deviceID := string(rejection.DeviceChangeID.GetDeviceID())
+	nack := rejection.Nack
+	rejected := status.Error(rejectionCodes[nack.Category], fmt.Sprintf("%s rejected the %s of %s: %s",
+		deviceID, rejection.Phase, networkChangeID, nack.Message))
+	if nack.Path != "" {
+		rejected = grpcerrors.WithPath(rejected, nack.Path)
+	}
+	return grpcerrors.Err(grpcerrors.WithMetadata(rejected,
+		grpcerrors.TargetKey, deviceID,
+		grpcerrors.ChangeKey, string(networkChangeID),
+		grpcerrors.CategoryKey, string(nack.Category),
+		grpcerrors.DeviceCodeKey, nack.Code,
+		grpcerrors.DeviceMessageKey, nack.Message,
+		grpcerrors.PathKey, nack.Path))
+}
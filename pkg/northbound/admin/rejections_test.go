@@ -0,0 +1,98 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/northbound/grpcerrors"
+	"github.com/onosproject/onos-config/pkg/store/change/push"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_ListChangeRejections(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mockNwChStore := mgrTest.NetworkChangesStore.(*mockstore.MockNetworkChangesStore)
+	failed := &networkchange.NetworkChange{ID: "change-3", Index: 3, Revision: 1,
+		Changes: []*devicechange.Change{{DeviceID: "device-1", DeviceVersion: "1.0.0"}, {DeviceID: "device-2", DeviceVersion: "1.0.0"}},
+		Status: changetypes.Status{Incarnation: 1, State: changetypes.State_PENDING,
+			Reason: changetypes.Reason_ERROR, Message: "change rejected by device"}}
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-3")).Return(failed, nil).AnyTimes()
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-4")).Return(nil, nil)
+
+	nack := &push.Nack{Category: push.CategorySyntax, Code: "InvalidArgument", Message: "% Invalid input", Path: "/cont1a/leaf1a"}
+	assert.NilError(t, mgrTest.PushStore.Put(&push.Push{DeviceChangeID: "change-3:device-1:1.0.0",
+		Key: "change-3:device-1:1.0.0/1/CHANGE", State: push.StateRejected, Nack: nack}))
+	assert.NilError(t, mgrTest.PushStore.Put(&push.Push{DeviceChangeID: "change-3:device-2:1.0.0",
+		Key: "change-3:device-2:1.0.0/1/CHANGE", State: push.StateAcknowledged}))
+
+	response, err := ExtServer{}.ListChangeRejections(adminCtx, &adminext.ListChangeRejectionsRequest{Name: "change-3"})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Name, "change-3")
+	assert.Equal(t, response.State, "PENDING")
+	assert.Equal(t, len(response.Rejections), 1)
+	assert.DeepEqual(t, response.Rejections[0], &adminext.DeviceRejection{
+		DeviceId:      "device-1",
+		DeviceVersion: "1.0.0",
+		Phase:         "CHANGE",
+		Category:      "syntax",
+		Code:          "InvalidArgument",
+		Message:       "% Invalid input",
+		Path:          "/cont1a/leaf1a",
+	})
+
+	_, err = ExtServer{}.ListChangeRejections(adminCtx, &adminext.ListChangeRejectionsRequest{Name: "change-4"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = ExtServer{}.ListChangeRejections(adminCtx, &adminext.ListChangeRejectionsRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.ListChangeRejections(context.Background(), &adminext.ListChangeRejectionsRequest{Name: "change-3"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// A failed rollback reports the rejection in the details of its error
+	err = rejectionError(fmt.Errorf("rollback failed"), "change-3")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.ErrorContains(t, err, "device-1 rejected the CHANGE of change-3: % Invalid input")
+	info := grpcerrors.ErrorInfo(err)
+	assert.Equal(t, info.Metadata[grpcerrors.TargetKey], "device-1")
+	assert.Equal(t, info.Metadata[grpcerrors.ChangeKey], "change-3")
+	assert.Equal(t, info.Metadata[grpcerrors.CategoryKey], "syntax")
+	assert.Equal(t, info.Metadata[grpcerrors.DeviceCodeKey], "InvalidArgument")
+	assert.Equal(t, info.Metadata[grpcerrors.DeviceMessageKey], "% Invalid input")
+	assert.Equal(t, info.Metadata[grpcerrors.PathKey], "/cont1a/leaf1a")
+	var badRequest *errdetails.BadRequest
+	for _, detail := range status.Convert(err).Details() {
+		if b, ok := detail.(*errdetails.BadRequest); ok {
+			badRequest = b
+		}
+	}
+	assert.Assert(t, badRequest != nil)
+	assert.Equal(t, badRequest.FieldViolations[0].Field, "/cont1a/leaf1a")
+
+	// Once the change is pushed again, the rejection is no longer reported
+	assert.NilError(t, mgrTest.PushStore.Put(&push.Push{DeviceChangeID: "change-3:device-1:1.0.0",
+		Key: "change-3:device-1:1.0.0/2/CHANGE", State: push.StateSent}))
+	err = rejectionError(fmt.Errorf("rollback failed"), "change-3")
+	assert.Error(t, err, "rollback failed")
+}
@@ -0,0 +1,61 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ReplaceDeviceConfig makes a JSON document the whole intended configuration of a device in one
+// network change, or only returns what it would change
+func (s ExtServer) ReplaceDeviceConfig(ctx context.Context, req *adminext.ReplaceDeviceConfigRequest) (*adminext.ReplaceDeviceConfigResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.DeviceId == "" {
+		return nil, errors.Status(errors.NewInvalid("no device given")).Err()
+	}
+
+	mgr := manager.GetManager()
+	target := devicetype.ID(req.DeviceId)
+	deviceType, version, err := mgr.CheckCacheForDevice(target, devicetype.Type(req.DeviceType), devicetype.Version(req.DeviceVersion))
+	if err != nil {
+		return nil, errors.Status(errors.NewInvalid("%v", err)).Err()
+	}
+	delta, change, err := mgr.ReplaceDeviceConfig(target, version, deviceType, req.Config, req.DryRun)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+
+	response := &adminext.ReplaceDeviceConfigResponse{Device: changeValues(ctx, delta)}
+	if change != nil {
+		response.ChangeId = string(change.ID)
+		audit.Record(audit.Entry{
+			User:    callerName(ctx),
+			Action:  "replace-device-config",
+			Target:  req.DeviceId,
+			Message: fmt.Sprintf("replaced %d values in %s", len(delta.Values), change.ID),
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	devicecache "github.com/onosproject/onos-config/pkg/store/device/cache"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_ReplaceDeviceConfigInvalid(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mgrTest.DeviceCache.(*cache.MockCache).EXPECT().GetDevicesByID(devicetype.ID("device-1")).Return([]*devicecache.Info{
+		{DeviceID: "device-1", Type: "Devicesim", Version: "1.0.0"},
+	}).AnyTimes()
+	mgrTest.DeviceStore.(*mockstore.MockDeviceStore).EXPECT().Get(topodevice.ID("device-1")).
+		Return(nil, errors.NewNotFound("device-1 not found")).AnyTimes()
+
+	_, err := ExtServer{}.ReplaceDeviceConfig(adminCtx, &adminext.ReplaceDeviceConfigRequest{Config: []byte("{}")})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.ReplaceDeviceConfig(adminCtx, &adminext.ReplaceDeviceConfigRequest{DeviceId: "device-1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.ErrorContains(t, err, "no configuration given")
+}
+
+func Test_ReplaceDeviceConfigUnauthenticated(t *testing.T) {
+	setUpExtServer(t)
+	_, err := ExtServer{}.ReplaceDeviceConfig(context.Background(), &adminext.ReplaceDeviceConfigRequest{DeviceId: "device-1"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
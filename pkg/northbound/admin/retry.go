@@ -0,0 +1,68 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// RetryChange re-drives a network change that failed, optionally only on the devices it did not reach
+func (s ExtServer) RetryChange(ctx context.Context, req *adminext.RetryChangeRequest) (*adminext.RetryChangeResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	user := callerName(ctx)
+	message := fmt.Sprintf("by '%s'", user)
+	if req.Reason != "" {
+		message = fmt.Sprintf("%s: %s", message, req.Reason)
+	}
+	change, retried, err := manager.GetManager().RetryNetworkChange(networkchange.ID(req.Name), req.FailedOnly, message)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	devices := make([]string, 0, len(retried))
+	for _, deviceChange := range retried {
+		devices = append(devices, string(deviceChange.Change.DeviceID))
+	}
+	auditMessage := fmt.Sprintf("retried on %s", strings.Join(devices, ","))
+	if req.FailedOnly {
+		auditMessage = fmt.Sprintf("retried on the devices it did not reach: %s", strings.Join(devices, ","))
+	}
+	if req.Reason != "" {
+		auditMessage = fmt.Sprintf("%s: %s", auditMessage, req.Reason)
+	}
+	audit.Record(audit.Entry{
+		User:    user,
+		Action:  "retry-change",
+		Target:  req.Name,
+		Message: auditMessage,
+	})
+	return &adminext.RetryChangeResponse{
+		Name:    string(change.ID),
+		Phase:   change.Status.Phase.String(),
+		State:   change.Status.State.String(),
+		Message: change.Status.Message,
+		Devices: devices,
+	}, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_RetryChange(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mockNwChStore := mgrTest.NetworkChangesStore.(*mockstore.MockNetworkChangesStore)
+	mockDevChStore := mgrTest.DeviceChangesStore.(*mockstore.MockDeviceChangesStore)
+	rejected := &networkchange.NetworkChange{ID: "change-3", Index: 3, Revision: 1,
+		Changes: []*devicechange.Change{{DeviceID: "device-1"}},
+		Refs:    []*networkchange.DeviceChangeRef{{DeviceChangeID: "change-3:device-1:1.0.0"}},
+		Status: changetypes.Status{Incarnation: 1, State: changetypes.State_PENDING,
+			Reason: changetypes.Reason_ERROR, Message: "change rejected by device"}}
+	complete := &networkchange.NetworkChange{ID: "change-2", Index: 2, Revision: 1,
+		Status: changetypes.Status{State: changetypes.State_COMPLETE}}
+	rolledBack := &devicechange.DeviceChange{ID: "change-3:device-1:1.0.0", Revision: 1,
+		Change: rejected.Changes[0],
+		Status: changetypes.Status{Incarnation: 1, Phase: changetypes.Phase_ROLLBACK, State: changetypes.State_COMPLETE,
+			Reason: changetypes.Reason_ERROR, Message: "device rejected /a/b"}}
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-3")).Return(rejected, nil).AnyTimes()
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-2")).Return(complete, nil).AnyTimes()
+	mockNwChStore.EXPECT().Update(rejected).Return(nil)
+	mockNwChStore.EXPECT().GetNext(networkchange.Index(3)).Return(nil, errors.NewNotFound("no next change"))
+	mockDevChStore.EXPECT().Get(devicechange.ID("change-3:device-1:1.0.0")).Return(rolledBack, nil)
+	mockDevChStore.EXPECT().Update(gomock.Any()).DoAndReturn(func(deviceChange *devicechange.DeviceChange) error {
+		assert.Equal(t, deviceChange.Status.Incarnation, uint64(2))
+		assert.Equal(t, deviceChange.Status.Phase, changetypes.Phase_CHANGE)
+		assert.Equal(t, deviceChange.Status.State, changetypes.State_PENDING)
+		return nil
+	})
+
+	_, err := ExtServer{}.RetryChange(adminCtx, &adminext.RetryChangeRequest{Name: "change-2"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = ExtServer{}.RetryChange(adminCtx, &adminext.RetryChangeRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	response, err := ExtServer{}.RetryChange(adminCtx, &adminext.RetryChangeRequest{Name: "change-3", FailedOnly: true, Reason: "device-1 upgraded"})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Name, "change-3")
+	assert.Equal(t, response.Phase, "CHANGE")
+	assert.Equal(t, response.State, "PENDING")
+	assert.Equal(t, response.Message, "Retried: by 'admin': device-1 upgraded")
+	assert.DeepEqual(t, response.Devices, []string{"device-1"})
+	entries := audit.Entries()
+	assert.Equal(t, entries[len(entries)-1].Action, "retry-change")
+	assert.Equal(t, entries[len(entries)-1].Message, "retried on the devices it did not reach: device-1: device-1 upgraded")
+
+	_, err = ExtServer{}.RetryChange(context.Background(), &adminext.RetryChangeRequest{Name: "change-3"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_RetryChangeSuperseded(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mockNwChStore := mgrTest.NetworkChangesStore.(*mockstore.MockNetworkChangesStore)
+	cancelled := &networkchange.NetworkChange{ID: "change-3", Index: 3, Revision: 1,
+		Changes: []*devicechange.Change{{DeviceID: "device-1"}},
+		Status:  changetypes.Status{Incarnation: 1, State: changetypes.State_FAILED}}
+	other := &networkchange.NetworkChange{ID: "change-4", Index: 4, Revision: 1,
+		Changes: []*devicechange.Change{{DeviceID: "device-2"}}}
+	later := &networkchange.NetworkChange{ID: "change-5", Index: 5, Revision: 1,
+		Changes: []*devicechange.Change{{DeviceID: "device-1"}}}
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-3")).Return(cancelled, nil)
+	mockNwChStore.EXPECT().GetNext(networkchange.Index(3)).Return(other, nil)
+	mockNwChStore.EXPECT().GetNext(networkchange.Index(4)).Return(later, nil)
+
+	_, err := ExtServer{}.RetryChange(adminCtx, &adminext.RetryChangeRequest{Name: "change-3"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.ErrorContains(t, err, "change-5 was made after it to device-1")
+
+	// Once the later change is rolled back, the cancelled change may be retried
+	later.Status = changetypes.Status{Phase: changetypes.Phase_ROLLBACK, State: changetypes.State_COMPLETE}
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-3")).Return(cancelled, nil)
+	mockNwChStore.EXPECT().GetNext(networkchange.Index(3)).Return(other, nil)
+	mockNwChStore.EXPECT().GetNext(networkchange.Index(4)).Return(later, nil)
+	mockNwChStore.EXPECT().GetNext(networkchange.Index(5)).Return(nil, errors.NewNotFound("no next change"))
+	mockNwChStore.EXPECT().Update(cancelled).Return(nil)
+	response, err := ExtServer{}.RetryChange(adminCtx, &adminext.RetryChangeRequest{Name: "change-3"})
+	assert.NilError(t, err)
+	assert.Equal(t, response.State, "PENDING")
+	assert.Equal(t, len(response.Devices), 0)
+}
@@ -0,0 +1,132 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/sampling"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// SetSampleInterval samples a subtree of the state of a device at the given interval
+func (s ExtServer) SetSampleInterval(ctx context.Context, req *adminext.SetSampleIntervalRequest) (*adminext.SetSampleIntervalResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.Interval == nil {
+		return nil, errors.Status(errors.NewInvalid("no interval given")).Err()
+	}
+	interval := &sampling.Interval{
+		DeviceID: req.Interval.DeviceId,
+		Prefix:   req.Interval.Prefix,
+		User:     callerName(ctx),
+	}
+	var err error
+	if interval.SampleInterval, err = fromDurationProto("sample interval", req.Interval.SampleInterval); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	if interval.HeartbeatInterval, err = fromDurationProto("heartbeat interval", req.Interval.HeartbeatInterval); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	if err := manager.GetManager().SetSampleInterval(interval); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	message := fmt.Sprintf("sample interval %s", interval.SampleInterval)
+	if interval.HeartbeatInterval > 0 {
+		message += fmt.Sprintf(", heartbeat interval %s", interval.HeartbeatInterval)
+	}
+	audit.Record(audit.Entry{
+		User:    interval.User,
+		Action:  "set-sample-interval",
+		Target:  interval.DeviceID,
+		Paths:   []string{samplePrefix(interval.Prefix)},
+		Message: message,
+	})
+	return &adminext.SetSampleIntervalResponse{
+		Interval: newSampleInterval(interval),
+	}, nil
+}
+
+// ResetSampleInterval leaves the sampling of a subtree of the state of a device to the device
+func (s ExtServer) ResetSampleInterval(ctx context.Context, req *adminext.ResetSampleIntervalRequest) (*adminext.ResetSampleIntervalResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	interval, err := manager.GetManager().ResetSampleInterval(req.DeviceId, req.Prefix)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "reset-sample-interval",
+		Target:  req.DeviceId,
+		Paths:   []string{samplePrefix(interval.Prefix)},
+		Message: "sampled at the interval of the device",
+	})
+	return &adminext.ResetSampleIntervalResponse{
+		Interval: newSampleInterval(interval),
+	}, nil
+}
+
+// ListSampleIntervals lists the sample intervals set by the operators
+func (s ExtServer) ListSampleIntervals(ctx context.Context, req *adminext.ListSampleIntervalsRequest) (*adminext.ListSampleIntervalsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	intervals, err := manager.GetManager().ListSampleIntervals(req.DeviceId)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	response := &adminext.ListSampleIntervalsResponse{
+		Intervals: make([]*adminext.SampleInterval, 0, len(intervals)),
+	}
+	for _, interval := range intervals {
+		response.Intervals = append(response.Intervals, newSampleInterval(interval))
+	}
+	return response, nil
+}
+
+// samplePrefix names the whole state of a device for the audit log
+func samplePrefix(prefix string) string {
+	if prefix == "" {
+		return "/"
+	}
+	return prefix
+}
+
+func newSampleInterval(interval *sampling.Interval) *adminext.SampleInterval {
+	sampleInterval := &adminext.SampleInterval{
+		DeviceId:       interval.DeviceID,
+		Prefix:         interval.Prefix,
+		SampleInterval: types.DurationProto(interval.SampleInterval),
+		User:           interval.User,
+	}
+	if interval.HeartbeatInterval > 0 {
+		sampleInterval.HeartbeatInterval = types.DurationProto(interval.HeartbeatInterval)
+	}
+	if !interval.Updated.IsZero() {
+		if updated, err := types.TimestampProto(interval.Updated); err == nil {
+			sampleInterval.Updated = updated
+		}
+	}
+	return sampleInterval
+}
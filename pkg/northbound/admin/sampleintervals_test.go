@@ -0,0 +1,90 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_SampleIntervals(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+
+	response, err := ExtServer{}.SetSampleInterval(adminCtx, &adminext.SetSampleIntervalRequest{
+		Interval: &adminext.SampleInterval{
+			DeviceId:          "Device1",
+			Prefix:            "/interfaces/interface[name=*]/state/counters",
+			SampleInterval:    types.DurationProto(10 * time.Second),
+			HeartbeatInterval: types.DurationProto(time.Minute),
+		},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Interval.User, "admin")
+	assert.Assert(t, response.Interval.Updated != nil)
+	entries := audit.Entries()
+	assert.Equal(t, entries[len(entries)-1].Action, "set-sample-interval")
+	assert.Equal(t, entries[len(entries)-1].Message, "sample interval 10s, heartbeat interval 1m0s")
+
+	_, err = ExtServer{}.SetSampleInterval(adminCtx, &adminext.SetSampleIntervalRequest{
+		Interval: &adminext.SampleInterval{
+			DeviceId:       "Device2",
+			Prefix:         "/",
+			SampleInterval: types.DurationProto(time.Second),
+		},
+	})
+	assert.NilError(t, err)
+
+	list, err := ExtServer{}.ListSampleIntervals(adminCtx, &adminext.ListSampleIntervalsRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(list.Intervals), 2)
+	assert.Equal(t, list.Intervals[0].DeviceId, "Device1")
+	assert.Equal(t, list.Intervals[1].Prefix, "")
+	assert.Assert(t, list.Intervals[1].HeartbeatInterval == nil)
+	list, err = ExtServer{}.ListSampleIntervals(adminCtx, &adminext.ListSampleIntervalsRequest{DeviceId: "Device2"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(list.Intervals), 1)
+
+	_, err = ExtServer{}.SetSampleInterval(adminCtx, &adminext.SetSampleIntervalRequest{
+		Interval: &adminext.SampleInterval{DeviceId: "Device1"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.SetSampleInterval(adminCtx, &adminext.SetSampleIntervalRequest{
+		Interval: &adminext.SampleInterval{
+			DeviceId:       "Device1",
+			Prefix:         "/interfaces/interface[name=eth0",
+			SampleInterval: types.DurationProto(time.Second),
+		},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.SetSampleInterval(adminCtx, &adminext.SetSampleIntervalRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	reset, err := ExtServer{}.ResetSampleInterval(adminCtx, &adminext.ResetSampleIntervalRequest{DeviceId: "Device2", Prefix: "/"})
+	assert.NilError(t, err)
+	assert.Equal(t, reset.Interval.SampleInterval.Seconds, int64(1))
+	_, err = ExtServer{}.ResetSampleInterval(adminCtx, &adminext.ResetSampleIntervalRequest{DeviceId: "Device2"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = ExtServer{}.ListSampleIntervals(context.Background(), &adminext.ListSampleIntervalsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
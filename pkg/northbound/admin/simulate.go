@@ -0,0 +1,105 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	networksnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/modelregistry/jsonvalues"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// SimulateChange applies a change to a scratch copy of the latest configuration of a device, or of
+// its configuration captured by a snapshot, validates the result against the device model and
+// returns it. Nothing is stored nor sent to the device.
+func (s ExtServer) SimulateChange(ctx context.Context, req *adminext.SimulateChangeRequest) (*adminext.SimulateChangeResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.DeviceId == "" {
+		return nil, errors.Status(errors.NewInvalid("no device given")).Err()
+	}
+	if len(req.Updates) == 0 && len(req.Deletes) == 0 {
+		return nil, errors.Status(errors.NewInvalid("no updates found in change on %s - invalid", req.DeviceId)).Err()
+	}
+
+	mgr := manager.GetManager()
+	target := devicetype.ID(req.DeviceId)
+	var snapshot *devicesnapshot.Snapshot
+	var deviceType devicetype.Type
+	var version devicetype.Version
+	var err error
+	if req.SnapshotId != "" {
+		// The device is simulated as it was in the snapshot, even if it is no longer known
+		snapshot, err = mgr.GetSnapshotValues(networksnapshot.ID(req.SnapshotId), target, devicetype.Version(req.DeviceVersion))
+		if err != nil {
+			return nil, errors.Status(err).Err()
+		}
+		deviceType, version = snapshot.DeviceType, snapshot.DeviceVersion
+	} else {
+		deviceType, version, err = mgr.CheckCacheForDevice(target, devicetype.Type(req.DeviceType), devicetype.Version(req.DeviceVersion))
+		if err != nil {
+			return nil, errors.Status(errors.NewInvalid("%v", err)).Err()
+		}
+	}
+	plugin, err := mgr.ModelRegistry.GetPlugin(utils.ToModelName(deviceType, version))
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+
+	updates := make(devicechange.TypedValueMap)
+	for _, update := range req.Updates {
+		pathValues, err := jsonvalues.DecomposeJSONWithPaths(update.Path, []byte(update.Value), nil, plugin.ReadWritePaths)
+		if err != nil {
+			return nil, errors.Status(errors.NewInvalid("invalid value for %s: %v", update.Path, err)).Err()
+		}
+		for _, pathValue := range pathValues {
+			updates[pathValue.Path] = pathValue.GetValue()
+		}
+	}
+
+	var config []*devicechange.PathValue
+	if snapshot != nil {
+		log.Infof("Simulating change on %s:%s:%s as of snapshot %s as requested by '%s'", target, deviceType, version,
+			req.SnapshotId, callerName(ctx))
+		config, err = mgr.SimulateChangeOnSnapshot(snapshot, updates, req.Deletes)
+	} else {
+		log.Infof("Simulating change on %s:%s:%s as requested by '%s'", target, deviceType, version, callerName(ctx))
+		config, err = mgr.SimulateChange(target, version, deviceType, updates, req.Deletes)
+	}
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	values := make([]*adminext.PathValue, 0, len(config))
+	for _, value := range config {
+		values = append(values, pathValue(ctx, req.DeviceId, value.Path, value.Value, false))
+	}
+	return &adminext.SimulateChangeResponse{
+		Device: &adminext.DeviceValues{
+			DeviceId:      req.DeviceId,
+			DeviceVersion: string(version),
+			DeviceType:    string(deviceType),
+			Values:        values,
+		},
+	}, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_SimulateChangeInvalid(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+	server := ExtServer{}
+
+	_, err := server.SimulateChange(adminCtx, &adminext.SimulateChangeRequest{
+		Updates: []*adminext.SimulatedUpdate{{Path: "/cont1a/leaf1a", Value: `"value"`}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.SimulateChange(adminCtx, &adminext.SimulateChangeRequest{DeviceId: "device-1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func Test_SimulateChangeOnSnapshot(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	expectSnapshots(mgrTest)
+	server := ExtServer{}
+
+	// The device must be in the snapshot, though it may no longer be known
+	_, err := server.SimulateChange(adminCtx, &adminext.SimulateChangeRequest{
+		DeviceId:   "device-2",
+		SnapshotId: "snapshot-2",
+		Deletes:    []string{"/system/config/hostname"},
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = server.SimulateChange(adminCtx, &adminext.SimulateChangeRequest{
+		DeviceId:   "device-1",
+		SnapshotId: "snapshot-4",
+		Deletes:    []string{"/system/config/hostname"},
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func Test_SimulateChangeUnauthenticated(t *testing.T) {
+	setUpExtServer(t)
+	_, err := ExtServer{}.SimulateChange(context.Background(), &adminext.SimulateChangeRequest{
+		DeviceId: "device-1",
+		Deletes:  []string{"/cont1a/leaf1a"},
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,137 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	networksnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/devicegroup"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// CompactChanges takes a snapshot of all the devices, or of the devices of a partition, and
+// deletes the network changes it covers
+func (s ExtServer) CompactChanges(ctx context.Context, req *adminext.CompactChangesRequest) (*adminext.CompactChangesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	var retainWindow *time.Duration
+	if req.RetentionPeriod != nil {
+		period, err := types.DurationFromProto(req.RetentionPeriod)
+		if err != nil || period < 0 {
+			return nil, errors.Status(errors.NewInvalid("invalid retention period %v", req.RetentionPeriod)).Err()
+		}
+		retainWindow = &period
+	}
+
+	snapshot, err := manager.GetManager().CompactChanges(req.Partition, retainWindow)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "compact-changes",
+		Target:  req.Partition,
+		Message: fmt.Sprintf("%d devices snapshotted as %s", len(snapshot.Refs), snapshot.ID),
+	})
+	return &adminext.CompactChangesResponse{
+		SnapshotId: string(snapshot.ID),
+		Partition:  req.Partition,
+		Devices:    int32(len(snapshot.Refs)),
+	}, nil
+}
+
+// ListDeviceGroups lists the device groups that snapshots can be scoped to
+func (s ExtServer) ListDeviceGroups(ctx context.Context, req *adminext.ListDeviceGroupsRequest) (*adminext.ListDeviceGroupsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	response := &adminext.ListDeviceGroupsResponse{}
+	for _, group := range devicegroup.GetRegistry().List() {
+		response.Groups = append(response.Groups, &adminext.DeviceGroup{
+			Name:     group.Name,
+			Devices:  group.Devices,
+			Selector: group.Selector,
+		})
+	}
+	return response, nil
+}
+
+// ListSnapshotDevices lists the devices a network snapshot covers
+func (s ExtServer) ListSnapshotDevices(ctx context.Context, req *adminext.ListSnapshotDevicesRequest) (*adminext.ListSnapshotDevicesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.SnapshotId == "" {
+		return nil, errors.Status(errors.NewInvalid("no snapshot given")).Err()
+	}
+	networkSnapshot, deviceSnapshots, err := manager.GetManager().ListSnapshotDevices(networksnapshot.ID(req.SnapshotId))
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	partition, err := manager.GetManager().NetworkSnapshotStore.GetPartition(networkSnapshot.ID)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	response := &adminext.ListSnapshotDevicesResponse{
+		SnapshotId: string(networkSnapshot.ID),
+		Partition:  partition,
+		Phase:      networkSnapshot.Status.Phase.String(),
+		State:      networkSnapshot.Status.State.String(),
+		Devices:    make([]*adminext.SnapshotDevice, 0, len(deviceSnapshots)),
+	}
+	for _, deviceSnapshot := range deviceSnapshots {
+		response.Devices = append(response.Devices, &adminext.SnapshotDevice{
+			DeviceId:              string(deviceSnapshot.DeviceID),
+			DeviceVersion:         string(deviceSnapshot.DeviceVersion),
+			DeviceType:            string(deviceSnapshot.DeviceType),
+			MaxNetworkChangeIndex: uint64(deviceSnapshot.MaxNetworkChangeIndex),
+			Phase:                 deviceSnapshot.Status.Phase.String(),
+			State:                 deviceSnapshot.Status.State.String(),
+		})
+	}
+	return response, nil
+}
+
+// GetSnapshotValues streams the values a network snapshot captured for a device
+func (s ExtServer) GetSnapshotValues(req *adminext.GetSnapshotValuesRequest, stream adminext.ConfigAdminExtService_GetSnapshotValuesServer) error {
+	ctx := stream.Context()
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return err
+	}
+	if req.SnapshotId == "" || req.DeviceId == "" {
+		return errors.Status(errors.NewInvalid("a snapshot and a device must be given")).Err()
+	}
+	snapshot, err := manager.GetManager().GetSnapshotValues(networksnapshot.ID(req.SnapshotId),
+		devicetype.ID(req.DeviceId), devicetype.Version(req.DeviceVersion))
+	if err != nil {
+		return errors.Status(err).Err()
+	}
+	for _, value := range snapshot.Values {
+		if err := stream.Send(pathValue(ctx, req.DeviceId, value.Path, value.Value, false)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
+	configtypes "github.com/onosproject/onos-api/go/onos/config"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-api/go/onos/config/snapshot"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	networksnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/network"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/devicegroup"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_CompactChangesInvalid(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+
+	_, err := ExtServer{}.CompactChanges(adminCtx, &adminext.CompactChangesRequest{Partition: "type="})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = ExtServer{}.CompactChanges(adminCtx, &adminext.CompactChangesRequest{Partition: "no-such-group"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = ExtServer{}.CompactChanges(adminCtx, &adminext.CompactChangesRequest{RetentionPeriod: &types.Duration{Seconds: -1}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func Test_CompactChangesUnauthenticated(t *testing.T) {
+	setUpExtServer(t)
+	_, err := ExtServer{}.CompactChanges(context.Background(), &adminext.CompactChangesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_ListDeviceGroups(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+	err := devicegroup.GetRegistry().Add(&devicegroup.Group{
+		Name:     "list-groups-test",
+		Selector: map[string]string{devicegroup.AttributeType: "Devicesim"},
+	})
+	assert.NilError(t, err)
+
+	response, err := ExtServer{}.ListDeviceGroups(adminCtx, &adminext.ListDeviceGroupsRequest{})
+	assert.NilError(t, err)
+	found := false
+	for _, group := range response.Groups {
+		if group.Name == "list-groups-test" {
+			found = true
+			assert.Equal(t, "Devicesim", group.Selector[devicegroup.AttributeType])
+		}
+	}
+	assert.Assert(t, found)
+
+	_, err = ExtServer{}.ListDeviceGroups(context.Background(), &adminext.ListDeviceGroupsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+// snapshotValuesStream collects the values sent by GetSnapshotValues
+type snapshotValuesStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	values []*adminext.PathValue
+}
+
+func (s *snapshotValuesStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *snapshotValuesStream) Send(value *adminext.PathValue) error {
+	s.values = append(s.values, value)
+	return nil
+}
+
+// expectSnapshots sets up three network snapshots of device-1. The full snapshot of the device
+// was stored by snapshot-2, of the Devicesim partition, replacing that of snapshot-1 whose values
+// were archived, and snapshot-3 stored a delta.
+func expectSnapshots(mgrTest *manager.Manager) {
+	mockNwSnapStore := mgrTest.NetworkSnapshotStore.(*mockstore.MockNetworkSnapshotStore)
+	mockDevSnapStore := mgrTest.DeviceSnapshotStore.(*mockstore.MockDeviceSnapshotStore)
+
+	deviceSnapshots := make([]*devicesnapshot.DeviceSnapshot, 0, 3)
+	for _, index := range []networksnapshot.Index{1, 2, 3} {
+		networkSnapshot := &networksnapshot.NetworkSnapshot{
+			ID:    networksnapshot.ID(fmt.Sprintf("snapshot-%d", index)),
+			Index: index,
+			Status: snapshot.Status{
+				Phase: snapshot.Phase_DELETE,
+				State: snapshot.State_COMPLETE,
+			},
+		}
+		deviceSnapshot := &devicesnapshot.DeviceSnapshot{
+			ID:            devicesnapshot.GetSnapshotID(configtypes.ID(networkSnapshot.ID), "device-1", "1.0.0"),
+			DeviceID:      "device-1",
+			DeviceVersion: "1.0.0",
+			DeviceType:    "Devicesim",
+			NetworkSnapshot: devicesnapshot.NetworkSnapshotRef{
+				ID:    configtypes.ID(networkSnapshot.ID),
+				Index: configtypes.Index(index),
+			},
+			MaxNetworkChangeIndex: configtypes.Index(index * 10),
+			Status: snapshot.Status{
+				Phase: snapshot.Phase_DELETE,
+				State: snapshot.State_COMPLETE,
+			},
+		}
+		networkSnapshot.Refs = []*networksnapshot.DeviceSnapshotRef{{DeviceSnapshotID: deviceSnapshot.ID}}
+		mockNwSnapStore.EXPECT().Get(networkSnapshot.ID).Return(networkSnapshot, nil).AnyTimes()
+		partition := ""
+		if index == 2 {
+			partition = "type=Devicesim"
+		}
+		mockNwSnapStore.EXPECT().GetPartition(networkSnapshot.ID).Return(partition, nil).AnyTimes()
+		mockDevSnapStore.EXPECT().Get(deviceSnapshot.ID).Return(deviceSnapshot, nil).AnyTimes()
+		deviceSnapshots = append(deviceSnapshots, deviceSnapshot)
+	}
+	mockDevSnapStore.EXPECT().List(gomock.Any()).DoAndReturn(func(ch chan<- *devicesnapshot.DeviceSnapshot) (stream.Context, error) {
+		go func() {
+			defer close(ch)
+			for _, deviceSnapshot := range deviceSnapshots {
+				ch <- deviceSnapshot
+			}
+		}()
+		return stream.NewContext(func() {}), nil
+	}).AnyTimes()
+	mockDevSnapStore.EXPECT().LoadArchived(devicesnapshot.GetSnapshotID("snapshot-1", "device-1", "1.0.0")).Return(&devicesnapshot.Snapshot{
+		ID:            "device-1",
+		DeviceID:      "device-1",
+		DeviceVersion: "1.0.0",
+		DeviceType:    "Devicesim",
+		SnapshotID:    devicesnapshot.GetSnapshotID("snapshot-1", "device-1", "1.0.0"),
+		ChangeIndex:   10,
+		Values: []*devicechange.PathValue{
+			{Path: "/system/config/hostname", Value: devicechange.NewTypedValueString("device-0")},
+		},
+	}, nil).AnyTimes()
+	mockDevSnapStore.EXPECT().LoadArchived(gomock.Any()).Return(nil, errors.NewNotFound("not found")).AnyTimes()
+	mockNwSnapStore.EXPECT().Get(gomock.Any()).Return(nil, errors.NewNotFound("not found")).AnyTimes()
+	mockDevSnapStore.EXPECT().LoadChain(devicetype.NewVersionedID("device-1", "1.0.0")).Return([]*devicesnapshot.Snapshot{
+		{
+			ID:            "device-1",
+			DeviceID:      "device-1",
+			DeviceVersion: "1.0.0",
+			DeviceType:    "Devicesim",
+			SnapshotID:    devicesnapshot.GetSnapshotID("snapshot-2", "device-1", "1.0.0"),
+			ChangeIndex:   20,
+			Values: []*devicechange.PathValue{
+				{Path: "/system/config/motd-banner", Value: devicechange.NewTypedValueString("hello")},
+				{Path: "/system/config/hostname", Value: devicechange.NewTypedValueString("device-1")},
+			},
+		},
+		{
+			DeviceID:      "device-1",
+			DeviceVersion: "1.0.0",
+			DeviceType:    "Devicesim",
+			SnapshotID:    devicesnapshot.GetSnapshotID("snapshot-3", "device-1", "1.0.0"),
+			ChangeIndex:   30,
+			Values: []*devicechange.PathValue{
+				{Path: "/system/config/motd-banner"},
+				{Path: "/system/clock/config/timezone-name", Value: devicechange.NewTypedValueString("UTC")},
+			},
+		},
+	}, nil).AnyTimes()
+}
+
+func Test_ListSnapshotDevices(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	expectSnapshots(mgrTest)
+
+	response, err := ExtServer{}.ListSnapshotDevices(adminCtx, &adminext.ListSnapshotDevicesRequest{SnapshotId: "snapshot-2"})
+	assert.NilError(t, err)
+	assert.Equal(t, "snapshot-2", response.SnapshotId)
+	assert.Equal(t, "type=Devicesim", response.Partition)
+	assert.Equal(t, "COMPLETE", response.State)
+	assert.Equal(t, 1, len(response.Devices))
+	assert.Equal(t, "device-1", response.Devices[0].DeviceId)
+	assert.Equal(t, "1.0.0", response.Devices[0].DeviceVersion)
+	assert.Equal(t, uint64(20), response.Devices[0].MaxNetworkChangeIndex)
+
+	_, err = ExtServer{}.ListSnapshotDevices(adminCtx, &adminext.ListSnapshotDevicesRequest{SnapshotId: "snapshot-4"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = ExtServer{}.ListSnapshotDevices(context.Background(), &adminext.ListSnapshotDevicesRequest{SnapshotId: "snapshot-2"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_GetSnapshotValues(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	expectSnapshots(mgrTest)
+
+	stream := &snapshotValuesStream{ctx: adminCtx}
+	err := ExtServer{}.GetSnapshotValues(&adminext.GetSnapshotValuesRequest{SnapshotId: "snapshot-2", DeviceId: "device-1"}, stream)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(stream.values))
+	assert.Equal(t, "/system/config/hostname", stream.values[0].Path)
+	assert.Equal(t, "device-1", stream.values[0].Value)
+	assert.Equal(t, "/system/config/motd-banner", stream.values[1].Path)
+
+	// The delta of snapshot-3 applies to the values of snapshot-2
+	stream = &snapshotValuesStream{ctx: adminCtx}
+	err = ExtServer{}.GetSnapshotValues(&adminext.GetSnapshotValuesRequest{SnapshotId: "snapshot-3", DeviceId: "device-1"}, stream)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(stream.values))
+	assert.Equal(t, "/system/clock/config/timezone-name", stream.values[0].Path)
+	assert.Equal(t, "/system/config/hostname", stream.values[1].Path)
+
+	// The values of the earlier snapshot were archived when snapshot-2 replaced them
+	stream = &snapshotValuesStream{ctx: adminCtx}
+	err = ExtServer{}.GetSnapshotValues(&adminext.GetSnapshotValuesRequest{SnapshotId: "snapshot-1", DeviceId: "device-1"}, stream)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(stream.values))
+	assert.Equal(t, "/system/config/hostname", stream.values[0].Path)
+	assert.Equal(t, "device-0", stream.values[0].Value)
+
+	err = ExtServer{}.GetSnapshotValues(&adminext.GetSnapshotValuesRequest{SnapshotId: "snapshot-2", DeviceId: "device-2"}, stream)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	err = ExtServer{}.GetSnapshotValues(&adminext.GetSnapshotValuesRequest{SnapshotId: "snapshot-2"}, stream)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	stream = &snapshotValuesStream{ctx: context.Background()}
+	err = ExtServer{}.GetSnapshotValues(&adminext.GetSnapshotValuesRequest{SnapshotId: "snapshot-2", DeviceId: "device-1"}, stream)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
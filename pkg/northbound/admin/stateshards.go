@@ -0,0 +1,48 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+)
+
+// ListStateShards lists the shards of the operational state of this node
+func (s ExtServer) ListStateShards(ctx context.Context, req *adminext.ListStateShardsRequest) (*adminext.ListStateShardsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	mgr := manager.GetManager()
+	response := &adminext.ListStateShardsResponse{
+		Shards: make([]*adminext.StateShard, 0),
+		Budget: uint64(mgr.OperationalStateCache.Budget()),
+	}
+	for _, stats := range mgr.GetStateShards() {
+		response.Shards = append(response.Shards, &adminext.StateShard{
+			Shard:        uint32(stats.Shard),
+			Devices:      uint32(stats.Devices),
+			Paths:        uint64(stats.Paths),
+			Events:       stats.Events,
+			Queued:       uint32(stats.Queued),
+			Bytes:        uint64(stats.Bytes),
+			Evictions:    stats.Evictions,
+			EvictedPaths: stats.EvictedPaths,
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_ListStateShards(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mgrTest.SetStateShards(2)
+	mgrTest.OperationalStateCache.Put("device-1", devicechange.TypedValueMap{
+		"/a/b": devicechange.NewTypedValueString("b"),
+		"/a/c": devicechange.NewTypedValueString("c"),
+	})
+	mgrTest.OperationalStateCache.Put("device-2", devicechange.TypedValueMap{
+		"/a/b": devicechange.NewTypedValueString("b"),
+	})
+
+	response, err := ExtServer{}.ListStateShards(adminCtx, &adminext.ListStateShardsRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Shards), 2)
+	assert.Equal(t, response.Budget, uint64(0))
+	var devices, paths, bytes uint64
+	for i, shard := range response.Shards {
+		assert.Equal(t, shard.Shard, uint32(i))
+		devices += uint64(shard.Devices)
+		paths += shard.Paths
+		bytes += shard.Bytes
+	}
+	assert.Equal(t, devices, uint64(2))
+	assert.Equal(t, paths, uint64(3))
+
+	// Over budget, the subtrees never read are evicted
+	mgrTest.SetStateBudget(int64(bytes / 2))
+	assert.Equal(t, mgrTest.OperationalStateCache.EnforceBudget(), 2)
+	response, err = ExtServer{}.ListStateShards(adminCtx, &adminext.ListStateShardsRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Budget, bytes/2)
+	var evictions, evictedPaths uint64
+	for _, shard := range response.Shards {
+		evictions += shard.Evictions
+		evictedPaths += shard.EvictedPaths
+	}
+	assert.Equal(t, evictions, uint64(2))
+	assert.Equal(t, evictedPaths, uint64(3))
+
+	_, err = ExtServer{}.ListStateShards(context.Background(), &adminext.ListStateShardsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,103 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/southbound/synchronizer"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ListStateSubscriptions lists the subscriptions of this node to the operational state of the devices
+func (s ExtServer) ListStateSubscriptions(ctx context.Context, req *adminext.ListStateSubscriptionsRequest) (*adminext.ListStateSubscriptionsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	response := &adminext.ListStateSubscriptionsResponse{
+		Subscriptions: make([]*adminext.StateSubscription, 0),
+	}
+	for _, subscription := range synchronizer.ListSubscriptions() {
+		if req.DeviceId != "" && string(subscription.DeviceID) != req.DeviceId {
+			continue
+		}
+		response.Subscriptions = append(response.Subscriptions, &adminext.StateSubscription{
+			DeviceId: string(subscription.DeviceID),
+			Mode:     string(subscription.Mode),
+			Paths:    subscription.Paths,
+			Added:    subscription.Added,
+			Removed:  subscription.Removed,
+		})
+	}
+	return response, nil
+}
+
+// AddStateSubtree subscribes to a subtree of the state of a device
+func (s ExtServer) AddStateSubtree(ctx context.Context, req *adminext.AddStateSubtreeRequest) (*adminext.AddStateSubtreeResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := synchronizer.AddSubscribedSubtree(topodevice.ID(req.DeviceId), req.Path); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "add-state-subtree",
+		Target:  req.DeviceId,
+		Paths:   []string{req.Path},
+		Message: fmt.Sprintf("subscribed to %s", req.Path),
+	})
+	return &adminext.AddStateSubtreeResponse{}, nil
+}
+
+// RemoveStateSubtree stops subscribing to a subtree of the state of a device
+func (s ExtServer) RemoveStateSubtree(ctx context.Context, req *adminext.RemoveStateSubtreeRequest) (*adminext.RemoveStateSubtreeResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := synchronizer.RemoveSubscribedSubtree(topodevice.ID(req.DeviceId), req.Path); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "remove-state-subtree",
+		Target:  req.DeviceId,
+		Paths:   []string{req.Path},
+		Message: fmt.Sprintf("unsubscribed from %s", req.Path),
+	})
+	return &adminext.RemoveStateSubtreeResponse{}, nil
+}
+
+// ResetStateSubtrees undoes the changes to the subtrees subscribed to of a device
+func (s ExtServer) ResetStateSubtrees(ctx context.Context, req *adminext.ResetStateSubtreesRequest) (*adminext.ResetStateSubtreesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := synchronizer.ResetSubscribedSubtrees(topodevice.ID(req.DeviceId)); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "reset-state-subtrees",
+		Target:  req.DeviceId,
+		Message: "subscribed to the state paths of the device only",
+	})
+	return &adminext.ResetStateSubtreesResponse{}, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_StateSubtrees(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+
+	_, err := ExtServer{}.AddStateSubtree(adminCtx, &adminext.AddStateSubtreeRequest{DeviceId: "device-1", Path: "/components"})
+	assert.NilError(t, err)
+	_, err = ExtServer{}.RemoveStateSubtree(adminCtx, &adminext.RemoveStateSubtreeRequest{DeviceId: "device-1", Path: "/interfaces/interface[name=*]/state/counters"})
+	assert.NilError(t, err)
+
+	response, err := ExtServer{}.ListStateSubscriptions(adminCtx, &adminext.ListStateSubscriptionsRequest{DeviceId: "device-1"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Subscriptions), 1)
+	assert.DeepEqual(t, response.Subscriptions[0].Added, []string{"/components"})
+	assert.DeepEqual(t, response.Subscriptions[0].Removed, []string{"/interfaces/interface[name=*]/state/counters"})
+	assert.Equal(t, len(response.Subscriptions[0].Paths), 0, "device-1 is not connected")
+
+	_, err = ExtServer{}.ResetStateSubtrees(adminCtx, &adminext.ResetStateSubtreesRequest{DeviceId: "device-1"})
+	assert.NilError(t, err)
+	response, err = ExtServer{}.ListStateSubscriptions(adminCtx, &adminext.ListStateSubscriptionsRequest{DeviceId: "device-1"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Subscriptions), 0)
+
+	_, err = ExtServer{}.ResetStateSubtrees(adminCtx, &adminext.ResetStateSubtreesRequest{DeviceId: "device-1"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = ExtServer{}.AddStateSubtree(adminCtx, &adminext.AddStateSubtreeRequest{DeviceId: "device-1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = ExtServer{}.AddStateSubtree(context.Background(), &adminext.AddStateSubtreeRequest{DeviceId: "device-1", Path: "/components"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
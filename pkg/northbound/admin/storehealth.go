@@ -0,0 +1,59 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/health"
+)
+
+// GetStoreHealth returns the latencies and the failures of the operations of the Atomix primitives
+// of the stores of this node
+func (s ExtServer) GetStoreHealth(ctx context.Context, req *adminext.GetStoreHealthRequest) (*adminext.GetStoreHealthResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	stats := health.GetStats()
+	response := &adminext.GetStoreHealthResponse{
+		Primitives: make([]*adminext.PrimitiveHealth, 0, len(stats)),
+	}
+	for _, s := range stats {
+		primitive := &adminext.PrimitiveHealth{
+			Name:       s.Primitive,
+			Type:       string(s.Type),
+			Operations: s.Operations,
+			Errors:     s.Errors,
+			Latency:    types.DurationProto(s.Latency),
+			Healthy:    s.Healthy(),
+			LastError:  s.LastError,
+		}
+		if !s.LastErrorTime.IsZero() {
+			if t, err := types.TimestampProto(s.LastErrorTime); err == nil {
+				primitive.LastErrorTime = t
+			}
+		}
+		if !s.LastSuccessTime.IsZero() {
+			if t, err := types.TimestampProto(s.LastSuccessTime); err == nil {
+				primitive.LastSuccessTime = t
+			}
+		}
+		response.Primitives = append(response.Primitives, primitive)
+	}
+	return response, nil
+}
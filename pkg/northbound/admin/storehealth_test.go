@@ -0,0 +1,63 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/store/health"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_GetStoreHealth(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NilError(t, test.Start())
+	defer test.Stop()
+	atomixClient, err := test.NewClient("test")
+	assert.NilError(t, err)
+	m, err := health.WrapClient(atomixClient).GetMap(context.Background(), "test-admin-health")
+	assert.NilError(t, err)
+	_, err = m.Put(context.Background(), "foo", []byte("bar"))
+	assert.NilError(t, err)
+
+	response, err := ExtServer{}.GetStoreHealth(adminCtx, &adminext.GetStoreHealthRequest{})
+	assert.NilError(t, err)
+	var primitive *adminext.PrimitiveHealth
+	for _, p := range response.Primitives {
+		if p.Name == "test-admin-health" {
+			primitive = p
+		}
+	}
+	assert.Assert(t, primitive != nil)
+	assert.Equal(t, "Map", primitive.Type)
+	assert.Equal(t, uint64(2), primitive.Operations)
+	assert.Equal(t, uint64(0), primitive.Errors)
+	assert.Assert(t, primitive.Healthy)
+	assert.Assert(t, primitive.LastSuccessTime != nil)
+	assert.Assert(t, primitive.LastErrorTime == nil)
+
+	_, err = ExtServer{}.GetStoreHealth(context.Background(), &adminext.GetStoreHealthRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
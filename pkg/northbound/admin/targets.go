@@ -0,0 +1,169 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogo/protobuf/types"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/grpc/status"
+)
+
+// ListTargets lists the southbound targets of this node, sorted by device ID and version
+func (s ExtServer) ListTargets(ctx context.Context, req *adminext.ListTargetsRequest) (*adminext.ListTargetsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	response := &adminext.ListTargetsResponse{}
+	for _, info := range southbound.ListTargets() {
+		stale, err := isStaleTarget(info.ID)
+		if err != nil {
+			return nil, errors.Status(err).Err()
+		}
+		if req.StaleOnly && !stale {
+			continue
+		}
+		response.Targets = append(response.Targets, newSouthboundTarget(info, stale))
+	}
+	return response, nil
+}
+
+// DisconnectTarget closes the southbound connection of this node to a device, and has the session
+// to the device recreated if reconnect is requested
+func (s ExtServer) DisconnectTarget(ctx context.Context, req *adminext.DisconnectTargetRequest) (*adminext.DisconnectTargetResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.DeviceId == "" || req.DeviceVersion == "" {
+		return nil, errors.Status(errors.NewInvalid("no device ID and version given")).Err()
+	}
+	key := devicetype.NewVersionedID(devicetype.ID(req.DeviceId), devicetype.Version(req.DeviceVersion))
+	stale, err := isStaleTarget(key)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	if req.Reconnect && stale {
+		return nil, errors.Status(errors.NewInvalid("target %s is stale and cannot be reconnected", key)).Err()
+	}
+	info, err := southbound.DisconnectTarget(key)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	message := "disconnected"
+	if req.Reconnect {
+		if _, err := manager.GetManager().ReconnectDevice(devicetype.ID(req.DeviceId)); err != nil {
+			return nil, errors.Status(err).Err()
+		}
+		message = "disconnected to reconnect"
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "disconnect-target",
+		Target:  string(key),
+		Message: message,
+	})
+	return &adminext.DisconnectTargetResponse{
+		Target: newSouthboundTarget(info, stale),
+	}, nil
+}
+
+// PruneStaleTargets disconnects the southbound targets of this node whose device was removed from
+// topo, or bound to another version since
+func (s ExtServer) PruneStaleTargets(ctx context.Context, req *adminext.PruneStaleTargetsRequest) (*adminext.PruneStaleTargetsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	response := &adminext.PruneStaleTargetsResponse{}
+	for _, info := range southbound.ListTargets() {
+		stale, err := isStaleTarget(info.ID)
+		if err != nil {
+			return nil, errors.Status(err).Err()
+		}
+		if !stale {
+			continue
+		}
+		info, err = southbound.DisconnectTarget(info.ID)
+		if errors.IsNotFound(err) {
+			// Released by its session in the meantime
+			continue
+		} else if err != nil {
+			return nil, errors.Status(err).Err()
+		}
+		response.Targets = append(response.Targets, newSouthboundTarget(info, true))
+	}
+	if len(response.Targets) > 0 {
+		audit.Record(audit.Entry{
+			User:    callerName(ctx),
+			Action:  "prune-stale-targets",
+			Message: fmt.Sprintf("disconnected %d stale targets", len(response.Targets)),
+		})
+	}
+	return response, nil
+}
+
+// isStaleTarget returns true if the device of a target is no longer in topo, or is bound to
+// another version than the target's
+func isStaleTarget(key devicetype.VersionedID) (bool, error) {
+	device, err := manager.GetManager().DeviceStore.Get(topodevice.ID(key.GetID()))
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			err = errors.FromGRPC(err)
+		}
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return device == nil || devicetype.Version(device.Version) != key.GetVersion(), nil
+}
+
+func newSouthboundTarget(info southbound.TargetInfo, stale bool) *adminext.SouthboundTarget {
+	target := &adminext.SouthboundTarget{
+		DeviceId:      string(info.ID.GetID()),
+		DeviceVersion: string(info.ID.GetVersion()),
+		DeviceType:    string(info.DeviceType),
+		Address:       info.Address,
+		Multiplexed:   info.Multiplexed,
+		LastError:     info.LastError,
+		InFlight:      uint32(info.InFlight),
+		Queued:        uint32(info.Queued),
+	}
+	switch {
+	case stale:
+		target.State = adminext.TargetState_STALE
+	case info.Connected.IsZero():
+		target.State = adminext.TargetState_DISCONNECTED
+	case info.LastError != "":
+		target.State = adminext.TargetState_FAILING
+	default:
+		target.State = adminext.TargetState_CONNECTED
+	}
+	if !info.Connected.IsZero() {
+		target.Connected, _ = types.TimestampProto(info.Connected)
+	}
+	if !info.LastUsed.IsZero() {
+		target.LastUsed, _ = types.TimestampProto(info.LastUsed)
+	}
+	return target
+}
@@ -0,0 +1,164 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/openconfig/gnmi/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+// closingClient is a gNMI client that is only ever closed
+type closingClient struct {
+	southbound.GnmiClient
+}
+
+func (closingClient) Close() error {
+	return nil
+}
+
+// connectTargets connects targets to version 1.0.0 of device-1 and device-2, of which only
+// device-1 is in topo
+func connectTargets(t *testing.T) {
+	saved := southbound.GnmiClientFactory
+	southbound.GnmiClientFactory = func(ctx context.Context, d client.Destination) (southbound.GnmiClient, error) {
+		return closingClient{}, nil
+	}
+	t.Cleanup(func() {
+		southbound.GnmiClientFactory = saved
+		for _, info := range southbound.ListTargets() {
+			_, _ = southbound.DisconnectTarget(info.ID)
+		}
+	})
+	timeout := time.Second
+	for _, id := range []topodevice.ID{"device-1", "device-2"} {
+		_, err := southbound.NewTarget().ConnectTarget(context.Background(), topodevice.Device{
+			ID:      id,
+			Address: string(id) + ":11161",
+			Version: "1.0.0",
+			Type:    "Devicesim",
+			TLS:     topodevice.TLSConfig{Plain: true},
+			Timeout: &timeout,
+		})
+		assert.NilError(t, err)
+	}
+}
+
+func expectTopo(deviceStore *mockstore.MockDeviceStore) {
+	deviceStore.EXPECT().Get(topodevice.ID("device-1")).Return(&topodevice.Device{
+		ID:      "device-1",
+		Version: "1.0.0",
+		Type:    "Devicesim",
+	}, nil).AnyTimes()
+	deviceStore.EXPECT().Get(topodevice.ID("device-2")).
+		Return(nil, status.Error(codes.NotFound, "device-2 not found")).AnyTimes()
+}
+
+func Test_ListTargets(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	expectTopo(mgrTest.DeviceStore.(*mockstore.MockDeviceStore))
+	connectTargets(t)
+
+	response, err := ExtServer{}.ListTargets(adminCtx, &adminext.ListTargetsRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Targets), 2)
+	assert.Equal(t, response.Targets[0].DeviceId, "device-1")
+	assert.Equal(t, response.Targets[0].DeviceVersion, "1.0.0")
+	assert.Equal(t, response.Targets[0].DeviceType, "Devicesim")
+	assert.Equal(t, response.Targets[0].Address, "device-1:11161")
+	assert.Equal(t, response.Targets[0].State, adminext.TargetState_CONNECTED)
+	assert.Assert(t, response.Targets[0].Connected != nil)
+	assert.Assert(t, response.Targets[0].LastUsed == nil)
+	assert.Equal(t, response.Targets[1].DeviceId, "device-2")
+	assert.Equal(t, response.Targets[1].State, adminext.TargetState_STALE)
+
+	response, err = ExtServer{}.ListTargets(adminCtx, &adminext.ListTargetsRequest{StaleOnly: true})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Targets), 1)
+	assert.Equal(t, response.Targets[0].DeviceId, "device-2")
+
+	_, err = ExtServer{}.ListTargets(context.Background(), &adminext.ListTargetsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_DisconnectTarget(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	expectTopo(mgrTest.DeviceStore.(*mockstore.MockDeviceStore))
+	connectTargets(t)
+
+	_, err := ExtServer{}.DisconnectTarget(adminCtx, &adminext.DisconnectTargetRequest{DeviceId: "device-1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	// Stale targets are not reconnected, as their device is gone
+	_, err = ExtServer{}.DisconnectTarget(adminCtx, &adminext.DisconnectTargetRequest{
+		DeviceId:      "device-2",
+		DeviceVersion: "1.0.0",
+		Reconnect:     true,
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	response, err := ExtServer{}.DisconnectTarget(adminCtx, &adminext.DisconnectTargetRequest{
+		DeviceId:      "device-1",
+		DeviceVersion: "1.0.0",
+		Reconnect:     true,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Target.DeviceId, "device-1")
+	assert.Equal(t, response.Target.State, adminext.TargetState_CONNECTED)
+	event := <-mgrTest.TopoChannel
+	assert.Equal(t, event.Type, topodevice.ListResponseNONE)
+	assert.Equal(t, event.Device.ID, topodevice.ID("device-1"))
+	assert.Equal(t, len(southbound.ListTargets()), 1)
+
+	_, err = ExtServer{}.DisconnectTarget(adminCtx, &adminext.DisconnectTargetRequest{
+		DeviceId:      "device-1",
+		DeviceVersion: "1.0.0",
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = ExtServer{}.DisconnectTarget(context.Background(), &adminext.DisconnectTargetRequest{
+		DeviceId:      "device-2",
+		DeviceVersion: "1.0.0",
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_PruneStaleTargets(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	expectTopo(mgrTest.DeviceStore.(*mockstore.MockDeviceStore))
+	connectTargets(t)
+
+	response, err := ExtServer{}.PruneStaleTargets(adminCtx, &adminext.PruneStaleTargetsRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Targets), 1)
+	assert.Equal(t, response.Targets[0].DeviceId, "device-2")
+	assert.Equal(t, response.Targets[0].State, adminext.TargetState_STALE)
+
+	targets := southbound.ListTargets()
+	assert.Equal(t, len(targets), 1)
+	assert.Equal(t, string(targets[0].ID.GetID()), "device-1")
+
+	response, err = ExtServer{}.PruneStaleTargets(adminCtx, &adminext.PruneStaleTargetsRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Targets), 0)
+}
@@ -0,0 +1,106 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	transformstore "github.com/onosproject/onos-config/pkg/store/transform"
+	"github.com/onosproject/onos-config/pkg/transform"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ListTransformRules lists the rules transforming the values of gNMI Set requests
+func (s ExtServer) ListTransformRules(ctx context.Context, req *adminext.ListTransformRulesRequest) (*adminext.ListTransformRulesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	rules, err := manager.GetManager().TransformStore.List()
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	response := &adminext.ListTransformRulesResponse{
+		Rules: make([]*adminext.TransformRule, 0, len(rules)),
+		Hooks: transform.Hooks(),
+	}
+	for _, rule := range rules {
+		response.Rules = append(response.Rules, transformRule(rule))
+	}
+	return response, nil
+}
+
+// PutTransformRule creates a transformation rule, or replaces the rule of the same name
+func (s ExtServer) PutTransformRule(ctx context.Context, req *adminext.PutTransformRuleRequest) (*adminext.PutTransformRuleResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.Rule == nil {
+		return nil, errors.Status(errors.NewInvalid("no rule given")).Err()
+	}
+	rule := &transformstore.Rule{
+		Name:       req.Rule.Name,
+		Path:       req.Rule.Path,
+		DeviceType: devicetype.Type(req.Rule.DeviceType),
+		Hook:       req.Rule.Hook,
+		Args:       req.Rule.Args,
+	}
+	if err := transform.Validate(rule); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	if err := manager.GetManager().TransformStore.Put(rule); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "put-transform-rule",
+		Target:  rule.Name,
+		Paths:   []string{rule.Path},
+		Message: rule.Hook,
+	})
+	return &adminext.PutTransformRuleResponse{
+		Rule: transformRule(rule),
+	}, nil
+}
+
+// DeleteTransformRule deletes a transformation rule
+func (s ExtServer) DeleteTransformRule(ctx context.Context, req *adminext.DeleteTransformRuleRequest) (*adminext.DeleteTransformRuleResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := manager.GetManager().TransformStore.Delete(req.Name); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:   callerName(ctx),
+		Action: "delete-transform-rule",
+		Target: req.Name,
+	})
+	return &adminext.DeleteTransformRuleResponse{}, nil
+}
+
+func transformRule(rule *transformstore.Rule) *adminext.TransformRule {
+	return &adminext.TransformRule{
+		Name:       rule.Name,
+		Path:       rule.Path,
+		DeviceType: string(rule.DeviceType),
+		Hook:       rule.Hook,
+		Args:       rule.Args,
+	}
+}
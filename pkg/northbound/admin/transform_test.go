@@ -0,0 +1,66 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/transform"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_TransformRules(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+
+	rule := &adminext.TransformRule{
+		Name:       "mac",
+		Path:       "/interfaces/interface[name=*]/config/mac",
+		DeviceType: "Devicesim",
+		Hook:       transform.HookMACAddress,
+		Args:       map[string]string{"case": "upper"},
+	}
+	response, err := ExtServer{}.PutTransformRule(adminCtx, &adminext.PutTransformRuleRequest{Rule: rule})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, response.Rule, rule)
+
+	rules, err := ExtServer{}.ListTransformRules(adminCtx, &adminext.ListTransformRulesRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(rules.Rules), 1)
+	assert.Equal(t, rules.Rules[0].Name, "mac")
+	assert.DeepEqual(t, rules.Hooks, transform.Hooks())
+
+	_, err = ExtServer{}.DeleteTransformRule(adminCtx, &adminext.DeleteTransformRuleRequest{Name: "mac"})
+	assert.NilError(t, err)
+	_, err = ExtServer{}.DeleteTransformRule(adminCtx, &adminext.DeleteTransformRuleRequest{Name: "mac"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func Test_PutTransformRuleInvalid(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+
+	_, err := ExtServer{}.PutTransformRule(adminCtx, &adminext.PutTransformRuleRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.PutTransformRule(adminCtx, &adminext.PutTransformRuleRequest{Rule: &adminext.TransformRule{
+		Name: "rot13", Path: "/system/hostname", Hook: "rot13",
+	}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = ExtServer{}.ListTransformRules(context.Background(), &adminext.ListTransformRulesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,137 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/trust"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ListTrustBundles lists the trust bundles that device connections may refer to
+func (s ExtServer) ListTrustBundles(ctx context.Context, req *adminext.ListTrustBundlesRequest) (*adminext.ListTrustBundlesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	bundles, err := manager.GetManager().TrustStore.List()
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	response := &adminext.ListTrustBundlesResponse{
+		Bundles: make([]*adminext.TrustBundle, 0, len(bundles)),
+	}
+	for _, bundle := range bundles {
+		response.Bundles = append(response.Bundles, trustBundle(bundle))
+	}
+	return response, nil
+}
+
+// GetTrustBundle describes a trust bundle
+func (s ExtServer) GetTrustBundle(ctx context.Context, req *adminext.GetTrustBundleRequest) (*adminext.GetTrustBundleResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	bundle, err := manager.GetManager().TrustStore.Get(req.Name)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	return &adminext.GetTrustBundleResponse{
+		Bundle: trustBundle(bundle),
+	}, nil
+}
+
+// PutTrustBundle uploads a trust bundle, replacing any bundle of the same name
+func (s ExtServer) PutTrustBundle(ctx context.Context, req *adminext.PutTrustBundleRequest) (*adminext.PutTrustBundleResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	kind := trust.KindCA
+	if req.Kind == adminext.TrustBundleKind_CLIENT {
+		kind = trust.KindClient
+	}
+	bundle := &trust.Bundle{
+		Name:    req.Name,
+		Kind:    kind,
+		Certs:   req.Certs,
+		Key:     req.Key,
+		Created: time.Now(),
+	}
+	if err := manager.GetManager().TrustStore.Put(bundle); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "upload-trust-bundle",
+		Target:  req.Name,
+		Message: string(kind),
+	})
+	return &adminext.PutTrustBundleResponse{
+		Bundle: trustBundle(bundle),
+	}, nil
+}
+
+// DeleteTrustBundle deletes a trust bundle
+func (s ExtServer) DeleteTrustBundle(ctx context.Context, req *adminext.DeleteTrustBundleRequest) (*adminext.DeleteTrustBundleResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := manager.GetManager().TrustStore.Delete(req.Name); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:   callerName(ctx),
+		Action: "delete-trust-bundle",
+		Target: req.Name,
+	})
+	return &adminext.DeleteTrustBundleResponse{}, nil
+}
+
+// trustBundle describes a trust bundle, without its private key
+func trustBundle(bundle *trust.Bundle) *adminext.TrustBundle {
+	result := &adminext.TrustBundle{
+		Name:     bundle.Name,
+		Kind:     adminext.TrustBundleKind_CA,
+		Subjects: make([]string, 0),
+	}
+	if bundle.Kind == trust.KindClient {
+		result.Kind = adminext.TrustBundleKind_CLIENT
+	}
+	if created, err := types.TimestampProto(bundle.Created); err == nil {
+		result.Created = created
+	}
+	certificates, err := bundle.Certificates()
+	if err != nil {
+		return result
+	}
+	var notAfter time.Time
+	for _, certificate := range certificates {
+		result.Subjects = append(result.Subjects, certificate.Subject.String())
+		// The bundle is as good as its first certificate to expire
+		if notAfter.IsZero() || certificate.NotAfter.Before(notAfter) {
+			notAfter = certificate.NotAfter
+		}
+	}
+	if expires, err := types.TimestampProto(notAfter); err == nil {
+		result.NotAfter = expires
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/store/trust"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+// newTestCertificate generates a self signed certificate and its key, PEM encoded
+func newTestCertificate(t *testing.T, commonName string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NilError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NilError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
+
+func Test_TrustBundles(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	keys, err := trust.NewKeyCipher(make([]byte, 32))
+	assert.NilError(t, err)
+	mgrTest.SetTrustStore(trust.NewLocalStore(keys))
+	cert, key := newTestCertificate(t, "lab-ca")
+
+	put, err := ExtServer{}.PutTrustBundle(adminCtx, &adminext.PutTrustBundleRequest{
+		Name:  "lab-ca",
+		Kind:  adminext.TrustBundleKind_CA,
+		Certs: cert,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, "lab-ca", put.Bundle.Name)
+	assert.DeepEqual(t, []string{"CN=lab-ca"}, put.Bundle.Subjects)
+	assert.Assert(t, put.Bundle.NotAfter != nil)
+
+	_, err = ExtServer{}.PutTrustBundle(adminCtx, &adminext.PutTrustBundleRequest{
+		Name:  "lab-client",
+		Kind:  adminext.TrustBundleKind_CLIENT,
+		Certs: cert,
+		Key:   key,
+	})
+	assert.NilError(t, err)
+
+	_, err = ExtServer{}.PutTrustBundle(adminCtx, &adminext.PutTrustBundleRequest{
+		Name:  "broken",
+		Certs: "not a certificate",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	list, err := ExtServer{}.ListTrustBundles(adminCtx, &adminext.ListTrustBundlesRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(list.Bundles))
+	assert.Equal(t, "lab-ca", list.Bundles[0].Name)
+	assert.Equal(t, adminext.TrustBundleKind_CLIENT, list.Bundles[1].Kind)
+
+	get, err := ExtServer{}.GetTrustBundle(adminCtx, &adminext.GetTrustBundleRequest{Name: "lab-client"})
+	assert.NilError(t, err)
+	assert.Equal(t, "lab-client", get.Bundle.Name)
+
+	_, err = ExtServer{}.DeleteTrustBundle(adminCtx, &adminext.DeleteTrustBundleRequest{Name: "lab-ca"})
+	assert.NilError(t, err)
+	_, err = ExtServer{}.DeleteTrustBundle(adminCtx, &adminext.DeleteTrustBundleRequest{Name: "lab-ca"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = ExtServer{}.GetTrustBundle(adminCtx, &adminext.GetTrustBundleRequest{Name: "lab-ca"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func Test_TrustBundlesUnauthorized(t *testing.T) {
+	setUpExtServer(t)
+	_, err := ExtServer{}.ListTrustBundles(context.Background(), &adminext.ListTrustBundlesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
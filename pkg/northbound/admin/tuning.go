@@ -0,0 +1,168 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/controller"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/tuning"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ListControllerTuning lists the runtime tuning of the controllers
+func (s ExtServer) ListControllerTuning(ctx context.Context, req *adminext.ListControllerTuningRequest) (*adminext.ListControllerTuningResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	names := []string{req.Controller}
+	if req.Controller == "" {
+		names = names[:0]
+		for _, queue := range controller.Queues() {
+			names = append(names, queue.Name())
+		}
+	}
+	response := &adminext.ListControllerTuningResponse{
+		Controllers: make([]*adminext.ControllerTuning, 0, len(names)),
+	}
+	for _, name := range names {
+		t, err := manager.GetManager().GetControllerTuning(name)
+		if err != nil {
+			return nil, errors.Status(err).Err()
+		}
+		response.Controllers = append(response.Controllers, newControllerTuning(t))
+	}
+	return response, nil
+}
+
+// SetControllerTuning tunes a controller, replacing any previous tuning of it
+func (s ExtServer) SetControllerTuning(ctx context.Context, req *adminext.SetControllerTuningRequest) (*adminext.SetControllerTuningResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.Tuning == nil {
+		return nil, errors.Status(errors.NewInvalid("no tuning given")).Err()
+	}
+	t := &tuning.Tuning{
+		Controller:  req.Tuning.Controller,
+		Parallelism: int(req.Tuning.Parallelism),
+		User:        callerName(ctx),
+	}
+	var err error
+	if t.BatchWindow, err = fromDurationProto("batch window", req.Tuning.BatchWindow); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	if t.RetryStep, err = fromDurationProto("retry step", req.Tuning.RetryStep); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	if t.MaxRetryDelay, err = fromDurationProto("maximum retry delay", req.Tuning.MaxRetryDelay); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	if err := manager.GetManager().TuneController(t); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    t.User,
+		Action:  "tune-controller",
+		Target:  t.Controller,
+		Message: describeTuning(t),
+	})
+	return &adminext.SetControllerTuningResponse{
+		Tuning: newControllerTuning(t),
+	}, nil
+}
+
+// ResetControllerTuning restores the defaults of a controller
+func (s ExtServer) ResetControllerTuning(ctx context.Context, req *adminext.ResetControllerTuningRequest) (*adminext.ResetControllerTuningResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	t, err := manager.GetManager().ResetControllerTuning(req.Controller)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "reset-controller-tuning",
+		Target:  req.Controller,
+		Message: "restored defaults",
+	})
+	return &adminext.ResetControllerTuningResponse{
+		Tuning: newControllerTuning(t),
+	}, nil
+}
+
+func fromDurationProto(name string, duration *types.Duration) (time.Duration, error) {
+	if duration == nil {
+		return 0, nil
+	}
+	d, err := types.DurationFromProto(duration)
+	if err != nil {
+		return 0, errors.NewInvalid("invalid %s: %v", name, err)
+	}
+	return d, nil
+}
+
+// describeTuning lists the tuned parameters for the audit log
+func describeTuning(t *tuning.Tuning) string {
+	params := make([]string, 0, 4)
+	if t.BatchWindow > 0 {
+		params = append(params, fmt.Sprintf("batch window %s", t.BatchWindow))
+	}
+	if t.Parallelism > 0 {
+		params = append(params, fmt.Sprintf("parallelism %d", t.Parallelism))
+	}
+	if t.RetryStep > 0 {
+		params = append(params, fmt.Sprintf("retry step %s", t.RetryStep))
+	}
+	if t.MaxRetryDelay > 0 {
+		params = append(params, fmt.Sprintf("max retry delay %s", t.MaxRetryDelay))
+	}
+	if len(params) == 0 {
+		return "tuned to defaults"
+	}
+	return "tuned: " + strings.Join(params, ", ")
+}
+
+func newControllerTuning(t *tuning.Tuning) *adminext.ControllerTuning {
+	controllerTuning := &adminext.ControllerTuning{
+		Controller:  t.Controller,
+		Parallelism: uint32(t.Parallelism),
+		User:        t.User,
+	}
+	if t.BatchWindow > 0 {
+		controllerTuning.BatchWindow = types.DurationProto(t.BatchWindow)
+	}
+	if t.RetryStep > 0 {
+		controllerTuning.RetryStep = types.DurationProto(t.RetryStep)
+	}
+	if t.MaxRetryDelay > 0 {
+		controllerTuning.MaxRetryDelay = types.DurationProto(t.MaxRetryDelay)
+	}
+	if !t.Updated.IsZero() {
+		if updated, err := types.TimestampProto(t.Updated); err == nil {
+			controllerTuning.Updated = updated
+		}
+	}
+	return controllerTuning
+}
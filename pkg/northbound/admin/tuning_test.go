@@ -0,0 +1,85 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/controller"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_ControllerTuning(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+	defer controller.SetTuning("DeviceChange", controller.Tuning{})
+
+	response, err := ExtServer{}.SetControllerTuning(adminCtx, &adminext.SetControllerTuningRequest{
+		Tuning: &adminext.ControllerTuning{
+			Controller:  "DeviceChange",
+			BatchWindow: types.DurationProto(50 * time.Millisecond),
+			Parallelism: 4,
+		},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Tuning.User, "admin")
+	assert.Equal(t, response.Tuning.Parallelism, uint32(4))
+	assert.Assert(t, response.Tuning.Updated != nil)
+	assert.Equal(t, controller.GetTuning("DeviceChange"), controller.Tuning{BatchWindow: 50 * time.Millisecond, Parallelism: 4})
+	entries := audit.Entries()
+	assert.Equal(t, entries[len(entries)-1].Action, "tune-controller")
+	assert.Equal(t, entries[len(entries)-1].Message, "tuned: batch window 50ms, parallelism 4")
+
+	list, err := ExtServer{}.ListControllerTuning(adminCtx, &adminext.ListControllerTuningRequest{})
+	assert.NilError(t, err)
+	tuned := make(map[string]*adminext.ControllerTuning)
+	for _, controllerTuning := range list.Controllers {
+		tuned[controllerTuning.Controller] = controllerTuning
+	}
+	assert.Equal(t, tuned["DeviceChange"].Parallelism, uint32(4))
+	assert.Assert(t, tuned["NetworkChange"] != nil)
+	assert.Assert(t, tuned["NetworkChange"].BatchWindow == nil)
+
+	_, err = ExtServer{}.SetControllerTuning(adminCtx, &adminext.SetControllerTuningRequest{
+		Tuning: &adminext.ControllerTuning{Controller: "NoSuchController"},
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = ExtServer{}.SetControllerTuning(adminCtx, &adminext.SetControllerTuningRequest{
+		Tuning: &adminext.ControllerTuning{
+			Controller:    "DeviceChange",
+			RetryStep:     types.DurationProto(time.Second),
+			MaxRetryDelay: types.DurationProto(time.Millisecond),
+		},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = ExtServer{}.SetControllerTuning(adminCtx, &adminext.SetControllerTuningRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	reset, err := ExtServer{}.ResetControllerTuning(adminCtx, &adminext.ResetControllerTuningRequest{Controller: "DeviceChange"})
+	assert.NilError(t, err)
+	assert.Equal(t, reset.Tuning.Parallelism, uint32(4))
+	assert.Equal(t, controller.GetTuning("DeviceChange"), controller.Tuning{})
+	_, err = ExtServer{}.ResetControllerTuning(adminCtx, &adminext.ResetControllerTuningRequest{Controller: "DeviceChange"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = ExtServer{}.ListControllerTuning(context.Background(), &adminext.ListControllerTuningRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,162 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"encoding/base64"
+	"regexp"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	adminv2 "github.com/onosproject/onos-config/api/admin/v2"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// V2Server implements version 2 of the administrative gRPC service, on the changes, the snapshots
+// and the models. Every operation is restricted to the administrators.
+type V2Server struct {
+}
+
+const (
+	// defaultPageSize is the size of a page when the request does not give one
+	defaultPageSize = 100
+	// maxPageSize is the largest page returned
+	maxPageSize = 1000
+)
+
+// page returns the bounds of the page of a list sorted by key that follows the key of the page
+// token, and the token of the next page, empty if the page is the last one. The keys must sort
+// as the list does.
+func page(n int, key func(i int) string, pageSize int32, pageToken string) (int, int, string, error) {
+	size := int(pageSize)
+	if size < 0 {
+		return 0, 0, "", errors.NewInvalid("page size cannot be negative")
+	} else if size == 0 {
+		size = defaultPageSize
+	} else if size > maxPageSize {
+		size = maxPageSize
+	}
+	start := 0
+	if pageToken != "" {
+		after, err := base64.RawURLEncoding.DecodeString(pageToken)
+		if err != nil {
+			return 0, 0, "", errors.NewInvalid("invalid page token %q", pageToken)
+		}
+		for start < n && key(start) <= string(after) {
+			start++
+		}
+	}
+	end := start + size
+	if end >= n {
+		return start, n, "", nil
+	}
+	return start, end, base64.RawURLEncoding.EncodeToString([]byte(key(end - 1))), nil
+}
+
+// filterMatcher returns the matcher of the IDs of a filter, nil if there is no filter
+func filterMatcher(filter string) (*regexp.Regexp, error) {
+	if filter == "" {
+		return nil, nil
+	}
+	matcher, err := utils.CompileWildcardChNameRegexp(filter, true)
+	if err != nil {
+		return nil, errors.NewInvalid("invalid filter %q: %v", filter, err)
+	}
+	return matcher, nil
+}
+
+// eventTypes are the types of the events of the stores, as streamed by the Watch operations
+var eventTypes = map[stream.EventType]adminv2.EventType{
+	stream.None:    adminv2.EventType_CURRENT,
+	stream.Created: adminv2.EventType_CREATED,
+	stream.Updated: adminv2.EventType_UPDATED,
+	stream.Deleted: adminv2.EventType_DELETED,
+}
+
+func newTypedValue(value *devicechange.TypedValue) *adminv2.TypedValue {
+	if value == nil {
+		return nil
+	}
+	return &adminv2.TypedValue{
+		Bytes:    value.Bytes,
+		Type:     adminv2.ValueType(value.Type),
+		TypeOpts: value.TypeOpts,
+	}
+}
+
+func fromTypedValue(value *adminv2.TypedValue) *devicechange.TypedValue {
+	if value == nil {
+		return nil
+	}
+	return &devicechange.TypedValue{
+		Bytes:    value.Bytes,
+		Type:     devicechange.ValueType(value.Type),
+		TypeOpts: value.TypeOpts,
+	}
+}
+
+func newChangeStatus(status changetypes.Status) *adminv2.ChangeStatus {
+	return &adminv2.ChangeStatus{
+		Phase:       adminv2.ChangePhase(status.Phase),
+		State:       adminv2.ChangeState(status.State),
+		Error:       status.Reason == changetypes.Reason_ERROR,
+		Message:     status.Message,
+		Incarnation: status.Incarnation,
+	}
+}
+
+func newTimestamp(t time.Time) *types.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	timestamp, err := types.TimestampProto(t)
+	if err != nil {
+		return nil
+	}
+	return timestamp
+}
+
+var v1CallsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "onos_config_admin_v1_calls_total",
+	Help: "Number of the calls to the operations of version 1 of the admin API that version 2 replaces",
+}, []string{"method"})
+
+func init() {
+	prometheus.MustRegister(v1CallsCounter)
+}
+
+// deprecatedKey is the header telling the callers of version 1 of an operation its replacement
+const deprecatedKey = "onos-config-deprecated"
+
+// deprecated records a call to version 1 of an operation, and tells the caller its replacement in
+// version 2 in the headers of the response
+func deprecated(ctx context.Context, method string, replacement string) {
+	v1CallsCounter.WithLabelValues(method).Inc()
+	_ = grpc.SetHeader(ctx, metadata.Pairs(deprecatedKey, "use onos.config.admin.v2.ConfigAdminService/"+replacement))
+}
+
+// deprecatedStream is deprecated for a streaming operation
+func deprecatedStream(stream grpc.ServerStream, method string, replacement string) {
+	v1CallsCounter.WithLabelValues(method).Inc()
+	_ = stream.SetHeader(metadata.Pairs(deprecatedKey, "use onos.config.admin.v2.ConfigAdminService/"+replacement))
+}
@@ -0,0 +1,224 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onosproject/onos-api/go/onos/config/admin"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	adminv2 "github.com/onosproject/onos-config/api/admin/v2"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func TestPage(t *testing.T) {
+	keys := []string{"a", "b", "c", "d", "e"}
+	key := func(i int) string { return keys[i] }
+
+	start, end, next, err := page(len(keys), key, 2, "")
+	assert.NilError(t, err)
+	assert.Equal(t, start, 0)
+	assert.Equal(t, end, 2)
+	assert.Assert(t, next != "")
+
+	start, end, next, err = page(len(keys), key, 2, next)
+	assert.NilError(t, err)
+	assert.Equal(t, start, 2)
+	assert.Equal(t, end, 4)
+
+	start, end, next, err = page(len(keys), key, 2, next)
+	assert.NilError(t, err)
+	assert.Equal(t, start, 4)
+	assert.Equal(t, end, 5)
+	assert.Equal(t, next, "")
+
+	start, end, next, err = page(len(keys), key, 0, "")
+	assert.NilError(t, err)
+	assert.Equal(t, start, 0)
+	assert.Equal(t, end, 5)
+	assert.Equal(t, next, "")
+
+	_, _, _, err = page(len(keys), key, -1, "")
+	assert.ErrorContains(t, err, "negative")
+	_, _, _, err = page(len(keys), key, 2, "not a token!")
+	assert.ErrorContains(t, err, "invalid page token")
+}
+
+func expectListNetworkChanges(mgrTest *manager.Manager, changes ...*networkchange.NetworkChange) {
+	mockNwChStore := mgrTest.NetworkChangesStore.(*mockstore.MockNetworkChangesStore)
+	mockNwChStore.EXPECT().List(gomock.Any()).DoAndReturn(
+		func(ch chan<- *networkchange.NetworkChange) (stream.Context, error) {
+			go func() {
+				for _, change := range changes {
+					ch <- change
+				}
+				close(ch)
+			}()
+			return stream.NewContext(func() {}), nil
+		}).AnyTimes()
+}
+
+func Test_V2ListNetworkChanges(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	expectListNetworkChanges(mgrTest,
+		&networkchange.NetworkChange{ID: "change-3", Index: 3},
+		&networkchange.NetworkChange{ID: "change-1", Index: 1, Refs: []*networkchange.DeviceChangeRef{{DeviceChangeID: "change-1:device-1:1.0.0"}}},
+		&networkchange.NetworkChange{ID: "change-2", Index: 2},
+		&networkchange.NetworkChange{ID: "other", Index: 4})
+
+	response, err := V2Server{}.ListNetworkChanges(adminCtx, &adminv2.ListNetworkChangesRequest{
+		Filter:   "change-*",
+		PageSize: 2,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Changes), 2)
+	assert.Equal(t, response.Changes[0].Id, "change-1")
+	assert.DeepEqual(t, response.Changes[0].DeviceChangeIds, []string{"change-1:device-1:1.0.0"})
+	assert.Equal(t, response.Changes[1].Id, "change-2")
+	assert.Assert(t, response.NextPageToken != "")
+
+	response, err = V2Server{}.ListNetworkChanges(adminCtx, &adminv2.ListNetworkChangesRequest{
+		Filter:    "change-*",
+		PageSize:  2,
+		PageToken: response.NextPageToken,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Changes), 1)
+	assert.Equal(t, response.Changes[0].Id, "change-3")
+	assert.Equal(t, response.NextPageToken, "")
+}
+
+func Test_V2GetNetworkChange(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	mockNwChStore := mgrTest.NetworkChangesStore.(*mockstore.MockNetworkChangesStore)
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-1")).Return(&networkchange.NetworkChange{
+		ID:    "change-1",
+		Index: 1,
+		Changes: []*devicechange.Change{{
+			DeviceID:      "device-1",
+			DeviceVersion: "1.0.0",
+			Values: []*devicechange.ChangeValue{
+				{Path: "/a/b", Value: devicechange.NewTypedValueString("b")},
+				{Path: "/a/c", Removed: true},
+			},
+		}},
+	}, nil)
+	mockNwChStore.EXPECT().Get(networkchange.ID("change-2")).Return(nil, nil)
+
+	response, err := V2Server{}.GetNetworkChange(adminCtx, &adminv2.GetNetworkChangeRequest{Id: "change-1"})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Change.Index, uint64(1))
+	assert.Equal(t, len(response.Change.Devices), 1)
+	assert.Equal(t, response.Change.Devices[0].DeviceId, "device-1")
+	assert.Equal(t, string(response.Change.Devices[0].Values[0].Value.Bytes), "b")
+	assert.Equal(t, response.Change.Devices[0].Values[0].Value.Type, adminv2.ValueType_STRING)
+	assert.Assert(t, response.Change.Devices[0].Values[1].Removed)
+
+	_, err = V2Server{}.GetNetworkChange(adminCtx, &adminv2.GetNetworkChangeRequest{Id: "change-2"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func Test_V2Unauthorized(t *testing.T) {
+	setUpExtServer(t)
+	_, err := V2Server{}.ListNetworkChanges(context.Background(), &adminv2.ListNetworkChangesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	userCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		interceptors.NameKey, "user", interceptors.GroupsKey, "operators"))
+	_, err = V2Server{}.CompactChanges(userCtx, &adminv2.CompactChangesRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func Test_V2ListModelsNoRegistry(t *testing.T) {
+	_, adminCtx := setUpExtServer(t)
+	_, err := V2Server{}.ListModels(adminCtx, &adminv2.ListModelsRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func expectLoadSnapshots(mgrTest *manager.Manager, snapshots ...*devicesnapshot.Snapshot) {
+	mockDevSnapshotStore := mgrTest.DeviceSnapshotStore.(*mockstore.MockDeviceSnapshotStore)
+	mockDevSnapshotStore.EXPECT().LoadAll(gomock.Any()).DoAndReturn(
+		func(ch chan<- *devicesnapshot.Snapshot) (stream.Context, error) {
+			go func() {
+				for _, snapshot := range snapshots {
+					ch <- snapshot
+				}
+				close(ch)
+			}()
+			return stream.NewContext(func() {}), nil
+		})
+}
+
+func Test_V2ListSnapshots(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	expectLoadSnapshots(mgrTest, generateSnapshotData(3)...)
+
+	response, err := V2Server{}.ListSnapshots(adminCtx, &adminv2.ListSnapshotsRequest{Filter: "device-1:*"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Snapshots), 1)
+	assert.Equal(t, response.Snapshots[0].DeviceId, "device-1")
+	assert.Equal(t, response.Snapshots[0].ChangeIndex, uint64(1))
+	assert.Equal(t, len(response.Snapshots[0].Values), 2)
+}
+
+// snapshotsStream is the stream of version 1 of ListSnapshots
+type snapshotsStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	header    metadata.MD
+	snapshots []*devicesnapshot.Snapshot
+}
+
+func (s *snapshotsStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *snapshotsStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *snapshotsStream) Send(snapshot *devicesnapshot.Snapshot) error {
+	s.snapshots = append(s.snapshots, snapshot)
+	return nil
+}
+
+func Test_V1ListSnapshotsShim(t *testing.T) {
+	mgrTest, _ := setUpExtServer(t)
+	expectLoadSnapshots(mgrTest, generateSnapshotData(2)...)
+
+	// Version 1 matches the prefix of the IDs
+	stream := &snapshotsStream{ctx: context.Background()}
+	assert.NilError(t, Server{}.ListSnapshots(&admin.ListSnapshotsRequest{ID: "device-1"}, stream))
+	assert.Equal(t, len(stream.snapshots), 1)
+	snapshot := stream.snapshots[0]
+	assert.Equal(t, string(snapshot.ID), "device-1:1.0.0")
+	assert.Equal(t, string(snapshot.SnapshotID), "test-snapshot")
+	assert.Equal(t, len(snapshot.Values), 2)
+	assert.DeepEqual(t, snapshot.Values[0].Value, devicechange.NewTypedValueInt(1, 32))
+	assert.DeepEqual(t, stream.header.Get(deprecatedKey), []string{"use onos.config.admin.v2.ConfigAdminService/ListSnapshots"})
+}
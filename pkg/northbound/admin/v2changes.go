@@ -0,0 +1,287 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	adminv2 "github.com/onosproject/onos-config/api/admin/v2"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/secrets"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ListNetworkChanges lists a page of the network changes, by increasing index
+func (s V2Server) ListNetworkChanges(ctx context.Context, req *adminv2.ListNetworkChangesRequest) (*adminv2.ListNetworkChangesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	matcher, err := filterMatcher(req.Filter)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	ch := make(chan *networkchange.NetworkChange)
+	listCtx, err := manager.GetManager().NetworkChangesStore.List(ch)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	defer listCtx.Close()
+	changes := make([]*networkchange.NetworkChange, 0)
+	for change := range ch {
+		if matcher == nil || matcher.MatchString(string(change.ID)) {
+			changes = append(changes, change)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Index < changes[j].Index
+	})
+
+	start, end, next, err := page(len(changes), func(i int) string {
+		return indexKey(uint64(changes[i].Index))
+	}, req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	response := &adminv2.ListNetworkChangesResponse{
+		Changes:       make([]*adminv2.NetworkChange, 0, end-start),
+		NextPageToken: next,
+	}
+	for _, change := range changes[start:end] {
+		response.Changes = append(response.Changes, newNetworkChange(secrets.GetRegistry().RedactNetworkChange(ctx, change)))
+	}
+	return response, nil
+}
+
+// GetNetworkChange gets a network change
+func (s V2Server) GetNetworkChange(ctx context.Context, req *adminv2.GetNetworkChangeRequest) (*adminv2.GetNetworkChangeResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.Id == "" {
+		return nil, errors.Status(errors.NewInvalid("no network change given")).Err()
+	}
+	change, err := manager.GetManager().NetworkChangesStore.Get(networkchange.ID(req.Id))
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	} else if change == nil {
+		return nil, errors.Status(errors.NewNotFound("network change %s not found", req.Id)).Err()
+	}
+	return &adminv2.GetNetworkChangeResponse{
+		Change: newNetworkChange(secrets.GetRegistry().RedactNetworkChange(ctx, change)),
+	}, nil
+}
+
+// WatchNetworkChanges streams the network changes, then their events, sequenced to be resumed on
+// any node
+func (s V2Server) WatchNetworkChanges(req *adminv2.WatchNetworkChangesRequest, stream adminv2.ConfigAdminService_WatchNetworkChangesServer) error {
+	ctx := stream.Context()
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return err
+	}
+	matcher, err := filterMatcher(req.Filter)
+	if err != nil {
+		return errors.Status(err).Err()
+	}
+	ch := make(chan manager.ChangeEvent)
+	if err := manager.GetManager().WatchNetworkChanges(ctx, req.ResumeAfter, ch); err != nil {
+		return errors.Status(err).Err()
+	}
+	for event := range ch {
+		if matcher != nil && !matcher.MatchString(string(event.NetworkChange.ID)) {
+			continue
+		}
+		if err := stream.Send(&adminv2.WatchNetworkChangesResponse{
+			Sequence: event.Sequence,
+			Type:     eventTypes[event.Type],
+			Change:   newNetworkChange(secrets.GetRegistry().RedactNetworkChange(ctx, event.NetworkChange)),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RollbackNetworkChange rolls back the last network change
+func (s V2Server) RollbackNetworkChange(ctx context.Context, req *adminv2.RollbackNetworkChangeRequest) (*adminv2.RollbackNetworkChangeResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return rollbackNetworkChange(ctx, req)
+}
+
+// rollbackNetworkChange rolls back a network change, for version 2 of the service and the shim of
+// version 1, which authorize the caller each in their own way
+func rollbackNetworkChange(ctx context.Context, req *adminv2.RollbackNetworkChangeRequest) (*adminv2.RollbackNetworkChangeResponse, error) {
+	log.Infof("Rolling back change '%s' as requested by '%s'", req.Id, callerName(ctx))
+	if err := manager.GetManager().RollbackTargetConfig(networkchange.ID(req.Id)); err != nil {
+		return nil, rejectionError(err, networkchange.ID(req.Id))
+	}
+	message := "rolled back"
+	if req.Comment != "" {
+		message = fmt.Sprintf("rolled back: %s", req.Comment)
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "rollback-change",
+		Target:  req.Id,
+		Message: message,
+	})
+	return &adminv2.RollbackNetworkChangeResponse{Id: req.Id}, nil
+}
+
+// ListDeviceChanges lists a page of the changes of a device, by increasing index
+func (s V2Server) ListDeviceChanges(ctx context.Context, req *adminv2.ListDeviceChangesRequest) (*adminv2.ListDeviceChangesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	deviceID, err := versionedDeviceID(req.DeviceId, req.DeviceVersion)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	ch := make(chan *devicechange.DeviceChange)
+	listCtx, err := manager.GetManager().DeviceChangesStore.List(deviceID, ch)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	defer listCtx.Close()
+	changes := make([]*devicechange.DeviceChange, 0)
+	for change := range ch {
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Index < changes[j].Index
+	})
+
+	start, end, next, err := page(len(changes), func(i int) string {
+		return indexKey(uint64(changes[i].Index))
+	}, req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	response := &adminv2.ListDeviceChangesResponse{
+		Changes:       make([]*adminv2.DeviceChange, 0, end-start),
+		NextPageToken: next,
+	}
+	for _, change := range changes[start:end] {
+		response.Changes = append(response.Changes, newDeviceChange(secrets.GetRegistry().RedactDeviceChange(ctx, change)))
+	}
+	return response, nil
+}
+
+// WatchDeviceChanges streams the changes of a device, then their events, sequenced to be resumed
+// on any node
+func (s V2Server) WatchDeviceChanges(req *adminv2.WatchDeviceChangesRequest, stream adminv2.ConfigAdminService_WatchDeviceChangesServer) error {
+	ctx := stream.Context()
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return err
+	}
+	deviceID, err := versionedDeviceID(req.DeviceId, req.DeviceVersion)
+	if err != nil {
+		return errors.Status(err).Err()
+	}
+	ch := make(chan manager.ChangeEvent)
+	if err := manager.GetManager().WatchDeviceChanges(ctx, deviceID, req.ResumeAfter, ch); err != nil {
+		return errors.Status(err).Err()
+	}
+	for event := range ch {
+		if err := stream.Send(&adminv2.WatchDeviceChangesResponse{
+			Sequence: event.Sequence,
+			Type:     eventTypes[event.Type],
+			Change:   newDeviceChange(secrets.GetRegistry().RedactDeviceChange(ctx, event.DeviceChange)),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// versionedDeviceID returns the ID of a version of a device, which may be left out for a device
+// with a single version
+func versionedDeviceID(deviceID string, version string) (devicetype.VersionedID, error) {
+	if deviceID == "" {
+		return "", errors.NewInvalid("no device given")
+	}
+	_, deviceVersion, err := manager.GetManager().CheckCacheForDevice(devicetype.ID(deviceID), "", devicetype.Version(version))
+	if err != nil {
+		return "", err
+	}
+	return devicetype.NewVersionedID(devicetype.ID(deviceID), deviceVersion), nil
+}
+
+// indexKey is the key of an index in a page token, sorting as the indexes do
+func indexKey(index uint64) string {
+	return fmt.Sprintf("%020d", index)
+}
+
+func newDeviceValues(change *devicechange.Change) *adminv2.DeviceValues {
+	if change == nil {
+		return nil
+	}
+	device := &adminv2.DeviceValues{
+		DeviceId:      string(change.DeviceID),
+		DeviceVersion: string(change.DeviceVersion),
+		DeviceType:    string(change.DeviceType),
+		Values:        make([]*adminv2.PathValue, 0, len(change.Values)),
+	}
+	for _, value := range change.Values {
+		device.Values = append(device.Values, &adminv2.PathValue{
+			Path:    value.Path,
+			Value:   newTypedValue(value.Value),
+			Removed: value.Removed,
+		})
+	}
+	return device
+}
+
+func newNetworkChange(change *networkchange.NetworkChange) *adminv2.NetworkChange {
+	v2Change := &adminv2.NetworkChange{
+		Id:       string(change.ID),
+		Index:    uint64(change.Index),
+		Revision: uint64(change.Revision),
+		Status:   newChangeStatus(change.Status),
+		Created:  newTimestamp(change.Created),
+		Updated:  newTimestamp(change.Updated),
+		Devices:  make([]*adminv2.DeviceValues, 0, len(change.Changes)),
+		Deleted:  change.Deleted,
+	}
+	for _, deviceChange := range change.Changes {
+		v2Change.Devices = append(v2Change.Devices, newDeviceValues(deviceChange))
+	}
+	for _, ref := range change.Refs {
+		v2Change.DeviceChangeIds = append(v2Change.DeviceChangeIds, string(ref.DeviceChangeID))
+	}
+	return v2Change
+}
+
+func newDeviceChange(change *devicechange.DeviceChange) *adminv2.DeviceChange {
+	return &adminv2.DeviceChange{
+		Id:                 string(change.ID),
+		Index:              uint64(change.Index),
+		Revision:           uint64(change.Revision),
+		NetworkChangeId:    string(change.NetworkChange.ID),
+		NetworkChangeIndex: uint64(change.NetworkChange.Index),
+		Status:             newChangeStatus(change.Status),
+		Created:            newTimestamp(change.Created),
+		Updated:            newTimestamp(change.Updated),
+		Device:             newDeviceValues(change.Change),
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"sort"
+
+	"github.com/onosproject/onos-api/go/onos/config/admin"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	configmodel "github.com/onosproject/onos-config-model/pkg/model"
+	adminv2 "github.com/onosproject/onos-config/api/admin/v2"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/modelregistry"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// ListModels lists a page of the registered models, by name and version, optionally filtered by
+// name and version. The read only and read write paths of the models are only listed when verbose.
+func (s V2Server) ListModels(ctx context.Context, req *adminv2.ListModelsRequest) (*adminv2.ListModelsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	models, err := listModels(req.Name, req.Version, req.Verbose)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	start, end, next, err := page(len(models), func(i int) string {
+		return models[i].Name + "\x00" + models[i].Version
+	}, req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	return &adminv2.ListModelsResponse{
+		Models:        models[start:end],
+		NextPageToken: next,
+	}, nil
+}
+
+// GetModel gets a registered model. The version may be left out for a model with a single
+// version.
+func (s V2Server) GetModel(ctx context.Context, req *adminv2.GetModelRequest) (*adminv2.GetModelResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.Name == "" {
+		return nil, errors.Status(errors.NewInvalid("no model given")).Err()
+	}
+	models, err := listModels(req.Name, req.Version, req.Verbose)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	switch len(models) {
+	case 0:
+		return nil, errors.Status(errors.NewNotFound("model %s %s not found", req.Name, req.Version)).Err()
+	case 1:
+		return &adminv2.GetModelResponse{Model: models[0]}, nil
+	default:
+		return nil, errors.Status(errors.NewInvalid("model %s has %d versions, one must be given", req.Name, len(models))).Err()
+	}
+}
+
+// listModels lists the registered models matching a name and a version, by name and version
+func listModels(name string, version string, verbose bool) ([]*adminv2.Model, error) {
+	if manager.GetManager().ModelRegistry == nil {
+		return nil, errors.NewUnavailable("no model registry")
+	}
+	plugins, err := manager.GetManager().ModelRegistry.GetPlugins()
+	if err != nil {
+		return nil, err
+	}
+	models := make([]*adminv2.Model, 0, len(plugins))
+	for _, plugin := range plugins {
+		if name != "" && name != string(plugin.Info.Name) {
+			continue
+		}
+		if version != "" && version != string(plugin.Info.Version) {
+			continue
+		}
+		models = append(models, newModel(plugin, verbose))
+	}
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].Name != models[j].Name {
+			return models[i].Name < models[j].Name
+		}
+		return models[i].Version < models[j].Version
+	})
+	return models, nil
+}
+
+// getStateModes are the get state modes of the models, as numbered in version 2
+var getStateModes = map[configmodel.GetStateMode]adminv2.GetStateMode{
+	configmodel.GetStateNone:                           adminv2.GetStateMode_GET_STATE_NONE,
+	configmodel.GetStateOpState:                        adminv2.GetStateMode_GET_STATE_OP_STATE,
+	configmodel.GetStateExplicitRoPaths:                adminv2.GetStateMode_GET_STATE_EXPLICIT_RO_PATHS,
+	configmodel.GetStateExplicitRoPathsExpandWildcards: adminv2.GetStateMode_GET_STATE_EXPLICIT_RO_PATHS_EXPAND_WILDCARDS,
+}
+
+func newModel(plugin *modelregistry.ModelPlugin, verbose bool) *adminv2.Model {
+	model := &adminv2.Model{
+		Name:         string(plugin.Info.Name),
+		Version:      string(plugin.Info.Version),
+		Plugin:       string(plugin.Info.Plugin.Name),
+		GetStateMode: getStateModes[plugin.Info.GetStateMode],
+	}
+	for _, module := range plugin.Info.Modules {
+		model.Modules = append(model.Modules, &adminv2.Module{
+			Name:         string(module.Name),
+			Organization: module.Organization,
+			Revision:     string(module.Revision),
+		})
+	}
+	if !verbose {
+		return model
+	}
+	for path, subPaths := range plugin.ReadOnlyPaths {
+		roPath := &adminv2.ReadOnlyPath{Path: path}
+		for subPath, attrib := range subPaths {
+			roPath.SubPaths = append(roPath.SubPaths, &adminv2.ReadOnlySubPath{
+				SubPath:   subPath,
+				ValueType: adminv2.ValueType(attrib.ValueType),
+			})
+		}
+		sort.Slice(roPath.SubPaths, func(i, j int) bool {
+			return roPath.SubPaths[i].SubPath < roPath.SubPaths[j].SubPath
+		})
+		model.ReadOnlyPaths = append(model.ReadOnlyPaths, roPath)
+	}
+	sort.Slice(model.ReadOnlyPaths, func(i, j int) bool {
+		return model.ReadOnlyPaths[i].Path < model.ReadOnlyPaths[j].Path
+	})
+	for path, elem := range plugin.ReadWritePaths {
+		model.ReadWritePaths = append(model.ReadWritePaths, &adminv2.ReadWritePath{
+			Path:        path,
+			ValueType:   adminv2.ValueType(elem.ValueType),
+			Units:       elem.Units,
+			Description: elem.Description,
+			Mandatory:   elem.Mandatory,
+			Default:     elem.Default,
+			Range:       elem.Range,
+			Length:      elem.Length,
+		})
+	}
+	sort.Slice(model.ReadWritePaths, func(i, j int) bool {
+		return model.ReadWritePaths[i].Path < model.ReadWritePaths[j].Path
+	})
+	return model
+}
+
+// fromModel translates a model back to version 1
+func fromModel(model *adminv2.Model) *admin.ModelInfo {
+	info := &admin.ModelInfo{
+		Name:         model.Name,
+		Version:      model.Version,
+		Module:       model.Plugin,
+		GetStateMode: uint32(model.GetStateMode),
+	}
+	for _, module := range model.Modules {
+		info.ModelData = append(info.ModelData, &gnmi.ModelData{
+			Name:         module.Name,
+			Organization: module.Organization,
+			Version:      module.Revision,
+		})
+	}
+	for _, path := range model.ReadOnlyPaths {
+		roPath := &admin.ReadOnlyPath{Path: path.Path}
+		for _, subPath := range path.SubPaths {
+			roPath.SubPath = append(roPath.SubPath, &admin.ReadOnlySubPath{
+				SubPath:   subPath.SubPath,
+				ValueType: devicechange.ValueType(subPath.ValueType),
+			})
+		}
+		info.ReadOnlyPath = append(info.ReadOnlyPath, roPath)
+	}
+	for _, path := range model.ReadWritePaths {
+		info.ReadWritePath = append(info.ReadWritePath, &admin.ReadWritePath{
+			Path:        path.Path,
+			ValueType:   devicechange.ValueType(path.ValueType),
+			Units:       path.Units,
+			Description: path.Description,
+			Mandatory:   path.Mandatory,
+			Default:     path.Default,
+			Range:       path.Range,
+			Length:      path.Length,
+		})
+	}
+	return info
+}
@@ -0,0 +1,219 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	adminv2 "github.com/onosproject/onos-config/api/admin/v2"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/secrets"
+	streams "github.com/onosproject/onos-config/pkg/store/stream"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ListSnapshots lists a page of the snapshots of the devices, by device and version
+func (s V2Server) ListSnapshots(ctx context.Context, req *adminv2.ListSnapshotsRequest) (*adminv2.ListSnapshotsResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	snapshots, err := listSnapshots(ctx, req.Filter)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	start, end, next, err := page(len(snapshots), func(i int) string {
+		return snapshots[i].DeviceId + "\x00" + snapshots[i].DeviceVersion
+	}, req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	return &adminv2.ListSnapshotsResponse{
+		Snapshots:     snapshots[start:end],
+		NextPageToken: next,
+	}, nil
+}
+
+// listSnapshots lists the snapshots matching a filter, by device and version
+func listSnapshots(ctx context.Context, filter string) ([]*adminv2.Snapshot, error) {
+	matcher, err := filterMatcher(filter)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan *devicesnapshot.Snapshot)
+	loadCtx, err := manager.GetManager().DeviceSnapshotStore.LoadAll(ch)
+	if err != nil {
+		return nil, err
+	}
+	defer loadCtx.Close()
+	snapshots := make([]*adminv2.Snapshot, 0)
+	for snapshot := range ch {
+		if matcher == nil || matcher.MatchString(string(snapshot.ID)) {
+			snapshots = append(snapshots, newSnapshot(secrets.GetRegistry().RedactSnapshot(ctx, snapshot)))
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		if snapshots[i].DeviceId != snapshots[j].DeviceId {
+			return snapshots[i].DeviceId < snapshots[j].DeviceId
+		}
+		return snapshots[i].DeviceVersion < snapshots[j].DeviceVersion
+	})
+	return snapshots, nil
+}
+
+// GetSnapshot gets the snapshot of a device
+func (s V2Server) GetSnapshot(ctx context.Context, req *adminv2.GetSnapshotRequest) (*adminv2.GetSnapshotResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	deviceID, err := versionedDeviceID(req.DeviceId, req.DeviceVersion)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	snapshot, err := manager.GetManager().DeviceSnapshotStore.Load(deviceID)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	} else if snapshot == nil {
+		return nil, errors.Status(errors.NewNotFound("no snapshot of device %s", deviceID)).Err()
+	}
+	return &adminv2.GetSnapshotResponse{
+		Snapshot: newSnapshot(secrets.GetRegistry().RedactSnapshot(ctx, snapshot)),
+	}, nil
+}
+
+// WatchSnapshots streams the snapshots of the devices, then their events
+func (s V2Server) WatchSnapshots(req *adminv2.WatchSnapshotsRequest, stream adminv2.ConfigAdminService_WatchSnapshotsServer) error {
+	if err := interceptors.AuthorizeAdmin(stream.Context()); err != nil {
+		return err
+	}
+	err := watchSnapshots(stream.Context(), req.Filter, func(eventType adminv2.EventType, snapshot *adminv2.Snapshot) error {
+		return stream.Send(&adminv2.WatchSnapshotsResponse{
+			Type:     eventType,
+			Snapshot: snapshot,
+		})
+	})
+	if err != nil {
+		return errors.Status(err).Err()
+	}
+	return nil
+}
+
+// watchSnapshots sends the snapshots matching a filter, then their events, until the context is
+// done
+func watchSnapshots(ctx context.Context, filter string, send func(adminv2.EventType, *adminv2.Snapshot) error) error {
+	matcher, err := filterMatcher(filter)
+	if err != nil {
+		return err
+	}
+	ch := make(chan streams.Event)
+	watchCtx, err := manager.GetManager().DeviceSnapshotStore.WatchAll(ch)
+	if err != nil {
+		return err
+	}
+	defer watchCtx.Close()
+	for {
+		select {
+		case event, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			snapshot := event.Object.(*devicesnapshot.Snapshot)
+			if matcher != nil && !matcher.MatchString(string(snapshot.ID)) {
+				continue
+			}
+			if err := send(eventTypes[event.Type], newSnapshot(secrets.GetRegistry().RedactSnapshot(ctx, snapshot))); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// CompactChanges takes a snapshot of all the devices, compacting their changes older than the
+// retention period
+func (s V2Server) CompactChanges(ctx context.Context, req *adminv2.CompactChangesRequest) (*adminv2.CompactChangesResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return compactChanges(ctx, req)
+}
+
+// compactChanges compacts the changes, for version 2 of the service and the shim of version 1
+func compactChanges(ctx context.Context, req *adminv2.CompactChangesRequest) (*adminv2.CompactChangesResponse, error) {
+	var retentionPeriod *time.Duration
+	if req.RetentionPeriod != nil {
+		period, err := types.DurationFromProto(req.RetentionPeriod)
+		if err != nil {
+			return nil, errors.Status(errors.NewInvalid(err.Error())).Err()
+		}
+		retentionPeriod = &period
+	}
+	if _, err := manager.GetManager().CompactChanges("", retentionPeriod); err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	audit.Record(audit.Entry{
+		User:    callerName(ctx),
+		Action:  "compact-changes",
+		Message: "compacted",
+	})
+	return &adminv2.CompactChangesResponse{}, nil
+}
+
+func newSnapshot(snapshot *devicesnapshot.Snapshot) *adminv2.Snapshot {
+	v2Snapshot := &adminv2.Snapshot{
+		Id:            string(snapshot.ID),
+		DeviceId:      string(snapshot.DeviceID),
+		DeviceVersion: string(snapshot.DeviceVersion),
+		DeviceType:    string(snapshot.DeviceType),
+		SnapshotId:    string(snapshot.SnapshotID),
+		ChangeIndex:   uint64(snapshot.ChangeIndex),
+		Values:        make([]*adminv2.PathValue, 0, len(snapshot.Values)),
+	}
+	for _, value := range snapshot.Values {
+		v2Snapshot.Values = append(v2Snapshot.Values, &adminv2.PathValue{
+			Path:  value.Path,
+			Value: newTypedValue(value.Value),
+		})
+	}
+	return v2Snapshot
+}
+
+// fromSnapshot translates a snapshot back to version 1
+func fromSnapshot(snapshot *adminv2.Snapshot) *devicesnapshot.Snapshot {
+	v1Snapshot := &devicesnapshot.Snapshot{
+		ID:            devicesnapshot.ID(snapshot.Id),
+		DeviceID:      devicetype.ID(snapshot.DeviceId),
+		DeviceVersion: devicetype.Version(snapshot.DeviceVersion),
+		DeviceType:    devicetype.Type(snapshot.DeviceType),
+		SnapshotID:    devicesnapshot.ID(snapshot.SnapshotId),
+		ChangeIndex:   devicechange.Index(snapshot.ChangeIndex),
+		Values:        make([]*devicechange.PathValue, 0, len(snapshot.Values)),
+	}
+	for _, value := range snapshot.Values {
+		v1Snapshot.Values = append(v1Snapshot.Values, &devicechange.PathValue{
+			Path:  value.Path,
+			Value: fromTypedValue(value.Value),
+		})
+	}
+	return v1Snapshot
+}
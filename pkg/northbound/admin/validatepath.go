@@ -0,0 +1,57 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ValidatePath checks a path against the model of a device type
+func (s ExtServer) ValidatePath(ctx context.Context, req *adminext.ValidatePathRequest) (*adminext.ValidatePathResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	plugin, err := modelPlugin(req.DeviceType, req.DeviceVersion)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+	validated, err := plugin.ValidatePath(req.Path)
+	if err != nil {
+		return nil, errors.Status(err).Err()
+	}
+
+	gnmiPath := &adminext.GnmiPath{
+		Elem: make([]*adminext.GnmiPathElem, 0, len(validated.Path.Elem)),
+	}
+	for _, elem := range validated.Path.Elem {
+		gnmiPath.Elem = append(gnmiPath.Elem, &adminext.GnmiPathElem{Name: elem.Name, Key: elem.Key})
+	}
+	response := &adminext.ValidatePathResponse{
+		Path:      utils.StrPath(validated.Path),
+		GnmiPath:  gnmiPath,
+		ModelPath: validated.ModelPath,
+		Leaf:      validated.Leaf,
+		ReadOnly:  validated.ReadOnly,
+	}
+	if validated.Leaf {
+		response.Type = validated.ValueType.String()
+	}
+	return response, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_ValidatePath(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	setUpTestDeviceModel(t, mgrTest)
+
+	response, err := ExtServer{}.ValidatePath(adminCtx, &adminext.ValidatePathRequest{
+		DeviceType: "TestDevice", DeviceVersion: "1.0.0", Path: "/cont1a/list5[key2=2][key1=one]/leaf5a"})
+	assert.NilError(t, err)
+	assert.Equal(t, response.Path, "/cont1a/list5[key1=one][key2=2]/leaf5a")
+	assert.Equal(t, response.ModelPath, "/cont1a/list5[key1=*][key2=*]/leaf5a")
+	assert.Assert(t, response.Leaf && !response.ReadOnly)
+	assert.Equal(t, response.Type, "STRING")
+
+	// The path can be read as a gNMI Path
+	bytes, err := response.GnmiPath.Marshal()
+	assert.NilError(t, err)
+	gnmiPath := &gnmi.Path{}
+	assert.NilError(t, proto.Unmarshal(bytes, gnmiPath))
+	assert.Equal(t, len(gnmiPath.Elem), 3)
+	assert.Equal(t, gnmiPath.Elem[1].Key["key2"], "2")
+
+	_, err = ExtServer{}.ValidatePath(adminCtx, &adminext.ValidatePathRequest{
+		DeviceType: "TestDevice", DeviceVersion: "1.0.0", Path: "/cont1a/list5[key1=one]/leaf5a"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = ExtServer{}.ValidatePath(context.Background(), &adminext.ValidatePathRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,95 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// changeEventTypes are the types of the events of the change stores
+var changeEventTypes = map[stream.EventType]adminext.ChangeEventType{
+	stream.None:    adminext.ChangeEventType_CURRENT,
+	stream.Created: adminext.ChangeEventType_CREATED,
+	stream.Updated: adminext.ChangeEventType_UPDATED,
+	stream.Deleted: adminext.ChangeEventType_DELETED,
+}
+
+// WatchNetworkChanges streams the network changes, sequenced to be resumed on any node
+func (s ExtServer) WatchNetworkChanges(req *adminext.WatchNetworkChangesRequest, stream adminext.ConfigAdminExtService_WatchNetworkChangesServer) error {
+	ctx := stream.Context()
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return err
+	}
+	ch := make(chan manager.ChangeEvent)
+	if err := manager.GetManager().WatchNetworkChanges(ctx, req.ResumeAfter, ch); err != nil {
+		return errors.Status(err).Err()
+	}
+	for event := range ch {
+		change, err := event.NetworkChange.Marshal()
+		if err != nil {
+			return errors.Status(errors.NewInternal("failed to encode network change %s: %v", event.NetworkChange.ID, err)).Err()
+		}
+		if err := stream.Send(&adminext.NetworkChangeEvent{
+			Sequence: event.Sequence,
+			Type:     changeEventTypes[event.Type],
+			ChangeId: string(event.NetworkChange.ID),
+			Change:   change,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WatchDeviceChanges streams the changes of a device, sequenced to be resumed on any node
+func (s ExtServer) WatchDeviceChanges(req *adminext.WatchDeviceChangesRequest, stream adminext.ConfigAdminExtService_WatchDeviceChangesServer) error {
+	ctx := stream.Context()
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return err
+	}
+	if req.DeviceId == "" {
+		return errors.Status(errors.NewInvalid("no device given")).Err()
+	}
+	mgr := manager.GetManager()
+	_, version, err := mgr.CheckCacheForDevice(devicetype.ID(req.DeviceId), "", devicetype.Version(req.DeviceVersion))
+	if err != nil {
+		return errors.Status(err).Err()
+	}
+	ch := make(chan manager.ChangeEvent)
+	deviceID := devicetype.NewVersionedID(devicetype.ID(req.DeviceId), version)
+	if err := mgr.WatchDeviceChanges(ctx, deviceID, req.ResumeAfter, ch); err != nil {
+		return errors.Status(err).Err()
+	}
+	for event := range ch {
+		change, err := event.DeviceChange.Marshal()
+		if err != nil {
+			return errors.Status(errors.NewInternal("failed to encode device change %s: %v", event.DeviceChange.ID, err)).Err()
+		}
+		if err := stream.Send(&adminext.DeviceChangeEvent{
+			Sequence: event.Sequence,
+			Type:     changeEventTypes[event.Type],
+			ChangeId: string(event.DeviceChange.ID),
+			Change:   change,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
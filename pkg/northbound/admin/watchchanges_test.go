@@ -0,0 +1,173 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	devicechanges "github.com/onosproject/onos-config/pkg/store/change/device"
+	networkchanges "github.com/onosproject/onos-config/pkg/store/change/network"
+	devicecache "github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+// networkChangesStream collects the events sent by WatchNetworkChanges
+type networkChangesStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events []*adminext.NetworkChangeEvent
+}
+
+func (s *networkChangesStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *networkChangesStream) Send(event *adminext.NetworkChangeEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+// deviceChangesStream collects the events sent by WatchDeviceChanges
+type deviceChangesStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events []*adminext.DeviceChangeEvent
+}
+
+func (s *deviceChangesStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *deviceChangesStream) Send(event *adminext.DeviceChangeEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+// expectNetworkChanges sets up the store to list change-3 and change-1, then to see change-1
+// again, change-2 created and change-1 deleted
+func expectNetworkChanges(mgrTest *manager.Manager) {
+	store := mgrTest.NetworkChangesStore.(*mockstore.MockNetworkChangesStore)
+	change := func(id string, revision networkchange.Revision) *networkchange.NetworkChange {
+		return &networkchange.NetworkChange{ID: networkchange.ID(id), Revision: revision}
+	}
+	store.EXPECT().List(gomock.Any()).DoAndReturn(func(ch chan<- *networkchange.NetworkChange) (stream.Context, error) {
+		go func() {
+			ch <- change("change-1", 5)
+			ch <- change("change-3", 3)
+			close(ch)
+		}()
+		return stream.NewContext(func() {}), nil
+	})
+	store.EXPECT().Watch(gomock.Any()).DoAndReturn(func(ch chan<- stream.Event, opts ...networkchanges.WatchOption) (stream.Context, error) {
+		go func() {
+			ch <- stream.Event{Type: stream.Updated, Object: change("change-1", 5)}
+			ch <- stream.Event{Type: stream.Created, Object: change("change-2", 7)}
+			ch <- stream.Event{Type: stream.Deleted, Object: change("change-1", 5)}
+			close(ch)
+		}()
+		return stream.NewContext(func() {}), nil
+	})
+}
+
+func Test_WatchNetworkChanges(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	expectNetworkChanges(mgrTest)
+	watch := &networkChangesStream{ctx: adminCtx}
+	assert.NilError(t, ExtServer{}.WatchNetworkChanges(&adminext.WatchNetworkChangesRequest{}, watch))
+	assert.Equal(t, len(watch.events), 4)
+	for i, expected := range []struct {
+		sequence  uint64
+		eventType adminext.ChangeEventType
+		id        string
+	}{
+		{3, adminext.ChangeEventType_CURRENT, "change-3"},
+		{5, adminext.ChangeEventType_CURRENT, "change-1"},
+		{7, adminext.ChangeEventType_CREATED, "change-2"},
+		{5, adminext.ChangeEventType_DELETED, "change-1"},
+	} {
+		assert.Equal(t, watch.events[i].Sequence, expected.sequence)
+		assert.Equal(t, watch.events[i].Type, expected.eventType)
+		assert.Equal(t, watch.events[i].ChangeId, expected.id)
+	}
+	change := &networkchange.NetworkChange{}
+	assert.NilError(t, change.Unmarshal(watch.events[2].Change))
+	assert.Equal(t, change.ID, networkchange.ID("change-2"))
+
+	// A consumer resuming after change-3 gets the later versions only
+	expectNetworkChanges(mgrTest)
+	watch = &networkChangesStream{ctx: adminCtx}
+	assert.NilError(t, ExtServer{}.WatchNetworkChanges(&adminext.WatchNetworkChangesRequest{ResumeAfter: 5}, watch))
+	assert.Equal(t, len(watch.events), 2)
+	assert.Equal(t, watch.events[0].ChangeId, "change-2")
+	assert.Equal(t, watch.events[1].Type, adminext.ChangeEventType_DELETED)
+
+	err := ExtServer{}.WatchNetworkChanges(&adminext.WatchNetworkChangesRequest{}, &networkChangesStream{ctx: context.Background()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_WatchDeviceChanges(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	deviceCache := mgrTest.DeviceCache.(*cache.MockCache)
+	deviceCache.EXPECT().GetDevicesByID(devicetype.ID("device-1")).Return([]*devicecache.Info{
+		{DeviceID: "device-1", Type: "Devicesim", Version: "1.0.0"},
+	})
+	deviceCache.EXPECT().GetDevicesByID(devicetype.ID("device-2")).Return(nil)
+	deviceStore := mgrTest.DeviceStore.(*mockstore.MockDeviceStore)
+	deviceStore.EXPECT().Get(gomock.Any()).Return(nil, errors.NewNotFound("not found")).Times(2)
+	deviceChanges := mgrTest.DeviceChangesStore.(*mockstore.MockDeviceChangesStore)
+	deviceID := devicetype.NewVersionedID("device-1", "1.0.0")
+	deviceChanges.EXPECT().List(deviceID, gomock.Any()).DoAndReturn(func(id devicetype.VersionedID, ch chan<- *devicechange.DeviceChange) (stream.Context, error) {
+		go func() {
+			ch <- &devicechange.DeviceChange{ID: "change-1:device-1:1.0.0", Revision: 2}
+			close(ch)
+		}()
+		return stream.NewContext(func() {}), nil
+	})
+	deviceChanges.EXPECT().Watch(deviceID, gomock.Any()).DoAndReturn(func(id devicetype.VersionedID, ch chan<- stream.Event, opts ...devicechanges.WatchOption) (stream.Context, error) {
+		go func() {
+			ch <- stream.Event{Type: stream.Updated, Object: &devicechange.DeviceChange{ID: "change-1:device-1:1.0.0", Revision: 4}}
+			close(ch)
+		}()
+		return stream.NewContext(func() {}), nil
+	})
+
+	watch := &deviceChangesStream{ctx: adminCtx}
+	assert.NilError(t, ExtServer{}.WatchDeviceChanges(&adminext.WatchDeviceChangesRequest{DeviceId: "device-1"}, watch))
+	assert.Equal(t, len(watch.events), 2)
+	assert.Equal(t, watch.events[0].Type, adminext.ChangeEventType_CURRENT)
+	assert.Equal(t, watch.events[1].Sequence, uint64(4))
+	assert.Equal(t, watch.events[1].Type, adminext.ChangeEventType_UPDATED)
+
+	err := ExtServer{}.WatchDeviceChanges(&adminext.WatchDeviceChangesRequest{DeviceId: "device-2"}, &deviceChangesStream{ctx: adminCtx})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	err = ExtServer{}.WatchDeviceChanges(&adminext.WatchDeviceChangesRequest{}, &deviceChangesStream{ctx: adminCtx})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	err = ExtServer{}.WatchDeviceChanges(&adminext.WatchDeviceChangesRequest{DeviceId: "device-1"}, &deviceChangesStream{ctx: context.Background()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
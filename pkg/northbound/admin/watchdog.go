@@ -0,0 +1,62 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+)
+
+// GetChangeWatchdog returns the policy and the decisions of the watchdog of stuck network changes
+func (s ExtServer) GetChangeWatchdog(ctx context.Context, req *adminext.GetChangeWatchdogRequest) (*adminext.GetChangeWatchdogResponse, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	w := manager.GetManager().Watchdog
+	if w == nil {
+		return &adminext.GetChangeWatchdogResponse{}, nil
+	}
+	policy := w.Policy()
+	stats := w.Stats()
+	response := &adminext.GetChangeWatchdogResponse{
+		Enabled:    true,
+		StuckAfter: types.DurationProto(policy.StuckAfter),
+		Action:     string(policy.Action),
+		Stuck:      stats.Stuck,
+		Retried:    stats.Retried,
+		Cancelled:  stats.Cancelled,
+		Failed:     stats.Failed,
+	}
+	for _, decision := range w.Decisions() {
+		watchdogDecision := &adminext.WatchdogDecision{
+			NetworkChange: string(decision.NetworkChange),
+			Stuck:         types.DurationProto(decision.Stuck),
+			Action:        string(decision.Action),
+			Error:         decision.Error,
+		}
+		if timestamp, err := types.TimestampProto(decision.Time); err == nil {
+			watchdogDecision.Time = timestamp
+		}
+		for _, id := range decision.DeviceChanges {
+			watchdogDecision.DeviceChanges = append(watchdogDecision.DeviceChanges, string(id))
+		}
+		response.Decisions = append(response.Decisions, watchdogDecision)
+	}
+	return response, nil
+}
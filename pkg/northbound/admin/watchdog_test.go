@@ -0,0 +1,46 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/controller/change/watchdog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func Test_GetChangeWatchdog(t *testing.T) {
+	mgrTest, adminCtx := setUpExtServer(t)
+	response, err := ExtServer{}.GetChangeWatchdog(adminCtx, &adminext.GetChangeWatchdogRequest{})
+	assert.NilError(t, err)
+	assert.Assert(t, !response.Enabled)
+
+	assert.NilError(t, mgrTest.SetWatchdog(watchdog.Policy{StuckAfter: 10 * time.Minute, Action: watchdog.ActionRetry}))
+	defer func() { mgrTest.Watchdog = nil }()
+	response, err = ExtServer{}.GetChangeWatchdog(adminCtx, &adminext.GetChangeWatchdogRequest{})
+	assert.NilError(t, err)
+	assert.Assert(t, response.Enabled)
+	assert.Equal(t, response.StuckAfter.Seconds, int64(600))
+	assert.Equal(t, response.Action, "retry")
+	assert.Equal(t, len(response.Decisions), 0)
+
+	_, err = ExtServer{}.GetChangeWatchdog(context.Background(), &adminext.GetChangeWatchdogRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
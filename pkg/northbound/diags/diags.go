@@ -26,10 +26,12 @@ import (
 	"github.com/onosproject/onos-api/go/onos/config/diags"
 	topodevice "github.com/onosproject/onos-config/pkg/device"
 	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/secrets"
 	"github.com/onosproject/onos-config/pkg/store/change/device"
 	"github.com/onosproject/onos-config/pkg/store/change/network"
 	streams "github.com/onosproject/onos-config/pkg/store/stream"
 	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-lib-go/pkg/northbound"
 	"google.golang.org/grpc"
@@ -61,17 +63,15 @@ func (s Server) GetOpState(r *diags.OpStateRequest, stream diags.OpStateDiags_Ge
 		}
 	}
 
-	manager.GetManager().OperationalStateCacheLock.RLock()
-	deviceCache, ok := manager.GetManager().OperationalStateCache[topodevice.ID(r.DeviceId)]
-	manager.GetManager().OperationalStateCacheLock.RUnlock()
+	deviceCache, ok := manager.GetManager().OperationalStateCache.Get(topodevice.ID(r.DeviceId))
 	if !ok {
-		return fmt.Errorf("no Operational State cache available for %s", r.DeviceId)
+		return errors.NewNotFound("no Operational State cache available for %s", r.DeviceId)
 	}
 
 	for path, value := range deviceCache {
 		pathValue := &devicechange.PathValue{
 			Path:  path,
-			Value: value,
+			Value: secrets.GetRegistry().RedactValueFor(stream.Context(), r.DeviceId, path, value),
 		}
 
 		msg := &diags.OpStateResponse{Type: admin.Type_NONE, Pathvalue: pathValue}
@@ -102,7 +102,7 @@ func (s Server) GetOpState(r *diags.OpStateRequest, stream diags.OpStateDiags_Ge
 
 				pathValue := &devicechange.PathValue{
 					Path:  opStateEvent.Path(),
-					Value: opStateEvent.Value(),
+					Value: secrets.GetRegistry().RedactValueFor(stream.Context(), r.DeviceId, opStateEvent.Path(), opStateEvent.Value()),
 				}
 
 				msg := &diags.OpStateResponse{Type: admin.Type_ADDED, Pathvalue: pathValue}
@@ -166,7 +166,7 @@ func (s Server) ListNetworkChanges(r *diags.ListNetworkChangeRequest, stream dia
 
 				if matcher.MatchString(string(change.ID)) {
 					msg := &diags.ListNetworkChangeResponse{
-						Change: change,
+						Change: secrets.GetRegistry().RedactNetworkChange(stream.Context(), change),
 						Type:   streamTypeToResponseType(event.Type),
 					}
 					log.Infof("Sending matching change %v", change.ID)
@@ -204,7 +204,7 @@ func (s Server) ListNetworkChanges(r *diags.ListNetworkChangeRequest, stream dia
 
 				if matcher.MatchString(string(change.ID)) {
 					msg := &diags.ListNetworkChangeResponse{
-						Change: change,
+						Change: secrets.GetRegistry().RedactNetworkChange(stream.Context(), change),
 						Type:   diags.Type_NONE,
 					}
 					log.Infof("Sending matching change %v", change.ID)
@@ -269,7 +269,7 @@ func (s Server) ListDeviceChanges(r *diags.ListDeviceChangeRequest, stream diags
 
 				change := event.Object.(*devicechange.DeviceChange)
 				msg := &diags.ListDeviceChangeResponse{
-					Change: change,
+					Change: secrets.GetRegistry().RedactDeviceChange(stream.Context(), change),
 					Type:   streamTypeToResponseType(event.Type),
 				}
 				log.Infof("Sending matching change %v", change.ID)
@@ -305,7 +305,7 @@ func (s Server) ListDeviceChanges(r *diags.ListDeviceChangeRequest, stream diags
 				}
 
 				msg := &diags.ListDeviceChangeResponse{
-					Change: change,
+					Change: secrets.GetRegistry().RedactDeviceChange(stream.Context(), change),
 					Type:   diags.Type_NONE,
 				}
 				log.Infof("Sending matching change %v", change.ID)
@@ -0,0 +1,335 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gateway implements an optional REST/JSON gateway to the administrative services.
+//
+// The changes, snapshots and models of the version 2 admin and of the admin extension gRPC
+// services are mapped to HTTP resources under Prefix by the grpc-gateway handlers generated from
+// the google.api.http annotations of their protos, so that web dashboards and curl-based
+// automation can use them without protobuf tooling. An OpenAPI description of the resources,
+// generated from the same annotations, is served on SpecPath.
+//
+// The handlers call the services through an in-process gRPC server with the server options of the
+// northbound server, so that every call goes through the same interceptor chain, request recorder
+// and read-only guard as the RPC it maps to. Calls are restricted to administrators.
+package gateway
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	adminv2 "github.com/onosproject/onos-config/api/admin/v2"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/northbound"
+	adminservice "github.com/onosproject/onos-config/pkg/northbound/admin"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var log = logging.GetLogger("northbound", "gateway")
+
+// Prefix is the HTTP path under which the resources are served
+const Prefix = "/admin/v1/"
+
+// SpecPath is the HTTP path of the OpenAPI description of the resources, served without
+// authentication
+const SpecPath = Prefix + "openapi.json"
+
+// maxBodyBytes is the largest body of a request
+const maxBodyBytes = 1 << 20
+
+// peerMetadataKey is the metadata with which the handlers pass the key of the TLS state of an HTTP
+// call to the in-process server. The callers cannot set it: only the credential headers are
+// forwarded as metadata.
+const peerMetadataKey = "onos-gateway-peer"
+
+// Error is the body of the responses of failed calls, with the gRPC status code of the failure
+type Error struct {
+	Code    codes.Code `json:"code"`
+	Message string     `json:"message"`
+}
+
+// Server serves the REST/JSON gateway over HTTP
+type Server struct {
+	port     int
+	caPath   string
+	certPath string
+	keyPath  string
+	chain    *interceptors.Chain
+	opts     []grpc.ServerOption
+	// peers are the TLS states of the HTTP calls being handled, by the key passed to the
+	// in-process server
+	peers sync.Map
+}
+
+// NewServer creates a new gateway listening with TLS on the given port, with the same
+// certificates as the gRPC services. The calls are served with the given options of the
+// northbound server, which must install the chain.
+func NewServer(port int, caPath string, certPath string, keyPath string, chain *interceptors.Chain, opts ...grpc.ServerOption) *Server {
+	return &Server{
+		port:     port,
+		caPath:   caPath,
+		certPath: certPath,
+		keyPath:  keyPath,
+		chain:    chain,
+		opts:     opts,
+	}
+}
+
+// Serve starts serving calls; it blocks until the server fails. It fails at once if the chain
+// cannot authenticate callers.
+func (s *Server) Serve() error {
+	if s.chain == nil || !s.chain.RequiresIdentity() {
+		return errors.NewInvalid("the REST gateway requires northbound authentication")
+	}
+	tlsCfg, err := northbound.TLSConfig(s.caPath, s.keyPath, s.certPath)
+	if err != nil {
+		return err
+	}
+	handler, stop, err := s.Handler(context.Background())
+	if err != nil {
+		return err
+	}
+	defer stop()
+	server := &http.Server{
+		Addr:      fmt.Sprintf(":%d", s.port),
+		Handler:   handler,
+		TLSConfig: tlsCfg,
+	}
+	log.Infof("Starting REST gateway on %s%s", server.Addr, Prefix)
+	return server.ListenAndServeTLS("", "")
+}
+
+// Handler returns the HTTP handler of the gateway, serving the calls through an in-process gRPC
+// server until the returned function is called
+func (s *Server) Handler(ctx context.Context) (http.Handler, func(), error) {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.restorePeerUnary),
+		grpc.ChainStreamInterceptor(s.restorePeerStream),
+	}
+	opts = append(opts, s.opts...)
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(authorizeAdminUnary),
+		grpc.ChainStreamInterceptor(authorizeAdminStream))
+	grpcServer := grpc.NewServer(opts...)
+	adminv2.RegisterConfigAdminServiceServer(grpcServer, adminservice.V2Server{})
+	adminext.RegisterConfigAdminExtServiceServer(grpcServer, adminservice.ExtServer{})
+	listener := bufconn.Listen(maxBodyBytes)
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Warnf("In-process server of the REST gateway stopped: %v", err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := func() {
+		cancel()
+		grpcServer.Stop()
+	}
+	conn, err := grpc.DialContext(ctx, "bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithInsecure())
+	if err != nil {
+		stop()
+		return nil, nil, err
+	}
+
+	// The fields of the bodies the services do not know of are refused rather than ignored
+	runtime.DisallowUnknownFields()
+	gateway := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{OrigName: true, EmitDefaults: true}),
+		runtime.WithIncomingHeaderMatcher(credentialHeader),
+		runtime.WithMetadata(peerMetadata),
+		runtime.WithProtoErrorHandler(writeProtoError))
+	if err := adminv2.RegisterConfigAdminServiceHandler(ctx, gateway, conn); err != nil {
+		stop()
+		return nil, nil, err
+	}
+	if err := adminext.RegisterConfigAdminExtServiceHandler(ctx, gateway, conn); err != nil {
+		stop()
+		return nil, nil, err
+	}
+
+	spec, err := Spec()
+	if err != nil {
+		stop()
+		return nil, nil, err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(SpecPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeHTTPError(w, http.StatusMethodNotAllowed, status.Errorf(codes.Unimplemented, "method %s not allowed on %s", r.Method, r.URL.Path))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(spec); err != nil {
+			log.Warnf("Failed writing REST gateway response: %v", err)
+		}
+	})
+	mux.Handle(Prefix, s.withPeer(gateway))
+	return mux, stop, nil
+}
+
+// credentialHeader forwards the credential headers of an HTTP call to the in-process server, and
+// only them
+func credentialHeader(header string) (string, bool) {
+	switch strings.ToLower(header) {
+	case "authorization", strings.ToLower(interceptors.APIKeyMetadataKey):
+		return strings.ToLower(header), true
+	}
+	return "", false
+}
+
+type peerKey struct{}
+
+// withPeer bounds the body of an HTTP call, and records its TLS state for the in-process server to
+// present the client certificate to the interceptors as the northbound server would
+func (s *Server) withPeer(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+		if r.TLS != nil {
+			nonce := make([]byte, 16)
+			if _, err := rand.Read(nonce); err != nil {
+				writeHTTPError(w, http.StatusInternalServerError, errors.NewInternal("failed to identify the call: %v", err))
+				return
+			}
+			key := hex.EncodeToString(nonce)
+			s.peers.Store(key, r.TLS)
+			defer s.peers.Delete(key)
+			r = r.WithContext(context.WithValue(r.Context(), peerKey{}, key))
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// peerMetadata passes the key of the TLS state of an HTTP call to the in-process server
+func peerMetadata(ctx context.Context, r *http.Request) metadata.MD {
+	if key, ok := r.Context().Value(peerKey{}).(string); ok {
+		return metadata.Pairs(peerMetadataKey, key)
+	}
+	return nil
+}
+
+// restorePeer presents the TLS state of the HTTP call of an in-process call as its peer
+func (s *Server) restorePeer(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range md.Get(peerMetadataKey) {
+		if state, ok := s.peers.Load(key); ok {
+			p, _ := peer.FromContext(ctx)
+			restored := &peer.Peer{AuthInfo: credentials.TLSInfo{State: *state.(*tls.ConnectionState)}}
+			if p != nil {
+				restored.Addr = p.Addr
+			}
+			return peer.NewContext(ctx, restored)
+		}
+	}
+	return ctx
+}
+
+func (s *Server) restorePeerUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(s.restorePeer(ctx), req)
+}
+
+func (s *Server) restorePeerStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &peerStream{ServerStream: stream, ctx: s.restorePeer(stream.Context())})
+}
+
+type peerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *peerStream) Context() context.Context {
+	return s.ctx
+}
+
+func authorizeAdminUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := interceptors.AuthorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func authorizeAdminStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := interceptors.AuthorizeAdmin(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// httpStatuses are the HTTP statuses of the gRPC status codes
+var httpStatuses = map[codes.Code]int{
+	codes.Canceled:           http.StatusRequestTimeout,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+}
+
+// writeProtoError writes the error of a call with the HTTP status of its gRPC status code
+func writeProtoError(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	httpStatus, ok := httpStatuses[statusOf(err).Code()]
+	if !ok {
+		httpStatus = http.StatusInternalServerError
+	}
+	writeHTTPError(w, httpStatus, err)
+}
+
+func writeHTTPError(w http.ResponseWriter, httpStatus int, err error) {
+	st := statusOf(err)
+	log.Warnf("REST gateway request failed: %v", st.Message())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	if err := json.NewEncoder(w).Encode(&Error{Code: st.Code(), Message: st.Message()}); err != nil {
+		log.Warnf("Failed writing REST gateway response: %v", err)
+	}
+}
+
+// statusOf returns the gRPC status of an error, either a status error or a typed error
+func statusOf(err error) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	return errors.Status(err)
+}
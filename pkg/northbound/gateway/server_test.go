@@ -0,0 +1,235 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	configmodel "github.com/onosproject/onos-config-model/pkg/model"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/modelregistry"
+	"github.com/onosproject/onos-config/pkg/northbound/grpcerrors"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-config/pkg/northbound/readonly"
+	"github.com/onosproject/onos-config/pkg/store/maintenance"
+	"github.com/onosproject/onos-config/pkg/store/stream"
+	"github.com/onosproject/onos-config/pkg/test/mocks/store"
+	"github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// testHandler returns the handler of a gateway authenticating callers by API key or by client
+// certificate, with the given options of the northbound server following the chain
+func testHandler(t *testing.T, opts ...grpc.ServerOption) http.Handler {
+	assert.NoError(t, os.Setenv(interceptors.AdminGroupsEnv, "AetherROCAdmin"))
+	t.Cleanup(func() { _ = os.Unsetenv(interceptors.AdminGroupsEnv) })
+	apiKeys, err := interceptors.NewAPIKeyInterceptor(
+		interceptors.APIKey{Key: "adm1n", Name: "admin", Groups: []string{"AetherROCAdmin"}},
+		interceptors.APIKey{Key: "us3r", Name: "user", Groups: []string{"operators"}})
+	assert.NoError(t, err)
+	certs, err := interceptors.NewMTLSInterceptor(
+		interceptors.CertificateIdentity{Name: "dashboard", Groups: []string{"AetherROCAdmin"}})
+	assert.NoError(t, err)
+	chain := interceptors.NewChain(true, apiKeys, certs)
+	serverOpts := append(grpcerrors.ServerOptions(), chain.ServerOptions()...)
+	handler, stop, err := NewServer(0, "", "", "", chain, append(serverOpts, opts...)...).Handler(context.Background())
+	assert.NoError(t, err)
+	t.Cleanup(stop)
+	return handler
+}
+
+// setUpManager creates the manager over mock stores, with the model of TestDevice 1.0.0
+func setUpManager(t *testing.T) *manager.Manager {
+	registry, err := modelregistry.NewModelRegistry(modelregistry.Config{
+		ModPath:      t.TempDir(),
+		RegistryPath: t.TempDir(),
+		PluginPath:   t.TempDir(),
+		ModTarget:    "github.com/onosproject/onos-config@master",
+	}, &modelregistry.ModelPlugin{
+		Info: configmodel.ModelInfo{Name: "TestDevice", Version: "1.0.0", GetStateMode: configmodel.GetStateOpState},
+		ReadWritePaths: modelregistry.ReadWritePathMap{
+			"/cont1a/leaf1a": modelregistry.ReadWritePathElem{},
+		},
+	})
+	assert.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	return manager.NewManager(
+		store.NewMockLeadershipStore(ctrl),
+		store.NewMockMastershipStore(ctrl),
+		store.NewMockDeviceChangesStore(ctrl),
+		store.NewMockDeviceStateStore(ctrl),
+		store.NewMockDeviceStore(ctrl),
+		cache.NewMockCache(ctrl),
+		store.NewMockNetworkChangesStore(ctrl),
+		store.NewMockNetworkSnapshotStore(ctrl),
+		store.NewMockDeviceSnapshotStore(ctrl),
+		true,
+		registry)
+}
+
+func call(handler http.Handler, method string, target string, body string, apiKey string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	request := httptest.NewRequest(method, target, reader)
+	if apiKey != "" {
+		request.Header.Set(interceptors.APIKeyMetadataKey, apiKey)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func Test_ServeHTTPAuthorization(t *testing.T) {
+	handler := testHandler(t)
+	setUpManager(t)
+
+	response := call(handler, http.MethodGet, "/admin/v1/models", "", "adm1n")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"models":[{"name":"TestDevice","version":"1.0.0","plugin":"","modules":[],"get_state_mode":"GET_STATE_OP_STATE","read_only_paths":[],"read_write_paths":[]}],"next_page_token":""}`,
+		response.Body.String())
+
+	assert.Equal(t, http.StatusUnauthorized, call(handler, http.MethodGet, "/admin/v1/models", "", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, call(handler, http.MethodGet, "/admin/v1/models", "", "bogus").Code)
+	assert.Equal(t, http.StatusForbidden, call(handler, http.MethodGet, "/admin/v1/models", "", "us3r").Code)
+
+	// the description of the resources is public
+	assert.Equal(t, http.StatusOK, call(handler, http.MethodGet, SpecPath, "", "").Code)
+}
+
+func Test_ServeHTTPClientCertificate(t *testing.T) {
+	handler := testHandler(t)
+	setUpManager(t)
+
+	request := httptest.NewRequest(http.MethodGet, "/admin/v1/models", nil)
+	request.TLS = &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "dashboard"}}}},
+	}
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// a caller cannot pass the TLS state of another call
+	request = httptest.NewRequest(http.MethodGet, "/admin/v1/models", nil)
+	request.Header.Set("Grpc-Metadata-"+peerMetadataKey, "0123")
+	request.Header.Set(peerMetadataKey, "0123")
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+}
+
+func Test_ServeUnauthenticated(t *testing.T) {
+	assert.Error(t, NewServer(0, "", "", "", nil).Serve())
+	assert.Error(t, NewServer(0, "", "", "", interceptors.NewChain(false)).Serve())
+}
+
+func Test_Routing(t *testing.T) {
+	handler := testHandler(t)
+	setUpManager(t)
+
+	response := call(handler, http.MethodGet, "/admin/v1/models?verbose=true&name=TestDevice", "", "adm1n")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `"read_write_paths":[{"path":"/cont1a/leaf1a"`)
+	response = call(handler, http.MethodGet, "/admin/v1/models?name=Other", "", "adm1n")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"models":[],"next_page_token":""}`, response.Body.String())
+
+	response = call(handler, http.MethodGet, "/admin/v1/models/TestDevice/1.0.0/validation?path=/cont1a/leaf1a", "", "adm1n")
+	assert.Equal(t, http.StatusOK, response.Code)
+	var validation map[string]interface{}
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &validation))
+	assert.Equal(t, "/cont1a/leaf1a", validation["model_path"])
+	assert.Equal(t, true, validation["leaf"])
+
+	response = call(handler, http.MethodGet, "/admin/v1/models/Other/1.0.0/validation?path=/cont1a", "", "adm1n")
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	var failure Error
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &failure))
+	assert.Equal(t, codes.NotFound, failure.Code)
+	assert.Contains(t, failure.Message, "Other")
+
+	assert.Equal(t, http.StatusBadRequest, call(handler, http.MethodGet, "/admin/v1/models?verbose=maybe", "", "adm1n").Code)
+	assert.Equal(t, http.StatusBadRequest, call(handler, http.MethodPost, "/admin/v1/network-changes/change-1/cancel", `{"bogus": true}`, "adm1n").Code)
+	assert.Equal(t, http.StatusNotImplemented, call(handler, http.MethodPost, "/admin/v1/models", "", "adm1n").Code)
+	assert.Equal(t, http.StatusNotImplemented, call(handler, http.MethodGet, "/admin/v1/bogus", "", "adm1n").Code)
+}
+
+func Test_BodyLimit(t *testing.T) {
+	handler := testHandler(t)
+	setUpManager(t)
+
+	body := `{"reason": "` + strings.Repeat("x", maxBodyBytes) + `"}`
+	response := call(handler, http.MethodPost, "/admin/v1/network-changes/change-1/cancel", body, "adm1n")
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func Test_ReadOnly(t *testing.T) {
+	modes := maintenance.NewLocalStore()
+	assert.NoError(t, modes.Put(&maintenance.Mode{User: "alice", Started: time.Now()}))
+	handler := testHandler(t, readonly.NewGuard(modes).ServerOptions()...)
+	setUpManager(t)
+
+	// the calls are guarded as the RPC they map to
+	response := call(handler, http.MethodPost, "/admin/v1/network-changes/change-1/cancel", `{"rollback": true}`, "adm1n")
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	var failure Error
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &failure))
+	assert.Contains(t, failure.Message, "onos-config is read-only")
+	assert.Equal(t, http.StatusOK, call(handler, http.MethodGet, "/admin/v1/models", "", "adm1n").Code)
+}
+
+func Test_ListNetworkChanges(t *testing.T) {
+	handler := testHandler(t)
+	mgrTest := setUpManager(t)
+
+	created := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	mgrTest.NetworkChangesStore.(*store.MockNetworkChangesStore).EXPECT().List(gomock.Any()).DoAndReturn(
+		func(ch chan<- *networkchange.NetworkChange) (stream.Context, error) {
+			go func() {
+				ch <- &networkchange.NetworkChange{ID: "change-1", Index: 1, Created: created}
+				ch <- &networkchange.NetworkChange{ID: "other", Index: 2, Created: created}
+				close(ch)
+			}()
+			return stream.NewContext(func() {}), nil
+		})
+
+	response := call(handler, http.MethodGet, "/admin/v1/network-changes?filter=change-*", "", "adm1n")
+	assert.Equal(t, http.StatusOK, response.Code)
+	var changes struct {
+		Changes []map[string]interface{} `json:"changes"`
+	}
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &changes))
+	assert.Len(t, changes.Changes, 1)
+	assert.Equal(t, "change-1", changes.Changes[0]["id"])
+	assert.Equal(t, "1", changes.Changes[0]["index"])
+	assert.Equal(t, "2021-06-01T12:00:00Z", changes.Changes[0]["created"])
+}
@@ -0,0 +1,66 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"encoding/json"
+
+	adminv2 "github.com/onosproject/onos-config/api/admin/v2"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// swagger is the part of an OpenAPI 2.0 document the descriptions of the services are merged by
+type swagger struct {
+	Swagger     string                     `json:"swagger"`
+	Info        map[string]interface{}     `json:"info"`
+	Consumes    []string                   `json:"consumes"`
+	Produces    []string                   `json:"produces"`
+	Paths       map[string]json.RawMessage `json:"paths"`
+	Definitions map[string]json.RawMessage `json:"definitions"`
+}
+
+// Spec returns the OpenAPI 2.0 description of the resources of the gateway, merging those
+// generated for each service. The services do not map two operations to the same path, and the
+// definitions they share are those of the gateway itself.
+func Spec() ([]byte, error) {
+	spec := &swagger{
+		Swagger: "2.0",
+		Info: map[string]interface{}{
+			"title":   "onos-config administrative REST API",
+			"version": Prefix,
+		},
+		Consumes:    []string{"application/json"},
+		Produces:    []string{"application/json"},
+		Paths:       make(map[string]json.RawMessage),
+		Definitions: make(map[string]json.RawMessage),
+	}
+	for _, document := range [][]byte{adminv2.Swagger, adminext.Swagger} {
+		service := &swagger{}
+		if err := json.Unmarshal(document, service); err != nil {
+			return nil, errors.NewInternal("invalid OpenAPI description: %v", err)
+		}
+		for path, operations := range service.Paths {
+			if _, ok := spec.Paths[path]; ok {
+				return nil, errors.NewInternal("%s is mapped by two services", path)
+			}
+			spec.Paths[path] = operations
+		}
+		for name, definition := range service.Definitions {
+			spec.Definitions[name] = definition
+		}
+	}
+	return json.Marshal(spec)
+}
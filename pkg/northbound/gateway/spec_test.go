@@ -0,0 +1,56 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Spec(t *testing.T) {
+	document, err := Spec()
+	assert.NoError(t, err)
+	var spec struct {
+		Swagger string `json:"swagger"`
+		Paths   map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+			Parameters  []struct {
+				Name string `json:"name"`
+				In   string `json:"in"`
+			} `json:"parameters"`
+		} `json:"paths"`
+		Definitions map[string]json.RawMessage `json:"definitions"`
+	}
+	assert.NoError(t, json.Unmarshal(document, &spec))
+	assert.Equal(t, "2.0", spec.Swagger)
+
+	// the resources of both services are described
+	assert.Equal(t, "ConfigAdminService_ListNetworkChanges", spec.Paths["/admin/v1/network-changes"]["get"].OperationID)
+	cancel := spec.Paths["/admin/v1/network-changes/{name}/cancel"]["post"]
+	assert.Equal(t, "ConfigAdminExtService_CancelChange", cancel.OperationID)
+	parameters := make(map[string]string)
+	for _, parameter := range cancel.Parameters {
+		parameters[parameter.Name] = parameter.In
+	}
+	assert.Equal(t, map[string]string{"name": "path", "body": "body"}, parameters)
+
+	// every reference resolves to a definition
+	for _, ref := range regexp.MustCompile(`"#/definitions/([^"]+)"`).FindAllStringSubmatch(string(document), -1) {
+		assert.Contains(t, spec.Definitions, ref[1])
+	}
+}
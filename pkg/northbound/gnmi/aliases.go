@@ -0,0 +1,85 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// aliasMinElems is the least number of elements of a prefix for it to be aliased
+const aliasMinElems = 2
+
+// maxAliases bounds the number of aliases defined on one Subscribe stream
+const maxAliases = 1024
+
+// pathAliases are the aliases onos-config defines on a Subscribe stream whose subscriber asked for
+// them with use_aliases. The parent of an updated path is aliased the second time it is notified,
+// and the notifications then carry the alias as their prefix and the rest of the path as updated
+// path. A nil pathAliases defines no alias.
+type pathAliases struct {
+	mu      sync.Mutex
+	seen    map[string]bool
+	aliases map[string]string
+}
+
+func newPathAliases() *pathAliases {
+	return &pathAliases{
+		seen:    make(map[string]bool),
+		aliases: make(map[string]string),
+	}
+}
+
+// compact splits a path of a target in to the prefix and the path of a notification. The prefix
+// is nil unless the parent of the path is aliased, in which case it is the alias and the path is
+// relative to it. The notification defining the alias is returned the first time it is used.
+func (a *pathAliases) compact(path *gnmi.Path) (*gnmi.Path, *gnmi.Path, *gnmi.Notification) {
+	if a == nil || len(path.GetElem()) <= aliasMinElems {
+		return nil, path, nil
+	}
+	parentElems := path.Elem[:len(path.Elem)-1]
+	key := fmt.Sprintf("%s:%s", path.Target, utils.StrPath(&gnmi.Path{Elem: parentElems}))
+	leaf := &gnmi.Path{Elem: path.Elem[len(path.Elem)-1:]}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if alias, ok := a.aliases[key]; ok {
+		return aliasPrefix(alias, path.Target), leaf, nil
+	}
+	if !a.seen[key] {
+		if len(a.seen) < maxAliases {
+			a.seen[key] = true
+		}
+		return nil, path, nil
+	}
+	alias := fmt.Sprintf("#%d", len(a.aliases)+1)
+	a.aliases[key] = alias
+	definition := &gnmi.Notification{
+		Timestamp: time.Now().Unix(),
+		Prefix:    &gnmi.Path{Elem: parentElems, Target: path.Target},
+		Alias:     alias,
+	}
+	log.Infof("Aliasing %s as %s", key, alias)
+	return aliasPrefix(alias, path.Target), leaf, definition
+}
+
+// aliasPrefix is the prefix of a notification that stands for an aliased path
+func aliasPrefix(alias string, target string) *gnmi.Path {
+	return &gnmi.Path{Elem: []*gnmi.PathElem{{Name: alias}}, Target: target}
+}
@@ -0,0 +1,75 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"testing"
+
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_pathAliases(t *testing.T) {
+	path, err := utils.ParseGNMIElements(utils.SplitPath("/system/clock/config/timezone-name"))
+	assert.NoError(t, err)
+	path.Target = "Device1"
+
+	// No alias is defined without use_aliases
+	var none *pathAliases
+	prefix, compacted, definition := none.compact(path)
+	assert.Nil(t, prefix)
+	assert.Equal(t, path, compacted)
+	assert.Nil(t, definition)
+
+	aliases := newPathAliases()
+	prefix, compacted, definition = aliases.compact(path)
+	assert.Nil(t, prefix)
+	assert.Equal(t, path, compacted)
+	assert.Nil(t, definition)
+
+	// The prefix is aliased once repeated
+	prefix, compacted, definition = aliases.compact(path)
+	if assert.NotNil(t, definition) {
+		assert.Equal(t, "#1", definition.Alias)
+		assert.Equal(t, "/system/clock/config", utils.StrPath(definition.Prefix))
+		assert.Equal(t, "Device1", definition.Prefix.Target)
+	}
+	assert.Equal(t, "/#1", utils.StrPath(prefix))
+	assert.Equal(t, "Device1", prefix.Target)
+	assert.Equal(t, "/timezone-name", utils.StrPath(compacted))
+
+	prefix, compacted, definition = aliases.compact(path)
+	assert.Nil(t, definition)
+	assert.Equal(t, "/#1", utils.StrPath(prefix))
+	assert.Equal(t, "/timezone-name", utils.StrPath(compacted))
+
+	// The same prefix on another target has its own alias
+	other, err := utils.ParseGNMIElements(utils.SplitPath("/system/clock/config/timezone-name"))
+	assert.NoError(t, err)
+	other.Target = "Device2"
+	aliases.compact(other)
+	_, _, definition = aliases.compact(other)
+	if assert.NotNil(t, definition) {
+		assert.Equal(t, "#2", definition.Alias)
+	}
+
+	// Short paths are never aliased
+	short, err := utils.ParseGNMIElements(utils.SplitPath("/cont1a/leaf1a"))
+	assert.NoError(t, err)
+	aliases.compact(short)
+	prefix, _, definition = aliases.compact(short)
+	assert.Nil(t, prefix)
+	assert.Nil(t, definition)
+}
@@ -0,0 +1,68 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"strings"
+
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/store/change/atomicity"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// getAtomicity returns the atomicity mode of a SetRequest: the one of its atomicity extension if
+// it has one, or else the mode of the deployment
+func getAtomicity(req *gnmi.SetRequest, deployment atomicity.Mode) (atomicity.Mode, error) {
+	if deployment == "" {
+		deployment = atomicity.ModeAllOrNothing
+	}
+	mode := deployment
+	given := false
+	for _, ext := range req.GetExtension() {
+		if ext.GetRegisteredExt().GetId() == GnmiExtensionAtomicity {
+			if given {
+				return "", status.Errorf(codes.InvalidArgument, "extension %d must only be given once", GnmiExtensionAtomicity)
+			}
+			given = true
+			parsed, err := atomicity.ParseMode(strings.TrimSpace(string(ext.GetRegisteredExt().GetMsg())))
+			if err != nil {
+				return "", status.Errorf(codes.InvalidArgument, "extension %d: %v", GnmiExtensionAtomicity, err)
+			}
+			mode = parsed
+		}
+	}
+	return mode, nil
+}
+
+// storeAtomicity stores the atomicity mode of a network change before the change is created
+func storeAtomicity(store atomicity.Store, changeID networkchange.ID, mode atomicity.Mode) error {
+	if store == nil {
+		return status.Error(codes.Unavailable, "atomicity modes cannot be recorded: no atomicity store")
+	}
+	if err := store.Create(changeID, mode); err != nil {
+		return errors.Status(err).Err()
+	}
+	return nil
+}
+
+// deleteAtomicity deletes the stored atomicity mode of a network change that could not be created
+func deleteAtomicity(store atomicity.Store, changeID networkchange.ID) {
+	if err := store.Delete(changeID); err != nil {
+		log.Errorf("Unable to delete atomicity mode of change %s: %v", changeID, err)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/store/change/atomicity"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func atomicityExtension(mode string) *gnmi_ext.Extension {
+	return &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  GnmiExtensionAtomicity,
+				Msg: []byte(mode),
+			},
+		},
+	}
+}
+
+func Test_getAtomicity(t *testing.T) {
+	mode, err := getAtomicity(&gnmi.SetRequest{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, atomicity.ModeAllOrNothing, mode)
+
+	mode, err = getAtomicity(&gnmi.SetRequest{}, atomicity.ModeBestEffort)
+	assert.NoError(t, err)
+	assert.Equal(t, atomicity.ModeBestEffort, mode)
+
+	// The request overrides the mode of the deployment, both ways
+	mode, err = getAtomicity(&gnmi.SetRequest{Extension: []*gnmi_ext.Extension{atomicityExtension("best-effort")}}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, atomicity.ModeBestEffort, mode)
+	mode, err = getAtomicity(&gnmi.SetRequest{Extension: []*gnmi_ext.Extension{atomicityExtension("all-or-nothing")}}, atomicity.ModeBestEffort)
+	assert.NoError(t, err)
+	assert.Equal(t, atomicity.ModeAllOrNothing, mode)
+
+	_, err = getAtomicity(&gnmi.SetRequest{Extension: []*gnmi_ext.Extension{atomicityExtension("atomic")}}, "")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = getAtomicity(&gnmi.SetRequest{Extension: []*gnmi_ext.Extension{
+		atomicityExtension("best-effort"), atomicityExtension("best-effort")}}, "")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// Test_doSetBestEffort checks the mode of a best-effort Set is stored for its network change
+func Test_doSetBestEffort(t *testing.T) {
+	server, mocks, _ := setUpForGetSetTests(t)
+	setUpChangesMock(mocks)
+	modes := atomicity.NewLocalStore()
+	manager.GetManager().SetAtomicityStore(modes)
+
+	leafPath, _ := utils.ParseGNMIElements(utils.SplitPath("/cont1a/cont2a/leaf2a"))
+	leafPath.Target = "Device1"
+	setRequest := &gnmi.SetRequest{
+		Update: []*gnmi.Update{{
+			Path: leafPath,
+			Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 12}},
+		}},
+		Extension: []*gnmi_ext.Extension{atomicityExtension("best-effort")},
+	}
+	setResponse, err := server.Set(context.Background(), setRequest)
+	assert.NoError(t, err)
+	changeID := networkchange.ID(setResponse.Extension[0].GetRegisteredExt().GetMsg())
+	mode, err := modes.Get(changeID)
+	assert.NoError(t, err)
+	assert.Equal(t, atomicity.ModeBestEffort, mode)
+}
+
+// Test_doSetBestEffortUndone checks a Set that cannot create its change deletes the records it
+// stored, and only those
+func Test_doSetBestEffortUndone(t *testing.T) {
+	server, mocks, mgr := setUpForGetSetTests(t)
+	setUpChangesMock(mocks)
+	modes := atomicity.NewLocalStore()
+	manager.GetManager().SetAtomicityStore(modes)
+
+	leafPath, _ := utils.ParseGNMIElements(utils.SplitPath("/cont1a/cont2a/leaf2a"))
+	leafPath.Target = "Device1"
+	changeName := &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  GnmiExtensionNetwkChangeID,
+				Msg: []byte("UndoneChange"),
+			},
+		},
+	}
+	confirmTimeout := &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  GnmiExtensionConfirmTimeout,
+				Msg: []byte("5m"),
+			},
+		},
+	}
+	setRequest := &gnmi.SetRequest{
+		Update: []*gnmi.Update{{
+			Path: leafPath,
+			Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 12}},
+		}},
+		Extension: []*gnmi_ext.Extension{changeName, atomicityExtension("best-effort"), confirmTimeout},
+	}
+
+	// The change already awaits a confirmation: the mode stored by the Set is deleted, and the
+	// confirmation it did not store is kept
+	_, err := mgr.AwaitConfirmation("UndoneChange", "alice", time.Minute)
+	assert.NoError(t, err)
+	_, err = server.Set(context.Background(), setRequest)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = modes.Get("UndoneChange")
+	assert.NoError(t, err)
+	assert.NoError(t, modes.Create("UndoneChange", atomicity.ModeBestEffort))
+	awaited, err := mgr.ConfirmationStore.Get("UndoneChange")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", awaited.User)
+
+	// The change already has a mode: the mode the Set did not store is kept
+	assert.NoError(t, mgr.ConfirmationStore.Delete("UndoneChange"))
+	_, err = server.Set(context.Background(), setRequest)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	mode, err := modes.Get("UndoneChange")
+	assert.NoError(t, err)
+	assert.Equal(t, atomicity.ModeBestEffort, mode)
+	_, err = mgr.ConfirmationStore.Get("UndoneChange")
+	assert.True(t, errors.IsNotFound(err))
+}
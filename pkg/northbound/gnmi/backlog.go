@@ -0,0 +1,52 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onosproject/onos-config/pkg/controller/change/latency"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+)
+
+// backlogExtension tells the client of a Set how many pending changes its network change is queued
+// behind on each of its targets, one "<target> <position> <eta>" per line, so that it can show
+// progress rather than appear hung; it is nil if the change is queued behind none
+func backlogExtension(backlog []latency.DeviceBacklog) *gnmi_ext.Extension {
+	lines := make([]string, 0, len(backlog))
+	for _, device := range backlog {
+		if device.Position == 0 {
+			continue
+		}
+		line := fmt.Sprintf("%s %d", device.DeviceID, device.Position)
+		if device.ETA > 0 {
+			line = fmt.Sprintf("%s %s", line, device.ETA.Round(time.Second))
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  GnmiExtensionBacklog,
+				Msg: []byte(strings.Join(lines, "\n")),
+			},
+		},
+	}
+}
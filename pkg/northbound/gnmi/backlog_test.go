@@ -0,0 +1,38 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-config/pkg/controller/change/latency"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"gotest.tools/assert"
+)
+
+func Test_backlogExtension(t *testing.T) {
+	assert.Assert(t, backlogExtension(nil) == nil)
+	assert.Assert(t, backlogExtension([]latency.DeviceBacklog{{DeviceID: "device-1", ETA: time.Second}}) == nil,
+		"no change ahead")
+
+	ext := backlogExtension([]latency.DeviceBacklog{
+		{DeviceID: "device-1", Position: 3, ETA: 42*time.Second + 300*time.Millisecond},
+		{DeviceID: "device-2"},
+		{DeviceID: "device-3", Position: 1},
+	})
+	assert.Equal(t, gnmi_ext.ExtensionID(GnmiExtensionBacklog), ext.GetRegisteredExt().GetId())
+	assert.Equal(t, string(ext.GetRegisteredExt().GetMsg()), "device-1 3 42s\ndevice-3 1")
+}
@@ -0,0 +1,103 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/secrets"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// BreakGlassGroup is the group a caller must belong to for a break-glass Set
+const BreakGlassGroup = "break-glass"
+
+// auditBreakGlassChange is the audit action recording break-glass Set requests
+const auditBreakGlassChange = "break-glass"
+
+// checkBreakGlass returns the reason given in the break-glass extension of a SetRequest, and whether
+// the request breaks the glass. The request is refused if the caller is not in the break-glass group
+// or gives no reason.
+func checkBreakGlass(ctx context.Context, req *gnmi.SetRequest) (string, bool, error) {
+	var reason string
+	var breakGlass bool
+	for _, ext := range req.GetExtension() {
+		if ext.GetRegisteredExt().GetId() == GnmiExtensionBreakGlass {
+			if breakGlass {
+				return "", false, status.Errorf(codes.InvalidArgument, "extension %d must only be given once", GnmiExtensionBreakGlass)
+			}
+			breakGlass = true
+			reason = strings.TrimSpace(string(ext.GetRegisteredExt().GetMsg()))
+		}
+	}
+	if !breakGlass {
+		return "", false, nil
+	}
+
+	user, groups := secrets.Caller(ctx)
+	allowed := false
+	for _, group := range groups {
+		if group == BreakGlassGroup {
+			allowed = true
+		}
+	}
+	if !allowed {
+		audit.Record(audit.Entry{
+			User:    user,
+			Action:  auditBreakGlassChange,
+			Message: fmt.Sprintf("rejected: not in the %s group", BreakGlassGroup),
+		})
+		return "", false, status.Errorf(codes.PermissionDenied, "'%s' may not break the glass; it requires the %s group", user, BreakGlassGroup)
+	}
+	if reason == "" {
+		return "", false, status.Errorf(codes.InvalidArgument, "extension %d must give the reason for breaking the glass", GnmiExtensionBreakGlass)
+	}
+	log.Warnf("'%s' breaks the glass on gNMI Set: %s", user, reason)
+	return reason, true, nil
+}
+
+// auditBreakGlass records a break-glass Set, per target, with the paths it sets and deletes. The
+// change ID is empty if the Set changed nothing.
+func auditBreakGlass(user string, changeID networkchange.ID, reason string,
+	targetUpdates mapTargetUpdates, targetRemoves mapTargetRemoves) {
+	targetPaths := make(map[string][]string)
+	for target, updates := range targetUpdates {
+		targetPaths[string(target)] = append(targetPaths[string(target)], updatePaths(updates)...)
+	}
+	for target, removes := range targetRemoves {
+		targetPaths[string(target)] = append(targetPaths[string(target)], removes...)
+	}
+	message := fmt.Sprintf("change %s: %s", changeID, reason)
+	if changeID == "" {
+		message = fmt.Sprintf("no change: %s", reason)
+	}
+	for target, paths := range targetPaths {
+		sort.Strings(paths)
+		audit.Record(audit.Entry{
+			User:    user,
+			Action:  auditBreakGlassChange,
+			Target:  target,
+			Paths:   paths,
+			Message: message,
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"context"
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func breakGlassRequest(reasons ...string) *gnmi.SetRequest {
+	req := &gnmi.SetRequest{}
+	for _, reason := range reasons {
+		req.Extension = append(req.Extension, &gnmi_ext.Extension{
+			Ext: &gnmi_ext.Extension_RegisteredExt{
+				RegisteredExt: &gnmi_ext.RegisteredExtension{
+					Id:  GnmiExtensionBreakGlass,
+					Msg: []byte(reason),
+				},
+			},
+		})
+	}
+	return req
+}
+
+func Test_checkBreakGlass(t *testing.T) {
+	alice := metadata.NewIncomingContext(context.Background(), metadata.Pairs("name", "alice", "groups", "operators"))
+	carol := metadata.NewIncomingContext(context.Background(), metadata.Pairs("name", "carol", "groups", "operators;"+BreakGlassGroup))
+
+	reason, breakGlass, err := checkBreakGlass(alice, breakGlassRequest())
+	assert.NoError(t, err)
+	assert.False(t, breakGlass)
+	assert.Empty(t, reason)
+
+	_, _, err = checkBreakGlass(alice, breakGlassRequest("INC-42 core uplink down"))
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, err.Error(), "'alice' may not break the glass")
+	entries := audit.Entries()
+	assert.Equal(t, auditBreakGlassChange, entries[len(entries)-1].Action)
+	assert.Equal(t, "alice", entries[len(entries)-1].User)
+
+	_, _, err = checkBreakGlass(carol, breakGlassRequest(" "))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, _, err = checkBreakGlass(carol, breakGlassRequest("a", "b"))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	reason, breakGlass, err = checkBreakGlass(carol, breakGlassRequest("INC-42 core uplink down"))
+	assert.NoError(t, err)
+	assert.True(t, breakGlass)
+	assert.Equal(t, "INC-42 core uplink down", reason)
+
+	_, _, _, err = extractExtensions(breakGlassRequest("INC-42 core uplink down"))
+	assert.NoError(t, err)
+}
+
+func Test_auditBreakGlass(t *testing.T) {
+	auditBreakGlass("carol", "change-1", "INC-42 core uplink down", mapTargetUpdates{
+		"device-1": devicechange.TypedValueMap{"/interfaces/interface[name=eth1]/config/enabled": devicechange.NewTypedValueBool(true)},
+	}, mapTargetRemoves{"device-1": {"/acl"}})
+	entries := audit.Entries()
+	entry := entries[len(entries)-1]
+	assert.Equal(t, auditBreakGlassChange, entry.Action)
+	assert.Equal(t, "carol", entry.User)
+	assert.Equal(t, "device-1", entry.Target)
+	assert.Equal(t, []string{"/acl", "/interfaces/interface[name=eth1]/config/enabled"}, entry.Paths)
+	assert.Equal(t, "change change-1: INC-42 core uplink down", entry.Message)
+
+	auditBreakGlass("carol", "", "INC-42 core uplink down", nil, mapTargetRemoves{"device-2": {"/acl"}})
+	entries = audit.Entries()
+	assert.Equal(t, "no change: INC-42 core uplink down", entries[len(entries)-1].Message)
+}
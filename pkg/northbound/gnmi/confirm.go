@@ -0,0 +1,71 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"strings"
+	"time"
+
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/store/change/confirmation"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// getConfirmTimeout returns the confirmation timeout of a commit-confirmed SetRequest, zero if the
+// request is not commit-confirmed
+func getConfirmTimeout(req *gnmi.SetRequest) (time.Duration, error) {
+	var timeout time.Duration
+	for _, ext := range req.GetExtension() {
+		if ext.GetRegisteredExt().GetId() == GnmiExtensionConfirmTimeout {
+			if timeout != 0 {
+				return 0, status.Errorf(codes.InvalidArgument, "extension %d must only be given once", GnmiExtensionConfirmTimeout)
+			}
+			parsed, err := time.ParseDuration(strings.TrimSpace(string(ext.GetRegisteredExt().GetMsg())))
+			if err != nil {
+				return 0, status.Errorf(codes.InvalidArgument, "extension %d: %v", GnmiExtensionConfirmTimeout, err)
+			}
+			if parsed < manager.MinConfirmTimeout || parsed > manager.MaxConfirmTimeout {
+				return 0, status.Errorf(codes.InvalidArgument, "extension %d: confirmation timeout %s is not between %s and %s",
+					GnmiExtensionConfirmTimeout, parsed, manager.MinConfirmTimeout, manager.MaxConfirmTimeout)
+			}
+			timeout = parsed
+		}
+	}
+	return timeout, nil
+}
+
+// deleteConfirmation deletes the confirmation awaited by a network change that could not be created.
+// The confirmation is deleted rather than confirmed, which would be refused once its deadline passed.
+func deleteConfirmation(store confirmation.Store, changeID networkchange.ID) {
+	if err := store.Delete(changeID); err != nil {
+		log.Errorf("Unable to delete confirmation of change %s: %v", changeID, err)
+	}
+}
+
+// confirmExtension gives the deadline of the confirmation of a commit-confirmed change
+func confirmExtension(c *confirmation.Confirmation) *gnmi_ext.Extension {
+	return &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  GnmiExtensionConfirmTimeout,
+				Msg: []byte(c.Deadline.UTC().Format(time.RFC3339)),
+			},
+		},
+	}
+}
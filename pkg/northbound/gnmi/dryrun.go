@@ -0,0 +1,56 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+)
+
+// isDryRun returns whether a Set request is only to be validated, having extension 115
+func isDryRun(req *gnmi.SetRequest) bool {
+	for _, ext := range req.GetExtension() {
+		if ext.GetRegisteredExt().GetId() == GnmiExtensionDryRun {
+			return true
+		}
+	}
+	return false
+}
+
+// buildDryRunResponse returns the response to a valid Set request made as a dry run: the values
+// the network change would set and delete, sorted by target and path, flagged by extension 115
+func buildDryRunResponse(targetUpdates mapTargetUpdates, targetRemoves mapTargetRemoves, noOp bool,
+	unknown *unknownPaths) *gnmi.SetResponse {
+	extensions := []*gnmi_ext.Extension{{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id: GnmiExtensionDryRun,
+			},
+		},
+	}}
+	if noOp {
+		extensions = append(extensions, noOpExtension())
+	}
+	if ext := unknown.extension(); ext != nil {
+		extensions = append(extensions, ext)
+	}
+	return &gnmi.SetResponse{
+		Response:  buildNoOpResults(targetUpdates, targetRemoves),
+		Timestamp: time.Now().Unix(),
+		Extension: extensions,
+	}
+}
@@ -33,4 +33,61 @@ const (
 	// was requested for one or more device which is currently not connected.
 	// Not Connected devices are included in the message.
 	GnmiExtensionDevicesNotConnected = 103
+
+	// GnmiExtensionSignature is used in Set to carry a detached signature over the canonicalized
+	// SetRequest, given as "<key-id>:<base64 signature>"
+	GnmiExtensionSignature = 104
+
+	// GnmiExtensionNoOp is returned by onos-config in the Set response when the values of the request
+	// all equal the intended configuration, i.e. the request changed nothing
+	GnmiExtensionNoOp = 105
+
+	// GnmiExtensionBreakGlass is used in Set to bypass the gates on changes during an incident,
+	// giving the reason as its message
+	GnmiExtensionBreakGlass = 106
+
+	// 107 is sent by onos-config to devices, in the Set requests pushing device changes, to carry the
+	// idempotency key of the push; see the device change controller
+
+	// GnmiExtensionValidationLevel is used in Set to override the validation level of the deployment
+	// for the request: strict, schema-only or none
+	GnmiExtensionValidationLevel = 108
+
+	// GnmiExtensionUnvalidatedPaths is returned by onos-config in the Set response when paths of the request
+	// are not in the models of their targets, which allow such paths; they are pushed unvalidated.
+	// Its message lists them, one "<target>:<path>" per line.
+	GnmiExtensionUnvalidatedPaths = 109
+
+	// GnmiExtensionProvenance is used in Set to give the template, intent or GitOps sync the request
+	// originates from, as "<kind>/<artifact>". In Get, it asks for the artifacts managing the returned
+	// values, listed by the extension of the response one "<target>:<path> <kind>/<artifact>" per line.
+	GnmiExtensionProvenance = 110
+
+	// GnmiExtensionForceOwnership is used in Set to change paths another principal claimed ownership
+	// of, giving the reason as its message
+	GnmiExtensionForceOwnership = 111
+
+	// GnmiExtensionAtomicity is used in Set to override the atomicity mode of the deployment for the
+	// network change of the request: all-or-nothing or best-effort
+	GnmiExtensionAtomicity = 112
+
+	// GnmiExtensionBacklog is returned by onos-config in the Set response when the network change of
+	// the request is queued behind pending changes of its targets. Its message lists them, one
+	// "<target> <position> <eta>" per line, the ETA being left out when it is not known.
+	GnmiExtensionBacklog = 113
+
+	// GnmiExtensionGetPage is used in Get to return at most a number of updates, as "<max-updates>"
+	// for the first page and "<max-updates> <token>" for the next ones. The response carries it with
+	// the token of the next page, unless it is the last one.
+	GnmiExtensionGetPage = 114
+
+	// GnmiExtensionDryRun is used in Set to validate the request, merged with the intended configuration
+	// of its targets, without creating a network change. The response carries it, its results being
+	// the values the change would set and delete.
+	GnmiExtensionDryRun = 115
+
+	// GnmiExtensionConfirmTimeout is used in Set to make a commit-confirmed change, giving as its message
+	// the time within which the change must be confirmed, e.g. "5m", or else be rolled back. The
+	// response carries it with the deadline of the confirmation, in RFC 3339 format.
+	GnmiExtensionConfirmTimeout = 116
 )
@@ -17,16 +17,20 @@ package gnmi
 import (
 	"context"
 	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
 	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
 	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
 	"github.com/onosproject/onos-config/pkg/manager"
-	"github.com/onosproject/onos-config/pkg/store"
+	"github.com/onosproject/onos-config/pkg/northbound/grpcerrors"
+	"github.com/onosproject/onos-config/pkg/secrets"
 	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-config/pkg/utils/tree"
 	"github.com/onosproject/onos-config/pkg/utils/values"
 	"github.com/openconfig/gnmi/proto/gnmi"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"sort"
 	"strings"
 	"time"
 )
@@ -35,13 +39,15 @@ import (
 func (s *Server) Get(ctx context.Context, req *gnmi.GetRequest) (*gnmi.GetResponse, error) {
 	notifications := make([]*gnmi.Notification, 0)
 	groups := make([]string, 0)
+	var user string
 	if md := metautils.ExtractIncoming(ctx); md != nil && md.Get("name") != "" {
+		user = md.Get("name")
 		groups = append(groups, strings.Split(md.Get("groups"), ";")...)
 		log.Infof("gNMI Get() called by '%s (%s)'. Groups %v. Token %s",
 			md.Get("name"), md.Get("email"), groups, md.Get("at_hash"))
 	}
 	if req == nil || (req.GetEncoding() != gnmi.Encoding_PROTO && req.GetEncoding() != gnmi.Encoding_JSON_IETF && req.GetEncoding() != gnmi.Encoding_JSON) {
-		return nil, fmt.Errorf("invalid encoding format in Get request. Only JSON_IETF and PROTO accepted. %v", req.Encoding)
+		return nil, status.Errorf(codes.Unimplemented, "invalid encoding format in Get request. Only JSON_IETF and PROTO accepted. %v", req.GetEncoding())
 	}
 	prefix := req.GetPrefix()
 
@@ -49,11 +55,15 @@ func (s *Server) Get(ctx context.Context, req *gnmi.GetRequest) (*gnmi.GetRespon
 	if err != nil {
 		return nil, err
 	}
+	page, err := extractGetPage(req)
+	if err != nil {
+		return nil, err
+	}
 
 	for _, path := range req.GetPath() {
-		updates, err := s.getUpdate(version, prefix, path, req.GetEncoding(), groups)
+		updates, err := s.getUpdate(version, prefix, path, req.GetEncoding(), user, groups)
 		if err != nil {
-			return nil, status.Error(codes.Internal, err.Error())
+			return nil, grpcerrors.Err(err)
 		}
 		notification := &gnmi.Notification{
 			Timestamp: time.Now().Unix(),
@@ -65,9 +75,9 @@ func (s *Server) Get(ctx context.Context, req *gnmi.GetRequest) (*gnmi.GetRespon
 	}
 	// Alternatively - if there's only the prefix
 	if len(req.GetPath()) == 0 {
-		updates, err := s.getUpdate(version, prefix, nil, req.GetEncoding(), groups)
+		updates, err := s.getUpdate(version, prefix, nil, req.GetEncoding(), user, groups)
 		if err != nil {
-			return nil, status.Error(codes.Internal, err.Error())
+			return nil, grpcerrors.Err(err)
 		}
 		notification := &gnmi.Notification{
 			Timestamp: time.Now().Unix(),
@@ -78,17 +88,46 @@ func (s *Server) Get(ctx context.Context, req *gnmi.GetRequest) (*gnmi.GetRespon
 		notifications = append(notifications, notification)
 	}
 
+	var nextPage string
+	if page != nil {
+		notifications, nextPage = page.paginate(notifications)
+	}
 	response := gnmi.GetResponse{
 		Notification: notifications,
 	}
+	if nextPage != "" {
+		response.Extension = append(response.Extension, pageExtension(page.maxUpdates, nextPage))
+	}
+	if provenanceRequested(req) {
+		paths := req.GetPath()
+		if len(paths) == 0 {
+			paths = []*gnmi.Path{nil}
+		}
+		managed := make([]string, 0)
+		for _, path := range paths {
+			lines, err := getProvenance(version, prefix, path)
+			if err != nil {
+				return nil, grpcerrors.Err(err)
+			}
+			managed = append(managed, lines...)
+		}
+		response.Extension = append(response.Extension, provenanceExtension(managed))
+	}
+	if page == nil && s.maxGetResponseBytes > 0 {
+		if size := proto.Size(&response); size > s.maxGetResponseBytes {
+			return nil, status.Errorf(codes.ResourceExhausted,
+				"response of %d bytes exceeds the limit of %d bytes: page it with extension %d or Subscribe with mode ONCE",
+				size, s.maxGetResponseBytes, GnmiExtensionGetPage)
+		}
+	}
 	return &response, nil
 }
 
 // getUpdate utility method for getting an Update for a given path
 func (s *Server) getUpdate(version devicetype.Version, prefix *gnmi.Path, path *gnmi.Path,
-	encoding gnmi.Encoding, userGroups []string) ([]*gnmi.Update, error) {
+	encoding gnmi.Encoding, user string, userGroups []string) ([]*gnmi.Update, error) {
 	if (path == nil || path.Target == "") && (prefix == nil || prefix.Target == "") {
-		return nil, fmt.Errorf("invalid request - Path %s has no target", utils.StrPath(path))
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request - Path %s has no target", utils.StrPath(path))
 	}
 
 	// If a target exists on the path, use it. If not use target of Prefix
@@ -122,7 +161,7 @@ func (s *Server) getUpdate(version devicetype.Version, prefix *gnmi.Path, path *
 			typedVal = gnmi.TypedValue{
 				Value: &gnmi.TypedValue_LeaflistVal{LeaflistVal: &gnmi.ScalarArray{Element: deviceIDStrs}}}
 		default:
-			return nil, fmt.Errorf("get targets - unhandled encoding format %v", encoding)
+			return nil, status.Errorf(codes.Unimplemented, "get targets - unhandled encoding format %v", encoding)
 		}
 		update := gnmi.Update{
 			Path: &allDevicesPath,
@@ -137,13 +176,22 @@ func (s *Server) getUpdate(version devicetype.Version, prefix *gnmi.Path, path *
 	deviceType, version, errTypeVersion := manager.GetManager().CheckCacheForDevice(devicetype.ID(target), "", version)
 	if errTypeVersion != nil {
 		log.Errorf("Error while extracting type and version for target %s with err %v", target, errTypeVersion)
-		return nil, status.Error(codes.InvalidArgument, errTypeVersion.Error())
+		return nil, grpcerrors.WithMetadata(errTypeVersion, grpcerrors.TargetKey, target)
 	}
 
 	pathAsString := utils.StrPath(path)
 	if prefix != nil && prefix.Elem != nil {
 		pathAsString = utils.StrPath(prefix) + pathAsString
 	}
+	// A path with wildcards must match some path of the model, unless it is not loaded
+	if utils.IsWildcardPath(pathAsString) && !manager.GetManager().AllowsUnknownPaths(devicetype.ID(target)) {
+		expanded, err := manager.GetManager().ExpandPath(deviceType, version, pathAsString)
+		if err != nil {
+			log.Warnf("Not expanding %s for %s: %v", pathAsString, target, err)
+		} else if len(expanded) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "path %s matches no path of the model of %s", pathAsString, target)
+		}
+	}
 
 	s.mu.RLock()
 	revision := s.lastWrite
@@ -159,11 +207,16 @@ func (s *Server) getUpdate(version devicetype.Version, prefix *gnmi.Path, path *
 	stateValues := manager.GetManager().GetTargetState(target, pathAsString)
 	//Merging the two results
 	configValues = append(configValues, stateValues...)
+	// Every value is redacted here, including those read through to the device
+	configValues = secrets.GetRegistry().Redact(target, configValues, user, userGroups)
 
-	return buildUpdate(prefix, path, configValues, encoding)
+	return buildUpdate(prefix, path, configValues, encoding, utils.ToModelName(deviceType, version))
 }
 
-func buildUpdate(prefix *gnmi.Path, path *gnmi.Path, configValues []*devicechange.PathValue, encoding gnmi.Encoding) ([]*gnmi.Update, error) {
+// buildUpdate renders the values of a Get; with the PROTO encoding, the values of the vendor types
+// of the model are encoded by their AnyCodec
+func buildUpdate(prefix *gnmi.Path, path *gnmi.Path, configValues []*devicechange.PathValue, encoding gnmi.Encoding,
+	modelName string) ([]*gnmi.Update, error) {
 	if len(configValues) == 0 {
 		emptyUpdate := gnmi.Update{
 			Path: path,
@@ -176,16 +229,15 @@ func buildUpdate(prefix *gnmi.Path, path *gnmi.Path, configValues []*devicechang
 
 	switch encoding {
 	case gnmi.Encoding_JSON, gnmi.Encoding_JSON_IETF:
-		json, err := store.BuildTree(configValues, true)
+		if hasWildcards(path) {
+			return buildEntryUpdates(prefix, path, configValues, encoding)
+		}
+		val, err := tree.TypedValue(configValues, encoding)
 		if err != nil {
 			return nil, err
 		}
 		update := &gnmi.Update{
-			Val: &gnmi.TypedValue{
-				Value: &gnmi.TypedValue_JsonVal{
-					JsonVal: json,
-				},
-			},
+			Val:  val,
 			Path: path,
 		}
 		return []*gnmi.Update{
@@ -194,7 +246,7 @@ func buildUpdate(prefix *gnmi.Path, path *gnmi.Path, configValues []*devicechang
 	case gnmi.Encoding_PROTO:
 		updates := make([]*gnmi.Update, 0, len(configValues))
 		for _, cv := range configValues {
-			gnmiVal, err := values.NativeTypeToGnmiTypedValue(cv.Value)
+			gnmiVal, err := values.EncodeAny(modelName, cv.Path, cv.Value)
 			if err != nil {
 				return nil, err
 			}
@@ -218,9 +270,70 @@ func buildUpdate(prefix *gnmi.Path, path *gnmi.Path, configValues []*devicechang
 		}
 		return updates, nil
 	default:
-		return nil, fmt.Errorf("unsupported encoding %v", encoding)
+		return nil, status.Errorf(codes.Unimplemented, "unsupported encoding %v", encoding)
+	}
+
+}
+
+// hasWildcards returns true if a path has '*' wildcards, as an element name or a key value, and no
+// '...' wildcard, so that each of its values resolves it to a single path
+func hasWildcards(path *gnmi.Path) bool {
+	wildcards := false
+	for _, elem := range path.GetElem() {
+		if elem.Name == "..." {
+			return false
+		}
+		if elem.Name == "*" {
+			wildcards = true
+		}
+		for _, value := range elem.Key {
+			if value == "*" {
+				wildcards = true
+			}
+		}
 	}
+	return wildcards
+}
 
+// buildEntryUpdates renders the values of a Get of a path with wildcards as one update per path
+// it resolves to, e.g. one per entry of a list, with the keys of the entry in the path of the update
+func buildEntryUpdates(prefix *gnmi.Path, path *gnmi.Path, configValues []*devicechange.PathValue,
+	encoding gnmi.Encoding) ([]*gnmi.Update, error) {
+	prefixLen := len(prefix.GetElem())
+	depth := prefixLen + len(path.Elem)
+	entryPaths := make(map[string]*gnmi.Path)
+	entryValues := make(map[string][]*devicechange.PathValue)
+	for _, cv := range configValues {
+		cvPath, err := utils.ParseGNMIElements(utils.SplitPath(cv.Path))
+		if err != nil {
+			return nil, err
+		}
+		if len(cvPath.Elem) < depth {
+			continue
+		}
+		entryPath := &gnmi.Path{Elem: cvPath.Elem[prefixLen:depth], Target: path.Target, Origin: path.Origin}
+		entry := utils.StrPath(entryPath)
+		entryPaths[entry] = entryPath
+		entryValues[entry] = append(entryValues[entry], cv)
+	}
+
+	entries := make([]string, 0, len(entryPaths))
+	for entry := range entryPaths {
+		entries = append(entries, entry)
+	}
+	sort.Strings(entries)
+	updates := make([]*gnmi.Update, 0, len(entries))
+	for _, entry := range entries {
+		val, err := tree.TypedValue(entryValues[entry], encoding)
+		if err != nil {
+			return nil, err
+		}
+		updates = append(updates, &gnmi.Update{
+			Path: entryPaths[entry],
+			Val:  val,
+		})
+	}
+	return updates, nil
 }
 
 func extractGetVersion(req *gnmi.GetRequest) (devicetype.Version, error) {
@@ -228,6 +341,10 @@ func extractGetVersion(req *gnmi.GetRequest) (devicetype.Version, error) {
 	for _, ext := range req.GetExtension() {
 		if ext.GetRegisteredExt().GetId() == GnmiExtensionVersion {
 			version = devicetype.Version(ext.GetRegisteredExt().GetMsg())
+		} else if ext.GetRegisteredExt().GetId() == GnmiExtensionProvenance {
+			continue // see provenanceRequested
+		} else if ext.GetRegisteredExt().GetId() == GnmiExtensionGetPage {
+			continue // see extractGetPage
 		} else {
 			return "", status.Error(codes.InvalidArgument, fmt.Errorf("unexpected extension %d = '%s' in Get()",
 				ext.GetRegisteredExt().GetId(), ext.GetRegisteredExt().GetMsg()).Error())
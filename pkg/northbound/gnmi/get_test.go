@@ -507,3 +507,35 @@ func Test_pathDoesNotExist(t *testing.T) {
 		"/leaf2w")
 	assert.Nil(t, result.Notification[0].Update[0].Val)
 }
+
+// Test_buildUpdateWildcardKeys is a Get of a path with a wildcard key, answered with one update
+// per list entry, with the key of the entry in its path
+func Test_buildUpdateWildcardKeys(t *testing.T) {
+	path, err := utils.ParseGNMIElements(utils.SplitPath("/cont1a/list2a[name=*]/tx-power"))
+	assert.NoError(t, err)
+	path.Target = "Device1"
+	configValues := []*devicechange.PathValue{
+		{Path: "/cont1a/list2a[name=second]/tx-power", Value: devicechange.NewTypedValueUint(12, 16)},
+		{Path: "/cont1a/list2a[name=first]/tx-power", Value: devicechange.NewTypedValueUint(19, 16)},
+	}
+
+	updates, err := buildUpdate(nil, path, configValues, gnmi.Encoding_JSON, "Devicesim-1.0.0")
+	assert.NoError(t, err)
+	assert.Len(t, updates, 2)
+	assert.Equal(t, "/cont1a/list2a[name=first]/tx-power", utils.StrPath(updates[0].Path))
+	assert.Equal(t, "Device1", updates[0].Path.Target)
+	assert.Contains(t, string(updates[0].Val.GetJsonVal()), `"tx-power": 19`)
+	assert.Equal(t, "/cont1a/list2a[name=second]/tx-power", utils.StrPath(updates[1].Path))
+	assert.Contains(t, string(updates[1].Val.GetJsonVal()), `"tx-power": 12`)
+
+	// With a prefix, the paths of the updates are relative to it
+	prefix, err := utils.ParseGNMIElements(utils.SplitPath("/cont1a"))
+	assert.NoError(t, err)
+	path, err = utils.ParseGNMIElements(utils.SplitPath("/list2a[name=*]"))
+	assert.NoError(t, err)
+	updates, err = buildUpdate(prefix, path, configValues, gnmi.Encoding_JSON_IETF, "Devicesim-1.0.0")
+	assert.NoError(t, err)
+	assert.Len(t, updates, 2)
+	assert.Equal(t, "/list2a[name=first]", utils.StrPath(updates[0].Path))
+	assert.Equal(t, "/list2a[name=second]", utils.StrPath(updates[1].Path))
+}
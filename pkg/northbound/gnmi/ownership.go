@@ -0,0 +1,136 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/store/ownership"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// auditOwnershipOverride is the audit action recording the changes of paths claimed by another owner
+const auditOwnershipOverride = "override-path-ownership"
+
+// checkForceOwnership returns the reason given in the force ownership extension of a SetRequest,
+// and whether the request forces its way through the claims of other owners
+func checkForceOwnership(req *gnmi.SetRequest) (string, bool, error) {
+	var reason string
+	var force bool
+	for _, ext := range req.GetExtension() {
+		if ext.GetRegisteredExt().GetId() == GnmiExtensionForceOwnership {
+			if force {
+				return "", false, status.Errorf(codes.InvalidArgument, "extension %d must only be given once", GnmiExtensionForceOwnership)
+			}
+			force = true
+			reason = strings.TrimSpace(string(ext.GetRegisteredExt().GetMsg()))
+		}
+	}
+	if force && reason == "" {
+		return "", false, status.Errorf(codes.InvalidArgument, "extension %d must give the reason for forcing the change", GnmiExtensionForceOwnership)
+	}
+	return reason, force, nil
+}
+
+// ownedPath is a path of a SetRequest in a subtree claimed by another owner
+type ownedPath struct {
+	path  string
+	claim *ownership.Claim
+}
+
+// checkOwnership returns the paths of each target a SetRequest changes in subtrees other owners
+// claimed. The request is refused unless it is forced or breaks the glass.
+func checkOwnership(store ownership.Store, user string, targetUpdates mapTargetUpdates, targetRemoves mapTargetRemoves,
+	force bool) (map[devicetype.ID][]ownedPath, error) {
+	targetOwned := make(map[devicetype.ID][]ownedPath)
+	targets := make(map[devicetype.ID]bool)
+	for target := range targetUpdates {
+		targets[target] = true
+	}
+	for target := range targetRemoves {
+		targets[target] = true
+	}
+	for target := range targets {
+		claims, err := store.List(target)
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "the ownership of the paths of %s cannot be checked: %v", target, err)
+		}
+		owned := make([]ownedPath, 0)
+		for _, claim := range claims {
+			if claim.Owner == user {
+				continue
+			}
+			for _, path := range updatePaths(targetUpdates[target]) {
+				if claim.Covers(path, false) {
+					owned = append(owned, ownedPath{path: path, claim: claim})
+				}
+			}
+			for _, path := range targetRemoves[target] {
+				if claim.Covers(path, true) {
+					owned = append(owned, ownedPath{path: path, claim: claim})
+				}
+			}
+		}
+		if len(owned) > 0 {
+			sort.Slice(owned, func(i, j int) bool {
+				return owned[i].path < owned[j].path
+			})
+			targetOwned[target] = owned
+		}
+	}
+	if len(targetOwned) == 0 || force {
+		return targetOwned, nil
+	}
+
+	ids := make([]string, 0, len(targetOwned))
+	for target := range targetOwned {
+		ids = append(ids, string(target))
+	}
+	sort.Strings(ids)
+	first := targetOwned[devicetype.ID(ids[0])][0]
+	return nil, status.Errorf(codes.PermissionDenied, "'%s' may not change %s of %s: %s is claimed by '%s'; force the change with extension %d",
+		user, first.path, ids[0], first.claim.Prefix, first.claim.Owner, GnmiExtensionForceOwnership)
+}
+
+// auditOwnershipOverrides records the changes of paths claimed by other owners, per target
+func auditOwnershipOverrides(user string, changeID networkchange.ID, reason string, targetOwned map[devicetype.ID][]ownedPath) {
+	for target, owned := range targetOwned {
+		paths := make([]string, 0, len(owned))
+		owners := make(map[string]bool)
+		for _, o := range owned {
+			paths = append(paths, o.path)
+			owners[o.claim.Owner] = true
+		}
+		ownerNames := make([]string, 0, len(owners))
+		for owner := range owners {
+			ownerNames = append(ownerNames, owner)
+		}
+		sort.Strings(ownerNames)
+		audit.Record(audit.Entry{
+			User:    user,
+			Action:  auditOwnershipOverride,
+			Target:  string(target),
+			Paths:   paths,
+			Message: fmt.Sprintf("change %s overrides the claims of %s: %s", changeID, strings.Join(ownerNames, ", "), reason),
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/store/ownership"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func forceOwnershipExt(reason string) *gnmi_ext.Extension {
+	return &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  GnmiExtensionForceOwnership,
+				Msg: []byte(reason),
+			},
+		},
+	}
+}
+
+func Test_checkForceOwnership(t *testing.T) {
+	reason, force, err := checkForceOwnership(&gnmi.SetRequest{})
+	assert.NoError(t, err)
+	assert.False(t, force)
+	assert.Equal(t, "", reason)
+
+	reason, force, err = checkForceOwnership(&gnmi.SetRequest{Extension: []*gnmi_ext.Extension{forceOwnershipExt(" controller is down ")}})
+	assert.NoError(t, err)
+	assert.True(t, force)
+	assert.Equal(t, "controller is down", reason)
+
+	_, _, err = checkForceOwnership(&gnmi.SetRequest{Extension: []*gnmi_ext.Extension{forceOwnershipExt("")}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, _, err = checkForceOwnership(&gnmi.SetRequest{Extension: []*gnmi_ext.Extension{forceOwnershipExt("a"), forceOwnershipExt("b")}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func Test_checkOwnership(t *testing.T) {
+	store := ownership.NewLocalStore()
+	assert.NoError(t, store.Create(&ownership.Claim{
+		DeviceID: "device-1",
+		Prefix:   "/interfaces/interface[name=eth1]",
+		Owner:    "fabric-controller",
+	}))
+
+	updates := mapTargetUpdates{
+		"device-1": devicechange.TypedValueMap{
+			"/interfaces/interface[name=eth1]/config/mtu": devicechange.NewTypedValueUint(9000, 16),
+			"/system/config/hostname":                     devicechange.NewTypedValueString("switch1"),
+		},
+	}
+
+	// The owner changes its subtree freely, as does anyone outside of it
+	targetOwned, err := checkOwnership(store, "fabric-controller", updates, nil, false)
+	assert.NoError(t, err)
+	assert.Empty(t, targetOwned)
+	targetOwned, err = checkOwnership(store, "alice", mapTargetUpdates{
+		"device-1": devicechange.TypedValueMap{"/system/config/hostname": devicechange.NewTypedValueString("switch1")},
+	}, mapTargetRemoves{"device-2": {"/interfaces"}}, false)
+	assert.NoError(t, err)
+	assert.Empty(t, targetOwned)
+
+	_, err = checkOwnership(store, "alice", updates, nil, false)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, err.Error(), "'alice' may not change /interfaces/interface[name=eth1]/config/mtu of device-1")
+	assert.Contains(t, err.Error(), "claimed by 'fabric-controller'")
+
+	// Deleting an ancestor of the subtree deletes it
+	_, err = checkOwnership(store, "alice", nil, mapTargetRemoves{"device-1": {"/interfaces"}}, false)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	targetOwned, err = checkOwnership(store, "alice", updates, mapTargetRemoves{"device-1": {"/interfaces"}}, true)
+	assert.NoError(t, err)
+	assert.Len(t, targetOwned["device-1"], 2)
+	assert.Equal(t, "/interfaces", targetOwned["device-1"][0].path)
+
+	auditOwnershipOverrides("alice", "change-1", "controller is down", targetOwned)
+	entries := audit.Entries()
+	entry := entries[len(entries)-1]
+	assert.Equal(t, auditOwnershipOverride, entry.Action)
+	assert.Equal(t, "device-1", entry.Target)
+	assert.Equal(t, []string{"/interfaces", "/interfaces/interface[name=eth1]/config/mtu"}, entry.Paths)
+	assert.Equal(t, "change change-1 overrides the claims of fabric-controller: controller is down", entry.Message)
+}
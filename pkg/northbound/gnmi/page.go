@@ -0,0 +1,172 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// getPage is a page of the updates of a Get, see GnmiExtensionGetPage
+type getPage struct {
+	maxUpdates int
+	// after is where the page starts, after the last update of the previous page; nil for the
+	// first page
+	after *pageCursor
+}
+
+// pageCursor is the last update of a page: the index of its notification and its path. The
+// updates of each notification are paged in the order of their paths, so that a value set or
+// removed between two pages does not shift the ones after it.
+type pageCursor struct {
+	notification int
+	path         string
+}
+
+// extractGetPage returns the page of a Get asked for by its extension, nil if it asks for none
+func extractGetPage(req *gnmi.GetRequest) (*getPage, error) {
+	for _, ext := range req.GetExtension() {
+		if ext.GetRegisteredExt().GetId() != GnmiExtensionGetPage {
+			continue
+		}
+		msg := string(ext.GetRegisteredExt().GetMsg())
+		fields := strings.Fields(msg)
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page '%s': expected '<max-updates> [<token>]'", msg)
+		}
+		maxUpdates, err := strconv.Atoi(fields[0])
+		if err != nil || maxUpdates <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page size '%s'", fields[0])
+		}
+		page := &getPage{maxUpdates: maxUpdates}
+		if len(fields) == 2 {
+			if page.after, err = decodePageToken(fields[1]); err != nil {
+				return nil, err
+			}
+		}
+		return page, nil
+	}
+	return nil, nil
+}
+
+// paginate returns the notifications of the updates of the page, and the token of the next page,
+// empty if it is the last one
+func (p *getPage) paginate(notifications []*gnmi.Notification) ([]*gnmi.Notification, string) {
+	paged := make([]*gnmi.Notification, 0)
+	var last *pageCursor
+	for i, notification := range notifications {
+		if p.after != nil && i < p.after.notification {
+			continue
+		}
+		updates := make([]*gnmi.Update, len(notification.Update))
+		copy(updates, notification.Update)
+		sort.SliceStable(updates, func(j, k int) bool {
+			return utils.StrPath(updates[j].Path) < utils.StrPath(updates[k].Path)
+		})
+		pageUpdates := make([]*gnmi.Update, 0)
+		for _, update := range updates {
+			path := utils.StrPath(update.Path)
+			if p.after != nil && i == p.after.notification && path <= p.after.path {
+				continue
+			}
+			if last != nil && countUpdates(paged)+len(pageUpdates) == p.maxUpdates {
+				return appendPageNotification(paged, notification, pageUpdates), encodePageToken(last)
+			}
+			pageUpdates = append(pageUpdates, update)
+			last = &pageCursor{notification: i, path: path}
+		}
+		paged = appendPageNotification(paged, notification, pageUpdates)
+	}
+	return paged, ""
+}
+
+// appendPageNotification appends a notification with the updates of another one on the page, if any
+func appendPageNotification(paged []*gnmi.Notification, notification *gnmi.Notification, updates []*gnmi.Update) []*gnmi.Notification {
+	if len(updates) == 0 {
+		return paged
+	}
+	return append(paged, &gnmi.Notification{
+		Timestamp: notification.Timestamp,
+		Prefix:    notification.Prefix,
+		Alias:     notification.Alias,
+		Update:    updates,
+	})
+}
+
+// countUpdates returns the number of updates of notifications
+func countUpdates(notifications []*gnmi.Notification) int {
+	count := 0
+	for _, notification := range notifications {
+		count += len(notification.Update)
+	}
+	return count
+}
+
+// encodePageToken returns the opaque token of the page after a cursor
+func encodePageToken(cursor *pageCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d %s", cursor.notification, cursor.path)))
+}
+
+// decodePageToken returns the cursor a page token starts after
+func decodePageToken(token string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page token '%s'", token)
+	}
+	parts := strings.SplitN(string(data), " ", 2)
+	if len(parts) != 2 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page token '%s'", token)
+	}
+	notification, err := strconv.Atoi(parts[0])
+	if err != nil || notification < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page token '%s'", token)
+	}
+	return &pageCursor{notification: notification, path: parts[1]}, nil
+}
+
+// pageExtension gives the client of a Get the token of the next page
+func pageExtension(maxUpdates int, token string) *gnmi_ext.Extension {
+	return &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  GnmiExtensionGetPage,
+				Msg: []byte(fmt.Sprintf("%d %s", maxUpdates, token)),
+			},
+		},
+	}
+}
+
+// splitUpdates splits updates into groups of at most a number of updates; a single group if the
+// number is 0
+func splitUpdates(updates []*gnmi.Update, maxUpdates int) [][]*gnmi.Update {
+	if maxUpdates <= 0 || len(updates) <= maxUpdates {
+		return [][]*gnmi.Update{updates}
+	}
+	groups := make([][]*gnmi.Update, 0, (len(updates)+maxUpdates-1)/maxUpdates)
+	for len(updates) > maxUpdates {
+		groups = append(groups, updates[:maxUpdates])
+		updates = updates[maxUpdates:]
+	}
+	return append(groups, updates)
+}
@@ -0,0 +1,103 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func pageRequest(msg string) *gnmi.GetRequest {
+	return &gnmi.GetRequest{Extension: []*gnmi_ext.Extension{{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{Id: GnmiExtensionGetPage, Msg: []byte(msg)},
+		},
+	}}}
+}
+
+func Test_getPage(t *testing.T) {
+	pathUpdate := func(path string) *gnmi.Update {
+		gnmiPath, err := utils.ParseGNMIElements(utils.SplitPath(path))
+		assert.NilError(t, err)
+		return &gnmi.Update{Path: gnmiPath}
+	}
+	notifications := []*gnmi.Notification{
+		{Update: []*gnmi.Update{pathUpdate("/a/c"), pathUpdate("/a/a"), pathUpdate("/a/b")}},
+		{Update: []*gnmi.Update{pathUpdate("/b/a"), pathUpdate("/b/b")}},
+	}
+
+	// The pages are read until the last one, which has no next page
+	paths := make([]string, 0)
+	msg := "2"
+	sizes := make([]int, 0)
+	for msg != "" {
+		page, err := extractGetPage(pageRequest(msg))
+		assert.NilError(t, err)
+		paged, next := page.paginate(notifications)
+		sizes = append(sizes, countUpdates(paged))
+		for _, notification := range paged {
+			for _, update := range notification.Update {
+				paths = append(paths, utils.StrPath(update.Path))
+			}
+		}
+		msg = ""
+		if next != "" {
+			msg = string(pageExtension(page.maxUpdates, next).GetRegisteredExt().GetMsg())
+		}
+	}
+	assert.DeepEqual(t, sizes, []int{2, 2, 1})
+	assert.DeepEqual(t, paths, []string{"/a/a", "/a/b", "/a/c", "/b/a", "/b/b"})
+
+	// The notifications are not changed
+	assert.Equal(t, utils.StrPath(notifications[0].Update[0].Path), "/a/c")
+
+	// A page is not shifted by an update removed before it
+	page, err := extractGetPage(pageRequest("2"))
+	assert.NilError(t, err)
+	_, next := page.paginate(notifications)
+	notifications[0].Update = []*gnmi.Update{notifications[0].Update[0], notifications[0].Update[2]}
+	page, err = extractGetPage(pageRequest("2 " + next))
+	assert.NilError(t, err)
+	paged, _ := page.paginate(notifications)
+	assert.Equal(t, utils.StrPath(paged[0].Update[0].Path), "/a/c")
+
+	page, err = extractGetPage(&gnmi.GetRequest{})
+	assert.NilError(t, err)
+	assert.Assert(t, page == nil)
+	for _, msg := range []string{"", "0", "ten", "2 not-a-token", fmt.Sprintf("2 %s extra", next)} {
+		_, err := extractGetPage(pageRequest(msg))
+		assert.Equal(t, status.Code(err), codes.InvalidArgument, msg)
+	}
+}
+
+func Test_splitUpdates(t *testing.T) {
+	updates := make([]*gnmi.Update, 5)
+	assert.Equal(t, len(splitUpdates(updates, 0)), 1)
+	assert.Equal(t, len(splitUpdates(updates, 5)), 1)
+	groups := splitUpdates(updates, 2)
+	sizes := make([]string, 0)
+	for _, group := range groups {
+		sizes = append(sizes, fmt.Sprint(len(group)))
+	}
+	assert.Equal(t, strings.Join(sizes, ","), "2,2,1")
+}
@@ -0,0 +1,93 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/protected"
+	"github.com/onosproject/onos-config/pkg/secrets"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// auditProtectedChange is the audit action recording the changes of protected subtrees
+const auditProtectedChange = "change-protected-paths"
+
+// checkProtected returns the paths of each target a SetRequest changes in protected subtrees. The
+// request is refused unless the caller may change them or breaks the glass.
+func checkProtected(ctx context.Context, registry *protected.Registry,
+	targetUpdates mapTargetUpdates, targetRemoves mapTargetRemoves, breakGlass bool) (map[devicetype.ID][]string, error) {
+	targetProtected := make(map[devicetype.ID][]string)
+	for target, updates := range targetUpdates {
+		if paths := registry.ProtectedPaths(updatePaths(updates), targetRemoves[target]); len(paths) > 0 {
+			targetProtected[target] = paths
+		}
+	}
+	for target, removes := range targetRemoves {
+		if _, ok := targetUpdates[target]; ok {
+			continue
+		}
+		if paths := registry.ProtectedPaths(nil, removes); len(paths) > 0 {
+			targetProtected[target] = paths
+		}
+	}
+	if len(targetProtected) == 0 {
+		return nil, nil
+	}
+	if breakGlass {
+		return targetProtected, nil
+	}
+
+	user, groups := secrets.Caller(ctx)
+	if !protected.CanChange(groups) {
+		targets := make([]string, 0, len(targetProtected))
+		for target := range targetProtected {
+			targets = append(targets, string(target))
+		}
+		sort.Strings(targets)
+		first := targets[0]
+		return nil, status.Errorf(codes.PermissionDenied, "'%s' may not change the protected paths %v of %s; it requires the %s group",
+			user, targetProtected[devicetype.ID(first)], first, protected.ChangeProtectedGroup)
+	}
+	return targetProtected, nil
+}
+
+func updatePaths(updates devicechange.TypedValueMap) []string {
+	paths := make([]string, 0, len(updates))
+	for path := range updates {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// auditProtected records the change of protected subtrees, per target
+func auditProtected(user string, changeID networkchange.ID, targetProtected map[devicetype.ID][]string) {
+	for target, paths := range targetProtected {
+		audit.Record(audit.Entry{
+			User:    user,
+			Action:  auditProtectedChange,
+			Target:  string(target),
+			Paths:   paths,
+			Message: fmt.Sprintf("protected paths changed by change %s", changeID),
+		})
+	}
+}
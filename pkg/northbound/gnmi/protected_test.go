@@ -0,0 +1,69 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"context"
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/protected"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func Test_checkProtected(t *testing.T) {
+	registry := protected.NewRegistry("/system/aaa")
+	alice := metadata.NewIncomingContext(context.Background(), metadata.Pairs("name", "alice", "groups", "operators"))
+	bob := metadata.NewIncomingContext(context.Background(), metadata.Pairs("name", "bob", "groups", "operators;"+protected.ChangeProtectedGroup))
+
+	routine := mapTargetUpdates{
+		"device-1": devicechange.TypedValueMap{"/system/config/hostname": devicechange.NewTypedValueString("switch1")},
+	}
+	targetProtected, err := checkProtected(alice, registry, routine, mapTargetRemoves{"device-2": {"/system/config/motd-banner"}}, false)
+	assert.NoError(t, err)
+	assert.Empty(t, targetProtected)
+
+	lockout := mapTargetRemoves{"device-2": {"/system"}}
+	_, err = checkProtected(alice, registry, routine, lockout, false)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, err.Error(), "'alice' may not change the protected paths [/system] of device-2")
+
+	targetProtected, err = checkProtected(alice, registry, routine, lockout, true)
+	assert.NoError(t, err)
+	assert.Equal(t, map[devicetype.ID][]string{"device-2": {"/system"}}, targetProtected)
+
+	targetProtected, err = checkProtected(bob, registry, mapTargetUpdates{
+		"device-1": devicechange.TypedValueMap{"/system/aaa/authentication/config/authentication-method": devicechange.NewTypedValueString("LOCAL")},
+	}, lockout, false)
+	assert.NoError(t, err)
+	assert.Equal(t, map[devicetype.ID][]string{
+		"device-1": {"/system/aaa/authentication/config/authentication-method"},
+		"device-2": {"/system"},
+	}, targetProtected)
+
+	auditProtected("bob", "change-1", map[devicetype.ID][]string{"device-2": {"/system"}})
+	entries := audit.Entries()
+	entry := entries[len(entries)-1]
+	assert.Equal(t, auditProtectedChange, entry.Action)
+	assert.Equal(t, "bob", entry.User)
+	assert.Equal(t, "device-2", entry.Target)
+	assert.Equal(t, []string{"/system"}, entry.Paths)
+	assert.Equal(t, "protected paths changed by change change-1", entry.Message)
+}
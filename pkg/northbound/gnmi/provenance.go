@@ -0,0 +1,123 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/store/change/provenance"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// extractProvenance returns the artifact a SetRequest originates from, given by its provenance
+// extension, or nil if it has none
+func extractProvenance(req *gnmi.SetRequest, user string) (*provenance.Provenance, error) {
+	var origin *provenance.Provenance
+	for _, ext := range req.GetExtension() {
+		if ext.GetRegisteredExt().GetId() == GnmiExtensionProvenance {
+			if origin != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "extension %d must only be given once", GnmiExtensionProvenance)
+			}
+			var err error
+			if origin, err = provenance.Parse(string(ext.GetRegisteredExt().GetMsg())); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			origin.User = user
+			origin.Created = time.Now()
+		}
+	}
+	return origin, nil
+}
+
+// storeProvenance stores the provenance of the network change a Set is about to create. The Set
+// must fail if it cannot be stored, or the values of the change would not be traced to their artifact.
+func storeProvenance(store provenance.Store, changeID networkchange.ID, origin *provenance.Provenance) error {
+	origin.ChangeID = changeID
+	if store == nil {
+		return status.Error(codes.Unavailable, "provenances cannot be recorded: no provenance store")
+	}
+	if err := store.Create(origin); err != nil {
+		return errors.Status(err).Err()
+	}
+	return nil
+}
+
+// deleteProvenance deletes the stored provenance of a network change that could not be created
+func deleteProvenance(store provenance.Store, changeID networkchange.ID) {
+	if err := store.Delete(changeID); err != nil {
+		log.Errorf("Unable to delete provenance of change %s: %v", changeID, err)
+	}
+}
+
+// provenanceRequested returns whether a GetRequest asks for the artifacts managing its values
+func provenanceRequested(req *gnmi.GetRequest) bool {
+	for _, ext := range req.GetExtension() {
+		if ext.GetRegisteredExt().GetId() == GnmiExtensionProvenance {
+			return true
+		}
+	}
+	return false
+}
+
+// getProvenance returns the values under a path of a Get that are managed by an artifact, each as
+// "<target>:<path> <kind>/<artifact>"
+func getProvenance(version devicetype.Version, prefix *gnmi.Path, path *gnmi.Path) ([]string, error) {
+	target := path.GetTarget()
+	if target == "" {
+		target = prefix.GetTarget()
+	}
+	if target == "*" {
+		return nil, nil
+	}
+	pathAsString := utils.StrPath(path)
+	if prefix != nil && prefix.Elem != nil {
+		pathAsString = utils.StrPath(prefix) + pathAsString
+	}
+	values, err := manager.GetManager().BlameConfig(devicetype.ID(target), version, pathAsString)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]string, 0)
+	for _, value := range values {
+		if value.Provenance != nil {
+			lines = append(lines, fmt.Sprintf("%s:%s %s", target, value.Path, value.Provenance))
+		}
+	}
+	return lines, nil
+}
+
+// provenanceExtension returns the extension of a GetResponse listing the managed values
+func provenanceExtension(lines []string) *gnmi_ext.Extension {
+	sort.Strings(lines)
+	return &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  GnmiExtensionProvenance,
+				Msg: []byte(strings.Join(lines, "\n")),
+			},
+		},
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"testing"
+
+	"github.com/onosproject/onos-config/pkg/store/change/provenance"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func provenanceExt(msg string) *gnmi_ext.Extension {
+	return &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  GnmiExtensionProvenance,
+				Msg: []byte(msg),
+			},
+		},
+	}
+}
+
+func Test_ExtractProvenance(t *testing.T) {
+	req := signedTestRequest()
+	origin, err := extractProvenance(req, "alice")
+	assert.NoError(t, err)
+	assert.Nil(t, origin)
+
+	req.Extension = []*gnmi_ext.Extension{provenanceExt("intent/slice-42")}
+	origin, err = extractProvenance(req, "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, provenance.Intent, origin.Kind)
+	assert.Equal(t, "slice-42", origin.Artifact)
+	assert.Equal(t, "alice", origin.User)
+
+	// The extension is not rejected with the others of the Set
+	_, _, _, err = extractExtensions(req)
+	assert.NoError(t, err)
+
+	req.Extension = []*gnmi_ext.Extension{provenanceExt("script/cleanup")}
+	_, err = extractProvenance(req, "alice")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	req.Extension = []*gnmi_ext.Extension{provenanceExt("template/a"), provenanceExt("template/b")}
+	_, err = extractProvenance(req, "alice")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func Test_StoreProvenance(t *testing.T) {
+	store := provenance.NewLocalStore()
+	origin, err := provenance.Parse("gitops/fabric@3f2a9c1")
+	assert.NoError(t, err)
+	assert.NoError(t, storeProvenance(store, "change-1", origin))
+	stored, err := store.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "gitops/fabric@3f2a9c1", stored.String())
+
+	assert.Equal(t, codes.AlreadyExists, status.Code(storeProvenance(store, "change-1", origin)))
+	assert.Equal(t, codes.Unavailable, status.Code(storeProvenance(nil, "change-2", origin)))
+
+	deleteProvenance(store, "change-1")
+	_, err = store.Get("change-1")
+	assert.Error(t, err)
+}
+
+func Test_ProvenanceRequested(t *testing.T) {
+	assert.False(t, provenanceRequested(&gnmi.GetRequest{}))
+	req := &gnmi.GetRequest{Extension: []*gnmi_ext.Extension{provenanceExt("")}}
+	assert.True(t, provenanceRequested(req))
+	_, err := extractGetVersion(req)
+	assert.NoError(t, err)
+
+	ext := provenanceExtension([]string{"device-2:/a template/x", "device-1:/b intent/y"})
+	assert.Equal(t, "device-1:/b intent/y\ndevice-2:/a template/x", string(ext.GetRegisteredExt().GetMsg()))
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
 	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/store/change/atomicity"
 	"io/ioutil"
 	"sync"
 
@@ -35,17 +36,48 @@ import (
 // Service implements Service for GNMI
 type Service struct {
 	northbound.Service
+	// SquashChanges makes a Set store only the final value of each path it writes
+	SquashChanges bool
+	// RecordNoOpSets makes a Set that changes nothing create a network change all the same
+	RecordNoOpSets bool
+	// ValidationLevel is how strictly a Set is validated, unless the request overrides it; strict
+	// if not set
+	ValidationLevel ValidationLevel
+	// Atomicity is how the network change of a Set behaves when one of its devices rejects it,
+	// unless the request overrides it; all-or-nothing if not set
+	Atomicity atomicity.Mode
+	// MaxUpdatesPerNotification is the most updates of a notification sent by a Subscribe with mode
+	// ONCE or POLL, the updates of a path beyond it being sent with several; unlimited if 0
+	MaxUpdatesPerNotification int
+	// MaxGetResponseBytes is the largest Get response, in bytes of its protobuf encoding, that is not
+	// paged; a larger one fails the Get. Unlimited if 0.
+	MaxGetResponseBytes int
 }
 
 // Register registers the GNMI server with grpc
 func (s Service) Register(r *grpc.Server) {
-	gnmi.RegisterGNMIServer(r, &Server{})
+	gnmi.RegisterGNMIServer(r, &Server{
+		squashChanges:             s.SquashChanges,
+		recordNoOpSets:            s.RecordNoOpSets,
+		validationLevel:           s.ValidationLevel,
+		atomicity:                 s.Atomicity,
+		maxUpdatesPerNotification: s.MaxUpdatesPerNotification,
+		maxGetResponseBytes:       s.MaxGetResponseBytes,
+	})
 }
 
 // Server implements the grpc GNMI service
 type Server struct {
-	mu        sync.RWMutex
-	lastWrite networkchange.Revision
+	mu              sync.RWMutex
+	lastWrite       networkchange.Revision
+	squashChanges   bool
+	recordNoOpSets  bool
+	validationLevel ValidationLevel
+	atomicity       atomicity.Mode
+	// maxUpdatesPerNotification and maxGetResponseBytes keep the responses of large trees within
+	// the message limits of gRPC, see Service
+	maxUpdatesPerNotification int
+	maxGetResponseBytes       int
 }
 
 // Capabilities implements gNMI Capabilities
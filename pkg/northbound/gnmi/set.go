@@ -18,18 +18,27 @@ import (
 	"context"
 	"fmt"
 	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	types "github.com/onosproject/onos-api/go/onos/config"
 	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
 	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
 	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
 	"github.com/onosproject/onos-config/pkg/manager"
 	"github.com/onosproject/onos-config/pkg/modelregistry"
 	"github.com/onosproject/onos-config/pkg/modelregistry/jsonvalues"
+	"github.com/onosproject/onos-config/pkg/northbound/grpcerrors"
+	"github.com/onosproject/onos-config/pkg/protected"
+	"github.com/onosproject/onos-config/pkg/store/change/atomicity"
+	"github.com/onosproject/onos-config/pkg/store/change/confirmation"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
 	"github.com/onosproject/onos-config/pkg/store/device/cache"
 	"github.com/onosproject/onos-config/pkg/utils"
 	"github.com/onosproject/onos-config/pkg/utils/values"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/openconfig/gnmi/proto/gnmi_ext"
 	"google.golang.org/grpc/codes"
@@ -42,7 +51,9 @@ type mapTargetModels map[devicetype.ID]modelregistry.ReadWritePathMap
 
 // Set implements gNMI Set
 func (s *Server) Set(ctx context.Context, req *gnmi.SetRequest) (*gnmi.SetResponse, error) {
+	var user string
 	if md := metautils.ExtractIncoming(ctx); md != nil && md.Get("name") != "" {
+		user = md.Get("name")
 		log.Infof("gNMI Set() called by '%s (%s)'. Groups [%v]. Token %s",
 			md.Get("name"), md.Get("email"), md.Get("groups"), md.Get("at_hash"))
 		// TODO replace the following with fine grained RBAC using OpenPolicyAgent Regos
@@ -61,10 +72,58 @@ func (s *Server) Set(ctx context.Context, req *gnmi.SetRequest) (*gnmi.SetRespon
 	targetUpdates := make(mapTargetUpdates)
 	targetRemoves := make(mapTargetRemoves)
 	targetModels := make(mapTargetModels)
+	var writes setWrites
+	unknown := newUnknownPaths(manager.GetManager().AllowsUnknownPaths)
+	if s.squashChanges {
+		writes = make(setWrites)
+	}
 
 	netCfgChangeName, version, deviceType, err := extractExtensions(req)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
+	}
+
+	changeSignature, err := verifySignature(req, user)
+	if err != nil {
+		return nil, err
+	}
+
+	changeProvenance, err := extractProvenance(req, user)
+	if err != nil {
+		return nil, err
+	}
+
+	breakGlassReason, breakGlass, err := checkBreakGlass(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	forceReason, forceOwnership, err := checkForceOwnership(req)
+	if err != nil {
+		return nil, err
+	}
+
+	validationLevel, err := getValidationLevel(req, s.validationLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	changeAtomicity, err := getAtomicity(req, s.atomicity)
+	if err != nil {
+		return nil, err
+	}
+
+	dryRun := isDryRun(req)
+
+	confirmTimeout, err := getConfirmTimeout(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The paths of a vendor-neutral model are mapped to the native models of the targets
+	req, err = translateSetRequest(req, version, deviceType)
+	if err != nil {
+		return nil, err
 	}
 
 	log.Infof("gNMI Set Request %v", req)
@@ -85,7 +144,8 @@ func (s *Server) Set(ctx context.Context, req *gnmi.SetRequest) (*gnmi.SetRespon
 		if err != nil {
 			return nil, err
 		}
-		targetUpdates[target], err = s.formatUpdateOrReplace(req.GetPrefix(), u, targetUpdates, rwPaths)
+		targetUpdates[target], err = s.formatUpdateOrReplace(req.GetPrefix(), u, targetUpdates, rwPaths,
+			gnmi.UpdateResult_UPDATE, writes, validationLevel, unknown)
 		if err != nil {
 			return nil, err
 		}
@@ -101,10 +161,11 @@ func (s *Server) Set(ctx context.Context, req *gnmi.SetRequest) (*gnmi.SetRespon
 		if err != nil {
 			return nil, err
 		}
-		targetUpdates[target], err = s.formatUpdateOrReplace(req.GetPrefix(), u, targetUpdates, rwPaths)
+		targetUpdates[target], err = s.formatUpdateOrReplace(req.GetPrefix(), u, targetUpdates, rwPaths,
+			gnmi.UpdateResult_REPLACE, writes, validationLevel, unknown)
 		if err != nil {
 			log.Warn("Error in replace", err)
-			return nil, status.Error(codes.InvalidArgument, err.Error())
+			return nil, err
 		}
 	}
 
@@ -118,9 +179,18 @@ func (s *Server) Set(ctx context.Context, req *gnmi.SetRequest) (*gnmi.SetRespon
 		if err != nil {
 			return nil, err
 		}
-		targetRemoves[target], err = s.doDelete(req.GetPrefix(), u, targetRemoves, rwPaths)
+		targetRemoves[target], err = s.doDelete(req.GetPrefix(), u, targetRemoves, rwPaths, writes, validationLevel, unknown)
 		if err != nil {
-			return nil, fmt.Errorf("doDelete() %s", err.Error())
+			return nil, err
+		}
+	}
+
+	// Keep only the final value of each path in the change; the writes it replaced are audited
+	var targetSquashed map[devicetype.ID][]*squashedPath
+	if s.squashChanges {
+		targetUpdates, targetRemoves, targetSquashed = writes.squash()
+		if len(targetUpdates)+len(targetRemoves) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "no values to set in SetRequest")
 		}
 	}
 
@@ -140,7 +210,7 @@ func (s *Server) Set(ctx context.Context, req *gnmi.SetRequest) (*gnmi.SetRespon
 	for target, updates := range targetUpdates {
 		deviceType, version, err = mgr.CheckCacheForDevice(target, deviceType, version)
 		if err != nil {
-			return nil, status.Error(codes.InvalidArgument, err.Error())
+			return nil, targetError(err, target)
 		}
 		deviceInfo[target] = cache.Info{
 			DeviceID: target,
@@ -148,12 +218,29 @@ func (s *Server) Set(ctx context.Context, req *gnmi.SetRequest) (*gnmi.SetRespon
 			Version:  version,
 		}
 
+		// The values are normalized by the transformation rules before they are validated
+		updates, err = mgr.TransformConfig(deviceType, updates)
+		if err != nil {
+			return nil, targetError(err, target)
+		}
+		targetUpdates[target] = updates
+
+		// The leaves of the subtrees replaced wholesale that are not written are deleted
+		removes, errMerge := mgr.MergeConfig(target, deviceType, version, updates, targetRemoves[target], lastWrite)
+		if errMerge != nil {
+			return nil, targetError(errMerge, target)
+		}
+		if len(removes) > 0 {
+			targetRemoves[target] = removes
+		}
+
 		// TODO: Since the change has not been stored yet, we cannot guarantee the change will be validated against
 		//       the same state as will be pushed to the device. Changes must be validated after they're stored
 		//       to achieve this level of consistency.
-		err := validateChange(target, deviceType, version, updates, targetRemoves[target], lastWrite)
+		err := validateChange(target, deviceType, version, updates, targetRemoves[target], lastWrite, validationLevel,
+			unknown.allow(target))
 		if err != nil {
-			return nil, status.Error(codes.InvalidArgument, err.Error())
+			return nil, err
 		}
 		delete(targetRemovesTmp, target)
 	}
@@ -161,7 +248,7 @@ func (s *Server) Set(ctx context.Context, req *gnmi.SetRequest) (*gnmi.SetRespon
 	for target, removes := range targetRemovesTmp {
 		deviceType, version, err = mgr.CheckCacheForDevice(target, deviceType, version)
 		if err != nil {
-			return nil, status.Error(codes.InvalidArgument, err.Error())
+			return nil, targetError(err, target)
 		}
 		deviceInfo[target] = cache.Info{
 			DeviceID: target,
@@ -172,17 +259,139 @@ func (s *Server) Set(ctx context.Context, req *gnmi.SetRequest) (*gnmi.SetRespon
 		// TODO: Since the change has not been stored yet, we cannot guarantee the change will be validated against
 		//       the same state as will be pushed to the device. Changes must be validated after they're stored
 		//       to achieve this level of consistency.
-		err := validateChange(target, deviceType, version, make(devicechange.TypedValueMap), removes, lastWrite)
+		err := validateChange(target, deviceType, version, make(devicechange.TypedValueMap), removes, lastWrite, validationLevel,
+			unknown.allow(target))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Protected subtrees may only be changed by the callers allowed to, or by breaking the glass
+	targetProtected, err := checkProtected(ctx, protected.GetRegistry(), targetUpdates, targetRemoves, breakGlass)
+	if err != nil {
+		return nil, err
+	}
+
+	// Subtrees claimed by other owners may only be changed by forcing the change, or by breaking the glass
+	targetOwned, err := checkOwnership(mgr.OwnershipStore, user, targetUpdates, targetRemoves, forceOwnership || breakGlass)
+	if err != nil {
+		return nil, err
+	}
+	if !forceOwnership {
+		forceReason = breakGlassReason
+	}
+
+	// A Set that leaves the intended configuration as it is creates no change, unless such
+	// changes are recorded
+	noOp, err := mgr.IsNoOpNetworkConfig(targetUpdates, targetRemoves, deviceInfo, lastWrite)
+	if err != nil {
+		return nil, grpcerrors.Err(err)
+	}
+
+	// The external validators are asked about the change before it is made, or about a dry run
+	if dryRun || !noOp || s.recordNoOpSets {
+		if err := validateExternally(ctx, mgr, user, dryRun, targetUpdates, targetRemoves, deviceInfo, lastWrite); err != nil {
+			return nil, grpcerrors.Err(err)
+		}
+	}
+
+	// A dry run stops once the request is known to be valid, before anything is stored
+	if dryRun {
+		log.Infof("gNMI Set Request is valid, no network change created in a dry run")
+		return buildDryRunResponse(targetUpdates, targetRemoves, noOp, unknown), nil
+	}
+	if noOp && !s.recordNoOpSets {
+		log.Infof("gNMI Set Request changes nothing, no network change created")
+		if breakGlass {
+			auditBreakGlass(user, "", breakGlassReason, targetUpdates, targetRemoves)
+		}
+		noOpExtensions := []*gnmi_ext.Extension{noOpExtension()}
+		if ext := unknown.extension(); ext != nil {
+			noOpExtensions = append(noOpExtensions, ext)
+		}
+		return &gnmi.SetResponse{
+			Response:  buildNoOpResults(targetUpdates, targetRemoves),
+			Timestamp: time.Now().Unix(),
+			Extension: noOpExtensions,
+		}, nil
+	}
+
+	// The records stored for the change before it is created are deleted if it cannot be created,
+	// the last stored first. Only the records this Set stored are deleted.
+	var undo []func()
+	defer func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+	}()
+
+	// The signature is stored before the change, so that a signed change is never created
+	// without its signature and a replayed request never creates a second change
+	if changeSignature != nil {
+		if netCfgChangeName == "" {
+			netCfgChangeName = types.NewUUID().String()
+		}
+		changeID := networkchange.ID(netCfgChangeName)
+		if err := storeSignature(mgr.SignatureStore, changeID, changeSignature); err != nil {
+			return nil, err
+		}
+		undo = append(undo, func() { deleteSignature(mgr.SignatureStore, changeID) })
+	}
+
+	// So is the provenance, so that the values of the change are always traced to their artifact
+	if changeProvenance != nil {
+		if netCfgChangeName == "" {
+			netCfgChangeName = types.NewUUID().String()
+		}
+		changeID := networkchange.ID(netCfgChangeName)
+		if err := storeProvenance(mgr.ProvenanceStore, changeID, changeProvenance); err != nil {
+			return nil, err
+		}
+		undo = append(undo, func() { deleteProvenance(mgr.ProvenanceStore, changeID) })
+	}
+
+	// And the atomicity mode, for the change never to be rolled back before its mode is known. Only
+	// the changes that are not all-or-nothing have their mode stored.
+	if changeAtomicity == atomicity.ModeBestEffort {
+		if netCfgChangeName == "" {
+			netCfgChangeName = types.NewUUID().String()
+		}
+		changeID := networkchange.ID(netCfgChangeName)
+		if err := storeAtomicity(mgr.AtomicityStore, changeID, changeAtomicity); err != nil {
+			return nil, err
+		}
+		undo = append(undo, func() { deleteAtomicity(mgr.AtomicityStore, changeID) })
+	}
+
+	// And the confirmation of a commit-confirmed change, for the change never to outlive its deadline
+	var awaited *confirmation.Confirmation
+	if confirmTimeout > 0 {
+		if netCfgChangeName == "" {
+			netCfgChangeName = types.NewUUID().String()
+		}
+		changeID := networkchange.ID(netCfgChangeName)
+		awaited, err = mgr.AwaitConfirmation(changeID, user, confirmTimeout)
 		if err != nil {
-			return nil, status.Error(codes.InvalidArgument, err.Error())
+			return nil, grpcerrors.Err(err)
 		}
+		undo = append(undo, func() { deleteConfirmation(mgr.ConfirmationStore, changeID) })
 	}
 
 	// Creating and setting the config on the atomix Store
 	change, errSet := mgr.SetNetworkConfig(targetUpdates, targetRemoves, deviceInfo, netCfgChangeName)
 	if errSet != nil {
 		log.Errorf("Error while setting config in atomix %s", errSet.Error())
-		return nil, status.Error(codes.Internal, errSet.Error())
+		return nil, grpcerrors.Err(errSet)
+	}
+	// The change is created with its records, which are kept
+	undo = nil
+
+	mgr.RecordEnvironment(change, string(validationLevel), unknown.allow)
+	auditSquashed(user, change.ID, targetSquashed)
+	auditProtected(user, change.ID, targetProtected)
+	auditOwnershipOverrides(user, change.ID, forceReason, targetOwned)
+	if breakGlass {
+		auditBreakGlass(user, change.ID, breakGlassReason, targetUpdates, targetRemoves)
 	}
 
 	// Store the highest known change index
@@ -225,6 +434,21 @@ func (s *Server) Set(ctx context.Context, req *gnmi.SetRequest) (*gnmi.SetRespon
 		},
 	}
 
+	if noOp {
+		extensions = append(extensions, noOpExtension())
+	}
+	if ext := unknown.extension(); ext != nil {
+		extensions = append(extensions, ext)
+	}
+	if awaited != nil {
+		extensions = append(extensions, confirmExtension(awaited))
+	}
+	if mgr.LatencyTracker != nil {
+		if ext := backlogExtension(mgr.LatencyTracker.Backlog(change)); ext != nil {
+			extensions = append(extensions, ext)
+		}
+	}
+
 	setResponse := &gnmi.SetResponse{
 		Response:  updateResults,
 		Timestamp: time.Now().Unix(),
@@ -241,10 +465,30 @@ func extractExtensions(req *gnmi.SetRequest) (string, devicetype.Version, device
 	for _, ext := range req.GetExtension() {
 		if ext.GetRegisteredExt().GetId() == GnmiExtensionNetwkChangeID {
 			netcfgchangename = string(ext.GetRegisteredExt().GetMsg())
+			if strings.HasPrefix(netcfgchangename, networkchangestore.AdoptedChangePrefix) {
+				return "", "", "", status.Errorf(codes.InvalidArgument, "change names starting with '%s' are reserved for adopted configurations",
+					networkchangestore.AdoptedChangePrefix)
+			}
 		} else if ext.GetRegisteredExt().GetId() == GnmiExtensionVersion {
 			version = string(ext.GetRegisteredExt().GetMsg())
 		} else if ext.GetRegisteredExt().GetId() == GnmiExtensionDeviceType {
 			deviceType = string(ext.GetRegisteredExt().GetMsg())
+		} else if ext.GetRegisteredExt().GetId() == GnmiExtensionSignature {
+			continue // verified separately, over the whole request
+		} else if ext.GetRegisteredExt().GetId() == GnmiExtensionBreakGlass {
+			continue // checked separately, against the groups of the caller
+		} else if ext.GetRegisteredExt().GetId() == GnmiExtensionValidationLevel {
+			continue // parsed separately, see getValidationLevel
+		} else if ext.GetRegisteredExt().GetId() == GnmiExtensionProvenance {
+			continue // parsed separately, see extractProvenance
+		} else if ext.GetRegisteredExt().GetId() == GnmiExtensionForceOwnership {
+			continue // checked separately, against the claims on the paths
+		} else if ext.GetRegisteredExt().GetId() == GnmiExtensionAtomicity {
+			continue // parsed separately, see getAtomicity
+		} else if ext.GetRegisteredExt().GetId() == GnmiExtensionDryRun {
+			continue // checked separately, see isDryRun
+		} else if ext.GetRegisteredExt().GetId() == GnmiExtensionConfirmTimeout {
+			continue // parsed separately, see getConfirmTimeout
 		} else {
 			return "", "", "", status.Error(codes.InvalidArgument, fmt.Errorf("unexpected extension %d = '%s' in Set()",
 				ext.GetRegisteredExt().GetId(), ext.GetRegisteredExt().GetMsg()).Error())
@@ -258,14 +502,17 @@ func extractExtensions(req *gnmi.SetRequest) (string, devicetype.Version, device
 // This deals with either a path and a value (simple case) or a path with
 // a JSON body which implies multiple paths and values.
 func (s *Server) formatUpdateOrReplace(prefix *gnmi.Path, u *gnmi.Update,
-	targetUpdates mapTargetUpdates, rwPaths modelregistry.ReadWritePathMap) (devicechange.TypedValueMap, error) {
+	targetUpdates mapTargetUpdates, rwPaths modelregistry.ReadWritePathMap,
+	op gnmi.UpdateResult_Operation, writes setWrites, level ValidationLevel, unknown *unknownPaths) (devicechange.TypedValueMap, error) {
 	target := devicetype.ID(u.Path.GetTarget())
 	if target == "" {
 		target = devicetype.ID(prefix.GetTarget())
 	}
 	prefixPath := utils.StrPath(prefix)
 	path := utils.StrPath(u.Path)
-	if prefixPath != "/" {
+	if prefixPath != "/" && path == "/" {
+		path = prefixPath
+	} else if prefixPath != "/" {
 		path = fmt.Sprintf("%s%s", prefixPath, path)
 	}
 
@@ -274,35 +521,67 @@ func (s *Server) formatUpdateOrReplace(prefix *gnmi.Path, u *gnmi.Update,
 		updates = make(devicechange.TypedValueMap)
 	}
 
+	// A JSON value holds a whole subtree, given as JSON or as JSON_IETF (RFC 7951)
 	jsonVal := u.GetVal().GetJsonVal()
+	if jsonVal == nil {
+		jsonVal = u.GetVal().GetJsonIetfVal()
+	}
 	if jsonVal != nil {
 		log.Infof("Processing Json Value in set from base %s: %s",
 			path, string(jsonVal))
 
-		pathValues, err := jsonvalues.DecomposeJSONWithPaths(path, jsonVal, nil, rwPaths)
+		jsonPath := path
+		if jsonPath == "/" {
+			jsonPath = ""
+		}
+		pathValues, err := jsonvalues.DecomposeJSONWithPaths(jsonPath, jsonVal, nil, rwPaths)
 		if err != nil {
 			log.Warnf("Json value in Set could not be parsed %v", err)
-			return nil, status.Error(codes.InvalidArgument, err.Error())
+			return nil, invalidPath(err, path)
 		}
 		if len(pathValues) == 0 {
 			log.Warnf("no pathValues found for %s in %v", path, string(jsonVal))
 		}
+		keyPaths := make(map[string]bool)
 		for _, cv := range pathValues {
+			if rwPathElem, ok := rwPaths[modelregistry.AnonymizePathIndices(cv.Path)]; ok && !rwPathElem.IsAKey {
+				cv.Value = values.NormalizeValue(rwPathElem.TypeName, cv.GetValue())
+			}
 			updates[cv.Path] = cv.GetValue()
+			writes.add(target, op, cv.Path, cv.GetValue())
+			keyValues, err := listKeyValues(cv.Path, rwPaths)
+			if err != nil {
+				return nil, err
+			}
+			for _, kv := range keyValues {
+				if !keyPaths[kv.Path] {
+					keyPaths[kv.Path] = true
+					updates[kv.Path] = kv.GetValue()
+					writes.add(target, op, kv.Path, kv.GetValue())
+				}
+			}
 		}
 	} else {
 		_, rwPathElem, err := findPathFromModel(path, rwPaths, true)
-		if err != nil {
-			return nil, err
+		if err != nil && unknown.allow(target) {
+			unknown.add(target, path)
+		} else if err != nil && level.checksSchema() {
+			return nil, invalidPath(err, path)
 		}
 		updateValue, err := values.GnmiTypedValueToNativeType(u.Val, rwPathElem)
 		if err != nil {
-			return nil, err
+			return nil, invalidPath(err, path)
 		}
-		if err = checkKeyValue(path, rwPathElem, updateValue); err != nil {
-			return nil, err
+		if rwPathElem != nil {
+			if !rwPathElem.IsAKey {
+				updateValue = values.NormalizeValue(rwPathElem.TypeName, updateValue)
+			}
+			if err = checkKeyValue(path, rwPathElem, updateValue); err != nil {
+				return nil, invalidPath(err, path)
+			}
 		}
 		updates[path] = updateValue
+		writes.add(target, op, path, updateValue)
 	}
 
 	return updates, nil
@@ -310,7 +589,8 @@ func (s *Server) formatUpdateOrReplace(prefix *gnmi.Path, u *gnmi.Update,
 }
 
 func (s *Server) doDelete(prefix *gnmi.Path, u *gnmi.Path,
-	targetRemoves mapTargetRemoves, rwPaths modelregistry.ReadWritePathMap) ([]string, error) {
+	targetRemoves mapTargetRemoves, rwPaths modelregistry.ReadWritePathMap, writes setWrites, level ValidationLevel,
+	unknown *unknownPaths) ([]string, error) {
 
 	target := devicetype.ID(u.GetTarget())
 	if target == "" {
@@ -327,16 +607,60 @@ func (s *Server) doDelete(prefix *gnmi.Path, u *gnmi.Path,
 	}
 	// Checks for read only paths
 	isExactMatch, rwPath, err := findPathFromModel(path, rwPaths, false)
-	if err != nil {
-		return nil, err
+	if err != nil && unknown.allow(target) {
+		unknown.add(target, path)
+	} else if err != nil && level.checksSchema() {
+		return nil, invalidPath(err, path)
 	}
 	if isExactMatch && rwPath.IsAKey && !strings.HasSuffix(path, "]") { // In case an index attribute is given - take it off
 		path = path[:strings.LastIndex(path, "/")]
 	}
 	deletes = append(deletes, path)
+	writes.add(target, gnmi.UpdateResult_DELETE, path, nil)
 	return deletes, nil
 }
 
+// buildNoOpResults builds the results of a Set that created no change, sorted by target and path
+func buildNoOpResults(targetUpdates mapTargetUpdates, targetRemoves mapTargetRemoves) []*gnmi.UpdateResult {
+	results := make([]*gnmi.UpdateResult, 0)
+	addResult := func(path string, target devicetype.ID, op gnmi.UpdateResult_Operation) {
+		result, err := buildUpdateResult(path, string(target), op)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		results = append(results, result)
+	}
+	for target, updates := range targetUpdates {
+		for path := range updates {
+			addResult(path, target, gnmi.UpdateResult_UPDATE)
+		}
+	}
+	for target, removes := range targetRemoves {
+		for _, path := range removes {
+			addResult(path, target, gnmi.UpdateResult_DELETE)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Path.Target != results[j].Path.Target {
+			return results[i].Path.Target < results[j].Path.Target
+		}
+		return utils.StrPath(results[i].Path) < utils.StrPath(results[j].Path)
+	})
+	return results
+}
+
+// noOpExtension flags a Set response as changing nothing
+func noOpExtension() *gnmi_ext.Extension {
+	return &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id: GnmiExtensionNoOp,
+			},
+		},
+	}
+}
+
 func buildUpdateResult(pathStr string, target string, op gnmi.UpdateResult_Operation) (*gnmi.UpdateResult, error) {
 	path, errInPath := utils.ParseGNMIElements(utils.SplitPath(pathStr))
 	if errInPath != nil {
@@ -353,15 +677,25 @@ func buildUpdateResult(pathStr string, target string, op gnmi.UpdateResult_Opera
 }
 
 func validateChange(target devicetype.ID, deviceType devicetype.Type, version devicetype.Version,
-	targetUpdates devicechange.TypedValueMap, targetRemoves []string, lastWrite networkchange.Revision) error {
+	targetUpdates devicechange.TypedValueMap, targetRemoves []string, lastWrite networkchange.Revision,
+	level ValidationLevel, unknownPaths bool) error {
 	if len(targetUpdates) == 0 && len(targetRemoves) == 0 {
 		return status.Errorf(codes.InvalidArgument, "no updates found in change on %s - invalid", target)
 	}
+	if !level.checksConstraints() {
+		log.Infof("Not validating change %s:%s:%s against the constraints of the model: validation is %s",
+			target, deviceType, version, level)
+		return nil
+	}
 	log.Infof("Validating change %s:%s:%s", target, deviceType, version)
-	errValidation := manager.GetManager().ValidateNetworkConfig(target, version, deviceType,
-		targetUpdates, targetRemoves, lastWrite)
+	validate := manager.GetManager().ValidateNetworkConfig
+	if unknownPaths {
+		// The paths that are not in the model are pushed to the device unvalidated
+		validate = manager.GetManager().ValidateModeledNetworkConfig
+	}
+	errValidation := validate(target, version, deviceType, targetUpdates, targetRemoves, lastWrite)
 	if errValidation != nil {
-		return status.Error(codes.InvalidArgument, errValidation.Error())
+		return targetError(errValidation, target)
 	}
 	log.Infof("Validating change %s:%s:%s DONE", target, deviceType, version)
 	return nil
@@ -380,12 +714,12 @@ func extractModelForTarget(target devicetype.ID,
 
 	actualType, actualVersion, err := manager.GetManager().CheckCacheForDevice(target, ext102Type, ext101Version)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, targetError(err, target)
 	}
 	modelName := utils.ToModelName(actualType, actualVersion)
 	plugin, err := manager.GetManager().ModelRegistry.GetPlugin(modelName)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, targetError(err, target)
 	}
 	return plugin.ReadWritePaths, nil
 }
@@ -425,6 +759,63 @@ func findPathFromModel(path string, rwPaths modelregistry.ReadWritePathMap, exac
 }
 
 // Check that if this is a Key attribute, that the value is the same as its parent's key
+// listKeyValues gives the values of the key leaves of the list entries along a path, e.g.
+// /cont1a/list2a[name=first]/name for /cont1a/list2a[name=first]/tx-power. A JSON value
+// only carries the keys of a list entry as the indices of the paths of its other leaves.
+func listKeyValues(path string, rwPaths modelregistry.ReadWritePathMap) ([]*devicechange.PathValue, error) {
+	keyValues := make([]*devicechange.PathValue, 0)
+	elems := utils.SplitPath(path)
+	for i, elem := range elems[:len(elems)-1] {
+		indexNames, indexValues := modelregistry.ExtractIndexNames(elem)
+		parentPath := "/" + strings.Join(elems[:i+1], "/")
+		for j, indexName := range indexNames {
+			keyPath := fmt.Sprintf("%s/%s", parentPath, indexName)
+			rwPath, ok := rwPaths[modelregistry.AnonymizePathIndices(keyPath)]
+			if !ok || !rwPath.IsAKey {
+				continue
+			}
+			keyValue, err := typedKeyValue(indexValues[j], &rwPath)
+			if err != nil {
+				return nil, invalidPath(status.Errorf(codes.InvalidArgument, "%s: %v", keyPath, err), keyPath)
+			}
+			keyValues = append(keyValues, &devicechange.PathValue{Path: keyPath, Value: keyValue})
+		}
+	}
+	return keyValues, nil
+}
+
+// typedKeyValue converts the value of an index in a path to the type of its key leaf
+func typedKeyValue(value string, rwPath *modelregistry.ReadWritePathElem) (*devicechange.TypedValue, error) {
+	width := devicechange.WidthThirtyTwo
+	if len(rwPath.TypeOpts) > 0 {
+		width = devicechange.Width(rwPath.TypeOpts[0])
+	}
+	switch rwPath.ValueType {
+	case devicechange.ValueType_STRING:
+		return devicechange.NewTypedValueString(value), nil
+	case devicechange.ValueType_INT:
+		intVal, err := strconv.ParseInt(value, 10, int(width))
+		if err != nil {
+			return nil, err
+		}
+		return devicechange.NewTypedValueInt(int(intVal), width), nil
+	case devicechange.ValueType_UINT:
+		uintVal, err := strconv.ParseUint(value, 10, int(width))
+		if err != nil {
+			return nil, err
+		}
+		return devicechange.NewTypedValueUint(uint(uintVal), width), nil
+	case devicechange.ValueType_BOOL:
+		boolVal, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, err
+		}
+		return devicechange.NewTypedValueBool(boolVal), nil
+	default:
+		return nil, fmt.Errorf("unhandled key of type %v", rwPath.ValueType)
+	}
+}
+
 func checkKeyValue(path string, rwPath *modelregistry.ReadWritePathElem, val *devicechange.TypedValue) error {
 	indexNames, indexValues := modelregistry.ExtractIndexNames(path)
 	if len(indexNames) == 0 {
@@ -440,3 +831,18 @@ func checkKeyValue(path string, rwPath *modelregistry.ReadWritePathElem, val *de
 	}
 	return status.Errorf(codes.InvalidArgument, "index attribute %s=%s does not match %s", rwPath.AttrName, val.ValueToString(), path)
 }
+
+// targetError returns the status of an error about a target of the request, naming it in the
+// details of the status
+func targetError(err error, target devicetype.ID) error {
+	return grpcerrors.Err(grpcerrors.WithMetadata(err, grpcerrors.TargetKey, string(target)))
+}
+
+// invalidPath returns the status of an error about a path of the request, naming it in the details
+// of the status. Errors that are not typed already are taken for the path being invalid.
+func invalidPath(err error, path string) error {
+	if _, ok := status.FromError(err); !ok && errors.TypeOf(err) == errors.Unknown {
+		err = errors.NewInvalid(err.Error())
+	}
+	return grpcerrors.Err(grpcerrors.WithPath(err, path))
+}
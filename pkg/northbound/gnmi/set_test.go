@@ -16,21 +16,30 @@ package gnmi
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/golang/mock/gomock"
+	td1 "github.com/onosproject/config-models/modelplugin/testdevice-1.0.0/testdevice_1_0_0"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
 	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
 	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
 	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/modelregistry"
 	"github.com/onosproject/onos-config/pkg/store/device/cache"
 	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-config/pkg/validators"
 	"github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"github.com/openconfig/goyang/pkg/yang"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 const (
@@ -136,6 +145,152 @@ func Test_doSingleSet(t *testing.T) {
 	assert.Equal(t, string(extensionChgID.Msg), "TestChange")
 }
 
+// Test_doSingleSetDryRun validates a Set without creating a network change
+func Test_doSingleSetDryRun(t *testing.T) {
+	server, mocks, _ := setUpForGetSetTests(t)
+	setUpChangesMock(mocks)
+
+	pathElemsRefs, _ := utils.ParseGNMIElements([]string{"cont1a", "cont2a", "leaf2a"})
+	dryRun := &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id: GnmiExtensionDryRun,
+			},
+		},
+	}
+	setRequest := &gnmi.SetRequest{
+		Update: []*gnmi.Update{{
+			Path: &gnmi.Path{Elem: pathElemsRefs.Elem, Target: "Device1"},
+			Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 11}},
+		}},
+		Extension: []*gnmi_ext.Extension{dryRun},
+	}
+
+	setResponse, err := server.Set(context.Background(), setRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(setResponse.Response))
+	assert.Equal(t, gnmi.UpdateResult_UPDATE, setResponse.Response[0].Op)
+	assert.Equal(t, "Device1", setResponse.Response[0].Path.Target)
+	assert.Equal(t, "/cont1a/cont2a/leaf2a", utils.StrPath(setResponse.Response[0].Path))
+	// The dry run is flagged, and no network change ID is given
+	assert.Equal(t, 1, len(setResponse.Extension))
+	assert.Equal(t, gnmi_ext.ExtensionID(GnmiExtensionDryRun), setResponse.Extension[0].GetRegisteredExt().Id)
+
+	// An invalid request fails as it would without the dry run
+	setRequest.Update[0].Val = &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "eleven"}}
+	_, err = server.Set(context.Background(), setRequest)
+	assert.Error(t, err)
+}
+
+func Test_doSingleSetConfirmed(t *testing.T) {
+	server, mocks, mgr := setUpForGetSetTests(t)
+	setUpChangesMock(mocks)
+
+	pathElemsRefs, _ := utils.ParseGNMIElements([]string{"cont1a", "cont2a", "leaf2a"})
+	confirmTimeout := &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  GnmiExtensionConfirmTimeout,
+				Msg: []byte("5m"),
+			},
+		},
+	}
+	changeName := &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  GnmiExtensionNetwkChangeID,
+				Msg: []byte("ConfirmedChange"),
+			},
+		},
+	}
+	setRequest := &gnmi.SetRequest{
+		Update: []*gnmi.Update{{
+			Path: &gnmi.Path{Elem: pathElemsRefs.Elem, Target: "Device1"},
+			Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 11}},
+		}},
+		Extension: []*gnmi_ext.Extension{changeName, confirmTimeout},
+	}
+
+	before := time.Now().Add(5 * time.Minute).Truncate(time.Second)
+	setResponse, err := server.Set(context.Background(), setRequest)
+	assert.NoError(t, err)
+	// The deadline of the confirmation is given with extension 116
+	assert.Equal(t, 2, len(setResponse.Extension))
+	assert.Equal(t, gnmi_ext.ExtensionID(GnmiExtensionConfirmTimeout), setResponse.Extension[1].GetRegisteredExt().Id)
+	deadline, err := time.Parse(time.RFC3339, string(setResponse.Extension[1].GetRegisteredExt().Msg))
+	assert.NoError(t, err)
+	assert.False(t, deadline.Before(before))
+
+	awaited, err := mgr.ConfirmationStore.Get("ConfirmedChange")
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, awaited.Timeout)
+
+	// Replaying the Set neither pushes back the deadline nor drops the awaited confirmation
+	confirmTimeout.GetRegisteredExt().Msg = []byte("10m")
+	_, err = server.Set(context.Background(), setRequest)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	awaited, err = mgr.ConfirmationStore.Get("ConfirmedChange")
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, awaited.Timeout)
+
+	// The timeout is bounded
+	confirmTimeout.GetRegisteredExt().Msg = []byte("1s")
+	_, err = server.Set(context.Background(), setRequest)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	confirmTimeout.GetRegisteredExt().Msg = []byte("soon")
+	_, err = server.Set(context.Background(), setRequest)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func Test_doSingleSetExternallyValidated(t *testing.T) {
+	server, mocks, _ := setUpForGetSetTests(t)
+	setUpChangesMock(mocks)
+
+	var asked []*validators.Proposal
+	validator := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proposal := &validators.Proposal{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(proposal))
+		asked = append(asked, proposal)
+		assert.NoError(t, json.NewEncoder(w).Encode(&validators.Verdict{Reasons: []string{"leaf2a too high"}}))
+	}))
+	defer validator.Close()
+	assert.NoError(t, validators.GetRegistry().Set(&validators.Config{Validators: []*validators.Validator{
+		{Name: "test", URL: validator.URL},
+	}}))
+	defer func() {
+		assert.NoError(t, validators.GetRegistry().Set(&validators.Config{}))
+	}()
+
+	pathElemsRefs, _ := utils.ParseGNMIElements([]string{"cont1a", "cont2a", "leaf2a"})
+	setRequest := &gnmi.SetRequest{
+		Update: []*gnmi.Update{{
+			Path: &gnmi.Path{Elem: pathElemsRefs.Elem, Target: "Device1"},
+			Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 11}},
+		}},
+	}
+
+	// A change the validator rejects is not made
+	_, err := server.Set(context.Background(), setRequest)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected invalid argument, got %v", err)
+	assert.Contains(t, err.Error(), "leaf2a too high")
+	assert.Len(t, asked, 1)
+	assert.False(t, asked[0].DryRun)
+	assert.Len(t, asked[0].Devices, 1)
+	assert.Equal(t, "/cont1a/cont2a/leaf2a", asked[0].Devices[0].Paths[0].Path)
+	assert.Equal(t, "11", *asked[0].Devices[0].Paths[0].After)
+
+	// Nor is a dry run of it valid
+	setRequest.Extension = []*gnmi_ext.Extension{{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{Id: GnmiExtensionDryRun},
+		},
+	}}
+	_, err = server.Set(context.Background(), setRequest)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "expected invalid argument, got %v", err)
+	assert.Len(t, asked, 2)
+	assert.True(t, asked[1].DryRun)
+}
+
 // Test_doSingleSetEmptyString deals with setting an empty value on a string
 func Test_doSingleSetEmptyString(t *testing.T) {
 	server, mocks, _ := setUpForGetSetTests(t)
@@ -166,7 +321,7 @@ func Test_doSingleSetEmptyString(t *testing.T) {
 
 	_, setError := server.Set(context.Background(), &setRequest)
 	assert.EqualError(t, setError,
-		`rpc error: code = InvalidArgument desc = rpc error: code = InvalidArgument desc = Empty string not allowed. Delete attribute instead. /cont1a/leaf1a`)
+		`rpc error: code = InvalidArgument desc = Empty string not allowed. Delete attribute instead. /cont1a/leaf1a`)
 }
 
 // Test_doSingleSet shows list within a list with leafref keys and double key
@@ -223,6 +378,46 @@ func Test_doSingleSetList(t *testing.T) {
 	}
 }
 
+// Test_doSingleSetJSONIetf shows how a subtree given as JSON_IETF is set as its leaves
+func Test_doSingleSetJSONIetf(t *testing.T) {
+	server, mocks, _ := setUpForGetSetTests(t)
+	setUpChangesMock(mocks)
+
+	prefixElemsRefs, _ := utils.ParseGNMIElements(utils.SplitPath("/cont1a"))
+	prefix := &gnmi.Path{Elem: prefixElemsRefs.Elem, Target: "Device1"}
+	jsonValue := gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{
+  "test1:cont2a": {"leaf2a": 12},
+  "test1:list2a": [{"name": "second", "tx-power": 7}]
+}`)}}
+
+	var setRequest = gnmi.SetRequest{
+		Prefix: prefix,
+		Update: []*gnmi.Update{{Path: &gnmi.Path{}, Val: &jsonValue}},
+	}
+
+	setResponse, setError := server.Set(context.Background(), &setRequest)
+	assert.NoError(t, setError)
+	assert.Equal(t, 3, len(setResponse.Response))
+	for _, resp := range setResponse.Response {
+		switch path := strings.ReplaceAll(resp.Path.String(), "  ", " "); path {
+		case
+			`elem:{name:"cont1a"} elem:{name:"cont2a"} elem:{name:"leaf2a"} target:"Device1"`,
+			`elem:{name:"cont1a"} elem:{name:"list2a" key:{key:"name" value:"second"}} elem:{name:"name"} target:"Device1"`,
+			`elem:{name:"cont1a"} elem:{name:"list2a" key:{key:"name" value:"second"}} elem:{name:"tx-power"} target:"Device1"`:
+			assert.Equal(t, resp.GetOp().String(), gnmi.UpdateResult_UPDATE.String())
+		default:
+			t.Errorf("unexpected path %s", path)
+		}
+	}
+
+	badValue := gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{"cont2a": {"leaf2a": "twelve"}}`)}}
+	_, setError = server.Set(context.Background(), &gnmi.SetRequest{
+		Prefix: prefix,
+		Update: []*gnmi.Update{{Path: &gnmi.Path{}, Val: &badValue}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(setError))
+}
+
 // Test_doSingleSet shows how a value of 1 list can be set on a target - using prefix
 func Test_doSingleSetListIndexInvalid(t *testing.T) {
 	server, mocks, _ := setUpForGetSetTests(t)
@@ -878,7 +1073,7 @@ func Test_doSingleSetListInvalidLeafRef(t *testing.T) {
 	}
 
 	setResponse, setError := server.Set(context.Background(), &setRequest)
-	assert.Contains(t, setError.Error(), `rpc error: code = InvalidArgument desc = validation error field name Id value second (string ptr) schema path /device/cont1a/list4/id has leafref path /cont1a/list2a/name not equal to any target nodes`)
+	assert.Contains(t, setError.Error(), `rpc error: code = InvalidArgument desc = validation error at /cont1a/list4[id=second]/id: field name Id value second (string ptr) schema path /device/cont1a/list4/id has leafref path /cont1a/list2a/name not equal to any target nodes`)
 	assert.Nil(t, setResponse)
 }
 
@@ -942,7 +1137,7 @@ func Test_doDeleteOfReferencedEntryFromListInvalid(t *testing.T) {
 	}
 
 	setResponse, setError := server.Set(context.Background(), &setRequest)
-	assert.Contains(t, setError.Error(), "rpc error: code = InvalidArgument desc = validation error pointed-to value with path /cont1a/list2a/name from field Id value first (string ptr) schema /device/cont1a/list4/id is empty set")
+	assert.Contains(t, setError.Error(), "rpc error: code = InvalidArgument desc = validation error at /cont1a/list4[id=first]/id: pointed-to value with path /cont1a/list2a/name from field Id value first (string ptr) schema /device/cont1a/list4/id is empty set")
 	assert.Nil(t, setResponse)
 }
 
@@ -1044,3 +1239,72 @@ func Test_deleteReferencedContainerList(t *testing.T) {
 	assert.Errorf(t, setError, "Expecting error as /cont1a/cont2 is used as a leafref")
 	assert.Nil(t, setResponse)
 }
+
+// Test_extractExtensionsReservedName tests that a Set cannot pose as an adopted configuration
+func Test_extractExtensionsReservedName(t *testing.T) {
+	req := &gnmi.SetRequest{
+		Extension: []*gnmi_ext.Extension{{
+			Ext: &gnmi_ext.Extension_RegisteredExt{
+				RegisteredExt: &gnmi_ext.RegisteredExtension{
+					Id:  GnmiExtensionNetwkChangeID,
+					Msg: []byte("adopted-Device1-1_0_0"),
+				},
+			},
+		}},
+	}
+	_, _, _, err := extractExtensions(req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// Test_buildNoOpResults tests the results of a Set that changed nothing are sorted by target and path
+func Test_buildNoOpResults(t *testing.T) {
+	targetUpdates := mapTargetUpdates{
+		"device2": devicechange.TypedValueMap{"/cont1a/leaf1a": devicechange.NewTypedValueString("a")},
+		device1: devicechange.TypedValueMap{
+			"/cont1a/leaf1b": devicechange.NewTypedValueString("b"),
+			"/cont1a/leaf1a": devicechange.NewTypedValueString("a"),
+		},
+	}
+	targetRemoves := mapTargetRemoves{device1: {"/cont1a/leaf1c"}}
+
+	results := buildNoOpResults(targetUpdates, targetRemoves)
+	assert.Len(t, results, 4)
+	assert.Equal(t, device1, results[0].Path.Target)
+	assert.Equal(t, "/cont1a/leaf1a", utils.StrPath(results[0].Path))
+	assert.Equal(t, gnmi.UpdateResult_UPDATE, results[0].Op)
+	assert.Equal(t, "/cont1a/leaf1b", utils.StrPath(results[1].Path))
+	assert.Equal(t, "/cont1a/leaf1c", utils.StrPath(results[2].Path))
+	assert.Equal(t, gnmi.UpdateResult_DELETE, results[2].Op)
+	assert.Equal(t, "device2", results[3].Path.Target)
+	assert.Equal(t, gnmi_ext.ExtensionID(GnmiExtensionNoOp), noOpExtension().GetRegisteredExt().Id)
+}
+
+func Test_listKeyValues(t *testing.T) {
+	modelSchema, err := td1.UnzipSchema()
+	assert.NoError(t, err)
+	_, rwPaths := modelregistry.ExtractPaths(modelSchema["Device"], yang.TSUnset, "", "")
+
+	keyValues, err := listKeyValues("/cont1a/list4[id=first]/list4a[fkey1=abc][fkey2=8]/displayname", rwPaths)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(keyValues))
+	assert.Equal(t, "/cont1a/list4[id=first]/id", keyValues[0].Path)
+	assert.Equal(t, "first", keyValues[0].GetValue().ValueToString())
+	assert.Equal(t, "/cont1a/list4[id=first]/list4a[fkey1=abc][fkey2=8]/fkey1", keyValues[1].Path)
+	assert.Equal(t, "abc", keyValues[1].GetValue().ValueToString())
+	assert.Equal(t, "/cont1a/list4[id=first]/list4a[fkey1=abc][fkey2=8]/fkey2", keyValues[2].Path)
+	assert.Equal(t, "8", keyValues[2].GetValue().ValueToString())
+
+	keyValues, err = listKeyValues("/cont1a/list5[key1=abc][key2=8]/leaf5a", rwPaths)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(keyValues))
+	assert.Equal(t, "/cont1a/list5[key1=abc][key2=8]/key2", keyValues[1].Path)
+	assert.Equal(t, devicechange.ValueType_UINT, keyValues[1].GetValue().GetType())
+	assert.Equal(t, "8", keyValues[1].GetValue().ValueToString())
+
+	keyValues, err = listKeyValues("/cont1a/cont2a/leaf2a", rwPaths)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(keyValues))
+
+	_, err = listKeyValues("/cont1a/list5[key1=abc][key2=many]/leaf5a", rwPaths)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,127 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"fmt"
+	"time"
+
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/signing"
+	"github.com/onosproject/onos-config/pkg/store/change/signature"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// auditSignedChange is the audit action recorded for signed Set requests
+const auditSignedChange = "signed-change"
+
+// verifySignature checks the signature extension of a SetRequest against the registered keys.
+// It returns a nil signature if the request is not signed and signatures are not required.
+func verifySignature(req *gnmi.SetRequest, user string) (*signature.ChangeSignature, error) {
+	registry := signing.GetKeyRegistry()
+	var sig *signing.Signature
+	for _, ext := range req.GetExtension() {
+		if ext.GetRegisteredExt().GetId() == GnmiExtensionSignature {
+			if sig != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "extension %d must only be given once", GnmiExtensionSignature)
+			}
+			var err error
+			if sig, err = signing.ParseSignature(ext.GetRegisteredExt().GetMsg()); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+	}
+	if sig == nil {
+		if registry.IsRequired() {
+			audit.Record(audit.Entry{
+				User:    user,
+				Action:  auditSignedChange,
+				Message: "rejected: unsigned SetRequest",
+			})
+			return nil, status.Errorf(codes.PermissionDenied, "SetRequest must be signed with extension %d", GnmiExtensionSignature)
+		}
+		return nil, nil
+	}
+
+	if err := sig.CheckTimestamp(time.Now()); err != nil {
+		audit.Record(audit.Entry{
+			User:    user,
+			Action:  auditSignedChange,
+			Message: fmt.Sprintf("rejected: key '%s': %v", sig.KeyID, err),
+		})
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	canonical, err := signing.Canonicalize(req, GnmiExtensionSignature)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	payload := signing.Payload(sig, canonical)
+	if err := registry.Verify(sig, payload); err != nil {
+		audit.Record(audit.Entry{
+			User:    user,
+			Action:  auditSignedChange,
+			Message: fmt.Sprintf("rejected: key '%s' verification failed: %v", sig.KeyID, err),
+		})
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	return &signature.ChangeSignature{
+		KeyID:     sig.KeyID,
+		Timestamp: sig.Timestamp,
+		Nonce:     sig.Nonce,
+		Signature: sig.Value,
+		Digest:    signing.Digest(payload),
+		User:      user,
+		Created:   time.Now(),
+	}, nil
+}
+
+// storeSignature stores the verified signature for the network change it is about to create.
+// The Set must fail if the signature cannot be stored; a replayed request fails with AlreadyExists.
+func storeSignature(store signature.Store, changeID networkchange.ID, sig *signature.ChangeSignature) error {
+	sig.ChangeID = changeID
+	if store == nil {
+		return status.Error(codes.Unavailable, "signed changes cannot be recorded: no signature store")
+	}
+	if err := store.Create(sig); err != nil {
+		audit.Record(audit.Entry{
+			User:    sig.User,
+			Action:  auditSignedChange,
+			Target:  string(changeID),
+			Message: fmt.Sprintf("rejected: key '%s' digest %x: %v", sig.KeyID, sig.Digest, err),
+		})
+		if errors.IsAlreadyExists(err) {
+			return status.Error(codes.AlreadyExists, err.Error())
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	audit.Record(audit.Entry{
+		User:    sig.User,
+		Action:  auditSignedChange,
+		Target:  string(changeID),
+		Message: fmt.Sprintf("verified with key '%s' digest %x", sig.KeyID, sig.Digest),
+	})
+	return nil
+}
+
+// deleteSignature deletes the stored signature of a network change that could not be created
+func deleteSignature(store signature.Store, changeID networkchange.ID) {
+	if err := store.Delete(changeID); err != nil {
+		log.Errorf("Unable to delete signature of change %s: %v", changeID, err)
+	}
+}
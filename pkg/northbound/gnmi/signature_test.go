@@ -0,0 +1,114 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-config/pkg/signing"
+	"github.com/onosproject/onos-config/pkg/store/change/signature"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// signRequest adds a signature extension to a SetRequest, signed at the given time
+func signRequest(t *testing.T, req *gnmi.SetRequest, private ed25519.PrivateKey, keyID string, signed time.Time) {
+	sig := &signing.Signature{KeyID: keyID, Timestamp: signed, Nonce: "nonce-0001"}
+	canonical, err := signing.Canonicalize(req, GnmiExtensionSignature)
+	assert.NoError(t, err)
+	sig.Value = ed25519.Sign(private, signing.Payload(sig, canonical))
+	req.Extension = append(req.Extension, &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  GnmiExtensionSignature,
+				Msg: []byte(sig.String()),
+			},
+		},
+	})
+}
+
+func signedTestRequest() *gnmi.SetRequest {
+	return &gnmi.SetRequest{
+		Update: []*gnmi.Update{
+			{
+				Path: &gnmi.Path{
+					Target: "device-1",
+					Elem:   []*gnmi.PathElem{{Name: "system"}, {Name: "config"}, {Name: "hostname"}},
+				},
+				Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "switch1"}},
+			},
+		},
+	}
+}
+
+func Test_VerifySignature(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	assert.NoError(t, signing.GetKeyRegistry().Register("test-verify-signature", public))
+
+	req := signedTestRequest()
+	signRequest(t, req, private, "test-verify-signature", time.Now())
+	sig, err := verifySignature(req, "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "test-verify-signature", sig.KeyID)
+	assert.Equal(t, "nonce-0001", sig.Nonce)
+	assert.Equal(t, "alice", sig.User)
+
+	tampered := signedTestRequest()
+	tampered.Update[0].Val = &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "switch2"}}
+	tampered.Extension = req.Extension
+	_, err = verifySignature(tampered, "alice")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	stale := signedTestRequest()
+	signRequest(t, stale, private, "test-verify-signature", time.Now().Add(-signing.MaxClockSkew-time.Minute))
+	_, err = verifySignature(stale, "alice")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	sig, err = verifySignature(signedTestRequest(), "alice")
+	assert.NoError(t, err)
+	assert.Nil(t, sig)
+}
+
+func Test_StoreSignature(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	assert.NoError(t, signing.GetKeyRegistry().Register("test-store-signature", public))
+
+	req := signedTestRequest()
+	signRequest(t, req, private, "test-store-signature", time.Now())
+	store := signature.NewLocalStore()
+
+	sig, err := verifySignature(req, "alice")
+	assert.NoError(t, err)
+	assert.NoError(t, storeSignature(store, "change-1", sig))
+	stored, err := store.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, sig.Digest, stored.Digest)
+
+	// The same signed request cannot create a second change
+	replay, err := verifySignature(req, "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, codes.AlreadyExists, status.Code(storeSignature(store, "change-2", replay)))
+
+	// Signed changes are refused when they cannot be recorded
+	assert.Equal(t, codes.Unavailable, status.Code(storeSignature(nil, "change-3", replay)))
+}
@@ -0,0 +1,139 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/secrets"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// auditSquashedChange is the audit action recording the writes squashed out of a change
+const auditSquashedChange = "squash-change"
+
+// setWrite is a write of a SetRequest to a path of a device
+type setWrite struct {
+	op    gnmi.UpdateResult_Operation
+	path  string
+	value *devicechange.TypedValue
+}
+
+// setWrites are the writes of a SetRequest to each device, in the order they were extracted
+type setWrites map[devicetype.ID][]*setWrite
+
+// add records a write; it does nothing on nil writes, i.e. when changes are not squashed
+func (w setWrites) add(target devicetype.ID, op gnmi.UpdateResult_Operation, path string, value *devicechange.TypedValue) {
+	if w != nil {
+		w[target] = append(w[target], &setWrite{op: op, path: path, value: value})
+	}
+}
+
+// squashedPath is a path of a device written more than once by a SetRequest
+type squashedPath struct {
+	path   string
+	writes []*setWrite
+}
+
+// squash returns the updates and removes that leave each device as the writes would, applied in
+// the gNMI order: deletes, then replaces, then updates. Each path keeps only its final value, and
+// the delete of a path that is written afterwards is dropped. It also returns the paths of each
+// device that were written more than once, with their writes in that order.
+func (w setWrites) squash() (mapTargetUpdates, mapTargetRemoves, map[devicetype.ID][]*squashedPath) {
+	targetUpdates := make(mapTargetUpdates)
+	targetRemoves := make(mapTargetRemoves)
+	targetSquashed := make(map[devicetype.ID][]*squashedPath)
+	rank := map[gnmi.UpdateResult_Operation]int{
+		gnmi.UpdateResult_DELETE:  0,
+		gnmi.UpdateResult_REPLACE: 1,
+		gnmi.UpdateResult_UPDATE:  2,
+	}
+	for target, writes := range w {
+		ordered := make([]*setWrite, len(writes))
+		copy(ordered, writes)
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return rank[ordered[i].op] < rank[ordered[j].op]
+		})
+
+		updates := make(devicechange.TypedValueMap)
+		removes := make([]string, 0)
+		history := make(map[string][]*setWrite)
+		for _, write := range ordered {
+			if write.op == gnmi.UpdateResult_DELETE {
+				if _, ok := history[write.path]; !ok {
+					removes = append(removes, write.path)
+				}
+			} else {
+				updates[write.path] = write.value
+			}
+			history[write.path] = append(history[write.path], write)
+		}
+
+		for _, remove := range removes {
+			if _, ok := updates[remove]; !ok {
+				targetRemoves[target] = append(targetRemoves[target], remove)
+			}
+		}
+		if len(updates) > 0 {
+			targetUpdates[target] = updates
+		}
+		for path, writes := range history {
+			if len(writes) > 1 {
+				targetSquashed[target] = append(targetSquashed[target], &squashedPath{path: path, writes: writes})
+			}
+		}
+		sort.Slice(targetSquashed[target], func(i, j int) bool {
+			return targetSquashed[target][i].path < targetSquashed[target][j].path
+		})
+	}
+	return targetUpdates, targetRemoves, targetSquashed
+}
+
+// auditSquashed records the intermediate writes that were squashed out of a stored change.
+// The values of sensitive paths are masked.
+func auditSquashed(user string, changeID networkchange.ID, targetSquashed map[devicetype.ID][]*squashedPath) {
+	for target, squashed := range targetSquashed {
+		paths := make([]string, 0, len(squashed))
+		descriptions := make([]string, 0, len(squashed))
+		for _, path := range squashed {
+			paths = append(paths, path.path)
+			writes := make([]string, 0, len(path.writes))
+			for _, write := range path.writes {
+				switch {
+				case write.op == gnmi.UpdateResult_DELETE:
+					writes = append(writes, "delete")
+				case secrets.GetRegistry().IsSensitive(path.path):
+					writes = append(writes, fmt.Sprintf("%s %q", strings.ToLower(write.op.String()), secrets.RedactedValue))
+				default:
+					writes = append(writes, fmt.Sprintf("%s %q", strings.ToLower(write.op.String()), write.value.ValueToString()))
+				}
+			}
+			descriptions = append(descriptions, fmt.Sprintf("%s: %s", path.path, strings.Join(writes, ", ")))
+		}
+		audit.Record(audit.Entry{
+			User:    user,
+			Action:  auditSquashedChange,
+			Target:  string(target),
+			Paths:   paths,
+			Message: fmt.Sprintf("squashed into change %s: %s", changeID, strings.Join(descriptions, "; ")),
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/secrets"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_squashWrites(t *testing.T) {
+	writes := make(setWrites)
+	writes.add("device-1", gnmi.UpdateResult_UPDATE, "/a/b", devicechange.NewTypedValueString("updated"))
+	writes.add("device-1", gnmi.UpdateResult_REPLACE, "/a/b", devicechange.NewTypedValueString("replaced"))
+	writes.add("device-1", gnmi.UpdateResult_UPDATE, "/a/c", devicechange.NewTypedValueString("first"))
+	writes.add("device-1", gnmi.UpdateResult_UPDATE, "/a/c", devicechange.NewTypedValueString("second"))
+	writes.add("device-1", gnmi.UpdateResult_DELETE, "/a/b", nil)
+	writes.add("device-1", gnmi.UpdateResult_DELETE, "/x", nil)
+	writes.add("device-1", gnmi.UpdateResult_DELETE, "/x", nil)
+	writes.add("device-1", gnmi.UpdateResult_UPDATE, "/x/y", devicechange.NewTypedValueString("kept"))
+	writes.add("device-2", gnmi.UpdateResult_DELETE, "/a/b", nil)
+
+	targetUpdates, targetRemoves, targetSquashed := writes.squash()
+
+	// The update is applied after the replace, and the delete before both
+	assert.Len(t, targetUpdates["device-1"], 3)
+	assert.Equal(t, "updated", targetUpdates["device-1"]["/a/b"].ValueToString())
+	assert.Equal(t, "second", targetUpdates["device-1"]["/a/c"].ValueToString())
+	assert.Equal(t, "kept", targetUpdates["device-1"]["/x/y"].ValueToString())
+	// The delete of /a/b is replaced by its update; the delete of /x still removes its other children
+	assert.Equal(t, []string{"/x"}, targetRemoves["device-1"])
+	assert.Equal(t, []string{"/a/b"}, targetRemoves["device-2"])
+	_, ok := targetUpdates["device-2"]
+	assert.False(t, ok)
+
+	squashed := targetSquashed["device-1"]
+	assert.Len(t, squashed, 3)
+	assert.Equal(t, "/a/b", squashed[0].path)
+	assert.Equal(t, gnmi.UpdateResult_DELETE, squashed[0].writes[0].op)
+	assert.Equal(t, gnmi.UpdateResult_REPLACE, squashed[0].writes[1].op)
+	assert.Equal(t, gnmi.UpdateResult_UPDATE, squashed[0].writes[2].op)
+	assert.Equal(t, "/a/c", squashed[1].path)
+	assert.Equal(t, "/x", squashed[2].path)
+	assert.Len(t, targetSquashed["device-2"], 0)
+}
+
+func Test_squashWritesNotSquashing(t *testing.T) {
+	// Writes are not recorded when changes are not squashed
+	var writes setWrites
+	writes.add("device-1", gnmi.UpdateResult_UPDATE, "/a/b", devicechange.NewTypedValueString("updated"))
+	assert.Len(t, writes, 0)
+}
+
+func Test_auditSquashed(t *testing.T) {
+	secrets.GetRegistry().Register("/squash/secret")
+	writes := make(setWrites)
+	writes.add("device-1", gnmi.UpdateResult_UPDATE, "/squash/secret", devicechange.NewTypedValueString("password1"))
+	writes.add("device-1", gnmi.UpdateResult_UPDATE, "/squash/secret", devicechange.NewTypedValueString("password2"))
+	writes.add("device-1", gnmi.UpdateResult_UPDATE, "/squash/public", devicechange.NewTypedValueString("one"))
+	writes.add("device-1", gnmi.UpdateResult_UPDATE, "/squash/public", devicechange.NewTypedValueString("two"))
+	_, _, targetSquashed := writes.squash()
+
+	auditSquashed("alice", "change-1", targetSquashed)
+	entries := audit.Entries()
+	entry := entries[len(entries)-1]
+	assert.Equal(t, auditSquashedChange, entry.Action)
+	assert.Equal(t, "device-1", entry.Target)
+	assert.Equal(t, []string{"/squash/public", "/squash/secret"}, entry.Paths)
+	assert.Equal(t, `squashed into change change-1: /squash/public: update "one", update "two"; `+
+		`/squash/secret: update "********", update "********"`, entry.Message)
+}
@@ -24,6 +24,9 @@ import (
 	topodevice "github.com/onosproject/onos-config/pkg/device"
 	"github.com/onosproject/onos-config/pkg/events"
 	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound/grpcerrors"
+	"github.com/onosproject/onos-config/pkg/northbound/subscriptions"
+	"github.com/onosproject/onos-config/pkg/secrets"
 	"github.com/onosproject/onos-config/pkg/store"
 	streams "github.com/onosproject/onos-config/pkg/store/stream"
 	"github.com/onosproject/onos-config/pkg/utils"
@@ -34,6 +37,7 @@ import (
 	"google.golang.org/grpc/status"
 	"io"
 	"regexp"
+	"strings"
 	"time"
 )
 
@@ -43,12 +47,21 @@ type result struct {
 	err     error
 }
 
+// subscriber identifies the caller of a subscription, so that sensitive values can be redacted
+type subscriber struct {
+	user   string
+	groups []string
+}
+
 // Subscribe implements gNMI Subscribe
 func (s *Server) Subscribe(stream gnmi.GNMI_SubscribeServer) error {
+	caller := subscriber{}
 	if stream.Context() != nil {
 		if md := metautils.ExtractIncoming(stream.Context()); md != nil && md.Get("name") != "" {
 			log.Infof("gNMI Subscribe() called by '%s (%s)'. Groups [%v]. Token %s",
 				md.Get("name"), md.Get("email"), md.Get("groups"), md.Get("at_hash"))
+			caller.user = md.Get("name")
+			caller.groups = strings.Split(md.Get("groups"), ";")
 		}
 	}
 	//updateChan := make(chan *gnmi.Update)
@@ -61,26 +74,42 @@ func (s *Server) Subscribe(stream gnmi.GNMI_SubscribeServer) error {
 		return err1
 	}
 	hash := store.B64(h.Sum(nil))
+	active, err := subscriptions.GetRegistry().Add(hash, caller.user)
+	if err != nil {
+		log.Warn("Subscription present: ", err)
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	defer subscriptions.GetRegistry().Remove(hash)
 	//Registering one listener for opStateChan
 	opStateChan, err := mgr.Dispatcher.RegisterOpState(hash)
 	if err != nil {
 		log.Warn("Subscription present: ", err)
 		return status.Error(codes.AlreadyExists, err.Error())
 	}
-	resChan := make(chan result)
+	// Buffered, so that the listener can report the end of the stream once the subscription is
+	// terminated
+	resChan := make(chan result, 1)
 	//Handles each subscribe request coming into the server, blocks until a new request or an error comes in
-	go s.listenOnChannel(stream, mgr, hash, resChan, subscribe, opStateChan)
+	go s.listenOnChannel(stream, mgr, hash, resChan, subscribe, opStateChan, caller, active)
 
-	res := <-resChan
+	var res result
+	select {
+	case res = <-resChan:
+	case <-active.Terminated():
+		// Ending the stream cancels it, on which the listener unregisters from the dispatcher
+		log.Infof("Subscription %s of '%s' terminated: %s", hash, caller.user, active.Message())
+		return status.Errorf(codes.Aborted, "subscription terminated: %s", active.Message())
+	}
 
 	if !res.success {
-		return status.Error(codes.Internal, res.err.Error())
+		return grpcerrors.Err(res.err)
 	}
 	return nil
 }
 
 func (s *Server) listenOnChannel(stream gnmi.GNMI_SubscribeServer, mgr *manager.Manager, hash string,
-	resChan chan result, subscribe *gnmi.SubscriptionList, opStateChan chan events.OperationalStateEvent) {
+	resChan chan result, subscribe *gnmi.SubscriptionList, opStateChan chan events.OperationalStateEvent, caller subscriber,
+	active *subscriptions.Active) {
 	for {
 		in, err := stream.Recv()
 		if err == io.EOF {
@@ -125,9 +154,12 @@ func (s *Server) listenOnChannel(stream gnmi.GNMI_SubscribeServer, mgr *manager.
 		//If there are no paths in the request such request is ignored
 		if subscribe.Subscription == nil {
 			log.Error("No subscription paths, ignoring request ", in)
-			resChan <- result{success: false, err: fmt.Errorf("no subscription paths in request")}
+			resChan <- result{success: false, err: status.Error(codes.InvalidArgument, "no subscription paths in request")}
 			break
 		}
+		if in.GetPoll() == nil {
+			recordSubscription(active, subscribe)
+		}
 
 		//If the subscription mode is ONCE or POLL we immediately start a routine to collect the data
 		version, err := extractSubscribeVersion(in)
@@ -135,7 +167,7 @@ func (s *Server) listenOnChannel(stream gnmi.GNMI_SubscribeServer, mgr *manager.
 			if err != nil {
 				resChan <- result{success: false, err: err}
 			} else {
-				go s.collector(mgr, version, stream, subscribe, resChan, mode)
+				go s.collector(mgr, version, stream, subscribe, resChan, mode, caller)
 			}
 		} else {
 
@@ -148,14 +180,43 @@ func (s *Server) listenOnChannel(stream gnmi.GNMI_SubscribeServer, mgr *manager.
 				subsStr = append(subsStr, utils.MatchWildcardRegexp(subscriptionPathStr, false))
 				targets[sub.Path.Target] = struct{}{}
 			}
+			// The operational state events are sent to the streams of each QoS marking by a lane
+			// of their own, so that the streams of lower marking do not hold them up
+			if qos := subscribe.GetQos(); qos != nil {
+				if err := mgr.Dispatcher.SetOpStatePriority(hash, qos.GetMarking()); err != nil {
+					mgr.Dispatcher.UnregisterOperationalState(hash)
+					resChan <- result{success: false, err: status.Error(codes.InvalidArgument, err.Error())}
+					break
+				}
+			}
+			var aliases *pathAliases
+			if subscribe.UseAliases {
+				aliases = newPathAliases()
+			}
 			//Each subscription request spawns a go routing listening for related events for the target and the paths
-			go listenForUpdates(stream, mgr, targets, version, subsStr, resChan)
-			go listenForOpStateUpdates(opStateChan, stream, targets, subsStr, resChan)
+			go listenForUpdates(stream, mgr, targets, version, subsStr, resChan, caller, aliases)
+			go listenForOpStateUpdates(opStateChan, stream, targets, subsStr, resChan, caller, aliases)
 		}
 	}
 }
 
-func (s *Server) collector(mgr *manager.Manager, version devicetype.Version, stream gnmi.GNMI_SubscribeServer, request *gnmi.SubscriptionList, resChan chan result, mode gnmi.SubscriptionList_Mode) {
+// recordSubscription records the paths of a subscription list and their targets with the active
+// subscription
+func recordSubscription(active *subscriptions.Active, subscribe *gnmi.SubscriptionList) {
+	paths := make([]string, 0, len(subscribe.Subscription))
+	targets := make(map[string]struct{})
+	for _, sub := range subscribe.Subscription {
+		paths = append(paths, utils.StrPath(sub.Path))
+		targets[sub.GetPath().GetTarget()] = struct{}{}
+	}
+	devices := make([]string, 0, len(targets))
+	for target := range targets {
+		devices = append(devices, target)
+	}
+	active.Subscribed(subscribe.Mode.String(), paths, devices)
+}
+
+func (s *Server) collector(mgr *manager.Manager, version devicetype.Version, stream gnmi.GNMI_SubscribeServer, request *gnmi.SubscriptionList, resChan chan result, mode gnmi.SubscriptionList_Mode, caller subscriber) {
 	for _, sub := range request.Subscription {
 		_, version, err := mgr.CheckCacheForDevice(devicetype.ID(sub.GetPath().GetTarget()), devicetype.Type(""), version)
 		if err != nil {
@@ -163,20 +224,23 @@ func (s *Server) collector(mgr *manager.Manager, version devicetype.Version, str
 			resChan <- result{success: false, err: err}
 		}
 		//We get the stated of the device, for each path we build an update and send it out.
-		updates, err := s.getUpdate(version, request.Prefix, sub.Path, gnmi.Encoding_PROTO, nil)
+		updates, err := s.getUpdate(version, request.Prefix, sub.Path, gnmi.Encoding_PROTO, caller.user, caller.groups)
 		if err != nil {
 			log.Error("Error while collecting data for subscribe once or poll ", err)
 			resChan <- result{success: false, err: err}
 		}
-		response, errGet := buildUpdateResponse(updates)
-		if errGet != nil {
-			log.Error("Error Retrieving Device", err)
-			resChan <- result{success: false, err: err}
-		}
-		err = sendResponse(response, stream)
-		if err != nil {
-			log.Error("Error sending response ", err)
-			resChan <- result{success: false, err: err}
+		// A large tree is streamed with several notifications
+		for _, group := range splitUpdates(updates, s.maxUpdatesPerNotification) {
+			response, errGet := buildUpdateResponse(nil, group)
+			if errGet != nil {
+				log.Error("Error Retrieving Device", err)
+				resChan <- result{success: false, err: err}
+			}
+			err = sendResponse(response, stream)
+			if err != nil {
+				log.Error("Error sending response ", err)
+				resChan <- result{success: false, err: err}
+			}
 		}
 	}
 	responseSync := buildSyncResponse()
@@ -193,20 +257,22 @@ func (s *Server) collector(mgr *manager.Manager, version devicetype.Version, str
 
 //For each update coming from the change channel we check if it's for a valid target and path then, if so, we send it NB
 func listenForUpdates(stream gnmi.GNMI_SubscribeServer, mgr *manager.Manager,
-	targets map[string]struct{}, version devicetype.Version, subs []*regexp.Regexp, resChan chan result) {
+	targets map[string]struct{}, version devicetype.Version, subs []*regexp.Regexp, resChan chan result, caller subscriber,
+	aliases *pathAliases) {
 	for target := range targets {
 		_, version, err := mgr.CheckCacheForDevice(devicetype.ID(target), devicetype.Type(""), version)
 		if err != nil {
 			log.Errorf("unable to get version from cache %s", err)
 			return
 		}
-		go listenForDeviceUpdates(stream, mgr, devicetype.ID(target), version, subs, resChan)
+		go listenForDeviceUpdates(stream, mgr, devicetype.ID(target), version, subs, resChan, caller, aliases)
 	}
 }
 
 //For each update coming from the change channel we check if it's for a valid target and path then, if so, we send it NB
 func listenForDeviceUpdates(stream gnmi.GNMI_SubscribeServer, mgr *manager.Manager,
-	target devicetype.ID, version devicetype.Version, subs []*regexp.Regexp, resChan chan result) {
+	target devicetype.ID, version devicetype.Version, subs []*regexp.Regexp, resChan chan result, caller subscriber,
+	aliases *pathAliases) {
 	eventCh := make(chan streams.Event)
 	ctx, errWatch := mgr.DeviceChangesStore.Watch(devicetype.NewVersionedID(target, version), eventCh)
 	if errWatch != nil {
@@ -230,7 +296,8 @@ func listenForDeviceUpdates(stream gnmi.GNMI_SubscribeServer, mgr *manager.Manag
 						continue
 					}
 					log.Infof("Subscribe notification for %s on %s with value %s", pathGnmi, target, value.Value)
-					err = buildAndSendUpdate(pathGnmi, string(target), value.Value, value.Removed, stream)
+					typedValue := secrets.GetRegistry().RedactValue(string(target), value.Path, value.Value, caller.user, caller.groups)
+					err = buildAndSendUpdate(pathGnmi, string(target), typedValue, value.Removed, stream, aliases)
 					if err != nil {
 						log.Error("Error in sending update path ", err)
 						resChan <- result{success: false, err: err}
@@ -243,7 +310,7 @@ func listenForDeviceUpdates(stream gnmi.GNMI_SubscribeServer, mgr *manager.Manag
 
 //For each update coming from the state channel we check if it's for a valid target and path then, if so, we send it NB
 func listenForOpStateUpdates(opStateChan chan events.OperationalStateEvent, stream gnmi.GNMI_SubscribeServer,
-	targets map[string]struct{}, subs []*regexp.Regexp, resChan chan result) {
+	targets map[string]struct{}, subs []*regexp.Regexp, resChan chan result, caller subscriber, aliases *pathAliases) {
 	for opStateChange := range opStateChan {
 		target := opStateChange.Subject()
 		_, targetPresent := targets[target]
@@ -256,7 +323,8 @@ func listenForOpStateUpdates(opStateChan chan events.OperationalStateEvent, stre
 				continue
 			}
 
-			err = buildAndSendUpdate(pathGnmi, target, opStateChange.Value(), len(opStateChange.Value().Bytes) == 0, stream)
+			typedValue := secrets.GetRegistry().RedactValue(target, opStateChange.Path(), opStateChange.Value(), caller.user, caller.groups)
+			err = buildAndSendUpdate(pathGnmi, target, typedValue, len(opStateChange.Value().Bytes) == 0, stream, aliases)
 			if err != nil {
 				log.Error("Error in sending update path ", err)
 				resChan <- result{success: false, err: err}
@@ -275,13 +343,23 @@ func matchRegex(path string, subs []*regexp.Regexp) bool {
 }
 
 func buildAndSendUpdate(pathGnmi *gnmi.Path, target string, value *devicechange.TypedValue, removed bool,
-	stream gnmi.GNMI_SubscribeServer) error {
+	stream gnmi.GNMI_SubscribeServer, aliases *pathAliases) error {
 	pathGnmi.Target = target
+	prefix, pathGnmi, aliasDefinition := aliases.compact(pathGnmi)
+	if aliasDefinition != nil {
+		response, err := buildSubscribeResponse(aliasDefinition)
+		if err != nil {
+			return err
+		}
+		if err := sendResponse(response, stream); err != nil {
+			return err
+		}
+	}
 	var response *gnmi.SubscribeResponse
 	var errGet error
 	//if removed we issue a delete notification
 	if removed {
-		response, errGet = buildDeleteResponse(pathGnmi)
+		response, errGet = buildDeleteResponse(prefix, pathGnmi)
 	} else {
 		valueGnmi, err := values.NativeTypeToGnmiTypedValue(value)
 		if err != nil {
@@ -295,7 +373,7 @@ func buildAndSendUpdate(pathGnmi *gnmi.Path, target string, value *devicechange.
 		}
 		updates := make([]*gnmi.Update, 1)
 		updates[0] = update
-		response, errGet = buildUpdateResponse(updates)
+		response, errGet = buildUpdateResponse(prefix, updates)
 	}
 	if errGet != nil {
 		return errGet
@@ -318,18 +396,20 @@ func buildSyncResponse() *gnmi.SubscribeResponse {
 	}
 }
 
-func buildUpdateResponse(updates []*gnmi.Update) (*gnmi.SubscribeResponse, error) {
+func buildUpdateResponse(prefix *gnmi.Path, updates []*gnmi.Update) (*gnmi.SubscribeResponse, error) {
 	notification := &gnmi.Notification{
 		Timestamp: time.Now().Unix(),
+		Prefix:    prefix,
 		Update:    updates,
 	}
 	return buildSubscribeResponse(notification)
 }
 
-func buildDeleteResponse(delete *gnmi.Path) (*gnmi.SubscribeResponse, error) {
+func buildDeleteResponse(prefix *gnmi.Path, delete *gnmi.Path) (*gnmi.SubscribeResponse, error) {
 	deleteArray := []*gnmi.Path{delete}
 	notification := &gnmi.Notification{
 		Timestamp: time.Now().Unix(),
+		Prefix:    prefix,
 		Delete:    deleteArray,
 	}
 	return buildSubscribeResponse(notification)
@@ -343,7 +423,11 @@ func buildSubscribeResponse(notification *gnmi.Notification) (*gnmi.SubscribeRes
 		Response: responseUpdate,
 	}
 	for _, u := range notification.Update {
-		ext, err := checkDevice(u.GetPath().GetTarget())
+		target := u.GetPath().GetTarget()
+		if target == "" {
+			target = notification.GetPrefix().GetTarget()
+		}
+		ext, err := checkDevice(target)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,133 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/modelregistry"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-config/pkg/utils/values"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// translateSetRequest maps the paths of a Set written against a vendor-neutral model, e.g. OpenConfig,
+// to the native models of the targets whose device type has a translation in the model registry, so
+// that intent stays vendor-neutral. The request is returned as it is if no path is mapped; otherwise
+// each path is given in full, the prefix giving only the target.
+func translateSetRequest(req *gnmi.SetRequest, version devicetype.Version, deviceType devicetype.Type) (*gnmi.SetRequest, error) {
+	mgr := manager.GetManager()
+	if mgr.ModelRegistry == nil || len(mgr.ModelRegistry.TranslatedTypes()) == 0 {
+		return req, nil
+	}
+	prefix := req.GetPrefix()
+	translated := false
+	translate := func(path *gnmi.Path) (*gnmi.Path, *modelregistry.PathMapping, error) {
+		fullPath := &gnmi.Path{
+			Elem:   append(append([]*gnmi.PathElem{}, prefix.GetElem()...), path.GetElem()...),
+			Target: path.GetTarget(),
+		}
+		target := path.GetTarget()
+		if target == "" {
+			target = prefix.GetTarget()
+		}
+		// A target that is not known is reported as the request is processed
+		targetType, _, err := mgr.CheckCacheForDevice(devicetype.ID(target), deviceType, version)
+		if err != nil {
+			return fullPath, nil, nil
+		}
+		translation := mgr.ModelRegistry.GetTranslation(targetType)
+		if translation == nil {
+			return fullPath, nil, nil
+		}
+		nativePath, mapping, err := translation.TranslatePath(utils.StrPath(fullPath))
+		if err != nil {
+			return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+		} else if mapping == nil {
+			return fullPath, nil, nil
+		}
+		native, err := utils.ParseGNMIElements(utils.SplitPath(nativePath))
+		if err != nil {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "invalid translation of %s to %s: %v",
+				utils.StrPath(fullPath), nativePath, err)
+		}
+		log.Infof("Translating %s of %s to %s", utils.StrPath(fullPath), target, nativePath)
+		native.Target = path.GetTarget()
+		translated = true
+		return native, mapping, nil
+	}
+	translateUpdates := func(updates []*gnmi.Update) ([]*gnmi.Update, error) {
+		nativeUpdates := make([]*gnmi.Update, 0, len(updates))
+		for _, update := range updates {
+			path, mapping, err := translate(update.GetPath())
+			if err != nil {
+				return nil, err
+			}
+			value := update.GetVal()
+			if mapping != nil {
+				if value, err = translateValue(utils.StrPath(update.GetPath()), value, mapping); err != nil {
+					return nil, err
+				}
+			}
+			nativeUpdates = append(nativeUpdates, &gnmi.Update{Path: path, Val: value, Duplicates: update.GetDuplicates()})
+		}
+		return nativeUpdates, nil
+	}
+
+	nativeReq := &gnmi.SetRequest{Extension: req.GetExtension()}
+	if prefix != nil {
+		nativeReq.Prefix = &gnmi.Path{Target: prefix.GetTarget(), Origin: prefix.GetOrigin()}
+	}
+	var err error
+	for _, path := range req.GetDelete() {
+		nativePath, _, err := translate(path)
+		if err != nil {
+			return nil, err
+		}
+		nativeReq.Delete = append(nativeReq.Delete, nativePath)
+	}
+	if nativeReq.Replace, err = translateUpdates(req.GetReplace()); err != nil {
+		return nil, err
+	}
+	if nativeReq.Update, err = translateUpdates(req.GetUpdate()); err != nil {
+		return nil, err
+	}
+	if !translated {
+		return req, nil
+	}
+	return nativeReq, nil
+}
+
+// translateValue returns the native value of a value written to a path a mapping applies to. Only
+// the leaves are mapped: a JSON value would hold paths of the vendor-neutral model.
+func translateValue(path string, value *gnmi.TypedValue, mapping *modelregistry.PathMapping) (*gnmi.TypedValue, error) {
+	if value.GetJsonVal() != nil || value.GetJsonIetfVal() != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"cannot translate the JSON value of %s to the native model: set its leaves one by one", path)
+	}
+	if len(mapping.Values) == 0 {
+		return value, nil
+	}
+	nativeValue, err := values.GnmiTypedValueToNativeType(value, nil)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot translate the value of %s: %v", path, err)
+	}
+	if current, translated := nativeValue.ValueToString(), mapping.TranslateValue(nativeValue.ValueToString()); translated != current {
+		return &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: translated}}, nil
+	}
+	return value, nil
+}
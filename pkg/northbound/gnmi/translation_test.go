@@ -0,0 +1,107 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/modelregistry"
+	mockstore "github.com/onosproject/onos-config/pkg/test/mocks/store"
+	mockcache "github.com/onosproject/onos-config/pkg/test/mocks/store/cache"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_translateSetRequest(t *testing.T) {
+	// The model registry needs no plugins
+	ctrl := gomock.NewController(t)
+	mockStores := &mockstore.MockStores{
+		DeviceStore:          mockstore.NewMockDeviceStore(ctrl),
+		DeviceStateStore:     mockstore.NewMockDeviceStateStore(ctrl),
+		NetworkChangesStore:  mockstore.NewMockNetworkChangesStore(ctrl),
+		DeviceChangesStore:   mockstore.NewMockDeviceChangesStore(ctrl),
+		NetworkSnapshotStore: mockstore.NewMockNetworkSnapshotStore(ctrl),
+		DeviceSnapshotStore:  mockstore.NewMockDeviceSnapshotStore(ctrl),
+		LeadershipStore:      mockstore.NewMockLeadershipStore(ctrl),
+		MastershipStore:      mockstore.NewMockMastershipStore(ctrl),
+	}
+	deviceCache := mockcache.NewMockCache(ctrl)
+	mgr := manager.NewManager(mockStores.LeadershipStore, mockStores.MastershipStore, mockStores.DeviceChangesStore,
+		mockStores.DeviceStateStore, mockStores.DeviceStore, deviceCache, mockStores.NetworkChangesStore,
+		mockStores.NetworkSnapshotStore, mockStores.DeviceSnapshotStore, false, &modelregistry.ModelRegistry{})
+	setUpWatchMock(&AllMocks{MockStores: mockStores, MockDeviceCache: deviceCache})
+	setUpBaseDevices(mockStores, deviceCache)
+
+	path := func(p string) *gnmi.Path {
+		gnmiPath, err := utils.ParseGNMIElements(utils.SplitPath(p))
+		assert.NoError(t, err)
+		return gnmiPath
+	}
+	stringValue := func(s string) *gnmi.TypedValue {
+		return &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: s}}
+	}
+	req := &gnmi.SetRequest{
+		Prefix: &gnmi.Path{Target: device1, Elem: path("/neutral").Elem},
+		Update: []*gnmi.Update{
+			{Path: path("/port[id=eth1]/power"), Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 5}}},
+			{Path: path("/port[id=eth1]/state"), Val: stringValue("up")},
+		},
+		Delete: []*gnmi.Path{path("/port[id=eth2]")},
+	}
+
+	// A request to a device type without a translation is not changed
+	translated, err := translateSetRequest(req, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, req, translated)
+
+	assert.NoError(t, mgr.ModelRegistry.RegisterTranslation("TestDevice",
+		&modelregistry.PathMapping{From: "/neutral/port[id={id}]", To: "/cont1a/list2a[name={id}]"},
+		&modelregistry.PathMapping{From: "/neutral/port[id={id}]/power", To: "/cont1a/list2a[name={id}]/tx-power"},
+		&modelregistry.PathMapping{
+			From:   "/neutral/port[id={id}]/state",
+			To:     "/cont1a/list2a[name={id}]/admin-state",
+			Values: map[string]string{"up": "UP"},
+		}))
+	defer func() {
+		assert.NoError(t, mgr.ModelRegistry.RegisterTranslation("TestDevice"))
+	}()
+
+	translated, err = translateSetRequest(req, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, device1, translated.Prefix.Target)
+	assert.Len(t, translated.Prefix.Elem, 0)
+	assert.Equal(t, "/cont1a/list2a[name=eth1]/tx-power", utils.StrPath(translated.Update[0].Path))
+	assert.Equal(t, uint64(5), translated.Update[0].Val.GetUintVal())
+	assert.Equal(t, "/cont1a/list2a[name=eth1]/admin-state", utils.StrPath(translated.Update[1].Path))
+	assert.Equal(t, "UP", translated.Update[1].Val.GetStringVal())
+	assert.Equal(t, "/cont1a/list2a[name=eth2]", utils.StrPath(translated.Delete[0]))
+	// The request is not changed
+	assert.Equal(t, "/port[id=eth1]/power", utils.StrPath(req.Update[0].Path))
+
+	// Only leaves are translated
+	_, err = translateSetRequest(&gnmi.SetRequest{
+		Prefix: &gnmi.Path{Target: device1},
+		Update: []*gnmi.Update{{
+			Path: path("/neutral/port[id=eth1]"),
+			Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: []byte(`{"power": 5}`)}},
+		}},
+	}, "", "")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
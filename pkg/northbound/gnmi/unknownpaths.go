@@ -0,0 +1,79 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+)
+
+// unknownPaths are the paths of a SetRequest that are not in the models of their targets, for the
+// targets that allow them, see manager.AllowsUnknownPaths
+type unknownPaths struct {
+	allows  func(devicetype.ID) bool
+	allowed map[devicetype.ID]bool
+	paths   map[devicetype.ID][]string
+}
+
+func newUnknownPaths(allows func(devicetype.ID) bool) *unknownPaths {
+	return &unknownPaths{
+		allows:  allows,
+		allowed: make(map[devicetype.ID]bool),
+		paths:   make(map[devicetype.ID][]string),
+	}
+}
+
+// allow returns whether the target allows unknown paths, asking only once per target
+func (u *unknownPaths) allow(target devicetype.ID) bool {
+	allowed, ok := u.allowed[target]
+	if !ok {
+		allowed = u.allows(target)
+		u.allowed[target] = allowed
+	}
+	return allowed
+}
+
+// add records an unknown path of the target, that is pushed to it unvalidated
+func (u *unknownPaths) add(target devicetype.ID, path string) {
+	log.Warnf("Path %s is not in the model of %s, it is set unvalidated", path, target)
+	u.paths[target] = append(u.paths[target], path)
+}
+
+// extension flags the unknown paths of the request in its response, one "<target>:<path>" per
+// line, sorted; it is nil if there are none
+func (u *unknownPaths) extension() *gnmi_ext.Extension {
+	lines := make([]string, 0)
+	for target, paths := range u.paths {
+		for _, path := range paths {
+			lines = append(lines, fmt.Sprintf("%s:%s", target, path))
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	sort.Strings(lines)
+	return &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  GnmiExtensionUnvalidatedPaths,
+				Msg: []byte(strings.Join(lines, "\n")),
+			},
+		},
+	}
+}
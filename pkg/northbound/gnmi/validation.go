@@ -0,0 +1,97 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ValidationLevel is how strictly a Set is validated against the model of its targets
+type ValidationLevel string
+
+const (
+	// ValidationStrict checks the paths and the types of the values of a Set against the model, and
+	// validates the resulting configuration with all the constraints of the model
+	ValidationStrict ValidationLevel = "strict"
+	// ValidationSchemaOnly only checks the paths and the types of the values of a Set against the
+	// model, e.g. for devices whose firmware diverges from the constraints of their published model
+	ValidationSchemaOnly ValidationLevel = "schema-only"
+	// ValidationNone also accepts the paths that are not in the model, with the types the values are
+	// given with. The paths of JSON values must still be in the model, which gives their types.
+	ValidationNone ValidationLevel = "none"
+)
+
+// ValidationLevels are the validation levels, from the strictest
+var ValidationLevels = []ValidationLevel{ValidationStrict, ValidationSchemaOnly, ValidationNone}
+
+// ParseValidationLevel parses a validation level, the empty string being strict
+func ParseValidationLevel(level string) (ValidationLevel, error) {
+	if level == "" {
+		return ValidationStrict, nil
+	}
+	for _, known := range ValidationLevels {
+		if ValidationLevel(level) == known {
+			return known, nil
+		}
+	}
+	names := make([]string, 0, len(ValidationLevels))
+	for _, known := range ValidationLevels {
+		names = append(names, string(known))
+	}
+	return "", fmt.Errorf("unknown validation level '%s', must be one of %s", level, strings.Join(names, ", "))
+}
+
+// checksSchema returns whether the paths of a Set must be in the model
+func (l ValidationLevel) checksSchema() bool {
+	return l != ValidationNone
+}
+
+// checksConstraints returns whether the configuration resulting from a Set is validated against
+// the constraints of the model
+func (l ValidationLevel) checksConstraints() bool {
+	return l != ValidationSchemaOnly && l != ValidationNone
+}
+
+// getValidationLevel returns the validation level of a SetRequest: the one of its validation
+// level extension if it has one, or else the level of the deployment
+func getValidationLevel(req *gnmi.SetRequest, deployment ValidationLevel) (ValidationLevel, error) {
+	if deployment == "" {
+		deployment = ValidationStrict
+	}
+	level := deployment
+	given := false
+	for _, ext := range req.GetExtension() {
+		if ext.GetRegisteredExt().GetId() == GnmiExtensionValidationLevel {
+			if given {
+				return "", status.Errorf(codes.InvalidArgument, "extension %d must only be given once", GnmiExtensionValidationLevel)
+			}
+			given = true
+			parsed, err := ParseValidationLevel(strings.TrimSpace(string(ext.GetRegisteredExt().GetMsg())))
+			if err != nil {
+				return "", status.Errorf(codes.InvalidArgument, "extension %d: %v", GnmiExtensionValidationLevel, err)
+			}
+			level = parsed
+		}
+	}
+	if given && level != deployment {
+		log.Infof("gNMI Set validated at level %s instead of %s", level, deployment)
+	}
+	return level, nil
+}
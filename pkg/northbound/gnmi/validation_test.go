@@ -0,0 +1,162 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"context"
+	"testing"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func validationLevelExtension(level string) *gnmi_ext.Extension {
+	return &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  GnmiExtensionValidationLevel,
+				Msg: []byte(level),
+			},
+		},
+	}
+}
+
+func Test_ParseValidationLevel(t *testing.T) {
+	for _, level := range ValidationLevels {
+		parsed, err := ParseValidationLevel(string(level))
+		assert.NoError(t, err)
+		assert.Equal(t, level, parsed)
+	}
+	parsed, err := ParseValidationLevel("")
+	assert.NoError(t, err)
+	assert.Equal(t, ValidationStrict, parsed)
+	_, err = ParseValidationLevel("lenient")
+	assert.EqualError(t, err, "unknown validation level 'lenient', must be one of strict, schema-only, none")
+}
+
+func Test_getValidationLevel(t *testing.T) {
+	level, err := getValidationLevel(&gnmi.SetRequest{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, ValidationStrict, level)
+
+	level, err = getValidationLevel(&gnmi.SetRequest{}, ValidationSchemaOnly)
+	assert.NoError(t, err)
+	assert.Equal(t, ValidationSchemaOnly, level)
+
+	// The request overrides the level of the deployment, both ways
+	level, err = getValidationLevel(&gnmi.SetRequest{Extension: []*gnmi_ext.Extension{validationLevelExtension("none")}}, ValidationStrict)
+	assert.NoError(t, err)
+	assert.Equal(t, ValidationNone, level)
+	level, err = getValidationLevel(&gnmi.SetRequest{Extension: []*gnmi_ext.Extension{validationLevelExtension("strict")}}, ValidationNone)
+	assert.NoError(t, err)
+	assert.Equal(t, ValidationStrict, level)
+
+	_, err = getValidationLevel(&gnmi.SetRequest{Extension: []*gnmi_ext.Extension{validationLevelExtension("lenient")}}, ValidationStrict)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = getValidationLevel(&gnmi.SetRequest{Extension: []*gnmi_ext.Extension{
+		validationLevelExtension("none"), validationLevelExtension("strict")}}, ValidationStrict)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// Test_doSetSchemaOnly sets a list entry whose leaf ref does not exist, which only the constraints
+// of the model reject
+func Test_doSetSchemaOnly(t *testing.T) {
+	server, mocks, _ := setUpForGetSetTests(t)
+	setUpChangesMock(mocks)
+
+	prefixElemsRefs, _ := utils.ParseGNMIElements(utils.SplitPath("/cont1a"))
+	prefix := &gnmi.Path{Elem: prefixElemsRefs.Elem, Target: "Device1"}
+	leafPath, _ := utils.ParseGNMIElements(utils.SplitPath("/list4[id=second]/leaf4b"))
+	setRequest := &gnmi.SetRequest{
+		Prefix: prefix,
+		Update: []*gnmi.Update{{
+			Path: &gnmi.Path{Elem: leafPath.Elem},
+			Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "4b second value"}},
+		}},
+	}
+
+	_, err := server.Set(context.Background(), setRequest)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	server.validationLevel = ValidationSchemaOnly
+	setResponse, err := server.Set(context.Background(), setRequest)
+	assert.NoError(t, err)
+	assert.Len(t, setResponse.Response, 1)
+
+	// The request asks for the constraints to be checked
+	setRequest.Extension = []*gnmi_ext.Extension{validationLevelExtension("strict")}
+	_, err = server.Set(context.Background(), setRequest)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// Test_doSetNoValidation sets and deletes a path that is not in the model
+func Test_doSetNoValidation(t *testing.T) {
+	server, mocks, _ := setUpForGetSetTests(t)
+	setUpChangesMock(mocks)
+
+	unknownPath, _ := utils.ParseGNMIElements(utils.SplitPath("/cont1a/vendor-leaf"))
+	unknownPath.Target = "Device1"
+	setRequest := &gnmi.SetRequest{
+		Update: []*gnmi.Update{{
+			Path: unknownPath,
+			Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 9000}},
+		}},
+	}
+
+	_, err := server.Set(context.Background(), setRequest)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	setRequest.Extension = []*gnmi_ext.Extension{validationLevelExtension("schema-only")}
+	_, err = server.Set(context.Background(), setRequest)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	setRequest.Extension = []*gnmi_ext.Extension{validationLevelExtension("none")}
+	setResponse, err := server.Set(context.Background(), setRequest)
+	assert.NoError(t, err)
+	if assert.Len(t, setResponse.Response, 1) {
+		assert.Equal(t, "/cont1a/vendor-leaf", utils.StrPath(setResponse.Response[0].Path))
+	}
+
+	deleteRequest := &gnmi.SetRequest{
+		Delete:    []*gnmi.Path{unknownPath},
+		Extension: []*gnmi_ext.Extension{validationLevelExtension("none")},
+	}
+	_, err = server.Set(context.Background(), deleteRequest)
+	assert.NoError(t, err)
+}
+
+func Test_unknownPaths(t *testing.T) {
+	asked := 0
+	unknown := newUnknownPaths(func(target devicetype.ID) bool {
+		asked++
+		return target == "Device1"
+	})
+	assert.Nil(t, unknown.extension())
+
+	assert.True(t, unknown.allow("Device1"))
+	assert.True(t, unknown.allow("Device1"))
+	assert.False(t, unknown.allow("Device2"))
+	assert.Equal(t, 2, asked)
+
+	unknown.add("Device1", "/cont1a/vendor-leaf2")
+	unknown.add("Device1", "/cont1a/vendor-leaf1")
+	ext := unknown.extension()
+	assert.Equal(t, gnmi_ext.ExtensionID(GnmiExtensionUnvalidatedPaths), ext.GetRegisteredExt().GetId())
+	assert.Equal(t, "Device1:/cont1a/vendor-leaf1\nDevice1:/cont1a/vendor-leaf2", string(ext.GetRegisteredExt().GetMsg()))
+}
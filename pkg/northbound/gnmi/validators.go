@@ -0,0 +1,48 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gnmi
+
+import (
+	"context"
+
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/validators"
+)
+
+// validateExternally asks the external validators, if any, whether the changes of a Set to the
+// intended configuration of its targets may be made
+func validateExternally(ctx context.Context, mgr *manager.Manager, user string, dryRun bool,
+	targetUpdates mapTargetUpdates, targetRemoves mapTargetRemoves, deviceInfo map[devicetype.ID]cache.Info,
+	lastWrite networkchange.Revision) error {
+	registry := validators.GetRegistry()
+	if !registry.Enabled() {
+		return nil
+	}
+	diffs, err := mgr.ProposedDiffs(targetUpdates, targetRemoves, deviceInfo, lastWrite)
+	if err != nil {
+		return err
+	}
+	if len(diffs) == 0 {
+		return nil
+	}
+	return registry.Validate(ctx, &validators.Proposal{
+		User:    user,
+		DryRun:  dryRun,
+		Devices: diffs,
+	})
+}
@@ -0,0 +1,198 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Object is a node of the configuration graph whose fields can be selected by a query.
+// TypeName returns the name of its type in the schema, e.g. "Device".
+// Resolve returns either a scalar value, another Object or a slice of Objects.
+type Object interface {
+	TypeName() string
+	Resolve(field *Field) (interface{}, error)
+}
+
+// Error is a GraphQL error, reported with the path of the field that failed
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// Response is the result of executing a query
+type Response struct {
+	Data   *OrderedMap `json:"data"`
+	Errors []Error     `json:"errors,omitempty"`
+}
+
+// OrderedMap is a JSON object which preserves the order of the selected fields
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *OrderedMap {
+	return &OrderedMap{
+		keys:   make([]string, 0),
+		values: make(map[string]interface{}),
+	}
+}
+
+// Set sets the value of a key, keeping the position of the first insertion
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get gets the value of a key
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Keys returns the keys in insertion order
+func (m *OrderedMap) Keys() []string {
+	return m.keys
+}
+
+// MarshalJSON encodes the map with keys in insertion order
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyJSON)
+		buf.WriteByte(':')
+		valueJSON, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(valueJSON)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Execute runs the query against the given root object
+func Execute(root Object, query *Query) *Response {
+	e := &executor{}
+	data := e.selectFields(root, query.Selections, []interface{}{})
+	return &Response{
+		Data:   data,
+		Errors: e.errors,
+	}
+}
+
+type executor struct {
+	errors []Error
+}
+
+func (e *executor) fail(path []interface{}, err error) {
+	e.errors = append(e.errors, Error{
+		Message: err.Error(),
+		Path:    path,
+	})
+}
+
+func (e *executor) selectFields(object Object, selections []*Field, path []interface{}) *OrderedMap {
+	result := newOrderedMap()
+	for _, field := range selections {
+		fieldPath := appendPath(path, field.Key())
+		if field.Name == "__typename" {
+			result.Set(field.Key(), object.TypeName())
+			continue
+		}
+		value, err := object.Resolve(field)
+		if err != nil {
+			e.fail(fieldPath, err)
+			result.Set(field.Key(), nil)
+			continue
+		}
+		result.Set(field.Key(), e.complete(value, field, fieldPath))
+	}
+	return result
+}
+
+func (e *executor) complete(value interface{}, field *Field, path []interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case Object:
+		if field.Selections == nil {
+			e.fail(path, fmt.Errorf("field '%s' must have a selection of subfields", field.Name))
+			return nil
+		}
+		return e.selectFields(v, field.Selections, path)
+	case []Object:
+		if field.Selections == nil {
+			e.fail(path, fmt.Errorf("field '%s' must have a selection of subfields", field.Name))
+			return nil
+		}
+		list := make([]interface{}, 0, len(v))
+		for i, item := range v {
+			list = append(list, e.selectFields(item, field.Selections, appendPath(path, i)))
+		}
+		return list
+	default:
+		if field.Selections != nil {
+			e.fail(path, fmt.Errorf("field '%s' is a scalar and cannot have a selection of subfields", field.Name))
+			return nil
+		}
+		return v
+	}
+}
+
+func appendPath(path []interface{}, elem interface{}) []interface{} {
+	newPath := make([]interface{}, len(path), len(path)+1)
+	copy(newPath, path)
+	return append(newPath, elem)
+}
+
+// stringArg returns the string value of an argument, or an empty string if it was not given
+func stringArg(field *Field, name string) (string, error) {
+	value, ok := field.Arguments[name]
+	if !ok || value == nil {
+		return "", nil
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("argument '%s' of field '%s' must be a string", name, field.Name)
+	}
+	return str, nil
+}
+
+// boolArg returns the boolean value of an argument, or false if it was not given
+func boolArg(field *Field, name string) (bool, error) {
+	value, ok := field.Arguments[name]
+	if !ok || value == nil {
+		return false, nil
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("argument '%s' of field '%s' must be a boolean", name, field.Name)
+	}
+	return b, nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testDevice struct {
+	id     string
+	values map[string]string
+}
+
+func (d *testDevice) TypeName() string {
+	return "Device"
+}
+
+func (d *testDevice) Resolve(field *Field) (interface{}, error) {
+	switch field.Name {
+	case "id":
+		return d.id, nil
+	case "value":
+		path, err := stringArg(field, "path")
+		if err != nil {
+			return nil, err
+		}
+		return d.values[path], nil
+	}
+	return nil, unknownField("Device", field)
+}
+
+type testRoot struct {
+	devices []Object
+}
+
+func (r *testRoot) TypeName() string {
+	return "Query"
+}
+
+func (r *testRoot) Resolve(field *Field) (interface{}, error) {
+	if field.Name == "devices" {
+		return r.devices, nil
+	}
+	return nil, unknownField("Query", field)
+}
+
+func newTestRoot() *testRoot {
+	return &testRoot{
+		devices: []Object{
+			&testDevice{id: "device-1", values: map[string]string{"/a": "1"}},
+			&testDevice{id: "device-2", values: map[string]string{"/a": "2"}},
+		},
+	}
+}
+
+func Test_ExecuteOrdered(t *testing.T) {
+	query, err := Parse(`{ devices { value(path: "/a") id } }`)
+	assert.NoError(t, err)
+
+	response := Execute(newTestRoot(), query)
+	assert.Empty(t, response.Errors)
+
+	bytes, err := json.Marshal(response)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"data":{"devices":[{"value":"1","id":"device-1"},{"value":"2","id":"device-2"}]}}`, string(bytes))
+}
+
+func Test_ExecuteTypeName(t *testing.T) {
+	query, err := Parse(`{ __typename devices { __typename id } }`)
+	assert.NoError(t, err)
+
+	response := Execute(newTestRoot(), query)
+	assert.Empty(t, response.Errors)
+
+	bytes, err := json.Marshal(response)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"data":{"__typename":"Query","devices":[{"__typename":"Device","id":"device-1"},{"__typename":"Device","id":"device-2"}]}}`, string(bytes))
+}
+
+func Test_ExecuteErrors(t *testing.T) {
+	query, err := Parse(`{ devices { id bogus value(path: 1) } }`)
+	assert.NoError(t, err)
+
+	response := Execute(newTestRoot(), query)
+	assert.Len(t, response.Errors, 4)
+	assert.Equal(t, "cannot query field 'bogus' on type 'Device'", response.Errors[0].Message)
+	assert.Equal(t, []interface{}{"devices", 0, "bogus"}, response.Errors[0].Path)
+	assert.Equal(t, "argument 'path' of field 'value' must be a string", response.Errors[1].Message)
+
+	devices, ok := response.Data.Get("devices")
+	assert.True(t, ok)
+	device := devices.([]interface{})[1].(*OrderedMap)
+	id, _ := device.Get("id")
+	assert.Equal(t, "device-2", id)
+	bogus, ok := device.Get("bogus")
+	assert.True(t, ok)
+	assert.Nil(t, bogus)
+}
+
+func Test_ExecuteSelectionRequired(t *testing.T) {
+	query, err := Parse(`{ devices }`)
+	assert.NoError(t, err)
+
+	response := Execute(newTestRoot(), query)
+	assert.Len(t, response.Errors, 1)
+	assert.Equal(t, "field 'devices' must have a selection of subfields", response.Errors[0].Message)
+
+	query, err = Parse(`{ devices { id { name } } }`)
+	assert.NoError(t, err)
+	response = Execute(newTestRoot(), query)
+	assert.Len(t, response.Errors, 2)
+}
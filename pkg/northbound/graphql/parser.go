@@ -0,0 +1,340 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Field is a single field selection in a query, possibly with arguments and
+// a nested selection set
+type Field struct {
+	Alias      string
+	Name       string
+	Arguments  map[string]interface{}
+	Selections []*Field
+}
+
+// Key returns the name under which the field result is reported
+func (f *Field) Key() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// Query is a parsed GraphQL query document. Only the query operation is supported;
+// mutations, subscriptions, fragments and variables are rejected by the parser.
+type Query struct {
+	Name       string
+	Selections []*Field
+}
+
+const (
+	// maxDepth is the deepest a query may nest selection sets, or list values
+	maxDepth = 10
+	// maxFields is the most fields a query may select, counting the fields of every selection set
+	maxFields = 500
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenString
+	tokenInt
+	tokenFloat
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// lex splits the query text into tokens
+func lex(text string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r) || r == ',' || r == '\uFEFF':
+			i++
+		case r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case strings.ContainsRune("{}():[]!=$@|&", r):
+			tokens = append(tokens, token{kind: tokenPunct, value: string(r), pos: i})
+			i++
+		case r == '.':
+			if i+2 < len(runes) && runes[i+1] == '.' && runes[i+2] == '.' {
+				tokens = append(tokens, token{kind: tokenPunct, value: "...", pos: i})
+				i += 3
+			} else {
+				return nil, fmt.Errorf("unexpected character '.' at %d", i)
+			}
+		case r == '"':
+			start := i
+			i++
+			var sb strings.Builder
+			for {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated string at %d", start)
+				}
+				if runes[i] == '"' {
+					i++
+					break
+				}
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					switch runes[i] {
+					case 'n':
+						sb.WriteRune('\n')
+					case 't':
+						sb.WriteRune('\t')
+					default:
+						sb.WriteRune(runes[i])
+					}
+					i++
+					continue
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenString, value: sb.String(), pos: start})
+		case r == '-' || unicode.IsDigit(r):
+			start := i
+			kind := tokenInt
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || strings.ContainsRune(".eE+-", runes[i])) {
+				if !unicode.IsDigit(runes[i]) {
+					kind = tokenFloat
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind: kind, value: string(runes[start:i]), pos: start})
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenName, value: string(runes[start:i]), pos: start})
+		default:
+			return nil, fmt.Errorf("unexpected character '%c' at %d", r, i)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+	fields int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expectPunct(value string) error {
+	t := p.next()
+	if t.kind != tokenPunct || t.value != value {
+		return fmt.Errorf("expected '%s' at %d, got '%s'", value, t.pos, t.value)
+	}
+	return nil
+}
+
+func (p *parser) isPunct(value string) bool {
+	t := p.peek()
+	return t.kind == tokenPunct && t.value == value
+}
+
+// Parse parses the text of a GraphQL query document
+func Parse(text string) (*Query, error) {
+	tokens, err := lex(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	query := &Query{}
+
+	if t := p.peek(); t.kind == tokenName {
+		switch t.value {
+		case "query":
+			p.next()
+			if p.peek().kind == tokenName {
+				query.Name = p.next().value
+			}
+			if p.isPunct("(") {
+				return nil, fmt.Errorf("query variables are not supported")
+			}
+		case "mutation", "subscription":
+			return nil, fmt.Errorf("%s operations are not supported", t.value)
+		default:
+			return nil, fmt.Errorf("unexpected '%s' at %d", t.value, t.pos)
+		}
+	}
+
+	query.Selections, err = p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected '%s' at %d; only a single operation is supported", t.value, t.pos)
+	}
+	return query, nil
+}
+
+func (p *parser) parseSelectionSet() ([]*Field, error) {
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return nil, fmt.Errorf("selection sets nested deeper than %d", maxDepth)
+	}
+	fields := make([]*Field, 0)
+	for !p.isPunct("}") {
+		if p.isPunct("...") {
+			return nil, fmt.Errorf("fragments are not supported")
+		}
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	p.next()
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty selection set")
+	}
+	return fields, nil
+}
+
+func (p *parser) parseField() (*Field, error) {
+	t := p.next()
+	if t.kind != tokenName {
+		return nil, fmt.Errorf("expected field name at %d, got '%s'", t.pos, t.value)
+	}
+	p.fields++
+	if p.fields > maxFields {
+		return nil, fmt.Errorf("more than %d fields selected", maxFields)
+	}
+	field := &Field{Name: t.value, Arguments: make(map[string]interface{})}
+	if p.isPunct(":") {
+		p.next()
+		t = p.next()
+		if t.kind != tokenName {
+			return nil, fmt.Errorf("expected field name after alias at %d", t.pos)
+		}
+		field.Alias = field.Name
+		field.Name = t.value
+	}
+	if p.isPunct("(") {
+		p.next()
+		for !p.isPunct(")") {
+			name := p.next()
+			if name.kind != tokenName {
+				return nil, fmt.Errorf("expected argument name at %d, got '%s'", name.pos, name.value)
+			}
+			if err := p.expectPunct(":"); err != nil {
+				return nil, err
+			}
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			field.Arguments[name.value] = value
+		}
+		p.next()
+	}
+	if p.isPunct("@") {
+		return nil, fmt.Errorf("directives are not supported")
+	}
+	if p.isPunct("{") {
+		selections, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		field.Selections = selections
+	}
+	return field, nil
+}
+
+func (p *parser) parseValue() (interface{}, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return t.value, nil
+	case tokenInt:
+		return strconv.ParseInt(t.value, 10, 64)
+	case tokenFloat:
+		return strconv.ParseFloat(t.value, 64)
+	case tokenName:
+		switch t.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		default:
+			// Enum values are passed through as strings
+			return t.value, nil
+		}
+	case tokenPunct:
+		switch t.value {
+		case "[":
+			p.depth++
+			defer func() { p.depth-- }()
+			if p.depth > maxDepth {
+				return nil, fmt.Errorf("lists nested deeper than %d", maxDepth)
+			}
+			list := make([]interface{}, 0)
+			for !p.isPunct("]") {
+				if p.peek().kind == tokenEOF {
+					return nil, fmt.Errorf("unterminated list at %d", t.pos)
+				}
+				value, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, value)
+			}
+			p.next()
+			return list, nil
+		case "$":
+			return nil, fmt.Errorf("query variables are not supported")
+		}
+	}
+	return nil, fmt.Errorf("unexpected '%s' at %d", t.value, t.pos)
+}
@@ -0,0 +1,95 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseNested(t *testing.T) {
+	query, err := Parse(`query Changes {
+		networkChanges(id: "change-*") {
+			id
+			devices { id current: values(path: "/system/...") { path value } }
+		}
+	}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "Changes", query.Name)
+	assert.Len(t, query.Selections, 1)
+
+	changes := query.Selections[0]
+	assert.Equal(t, "networkChanges", changes.Name)
+	assert.Equal(t, "change-*", changes.Arguments["id"])
+	assert.Len(t, changes.Selections, 2)
+
+	devices := changes.Selections[1]
+	assert.Equal(t, "devices", devices.Name)
+	values := devices.Selections[1]
+	assert.Equal(t, "current", values.Alias)
+	assert.Equal(t, "values", values.Name)
+	assert.Equal(t, "current", values.Key())
+	assert.Equal(t, "/system/...", values.Arguments["path"])
+	assert.Len(t, values.Selections, 2)
+}
+
+func Test_ParseArgumentTypes(t *testing.T) {
+	query, err := Parse(`{ devices(a: 1, b: 2.5, c: true, d: null, e: ENUM, f: ["x", 2]) { id } }`)
+	assert.NoError(t, err)
+	args := query.Selections[0].Arguments
+	assert.Equal(t, int64(1), args["a"])
+	assert.Equal(t, 2.5, args["b"])
+	assert.Equal(t, true, args["c"])
+	assert.Nil(t, args["d"])
+	assert.Equal(t, "ENUM", args["e"])
+	assert.Equal(t, []interface{}{"x", int64(2)}, args["f"])
+}
+
+func Test_ParseUnsupported(t *testing.T) {
+	_, err := Parse(`mutation { rollback(id: "x") { id } }`)
+	assert.EqualError(t, err, "mutation operations are not supported")
+
+	_, err = Parse(`query Q($id: String) { devices(id: $id) { id } }`)
+	assert.EqualError(t, err, "query variables are not supported")
+
+	_, err = Parse(`{ devices { ...DeviceFields } }`)
+	assert.EqualError(t, err, "fragments are not supported")
+
+	_, err = Parse(`{ devices { id }`)
+	assert.Error(t, err)
+
+	_, err = Parse(`{ devices(id: "x) { id } }`)
+	assert.Error(t, err)
+}
+
+func Test_ParseLimits(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat("{ a ", depth-1) + "{ b }" + strings.Repeat(" }", depth-1)
+	}
+	_, err := Parse(nested(maxDepth))
+	assert.NoError(t, err)
+	_, err = Parse(nested(maxDepth + 1))
+	assert.EqualError(t, err, "selection sets nested deeper than 10")
+
+	_, err = Parse(`{ devices(id: ` + strings.Repeat("[", maxDepth+1) + `) { id } }`)
+	assert.EqualError(t, err, "lists nested deeper than 10")
+
+	_, err = Parse("{ " + strings.Repeat("a ", maxFields) + "}")
+	assert.NoError(t, err)
+	_, err = Parse("{ " + strings.Repeat("a ", maxFields/2) + "b { " + strings.Repeat("a ", maxFields/2) + "} }")
+	assert.EqualError(t, err, "more than 500 fields selected")
+}
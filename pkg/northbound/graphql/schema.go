@@ -0,0 +1,552 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/secrets"
+	"github.com/onosproject/onos-config/pkg/store/change/device/state"
+	"github.com/onosproject/onos-config/pkg/store/device/cache"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// session is the state shared by the objects resolving a single query: the manager whose
+// stores are queried and the context of the call, whose caller values are redacted for
+type session struct {
+	ctx context.Context
+	mgr *manager.Manager
+}
+
+// redact returns the values of a device as they may be shown to the caller
+func (s *session) redact(deviceID devicetype.ID, values []*devicechange.PathValue) []*devicechange.PathValue {
+	return secrets.GetRegistry().RedactFor(s.ctx, string(deviceID), values)
+}
+
+// queryRoot is the root of the configuration graph
+//
+//	type Query {
+//	  devices(id: String, type: String): [Device]
+//	  networkChange(id: String!): NetworkChange
+//	  networkChanges(id: String): [NetworkChange]
+//	  snapshots(deviceId: String): [Snapshot]
+//	  search(value: String!, regex: Boolean): [SearchResult]
+//	}
+type queryRoot struct {
+	session *session
+}
+
+func (r *queryRoot) TypeName() string {
+	return "Query"
+}
+
+func (r *queryRoot) Resolve(field *Field) (interface{}, error) {
+	switch field.Name {
+	case "devices":
+		id, err := stringArg(field, "id")
+		if err != nil {
+			return nil, err
+		}
+		deviceType, err := stringArg(field, "type")
+		if err != nil {
+			return nil, err
+		}
+		matcher, err := nameMatcher(id)
+		if err != nil {
+			return nil, err
+		}
+		devices := make([]Object, 0)
+		for _, info := range r.session.mgr.DeviceCache.GetDevices() {
+			if !matcher.MatchString(string(info.DeviceID)) {
+				continue
+			}
+			if deviceType != "" && string(info.Type) != deviceType {
+				continue
+			}
+			devices = append(devices, &deviceObject{session: r.session, info: info})
+		}
+		return devices, nil
+	case "networkChange":
+		id, err := stringArg(field, "id")
+		if err != nil {
+			return nil, err
+		}
+		if id == "" {
+			return nil, errors.NewInvalid("argument 'id' is required")
+		}
+		change, err := r.session.mgr.NetworkChangesStore.Get(networkchange.ID(id))
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		} else if change == nil {
+			return nil, nil
+		}
+		return &networkChangeObject{session: r.session, change: change}, nil
+	case "networkChanges":
+		id, err := stringArg(field, "id")
+		if err != nil {
+			return nil, err
+		}
+		matcher, err := nameMatcher(id)
+		if err != nil {
+			return nil, err
+		}
+		ch := make(chan *networkchange.NetworkChange)
+		ctx, err := r.session.mgr.NetworkChangesStore.List(ch)
+		if err != nil {
+			return nil, err
+		}
+		defer ctx.Close()
+		changes := make([]Object, 0)
+		for change := range ch {
+			if matcher.MatchString(string(change.ID)) {
+				changes = append(changes, &networkChangeObject{session: r.session, change: change})
+			}
+		}
+		return changes, nil
+	case "snapshots":
+		deviceID, err := stringArg(field, "deviceId")
+		if err != nil {
+			return nil, err
+		}
+		matcher, err := nameMatcher(deviceID)
+		if err != nil {
+			return nil, err
+		}
+		ch := make(chan *devicesnapshot.Snapshot)
+		ctx, err := r.session.mgr.DeviceSnapshotStore.LoadAll(ch)
+		if err != nil {
+			return nil, err
+		}
+		defer ctx.Close()
+		snapshots := make([]Object, 0)
+		for snapshot := range ch {
+			if matcher.MatchString(string(snapshot.DeviceID)) {
+				snapshots = append(snapshots, &snapshotObject{session: r.session, snapshot: snapshot})
+			}
+		}
+		return snapshots, nil
+	case "search":
+		value, err := stringArg(field, "value")
+		if err != nil {
+			return nil, err
+		}
+		regex, err := boolArg(field, "regex")
+		if err != nil {
+			return nil, err
+		}
+		results, err := r.session.mgr.SearchValues(value, regex)
+		if err != nil {
+			return nil, err
+		}
+		objects := make([]Object, 0, len(results))
+		for _, result := range results {
+			if !secrets.GetRegistry().Searchable(r.session.ctx, result.Path) {
+				continue
+			}
+			objects = append(objects, &searchResultObject{session: r.session, result: result})
+		}
+		return objects, nil
+	}
+	return nil, unknownField("Query", field)
+}
+
+// deviceObject is a device known to the configuration system
+//
+//	type Device {
+//	  id: String
+//	  type: String
+//	  version: String
+//	  values(path: String): [PathValue]
+//	  changes: [DeviceChange]
+//	}
+type deviceObject struct {
+	session *session
+	info    *cache.Info
+}
+
+func (d *deviceObject) TypeName() string {
+	return "Device"
+}
+
+func (d *deviceObject) Resolve(field *Field) (interface{}, error) {
+	switch field.Name {
+	case "id":
+		return string(d.info.DeviceID), nil
+	case "type":
+		return string(d.info.Type), nil
+	case "version":
+		return string(d.info.Version), nil
+	case "values":
+		path, err := stringArg(field, "path")
+		if err != nil {
+			return nil, err
+		}
+		values, err := d.session.mgr.DeviceStateStore.Get(devicetype.NewVersionedID(d.info.DeviceID, d.info.Version), 0)
+		if err != nil {
+			return nil, err
+		}
+		return filterPathValues(d.session.redact(d.info.DeviceID, values), path)
+	case "changes":
+		ch := make(chan *devicechange.DeviceChange)
+		ctx, err := d.session.mgr.DeviceChangesStore.List(devicetype.NewVersionedID(d.info.DeviceID, d.info.Version), ch)
+		if err != nil {
+			return nil, err
+		}
+		defer ctx.Close()
+		changes := make([]Object, 0)
+		for change := range ch {
+			changes = append(changes, &deviceChangeObject{session: d.session, change: change})
+		}
+		return changes, nil
+	}
+	return nil, unknownField("Device", field)
+}
+
+// networkChangeObject is a network change
+//
+//	type NetworkChange {
+//	  id: String
+//	  index: Int
+//	  revision: Int
+//	  created: String
+//	  updated: String
+//	  phase: String
+//	  state: String
+//	  reason: String
+//	  message: String
+//	  incarnation: Int
+//	  devices: [Device]
+//	  changes: [Change]
+//	}
+type networkChangeObject struct {
+	session *session
+	change  *networkchange.NetworkChange
+}
+
+func (n *networkChangeObject) TypeName() string {
+	return "NetworkChange"
+}
+
+func (n *networkChangeObject) Resolve(field *Field) (interface{}, error) {
+	switch field.Name {
+	case "id":
+		return string(n.change.ID), nil
+	case "index":
+		return uint64(n.change.Index), nil
+	case "revision":
+		return uint64(n.change.Revision), nil
+	case "created":
+		return formatTime(n.change.Created), nil
+	case "updated":
+		return formatTime(n.change.Updated), nil
+	case "incarnation":
+		return n.change.Status.Incarnation, nil
+	case "phase", "state", "reason", "message":
+		return resolveStatus(n.change.Status, field.Name), nil
+	case "devices":
+		devices := make([]Object, 0, len(n.change.Changes))
+		for _, change := range n.change.Changes {
+			devices = append(devices, &deviceObject{session: n.session, info: changeDeviceInfo(change)})
+		}
+		return devices, nil
+	case "changes":
+		changes := make([]Object, 0, len(n.change.Changes))
+		for _, change := range n.change.Changes {
+			changes = append(changes, &changeObject{session: n.session, change: change})
+		}
+		return changes, nil
+	}
+	return nil, unknownField("NetworkChange", field)
+}
+
+// deviceChangeObject is a device change
+//
+//	type DeviceChange {
+//	  id: String
+//	  index: Int
+//	  revision: Int
+//	  networkChange: NetworkChange
+//	  phase: String
+//	  state: String
+//	  reason: String
+//	  message: String
+//	  incarnation: Int
+//	  change: Change
+//	}
+type deviceChangeObject struct {
+	session *session
+	change  *devicechange.DeviceChange
+}
+
+func (d *deviceChangeObject) TypeName() string {
+	return "DeviceChange"
+}
+
+func (d *deviceChangeObject) Resolve(field *Field) (interface{}, error) {
+	switch field.Name {
+	case "id":
+		return string(d.change.ID), nil
+	case "index":
+		return uint64(d.change.Index), nil
+	case "revision":
+		return uint64(d.change.Revision), nil
+	case "incarnation":
+		return d.change.Status.Incarnation, nil
+	case "phase", "state", "reason", "message":
+		return resolveStatus(d.change.Status, field.Name), nil
+	case "networkChange":
+		change, err := d.session.mgr.NetworkChangesStore.Get(networkchange.ID(d.change.NetworkChange.ID))
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		} else if change == nil {
+			return nil, nil
+		}
+		return &networkChangeObject{session: d.session, change: change}, nil
+	case "change":
+		if d.change.Change == nil {
+			return nil, nil
+		}
+		return &changeObject{session: d.session, change: d.change.Change}, nil
+	}
+	return nil, unknownField("DeviceChange", field)
+}
+
+// changeObject is the set of values changed on a single device
+//
+//	type Change {
+//	  device: Device
+//	  values: [PathValue]
+//	}
+type changeObject struct {
+	session *session
+	change  *devicechange.Change
+}
+
+func (c *changeObject) TypeName() string {
+	return "Change"
+}
+
+func (c *changeObject) Resolve(field *Field) (interface{}, error) {
+	switch field.Name {
+	case "device":
+		return &deviceObject{session: c.session, info: changeDeviceInfo(c.change)}, nil
+	case "values":
+		values := make([]Object, 0, len(c.change.Values))
+		for _, value := range c.change.Values {
+			values = append(values, &pathValueObject{
+				path:    value.Path,
+				value:   secrets.GetRegistry().RedactValueFor(c.session.ctx, string(c.change.DeviceID), value.Path, value.Value),
+				removed: value.Removed,
+			})
+		}
+		return values, nil
+	}
+	return nil, unknownField("Change", field)
+}
+
+// snapshotObject is a device snapshot
+//
+//	type Snapshot {
+//	  id: String
+//	  snapshotId: String
+//	  changeIndex: Int
+//	  device: Device
+//	  values(path: String): [PathValue]
+//	}
+type snapshotObject struct {
+	session  *session
+	snapshot *devicesnapshot.Snapshot
+}
+
+func (s *snapshotObject) TypeName() string {
+	return "Snapshot"
+}
+
+func (s *snapshotObject) Resolve(field *Field) (interface{}, error) {
+	switch field.Name {
+	case "id":
+		return string(s.snapshot.ID), nil
+	case "snapshotId":
+		return string(s.snapshot.SnapshotID), nil
+	case "changeIndex":
+		return uint64(s.snapshot.ChangeIndex), nil
+	case "device":
+		return &deviceObject{session: s.session, info: &cache.Info{
+			DeviceID: s.snapshot.DeviceID,
+			Type:     s.snapshot.DeviceType,
+			Version:  s.snapshot.DeviceVersion,
+		}}, nil
+	case "values":
+		path, err := stringArg(field, "path")
+		if err != nil {
+			return nil, err
+		}
+		return filterPathValues(s.session.redact(s.snapshot.DeviceID, s.snapshot.Values), path)
+	}
+	return nil, unknownField("Snapshot", field)
+}
+
+// pathValueObject is a single configuration value
+//
+//	type PathValue {
+//	  path: String
+//	  value: String
+//	  type: String
+//	  removed: Boolean
+//	}
+type pathValueObject struct {
+	path    string
+	value   *devicechange.TypedValue
+	removed bool
+}
+
+func (p *pathValueObject) TypeName() string {
+	return "PathValue"
+}
+
+func (p *pathValueObject) Resolve(field *Field) (interface{}, error) {
+	switch field.Name {
+	case "path":
+		return p.path, nil
+	case "value":
+		if p.value == nil {
+			return nil, nil
+		}
+		return p.value.ValueToString(), nil
+	case "type":
+		if p.value == nil {
+			return nil, nil
+		}
+		return p.value.Type.String(), nil
+	case "removed":
+		return p.removed, nil
+	}
+	return nil, unknownField("PathValue", field)
+}
+
+// searchResultObject is a device path whose value matched a search
+//
+//	type SearchResult {
+//	  device: Device
+//	  path: String
+//	  value: String
+//	  type: String
+//	}
+type searchResultObject struct {
+	session *session
+	result  *state.SearchResult
+}
+
+func (s *searchResultObject) TypeName() string {
+	return "SearchResult"
+}
+
+func (s *searchResultObject) Resolve(field *Field) (interface{}, error) {
+	switch field.Name {
+	case "device":
+		info := &cache.Info{
+			DeviceID: s.result.DeviceID.GetID(),
+			Version:  s.result.DeviceID.GetVersion(),
+		}
+		for _, cached := range s.session.mgr.DeviceCache.GetDevicesByID(info.DeviceID) {
+			if cached.Version == info.Version {
+				info.Type = cached.Type
+			}
+		}
+		return &deviceObject{session: s.session, info: info}, nil
+	case "path", "value", "type":
+		value := &pathValueObject{
+			path:  s.result.Path,
+			value: secrets.GetRegistry().RedactValueFor(s.session.ctx, string(s.result.DeviceID.GetID()), s.result.Path, s.result.Value),
+		}
+		return value.Resolve(field)
+	}
+	return nil, unknownField("SearchResult", field)
+}
+
+func filterPathValues(values []*devicechange.PathValue, path string) ([]Object, error) {
+	matcher, err := utils.CompileWildcardRegexp(path, false)
+	if err != nil {
+		return nil, errors.NewInvalid("invalid path '%s': %v", path, err)
+	}
+	objects := make([]Object, 0, len(values))
+	for _, value := range values {
+		if matcher.MatchString(value.Path) {
+			objects = append(objects, &pathValueObject{path: value.Path, value: value.Value})
+		}
+	}
+	return objects, nil
+}
+
+// nameMatcher returns the matcher of the device or change IDs selected by a wildcard argument;
+// every ID is selected if the argument is empty
+func nameMatcher(name string) (*regexp.Regexp, error) {
+	if name == "" {
+		return utils.CompileWildcardChNameRegexp("", false)
+	}
+	matcher, err := utils.CompileWildcardChNameRegexp(name, true)
+	if err != nil {
+		return nil, errors.NewInvalid("invalid ID '%s': %v", name, err)
+	}
+	return matcher, nil
+}
+
+func changeDeviceInfo(change *devicechange.Change) *cache.Info {
+	return &cache.Info{
+		DeviceID: change.DeviceID,
+		Type:     change.DeviceType,
+		Version:  change.DeviceVersion,
+	}
+}
+
+func resolveStatus(status changetypes.Status, name string) string {
+	switch name {
+	case "phase":
+		return status.Phase.String()
+	case "state":
+		return status.State.String()
+	case "reason":
+		return status.Reason.String()
+	default:
+		return status.Message
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func unknownField(typeName string, field *Field) error {
+	return fmt.Errorf("cannot query field '%s' on type '%s'", field.Name, typeName)
+}
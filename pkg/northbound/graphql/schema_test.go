@@ -0,0 +1,76 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"context"
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	"github.com/onosproject/onos-config/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+const passwordPath = "/system/aaa/authentication/admin-user/config/admin-password"
+
+func testSnapshot(ctx context.Context) Object {
+	return &snapshotObject{
+		session: &session{ctx: ctx},
+		snapshot: &devicesnapshot.Snapshot{
+			DeviceID: "device-1",
+			Values: []*devicechange.PathValue{
+				{Path: "/system/config/hostname", Value: devicechange.NewTypedValueString("switch1")},
+				{Path: passwordPath, Value: devicechange.NewTypedValueString("s3cr3t")},
+			},
+		},
+	}
+}
+
+func Test_SnapshotValuesRedacted(t *testing.T) {
+	secrets.GetRegistry().Register(passwordPath)
+	query, err := Parse(`{ values { path value } }`)
+	assert.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("name", "alice", "groups", "operators"))
+	response := Execute(testSnapshot(ctx), query)
+	assert.Empty(t, response.Errors)
+	values, _ := response.Data.Get("values")
+	hostname, _ := values.([]interface{})[0].(*OrderedMap).Get("value")
+	assert.Equal(t, "switch1", hostname)
+	password, _ := values.([]interface{})[1].(*OrderedMap).Get("value")
+	assert.Equal(t, secrets.RedactedValue, password)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("name", "bob", "groups", secrets.RevealSecretsGroup))
+	response = Execute(testSnapshot(ctx), query)
+	values, _ = response.Data.Get("values")
+	password, _ = values.([]interface{})[1].(*OrderedMap).Get("value")
+	assert.Equal(t, "s3cr3t", password)
+}
+
+func Test_InvalidArguments(t *testing.T) {
+	query, err := Parse(`{ values(path: "/system/(config") { path } }`)
+	assert.NoError(t, err)
+	response := Execute(testSnapshot(context.Background()), query)
+	assert.Len(t, response.Errors, 1)
+	assert.Contains(t, response.Errors[0].Message, "invalid path")
+
+	_, err = nameMatcher("device-[")
+	assert.Error(t, err)
+	matcher, err := nameMatcher("")
+	assert.NoError(t, err)
+	assert.True(t, matcher.MatchString("device-1"))
+}
@@ -0,0 +1,155 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graphql implements an optional GraphQL query endpoint over the configuration stores.
+//
+// Devices, network changes, device changes and snapshots are exposed as a graph so that
+// a GUI can fetch e.g. a change, the devices it touches and their current values in a
+// single round trip rather than by combining several admin and diags streams.
+// Only read-only queries are supported.
+//
+// Queries go through the same northbound interceptor chain as the gRPC services and are
+// restricted to administrators, as they span every device. Sensitive values are redacted.
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/onosproject/onos-config/pkg/manager"
+	"github.com/onosproject/onos-config/pkg/northbound"
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var log = logging.GetLogger("northbound", "graphql")
+
+// Path is the HTTP path on which queries are accepted
+const Path = "/graphql"
+
+// maxBodyBytes is the largest body of a POST request
+const maxBodyBytes = 1 << 20
+
+// Request is the body of a GraphQL HTTP POST request
+type Request struct {
+	Query         string `json:"query"`
+	OperationName string `json:"operationName,omitempty"`
+}
+
+// Server serves GraphQL queries over HTTP
+type Server struct {
+	port     int
+	caPath   string
+	certPath string
+	keyPath  string
+	chain    *interceptors.Chain
+}
+
+// NewServer creates a new GraphQL server listening with TLS on the given port, with the same
+// certificates as the gRPC services. Every query goes through the chain.
+func NewServer(port int, caPath string, certPath string, keyPath string, chain *interceptors.Chain) *Server {
+	return &Server{
+		port:     port,
+		caPath:   caPath,
+		certPath: certPath,
+		keyPath:  keyPath,
+		chain:    chain,
+	}
+}
+
+// Serve starts serving queries; it blocks until the server fails. It fails at once if the chain
+// cannot authenticate callers.
+func (s *Server) Serve() error {
+	if s.chain == nil || !s.chain.RequiresIdentity() {
+		return errors.NewInvalid("the GraphQL endpoint requires northbound authentication")
+	}
+	tlsCfg, err := northbound.TLSConfig(s.caPath, s.keyPath, s.certPath)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(Path, s)
+	server := &http.Server{
+		Addr:      fmt.Sprintf(":%d", s.port),
+		Handler:   mux,
+		TLSConfig: tlsCfg,
+	}
+	log.Infof("Starting GraphQL server on %s%s", server.Addr, Path)
+	return server.ListenAndServeTLS("", "")
+}
+
+// ServeHTTP handles a single GraphQL query. Queries are accepted either as a JSON POST
+// body or in the 'query' URL parameter of a GET.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, err := s.chain.InterceptHTTP(r, fmt.Sprintf("%s %s", r.Method, Path))
+	if err == nil {
+		err = interceptors.AuthorizeAdmin(ctx)
+	}
+	if err != nil {
+		if status.Code(err) == codes.Unauthenticated {
+			writeError(w, http.StatusUnauthorized, err)
+		} else {
+			writeError(w, http.StatusForbidden, err)
+		}
+		return
+	}
+
+	var request Request
+	switch r.Method {
+	case http.MethodGet:
+		request.Query = r.URL.Query().Get("query")
+	case http.MethodPost:
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	query, err := Parse(request.Query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	log.Debugf("Executing GraphQL query %s", request.Query)
+	response := Execute(&queryRoot{session: &session{ctx: ctx, mgr: manager.GetManager()}}, query)
+	writeResponse(w, http.StatusOK, response)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	log.Warnf("GraphQL request failed: %v", err)
+	writeResponse(w, status, &Response{
+		Errors: []Error{{Message: err.Error()}},
+	})
+}
+
+func writeResponse(w http.ResponseWriter, status int, response *Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Warnf("Failed writing GraphQL response: %v", err)
+	}
+}
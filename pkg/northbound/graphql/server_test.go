@@ -0,0 +1,83 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/onosproject/onos-config/pkg/northbound/interceptors"
+	"github.com/stretchr/testify/assert"
+)
+
+func testServer(t *testing.T) *Server {
+	apiKeys, err := interceptors.NewAPIKeyInterceptor(
+		interceptors.APIKey{Key: "adm1n", Name: "admin", Groups: []string{"AetherROCAdmin"}},
+		interceptors.APIKey{Key: "us3r", Name: "user", Groups: []string{"operators"}})
+	assert.NoError(t, err)
+	return NewServer(0, "", "", "", interceptors.NewChain(true, apiKeys))
+}
+
+func query(server *Server, apiKey string) *httptest.ResponseRecorder {
+	return post(server, apiKey, `{"query": "{ __typename }"}`)
+}
+
+func post(server *Server, apiKey string, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, Path, strings.NewReader(body))
+	if apiKey != "" {
+		request.Header.Set(interceptors.APIKeyMetadataKey, apiKey)
+	}
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func Test_ServeHTTPAuthorization(t *testing.T) {
+	assert.NoError(t, os.Setenv(interceptors.AdminGroupsEnv, "AetherROCAdmin"))
+	defer os.Unsetenv(interceptors.AdminGroupsEnv)
+	server := testServer(t)
+
+	response := query(server, "adm1n")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"data":{"__typename":"Query"}}`, response.Body.String())
+
+	assert.Equal(t, http.StatusUnauthorized, query(server, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, query(server, "bogus").Code)
+	assert.Equal(t, http.StatusForbidden, query(server, "us3r").Code)
+}
+
+func Test_ServeUnauthenticated(t *testing.T) {
+	assert.Error(t, NewServer(0, "", "", "", nil).Serve())
+	assert.Error(t, NewServer(0, "", "", "", interceptors.NewChain(false)).Serve())
+	assert.Equal(t, http.StatusUnauthorized, query(NewServer(0, "", "", "", nil), "adm1n").Code)
+	assert.Equal(t, http.StatusUnauthorized, query(NewServer(0, "", "", "", interceptors.NewChain(false)), "adm1n").Code)
+}
+
+func Test_ServeHTTPLimits(t *testing.T) {
+	assert.NoError(t, os.Setenv(interceptors.AdminGroupsEnv, "AetherROCAdmin"))
+	defer os.Unsetenv(interceptors.AdminGroupsEnv)
+	server := testServer(t)
+
+	response := post(server, "adm1n", `{"query": "{ __typename }", "padding": "`+strings.Repeat("x", maxBodyBytes)+`"}`)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.JSONEq(t, `{"data":null,"errors":[{"message":"http: request body too large"}]}`, response.Body.String())
+
+	response = post(server, "adm1n", `{"query": "`+strings.Repeat("{ a ", maxDepth)+`{ b }`+strings.Repeat(" }", maxDepth)+`"}`)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.JSONEq(t, `{"data":null,"errors":[{"message":"selection sets nested deeper than 10"}]}`, response.Body.String())
+}
@@ -0,0 +1,232 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grpcerrors maps the errors of onos-config to the gRPC statuses returned to the
+// northbound clients, so that every service reports the same kind of failure with the same code:
+//   - InvalidArgument when a request is invalid, e.g. it fails validation against the model
+//   - NotFound when something it names is unknown, e.g. a device or a network change
+//   - FailedPrecondition when the state of onos-config does not allow it, e.g. a change in progress
+//   - Unavailable when a device, or a store, cannot be reached
+//
+// The errors of onos-lib-go are mapped by their type, and errors that already are gRPC statuses
+// keep their code. Anything else is Internal. Each status carries a google.rpc.ErrorInfo detail
+// whose reason is the code, and the requests failing on a path carry a google.rpc.BadRequest
+// detail naming it, so that clients can tell failures apart without parsing the messages.
+package grpcerrors
+
+import (
+	"context"
+	goerrors "errors"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var log = logging.GetLogger("northbound", "grpcerrors")
+
+// Domain is the domain of the ErrorInfo details of the statuses
+const Domain = "config.onosproject.org"
+
+// Metadata keys of the ErrorInfo details
+const (
+	// TargetKey is the device the request failed on
+	TargetKey = "target"
+	// ChangeKey is the network change the request failed on
+	ChangeKey = "change"
+	// CategoryKey is the category of the rejection of a change by a device, e.g. syntax
+	CategoryKey = "category"
+	// DeviceCodeKey is the gRPC code a device rejected a change with
+	DeviceCodeKey = "device-code"
+	// DeviceMessageKey is the error message a device rejected a change with, as is
+	DeviceMessageKey = "device-message"
+	// PathKey is the gNMI path a device rejected
+	PathKey = "path"
+)
+
+// reasons are the ErrorInfo reasons of the codes
+var reasons = map[codes.Code]string{
+	codes.Canceled:           "CANCELLED",
+	codes.Unknown:            "UNKNOWN",
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "ABORTED",
+	codes.OutOfRange:         "OUT_OF_RANGE",
+	codes.Unimplemented:      "UNIMPLEMENTED",
+	codes.Internal:           "INTERNAL",
+	codes.Unavailable:        "UNAVAILABLE",
+	codes.DataLoss:           "DATA_LOSS",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+}
+
+// Reason returns the ErrorInfo reason of a code, e.g. NOT_FOUND
+func Reason(code codes.Code) string {
+	return reasons[code]
+}
+
+// detailedError annotates an error with the details of its status
+type detailedError struct {
+	err      error
+	metadata map[string]string
+	paths    []string
+}
+
+func (e *detailedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *detailedError) Unwrap() error {
+	return e.err
+}
+
+// WithMetadata annotates an error with metadata of the ErrorInfo detail of its status, given as
+// key and value pairs, e.g. the TargetKey and the ID of a device
+func WithMetadata(err error, keysAndValues ...string) error {
+	if err == nil {
+		return nil
+	}
+	detailed := &detailedError{err: err, metadata: make(map[string]string)}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		detailed.metadata[keysAndValues[i]] = keysAndValues[i+1]
+	}
+	return detailed
+}
+
+// WithPath annotates an error with the path of the request it is about, which is reported as a
+// field violation of the BadRequest detail of its status
+func WithPath(err error, path string) error {
+	if err == nil {
+		return nil
+	}
+	return &detailedError{err: err, paths: []string{path}}
+}
+
+// Status returns the gRPC status of an error, with its details
+func Status(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	metadata := make(map[string]string)
+	var paths []string
+	cause := err
+	for {
+		var detailed *detailedError
+		if !goerrors.As(cause, &detailed) {
+			break
+		}
+		for key, value := range detailed.metadata {
+			if _, ok := metadata[key]; !ok {
+				metadata[key] = value
+			}
+		}
+		paths = append(paths, detailed.paths...)
+		cause = detailed.err
+	}
+
+	st, ok := status.FromError(cause)
+	if !ok {
+		st = errors.Status(cause)
+	}
+	if st.Code() == codes.OK {
+		return st
+	}
+	for _, detail := range st.Details() {
+		if _, ok := detail.(*errdetails.ErrorInfo); ok {
+			return st
+		}
+	}
+
+	info := &errdetails.ErrorInfo{
+		Reason: Reason(st.Code()),
+		Domain: Domain,
+	}
+	if len(metadata) > 0 {
+		info.Metadata = metadata
+	}
+	withDetails, err := st.WithDetails(info)
+	if err != nil {
+		log.Warnf("Cannot add the details of %v: %v", st.Err(), err)
+		return st
+	}
+	if len(paths) > 0 && st.Code() == codes.InvalidArgument {
+		badRequest := &errdetails.BadRequest{}
+		for _, path := range paths {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       path,
+				Description: st.Message(),
+			})
+		}
+		if withPaths, err := withDetails.WithDetails(badRequest); err == nil {
+			withDetails = withPaths
+		}
+	}
+	return withDetails
+}
+
+// Err returns an error as a gRPC status error, with its details
+func Err(err error) error {
+	if err == nil {
+		return nil
+	}
+	return Status(err).Err()
+}
+
+// ErrorInfo returns the ErrorInfo detail of a gRPC status error, if it has one
+func ErrorInfo(err error) *errdetails.ErrorInfo {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	return nil
+}
+
+// ServerOptions returns the options that map the errors of every call to gRPC statuses. They
+// must come first, so that the errors of the interceptors that follow are mapped too.
+func ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor()),
+	}
+}
+
+// UnaryServerInterceptor maps the errors of unary calls
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, Err(err)
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor maps the errors of streaming calls
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return Err(handler(srv, stream))
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcerrors
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusCodes(t *testing.T) {
+	assert.Equal(t, codes.OK, Status(nil).Code())
+	assert.Nil(t, Err(nil))
+	assert.Equal(t, codes.InvalidArgument, Status(errors.NewInvalid("bad value")).Code())
+	assert.Equal(t, codes.NotFound, Status(errors.NewNotFound("no device")).Code())
+	assert.Equal(t, codes.FailedPrecondition, Status(errors.NewConflict("not pending")).Code())
+	assert.Equal(t, codes.Unavailable, Status(errors.NewUnavailable("unreachable")).Code())
+	assert.Equal(t, codes.Internal, Status(fmt.Errorf("something")).Code())
+	assert.Equal(t, codes.PermissionDenied, Status(status.Error(codes.PermissionDenied, "no")).Code())
+
+	err := Err(errors.NewNotFound("target d1 is not known"))
+	assert.EqualError(t, err, "rpc error: code = NotFound desc = target d1 is not known")
+}
+
+func TestErrorInfo(t *testing.T) {
+	err := Err(WithMetadata(errors.NewNotFound("target d1 is not known"), TargetKey, "d1"))
+	info := ErrorInfo(err)
+	if assert.NotNil(t, info) {
+		assert.Equal(t, "NOT_FOUND", info.Reason)
+		assert.Equal(t, Domain, info.Domain)
+		assert.Equal(t, map[string]string{TargetKey: "d1"}, info.Metadata)
+	}
+
+	// The details are added once
+	again := Err(err)
+	assert.Len(t, status.Convert(again).Details(), 1)
+
+	// Statuses created elsewhere keep their code and get the details
+	info = ErrorInfo(Err(status.Error(codes.AlreadyExists, "exists")))
+	if assert.NotNil(t, info) {
+		assert.Equal(t, "ALREADY_EXISTS", info.Reason)
+		assert.Nil(t, info.Metadata)
+	}
+	assert.Nil(t, ErrorInfo(fmt.Errorf("not a status")))
+}
+
+func TestWithPath(t *testing.T) {
+	err := Err(WithMetadata(WithPath(errors.NewInvalid("leaf1a is too long"), "/cont1a/leaf1a"), TargetKey, "d1"))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	var badRequest *errdetails.BadRequest
+	for _, detail := range status.Convert(err).Details() {
+		if b, ok := detail.(*errdetails.BadRequest); ok {
+			badRequest = b
+		}
+	}
+	if assert.NotNil(t, badRequest) && assert.Len(t, badRequest.FieldViolations, 1) {
+		assert.Equal(t, "/cont1a/leaf1a", badRequest.FieldViolations[0].Field)
+		assert.Equal(t, "leaf1a is too long", badRequest.FieldViolations[0].Description)
+	}
+	assert.Equal(t, "d1", ErrorInfo(err).Metadata[TargetKey])
+
+	// Paths are only reported for invalid requests
+	err = Err(WithPath(errors.NewUnavailable("store down"), "/cont1a/leaf1a"))
+	assert.Len(t, status.Convert(err).Details(), 1)
+}
+
+func TestInterceptors(t *testing.T) {
+	unary := UnaryServerInterceptor()
+	_, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test/Unary"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, errors.NewConflict("network change is not in progress")
+		})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, "FAILED_PRECONDITION", ErrorInfo(err).Reason)
+
+	resp, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test/Unary"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "ok", nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	stream := StreamServerInterceptor()
+	err = stream(nil, nil, &grpc.StreamServerInfo{FullMethod: "/test/Stream"},
+		func(srv interface{}, stream grpc.ServerStream) error {
+			return errors.NewUnavailable("device unreachable")
+		})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
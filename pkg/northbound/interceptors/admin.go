@@ -0,0 +1,49 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AdminGroupsEnv is the environment variable listing the groups of the administrators,
+// separated by commas or semicolons
+const AdminGroupsEnv = "ADMINGROUPS"
+
+// AuthorizeAdmin returns an error unless the caller was authenticated by the chain and belongs
+// to one of the administrator groups. Unlike the admin calls of onos-api, the administrative
+// operations guarded by it are refused when the northbound has no authentication configured.
+func AuthorizeAdmin(ctx context.Context) error {
+	user, groups := identity(ctx)
+	if user == "" {
+		return status.Error(codes.Unauthenticated, "administrative operations require an authenticated caller")
+	}
+	adminGroups := strings.FieldsFunc(os.Getenv(AdminGroupsEnv), func(r rune) bool {
+		return r == ',' || r == ';' || r == ' '
+	})
+	for _, group := range groups {
+		for _, adminGroup := range adminGroups {
+			if group == adminGroup {
+				return nil
+			}
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "'%s' is not an administrator", user)
+}
@@ -0,0 +1,99 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+)
+
+// APIKeyMetadataKey is the metadata key a client passes its API key in
+const APIKeyMetadataKey = "x-api-key"
+
+// APIKey is a key issued to a client and the identity it authenticates
+type APIKey struct {
+	// Key is the key itself
+	Key string `yaml:"key"`
+	// Name is the name of the caller the key is issued to
+	Name string `yaml:"name"`
+	// Groups are the groups of the caller
+	Groups []string `yaml:"groups"`
+}
+
+// NewAPIKeyInterceptor returns an authenticator identifying callers by the API key in the
+// x-api-key metadata. Only digests of the keys are kept in memory.
+func NewAPIKeyInterceptor(keys ...APIKey) (Interceptor, error) {
+	interceptor := &apiKeyInterceptor{
+		keys: make(map[string]APIKey),
+	}
+	for _, key := range keys {
+		if key.Key == "" || key.Name == "" {
+			return nil, errors.NewInvalid("API keys must have a key and a name")
+		}
+		digest := keyDigest(key.Key)
+		key.Key = ""
+		interceptor.keys[digest] = key
+	}
+	return interceptor, nil
+}
+
+// LoadAPIKeyInterceptor creates an API key authenticator from a YAML list of keys
+func LoadAPIKeyInterceptor(path string) (Interceptor, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]APIKey, 0)
+	if err := yaml.Unmarshal(data, &keys); err != nil {
+		return nil, errors.NewInvalid("cannot parse API keys file %s: %v", path, err)
+	}
+	return NewAPIKeyInterceptor(keys...)
+}
+
+type apiKeyInterceptor struct {
+	keys map[string]APIKey
+}
+
+func (i *apiKeyInterceptor) Name() string {
+	return InterceptorAPIKey
+}
+
+func (i *apiKeyInterceptor) Intercept(ctx context.Context, method string) (context.Context, error) {
+	if hasIdentity(ctx) {
+		return ctx, nil
+	}
+	value := metautils.ExtractIncoming(ctx).Get(APIKeyMetadataKey)
+	if value == "" {
+		return ctx, nil
+	}
+	key, ok := i.keys[keyDigest(value)]
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid API key")
+	}
+	return withIdentity(ctx, key.Name, "", key.Groups), nil
+}
+
+func keyDigest(key string) string {
+	digest := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(digest[:])
+}
@@ -0,0 +1,107 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// authzTimeout bounds each call to the authorization service
+const authzTimeout = 5 * time.Second
+
+// AuthzRequest is the body POSTed to the external authorization service for each call
+type AuthzRequest struct {
+	// Method is the full gRPC method name e.g. "/gnmi.gNMI/Set"
+	Method string `json:"method"`
+	// User is the name of the authenticated caller, if any
+	User string `json:"user,omitempty"`
+	// Groups are the groups of the authenticated caller
+	Groups []string `json:"groups,omitempty"`
+	// Resource is what the request operates on. It is absent when a stream is opened, in which
+	// case each request received on the stream is authorized on its own afterwards.
+	Resource *Resource `json:"resource,omitempty"`
+}
+
+// NewAuthzInterceptor returns an interceptor asking the authorization service at the given
+// URL whether each call is allowed, given the caller identity and the resources of the request.
+// The service answers 200 to allow the call and 403 to deny it; calls are denied if the service
+// cannot be reached.
+func NewAuthzInterceptor(url string) Interceptor {
+	return &authzInterceptor{
+		url:    url,
+		client: &http.Client{Timeout: authzTimeout},
+	}
+}
+
+type authzInterceptor struct {
+	url    string
+	client *http.Client
+}
+
+func (i *authzInterceptor) Name() string {
+	return InterceptorAuthz
+}
+
+func (i *authzInterceptor) Intercept(ctx context.Context, method string) (context.Context, error) {
+	return ctx, i.authorize(ctx, method, nil)
+}
+
+func (i *authzInterceptor) InterceptRequest(ctx context.Context, method string, req interface{}) (context.Context, error) {
+	return ctx, i.authorize(ctx, method, ResourceOf(method, req))
+}
+
+func (i *authzInterceptor) authorize(ctx context.Context, method string, resource *Resource) error {
+	user, groups := identity(ctx)
+	body, err := json.Marshal(&AuthzRequest{
+		Method:   method,
+		User:     user,
+		Groups:   groups,
+		Resource: resource,
+	})
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, i.url, bytes.NewReader(body))
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := i.client.Do(request)
+	if err != nil {
+		log.Warnf("Authorization service %s unavailable: %v", i.url, err)
+		return status.Error(codes.Unavailable, "authorization service unavailable")
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusForbidden, http.StatusUnauthorized:
+		if resource != nil {
+			return status.Errorf(codes.PermissionDenied, "%s on %v denied for '%s'", resource.Operation, resource.Devices, user)
+		}
+		return status.Errorf(codes.PermissionDenied, "%s denied for '%s'", method, user)
+	default:
+		log.Warnf("Authorization service %s answered %s", i.url, response.Status)
+		return status.Error(codes.Unavailable, "authorization service unavailable")
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package interceptors builds the chain of authentication and authorization interceptors
+// that guards the northbound services, over gRPC as well as over HTTP.
+//
+// Authenticators establish the identity of the caller and publish it in the incoming
+// metadata under the "name", "email" and "groups" keys, the same keys the InterceptorJWT claims
+// have always been published under, so the services do not need to know which
+// mechanism authenticated the caller. Any identity metadata sent by the client itself
+// is removed before the chain runs.
+package interceptors
+
+import (
+	"context"
+	"strings"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var log = logging.GetLogger("northbound", "interceptors")
+
+// Metadata keys of the caller identity
+const (
+	NameKey   = "name"
+	EmailKey  = "email"
+	GroupsKey = "groups"
+)
+
+// groupSeparator separates the groups in the GroupsKey metadata
+const groupSeparator = ";"
+
+// Names of the interceptors that can be configured
+const (
+	InterceptorJWT    = "jwt"
+	InterceptorMTLS   = "mtls"
+	InterceptorAPIKey = "apikey"
+	InterceptorAuthz  = "authz"
+)
+
+// Interceptor is a single step of the chain
+type Interceptor interface {
+	// Name returns the name the interceptor is configured by
+	Name() string
+
+	// Intercept is called before each call to the given method and returns the context the call
+	// continues with. An error aborts the call.
+	Intercept(ctx context.Context, method string) (context.Context, error)
+}
+
+// RequestInterceptor is an Interceptor that also inspects the requests of the calls, e.g. to
+// authorize them per resource. It is given each unary request instead of being called through
+// Intercept, and each message received on a stream once Intercept has accepted the stream.
+type RequestInterceptor interface {
+	Interceptor
+
+	// InterceptRequest is called with each request of the given method
+	InterceptRequest(ctx context.Context, method string, req interface{}) (context.Context, error)
+}
+
+// Chain runs interceptors in order before each unary and stream call
+type Chain struct {
+	interceptors    []Interceptor
+	requireIdentity bool
+}
+
+// NewChain creates a chain of the given interceptors. If requireIdentity is true, calls
+// for which none of the interceptors established an identity are rejected.
+func NewChain(requireIdentity bool, interceptors ...Interceptor) *Chain {
+	return &Chain{
+		interceptors:    interceptors,
+		requireIdentity: requireIdentity,
+	}
+}
+
+// RequiresIdentity returns true if calls that no interceptor could authenticate are rejected
+func (c *Chain) RequiresIdentity() bool {
+	return c.requireIdentity
+}
+
+// Names returns the names of the interceptors in the chain, in order
+func (c *Chain) Names() []string {
+	names := make([]string, 0, len(c.interceptors))
+	for _, interceptor := range c.interceptors {
+		names = append(names, interceptor.Name())
+	}
+	return names
+}
+
+// ServerOptions returns the options that install the chain on a gRPC server. The chained
+// interceptor options are used so that the chain can coexist with other interceptors.
+func (c *Chain) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(c.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(c.StreamServerInterceptor()),
+	}
+}
+
+// UnaryServerInterceptor returns the chain as a unary interceptor
+func (c *Chain) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := c.Intercept(ctx, info.FullMethod, req)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns the chain as a stream interceptor
+func (c *Chain) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := c.Intercept(stream.Context(), info.FullMethod, nil)
+		if err != nil {
+			return err
+		}
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = ctx
+		return handler(srv, &interceptedStream{WrappedServerStream: wrapped, chain: c, method: info.FullMethod})
+	}
+}
+
+// Intercept runs the chain for a call; req is nil when a stream is opened
+func (c *Chain) Intercept(ctx context.Context, method string, req interface{}) (context.Context, error) {
+	ctx = stripIdentity(ctx)
+	for _, interceptor := range c.interceptors {
+		var err error
+		if requestInterceptor, ok := interceptor.(RequestInterceptor); ok && req != nil {
+			ctx, err = requestInterceptor.InterceptRequest(ctx, method, req)
+		} else {
+			ctx, err = interceptor.Intercept(ctx, method)
+		}
+		if err != nil {
+			log.Infof("%s rejected by %s interceptor: %v", method, interceptor.Name(), err)
+			return nil, err
+		}
+	}
+	if c.requireIdentity && !hasIdentity(ctx) {
+		return nil, status.Errorf(codes.Unauthenticated, "no credentials accepted for %s", method)
+	}
+	return ctx, nil
+}
+
+// interceptedStream passes each message received on a stream to the request interceptors
+type interceptedStream struct {
+	*grpc_middleware.WrappedServerStream
+	chain  *Chain
+	method string
+}
+
+func (s *interceptedStream) RecvMsg(m interface{}) error {
+	if err := s.WrappedServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	for _, interceptor := range s.chain.interceptors {
+		if requestInterceptor, ok := interceptor.(RequestInterceptor); ok {
+			if _, err := requestInterceptor.InterceptRequest(s.Context(), s.method, m); err != nil {
+				log.Infof("%s request rejected by %s interceptor: %v", s.method, interceptor.Name(), err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NewInterceptor creates the interceptor with the given name
+func NewInterceptor(name string, config Config) (Interceptor, error) {
+	switch name {
+	case InterceptorJWT:
+		return NewJWTInterceptor(), nil
+	case InterceptorMTLS:
+		if config.CertIdentitiesPath == "" {
+			return NewMTLSInterceptor()
+		}
+		return LoadMTLSInterceptor(config.CertIdentitiesPath)
+	case InterceptorAPIKey:
+		if config.APIKeysPath == "" {
+			return nil, errors.NewInvalid("the %s interceptor requires an API keys file", InterceptorAPIKey)
+		}
+		return LoadAPIKeyInterceptor(config.APIKeysPath)
+	case InterceptorAuthz:
+		if config.AuthzURL == "" {
+			return nil, errors.NewInvalid("the %s interceptor requires an authorization service URL", InterceptorAuthz)
+		}
+		return NewAuthzInterceptor(config.AuthzURL), nil
+	default:
+		return nil, errors.NewNotSupported("unknown interceptor '%s'", name)
+	}
+}
+
+// Config is the configuration of the interceptors that need some
+type Config struct {
+	// APIKeysPath is the YAML file of API keys used by the apikey interceptor
+	APIKeysPath string
+	// CertIdentitiesPath is the YAML file of the groups of the client certificates used by the
+	// mtls interceptor; certificates that are not listed authenticate callers without groups
+	CertIdentitiesPath string
+	// AuthzURL is the endpoint of the external authorization service used by the authz interceptor
+	AuthzURL string
+}
+
+// BuildChain creates a chain of the named interceptors, in the given order. Calls must
+// be authenticated by one of them if any authenticator is part of the chain. The authz
+// interceptor has to follow an authenticator, since it decides on the identity of the caller.
+func BuildChain(names []string, config Config) (*Chain, error) {
+	interceptors := make([]Interceptor, 0, len(names))
+	requireIdentity := false
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		interceptor, err := NewInterceptor(name, config)
+		if err != nil {
+			return nil, err
+		}
+		if name != InterceptorAuthz {
+			requireIdentity = true
+		} else if !requireIdentity {
+			return nil, errors.NewInvalid("the %s interceptor must follow an authenticator", InterceptorAuthz)
+		}
+		interceptors = append(interceptors, interceptor)
+	}
+	return NewChain(requireIdentity, interceptors...), nil
+}
+
+func stripIdentity(ctx context.Context) context.Context {
+	md := metautils.ExtractIncoming(ctx).Clone()
+	md.Del(NameKey)
+	md.Del(EmailKey)
+	md.Del(GroupsKey)
+	return md.ToIncoming(ctx)
+}
+
+func hasIdentity(ctx context.Context) bool {
+	return metautils.ExtractIncoming(ctx).Get(NameKey) != ""
+}
+
+// withIdentity publishes the identity of the caller in the incoming metadata
+func withIdentity(ctx context.Context, name string, email string, groups []string) context.Context {
+	md := metautils.ExtractIncoming(ctx).Clone()
+	md.Set(NameKey, name)
+	if email != "" {
+		md.Set(EmailKey, email)
+	}
+	if len(groups) > 0 {
+		md.Set(GroupsKey, strings.Join(groups, groupSeparator))
+	}
+	return md.ToIncoming(ctx)
+}
+
+// identity returns the caller identity established by the authenticators
+func identity(ctx context.Context) (string, []string) {
+	md := metautils.ExtractIncoming(ctx)
+	var groups []string
+	if md.Get(GroupsKey) != "" {
+		groups = strings.Split(md.Get(GroupsKey), groupSeparator)
+	}
+	return md.Get(NameKey), groups
+}
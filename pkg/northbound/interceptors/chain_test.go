@@ -0,0 +1,185 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const setMethod = "/gnmi.gNMI/Set"
+
+func callUnary(ctx context.Context, chain *Chain) (context.Context, error) {
+	var handled context.Context
+	_, err := chain.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: setMethod},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			handled = ctx
+			return nil, nil
+		})
+	return handled, err
+}
+
+func incoming(pairs ...string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+}
+
+func Test_APIKeyChain(t *testing.T) {
+	apiKeys, err := NewAPIKeyInterceptor(APIKey{Key: "k3y", Name: "ci-robot", Groups: []string{"AetherROCAdmin"}})
+	assert.NoError(t, err)
+	chain := NewChain(true, apiKeys)
+
+	ctx, err := callUnary(incoming(APIKeyMetadataKey, "k3y"), chain)
+	assert.NoError(t, err)
+	md := metautils.ExtractIncoming(ctx)
+	assert.Equal(t, "ci-robot", md.Get(NameKey))
+	assert.Equal(t, "AetherROCAdmin", md.Get(GroupsKey))
+
+	_, err = callUnary(incoming(APIKeyMetadataKey, "wrong"), chain)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// A client cannot claim an identity without credentials
+	_, err = callUnary(incoming(NameKey, "admin", GroupsKey, "AetherROCAdmin"), chain)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_CombinedChain(t *testing.T) {
+	first, err := NewAPIKeyInterceptor(APIKey{Key: "first", Name: "first-user"})
+	assert.NoError(t, err)
+	second, err := NewAPIKeyInterceptor(APIKey{Key: "first", Name: "second-user"})
+	assert.NoError(t, err)
+	mtls, err := NewMTLSInterceptor()
+	assert.NoError(t, err)
+	chain := NewChain(true, mtls, first, second)
+	assert.Equal(t, []string{InterceptorMTLS, InterceptorAPIKey, InterceptorAPIKey}, chain.Names())
+
+	// The first authenticator to establish an identity wins
+	ctx, err := callUnary(incoming(APIKeyMetadataKey, "first"), chain)
+	assert.NoError(t, err)
+	assert.Equal(t, "first-user", metautils.ExtractIncoming(ctx).Get(NameKey))
+}
+
+func Test_AuthzInterceptor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &AuthzRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		assert.Equal(t, setMethod, request.Method)
+		if request.User == "ci-robot" {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	robot, err := NewAPIKeyInterceptor(
+		APIKey{Key: "robot", Name: "ci-robot"},
+		APIKey{Key: "other", Name: "someone-else"})
+	assert.NoError(t, err)
+	chain := NewChain(true, robot, NewAuthzInterceptor(server.URL))
+
+	_, err = callUnary(incoming(APIKeyMetadataKey, "robot"), chain)
+	assert.NoError(t, err)
+	_, err = callUnary(incoming(APIKeyMetadataKey, "other"), chain)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	unreachable := NewChain(false, NewAuthzInterceptor("http://127.0.0.1:1"))
+	_, err = callUnary(context.Background(), unreachable)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func Test_BuildChain(t *testing.T) {
+	chain, err := BuildChain([]string{InterceptorJWT, " mtls "}, Config{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{InterceptorJWT, InterceptorMTLS}, chain.Names())
+	assert.True(t, chain.requireIdentity)
+
+	chain, err = BuildChain([]string{InterceptorJWT, InterceptorAuthz}, Config{AuthzURL: "http://authz"})
+	assert.NoError(t, err)
+	assert.True(t, chain.RequiresIdentity())
+
+	// authz decides on the identity established before it
+	_, err = BuildChain([]string{InterceptorAuthz}, Config{AuthzURL: "http://authz"})
+	assert.Error(t, err)
+	_, err = BuildChain([]string{InterceptorAuthz, InterceptorJWT}, Config{AuthzURL: "http://authz"})
+	assert.Error(t, err)
+
+	chain, err = BuildChain([]string{""}, Config{})
+	assert.NoError(t, err)
+	assert.False(t, chain.RequiresIdentity())
+
+	_, err = BuildChain([]string{InterceptorAPIKey}, Config{})
+	assert.Error(t, err)
+	_, err = BuildChain([]string{"kerberos"}, Config{})
+	assert.Error(t, err)
+}
+
+func withCertificate(ctx context.Context, subject pkix.Name) context.Context {
+	return peer.NewContext(ctx, &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{{Subject: subject}}},
+			},
+		},
+	})
+}
+
+func Test_MTLSInterceptor(t *testing.T) {
+	mtls, err := NewMTLSInterceptor(CertificateIdentity{Name: "ci-robot", Groups: []string{"AetherROCAdmin"}})
+	assert.NoError(t, err)
+	chain := NewChain(true, mtls)
+
+	ctx, err := callUnary(withCertificate(context.Background(), pkix.Name{CommonName: "ci-robot"}), chain)
+	assert.NoError(t, err)
+	assert.Equal(t, "ci-robot", metautils.ExtractIncoming(ctx).Get(NameKey))
+	assert.Equal(t, "AetherROCAdmin", metautils.ExtractIncoming(ctx).Get(GroupsKey))
+
+	// The groups come from the server side mapping, never from the certificate
+	ctx, err = callUnary(withCertificate(context.Background(), pkix.Name{
+		CommonName:         "someone",
+		OrganizationalUnit: []string{"AetherROCAdmin"},
+	}), chain)
+	assert.NoError(t, err)
+	assert.Equal(t, "someone", metautils.ExtractIncoming(ctx).Get(NameKey))
+	assert.Equal(t, "", metautils.ExtractIncoming(ctx).Get(GroupsKey))
+
+	_, err = callUnary(context.Background(), chain)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func Test_AuthorizeAdmin(t *testing.T) {
+	assert.NoError(t, os.Setenv(AdminGroupsEnv, "AetherROCAdmin,mec-admin"))
+	defer os.Unsetenv(AdminGroupsEnv)
+
+	assert.NoError(t, AuthorizeAdmin(withIdentity(context.Background(), "admin", "", []string{"users", "mec-admin"})))
+	assert.Equal(t, codes.PermissionDenied, status.Code(AuthorizeAdmin(withIdentity(context.Background(), "user", "", []string{"users"}))))
+	assert.Equal(t, codes.PermissionDenied, status.Code(AuthorizeAdmin(withIdentity(context.Background(), "user", "", nil))))
+	assert.Equal(t, codes.Unauthenticated, status.Code(AuthorizeAdmin(context.Background())))
+}
@@ -0,0 +1,64 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// httpCredentialHeaders are the headers of an HTTP call that are presented to the chain as metadata
+var httpCredentialHeaders = []string{"Authorization", APIKeyMetadataKey}
+
+// InterceptHTTP runs the chain for an HTTP call, whose 'Authorization' and 'X-Api-Key' headers
+// and client certificate are presented to the interceptors as they would be by a gRPC client.
+// Unlike gRPC calls, HTTP calls are rejected if no interceptor could authenticate the caller,
+// whatever the chain.
+func (c *Chain) InterceptHTTP(r *http.Request, method string) (context.Context, error) {
+	if c == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "no authentication configured for %s", method)
+	}
+	ctx, err := c.Intercept(incomingContext(r), method, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !hasIdentity(ctx) {
+		return nil, status.Errorf(codes.Unauthenticated, "no credentials accepted for %s", method)
+	}
+	return ctx, nil
+}
+
+// incomingContext presents the credentials of an HTTP call as those of a gRPC call
+func incomingContext(r *http.Request) context.Context {
+	md := metautils.NiceMD(metadata.MD{})
+	for _, header := range httpCredentialHeaders {
+		if value := r.Header.Get(header); value != "" {
+			md.Set(strings.ToLower(header), value)
+		}
+	}
+	ctx := md.ToIncoming(r.Context())
+	if r.TLS != nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{State: *r.TLS}})
+	}
+	return ctx
+}
@@ -0,0 +1,44 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"context"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	"github.com/onosproject/onos-lib-go/pkg/grpc/auth"
+)
+
+// authorizationKey is the metadata key of the bearer token
+const authorizationKey = "authorization"
+
+// NewJWTInterceptor returns an authenticator validating the bearer token of the call against
+// the OIDC server, with the claims of the token published as the caller identity
+func NewJWTInterceptor() Interceptor {
+	return &jwtInterceptor{}
+}
+
+type jwtInterceptor struct{}
+
+func (i *jwtInterceptor) Name() string {
+	return InterceptorJWT
+}
+
+func (i *jwtInterceptor) Intercept(ctx context.Context, method string) (context.Context, error) {
+	if hasIdentity(ctx) || metautils.ExtractIncoming(ctx).Get(authorizationKey) == "" {
+		return ctx, nil
+	}
+	return auth.AuthenticationInterceptor(ctx)
+}
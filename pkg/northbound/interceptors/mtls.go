@@ -0,0 +1,91 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"context"
+	"io/ioutil"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"gopkg.in/yaml.v2"
+)
+
+// CertificateIdentity gives the groups of the caller whose client certificate has the given
+// common name
+type CertificateIdentity struct {
+	// Name is the common name of the certificate subject
+	Name string `yaml:"name"`
+	// Groups are the groups of the caller
+	Groups []string `yaml:"groups"`
+}
+
+// NewMTLSInterceptor returns an authenticator identifying the caller by the common name of its
+// verified client certificate. The groups of the caller are those given here for that name:
+// the fields of the certificate other than the common name are not trusted, so that a
+// certificate issued by the CA for some other purpose cannot claim e.g. admin rights.
+func NewMTLSInterceptor(identities ...CertificateIdentity) (Interceptor, error) {
+	interceptor := &mtlsInterceptor{
+		groups: make(map[string][]string),
+	}
+	for _, identity := range identities {
+		if identity.Name == "" {
+			return nil, errors.NewInvalid("certificate identities must have a name")
+		}
+		interceptor.groups[identity.Name] = identity.Groups
+	}
+	return interceptor, nil
+}
+
+// LoadMTLSInterceptor creates a client certificate authenticator from a YAML list of identities
+func LoadMTLSInterceptor(path string) (Interceptor, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	identities := make([]CertificateIdentity, 0)
+	if err := yaml.Unmarshal(data, &identities); err != nil {
+		return nil, errors.NewInvalid("cannot parse certificate identities file %s: %v", path, err)
+	}
+	return NewMTLSInterceptor(identities...)
+}
+
+type mtlsInterceptor struct {
+	groups map[string][]string
+}
+
+func (i *mtlsInterceptor) Name() string {
+	return InterceptorMTLS
+}
+
+func (i *mtlsInterceptor) Intercept(ctx context.Context, method string) (context.Context, error) {
+	if hasIdentity(ctx) {
+		return ctx, nil
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ctx, nil
+	}
+	name := tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
+	if name == "" {
+		return ctx, nil
+	}
+	return withIdentity(ctx, name, "", i.groups[name]), nil
+}
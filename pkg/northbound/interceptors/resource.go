@@ -0,0 +1,195 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/onosproject/onos-api/go/onos/config/admin"
+	"github.com/onosproject/onos-api/go/onos/config/diags"
+	adminv2 "github.com/onosproject/onos-config/api/admin/v2"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Operations a call may be authorized for
+const (
+	OperationGet       = "get"
+	OperationSet       = "set"
+	OperationSubscribe = "subscribe"
+)
+
+// AllDevices is the device of the calls that may operate on any device, e.g. a Get of target
+// "*", listing every snapshot or compacting the change stores
+const AllDevices = "*"
+
+// Resource describes what a call operates on, so that authorization policies can be written
+// per device and per path rather than only per RPC method
+type Resource struct {
+	// Operation is the kind of call e.g. "set"; for non gNMI calls it is the lower case method name
+	Operation string `json:"operation"`
+	// Devices are the targets of the call, sorted; AllDevices stands for any device
+	Devices []string `json:"devices,omitempty"`
+	// Paths are the full paths the call reads or writes, without their target, sorted
+	Paths []string `json:"paths,omitempty"`
+	// Updates, Replaces and Deletes count the operations of a Set
+	Updates  int `json:"updates,omitempty"`
+	Replaces int `json:"replaces,omitempty"`
+	Deletes  int `json:"deletes,omitempty"`
+}
+
+// ResourceOf describes the resources the request of the given method operates on
+func ResourceOf(method string, req interface{}) *Resource {
+	r := &resourceBuilder{
+		devices: make(map[string]bool),
+		paths:   make(map[string]bool),
+	}
+	switch request := req.(type) {
+	case *gnmi.SetRequest:
+		r.resource.Operation = OperationSet
+		for _, update := range request.GetUpdate() {
+			r.addPath(request.GetPrefix(), update.GetPath())
+		}
+		for _, replace := range request.GetReplace() {
+			r.addPath(request.GetPrefix(), replace.GetPath())
+		}
+		for _, path := range request.GetDelete() {
+			r.addPath(request.GetPrefix(), path)
+		}
+		r.resource.Updates = len(request.GetUpdate())
+		r.resource.Replaces = len(request.GetReplace())
+		r.resource.Deletes = len(request.GetDelete())
+	case *gnmi.GetRequest:
+		r.resource.Operation = OperationGet
+		for _, path := range request.GetPath() {
+			r.addPath(request.GetPrefix(), path)
+		}
+	case *gnmi.SubscribeRequest:
+		r.resource.Operation = OperationSubscribe
+		subscriptions := request.GetSubscribe()
+		for _, subscription := range subscriptions.GetSubscription() {
+			r.addPath(subscriptions.GetPrefix(), subscription.GetPath())
+		}
+	default:
+		r.resource.Operation = strings.ToLower(method[strings.LastIndex(method, "/")+1:])
+		r.addRequestDevices(req)
+	}
+	return r.build()
+}
+
+// addRequestDevices adds the devices the admin and diags requests operate on. Requests on a
+// network change, on every device or on a device wildcard operate on AllDevices.
+func (r *resourceBuilder) addRequestDevices(req interface{}) {
+	switch request := req.(type) {
+	case *admin.ListSnapshotsRequest:
+		id := string(request.ID)
+		if strings.Count(id, ":") >= 2 {
+			r.addDevice(string(request.ID.GetDeviceID()))
+		} else {
+			r.addDevice(AllDevices)
+		}
+	case *admin.RollbackRequest, *admin.CompactChangesRequest:
+		r.addDevice(AllDevices)
+	case *diags.OpStateRequest:
+		r.addDevice(request.DeviceId)
+	case *diags.ListDeviceChangeRequest:
+		r.addDevice(string(request.DeviceID))
+	case *diags.ListNetworkChangeRequest:
+		r.addDevice(AllDevices)
+	case *adminv2.ListNetworkChangesRequest, *adminv2.GetNetworkChangeRequest, *adminv2.WatchNetworkChangesRequest,
+		*adminv2.RollbackNetworkChangeRequest, *adminv2.ListSnapshotsRequest, *adminv2.WatchSnapshotsRequest,
+		*adminv2.CompactChangesRequest:
+		r.addDevice(AllDevices)
+	case *adminv2.ListDeviceChangesRequest:
+		r.addDevice(request.DeviceId)
+	case *adminv2.WatchDeviceChangesRequest:
+		r.addDevice(request.DeviceId)
+	case *adminv2.GetSnapshotRequest:
+		r.addDevice(request.DeviceId)
+	case *adminext.RollbackRequest, *adminext.SearchValuesRequest, *adminext.CompactChangesRequest,
+		*adminext.ListSnapshotDevicesRequest, *adminext.ListQuarantinedDevicesRequest, *adminext.DeleteSubtreeRequest,
+		*adminext.GetDeviceGroupStatusRequest:
+		r.addDevice(AllDevices)
+	case *adminext.AdoptConfigRequest:
+		r.addDevice(request.DeviceId)
+	case *adminext.SimulateChangeRequest:
+		r.addDevice(request.DeviceId)
+	case *adminext.TestConnectionRequest:
+		r.addDevice(request.DeviceId)
+	case *adminext.GetSnapshotValuesRequest:
+		r.addDevice(request.DeviceId)
+	case *adminext.RebindDeviceRequest:
+		r.addDevice(request.DeviceId)
+	case *adminext.MoveListEntryRequest:
+		r.addDevice(request.DeviceId)
+	case *adminext.CountListEntriesRequest:
+		r.addDevice(request.DeviceId)
+	case *adminext.ReplaceDeviceConfigRequest:
+		r.addDevice(request.DeviceId)
+	}
+}
+
+// addDevice adds a device of an admin or diags request; an empty ID or a wildcard is AllDevices
+func (r *resourceBuilder) addDevice(id string) {
+	if id == "" || strings.ContainsAny(id, "*?") {
+		id = AllDevices
+	}
+	r.devices[id] = true
+}
+
+type resourceBuilder struct {
+	resource Resource
+	devices  map[string]bool
+	paths    map[string]bool
+}
+
+// addPath adds a path relative to the prefix; the target of the path, if any, wins over the prefix's
+func (r *resourceBuilder) addPath(prefix *gnmi.Path, path *gnmi.Path) {
+	target := path.GetTarget()
+	if target == "" {
+		target = prefix.GetTarget()
+	}
+	if target != "" {
+		r.devices[target] = true
+	}
+	elems := make([]*gnmi.PathElem, 0, len(prefix.GetElem())+len(path.GetElem()))
+	elems = append(elems, prefix.GetElem()...)
+	elems = append(elems, path.GetElem()...)
+	if len(elems) == 0 {
+		r.paths["/"] = true
+	} else {
+		r.paths[utils.StrPathElem(elems)] = true
+	}
+}
+
+func (r *resourceBuilder) build() *Resource {
+	r.resource.Devices = sortedKeys(r.devices)
+	r.resource.Paths = sortedKeys(r.paths)
+	return &r.resource
+}
+
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
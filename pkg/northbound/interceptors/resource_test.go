@@ -0,0 +1,199 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interceptors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onosproject/onos-api/go/onos/config/admin"
+	"github.com/onosproject/onos-api/go/onos/config/diags"
+	adminv2 "github.com/onosproject/onos-config/api/admin/v2"
+	"github.com/onosproject/onos-config/api/adminext"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func elems(names ...string) []*gnmi.PathElem {
+	result := make([]*gnmi.PathElem, 0, len(names))
+	for _, name := range names {
+		result = append(result, &gnmi.PathElem{Name: name})
+	}
+	return result
+}
+
+func testSetRequest() *gnmi.SetRequest {
+	return &gnmi.SetRequest{
+		Prefix: &gnmi.Path{Target: "device-1", Elem: elems("system")},
+		Update: []*gnmi.Update{
+			{Path: &gnmi.Path{Elem: elems("config", "hostname")}},
+			{Path: &gnmi.Path{Target: "device-2", Elem: elems("config", "hostname")}},
+		},
+		Delete: []*gnmi.Path{{Elem: elems("config", "motd-banner")}},
+	}
+}
+
+func Test_ResourceOfSet(t *testing.T) {
+	resource := ResourceOf("/gnmi.gNMI/Set", testSetRequest())
+	assert.Equal(t, OperationSet, resource.Operation)
+	assert.Equal(t, []string{"device-1", "device-2"}, resource.Devices)
+	assert.Equal(t, []string{"/system/config/hostname", "/system/config/motd-banner"}, resource.Paths)
+	assert.Equal(t, 2, resource.Updates)
+	assert.Equal(t, 0, resource.Replaces)
+	assert.Equal(t, 1, resource.Deletes)
+}
+
+func Test_ResourceOfOther(t *testing.T) {
+	resource := ResourceOf("/gnmi.gNMI/Get", &gnmi.GetRequest{Path: []*gnmi.Path{{Target: "*"}}})
+	assert.Equal(t, OperationGet, resource.Operation)
+	assert.Equal(t, []string{"*"}, resource.Devices)
+	assert.Equal(t, []string{"/"}, resource.Paths)
+
+	resource = ResourceOf("/onos.config.admin.ConfigAdminService/ListRegisteredModels", &admin.ListModelsRequest{})
+	assert.Equal(t, "listregisteredmodels", resource.Operation)
+	assert.Nil(t, resource.Devices)
+}
+
+func Test_ResourceOfAdmin(t *testing.T) {
+	resource := ResourceOf("/onos.config.admin.ConfigAdminService/ListSnapshots",
+		&admin.ListSnapshotsRequest{ID: "network-1:device-1:1.0.0"})
+	assert.Equal(t, "listsnapshots", resource.Operation)
+	assert.Equal(t, []string{"device-1"}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.admin.ConfigAdminService/ListSnapshots",
+		&admin.ListSnapshotsRequest{ID: "*:dev*:1.0.0"})
+	assert.Equal(t, []string{AllDevices}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.admin.ConfigAdminService/ListSnapshots", &admin.ListSnapshotsRequest{})
+	assert.Equal(t, []string{AllDevices}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.admin.ConfigAdminService/CompactChanges", &admin.CompactChangesRequest{})
+	assert.Equal(t, []string{AllDevices}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.admin.ConfigAdminService/RollbackNetworkChange",
+		&admin.RollbackRequest{Name: "change-1"})
+	assert.Equal(t, []string{AllDevices}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.admin.v2.ConfigAdminService/RollbackNetworkChange",
+		&adminv2.RollbackNetworkChangeRequest{Id: "change-1"})
+	assert.Equal(t, []string{AllDevices}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.admin.v2.ConfigAdminService/ListDeviceChanges",
+		&adminv2.ListDeviceChangesRequest{DeviceId: "device-1"})
+	assert.Equal(t, "listdevicechanges", resource.Operation)
+	assert.Equal(t, []string{"device-1"}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.adminext.ConfigAdminExtService/Rollback",
+		&adminext.RollbackRequest{Name: "change-1"})
+	assert.Equal(t, []string{AllDevices}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.adminext.ConfigAdminExtService/CompactChanges",
+		&adminext.CompactChangesRequest{Partition: "site-a"})
+	assert.Equal(t, []string{AllDevices}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.adminext.ConfigAdminExtService/AdoptConfig",
+		&adminext.AdoptConfigRequest{DeviceId: "device-1"})
+	assert.Equal(t, []string{"device-1"}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.adminext.ConfigAdminExtService/DeleteSubtree",
+		&adminext.DeleteSubtreeRequest{Partition: "type=Devicesim", Prefix: "/system"})
+	assert.Equal(t, []string{AllDevices}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.adminext.ConfigAdminExtService/GetDeviceGroupStatus",
+		&adminext.GetDeviceGroupStatusRequest{Group: "site-a"})
+	assert.Equal(t, []string{AllDevices}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.adminext.ConfigAdminExtService/MoveListEntry",
+		&adminext.MoveListEntryRequest{DeviceId: "device-1"})
+	assert.Equal(t, []string{"device-1"}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.adminext.ConfigAdminExtService/CountListEntries",
+		&adminext.CountListEntriesRequest{DeviceId: "device-1", Path: "/interfaces/interface"})
+	assert.Equal(t, []string{"device-1"}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.adminext.ConfigAdminExtService/ReplaceDeviceConfig",
+		&adminext.ReplaceDeviceConfigRequest{DeviceId: "device-2", Config: []byte("{}")})
+	assert.Equal(t, []string{"device-2"}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.adminext.ConfigAdminExtService/SimulateChange",
+		&adminext.SimulateChangeRequest{DeviceId: "device-2"})
+	assert.Equal(t, []string{"device-2"}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.adminext.ConfigAdminExtService/TestConnection",
+		&adminext.TestConnectionRequest{DeviceId: "device-3"})
+	assert.Equal(t, []string{"device-3"}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.adminext.ConfigAdminExtService/ListSnapshotDevices",
+		&adminext.ListSnapshotDevicesRequest{SnapshotId: "snapshot-1"})
+	assert.Equal(t, []string{AllDevices}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.adminext.ConfigAdminExtService/GetSnapshotValues",
+		&adminext.GetSnapshotValuesRequest{SnapshotId: "snapshot-1", DeviceId: "device-4"})
+	assert.Equal(t, []string{"device-4"}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.adminext.ConfigAdminExtService/ListQuarantinedDevices",
+		&adminext.ListQuarantinedDevicesRequest{})
+	assert.Equal(t, []string{AllDevices}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.adminext.ConfigAdminExtService/RebindDevice",
+		&adminext.RebindDeviceRequest{DeviceId: "device-5", DeviceVersion: "2.0.0"})
+	assert.Equal(t, []string{"device-5"}, resource.Devices)
+}
+
+func Test_ResourceOfDiags(t *testing.T) {
+	resource := ResourceOf("/onos.config.diags.OpStateDiags/GetOpState", &diags.OpStateRequest{DeviceId: "device-1"})
+	assert.Equal(t, "getopstate", resource.Operation)
+	assert.Equal(t, []string{"device-1"}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.diags.ChangeService/ListDeviceChanges",
+		&diags.ListDeviceChangeRequest{DeviceID: "device-2"})
+	assert.Equal(t, []string{"device-2"}, resource.Devices)
+
+	resource = ResourceOf("/onos.config.diags.ChangeService/ListNetworkChanges", &diags.ListNetworkChangeRequest{})
+	assert.Equal(t, []string{AllDevices}, resource.Devices)
+}
+
+func Test_AuthzResourceContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &AuthzRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(request))
+		// Only device-1 may be configured
+		if request.Resource != nil && len(request.Resource.Devices) == 1 && request.Resource.Devices[0] == "device-1" {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+	chain := NewChain(false, NewAuthzInterceptor(server.URL))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: setMethod}
+
+	_, err := chain.UnaryServerInterceptor()(context.Background(), testSetRequest(), info, handler)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	request := testSetRequest()
+	request.Update = request.Update[:1]
+	_, err = chain.UnaryServerInterceptor()(context.Background(), request, info, handler)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,221 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package readonly rejects the mutating northbound calls while onos-config is in read-only
+// maintenance mode, e.g. during a store migration or an upgrade. Only a gNMI Set that breaks
+// the glass is let through, for the Set to authorize it as usual, and a dry run of a Set, which
+// changes nothing.
+package readonly
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	nbgnmi "github.com/onosproject/onos-config/pkg/northbound/gnmi"
+	"github.com/onosproject/onos-config/pkg/store/maintenance"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var log = logging.GetLogger("northbound", "readonly")
+
+const (
+	gnmiService     = "/gnmi.gNMI/"
+	gnmiSet         = gnmiService + "Set"
+	adminService    = "/onos.config.admin.ConfigAdminService/"
+	adminV2Service  = "/onos.config.admin.v2.ConfigAdminService/"
+	adminExtService = "/onos.config.adminext.ConfigAdminExtService/"
+)
+
+// guardedServices are the northbound services whose methods are rejected in maintenance mode,
+// unless they are read-only
+var guardedServices = []string{
+	gnmiService,
+	adminService,
+	adminV2Service,
+	adminExtService,
+}
+
+// readOnlyMethods are the methods of the guarded services that change neither the configuration
+// nor the stores. Every other method of the services is rejected, so that a method added to one of
+// them is rejected until it is listed here. Entering and exiting the maintenance mode are let
+// through, and so is MigrateStores: it rewrites records without changing their content, and is
+// best run in maintenance mode, while nothing else writes to the stores.
+var readOnlyMethods = map[string]bool{
+	gnmiService + "Capabilities":                true,
+	gnmiService + "Get":                         true,
+	gnmiService + "Subscribe":                   true,
+	adminService + "ListRegisteredModels":       true,
+	adminService + "ListSnapshots":              true,
+	adminV2Service + "GetModel":                 true,
+	adminV2Service + "GetNetworkChange":         true,
+	adminV2Service + "GetSnapshot":              true,
+	adminV2Service + "ListDeviceChanges":        true,
+	adminV2Service + "ListModels":               true,
+	adminV2Service + "ListNetworkChanges":       true,
+	adminV2Service + "ListSnapshots":            true,
+	adminV2Service + "WatchDeviceChanges":       true,
+	adminV2Service + "WatchNetworkChanges":      true,
+	adminV2Service + "WatchSnapshots":           true,
+	adminExtService + "BlameConfig":             true,
+	adminExtService + "CompletePath":            true,
+	adminExtService + "CountListEntries":        true,
+	adminExtService + "EnterMaintenanceMode":    true,
+	adminExtService + "ExitMaintenanceMode":     true,
+	adminExtService + "ExportDeviceChanges":     true,
+	adminExtService + "GetCapacity":             true,
+	adminExtService + "GetChangeEnvironment":    true,
+	adminExtService + "GetChangeWatchdog":       true,
+	adminExtService + "GetDeviceGroupStatus":    true,
+	adminExtService + "GetDeviceTwin":           true,
+	adminExtService + "GetElections":            true,
+	adminExtService + "GetLatencyReport":        true,
+	adminExtService + "GetMaintenanceMode":      true,
+	adminExtService + "GetSnapshotValues":       true,
+	adminExtService + "GetStoreHealth":          true,
+	adminExtService + "GetTrustBundle":          true,
+	adminExtService + "ListAnnotations":         true,
+	adminExtService + "ListAppliedIndexes":      true,
+	adminExtService + "ListChangeAnomalies":     true,
+	adminExtService + "ListChangeRejections":    true,
+	adminExtService + "ListClientSubscriptions": true,
+	adminExtService + "ListClosedLoopTriggers":  true,
+	adminExtService + "ListControllerQueues":    true,
+	adminExtService + "ListControllerTuning":    true,
+	adminExtService + "ListDeviceGroups":        true,
+	adminExtService + "ListDeviceLocations":     true,
+	adminExtService + "ListDeviceOperations":    true,
+	adminExtService + "ListMergeRules":          true,
+	adminExtService + "ListPathClaims":          true,
+	adminExtService + "ListPausedChanges":       true,
+	adminExtService + "ListQuarantinedDevices":  true,
+	adminExtService + "ListRecordedRequests":    true,
+	adminExtService + "ListSampleIntervals":     true,
+	adminExtService + "ListSnapshotDevices":     true,
+	adminExtService + "ListStateShards":         true,
+	adminExtService + "ListStateSubscriptions":  true,
+	adminExtService + "ListTargets":             true,
+	adminExtService + "ListTransformRules":      true,
+	adminExtService + "ListTrustBundles":        true,
+	adminExtService + "ListUnconfirmedChanges":  true,
+	adminExtService + "MigrateStores":           true,
+	adminExtService + "SearchValues":            true,
+	adminExtService + "SimulateChange":          true,
+	adminExtService + "TestConnection":          true,
+	adminExtService + "ValidatePath":            true,
+	adminExtService + "WatchChangeAnomalies":    true,
+	adminExtService + "WatchDeviceChanges":      true,
+	adminExtService + "WatchElections":          true,
+	adminExtService + "WatchNetworkChanges":     true,
+}
+
+// IsMutating returns whether a northbound method is rejected in maintenance mode
+func IsMutating(method string) bool {
+	if readOnlyMethods[method] {
+		return false
+	}
+	for _, service := range guardedServices {
+		if strings.HasPrefix(method, service) {
+			return true
+		}
+	}
+	return false
+}
+
+// Guard rejects the mutating calls while onos-config is in maintenance mode
+type Guard struct {
+	store maintenance.Store
+}
+
+// NewGuard creates a guard of the maintenance mode of the given store
+func NewGuard(store maintenance.Store) *Guard {
+	return &Guard{
+		store: store,
+	}
+}
+
+// ServerOptions returns the options that install the guard on a gRPC server. They must follow
+// the interceptor chain, so that the callers are authenticated when the guard logs them.
+func (g *Guard) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(g.UnaryServerInterceptor()),
+	}
+}
+
+// UnaryServerInterceptor returns the guard as a unary interceptor. No streaming method mutates
+// but UploadRegisterModel, which is not implemented.
+func (g *Guard) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := g.Check(info.FullMethod, req); err != nil {
+			log.Infof("%s rejected: %v", info.FullMethod, err)
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Check returns an Unavailable error if the given request must be rejected in maintenance mode.
+// The mutating calls are rejected too if the mode cannot be read.
+func (g *Guard) Check(method string, req interface{}) error {
+	if !IsMutating(method) {
+		return nil
+	}
+	if method == gnmiSet && (breaksGlass(req) || isDryRun(req)) {
+		return nil
+	}
+	mode, err := g.store.Get()
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return status.Errorf(codes.Unavailable, "cannot tell whether onos-config is in maintenance mode: %v", err)
+	}
+	return status.Error(codes.Unavailable, Describe(mode))
+}
+
+// Describe returns the message calls are rejected with in the given maintenance mode
+func Describe(mode *maintenance.Mode) string {
+	message := fmt.Sprintf("onos-config is read-only: placed in maintenance mode by '%s' at %s",
+		mode.User, mode.Started.UTC().Format("2006-01-02T15:04:05Z"))
+	if mode.Reason != "" {
+		message += ": " + mode.Reason
+	}
+	return message
+}
+
+func breaksGlass(req interface{}) bool {
+	return hasExtension(req, nbgnmi.GnmiExtensionBreakGlass)
+}
+
+func isDryRun(req interface{}) bool {
+	return hasExtension(req, nbgnmi.GnmiExtensionDryRun)
+}
+
+// hasExtension returns whether a gNMI Set request has an extension
+func hasExtension(req interface{}, id int) bool {
+	setRequest, ok := req.(*gnmi.SetRequest)
+	if !ok {
+		return false
+	}
+	for _, ext := range setRequest.GetExtension() {
+		if int(ext.GetRegisteredExt().GetId()) == id {
+			return true
+		}
+	}
+	return false
+}
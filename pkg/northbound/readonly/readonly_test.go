@@ -0,0 +1,168 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package readonly
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-api/go/onos/config/admin"
+	adminv2 "github.com/onosproject/onos-config/api/admin/v2"
+	"github.com/onosproject/onos-config/api/adminext"
+	nbgnmi "github.com/onosproject/onos-config/pkg/northbound/gnmi"
+	"github.com/onosproject/onos-config/pkg/store/maintenance"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mutatingMethods are the methods of the guarded services rejected in maintenance mode
+var mutatingMethods = []string{
+	gnmiSet,
+	adminService + "CompactChanges",
+	adminService + "RollbackNetworkChange",
+	adminService + "UploadRegisterModel",
+	adminV2Service + "CompactChanges",
+	adminV2Service + "RollbackNetworkChange",
+	adminExtService + "AddAnnotation",
+	adminExtService + "AddStateSubtree",
+	adminExtService + "AdoptConfig",
+	adminExtService + "ApproveClosedLoopAction",
+	adminExtService + "CancelChange",
+	adminExtService + "ClaimPaths",
+	adminExtService + "CompactChanges",
+	adminExtService + "ConfirmChange",
+	adminExtService + "DeleteAnnotation",
+	adminExtService + "DeleteDeviceLocation",
+	adminExtService + "DeleteMergeRule",
+	adminExtService + "DeleteSubtree",
+	adminExtService + "DeleteTransformRule",
+	adminExtService + "DeleteTrustBundle",
+	adminExtService + "DisconnectTarget",
+	adminExtService + "MoveListEntry",
+	adminExtService + "PauseChange",
+	adminExtService + "PruneStaleTargets",
+	adminExtService + "PutMergeRule",
+	adminExtService + "PutTransformRule",
+	adminExtService + "PutTrustBundle",
+	adminExtService + "RebindDevice",
+	adminExtService + "RebuildDeviceCache",
+	adminExtService + "RejectClosedLoopAction",
+	adminExtService + "ReleasePaths",
+	adminExtService + "RemoveStateSubtree",
+	adminExtService + "ReplaceDeviceConfig",
+	adminExtService + "ResetControllerTuning",
+	adminExtService + "ResetSampleInterval",
+	adminExtService + "ResetStateSubtrees",
+	adminExtService + "ResumeChange",
+	adminExtService + "RetryChange",
+	adminExtService + "Rollback",
+	adminExtService + "SetControllerTuning",
+	adminExtService + "SetDeviceLocation",
+	adminExtService + "SetSampleInterval",
+	adminExtService + "TerminateClientSubscription",
+}
+
+// TestMethodsClassified checks that every method of the guarded services is either read-only or
+// one of the mutating methods, so that a method added to a service must be classified
+func TestMethodsClassified(t *testing.T) {
+	server := grpc.NewServer()
+	admin.RegisterConfigAdminServiceServer(server, &admin.UnimplementedConfigAdminServiceServer{})
+	adminv2.RegisterConfigAdminServiceServer(server, &adminv2.UnimplementedConfigAdminServiceServer{})
+	adminext.RegisterConfigAdminExtServiceServer(server, &adminext.UnimplementedConfigAdminExtServiceServer{})
+	gnmi.RegisterGNMIServer(server, &gnmi.UnimplementedGNMIServer{})
+	methods := make(map[string]bool)
+	for service, info := range server.GetServiceInfo() {
+		for _, method := range info.Methods {
+			methods["/"+service+"/"+method.Name] = true
+		}
+	}
+	mutating := make(map[string]bool)
+	for _, method := range mutatingMethods {
+		assert.True(t, methods[method], method)
+		mutating[method] = true
+	}
+	for method := range readOnlyMethods {
+		assert.True(t, methods[method], method)
+		assert.False(t, mutating[method], method)
+	}
+	for method := range methods {
+		assert.True(t, readOnlyMethods[method] || mutating[method], "%s is not classified", method)
+		assert.Equal(t, mutating[method], IsMutating(method), method)
+	}
+
+	// A method the guarded services do not know of is rejected, and other services are not guarded
+	assert.True(t, IsMutating(adminExtService+"DeleteEverything"))
+	assert.False(t, IsMutating("/grpc.health.v1.Health/Check"))
+}
+
+func TestGuard(t *testing.T) {
+	store := maintenance.NewLocalStore()
+	guard := NewGuard(store)
+	set := &gnmi.SetRequest{}
+	breakGlass := &gnmi.SetRequest{
+		Extension: []*gnmi_ext.Extension{{
+			Ext: &gnmi_ext.Extension_RegisteredExt{
+				RegisteredExt: &gnmi_ext.RegisteredExtension{
+					Id:  nbgnmi.GnmiExtensionBreakGlass,
+					Msg: []byte("INC-42 core uplink down"),
+				},
+			},
+		}},
+	}
+	dryRun := &gnmi.SetRequest{
+		Extension: []*gnmi_ext.Extension{{
+			Ext: &gnmi_ext.Extension_RegisteredExt{
+				RegisteredExt: &gnmi_ext.RegisteredExtension{
+					Id: nbgnmi.GnmiExtensionDryRun,
+				},
+			},
+		}},
+	}
+
+	assert.NoError(t, guard.Check("/gnmi.gNMI/Set", set))
+	assert.NoError(t, guard.Check("/onos.config.adminext.ConfigAdminExtService/CancelChange", nil))
+
+	started := time.Date(2021, 6, 2, 9, 0, 0, 0, time.UTC)
+	assert.NoError(t, store.Put(&maintenance.Mode{User: "alice", Reason: "store migration", Started: started}))
+	err := guard.Check("/gnmi.gNMI/Set", set)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, "onos-config is read-only: placed in maintenance mode by 'alice' at 2021-06-02T09:00:00Z: store migration",
+		status.Convert(err).Message())
+	err = guard.Check("/onos.config.adminext.ConfigAdminExtService/CancelChange", nil)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	// Reads, the maintenance mode itself, break-glass Sets and dry runs are let through
+	assert.NoError(t, guard.Check("/gnmi.gNMI/Get", &gnmi.GetRequest{}))
+	assert.NoError(t, guard.Check("/onos.config.adminext.ConfigAdminExtService/ListPausedChanges", nil))
+	assert.NoError(t, guard.Check("/onos.config.adminext.ConfigAdminExtService/ExitMaintenanceMode", nil))
+	assert.NoError(t, guard.Check("/gnmi.gNMI/Set", breakGlass))
+	assert.NoError(t, guard.Check("/gnmi.gNMI/Set", dryRun))
+
+	interceptor := guard.UnaryServerInterceptor()
+	_, err = interceptor(context.Background(), set, &grpc.UnaryServerInfo{FullMethod: "/gnmi.gNMI/Set"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			t.Fatal("rejected call handled")
+			return nil, nil
+		})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	assert.NoError(t, store.Delete())
+	assert.NoError(t, guard.Check("/gnmi.gNMI/Set", set))
+}
@@ -0,0 +1,224 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package recorder keeps the last northbound gNMI Set and Get requests and their responses, so
+// that the issues of a client can be reproduced by replaying its requests. The values of the
+// sensitive paths are redacted before they are recorded, since the records may be read by callers
+// who may not reveal them.
+package recorder
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-config/pkg/northbound/grpcerrors"
+	"github.com/onosproject/onos-config/pkg/secrets"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-config/pkg/utils/values"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+var log = logging.GetLogger("northbound", "recorder")
+
+// Recorded methods
+const (
+	MethodSet = "/gnmi.gNMI/Set"
+	MethodGet = "/gnmi.gNMI/Get"
+)
+
+// Record is a recorded call
+type Record struct {
+	// Sequence numbers the records from 1, in the order the calls completed
+	Sequence uint64
+	Time     time.Time
+	Method   string
+	User     string
+	// Request and Response are the messages of the call in the protobuf text format, redacted;
+	// Response is empty if the call failed
+	Request  string
+	Response string
+	Code     codes.Code
+	Error    string
+}
+
+// Recorder keeps the last calls in a ring buffer
+type Recorder struct {
+	mu       sync.RWMutex
+	records  []*Record
+	next     int
+	sequence uint64
+	registry *secrets.Registry
+}
+
+var recorder = NewRecorder(0, secrets.GetRegistry())
+
+// GetRecorder returns the recorder of the northbound. It records nothing until its capacity is set.
+func GetRecorder() *Recorder {
+	return recorder
+}
+
+// NewRecorder creates a recorder keeping the given number of calls, redacting the sensitive paths
+// of the given registry
+func NewRecorder(capacity int, registry *secrets.Registry) *Recorder {
+	r := &Recorder{registry: registry}
+	r.SetCapacity(capacity)
+	return r
+}
+
+// SetCapacity sets the number of calls kept, dropping the calls recorded so far; 0 disables the
+// recording
+func (r *Recorder) SetCapacity(capacity int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if capacity < 0 {
+		capacity = 0
+	}
+	r.records = make([]*Record, 0, capacity)
+	r.next = 0
+}
+
+// Capacity returns the number of calls kept
+func (r *Recorder) Capacity() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return cap(r.records)
+}
+
+// Records returns the records kept, from the oldest
+func (r *Recorder) Records() []*Record {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	records := make([]*Record, 0, len(r.records))
+	records = append(records, r.records[r.next:]...)
+	return append(records, r.records[:r.next]...)
+}
+
+// ServerOptions returns the options that install the recorder on a gRPC server. They must follow
+// the interceptor chain, so that the callers are authenticated when their calls are recorded.
+func (r *Recorder) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(r.UnaryServerInterceptor()),
+	}
+}
+
+// UnaryServerInterceptor returns the recorder as a unary interceptor
+func (r *Recorder) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if info.FullMethod == MethodSet || info.FullMethod == MethodGet {
+			r.record(ctx, info.FullMethod, req, resp, err)
+		}
+		return resp, err
+	}
+}
+
+func (r *Recorder) record(ctx context.Context, method string, req interface{}, resp interface{}, err error) {
+	if r.Capacity() == 0 {
+		return
+	}
+	user, _ := secrets.Caller(ctx)
+	record := &Record{
+		Time:    time.Now(),
+		Method:  method,
+		User:    user,
+		Request: r.format(req),
+	}
+	if err != nil {
+		st := grpcerrors.Status(err)
+		record.Code = st.Code()
+		record.Error = st.Message()
+	} else {
+		record.Response = r.format(resp)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cap(r.records) == 0 {
+		return
+	}
+	r.sequence++
+	record.Sequence = r.sequence
+	if len(r.records) < cap(r.records) {
+		r.records = append(r.records, record)
+	} else {
+		r.records[r.next] = record
+		r.next = (r.next + 1) % cap(r.records)
+	}
+}
+
+// format renders a redacted copy of a message in the protobuf text format
+func (r *Recorder) format(msg interface{}) string {
+	switch m := msg.(type) {
+	case *gnmi.SetRequest:
+		redacted := proto.Clone(m).(*gnmi.SetRequest)
+		for _, u := range append(redacted.Update, redacted.Replace...) {
+			u.Val = r.redact(redacted.Prefix, u.Path, u.Val)
+		}
+		return prototext.Format(redacted)
+	case *gnmi.GetResponse:
+		redacted := proto.Clone(m).(*gnmi.GetResponse)
+		for _, notification := range redacted.Notification {
+			for _, u := range notification.Update {
+				u.Val = r.redact(notification.Prefix, u.Path, u.Val)
+			}
+		}
+		return prototext.Format(redacted)
+	case proto.Message:
+		return prototext.Format(m)
+	default:
+		return ""
+	}
+}
+
+// redact returns the value to record for the path under the prefix: a JSON value is masked as a
+// whole if it may hold a sensitive path, any other value if its path is sensitive
+func (r *Recorder) redact(prefix *gnmi.Path, path *gnmi.Path, value *gnmi.TypedValue) *gnmi.TypedValue {
+	elems := append(append([]*gnmi.PathElem{}, prefix.GetElem()...), path.GetElem()...)
+	fullPath := utils.StrPath(&gnmi.Path{Elem: elems})
+	switch value.GetValue().(type) {
+	case *gnmi.TypedValue_JsonVal:
+		if r.registry.MayHoldSensitive(fullPath) {
+			return &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: []byte(`"` + secrets.RedactedValue + `"`)}}
+		}
+	case *gnmi.TypedValue_JsonIetfVal:
+		if r.registry.MayHoldSensitive(fullPath) {
+			return &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"` + secrets.RedactedValue + `"`)}}
+		}
+	default:
+		if r.registry.IsSensitive(fullPath) {
+			return maskGnmi(value)
+		}
+	}
+	return value
+}
+
+// maskGnmi masks a gNMI value keeping its type, or as a string if it has no native type
+func maskGnmi(value *gnmi.TypedValue) *gnmi.TypedValue {
+	native, err := values.GnmiTypedValueToNativeType(value, nil)
+	if err == nil {
+		var masked *gnmi.TypedValue
+		masked, err = values.NativeTypeToGnmiTypedValue(secrets.Mask(native))
+		if err == nil {
+			return masked
+		}
+	}
+	log.Debugf("Masking a value of unknown type as a string: %v", err)
+	return &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: secrets.RedactedValue}}
+}
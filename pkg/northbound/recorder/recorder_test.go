@@ -0,0 +1,93 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package recorder
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/onosproject/onos-config/pkg/secrets"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func call(t *testing.T, r *Recorder, method string, req interface{}, resp interface{}, err error) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return resp, err
+	}
+	_, _ = r.UnaryServerInterceptor()(context.Background(), req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+}
+
+func setRequest(t *testing.T, path string, value *gnmi.TypedValue) *gnmi.SetRequest {
+	p, err := utils.ParseGNMIElements(utils.SplitPath(path))
+	assert.NilError(t, err)
+	p.Target = "device-1"
+	return &gnmi.SetRequest{Update: []*gnmi.Update{{Path: p, Val: value}}}
+}
+
+func Test_RecordRing(t *testing.T) {
+	r := NewRecorder(2, secrets.NewRegistry())
+	for _, leaf := range []string{"/a/b", "/a/c", "/a/d"} {
+		call(t, r, MethodSet, setRequest(t, leaf, &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 1}}), &gnmi.SetResponse{}, nil)
+	}
+	call(t, r, "/gnmi.gNMI/Capabilities", &gnmi.CapabilityRequest{}, &gnmi.CapabilityResponse{}, nil)
+
+	records := r.Records()
+	assert.Equal(t, len(records), 2)
+	assert.Equal(t, records[0].Sequence, uint64(2))
+	assert.Equal(t, records[1].Sequence, uint64(3))
+	assert.Assert(t, strings.Contains(records[1].Request, `"d"`), records[1].Request)
+	assert.Equal(t, records[1].Method, MethodSet)
+	assert.Equal(t, records[1].Code, codes.OK)
+
+	r.SetCapacity(0)
+	call(t, r, MethodSet, setRequest(t, "/a/e", nil), &gnmi.SetResponse{}, nil)
+	assert.Equal(t, len(r.Records()), 0)
+}
+
+func Test_RecordRedacts(t *testing.T) {
+	r := NewRecorder(10, secrets.NewRegistry("/system/aaa/server[name=*]/secret-key"))
+	call(t, r, MethodSet, setRequest(t, "/system/aaa/server[name=radius]/secret-key",
+		&gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "s3cr3t"}}), &gnmi.SetResponse{}, nil)
+	call(t, r, MethodSet, setRequest(t, "/system/aaa",
+		&gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: []byte(`{"server":{"secret-key":"s3cr3t"}}`)}}), &gnmi.SetResponse{}, nil)
+	call(t, r, MethodSet, setRequest(t, "/system/hostname",
+		&gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: []byte(`"router-1"`)}}), &gnmi.SetResponse{}, nil)
+
+	records := r.Records()
+	assert.Equal(t, len(records), 3)
+	for _, record := range records[:2] {
+		assert.Assert(t, !strings.Contains(record.Request, "s3cr3t"), record.Request)
+		assert.Assert(t, strings.Contains(record.Request, secrets.RedactedValue), record.Request)
+	}
+	assert.Assert(t, strings.Contains(records[2].Request, "router-1"), records[2].Request)
+}
+
+func Test_RecordError(t *testing.T) {
+	r := NewRecorder(10, secrets.NewRegistry())
+	call(t, r, MethodGet, &gnmi.GetRequest{}, nil, status.Error(codes.NotFound, "device-1 not found"))
+
+	records := r.Records()
+	assert.Equal(t, len(records), 1)
+	assert.Equal(t, records[0].Method, MethodGet)
+	assert.Equal(t, records[0].Code, codes.NotFound)
+	assert.Equal(t, records[0].Error, "device-1 not found")
+	assert.Equal(t, records[0].Response, "")
+}
@@ -0,0 +1,122 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package northbound serves the northbound gRPC services of onos-config.
+//
+// It is the onos-lib-go northbound server, with the server options given by the caller, e.g.
+// the interceptor chain, and with client certificates verified whenever they are presented so
+// that callers can be authenticated by their certificate.
+package northbound
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"github.com/onosproject/onos-lib-go/pkg/certs"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/onosproject/onos-lib-go/pkg/northbound"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+var log = logging.GetLogger("northbound")
+
+// Server serves the northbound gRPC services
+type Server struct {
+	port     int
+	caPath   string
+	keyPath  string
+	certPath string
+	opts     []grpc.ServerOption
+	services []northbound.Service
+	server   *grpc.Server
+}
+
+// NewServer creates a server listening on the given port, with the given gRPC server options
+func NewServer(caPath string, keyPath string, certPath string, port int, opts ...grpc.ServerOption) *Server {
+	return &Server{
+		port:     port,
+		caPath:   caPath,
+		keyPath:  keyPath,
+		certPath: certPath,
+		opts:     opts,
+		services: []northbound.Service{},
+	}
+}
+
+// AddService adds a Service to the server to be registered on Serve
+func (s *Server) AddService(r northbound.Service) {
+	s.services = append(s.services, r)
+}
+
+// Serve starts the server; it blocks until the server fails
+func (s *Server) Serve(started func(string)) error {
+	tlsCfg, err := TLSConfig(s.caPath, s.keyPath, s.certPath)
+	if err != nil {
+		return err
+	}
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.port))
+	if err != nil {
+		return err
+	}
+	opts := append([]grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsCfg))}, s.opts...)
+	s.server = grpc.NewServer(opts...)
+	for i := range s.services {
+		s.services[i].Register(s.server)
+	}
+	started(lis.Addr().String())
+
+	log.Infof("Starting RPC server on address: %s", lis.Addr().String())
+	return s.server.Serve(lis)
+}
+
+// Stop stops the server
+func (s *Server) Stop() {
+	s.server.Stop()
+}
+
+// TLSConfig returns the server TLS configuration of the northbound endpoints. The default
+// localhost certificate and CA are used unless paths are given. Client certificates are not
+// required, but they are verified against the CA when presented.
+func TLSConfig(caPath string, keyPath string, certPath string) (*tls.Config, error) {
+	tlsCfg := &tls.Config{
+		ClientAuth: tls.VerifyClientCertIfGiven,
+	}
+	if certPath == "" && keyPath == "" {
+		serverCerts, err := tls.X509KeyPair([]byte(certs.DefaultLocalhostCrt), []byte(certs.DefaultLocalhostKey))
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.Certificates = []tls.Certificate{serverCerts}
+	} else {
+		log.Infof("Loading certs: %s %s", certPath, keyPath)
+		serverCerts, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg.Certificates = []tls.Certificate{serverCerts}
+	}
+
+	var err error
+	if caPath == "" {
+		tlsCfg.ClientCAs, err = certs.GetCertPoolDefault()
+	} else {
+		tlsCfg.ClientCAs, err = certs.GetCertPool(caPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tlsCfg, nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package subscriptions keeps the active northbound gNMI subscriptions of this node, so that the
+// clients overloading the telemetry can be found, and their subscriptions terminated.
+package subscriptions
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Subscription is an active northbound gNMI subscription
+type Subscription struct {
+	// ID identifies the subscription, as the listener of the operational state events it is
+	// registered as with the dispatcher
+	ID string
+	// User is the user who subscribed, empty if the caller is not authenticated
+	User string
+	// Mode is the mode of the subscription list, e.g. STREAM; empty until the client sends it
+	Mode string
+	// Paths are the paths subscribed to, and Devices their targets, sorted
+	Paths   []string
+	Devices []string
+	Started time.Time
+}
+
+// Active is the handle of an active subscription
+type Active struct {
+	registry     *Registry
+	subscription Subscription
+	terminated   chan struct{}
+	once         sync.Once
+	message      string
+}
+
+// Registry keeps the active subscriptions
+type Registry struct {
+	mu            sync.RWMutex
+	subscriptions map[string]*Active
+}
+
+var registry = NewRegistry()
+
+// GetRegistry returns the registry of the subscriptions of the northbound
+func GetRegistry() *Registry {
+	return registry
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		subscriptions: make(map[string]*Active),
+	}
+}
+
+// Add adds the subscription of a user, which is active until it is removed
+func (r *Registry) Add(id string, user string) (*Active, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.subscriptions[id]; ok {
+		return nil, errors.NewAlreadyExists("subscription %s is already active", id)
+	}
+	active := &Active{
+		registry: r,
+		subscription: Subscription{
+			ID:      id,
+			User:    user,
+			Started: time.Now(),
+		},
+		terminated: make(chan struct{}),
+	}
+	r.subscriptions[id] = active
+	return active, nil
+}
+
+// Remove removes a subscription once it has ended
+func (r *Registry) Remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subscriptions, id)
+}
+
+// Get returns an active subscription
+func (r *Registry) Get(id string) (Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	active, ok := r.subscriptions[id]
+	if !ok {
+		return Subscription{}, errors.NewNotFound("subscription %s is not active", id)
+	}
+	return active.subscription, nil
+}
+
+// List returns the active subscriptions, sorted by ID
+func (r *Registry) List() []Subscription {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	subscriptions := make([]Subscription, 0, len(r.subscriptions))
+	for _, active := range r.subscriptions {
+		subscriptions = append(subscriptions, active.subscription)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].ID < subscriptions[j].ID
+	})
+	return subscriptions
+}
+
+// Terminate asks an active subscription to end, the message telling the client why; it is
+// removed once its stream has ended
+func (r *Registry) Terminate(id string, message string) (Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	active, ok := r.subscriptions[id]
+	if !ok {
+		return Subscription{}, errors.NewNotFound("subscription %s is not active", id)
+	}
+	active.once.Do(func() {
+		active.message = message
+		close(active.terminated)
+	})
+	return active.subscription, nil
+}
+
+// Subscribed records what the client subscribed to
+func (a *Active) Subscribed(mode string, paths []string, devices []string) {
+	paths = append([]string{}, paths...)
+	sort.Strings(paths)
+	devices = append([]string{}, devices...)
+	sort.Strings(devices)
+	a.registry.mu.Lock()
+	defer a.registry.mu.Unlock()
+	a.subscription.Mode = mode
+	a.subscription.Paths = paths
+	a.subscription.Devices = devices
+}
+
+// Terminated is closed when the subscription is terminated
+func (a *Active) Terminated() <-chan struct{} {
+	return a.terminated
+}
+
+// Message returns the message the subscription was terminated with
+func (a *Active) Message() string {
+	<-a.terminated
+	return a.message
+}
@@ -0,0 +1,65 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscriptions
+
+import (
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"gotest.tools/assert"
+)
+
+func Test_Registry(t *testing.T) {
+	r := NewRegistry()
+	active, err := r.Add("sub-2", "alice")
+	assert.NilError(t, err)
+	_, err = r.Add("sub-2", "alice")
+	assert.Assert(t, errors.IsAlreadyExists(err))
+	_, err = r.Add("sub-1", "")
+	assert.NilError(t, err)
+
+	active.Subscribed("STREAM", []string{"/interfaces/interface[name=*]/state/counters", "/system/state"},
+		[]string{"device-2", "device-1"})
+	subscriptions := r.List()
+	assert.Equal(t, len(subscriptions), 2)
+	assert.Equal(t, subscriptions[0].ID, "sub-1")
+	assert.Equal(t, subscriptions[1].User, "alice")
+	assert.Equal(t, subscriptions[1].Mode, "STREAM")
+	assert.DeepEqual(t, subscriptions[1].Devices, []string{"device-1", "device-2"})
+	assert.Equal(t, subscriptions[1].Paths[0], "/interfaces/interface[name=*]/state/counters")
+
+	select {
+	case <-active.Terminated():
+		t.Fatal("subscription terminated")
+	default:
+	}
+	subscription, err := r.Terminate("sub-2", "too many counters")
+	assert.NilError(t, err)
+	assert.Equal(t, subscription.User, "alice")
+	<-active.Terminated()
+	assert.Equal(t, active.Message(), "too many counters")
+
+	// Terminating it again keeps the first message
+	_, err = r.Terminate("sub-2", "again")
+	assert.NilError(t, err)
+	assert.Equal(t, active.Message(), "too many counters")
+
+	r.Remove("sub-2")
+	_, err = r.Get("sub-2")
+	assert.Assert(t, errors.IsNotFound(err))
+	_, err = r.Terminate("sub-2", "gone")
+	assert.Assert(t, errors.IsNotFound(err))
+	assert.Equal(t, len(r.List()), 1)
+}
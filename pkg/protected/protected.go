@@ -0,0 +1,192 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package protected keeps the registry of protected subtrees of the configuration, e.g. /system/aaa
+// or the management ACLs, a mistake in which may lock the operators out of their devices.
+//
+// A gNMI Set that writes or deletes anything in a protected subtree, or deletes an ancestor of one,
+// is refused unless the caller belongs to the ChangeProtectedGroup. Every change of protected paths
+// that is allowed is audited.
+package protected
+
+import (
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// ChangeProtectedGroup is the group a caller must belong to in order to change protected subtrees
+const ChangeProtectedGroup = "change-protected-paths"
+
+// Registry is a set of protected subtrees. Paths may contain the '*' and '...' wildcards, in
+// element names and key values alike.
+type Registry struct {
+	mu       sync.RWMutex
+	paths    []string
+	patterns [][]elemPattern
+}
+
+// elemPattern matches an element of a path
+type elemPattern struct {
+	// rest is set for the '...' wildcard, which matches the rest of the path
+	rest bool
+	name *regexp.Regexp
+	keys map[string]*regexp.Regexp
+}
+
+var registry = NewRegistry()
+
+// GetRegistry returns the protected subtree registry used by the northbound
+func GetRegistry() *Registry {
+	return registry
+}
+
+// NewRegistry creates a new registry containing the given paths
+func NewRegistry(paths ...string) *Registry {
+	r := &Registry{
+		paths:    make([]string, 0),
+		patterns: make([][]elemPattern, 0),
+	}
+	r.Register(paths...)
+	return r
+}
+
+// Register adds protected subtrees to the registry
+func (r *Registry) Register(paths ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		r.paths = append(r.paths, path)
+		r.patterns = append(r.patterns, compile(path))
+	}
+}
+
+func compile(path string) []elemPattern {
+	gnmiPath, err := utils.ParseGNMIElements(utils.SplitPath(path))
+	if err != nil {
+		// Not a valid path; matched as a whole
+		return []elemPattern{{name: utils.MatchWildcardRegexp(path, true)}}
+	}
+	elems := make([]elemPattern, 0, len(gnmiPath.Elem))
+	for _, elem := range gnmiPath.Elem {
+		if elem.Name == "..." {
+			elems = append(elems, elemPattern{rest: true})
+			break
+		}
+		pattern := elemPattern{
+			name: utils.MatchWildcardRegexp(elem.Name, true),
+			keys: make(map[string]*regexp.Regexp),
+		}
+		for key, value := range elem.Key {
+			pattern.keys[key] = utils.MatchWildcardRegexp(value, true)
+		}
+		elems = append(elems, pattern)
+	}
+	return elems
+}
+
+// Paths returns the registered protected subtrees
+func (r *Registry) Paths() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	paths := make([]string, len(r.paths))
+	copy(paths, r.paths)
+	return paths
+}
+
+// IsProtected returns true if the path is in a protected subtree, i.e. writing it changes the subtree
+func (r *Registry) IsProtected(path string) bool {
+	return r.matches(path, false)
+}
+
+// IsDeleteProtected returns true if deleting the path deletes anything in a protected subtree: the
+// path is in a protected subtree, or is the ancestor of one
+func (r *Registry) IsDeleteProtected(path string) bool {
+	return r.matches(path, true)
+}
+
+func (r *Registry) matches(path string, ancestors bool) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.patterns) == 0 {
+		return false
+	}
+	gnmiPath, err := utils.ParseGNMIElements(utils.SplitPath(path))
+	if err != nil {
+		return false
+	}
+	for _, pattern := range r.patterns {
+		if overlaps(gnmiPath.Elem, pattern, ancestors) {
+			return true
+		}
+	}
+	return false
+}
+
+// overlaps compares a path with a protected subtree element by element. An element of the path
+// without keys stands for all the entries of its list. Unless ancestors are wanted, the path must
+// be at least as deep as the subtree.
+func overlaps(elems []*gnmi.PathElem, pattern []elemPattern, ancestors bool) bool {
+	for i, elemPattern := range pattern {
+		if elemPattern.rest {
+			return true
+		}
+		if i == len(elems) {
+			return ancestors
+		}
+		elem := elems[i]
+		if !elemPattern.name.MatchString(elem.Name) {
+			return false
+		}
+		for key, value := range elemPattern.keys {
+			if elemValue, ok := elem.Key[key]; ok && !value.MatchString(elemValue) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CanChange returns true if a caller in the given groups may change protected subtrees
+func CanChange(groups []string) bool {
+	for _, group := range groups {
+		if group == ChangeProtectedGroup {
+			return true
+		}
+	}
+	return false
+}
+
+// ProtectedPaths returns, sorted, the paths written and deleted that change protected subtrees
+func (r *Registry) ProtectedPaths(updates []string, deletes []string) []string {
+	protectedPaths := make([]string, 0)
+	for _, path := range updates {
+		if r.IsProtected(path) {
+			protectedPaths = append(protectedPaths, path)
+		}
+	}
+	for _, path := range deletes {
+		if r.IsDeleteProtected(path) {
+			protectedPaths = append(protectedPaths, path)
+		}
+	}
+	sort.Strings(protectedPaths)
+	return protectedPaths
+}
@@ -0,0 +1,71 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protected
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IsProtected(t *testing.T) {
+	registry := NewRegistry("/system/aaa", "/acl/acl-sets/acl-set[name=mgmt*][type=*]")
+	assert.True(t, registry.IsProtected("/system/aaa"))
+	assert.True(t, registry.IsProtected("/system/aaa/authentication/users/user[username=admin]/config/password"))
+	assert.False(t, registry.IsProtected("/system/aaa-logs/config/level"))
+	assert.False(t, registry.IsProtected("/system/config/hostname"))
+	assert.False(t, registry.IsProtected("/system"))
+
+	assert.True(t, registry.IsProtected("/acl/acl-sets/acl-set[name=mgmt-v4][type=ACL_IPV4]/config/description"))
+	assert.False(t, registry.IsProtected("/acl/acl-sets/acl-set[name=users][type=ACL_IPV4]/config/description"))
+}
+
+func Test_IsDeleteProtected(t *testing.T) {
+	registry := NewRegistry("/system/aaa", "/acl/acl-sets/acl-set[name=mgmt*][type=*]")
+	assert.True(t, registry.IsDeleteProtected("/system/aaa/authentication"))
+	assert.True(t, registry.IsDeleteProtected("/system"))
+	assert.False(t, registry.IsDeleteProtected("/system/config"))
+
+	// Deleting a whole list deletes the protected entries
+	assert.True(t, registry.IsDeleteProtected("/acl/acl-sets/acl-set"))
+	assert.True(t, registry.IsDeleteProtected("/acl/acl-sets/acl-set[name=mgmt-v6]"))
+	assert.False(t, registry.IsDeleteProtected("/acl/acl-sets/acl-set[name=users]"))
+}
+
+func Test_Wildcards(t *testing.T) {
+	registry := NewRegistry("/interfaces/interface[name=mgmt0]/...", "/*/aaa")
+	assert.True(t, registry.IsProtected("/interfaces/interface[name=mgmt0]/config/enabled"))
+	assert.False(t, registry.IsProtected("/interfaces/interface[name=eth1]/config/enabled"))
+	assert.True(t, registry.IsProtected("/system/aaa/server-groups"))
+	assert.True(t, registry.IsDeleteProtected("/interfaces"))
+	assert.False(t, registry.IsDeleteProtected("/interfaces/interface[name=eth1]"))
+	// Any top level container may hold an aaa container
+	assert.True(t, registry.IsDeleteProtected("/routing"))
+}
+
+func Test_ProtectedPaths(t *testing.T) {
+	registry := NewRegistry("/system/aaa")
+	assert.Equal(t, []string{"/system", "/system/aaa/config/enabled"}, registry.ProtectedPaths(
+		[]string{"/system/config/hostname", "/system/aaa/config/enabled"},
+		[]string{"/system/config/motd-banner", "/system"}))
+	assert.Empty(t, NewRegistry().ProtectedPaths([]string{"/system/aaa"}, nil))
+	assert.Equal(t, []string{"/system/aaa"}, registry.Paths())
+}
+
+func Test_CanChange(t *testing.T) {
+	assert.True(t, CanChange([]string{"operators", ChangeProtectedGroup}))
+	assert.False(t, CanChange([]string{"operators"}))
+	assert.False(t, CanChange(nil))
+}
@@ -0,0 +1,309 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secrets keeps the registry of sensitive paths, e.g. passwords and keys, and
+// redacts their values from everything the northbound returns: gNMI Get and Subscribe,
+// the admin and diagnostic streams of changes and snapshots, and the admin extensions.
+//
+// Callers belonging to the RevealSecretsGroup see the real values; every time a secret
+// is revealed to them an audit entry is recorded.
+//
+// A redacted value keeps its type, so that clients decoding values by type are not broken:
+// strings and bytes are replaced by RedactedValue and the other types by their zero value.
+package secrets
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/utils"
+)
+
+// RevealSecretsGroup is the group a caller must belong to in order to see sensitive values
+const RevealSecretsGroup = "reveal-secrets"
+
+// RedactedValue replaces the value of a sensitive string or bytes path
+const RedactedValue = "********"
+
+// Registry is a set of sensitive paths. Paths may contain the '*' and '...' wildcards.
+type Registry struct {
+	mu       sync.RWMutex
+	paths    []string
+	matchers []*regexp.Regexp
+}
+
+var registry = NewRegistry()
+
+// GetRegistry returns the sensitive path registry used by the northbound
+func GetRegistry() *Registry {
+	return registry
+}
+
+// NewRegistry creates a new registry containing the given paths
+func NewRegistry(paths ...string) *Registry {
+	r := &Registry{
+		paths:    make([]string, 0),
+		matchers: make([]*regexp.Regexp, 0),
+	}
+	r.Register(paths...)
+	return r
+}
+
+// Register adds sensitive paths to the registry
+func (r *Registry) Register(paths ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		r.paths = append(r.paths, path)
+		r.matchers = append(r.matchers, utils.MatchWildcardRegexp(path, true))
+	}
+}
+
+// Paths returns the registered sensitive paths
+func (r *Registry) Paths() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	paths := make([]string, len(r.paths))
+	copy(paths, r.paths)
+	return paths
+}
+
+// IsSensitive returns true if the value of the path is a secret
+func (r *Registry) IsSensitive(path string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, matcher := range r.matchers {
+		if matcher.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// MayHoldSensitive returns true if the path or a path beneath it may be sensitive, e.g. for a JSON
+// value holding a subtree
+func (r *Registry) MayHoldSensitive(path string) bool {
+	if r.IsSensitive(path) {
+		return true
+	}
+	elems := utils.SplitPath(path)
+	for _, pattern := range r.Paths() {
+		if isAncestor(elems, utils.SplitPath(pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAncestor returns true if the path of the given elements is above the paths of the pattern
+func isAncestor(elems []string, pattern []string) bool {
+	for i, elem := range elems {
+		if i >= len(pattern) {
+			return false
+		}
+		if pattern[i] == "..." {
+			return true
+		}
+		if !utils.MatchWildcardRegexp(pattern[i], true).MatchString(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+// CanReveal returns true if a caller in the given groups may see sensitive values
+func CanReveal(groups []string) bool {
+	for _, group := range groups {
+		if group == RevealSecretsGroup {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns the values to send to a caller. The values of sensitive paths are masked unless
+// the caller may reveal them, in which case the reveal is audited. The given slice is not modified.
+func (r *Registry) Redact(target string, values []*devicechange.PathValue, user string, groups []string) []*devicechange.PathValue {
+	sensitive := make([]string, 0)
+	for _, value := range values {
+		if r.IsSensitive(value.Path) {
+			sensitive = append(sensitive, value.Path)
+		}
+	}
+	if len(sensitive) == 0 {
+		return values
+	}
+	if CanReveal(groups) {
+		audit.Record(audit.Entry{
+			User:    user,
+			Action:  RevealSecretsGroup,
+			Target:  target,
+			Paths:   sensitive,
+			Message: "sensitive values returned",
+		})
+		return values
+	}
+
+	redacted := make([]*devicechange.PathValue, 0, len(values))
+	for _, value := range values {
+		if r.IsSensitive(value.Path) {
+			value = &devicechange.PathValue{
+				Path:  value.Path,
+				Value: Mask(value.Value),
+			}
+		}
+		redacted = append(redacted, value)
+	}
+	return redacted
+}
+
+// RedactValue is the single value form of Redact, used for streamed updates
+func (r *Registry) RedactValue(target string, path string, value *devicechange.TypedValue, user string, groups []string) *devicechange.TypedValue {
+	if value == nil || !r.IsSensitive(path) {
+		return value
+	}
+	if CanReveal(groups) {
+		audit.Record(audit.Entry{
+			User:    user,
+			Action:  RevealSecretsGroup,
+			Target:  target,
+			Paths:   []string{path},
+			Message: "sensitive value streamed",
+		})
+		return value
+	}
+	return Mask(value)
+}
+
+// Caller returns the name and groups of the caller of a northbound call, as established by
+// the interceptor chain
+func Caller(ctx context.Context) (string, []string) {
+	if ctx == nil {
+		return "", nil
+	}
+	md := metautils.ExtractIncoming(ctx)
+	var groups []string
+	if md.Get("groups") != "" {
+		groups = strings.Split(md.Get("groups"), ";")
+	}
+	return md.Get("name"), groups
+}
+
+// RedactFor is Redact for the caller of the given northbound call
+func (r *Registry) RedactFor(ctx context.Context, target string, values []*devicechange.PathValue) []*devicechange.PathValue {
+	user, groups := Caller(ctx)
+	return r.Redact(target, values, user, groups)
+}
+
+// RedactValueFor is RedactValue for the caller of the given northbound call
+func (r *Registry) RedactValueFor(ctx context.Context, target string, path string, value *devicechange.TypedValue) *devicechange.TypedValue {
+	user, groups := Caller(ctx)
+	return r.RedactValue(target, path, value, user, groups)
+}
+
+// Searchable returns true if the values of the path may be matched by a search of the caller of
+// the given northbound call. Matching the values of sensitive paths would reveal them one guess
+// at a time, so they are only searched for the callers who may reveal them.
+func (r *Registry) Searchable(ctx context.Context, path string) bool {
+	_, groups := Caller(ctx)
+	return !r.IsSensitive(path) || CanReveal(groups)
+}
+
+// RedactSnapshot returns the snapshot to send to the caller of the given northbound call. The
+// given snapshot is not modified.
+func (r *Registry) RedactSnapshot(ctx context.Context, snapshot *devicesnapshot.Snapshot) *devicesnapshot.Snapshot {
+	redacted := *snapshot
+	redacted.Values = r.RedactFor(ctx, string(snapshot.DeviceID), snapshot.Values)
+	return &redacted
+}
+
+// RedactDeviceChange returns the device change to send to the caller of the given northbound
+// call. The given change is not modified.
+func (r *Registry) RedactDeviceChange(ctx context.Context, change *devicechange.DeviceChange) *devicechange.DeviceChange {
+	if change.Change == nil {
+		return change
+	}
+	redacted := *change
+	redacted.Change = r.redactChange(ctx, change.Change)
+	return &redacted
+}
+
+// RedactNetworkChange returns the network change to send to the caller of the given northbound
+// call. The given change is not modified.
+func (r *Registry) RedactNetworkChange(ctx context.Context, change *networkchange.NetworkChange) *networkchange.NetworkChange {
+	redacted := *change
+	redacted.Changes = make([]*devicechange.Change, 0, len(change.Changes))
+	for _, deviceChange := range change.Changes {
+		redacted.Changes = append(redacted.Changes, r.redactChange(ctx, deviceChange))
+	}
+	return &redacted
+}
+
+func (r *Registry) redactChange(ctx context.Context, change *devicechange.Change) *devicechange.Change {
+	pathValues := make([]*devicechange.PathValue, 0, len(change.Values))
+	for _, value := range change.Values {
+		pathValues = append(pathValues, &devicechange.PathValue{Path: value.Path, Value: value.Value})
+	}
+	pathValues = r.RedactFor(ctx, string(change.DeviceID), pathValues)
+	redacted := *change
+	redacted.Values = make([]*devicechange.ChangeValue, 0, len(change.Values))
+	for i, value := range change.Values {
+		if pathValues[i].Value != value.Value {
+			value = &devicechange.ChangeValue{
+				Path:    value.Path,
+				Value:   pathValues[i].Value,
+				Removed: value.Removed,
+			}
+		}
+		redacted.Values = append(redacted.Values, value)
+	}
+	return &redacted
+}
+
+// Mask returns the value standing for a sensitive value, of the same type
+func Mask(value *devicechange.TypedValue) *devicechange.TypedValue {
+	if value == nil {
+		return nil
+	}
+	switch value.Type {
+	case devicechange.ValueType_EMPTY:
+		return value
+	case devicechange.ValueType_STRING:
+		return devicechange.NewTypedValueString(RedactedValue)
+	case devicechange.ValueType_BYTES:
+		return devicechange.NewTypedValueBytes([]byte(RedactedValue))
+	case devicechange.ValueType_LEAFLIST_STRING:
+		return devicechange.NewLeafListStringTv([]string{RedactedValue})
+	case devicechange.ValueType_LEAFLIST_BYTES:
+		return devicechange.NewLeafListBytesTv([][]byte{[]byte(RedactedValue)})
+	default:
+		// Numbers and booleans are encoded in a fixed number of bytes, that are zero for the
+		// zero value
+		return &devicechange.TypedValue{
+			Bytes:    make([]byte, len(value.Bytes)),
+			Type:     value.Type,
+			TypeOpts: value.TypeOpts,
+		}
+	}
+}
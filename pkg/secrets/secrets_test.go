@@ -0,0 +1,150 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	passwordPath = "/system/aaa/authentication/users/user[username=admin]/config/password"
+	hostnamePath = "/system/config/hostname"
+)
+
+func testValues() []*devicechange.PathValue {
+	return []*devicechange.PathValue{
+		{Path: hostnamePath, Value: devicechange.NewTypedValueString("switch1")},
+		{Path: passwordPath, Value: devicechange.NewTypedValueString("s3cr3t")},
+	}
+}
+
+func Test_IsSensitive(t *testing.T) {
+	registry := NewRegistry("/system/aaa/authentication/users/user[username=*]/config/password")
+	assert.True(t, registry.IsSensitive(passwordPath))
+	assert.False(t, registry.IsSensitive(hostnamePath))
+	assert.False(t, registry.IsSensitive(passwordPath+"-hint"))
+}
+
+func Test_MayHoldSensitive(t *testing.T) {
+	registry := NewRegistry("/system/aaa/authentication/users/user[username=*]/config/password", "/keys/...")
+	assert.True(t, registry.MayHoldSensitive(passwordPath))
+	assert.True(t, registry.MayHoldSensitive("/system/aaa"))
+	assert.True(t, registry.MayHoldSensitive("/system/aaa/authentication/users/user[username=admin]"))
+	assert.True(t, registry.MayHoldSensitive("/keys/key[id=1]"))
+	assert.False(t, registry.MayHoldSensitive("/system/config"))
+	assert.False(t, registry.MayHoldSensitive(hostnamePath))
+}
+
+func Test_Redact(t *testing.T) {
+	audit.Clear()
+	registry := NewRegistry("/system/aaa/...")
+	values := testValues()
+
+	redacted := registry.Redact("device-1", values, "alice", []string{"operators"})
+	assert.Len(t, redacted, 2)
+	assert.Equal(t, "switch1", redacted[0].Value.ValueToString())
+	assert.Equal(t, RedactedValue, redacted[1].Value.ValueToString())
+	assert.Equal(t, "s3cr3t", values[1].Value.ValueToString(), "the given values must not be modified")
+	assert.Len(t, audit.Entries(), 0)
+
+	revealed := registry.Redact("device-1", values, "bob", []string{"operators", RevealSecretsGroup})
+	assert.Equal(t, "s3cr3t", revealed[1].Value.ValueToString())
+	entries := audit.Entries()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "bob", entries[0].User)
+	assert.Equal(t, "device-1", entries[0].Target)
+	assert.Equal(t, []string{passwordPath}, entries[0].Paths)
+
+	// Nothing sensitive; nothing audited
+	registry.Redact("device-1", values[:1], "bob", []string{RevealSecretsGroup})
+	assert.Len(t, audit.Entries(), 1)
+}
+
+func Test_RedactValue(t *testing.T) {
+	audit.Clear()
+	registry := NewRegistry("/system/aaa/...")
+	value := devicechange.NewTypedValueString("s3cr3t")
+
+	assert.Equal(t, RedactedValue, registry.RedactValue("device-1", passwordPath, value, "alice", nil).ValueToString())
+	assert.Equal(t, value, registry.RedactValue("device-1", hostnamePath, value, "alice", nil))
+	assert.Equal(t, value, registry.RedactValue("device-1", passwordPath, value, "bob", []string{RevealSecretsGroup}))
+	assert.Len(t, audit.Entries(), 1)
+}
+
+func Test_RedactKeepsType(t *testing.T) {
+	registry := NewRegistry("/system/aaa/...")
+	redacted := registry.Redact("device-1", []*devicechange.PathValue{
+		{Path: passwordPath, Value: devicechange.NewTypedValueInt(1234, devicechange.WidthThirtyTwo)},
+		{Path: passwordPath + "-hash", Value: devicechange.NewTypedValueBytes([]byte{1, 2, 3})},
+		{Path: passwordPath + "-enabled", Value: devicechange.NewTypedValueBool(true)},
+	}, "alice", nil)
+	assert.Equal(t, devicechange.ValueType_INT, redacted[0].Value.Type)
+	assert.Equal(t, "0", redacted[0].Value.ValueToString())
+	assert.Equal(t, devicechange.ValueType_BYTES, redacted[1].Value.Type)
+	assert.Equal(t, []byte(RedactedValue), redacted[1].Value.Bytes)
+	assert.Equal(t, devicechange.ValueType_BOOL, redacted[2].Value.Type)
+	assert.Equal(t, "false", redacted[2].Value.ValueToString())
+}
+
+func Test_RedactForCaller(t *testing.T) {
+	audit.Clear()
+	registry := NewRegistry("/system/aaa/...")
+	alice := metadata.NewIncomingContext(context.Background(), metadata.Pairs("name", "alice", "groups", "operators"))
+	bob := metadata.NewIncomingContext(context.Background(), metadata.Pairs("name", "bob", "groups", "operators;"+RevealSecretsGroup))
+
+	snapshot := &devicesnapshot.Snapshot{DeviceID: "device-1", Values: testValues()}
+	assert.Equal(t, RedactedValue, registry.RedactSnapshot(alice, snapshot).Values[1].Value.ValueToString())
+	assert.Equal(t, "s3cr3t", registry.RedactSnapshot(bob, snapshot).Values[1].Value.ValueToString())
+	assert.Equal(t, "s3cr3t", snapshot.Values[1].Value.ValueToString(), "the given snapshot must not be modified")
+	assert.Len(t, audit.Entries(), 1)
+
+	change := &networkchange.NetworkChange{
+		ID: "change-1",
+		Changes: []*devicechange.Change{{
+			DeviceID: "device-1",
+			Values: []*devicechange.ChangeValue{
+				{Path: hostnamePath, Value: devicechange.NewTypedValueString("switch1")},
+				{Path: passwordPath, Value: devicechange.NewTypedValueString("s3cr3t")},
+				{Path: passwordPath + "-old", Removed: true},
+			},
+		}},
+	}
+	redacted := registry.RedactNetworkChange(alice, change)
+	assert.Equal(t, "switch1", redacted.Changes[0].Values[0].Value.ValueToString())
+	assert.Equal(t, RedactedValue, redacted.Changes[0].Values[1].Value.ValueToString())
+	assert.True(t, redacted.Changes[0].Values[2].Removed)
+	assert.Equal(t, "s3cr3t", change.Changes[0].Values[1].Value.ValueToString(), "the given change must not be modified")
+
+	deviceChange := registry.RedactDeviceChange(context.Background(), &devicechange.DeviceChange{Change: change.Changes[0]})
+	assert.Equal(t, RedactedValue, deviceChange.Change.Values[1].Value.ValueToString())
+}
+
+func Test_Searchable(t *testing.T) {
+	registry := NewRegistry("/system/aaa/...")
+	alice := metadata.NewIncomingContext(context.Background(), metadata.Pairs("name", "alice", "groups", "operators"))
+	bob := metadata.NewIncomingContext(context.Background(), metadata.Pairs("name", "bob", "groups", RevealSecretsGroup))
+	assert.True(t, registry.Searchable(alice, hostnamePath))
+	assert.False(t, registry.Searchable(alice, passwordPath))
+	assert.False(t, registry.Searchable(context.Background(), passwordPath))
+	assert.True(t, registry.Searchable(bob, passwordPath))
+}
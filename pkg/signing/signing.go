@@ -0,0 +1,266 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signing verifies detached signatures attached by clients to gNMI Set requests.
+//
+// The signature is computed over the payload returned by Payload: the signing time and a nonce
+// chosen by the client, followed by the canonical form of the SetRequest returned by
+// Canonicalize. It is made with the private key matching one of the public keys registered
+// with onos-config. Ed25519, ECDSA (ASN.1 encoded, over SHA-256) and RSA PKCS #1 v1.5 (over
+// SHA-256) keys are supported.
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+)
+
+var log = logging.GetLogger("signing")
+
+// MaxClockSkew is how far the signing time of a request may be from the time onos-config
+// receives it. Older signatures are rejected, so that only the signatures made within this
+// window need to be remembered to reject replays.
+const MaxClockSkew = 5 * time.Minute
+
+// nonceRegexp is the form of the nonces chosen by clients
+var nonceRegexp = regexp.MustCompile(`^[a-zA-Z0-9_\-]{8,64}$`)
+
+// Signature is a detached signature over a SetRequest
+type Signature struct {
+	// KeyID identifies the registered public key to verify the signature with
+	KeyID string
+	// Timestamp is when the client signed the request, to the second
+	Timestamp time.Time
+	// Nonce is chosen by the client to make every signed payload unique
+	Nonce string
+	// Value is the raw signature
+	Value []byte
+}
+
+// ParseSignature parses the message of the signature extension,
+// "<key-id>:<unix time>:<nonce>:<base64 signature>"
+func ParseSignature(msg []byte) (*Signature, error) {
+	parts := strings.Split(string(msg), ":")
+	if len(parts) != 4 || parts[0] == "" || parts[3] == "" {
+		return nil, errors.NewInvalid("signature must be given as '<key-id>:<unix time>:<nonce>:<base64 signature>'")
+	}
+	seconds, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, errors.NewInvalid("signature time is not a number of seconds: %v", err)
+	}
+	if !nonceRegexp.MatchString(parts[2]) {
+		return nil, errors.NewInvalid("signature nonce must have 8 to 64 letters, digits, '-' or '_'")
+	}
+	value, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, errors.NewInvalid("signature is not valid base64: %v", err)
+	}
+	return &Signature{
+		KeyID:     parts[0],
+		Timestamp: time.Unix(seconds, 0).UTC(),
+		Nonce:     parts[2],
+		Value:     value,
+	}, nil
+}
+
+// String formats the signature as the message of the signature extension
+func (s *Signature) String() string {
+	return fmt.Sprintf("%s:%d:%s:%s", s.KeyID, s.Timestamp.Unix(), s.Nonce, base64.StdEncoding.EncodeToString(s.Value))
+}
+
+// CheckTimestamp returns an error if the signature was not made within MaxClockSkew of now
+func (s *Signature) CheckTimestamp(now time.Time) error {
+	skew := now.Sub(s.Timestamp)
+	if skew > MaxClockSkew || skew < -MaxClockSkew {
+		return errors.NewForbidden("signature time %s is more than %s away from %s",
+			s.Timestamp.Format(time.RFC3339), MaxClockSkew, now.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Canonicalize returns the canonical form of a SetRequest: its protobuf encoding without its
+// registered extension signatureExtension and without unknown fields, with fields written in
+// field number order and the keys of path elements sorted
+func Canonicalize(req *gnmi.SetRequest, signatureExtension int32) ([]byte, error) {
+	unsigned := proto.Clone(req).(*gnmi.SetRequest)
+	proto.DiscardUnknown(unsigned)
+	extensions := unsigned.Extension[:0]
+	for _, ext := range unsigned.Extension {
+		if ext.GetRegisteredExt().GetId() != gnmi_ext.ExtensionID(signatureExtension) {
+			extensions = append(extensions, ext)
+		}
+	}
+	unsigned.Extension = extensions
+	if len(unsigned.Extension) == 0 {
+		unsigned.Extension = nil
+	}
+
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	if err := buf.Marshal(unsigned); err != nil {
+		return nil, errors.NewInvalid("cannot canonicalize SetRequest: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Payload returns the bytes a signature is computed over: "<unix time>:<nonce>:" followed by
+// the canonical form of the request
+func Payload(sig *Signature, canonical []byte) []byte {
+	prefix := fmt.Sprintf("%d:%s:", sig.Timestamp.Unix(), sig.Nonce)
+	return append([]byte(prefix), canonical...)
+}
+
+// Digest returns the SHA-256 digest of a canonicalized payload
+func Digest(payload []byte) []byte {
+	digest := sha256.Sum256(payload)
+	return digest[:]
+}
+
+// KeyRegistry holds the public keys that signatures are verified against
+type KeyRegistry struct {
+	mu   sync.RWMutex
+	keys map[string]crypto.PublicKey
+	// required rejects unsigned changes when true
+	required bool
+}
+
+var registry = NewKeyRegistry()
+
+// GetKeyRegistry returns the key registry used by the northbound
+func GetKeyRegistry() *KeyRegistry {
+	return registry
+}
+
+// NewKeyRegistry creates an empty key registry
+func NewKeyRegistry() *KeyRegistry {
+	return &KeyRegistry{
+		keys: make(map[string]crypto.PublicKey),
+	}
+}
+
+// SetRequired sets whether Set requests must be signed
+func (r *KeyRegistry) SetRequired(required bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.required = required
+}
+
+// IsRequired returns true if Set requests must be signed
+func (r *KeyRegistry) IsRequired() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.required
+}
+
+// Register registers a public key under the given ID
+func (r *KeyRegistry) Register(keyID string, key crypto.PublicKey) error {
+	switch key.(type) {
+	case ed25519.PublicKey, *ecdsa.PublicKey, *rsa.PublicKey:
+	default:
+		return errors.NewNotSupported("unsupported public key type %T for key '%s'", key, keyID)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys[keyID] = key
+	return nil
+}
+
+// RegisterPEM registers a PEM encoded PKIX public key under the given ID
+func (r *KeyRegistry) RegisterPEM(keyID string, data []byte) error {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return errors.NewInvalid("no PEM data found for key '%s'", keyID)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return errors.NewInvalid("cannot parse public key '%s': %v", keyID, err)
+	}
+	return r.Register(keyID, key)
+}
+
+// LoadDir registers every '*.pem' public key in the given directory, using the
+// file name without its extension as the key ID
+func (r *KeyRegistry) LoadDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		keyID := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if err := r.RegisterPEM(keyID, data); err != nil {
+			return err
+		}
+		log.Infof("Registered signing key '%s'", keyID)
+	}
+	return nil
+}
+
+// KeyIDs returns the IDs of the registered keys
+func (r *KeyRegistry) KeyIDs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]string, 0, len(r.keys))
+	for id := range r.keys {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Verify verifies a signature over the given canonical payload
+func (r *KeyRegistry) Verify(signature *Signature, payload []byte) error {
+	r.mu.RLock()
+	key, ok := r.keys[signature.KeyID]
+	r.mu.RUnlock()
+	if !ok {
+		return errors.NewNotFound("unknown signing key '%s'", signature.KeyID)
+	}
+
+	var valid bool
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(k, payload, signature.Value)
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(k, Digest(payload), signature.Value)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(k, crypto.SHA256, Digest(payload), signature.Value) == nil
+	}
+	if !valid {
+		return errors.NewForbidden("signature does not match key '%s'", signature.KeyID)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signing
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/proto/gnmi_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+// testExtensionID is the registered extension the tests carry signatures in
+const testExtensionID = 104
+
+func testRequest(hostname string) *gnmi.SetRequest {
+	return &gnmi.SetRequest{
+		Update: []*gnmi.Update{
+			{
+				Path: &gnmi.Path{
+					Target: "device-1",
+					Elem:   []*gnmi.PathElem{{Name: "system"}, {Name: "config"}, {Name: "hostname"}},
+				},
+				Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: hostname}},
+			},
+		},
+	}
+}
+
+func testPayload(t *testing.T, sig *Signature, hostname string) []byte {
+	canonical, err := Canonicalize(testRequest(hostname), testExtensionID)
+	assert.NoError(t, err)
+	return Payload(sig, canonical)
+}
+
+func signatureExtension(sig *Signature) *gnmi_ext.Extension {
+	return &gnmi_ext.Extension{
+		Ext: &gnmi_ext.Extension_RegisteredExt{
+			RegisteredExt: &gnmi_ext.RegisteredExtension{
+				Id:  testExtensionID,
+				Msg: []byte(sig.String()),
+			},
+		},
+	}
+}
+
+func Test_ParseSignature(t *testing.T) {
+	sig, err := ParseSignature([]byte("operator-1:1620000000:nonce-0001:AQID"))
+	assert.NoError(t, err)
+	assert.Equal(t, "operator-1", sig.KeyID)
+	assert.Equal(t, time.Unix(1620000000, 0).UTC(), sig.Timestamp)
+	assert.Equal(t, "nonce-0001", sig.Nonce)
+	assert.Equal(t, []byte{1, 2, 3}, sig.Value)
+	assert.Equal(t, "operator-1:1620000000:nonce-0001:AQID", sig.String())
+
+	_, err = ParseSignature([]byte("operator-1:AQID"))
+	assert.True(t, errors.IsInvalid(err))
+	_, err = ParseSignature([]byte("operator-1:yesterday:nonce-0001:AQID"))
+	assert.True(t, errors.IsInvalid(err))
+	_, err = ParseSignature([]byte("operator-1:1620000000:short:AQID"))
+	assert.True(t, errors.IsInvalid(err))
+	_, err = ParseSignature([]byte("operator-1:1620000000:nonce-0001:not base64!"))
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func Test_CheckTimestamp(t *testing.T) {
+	now := time.Now()
+	sig := &Signature{KeyID: "operator-1", Timestamp: now.Add(-time.Minute), Nonce: "nonce-0001"}
+	assert.NoError(t, sig.CheckTimestamp(now))
+	sig.Timestamp = now.Add(-MaxClockSkew - time.Second)
+	assert.True(t, errors.IsForbidden(sig.CheckTimestamp(now)))
+	sig.Timestamp = now.Add(MaxClockSkew + time.Second)
+	assert.True(t, errors.IsForbidden(sig.CheckTimestamp(now)))
+}
+
+func Test_Canonicalize(t *testing.T) {
+	unsigned, err := Canonicalize(testRequest("switch1"), testExtensionID)
+	assert.NoError(t, err)
+
+	req := testRequest("switch1")
+	req.Extension = append(req.Extension, signatureExtension(&Signature{KeyID: "operator-1", Nonce: "nonce-0001", Value: []byte{1}}))
+	signed, err := Canonicalize(req, testExtensionID)
+	assert.NoError(t, err)
+	assert.Equal(t, unsigned, signed, "the signature extension must not be part of the canonical form")
+	assert.Len(t, req.Extension, 1, "the request must not be modified")
+
+	other, err := Canonicalize(testRequest("switch2"), testExtensionID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, unsigned, other)
+}
+
+func Test_CanonicalizeKeyOrder(t *testing.T) {
+	req := testRequest("switch1")
+	req.Update[0].Path.Elem[0].Key = map[string]string{"a": "1", "b": "2", "c": "3", "d": "4"}
+	first, err := Canonicalize(req, testExtensionID)
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		again, err := Canonicalize(req, testExtensionID)
+		assert.NoError(t, err)
+		assert.Equal(t, first, again)
+	}
+}
+
+func Test_Payload(t *testing.T) {
+	sig := &Signature{KeyID: "operator-1", Timestamp: time.Unix(1620000000, 0), Nonce: "nonce-0001"}
+	assert.Equal(t, []byte("1620000000:nonce-0001:abc"), Payload(sig, []byte("abc")))
+
+	other := &Signature{KeyID: "operator-1", Timestamp: sig.Timestamp, Nonce: "nonce-0002"}
+	assert.NotEqual(t, testPayload(t, sig, "switch1"), testPayload(t, other, "switch1"),
+		"the same request signed with another nonce must have another payload")
+}
+
+func Test_VerifyEd25519(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	registry := NewKeyRegistry()
+	assert.NoError(t, registry.Register("operator-1", public))
+
+	sig := &Signature{KeyID: "operator-1", Timestamp: time.Now(), Nonce: "nonce-0001"}
+	payload := testPayload(t, sig, "switch1")
+	sig.Value = ed25519.Sign(private, payload)
+	assert.NoError(t, registry.Verify(sig, payload))
+
+	tampered := testPayload(t, sig, "switch2")
+	assert.True(t, errors.IsForbidden(registry.Verify(sig, tampered)))
+
+	unknown := &Signature{KeyID: "operator-2", Timestamp: sig.Timestamp, Nonce: sig.Nonce, Value: sig.Value}
+	assert.True(t, errors.IsNotFound(registry.Verify(unknown, payload)))
+}
+
+func Test_RegisterPEM(t *testing.T) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	assert.NoError(t, err)
+
+	registry := NewKeyRegistry()
+	assert.NoError(t, registry.RegisterPEM("operator-1", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+	assert.Equal(t, []string{"operator-1"}, registry.KeyIDs())
+	assert.True(t, errors.IsInvalid(registry.RegisterPEM("operator-2", []byte("not a key"))))
+
+	sig := &Signature{KeyID: "operator-1", Timestamp: time.Now(), Nonce: "nonce-0001"}
+	payload := testPayload(t, sig, "switch1")
+	sig.Value, err = ecdsa.SignASN1(rand.Reader, private, Digest(payload))
+	assert.NoError(t, err)
+	assert.NoError(t, registry.Verify(sig, payload))
+}
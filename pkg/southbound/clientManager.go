@@ -25,8 +25,13 @@ import (
 	"io/ioutil"
 	"strings"
 	"sync"
+	"time"
 
 	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/southbound/features"
+	"github.com/onosproject/onos-config/pkg/store/oplog"
+	"github.com/onosproject/onos-config/pkg/store/quarantine"
+	"github.com/onosproject/onos-config/pkg/store/trust"
 	"github.com/onosproject/onos-config/pkg/utils"
 	"github.com/onosproject/onos-lib-go/pkg/certs"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
@@ -42,6 +47,35 @@ var log = logging.GetLogger("southbound")
 var targets = make(map[devicetype.VersionedID]TargetIf)
 var targetMu = &sync.RWMutex{}
 
+// trustStore holds the trust bundles that device TLS configurations may refer to by name
+var trustStore trust.Store
+var trustStoreMu = &sync.RWMutex{}
+
+// SetTrustStore sets the store in which the trust bundles referred to by devices are looked up
+func SetTrustStore(store trust.Store) {
+	trustStoreMu.Lock()
+	defer trustStoreMu.Unlock()
+	trustStore = store
+}
+
+// quarantineStore holds the devices whose capabilities do not match their model
+var quarantineStore quarantine.Store
+var quarantineStoreMu = &sync.RWMutex{}
+
+// SetQuarantineStore sets the store of the devices to which no configuration is pushed
+func SetQuarantineStore(store quarantine.Store) {
+	quarantineStoreMu.Lock()
+	defer quarantineStoreMu.Unlock()
+	quarantineStore = store
+}
+
+// GetQuarantineStore returns the store of the quarantined devices, nil if none is set
+func GetQuarantineStore() quarantine.Store {
+	quarantineStoreMu.RLock()
+	defer quarantineStoreMu.RUnlock()
+	return quarantineStore
+}
+
 // NewTargetItem - add to the target map
 func NewTargetItem(deviceID devicetype.VersionedID, target TargetIf) {
 	targets[deviceID] = target
@@ -67,6 +101,8 @@ func createDestination(device topodevice.Device) (*client.Destination, devicetyp
 		if device.TLS.CaCert == "" {
 			log.Info("Loading default CA onfca")
 			d.TLS.RootCAs = getCertPoolDefault()
+		} else if name, ok := trust.BundleName(device.TLS.CaCert); ok {
+			d.TLS.RootCAs = getCertPoolBundle(name)
 		} else {
 			d.TLS.RootCAs = getCertPool(device.TLS.CaCert)
 		}
@@ -78,6 +114,9 @@ func createDestination(device topodevice.Device) (*client.Destination, devicetyp
 				log.Error("Error loading default certs")
 			}
 			d.TLS.Certificates = []tls.Certificate{clientCerts}
+		} else if name, ok := trust.BundleName(device.TLS.Cert); ok {
+			// The key is part of the client bundle
+			d.TLS.Certificates = []tls.Certificate{getCertificateBundle(name)}
 		} else if device.TLS.Cert != "" && device.TLS.Key != "" {
 			// Load certs given for device
 			d.TLS.Certificates = []tls.Certificate{setCertificate(device.TLS.Cert, device.TLS.Key)}
@@ -112,14 +151,26 @@ func GetTarget(key devicetype.VersionedID) (TargetIf, error) {
 
 // ConnectTarget connects to a given Device according to the passed information establishing a channel to it.
 //TODO make asyc
-//TODO lock channel to allow one request to device at each time
 func (target *Target) ConnectTarget(ctx context.Context, device topodevice.Device) (devicetype.VersionedID, error) {
-	dest, key := createDestination(device)
-	c, err := GnmiClientFactory(ctx, *dest)
-
-	//c.handler := client.NotificationHandler{}
+	dest, key, err := resolveDestination(device)
 	if err != nil {
-		return "", fmt.Errorf("could not create a gNMI client: %v", err)
+		return "", err
+	}
+	chassis := chassisOf(device)
+	var c GnmiClient
+	if chassis != "" {
+		if c, err = connectShared(ctx, chassis, device.Version, *dest); err != nil {
+			return "", err
+		}
+	} else if standby, ok := takeStandby(key, dest.Addrs[0]); ok {
+		log.Infof("Taking over the standby connection to %v", key)
+		c = standby
+	} else {
+		c, err = GnmiClientFactory(ctx, *dest)
+		//c.handler := client.NotificationHandler{}
+		if err != nil {
+			return "", fmt.Errorf("could not create a gNMI client: %v", err)
+		}
 	}
 
 	target.mu.Lock()
@@ -128,15 +179,24 @@ func (target *Target) ConnectTarget(ctx context.Context, device topodevice.Devic
 		target.clt.Close()
 	}
 
+	target.deviceID = devicetype.ID(device.ID)
+	target.deviceVersion = devicetype.Version(device.Version)
+	target.deviceType = devicetype.Type(device.Type)
+	if !features.GetRegistry().IsRegistered(target.deviceType) {
+		log.Infof("Type %s of %v is not registered, assuming the default features", device.Type, key)
+	}
 	target.dest = *dest
 	target.clt = c
 	target.ctx = ctx
+	target.multiplexed = chassis != ""
+	target.connected = time.Now()
+	target.lastError = ""
 	target.mu.Unlock()
 
 	targetMu.Lock()
 	targets[key] = target
 	targetMu.Unlock()
-	return key, err
+	return key, nil
 }
 
 func setCertificate(pathCert string, pathKey string) tls.Certificate {
@@ -159,6 +219,44 @@ func getCertPool(CaPath string) *x509.CertPool {
 	return certPool
 }
 
+func getBundle(name string) (*trust.Bundle, error) {
+	trustStoreMu.RLock()
+	store := trustStore
+	trustStoreMu.RUnlock()
+	if store == nil {
+		return nil, errors.NewNotFound("no trust store to look up bundle '%s' in", name)
+	}
+	return store.Get(name)
+}
+
+func getCertPoolBundle(name string) *x509.CertPool {
+	certPool := x509.NewCertPool()
+	bundle, err := getBundle(name)
+	if err != nil {
+		log.Error("could not load CA bundle ", name, err)
+		return certPool
+	}
+	pool, err := bundle.CertPool()
+	if err != nil {
+		log.Error("failed to append CA certificates ", err)
+		return certPool
+	}
+	return pool
+}
+
+func getCertificateBundle(name string) tls.Certificate {
+	bundle, err := getBundle(name)
+	if err != nil {
+		log.Error("could not load client bundle ", name, err)
+		return tls.Certificate{}
+	}
+	certificate, err := bundle.KeyPair()
+	if err != nil {
+		log.Error("could not load client key pair ", err)
+	}
+	return certificate
+}
+
 func getCertPoolDefault() *x509.CertPool {
 	certPool := x509.NewCertPool()
 	if ok := certPool.AppendCertsFromPEM([]byte(certs.OnfCaCrt)); !ok {
@@ -179,7 +277,13 @@ func (target *Target) CapabilitiesWithString(ctx context.Context, request string
 
 // Capabilities get capabilities according to a formatted request
 func (target *Target) Capabilities(ctx context.Context, request *gpb.CapabilityRequest) (*gpb.CapabilityResponse, error) {
+	ctx, release, err := target.queue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 	response, err := target.Client().Capabilities(ctx, request)
+	target.used(err)
 	if err != nil {
 		return nil, fmt.Errorf("target returned RPC error for Capabilities(%q): %v", request.String(), err)
 	}
@@ -201,9 +305,21 @@ func (target *Target) GetWithString(ctx context.Context, request string) (*gpb.G
 
 // Get can make a get request according to a formatted request
 func (target *Target) Get(ctx context.Context, request *gpb.GetRequest) (*gpb.GetResponse, error) {
+	request = translateGetRequest(request, features.GetRegistry().Get(target.getDeviceType()))
+	if target.isMultiplexed() {
+		request = multiplexGetRequest(request, target.Destination().Target)
+	}
+	ctx, release, err := target.queue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	start := time.Now()
 	response, err := target.Client().Get(ctx, request)
+	logOperation(target.getDeviceID(), oplog.MethodGet, summarizeGetRequest(request), start, err)
+	target.used(err)
 	if err != nil {
-		return nil, fmt.Errorf("target returned RPC error for Get(%q) : %v", request.String(), err)
+		return nil, fmt.Errorf("target returned RPC error for Get(%q) : %w", request.String(), err)
 	}
 	return response, nil
 }
@@ -223,7 +339,30 @@ func (target *Target) SetWithString(ctx context.Context, request string) (*gpb.S
 
 // Set can make a set request according to a formatted request
 func (target *Target) Set(ctx context.Context, request *gpb.SetRequest) (*gpb.SetResponse, error) {
+	// The Set waits for the requests to its device before it takes one of the Sets of all devices
+	ctx, release, err := target.queue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	releaseSet, err := setLimit.acquire(ctx, target.getDeviceID())
+	if err != nil {
+		return nil, err
+	}
+	defer releaseSet()
+	typeFeatures := features.GetRegistry().Get(target.getDeviceType())
+	if typeFeatures.SequentialSets {
+		target.setMu.Lock()
+		defer target.setMu.Unlock()
+	}
+	request = translateSetRequest(request, typeFeatures)
+	if target.isMultiplexed() {
+		request = multiplexSetRequest(request, target.Destination().Target)
+	}
+	start := time.Now()
 	response, err := target.Client().Set(ctx, request)
+	logOperation(target.getDeviceID(), oplog.MethodSet, summarizeSetRequest(request), start, err)
+	target.used(err)
 	if err != nil {
 		return nil, fmt.Errorf("target returned RPC error for Set(%q) : %v", request.String(), err)
 	}
@@ -249,6 +388,9 @@ func (target *Target) Subscribe(ctx context.Context, request *gpb.SubscribeReque
 	c := GnmiBaseClientFactory()
 	err = c.Subscribe(ctx, q, "gnmi")
 	if err != nil {
+		if isConnectionError(err) && ctx.Err() == nil {
+			target.connectionLost(err)
+		}
 		return fmt.Errorf("could not create a gNMI for subscription: %v", err)
 	}
 	return err
@@ -271,9 +413,34 @@ func (target *Target) Client() GnmiClient {
 	return target.clt
 }
 
+// getDeviceID returns the ID of the device the target is connected to
+func (target *Target) getDeviceID() devicetype.ID {
+	target.mu.RLock()
+	defer target.mu.RUnlock()
+	return target.deviceID
+}
+
+// getDeviceType returns the type of the device the target is connected to
+func (target *Target) getDeviceType() devicetype.Type {
+	target.mu.RLock()
+	defer target.mu.RUnlock()
+	return target.deviceType
+}
+
+// isMultiplexed returns whether the target shares the connection to the device with other targets
+func (target *Target) isMultiplexed() bool {
+	target.mu.RLock()
+	defer target.mu.RUnlock()
+	return target.multiplexed
+}
+
 // Close closes the target
 func (target *Target) Close() error {
-	return target.Client().Close()
+	c := target.Client()
+	if c == nil {
+		return nil
+	}
+	return c.Close()
 }
 
 // NewSubscribeRequest returns a SubscribeRequest for the given paths
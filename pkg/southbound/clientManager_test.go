@@ -19,7 +19,10 @@ import (
 	"github.com/golang/protobuf/proto"
 	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
 	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/store/location"
+	"github.com/onosproject/onos-config/pkg/store/oplog"
 	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/openconfig/gnmi/client"
 	"github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/stretchr/testify/assert"
@@ -169,6 +172,148 @@ func Test_ConnectTarget(t *testing.T) {
 	tearDown()
 }
 
+func Test_ConnectStandby(t *testing.T) {
+	setUp(t)
+	defer tearDown()
+	dials := 0
+	GnmiClientFactory = func(ctx context.Context, d client.Destination) (GnmiClient, error) {
+		dials++
+		return TestClientImpl{}, nil
+	}
+
+	// The target takes over the standby connection rather than connecting
+	assert.NoError(t, ConnectStandby(context.Background(), device))
+	assert.Equal(t, 1, dials)
+	assert.Len(t, standbys, 1)
+	getDevice1Target(t)
+	assert.Equal(t, 1, dials)
+	assert.Empty(t, standbys)
+
+	// A standby connection to another address is not taken over
+	assert.NoError(t, ConnectStandby(context.Background(), device))
+	moved := device
+	moved.Address = "localhost:10162"
+	_, err := (&Target{}).ConnectTarget(context.Background(), moved)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, dials)
+	assert.Empty(t, standbys)
+
+	assert.NoError(t, ConnectStandby(context.Background(), device))
+	CloseStandby(devicetype.NewVersionedID("localhost-1", "1.0.0"))
+	assert.Empty(t, standbys)
+}
+
+func Test_ResolveAddress(t *testing.T) {
+	setUp(t)
+	defer tearDown()
+	SetLocationStore(location.NewLocalStore())
+	defer SetLocationStore(nil)
+	saveLookupHost := lookupHost
+	defer func() { lookupHost = saveLookupHost }()
+	ips := []string{"10.0.0.2", "10.0.0.1"}
+	lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		return ips, nil
+	}
+	var dialed string
+	GnmiClientFactory = func(ctx context.Context, d client.Destination) (GnmiClient, error) {
+		dialed = d.Addrs[0]
+		return TestClientImpl{}, nil
+	}
+
+	// A device not located yet is connected to at its topo address
+	device.SerialNumber = "SN-1"
+	address, err := ResolveAddress(device)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:10161", address)
+	endpoint, err := ResolveEndpoint(context.Background(), device)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:10161=10.0.0.1,10.0.0.2", endpoint)
+
+	// Then at the address registered for its serial number, its certificate verified for its host
+	assert.NoError(t, GetLocationStore().Put(&location.Location{SerialNumber: "SN-1", Address: "10.0.1.1:10161"}))
+	device.TLS.Insecure = true
+	dest, _, err := resolveDestination(device)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.1.1:10161"}, dest.Addrs)
+	assert.Equal(t, "localhost", dest.TLS.ServerName)
+	_, err = (&Target{}).ConnectTarget(context.Background(), device)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.1.1:10161", dialed)
+	endpoint, err = ResolveEndpoint(context.Background(), device)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.1.1:10161", endpoint)
+
+	// A device with neither a location nor an address cannot be resolved
+	device.SerialNumber = "SN-2"
+	device.Address = ""
+	_, err = ResolveAddress(device)
+	assert.True(t, errors.IsNotFound(err))
+}
+
+// recordingClient records the Set requests issued through it
+type recordingClient struct {
+	TestClientImpl
+	sets   *[]*gnmi.SetRequest
+	closed *int
+}
+
+func (c recordingClient) Set(ctx context.Context, r *gnmi.SetRequest) (*gnmi.SetResponse, error) {
+	*c.sets = append(*c.sets, r)
+	return c.TestClientImpl.Set(ctx, r)
+}
+
+func (c recordingClient) Close() error {
+	*c.closed++
+	return nil
+}
+
+func Test_ConnectSubTargets(t *testing.T) {
+	setUp(t)
+	defer tearDown()
+	dials := 0
+	closed := 0
+	var sets []*gnmi.SetRequest
+	GnmiClientFactory = func(ctx context.Context, d client.Destination) (GnmiClient, error) {
+		dials++
+		return recordingClient{sets: &sets, closed: &closed}, nil
+	}
+
+	// The device and its sub-targets share a connection
+	chassis := device
+	chassis.SubTargets = []string{"member-1", "member-2"}
+	chassisTarget := &Target{}
+	_, err := chassisTarget.ConnectTarget(context.Background(), chassis)
+	assert.NoError(t, err)
+	member1 := &Target{}
+	key, err := member1.ConnectTarget(context.Background(), *chassis.SubTarget("member-1"))
+	assert.NoError(t, err)
+	assert.Equal(t, devicetype.NewVersionedID("localhost-1/member-1", "1.0.0"), key)
+	member2 := &Target{}
+	_, err = member2.ConnectTarget(context.Background(), *chassis.SubTarget("member-2"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dials)
+
+	// The requests of a sub-target name its target
+	request := &gnmi.SetRequest{Update: []*gnmi.Update{{Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "system"}}}}}}
+	_, err = member2.Set(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Len(t, sets, 1)
+	assert.Equal(t, "member-2", sets[0].Prefix.Target)
+	assert.Nil(t, request.Prefix)
+	_, err = chassisTarget.Set(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Nil(t, sets[1].Prefix)
+
+	// The connection is closed with the last of its targets
+	assert.NoError(t, member1.Close())
+	assert.NoError(t, member1.Close())
+	assert.NoError(t, member2.Close())
+	assert.Equal(t, 0, closed)
+	assert.NoError(t, chassisTarget.Close())
+	assert.Equal(t, 1, closed)
+	assert.Empty(t, sharedConnections)
+}
+
 func Test_BadTarget(t *testing.T) {
 	setUp(t)
 
@@ -448,3 +593,26 @@ func Test_SetWithString(t *testing.T) {
 
 	tearDown()
 }
+
+func Test_OperationLog(t *testing.T) {
+	setUp(t)
+	defer tearDown()
+	operations := oplog.NewLocalStore(10)
+	SetOperationLog(operations)
+	defer SetOperationLog(nil)
+
+	target, _, ctx := getDevice1Target(t)
+	_, err := target.SetWithString(ctx, "delete: <elem: <name: 'system'> elem:<name:'config'> elem: <name: 'hostname'>>")
+	assert.NoError(t, err)
+	_, err = target.GetWithString(ctx, "path: <elem: <name: 'system'>>")
+	assert.NoError(t, err)
+
+	logged, err := operations.List("localhost-1", 0)
+	assert.NoError(t, err)
+	assert.Len(t, logged, 2)
+	assert.Equal(t, oplog.MethodSet, logged[0].Method)
+	assert.Equal(t, "delete: /system/config/hostname", logged[0].Request)
+	assert.Equal(t, oplog.MethodGet, logged[1].Method)
+	assert.Contains(t, logged[1].Request, "paths: /system")
+	assert.Empty(t, logged[1].Code)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/openconfig/gnmi/client"
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
 	"sync"
+	"time"
 )
 
 // TargetGenerator is a function for generating gnmi southbound Targets
@@ -45,10 +46,25 @@ type TargetIf interface {
 
 // Target struct for connecting to gNMI
 type Target struct {
-	dest client.Destination
-	clt  GnmiClient
-	ctx  context.Context
-	mu   sync.RWMutex
+	// deviceID is the device the target is connected to, which its operations are logged for
+	deviceID devicetype.ID
+	// deviceVersion is the version of the device, reported along its ID when the connection drops
+	deviceVersion devicetype.Version
+	dest          client.Destination
+	clt           GnmiClient
+	ctx           context.Context
+	// deviceType is the type of the device, whose features the requests are translated along
+	deviceType devicetype.Type
+	// multiplexed is true if the target shares the connection to the device with other targets
+	multiplexed bool
+	// connected is when the target was last connected
+	connected time.Time
+	// lastUsed is when a request was last sent through the target, and lastError its error
+	lastUsed  time.Time
+	lastError string
+	mu        sync.RWMutex
+	// setMu keeps the Sets to a device of a type applying one Set at a time from overlapping
+	setMu sync.Mutex
 }
 
 // NewTarget is a method for constructing a target
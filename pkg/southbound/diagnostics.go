@@ -0,0 +1,221 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Steps of a device connection test, in the order they are run
+const (
+	StepDNS  = "dns"
+	StepTCP  = "tcp"
+	StepTLS  = "tls"
+	StepAuth = "auth"
+	StepGNMI = "gnmi"
+)
+
+// defaultDiagnosticTimeout bounds each step when the device has no timeout
+const defaultDiagnosticTimeout = 5 * time.Second
+
+// DiagnosticStep is the outcome of one step of a device connection test
+type DiagnosticStep struct {
+	// Step is the name of the step e.g. "tls"
+	Step string `json:"step"`
+	// OK is true if the step succeeded
+	OK bool `json:"ok"`
+	// Skipped is true if the step was not run, because it does not apply or an earlier step failed
+	Skipped bool `json:"skipped,omitempty"`
+	// Duration is how long the step took
+	Duration time.Duration `json:"duration"`
+	// Message describes the outcome
+	Message string `json:"message"`
+}
+
+// ConnectionDiagnostic is the step by step outcome of a device connection test
+type ConnectionDiagnostic struct {
+	DeviceID topodevice.ID     `json:"deviceId"`
+	Address  string            `json:"address"`
+	OK       bool              `json:"ok"`
+	Steps    []*DiagnosticStep `json:"steps"`
+}
+
+// TestDeviceConnection tries to connect to a device the way onos-config would, without keeping
+// the connection nor registering the device: it resolves the address, opens a TCP connection,
+// performs the TLS handshake, then asks the device for its gNMI capabilities. Nothing is read
+// from or written to the device configuration. The first failing step is reported and the
+// following ones are skipped.
+func TestDeviceConnection(ctx context.Context, device topodevice.Device) *ConnectionDiagnostic {
+	// A device identified by its serial number is tested at the address registered for it
+	address := device.Address
+	if resolved, err := ResolveAddress(device); err == nil {
+		address = resolved
+	}
+	d := &diagnostician{
+		diagnostic: &ConnectionDiagnostic{
+			DeviceID: device.ID,
+			Address:  address,
+			Steps:    make([]*DiagnosticStep, 0),
+		},
+		timeout: defaultDiagnosticTimeout,
+	}
+	if device.Timeout != nil && *device.Timeout > 0 {
+		d.timeout = *device.Timeout
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	d.run(StepDNS, func() (string, error) {
+		if err != nil {
+			return "", fmt.Errorf("invalid address '%s': %v", address, err)
+		}
+		if net.ParseIP(host) != nil {
+			return fmt.Sprintf("%s is an IP address", host), nil
+		}
+		dnsCtx, cancel := context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+		addrs, err := net.DefaultResolver.LookupHost(dnsCtx, host)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s resolves to %v", host, addrs), nil
+	})
+
+	var conn net.Conn
+	d.run(StepTCP, func() (string, error) {
+		dialer := &net.Dialer{Timeout: d.timeout}
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("connected to %s", conn.RemoteAddr()), nil
+	})
+	if conn != nil {
+		defer conn.Close()
+	}
+
+	dest, _, err := resolveDestination(device)
+	if err != nil {
+		dest, _ = createDestination(device)
+	}
+	if device.TLS.Plain {
+		d.skip(StepTLS, "plain connection configured")
+	} else {
+		d.run(StepTLS, func() (string, error) {
+			config := dest.TLS.Clone()
+			if config.ServerName == "" {
+				config.ServerName = host
+			}
+			tlsConn := tls.Client(conn, config)
+			if err := conn.SetDeadline(time.Now().Add(d.timeout)); err != nil {
+				return "", err
+			}
+			if err := tlsConn.Handshake(); err != nil {
+				return "", err
+			}
+			state := tlsConn.ConnectionState()
+			if len(state.PeerCertificates) == 0 {
+				return "handshake completed", nil
+			}
+			certificate := state.PeerCertificates[0]
+			message := fmt.Sprintf("handshake completed; device certificate '%s' issued by '%s' expires %s",
+				certificate.Subject, certificate.Issuer, certificate.NotAfter.Format(time.RFC3339))
+			if config.InsecureSkipVerify {
+				message += " (not verified: insecure connection configured)"
+			}
+			return message, nil
+		})
+	}
+
+	var capabilities *gpb.CapabilityResponse
+	var capabilitiesErr error
+	var client GnmiClient
+	d.run(StepAuth, func() (string, error) {
+		clientCtx, cancel := context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+		client, err = GnmiClientFactory(clientCtx, *dest)
+		if err != nil {
+			return "", err
+		}
+		capabilities, capabilitiesErr = client.Capabilities(clientCtx, &gpb.CapabilityRequest{})
+		if code := status.Code(capabilitiesErr); code == codes.Unauthenticated || code == codes.PermissionDenied {
+			return "", capabilitiesErr
+		}
+		// Any other failure is reported by the gNMI step
+		return "credentials accepted", nil
+	})
+	if client != nil {
+		defer client.Close()
+	}
+
+	d.run(StepGNMI, func() (string, error) {
+		if capabilitiesErr != nil {
+			return "", fmt.Errorf("capabilities failed: %v", capabilitiesErr)
+		}
+		if len(capabilities.SupportedEncodings) == 0 {
+			return "", fmt.Errorf("capabilities returned gNMI %s with %d model(s) but no encoding",
+				capabilities.GNMIVersion, len(capabilities.SupportedModels))
+		}
+		return fmt.Sprintf("gNMI %s with %d model(s); encodings %v",
+			capabilities.GNMIVersion, len(capabilities.SupportedModels), capabilities.SupportedEncodings), nil
+	})
+
+	d.diagnostic.OK = !d.failed
+	return d.diagnostic
+}
+
+// diagnostician runs the steps of a connection test
+type diagnostician struct {
+	diagnostic *ConnectionDiagnostic
+	timeout    time.Duration
+	failed     bool
+}
+
+func (d *diagnostician) run(name string, step func() (string, error)) {
+	if d.failed {
+		d.skip(name, "an earlier step failed")
+		return
+	}
+	start := time.Now()
+	message, err := step()
+	result := &DiagnosticStep{
+		Step:     name,
+		OK:       err == nil,
+		Duration: time.Since(start),
+		Message:  message,
+	}
+	if err != nil {
+		result.Message = err.Error()
+		d.failed = true
+	}
+	d.diagnostic.Steps = append(d.diagnostic.Steps, result)
+}
+
+func (d *diagnostician) skip(name string, reason string) {
+	d.diagnostic.Steps = append(d.diagnostic.Steps, &DiagnosticStep{
+		Step:    name,
+		OK:      !d.failed,
+		Skipped: true,
+		Message: reason,
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/openconfig/gnmi/client"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type unauthenticatedClient struct {
+	TestClientImpl
+}
+
+func (unauthenticatedClient) Capabilities(ctx context.Context, r *gnmi.CapabilityRequest) (*gnmi.CapabilityResponse, error) {
+	return nil, status.Error(codes.Unauthenticated, "bad credentials")
+}
+
+func listen(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+	return listener
+}
+
+func assertSteps(t *testing.T, diagnostic *ConnectionDiagnostic, ok []bool, skipped []bool) {
+	assert.Len(t, diagnostic.Steps, 5)
+	for i, step := range diagnostic.Steps {
+		assert.Equal(t, []string{StepDNS, StepTCP, StepTLS, StepAuth, StepGNMI}[i], step.Step)
+		assert.Equal(t, ok[i], step.OK, "step %s: %s", step.Step, step.Message)
+		assert.Equal(t, skipped[i], step.Skipped, "step %s: %s", step.Step, step.Message)
+	}
+}
+
+func Test_TestDeviceConnection(t *testing.T) {
+	setUp(t)
+	defer tearDown()
+	listener := listen(t)
+	defer listener.Close()
+
+	diagnostic := TestDeviceConnection(context.Background(), topodevice.Device{
+		ID:      "device-1",
+		Address: listener.Addr().String(),
+		TLS:     topodevice.TLSConfig{Plain: true},
+	})
+	assert.True(t, diagnostic.OK)
+	assertSteps(t, diagnostic,
+		[]bool{true, true, true, true, true},
+		[]bool{false, false, true, false, false})
+	assert.Contains(t, diagnostic.Steps[4].Message, "gNMI 1.0 with 1 model(s)")
+}
+
+func Test_TestDeviceConnectionUnauthenticated(t *testing.T) {
+	setUp(t)
+	defer tearDown()
+	GnmiClientFactory = func(ctx context.Context, d client.Destination) (GnmiClient, error) {
+		return unauthenticatedClient{}, nil
+	}
+	listener := listen(t)
+	defer listener.Close()
+
+	diagnostic := TestDeviceConnection(context.Background(), topodevice.Device{
+		ID:      "device-1",
+		Address: listener.Addr().String(),
+		TLS:     topodevice.TLSConfig{Plain: true},
+	})
+	assert.False(t, diagnostic.OK)
+	assertSteps(t, diagnostic,
+		[]bool{true, true, true, false, false},
+		[]bool{false, false, true, false, true})
+	assert.Contains(t, diagnostic.Steps[3].Message, "bad credentials")
+}
+
+func Test_TestDeviceConnectionBadAddress(t *testing.T) {
+	setUp(t)
+	defer tearDown()
+
+	diagnostic := TestDeviceConnection(context.Background(), topodevice.Device{
+		ID:      "device-1",
+		Address: "no-port",
+	})
+	assert.False(t, diagnostic.OK)
+	assertSteps(t, diagnostic,
+		[]bool{false, false, false, false, false},
+		[]bool{false, true, true, true, true})
+}
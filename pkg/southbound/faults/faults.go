@@ -0,0 +1,260 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build faults
+// +build faults
+
+// Package faults injects faults in the southbound, for the resilience tests of the change
+// controllers: latency, dropped requests, errors and partial Set responses. It is only built with
+// the faults build tag, so that production builds cannot inject faults; test builds install it
+// with Install, after which faults are injected per device through the Injector.
+package faults
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/openconfig/gnmi/client"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var log = logging.GetLogger("southbound", "faults")
+
+// Southbound methods faults are injected in
+const (
+	MethodCapabilities = "Capabilities"
+	MethodGet          = "Get"
+	MethodSet          = "Set"
+	MethodSubscribe    = "Subscribe"
+)
+
+// Fault is a fault injected in the requests to a device. Its zero value injects nothing.
+type Fault struct {
+	// Methods are the methods the fault is injected in, all of them if empty
+	Methods []string
+	// Probability is the fraction of the requests the fault is injected in, all of them if 0
+	Probability float64
+	// Latency delays the requests, unless their context is done first
+	Latency time.Duration
+	// Drop fails the requests with Unavailable without sending them to the device
+	Drop bool
+	// Code fails the requests with the code and Message without sending them to the device, unless
+	// it is OK
+	Code    codes.Code
+	Message string
+	// DropSetResults is the number of results removed from the end of the Set responses, the
+	// device having applied the whole request
+	DropSetResults int
+}
+
+func (f Fault) appliesTo(method string) bool {
+	if len(f.Methods) == 0 {
+		return true
+	}
+	for _, m := range f.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// Injector holds the faults injected per device
+type Injector struct {
+	mu     sync.RWMutex
+	faults map[devicetype.ID]Fault
+	rand   *rand.Rand
+}
+
+var injector = NewInjector(time.Now().UnixNano())
+
+// GetInjector returns the injector of the targets installed with Install
+func GetInjector() *Injector {
+	return injector
+}
+
+// NewInjector creates an injector, drawing the requests faults are injected in from the seed
+func NewInjector(seed int64) *Injector {
+	return &Injector{
+		faults: make(map[devicetype.ID]Fault),
+		rand:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Inject injects a fault in the requests to a device, replacing any fault injected before
+func (i *Injector) Inject(deviceID devicetype.ID, fault Fault) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	log.Infof("Injecting %+v in the requests to %s", fault, deviceID)
+	i.faults[deviceID] = fault
+}
+
+// Clear stops injecting faults in the requests to a device
+func (i *Injector) Clear(deviceID devicetype.ID) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.faults, deviceID)
+}
+
+// ClearAll stops injecting faults
+func (i *Injector) ClearAll() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults = make(map[devicetype.ID]Fault)
+}
+
+// Fault returns the fault injected in the requests to a device, if any
+func (i *Injector) Fault(deviceID devicetype.ID) (Fault, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	fault, ok := i.faults[deviceID]
+	return fault, ok
+}
+
+// draw returns the fault to inject in a request of the method to a device, if any
+func (i *Injector) draw(deviceID devicetype.ID, method string) (Fault, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	fault, ok := i.faults[deviceID]
+	if !ok || !fault.appliesTo(method) {
+		return Fault{}, false
+	}
+	if fault.Probability > 0 && i.rand.Float64() >= fault.Probability {
+		return Fault{}, false
+	}
+	return fault, true
+}
+
+// Install makes the southbound create targets that inject the faults of the injector
+func Install() {
+	newTarget := southbound.TargetGenerator
+	southbound.TargetGenerator = func() southbound.TargetIf {
+		return Wrap(newTarget(), GetInjector())
+	}
+	log.Warn("Southbound fault injection is installed")
+}
+
+// Wrap returns a target injecting the faults of the injector in the requests to the target
+func Wrap(target southbound.TargetIf, injector *Injector) southbound.TargetIf {
+	return &faultyTarget{TargetIf: target, injector: injector}
+}
+
+// faultyTarget injects faults in the requests to its device. The requests made through its
+// Client bypass it.
+type faultyTarget struct {
+	southbound.TargetIf
+	injector *Injector
+	mu       sync.RWMutex
+	deviceID devicetype.ID
+}
+
+func (t *faultyTarget) ConnectTarget(ctx context.Context, device topodevice.Device) (devicetype.VersionedID, error) {
+	t.mu.Lock()
+	t.deviceID = devicetype.ID(device.ID)
+	t.mu.Unlock()
+	return t.TargetIf.ConnectTarget(ctx, device)
+}
+
+// before injects the fault of the request, if any; it returns the fault and the error the request
+// fails with without reaching the device
+func (t *faultyTarget) before(ctx context.Context, method string) (Fault, error) {
+	t.mu.RLock()
+	deviceID := t.deviceID
+	t.mu.RUnlock()
+	fault, ok := t.injector.draw(deviceID, method)
+	if !ok {
+		return fault, nil
+	}
+	if fault.Latency > 0 {
+		select {
+		case <-time.After(fault.Latency):
+		case <-ctx.Done():
+			return fault, ctx.Err()
+		}
+	}
+	if fault.Drop {
+		return fault, status.Errorf(codes.Unavailable, "%s request to %s dropped by fault injection", method, deviceID)
+	}
+	if fault.Code != codes.OK {
+		return fault, status.Error(fault.Code, fault.Message)
+	}
+	return fault, nil
+}
+
+func (t *faultyTarget) CapabilitiesWithString(ctx context.Context, request string) (*gpb.CapabilityResponse, error) {
+	if _, err := t.before(ctx, MethodCapabilities); err != nil {
+		return nil, err
+	}
+	return t.TargetIf.CapabilitiesWithString(ctx, request)
+}
+
+func (t *faultyTarget) Get(ctx context.Context, request *gpb.GetRequest) (*gpb.GetResponse, error) {
+	if _, err := t.before(ctx, MethodGet); err != nil {
+		return nil, err
+	}
+	return t.TargetIf.Get(ctx, request)
+}
+
+func (t *faultyTarget) GetWithString(ctx context.Context, request string) (*gpb.GetResponse, error) {
+	if _, err := t.before(ctx, MethodGet); err != nil {
+		return nil, err
+	}
+	return t.TargetIf.GetWithString(ctx, request)
+}
+
+func (t *faultyTarget) Set(ctx context.Context, request *gpb.SetRequest) (*gpb.SetResponse, error) {
+	fault, err := t.before(ctx, MethodSet)
+	if err != nil {
+		return nil, err
+	}
+	response, err := t.TargetIf.Set(ctx, request)
+	return truncate(response, fault.DropSetResults), err
+}
+
+func (t *faultyTarget) SetWithString(ctx context.Context, request string) (*gpb.SetResponse, error) {
+	fault, err := t.before(ctx, MethodSet)
+	if err != nil {
+		return nil, err
+	}
+	response, err := t.TargetIf.SetWithString(ctx, request)
+	return truncate(response, fault.DropSetResults), err
+}
+
+func (t *faultyTarget) Subscribe(ctx context.Context, request *gpb.SubscribeRequest, handler client.ProtoHandler) error {
+	if _, err := t.before(ctx, MethodSubscribe); err != nil {
+		return err
+	}
+	return t.TargetIf.Subscribe(ctx, request, handler)
+}
+
+// truncate removes the given number of results from the end of a Set response
+func truncate(response *gpb.SetResponse, drop int) *gpb.SetResponse {
+	if response == nil || drop <= 0 {
+		return response
+	}
+	keep := len(response.Response) - drop
+	if keep < 0 {
+		keep = 0
+	}
+	response.Response = response.Response[:keep]
+	return response
+}
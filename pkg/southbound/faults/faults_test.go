@@ -0,0 +1,119 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build faults
+// +build faults
+
+package faults
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	mocksouthbound "github.com/onosproject/onos-config/pkg/test/mocks/southbound"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func setUpTarget(t *testing.T, injector *Injector) (southbound.TargetIf, *mocksouthbound.MockTargetIf) {
+	mock := mocksouthbound.NewMockTargetIf(gomock.NewController(t))
+	mock.EXPECT().ConnectTarget(gomock.Any(), gomock.Any()).Return(devicetype.NewVersionedID("device-1", "1.0.0"), nil)
+	target := Wrap(mock, injector)
+	_, err := target.ConnectTarget(context.Background(), topodevice.Device{ID: "device-1", Version: "1.0.0"})
+	assert.NilError(t, err)
+	return target, mock
+}
+
+func Test_NoFault(t *testing.T) {
+	target, mock := setUpTarget(t, NewInjector(1))
+	mock.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&gpb.GetResponse{}, nil)
+	_, err := target.Get(context.Background(), &gpb.GetRequest{})
+	assert.NilError(t, err)
+}
+
+func Test_ErrorAndDrop(t *testing.T) {
+	injector := NewInjector(1)
+	target, mock := setUpTarget(t, injector)
+
+	injector.Inject("device-1", Fault{Methods: []string{MethodSet}, Code: codes.ResourceExhausted, Message: "too busy"})
+	_, err := target.Set(context.Background(), &gpb.SetRequest{})
+	assert.Equal(t, status.Code(err), codes.ResourceExhausted)
+	mock.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&gpb.GetResponse{}, nil)
+	_, err = target.Get(context.Background(), &gpb.GetRequest{})
+	assert.NilError(t, err)
+
+	injector.Inject("device-1", Fault{Drop: true})
+	_, err = target.GetWithString(context.Background(), "")
+	assert.Equal(t, status.Code(err), codes.Unavailable)
+	err = target.Subscribe(context.Background(), &gpb.SubscribeRequest{}, nil)
+	assert.Equal(t, status.Code(err), codes.Unavailable)
+
+	injector.Inject("device-2", Fault{Drop: true})
+	injector.Clear("device-1")
+	mock.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&gpb.GetResponse{}, nil)
+	_, err = target.Get(context.Background(), &gpb.GetRequest{})
+	assert.NilError(t, err)
+}
+
+func Test_Latency(t *testing.T) {
+	injector := NewInjector(1)
+	target, mock := setUpTarget(t, injector)
+	injector.Inject("device-1", Fault{Latency: 50 * time.Millisecond})
+
+	mock.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&gpb.GetResponse{}, nil)
+	start := time.Now()
+	_, err := target.Get(context.Background(), &gpb.GetRequest{})
+	assert.NilError(t, err)
+	assert.Assert(t, time.Since(start) >= 50*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err = target.Get(ctx, &gpb.GetRequest{})
+	assert.Equal(t, err, context.DeadlineExceeded)
+}
+
+func Test_PartialSet(t *testing.T) {
+	injector := NewInjector(1)
+	target, mock := setUpTarget(t, injector)
+	injector.Inject("device-1", Fault{DropSetResults: 1})
+
+	mock.EXPECT().Set(gomock.Any(), gomock.Any()).Return(&gpb.SetResponse{Response: []*gpb.UpdateResult{
+		{Op: gpb.UpdateResult_UPDATE}, {Op: gpb.UpdateResult_DELETE}}}, nil)
+	response, err := target.Set(context.Background(), &gpb.SetRequest{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(response.Response), 1)
+	assert.Equal(t, response.Response[0].Op, gpb.UpdateResult_UPDATE)
+}
+
+func Test_Probability(t *testing.T) {
+	injector := NewInjector(1)
+	target, mock := setUpTarget(t, injector)
+	injector.Inject("device-1", Fault{Probability: 0.5, Drop: true})
+
+	mock.EXPECT().Get(gomock.Any(), gomock.Any()).Return(&gpb.GetResponse{}, nil).AnyTimes()
+	dropped := 0
+	for i := 0; i < 1000; i++ {
+		if _, err := target.Get(context.Background(), &gpb.GetRequest{}); err != nil {
+			dropped++
+		}
+	}
+	assert.Assert(t, dropped > 400 && dropped < 600, dropped)
+}
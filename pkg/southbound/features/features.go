@@ -0,0 +1,177 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package features keeps what the devices of each type support of gNMI, which the southbound
+// translates the requests sent to a device along.
+//
+// The device types onos-config knows of are registered at compile time, see builtin; the
+// features of a type can be tuned, or those of another type given, by a YAML file mapping device
+// types to their features, e.g.
+//
+//	Stratum:
+//	  sequentialSets: true
+//	  maxPathsPerSet: 100
+//
+// The features a file leaves out keep their built-in or default values.
+package features
+
+import (
+	"io/ioutil"
+	"sort"
+	"sync"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Features are what the devices of a type support of gNMI
+type Features struct {
+	// Replace is whether the devices apply the replaces of a Set; if not, a replace is sent as a
+	// delete of its path followed by an update
+	Replace bool `yaml:"replace" json:"replace"`
+	// JSONIETF is whether the devices encode values as JSON_IETF; if not, JSON is requested instead
+	JSONIETF bool `yaml:"jsonIetf" json:"jsonIetf"`
+	// SequentialSets is whether the devices apply one Set at a time, so that the Sets to a device
+	// are sent one after the other
+	SequentialSets bool `yaml:"sequentialSets" json:"sequentialSets"`
+	// MaxPathsPerSet is the most paths a Set updates or deletes, a change with more being pushed
+	// with several Sets; unlimited if 0. The max-set-updates label of a device takes precedence.
+	MaxPathsPerSet int `yaml:"maxPathsPerSet" json:"maxPathsPerSet"`
+	// MaxConcurrentRequests is the most gNMI requests issued to a device at once, the others waiting
+	// in its queue; the concurrency set for all the devices if 0
+	MaxConcurrentRequests int `yaml:"maxConcurrentRequests" json:"maxConcurrentRequests"`
+	// IdempotencyKeys is whether the devices accept the idempotency key of a push in the extension
+	// 107 of a Set; if not, the extension is not sent to them
+	IdempotencyKeys bool `yaml:"idempotencyKeys" json:"idempotencyKeys"`
+}
+
+// Default are the features of the device types that are not registered
+var Default = Features{
+	Replace:  true,
+	JSONIETF: true,
+}
+
+// builtin are the features of the device types known at compile time
+var builtin = map[devicetype.Type]Features{
+	"Devicesim": {
+		Replace:  true,
+		JSONIETF: true,
+	},
+	"TestDevice": {
+		Replace:  true,
+		JSONIETF: true,
+	},
+	// Stratum encodes values as PROTO only, and handles a single Set at a time
+	"Stratum": {
+		Replace:        true,
+		SequentialSets: true,
+	},
+}
+
+// Registry holds the features of the device types
+type Registry struct {
+	mu       sync.RWMutex
+	features map[devicetype.Type]Features
+}
+
+var registry = NewRegistry()
+
+// GetRegistry returns the features of the device types known to onos-config
+func GetRegistry() *Registry {
+	return registry
+}
+
+// NewRegistry creates a registry of the device types known at compile time
+func NewRegistry() *Registry {
+	features := make(map[devicetype.Type]Features, len(builtin))
+	for deviceType, typeFeatures := range builtin {
+		features[deviceType] = typeFeatures
+	}
+	return &Registry{features: features}
+}
+
+// Get returns the features of a device type, the default ones if it is not registered
+func (r *Registry) Get(deviceType devicetype.Type) Features {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if typeFeatures, ok := r.features[deviceType]; ok {
+		return typeFeatures
+	}
+	return Default
+}
+
+// IsRegistered returns whether the features of a device type are registered
+func (r *Registry) IsRegistered(deviceType devicetype.Type) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.features[deviceType]
+	return ok
+}
+
+// Set sets the features of a device type
+func (r *Registry) Set(deviceType devicetype.Type, typeFeatures Features) error {
+	if deviceType == "" {
+		return errors.NewInvalid("device type has no name")
+	} else if typeFeatures.MaxPathsPerSet < 0 {
+		return errors.NewInvalid("device type %s: maxPathsPerSet cannot be negative", deviceType)
+	} else if typeFeatures.MaxConcurrentRequests < 0 {
+		return errors.NewInvalid("device type %s: maxConcurrentRequests cannot be negative", deviceType)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.features[deviceType] = typeFeatures
+	return nil
+}
+
+// Load tunes the features of the device types of a YAML file
+func (r *Registry) Load(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	types := make(map[string]yaml.MapSlice)
+	if err := yaml.Unmarshal(data, &types); err != nil {
+		return errors.NewInvalid("cannot parse device types file %s: %v", path, err)
+	}
+	for deviceType, tuning := range types {
+		// The features the file gives are decoded over those of the type
+		typeFeatures := r.Get(devicetype.Type(deviceType))
+		tuningData, err := yaml.Marshal(tuning)
+		if err != nil {
+			return err
+		}
+		if err := yaml.UnmarshalStrict(tuningData, &typeFeatures); err != nil {
+			return errors.NewInvalid("device type %s in %s: %v", deviceType, path, err)
+		}
+		if err := r.Set(devicetype.Type(deviceType), typeFeatures); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List lists the registered device types, sorted
+func (r *Registry) List() []devicetype.Type {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]devicetype.Type, 0, len(r.features))
+	for deviceType := range r.features {
+		types = append(types, deviceType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+	return types
+}
@@ -0,0 +1,71 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package features
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Registry(t *testing.T) {
+	registry := NewRegistry()
+	assert.True(t, registry.IsRegistered("Stratum"))
+	assert.True(t, registry.Get("Stratum").SequentialSets)
+	assert.False(t, registry.Get("Stratum").JSONIETF)
+	assert.False(t, registry.IsRegistered("Unknown"))
+	assert.Equal(t, Default, registry.Get("Unknown"))
+
+	assert.NoError(t, registry.Set("Unknown", Features{MaxPathsPerSet: 10}))
+	assert.Equal(t, Features{MaxPathsPerSet: 10}, registry.Get("Unknown"))
+	assert.Equal(t, []devicetype.Type{"Devicesim", "Stratum", "TestDevice", "Unknown"}, registry.List())
+	assert.True(t, errors.IsInvalid(registry.Set("Unknown", Features{MaxPathsPerSet: -1})))
+	assert.True(t, errors.IsInvalid(registry.Set("Unknown", Features{MaxConcurrentRequests: -1})))
+
+	// The registry of a new replica is not changed
+	assert.False(t, NewRegistry().IsRegistered("Unknown"))
+}
+
+func Test_Load(t *testing.T) {
+	dir, err := ioutil.TempDir("", "features")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "types.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`
+Stratum:
+  maxPathsPerSet: 100
+Vendor:
+  replace: false
+  jsonIetf: true
+  idempotencyKeys: true
+`), 0644))
+
+	registry := NewRegistry()
+	assert.NoError(t, registry.Load(path))
+	// The features left out keep their built-in values
+	assert.Equal(t, Features{Replace: true, SequentialSets: true, MaxPathsPerSet: 100}, registry.Get("Stratum"))
+	assert.Equal(t, Features{JSONIETF: true, IdempotencyKeys: true}, registry.Get("Vendor"))
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("Stratum:\n  maxPaths: 100\n"), 0644))
+	assert.True(t, errors.IsInvalid(registry.Load(path)))
+	assert.NoError(t, ioutil.WriteFile(path, []byte("Stratum:\n  maxPathsPerSet: -1\n"), 0644))
+	assert.True(t, errors.IsInvalid(registry.Load(path)))
+	assert.Error(t, registry.Load(filepath.Join(dir, "missing.yaml")))
+}
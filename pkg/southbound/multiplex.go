@@ -0,0 +1,138 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/openconfig/gnmi/client"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// sharedConnection is a connection to a device shared by the targets of its sub-targets
+type sharedConnection struct {
+	client  GnmiClient
+	address string
+	refs    int
+}
+
+// sharedConnections are the shared connections, by device
+var sharedConnections = make(map[topodevice.ID]*sharedConnection)
+var sharedConnectionsMu = &sync.Mutex{}
+
+// chassisOf returns the device whose connection a device shares, empty if it shares none: the
+// sub-targets of a device and the device itself share its connection
+func chassisOf(device topodevice.Device) topodevice.ID {
+	if device.Chassis != "" {
+		return device.Chassis
+	}
+	if len(device.SubTargets) > 0 {
+		return device.ID
+	}
+	return ""
+}
+
+// connectShared returns a client over the connection shared by a device and its sub-targets,
+// connecting to the device if no target uses the connection yet
+func connectShared(ctx context.Context, chassis topodevice.ID, version string, dest client.Destination) (GnmiClient, error) {
+	sharedConnectionsMu.Lock()
+	defer sharedConnectionsMu.Unlock()
+	conn, ok := sharedConnections[chassis]
+	if !ok || conn.address != dest.Addrs[0] {
+		key := devicetype.NewVersionedID(devicetype.ID(chassis), devicetype.Version(version))
+		c, ok := takeStandby(key, dest.Addrs[0])
+		if !ok {
+			var err error
+			if c, err = GnmiClientFactory(ctx, dest); err != nil {
+				return nil, fmt.Errorf("could not create a gNMI client: %v", err)
+			}
+		}
+		// The targets still using a connection to a previous address close it as they reconnect
+		conn = &sharedConnection{client: c, address: dest.Addrs[0]}
+		sharedConnections[chassis] = conn
+		log.Infof("Connected to %s at %s for its targets", chassis, conn.address)
+	}
+	conn.refs++
+	return &sharedClient{GnmiClient: conn.client, chassis: chassis, conn: conn}, nil
+}
+
+// sharedClient is the client of a target over a shared connection, which is closed once the last
+// of its targets closes its client
+type sharedClient struct {
+	GnmiClient
+	chassis topodevice.ID
+	conn    *sharedConnection
+	once    sync.Once
+}
+
+func (c *sharedClient) Close() error {
+	var err error
+	c.once.Do(func() {
+		sharedConnectionsMu.Lock()
+		defer sharedConnectionsMu.Unlock()
+		c.conn.refs--
+		if c.conn.refs > 0 {
+			return
+		}
+		if sharedConnections[c.chassis] == c.conn {
+			delete(sharedConnections, c.chassis)
+		}
+		log.Infof("Closing the connection to %s shared by its targets", c.chassis)
+		err = c.conn.client.Close()
+	})
+	return err
+}
+
+// withTarget returns the prefix of a request to a target over a shared connection, naming the
+// target for the device to tell the targets apart
+func withTarget(prefix *gpb.Path, target string) *gpb.Path {
+	if target == "" || prefix.GetTarget() != "" {
+		return prefix
+	}
+	if prefix == nil {
+		return &gpb.Path{Target: target}
+	}
+	prefix = proto.Clone(prefix).(*gpb.Path)
+	prefix.Target = target
+	return prefix
+}
+
+// multiplexSetRequest returns a Set request to a target over a shared connection
+func multiplexSetRequest(request *gpb.SetRequest, target string) *gpb.SetRequest {
+	return &gpb.SetRequest{
+		Prefix:    withTarget(request.Prefix, target),
+		Delete:    request.Delete,
+		Replace:   request.Replace,
+		Update:    request.Update,
+		Extension: request.Extension,
+	}
+}
+
+// multiplexGetRequest returns a Get request to a target over a shared connection
+func multiplexGetRequest(request *gpb.GetRequest, target string) *gpb.GetRequest {
+	return &gpb.GetRequest{
+		Prefix:    withTarget(request.Prefix, target),
+		Path:      request.Path,
+		Type:      request.Type,
+		Encoding:  request.Encoding,
+		UseModels: request.UseModels,
+		Extension: request.Extension,
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"strings"
+
+	"github.com/onosproject/onos-config/pkg/store/change/push"
+	"github.com/onosproject/onos-config/pkg/utils"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// nackCategories are the categories of the codes devices reject a Set with
+var nackCategories = map[codes.Code]push.Category{
+	codes.InvalidArgument:    push.CategorySyntax,
+	codes.OutOfRange:         push.CategorySyntax,
+	codes.ResourceExhausted:  push.CategoryResource,
+	codes.AlreadyExists:      push.CategoryResource,
+	codes.FailedPrecondition: push.CategoryResource,
+	codes.Aborted:            push.CategoryResource,
+	codes.Unauthenticated:    push.CategoryAuth,
+	codes.PermissionDenied:   push.CategoryAuth,
+	codes.Unimplemented:      push.CategoryUnsupported,
+	codes.NotFound:           push.CategoryUnsupported,
+}
+
+// nackKeywords categorize the rejections whose code does not tell, e.g. Unknown, by their message.
+// They are checked in order.
+var nackKeywords = []struct {
+	category push.Category
+	keywords []string
+}{
+	{push.CategoryAuth, []string{"unauthorized", "unauthenticated", "permission", "access denied", "authoriz", "authentic"}},
+	{push.CategoryUnsupported, []string{"not supported", "unsupported", "unimplemented", "unknown path", "unknown element", "no such"}},
+	{push.CategoryResource, []string{"resource", "exhausted", "out of memory", "capacity", "limit", "in use", "busy", "locked"}},
+	{push.CategorySyntax, []string{"syntax", "parse", "malformed", "invalid", "bad value", "out of range"}},
+}
+
+// NewNack returns the rejection by a device of a Set request, from the error it answered with.
+// The error is categorized by its gRPC code, or else by its message; the path is the one of the
+// request the message names, or the only path of the request.
+func NewNack(err error, request *gpb.SetRequest) *push.Nack {
+	st := status.Convert(err)
+	nack := &push.Nack{
+		Category: categorize(st),
+		Code:     st.Code().String(),
+		Message:  st.Message(),
+	}
+
+	var paths []string
+	for _, update := range request.GetUpdate() {
+		paths = append(paths, utils.StrPath(update.GetPath()))
+	}
+	for _, update := range request.GetReplace() {
+		paths = append(paths, utils.StrPath(update.GetPath()))
+	}
+	for _, path := range request.GetDelete() {
+		paths = append(paths, utils.StrPath(path))
+	}
+	for _, path := range paths {
+		if strings.Contains(st.Message(), path) && len(path) > len(nack.Path) {
+			nack.Path = path
+		}
+	}
+	if nack.Path == "" && len(paths) == 1 {
+		nack.Path = paths[0]
+	}
+	return nack
+}
+
+func categorize(st *status.Status) push.Category {
+	if category, ok := nackCategories[st.Code()]; ok {
+		return category
+	}
+	message := strings.ToLower(st.Message())
+	for _, category := range nackKeywords {
+		for _, keyword := range category.keywords {
+			if strings.Contains(message, keyword) {
+				return category.category
+			}
+		}
+	}
+	return push.CategoryUnknown
+}
@@ -0,0 +1,74 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/onosproject/onos-config/pkg/store/change/push"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func setRequest(t *testing.T, paths ...string) *gnmi.SetRequest {
+	request := &gnmi.SetRequest{}
+	for _, path := range paths {
+		gnmiPath, err := utils.ParseGNMIElements(utils.SplitPath(path))
+		assert.NoError(t, err)
+		request.Update = append(request.Update, &gnmi.Update{Path: gnmiPath})
+	}
+	return request
+}
+
+func Test_NackCategories(t *testing.T) {
+	request := setRequest(t, "/cont1a/leaf1a")
+	tests := []struct {
+		err      error
+		category push.Category
+	}{
+		{status.Error(codes.InvalidArgument, "value out of bounds"), push.CategorySyntax},
+		{status.Error(codes.ResourceExhausted, "too many VLANs"), push.CategoryResource},
+		{status.Error(codes.PermissionDenied, "read-only user"), push.CategoryAuth},
+		{status.Error(codes.Unimplemented, "replace not implemented"), push.CategoryUnsupported},
+		{status.Error(codes.Unknown, "% Syntax error at 'leaf1a'"), push.CategorySyntax},
+		{status.Error(codes.Unknown, "operation not supported on this platform"), push.CategoryUnsupported},
+		{fmt.Errorf("TCAM resource exhausted"), push.CategoryResource},
+		{status.Error(codes.Internal, "commit failed"), push.CategoryUnknown},
+	}
+	for _, test := range tests {
+		nack := NewNack(test.err, request)
+		assert.Equal(t, test.category, nack.Category, "%v", test.err)
+	}
+}
+
+func Test_NackDetails(t *testing.T) {
+	nack := NewNack(status.Error(codes.InvalidArgument, "% Invalid input"), setRequest(t, "/cont1a/leaf1a"))
+	assert.Equal(t, "InvalidArgument", nack.Code)
+	assert.Equal(t, "% Invalid input", nack.Message)
+	assert.Equal(t, "/cont1a/leaf1a", nack.Path)
+
+	// The path the message names is the one rejected
+	request := setRequest(t, "/cont1a/leaf1a", "/cont1a/cont2a/leaf2a")
+	nack = NewNack(status.Error(codes.InvalidArgument, "bad value for /cont1a/cont2a/leaf2a"), request)
+	assert.Equal(t, "/cont1a/cont2a/leaf2a", nack.Path)
+
+	// Which of the paths is rejected is not known
+	nack = NewNack(status.Error(codes.InvalidArgument, "commit check failed"), request)
+	assert.Equal(t, "", nack.Path)
+}
@@ -0,0 +1,124 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/oplog"
+	"github.com/onosproject/onos-config/pkg/utils"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc/status"
+)
+
+// maxSummaryPaths is the most paths of a request listed in its summary in the operation log
+const maxSummaryPaths = 20
+
+// operationLog holds the log of the Sets and Gets issued to the devices
+var operationLog oplog.Store
+var operationLogMu = &sync.RWMutex{}
+
+// SetOperationLog sets the store the Sets and Gets issued to the devices are logged in
+func SetOperationLog(store oplog.Store) {
+	operationLogMu.Lock()
+	defer operationLogMu.Unlock()
+	operationLog = store
+}
+
+// GetOperationLog returns the store of the operations issued to the devices, nil if none is set
+func GetOperationLog() oplog.Store {
+	operationLogMu.RLock()
+	defer operationLogMu.RUnlock()
+	return operationLog
+}
+
+// logOperation logs an operation issued to a device at start, which completed with err
+func logOperation(deviceID devicetype.ID, method string, request string, start time.Time, err error) {
+	operations := GetOperationLog()
+	if operations == nil || deviceID == "" {
+		return
+	}
+	operation := &oplog.Operation{
+		DeviceID: deviceID,
+		Time:     start,
+		Method:   method,
+		Request:  request,
+		Latency:  time.Since(start),
+	}
+	if err != nil {
+		operation.Code = status.Code(err).String()
+		operation.Error = err.Error()
+	}
+	if err := operations.Append(operation); err != nil {
+		log.Warnf("Could not log the %s issued to %s: %v", method, deviceID, err)
+	}
+}
+
+// summarizeGetRequest returns the summary of a Get request: its type, encoding and paths
+func summarizeGetRequest(request *gpb.GetRequest) string {
+	return fmt.Sprintf("type %s, encoding %s, paths: %s", request.Type, request.Encoding,
+		summarizePaths(request.Prefix, request.Path))
+}
+
+// summarizeSetRequest returns the summary of a Set request: the paths it deletes, replaces and
+// updates, without their values, and its registered extensions, such as the idempotency key of
+// the pushes of device changes
+func summarizeSetRequest(request *gpb.SetRequest) string {
+	parts := make([]string, 0)
+	if len(request.Delete) > 0 {
+		parts = append(parts, "delete: "+summarizePaths(request.Prefix, request.Delete))
+	}
+	if len(request.Replace) > 0 {
+		parts = append(parts, "replace: "+summarizePaths(request.Prefix, updatePaths(request.Replace)))
+	}
+	if len(request.Update) > 0 {
+		parts = append(parts, "update: "+summarizePaths(request.Prefix, updatePaths(request.Update)))
+	}
+	for _, extension := range request.Extension {
+		if registered := extension.GetRegisteredExt(); registered != nil {
+			parts = append(parts, fmt.Sprintf("extension %d: %s", registered.Id, registered.Msg))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+func updatePaths(updates []*gpb.Update) []*gpb.Path {
+	paths := make([]*gpb.Path, 0, len(updates))
+	for _, update := range updates {
+		paths = append(paths, update.Path)
+	}
+	return paths
+}
+
+// summarizePaths returns the list of the paths of a request, up to maxSummaryPaths of them
+func summarizePaths(prefix *gpb.Path, paths []*gpb.Path) string {
+	var prefixPath string
+	if len(prefix.GetElem()) > 0 {
+		prefixPath = utils.StrPath(prefix)
+	}
+	summaries := make([]string, 0, len(paths))
+	for i, path := range paths {
+		if i == maxSummaryPaths {
+			summaries = append(summaries, fmt.Sprintf("and %d more", len(paths)-maxSummaryPaths))
+			break
+		}
+		summaries = append(summaries, prefixPath+utils.StrPath(path))
+	}
+	return strings.Join(summaries, ", ")
+}
@@ -0,0 +1,227 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultReconnectInterval is the delay before the first attempt to reconnect to a device
+	DefaultReconnectInterval = time.Second
+	// DefaultMaxReconnectInterval caps the delay between the attempts to reconnect to a device
+	DefaultMaxReconnectInterval = time.Minute
+	// reconnectJitter is the ratio the delays are randomized by, so that the devices behind a
+	// link that failed are not all retried at once
+	reconnectJitter = 0.5
+)
+
+// ConnectivityState is the state of the connection to a device, as reported by the reconnection
+// of the devices
+type ConnectivityState int
+
+const (
+	// ConnectivityLost is when a request to a device found its connection dropped
+	ConnectivityLost ConnectivityState = iota
+	// ConnectivityRetrying is when an attempt to reconnect to a device failed, and another is due
+	ConnectivityRetrying
+	// ConnectivityRestored is when a device was reconnected to
+	ConnectivityRestored
+)
+
+func (s ConnectivityState) String() string {
+	return [...]string{"Lost", "Retrying", "Restored"}[s]
+}
+
+// ConnectivityEvent is a change of the state of the connection to a device
+type ConnectivityEvent struct {
+	DeviceID devicetype.VersionedID
+	State    ConnectivityState
+	// Attempt is the number of the attempts to reconnect so far
+	Attempt int
+	// Retry is the delay before the next attempt, when retrying
+	Retry time.Duration
+	// Error is why the connection was lost, or why the last attempt failed
+	Error error
+}
+
+// ReconnectFunc reconnects to a device, and resumes its synchronization
+type ReconnectFunc func() error
+
+// reconnection is a device that can be reconnected to
+type reconnection struct {
+	// target is the target of the session that registered the device
+	target    TargetIf
+	reconnect ReconnectFunc
+	// cancel stops the attempts to reconnect, nil if the device is not being reconnected to
+	cancel context.CancelFunc
+}
+
+// reconnector reconnects to the devices whose connection dropped, retrying with a jittered
+// exponential backoff until it is restored or the device is unregistered
+type reconnector struct {
+	mu              sync.Mutex
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	devices         map[devicetype.VersionedID]*reconnection
+	watchers        []chan<- ConnectivityEvent
+}
+
+func newReconnector(initialInterval time.Duration, maxInterval time.Duration) *reconnector {
+	return &reconnector{
+		initialInterval: initialInterval,
+		maxInterval:     maxInterval,
+		devices:         make(map[devicetype.VersionedID]*reconnection),
+	}
+}
+
+// reconnects reconnects to the devices of all the targets
+var reconnects = newReconnector(DefaultReconnectInterval, DefaultMaxReconnectInterval)
+
+// SetReconnectBackoff sets the delay before the first attempt to reconnect to a device, doubled
+// after each failed attempt up to the most delay between two attempts. It applies to the devices
+// whose connection drops next.
+func SetReconnectBackoff(initialInterval time.Duration, maxInterval time.Duration) {
+	if initialInterval <= 0 {
+		initialInterval = DefaultReconnectInterval
+	}
+	if maxInterval < initialInterval {
+		maxInterval = initialInterval
+	}
+	reconnects.mu.Lock()
+	defer reconnects.mu.Unlock()
+	reconnects.initialInterval = initialInterval
+	reconnects.maxInterval = maxInterval
+}
+
+// RegisterReconnect registers how the session of the given target reconnects to a device once
+// its connection drops, replacing that of a previous session
+func RegisterReconnect(key devicetype.VersionedID, target TargetIf, reconnect ReconnectFunc) {
+	reconnects.register(key, target, reconnect)
+}
+
+// UnregisterReconnect stops reconnecting to a device once the session of the given target is
+// closed, unless a newer session has registered the device by now
+func UnregisterReconnect(key devicetype.VersionedID, target TargetIf) {
+	reconnects.unregister(key, target)
+}
+
+// WatchConnectivity sends the changes of the state of the connections to the devices to the given
+// channel. The channel must be read from, as the reconnections wait for their events to be taken.
+func WatchConnectivity(ch chan<- ConnectivityEvent) {
+	reconnects.mu.Lock()
+	defer reconnects.mu.Unlock()
+	reconnects.watchers = append(reconnects.watchers, ch)
+}
+
+// ConnectionLost reports that the connection to a device dropped. The device is reconnected to if
+// it is registered and not being reconnected to already.
+func ConnectionLost(key devicetype.VersionedID, err error) {
+	reconnects.lost(key, err)
+}
+
+// isConnectionError returns whether an error of a request means that the connection dropped
+func isConnectionError(err error) bool {
+	return err != nil && status.Code(err) == codes.Unavailable
+}
+
+func (r *reconnector) register(key devicetype.VersionedID, target TargetIf, reconnect ReconnectFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if device, ok := r.devices[key]; ok && device.cancel != nil {
+		device.cancel()
+	}
+	r.devices[key] = &reconnection{target: target, reconnect: reconnect}
+}
+
+func (r *reconnector) unregister(key devicetype.VersionedID, target TargetIf) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if device, ok := r.devices[key]; ok && device.target == target {
+		if device.cancel != nil {
+			device.cancel()
+		}
+		delete(r.devices, key)
+	}
+}
+
+func (r *reconnector) lost(key devicetype.VersionedID, err error) {
+	r.mu.Lock()
+	device, ok := r.devices[key]
+	if !ok || device.cancel != nil {
+		r.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	device.cancel = cancel
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = r.initialInterval
+	b.MaxInterval = r.maxInterval
+	b.Multiplier = 2
+	b.RandomizationFactor = reconnectJitter
+	// Never stops retrying
+	b.MaxElapsedTime = 0
+	b.Reset()
+	r.mu.Unlock()
+
+	log.Warnf("Connection to %s lost: %v", key, err)
+	go r.reconnect(ctx, key, device, b, err)
+}
+
+// reconnect attempts to reconnect to a device until it succeeds or the context is cancelled
+func (r *reconnector) reconnect(ctx context.Context, key devicetype.VersionedID, device *reconnection, b backoff.BackOff, err error) {
+	r.emit(ConnectivityEvent{DeviceID: key, State: ConnectivityLost, Error: err})
+	delay := b.NextBackOff()
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		err = device.reconnect()
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			r.mu.Lock()
+			device.cancel()
+			device.cancel = nil
+			r.mu.Unlock()
+			log.Infof("Reconnected to %s after %d attempts", key, attempt)
+			r.emit(ConnectivityEvent{DeviceID: key, State: ConnectivityRestored, Attempt: attempt})
+			return
+		}
+		delay = b.NextBackOff()
+		log.Infof("Failed to reconnect to %s: %v. Retry after %v Attempt %d", key, err, delay, attempt)
+		r.emit(ConnectivityEvent{DeviceID: key, State: ConnectivityRetrying, Attempt: attempt, Retry: delay, Error: err})
+	}
+}
+
+func (r *reconnector) emit(event ConnectivityEvent) {
+	r.mu.Lock()
+	watchers := make([]chan<- ConnectivityEvent, len(r.watchers))
+	copy(watchers, r.watchers)
+	r.mu.Unlock()
+	for _, watcher := range watchers {
+		watcher <- event
+	}
+}
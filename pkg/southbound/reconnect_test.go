@@ -0,0 +1,142 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func nextConnectivityEvent(t *testing.T, ch <-chan ConnectivityEvent) ConnectivityEvent {
+	select {
+	case event := <-ch:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no connectivity event")
+		return ConnectivityEvent{}
+	}
+}
+
+func TestReconnector_Backoff(t *testing.T) {
+	r := newReconnector(time.Millisecond, 4*time.Millisecond)
+	ch := make(chan ConnectivityEvent)
+	r.mu.Lock()
+	r.watchers = append(r.watchers, ch)
+	r.mu.Unlock()
+
+	target := &Target{}
+	attempts := 0
+	r.register("device-1:1.0.0", target, func() error {
+		attempts++
+		if attempts < 3 {
+			return fmt.Errorf("attempt %d failed", attempts)
+		}
+		return nil
+	})
+
+	lostErr := status.Error(codes.Unavailable, "connection refused")
+	r.lost("device-1:1.0.0", lostErr)
+	// Reported lost again while it is being reconnected to, the device is reconnected to once
+	r.lost("device-1:1.0.0", lostErr)
+
+	event := nextConnectivityEvent(t, ch)
+	assert.Equal(t, ConnectivityLost, event.State)
+	assert.Equal(t, lostErr, event.Error)
+	for attempt := 1; attempt <= 2; attempt++ {
+		event = nextConnectivityEvent(t, ch)
+		assert.Equal(t, ConnectivityRetrying, event.State)
+		assert.Equal(t, attempt, event.Attempt)
+		assert.EqualError(t, event.Error, fmt.Sprintf("attempt %d failed", attempt))
+		// The delays are jittered by up to half of the delay, the most delay included
+		assert.True(t, event.Retry > 0 && event.Retry <= 6*time.Millisecond, event.Retry)
+	}
+	event = nextConnectivityEvent(t, ch)
+	assert.Equal(t, ConnectivityRestored, event.State)
+	assert.Equal(t, 3, event.Attempt)
+
+	// Once restored, the connection can be lost again
+	r.lost("device-1:1.0.0", lostErr)
+	assert.Equal(t, ConnectivityLost, nextConnectivityEvent(t, ch).State)
+	assert.Equal(t, ConnectivityRestored, nextConnectivityEvent(t, ch).State)
+	assert.Equal(t, 4, attempts)
+}
+
+func TestReconnector_Unregister(t *testing.T) {
+	r := newReconnector(time.Millisecond, time.Millisecond)
+	ch := make(chan ConnectivityEvent, 10)
+	r.mu.Lock()
+	r.watchers = append(r.watchers, ch)
+	r.mu.Unlock()
+
+	// A device that is not registered is not reconnected to
+	r.lost("device-1:1.0.0", status.Error(codes.Unavailable, "connection refused"))
+
+	oldTarget := &Target{}
+	newTarget := &Target{}
+	r.register("device-1:1.0.0", oldTarget, func() error { return nil })
+	r.register("device-1:1.0.0", newTarget, func() error {
+		return fmt.Errorf("still down")
+	})
+	// The session of the old target is closed after the new one registered
+	r.unregister("device-1:1.0.0", oldTarget)
+
+	r.lost("device-1:1.0.0", status.Error(codes.Unavailable, "connection refused"))
+	assert.Equal(t, ConnectivityLost, nextConnectivityEvent(t, ch).State)
+	assert.Equal(t, ConnectivityRetrying, nextConnectivityEvent(t, ch).State)
+
+	r.unregister("device-1:1.0.0", newTarget)
+	r.mu.Lock()
+	assert.Empty(t, r.devices)
+	r.mu.Unlock()
+	// The attempts stop, but for the one underway
+	time.Sleep(10 * time.Millisecond)
+	for len(ch) > 0 {
+		assert.Equal(t, ConnectivityRetrying, (<-ch).State)
+	}
+	time.Sleep(10 * time.Millisecond)
+	assert.Empty(t, ch)
+}
+
+func TestTarget_ConnectionLost(t *testing.T) {
+	saved := reconnects
+	reconnects = newReconnector(time.Millisecond, time.Millisecond)
+	defer func() { reconnects = saved }()
+	ch := make(chan ConnectivityEvent, 10)
+	WatchConnectivity(ch)
+
+	target := &Target{deviceID: "device-1", deviceVersion: "1.0.0"}
+	reconnected := make(chan struct{}, 1)
+	RegisterReconnect("device-1:1.0.0", target, func() error {
+		reconnected <- struct{}{}
+		return nil
+	})
+
+	// Errors of the device itself do not mean the connection dropped
+	target.used(status.Error(codes.InvalidArgument, "invalid path"))
+	target.used(nil)
+	assert.Empty(t, ch)
+
+	target.used(status.Error(codes.Unavailable, "connection refused"))
+	event := nextConnectivityEvent(t, ch)
+	assert.Equal(t, ConnectivityLost, event.State)
+	assert.Equal(t, "device-1:1.0.0", string(event.DeviceID))
+	<-reconnected
+	assert.Equal(t, ConnectivityRestored, nextConnectivityEvent(t, ch).State)
+}
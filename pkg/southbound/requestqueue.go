@@ -0,0 +1,204 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/southbound/features"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	requestsInFlightGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "onos_config_southbound_requests_in_flight",
+		Help: "Number of the gNMI requests issued to a device that have not completed",
+	}, []string{"device_id"})
+	requestsQueuedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "onos_config_southbound_requests_queued",
+		Help: "Number of the gNMI requests to a device waiting for the requests issued before them",
+	}, []string{"device_id"})
+	requestsTimedOutCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "onos_config_southbound_requests_timed_out_total",
+		Help: "Number of the gNMI requests to a device that timed out waiting in its queue",
+	}, []string{"device_id"})
+)
+
+func init() {
+	prometheus.MustRegister(requestsInFlightGauge, requestsQueuedGauge, requestsTimedOutCounter)
+}
+
+// DefaultConcurrency is the most requests issued to a device at once by default
+const DefaultConcurrency = 1
+
+// requestQueue holds the requests to a device, issued in the order they are made
+type requestQueue struct {
+	inFlight int
+	waiting  []chan struct{}
+	// limit is the most requests issued at once, as of the last request made
+	limit int
+}
+
+// requestQueues queue the requests to each device, so that the requests made by concurrent
+// network changes are issued to a device one at a time, or up to its concurrency
+type requestQueues struct {
+	mu          sync.Mutex
+	concurrency int
+	timeout     time.Duration
+	queues      map[devicetype.ID]*requestQueue
+}
+
+func newRequestQueues() *requestQueues {
+	return &requestQueues{
+		concurrency: DefaultConcurrency,
+		queues:      make(map[devicetype.ID]*requestQueue),
+	}
+}
+
+// requests are the queues of the requests issued by all the targets
+var requests = newRequestQueues()
+
+// SetTargetConcurrency sets the most requests issued to a device at once, unless the features of
+// its type say otherwise; unlimited if 0
+func SetTargetConcurrency(concurrency int) {
+	requests.mu.Lock()
+	defer requests.mu.Unlock()
+	requests.concurrency = concurrency
+}
+
+// SetTargetRequestTimeout sets how long a request to a device may take, waiting in its queue
+// included; only bounded by the context of the request if 0
+func SetTargetRequestTimeout(timeout time.Duration) {
+	requests.mu.Lock()
+	defer requests.mu.Unlock()
+	requests.timeout = timeout
+}
+
+// acquire waits for the turn of a request to a device, the concurrency of the type of the device
+// taking precedence if set. It returns the context to issue the request with, bounded by the
+// request timeout, and the function ending the turn once the request completes.
+func (q *requestQueues) acquire(ctx context.Context, deviceID devicetype.ID, concurrency int) (context.Context, func(), error) {
+	q.mu.Lock()
+	if concurrency <= 0 {
+		concurrency = q.concurrency
+	}
+	cancel := func() {}
+	if q.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, q.timeout)
+	}
+	release := func() {
+		q.release(deviceID)
+		cancel()
+	}
+	queue, ok := q.queues[deviceID]
+	if !ok {
+		queue = &requestQueue{}
+		q.queues[deviceID] = queue
+	}
+	queue.limit = concurrency
+	if queue.limit <= 0 || (queue.inFlight < queue.limit && len(queue.waiting) == 0) {
+		queue.inFlight++
+		requestsInFlightGauge.WithLabelValues(string(deviceID)).Set(float64(queue.inFlight))
+		q.mu.Unlock()
+		return ctx, release, nil
+	}
+	ch := make(chan struct{})
+	queue.waiting = append(queue.waiting, ch)
+	requestsQueuedGauge.WithLabelValues(string(deviceID)).Set(float64(len(queue.waiting)))
+	q.mu.Unlock()
+
+	select {
+	case <-ch:
+		return ctx, release, nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		granted := !q.remove(deviceID, ch)
+		q.mu.Unlock()
+		if granted {
+			// The turn came as the context was done
+			q.release(deviceID)
+		}
+		cancel()
+		if ctx.Err() == context.DeadlineExceeded {
+			requestsTimedOutCounter.WithLabelValues(string(deviceID)).Inc()
+			return nil, nil, errors.NewTimeout("request to %s timed out waiting for the requests before it", deviceID)
+		}
+		return nil, nil, ctx.Err()
+	}
+}
+
+// release ends the turn of a request that completed, and issues the requests waiting for it
+func (q *requestQueues) release(deviceID devicetype.ID) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	queue, ok := q.queues[deviceID]
+	if !ok {
+		return
+	}
+	queue.inFlight--
+	for len(queue.waiting) > 0 && (queue.limit <= 0 || queue.inFlight < queue.limit) {
+		ch := queue.waiting[0]
+		queue.waiting = queue.waiting[1:]
+		queue.inFlight++
+		close(ch)
+	}
+	q.update(deviceID, queue)
+}
+
+// remove removes a request that no longer waits from the queue of its device, returning false if
+// its turn came already
+func (q *requestQueues) remove(deviceID devicetype.ID, ch chan struct{}) bool {
+	queue := q.queues[deviceID]
+	for i, waiting := range queue.waiting {
+		if waiting == ch {
+			queue.waiting = append(queue.waiting[:i:i], queue.waiting[i+1:]...)
+			q.update(deviceID, queue)
+			return true
+		}
+	}
+	return false
+}
+
+// update updates the metrics of the queue of a device, and drops the queue once it is empty
+func (q *requestQueues) update(deviceID devicetype.ID, queue *requestQueue) {
+	if queue.inFlight == 0 && len(queue.waiting) == 0 {
+		delete(q.queues, deviceID)
+		requestsInFlightGauge.DeleteLabelValues(string(deviceID))
+		requestsQueuedGauge.DeleteLabelValues(string(deviceID))
+		return
+	}
+	requestsInFlightGauge.WithLabelValues(string(deviceID)).Set(float64(queue.inFlight))
+	requestsQueuedGauge.WithLabelValues(string(deviceID)).Set(float64(len(queue.waiting)))
+}
+
+// stats returns the requests in flight to a device and those waiting in its queue
+func (q *requestQueues) stats(deviceID devicetype.ID) (int, int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if queue, ok := q.queues[deviceID]; ok {
+		return queue.inFlight, len(queue.waiting)
+	}
+	return 0, 0
+}
+
+// queue waits for the turn of a request through the target to the device
+func (target *Target) queue(ctx context.Context) (context.Context, func(), error) {
+	concurrency := features.GetRegistry().Get(target.getDeviceType()).MaxConcurrentRequests
+	return requests.acquire(ctx, target.getDeviceID(), concurrency)
+}
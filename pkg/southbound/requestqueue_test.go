@@ -0,0 +1,117 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestQueues_InOrder(t *testing.T) {
+	queues := newRequestQueues()
+	_, release, err := queues.acquire(context.Background(), "device-1", 0)
+	assert.NoError(t, err)
+
+	// Another device is not held up by the requests to device-1
+	_, release2, err := queues.acquire(context.Background(), "device-2", 0)
+	assert.NoError(t, err)
+	release2()
+
+	issued := make(chan int, 3)
+	for i := 1; i <= 3; i++ {
+		i := i
+		go func() {
+			_, release, err := queues.acquire(context.Background(), "device-1", 0)
+			assert.NoError(t, err)
+			issued <- i
+			release()
+		}()
+		assert.Eventually(t, func() bool {
+			_, queued := queues.stats("device-1")
+			return queued == i
+		}, time.Second, time.Millisecond)
+	}
+	inFlight, _ := queues.stats("device-1")
+	assert.Equal(t, 1, inFlight)
+
+	release()
+	for i := 1; i <= 3; i++ {
+		assert.Equal(t, i, <-issued)
+	}
+	assert.Eventually(t, func() bool {
+		queues.mu.Lock()
+		defer queues.mu.Unlock()
+		return len(queues.queues) == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestRequestQueues_Concurrency(t *testing.T) {
+	queues := newRequestQueues()
+	queues.concurrency = 2
+	_, release1, err := queues.acquire(context.Background(), "device-1", 0)
+	assert.NoError(t, err)
+	_, release2, err := queues.acquire(context.Background(), "device-1", 0)
+	assert.NoError(t, err)
+	inFlight, queued := queues.stats("device-1")
+	assert.Equal(t, 2, inFlight)
+	assert.Equal(t, 0, queued)
+
+	// The concurrency of the type of the device takes precedence
+	_, release3, err := queues.acquire(context.Background(), "device-1", 3)
+	assert.NoError(t, err)
+	release1()
+	release2()
+	release3()
+
+	queues.concurrency = 0
+	for i := 0; i < 10; i++ {
+		_, _, err := queues.acquire(context.Background(), "device-2", 0)
+		assert.NoError(t, err)
+	}
+	inFlight, _ = queues.stats("device-2")
+	assert.Equal(t, 10, inFlight)
+}
+
+func TestRequestQueues_Timeout(t *testing.T) {
+	queues := newRequestQueues()
+	_, release, err := queues.acquire(context.Background(), "device-1", 0)
+	assert.NoError(t, err)
+
+	queues.timeout = 50 * time.Millisecond
+	_, _, err = queues.acquire(context.Background(), "device-1", 0)
+	assert.True(t, errors.IsTimeout(err), "expected timeout, got %v", err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = queues.acquire(ctx, "device-1", 0)
+	assert.Equal(t, context.Canceled, err)
+
+	inFlight, queued := queues.stats("device-1")
+	assert.Equal(t, 1, inFlight)
+	assert.Equal(t, 0, queued)
+	release()
+
+	// A request issued is given the rest of the timeout
+	ctx, release, err = queues.acquire(context.Background(), "device-1", 0)
+	assert.NoError(t, err)
+	_, ok := ctx.Deadline()
+	assert.True(t, ok)
+	release()
+	assert.Error(t, ctx.Err())
+}
@@ -0,0 +1,107 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/store/location"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/client"
+)
+
+// locationStore holds the addresses of the devices identified by their serial number
+var locationStore location.Store
+var locationStoreMu = &sync.RWMutex{}
+
+// lookupHost resolves a DNS name, replaced by tests
+var lookupHost = net.DefaultResolver.LookupHost
+
+// SetLocationStore sets the store in which the addresses of the devices identified by their serial
+// number are looked up
+func SetLocationStore(store location.Store) {
+	locationStoreMu.Lock()
+	defer locationStoreMu.Unlock()
+	locationStore = store
+}
+
+// GetLocationStore returns the store of the device locations, nil if none is set
+func GetLocationStore() location.Store {
+	locationStoreMu.RLock()
+	defer locationStoreMu.RUnlock()
+	return locationStore
+}
+
+// ResolveAddress returns the address to connect to a device at: the address registered for its
+// serial number if it is identified by one and is located, its topo address otherwise
+func ResolveAddress(device topodevice.Device) (string, error) {
+	if device.SerialNumber != "" {
+		if store := GetLocationStore(); store != nil {
+			loc, err := store.Get(device.SerialNumber)
+			if err == nil {
+				return loc.Address, nil
+			} else if !errors.IsNotFound(err) {
+				return "", err
+			}
+		}
+	}
+	if device.Address == "" {
+		return "", errors.NewNotFound("no address known for %s with serial number '%s'", device.ID, device.SerialNumber)
+	}
+	return device.Address, nil
+}
+
+// ResolveEndpoint returns where a device is reached: its address and the IP addresses its host
+// resolves to. The endpoint changes as the device moves, or as its DNS name is mapped to other IPs.
+func ResolveEndpoint(ctx context.Context, device topodevice.Device) (string, error) {
+	address, err := ResolveAddress(device)
+	if err != nil {
+		return "", err
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil || net.ParseIP(host) != nil {
+		return address, nil
+	}
+	ips, err := lookupHost(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(ips)
+	return address + "=" + strings.Join(ips, ","), nil
+}
+
+// resolveDestination returns the destination of a device at its resolved address. The TLS server
+// name stays the host of the device address, for the certificate of a device that moved to verify.
+func resolveDestination(device topodevice.Device) (*client.Destination, devicetype.VersionedID, error) {
+	address, err := ResolveAddress(device)
+	if err != nil {
+		return nil, "", err
+	}
+	dest, key := createDestination(device)
+	if address != device.Address {
+		log.Infof("%s with serial number '%s' is located at %s", device.ID, device.SerialNumber, address)
+		dest.Addrs = []string{address}
+		if host, _, err := net.SplitHostPort(device.Address); err == nil && dest.TLS != nil && dest.TLS.ServerName == "" {
+			dest.TLS.ServerName = host
+		}
+	}
+	return dest, key, nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"context"
+	"sync"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	setsInFlightGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "onos_config_southbound_sets_in_flight",
+		Help: "Number of the gNMI Sets issued to the devices that have not completed",
+	})
+	setsWaitingGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "onos_config_southbound_sets_waiting",
+		Help: "Number of the gNMI Sets to the devices waiting for the limit of the concurrent Sets",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(setsInFlightGauge, setsWaitingGauge)
+}
+
+// setLimiter limits the Sets issued to the devices at once. The Sets beyond the limit wait in a
+// queue of their device, and the freed slots are granted round-robin across the devices with Sets
+// waiting, so that a change to many devices does not hold up the changes to other devices.
+type setLimiter struct {
+	mu       sync.Mutex
+	limit    int
+	inFlight int
+	waiting  map[devicetype.ID][]chan struct{}
+	// devices are the devices with Sets waiting, in the order their next Set is granted
+	devices []devicetype.ID
+}
+
+func newSetLimiter() *setLimiter {
+	return &setLimiter{waiting: make(map[devicetype.ID][]chan struct{})}
+}
+
+// setLimit is the limit of the Sets issued by all the targets
+var setLimit = newSetLimiter()
+
+// SetMaxConcurrentSets sets the most Sets issued to the devices at once; unlimited if 0
+func SetMaxConcurrentSets(limit int) {
+	setLimit.setLimit(limit)
+}
+
+func (l *setLimiter) setLimit(limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = limit
+	l.grant()
+}
+
+// acquire waits for a slot for a Set to a device, returning the function releasing it once the Set
+// completes
+func (l *setLimiter) acquire(ctx context.Context, deviceID devicetype.ID) (func(), error) {
+	l.mu.Lock()
+	if l.limit <= 0 || (l.inFlight < l.limit && len(l.devices) == 0) {
+		l.inFlight++
+		setsInFlightGauge.Set(float64(l.inFlight))
+		l.mu.Unlock()
+		return l.release, nil
+	}
+	ch := make(chan struct{})
+	if len(l.waiting[deviceID]) == 0 {
+		l.devices = append(l.devices, deviceID)
+	}
+	l.waiting[deviceID] = append(l.waiting[deviceID], ch)
+	setsWaitingGauge.Inc()
+	l.mu.Unlock()
+
+	select {
+	case <-ch:
+		return l.release, nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if !l.remove(deviceID, ch) {
+			// The slot was granted as the context was done
+			l.inFlight--
+			l.grant()
+			setsInFlightGauge.Set(float64(l.inFlight))
+		} else {
+			setsWaitingGauge.Dec()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// release releases the slot of a Set that completed
+func (l *setLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	l.grant()
+	setsInFlightGauge.Set(float64(l.inFlight))
+}
+
+// grant grants the free slots to the waiting Sets, to each device in turn
+func (l *setLimiter) grant() {
+	for len(l.devices) > 0 && (l.limit <= 0 || l.inFlight < l.limit) {
+		deviceID := l.devices[0]
+		l.devices = l.devices[1:]
+		queue := l.waiting[deviceID]
+		ch := queue[0]
+		if len(queue) > 1 {
+			l.waiting[deviceID] = queue[1:]
+			l.devices = append(l.devices, deviceID)
+		} else {
+			delete(l.waiting, deviceID)
+		}
+		l.inFlight++
+		setsWaitingGauge.Dec()
+		close(ch)
+	}
+	setsInFlightGauge.Set(float64(l.inFlight))
+}
+
+// remove removes a Set that no longer waits from the queue of its device, returning false if it
+// was granted already
+func (l *setLimiter) remove(deviceID devicetype.ID, ch chan struct{}) bool {
+	queue := l.waiting[deviceID]
+	for i, waiting := range queue {
+		if waiting != ch {
+			continue
+		}
+		if len(queue) > 1 {
+			l.waiting[deviceID] = append(queue[:i:i], queue[i+1:]...)
+			return true
+		}
+		delete(l.waiting, deviceID)
+		for j, id := range l.devices {
+			if id == deviceID {
+				l.devices = append(l.devices[:j:j], l.devices[j+1:]...)
+				break
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLimiter_RoundRobin(t *testing.T) {
+	limiter := newSetLimiter()
+	limiter.setLimit(1)
+	release, err := limiter.acquire(context.Background(), "device-1")
+	assert.NoError(t, err)
+
+	// Device 1 queues three Sets before device 2 queues one; device 2 is granted the second slot
+	granted := make(chan devicetype.ID, 4)
+	wait := func(deviceID devicetype.ID) {
+		release, err := limiter.acquire(context.Background(), deviceID)
+		assert.NoError(t, err)
+		granted <- deviceID
+		release()
+	}
+	waitQueued := func(n int) {
+		assert.Eventually(t, func() bool {
+			limiter.mu.Lock()
+			defer limiter.mu.Unlock()
+			queued := 0
+			for _, queue := range limiter.waiting {
+				queued += len(queue)
+			}
+			return queued == n
+		}, time.Second, time.Millisecond)
+	}
+	for i := 1; i <= 3; i++ {
+		go wait("device-1")
+		waitQueued(i)
+	}
+	go wait("device-2")
+	waitQueued(4)
+
+	release()
+	order := make([]devicetype.ID, 0, 4)
+	for i := 0; i < 4; i++ {
+		order = append(order, <-granted)
+	}
+	assert.Equal(t, []devicetype.ID{"device-1", "device-2", "device-1", "device-1"}, order)
+	assert.Equal(t, 0, limiter.inFlight)
+}
+
+func TestSetLimiter_Cancel(t *testing.T) {
+	limiter := newSetLimiter()
+	limiter.setLimit(1)
+	release, err := limiter.acquire(context.Background(), "device-1")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = limiter.acquire(ctx, "device-2")
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Empty(t, limiter.waiting)
+	assert.Empty(t, limiter.devices)
+
+	// Raising the limit grants a slot at once
+	limiter.setLimit(2)
+	release2, err := limiter.acquire(context.Background(), "device-2")
+	assert.NoError(t, err)
+	release()
+	release2()
+	assert.Equal(t, 0, limiter.inFlight)
+
+	// Unlimited by default
+	limiter.setLimit(0)
+	for i := 0; i < 10; i++ {
+		_, err := limiter.acquire(context.Background(), "device-1")
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var standbyConnectionsGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "onos_config_southbound_standby_connections",
+	Help: "Number of the connections kept to the devices this replica is not the master of",
+})
+
+func init() {
+	prometheus.MustRegister(standbyConnectionsGauge)
+}
+
+// standby is a connection to a device that is not used until this replica becomes its master
+type standby struct {
+	client  GnmiClient
+	address string
+}
+
+// standbys are the standby connections, by device
+var standbys = make(map[devicetype.VersionedID]standby)
+var standbyMu = &sync.Mutex{}
+
+// ConnectStandby connects to a device without using the connection, for the target of the device
+// to take it over once this replica becomes the master of the device, rather than connecting then
+func ConnectStandby(ctx context.Context, device topodevice.Device) error {
+	// The sub-targets of a device take over the standby connection of the device
+	if device.Chassis != "" {
+		return nil
+	}
+	dest, key, err := resolveDestination(device)
+	if err != nil {
+		return err
+	}
+	c, err := GnmiClientFactory(ctx, *dest)
+	if err != nil {
+		return fmt.Errorf("could not create a gNMI client: %v", err)
+	}
+
+	standbyMu.Lock()
+	defer standbyMu.Unlock()
+	if old, ok := standbys[key]; ok {
+		_ = old.client.Close()
+	}
+	standbys[key] = standby{client: c, address: dest.Addrs[0]}
+	standbyConnectionsGauge.Set(float64(len(standbys)))
+	log.Infof("Connected to %v at %s as a standby", key, dest.Addrs[0])
+	return nil
+}
+
+// CloseStandby closes the standby connection to a device, if any
+func CloseStandby(key devicetype.VersionedID) {
+	standbyMu.Lock()
+	defer standbyMu.Unlock()
+	if old, ok := standbys[key]; ok {
+		_ = old.client.Close()
+		delete(standbys, key)
+		standbyConnectionsGauge.Set(float64(len(standbys)))
+	}
+}
+
+// takeStandby takes the standby connection to a device, if any is connected to its address
+func takeStandby(key devicetype.VersionedID, address string) (GnmiClient, bool) {
+	standbyMu.Lock()
+	defer standbyMu.Unlock()
+	s, ok := standbys[key]
+	if !ok {
+		return nil, false
+	}
+	delete(standbys, key)
+	standbyConnectionsGauge.Set(float64(len(standbys)))
+	if s.address != address {
+		_ = s.client.Close()
+		return nil, false
+	}
+	return s.client, true
+}
@@ -112,6 +112,8 @@ func (s *Session) updateDeviceState() error {
 		case events.EventTypeErrorDeviceConnect:
 			// TODO: Retry only on write conflicts
 			_ = backoff.Retry(s.updateDisconnectedDevice, backoff.NewExponentialBackOff())
+		case events.EventTypeErrorModelMismatch:
+			log.Warnf("Device %s is quarantined: %v", event.Subject(), event.Error())
 
 		default:
 
@@ -0,0 +1,174 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/events"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-config/pkg/store/quarantine"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	modelMismatchGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "onos_config_device_model_mismatches",
+		Help: "Number of the models of its plugin that a device does not report, or reports with another version",
+	}, []string{"device_id"})
+	modelMismatchCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "onos_config_device_model_mismatches_detected_total",
+		Help: "Number of the times the models reported by a device were found to diverge from its plugin",
+	}, []string{"device_id"})
+)
+
+func init() {
+	prometheus.MustRegister(modelMismatchGauge, modelMismatchCounter)
+}
+
+// modelMismatches describes each of the expected models that the device does not report, or
+// reports with another version. A device that reports no models at all is not checked.
+func modelMismatches(expected []*gnmi.ModelData, reported []*gnmi.ModelData) []string {
+	if len(reported) == 0 {
+		return nil
+	}
+	reportedVersions := make(map[string][]string)
+	for _, model := range reported {
+		reportedVersions[model.Name] = append(reportedVersions[model.Name], model.Version)
+	}
+
+	mismatches := make([]string, 0)
+	for _, model := range expected {
+		versions, ok := reportedVersions[model.Name]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s %s is not reported", model.Name, model.Version))
+			continue
+		}
+		if model.Version == "" || containsVersion(versions, model.Version) || containsVersion(versions, "") {
+			continue
+		}
+		mismatches = append(mismatches, fmt.Sprintf("%s %s is reported as %s",
+			model.Name, model.Version, strings.Join(versions, ", ")))
+	}
+	sort.Strings(mismatches)
+	return mismatches
+}
+
+func containsVersion(versions []string, version string) bool {
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// checkModels quarantines the device if its capabilities do not match the models of its plugin,
+// and lifts its quarantine once they do
+func (s *Session) checkModels(expected []*gnmi.ModelData, capabilities *gnmi.CapabilityResponse) {
+	s.mu.RLock()
+	closed := s.closed
+	s.mu.RUnlock()
+	if closed {
+		// The metrics of the session may be deleted already
+		return
+	}
+	mismatches := modelMismatches(expected, capabilities.GetSupportedModels())
+	modelMismatchGauge.WithLabelValues(string(s.device.ID)).Set(float64(len(mismatches)))
+	store := southbound.GetQuarantineStore()
+	if store == nil {
+		return
+	}
+	deviceID := devicetype.ID(s.device.ID)
+
+	if len(mismatches) == 0 {
+		err := store.Delete(deviceID)
+		if err == nil {
+			log.Infof("Lifted the quarantine of device %s: its capabilities match model %s:%s",
+				s.device.ID, s.device.Type, s.device.Version)
+		} else if !errors.IsNotFound(err) {
+			log.Errorf("Cannot lift the quarantine of device %s: %v", s.device.ID, err)
+		}
+		return
+	}
+
+	created := time.Now()
+	if previous, err := store.Get(deviceID); err == nil && previous.DeviceVersion == devicetype.Version(s.device.Version) {
+		// A divergence already reported is not raised again at each check
+		if equalMismatches(previous.Mismatches, mismatches) {
+			return
+		}
+		created = previous.Created
+	}
+	err := store.Put(&quarantine.Quarantine{
+		DeviceID:      deviceID,
+		DeviceType:    devicetype.Type(s.device.Type),
+		DeviceVersion: devicetype.Version(s.device.Version),
+		Mismatches:    mismatches,
+		Created:       created,
+	})
+	if err != nil {
+		log.Errorf("Cannot quarantine device %s: %v", s.device.ID, err)
+		return
+	}
+	modelMismatchCounter.WithLabelValues(string(s.device.ID)).Inc()
+	s.deviceResponseChan <- events.NewErrorEventNoChangeID(events.EventTypeErrorModelMismatch, string(s.device.ID),
+		fmt.Errorf("capabilities do not match model %s:%s: %s", s.device.Type, s.device.Version, strings.Join(mismatches, "; ")))
+}
+
+// deleteModelMetrics deletes the model mismatch metrics of a device, once its session is deleted
+func deleteModelMetrics(deviceID devicetype.ID) {
+	modelMismatchGauge.DeleteLabelValues(string(deviceID))
+	modelMismatchCounter.DeleteLabelValues(string(deviceID))
+}
+
+func equalMismatches(previous []string, mismatches []string) bool {
+	if len(previous) != len(mismatches) {
+		return false
+	}
+	for i := range previous {
+		if previous[i] != mismatches[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// watchModels checks the models reported by the device against its plugin every model check
+// interval, for as long as the device is connected, so that a device upgraded in place is found
+func (s *Session) watchModels(ctx context.Context, expected []*gnmi.ModelData) {
+	ticker := time.NewTicker(s.modelCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			capabilities, err := s.target.CapabilitiesWithString(ctx, "")
+			if err != nil {
+				log.Warnf("Cannot check the models of device %s: %v", s.device.ID, err)
+				continue
+			}
+			s.checkModels(expected, capabilities)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/events"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-config/pkg/store/quarantine"
+	mocksouthbound "github.com/onosproject/onos-config/pkg/test/mocks/southbound"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"gotest.tools/assert"
+)
+
+var expectedModels = []*gnmi.ModelData{
+	{Name: "openconfig-interfaces", Version: "2.0.0"},
+	{Name: "openconfig-system", Version: "0.5.0"},
+}
+
+func Test_modelMismatches(t *testing.T) {
+	assert.Equal(t, len(modelMismatches(expectedModels, nil)), 0, "a device reporting no models is not checked")
+	assert.Equal(t, len(modelMismatches(expectedModels, []*gnmi.ModelData{
+		{Name: "openconfig-interfaces", Version: "2.0.0"},
+		{Name: "openconfig-system", Version: "0.5.0"},
+		{Name: "openconfig-platform", Version: "0.12.2"},
+	})), 0)
+
+	mismatches := modelMismatches(expectedModels, []*gnmi.ModelData{
+		{Name: "openconfig-interfaces", Version: "2.4.3"},
+	})
+	assert.DeepEqual(t, mismatches, []string{
+		"openconfig-interfaces 2.0.0 is reported as 2.4.3",
+		"openconfig-system 0.5.0 is not reported",
+	})
+}
+
+func Test_checkModels(t *testing.T) {
+	store := quarantine.NewLocalStore()
+	southbound.SetQuarantineStore(store)
+	t.Cleanup(func() { southbound.SetQuarantineStore(nil) })
+
+	session := &Session{
+		device:             &topodevice.Device{ID: "device-1", Type: "Devicesim", Version: "1.0.0"},
+		deviceResponseChan: make(chan events.DeviceResponse, 1),
+	}
+	session.checkModels(expectedModels, &gnmi.CapabilityResponse{
+		SupportedModels: []*gnmi.ModelData{{Name: "openconfig-interfaces", Version: "2.4.3"}},
+	})
+	quarantined, err := store.Get("device-1")
+	assert.NilError(t, err)
+	assert.Equal(t, string(quarantined.DeviceVersion), "1.0.0")
+	assert.Equal(t, len(quarantined.Mismatches), 2)
+	event := <-session.deviceResponseChan
+	assert.Equal(t, event.EventType(), events.EventTypeErrorModelMismatch)
+	assert.Equal(t, event.Subject(), "device-1")
+
+	// The same divergence found again is not raised again
+	session.checkModels(expectedModels, &gnmi.CapabilityResponse{
+		SupportedModels: []*gnmi.ModelData{{Name: "openconfig-interfaces", Version: "2.4.3"}},
+	})
+	assert.Equal(t, len(session.deviceResponseChan), 0)
+
+	// The quarantine is lifted once the device reports the models
+	session.checkModels(expectedModels, &gnmi.CapabilityResponse{SupportedModels: expectedModels})
+	_, err = store.Get("device-1")
+	assert.Assert(t, errors.IsNotFound(err))
+
+	// The metrics of the device are deleted with it, and not recorded again by its closed session
+	deleteModelMetrics("device-1")
+	session.closed = true
+	session.checkModels(expectedModels, &gnmi.CapabilityResponse{
+		SupportedModels: []*gnmi.ModelData{{Name: "openconfig-interfaces", Version: "2.4.3"}},
+	})
+	assert.Assert(t, !modelMismatchGauge.DeleteLabelValues("device-1"))
+	assert.Assert(t, !modelMismatchCounter.DeleteLabelValues("device-1"))
+}
+
+func Test_watchModels(t *testing.T) {
+	store := quarantine.NewLocalStore()
+	southbound.SetQuarantineStore(store)
+	t.Cleanup(func() { southbound.SetQuarantineStore(nil) })
+
+	// The device is upgraded in place after it connected
+	target := mocksouthbound.NewMockTargetIf(gomock.NewController(t))
+	target.EXPECT().CapabilitiesWithString(gomock.Any(), "").Return(&gnmi.CapabilityResponse{
+		SupportedModels: []*gnmi.ModelData{
+			{Name: "openconfig-interfaces", Version: "2.4.3"},
+			{Name: "openconfig-system", Version: "0.5.0"},
+		},
+	}, nil).AnyTimes()
+	session := &Session{
+		device:             &topodevice.Device{ID: "device-1", Type: "Devicesim", Version: "1.0.0"},
+		deviceResponseChan: make(chan events.DeviceResponse, 1),
+		target:             target,
+		modelCheckInterval: 10 * time.Millisecond,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go session.watchModels(ctx, expectedModels)
+
+	event := <-session.deviceResponseChan
+	assert.Equal(t, event.EventType(), events.EventTypeErrorModelMismatch)
+	quarantined, err := store.Get("device-1")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, quarantined.Mismatches, []string{"openconfig-interfaces 2.0.0 is reported as 2.4.3"})
+}
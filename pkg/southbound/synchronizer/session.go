@@ -27,7 +27,6 @@ import (
 
 	"github.com/onosproject/onos-config/pkg/utils"
 
-	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
 	"github.com/onosproject/onos-config/pkg/dispatcher"
 	"github.com/onosproject/onos-config/pkg/events"
 	"github.com/onosproject/onos-config/pkg/modelregistry"
@@ -35,6 +34,7 @@ import (
 
 	devicestore "github.com/onosproject/onos-config/pkg/store/device"
 	"github.com/onosproject/onos-config/pkg/store/mastership"
+	"github.com/onosproject/onos-config/pkg/store/opstate"
 
 	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
 	topodevice "github.com/onosproject/onos-config/pkg/device"
@@ -48,22 +48,24 @@ const (
 
 // Session a gNMI session
 type Session struct {
-	deviceStore               devicestore.Store
-	mastershipState           *mastership.Mastership
-	nodeID                    cluster.NodeID
-	connected                 bool
-	opStateChan               chan<- events.OperationalStateEvent
-	deviceResponseChan        chan events.DeviceResponse
-	dispatcher                *dispatcher.Dispatcher
-	modelRegistry             *modelregistry.ModelRegistry
-	operationalStateCache     map[topodevice.ID]devicechange.TypedValueMap
-	operationalStateCacheLock *sync.RWMutex
-	deviceChangeStore         device.Store
-	device                    *topodevice.Device
-	target                    southbound.TargetIf
-	cancel                    context.CancelFunc
-	closed                    bool
-	mu                        sync.RWMutex
+	deviceStore           devicestore.Store
+	mastershipState       *mastership.Mastership
+	nodeID                cluster.NodeID
+	connected             bool
+	opStateChan           chan<- events.OperationalStateEvent
+	deviceResponseChan    chan events.DeviceResponse
+	dispatcher            *dispatcher.Dispatcher
+	modelRegistry         *modelregistry.ModelRegistry
+	operationalStateCache *opstate.Cache
+	deviceChangeStore     device.Store
+	device                *topodevice.Device
+	target                southbound.TargetIf
+	cancel                context.CancelFunc
+	closed                bool
+	modelCheckInterval    time.Duration
+	// endpoint is where the device was resolved to be when the session was created
+	endpoint string
+	mu       sync.RWMutex
 }
 
 func (s *Session) getCurrentTerm() (uint64, error) {
@@ -102,6 +104,8 @@ func (s *Session) open() error {
 				s.connected = true
 				s.mu.Unlock()
 			}
+		} else if s.device.WarmStandby {
+			s.connectStandby()
 		}
 
 	}()
@@ -131,10 +135,56 @@ func (s *Session) connect() error {
 		return err
 	}
 
+	// From now on, the device is reconnected to whenever its connection drops
+	s.mu.RLock()
+	if !s.closed {
+		southbound.RegisterReconnect(s.versionedID(), s.target, s.reconnect)
+	}
+	s.mu.RUnlock()
 	return nil
 
 }
 
+// reconnect synchronizes the device again once its connection dropped, the synchronization
+// underway having lost the device
+func (s *Session) reconnect() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return errors.NewUnavailable("session of %s is closed", s.device.ID)
+	}
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+	s.mu.Unlock()
+	stopSubscription(s.device.ID)
+	return s.synchronize()
+}
+
+// connectionLost reports the device disconnected, until it is reconnected to
+func (s *Session) connectionLost(err error) {
+	s.deviceResponseChan <- events.NewErrorEventNoChangeID(events.EventTypeErrorDeviceConnect, string(s.device.ID), err)
+}
+
+func (s *Session) versionedID() devicetype.VersionedID {
+	return devicetype.NewVersionedID(devicetype.ID(s.device.ID), devicetype.Version(s.device.Version))
+}
+
+// connectStandby connects to a device this replica is not the master of without using the
+// connection, for the session created once this replica becomes the master to take it over
+func (s *Session) connectStandby() {
+	ctx := context.Background()
+	if s.device.Timeout != nil && *s.device.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *s.device.Timeout)
+		defer cancel()
+	}
+	if err := southbound.ConnectStandby(ctx, *s.device); err != nil {
+		log.Warnf("Could not connect to %s as a standby: %v", s.device.ID, err)
+	}
+}
+
 // synchronize connects to the device for synchronization
 func (s *Session) synchronize() error {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -163,25 +213,29 @@ func (s *Session) synchronize() error {
 			mStateGetMode = pluginStateGetMode
 		}
 	}
-	valueMap := make(devicechange.TypedValueMap)
-	s.operationalStateCacheLock.Lock()
-	s.operationalStateCache[s.device.ID] = valueMap
-	s.operationalStateCacheLock.Unlock()
+	valueMap, valueMapLock := s.operationalStateCache.Create(s.device.ID)
 	s.mu.RUnlock()
 
 	sync, err := New(ctx, s.device, s.opStateChan, s.deviceResponseChan,
-		valueMap, mReadOnlyPaths, s.target, mStateGetMode, s.operationalStateCacheLock, s.deviceChangeStore)
+		valueMap, mReadOnlyPaths, s.target, mStateGetMode, valueMapLock, s.deviceChangeStore)
 	if err != nil {
 		log.Warnf("Error connecting to device %v: %v", s.device, err)
 		//unregistering the listener for changes to the device
 		//unregistering the listener for changes to the device
 		s.dispatcher.UnregisterOperationalState(string(s.device.ID))
-		s.operationalStateCacheLock.Lock()
-		delete(s.operationalStateCache, s.device.ID)
-		s.operationalStateCacheLock.Unlock()
+		s.operationalStateCache.Delete(s.device.ID)
 		return err
 	}
 
+	// The device is checked against its model before it is reported as connected, so that no
+	// change is pushed to it in between
+	if plugin != nil {
+		s.checkModels(plugin.Model.Data(), sync.capabilities)
+		if s.modelCheckInterval > 0 {
+			go s.watchModels(ctx, plugin.Model.Data())
+		}
+	}
+
 	//spawning two go routines to propagate changes and to get operational state
 	//go sync.syncConfigEventsToDevice(target, respChan)
 	s.deviceResponseChan <- events.NewDeviceConnectedEvent(events.EventTypeDeviceConnected, string(s.device.ID))
@@ -204,9 +258,13 @@ func (s *Session) disconnect() error {
 		s.cancel = nil
 	}
 	s.mu.Unlock()
-	s.operationalStateCacheLock.Lock()
-	delete(s.operationalStateCache, s.device.ID)
-	s.operationalStateCacheLock.Unlock()
+	southbound.UnregisterReconnect(s.versionedID(), s.target)
+	stopSubscription(s.device.ID)
+	s.operationalStateCache.Delete(s.device.ID)
+	if s.target != nil {
+		// Release the connection of the session, unless a newer session has taken its place
+		southbound.ReleaseTarget(s.versionedID(), s.target)
+	}
 	return nil
 }
 
@@ -15,9 +15,12 @@
 package synchronizer
 
 import (
+	"context"
 	"sync"
+	"time"
 
-	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/capacity"
 	topodevice "github.com/onosproject/onos-config/pkg/device"
 	"github.com/onosproject/onos-config/pkg/dispatcher"
 	"github.com/onosproject/onos-config/pkg/events"
@@ -26,23 +29,28 @@ import (
 	"github.com/onosproject/onos-config/pkg/store/change/device"
 	devicestore "github.com/onosproject/onos-config/pkg/store/device"
 	"github.com/onosproject/onos-config/pkg/store/mastership"
+	"github.com/onosproject/onos-config/pkg/store/opstate"
+	"github.com/onosproject/onos-lib-go/pkg/cluster"
 )
 
 // SessionManager is a gNMI session manager
 type SessionManager struct {
-	topoChannel               chan *topodevice.ListResponse
-	opStateChan               chan<- events.OperationalStateEvent
-	deviceStore               devicestore.Store
-	closeCh                   chan struct{}
-	dispatcher                *dispatcher.Dispatcher
-	modelRegistry             *modelregistry.ModelRegistry
-	sessions                  map[topodevice.ID]*Session
-	operationalStateCache     map[topodevice.ID]devicechange.TypedValueMap
-	newTargetFn               func() southbound.TargetIf
-	operationalStateCacheLock *sync.RWMutex
-	deviceChangeStore         device.Store
-	mastershipStore           mastership.Store
-	mu                        sync.RWMutex
+	topoChannel           chan *topodevice.ListResponse
+	opStateChan           chan<- events.OperationalStateEvent
+	deviceStore           devicestore.Store
+	closeCh               chan struct{}
+	dispatcher            *dispatcher.Dispatcher
+	modelRegistry         *modelregistry.ModelRegistry
+	sessions              map[topodevice.ID]*Session
+	operationalStateCache *opstate.Cache
+	newTargetFn           func() southbound.TargetIf
+	deviceChangeStore     device.Store
+	mastershipStore       mastership.Store
+	modelCheckInterval    time.Duration
+	resolveInterval       time.Duration
+	mu                    sync.RWMutex
+	// refused are the devices beyond the limit of the devices, connected to as others are removed
+	refused map[topodevice.ID]*topodevice.Device
 }
 
 // NewSessionManager create a new session manager
@@ -107,7 +115,7 @@ func WithModelRegistry(modelRegistry *modelregistry.ModelRegistry) func(*Session
 }
 
 // WithOperationalStateCache sets operational state cache
-func WithOperationalStateCache(operationalStateCache map[topodevice.ID]devicechange.TypedValueMap) func(*SessionManager) {
+func WithOperationalStateCache(operationalStateCache *opstate.Cache) func(*SessionManager) {
 	return func(sessionManager *SessionManager) {
 		sessionManager.operationalStateCache = operationalStateCache
 	}
@@ -120,17 +128,25 @@ func WithNewTargetFn(newTargetFn func() southbound.TargetIf) func(*SessionManage
 	}
 }
 
-// WithOperationalStateCacheLock sets operational state cache lock
-func WithOperationalStateCacheLock(operationalStateCacheLock *sync.RWMutex) func(*SessionManager) {
+// WithDeviceChangeStore sets device change store
+func WithDeviceChangeStore(deviceChangeStore device.Store) func(*SessionManager) {
 	return func(sessionManager *SessionManager) {
-		sessionManager.operationalStateCacheLock = operationalStateCacheLock
+		sessionManager.deviceChangeStore = deviceChangeStore
 	}
 }
 
-// WithDeviceChangeStore sets device change store
-func WithDeviceChangeStore(deviceChangeStore device.Store) func(*SessionManager) {
+// WithModelCheckInterval sets how often the models reported by the connected devices are checked
+func WithModelCheckInterval(interval time.Duration) func(*SessionManager) {
 	return func(sessionManager *SessionManager) {
-		sessionManager.deviceChangeStore = deviceChangeStore
+		sessionManager.modelCheckInterval = interval
+	}
+}
+
+// WithResolveInterval sets how often the addresses of the connected devices are resolved again, to
+// reconnect to the devices that moved or whose DNS names map to other IPs
+func WithResolveInterval(interval time.Duration) func(*SessionManager) {
+	return func(sessionManager *SessionManager) {
+		sessionManager.resolveInterval = interval
 	}
 }
 
@@ -138,6 +154,12 @@ func WithDeviceChangeStore(deviceChangeStore device.Store) func(*SessionManager)
 func (sm *SessionManager) Start() error {
 	log.Info("Session manager started")
 	go sm.processDeviceEvents(sm.topoChannel)
+	connectivityCh := make(chan southbound.ConnectivityEvent)
+	southbound.WatchConnectivity(connectivityCh)
+	go sm.processConnectivityEvents(connectivityCh)
+	if sm.resolveInterval > 0 {
+		go sm.watchEndpoints()
+	}
 
 	err := sm.deviceStore.Watch(sm.topoChannel)
 	if err != nil {
@@ -159,6 +181,33 @@ func (sm *SessionManager) processDeviceEvents(ch <-chan *topodevice.ListResponse
 	}
 }
 
+// processConnectivityEvents reports the devices whose connection dropped disconnected, until they
+// are reconnected to
+func (sm *SessionManager) processConnectivityEvents(ch <-chan southbound.ConnectivityEvent) {
+	for event := range ch {
+		sm.processConnectivityEvent(event)
+	}
+}
+
+// processConnectivityEvent processes a change of the state of the connection to a device
+func (sm *SessionManager) processConnectivityEvent(event southbound.ConnectivityEvent) {
+	sm.mu.RLock()
+	session, ok := sm.sessions[topodevice.ID(event.DeviceID.GetID())]
+	sm.mu.RUnlock()
+	if !ok || session.device.Version != string(event.DeviceID.GetVersion()) {
+		return
+	}
+	switch event.State {
+	case southbound.ConnectivityLost:
+		session.connectionLost(event.Error)
+	case southbound.ConnectivityRetrying:
+		log.Debugf("Reconnecting to %s, attempt %d failed: %v", event.DeviceID, event.Attempt, event.Error)
+	case southbound.ConnectivityRestored:
+		// The session reported the device connected as it synchronized it again
+		log.Infof("Connection to %s restored", event.DeviceID)
+	}
+}
+
 // processDeviceEvent process a device event
 func (sm *SessionManager) processDeviceEvent(event *topodevice.ListResponse) error {
 	switch event.Type {
@@ -176,12 +225,22 @@ func (sm *SessionManager) processDeviceEvent(event *topodevice.ListResponse) err
 
 	case topodevice.ListResponseUPDATED:
 		session, ok := sm.sessions[event.Device.ID]
+		sm.mu.RLock()
+		_, refused := sm.refused[event.Device.ID]
+		sm.mu.RUnlock()
+		if !ok && refused {
+			return sm.createSession(event.Device)
+		}
 		if !ok {
 			log.Errorf("Session for the device %s does not exist", event.Device.ID)
 			return nil
 		}
-		// If the address is changed, delete the current session and creates  new one
-		if session.device.Address != event.Device.Address {
+		if err := sm.setMastershipPreference(event.Device); err != nil {
+			return err
+		}
+		// If the address, the serial number or the model is changed, delete the current session and creates  new one
+		if session.device.Address != event.Device.Address || session.device.SerialNumber != event.Device.SerialNumber ||
+			session.device.Version != event.Device.Version || session.device.Type != event.Device.Type {
 			err := sm.deleteSession(event.Device)
 			if err != nil {
 				return err
@@ -197,6 +256,7 @@ func (sm *SessionManager) processDeviceEvent(event *topodevice.ListResponse) err
 		if err != nil {
 			return err
 		}
+		sm.admitRefused(event.Device.ID)
 
 	}
 	return nil
@@ -234,7 +294,9 @@ func (sm *SessionManager) handleMastershipEvents(session *Session) {
 					sm.mu.Lock()
 					session.connected = false
 					sm.mu.Unlock()
-
+					if session.device.WarmStandby {
+						session.connectStandby()
+					}
 				}
 			}
 		case <-sm.closeCh:
@@ -244,28 +306,55 @@ func (sm *SessionManager) handleMastershipEvents(session *Session) {
 
 }
 
+// setMastershipPreference sets the master preferred by the labels of a device
+func (sm *SessionManager) setMastershipPreference(device *topodevice.Device) error {
+	return sm.mastershipStore.SetPreference(device.ID, mastership.Preference{
+		Node: cluster.NodeID(device.PreferredMaster),
+		Zone: device.PreferredZone,
+	})
+}
+
 // createSession creates a new gNMI session
 func (sm *SessionManager) createSession(device *topodevice.Device) error {
 
 	log.Info("Creating session for device:", device.ID)
 
+	if err := capacity.GetGuard().AdmitDevice(devicetype.ID(device.ID)); err != nil {
+		sm.mu.Lock()
+		if sm.refused == nil {
+			sm.refused = make(map[topodevice.ID]*topodevice.Device)
+		}
+		sm.refused[device.ID] = device
+		sm.mu.Unlock()
+		return err
+	}
+	sm.mu.Lock()
+	delete(sm.refused, device.ID)
+	sm.mu.Unlock()
+
+	if err := sm.setMastershipPreference(device); err != nil {
+		return err
+	}
 	state, err := sm.mastershipStore.GetMastership(device.ID)
 	if err != nil {
 		return err
 	}
 
 	session := &Session{
-		opStateChan:               sm.opStateChan,
-		dispatcher:                sm.dispatcher,
-		modelRegistry:             sm.modelRegistry,
-		operationalStateCache:     sm.operationalStateCache,
-		operationalStateCacheLock: sm.operationalStateCacheLock,
-		deviceChangeStore:         sm.deviceChangeStore,
-		device:                    device,
-		target:                    sm.newTargetFn(),
-		deviceStore:               sm.deviceStore,
-		mastershipState:           state,
-		nodeID:                    sm.mastershipStore.NodeID(),
+		opStateChan:           sm.opStateChan,
+		dispatcher:            sm.dispatcher,
+		modelRegistry:         sm.modelRegistry,
+		operationalStateCache: sm.operationalStateCache,
+		deviceChangeStore:     sm.deviceChangeStore,
+		device:                device,
+		target:                sm.newTargetFn(),
+		deviceStore:           sm.deviceStore,
+		mastershipState:       state,
+		nodeID:                sm.mastershipStore.NodeID(),
+		modelCheckInterval:    sm.modelCheckInterval,
+	}
+	if sm.resolveInterval > 0 {
+		session.endpoint = sm.resolveEndpoint(device)
 	}
 
 	err = session.open()
@@ -299,7 +388,77 @@ func (sm *SessionManager) deleteSession(device *topodevice.Device) error {
 		if sm.closeCh != nil {
 			close(sm.closeCh)
 		}
+		capacity.GetGuard().ReleaseDevice(devicetype.ID(device.ID))
+		device = session.device
 	}
+	southbound.CloseStandby(devicetype.NewVersionedID(devicetype.ID(device.ID), devicetype.Version(device.Version)))
+	deleteModelMetrics(devicetype.ID(device.ID))
 	return nil
 
 }
+
+// admitRefused connects to a device refused beyond the limit of the devices, if any, once another
+// device was removed
+func (sm *SessionManager) admitRefused(removed topodevice.ID) {
+	sm.mu.Lock()
+	delete(sm.refused, removed)
+	var device *topodevice.Device
+	for _, refused := range sm.refused {
+		device = refused
+		break
+	}
+	sm.mu.Unlock()
+	if device != nil {
+		if err := sm.createSession(device); err != nil {
+			log.Errorf("Error creating session for %s: %v", device.ID, err)
+		}
+	}
+}
+
+// watchEndpoints periodically resolves the endpoints of the devices again, and reconnects to those
+// whose endpoint changed
+func (sm *SessionManager) watchEndpoints() {
+	ticker := time.NewTicker(sm.resolveInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		sm.checkEndpoints()
+	}
+}
+
+// checkEndpoints reconnects to the devices whose endpoint changed since their session was created
+func (sm *SessionManager) checkEndpoints() {
+	sm.mu.RLock()
+	sessions := make([]*Session, 0, len(sm.sessions))
+	for _, session := range sm.sessions {
+		sessions = append(sessions, session)
+	}
+	sm.mu.RUnlock()
+
+	for _, session := range sessions {
+		endpoint := sm.resolveEndpoint(session.device)
+		// A device that cannot be resolved for now is left connected where it was
+		if endpoint == "" || endpoint == session.endpoint {
+			continue
+		}
+		log.Infof("Endpoint of %s changed from '%s' to '%s', reconnecting", session.device.ID, session.endpoint, endpoint)
+		if err := sm.deleteSession(session.device); err != nil {
+			log.Errorf("Error deleting session for %s: %v", session.device.ID, err)
+			continue
+		}
+		if err := sm.createSession(session.device); err != nil {
+			log.Errorf("Error creating session for %s: %v", session.device.ID, err)
+		}
+	}
+}
+
+// resolveEndpoint returns the endpoint of a device, empty if it cannot be resolved
+func (sm *SessionManager) resolveEndpoint(device *topodevice.Device) string {
+	ctx, cancel := context.WithTimeout(context.Background(), sm.resolveInterval)
+	defer cancel()
+	endpoint, err := southbound.ResolveEndpoint(ctx, *device)
+	if err != nil {
+		log.Warnf("Could not resolve the endpoint of %s: %v", device.ID, err)
+		return ""
+	}
+	return endpoint
+}
@@ -16,7 +16,6 @@ package synchronizer
 
 import (
 	"github.com/onosproject/onos-lib-go/pkg/errors"
-	"sync"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -27,6 +26,7 @@ import (
 	"github.com/onosproject/onos-config/pkg/events"
 	modelregistrypkg "github.com/onosproject/onos-config/pkg/modelregistry"
 	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-config/pkg/store/opstate"
 	"github.com/onosproject/onos-config/pkg/store/stream"
 	storemock "github.com/onosproject/onos-config/pkg/test/mocks/store"
 	"gotest.tools/assert"
@@ -35,8 +35,7 @@ import (
 func createSessionManager(t *testing.T) *SessionManager {
 	dispatcher := dispatcherpkg.NewDispatcher()
 	models := new(modelregistrypkg.ModelRegistry)
-	opstateCache := make(map[topodevice.ID]devicechange.TypedValueMap)
-	opStateCacheLock := &sync.RWMutex{}
+	opstateCache := opstate.NewCache(4)
 	ctrl := gomock.NewController(t)
 	deviceChangeStore := storemock.NewMockDeviceChangesStore(ctrl)
 	mastershipStore := storemock.NewMockMastershipStore(ctrl)
@@ -50,6 +49,7 @@ func createSessionManager(t *testing.T) *SessionManager {
 
 	deviceStore.EXPECT().Watch(gomock.Any()).AnyTimes()
 	mastershipStore.EXPECT().Watch(gomock.Any(), gomock.Any()).AnyTimes()
+	mastershipStore.EXPECT().SetPreference(gomock.Any(), gomock.Any()).AnyTimes()
 	mastershipStore.EXPECT().GetMastership(gomock.Any()).AnyTimes()
 	mastershipStore.EXPECT().NodeID().AnyTimes()
 	mastershipStore.EXPECT().Close().AnyTimes()
@@ -64,7 +64,6 @@ func createSessionManager(t *testing.T) *SessionManager {
 		WithModelRegistry(models),
 		WithOperationalStateCache(opstateCache),
 		WithNewTargetFn(southbound.NewTarget),
-		WithOperationalStateCacheLock(opStateCacheLock),
 		WithDeviceChangeStore(deviceChangeStore),
 		WithMastershipStore(mastershipStore),
 		WithDeviceStore(deviceStore),
@@ -116,9 +115,7 @@ func TestSessionManager(t *testing.T) {
 
 	// Wait for gRPC connection to timeout
 	time.Sleep(time.Millisecond * 1000) // Give it a moment for the event to take effect and for timeout to happen
-	sessionManager.operationalStateCacheLock.RLock()
-	opStateCacheUpdated, ok := sessionManager.operationalStateCache[device1.ID]
-	sessionManager.operationalStateCacheLock.RUnlock()
+	opStateCacheUpdated, ok := sessionManager.operationalStateCache.Get(device1.ID)
 	assert.Assert(t, ok, "Op state cache entry created")
 	assert.Equal(t, len(opStateCacheUpdated), 0)
 
@@ -161,9 +158,7 @@ func TestSessionManager(t *testing.T) {
 
 	time.Sleep(1 * time.Second)
 
-	sessionManager.operationalStateCacheLock.RLock()
-	_, ok = sessionManager.operationalStateCache[device1.ID]
-	sessionManager.operationalStateCacheLock.RUnlock()
+	_, ok = sessionManager.operationalStateCache.Get(device1.ID)
 	assert.Assert(t, !ok, "Expected Op state cache entry to have been removed")
 
 	close(sessionManager.topoChannel)
@@ -181,3 +176,35 @@ func TestSessionManager(t *testing.T) {
 	//opStateCacheLock.RUnlock()
 	//assert.Assert(t, !ok, "Op state cache entry deleted")*/
 }
+
+func TestSessionManager_ConnectivityEvents(t *testing.T) {
+	sessionManager := createSessionManager(t)
+	session := &Session{
+		device:             &topodevice.Device{ID: "device-1", Version: "1.0.0"},
+		deviceResponseChan: make(chan events.DeviceResponse, 1),
+	}
+	sessionManager.sessions[session.device.ID] = session
+
+	// The events of another version of the device are not those of its session
+	sessionManager.processConnectivityEvent(southbound.ConnectivityEvent{
+		DeviceID: "device-1:2.0.0",
+		State:    southbound.ConnectivityLost,
+	})
+	assert.Equal(t, 0, len(session.deviceResponseChan))
+
+	sessionManager.processConnectivityEvent(southbound.ConnectivityEvent{
+		DeviceID: "device-1:1.0.0",
+		State:    southbound.ConnectivityLost,
+		Error:    errors.NewUnavailable("connection refused"),
+	})
+	response := <-session.deviceResponseChan
+	assert.Equal(t, events.EventTypeErrorDeviceConnect, response.EventType())
+	assert.Equal(t, "device-1", response.Subject())
+
+	sessionManager.processConnectivityEvent(southbound.ConnectivityEvent{
+		DeviceID: "device-1:1.0.0",
+		State:    southbound.ConnectivityRestored,
+		Attempt:  2,
+	})
+	assert.Equal(t, 0, len(session.deviceResponseChan))
+}
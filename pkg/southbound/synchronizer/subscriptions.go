@@ -0,0 +1,302 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	configmodel "github.com/onosproject/onos-config-model/pkg/model"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/store/sampling"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Subscription is the subscription of onos-config to the operational state of a device
+type Subscription struct {
+	DeviceID topodevice.ID
+	// Mode is how the state paths of the device were found, from its model plugin
+	Mode configmodel.GetStateMode
+	// Paths are the paths subscribed to; none while the device is not subscribed to
+	Paths []string
+	// Added are the subtrees subscribed to beyond the state paths of the device
+	Added []string
+	// Removed are the subtrees of the state of the device not subscribed to
+	Removed []string
+}
+
+// subtrees are the subtrees added to and removed from the subscription of a device at runtime
+type subtrees struct {
+	added   map[string]bool
+	removed map[string]bool
+}
+
+// activeSubscription is a running subscription, with the functions restarting and stopping it
+type activeSubscription struct {
+	mode    configmodel.GetStateMode
+	paths   []string
+	restart func()
+	stop    func()
+}
+
+// subscriptions are the running subscriptions to the state of the devices, and the subtrees of
+// their state changed at runtime, which are kept across their reconnections
+var subscriptions = make(map[topodevice.ID]*activeSubscription)
+var subscriptionSubtrees = make(map[topodevice.ID]*subtrees)
+var subscriptionsMu sync.RWMutex
+
+// sampleIntervals are the sample intervals set for the subscriptions of the devices
+var sampleIntervals = make(map[topodevice.ID][]*sampling.Interval)
+
+// ListSubscriptions lists the subscriptions to the state of the devices, with the devices whose
+// subscription has subtrees changed but is not running, sorted by device
+func ListSubscriptions() []*Subscription {
+	subscriptionsMu.RLock()
+	defer subscriptionsMu.RUnlock()
+	list := make([]*Subscription, 0, len(subscriptions))
+	for deviceID, active := range subscriptions {
+		subscription := newSubscription(deviceID)
+		subscription.Mode = active.mode
+		subscription.Paths = active.paths
+		list = append(list, subscription)
+	}
+	for deviceID := range subscriptionSubtrees {
+		if _, ok := subscriptions[deviceID]; !ok {
+			list = append(list, newSubscription(deviceID))
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].DeviceID < list[j].DeviceID
+	})
+	return list
+}
+
+// newSubscription returns the subscription of a device with the subtrees changed at runtime
+func newSubscription(deviceID topodevice.ID) *Subscription {
+	subscription := &Subscription{DeviceID: deviceID, Added: make([]string, 0), Removed: make([]string, 0)}
+	if changed, ok := subscriptionSubtrees[deviceID]; ok {
+		for subtree := range changed.added {
+			subscription.Added = append(subscription.Added, subtree)
+		}
+		for subtree := range changed.removed {
+			subscription.Removed = append(subscription.Removed, subtree)
+		}
+		sort.Strings(subscription.Added)
+		sort.Strings(subscription.Removed)
+	}
+	return subscription
+}
+
+// AddSubscribedSubtree subscribes to a subtree of the state of a device, in the model of the
+// device or not. A running subscription of the device is restarted to include it.
+func AddSubscribedSubtree(deviceID topodevice.ID, subtree string) error {
+	return changeSubtrees(deviceID, subtree, func(changed *subtrees) {
+		delete(changed.removed, subtree)
+		changed.added[subtree] = true
+	})
+}
+
+// RemoveSubscribedSubtree stops subscribing to a subtree of the state of a device, wildcards
+// allowed, which is also dropped from its operational state. The subtrees added under it are
+// removed too. A running subscription of the device is restarted without it.
+func RemoveSubscribedSubtree(deviceID topodevice.ID, subtree string) error {
+	return changeSubtrees(deviceID, subtree, func(changed *subtrees) {
+		for added := range changed.added {
+			if underSubtree(added, subtree) {
+				delete(changed.added, added)
+			}
+		}
+		changed.removed[subtree] = true
+	})
+}
+
+// ResetSubscribedSubtrees undoes the changes to the subtrees subscribed to of a device
+func ResetSubscribedSubtrees(deviceID topodevice.ID) error {
+	subscriptionsMu.Lock()
+	if _, ok := subscriptionSubtrees[deviceID]; !ok {
+		subscriptionsMu.Unlock()
+		return errors.NewNotFound("the subscription of %s has no subtrees changed", deviceID)
+	}
+	delete(subscriptionSubtrees, deviceID)
+	active := subscriptions[deviceID]
+	subscriptionsMu.Unlock()
+	if active != nil {
+		active.restart()
+	}
+	return nil
+}
+
+func changeSubtrees(deviceID topodevice.ID, subtree string, change func(changed *subtrees)) error {
+	if deviceID == "" {
+		return errors.NewInvalid("no device given")
+	}
+	if _, err := utils.ParseGNMIElements(utils.SplitPath(subtree)); err != nil || subtree == "" || subtree == "/" {
+		return errors.NewInvalid("invalid subtree '%s'", subtree)
+	}
+	subscriptionsMu.Lock()
+	changed, ok := subscriptionSubtrees[deviceID]
+	if !ok {
+		changed = &subtrees{added: make(map[string]bool), removed: make(map[string]bool)}
+		subscriptionSubtrees[deviceID] = changed
+	}
+	change(changed)
+	active := subscriptions[deviceID]
+	subscriptionsMu.Unlock()
+	if active != nil {
+		active.restart()
+	}
+	return nil
+}
+
+// subscriptionPaths returns the paths to subscribe to for the state paths of a device, and the
+// state paths dropped as they are in removed subtrees
+func subscriptionPaths(deviceID topodevice.ID, statePaths []string) ([]string, []string) {
+	subscriptionsMu.RLock()
+	defer subscriptionsMu.RUnlock()
+	changed, ok := subscriptionSubtrees[deviceID]
+	if !ok {
+		return statePaths, nil
+	}
+	paths := make([]string, 0, len(statePaths)+len(changed.added))
+	dropped := make([]string, 0)
+	for _, path := range statePaths {
+		if underAny(path, changed.added) {
+			continue
+		} else if underAny(path, changed.removed) {
+			dropped = append(dropped, path)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	for subtree := range changed.added {
+		paths = append(paths, subtree)
+	}
+	return paths, dropped
+}
+
+// registerSubscription records the running subscription of a device
+func registerSubscription(deviceID topodevice.ID, active *activeSubscription) {
+	subscriptionsMu.Lock()
+	defer subscriptionsMu.Unlock()
+	subscriptions[deviceID] = active
+}
+
+// unregisterSubscription forgets the subscription of a device, unless another one replaced it
+func unregisterSubscription(deviceID topodevice.ID, active *activeSubscription) {
+	subscriptionsMu.Lock()
+	defer subscriptionsMu.Unlock()
+	if subscriptions[deviceID] == active {
+		delete(subscriptions, deviceID)
+	}
+}
+
+// SetSampleIntervals sets the sample intervals of the subscriptions to the state of the devices,
+// replacing all of the previous ones. The running subscriptions whose intervals change are
+// restarted with them.
+func SetSampleIntervals(intervals []*sampling.Interval) {
+	byDevice := make(map[topodevice.ID][]*sampling.Interval)
+	for _, interval := range intervals {
+		deviceID := topodevice.ID(interval.DeviceID)
+		byDevice[deviceID] = append(byDevice[deviceID], interval)
+	}
+	subscriptionsMu.Lock()
+	restarts := make([]*activeSubscription, 0)
+	for deviceID, active := range subscriptions {
+		if intervalsKey(sampleIntervals[deviceID]) != intervalsKey(byDevice[deviceID]) {
+			restarts = append(restarts, active)
+		}
+	}
+	sampleIntervals = byDevice
+	subscriptionsMu.Unlock()
+	for _, active := range restarts {
+		active.restart()
+	}
+}
+
+// intervalsKey describes sample intervals, to compare them
+func intervalsKey(intervals []*sampling.Interval) string {
+	keys := make([]string, 0, len(intervals))
+	for _, interval := range intervals {
+		keys = append(keys, fmt.Sprintf("%s=%d/%d", interval.Prefix, interval.SampleInterval, interval.HeartbeatInterval))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// applySampleIntervals samples the paths of a subscription request of a device that are under a
+// subtree given a sample interval, by the interval of the longest of these subtrees. The other
+// paths are left to the device.
+func applySampleIntervals(deviceID topodevice.ID, request *gnmi.SubscribeRequest) {
+	subscriptionsMu.RLock()
+	intervals := sampleIntervals[deviceID]
+	subscriptionsMu.RUnlock()
+	if len(intervals) == 0 {
+		return
+	}
+	for _, subscription := range request.GetSubscribe().GetSubscription() {
+		path := utils.StrPath(subscription.Path)
+		var sampled *sampling.Interval
+		for _, interval := range intervals {
+			if interval.Prefix != "" && !underSubtree(path, interval.Prefix) {
+				continue
+			} else if sampled == nil || len(interval.Prefix) > len(sampled.Prefix) {
+				sampled = interval
+			}
+		}
+		if sampled != nil {
+			subscription.Mode = gnmi.SubscriptionMode_SAMPLE
+			subscription.SampleInterval = uint64(sampled.SampleInterval.Nanoseconds())
+			subscription.HeartbeatInterval = uint64(sampled.HeartbeatInterval.Nanoseconds())
+		}
+	}
+}
+
+// stopSubscription stops the subscription of a device, if running
+func stopSubscription(deviceID topodevice.ID) {
+	subscriptionsMu.Lock()
+	active, ok := subscriptions[deviceID]
+	delete(subscriptions, deviceID)
+	subscriptionsMu.Unlock()
+	if ok {
+		active.stop()
+	}
+}
+
+func underAny(path string, subtrees map[string]bool) bool {
+	for subtree := range subtrees {
+		if underSubtree(path, subtree) {
+			return true
+		}
+	}
+	return false
+}
+
+// underSubtree returns true if a path is a subtree, which may have wildcards, or is under it
+func underSubtree(path string, subtree string) bool {
+	re, err := utils.CompileWildcardRegexp(subtree, false)
+	if err != nil {
+		return false
+	}
+	match := re.FindStringIndex(path)
+	if match == nil {
+		return false
+	}
+	rest := path[match[1]:]
+	return rest == "" || rest[0] == '/' || rest[0] == '['
+}
@@ -0,0 +1,141 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"testing"
+	"time"
+
+	configmodel "github.com/onosproject/onos-config-model/pkg/model"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-config/pkg/southbound"
+	"github.com/onosproject/onos-config/pkg/store/sampling"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"gotest.tools/assert"
+)
+
+func Test_underSubtree(t *testing.T) {
+	assert.Assert(t, underSubtree("/interfaces/interface[name=eth1]/state/counters/in-octets", "/interfaces"))
+	assert.Assert(t, underSubtree("/interfaces/interface[name=eth1]/state", "/interfaces/interface"))
+	assert.Assert(t, underSubtree("/interfaces/interface[name=eth1]/state/counters", "/interfaces/interface[name=*]/state"))
+	assert.Assert(t, underSubtree("/interfaces/interface[name=eth1]/state", "/interfaces/interface[name=eth1]/state"))
+	assert.Assert(t, !underSubtree("/interfaces-state/x", "/interfaces"))
+	assert.Assert(t, !underSubtree("/interfaces/interface[name=eth1]/config", "/interfaces/interface[name=*]/state"))
+}
+
+func Test_SubscribedSubtrees(t *testing.T) {
+	const deviceID = topodevice.ID("device-subtrees")
+	defer func() { _ = ResetSubscribedSubtrees(deviceID) }()
+	statePaths := []string{
+		"/interfaces/interface[name=eth1]/state/counters/in-octets",
+		"/interfaces/interface[name=eth1]/state/oper-status",
+		"/system/state/hostname",
+	}
+	paths, dropped := subscriptionPaths(deviceID, statePaths)
+	assert.DeepEqual(t, paths, statePaths)
+	assert.Equal(t, len(dropped), 0)
+
+	restarts := 0
+	registerSubscription(deviceID, &activeSubscription{
+		mode:    configmodel.GetStateOpState,
+		paths:   statePaths,
+		restart: func() { restarts++ },
+		stop:    func() {},
+	})
+	defer stopSubscription(deviceID)
+
+	assert.NilError(t, RemoveSubscribedSubtree(deviceID, "/interfaces/interface[name=*]/state/counters"))
+	assert.NilError(t, AddSubscribedSubtree(deviceID, "/components"))
+	assert.Equal(t, restarts, 2)
+	paths, dropped = subscriptionPaths(deviceID, statePaths)
+	assert.DeepEqual(t, paths, []string{statePaths[1], statePaths[2], "/components"})
+	assert.DeepEqual(t, dropped, statePaths[:1])
+
+	// Removing a subtree removes the subtrees added under it
+	assert.NilError(t, AddSubscribedSubtree(deviceID, "/system/state"))
+	assert.NilError(t, RemoveSubscribedSubtree(deviceID, "/system"))
+	paths, _ = subscriptionPaths(deviceID, statePaths)
+	assert.DeepEqual(t, paths, []string{statePaths[1], "/components"})
+
+	var subscription *Subscription
+	for _, s := range ListSubscriptions() {
+		if s.DeviceID == deviceID {
+			subscription = s
+		}
+	}
+	assert.Assert(t, subscription != nil)
+	assert.Equal(t, subscription.Mode, configmodel.GetStateOpState)
+	assert.DeepEqual(t, subscription.Added, []string{"/components"})
+	assert.DeepEqual(t, subscription.Removed, []string{"/interfaces/interface[name=*]/state/counters", "/system"})
+
+	assert.Assert(t, errors.IsInvalid(AddSubscribedSubtree(deviceID, "/")))
+	assert.Assert(t, errors.IsInvalid(AddSubscribedSubtree("", "/components")))
+
+	assert.NilError(t, ResetSubscribedSubtrees(deviceID))
+	assert.Assert(t, errors.IsNotFound(ResetSubscribedSubtrees(deviceID)))
+	paths, _ = subscriptionPaths(deviceID, statePaths)
+	assert.DeepEqual(t, paths, statePaths)
+}
+
+func Test_SampleIntervals(t *testing.T) {
+	const deviceID = topodevice.ID("device-sampled")
+	defer SetSampleIntervals(nil)
+	restarts := 0
+	registerSubscription(deviceID, &activeSubscription{
+		restart: func() { restarts++ },
+		stop:    func() {},
+	})
+	defer stopSubscription(deviceID)
+
+	intervals := []*sampling.Interval{
+		{DeviceID: string(deviceID), SampleInterval: time.Minute},
+		{DeviceID: string(deviceID), Prefix: "/interfaces/interface[name=*]/state/counters", SampleInterval: 10 * time.Second, HeartbeatInterval: time.Minute},
+		{DeviceID: "device-other", SampleInterval: time.Second},
+	}
+	SetSampleIntervals(intervals)
+	assert.Equal(t, restarts, 1)
+	SetSampleIntervals(intervals)
+	assert.Equal(t, restarts, 1, "the intervals of the device did not change")
+
+	request, err := southbound.NewSubscribeRequest(&southbound.SubscribeOptions{
+		Mode:       "stream",
+		StreamMode: "target_defined",
+		Paths: [][]string{
+			utils.SplitPath("/interfaces/interface[name=eth1]/state/counters/in-octets"),
+			utils.SplitPath("/system/state/hostname"),
+		},
+	})
+	assert.NilError(t, err)
+	applySampleIntervals(deviceID, request)
+	subscriptions := request.GetSubscribe().Subscription
+	assert.Equal(t, subscriptions[0].Mode, gnmi.SubscriptionMode_SAMPLE)
+	assert.Equal(t, subscriptions[0].SampleInterval, uint64(10*time.Second))
+	assert.Equal(t, subscriptions[0].HeartbeatInterval, uint64(time.Minute))
+	assert.Equal(t, subscriptions[1].Mode, gnmi.SubscriptionMode_SAMPLE)
+	assert.Equal(t, subscriptions[1].SampleInterval, uint64(time.Minute))
+
+	SetSampleIntervals(intervals[2:])
+	assert.Equal(t, restarts, 2)
+	request, err = southbound.NewSubscribeRequest(&southbound.SubscribeOptions{
+		Mode:       "stream",
+		StreamMode: "target_defined",
+		Paths:      [][]string{utils.SplitPath("/system/state/hostname")},
+	})
+	assert.NilError(t, err)
+	applySampleIntervals(deviceID, request)
+	assert.Equal(t, request.GetSubscribe().Subscription[0].Mode, gnmi.SubscriptionMode_TARGET_DEFINED)
+}
@@ -21,6 +21,7 @@ import (
 	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
 	configmodel "github.com/onosproject/onos-config-model/pkg/model"
 	"regexp"
+	"sort"
 	"strings"
 	syncPrimitives "sync"
 
@@ -58,6 +59,7 @@ type Synchronizer struct {
 	encoding             gnmi.Encoding
 	getStateMode         configmodel.GetStateMode
 	target               southbound.TargetIf
+	capabilities         *gnmi.CapabilityResponse
 }
 
 // New builds a new Synchronizer given the parameters, starts the connection with the device and polls the capabilities
@@ -95,6 +97,7 @@ func New(context context.Context,
 			string(device.ID), capErr)
 		return nil, capErr
 	}
+	sync.capabilities = capResponse
 	sync.encoding = gnmi.Encoding_PROTO // Default
 	if capResponse != nil {
 		for _, enc := range capResponse.SupportedEncodings {
@@ -369,15 +372,31 @@ func (sync Synchronizer) getValuesFromJSON(update *gnmi.Update) ([]*devicechange
  *	with Get (of state - which ever method was successful).
  *  This can be found from the OpStateCache
  *  At this stage the wildcards will have been expanded and the ReadOnly paths traversed
+ *  The subtrees added to or removed from the subscription of the device at runtime are applied
+ *  to these paths, and the paths given sample intervals are sampled; a change to either restarts
+ *  the subscription.
  */
 func (sync *Synchronizer) subscribeOpState(errChan chan<- events.DeviceResponse) {
-	subscribePaths := make([][]string, 0)
+	statePaths := make([]string, 0)
 	sync.operationalCacheLock.RLock()
 	for p := range sync.operationalCache {
-		subscribePaths = append(subscribePaths, utils.SplitPath(p))
+		statePaths = append(statePaths, p)
 	}
 	sync.operationalCacheLock.RUnlock()
 
+	paths, dropped := subscriptionPaths(sync.Device.ID, statePaths)
+	if len(dropped) > 0 {
+		sync.operationalCacheLock.Lock()
+		for _, p := range dropped {
+			delete(sync.operationalCache, p)
+		}
+		sync.operationalCacheLock.Unlock()
+	}
+	subscribePaths := make([][]string, 0, len(paths))
+	for _, p := range paths {
+		subscribePaths = append(subscribePaths, utils.SplitPath(p))
+	}
+
 	options := &southbound.SubscribeOptions{
 		UpdatesOnly:       false,
 		Prefix:            "",
@@ -389,6 +408,20 @@ func (sync *Synchronizer) subscribeOpState(errChan chan<- events.DeviceResponse)
 		Origin:            "",
 	}
 
+	subscriptionContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sort.Strings(paths)
+	active := &activeSubscription{
+		mode:  sync.getStateMode,
+		paths: paths,
+		restart: func() {
+			cancel()
+			go sync.subscribeOpState(errChan)
+		},
+		stop: cancel,
+	}
+	registerSubscription(sync.Device.ID, active)
+
 	if len(subscribePaths) == 0 {
 		log.Info("No operational state path found for subscription")
 		return
@@ -397,13 +430,18 @@ func (sync *Synchronizer) subscribeOpState(errChan chan<- events.DeviceResponse)
 	log.Infof("Subscribing to %d paths. %s", len(subscribePaths), string(sync.key))
 	req, err := southbound.NewSubscribeRequest(options)
 	if err != nil {
+		unregisterSubscription(sync.Device.ID, active)
 		errChan <- events.NewErrorEventNoChangeID(events.EventTypeErrorParseConfig,
 			string(sync.key), err)
 		return
 	}
-	subscriptionContext, cancel := context.WithCancel(context.Background())
+	applySampleIntervals(sync.Device.ID, req)
 	subErr := sync.target.Subscribe(subscriptionContext, req, sync.opStateSubHandler) // Blocks here until error in handler
-	cancel()
+	if subscriptionContext.Err() != nil {
+		// Restarted with other subtrees, or stopped as the device is disconnected
+		return
+	}
+	unregisterSubscription(sync.Device.ID, active)
 	if subErr != nil {
 		log.Warn("Error in subscribe ", subErr)
 		stat, ok := status.FromError(subErr)
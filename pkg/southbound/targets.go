@@ -0,0 +1,144 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"sort"
+	"time"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// TargetInfo describes an entry of the targets cache
+type TargetInfo struct {
+	ID         devicetype.VersionedID
+	DeviceType devicetype.Type
+	// Address is the address the target is connected to
+	Address string
+	// Multiplexed is true if the target shares the connection to its device with other targets
+	Multiplexed bool
+	// Connected is when the target was connected, zero if it never was
+	Connected time.Time
+	// LastUsed is when the device was last sent a request through the target, zero if never
+	LastUsed time.Time
+	// LastError is the error of the last request sent through the target, empty if it succeeded
+	LastError string
+	// InFlight are the requests issued to the device that have not completed, and Queued those
+	// waiting for them, through any target of the device
+	InFlight int
+	Queued   int
+}
+
+// used records the outcome of a request sent through the target, reporting the connection lost
+// if the device could not be reached
+func (target *Target) used(err error) {
+	target.mu.Lock()
+	target.lastUsed = time.Now()
+	if err != nil {
+		target.lastError = err.Error()
+	} else {
+		target.lastError = ""
+	}
+	target.mu.Unlock()
+	if isConnectionError(err) {
+		target.connectionLost(err)
+	}
+}
+
+// connectionLost reports that the connection of the target dropped
+func (target *Target) connectionLost(err error) {
+	target.mu.RLock()
+	key := devicetype.NewVersionedID(target.deviceID, target.deviceVersion)
+	target.mu.RUnlock()
+	ConnectionLost(key, err)
+}
+
+// info describes the target
+func (target *Target) info(key devicetype.VersionedID) TargetInfo {
+	target.mu.RLock()
+	defer target.mu.RUnlock()
+	info := TargetInfo{
+		ID:          key,
+		DeviceType:  target.deviceType,
+		Multiplexed: target.multiplexed,
+		Connected:   target.connected,
+		LastUsed:    target.lastUsed,
+		LastError:   target.lastError,
+	}
+	if len(target.dest.Addrs) > 0 {
+		info.Address = target.dest.Addrs[0]
+	}
+	info.InFlight, info.Queued = requests.stats(target.deviceID)
+	return info
+}
+
+// ListTargets lists the entries of the targets cache, sorted by device ID and version
+func ListTargets() []TargetInfo {
+	targetMu.RLock()
+	infos := make([]TargetInfo, 0, len(targets))
+	for key, t := range targets {
+		if target, ok := t.(*Target); ok {
+			infos = append(infos, target.info(key))
+		} else {
+			infos = append(infos, TargetInfo{ID: key})
+		}
+	}
+	targetMu.RUnlock()
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
+// DisconnectTarget closes the connection of a target and removes it from the targets cache. It
+// returns the description of the target it disconnected.
+func DisconnectTarget(key devicetype.VersionedID) (TargetInfo, error) {
+	targetMu.Lock()
+	t, ok := targets[key]
+	if ok {
+		delete(targets, key)
+	}
+	targetMu.Unlock()
+	if !ok {
+		return TargetInfo{}, errors.NewNotFound("no target for %s", key)
+	}
+	info := TargetInfo{ID: key}
+	if target, ok := t.(*Target); ok {
+		info = target.info(key)
+	}
+	log.Infof("Disconnecting target %s", key)
+	if err := t.Close(); err != nil {
+		log.Warnf("Error closing the connection of target %s: %v", key, err)
+	}
+	return info, nil
+}
+
+// ReleaseTarget closes the connection of a target whose session ended, and removes it from the
+// targets cache, unless the cache holds another target for the device by now
+func ReleaseTarget(key devicetype.VersionedID, target TargetIf) {
+	targetMu.Lock()
+	t, ok := targets[key]
+	if !ok || t != target {
+		targetMu.Unlock()
+		return
+	}
+	delete(targets, key)
+	targetMu.Unlock()
+	log.Infof("Releasing target %s", key)
+	if err := target.Close(); err != nil {
+		log.Warnf("Error closing the connection of target %s: %v", key, err)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"context"
+	"testing"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ListTargets(t *testing.T) {
+	setUp(t)
+	defer tearDown()
+
+	target, key, _ := getDevice1Target(t)
+	_, err := target.Get(context.TODO(), &gnmi.GetRequest{})
+	assert.NoError(t, err)
+
+	infos := ListTargets()
+	assert.Len(t, infos, 4)
+	assert.Equal(t, devicetype.NewVersionedID("dummyDevice1", "1.0.0"), infos[0].ID)
+	assert.Equal(t, devicetype.NewVersionedID("dummyDevice1", "2.0.0"), infos[1].ID)
+	assert.Equal(t, devicetype.NewVersionedID("dummyDevice2", "1.0.0"), infos[2].ID)
+	assert.True(t, infos[2].Connected.IsZero())
+
+	info := infos[3]
+	assert.Equal(t, key, info.ID)
+	assert.Equal(t, "localhost:10161", info.Address)
+	assert.False(t, info.Multiplexed)
+	assert.False(t, info.Connected.IsZero())
+	assert.False(t, info.LastUsed.Before(info.Connected))
+	assert.Empty(t, info.LastError)
+}
+
+func Test_DisconnectTarget(t *testing.T) {
+	setUp(t)
+	defer tearDown()
+
+	_, key, _ := getDevice1Target(t)
+	info, err := DisconnectTarget(key)
+	assert.NoError(t, err)
+	assert.Equal(t, key, info.ID)
+	_, err = GetTarget(key)
+	assert.Error(t, err)
+
+	_, err = DisconnectTarget(key)
+	assert.True(t, errors.IsNotFound(err))
+
+	// Targets that never connected are disconnected as well
+	_, err = DisconnectTarget(devicetype.NewVersionedID("dummyDevice2", "1.0.0"))
+	assert.NoError(t, err)
+	assert.Len(t, ListTargets(), 2)
+}
+
+func Test_ReleaseTarget(t *testing.T) {
+	setUp(t)
+	defer tearDown()
+
+	old, key, _ := getDevice1Target(t)
+	current, _, _ := getDevice1Target(t)
+
+	// A session releasing a target that has been replaced leaves the replacement alone
+	ReleaseTarget(key, old)
+	fetched, err := GetTarget(key)
+	assert.NoError(t, err)
+	assert.Equal(t, current, fetched)
+
+	ReleaseTarget(key, current)
+	_, err = GetTarget(key)
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/onosproject/onos-config/pkg/southbound/features"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// translateSetRequest returns a Set request as the devices with the given features apply it: the
+// replaces are sent as deletes of their paths followed by updates to devices not applying them
+func translateSetRequest(request *gpb.SetRequest, typeFeatures features.Features) *gpb.SetRequest {
+	if typeFeatures.Replace || len(request.GetReplace()) == 0 {
+		return request
+	}
+	translated := proto.Clone(request).(*gpb.SetRequest)
+	// The replaces are applied after the deletes and before the updates of the request
+	updates := make([]*gpb.Update, 0, len(translated.Replace)+len(translated.Update))
+	for _, replace := range translated.Replace {
+		translated.Delete = append(translated.Delete, replace.Path)
+		updates = append(updates, replace)
+	}
+	translated.Update = append(updates, translated.Update...)
+	translated.Replace = nil
+	return translated
+}
+
+// translateGetRequest returns a Get request as the devices with the given features answer it:
+// JSON is requested from devices not encoding values as JSON_IETF
+func translateGetRequest(request *gpb.GetRequest, typeFeatures features.Features) *gpb.GetRequest {
+	if typeFeatures.JSONIETF || request.GetEncoding() != gpb.Encoding_JSON_IETF {
+		return request
+	}
+	translated := proto.Clone(request).(*gpb.GetRequest)
+	translated.Encoding = gpb.Encoding_JSON
+	return translated
+}
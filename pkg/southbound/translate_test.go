@@ -0,0 +1,61 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package southbound
+
+import (
+	"testing"
+
+	"github.com/onosproject/onos-config/pkg/southbound/features"
+	"github.com/onosproject/onos-config/pkg/utils"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_translateSetRequest(t *testing.T) {
+	path := func(p string) *gpb.Path {
+		gnmiPath, err := utils.ParseGNMIElements(utils.SplitPath(p))
+		assert.NoError(t, err)
+		return gnmiPath
+	}
+	value := &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "value"}}
+	request := &gpb.SetRequest{
+		Delete:  []*gpb.Path{path("/a/b")},
+		Replace: []*gpb.Update{{Path: path("/a/c"), Val: value}},
+		Update:  []*gpb.Update{{Path: path("/a/c/d"), Val: value}},
+	}
+
+	assert.Equal(t, request, translateSetRequest(request, features.Features{Replace: true}))
+
+	translated := translateSetRequest(request, features.Features{})
+	assert.Empty(t, translated.Replace)
+	assert.Len(t, translated.Delete, 2)
+	assert.Equal(t, "/a/c", utils.StrPath(translated.Delete[1]))
+	assert.Len(t, translated.Update, 2)
+	assert.Equal(t, "/a/c", utils.StrPath(translated.Update[0].Path))
+	assert.Equal(t, "/a/c/d", utils.StrPath(translated.Update[1].Path))
+	// The request is not changed
+	assert.Len(t, request.Replace, 1)
+	assert.Len(t, request.Delete, 1)
+}
+
+func Test_translateGetRequest(t *testing.T) {
+	request := &gpb.GetRequest{Encoding: gpb.Encoding_JSON_IETF}
+	assert.Equal(t, gpb.Encoding_JSON_IETF, translateGetRequest(request, features.Features{JSONIETF: true}).Encoding)
+	assert.Equal(t, gpb.Encoding_JSON, translateGetRequest(request, features.Features{}).Encoding)
+	assert.Equal(t, gpb.Encoding_JSON_IETF, request.Encoding)
+
+	request = &gpb.GetRequest{Encoding: gpb.Encoding_PROTO}
+	assert.Equal(t, gpb.Encoding_PROTO, translateGetRequest(request, features.Features{}).Encoding)
+}
@@ -0,0 +1,163 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package annotation stores the free-form notes operators attach to devices and network changes,
+// e.g. to coordinate who may touch a device and when.
+package annotation
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	"github.com/google/uuid"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Kind is the kind of object an annotation is attached to
+type Kind string
+
+const (
+	// Device is an annotation of a device
+	Device Kind = "device"
+	// NetworkChange is an annotation of a network change
+	NetworkChange Kind = "network-change"
+)
+
+// Annotation is a note attached to a device or a network change
+type Annotation struct {
+	// ID identifies the annotation
+	ID string `json:"id"`
+	// Kind is the kind of the annotated object
+	Kind Kind `json:"kind"`
+	// Target is the ID of the annotated device or network change
+	Target string `json:"target"`
+	// Note is the text of the annotation
+	Note string `json:"note"`
+	// Author is the user who wrote the annotation
+	Author string `json:"author"`
+	// Created is when the annotation was written
+	Created time.Time `json:"created"`
+}
+
+// Store stores the annotations
+type Store interface {
+	io.Closer
+
+	// Add adds an annotation, assigning it a new ID
+	Add(annotation *Annotation) error
+
+	// Get gets an annotation
+	Get(id string) (*Annotation, error)
+
+	// Delete deletes an annotation
+	Delete(id string) error
+
+	// List lists the annotations of an object, sorted by time created. All the annotations of
+	// the kind are listed if target is empty, and all the annotations if kind is empty too.
+	List(kind Kind, target string) ([]*Annotation, error)
+}
+
+// kind describes the annotations in the errors of the store
+const kind = "annotation"
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	annotations, err := records.NewAtomixMap(client, "onos-config-annotations", kind)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		annotations: annotations,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps annotations in memory
+func NewLocalStore() Store {
+	return &store{
+		annotations: records.NewLocalMap(kind),
+	}
+}
+
+// store keeps the annotations by ID
+type store struct {
+	annotations records.Map
+}
+
+func (s *store) Add(annotation *Annotation) error {
+	if err := checkAnnotation(annotation); err != nil {
+		return err
+	}
+	annotation.ID = uuid.New().String()
+	return s.annotations.Create(annotation.ID, annotation)
+}
+
+func (s *store) Get(id string) (*Annotation, error) {
+	annotation := &Annotation{}
+	if err := s.annotations.Get(id, annotation); err != nil {
+		return nil, err
+	}
+	return annotation, nil
+}
+
+func (s *store) Delete(id string) error {
+	return s.annotations.Delete(id)
+}
+
+func (s *store) List(kind Kind, target string) ([]*Annotation, error) {
+	list, err := s.annotations.List(func() interface{} { return &Annotation{} })
+	if err != nil {
+		return nil, err
+	}
+	annotations := make([]*Annotation, 0, len(list))
+	for _, record := range list {
+		if annotation := record.(*Annotation); annotation.matches(kind, target) {
+			annotations = append(annotations, annotation)
+		}
+	}
+	sortAnnotations(annotations)
+	return annotations, nil
+}
+
+func (s *store) Close() error {
+	return s.annotations.Close()
+}
+
+func (a *Annotation) matches(kind Kind, target string) bool {
+	return (kind == "" || a.Kind == kind) && (target == "" || a.Target == target)
+}
+
+func checkAnnotation(annotation *Annotation) error {
+	if annotation.Kind != Device && annotation.Kind != NetworkChange {
+		return errors.NewInvalid("unknown annotation kind '%s'", annotation.Kind)
+	}
+	if annotation.Target == "" {
+		return errors.NewInvalid("no %s given", annotation.Kind)
+	}
+	if annotation.Note == "" {
+		return errors.NewInvalid("no note given")
+	}
+	return nil
+}
+
+func sortAnnotations(annotations []*Annotation) {
+	sort.Slice(annotations, func(i, j int) bool {
+		if !annotations[i].Created.Equal(annotations[j].Created) {
+			return annotations[i].Created.Before(annotations[j].Created)
+		}
+		return annotations[i].ID < annotations[j].ID
+	})
+}
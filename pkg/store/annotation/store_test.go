@@ -0,0 +1,90 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	now := time.Now()
+	friday := &Annotation{
+		Kind:    Device,
+		Target:  "leaf-1",
+		Note:    "do not touch until Friday",
+		Author:  "alice",
+		Created: now,
+	}
+	assert.NoError(t, store.Add(friday))
+	assert.NotEmpty(t, friday.ID)
+	assert.NoError(t, store.Add(&Annotation{Kind: Device, Target: "leaf-1", Note: "RMA pending", Author: "bob", Created: now.Add(time.Minute)}))
+	assert.NoError(t, store.Add(&Annotation{Kind: NetworkChange, Target: "change-3", Note: "for ticket 42", Author: "bob", Created: now}))
+	assert.True(t, errors.IsInvalid(store.Add(&Annotation{Kind: "link", Target: "link-1", Note: "flapping"})))
+	assert.True(t, errors.IsInvalid(store.Add(&Annotation{Kind: Device, Note: "flapping"})))
+	assert.True(t, errors.IsInvalid(store.Add(&Annotation{Kind: Device, Target: "leaf-1"})))
+
+	annotations, err := store.List(Device, "leaf-1")
+	assert.NoError(t, err)
+	assert.Len(t, annotations, 2)
+	assert.Equal(t, "do not touch until Friday", annotations[0].Note)
+	assert.Equal(t, "RMA pending", annotations[1].Note)
+	annotations, err = store.List(NetworkChange, "")
+	assert.NoError(t, err)
+	assert.Len(t, annotations, 1)
+	assert.Equal(t, "change-3", annotations[0].Target)
+	annotations, err = store.List("", "")
+	assert.NoError(t, err)
+	assert.Len(t, annotations, 3)
+
+	annotation, err := store.Get(friday.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", annotation.Author)
+
+	assert.NoError(t, store.Delete(friday.ID))
+	_, err = store.Get(friday.ID)
+	assert.True(t, errors.IsNotFound(err))
+	assert.True(t, errors.IsNotFound(store.Delete(friday.ID)))
+	annotations, err = store.List(Device, "leaf-1")
+	assert.NoError(t, err)
+	assert.Len(t, annotations, 1)
+
+	// Each annotation added gets its own ID, even with the same note
+	again := &Annotation{Kind: Device, Target: "leaf-1", Note: "RMA pending", Author: "bob", Created: now}
+	assert.NoError(t, store.Add(again))
+	assert.NotEqual(t, friday.ID, again.ID)
+	annotations, err = store.List(Device, "leaf-1")
+	assert.NoError(t, err)
+	assert.Len(t, annotations, 2)
+	annotations, err = store.List(Device, "leaf-2")
+	assert.NoError(t, err)
+	assert.Len(t, annotations, 0)
+
+	// The annotations returned are copies
+	annotation, err = store.Get(again.ID)
+	assert.NoError(t, err)
+	annotation.Note = "changed"
+	annotation, err = store.Get(again.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "RMA pending", annotation.Note)
+
+	assert.EqualError(t, store.Delete(friday.ID), "annotation '"+friday.ID+"' not found")
+}
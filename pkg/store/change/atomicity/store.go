@@ -0,0 +1,139 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package atomicity stores the atomicity mode of the network changes that are not all-or-nothing,
+// i.e. whose device changes are not rolled back once one of their devices rejects its change.
+package atomicity
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Mode is how a network change spanning several devices behaves when one of them rejects its change
+type Mode string
+
+const (
+	// ModeAllOrNothing rolls the change back on the devices that applied it once a device rejects
+	// it, and retries the change as a whole; the mode of the changes for which none is stored
+	ModeAllOrNothing Mode = "all-or-nothing"
+	// ModeBestEffort keeps the change on the devices that applied it once a device rejects it, and
+	// fails the network change; the rejected device changes may be retried on their own
+	ModeBestEffort Mode = "best-effort"
+)
+
+// Modes are the atomicity modes
+var Modes = []Mode{ModeAllOrNothing, ModeBestEffort}
+
+// ParseMode parses an atomicity mode, the empty string being all-or-nothing
+func ParseMode(mode string) (Mode, error) {
+	if mode == "" {
+		return ModeAllOrNothing, nil
+	}
+	for _, known := range Modes {
+		if Mode(mode) == known {
+			return known, nil
+		}
+	}
+	names := make([]string, 0, len(Modes))
+	for _, known := range Modes {
+		names = append(names, string(known))
+	}
+	return "", fmt.Errorf("unknown atomicity mode '%s', must be one of %s", mode, strings.Join(names, ", "))
+}
+
+// Store stores the atomicity modes of the network changes
+type Store interface {
+	io.Closer
+
+	// Get gets the atomicity mode of a network change, all-or-nothing if none is stored for it
+	Get(id networkchange.ID) (Mode, error)
+
+	// Create stores the atomicity mode of a network change, before the change is created. It fails
+	// with AlreadyExists if a mode is already stored for the change.
+	Create(id networkchange.ID, mode Mode) error
+
+	// Delete deletes the atomicity mode of a network change that could not be created
+	Delete(id networkchange.ID) error
+}
+
+// kind describes the modes in the errors of the store
+const kind = "atomicity mode"
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	modes, err := records.NewAtomixMap(client, "onos-config-change-atomicity", kind)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		modes: modes,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps the modes in memory
+func NewLocalStore() Store {
+	return &store{
+		modes: records.NewLocalMap(kind),
+	}
+}
+
+// store keeps the modes by network change ID
+type store struct {
+	modes records.Map
+}
+
+func (s *store) Get(id networkchange.ID) (Mode, error) {
+	var mode Mode
+	if err := s.modes.Get(string(id), &mode); err != nil {
+		if errors.IsNotFound(err) {
+			return ModeAllOrNothing, nil
+		}
+		return "", err
+	}
+	return mode, nil
+}
+
+func (s *store) Create(id networkchange.ID, mode Mode) error {
+	if id == "" {
+		return errors.NewInvalid("no change ID specified")
+	}
+	if _, err := ParseMode(string(mode)); err != nil {
+		return errors.NewInvalid("%v", err)
+	}
+	if err := s.modes.Create(string(id), mode); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return errors.NewAlreadyExists("change '%s' already has an atomicity mode", id)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *store) Delete(id networkchange.ID) error {
+	if err := s.modes.Delete(string(id)); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *store) Close() error {
+	return s.modes.Close()
+}
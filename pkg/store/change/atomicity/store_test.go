@@ -0,0 +1,66 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomicity
+
+import (
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMode(t *testing.T) {
+	mode, err := ParseMode("")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeAllOrNothing, mode)
+	mode, err = ParseMode("best-effort")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeBestEffort, mode)
+	_, err = ParseMode("atomic")
+	assert.EqualError(t, err, "unknown atomicity mode 'atomic', must be one of all-or-nothing, best-effort")
+}
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	// The changes with no stored mode are all-or-nothing
+	mode, err := store.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeAllOrNothing, mode)
+
+	assert.NoError(t, store.Create("change-1", ModeBestEffort))
+	mode, err = store.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeBestEffort, mode)
+
+	assert.True(t, errors.IsInvalid(store.Create("change-2", "atomic")))
+	assert.True(t, errors.IsInvalid(store.Create("", ModeBestEffort)))
+
+	// The mode of a change is only stored once
+	err = store.Create("change-1", ModeAllOrNothing)
+	assert.True(t, errors.IsAlreadyExists(err))
+	assert.EqualError(t, err, "change 'change-1' already has an atomicity mode")
+	mode, err = store.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeBestEffort, mode)
+
+	assert.NoError(t, store.Delete("change-1"))
+	mode, err = store.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeAllOrNothing, mode)
+	assert.NoError(t, store.Delete("change-1"))
+	assert.NoError(t, store.Create("change-1", ModeAllOrNothing))
+}
@@ -0,0 +1,137 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package confirmation stores the confirmations awaited by the network changes of commit-confirmed
+// Sets. A network change that is not confirmed before its deadline is rolled back.
+package confirmation
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	"github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Confirmation is the confirmation awaited by a network change
+type Confirmation struct {
+	// NetworkChangeID is the network change to confirm
+	NetworkChangeID network.ID `json:"networkChangeId"`
+	// User is the caller who made the network change
+	User string `json:"user"`
+	// Timeout is how long the network change is given to be confirmed
+	Timeout time.Duration `json:"timeout"`
+	// Deadline is when the network change is rolled back unless it is confirmed
+	Deadline time.Time `json:"deadline"`
+}
+
+// Store stores the awaited confirmations
+type Store interface {
+	io.Closer
+
+	// Get gets the confirmation awaited by a network change
+	Get(id network.ID) (*Confirmation, error)
+
+	// Create awaits the confirmation of a network change. It fails with AlreadyExists if the
+	// change already awaits one.
+	Create(confirmation *Confirmation) error
+
+	// Delete stops awaiting the confirmation of a network change
+	Delete(id network.ID) error
+
+	// List lists the awaited confirmations, sorted by deadline and network change ID
+	List() ([]*Confirmation, error)
+}
+
+// kind and notFound describe the confirmations in the errors of the store
+const kind = "confirmation"
+
+var notFound = records.WithNotFound("network change '%s' awaits no confirmation")
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	confirmations, err := records.NewAtomixMap(client, "onos-config-change-confirmations", kind, notFound)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		confirmations: confirmations,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps confirmations in memory
+func NewLocalStore() Store {
+	return &store{
+		confirmations: records.NewLocalMap(kind, notFound),
+	}
+}
+
+// store keeps the confirmations by network change ID
+type store struct {
+	confirmations records.Map
+}
+
+func (s *store) Get(id network.ID) (*Confirmation, error) {
+	confirmation := &Confirmation{}
+	if err := s.confirmations.Get(string(id), confirmation); err != nil {
+		return nil, err
+	}
+	return confirmation, nil
+}
+
+func (s *store) Create(confirmation *Confirmation) error {
+	if confirmation.NetworkChangeID == "" {
+		return errors.NewInvalid("no network change ID given")
+	}
+	if err := s.confirmations.Create(string(confirmation.NetworkChangeID), confirmation); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return errors.NewAlreadyExists("network change '%s' already awaits a confirmation", confirmation.NetworkChangeID)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *store) Delete(id network.ID) error {
+	return s.confirmations.Delete(string(id))
+}
+
+func (s *store) List() ([]*Confirmation, error) {
+	list, err := s.confirmations.List(func() interface{} { return &Confirmation{} })
+	if err != nil {
+		return nil, err
+	}
+	confirmations := make([]*Confirmation, 0, len(list))
+	for _, record := range list {
+		confirmations = append(confirmations, record.(*Confirmation))
+	}
+	sortConfirmations(confirmations)
+	return confirmations, nil
+}
+
+func (s *store) Close() error {
+	return s.confirmations.Close()
+}
+
+func sortConfirmations(confirmations []*Confirmation) {
+	sort.Slice(confirmations, func(i, j int) bool {
+		if !confirmations[i].Deadline.Equal(confirmations[j].Deadline) {
+			return confirmations[i].Deadline.Before(confirmations[j].Deadline)
+		}
+		return confirmations[i].NetworkChangeID < confirmations[j].NetworkChangeID
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confirmation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	now := time.Now()
+	assert.NoError(t, store.Create(&Confirmation{
+		NetworkChangeID: "change-1",
+		User:            "alice",
+		Timeout:         10 * time.Minute,
+		Deadline:        now.Add(10 * time.Minute),
+	}))
+	assert.NoError(t, store.Create(&Confirmation{NetworkChangeID: "change-2", User: "bob", Timeout: time.Minute, Deadline: now.Add(time.Minute)}))
+	assert.True(t, errors.IsInvalid(store.Create(&Confirmation{})))
+
+	// The earliest deadline is listed first
+	confirmations, err := store.List()
+	assert.NoError(t, err)
+	assert.Len(t, confirmations, 2)
+	assert.Equal(t, "change-2", string(confirmations[0].NetworkChangeID))
+	assert.Equal(t, "change-1", string(confirmations[1].NetworkChangeID))
+
+	confirmation, err := store.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", confirmation.User)
+	assert.Equal(t, 10*time.Minute, confirmation.Timeout)
+	assert.True(t, now.Add(10*time.Minute).Equal(confirmation.Deadline))
+
+	assert.NoError(t, store.Delete("change-1"))
+	_, err = store.Get("change-1")
+	assert.True(t, errors.IsNotFound(err))
+	assert.True(t, errors.IsNotFound(store.Delete("change-1")))
+
+	// A change awaits only one confirmation, whose deadline is never pushed back
+	err = store.Create(&Confirmation{NetworkChangeID: "change-2", User: "bob", Timeout: 20 * time.Minute, Deadline: now.Add(20 * time.Minute)})
+	assert.True(t, errors.IsAlreadyExists(err))
+	assert.EqualError(t, err, "network change 'change-2' already awaits a confirmation")
+	confirmation, err = store.Get("change-2")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, confirmation.Timeout)
+
+	// The confirmations returned are copies
+	confirmation.User = "carol"
+	confirmation, err = store.Get("change-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", confirmation.User)
+
+	assert.EqualError(t, store.Delete("change-1"), "network change 'change-1' awaits no confirmation")
+}
@@ -0,0 +1,125 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"regexp"
+	"sort"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+)
+
+// SearchResult is a device path whose current value matched a search
+type SearchResult struct {
+	DeviceID devicetype.VersionedID
+	Path     string
+	Value    *devicechange.TypedValue
+}
+
+// valueIndex maps the string form of each value in the state store to the
+// device paths currently holding it
+type valueIndex struct {
+	values map[string]map[devicetype.VersionedID]map[string]*devicechange.TypedValue
+}
+
+func newValueIndex() *valueIndex {
+	return &valueIndex{
+		values: make(map[string]map[devicetype.VersionedID]map[string]*devicechange.TypedValue),
+	}
+}
+
+func (i *valueIndex) add(deviceID devicetype.VersionedID, path string, value *devicechange.TypedValue) {
+	if value == nil {
+		return
+	}
+	key := value.ValueToString()
+	devices, ok := i.values[key]
+	if !ok {
+		devices = make(map[devicetype.VersionedID]map[string]*devicechange.TypedValue)
+		i.values[key] = devices
+	}
+	paths, ok := devices[deviceID]
+	if !ok {
+		paths = make(map[string]*devicechange.TypedValue)
+		devices[deviceID] = paths
+	}
+	paths[path] = value
+}
+
+func (i *valueIndex) remove(deviceID devicetype.VersionedID, path string, value *devicechange.TypedValue) {
+	if value == nil {
+		return
+	}
+	key := value.ValueToString()
+	devices, ok := i.values[key]
+	if !ok {
+		return
+	}
+	paths, ok := devices[deviceID]
+	if !ok {
+		return
+	}
+	delete(paths, path)
+	if len(paths) == 0 {
+		delete(devices, deviceID)
+	}
+	if len(devices) == 0 {
+		delete(i.values, key)
+	}
+}
+
+// lookup returns the paths holding exactly the given value
+func (i *valueIndex) lookup(value string) []*SearchResult {
+	results := make([]*SearchResult, 0)
+	results = i.appendResults(results, i.values[value])
+	sortResults(results)
+	return results
+}
+
+// match returns the paths holding a value matched by the given expression. Only the
+// distinct values are matched, rather than every path of every device.
+func (i *valueIndex) match(re *regexp.Regexp) []*SearchResult {
+	results := make([]*SearchResult, 0)
+	for value, devices := range i.values {
+		if re.MatchString(value) {
+			results = i.appendResults(results, devices)
+		}
+	}
+	sortResults(results)
+	return results
+}
+
+func (i *valueIndex) appendResults(results []*SearchResult, devices map[devicetype.VersionedID]map[string]*devicechange.TypedValue) []*SearchResult {
+	for deviceID, paths := range devices {
+		for path, value := range paths {
+			results = append(results, &SearchResult{
+				DeviceID: deviceID,
+				Path:     path,
+				Value:    value,
+			})
+		}
+	}
+	return results
+}
+
+func sortResults(results []*SearchResult) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].DeviceID != results[j].DeviceID {
+			return results[i].DeviceID < results[j].DeviceID
+		}
+		return results[i].Path < results[j].Path
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestState(id devicetype.VersionedID, store *deviceChangeStoreStateStore) *deviceChangeStateStore {
+	state := &deviceChangeStateStore{
+		deviceID: id,
+		state:    make(map[string]*devicechange.TypedValue),
+		index:    store.index,
+	}
+	store.devices[id] = state
+	return state
+}
+
+func TestDeviceStateStore_Search(t *testing.T) {
+	store := &deviceChangeStoreStateStore{
+		devices: make(map[devicetype.VersionedID]*deviceChangeStateStore),
+		index:   newValueIndex(),
+	}
+	device1 := devicetype.NewVersionedID("device-1", "1.0.0")
+	device2 := devicetype.NewVersionedID("device-2", "1.0.0")
+
+	state1 := newTestState(device1, store)
+	state1.update(&devicechange.PathValue{Path: "/bgp/global/config/as", Value: devicechange.NewTypedValueUint(65001, 32)})
+	state1.update(&devicechange.PathValue{Path: "/bgp/neighbors/neighbor[address=10.0.0.2]/config/peer-as", Value: devicechange.NewTypedValueUint(65002, 32)})
+	state2 := newTestState(device2, store)
+	state2.update(&devicechange.PathValue{Path: "/bgp/neighbors/neighbor[address=10.0.0.1]/config/peer-as", Value: devicechange.NewTypedValueUint(65001, 32)})
+	state2.update(&devicechange.PathValue{Path: "/system/config/hostname", Value: devicechange.NewTypedValueString("edge-2")})
+
+	results, err := store.Search("65001", false)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, device1, results[0].DeviceID)
+	assert.Equal(t, "/bgp/global/config/as", results[0].Path)
+	assert.Equal(t, device2, results[1].DeviceID)
+
+	results, err = store.Search("^6500[12]$", true)
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+
+	// Updating a value moves it in the index
+	state1.update(&devicechange.PathValue{Path: "/bgp/global/config/as", Value: devicechange.NewTypedValueUint(65003, 32)})
+	results, err = store.Search("65001", false)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, device2, results[0].DeviceID)
+
+	// Removing a subtree removes all of its values from the index
+	state2.remove("/bgp")
+	results, err = store.Search("65001", false)
+	assert.NoError(t, err)
+	assert.Len(t, results, 0)
+
+	// Replacing a device state, as on a rollback, reindexes it
+	rebuilt := &deviceChangeStateStore{
+		deviceID: device2,
+		state: map[string]*devicechange.TypedValue{
+			"/system/config/hostname": devicechange.NewTypedValueString("edge-2b"),
+		},
+	}
+	state2.unindex()
+	rebuilt.reindex(store.index)
+	store.devices[device2] = rebuilt
+	results, err = store.Search("edge-2", false)
+	assert.NoError(t, err)
+	assert.Len(t, results, 0)
+	results, err = store.Search("edge-.*", true)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "edge-2b", results[0].Value.ValueToString())
+
+	_, err = store.Search("[", true)
+	assert.Error(t, err)
+}
@@ -24,18 +24,23 @@ import (
 	devicesnapshotstore "github.com/onosproject/onos-config/pkg/store/snapshot/device"
 	"github.com/onosproject/onos-config/pkg/store/stream"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 )
 
+var log = logging.GetLogger("store", "change", "device", "state")
+
 // NewStore returns a new store backed by the device change store
 func NewStore(networkChangeStore networkchangestore.Store, deviceSnapshotStore devicesnapshotstore.Store) (Store, error) {
 	store := &deviceChangeStoreStateStore{
 		changeStore:   networkChangeStore,
 		snapshotStore: deviceSnapshotStore,
 		devices:       make(map[devicetype.VersionedID]*deviceChangeStateStore),
+		index:         newValueIndex(),
 		waiters:       make(map[networkchange.Revision]chan struct{}),
 	}
 	if err := store.listen(); err != nil {
@@ -48,6 +53,10 @@ func NewStore(networkChangeStore networkchangestore.Store, deviceSnapshotStore d
 type Store interface {
 	// Get gets the state of the given device
 	Get(id devicetype.VersionedID, revision networkchange.Revision) ([]*devicechange.PathValue, error)
+
+	// Search finds the device paths whose current value equals the given value, or
+	// matches it as a regular expression if regex is true
+	Search(value string, regex bool) ([]*SearchResult, error)
 }
 
 // deviceChangeStoreStateStore is a device state store that listens to the device change store
@@ -55,6 +64,7 @@ type deviceChangeStoreStateStore struct {
 	changeStore   networkchangestore.Store
 	snapshotStore devicesnapshotstore.Store
 	devices       map[devicetype.VersionedID]*deviceChangeStateStore
+	index         *valueIndex
 	waiters       map[networkchange.Revision]chan struct{}
 	changeIndex   networkchange.Index
 	rollbackIndex networkchange.Index
@@ -132,6 +142,7 @@ func (s *deviceChangeStoreStateStore) processNetworkChange(networkChange *networ
 			state = &deviceChangeStateStore{
 				deviceID: deviceChange.GetVersionedDeviceID(),
 				state:    make(map[string]*devicechange.TypedValue),
+				index:    s.index,
 			}
 			snapshot, err := s.snapshotStore.Load(deviceChange.GetVersionedDeviceID())
 			if err != nil {
@@ -145,6 +156,10 @@ func (s *deviceChangeStoreStateStore) processNetworkChange(networkChange *networ
 			}
 			s.devices[deviceChange.GetVersionedDeviceID()] = state
 		}
+		if !state.adopts(networkChange) {
+			log.Warnf("Ignoring adopted configuration %s: %s already has configuration", networkChange.ID, deviceChange.GetVersionedDeviceID())
+			continue
+		}
 
 		for _, value := range deviceChange.Values {
 			if value.Removed {
@@ -196,7 +211,7 @@ func (s *deviceChangeStoreStateStore) processNetworkRollback(networkChange *netw
 		if netChange.Status.Phase == changetype.Phase_CHANGE {
 			for _, devChange := range netChange.Changes {
 				state, ok := states[devChange.GetVersionedDeviceID()]
-				if ok {
+				if ok && state.adopts(netChange) {
 					for _, value := range devChange.Values {
 						if value.Removed {
 							state.remove(value.Path)
@@ -212,6 +227,10 @@ func (s *deviceChangeStoreStateStore) processNetworkRollback(networkChange *netw
 		}
 	}
 	for device, state := range states {
+		if prevState, ok := s.devices[device]; ok {
+			prevState.unindex()
+		}
+		state.reindex(s.index)
 		s.devices[device] = state
 	}
 	return nil
@@ -250,25 +269,72 @@ func (s *deviceChangeStoreStateStore) Get(id devicetype.VersionedID, revision ne
 	return device.get()
 }
 
+func (s *deviceChangeStoreStateStore) Search(value string, regex bool) ([]*SearchResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !regex {
+		return s.index.lookup(value), nil
+	}
+	re, err := regexp.Compile(value)
+	if err != nil {
+		return nil, errors.NewInvalid("invalid search expression '%s': %v", value, err)
+	}
+	return s.index.match(re), nil
+}
+
 // deviceChangeStateStore is a device state store that listens to changes for a specific device
 type deviceChangeStateStore struct {
 	deviceID devicetype.VersionedID
 	state    map[string]*devicechange.TypedValue
+	// index is the value index of the parent store; it is nil while a state is being rebuilt
+	index *valueIndex
+}
+
+// adopts returns false if the change is an adopted configuration and the device already has
+// configuration. Every replica replays the changes in the same order, so they all agree on
+// which adoption of a device, if any, takes effect.
+func (s *deviceChangeStateStore) adopts(change *networkchange.NetworkChange) bool {
+	return !networkchangestore.IsAdoptedChange(change) || len(s.state) == 0
 }
 
 func (s *deviceChangeStateStore) update(value *devicechange.PathValue) {
+	if s.index != nil {
+		s.index.remove(s.deviceID, value.Path, s.state[value.Path])
+		s.index.add(s.deviceID, value.Path, value.Value)
+	}
 	s.state[value.Path] = value.Value
 }
 
 func (s *deviceChangeStateStore) remove(rootPath string) {
-	delete(s.state, rootPath)
-	for path := range s.state {
-		if strings.Contains(path, rootPath) {
+	for path, value := range s.state {
+		if path == rootPath || strings.Contains(path, rootPath) {
+			if s.index != nil {
+				s.index.remove(s.deviceID, path, value)
+			}
 			delete(s.state, path)
 		}
 	}
 }
 
+// reindex adds all the values of the device to the given index
+func (s *deviceChangeStateStore) reindex(index *valueIndex) {
+	s.index = index
+	for path, value := range s.state {
+		index.add(s.deviceID, path, value)
+	}
+}
+
+// unindex removes all the values of the device from its index
+func (s *deviceChangeStateStore) unindex() {
+	if s.index == nil {
+		return
+	}
+	for path, value := range s.state {
+		s.index.remove(s.deviceID, path, value)
+	}
+	s.index = nil
+}
+
 // get gets the state of the device up to the given revision
 func (s *deviceChangeStateStore) get() ([]*devicechange.PathValue, error) {
 	state := make([]*devicechange.PathValue, 0, len(s.state))
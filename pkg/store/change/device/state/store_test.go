@@ -16,6 +16,12 @@ package state
 
 import (
 	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	networkchangestore "github.com/onosproject/onos-config/pkg/store/change/network"
+	"github.com/stretchr/testify/assert"
 )
 
 // TestDeviceStateStore tests that device changes are propagated to the device state store
@@ -80,3 +86,43 @@ func TestDeviceStateStore(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, state, 0)*/
 }
+
+func adoptedChange(deviceID devicetype.ID) *networkchange.NetworkChange {
+	return &networkchange.NetworkChange{
+		ID: networkchangestore.NewAdoptedChangeID(deviceID, "1.0.0"),
+		Changes: []*devicechange.Change{
+			{
+				DeviceID:      deviceID,
+				DeviceVersion: "1.0.0",
+				DeviceType:    "Devicesim",
+				Values: []*devicechange.ChangeValue{
+					{Path: "/system/config/hostname", Value: devicechange.NewTypedValueString("adopted")},
+				},
+			},
+		},
+	}
+}
+
+func TestDeviceStateStore_Adoption(t *testing.T) {
+	store := &deviceChangeStoreStateStore{
+		devices: make(map[devicetype.VersionedID]*deviceChangeStateStore),
+		index:   newValueIndex(),
+	}
+	device1 := devicetype.NewVersionedID("device-1", "1.0.0")
+	device2 := devicetype.NewVersionedID("device-2", "1.0.0")
+	newTestState(device1, store).update(&devicechange.PathValue{Path: "/system/config/hostname", Value: devicechange.NewTypedValueString("set")})
+	newTestState(device2, store)
+
+	// An adopted configuration only sets the baseline of a device without configuration
+	assert.NoError(t, store.processNetworkChange(adoptedChange("device-1")))
+	values, err := store.devices[device1].get()
+	assert.NoError(t, err)
+	assert.Len(t, values, 1)
+	assert.Equal(t, "set", values[0].Value.ValueToString())
+
+	assert.NoError(t, store.processNetworkChange(adoptedChange("device-2")))
+	values, err = store.devices[device2].get()
+	assert.NoError(t, err)
+	assert.Len(t, values, 1)
+	assert.Equal(t, "adopted", values[0].Value.ValueToString())
+}
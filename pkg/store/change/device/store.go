@@ -26,9 +26,9 @@ import (
 	"github.com/atomix/atomix-go-client/pkg/atomix"
 	"github.com/atomix/atomix-go-client/pkg/atomix/indexedmap"
 	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
-	"github.com/gogo/protobuf/proto"
 	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
 	"github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/schema"
 	"github.com/onosproject/onos-config/pkg/store/stream"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 )
@@ -51,6 +51,14 @@ func NewAtomixStore(client atomix.Client) (Store, error) {
 	}, nil
 }
 
+// Migrator is implemented by the stores whose device changes can be migrated by a migration job.
+// Device changes are partitioned by device, so they are migrated one device at a time.
+type Migrator interface {
+	// MigrateDevice rewrites the outdated changes of a device at the schema version written by the cluster.
+	// A dry run only counts the outdated changes.
+	MigrateDevice(deviceID device.VersionedID, dryRun bool) (schema.Report, error)
+}
+
 // Store stores DeviceChanges
 type Store interface {
 	io.Closer
@@ -182,7 +190,10 @@ func (s *atomixStore) Create(change *devicechange.DeviceChange) error {
 		return errors.FromAtomix(err)
 	}
 
-	bytes, err := proto.Marshal(change)
+	// The time is stripped of its monotonic reading, to be the same once decoded
+	change.Created = time.Now().UTC()
+	change.Updated = change.Created
+	bytes, err := schema.Marshal(schema.DeviceChange, change)
 	if err != nil {
 		return errors.NewInvalid("change encoding failed: %v", err)
 	}
@@ -230,7 +241,8 @@ func (s *atomixStore) Update(change *devicechange.DeviceChange) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	bytes, err := proto.Marshal(change)
+	change.Updated = time.Now().UTC()
+	bytes, err := schema.Marshal(schema.DeviceChange, change)
 	if err != nil {
 		return errors.NewInvalid("change encoding failed: %v", err)
 	}
@@ -347,6 +359,15 @@ func (s *atomixStore) Watch(deviceID device.VersionedID, ch chan<- stream.Event,
 	return stream.NewCancelContext(cancel), nil
 }
 
+// MigrateDevice rewrites the outdated changes of a device at the schema version written by the cluster
+func (s *atomixStore) MigrateDevice(deviceID device.VersionedID, dryRun bool) (schema.Report, error) {
+	changes, err := s.getDeviceChanges(deviceID)
+	if err != nil {
+		return schema.Report{}, errors.FromAtomix(err)
+	}
+	return schema.MigrateIndexedMap(fmt.Sprintf("onos-config-device-changes/%s", deviceID), schema.DeviceChange, changes, dryRun)
+}
+
 func (s *atomixStore) Close() error {
 	var returnErr error
 	for _, changes := range s.deviceChanges {
@@ -359,7 +380,7 @@ func (s *atomixStore) Close() error {
 
 func decodeChange(entry indexedmap.Entry) (*devicechange.DeviceChange, error) {
 	change := &devicechange.DeviceChange{}
-	if err := proto.Unmarshal(entry.Value, change); err != nil {
+	if err := schema.Unmarshal(schema.DeviceChange, entry.Value, change); err != nil {
 		return nil, errors.NewInvalid("change decoding failed: %v", err)
 	}
 	change.ID = devicechange.ID(entry.Key)
@@ -20,6 +20,7 @@ import (
 
 	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
 	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
 	"github.com/onosproject/onos-api/go/onos/config/device"
 	devicechangestore "github.com/onosproject/onos-config/pkg/store/change/device"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
@@ -114,3 +115,149 @@ func getPathValue(storeChange *devicechange.Change, consolidatedConfig []*device
 	}
 	return consolidatedConfig
 }
+
+// ExtractConfigWithout retrieves the full consolidated config for a device as it would be
+// without the device change belonging to the given network change, i.e. the configuration
+// a rollback of that network change returns the device to
+func ExtractConfigWithout(deviceID device.VersionedID, networkChangeID networkchange.ID, changeStore devicechangestore.Store) ([]*devicechange.PathValue, error) {
+	consolidatedConfig := make([]*devicechange.PathValue, 0)
+
+	changeChan := make(chan *devicechange.DeviceChange)
+	ctx, err := changeStore.List(deviceID, changeChan)
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Close()
+
+	for storeChange := range changeChan {
+		if storeChange.Status.Phase == changetypes.Phase_CHANGE && string(storeChange.NetworkChange.ID) != string(networkChangeID) {
+			consolidatedConfig = getPathValue(storeChange.Change, consolidatedConfig)
+		}
+	}
+
+	sort.Slice(consolidatedConfig, func(i, j int) bool {
+		return consolidatedConfig[i].Path < consolidatedConfig[j].Path
+	})
+	return consolidatedConfig, nil
+}
+
+// ExtractConfigBefore retrieves the full consolidated config for a device as the changes that
+// precede the change of the given index in network change index order left it, i.e. the
+// configuration the device has before that change is applied
+func ExtractConfigBefore(deviceID device.VersionedID, index devicechange.Index, changeStore devicechangestore.Store) ([]*devicechange.PathValue, error) {
+	changeChan := make(chan *devicechange.DeviceChange)
+	ctx, err := changeStore.List(deviceID, changeChan)
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Close()
+
+	preceding := make([]*devicechange.DeviceChange, 0)
+	for storeChange := range changeChan {
+		if storeChange.Index < index && storeChange.Status.Phase == changetypes.Phase_CHANGE &&
+			storeChange.Status.State != changetypes.State_FAILED {
+			preceding = append(preceding, storeChange)
+		}
+	}
+	sort.Slice(preceding, func(i, j int) bool {
+		return preceding[i].Index < preceding[j].Index
+	})
+
+	consolidatedConfig := make([]*devicechange.PathValue, 0)
+	for _, storeChange := range preceding {
+		consolidatedConfig = getPathValue(storeChange.Change, consolidatedConfig)
+	}
+	sort.Slice(consolidatedConfig, func(i, j int) bool {
+		return consolidatedConfig[i].Path < consolidatedConfig[j].Path
+	})
+	return consolidatedConfig, nil
+}
+
+// ApplyChange returns a consolidated config with the values of a change applied to it
+func ApplyChange(config []*devicechange.PathValue, change *devicechange.Change) []*devicechange.PathValue {
+	consolidatedConfig := make([]*devicechange.PathValue, 0, len(config))
+	for _, pathValue := range config {
+		consolidatedConfig = append(consolidatedConfig, &devicechange.PathValue{Path: pathValue.Path, Value: pathValue.Value})
+	}
+	consolidatedConfig = getPathValue(change, consolidatedConfig)
+	sort.Slice(consolidatedConfig, func(i, j int) bool {
+		return consolidatedConfig[i].Path < consolidatedConfig[j].Path
+	})
+	return consolidatedConfig
+}
+
+// AppliedIndex is the high-water mark of the changes applied to a device
+type AppliedIndex struct {
+	// Index is the index of the last change applied to the device, 0 if none is
+	Index devicechange.Index
+	// NetworkChange is the network change of the last change applied to the device
+	NetworkChange networkchange.ID
+	// Pending are the changes to the device still to be applied, in index order
+	Pending []*devicechange.DeviceChange
+}
+
+// GetAppliedIndex returns the high-water mark of the changes applied to a device. As changes are
+// applied in index order, every change before it is applied, or rolled back.
+func GetAppliedIndex(deviceID device.VersionedID, changeStore devicechangestore.Store) (*AppliedIndex, error) {
+	changeChan := make(chan *devicechange.DeviceChange)
+	ctx, err := changeStore.List(deviceID, changeChan)
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Close()
+
+	applied := &AppliedIndex{
+		Pending: make([]*devicechange.DeviceChange, 0),
+	}
+	for storeChange := range changeChan {
+		if storeChange.Status.Phase == changetypes.Phase_CHANGE && storeChange.Status.State == changetypes.State_COMPLETE &&
+			storeChange.Index > applied.Index {
+			applied.Index = storeChange.Index
+			applied.NetworkChange = networkchange.ID(storeChange.NetworkChange.ID)
+		} else if isToBeApplied(storeChange) {
+			applied.Pending = append(applied.Pending, storeChange)
+		}
+	}
+	sort.Slice(applied.Pending, func(i, j int) bool {
+		return applied.Pending[i].Index < applied.Pending[j].Index
+	})
+	return applied, nil
+}
+
+// isToBeApplied returns whether a device change is still to be applied to its device
+func isToBeApplied(deviceChange *devicechange.DeviceChange) bool {
+	return deviceChange.Status.Phase == changetypes.Phase_CHANGE && deviceChange.Status.State == changetypes.State_PENDING
+}
+
+// ComputeRollback returns a change containing the previous value for each path of the rollbackChange,
+// or a removal of the path if it had no previous value
+func ComputeRollback(rollbackChange *devicechange.Change, prevValues []*devicechange.PathValue) *devicechange.Change {
+	//TODO We might want to consider doing reverse iteration to get the previous value for a path instead of
+	// reading up to the previous change for the target. see comments on PR #805
+	previousValues := make([]*devicechange.ChangeValue, 0)
+	alreadyUpdated := make(map[string]struct{})
+	for _, rbValue := range rollbackChange.Values {
+		for _, prevVal := range prevValues {
+			if prevVal.Path == rbValue.Path ||
+				rbValue.Removed && strings.HasPrefix(prevVal.Path, rbValue.Path) {
+				alreadyUpdated[rbValue.Path] = struct{}{}
+				previousValues = append(previousValues, &devicechange.ChangeValue{
+					Path:  prevVal.Path,
+					Value: prevVal.Value,
+				})
+			}
+		}
+		if _, ok := alreadyUpdated[rbValue.Path]; !ok {
+			previousValues = append(previousValues, &devicechange.ChangeValue{
+				Path:    rbValue.Path,
+				Removed: true,
+			})
+		}
+	}
+	return &devicechange.Change{
+		DeviceID:      rollbackChange.DeviceID,
+		DeviceVersion: rollbackChange.DeviceVersion,
+		DeviceType:    rollbackChange.DeviceType,
+		Values:        previousValues,
+	}
+}
@@ -16,7 +16,9 @@ package utils
 
 import (
 	"encoding/base64"
+	"fmt"
 	"github.com/golang/mock/gomock"
+	types "github.com/onosproject/onos-api/go/onos/config"
 	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
 	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
 	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
@@ -515,3 +517,69 @@ func Test_device2_version(t *testing.T) {
 			Config2Paths[0:11], Config2Values[0:11], Config2Types[0:11])
 	}
 }
+
+func Test_ComputeRollback(t *testing.T) {
+	rollbackChange := &devicechange.Change{
+		DeviceID:      "Device1",
+		DeviceVersion: "1.0.0",
+		DeviceType:    "TestDevice",
+		Values: []*devicechange.ChangeValue{
+			{Path: Test1Cont1ACont2ALeaf2A, Value: devicechange.NewTypedValueUint(13, 8)},
+			{Path: Test1Cont1ACont2ALeaf2B, Value: devicechange.NewTypedValueDecimal(1234, 3)},
+			{Path: Test1Cont1AList2ATxout1, Removed: true},
+		},
+	}
+	prevValues := []*devicechange.PathValue{
+		{Path: Test1Cont1ACont2ALeaf2A, Value: devicechange.NewTypedValueUint(12, 8)},
+		{Path: Test1Cont1AList2ATxout1Txpwr, Value: devicechange.NewTypedValueUint(8, 16)},
+	}
+
+	rollback := ComputeRollback(rollbackChange, prevValues)
+	assert.Equal(t, devicetype.ID("Device1"), rollback.DeviceID)
+	assert.Equal(t, devicetype.Version("1.0.0"), rollback.DeviceVersion)
+	assert.Equal(t, devicetype.Type("TestDevice"), rollback.DeviceType)
+	assert.Equal(t, 3, len(rollback.Values))
+	assert.Equal(t, Test1Cont1ACont2ALeaf2A, rollback.Values[0].Path)
+	assert.Equal(t, "12", rollback.Values[0].Value.ValueToString())
+	assert.Equal(t, Test1Cont1ACont2ALeaf2B, rollback.Values[1].Path)
+	assert.Assert(t, rollback.Values[1].Removed)
+	assert.Equal(t, Test1Cont1AList2ATxout1Txpwr, rollback.Values[2].Path)
+	assert.Equal(t, "8", rollback.Values[2].Value.ValueToString())
+}
+
+func Test_AppliedIndex(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockChangeStore := mockstore.NewMockDeviceChangesStore(ctrl)
+	newDeviceChange := func(index devicechange.Index, phase changetypes.Phase, state changetypes.State) *devicechange.DeviceChange {
+		return &devicechange.DeviceChange{
+			ID:            devicechange.ID(fmt.Sprintf("change-%d:device-1:1.0.0", index)),
+			Index:         index,
+			NetworkChange: devicechange.NetworkChangeRef{ID: types.ID(fmt.Sprintf("change-%d", index))},
+			Status:        changetypes.Status{Phase: phase, State: state},
+		}
+	}
+	deviceChanges := []*devicechange.DeviceChange{
+		newDeviceChange(1, changetypes.Phase_CHANGE, changetypes.State_COMPLETE),
+		newDeviceChange(2, changetypes.Phase_ROLLBACK, changetypes.State_COMPLETE),
+		newDeviceChange(3, changetypes.Phase_CHANGE, changetypes.State_COMPLETE),
+		newDeviceChange(4, changetypes.Phase_CHANGE, changetypes.State_PENDING),
+		newDeviceChange(5, changetypes.Phase_CHANGE, changetypes.State_PENDING),
+	}
+	mockChangeStore.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(device devicetype.VersionedID, c chan<- *devicechange.DeviceChange) (stream.Context, error) {
+			go func() {
+				for _, deviceChange := range deviceChanges {
+					c <- deviceChange
+				}
+				close(c)
+			}()
+			return stream.NewContext(func() {}), nil
+		}).AnyTimes()
+
+	applied, err := GetAppliedIndex("device-1:1.0.0", mockChangeStore)
+	assert.NilError(t, err)
+	assert.Equal(t, applied.Index, devicechange.Index(3))
+	assert.Equal(t, string(applied.NetworkChange), "change-3")
+	assert.Equal(t, len(applied.Pending), 2)
+	assert.Equal(t, applied.Pending[0].Index, devicechange.Index(4))
+}
@@ -0,0 +1,131 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package environment stores the environment each network change was created in: the version of
+// onos-config, the validation settings and the model plugins its devices were validated with. It
+// explains, long after the fact, why a change that is invalid today was accepted at the time.
+package environment
+
+import (
+	"io"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Environment is the environment a network change was created in
+type Environment struct {
+	// ChangeID is the network change created in the environment
+	ChangeID networkchange.ID `json:"changeId"`
+	// Created is when the environment was captured, as the change was created
+	Created time.Time `json:"created"`
+	// ControllerVersion is the version of the onos-config node that created the change
+	ControllerVersion string `json:"controllerVersion"`
+	// ValidationLevel is the level the change was validated at, e.g. "strict"
+	ValidationLevel string `json:"validationLevel"`
+	// AllowUnvalidatedConfig is true if the devices without a model plugin could be configured
+	AllowUnvalidatedConfig bool `json:"allowUnvalidatedConfig,omitempty"`
+	// Devices are the environments of the devices of the change, sorted by device
+	Devices []*DeviceEnvironment `json:"devices"`
+}
+
+// DeviceEnvironment is the environment the change of a device was validated in
+type DeviceEnvironment struct {
+	DeviceID      devicetype.ID      `json:"deviceId"`
+	DeviceType    devicetype.Type    `json:"deviceType"`
+	DeviceVersion devicetype.Version `json:"deviceVersion"`
+	// Model is the name and version of the model the change was validated with, as name@version;
+	// empty if no model plugin was available
+	Model string `json:"model,omitempty"`
+	// Plugin is the name and version of the model plugin, as name@version
+	Plugin string `json:"plugin,omitempty"`
+	// AllowUnknownPaths is true if the paths that are not in the model were accepted
+	AllowUnknownPaths bool `json:"allowUnknownPaths,omitempty"`
+}
+
+// Store stores the environments of the network changes
+type Store interface {
+	io.Closer
+
+	// Get gets the environment of a network change
+	Get(id networkchange.ID) (*Environment, error)
+
+	// Create stores the environment of a new network change. It fails with AlreadyExists if the
+	// change already has one.
+	Create(environment *Environment) error
+
+	// Delete deletes the environment of a network change
+	Delete(id networkchange.ID) error
+}
+
+// kind and notFound describe the environments in the errors of the store
+const kind = "environment"
+
+var notFound = records.WithNotFound("no environment for change '%s'")
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	environments, err := records.NewAtomixMap(client, "onos-config-change-environments", kind, notFound)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		environments: environments,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps environments in memory
+func NewLocalStore() Store {
+	return &store{
+		environments: records.NewLocalMap(kind, notFound),
+	}
+}
+
+// store keeps the environments by network change ID
+type store struct {
+	environments records.Map
+}
+
+func (s *store) Get(id networkchange.ID) (*Environment, error) {
+	environment := &Environment{}
+	if err := s.environments.Get(string(id), environment); err != nil {
+		return nil, err
+	}
+	return environment, nil
+}
+
+func (s *store) Create(environment *Environment) error {
+	if environment.ChangeID == "" {
+		return errors.NewInvalid("no change ID specified")
+	}
+	if err := s.environments.Create(string(environment.ChangeID), environment); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return errors.NewAlreadyExists("change '%s' already has an environment", environment.ChangeID)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *store) Delete(id networkchange.ID) error {
+	return s.environments.Delete(string(id))
+}
+
+func (s *store) Close() error {
+	return s.environments.Close()
+}
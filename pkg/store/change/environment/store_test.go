@@ -0,0 +1,72 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package environment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	environment := &Environment{
+		ChangeID:          "change-1",
+		Created:           time.Unix(1620000000, 0).UTC(),
+		ControllerVersion: "v0.10.1",
+		ValidationLevel:   "schema-only",
+		Devices: []*DeviceEnvironment{{
+			DeviceID:          "device-1",
+			DeviceType:        "Devicesim",
+			DeviceVersion:     "1.0.0",
+			Model:             "Devicesim@1.0.0",
+			Plugin:            "devicesim@1.0.0",
+			AllowUnknownPaths: true,
+		}},
+	}
+	assert.NoError(t, store.Create(environment))
+
+	stored, err := store.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, environment, stored)
+
+	// A change only has one environment
+	other := *environment
+	other.ControllerVersion = "v0.10.2"
+	assert.True(t, errors.IsAlreadyExists(store.Create(&other)))
+	assert.True(t, errors.IsInvalid(store.Create(&Environment{})))
+
+	assert.NoError(t, store.Delete("change-1"))
+	_, err = store.Get("change-1")
+	assert.True(t, errors.IsNotFound(err))
+	assert.True(t, errors.IsNotFound(store.Delete("change-1")))
+
+	// The environments returned are copies
+	assert.NoError(t, store.Create(&other))
+	stored, err = store.Get("change-1")
+	assert.NoError(t, err)
+	stored.Devices[0].Plugin = "devicesim@2.0.0"
+	stored, err = store.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.10.2", stored.ControllerVersion)
+	assert.Equal(t, "devicesim@1.0.0", stored.Devices[0].Plugin)
+
+	assert.EqualError(t, store.Create(environment), "change 'change-1' already has an environment")
+	assert.EqualError(t, store.Delete("change-2"), "no environment for change 'change-2'")
+}
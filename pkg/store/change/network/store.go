@@ -16,19 +16,26 @@ package network
 
 import (
 	"context"
+	"fmt"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/atomix/atomix-go-client/pkg/atomix"
 	"github.com/atomix/atomix-go-client/pkg/atomix/indexedmap"
-	"github.com/gogo/protobuf/proto"
 	types "github.com/onosproject/onos-api/go/onos/config"
 	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/schema"
 	"github.com/onosproject/onos-config/pkg/store/stream"
 )
 
+// AdoptedChangePrefix starts the ID of the network changes holding the adopted configuration
+// of a device. Such a change only sets the baseline of a device that has no configuration yet.
+const AdoptedChangePrefix = "adopted-"
+
 // NewAtomixStore returns a new persistent Store
 func NewAtomixStore(client atomix.Client) (Store, error) {
 	changes, err := client.GetIndexedMap(context.Background(), "onos-config-network-changes")
@@ -105,6 +112,20 @@ func WithChangeID(id networkchange.ID) WatchOption {
 	return watchIDOption{id: id}
 }
 
+// adoptedChangeIDReplacer replaces the characters that network change IDs may not contain
+var adoptedChangeIDReplacer = strings.NewReplacer(".", "_", ":", "_", "/", "_")
+
+// NewAdoptedChangeID returns the ID of the network change holding the adopted configuration of
+// a device. It is the same for every adoption of the device, so that it is adopted at most once.
+func NewAdoptedChangeID(deviceID devicetype.ID, version devicetype.Version) networkchange.ID {
+	return networkchange.ID(AdoptedChangePrefix + adoptedChangeIDReplacer.Replace(fmt.Sprintf("%s-%s", deviceID, version)))
+}
+
+// IsAdoptedChange returns true if the change holds the adopted configuration of a device
+func IsAdoptedChange(change *networkchange.NetworkChange) bool {
+	return strings.HasPrefix(string(change.ID), AdoptedChangePrefix)
+}
+
 // newChangeID creates a new network change ID
 func newChangeID() networkchange.ID {
 	newUUID := types.NewUUID()
@@ -167,8 +188,11 @@ func (s *atomixStore) Create(change *networkchange.NetworkChange) error {
 	if change.Revision != 0 {
 		return errors.NewInvalid("not a new object")
 	}
+	// The time is stripped of its monotonic reading, to be the same once decoded
+	change.Created = time.Now().UTC()
+	change.Updated = change.Created
 
-	bytes, err := proto.Marshal(change)
+	bytes, err := schema.Marshal(schema.NetworkChange, change)
 	if err != nil {
 		return errors.NewInvalid("change encoding failed: %v", err)
 	}
@@ -194,7 +218,8 @@ func (s *atomixStore) Update(change *networkchange.NetworkChange) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	bytes, err := proto.Marshal(change)
+	change.Updated = time.Now().UTC()
+	bytes, err := schema.Marshal(schema.NetworkChange, change)
 	if err != nil {
 		return errors.NewInvalid("change encoding failed: %v", err)
 	}
@@ -293,13 +318,22 @@ func (s *atomixStore) Watch(ch chan<- stream.Event, opts ...WatchOption) (stream
 	}), nil
 }
 
+// Migrate rewrites the outdated network changes at the schema version written by the cluster
+func (s *atomixStore) Migrate(dryRun bool) ([]schema.Report, error) {
+	report, err := schema.MigrateIndexedMap("onos-config-network-changes", schema.NetworkChange, s.changes, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	return []schema.Report{report}, nil
+}
+
 func (s *atomixStore) Close() error {
 	return s.changes.Close(context.Background())
 }
 
 func decodeChange(entry indexedmap.Entry) (*networkchange.NetworkChange, error) {
 	change := &networkchange.NetworkChange{}
-	if err := proto.Unmarshal(entry.Value, change); err != nil {
+	if err := schema.Unmarshal(schema.NetworkChange, entry.Value, change); err != nil {
 		return nil, errors.NewInvalid("change decoding failed: %v", err)
 	}
 	change.ID = networkchange.ID(entry.Key)
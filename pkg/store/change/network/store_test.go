@@ -15,12 +15,15 @@
 package network
 
 import (
+	"context"
 	"github.com/atomix/atomix-go-client/pkg/atomix/test"
 	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	"github.com/gogo/protobuf/proto"
 	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
 	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
 	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
 	"github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/schema"
 	"github.com/onosproject/onos-config/pkg/store/stream"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -124,6 +127,8 @@ func TestNetworkChangeStore(t *testing.T) {
 	assert.Equal(t, networkchange.ID("change-1"), change1.ID)
 	assert.Equal(t, networkchange.Index(1), change1.Index)
 	assert.NotEqual(t, networkchange.Revision(0), change1.Revision)
+	assert.False(t, change1.Created.IsZero())
+	assert.Equal(t, change1.Created, change1.Updated)
 
 	// Create another change
 	err = store2.Create(change2)
@@ -153,6 +158,8 @@ func TestNetworkChangeStore(t *testing.T) {
 	event := (<-changeCh).Object.(*networkchange.NetworkChange)
 	assert.Equal(t, change2.ID, event.ID)
 	assert.Equal(t, change2.Revision, event.Revision)
+	assert.Equal(t, change2.Created, event.Created)
+	assert.True(t, event.Updated.After(event.Created))
 
 	// Get previous and next changes
 	change1, err = store2.Get("change-1")
@@ -284,6 +291,67 @@ func TestNetworkChangeStore(t *testing.T) {
 	assert.Equal(t, networkchange.Index(4), change.Index)
 }
 
+func TestNetworkChangeStoreMigration(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	client, err := test.NewClient("node-1")
+	assert.NoError(t, err)
+	store, err := NewAtomixStore(client)
+	assert.NoError(t, err)
+	defer store.Close()
+	changes := store.(*atomixStore).changes
+
+	// A change written before schema versioning is read as is
+	legacy, err := proto.Marshal(&networkchange.NetworkChange{
+		Changes: []*devicechange.Change{
+			{
+				DeviceID:      "device-1",
+				DeviceVersion: "1.0.0",
+			},
+		},
+	})
+	assert.NoError(t, err)
+	_, err = changes.Append(context.Background(), "change-1", legacy)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Create(&networkchange.NetworkChange{ID: "change-2"}))
+
+	change, err := store.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, device.ID("device-1"), change.Changes[0].DeviceID)
+
+	reports, err := store.(schema.Migrator).Migrate(true)
+	assert.NoError(t, err)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, 2, reports[0].Scanned)
+	assert.Equal(t, 1, reports[0].Outdated)
+	assert.Equal(t, 0, reports[0].Migrated)
+
+	reports, err = store.(schema.Migrator).Migrate(false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reports[0].Outdated)
+	assert.Equal(t, 1, reports[0].Migrated)
+	assert.Equal(t, 0, reports[0].Failed)
+
+	entry, err := changes.Get(context.Background(), "change-1")
+	assert.NoError(t, err)
+	version, err := schema.VersionOf(entry.Value)
+	assert.NoError(t, err)
+	assert.Equal(t, schema.CurrentVersion(schema.NetworkChange), version)
+	change, err = store.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, device.ID("device-1"), change.Changes[0].DeviceID)
+	assert.Equal(t, networkchange.Index(1), change.Index)
+
+	reports, err = store.(schema.Migrator).Migrate(false)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, reports[0].Outdated)
+}
+
 func nextEvent(t *testing.T, ch chan stream.Event) *networkchange.NetworkChange {
 	select {
 	case c := <-ch:
@@ -0,0 +1,127 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pause stores the network changes whose propagation an operator has paused. No device
+// change of a paused network change is pushed until the network change is resumed.
+package pause
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	"github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Pause records who paused a network change and why
+type Pause struct {
+	// NetworkChangeID is the paused network change
+	NetworkChangeID network.ID `json:"networkChangeId"`
+	// User is the user who paused the network change
+	User string `json:"user"`
+	// Reason is why the network change was paused
+	Reason string `json:"reason"`
+	// Created is when the network change was paused
+	Created time.Time `json:"created"`
+}
+
+// Store stores the paused network changes
+type Store interface {
+	io.Closer
+
+	// Get gets the pause of a network change
+	Get(id network.ID) (*Pause, error)
+
+	// Put pauses a network change, replacing any previous pause of it
+	Put(pause *Pause) error
+
+	// Delete resumes a network change
+	Delete(id network.ID) error
+
+	// List lists the paused network changes, sorted by network change ID
+	List() ([]*Pause, error)
+}
+
+// kind and notFound describe the pauses in the errors of the store
+const kind = "pause"
+
+var notFound = records.WithNotFound("network change '%s' is not paused")
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	pauses, err := records.NewAtomixMap(client, "onos-config-paused-changes", kind, notFound)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		pauses: pauses,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps pauses in memory
+func NewLocalStore() Store {
+	return &store{
+		pauses: records.NewLocalMap(kind, notFound),
+	}
+}
+
+// store keeps the pauses by network change ID
+type store struct {
+	pauses records.Map
+}
+
+func (s *store) Get(id network.ID) (*Pause, error) {
+	pause := &Pause{}
+	if err := s.pauses.Get(string(id), pause); err != nil {
+		return nil, err
+	}
+	return pause, nil
+}
+
+func (s *store) Put(pause *Pause) error {
+	if pause.NetworkChangeID == "" {
+		return errors.NewInvalid("no network change ID given")
+	}
+	return s.pauses.Put(string(pause.NetworkChangeID), pause)
+}
+
+func (s *store) Delete(id network.ID) error {
+	return s.pauses.Delete(string(id))
+}
+
+func (s *store) List() ([]*Pause, error) {
+	list, err := s.pauses.List(func() interface{} { return &Pause{} })
+	if err != nil {
+		return nil, err
+	}
+	pauses := make([]*Pause, 0, len(list))
+	for _, record := range list {
+		pauses = append(pauses, record.(*Pause))
+	}
+	sortPauses(pauses)
+	return pauses, nil
+}
+
+func (s *store) Close() error {
+	return s.pauses.Close()
+}
+
+func sortPauses(pauses []*Pause) {
+	sort.Slice(pauses, func(i, j int) bool {
+		return pauses[i].NetworkChangeID < pauses[j].NetworkChangeID
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pause
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	assert.NoError(t, store.Put(&Pause{
+		NetworkChangeID: "change-2",
+		User:            "alice",
+		Reason:          "errors on leaf-1",
+		Created:         time.Now(),
+	}))
+	assert.NoError(t, store.Put(&Pause{NetworkChangeID: "change-1", User: "bob"}))
+	assert.True(t, errors.IsInvalid(store.Put(&Pause{})))
+
+	pauses, err := store.List()
+	assert.NoError(t, err)
+	assert.Len(t, pauses, 2)
+	assert.Equal(t, "change-1", string(pauses[0].NetworkChangeID))
+	assert.Equal(t, "change-2", string(pauses[1].NetworkChangeID))
+
+	pause, err := store.Get("change-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", pause.User)
+	assert.Equal(t, "errors on leaf-1", pause.Reason)
+
+	assert.NoError(t, store.Delete("change-2"))
+	_, err = store.Get("change-2")
+	assert.True(t, errors.IsNotFound(err))
+	assert.True(t, errors.IsNotFound(store.Delete("change-2")))
+
+	// A change paused again keeps only the latest pause
+	assert.NoError(t, store.Put(&Pause{NetworkChangeID: "change-1", User: "alice", Reason: "maintenance"}))
+	pause, err = store.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", pause.User)
+
+	// The pauses returned are copies
+	pause.Reason = "none"
+	pause, err = store.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "maintenance", pause.Reason)
+
+	assert.EqualError(t, store.Delete("change-2"), "network change 'change-2' is not paused")
+}
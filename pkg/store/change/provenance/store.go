@@ -0,0 +1,167 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package provenance stores the artifacts network changes originate from: the template, the
+// intent or the GitOps sync that made the change on behalf of its user. A provenance applies to
+// every value of its change, so that the leaves it set can be traced back to the artifact that
+// manages them.
+package provenance
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Kind is the kind of artifact a network change originates from
+type Kind string
+
+const (
+	// Template is a configuration template rendered for a device
+	Template Kind = "template"
+	// Intent is an intent translated into configuration
+	Intent Kind = "intent"
+	// GitOps is a configuration synced from a Git repository
+	GitOps Kind = "gitops"
+	// ClosedLoop is the action of a closed loop rule triggered by the state of a device
+	ClosedLoop Kind = "closed-loop"
+)
+
+// Provenance is the artifact a network change originates from
+type Provenance struct {
+	// ChangeID is the network change originating from the artifact
+	ChangeID networkchange.ID `json:"changeId"`
+	// Kind is the kind of the artifact
+	Kind Kind `json:"kind"`
+	// Artifact is the ID of the artifact, e.g. the name of the template
+	Artifact string `json:"artifact"`
+	// User is the name of the caller who made the change
+	User string `json:"user,omitempty"`
+	// Created is when the provenance was stored
+	Created time.Time `json:"created"`
+}
+
+// String returns the provenance as "<kind>/<artifact>"
+func (p *Provenance) String() string {
+	return string(p.Kind) + "/" + p.Artifact
+}
+
+// Parse parses a provenance given as "<kind>/<artifact>", e.g. "template/access-ports"
+func Parse(value string) (*Provenance, error) {
+	i := strings.Index(value, "/")
+	if i < 0 {
+		return nil, errors.NewInvalid("provenance '%s' is not <kind>/<artifact>", value)
+	}
+	provenance := &Provenance{
+		Kind:     Kind(value[:i]),
+		Artifact: value[i+1:],
+	}
+	if err := checkProvenance(provenance); err != nil {
+		return nil, err
+	}
+	return provenance, nil
+}
+
+func checkProvenance(provenance *Provenance) error {
+	switch provenance.Kind {
+	case Template, Intent, GitOps, ClosedLoop:
+	default:
+		return errors.NewInvalid("unknown provenance kind '%s': expected %s, %s, %s or %s", provenance.Kind,
+			Template, Intent, GitOps, ClosedLoop)
+	}
+	if provenance.Artifact == "" {
+		return errors.NewInvalid("no %s given", provenance.Kind)
+	}
+	return nil
+}
+
+// Store stores the provenances of network changes
+type Store interface {
+	io.Closer
+
+	// Get gets the provenance of a network change
+	Get(id networkchange.ID) (*Provenance, error)
+
+	// Create stores the provenance of a new network change, before the change is created. It fails
+	// with AlreadyExists if the change already has one.
+	Create(provenance *Provenance) error
+
+	// Delete deletes the provenance of a network change that could not be created
+	Delete(id networkchange.ID) error
+}
+
+// kind and notFound describe the provenances in the errors of the store
+const kind = "provenance"
+
+var notFound = records.WithNotFound("no provenance for change '%s'")
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	provenances, err := records.NewAtomixMap(client, "onos-config-change-provenances", kind, notFound)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		provenances: provenances,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps provenances in memory
+func NewLocalStore() Store {
+	return &store{
+		provenances: records.NewLocalMap(kind, notFound),
+	}
+}
+
+// store keeps the provenances by network change ID
+type store struct {
+	provenances records.Map
+}
+
+func (s *store) Get(id networkchange.ID) (*Provenance, error) {
+	provenance := &Provenance{}
+	if err := s.provenances.Get(string(id), provenance); err != nil {
+		return nil, err
+	}
+	return provenance, nil
+}
+
+func (s *store) Create(provenance *Provenance) error {
+	if provenance.ChangeID == "" {
+		return errors.NewInvalid("no change ID specified")
+	}
+	if err := checkProvenance(provenance); err != nil {
+		return err
+	}
+	if err := s.provenances.Create(string(provenance.ChangeID), provenance); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return errors.NewAlreadyExists("change '%s' already has a provenance", provenance.ChangeID)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *store) Delete(id networkchange.ID) error {
+	return s.provenances.Delete(string(id))
+}
+
+func (s *store) Close() error {
+	return s.provenances.Close()
+}
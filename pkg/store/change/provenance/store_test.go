@@ -0,0 +1,86 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	provenance, err := Parse("template/access-ports/v2")
+	assert.NoError(t, err)
+	assert.Equal(t, Template, provenance.Kind)
+	assert.Equal(t, "access-ports/v2", provenance.Artifact)
+	assert.Equal(t, "template/access-ports/v2", provenance.String())
+
+	_, err = Parse("intent")
+	assert.True(t, errors.IsInvalid(err))
+	_, err = Parse("intent/")
+	assert.True(t, errors.IsInvalid(err))
+	_, err = Parse("script/cleanup")
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	provenance := &Provenance{
+		ChangeID: "change-1",
+		Kind:     Intent,
+		Artifact: "slice-42",
+		User:     "alice",
+		Created:  time.Unix(1620000000, 0).UTC(),
+	}
+	assert.NoError(t, store.Create(provenance))
+
+	stored, err := store.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, provenance, stored)
+
+	// A change only has one provenance
+	other := *provenance
+	other.Artifact = "slice-43"
+	assert.True(t, errors.IsAlreadyExists(store.Create(&other)))
+
+	invalid := *provenance
+	invalid.ChangeID = "change-2"
+	invalid.Kind = "script"
+	assert.True(t, errors.IsInvalid(store.Create(&invalid)))
+
+	assert.NoError(t, store.Delete("change-1"))
+	_, err = store.Get("change-1")
+	assert.True(t, errors.IsNotFound(err))
+	assert.True(t, errors.IsNotFound(store.Delete("change-1")))
+
+	// The provenance refused for a change that has one can still be recorded for another
+	other.ChangeID = "change-3"
+	assert.NoError(t, store.Create(&other))
+
+	// The provenances returned are copies
+	stored, err = store.Get("change-3")
+	assert.NoError(t, err)
+	stored.Artifact = "slice-44"
+	stored, err = store.Get("change-3")
+	assert.NoError(t, err)
+	assert.Equal(t, "slice-43", stored.Artifact)
+
+	assert.EqualError(t, store.Create(&other), "change 'change-3' already has a provenance")
+	assert.EqualError(t, store.Delete("change-1"), "no provenance for change 'change-1'")
+}
@@ -0,0 +1,156 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package push stores the pushes of device changes to their devices, so that a change a device
+// acknowledged is not sent to it again, e.g. after a restart of the controller.
+package push
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// State is the state of a push
+type State string
+
+const (
+	// StateSent is the state of a push sent to a device that did not acknowledge it yet
+	StateSent State = "sent"
+	// StateAcknowledged is the state of a push the device acknowledged
+	StateAcknowledged State = "acknowledged"
+	// StateRejected is the state of a push the device rejected, see Nack
+	StateRejected State = "rejected"
+)
+
+// Category is the normalized category of the rejection of a push by a device
+type Category string
+
+const (
+	// CategorySyntax is a push the device could not parse or validate, e.g. a malformed value
+	CategorySyntax Category = "syntax"
+	// CategoryResource is a push the device has no resources for, or that conflicts with its state
+	CategoryResource Category = "resource"
+	// CategoryAuth is a push the device did not authenticate or authorize
+	CategoryAuth Category = "auth"
+	// CategoryUnsupported is a push of paths or operations the device does not support
+	CategoryUnsupported Category = "unsupported"
+	// CategoryUnknown is a push rejected for any other reason
+	CategoryUnknown Category = "unknown"
+)
+
+// Nack is the rejection of a push by a device
+type Nack struct {
+	// Category is the normalized category of the rejection
+	Category Category `json:"category"`
+	// Code is the gRPC code the device answered with, e.g. InvalidArgument
+	Code string `json:"code"`
+	// Message is the error message of the device, as is
+	Message string `json:"message"`
+	// Path is the gNMI path the device rejected, if it is known
+	Path string `json:"path,omitempty"`
+}
+
+// Push records the last push of a device change to its device
+type Push struct {
+	// DeviceChangeID is the pushed device change
+	DeviceChangeID devicechange.ID `json:"deviceChangeId"`
+	// Key is the idempotency key of the push, see NewKey
+	Key   string `json:"key"`
+	State State  `json:"state"`
+	// Updated is when the push was sent, acknowledged or rejected
+	Updated time.Time `json:"updated"`
+	// Nack is the rejection of the push, if it is rejected
+	Nack *Nack `json:"nack,omitempty"`
+	// Sets is the number of Sets of the series a push is split into, 0 if it is not split
+	Sets int `json:"sets,omitempty"`
+	// AcknowledgedSets is the number of Sets of the series the device acknowledged, in order
+	AcknowledgedSets int `json:"acknowledgedSets,omitempty"`
+}
+
+// NewKey returns the idempotency key of the push of a device change: a device change is pushed
+// once for each incarnation and phase
+func NewKey(deviceChange *devicechange.DeviceChange) string {
+	return fmt.Sprintf("%s/%d/%s", deviceChange.ID, deviceChange.Status.Incarnation, deviceChange.Status.Phase)
+}
+
+// Store stores the pushes of device changes
+type Store interface {
+	io.Closer
+
+	// Get gets the last push of a device change
+	Get(id devicechange.ID) (*Push, error)
+
+	// Put records a push of a device change, replacing any previous push of it
+	Put(push *Push) error
+
+	// Delete deletes the pushes of a device change
+	Delete(id devicechange.ID) error
+}
+
+// kind and notFound describe the pushes in the errors of the store
+const kind = "push"
+
+var notFound = records.WithNotFound("device change '%s' was not pushed")
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	pushes, err := records.NewAtomixMap(client, "onos-config-device-pushes", kind, notFound)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		pushes: pushes,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps pushes in memory
+func NewLocalStore() Store {
+	return &store{
+		pushes: records.NewLocalMap(kind, notFound),
+	}
+}
+
+// store keeps the pushes by device change ID
+type store struct {
+	pushes records.Map
+}
+
+func (s *store) Get(id devicechange.ID) (*Push, error) {
+	push := &Push{}
+	if err := s.pushes.Get(string(id), push); err != nil {
+		return nil, err
+	}
+	return push, nil
+}
+
+func (s *store) Put(push *Push) error {
+	if push.DeviceChangeID == "" {
+		return errors.NewInvalid("no device change ID given")
+	}
+	return s.pushes.Put(string(push.DeviceChangeID), push)
+}
+
+func (s *store) Delete(id devicechange.ID) error {
+	return s.pushes.Delete(string(id))
+}
+
+func (s *store) Close() error {
+	return s.pushes.Close()
+}
@@ -0,0 +1,73 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"testing"
+	"time"
+
+	changetypes "github.com/onosproject/onos-api/go/onos/config/change"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	deviceChange := &devicechange.DeviceChange{
+		ID:     "change-1:device-1:1.0.0",
+		Status: changetypes.Status{Incarnation: 2, Phase: changetypes.Phase_ROLLBACK},
+	}
+	key := NewKey(deviceChange)
+	assert.Equal(t, "change-1:device-1:1.0.0/2/ROLLBACK", key)
+
+	assert.NoError(t, store.Put(&Push{DeviceChangeID: deviceChange.ID, Key: key, State: StateSent, Updated: time.Now()}))
+	assert.True(t, errors.IsInvalid(store.Put(&Push{})))
+	push, err := store.Get(deviceChange.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, key, push.Key)
+	assert.Equal(t, StateSent, push.State)
+
+	assert.NoError(t, store.Put(&Push{DeviceChangeID: deviceChange.ID, Key: key, State: StateAcknowledged}))
+	push, err = store.Get(deviceChange.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StateAcknowledged, push.State)
+	assert.Nil(t, push.Nack)
+
+	nack := &Nack{Category: CategorySyntax, Code: "InvalidArgument", Message: "bad value", Path: "/cont1a/leaf1a"}
+	assert.NoError(t, store.Put(&Push{DeviceChangeID: deviceChange.ID, Key: key, State: StateRejected, Nack: nack}))
+	push, err = store.Get(deviceChange.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StateRejected, push.State)
+	assert.Equal(t, nack, push.Nack)
+
+	assert.NoError(t, store.Delete(deviceChange.ID))
+	_, err = store.Get(deviceChange.ID)
+	assert.True(t, errors.IsNotFound(err))
+	assert.True(t, errors.IsNotFound(store.Delete(deviceChange.ID)))
+
+	// The pushes returned are copies
+	assert.NoError(t, store.Put(&Push{DeviceChangeID: deviceChange.ID, Key: key, State: StateRejected, Nack: nack}))
+	push, err = store.Get(deviceChange.ID)
+	assert.NoError(t, err)
+	push.Nack.Message = "changed"
+	push, err = store.Get(deviceChange.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "bad value", push.Nack.Message)
+
+	assert.EqualError(t, store.Delete("change-2:device-1:1.0.0"), "device change 'change-2:device-1:1.0.0' was not pushed")
+}
@@ -0,0 +1,141 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signature stores the client signatures of network changes alongside the changes themselves.
+//
+// Each signature is stored under the change it is for and under its digest, so that a signed
+// request can only ever create a single change: storing a signature whose digest was already
+// stored fails, which rejects replayed requests.
+package signature
+
+import (
+	"encoding/hex"
+	"io"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// ChangeSignature is the signature a client attached to the Set request that created a network change
+type ChangeSignature struct {
+	// ChangeID is the network change the signature is for
+	ChangeID networkchange.ID `json:"changeId"`
+	// KeyID is the registered key the signature was verified with
+	KeyID string `json:"keyId"`
+	// Timestamp is when the client signed the request
+	Timestamp time.Time `json:"timestamp"`
+	// Nonce is the nonce chosen by the client
+	Nonce string `json:"nonce"`
+	// Signature is the raw signature
+	Signature []byte `json:"signature"`
+	// Digest is the SHA-256 digest of the signed payload
+	Digest []byte `json:"digest"`
+	// User is the name of the caller who made the request
+	User string `json:"user,omitempty"`
+	// Created is when the signature was verified and stored
+	Created time.Time `json:"created"`
+}
+
+// Store stores network change signatures
+type Store interface {
+	io.Closer
+
+	// Get gets the signature of a network change
+	Get(id networkchange.ID) (*ChangeSignature, error)
+
+	// Create stores the signature of a new network change, before the change is created. It fails
+	// with AlreadyExists if the change is already signed or if the same payload was signed before.
+	Create(signature *ChangeSignature) error
+
+	// Delete deletes the signature of a network change that could not be created
+	Delete(id networkchange.ID) error
+}
+
+// kind and notFound describe the signatures in the errors of the store
+const kind = "signature"
+
+var notFound = records.WithNotFound("no signature for change '%s'")
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	signatures, err := records.NewAtomixMap(client, "onos-config-change-signatures", kind, notFound)
+	if err != nil {
+		return nil, err
+	}
+	digests, err := records.NewAtomixMap(client, "onos-config-change-signature-digests", "signed payload")
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		signatures: signatures,
+		digests:    digests,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps signatures in memory
+func NewLocalStore() Store {
+	return &store{
+		signatures: records.NewLocalMap(kind, notFound),
+		digests:    records.NewLocalMap("signed payload"),
+	}
+}
+
+// store keeps the signatures by change
+type store struct {
+	signatures records.Map
+	// digests maps the hex digest of each signed payload to the change it created
+	digests records.Map
+}
+
+func (s *store) Get(id networkchange.ID) (*ChangeSignature, error) {
+	signature := &ChangeSignature{}
+	if err := s.signatures.Get(string(id), signature); err != nil {
+		return nil, err
+	}
+	return signature, nil
+}
+
+func (s *store) Create(signature *ChangeSignature) error {
+	if signature.ChangeID == "" {
+		return errors.NewInvalid("no change ID specified")
+	}
+	digest := hex.EncodeToString(signature.Digest)
+	if err := s.digests.Create(digest, signature.ChangeID); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return errors.NewAlreadyExists("the signed payload %s was already used", digest)
+		}
+		return err
+	}
+	if err := s.signatures.Create(string(signature.ChangeID), signature); err != nil {
+		_ = s.digests.Delete(digest)
+		if errors.IsAlreadyExists(err) {
+			return errors.NewAlreadyExists("change '%s' is already signed", signature.ChangeID)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *store) Delete(id networkchange.ID) error {
+	// The digest is kept, so that the signed payload still cannot be used again
+	return s.signatures.Delete(string(id))
+}
+
+func (s *store) Close() error {
+	_ = s.digests.Close()
+	return s.signatures.Close()
+}
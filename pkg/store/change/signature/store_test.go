@@ -0,0 +1,78 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signature
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	sig := &ChangeSignature{
+		ChangeID:  "change-1",
+		KeyID:     "operator-1",
+		Timestamp: time.Unix(1620000000, 0).UTC(),
+		Nonce:     "nonce-0001",
+		Signature: []byte{1, 2, 3},
+		Digest:    []byte{4, 5, 6},
+		User:      "alice",
+		Created:   time.Unix(1620000001, 0).UTC(),
+	}
+	assert.NoError(t, store.Create(sig))
+
+	stored, err := store.Get("change-1")
+	assert.NoError(t, err)
+	assert.Equal(t, sig, stored)
+
+	// The same payload cannot create another change
+	replay := *sig
+	replay.ChangeID = "change-2"
+	assert.True(t, errors.IsAlreadyExists(store.Create(&replay)))
+	_, err = store.Get("change-2")
+	assert.True(t, errors.IsNotFound(err))
+
+	// A change cannot be signed twice
+	other := *sig
+	other.Digest = []byte{7, 8, 9}
+	assert.True(t, errors.IsAlreadyExists(store.Create(&other)))
+
+	// Deleting the signature of a change that was not created does not allow a replay
+	assert.NoError(t, store.Delete("change-1"))
+	_, err = store.Get("change-1")
+	assert.True(t, errors.IsNotFound(err))
+	assert.True(t, errors.IsNotFound(store.Delete("change-1")))
+	assert.True(t, errors.IsAlreadyExists(store.Create(sig)))
+
+	// The payload refused for a change signed twice can still sign another change
+	other.ChangeID = "change-3"
+	assert.NoError(t, store.Create(&other))
+
+	// The signatures returned are copies
+	stored, err = store.Get("change-3")
+	assert.NoError(t, err)
+	stored.KeyID = "operator-2"
+	stored, err = store.Get("change-3")
+	assert.NoError(t, err)
+	assert.Equal(t, "operator-1", stored.KeyID)
+
+	assert.True(t, errors.IsInvalid(store.Create(&ChangeSignature{Digest: []byte{1}})))
+	assert.EqualError(t, store.Delete("change-4"), "no signature for change 'change-4'")
+}
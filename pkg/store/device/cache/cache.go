@@ -17,6 +17,7 @@ package cache
 import (
 	"fmt"
 	"io"
+	"sort"
 	"sync"
 
 	networkchange "github.com/onosproject/onos-api/go/onos/config/change/network"
@@ -55,6 +56,20 @@ type Cache interface {
 
 	// Watch allows tracking updates of the cache
 	Watch(ch chan<- stream.Event, replay bool) (stream.Context, error)
+
+	// Rebuild reconstructs the cache from the network changes and the device snapshots, e.g. once
+	// it drifted from them after a crash, and returns the discrepancies it fixed
+	Rebuild() (*Repair, error)
+}
+
+// Repair describes the discrepancies fixed by rebuilding the cache
+type Repair struct {
+	// Added are the devices that were missing from the cache
+	Added []*Info
+	// Removed are the devices that no network change or snapshot refers to any more
+	Removed []*Info
+	// Updated are the devices whose type differed from that of the stores
+	Updated []*Info
 }
 
 // NewCache returns a new cache based on the NetworkChange store
@@ -266,6 +281,104 @@ func (c *networkChangeStoreCache) Watch(ch chan<- stream.Event, replay bool) (st
 	}), nil
 }
 
+// Rebuild reconstructs the cache from the network changes and the device snapshots. The cache is
+// locked while the stores are listed, for no event to be applied to the cache in the meantime.
+func (c *networkChangeStoreCache) Rebuild() (*Repair, error) {
+	c.mu.Lock()
+	devices, err := c.listDevices()
+	if err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+
+	repair := &Repair{}
+	events := make([]stream.Event, 0)
+	for key, info := range devices {
+		cached, ok := c.devices[key]
+		if !ok {
+			repair.Added = append(repair.Added, info)
+			events = append(events, stream.Event{Type: stream.Created, Object: info})
+		} else if cached.Type != info.Type {
+			repair.Updated = append(repair.Updated, info)
+			events = append(events, stream.Event{Type: stream.Updated, Object: info})
+		} else {
+			continue
+		}
+		c.devices[key] = info
+	}
+	for key, cached := range c.devices {
+		if _, ok := devices[key]; !ok {
+			repair.Removed = append(repair.Removed, cached)
+			events = append(events, stream.Event{Type: stream.Deleted, Object: cached})
+			delete(c.devices, key)
+		}
+	}
+	log.Infof("Rebuilt cache: %d added, %d removed, %d updated. Size %d",
+		len(repair.Added), len(repair.Removed), len(repair.Updated), len(c.devices))
+	listeners := c.getListeners()
+	c.mu.Unlock()
+
+	for _, event := range events {
+		for _, l := range listeners {
+			if l != nil {
+				l <- event
+			}
+		}
+	}
+	sortInfos(repair.Added)
+	sortInfos(repair.Removed)
+	sortInfos(repair.Updated)
+	return repair, nil
+}
+
+// listDevices lists the devices the network changes and the device snapshots refer to. As when
+// the stores are watched, the type a device is first listed with is kept.
+func (c *networkChangeStoreCache) listDevices() (map[device.VersionedID]*Info, error) {
+	devices := make(map[device.VersionedID]*Info)
+	add := func(id device.ID, deviceType device.Type, version device.Version) {
+		key := device.NewVersionedID(id, version)
+		if _, ok := devices[key]; !ok {
+			devices[key] = &Info{
+				DeviceID: id,
+				Type:     deviceType,
+				Version:  version,
+			}
+		}
+	}
+
+	changeCh := make(chan *networkchange.NetworkChange)
+	ctx, err := c.networkChangeStore.List(changeCh)
+	if err != nil {
+		return nil, err
+	}
+	for netChange := range changeCh {
+		for _, devChange := range netChange.Changes {
+			add(devChange.DeviceID, devChange.DeviceType, devChange.DeviceVersion)
+		}
+	}
+	ctx.Close()
+
+	snapshotCh := make(chan *devicesnapshot.DeviceSnapshot)
+	ssCtx, err := c.deviceSnapshotStore.List(snapshotCh)
+	if err != nil {
+		return nil, err
+	}
+	for snapshot := range snapshotCh {
+		add(snapshot.DeviceID, snapshot.DeviceType, snapshot.DeviceVersion)
+	}
+	ssCtx.Close()
+	return devices, nil
+}
+
+func sortInfos(infos []*Info) {
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].DeviceID != infos[j].DeviceID {
+			return infos[i].DeviceID < infos[j].DeviceID
+		}
+		return infos[i].Version < infos[j].Version
+	})
+}
+
 func (c *networkChangeStoreCache) Close() error {
 	return nil
 }
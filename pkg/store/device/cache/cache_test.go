@@ -285,3 +285,91 @@ func TestDeviceCache(t *testing.T) {
 	// Wait for the test to complete
 	time.Sleep(20 * time.Millisecond)
 }
+
+func TestDeviceCacheRebuild(t *testing.T) {
+	chNwChangesVal := &atomic.Value{}
+	ctrl := gomock.NewController(t)
+	netChangeStore := store.NewMockNetworkChangesStore(ctrl)
+	netChangeStore.EXPECT().Watch(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ch chan<- stream.Event, opts ...networkchangestore.WatchOption) (stream.Context, error) {
+			chNwChangesVal.Store(ch)
+			return stream.NewContext(func() {}), nil
+		})
+	netChangeStore.EXPECT().List(gomock.Any()).DoAndReturn(
+		func(ch chan<- *networkchange.NetworkChange) (stream.Context, error) {
+			go func() {
+				ch <- &networkchange.NetworkChange{
+					ID: "network-change-2",
+					Changes: []*devicechange.Change{
+						{DeviceID: "device-1", DeviceType: "Devicesim", DeviceVersion: "1.0.0"},
+						{DeviceID: "device-3", DeviceType: "Stratum", DeviceVersion: "1.0.0"},
+					},
+				}
+				close(ch)
+			}()
+			return stream.NewContext(func() {}), nil
+		})
+	devSnapshotStore := store.NewMockDeviceSnapshotStore(ctrl)
+	devSnapshotStore.EXPECT().Watch(gomock.Any()).Return(stream.NewContext(func() {}), nil)
+	devSnapshotStore.EXPECT().List(gomock.Any()).DoAndReturn(
+		func(ch chan<- *devicesnapshot.DeviceSnapshot) (stream.Context, error) {
+			go func() {
+				ch <- &devicesnapshot.DeviceSnapshot{
+					DeviceID:      "device-4",
+					DeviceType:    "Stratum",
+					DeviceVersion: "2.0.0",
+				}
+				// Devices are kept with the type they are first listed with
+				ch <- &devicesnapshot.DeviceSnapshot{
+					DeviceID:      "device-3",
+					DeviceType:    "Devicesim",
+					DeviceVersion: "1.0.0",
+				}
+				close(ch)
+			}()
+			return stream.NewContext(func() {}), nil
+		})
+
+	cache, err := NewCache(netChangeStore, devSnapshotStore)
+	assert.NoError(t, err)
+	chNwChangesVal.Load().(chan<- stream.Event) <- stream.Event{
+		Type: stream.Created,
+		Object: &networkchange.NetworkChange{
+			ID: "network-change-1",
+			Changes: []*devicechange.Change{
+				{DeviceID: "device-1", DeviceType: "Stratum", DeviceVersion: "1.0.0"},
+				{DeviceID: "device-2", DeviceType: "Stratum", DeviceVersion: "1.0.0"},
+			},
+		},
+	}
+	assert.Eventually(t, func() bool {
+		return len(cache.GetDevices()) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	cacheCh := make(chan stream.Event, 10)
+	cacheCtx, err := cache.Watch(cacheCh, false)
+	assert.NoError(t, err)
+	defer cacheCtx.Close()
+
+	repair, err := cache.Rebuild()
+	assert.NoError(t, err)
+	assert.Len(t, repair.Added, 2)
+	assert.Equal(t, devicebase.ID("device-3"), repair.Added[0].DeviceID)
+	assert.Equal(t, devicebase.Type("Stratum"), repair.Added[0].Type)
+	assert.Equal(t, devicebase.ID("device-4"), repair.Added[1].DeviceID)
+	assert.Len(t, repair.Removed, 1)
+	assert.Equal(t, devicebase.ID("device-2"), repair.Removed[0].DeviceID)
+	assert.Len(t, repair.Updated, 1)
+	assert.Equal(t, devicebase.ID("device-1"), repair.Updated[0].DeviceID)
+	assert.Equal(t, devicebase.Type("Devicesim"), repair.Updated[0].Type)
+
+	assert.Len(t, cache.GetDevices(), 3)
+	assert.Len(t, cache.GetDevicesByID("device-2"), 0)
+	assert.Len(t, cache.GetDevicesByType("Devicesim"), 1)
+
+	events := make(map[stream.EventType]int)
+	for i := 0; i < 4; i++ {
+		events[(<-cacheCh).Type]++
+	}
+	assert.Equal(t, map[stream.EventType]int{stream.Created: 2, stream.Deleted: 1, stream.Updated: 1}, events)
+}
@@ -17,6 +17,7 @@ package device
 import (
 	"context"
 	"fmt"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"io"
 	"time"
@@ -79,12 +80,41 @@ func (s *topoStore) Get(id device.ID) (*device.Device, error) {
 		ID: topo.ID(id),
 	})
 	if err != nil {
+		if chassisID, target, ok := device.ParseSubTargetID(id); ok && errors.IsNotFound(errors.FromGRPC(err)) {
+			return s.getSubTarget(chassisID, target, err)
+		}
 		return nil, err
 	}
 	return device.ToDevice(response.Object)
 }
 
+// getSubTarget gets the sub-target of a device for one of its targets, returning notFound if the
+// device has no such target
+func (s *topoStore) getSubTarget(chassisID device.ID, target string, notFound error) (*device.Device, error) {
+	chassis, err := s.Get(chassisID)
+	if err != nil {
+		return nil, notFound
+	}
+	subTarget := chassis.SubTarget(target)
+	if subTarget == nil {
+		return nil, notFound
+	}
+	return subTarget, nil
+}
+
 func (s *topoStore) Update(updatedDevice *device.Device) (*device.Device, error) {
+	// A sub-target has no topo entity; it follows the model of its device
+	if updatedDevice.Chassis != "" {
+		chassis, err := s.Get(updatedDevice.Chassis)
+		if err != nil {
+			return nil, err
+		}
+		if chassis.Version != updatedDevice.Version || chassis.Type != updatedDevice.Type {
+			return nil, errors.NewInvalid("%s is a target of %s and is bound to the model of %s",
+				updatedDevice.ID, updatedDevice.Chassis, updatedDevice.Chassis)
+		}
+		return updatedDevice, nil
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 	updateReq := &topo.UpdateRequest{
@@ -110,7 +140,9 @@ func (s *topoStore) List(ch chan<- *device.Device) error {
 				log.Warnf("Ignoring Topo object. %s", err.Error())
 				continue
 			}
-			ch <- configDevice
+			for _, d := range device.ExpandSubTargets(configDevice) {
+				ch <- d
+			}
 		}
 	}()
 	return nil
@@ -124,6 +156,8 @@ func (s *topoStore) Watch(ch chan<- *device.ListResponse) error {
 		return err
 	}
 	go func() {
+		// subTargets are the sub-targets of each device as of its last event
+		subTargets := make(map[device.ID][]*device.Device)
 		for {
 			resp, err := stream.Recv()
 			if err == io.EOF {
@@ -138,9 +172,14 @@ func (s *topoStore) Watch(ch chan<- *device.ListResponse) error {
 					log.Warnf("Ignoring Topo event. %s +v+", err.Error(), resp.Event)
 					continue
 				}
+				eventType := toEventType(resp.Event.Type)
 				ch <- &device.ListResponse{
 					Device: configDevice,
-					Type:   toEventType(resp.Event.Type),
+					Type:   eventType,
+				}
+				subTargets[configDevice.ID] = sendSubTargetEvents(ch, configDevice, eventType, subTargets[configDevice.ID])
+				if len(subTargets[configDevice.ID]) == 0 {
+					delete(subTargets, configDevice.ID)
 				}
 			} else {
 				log.Warnf("Ignoring non ENTITY Topo event %v+", resp.Event)
@@ -150,6 +189,41 @@ func (s *topoStore) Watch(ch chan<- *device.ListResponse) error {
 	return nil
 }
 
+// sendSubTargetEvents sends the events of the sub-targets of a device for an event of the device,
+// given its sub-targets as of its previous event, and returns its current sub-targets. A sub-target
+// no longer named by the device is removed.
+func sendSubTargetEvents(ch chan<- *device.ListResponse, chassis *device.Device, eventType device.ListResponseType,
+	previous []*device.Device) []*device.Device {
+	var current []*device.Device
+	if eventType != device.ListResponseREMOVED {
+		current = device.ExpandSubTargets(chassis)[1:]
+	} else if previous == nil {
+		previous = device.ExpandSubTargets(chassis)[1:]
+	}
+	for _, subTarget := range current {
+		subTargetEventType := eventType
+		if eventType == device.ListResponseUPDATED && !containsDevice(previous, subTarget.ID) {
+			subTargetEventType = device.ListResponseADDED
+		}
+		ch <- &device.ListResponse{Device: subTarget, Type: subTargetEventType}
+	}
+	for _, subTarget := range previous {
+		if !containsDevice(current, subTarget.ID) {
+			ch <- &device.ListResponse{Device: subTarget, Type: device.ListResponseREMOVED}
+		}
+	}
+	return current
+}
+
+func containsDevice(devices []*device.Device, id device.ID) bool {
+	for _, d := range devices {
+		if d.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
 func toEventType(et topo.EventType) device.ListResponseType {
 	if et == topo.EventType_ADDED {
 		return device.ListResponseADDED
@@ -19,6 +19,7 @@ import (
 	"github.com/onosproject/onos-api/go/onos/topo"
 	topodevice "github.com/onosproject/onos-config/pkg/device"
 	"github.com/onosproject/onos-config/pkg/test/mocks"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -141,6 +142,87 @@ func TestUpdateDevice(t *testing.T) {
 
 }
 
+func TestSubTargets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	chassis := &topodevice.Device{
+		ID:         device1ID,
+		Revision:   1,
+		Address:    device1Addr,
+		Version:    v1,
+		Type:       stratumType,
+		SubTargets: []string{"member-1", "member-2"},
+	}
+
+	client := mocks.NewMockTopoClient(ctrl)
+	client.EXPECT().List(gomock.Any(), gomock.Any()).Return(&topo.ListResponse{
+		Objects: []topo.Object{*topodevice.ToObject(chassis)},
+	}, nil)
+	client.EXPECT().Get(gomock.Any(), &topo.GetRequest{ID: "device-1/member-2"}).
+		Return(nil, errors.Status(errors.NewNotFound("not found")).Err()).AnyTimes()
+	client.EXPECT().Get(gomock.Any(), &topo.GetRequest{ID: "device-1/member-3"}).
+		Return(nil, errors.Status(errors.NewNotFound("not found")).Err())
+	client.EXPECT().Get(gomock.Any(), &topo.GetRequest{ID: topo.ID(device1ID)}).
+		Return(&topo.GetResponse{Object: topodevice.ToObject(chassis)}, nil).AnyTimes()
+
+	store := topoStore{
+		client: client,
+	}
+
+	// The sub-targets are listed after their device
+	ch := make(chan *topodevice.Device)
+	assert.NoError(t, store.List(ch))
+	assert.Equal(t, device1ID, nextDevice(t, ch).ID)
+	assert.Equal(t, topodevice.ID("device-1/member-1"), nextDevice(t, ch).ID)
+	assert.Equal(t, topodevice.ID("device-1/member-2"), nextDevice(t, ch).ID)
+
+	member, err := store.Get("device-1/member-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "member-2", member.Target)
+	assert.Equal(t, device1ID, member.Chassis)
+	_, err = store.Get("device-1/member-3")
+	assert.True(t, errors.IsNotFound(errors.FromGRPC(err)))
+
+	// A sub-target is not stored, and follows the model of its device
+	updated, err := store.Update(member)
+	assert.NoError(t, err)
+	assert.Equal(t, member, updated)
+	member.Version = "2.0.0"
+	_, err = store.Update(member)
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestSubTargetEvents(t *testing.T) {
+	chassis := &topodevice.Device{
+		ID:         device1ID,
+		SubTargets: []string{"member-1", "member-2"},
+	}
+	ch := make(chan *topodevice.ListResponse, 10)
+	previous := sendSubTargetEvents(ch, chassis, topodevice.ListResponseADDED, nil)
+	assert.Len(t, previous, 2)
+	assert.Equal(t, topodevice.ListResponseADDED, (<-ch).Type)
+	assert.Equal(t, topodevice.ListResponseADDED, (<-ch).Type)
+
+	// A target added is added, a target no longer named is removed
+	chassis.SubTargets = []string{"member-2", "member-3"}
+	previous = sendSubTargetEvents(ch, chassis, topodevice.ListResponseUPDATED, previous)
+	event := <-ch
+	assert.Equal(t, topodevice.ID("device-1/member-2"), event.Device.ID)
+	assert.Equal(t, topodevice.ListResponseUPDATED, event.Type)
+	event = <-ch
+	assert.Equal(t, topodevice.ID("device-1/member-3"), event.Device.ID)
+	assert.Equal(t, topodevice.ListResponseADDED, event.Type)
+	event = <-ch
+	assert.Equal(t, topodevice.ID("device-1/member-1"), event.Device.ID)
+	assert.Equal(t, topodevice.ListResponseREMOVED, event.Type)
+
+	previous = sendSubTargetEvents(ch, chassis, topodevice.ListResponseREMOVED, previous)
+	assert.Empty(t, previous)
+	assert.Equal(t, topodevice.ListResponseREMOVED, (<-ch).Type)
+	assert.Equal(t, topodevice.ListResponseREMOVED, (<-ch).Type)
+	assert.Empty(t, ch)
+}
+
 func nextDevice(t *testing.T, ch chan *topodevice.Device) *topodevice.Device {
 	select {
 	case d := <-ch:
@@ -0,0 +1,323 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	"github.com/atomix/atomix-go-client/pkg/atomix/election"
+	"github.com/atomix/atomix-go-client/pkg/atomix/indexedmap"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+)
+
+// WrapClient returns an Atomix client whose maps, indexed maps and elections record the stats of
+// their operations
+func WrapClient(client atomix.Client) atomix.Client {
+	return &healthClient{Client: client}
+}
+
+type healthClient struct {
+	atomix.Client
+}
+
+func (c *healthClient) GetMap(ctx context.Context, name string, opts ...primitive.Option) (_map.Map, error) {
+	start := time.Now()
+	m, err := c.Client.GetMap(ctx, name, opts...)
+	observe(name, _map.Type, "Open", start, err)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := &healthMap{Map: m}
+	register(name, wrapped, func(ctx context.Context) error {
+		_, err := wrapped.Len(ctx)
+		return err
+	})
+	return wrapped, nil
+}
+
+func (c *healthClient) GetIndexedMap(ctx context.Context, name string, opts ...primitive.Option) (indexedmap.IndexedMap, error) {
+	start := time.Now()
+	m, err := c.Client.GetIndexedMap(ctx, name, opts...)
+	observe(name, indexedmap.Type, "Open", start, err)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := &healthIndexedMap{IndexedMap: m}
+	register(name, wrapped, func(ctx context.Context) error {
+		_, err := wrapped.Len(ctx)
+		return err
+	})
+	return wrapped, nil
+}
+
+func (c *healthClient) GetElection(ctx context.Context, name string, opts ...primitive.Option) (election.Election, error) {
+	start := time.Now()
+	e, err := c.Client.GetElection(ctx, name, opts...)
+	observe(name, election.Type, "Open", start, err)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := &healthElection{Election: e}
+	register(name, wrapped, func(ctx context.Context) error {
+		_, err := wrapped.GetTerm(ctx)
+		return err
+	})
+	return wrapped, nil
+}
+
+type healthMap struct {
+	_map.Map
+}
+
+func (m *healthMap) observe(operation string, start time.Time, err error) {
+	observe(m.Name(), m.Type(), operation, start, err)
+}
+
+func (m *healthMap) Put(ctx context.Context, key string, value []byte, opts ..._map.PutOption) (*_map.Entry, error) {
+	start := time.Now()
+	entry, err := m.Map.Put(ctx, key, value, opts...)
+	m.observe("Put", start, err)
+	return entry, err
+}
+
+func (m *healthMap) Get(ctx context.Context, key string, opts ..._map.GetOption) (*_map.Entry, error) {
+	start := time.Now()
+	entry, err := m.Map.Get(ctx, key, opts...)
+	m.observe("Get", start, err)
+	return entry, err
+}
+
+func (m *healthMap) Remove(ctx context.Context, key string, opts ..._map.RemoveOption) (*_map.Entry, error) {
+	start := time.Now()
+	entry, err := m.Map.Remove(ctx, key, opts...)
+	m.observe("Remove", start, err)
+	return entry, err
+}
+
+func (m *healthMap) Len(ctx context.Context) (int, error) {
+	start := time.Now()
+	n, err := m.Map.Len(ctx)
+	m.observe("Len", start, err)
+	return n, err
+}
+
+func (m *healthMap) Clear(ctx context.Context) error {
+	start := time.Now()
+	err := m.Map.Clear(ctx)
+	m.observe("Clear", start, err)
+	return err
+}
+
+func (m *healthMap) Entries(ctx context.Context, ch chan<- _map.Entry) error {
+	start := time.Now()
+	err := m.Map.Entries(ctx, ch)
+	m.observe("Entries", start, err)
+	return err
+}
+
+func (m *healthMap) Watch(ctx context.Context, ch chan<- _map.Event, opts ..._map.WatchOption) error {
+	start := time.Now()
+	err := m.Map.Watch(ctx, ch, opts...)
+	m.observe("Watch", start, err)
+	return err
+}
+
+func (m *healthMap) Close(ctx context.Context) error {
+	unregister(m.Name(), m)
+	return m.Map.Close(ctx)
+}
+
+type healthIndexedMap struct {
+	indexedmap.IndexedMap
+}
+
+func (m *healthIndexedMap) observe(operation string, start time.Time, err error) {
+	observe(m.Name(), m.Type(), operation, start, err)
+}
+
+func (m *healthIndexedMap) Append(ctx context.Context, key string, value []byte) (*indexedmap.Entry, error) {
+	start := time.Now()
+	entry, err := m.IndexedMap.Append(ctx, key, value)
+	m.observe("Append", start, err)
+	return entry, err
+}
+
+func (m *healthIndexedMap) Put(ctx context.Context, key string, value []byte) (*indexedmap.Entry, error) {
+	start := time.Now()
+	entry, err := m.IndexedMap.Put(ctx, key, value)
+	m.observe("Put", start, err)
+	return entry, err
+}
+
+func (m *healthIndexedMap) Set(ctx context.Context, index indexedmap.Index, key string, value []byte, opts ...indexedmap.SetOption) (*indexedmap.Entry, error) {
+	start := time.Now()
+	entry, err := m.IndexedMap.Set(ctx, index, key, value, opts...)
+	m.observe("Set", start, err)
+	return entry, err
+}
+
+func (m *healthIndexedMap) Get(ctx context.Context, key string, opts ...indexedmap.GetOption) (*indexedmap.Entry, error) {
+	start := time.Now()
+	entry, err := m.IndexedMap.Get(ctx, key, opts...)
+	m.observe("Get", start, err)
+	return entry, err
+}
+
+func (m *healthIndexedMap) GetIndex(ctx context.Context, index indexedmap.Index, opts ...indexedmap.GetOption) (*indexedmap.Entry, error) {
+	start := time.Now()
+	entry, err := m.IndexedMap.GetIndex(ctx, index, opts...)
+	m.observe("GetIndex", start, err)
+	return entry, err
+}
+
+func (m *healthIndexedMap) FirstEntry(ctx context.Context) (*indexedmap.Entry, error) {
+	start := time.Now()
+	entry, err := m.IndexedMap.FirstEntry(ctx)
+	m.observe("FirstEntry", start, err)
+	return entry, err
+}
+
+func (m *healthIndexedMap) LastEntry(ctx context.Context) (*indexedmap.Entry, error) {
+	start := time.Now()
+	entry, err := m.IndexedMap.LastEntry(ctx)
+	m.observe("LastEntry", start, err)
+	return entry, err
+}
+
+func (m *healthIndexedMap) PrevEntry(ctx context.Context, index indexedmap.Index) (*indexedmap.Entry, error) {
+	start := time.Now()
+	entry, err := m.IndexedMap.PrevEntry(ctx, index)
+	m.observe("PrevEntry", start, err)
+	return entry, err
+}
+
+func (m *healthIndexedMap) NextEntry(ctx context.Context, index indexedmap.Index) (*indexedmap.Entry, error) {
+	start := time.Now()
+	entry, err := m.IndexedMap.NextEntry(ctx, index)
+	m.observe("NextEntry", start, err)
+	return entry, err
+}
+
+func (m *healthIndexedMap) Remove(ctx context.Context, key string, opts ...indexedmap.RemoveOption) (*indexedmap.Entry, error) {
+	start := time.Now()
+	entry, err := m.IndexedMap.Remove(ctx, key, opts...)
+	m.observe("Remove", start, err)
+	return entry, err
+}
+
+func (m *healthIndexedMap) RemoveIndex(ctx context.Context, index indexedmap.Index, opts ...indexedmap.RemoveOption) (*indexedmap.Entry, error) {
+	start := time.Now()
+	entry, err := m.IndexedMap.RemoveIndex(ctx, index, opts...)
+	m.observe("RemoveIndex", start, err)
+	return entry, err
+}
+
+func (m *healthIndexedMap) Len(ctx context.Context) (int, error) {
+	start := time.Now()
+	n, err := m.IndexedMap.Len(ctx)
+	m.observe("Len", start, err)
+	return n, err
+}
+
+func (m *healthIndexedMap) Clear(ctx context.Context) error {
+	start := time.Now()
+	err := m.IndexedMap.Clear(ctx)
+	m.observe("Clear", start, err)
+	return err
+}
+
+func (m *healthIndexedMap) Entries(ctx context.Context, ch chan<- indexedmap.Entry) error {
+	start := time.Now()
+	err := m.IndexedMap.Entries(ctx, ch)
+	m.observe("Entries", start, err)
+	return err
+}
+
+func (m *healthIndexedMap) Watch(ctx context.Context, ch chan<- indexedmap.Event, opts ...indexedmap.WatchOption) error {
+	start := time.Now()
+	err := m.IndexedMap.Watch(ctx, ch, opts...)
+	m.observe("Watch", start, err)
+	return err
+}
+
+func (m *healthIndexedMap) Close(ctx context.Context) error {
+	unregister(m.Name(), m)
+	return m.IndexedMap.Close(ctx)
+}
+
+type healthElection struct {
+	election.Election
+}
+
+func (e *healthElection) observe(operation string, start time.Time, err error) {
+	observe(e.Name(), e.Type(), operation, start, err)
+}
+
+func (e *healthElection) GetTerm(ctx context.Context) (*election.Term, error) {
+	start := time.Now()
+	term, err := e.Election.GetTerm(ctx)
+	e.observe("GetTerm", start, err)
+	return term, err
+}
+
+func (e *healthElection) Enter(ctx context.Context) (*election.Term, error) {
+	start := time.Now()
+	term, err := e.Election.Enter(ctx)
+	e.observe("Enter", start, err)
+	return term, err
+}
+
+func (e *healthElection) Leave(ctx context.Context) (*election.Term, error) {
+	start := time.Now()
+	term, err := e.Election.Leave(ctx)
+	e.observe("Leave", start, err)
+	return term, err
+}
+
+func (e *healthElection) Anoint(ctx context.Context, id string) (*election.Term, error) {
+	start := time.Now()
+	term, err := e.Election.Anoint(ctx, id)
+	e.observe("Anoint", start, err)
+	return term, err
+}
+
+func (e *healthElection) Promote(ctx context.Context, id string) (*election.Term, error) {
+	start := time.Now()
+	term, err := e.Election.Promote(ctx, id)
+	e.observe("Promote", start, err)
+	return term, err
+}
+
+func (e *healthElection) Evict(ctx context.Context, id string) (*election.Term, error) {
+	start := time.Now()
+	term, err := e.Election.Evict(ctx, id)
+	e.observe("Evict", start, err)
+	return term, err
+}
+
+func (e *healthElection) Watch(ctx context.Context, ch chan<- election.Event) error {
+	start := time.Now()
+	err := e.Election.Watch(ctx, ch)
+	e.observe("Watch", start, err)
+	return err
+}
+
+func (e *healthElection) Close(ctx context.Context) error {
+	unregister(e.Name(), e)
+	return e.Election.Close(ctx)
+}
@@ -0,0 +1,188 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package health records the latencies and the failures of the operations of the Atomix primitives
+// the stores are built on, to tell which primitives of the cluster are unhealthy.
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var log = logging.GetLogger("store", "health")
+
+// latencyWeight is the weight of the latest operation in the moving average of the latency
+const latencyWeight = 0.2
+
+// probeTimeout is how long a probe of a primitive may take before it is failed
+const probeTimeout = 5 * time.Second
+
+var (
+	operationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "onos_config_store_operation_seconds",
+		Help:    "Latency of the operations of the Atomix primitives of the stores",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"primitive", "operation"})
+	operationErrorCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "onos_config_store_operation_errors_total",
+		Help: "Number of the operations of the Atomix primitives of the stores that failed",
+	}, []string{"primitive", "operation"})
+	primitiveHealthyGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "onos_config_store_primitive_healthy",
+		Help: "Whether the last operation of an Atomix primitive of the stores succeeded (1) or failed (0)",
+	}, []string{"primitive", "type"})
+)
+
+func init() {
+	prometheus.MustRegister(operationHistogram, operationErrorCounter, primitiveHealthyGauge)
+}
+
+// Stats are the stats of the operations of a primitive
+type Stats struct {
+	// Primitive is the name of the primitive; the instances of a primitive partitioned by cluster keys
+	// share a name and their operations are counted together
+	Primitive string
+	Type      primitive.Type
+	// Operations is the number of the operations of the primitive
+	Operations uint64
+	// Errors is the number of the operations of the primitive that failed
+	Errors uint64
+	// Latency is the moving average of the latency of the operations of the primitive
+	Latency         time.Duration
+	LastError       string
+	LastErrorTime   time.Time
+	LastSuccessTime time.Time
+}
+
+// Healthy returns whether no operation of the primitive failed since the last that succeeded
+func (s Stats) Healthy() bool {
+	return s.LastErrorTime.IsZero() || s.LastSuccessTime.After(s.LastErrorTime)
+}
+
+// probe is the probe of the last instance of a primitive that was opened
+type probe struct {
+	instance interface{}
+	check    func(ctx context.Context) error
+}
+
+var (
+	stats  = make(map[string]*Stats)
+	probes = make(map[string]probe)
+	mu     sync.RWMutex
+)
+
+// isFailure returns whether an error means the primitive is unhealthy, rather than being the
+// answer of a healthy primitive to the operation, e.g. a key that is not found
+func isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	err = errors.FromAtomix(err)
+	return !errors.IsNotFound(err) && !errors.IsAlreadyExists(err) && !errors.IsConflict(err) &&
+		!errors.IsInvalid(err) && !errors.IsCanceled(err) && err != context.Canceled
+}
+
+// observe records an operation of a primitive started at the given time
+func observe(name string, primitiveType primitive.Type, operation string, start time.Time, err error) {
+	now := time.Now()
+	latency := now.Sub(start)
+	operationHistogram.WithLabelValues(name, operation).Observe(latency.Seconds())
+
+	mu.Lock()
+	defer mu.Unlock()
+	s, ok := stats[name]
+	if !ok {
+		s = &Stats{Primitive: name, Type: primitiveType, Latency: latency}
+		stats[name] = s
+	}
+	s.Operations++
+	s.Latency += time.Duration(latencyWeight * float64(latency-s.Latency))
+	if isFailure(err) {
+		s.Errors++
+		s.LastError = err.Error()
+		s.LastErrorTime = now
+		operationErrorCounter.WithLabelValues(name, operation).Inc()
+		primitiveHealthyGauge.WithLabelValues(name, string(primitiveType)).Set(0)
+		log.Warnf("Operation %s of the primitive %s failed: %v", operation, name, err)
+		return
+	}
+	s.LastSuccessTime = now
+	primitiveHealthyGauge.WithLabelValues(name, string(primitiveType)).Set(1)
+}
+
+// register registers the probe of an instance of a primitive
+func register(name string, instance interface{}, check func(ctx context.Context) error) {
+	mu.Lock()
+	defer mu.Unlock()
+	probes[name] = probe{instance: instance, check: check}
+}
+
+// unregister unregisters the probe of an instance of a primitive once it is closed
+func unregister(name string, instance interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+	if p, ok := probes[name]; ok && p.instance == instance {
+		delete(probes, name)
+	}
+}
+
+// GetStats returns the stats of the primitives, by name
+func GetStats() []Stats {
+	mu.RLock()
+	defer mu.RUnlock()
+	list := make([]Stats, 0, len(stats))
+	for _, s := range stats {
+		list = append(list, *s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Primitive < list[j].Primitive
+	})
+	return list
+}
+
+// Probe runs a read-only operation on each open primitive, for the failures of the primitives
+// that are not used to be seen, and their recovery to be seen once they are
+func Probe() {
+	mu.RLock()
+	checks := make([]func(ctx context.Context) error, 0, len(probes))
+	for _, p := range probes {
+		checks = append(checks, p.check)
+	}
+	mu.RUnlock()
+
+	for _, check := range checks {
+		ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+		_ = check(ctx)
+		cancel()
+	}
+}
+
+// StartProbe probes the open primitives at the given interval
+func StartProbe(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			Probe()
+		}
+	}()
+}
@@ -0,0 +1,90 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func getStats(t *testing.T, name string) Stats {
+	for _, s := range GetStats() {
+		if s.Primitive == name {
+			return s
+		}
+	}
+	t.Fatalf("no stats of the primitive %s", name)
+	return Stats{}
+}
+
+func TestWrapClient(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+	atomixClient, err := test.NewClient("test")
+	assert.NoError(t, err)
+	client := WrapClient(atomixClient)
+
+	ctx := context.Background()
+	m, err := client.GetMap(ctx, "test-health-map")
+	assert.NoError(t, err)
+	_, err = m.Put(ctx, "foo", []byte("bar"))
+	assert.NoError(t, err)
+	_, err = m.Get(ctx, "foo")
+	assert.NoError(t, err)
+
+	// A key that is not found is no failure of the primitive
+	_, err = m.Get(ctx, "baz")
+	assert.Error(t, err)
+	s := getStats(t, "test-health-map")
+	assert.Equal(t, uint64(4), s.Operations)
+	assert.Equal(t, uint64(0), s.Errors)
+	assert.True(t, s.Healthy())
+	assert.True(t, s.Latency > 0)
+
+	// The probe reads the open primitives, and no longer once they are closed
+	Probe()
+	assert.Equal(t, uint64(5), getStats(t, "test-health-map").Operations)
+	assert.NoError(t, m.Close(ctx))
+	Probe()
+	assert.Equal(t, uint64(5), getStats(t, "test-health-map").Operations)
+
+	e, err := client.GetElection(ctx, "test-health-election")
+	assert.NoError(t, err)
+	_, err = e.Enter(ctx)
+	assert.NoError(t, err)
+	s = getStats(t, "test-health-election")
+	assert.Equal(t, uint64(2), s.Operations)
+	assert.True(t, s.Healthy())
+
+	// An unavailable primitive is unhealthy until one of its operations succeeds again
+	observe("test-health-election", e.Type(), "GetTerm", time.Now(), errors.NewUnavailable("partition unavailable"))
+	s = getStats(t, "test-health-election")
+	assert.Equal(t, uint64(1), s.Errors)
+	assert.False(t, s.Healthy())
+	assert.Contains(t, s.LastError, "partition unavailable")
+	_, err = e.GetTerm(ctx)
+	assert.NoError(t, err)
+	assert.True(t, getStats(t, "test-health-election").Healthy())
+}
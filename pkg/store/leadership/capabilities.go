@@ -0,0 +1,118 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leadership
+
+import (
+	"sort"
+
+	"github.com/onosproject/onos-config/pkg/store/schema"
+)
+
+// Feature is a behavior that is only enabled once every node of the cluster supports it
+type Feature string
+
+const (
+	// MaintenanceMode is the read-only maintenance mode; the nodes that do not support it would
+	// keep accepting writes
+	MaintenanceMode Feature = "maintenance-mode"
+	// StorageEncryption is the encryption of the records at rest; the nodes that do not support it
+	// would fail to read the encrypted records
+	StorageEncryption Feature = "storage-encryption"
+)
+
+// features are the features supported by this release
+var features = []Feature{MaintenanceMode, StorageEncryption}
+
+// Capabilities are the record schema versions and the features supported by a node, or by every
+// node of the cluster
+type Capabilities struct {
+	// Schemas are the highest schema versions read, by record kind
+	Schemas map[schema.Kind]schema.Version `json:"schemas,omitempty"`
+
+	// Features are the supported features
+	Features []Feature `json:"features,omitempty"`
+}
+
+// Supports returns whether a feature is supported
+func (c Capabilities) Supports(feature Feature) bool {
+	for _, f := range c.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// equal returns whether two sets of capabilities are the same
+func (c Capabilities) equal(other Capabilities) bool {
+	if len(c.Schemas) != len(other.Schemas) || len(c.Features) != len(other.Features) {
+		return false
+	}
+	for kind, version := range c.Schemas {
+		if otherVersion, ok := other.Schemas[kind]; !ok || otherVersion != version {
+			return false
+		}
+	}
+	for _, feature := range c.Features {
+		if !other.Supports(feature) {
+			return false
+		}
+	}
+	return true
+}
+
+// LocalCapabilities returns the capabilities of the local node
+func LocalCapabilities() Capabilities {
+	schemas := make(map[schema.Kind]schema.Version)
+	for _, kind := range schema.Kinds {
+		schemas[kind] = schema.CurrentVersion(kind)
+	}
+	return Capabilities{
+		Schemas:  schemas,
+		Features: append([]Feature{}, features...),
+	}
+}
+
+// Negotiate returns the capabilities supported by the local node and every other node: the lowest
+// version of each kind of record known to the local node, and the features every node supports.
+// A node that does not advertise its capabilities, i.e. that runs a release older than capability
+// negotiation, reads unversioned records and supports no feature.
+func Negotiate(local Capabilities, nodes ...Capabilities) Capabilities {
+	negotiated := Capabilities{
+		Schemas:  make(map[schema.Kind]schema.Version),
+		Features: make([]Feature, 0, len(local.Features)),
+	}
+	for kind, version := range local.Schemas {
+		for _, node := range nodes {
+			if nodeVersion := node.Schemas[kind]; nodeVersion < version {
+				version = nodeVersion
+			}
+		}
+		negotiated.Schemas[kind] = version
+	}
+	for _, feature := range local.Features {
+		supported := true
+		for _, node := range nodes {
+			supported = supported && node.Supports(feature)
+		}
+		if supported {
+			negotiated.Features = append(negotiated.Features, feature)
+		}
+	}
+	sort.Slice(negotiated.Features, func(i, j int) bool {
+		return negotiated.Features[i] < negotiated.Features[j]
+	})
+	return negotiated
+}
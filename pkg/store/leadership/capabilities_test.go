@@ -0,0 +1,52 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leadership
+
+import (
+	"testing"
+
+	"github.com/onosproject/onos-config/pkg/store/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	local := Capabilities{
+		Schemas: map[schema.Kind]schema.Version{
+			schema.NetworkChange: 3,
+			schema.DeviceChange:  2,
+		},
+		Features: []Feature{MaintenanceMode, "future"},
+	}
+	assert.True(t, Negotiate(local).equal(local))
+
+	older := Capabilities{
+		Schemas: map[schema.Kind]schema.Version{
+			schema.NetworkChange: 2,
+			schema.DeviceChange:  2,
+			"Unknown":            7,
+		},
+		Features: []Feature{MaintenanceMode},
+	}
+	negotiated := Negotiate(local, older)
+	assert.Equal(t, map[schema.Kind]schema.Version{schema.NetworkChange: 2, schema.DeviceChange: 2}, negotiated.Schemas)
+	assert.Equal(t, []Feature{MaintenanceMode}, negotiated.Features)
+	assert.False(t, negotiated.Supports("future"))
+
+	// A node that does not advertise its capabilities reads unversioned records only
+	negotiated = Negotiate(local, older, Capabilities{})
+	assert.Equal(t, map[schema.Kind]schema.Version{schema.NetworkChange: 0, schema.DeviceChange: 0}, negotiated.Schemas)
+	assert.Empty(t, negotiated.Features)
+	assert.False(t, negotiated.equal(local))
+}
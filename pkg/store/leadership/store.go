@@ -16,16 +16,21 @@ package leadership
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"io"
 	"sync"
 	"time"
 
 	"github.com/atomix/atomix-go-client/pkg/atomix"
 	"github.com/atomix/atomix-go-client/pkg/atomix/election"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
 	"github.com/onosproject/onos-lib-go/pkg/cluster"
 )
 
+var log = logging.GetLogger("store", "leadership")
+
 // Term is a monotonically increasing leadership term
 type Term uint64
 
@@ -39,6 +44,12 @@ type Store interface {
 	// IsLeader returns a boolean indicating whether the local node is the leader
 	IsLeader() (bool, error)
 
+	// GetLeadership returns the current leadership term
+	GetLeadership() (*Leadership, error)
+
+	// Capabilities returns the capabilities supported by every candidate of the election
+	Capabilities() (Capabilities, error)
+
 	// Watch watches the store for changes
 	Watch(chan<- Leadership) error
 }
@@ -50,17 +61,45 @@ type Leadership struct {
 
 	// Leader is the NodeID of the leader
 	Leader cluster.NodeID
+
+	// Capabilities are the capabilities supported by every candidate of the election; a change
+	// of the capabilities is watched as a change of the leadership
+	Capabilities Capabilities
+}
+
+// Option is an option of the Store
+type Option func(*atomixStore)
+
+// WithCapabilities advertises the given capabilities of the local node instead of those of this
+// release, e.g. to stand for a node of another release
+func WithCapabilities(capabilities Capabilities) Option {
+	return func(store *atomixStore) {
+		store.local = capabilities
+	}
 }
 
 // NewAtomixStore returns a new persistent Store
-func NewAtomixStore(client atomix.Client) (Store, error) {
+func NewAtomixStore(client atomix.Client, options ...Option) (Store, error) {
 	election, err := client.GetElection(context.Background(), "onos-config-leaderships")
 	if err != nil {
 		return nil, errors.FromAtomix(err)
 	}
+	capabilities, err := client.GetMap(context.Background(), "onos-config-node-capabilities")
+	if err != nil {
+		_ = election.Close(context.Background())
+		return nil, errors.FromAtomix(err)
+	}
 	store := &atomixStore{
-		election: election,
-		watchers: make([]chan<- Leadership, 0, 1),
+		election:     election,
+		capabilities: capabilities,
+		local:        LocalCapabilities(),
+		watchers:     make([]chan<- Leadership, 0, 1),
+	}
+	for _, option := range options {
+		option(store)
+	}
+	if err := store.advertise(); err != nil {
+		return nil, err
 	}
 	if err := store.enter(); err != nil {
 		return nil, err
@@ -70,10 +109,54 @@ func NewAtomixStore(client atomix.Client) (Store, error) {
 
 // atomixStore is the default implementation of the NetworkConfig store
 type atomixStore struct {
-	election   election.Election
-	leadership *Leadership
-	watchers   []chan<- Leadership
-	mu         sync.RWMutex
+	election     election.Election
+	capabilities _map.Map
+	local        Capabilities
+	leadership   *Leadership
+	watchers     []chan<- Leadership
+	mu           sync.RWMutex
+}
+
+// advertise advertises the capabilities of the local node before it enters the election, so that
+// the other candidates find them once it does
+func (s *atomixStore) advertise() error {
+	bytes, err := json.Marshal(s.local)
+	if err != nil {
+		return errors.NewInvalid("capabilities encoding failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if _, err := s.capabilities.Put(ctx, s.election.ID(), bytes); err != nil {
+		_ = s.capabilities.Close(context.Background())
+		_ = s.election.Close(context.Background())
+		return errors.FromAtomix(err)
+	}
+	return nil
+}
+
+// negotiate returns the capabilities supported by every candidate of the election
+func (s *atomixStore) negotiate(candidates []string) Capabilities {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	nodes := make([]Capabilities, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate == s.election.ID() {
+			continue
+		}
+		var capabilities Capabilities
+		entry, err := s.capabilities.Get(ctx, candidate)
+		if err != nil {
+			if err = errors.FromAtomix(err); !errors.IsNotFound(err) {
+				log.Warnf("Cannot load the capabilities of node %s: %v", candidate, err)
+			}
+		} else if entry != nil {
+			if err := json.Unmarshal(entry.Value, &capabilities); err != nil {
+				log.Warnf("Cannot decode the capabilities of node %s: %v", candidate, err)
+			}
+		}
+		nodes = append(nodes, capabilities)
+	}
+	return Negotiate(s.local, nodes...)
 }
 
 // enter enters the election
@@ -88,6 +171,7 @@ func (s *atomixStore) enter() error {
 	term, err := s.election.Enter(ctx)
 	cancel()
 	if err != nil {
+		_ = s.capabilities.Close(context.Background())
 		_ = s.election.Close(context.Background())
 		return errors.FromAtomix(err)
 	}
@@ -95,8 +179,9 @@ func (s *atomixStore) enter() error {
 	// Set the leadership term
 	s.mu.Lock()
 	s.leadership = &Leadership{
-		Term:   Term(term.Revision),
-		Leader: cluster.NodeID(term.Leader),
+		Term:         Term(term.Revision),
+		Leader:       cluster.NodeID(term.Leader),
+		Capabilities: s.negotiate(term.Candidates),
 	}
 	s.mu.Unlock()
 
@@ -108,6 +193,7 @@ func (s *atomixStore) enter() error {
 		}
 	}
 
+	_ = s.capabilities.Close(context.Background())
 	_ = s.election.Close(context.Background())
 	return errors.NewUnavailable("failed to enter election")
 }
@@ -116,11 +202,16 @@ func (s *atomixStore) enter() error {
 func (s *atomixStore) watchElection(ch <-chan election.Event) {
 	for event := range ch {
 		var leadership *Leadership
+		capabilities := s.negotiate(event.Term.Candidates)
 		s.mu.Lock()
-		if s.leadership.Term != Term(event.Term.Revision) {
+		if s.leadership.Term != Term(event.Term.Revision) || !s.leadership.Capabilities.equal(capabilities) {
+			if !s.leadership.Capabilities.equal(capabilities) {
+				log.Infof("Cluster capabilities changed to schemas %v, features %v", capabilities.Schemas, capabilities.Features)
+			}
 			leadership = &Leadership{
-				Term:   Term(event.Term.Revision),
-				Leader: cluster.NodeID(event.Term.Leader),
+				Term:         Term(event.Term.Revision),
+				Leader:       cluster.NodeID(event.Term.Leader),
+				Capabilities: capabilities,
 			}
 			s.leadership = leadership
 		}
@@ -149,6 +240,25 @@ func (s *atomixStore) IsLeader() (bool, error) {
 	return true, nil
 }
 
+func (s *atomixStore) GetLeadership() (*Leadership, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.leadership == nil {
+		return nil, errors.NewUnavailable("not in the election")
+	}
+	leadership := *s.leadership
+	return &leadership, nil
+}
+
+func (s *atomixStore) Capabilities() (Capabilities, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.leadership == nil {
+		return Capabilities{}, errors.NewUnavailable("not in the election")
+	}
+	return s.leadership.Capabilities, nil
+}
+
 func (s *atomixStore) Watch(ch chan<- Leadership) error {
 	s.mu.Lock()
 	s.watchers = append(s.watchers, ch)
@@ -157,7 +267,16 @@ func (s *atomixStore) Watch(ch chan<- Leadership) error {
 }
 
 func (s *atomixStore) Close() error {
+	// Leaving the election elects another leader; the watchers are not told once the store is closed
+	s.mu.Lock()
+	s.watchers = nil
+	s.mu.Unlock()
+
 	err := s.election.Close(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	_, _ = s.capabilities.Remove(ctx, s.election.ID())
+	cancel()
+	_ = s.capabilities.Close(context.Background())
 	if err != nil {
 		return errors.FromAtomix(err)
 	}
@@ -15,10 +15,13 @@
 package leadership
 
 import (
+	"context"
 	"github.com/atomix/atomix-go-client/pkg/atomix/test"
 	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
 	"testing"
+	"time"
 
+	"github.com/onosproject/onos-config/pkg/store/schema"
 	"github.com/onosproject/onos-lib-go/pkg/cluster"
 	"github.com/stretchr/testify/assert"
 )
@@ -83,6 +86,11 @@ func TestLeadershipStore(t *testing.T) {
 	leadership = <-store3Ch
 	assert.Equal(t, cluster.NodeID("node-2"), leadership.Leader)
 
+	current, err := store3.GetLeadership()
+	assert.NoError(t, err)
+	assert.Equal(t, cluster.NodeID("node-2"), current.Leader)
+	assert.Equal(t, leadership.Term, current.Term)
+
 	leader, err = store3.IsLeader()
 	assert.NoError(t, err)
 	assert.False(t, leader)
@@ -99,3 +107,71 @@ func TestLeadershipStore(t *testing.T) {
 
 	_ = store3.Close()
 }
+
+func TestCapabilities(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	client1, err := test.NewClient("node-1")
+	assert.NoError(t, err)
+	store1, err := NewAtomixStore(client1)
+	assert.NoError(t, err)
+	defer store1.Close()
+	store1Ch := make(chan Leadership)
+	assert.NoError(t, store1.Watch(store1Ch))
+
+	capabilities, err := store1.Capabilities()
+	assert.NoError(t, err)
+	assert.True(t, capabilities.equal(LocalCapabilities()))
+	assert.True(t, capabilities.Supports(MaintenanceMode))
+
+	// A node that reads the current schemas but does not support maintenance mode
+	client2, err := test.NewClient("node-2")
+	assert.NoError(t, err)
+	store2, err := NewAtomixStore(client2, WithCapabilities(Capabilities{
+		Schemas: LocalCapabilities().Schemas,
+	}))
+	assert.NoError(t, err)
+	leadership := nextLeadership(t, store1Ch)
+	assert.Equal(t, cluster.NodeID("node-1"), leadership.Leader)
+	assert.Equal(t, schema.Initial, leadership.Capabilities.Schemas[schema.NetworkChange])
+	assert.False(t, leadership.Capabilities.Supports(MaintenanceMode))
+	capabilities, err = store2.Capabilities()
+	assert.NoError(t, err)
+	assert.True(t, capabilities.equal(leadership.Capabilities))
+
+	assert.NoError(t, store2.Close())
+	leadership = nextLeadership(t, store1Ch)
+	assert.True(t, leadership.Capabilities.equal(LocalCapabilities()))
+
+	// A node of a release that does not advertise its capabilities reads unversioned records only
+	client3, err := test.NewClient("node-3")
+	assert.NoError(t, err)
+	legacy, err := client3.GetElection(context.Background(), "onos-config-leaderships")
+	assert.NoError(t, err)
+	_, err = legacy.Enter(context.Background())
+	assert.NoError(t, err)
+	leadership = nextLeadership(t, store1Ch)
+	for _, kind := range schema.Kinds {
+		assert.Equal(t, schema.Unversioned, leadership.Capabilities.Schemas[kind])
+	}
+	assert.Empty(t, leadership.Capabilities.Features)
+
+	assert.NoError(t, legacy.Close(context.Background()))
+	leadership = nextLeadership(t, store1Ch)
+	assert.True(t, leadership.Capabilities.equal(LocalCapabilities()))
+}
+
+func nextLeadership(t *testing.T, ch chan Leadership) Leadership {
+	select {
+	case leadership := <-ch:
+		return leadership
+	case <-time.After(5 * time.Second):
+		t.FailNow()
+	}
+	return Leadership{}
+}
@@ -0,0 +1,138 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package location stores the addresses the devices identified by their serial number are located
+// at, registered as they come up, e.g. by the provisioning of the devices.
+package location
+
+import (
+	"io"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Location is the address a device is located at
+type Location struct {
+	// SerialNumber is the serial number of the device
+	SerialNumber string `json:"serialNumber"`
+	// Address is the host:port the device is reached at
+	Address string `json:"address"`
+	// User is the user who registered the location
+	User string `json:"user"`
+	// Updated is when the location was registered
+	Updated time.Time `json:"updated"`
+}
+
+// Store stores the locations of the devices
+type Store interface {
+	io.Closer
+
+	// Get gets the location of the device with a serial number
+	Get(serialNumber string) (*Location, error)
+
+	// Put registers the location of a device, replacing its previous location
+	Put(location *Location) error
+
+	// Delete deletes the location of the device with a serial number
+	Delete(serialNumber string) error
+
+	// List lists the locations, sorted by serial number
+	List() ([]*Location, error)
+}
+
+// kind and notFound describe the locations in the errors of the store
+const kind = "location"
+
+var notFound = records.WithNotFound("no location registered for serial number '%s'")
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	locations, err := records.NewAtomixMap(client, "onos-config-device-locations", kind, notFound)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		locations: locations,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps locations in memory
+func NewLocalStore() Store {
+	return &store{
+		locations: records.NewLocalMap(kind, notFound),
+	}
+}
+
+// store keeps the locations by serial number
+type store struct {
+	locations records.Map
+}
+
+func (s *store) Get(serialNumber string) (*Location, error) {
+	location := &Location{}
+	if err := s.locations.Get(serialNumber, location); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+func (s *store) Put(location *Location) error {
+	if err := validate(location); err != nil {
+		return err
+	}
+	return s.locations.Put(location.SerialNumber, location)
+}
+
+func (s *store) Delete(serialNumber string) error {
+	return s.locations.Delete(serialNumber)
+}
+
+func (s *store) List() ([]*Location, error) {
+	list, err := s.locations.List(func() interface{} { return &Location{} })
+	if err != nil {
+		return nil, err
+	}
+	locations := make([]*Location, 0, len(list))
+	for _, record := range list {
+		locations = append(locations, record.(*Location))
+	}
+	sortLocations(locations)
+	return locations, nil
+}
+
+func (s *store) Close() error {
+	return s.locations.Close()
+}
+
+// validate checks a location has a serial number and a host:port address
+func validate(location *Location) error {
+	if location.SerialNumber == "" {
+		return errors.NewInvalid("no serial number given")
+	}
+	if _, _, err := net.SplitHostPort(location.Address); err != nil {
+		return errors.NewInvalid("address '%s' of %s is not a host:port", location.Address, location.SerialNumber)
+	}
+	return nil
+}
+
+func sortLocations(locations []*Location) {
+	sort.Slice(locations, func(i, j int) bool {
+		return locations[i].SerialNumber < locations[j].SerialNumber
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package location
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	assert.NoError(t, store.Put(&Location{
+		SerialNumber: "SN-2",
+		Address:      "10.0.0.2:9339",
+		User:         "alice",
+		Updated:      time.Now(),
+	}))
+	assert.NoError(t, store.Put(&Location{SerialNumber: "SN-1", Address: "10.0.0.1:9339"}))
+	assert.True(t, errors.IsInvalid(store.Put(&Location{Address: "10.0.0.3:9339"})))
+	assert.True(t, errors.IsInvalid(store.Put(&Location{SerialNumber: "SN-3", Address: "10.0.0.3"})))
+
+	locations, err := store.List()
+	assert.NoError(t, err)
+	assert.Len(t, locations, 2)
+	assert.Equal(t, "SN-1", locations[0].SerialNumber)
+	assert.Equal(t, "SN-2", locations[1].SerialNumber)
+
+	// A device moving is registered at its new address
+	assert.NoError(t, store.Put(&Location{SerialNumber: "SN-2", Address: "10.0.1.2:9339", User: "bob"}))
+	location, err := store.Get("SN-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.1.2:9339", location.Address)
+	assert.Equal(t, "bob", location.User)
+
+	assert.NoError(t, store.Delete("SN-1"))
+	_, err = store.Get("SN-1")
+	assert.True(t, errors.IsNotFound(err))
+	assert.True(t, errors.IsNotFound(store.Delete("SN-1")))
+
+	// The locations returned are copies
+	location.Address = "10.0.2.2:9339"
+	location, err = store.Get("SN-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.1.2:9339", location.Address)
+
+	assert.EqualError(t, store.Delete("SN-1"), "no location registered for serial number 'SN-1'")
+}
@@ -0,0 +1,108 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package maintenance stores whether onos-config is in read-only maintenance mode, e.g. during
+// a store migration or an upgrade. The mode applies to every node of the cluster.
+package maintenance
+
+import (
+	"io"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// modeKey is the key of the mode in the atomix map
+const modeKey = "read-only"
+
+// Mode records who placed onos-config in maintenance mode, when and why
+type Mode struct {
+	// User is the user who placed onos-config in maintenance mode
+	User string `json:"user"`
+	// Reason is why onos-config is in maintenance mode
+	Reason string `json:"reason"`
+	// Started is when onos-config was placed in maintenance mode
+	Started time.Time `json:"started"`
+}
+
+// Store stores the maintenance mode
+type Store interface {
+	io.Closer
+
+	// Get gets the maintenance mode; it returns a NotFound error if onos-config is not in it
+	Get() (*Mode, error)
+
+	// Put places onos-config in maintenance mode, replacing any previous mode
+	Put(mode *Mode) error
+
+	// Delete takes onos-config out of maintenance mode
+	Delete() error
+}
+
+// kind describes the mode in the errors of the store
+const kind = "maintenance mode"
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	modes, err := records.NewAtomixMap(client, "onos-config-maintenance", kind)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		modes: modes,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps the maintenance mode in memory
+func NewLocalStore() Store {
+	return &store{
+		modes: records.NewLocalMap(kind),
+	}
+}
+
+// store keeps the mode under modeKey
+type store struct {
+	modes records.Map
+}
+
+func (s *store) Get() (*Mode, error) {
+	mode := &Mode{}
+	if err := s.modes.Get(modeKey, mode); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NewNotFound("not in maintenance mode")
+		}
+		return nil, err
+	}
+	return mode, nil
+}
+
+func (s *store) Put(mode *Mode) error {
+	return s.modes.Put(modeKey, mode)
+}
+
+func (s *store) Delete() error {
+	if err := s.modes.Delete(modeKey); err != nil {
+		if errors.IsNotFound(err) {
+			return errors.NewNotFound("not in maintenance mode")
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *store) Close() error {
+	return s.modes.Close()
+}
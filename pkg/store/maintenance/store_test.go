@@ -0,0 +1,59 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	_, err := store.Get()
+	assert.True(t, errors.IsNotFound(err))
+	assert.True(t, errors.IsNotFound(store.Delete()))
+
+	assert.NoError(t, store.Put(&Mode{User: "alice", Reason: "store migration", Started: time.Now()}))
+	mode, err := store.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", mode.User)
+	assert.Equal(t, "store migration", mode.Reason)
+
+	assert.NoError(t, store.Put(&Mode{User: "bob", Reason: "upgrade to 0.8"}))
+	mode, err = store.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", mode.User)
+
+	assert.NoError(t, store.Delete())
+	_, err = store.Get()
+	assert.True(t, errors.IsNotFound(err))
+
+	// The mode returned is a copy
+	assert.NoError(t, store.Put(&Mode{User: "carol", Reason: "upgrade to 0.9"}))
+	mode, err = store.Get()
+	assert.NoError(t, err)
+	mode.User = "dave"
+	mode, err = store.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "carol", mode.User)
+
+	assert.NoError(t, store.Delete())
+	assert.EqualError(t, store.Delete(), "not in maintenance mode")
+}
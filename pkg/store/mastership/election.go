@@ -27,11 +27,13 @@ import (
 )
 
 // newDeviceMastershipElection creates and enters a new device mastership election
-func newDeviceMastershipElection(deviceID topodevice.ID, election election.Election) (deviceMastershipElection, error) {
+func newDeviceMastershipElection(deviceID topodevice.ID, election election.Election, zones *zones, preference Preference) (deviceMastershipElection, error) {
 	deviceElection := &atomixDeviceMastershipElection{
-		deviceID: deviceID,
-		election: election,
-		watchers: make([]chan<- Mastership, 0, 1),
+		deviceID:   deviceID,
+		election:   election,
+		zones:      zones,
+		preference: preference,
+		watchers:   make([]chan<- Mastership, 0, 1),
 	}
 	if err := deviceElection.enter(); err != nil {
 		return nil, err
@@ -54,12 +56,17 @@ type deviceMastershipElection interface {
 
 	// watch watches the election for changes
 	watch(ch chan<- Mastership) error
+
+	// setPreference sets the preferred master of the device
+	setPreference(preference Preference)
 }
 
 // atomixDeviceMastershipElection is a persistent device mastership election
 type atomixDeviceMastershipElection struct {
 	deviceID   topodevice.ID
 	election   election.Election
+	zones      *zones
+	preference Preference
 	mastership *Mastership
 	watchers   []chan<- Mastership
 	mu         sync.RWMutex
@@ -98,6 +105,7 @@ func (e *atomixDeviceMastershipElection) enter() error {
 	}
 	e.mu.Unlock()
 	go e.watchElection(*term, ch)
+	go e.claim()
 	return nil
 }
 
@@ -127,10 +135,40 @@ func (e *atomixDeviceMastershipElection) watchElection(term election.Term, ch <-
 				watcher <- *mastership
 			}
 			e.mu.RUnlock()
+			go e.claim()
 		}
 	}
 }
 
+// claim takes the mastership if the local node is preferred to the current master
+func (e *atomixDeviceMastershipElection) claim() {
+	e.mu.RLock()
+	preference := e.preference
+	mastership := e.mastership
+	e.mu.RUnlock()
+	local := e.NodeID()
+	if mastership == nil || mastership.Master == local || preference == (Preference{}) {
+		return
+	}
+	localRank := preference.rank(local, e.zones.local)
+	if localRank == 0 || localRank <= preference.rank(mastership.Master, e.zones.zoneOf(mastership.Master)) {
+		return
+	}
+	log.Infof("Taking the mastership of %s over %s, as preferred by %+v", e.deviceID, mastership.Master, preference)
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if _, err := e.election.Anoint(ctx, string(local)); err != nil {
+		log.Warnf("Cannot take the mastership of %s: %v", e.deviceID, errors.FromAtomix(err))
+	}
+}
+
+func (e *atomixDeviceMastershipElection) setPreference(preference Preference) {
+	e.mu.Lock()
+	e.preference = preference
+	e.mu.Unlock()
+	go e.claim()
+}
+
 func (e *atomixDeviceMastershipElection) getMastership() *Mastership {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
@@ -39,7 +39,7 @@ func TestMastershipElection(t *testing.T) {
 	election1, err := client1.GetElection(context.TODO(), "masterships")
 	assert.NoError(t, err)
 
-	store1, err := newDeviceMastershipElection("test", election1)
+	store1, err := newDeviceMastershipElection("test", election1, &zones{}, Preference{})
 	assert.NoError(t, err)
 
 	client2, err := test.NewClient("node-2")
@@ -48,7 +48,7 @@ func TestMastershipElection(t *testing.T) {
 	election2, err := client2.GetElection(context.TODO(), "masterships")
 	assert.NoError(t, err)
 
-	store2, err := newDeviceMastershipElection("test", election2)
+	store2, err := newDeviceMastershipElection("test", election2, &zones{}, Preference{})
 	assert.NoError(t, err)
 
 	store2Ch := make(chan Mastership)
@@ -61,7 +61,7 @@ func TestMastershipElection(t *testing.T) {
 	election3, err := client3.GetElection(context.TODO(), "masterships")
 	assert.NoError(t, err)
 
-	store3, err := newDeviceMastershipElection("test", election3)
+	store3, err := newDeviceMastershipElection("test", election3, &zones{}, Preference{})
 	assert.NoError(t, err)
 
 	store3Ch := make(chan Mastership)
@@ -0,0 +1,98 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mastership
+
+import (
+	"context"
+	"time"
+
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/onosproject/onos-lib-go/pkg/cluster"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Preference is the preferred master of a device. A preferred node takes the mastership of the
+// device over a node that is not, and keeps it while it runs: a mastership never moves between
+// equally preferred nodes.
+type Preference struct {
+	// Node is the node preferred as master, if any
+	Node cluster.NodeID
+	// Zone is the zone the master is preferably in, if any; a node in the zone is preferred to a
+	// node outside it, but not to Node
+	Zone string
+}
+
+// rank returns how much a node in the given zone is preferred: 2 for the preferred node, 1 in the
+// preferred zone, 0 otherwise
+func (p Preference) rank(node cluster.NodeID, zone string) int {
+	if p.Node != "" && node == p.Node {
+		return 2
+	}
+	if p.Zone != "" && zone == p.Zone {
+		return 1
+	}
+	return 0
+}
+
+// zones locates the nodes in zones. The nodes given a zone publish it, so that the others learn
+// whether a master is in the preferred zone of its device.
+type zones struct {
+	local string
+	nodes _map.Map
+}
+
+// publish publishes the zone of the local node
+func (z *zones) publish(nodeID cluster.NodeID) error {
+	if z.nodes == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if _, err := z.nodes.Put(ctx, string(nodeID), []byte(z.local)); err != nil {
+		return errors.FromAtomix(err)
+	}
+	return nil
+}
+
+// zoneOf returns the zone of a node, empty if it has none
+func (z *zones) zoneOf(nodeID cluster.NodeID) string {
+	if z.nodes == nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	entry, err := z.nodes.Get(ctx, string(nodeID))
+	if err != nil {
+		if err = errors.FromAtomix(err); !errors.IsNotFound(err) {
+			log.Warnf("Cannot load the zone of node %s: %v", nodeID, err)
+		}
+		return ""
+	}
+	if entry == nil {
+		return ""
+	}
+	return string(entry.Value)
+}
+
+// close withdraws the zone of the local node
+func (z *zones) close(nodeID cluster.NodeID) {
+	if z.nodes == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	_, _ = z.nodes.Remove(ctx, string(nodeID))
+	cancel()
+	_ = z.nodes.Close(context.Background())
+}
@@ -23,8 +23,12 @@ import (
 
 	"github.com/onosproject/onos-config/pkg/device"
 	"github.com/onosproject/onos-lib-go/pkg/cluster"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
 )
 
+var log = logging.GetLogger("store", "mastership")
+
 // Term is a monotonically increasing mastership term
 type Term uint64
 
@@ -38,8 +42,19 @@ type Store interface {
 	// GetMastership returns the mastership for a given device
 	GetMastership(id device.ID) (*Mastership, error)
 
+	// ListMasterships returns the masterships of the devices whose elections the local node entered
+	ListMasterships() ([]*Mastership, error)
+
 	// Watch watches the store for mastership changes
 	Watch(device.ID, chan<- Mastership) error
+
+	// WatchAll watches the store for the mastership changes of every device whose election the
+	// local node entered
+	WatchAll(chan<- Mastership) error
+
+	// SetPreference sets the preferred master of a device; the local node takes the mastership of
+	// the device if it is preferred to the current master
+	SetPreference(device.ID, Preference) error
 }
 
 // Mastership contains information about a device mastership term
@@ -54,35 +69,76 @@ type Mastership struct {
 	Master cluster.NodeID
 }
 
+// Option is an option of the Store
+type Option func(*atomixStore)
+
+// WithZone places the local node in a zone, for the devices preferring their master in a zone
+func WithZone(zone string) Option {
+	return func(store *atomixStore) {
+		store.zones.local = zone
+	}
+}
+
 // NewAtomixStore returns a new persistent Store
-func NewAtomixStore(client atomix.Client, nodeID cluster.NodeID) (Store, error) {
-	return &atomixStore{
-		nodeID: nodeID,
-		newElection: func(id device.ID) (deviceMastershipElection, error) {
-			election, err := client.GetElection(
-				context.Background(),
-				"onos-config-masterships",
-				primitive.WithSessionID(string(nodeID)),
-				primitive.WithClusterKey(string(id)))
-			if err != nil {
-				return nil, err
-			}
-			return newDeviceMastershipElection(id, election)
-		},
-		elections: make(map[device.ID]deviceMastershipElection),
-	}, nil
+func NewAtomixStore(client atomix.Client, nodeID cluster.NodeID, options ...Option) (Store, error) {
+	store := &atomixStore{
+		nodeID:      nodeID,
+		zones:       &zones{},
+		elections:   make(map[device.ID]deviceMastershipElection),
+		preferences: make(map[device.ID]Preference),
+		watchers:    make([]chan<- Mastership, 0),
+	}
+	for _, option := range options {
+		option(store)
+	}
+	if store.zones.local != "" {
+		nodes, err := client.GetMap(context.Background(), "onos-config-node-zones")
+		if err != nil {
+			return nil, errors.FromAtomix(err)
+		}
+		store.zones.nodes = nodes
+		if err := store.zones.publish(nodeID); err != nil {
+			_ = nodes.Close(context.Background())
+			return nil, err
+		}
+	}
+	store.newElection = func(id device.ID, preference Preference) (deviceMastershipElection, error) {
+		election, err := client.GetElection(
+			context.Background(),
+			"onos-config-masterships",
+			primitive.WithSessionID(string(nodeID)),
+			primitive.WithClusterKey(string(id)))
+		if err != nil {
+			return nil, err
+		}
+		return newDeviceMastershipElection(id, election, store.zones, preference)
+	}
+	return store, nil
 }
 
 // atomixStore is the default implementation of the NetworkConfig store
 type atomixStore struct {
 	nodeID      cluster.NodeID
-	newElection func(device.ID) (deviceMastershipElection, error)
+	zones       *zones
+	newElection func(device.ID, Preference) (deviceMastershipElection, error)
 	elections   map[device.ID]deviceMastershipElection
+	preferences map[device.ID]Preference
+	watchers    []chan<- Mastership
 	mu          sync.RWMutex
 }
 
+// electionID returns the device whose mastership election decides the master of a device: the
+// sub-targets of a device share its master, which holds the connection they share
+func electionID(deviceID device.ID) device.ID {
+	if chassisID, _, ok := device.ParseSubTargetID(deviceID); ok {
+		return chassisID
+	}
+	return deviceID
+}
+
 // getElection gets the mastership election for the given device
 func (s *atomixStore) getElection(deviceID device.ID) (deviceMastershipElection, error) {
+	deviceID = electionID(deviceID)
 	s.mu.RLock()
 	election, ok := s.elections[deviceID]
 	s.mu.RUnlock()
@@ -90,19 +146,41 @@ func (s *atomixStore) getElection(deviceID device.ID) (deviceMastershipElection,
 		s.mu.Lock()
 		election, ok = s.elections[deviceID]
 		if !ok {
-			e, err := s.newElection(deviceID)
+			e, err := s.newElection(deviceID, s.preferences[deviceID])
 			if err != nil {
 				s.mu.Unlock()
 				return nil, err
 			}
 			election = e
 			s.elections[deviceID] = election
+			if err := s.forward(election); err != nil {
+				s.mu.Unlock()
+				return nil, err
+			}
 		}
 		s.mu.Unlock()
 	}
 	return election, nil
 }
 
+// forward forwards the mastership changes of an election to the watchers of every device
+func (s *atomixStore) forward(election deviceMastershipElection) error {
+	ch := make(chan Mastership)
+	if err := election.watch(ch); err != nil {
+		return err
+	}
+	go func() {
+		for mastership := range ch {
+			s.mu.RLock()
+			for _, watcher := range s.watchers {
+				watcher <- mastership
+			}
+			s.mu.RUnlock()
+		}
+	}()
+	return nil
+}
+
 func (s *atomixStore) NodeID() cluster.NodeID {
 	return s.nodeID
 }
@@ -113,7 +191,25 @@ func (s *atomixStore) GetMastership(deviceID device.ID) (*Mastership, error) {
 		return nil, err
 	}
 
-	return election.getMastership(), nil
+	mastership := election.getMastership()
+	if mastership != nil && mastership.Device != deviceID {
+		subTargetMastership := *mastership
+		subTargetMastership.Device = deviceID
+		return &subTargetMastership, nil
+	}
+	return mastership, nil
+}
+
+func (s *atomixStore) ListMasterships() ([]*Mastership, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	masterships := make([]*Mastership, 0, len(s.elections))
+	for _, election := range s.elections {
+		if mastership := election.getMastership(); mastership != nil {
+			masterships = append(masterships, mastership)
+		}
+	}
+	return masterships, nil
 }
 
 func (s *atomixStore) Watch(deviceID device.ID, ch chan<- Mastership) error {
@@ -124,7 +220,31 @@ func (s *atomixStore) Watch(deviceID device.ID, ch chan<- Mastership) error {
 	return election.watch(ch)
 }
 
+func (s *atomixStore) WatchAll(ch chan<- Mastership) error {
+	s.mu.Lock()
+	s.watchers = append(s.watchers, ch)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *atomixStore) SetPreference(deviceID device.ID, preference Preference) error {
+	deviceID = electionID(deviceID)
+	s.mu.Lock()
+	if s.preferences[deviceID] == preference {
+		s.mu.Unlock()
+		return nil
+	}
+	s.preferences[deviceID] = preference
+	election, ok := s.elections[deviceID]
+	s.mu.Unlock()
+	if ok {
+		election.setPreference(preference)
+	}
+	return nil
+}
+
 func (s *atomixStore) Close() error {
+	defer s.zones.close(s.nodeID)
 	var returnErr error
 	for _, election := range s.elections {
 		if err := election.Close(); err != nil && returnErr == nil {
@@ -55,6 +55,10 @@ func TestMastershipStore(t *testing.T) {
 	store3, err := NewAtomixStore(client3, node3)
 	assert.NoError(t, err)
 
+	store3All := make(chan Mastership, 10)
+	err = store3.WatchAll(store3All)
+	assert.NoError(t, err)
+
 	device1 := topodevice.ID("device1")
 	device2 := topodevice.ID("device2")
 
@@ -179,5 +183,124 @@ func TestMastershipStore(t *testing.T) {
 	// Since master of device2 has been changed its term has been increased by 1
 	assert.Equal(t, master.Term, Term(2))
 
+	// Verify that node3 saw every mastership change of the devices whose elections it entered
+	masters := make(map[topodevice.ID]cluster.NodeID)
+	for i := 0; i < 3; i++ {
+		mastership = <-store3All
+		masters[mastership.Device] = mastership.Master
+	}
+	assert.Equal(t, node3, masters[device1])
+	assert.Equal(t, node3, masters[device2])
+
+	masterships, err := store3.ListMasterships()
+	assert.NoError(t, err)
+	assert.Len(t, masterships, 2)
+	for _, m := range masterships {
+		assert.Equal(t, node3, m.Master)
+	}
+
 	_ = store3.Close()
 }
+
+func TestMastershipPreference(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1),
+		test.WithDebugLogs())
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	node1 := cluster.NodeID("node1")
+	node2 := cluster.NodeID("node2")
+	node3 := cluster.NodeID("node3")
+
+	client1, err := test.NewClient(string(node1))
+	assert.NoError(t, err)
+	client2, err := test.NewClient(string(node2))
+	assert.NoError(t, err)
+	client3, err := test.NewClient(string(node3))
+	assert.NoError(t, err)
+
+	store1, err := NewAtomixStore(client1, node1, WithZone("zone-a"))
+	assert.NoError(t, err)
+	store2, err := NewAtomixStore(client2, node2, WithZone("zone-b"))
+	assert.NoError(t, err)
+	store3, err := NewAtomixStore(client3, node3)
+	assert.NoError(t, err)
+	stores := []Store{store1, store2, store3}
+
+	device1 := topodevice.ID("device1")
+	for _, store := range stores {
+		assert.NoError(t, store.SetPreference(device1, Preference{Zone: "zone-b"}))
+	}
+
+	// The first node to enter is the master, even outside the preferred zone
+	master, err := store1.GetMastership(device1)
+	assert.NoError(t, err)
+	assert.Equal(t, node1, master.Master)
+
+	// A node of the preferred zone takes the mastership as it enters
+	ch2 := make(chan Mastership, 10)
+	assert.NoError(t, store2.Watch(device1, ch2))
+	mastership := <-ch2
+	assert.Equal(t, node2, mastership.Master)
+	assert.Equal(t, Term(2), mastership.Term)
+
+	// A node that is not preferred leaves the mastership where it is
+	ch3 := make(chan Mastership, 10)
+	assert.NoError(t, store3.Watch(device1, ch3))
+	master, err = store3.GetMastership(device1)
+	assert.NoError(t, err)
+	assert.Equal(t, node2, master.Master)
+
+	// The preferred node takes the mastership over the preferred zone
+	for _, store := range stores {
+		assert.NoError(t, store.SetPreference(device1, Preference{Node: node3, Zone: "zone-b"}))
+	}
+	mastership = <-ch3
+	assert.Equal(t, node3, mastership.Master)
+	assert.Equal(t, Term(3), mastership.Term)
+
+	// Once the preferred node is gone, the mastership moves back to the preferred zone
+	assert.NoError(t, store3.Close())
+	for mastership = range ch2 {
+		if mastership.Master == node2 {
+			break
+		}
+	}
+	assert.Equal(t, node2, mastership.Master)
+
+	_ = store2.Close()
+	_ = store1.Close()
+}
+
+func TestMastershipSubTargets(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	node1 := cluster.NodeID("node1")
+	node2 := cluster.NodeID("node2")
+	client1, err := test.NewClient(string(node1))
+	assert.NoError(t, err)
+	client2, err := test.NewClient(string(node2))
+	assert.NoError(t, err)
+	store1, err := NewAtomixStore(client1, node1)
+	assert.NoError(t, err)
+	store2, err := NewAtomixStore(client2, node2)
+	assert.NoError(t, err)
+
+	// The sub-targets of a device share its master
+	master, err := store1.GetMastership("chassis1")
+	assert.NoError(t, err)
+	assert.Equal(t, node1, master.Master)
+	master, err = store2.GetMastership(topodevice.SubTargetID("chassis1", "member2"))
+	assert.NoError(t, err)
+	assert.Equal(t, node1, master.Master)
+	assert.Equal(t, topodevice.ID("chassis1/member2"), master.Device)
+	assert.Equal(t, Term(1), master.Term)
+}
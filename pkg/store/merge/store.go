@@ -0,0 +1,157 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package merge stores the strategies declared by the operators for merging the values written by
+// gNMI Set requests into subtrees of the configuration. The strategies themselves are applied by
+// the merge package.
+package merge
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Strategy is how the values written to a subtree are merged into its configuration
+type Strategy string
+
+const (
+	// Merge merges the values written leaf-wise into the subtree, leaving its other leaves as they are
+	Merge Strategy = "merge"
+	// Replace replaces the subtree wholesale: its leaves that are not written are deleted
+	Replace Strategy = "replace"
+)
+
+// ParseStrategy parses the name of a strategy
+func ParseStrategy(name string) (Strategy, error) {
+	switch Strategy(name) {
+	case Merge, Replace:
+		return Strategy(name), nil
+	}
+	return "", errors.NewInvalid("unknown merge strategy '%s': expected %s or %s", name, Merge, Replace)
+}
+
+// Rule declares the strategy of a subtree
+type Rule struct {
+	// Path is the path of the subtree; it may contain the '*' wildcard, in element names and key
+	// values alike
+	Path string `json:"path"`
+	// DeviceType restricts the rule to the devices of a type of model, if not empty
+	DeviceType devicetype.Type `json:"deviceType,omitempty"`
+	// Strategy is the strategy of the subtree
+	Strategy Strategy `json:"strategy"`
+}
+
+// Validate checks that the rule has a path and a known strategy
+func (r *Rule) Validate() error {
+	if !strings.HasPrefix(r.Path, "/") {
+		return errors.NewInvalid("invalid subtree path '%s'", r.Path)
+	} else if strings.Contains(r.Path, "...") {
+		return errors.NewInvalid("the subtree path %s may not have the '...' wildcard", r.Path)
+	}
+	_, err := ParseStrategy(string(r.Strategy))
+	return err
+}
+
+// Store stores merge strategy rules
+type Store interface {
+	io.Closer
+
+	// Put creates or replaces the rule of a subtree
+	Put(rule *Rule) error
+
+	// Delete deletes the rule of a subtree
+	Delete(deviceType devicetype.Type, path string) error
+
+	// List lists the rules, sorted by device type and path
+	List() ([]*Rule, error)
+}
+
+// kind describes the rules in the errors of the store
+const kind = "merge rule"
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	rules, err := records.NewAtomixMap(client, "onos-config-merge-rules", kind)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		rules: rules,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps rules in memory
+func NewLocalStore() Store {
+	return &store{
+		rules: records.NewLocalMap(kind),
+	}
+}
+
+// store keeps the rules by device type and path
+type store struct {
+	rules records.Map
+}
+
+func ruleKey(deviceType devicetype.Type, path string) string {
+	return string(deviceType) + path
+}
+
+func (s *store) Put(rule *Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	return s.rules.Put(ruleKey(rule.DeviceType, rule.Path), rule)
+}
+
+func (s *store) Delete(deviceType devicetype.Type, path string) error {
+	if err := s.rules.Delete(ruleKey(deviceType, path)); err != nil {
+		if errors.IsNotFound(err) {
+			return errors.NewNotFound("no merge rule for %s", path)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *store) List() ([]*Rule, error) {
+	list, err := s.rules.List(func() interface{} { return &Rule{} })
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]*Rule, 0, len(list))
+	for _, record := range list {
+		rules = append(rules, record.(*Rule))
+	}
+	sortRules(rules)
+	return rules, nil
+}
+
+func (s *store) Close() error {
+	return s.rules.Close()
+}
+
+func sortRules(rules []*Rule) {
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].DeviceType != rules[j].DeviceType {
+			return rules[i].DeviceType < rules[j].DeviceType
+		}
+		return rules[i].Path < rules[j].Path
+	})
+}
@@ -0,0 +1,82 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Validate(t *testing.T) {
+	assert.NoError(t, (&Rule{Path: "/acl/acl-sets/acl-set[name=*][type=*]", Strategy: Replace}).Validate())
+	assert.True(t, errors.IsInvalid((&Rule{Path: "acl/acl-sets", Strategy: Replace}).Validate()))
+	assert.True(t, errors.IsInvalid((&Rule{Path: "/acl/.../acl-set", Strategy: Replace}).Validate()))
+	assert.True(t, errors.IsInvalid((&Rule{Path: "/acl/acl-sets", Strategy: "overwrite"}).Validate()))
+
+	strategy, err := ParseStrategy("merge")
+	assert.NoError(t, err)
+	assert.Equal(t, Merge, strategy)
+}
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	assert.NoError(t, store.Put(&Rule{
+		Path:       "/acl/acl-sets/acl-set[name=*][type=*]",
+		DeviceType: "Devicesim",
+		Strategy:   Replace,
+	}))
+	assert.NoError(t, store.Put(&Rule{
+		Path:     "/system/ntp/servers",
+		Strategy: Replace,
+	}))
+	assert.True(t, errors.IsInvalid(store.Put(&Rule{Path: "/system"})))
+
+	// Putting the rule of a subtree again replaces it
+	assert.NoError(t, store.Put(&Rule{
+		Path:     "/system/ntp/servers",
+		Strategy: Merge,
+	}))
+
+	rules, err := store.List()
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "/system/ntp/servers", rules[0].Path)
+	assert.Equal(t, Merge, rules[0].Strategy)
+	assert.Equal(t, "/acl/acl-sets/acl-set[name=*][type=*]", rules[1].Path)
+
+	assert.True(t, errors.IsNotFound(store.Delete("", "/acl/acl-sets/acl-set[name=*][type=*]")))
+	assert.NoError(t, store.Delete("Devicesim", "/acl/acl-sets/acl-set[name=*][type=*]"))
+	rules, err = store.List()
+	assert.NoError(t, err)
+	assert.Len(t, rules, 1)
+
+	// Rules of the same path for different device types are kept apart
+	assert.NoError(t, store.Put(&Rule{Path: "/system/ntp/servers", DeviceType: "Stratum", Strategy: Replace}))
+	rules, err = store.List()
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+
+	// The rules returned are copies
+	rules[0].Strategy = Replace
+	rules, err = store.List()
+	assert.NoError(t, err)
+	assert.Equal(t, Merge, rules[0].Strategy)
+
+	assert.EqualError(t, store.Delete("", "/acl"), "no merge rule for /acl")
+}
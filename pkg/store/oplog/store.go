@@ -0,0 +1,262 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package oplog stores the log of the southbound operations, the gNMI Sets and Gets, issued to
+// each device, so that an issue with a device can be escalated to its vendor with the requests
+// onos-config sent. The log of a device keeps its last operations, up to the capacity of the
+// store, the oldest ones being dropped as new ones are appended.
+package oplog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	"github.com/atomix/atomix-go-client/pkg/atomix/indexedmap"
+	"github.com/atomix/atomix-go-client/pkg/atomix/primitive"
+	"github.com/google/uuid"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Southbound methods that are logged
+const (
+	MethodGet = "Get"
+	MethodSet = "Set"
+)
+
+// Operation is a southbound operation issued to a device
+type Operation struct {
+	DeviceID devicetype.ID `json:"deviceId"`
+	// Sequence orders the operations of the device; it is assigned when the operation is appended
+	Sequence uint64 `json:"sequence"`
+	// Time is when the operation was issued
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	// Request is the summary of the request: the paths it reads or writes, without their values
+	Request string `json:"request"`
+	// Latency is how long the device took to answer
+	Latency time.Duration `json:"latency"`
+	// Code is the gRPC code the operation failed with, empty if it succeeded
+	Code string `json:"code,omitempty"`
+	// Error is the error the operation failed with
+	Error string `json:"error,omitempty"`
+}
+
+// Store stores the logs of the southbound operations of the devices
+type Store interface {
+	io.Closer
+
+	// Append appends an operation to the log of its device, assigning its sequence, and drops the
+	// oldest operations of the device beyond the capacity of the store
+	Append(operation *Operation) error
+
+	// List lists the operations of a device after the given sequence, from the oldest
+	List(deviceID devicetype.ID, after uint64) ([]*Operation, error)
+
+	// Capacity returns the number of operations kept for each device
+	Capacity() int
+}
+
+// getDeviceOperationsName returns the name of the operations map of a device
+func getDeviceOperationsName(deviceID devicetype.ID) string {
+	return fmt.Sprintf("device-operations-%s", deviceID)
+}
+
+// NewAtomixStore returns a new persistent Store keeping the given number of operations of each device
+func NewAtomixStore(client atomix.Client, capacity int) (Store, error) {
+	if capacity <= 0 {
+		return nil, errors.NewInvalid("the capacity of the operation log must be positive: %d", capacity)
+	}
+	operationsFactory := func(deviceID devicetype.ID) (indexedmap.IndexedMap, error) {
+		return client.GetIndexedMap(context.Background(), "onos-config-device-operations",
+			primitive.WithClusterKey(getDeviceOperationsName(deviceID)))
+	}
+	return &atomixStore{
+		capacity:          capacity,
+		operationsFactory: operationsFactory,
+		deviceOperations:  make(map[devicetype.ID]indexedmap.IndexedMap),
+	}, nil
+}
+
+// atomixStore is the default implementation of the operation log store
+type atomixStore struct {
+	capacity          int
+	operationsFactory func(devicetype.ID) (indexedmap.IndexedMap, error)
+	deviceOperations  map[devicetype.ID]indexedmap.IndexedMap
+	mu                sync.RWMutex
+}
+
+func (s *atomixStore) getDeviceOperations(deviceID devicetype.ID) (indexedmap.IndexedMap, error) {
+	s.mu.RLock()
+	operations, ok := s.deviceOperations[deviceID]
+	s.mu.RUnlock()
+	if ok {
+		return operations, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if operations, ok = s.deviceOperations[deviceID]; ok {
+		return operations, nil
+	}
+	operations, err := s.operationsFactory(deviceID)
+	if err != nil {
+		return nil, errors.FromAtomix(err)
+	}
+	s.deviceOperations[deviceID] = operations
+	return operations, nil
+}
+
+func (s *atomixStore) Append(operation *Operation) error {
+	if operation.DeviceID == "" {
+		return errors.NewInvalid("no device ID given")
+	}
+	operations, err := s.getDeviceOperations(operation.DeviceID)
+	if err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(operation)
+	if err != nil {
+		return errors.NewInvalid("operation encoding failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	entry, err := operations.Append(ctx, uuid.New().String(), bytes)
+	if err != nil {
+		return errors.FromAtomix(err)
+	}
+	// The sequence is the index of the entry, which is only known once it is appended
+	operation.Sequence = uint64(entry.Index)
+
+	for {
+		length, err := operations.Len(ctx)
+		if err != nil {
+			return errors.FromAtomix(err)
+		} else if length <= s.capacity {
+			return nil
+		}
+		first, err := operations.FirstIndex(ctx)
+		if err != nil {
+			return errors.FromAtomix(err)
+		}
+		if _, err := operations.RemoveIndex(ctx, first); err != nil && !errors.IsNotFound(errors.FromAtomix(err)) {
+			return errors.FromAtomix(err)
+		}
+	}
+}
+
+func (s *atomixStore) List(deviceID devicetype.ID, after uint64) ([]*Operation, error) {
+	operations, err := s.getDeviceOperations(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	ch := make(chan indexedmap.Entry)
+	if err := operations.Entries(ctx, ch); err != nil {
+		return nil, errors.FromAtomix(err)
+	}
+	list := make([]*Operation, 0)
+	for entry := range ch {
+		if uint64(entry.Index) <= after || err != nil {
+			continue
+		}
+		operation := &Operation{}
+		if err = json.Unmarshal(entry.Value, operation); err != nil {
+			err = errors.NewInvalid("operation decoding failed: %v", err)
+			continue
+		}
+		operation.Sequence = uint64(entry.Index)
+		list = append(list, operation)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *atomixStore) Capacity() int {
+	return s.capacity
+}
+
+func (s *atomixStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for deviceID, operations := range s.deviceOperations {
+		_ = operations.Close(context.Background())
+		delete(s.deviceOperations, deviceID)
+	}
+	return nil
+}
+
+// NewLocalStore returns a new store that only keeps the given number of operations of each device
+// in memory
+func NewLocalStore(capacity int) Store {
+	return &localStore{
+		capacity:   capacity,
+		operations: make(map[devicetype.ID][]*Operation),
+		sequences:  make(map[devicetype.ID]uint64),
+	}
+}
+
+// localStore is an in-memory operation log store, used when no persistent store is configured
+type localStore struct {
+	capacity   int
+	mu         sync.RWMutex
+	operations map[devicetype.ID][]*Operation
+	sequences  map[devicetype.ID]uint64
+}
+
+func (s *localStore) Append(operation *Operation) error {
+	if operation.DeviceID == "" {
+		return errors.NewInvalid("no device ID given")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sequences[operation.DeviceID]++
+	operation.Sequence = s.sequences[operation.DeviceID]
+	copied := *operation
+	operations := append(s.operations[operation.DeviceID], &copied)
+	if len(operations) > s.capacity {
+		operations = operations[len(operations)-s.capacity:]
+	}
+	s.operations[operation.DeviceID] = operations
+	return nil
+}
+
+func (s *localStore) List(deviceID devicetype.ID, after uint64) ([]*Operation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]*Operation, 0)
+	for _, operation := range s.operations[deviceID] {
+		if operation.Sequence > after {
+			copied := *operation
+			list = append(list, &copied)
+		}
+	}
+	return list, nil
+}
+
+func (s *localStore) Capacity() int {
+	return s.capacity
+}
+
+func (s *localStore) Close() error {
+	return nil
+}
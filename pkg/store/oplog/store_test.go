@@ -0,0 +1,94 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oplog
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func testStore(t *testing.T, store Store) {
+	assert.Equal(t, 3, store.Capacity())
+	for i := 1; i <= 5; i++ {
+		operation := &Operation{
+			DeviceID: "device-1",
+			Time:     time.Now(),
+			Method:   MethodSet,
+			Request:  fmt.Sprintf("update /cont1a/leaf%d", i),
+			Latency:  time.Duration(i) * time.Millisecond,
+		}
+		assert.NoError(t, store.Append(operation))
+		assert.NotZero(t, operation.Sequence)
+	}
+	assert.NoError(t, store.Append(&Operation{
+		DeviceID: "device-2",
+		Method:   MethodGet,
+		Code:     "Unavailable",
+		Error:    "connection refused",
+	}))
+	assert.True(t, errors.IsInvalid(store.Append(&Operation{Method: MethodGet})))
+
+	// The oldest operations of a device are dropped beyond the capacity
+	operations, err := store.List("device-1", 0)
+	assert.NoError(t, err)
+	assert.Len(t, operations, 3)
+	assert.Equal(t, "update /cont1a/leaf3", operations[0].Request)
+	assert.Equal(t, "update /cont1a/leaf5", operations[2].Request)
+	assert.Equal(t, 5*time.Millisecond, operations[2].Latency)
+	assert.True(t, operations[0].Sequence < operations[1].Sequence && operations[1].Sequence < operations[2].Sequence)
+
+	operations, err = store.List("device-1", operations[1].Sequence)
+	assert.NoError(t, err)
+	assert.Len(t, operations, 1)
+	assert.Equal(t, "update /cont1a/leaf5", operations[0].Request)
+
+	operations, err = store.List("device-2", 0)
+	assert.NoError(t, err)
+	assert.Len(t, operations, 1)
+	assert.Equal(t, "Unavailable", operations[0].Code)
+
+	operations, err = store.List("device-3", 0)
+	assert.NoError(t, err)
+	assert.Len(t, operations, 0)
+
+	assert.NoError(t, store.Close())
+}
+
+func Test_LocalStore(t *testing.T) {
+	testStore(t, NewLocalStore(3))
+}
+
+func Test_AtomixStore(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	client, err := test.NewClient("node-1")
+	assert.NoError(t, err)
+	_, err = NewAtomixStore(client, 0)
+	assert.True(t, errors.IsInvalid(err))
+	store, err := NewAtomixStore(client, 3)
+	assert.NoError(t, err)
+	testStore(t, store)
+}
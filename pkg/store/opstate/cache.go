@@ -0,0 +1,345 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package opstate caches the operational state the devices report. The cache is split in shards
+// by device, each guarded by its own lock, so that the devices streaming state do not contend for
+// a single lock. The cache may be given a memory budget: the subtrees of the devices read least
+// recently are then evicted whenever it is over budget, and re-fetched from their device when
+// they are read again.
+package opstate
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+)
+
+var log = logging.GetLogger("store", "opstate")
+
+// entryOverhead is the estimated memory used by a cached path besides its path and value bytes
+const entryOverhead = 64
+
+// ShardOf returns the shard of a device among the given number of shards
+func ShardOf(deviceID topodevice.ID, shards int) int {
+	if shards <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(deviceID))
+	return int(h.Sum32() % uint32(shards))
+}
+
+// SubtreeOf returns the subtree of a state path, the unit of eviction: its first element, e.g.
+// "/interfaces" for "/interfaces/interface[name=eth1]/state/counters"
+func SubtreeOf(path string) string {
+	depth := 0
+	for i := 1; i < len(path); i++ {
+		switch path[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '/':
+			if depth == 0 {
+				return path[:i]
+			}
+		}
+	}
+	return path
+}
+
+// Cache is the operational state of the devices, sharded by device
+type Cache struct {
+	shards []*shard
+	budget int64
+}
+
+type shard struct {
+	mu      sync.RWMutex
+	devices map[topodevice.ID]devicechange.TypedValueMap
+	// reads is when each subtree of each device was last read
+	reads map[topodevice.ID]map[string]time.Time
+	// evicted are the subtrees of each device evicted since they were last read
+	evicted      map[topodevice.ID]map[string]bool
+	evictions    uint64
+	evictedPaths uint64
+}
+
+// NewCache creates a cache of the given number of shards, at least one
+func NewCache(shards int) *Cache {
+	if shards < 1 {
+		shards = 1
+	}
+	c := &Cache{shards: make([]*shard, shards)}
+	for i := range c.shards {
+		c.shards[i] = &shard{
+			devices: make(map[topodevice.ID]devicechange.TypedValueMap),
+			reads:   make(map[topodevice.ID]map[string]time.Time),
+			evicted: make(map[topodevice.ID]map[string]bool),
+		}
+	}
+	return c
+}
+
+// Shards returns the number of shards of the cache
+func (c *Cache) Shards() int {
+	return len(c.shards)
+}
+
+// SetBudget sets the estimated memory, in bytes, the cache may use before it evicts state; 0
+// leaves it unbounded
+func (c *Cache) SetBudget(budget int64) {
+	if budget < 0 {
+		budget = 0
+	}
+	atomic.StoreInt64(&c.budget, budget)
+}
+
+// Budget returns the memory budget of the cache, 0 if unbounded
+func (c *Cache) Budget() int64 {
+	return atomic.LoadInt64(&c.budget)
+}
+
+func (c *Cache) shardOf(deviceID topodevice.ID) *shard {
+	return c.shards[ShardOf(deviceID, len(c.shards))]
+}
+
+// Create creates the empty state of a device, replacing any state it had. The state is returned
+// with the lock of its shard, which its writers and readers must hold.
+func (c *Cache) Create(deviceID topodevice.ID) (devicechange.TypedValueMap, *sync.RWMutex) {
+	s := c.shardOf(deviceID)
+	values := make(devicechange.TypedValueMap)
+	s.mu.Lock()
+	s.devices[deviceID] = values
+	delete(s.reads, deviceID)
+	delete(s.evicted, deviceID)
+	s.mu.Unlock()
+	return values, &s.mu
+}
+
+// Put sets the state of a device, e.g. in tests
+func (c *Cache) Put(deviceID topodevice.ID, values devicechange.TypedValueMap) {
+	s := c.shardOf(deviceID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.devices[deviceID] = values
+	delete(s.reads, deviceID)
+	delete(s.evicted, deviceID)
+}
+
+// Delete deletes the state of a device
+func (c *Cache) Delete(deviceID topodevice.ID) {
+	s := c.shardOf(deviceID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.devices, deviceID)
+	delete(s.reads, deviceID)
+	delete(s.evicted, deviceID)
+}
+
+// Get returns a copy of the state of a device, and whether the device has a state
+func (c *Cache) Get(deviceID topodevice.ID) (devicechange.TypedValueMap, bool) {
+	values, _, ok := c.Read(deviceID, "/")
+	return values, ok
+}
+
+// Read returns a copy of the state of a device in the subtrees a path may be in, with those of
+// these subtrees that were evicted, and whether the device has a state. The subtrees are marked
+// as read.
+func (c *Cache) Read(deviceID topodevice.ID, path string) (devicechange.TypedValueMap, []string, bool) {
+	s := c.shardOf(deviceID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	values, ok := s.devices[deviceID]
+	if !ok {
+		return nil, nil, false
+	}
+	subtree := SubtreeOf(path)
+	all := subtree == "/" || subtree == "" || strings.Contains(subtree, "*") || strings.Contains(subtree, "...")
+	covers := func(other string) bool {
+		return all || other == subtree
+	}
+
+	now := time.Now()
+	reads := s.reads[deviceID]
+	if reads == nil {
+		reads = make(map[string]time.Time)
+		s.reads[deviceID] = reads
+	}
+	result := make(devicechange.TypedValueMap)
+	for p, value := range values {
+		if other := SubtreeOf(p); covers(other) {
+			result[p] = value
+			reads[other] = now
+		}
+	}
+	evicted := make([]string, 0)
+	for other := range s.evicted[deviceID] {
+		if covers(other) {
+			evicted = append(evicted, other)
+		}
+	}
+	sort.Strings(evicted)
+	return result, evicted, true
+}
+
+// Refill puts the values of an evicted subtree of a device, re-fetched from the device, back in
+// the cache; they are dropped if the device has no state anymore
+func (c *Cache) Refill(deviceID topodevice.ID, subtree string, values []*devicechange.PathValue) {
+	s := c.shardOf(deviceID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deviceValues, ok := s.devices[deviceID]
+	if !ok {
+		return
+	}
+	for _, value := range values {
+		deviceValues[value.Path] = value.Value
+	}
+	delete(s.evicted[deviceID], subtree)
+	if s.reads[deviceID] == nil {
+		s.reads[deviceID] = make(map[string]time.Time)
+	}
+	s.reads[deviceID][subtree] = time.Now()
+}
+
+// sizeOf estimates the memory used by a cached path
+func sizeOf(path string, value *devicechange.TypedValue) int64 {
+	size := int64(len(path) + entryOverhead)
+	if value != nil {
+		size += int64(len(value.Bytes) + 4*len(value.TypeOpts))
+	}
+	return size
+}
+
+// subtreeUsage is the memory used by a subtree of a device
+type subtreeUsage struct {
+	shard    *shard
+	device   topodevice.ID
+	subtree  string
+	size     int64
+	lastRead time.Time
+}
+
+// EnforceBudget evicts the subtrees of the devices read least recently, those never read first,
+// until the cache is within its budget. It returns the number of subtrees evicted.
+func (c *Cache) EnforceBudget() int {
+	budget := c.Budget()
+	if budget == 0 {
+		return 0
+	}
+	var total int64
+	usages := make([]*subtreeUsage, 0)
+	for _, s := range c.shards {
+		s.mu.RLock()
+		for deviceID, values := range s.devices {
+			subtrees := make(map[string]*subtreeUsage)
+			for path, value := range values {
+				subtree := SubtreeOf(path)
+				usage, ok := subtrees[subtree]
+				if !ok {
+					usage = &subtreeUsage{shard: s, device: deviceID, subtree: subtree, lastRead: s.reads[deviceID][subtree]}
+					subtrees[subtree] = usage
+					usages = append(usages, usage)
+				}
+				size := sizeOf(path, value)
+				usage.size += size
+				total += size
+			}
+		}
+		s.mu.RUnlock()
+	}
+	if total <= budget {
+		return 0
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		return usages[i].lastRead.Before(usages[j].lastRead)
+	})
+	evictions := 0
+	for _, usage := range usages {
+		if total <= budget {
+			break
+		}
+		total -= usage.shard.evict(usage.device, usage.subtree)
+		evictions++
+	}
+	log.Infof("Evicted %d subtrees of operational state to keep within %d bytes", evictions, budget)
+	return evictions
+}
+
+// evict drops the values of a subtree of a device, returning the memory they used
+func (s *shard) evict(deviceID topodevice.ID, subtree string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	values, ok := s.devices[deviceID]
+	if !ok {
+		return 0
+	}
+	var size int64
+	for path, value := range values {
+		if SubtreeOf(path) == subtree {
+			size += sizeOf(path, value)
+			delete(values, path)
+			s.evictedPaths++
+		}
+	}
+	if s.evicted[deviceID] == nil {
+		s.evicted[deviceID] = make(map[string]bool)
+	}
+	s.evicted[deviceID][subtree] = true
+	s.evictions++
+	log.Debugf("Evicted %s of %s from the operational state", subtree, deviceID)
+	return size
+}
+
+// ShardStats are the number of devices and paths of a shard, their estimated memory, and the
+// number of subtrees and paths evicted from the shard
+type ShardStats struct {
+	Shard        int
+	Devices      int
+	Paths        int
+	Bytes        int64
+	Evictions    uint64
+	EvictedPaths uint64
+}
+
+// Stats returns the statistics of each shard
+func (c *Cache) Stats() []ShardStats {
+	stats := make([]ShardStats, len(c.shards))
+	for i, s := range c.shards {
+		s.mu.RLock()
+		stats[i] = ShardStats{
+			Shard:        i,
+			Devices:      len(s.devices),
+			Evictions:    s.evictions,
+			EvictedPaths: s.evictedPaths,
+		}
+		for _, values := range s.devices {
+			stats[i].Paths += len(values)
+			for path, value := range values {
+				stats[i].Bytes += sizeOf(path, value)
+			}
+		}
+		s.mu.RUnlock()
+	}
+	return stats
+}
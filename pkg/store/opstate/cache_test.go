@@ -0,0 +1,138 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opstate
+
+import (
+	"fmt"
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	topodevice "github.com/onosproject/onos-config/pkg/device"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ShardOf(t *testing.T) {
+	assert.Equal(t, 0, ShardOf("device-1", 1))
+	assert.Equal(t, 0, ShardOf("device-1", 0))
+	used := make(map[int]bool)
+	for i := 0; i < 100; i++ {
+		deviceID := topodevice.ID(fmt.Sprintf("device-%d", i))
+		shard := ShardOf(deviceID, 4)
+		assert.Equal(t, shard, ShardOf(deviceID, 4))
+		assert.True(t, shard >= 0 && shard < 4)
+		used[shard] = true
+	}
+	assert.Len(t, used, 4)
+}
+
+func Test_Cache(t *testing.T) {
+	cache := NewCache(4)
+	assert.Equal(t, 4, cache.Shards())
+
+	values, lock := cache.Create("device-1")
+	lock.Lock()
+	values["/a/b"] = devicechange.NewTypedValueString("b")
+	values["/a/c"] = devicechange.NewTypedValueString("c")
+	lock.Unlock()
+	cache.Put("device-2", devicechange.TypedValueMap{"/a/b": devicechange.NewTypedValueString("b")})
+
+	state, ok := cache.Get("device-1")
+	assert.True(t, ok)
+	assert.Len(t, state, 2)
+	state["/a/d"] = devicechange.NewTypedValueString("d")
+	state, _ = cache.Get("device-1")
+	assert.Len(t, state, 2)
+
+	devices, paths := 0, 0
+	for _, stats := range cache.Stats() {
+		devices += stats.Devices
+		paths += stats.Paths
+	}
+	assert.Equal(t, 2, devices)
+	assert.Equal(t, 3, paths)
+
+	cache.Delete("device-1")
+	_, ok = cache.Get("device-1")
+	assert.False(t, ok)
+}
+
+func Test_SubtreeOf(t *testing.T) {
+	assert.Equal(t, "/interfaces", SubtreeOf("/interfaces/interface[name=eth1]/state"))
+	assert.Equal(t, "/a[name=x/y]", SubtreeOf("/a[name=x/y]/b"))
+	assert.Equal(t, "/system", SubtreeOf("/system"))
+	assert.Equal(t, "/", SubtreeOf("/"))
+}
+
+func Test_Read(t *testing.T) {
+	cache := NewCache(1)
+	cache.Put("device-1", devicechange.TypedValueMap{
+		"/a/b": devicechange.NewTypedValueString("b"),
+		"/x/y": devicechange.NewTypedValueString("y"),
+	})
+	values, evicted, ok := cache.Read("device-1", "/a/*")
+	assert.True(t, ok)
+	assert.Len(t, values, 1)
+	assert.Len(t, evicted, 0)
+	values, _, _ = cache.Read("device-1", "/*/b")
+	assert.Len(t, values, 2)
+	_, _, ok = cache.Read("device-2", "/a")
+	assert.False(t, ok)
+}
+
+func Test_EnforceBudget(t *testing.T) {
+	cache := NewCache(2)
+	for _, deviceID := range []topodevice.ID{"device-1", "device-2"} {
+		values, lock := cache.Create(deviceID)
+		lock.Lock()
+		for i := 0; i < 10; i++ {
+			values[fmt.Sprintf("/a/b%d", i)] = devicechange.NewTypedValueString("value")
+			values[fmt.Sprintf("/x/y%d", i)] = devicechange.NewTypedValueString("value")
+		}
+		lock.Unlock()
+	}
+	assert.Equal(t, 0, cache.EnforceBudget(), "an unbounded cache evicts nothing")
+
+	// /a of device-1 is the only subtree read: it is the only one kept
+	_, _, _ = cache.Read("device-1", "/a/b1")
+	var bytes int64
+	for _, stats := range cache.Stats() {
+		bytes += stats.Bytes
+	}
+	cache.SetBudget(bytes / 4)
+	assert.Equal(t, 3, cache.EnforceBudget())
+	assert.Equal(t, 0, cache.EnforceBudget())
+
+	values, evicted, _ := cache.Read("device-1", "/")
+	assert.Len(t, values, 10)
+	assert.Equal(t, []string{"/x"}, evicted)
+	values, evicted, _ = cache.Read("device-2", "/x/y1")
+	assert.Len(t, values, 0)
+	assert.Equal(t, []string{"/x"}, evicted)
+
+	var evictions, evictedPaths uint64
+	for _, stats := range cache.Stats() {
+		evictions += stats.Evictions
+		evictedPaths += stats.EvictedPaths
+	}
+	assert.Equal(t, uint64(3), evictions)
+	assert.Equal(t, uint64(30), evictedPaths)
+
+	cache.Refill("device-2", "/x", []*devicechange.PathValue{
+		{Path: "/x/y1", Value: devicechange.NewTypedValueString("value")},
+	})
+	values, evicted, _ = cache.Read("device-2", "/x/y1")
+	assert.Len(t, values, 1)
+	assert.Len(t, evicted, 0)
+}
@@ -0,0 +1,204 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ownership stores the claims automation systems make on subtrees of the configuration of
+// devices. The subtree a principal claims on a device may only be changed by that principal, so
+// that controllers do not fight over the same leaves.
+package ownership
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Claim is the ownership of a subtree of the configuration of a device by a principal
+type Claim struct {
+	// DeviceID is the device the subtree is claimed on
+	DeviceID devicetype.ID `json:"deviceId"`
+	// Prefix is the path of the subtree
+	Prefix string `json:"prefix"`
+	// Owner is the principal owning the subtree
+	Owner string `json:"owner"`
+	// Reason is why the subtree is claimed, e.g. the controller managing it
+	Reason string `json:"reason,omitempty"`
+	// Created is when the subtree was claimed
+	Created time.Time `json:"created"`
+}
+
+// Covers returns true if writing the path changes the claimed subtree, i.e. the path is in the
+// subtree. With ancestors, it also returns true if the path is an ancestor of the subtree, which
+// deleting it would delete.
+func (c *Claim) Covers(path string, ancestors bool) bool {
+	prefix, err := utils.ParseGNMIElements(utils.SplitPath(c.Prefix))
+	if err != nil {
+		return false
+	}
+	elems, err := utils.ParseGNMIElements(utils.SplitPath(path))
+	if err != nil {
+		return false
+	}
+	return covers(prefix.Elem, elems.Elem, ancestors)
+}
+
+// covers compares a path with a claimed subtree element by element. An element of the path
+// without keys stands for all the entries of its list.
+func covers(prefix []*gnmi.PathElem, elems []*gnmi.PathElem, ancestors bool) bool {
+	for i, prefixElem := range prefix {
+		if i == len(elems) {
+			return ancestors
+		}
+		elem := elems[i]
+		if prefixElem.Name != elem.Name {
+			return false
+		}
+		for key, value := range prefixElem.Key {
+			if elemValue, ok := elem.Key[key]; ok && elemValue != value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Store stores the ownership claims
+type Store interface {
+	io.Closer
+
+	// Create stores a claim. It fails with AlreadyExists if the subtree is already claimed on
+	// the device.
+	Create(claim *Claim) error
+
+	// Get gets the claim of a subtree of a device
+	Get(deviceID devicetype.ID, prefix string) (*Claim, error)
+
+	// Delete deletes the claim of a subtree of a device
+	Delete(deviceID devicetype.ID, prefix string) error
+
+	// List lists the claims on a device, sorted by prefix; those on all the devices, sorted by
+	// device, if deviceID is empty
+	List(deviceID devicetype.ID) ([]*Claim, error)
+}
+
+// kind describes the claims in the errors of the store
+const kind = "claim"
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	claims, err := records.NewAtomixMap(client, "onos-config-ownership-claims", kind)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		claims: claims,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps claims in memory
+func NewLocalStore() Store {
+	return &store{
+		claims: records.NewLocalMap(kind),
+	}
+}
+
+// store keeps the claims by device and prefix
+type store struct {
+	claims records.Map
+}
+
+func claimKey(deviceID devicetype.ID, prefix string) string {
+	return string(deviceID) + prefix
+}
+
+func (s *store) Create(claim *Claim) error {
+	if err := checkClaim(claim); err != nil {
+		return err
+	}
+	if err := s.claims.Create(claimKey(claim.DeviceID, claim.Prefix), claim); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return errors.NewAlreadyExists("%s of %s is already claimed", claim.Prefix, claim.DeviceID)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *store) Get(deviceID devicetype.ID, prefix string) (*Claim, error) {
+	claim := &Claim{}
+	if err := s.claims.Get(claimKey(deviceID, prefix), claim); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.NewNotFound("%s of %s is not claimed", prefix, deviceID)
+		}
+		return nil, err
+	}
+	return claim, nil
+}
+
+func (s *store) Delete(deviceID devicetype.ID, prefix string) error {
+	if err := s.claims.Delete(claimKey(deviceID, prefix)); err != nil {
+		if errors.IsNotFound(err) {
+			return errors.NewNotFound("%s of %s is not claimed", prefix, deviceID)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *store) List(deviceID devicetype.ID) ([]*Claim, error) {
+	list, err := s.claims.List(func() interface{} { return &Claim{} })
+	if err != nil {
+		return nil, err
+	}
+	claims := make([]*Claim, 0, len(list))
+	for _, record := range list {
+		if claim := record.(*Claim); deviceID == "" || claim.DeviceID == deviceID {
+			claims = append(claims, claim)
+		}
+	}
+	sortClaims(claims)
+	return claims, nil
+}
+
+func (s *store) Close() error {
+	return s.claims.Close()
+}
+
+func checkClaim(claim *Claim) error {
+	if claim.DeviceID == "" {
+		return errors.NewInvalid("no device given")
+	}
+	if claim.Prefix == "" {
+		return errors.NewInvalid("no path prefix given")
+	}
+	if claim.Owner == "" {
+		return errors.NewInvalid("no owner given")
+	}
+	return nil
+}
+
+func sortClaims(claims []*Claim) {
+	sort.Slice(claims, func(i, j int) bool {
+		if claims[i].DeviceID != claims[j].DeviceID {
+			return claims[i].DeviceID < claims[j].DeviceID
+		}
+		return claims[i].Prefix < claims[j].Prefix
+	})
+}
@@ -0,0 +1,96 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ownership
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaim_Covers(t *testing.T) {
+	claim := &Claim{Prefix: "/interfaces/interface[name=eth1]/config"}
+	assert.True(t, claim.Covers("/interfaces/interface[name=eth1]/config", false))
+	assert.True(t, claim.Covers("/interfaces/interface[name=eth1]/config/mtu", false))
+	assert.False(t, claim.Covers("/interfaces/interface[name=eth2]/config/mtu", false))
+	assert.False(t, claim.Covers("/interfaces/interface[name=eth1]/state/mtu", false))
+	assert.False(t, claim.Covers("/interfaces/interface[name=eth1]", false))
+
+	// Deleting an ancestor deletes the subtree, as does deleting all the entries of its list
+	assert.True(t, claim.Covers("/interfaces/interface[name=eth1]", true))
+	assert.True(t, claim.Covers("/interfaces", true))
+	assert.True(t, claim.Covers("/interfaces/interface/config", false))
+	assert.False(t, claim.Covers("/system", true))
+}
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	claim := &Claim{
+		DeviceID: "device-1",
+		Prefix:   "/interfaces/interface[name=eth1]",
+		Owner:    "fabric-controller",
+		Reason:   "uplinks",
+		Created:  time.Unix(1620000000, 0).UTC(),
+	}
+	assert.NoError(t, store.Create(claim))
+	assert.NoError(t, store.Create(&Claim{DeviceID: "device-2", Prefix: "/system", Owner: "aaa-sync"}))
+	assert.NoError(t, store.Create(&Claim{DeviceID: "device-1", Prefix: "/acl", Owner: "acl-sync"}))
+
+	stored, err := store.Get("device-1", "/interfaces/interface[name=eth1]")
+	assert.NoError(t, err)
+	assert.Equal(t, claim, stored)
+
+	other := *claim
+	other.Owner = "other-controller"
+	assert.True(t, errors.IsAlreadyExists(store.Create(&other)))
+	assert.True(t, errors.IsInvalid(store.Create(&Claim{DeviceID: "device-1", Prefix: "/system"})))
+
+	claims, err := store.List("device-1")
+	assert.NoError(t, err)
+	assert.Len(t, claims, 2)
+	assert.Equal(t, "/acl", claims[0].Prefix)
+	claims, err = store.List("")
+	assert.NoError(t, err)
+	assert.Len(t, claims, 3)
+	assert.Equal(t, "device-2", string(claims[2].DeviceID))
+
+	assert.NoError(t, store.Delete("device-1", "/acl"))
+	_, err = store.Get("device-1", "/acl")
+	assert.True(t, errors.IsNotFound(err))
+	assert.True(t, errors.IsNotFound(store.Delete("device-1", "/acl")))
+
+	// A released subtree can be claimed again by another owner
+	assert.NoError(t, store.Create(&Claim{DeviceID: "device-1", Prefix: "/acl", Owner: "other-controller"}))
+	stored, err = store.Get("device-1", "/acl")
+	assert.NoError(t, err)
+	assert.Equal(t, "other-controller", stored.Owner)
+
+	// The claims returned are copies
+	stored.Owner = "acl-sync"
+	stored, err = store.Get("device-1", "/acl")
+	assert.NoError(t, err)
+	assert.Equal(t, "other-controller", stored.Owner)
+
+	claims, err = store.List("device-3")
+	assert.NoError(t, err)
+	assert.Len(t, claims, 0)
+
+	assert.EqualError(t, store.Create(&other), "/interfaces/interface[name=eth1] of device-1 is already claimed")
+	assert.EqualError(t, store.Delete("device-2", "/acl"), "/acl of device-2 is not claimed")
+}
@@ -0,0 +1,129 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package quarantine stores the devices whose reported capabilities do not match the model
+// they are registered with. No configuration is pushed to a quarantined device.
+package quarantine
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Quarantine records why a device was quarantined
+type Quarantine struct {
+	// DeviceID is the quarantined device
+	DeviceID devicetype.ID `json:"deviceId"`
+	// DeviceType is the type of the model the device is registered with
+	DeviceType devicetype.Type `json:"deviceType"`
+	// DeviceVersion is the version of the model the device is registered with
+	DeviceVersion devicetype.Version `json:"deviceVersion"`
+	// Mismatches describes each model of the device model that the device does not report as is
+	Mismatches []string `json:"mismatches"`
+	// Created is when the device was quarantined
+	Created time.Time `json:"created"`
+}
+
+// Store stores the quarantined devices
+type Store interface {
+	io.Closer
+
+	// Get gets the quarantine of a device
+	Get(id devicetype.ID) (*Quarantine, error)
+
+	// Put quarantines a device, replacing any previous quarantine of it
+	Put(quarantine *Quarantine) error
+
+	// Delete lifts the quarantine of a device
+	Delete(id devicetype.ID) error
+
+	// List lists the quarantined devices, sorted by device ID
+	List() ([]*Quarantine, error)
+}
+
+// kind and notFound describe the quarantines in the errors of the store
+const kind = "quarantine"
+
+var notFound = records.WithNotFound("device '%s' is not quarantined")
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	quarantines, err := records.NewAtomixMap(client, "onos-config-quarantines", kind, notFound)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		quarantines: quarantines,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps quarantines in memory
+func NewLocalStore() Store {
+	return &store{
+		quarantines: records.NewLocalMap(kind, notFound),
+	}
+}
+
+// store keeps the quarantines by device ID
+type store struct {
+	quarantines records.Map
+}
+
+func (s *store) Get(id devicetype.ID) (*Quarantine, error) {
+	quarantine := &Quarantine{}
+	if err := s.quarantines.Get(string(id), quarantine); err != nil {
+		return nil, err
+	}
+	return quarantine, nil
+}
+
+func (s *store) Put(quarantine *Quarantine) error {
+	if quarantine.DeviceID == "" {
+		return errors.NewInvalid("no device ID given")
+	}
+	return s.quarantines.Put(string(quarantine.DeviceID), quarantine)
+}
+
+func (s *store) Delete(id devicetype.ID) error {
+	return s.quarantines.Delete(string(id))
+}
+
+func (s *store) List() ([]*Quarantine, error) {
+	list, err := s.quarantines.List(func() interface{} { return &Quarantine{} })
+	if err != nil {
+		return nil, err
+	}
+	quarantines := make([]*Quarantine, 0, len(list))
+	for _, record := range list {
+		quarantines = append(quarantines, record.(*Quarantine))
+	}
+	sortQuarantines(quarantines)
+	return quarantines, nil
+}
+
+func (s *store) Close() error {
+	return s.quarantines.Close()
+}
+
+func sortQuarantines(quarantines []*Quarantine) {
+	sort.Slice(quarantines, func(i, j int) bool {
+		return quarantines[i].DeviceID < quarantines[j].DeviceID
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quarantine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	assert.NoError(t, store.Put(&Quarantine{
+		DeviceID:      "device-2",
+		DeviceType:    "Devicesim",
+		DeviceVersion: "1.0.0",
+		Mismatches:    []string{"openconfig-interfaces 2.0.0 is reported as 2.4.3"},
+		Created:       time.Now(),
+	}))
+	assert.NoError(t, store.Put(&Quarantine{DeviceID: "device-1", DeviceType: "Devicesim", DeviceVersion: "1.0.0"}))
+	assert.True(t, errors.IsInvalid(store.Put(&Quarantine{})))
+
+	quarantines, err := store.List()
+	assert.NoError(t, err)
+	assert.Len(t, quarantines, 2)
+	assert.Equal(t, "device-1", string(quarantines[0].DeviceID))
+	assert.Equal(t, "device-2", string(quarantines[1].DeviceID))
+
+	quarantine, err := store.Get("device-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0", string(quarantine.DeviceVersion))
+	assert.Equal(t, []string{"openconfig-interfaces 2.0.0 is reported as 2.4.3"}, quarantine.Mismatches)
+
+	assert.NoError(t, store.Delete("device-2"))
+	_, err = store.Get("device-2")
+	assert.True(t, errors.IsNotFound(err))
+	assert.True(t, errors.IsNotFound(store.Delete("device-2")))
+
+	// A device quarantined again keeps only its latest mismatches
+	assert.NoError(t, store.Put(&Quarantine{DeviceID: "device-1", DeviceType: "Devicesim", DeviceVersion: "1.0.0", Mismatches: []string{"a"}}))
+	assert.NoError(t, store.Put(&Quarantine{DeviceID: "device-1", DeviceType: "Devicesim", DeviceVersion: "1.0.1", Mismatches: []string{"b"}}))
+	quarantine, err = store.Get("device-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.1", string(quarantine.DeviceVersion))
+	assert.Equal(t, []string{"b"}, quarantine.Mismatches)
+
+	// The quarantines returned are copies
+	quarantine.Mismatches[0] = "c"
+	quarantine, err = store.Get("device-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b"}, quarantine.Mismatches)
+
+	assert.EqualError(t, store.Delete("device-3"), "device 'device-3' is not quarantined")
+}
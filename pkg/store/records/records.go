@@ -0,0 +1,289 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package records keeps the JSON encoded records of the stores of plain Go structs, in an Atomix
+// map or, when no persistent store is configured, in memory. Each of these stores is written once
+// over a Map, whichever keeps its records, and the records read from either are copies.
+package records
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Map is a map of JSON encoded records. The errors are those of the onos-lib-go errors package,
+// whose messages name the records after the kind of the map, e.g. "trust bundle 'lab-ca' not found".
+type Map interface {
+	io.Closer
+
+	// Get decodes the record of a key into record; it fails with NotFound if there is none
+	Get(key string, record interface{}) error
+
+	// Put creates or replaces the record of a key
+	Put(key string, record interface{}) error
+
+	// Create creates the record of a key; it fails with AlreadyExists if there is one
+	Create(key string, record interface{}) error
+
+	// Delete deletes the record of a key; it fails with NotFound if there is none
+	Delete(key string) error
+
+	// List decodes every record into a new record returned by newRecord, in no particular order
+	List(newRecord func() interface{}) ([]interface{}, error)
+}
+
+// Option is an option of a Map
+type Option func(*kind)
+
+// WithNotFound sets the message of the NotFound errors, formatted with the key of the record
+func WithNotFound(format string) Option {
+	return func(kind *kind) {
+		kind.notFound = format
+	}
+}
+
+// kind describes the records of a map in the messages of its errors
+type kind struct {
+	name     string
+	notFound string
+}
+
+func newKind(name string, options []Option) kind {
+	kind := kind{
+		name:     name,
+		notFound: name + " '%s' not found",
+	}
+	for _, option := range options {
+		option(&kind)
+	}
+	return kind
+}
+
+// NewAtomixMap returns a Map keeping the records of the given kind in the Atomix map of the given name
+func NewAtomixMap(client atomix.Client, name string, kind string, options ...Option) (Map, error) {
+	records, err := client.GetMap(context.Background(), name)
+	if err != nil {
+		return nil, errors.FromAtomix(err)
+	}
+	return &atomixMap{
+		records: records,
+		kind:    newKind(kind, options),
+	}, nil
+}
+
+// atomixMap is the persistent Map
+type atomixMap struct {
+	records _map.Map
+	kind    kind
+}
+
+func (m *atomixMap) Get(key string, record interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	entry, err := m.records.Get(ctx, key)
+	if err != nil {
+		if err = errors.FromAtomix(err); errors.IsNotFound(err) {
+			return notFound(m.kind, key)
+		}
+		return err
+	} else if entry == nil {
+		return notFound(m.kind, key)
+	}
+	return decode(m.kind, entry.Value, record)
+}
+
+func (m *atomixMap) Put(key string, record interface{}) error {
+	bytes, err := encode(m.kind, record)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if _, err := m.records.Put(ctx, key, bytes); err != nil {
+		return errors.FromAtomix(err)
+	}
+	return nil
+}
+
+func (m *atomixMap) Create(key string, record interface{}) error {
+	bytes, err := encode(m.kind, record)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if _, err := m.records.Put(ctx, key, bytes, _map.IfNotSet()); err != nil {
+		if err = errors.FromAtomix(err); errors.IsConflict(err) || errors.IsAlreadyExists(err) {
+			return errors.NewAlreadyExists("%s '%s' already exists", m.kind.name, key)
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *atomixMap) Delete(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	entry, err := m.records.Remove(ctx, key)
+	if err != nil {
+		if err = errors.FromAtomix(err); errors.IsNotFound(err) {
+			return notFound(m.kind, key)
+		}
+		return err
+	} else if entry == nil {
+		return notFound(m.kind, key)
+	}
+	return nil
+}
+
+func (m *atomixMap) List(newRecord func() interface{}) ([]interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	entries := make(chan _map.Entry)
+	if err := m.records.Entries(ctx, entries); err != nil {
+		return nil, errors.FromAtomix(err)
+	}
+	var err error
+	records := make([]interface{}, 0)
+	for entry := range entries {
+		if err != nil {
+			continue
+		}
+		record := newRecord()
+		if err = decode(m.kind, entry.Value, record); err == nil {
+			records = append(records, record)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (m *atomixMap) Close() error {
+	if err := m.records.Close(context.Background()); err != nil {
+		return errors.FromAtomix(err)
+	}
+	return nil
+}
+
+// NewLocalMap returns a Map only keeping the records of the given kind in memory
+func NewLocalMap(kind string, options ...Option) Map {
+	return &localMap{
+		records: make(map[string][]byte),
+		kind:    newKind(kind, options),
+	}
+}
+
+// localMap is the in-memory Map. The records are kept encoded, so that they are copied as those of
+// the persistent Map are.
+type localMap struct {
+	mu      sync.RWMutex
+	records map[string][]byte
+	kind    kind
+}
+
+func (m *localMap) Get(key string, record interface{}) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	bytes, ok := m.records[key]
+	if !ok {
+		return notFound(m.kind, key)
+	}
+	return decode(m.kind, bytes, record)
+}
+
+func (m *localMap) Put(key string, record interface{}) error {
+	bytes, err := encode(m.kind, record)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[key] = bytes
+	return nil
+}
+
+func (m *localMap) Create(key string, record interface{}) error {
+	bytes, err := encode(m.kind, record)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.records[key]; ok {
+		return errors.NewAlreadyExists("%s '%s' already exists", m.kind.name, key)
+	}
+	m.records[key] = bytes
+	return nil
+}
+
+func (m *localMap) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.records[key]; !ok {
+		return notFound(m.kind, key)
+	}
+	delete(m.records, key)
+	return nil
+}
+
+func (m *localMap) List(newRecord func() interface{}) ([]interface{}, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	records := make([]interface{}, 0, len(m.records))
+	for _, bytes := range m.records {
+		record := newRecord()
+		if err := decode(m.kind, bytes, record); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (m *localMap) Close() error {
+	return nil
+}
+
+func notFound(kind kind, key string) error {
+	return errors.NewNotFound(kind.notFound, key)
+}
+
+func encode(kind kind, record interface{}) ([]byte, error) {
+	bytes, err := json.Marshal(record)
+	if err != nil {
+		return nil, errors.NewInvalid("%s encoding failed: %v", kind.name, err)
+	}
+	return bytes, nil
+}
+
+func decode(kind kind, bytes []byte, record interface{}) error {
+	if err := json.Unmarshal(bytes, record); err != nil {
+		return errors.NewInvalid("%s decoding failed: %v", kind.name, err)
+	}
+	return nil
+}
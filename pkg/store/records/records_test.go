@@ -0,0 +1,91 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package records
+
+import (
+	"testing"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/test"
+	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type record struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+func testMap(t *testing.T, records Map) {
+	assert.NoError(t, records.Put("a", &record{Name: "a", Value: 1}))
+	assert.NoError(t, records.Put("a", &record{Name: "a", Value: 2}))
+	assert.NoError(t, records.Create("b", &record{Name: "b", Value: 3}))
+	err := records.Create("b", &record{Name: "b", Value: 4})
+	assert.True(t, errors.IsAlreadyExists(err))
+	assert.Equal(t, "record 'b' already exists", err.Error())
+
+	a := &record{}
+	assert.NoError(t, records.Get("a", a))
+	assert.Equal(t, 2, a.Value)
+	// The records read are copies
+	a.Value = 5
+	a = &record{}
+	assert.NoError(t, records.Get("a", a))
+	assert.Equal(t, 2, a.Value)
+
+	list, err := records.List(func() interface{} { return &record{} })
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	values := map[string]int{}
+	for _, r := range list {
+		values[r.(*record).Name] = r.(*record).Value
+	}
+	assert.Equal(t, map[string]int{"a": 2, "b": 3}, values)
+
+	assert.NoError(t, records.Delete("a"))
+	err = records.Get("a", &record{})
+	assert.True(t, errors.IsNotFound(err))
+	assert.Equal(t, "record 'a' not found", err.Error())
+	assert.True(t, errors.IsNotFound(records.Delete("a")))
+	assert.True(t, errors.IsInvalid(records.Get("b", &[]string{})))
+
+	assert.NoError(t, records.Close())
+}
+
+func TestNotFound(t *testing.T) {
+	records := NewLocalMap("pause", WithNotFound("network change '%s' is not paused"))
+	err := records.Delete("change-1")
+	assert.True(t, errors.IsNotFound(err))
+	assert.Equal(t, "network change 'change-1' is not paused", err.Error())
+}
+
+func TestLocalMap(t *testing.T) {
+	testMap(t, NewLocalMap("record"))
+}
+
+func TestAtomixMap(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	client, err := test.NewClient("node-1")
+	assert.NoError(t, err)
+	records, err := NewAtomixMap(client, "onos-config-test-records", "record")
+	assert.NoError(t, err)
+	testMap(t, records)
+}
@@ -0,0 +1,153 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sampling stores the sample intervals set by operators for the subscriptions to the
+// state of the devices, so that they survive restarts.
+package sampling
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Interval is the sampling of the state of a device, or of a subtree of it
+type Interval struct {
+	// DeviceID is the ID of the device
+	DeviceID string `json:"deviceId"`
+	// Prefix is the subtree of the state sampled, wildcards allowed; empty for the whole state
+	Prefix string `json:"prefix"`
+	// SampleInterval is how often the device sends the values of the subtree
+	SampleInterval time.Duration `json:"sampleInterval"`
+	// HeartbeatInterval is how often the device sends the values that did not change, when it
+	// suppresses them otherwise; 0 to leave it to the device
+	HeartbeatInterval time.Duration `json:"heartbeatInterval"`
+	// User is the user who set the interval
+	User string `json:"user"`
+	// Updated is when the interval was set
+	Updated time.Time `json:"updated"`
+}
+
+// Store stores the sample intervals
+type Store interface {
+	io.Closer
+
+	// Get gets the sample interval of a subtree of the state of a device
+	Get(deviceID string, prefix string) (*Interval, error)
+
+	// Put sets a sample interval, replacing any previous one of the subtree
+	Put(interval *Interval) error
+
+	// Delete deletes the sample interval of a subtree of the state of a device
+	Delete(deviceID string, prefix string) error
+
+	// List lists the sample intervals, sorted by device and prefix
+	List() ([]*Interval, error)
+}
+
+// kind describes the sample intervals in the errors of the store
+const kind = "sample interval"
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	intervals, err := records.NewAtomixMap(client, "onos-config-sample-intervals", kind)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		intervals: intervals,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps sample intervals in memory
+func NewLocalStore() Store {
+	return &store{
+		intervals: records.NewLocalMap(kind),
+	}
+}
+
+// store keeps the sample intervals by device and prefix
+type store struct {
+	intervals records.Map
+}
+
+func (s *store) Get(deviceID string, prefix string) (*Interval, error) {
+	interval := &Interval{}
+	if err := s.intervals.Get(key(deviceID, prefix), interval); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, notFound(deviceID, prefix)
+		}
+		return nil, err
+	}
+	return interval, nil
+}
+
+func (s *store) Put(interval *Interval) error {
+	if interval.DeviceID == "" {
+		return errors.NewInvalid("no device given")
+	}
+	return s.intervals.Put(key(interval.DeviceID, interval.Prefix), interval)
+}
+
+func (s *store) Delete(deviceID string, prefix string) error {
+	if err := s.intervals.Delete(key(deviceID, prefix)); err != nil {
+		if errors.IsNotFound(err) {
+			return notFound(deviceID, prefix)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *store) List() ([]*Interval, error) {
+	list, err := s.intervals.List(func() interface{} { return &Interval{} })
+	if err != nil {
+		return nil, err
+	}
+	intervals := make([]*Interval, 0, len(list))
+	for _, record := range list {
+		intervals = append(intervals, record.(*Interval))
+	}
+	sortIntervals(intervals)
+	return intervals, nil
+}
+
+func (s *store) Close() error {
+	return s.intervals.Close()
+}
+
+// key is the key of the sample interval of a subtree of the state of a device
+func key(deviceID string, prefix string) string {
+	return deviceID + "|" + prefix
+}
+
+func notFound(deviceID string, prefix string) error {
+	if prefix == "" {
+		return errors.NewNotFound("device '%s' has no sample interval", deviceID)
+	}
+	return errors.NewNotFound("device '%s' has no sample interval for %s", deviceID, prefix)
+}
+
+func sortIntervals(intervals []*Interval) {
+	sort.Slice(intervals, func(i, j int) bool {
+		if intervals[i].DeviceID != intervals[j].DeviceID {
+			return intervals[i].DeviceID < intervals[j].DeviceID
+		}
+		return intervals[i].Prefix < intervals[j].Prefix
+	})
+}
@@ -0,0 +1,76 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	assert.NoError(t, store.Put(&Interval{
+		DeviceID:          "device-2",
+		SampleInterval:    time.Minute,
+		HeartbeatInterval: 10 * time.Minute,
+		User:              "alice",
+		Updated:           time.Now(),
+	}))
+	assert.NoError(t, store.Put(&Interval{DeviceID: "device-1", Prefix: "/interfaces", SampleInterval: time.Second}))
+	assert.NoError(t, store.Put(&Interval{DeviceID: "device-1", SampleInterval: 30 * time.Second}))
+	assert.True(t, errors.IsInvalid(store.Put(&Interval{SampleInterval: time.Second})))
+
+	intervals, err := store.List()
+	assert.NoError(t, err)
+	assert.Len(t, intervals, 3)
+	assert.Equal(t, "device-1", intervals[0].DeviceID)
+	assert.Equal(t, "", intervals[0].Prefix)
+	assert.Equal(t, "/interfaces", intervals[1].Prefix)
+	assert.Equal(t, "device-2", intervals[2].DeviceID)
+
+	interval, err := store.Get("device-2", "")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, interval.SampleInterval)
+	assert.Equal(t, 10*time.Minute, interval.HeartbeatInterval)
+	assert.Equal(t, "alice", interval.User)
+
+	assert.NoError(t, store.Delete("device-1", "/interfaces"))
+	_, err = store.Get("device-1", "/interfaces")
+	assert.True(t, errors.IsNotFound(err))
+	assert.True(t, errors.IsNotFound(store.Delete("device-1", "/interfaces")))
+	_, err = store.Get("device-1", "")
+	assert.NoError(t, err)
+
+	// An interval set again replaces the earlier one
+	assert.NoError(t, store.Put(&Interval{DeviceID: "device-2", SampleInterval: 5 * time.Second}))
+	interval, err = store.Get("device-2", "")
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, interval.SampleInterval)
+	assert.Equal(t, time.Duration(0), interval.HeartbeatInterval)
+
+	// The intervals returned are copies
+	interval.SampleInterval = time.Hour
+	interval, err = store.Get("device-2", "")
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, interval.SampleInterval)
+
+	assert.EqualError(t, store.Delete("device-1", "/interfaces"), "device 'device-1' has no sample interval for /interfaces")
+	assert.EqualError(t, store.Delete("device-3", ""), "device 'device-3' has no sample interval")
+}
@@ -0,0 +1,236 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// encryptedMarker starts the encrypted records: like marker, it cannot start an encoded proto.
+// An encrypted record is the marker followed by the uvarint schema version, the uvarint length
+// of the ID of the key it is encrypted with, the key ID and the sealed proto. The version and the
+// key ID are kept in the clear, so that the outdated records can be found without decrypting them,
+// and are authenticated along with the proto.
+const encryptedMarker byte = 0x01
+
+// Encryptor encrypts the records at rest with key encryption keys identified by ID, so that the
+// records encrypted with a previous key can still be read once the key is rotated. Encryptors
+// may keep their keys outside onos-config, e.g. in a KMS; Keyring keeps them in memory.
+type Encryptor interface {
+	// KeyID returns the ID of the key the records are encrypted with
+	KeyID() string
+	// Seal encrypts a record with the key of KeyID, authenticating the additional data with it
+	Seal(plaintext []byte, additionalData []byte) ([]byte, error)
+	// Open decrypts a record sealed with the key of an ID
+	Open(keyID string, sealed []byte, additionalData []byte) ([]byte, error)
+}
+
+var (
+	encryptionMu      sync.RWMutex
+	encryptor         Encryptor
+	encryptionEnabled bool
+)
+
+// SetEncryptor sets the encryptor of the records written from now on; with nil, records are
+// written in the clear. Encrypted records can only be read while the encryptor can open them.
+func SetEncryptor(e Encryptor) {
+	encryptionMu.Lock()
+	defer encryptionMu.Unlock()
+	encryptor = e
+}
+
+// SetEncryptionEnabled sets whether every node of the cluster reads encrypted records, as
+// negotiated through the leadership store. Until it does, the records are written in the clear
+// even if an encryptor is set, so that the nodes of the previous release still read them.
+func SetEncryptionEnabled(enabled bool) {
+	encryptionMu.Lock()
+	defer encryptionMu.Unlock()
+	encryptionEnabled = enabled
+}
+
+func getEncryptor() Encryptor {
+	encryptionMu.RLock()
+	defer encryptionMu.RUnlock()
+	return encryptor
+}
+
+// getSealer returns the encryptor the records are written with, nil if they are written in the
+// clear
+func getSealer() Encryptor {
+	encryptionMu.RLock()
+	defer encryptionMu.RUnlock()
+	if !encryptionEnabled {
+		return nil
+	}
+	return encryptor
+}
+
+// currentKeyID returns the ID of the key the records are encrypted with, empty when they are
+// written in the clear
+func currentKeyID() string {
+	if e := getSealer(); e != nil {
+		return e.KeyID()
+	}
+	return ""
+}
+
+// seal encrypts the encoded proto of a record at a version
+func seal(e Encryptor, version Version, payload []byte) ([]byte, error) {
+	keyID := e.KeyID()
+	header := make([]byte, 1+2*binary.MaxVarintLen64+len(keyID))
+	header[0] = encryptedMarker
+	n := 1 + binary.PutUvarint(header[1:], uint64(version))
+	n += binary.PutUvarint(header[n:], uint64(len(keyID)))
+	n += copy(header[n:], keyID)
+	header = header[:n]
+	sealed, err := e.Seal(payload, header)
+	if err != nil {
+		return nil, errors.NewInternal("record encryption with key %s failed: %v", keyID, err)
+	}
+	return append(header, sealed...), nil
+}
+
+// open decrypts the encoded proto of an encrypted record
+func open(kind Kind, r record) ([]byte, error) {
+	e := getEncryptor()
+	if e == nil {
+		return nil, errors.NewUnavailable("%s record is encrypted with key %s but no storage encryption is configured", kind, r.keyID)
+	}
+	payload, err := e.Open(r.keyID, r.body, r.header)
+	if err != nil {
+		return nil, errors.NewForbidden("%s record decryption with key %s failed: %v", kind, r.keyID, err)
+	}
+	return payload, nil
+}
+
+// Keyring is an Encryptor sealing the records with AES-256-GCM under one of its keys, and opening
+// them with any of its keys
+type Keyring struct {
+	keyID string
+	keys  map[string]cipher.AEAD
+}
+
+// NewKeyring returns a keyring of 32 byte key encryption keys by ID, encrypting the records with
+// the key of keyID
+func NewKeyring(keyID string, keys map[string][]byte) (*Keyring, error) {
+	keyring := &Keyring{
+		keyID: keyID,
+		keys:  make(map[string]cipher.AEAD),
+	}
+	for id, key := range keys {
+		if id == "" {
+			return nil, errors.NewInvalid("key encryption keys must have an ID")
+		}
+		if len(key) != 32 {
+			return nil, errors.NewInvalid("key encryption key %s must have 32 bytes, not %d", id, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, errors.NewInvalid("invalid key encryption key %s: %v", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, errors.NewInvalid("invalid key encryption key %s: %v", id, err)
+		}
+		keyring.keys[id] = aead
+	}
+	if _, ok := keyring.keys[keyID]; !ok {
+		return nil, errors.NewInvalid("no key encryption key %s", keyID)
+	}
+	return keyring, nil
+}
+
+// LoadKeyring returns a keyring of the key encryption keys of a directory, each in a file named
+// after its ID with the .key extension and holding 32 bytes, base64 encoded e.g. by
+// "openssl rand -base64 32". The records are encrypted with the key of keyID, which may be empty
+// if the directory has a single key.
+func LoadKeyring(dir string, keyID string) (*Keyring, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.key"))
+	if err != nil {
+		return nil, errors.NewInvalid("cannot list the key encryption keys: %v", err)
+	}
+	keys := make(map[string][]byte)
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, errors.NewInvalid("cannot read the key encryption key: %v", err)
+		}
+		id := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, errors.NewInvalid("key encryption key %s is not valid base64: %v", id, err)
+		}
+		keys[id] = key
+	}
+	if keyID == "" {
+		if len(keys) != 1 {
+			return nil, errors.NewInvalid("%d key encryption keys in %s: the key to encrypt with must be given", len(keys), dir)
+		}
+		for id := range keys {
+			keyID = id
+		}
+	}
+	return NewKeyring(keyID, keys)
+}
+
+// KeyID returns the ID of the key the records are encrypted with
+func (k *Keyring) KeyID() string {
+	return k.keyID
+}
+
+// KeyIDs returns the sorted IDs of the keys of the keyring
+func (k *Keyring) KeyIDs() []string {
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Seal encrypts a record with the key of KeyID, prefixed with a random nonce
+func (k *Keyring) Seal(plaintext []byte, additionalData []byte) ([]byte, error) {
+	aead := k.keys[k.keyID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.NewInternal("cannot generate a nonce: %v", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// Open decrypts a record sealed with the key of an ID
+func (k *Keyring) Open(keyID string, sealed []byte, additionalData []byte) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, errors.NewNotFound("no key encryption key %s", keyID)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.NewInvalid("the record is truncated")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
@@ -0,0 +1,163 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func setEncryptor(t *testing.T, e Encryptor) {
+	SetEncryptor(e)
+	SetEncryptionEnabled(true)
+	t.Cleanup(func() {
+		SetEncryptor(nil)
+		SetEncryptionEnabled(false)
+	})
+}
+
+func TestEncryption(t *testing.T) {
+	keyring, err := NewKeyring("kek-1", map[string][]byte{"kek-1": testKey(1)})
+	assert.NoError(t, err)
+	setEncryptor(t, keyring)
+
+	value, err := Marshal(NetworkChange, &types.StringValue{Value: "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, encryptedMarker, value[0])
+	assert.False(t, bytes.Contains(value, []byte("secret")))
+
+	version, err := VersionOf(value)
+	assert.NoError(t, err)
+	assert.Equal(t, Initial, version)
+	keyID, err := KeyIDOf(value)
+	assert.NoError(t, err)
+	assert.Equal(t, "kek-1", keyID)
+
+	decoded := &types.StringValue{}
+	assert.NoError(t, Unmarshal(NetworkChange, value, decoded))
+	assert.Equal(t, "secret", decoded.Value)
+
+	// the header is authenticated with the record
+	tampered := append([]byte{}, value...)
+	tampered[1] = byte(Initial + 1)
+	assert.True(t, errors.IsForbidden(Unmarshal(NetworkChange, tampered, decoded)))
+	_, err = VersionOf(value[:3])
+	assert.True(t, errors.IsInvalid(err))
+
+	// records in the clear are still read
+	stored := &types.StringValue{}
+	SetEncryptor(nil)
+	plain, err := Marshal(NetworkChange, &types.StringValue{Value: "plain"})
+	assert.NoError(t, err)
+	assert.True(t, errors.IsUnavailable(Unmarshal(NetworkChange, value, decoded)))
+	SetEncryptor(keyring)
+	assert.NoError(t, Unmarshal(NetworkChange, plain, stored))
+	assert.Equal(t, "plain", stored.Value)
+}
+
+func TestEncryptionNotNegotiated(t *testing.T) {
+	keyring, err := NewKeyring("kek-1", map[string][]byte{"kek-1": testKey(1)})
+	assert.NoError(t, err)
+	setEncryptor(t, keyring)
+	SetEncryptionEnabled(false)
+
+	// until every node reads encrypted records, they are written in the clear and not migrated
+	value, err := Marshal(NetworkChange, &types.StringValue{Value: "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, marker, value[0])
+	report := Report{Kind: NetworkChange, Version: WriteVersion(NetworkChange)}
+	assert.False(t, report.count(value))
+
+	SetEncryptionEnabled(true)
+	assert.True(t, report.count(value))
+	value, err = Marshal(NetworkChange, &types.StringValue{Value: "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, encryptedMarker, value[0])
+}
+
+func TestKeyRotation(t *testing.T) {
+	previous, err := NewKeyring("kek-1", map[string][]byte{"kek-1": testKey(1)})
+	assert.NoError(t, err)
+	setEncryptor(t, previous)
+	value, err := Marshal(NetworkChange, &types.StringValue{Value: "secret"})
+	assert.NoError(t, err)
+	plain := []byte{marker, byte(Initial)}
+
+	rotated, err := NewKeyring("kek-2", map[string][]byte{"kek-1": testKey(1), "kek-2": testKey(2)})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kek-1", "kek-2"}, rotated.KeyIDs())
+	SetEncryptor(rotated)
+
+	decoded := &types.StringValue{}
+	assert.NoError(t, Unmarshal(NetworkChange, value, decoded))
+	assert.Equal(t, "secret", decoded.Value)
+
+	// the records in the clear or under the previous key are migrated to the current key
+	report := Report{Kind: NetworkChange, Version: WriteVersion(NetworkChange)}
+	assert.True(t, report.count(value))
+	assert.True(t, report.count(plain))
+	migrated, ok := report.upgrade(value)
+	assert.True(t, ok)
+	assert.False(t, report.count(migrated))
+	keyID, err := KeyIDOf(migrated)
+	assert.NoError(t, err)
+	assert.Equal(t, "kek-2", keyID)
+	assert.Equal(t, Report{Kind: NetworkChange, Version: Initial, Scanned: 3, Outdated: 2}, report)
+
+	// records under a removed key cannot be read
+	current, err := NewKeyring("kek-2", map[string][]byte{"kek-2": testKey(2)})
+	assert.NoError(t, err)
+	SetEncryptor(current)
+	assert.True(t, errors.IsForbidden(Unmarshal(NetworkChange, value, decoded)))
+	_, ok = report.upgrade(value)
+	assert.False(t, ok)
+}
+
+func TestLoadKeyring(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data string) {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0600))
+	}
+	write("kek-1.key", base64.StdEncoding.EncodeToString(testKey(1))+"\n")
+	write("README", "not a key")
+
+	keyring, err := LoadKeyring(dir, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "kek-1", keyring.KeyID())
+
+	write("kek-2.key", base64.StdEncoding.EncodeToString(testKey(2)))
+	_, err = LoadKeyring(dir, "")
+	assert.True(t, errors.IsInvalid(err))
+	keyring, err = LoadKeyring(dir, "kek-2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kek-1", "kek-2"}, keyring.KeyIDs())
+	_, err = LoadKeyring(dir, "kek-3")
+	assert.True(t, errors.IsInvalid(err))
+
+	write("short.key", base64.StdEncoding.EncodeToString(testKey(3)[:16]))
+	_, err = LoadKeyring(dir, "kek-2")
+	assert.True(t, errors.IsInvalid(err))
+}
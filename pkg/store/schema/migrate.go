@@ -0,0 +1,159 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"context"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix/indexedmap"
+	_map "github.com/atomix/atomix-go-client/pkg/atomix/map"
+	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+const writeTimeout = 15 * time.Second
+
+// Report is the outcome of the migration of the records of a store
+type Report struct {
+	// Store is the name of the migrated store
+	Store string
+	// Kind is the kind of the records of the store
+	Kind Kind
+	// Version is the version the records are migrated to, i.e. the write version of their kind
+	Version Version
+	// Scanned is the number of records read
+	Scanned int
+	// Outdated is the number of records stored at a previous version, or not encrypted with the
+	// key records are encrypted with, e.g. stored in the clear or under a rotated key
+	Outdated int
+	// Migrated is the number of records rewritten at the write version, with the current key. Outdated records that
+	// are neither migrated nor failed were rewritten or removed concurrently by their store.
+	Migrated int
+	// Failed is the number of records that could not be migrated
+	Failed int
+}
+
+// Migrator is implemented by the stores whose records can be migrated by a migration job
+type Migrator interface {
+	// Migrate rewrites the outdated records of the store at the write version of their kind.
+	// A dry run only counts the outdated records.
+	Migrate(dryRun bool) ([]Report, error)
+}
+
+// MigrateIndexedMap migrates the outdated records of an indexed map
+func MigrateIndexedMap(store string, kind Kind, m indexedmap.IndexedMap, dryRun bool) (Report, error) {
+	report := Report{Store: store, Kind: kind, Version: WriteVersion(kind)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	entries := make(chan indexedmap.Entry)
+	if err := m.Entries(ctx, entries); err != nil {
+		return report, errors.FromAtomix(err)
+	}
+	var outdated []indexedmap.Entry
+	for entry := range entries {
+		if report.count(entry.Value) {
+			outdated = append(outdated, entry)
+		}
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	for _, entry := range outdated {
+		value, ok := report.upgrade(entry.Value)
+		if !ok {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		_, err := m.Set(ctx, entry.Index, entry.Key, value, indexedmap.IfMatch(meta.NewRevision(entry.Revision)))
+		cancel()
+		report.written(errors.FromAtomix(err))
+	}
+	return report, nil
+}
+
+// MigrateMap migrates the outdated records of a map
+func MigrateMap(store string, kind Kind, m _map.Map, dryRun bool) (Report, error) {
+	report := Report{Store: store, Kind: kind, Version: WriteVersion(kind)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	entries := make(chan _map.Entry)
+	if err := m.Entries(ctx, entries); err != nil {
+		return report, errors.FromAtomix(err)
+	}
+	var outdated []_map.Entry
+	for entry := range entries {
+		if report.count(entry.Value) {
+			outdated = append(outdated, entry)
+		}
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	for _, entry := range outdated {
+		value, ok := report.upgrade(entry.Value)
+		if !ok {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		_, err := m.Put(ctx, entry.Key, value, _map.IfMatch(meta.NewRevision(entry.Revision)))
+		cancel()
+		report.written(errors.FromAtomix(err))
+	}
+	return report, nil
+}
+
+// count counts a scanned record, returning whether it is outdated
+func (r *Report) count(value []byte) bool {
+	r.Scanned++
+	record, err := split(value)
+	if err != nil || record.version > CurrentVersion(r.Kind) {
+		r.Failed++
+		return false
+	}
+	if record.version >= r.Version && record.keyID == currentKeyID() {
+		return false
+	}
+	r.Outdated++
+	return true
+}
+
+// upgrade returns an outdated record at the write version
+func (r *Report) upgrade(value []byte) ([]byte, bool) {
+	payload, _, err := Decode(r.Kind, value)
+	if err == nil {
+		value, err = Encode(r.Kind, payload)
+	}
+	if err != nil {
+		r.Failed++
+		return nil, false
+	}
+	return value, true
+}
+
+// written counts the rewrite of an outdated record; a conflict means the record was rewritten
+// at the write version, or removed, concurrently by its store
+func (r *Report) written(err error) {
+	switch {
+	case err == nil:
+		r.Migrated++
+	case !errors.IsConflict(err) && !errors.IsNotFound(err):
+		r.Failed++
+	}
+}
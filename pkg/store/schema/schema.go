@@ -0,0 +1,273 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schema versions the records kept in the Atomix stores and upgrades the records written
+// by previous releases, so that changes to the stored protos do not require wiping the stores.
+//
+// A versioned record is a zero byte followed by the uvarint schema version and the encoded proto.
+// A proto never starts with a zero byte, since field number 0 is reserved, so the records written
+// before versioning are read as version 0. Records are upgraded lazily when they are read, and are
+// persisted at the current version the next time they are written or by a migration job.
+//
+// During a rolling upgrade, records are written at the highest version every node reads, as
+// negotiated through the leadership store, so that the nodes of the previous release still read
+// the records written by the upgraded ones.
+//
+// Records may also be encrypted at rest by an Encryptor, for deployments that require it beyond
+// the encryption of the underlying storage. Like the schema versions, encryption is negotiated:
+// the records are only encrypted once every node reads encrypted records.
+package schema
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Kind is the kind of a stored record
+type Kind string
+
+const (
+	// NetworkChange is a network change record
+	NetworkChange Kind = "NetworkChange"
+	// DeviceChange is a device change record
+	DeviceChange Kind = "DeviceChange"
+	// NetworkSnapshot is a network snapshot record
+	NetworkSnapshot Kind = "NetworkSnapshot"
+	// DeviceSnapshot is a device snapshot record
+	DeviceSnapshot Kind = "DeviceSnapshot"
+	// Snapshot is a device configuration snapshot record
+	Snapshot Kind = "Snapshot"
+)
+
+// Kinds are the kinds of the stored records
+var Kinds = []Kind{NetworkChange, DeviceChange, NetworkSnapshot, DeviceSnapshot, Snapshot}
+
+// Version is the schema version of a stored record
+type Version uint64
+
+const (
+	// Unversioned is the version of the records written before schema versioning
+	Unversioned Version = 0
+	// Initial is the first schema version; records are upgraded to it unchanged
+	Initial Version = 1
+)
+
+const marker byte = 0x00
+
+// Migration upgrades the encoded proto of a record of a kind from a version to the next one
+type Migration struct {
+	// Kind is the kind of the records to migrate
+	Kind Kind
+	// From is the version upgraded by the migration
+	From Version
+	// Migrate upgrades an encoded proto from the From version to the next one
+	Migrate func(payload []byte) ([]byte, error)
+	// Downgrade reverts Migrate, so that the records can still be written at the From version while
+	// nodes of the previous release are running. Without it, the records of the kind cannot be
+	// written until every node reads the next version.
+	Downgrade func(payload []byte) ([]byte, error)
+}
+
+var (
+	migrationsMu  sync.RWMutex
+	migrations    = make(map[Kind][]Migration)
+	writeVersions = make(map[Kind]Version)
+)
+
+// Register registers the migration of a kind from its current version, making the next
+// version the current one. Migrations are typically registered from the init function of
+// the package that changes the schema of a record.
+func Register(migration Migration) error {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	if migration.Migrate == nil {
+		return errors.NewInvalid("migration of %s from version %d has no function", migration.Kind, migration.From)
+	}
+	current := Initial + Version(len(migrations[migration.Kind]))
+	if migration.From != current {
+		return errors.NewInvalid("migration of %s must be from version %d, not %d", migration.Kind, current, migration.From)
+	}
+	migrations[migration.Kind] = append(migrations[migration.Kind], migration)
+	return nil
+}
+
+// CurrentVersion returns the version at which the records of a kind are written
+func CurrentVersion(kind Kind) Version {
+	migrationsMu.RLock()
+	defer migrationsMu.RUnlock()
+	return Initial + Version(len(migrations[kind]))
+}
+
+// SetWriteVersions sets the versions at which the records are written, by kind, e.g. the highest
+// versions read by every node of the cluster. The records of a kind that has no version, or whose
+// version is above the current one, are written at the current version.
+func SetWriteVersions(versions map[Kind]Version) {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	writeVersions = make(map[Kind]Version)
+	for kind, version := range versions {
+		writeVersions[kind] = version
+	}
+}
+
+// WriteVersion returns the version at which the records of a kind are written
+func WriteVersion(kind Kind) Version {
+	migrationsMu.RLock()
+	defer migrationsMu.RUnlock()
+	return writeVersion(kind)
+}
+
+func writeVersion(kind Kind) Version {
+	current := Initial + Version(len(migrations[kind]))
+	if version, ok := writeVersions[kind]; ok && version < current {
+		return version
+	}
+	return current
+}
+
+// VersionOf returns the schema version of a stored record
+func VersionOf(value []byte) (Version, error) {
+	r, err := split(value)
+	return r.version, err
+}
+
+// KeyIDOf returns the ID of the key a stored record is encrypted with, empty if it is stored
+// in the clear
+func KeyIDOf(value []byte) (string, error) {
+	r, err := split(value)
+	return r.keyID, err
+}
+
+// Encode returns the record of a proto encoded at the current version of its kind. The record is
+// downgraded to the write version of its kind if it is lower, and is not versioned at all if the
+// write version is Unversioned. The record is encrypted if an Encryptor is set and every node
+// reads encrypted records.
+func Encode(kind Kind, payload []byte) ([]byte, error) {
+	migrationsMu.RLock()
+	pending := migrations[kind]
+	version := writeVersion(kind)
+	migrationsMu.RUnlock()
+
+	for i := len(pending) - 1; i >= 0 && pending[i].From >= version; i-- {
+		if pending[i].Downgrade == nil {
+			return nil, errors.NewUnavailable("%s records cannot be written at version %d until every node reads version %d",
+				kind, pending[i].From, pending[i].From+1)
+		}
+		var err error
+		if payload, err = pending[i].Downgrade(payload); err != nil {
+			return nil, errors.NewInvalid("%s record downgrade to version %d failed: %v", kind, pending[i].From, err)
+		}
+	}
+	if e := getSealer(); e != nil {
+		return seal(e, version, payload)
+	}
+	if version == Unversioned {
+		return payload, nil
+	}
+
+	header := make([]byte, 1+binary.MaxVarintLen64)
+	header[0] = marker
+	n := binary.PutUvarint(header[1:], uint64(version))
+	return append(header[:1+n], payload...), nil
+}
+
+// Marshal encodes a proto as a record at the write version of its kind
+func Marshal(kind Kind, msg proto.Message) ([]byte, error) {
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return Encode(kind, payload)
+}
+
+// Decode returns the encoded proto of a record upgraded to the current version of its kind,
+// along with the version at which the record was stored. Records written by a newer release
+// cannot be read and are rejected as invalid.
+func Decode(kind Kind, value []byte) ([]byte, Version, error) {
+	r, err := split(value)
+	if err != nil {
+		return nil, r.version, err
+	}
+	stored, payload := r.version, r.body
+	if r.encrypted {
+		if payload, err = open(kind, r); err != nil {
+			return nil, stored, err
+		}
+	}
+	version := stored
+	if version == Unversioned {
+		version = Initial
+	}
+
+	migrationsMu.RLock()
+	pending := migrations[kind]
+	migrationsMu.RUnlock()
+	current := Initial + Version(len(pending))
+	if version > current {
+		return nil, stored, errors.NewInvalid("%s record version %d is newer than supported version %d", kind, version, current)
+	}
+	for _, migration := range pending[version-Initial:] {
+		if payload, err = migration.Migrate(payload); err != nil {
+			return nil, stored, errors.NewInvalid("%s record migration from version %d failed: %v", kind, migration.From, err)
+		}
+	}
+	return payload, stored, nil
+}
+
+// Unmarshal decodes a record of a kind into a proto, upgrading it to the current version
+func Unmarshal(kind Kind, value []byte, msg proto.Message) error {
+	payload, _, err := Decode(kind, value)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(payload, msg)
+}
+
+// record is a stored record split into its header and its encoded proto, still sealed if the
+// record is encrypted
+type record struct {
+	version   Version
+	encrypted bool
+	keyID     string
+	header    []byte
+	body      []byte
+}
+
+// split splits a record into its header and encoded proto
+func split(value []byte) (record, error) {
+	if len(value) == 0 || (value[0] != marker && value[0] != encryptedMarker) {
+		return record{version: Unversioned, body: value}, nil
+	}
+	version, n := binary.Uvarint(value[1:])
+	if n <= 0 {
+		return record{}, errors.NewInvalid("malformed record version")
+	}
+	r := record{version: Version(version), header: value[:1+n], body: value[1+n:]}
+	if value[0] == marker {
+		return r, nil
+	}
+
+	length, m := binary.Uvarint(r.body)
+	if m <= 0 || length > uint64(len(r.body)-m) {
+		return record{}, errors.NewInvalid("malformed record key ID")
+	}
+	end := len(r.header) + m + int(length)
+	r.encrypted = true
+	r.keyID = string(value[len(r.header)+m : end])
+	r.header, r.body = value[:end], value[end:]
+	return r, nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+const testKind Kind = "Test"
+
+func TestUnversioned(t *testing.T) {
+	legacy, err := proto.Marshal(&types.StringValue{Value: "legacy"})
+	assert.NoError(t, err)
+
+	version, err := VersionOf(legacy)
+	assert.NoError(t, err)
+	assert.Equal(t, Unversioned, version)
+
+	value := &types.StringValue{}
+	assert.NoError(t, Unmarshal(NetworkChange, legacy, value))
+	assert.Equal(t, "legacy", value.Value)
+
+	version, err = VersionOf(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Unversioned, version)
+}
+
+func TestMarshal(t *testing.T) {
+	bytes, err := Marshal(NetworkChange, &types.StringValue{Value: "current"})
+	assert.NoError(t, err)
+	version, err := VersionOf(bytes)
+	assert.NoError(t, err)
+	assert.Equal(t, Initial, version)
+
+	value := &types.StringValue{}
+	assert.NoError(t, Unmarshal(NetworkChange, bytes, value))
+	assert.Equal(t, "current", value.Value)
+
+	_, err = VersionOf([]byte{marker})
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestMigration(t *testing.T) {
+	legacy, err := proto.Marshal(&types.StringValue{Value: "legacy"})
+	assert.NoError(t, err)
+	initial, err := Encode(testKind, legacy)
+	assert.NoError(t, err)
+
+	upper := func(payload []byte) ([]byte, error) {
+		value := &types.StringValue{}
+		if err := proto.Unmarshal(payload, value); err != nil {
+			return nil, err
+		}
+		value.Value += "-migrated"
+		return proto.Marshal(value)
+	}
+	assert.True(t, errors.IsInvalid(Register(Migration{Kind: testKind, From: 2, Migrate: upper})))
+	assert.True(t, errors.IsInvalid(Register(Migration{Kind: testKind, From: Initial})))
+	assert.NoError(t, Register(Migration{Kind: testKind, From: Initial, Migrate: upper}))
+	assert.Equal(t, Version(2), CurrentVersion(testKind))
+	assert.Equal(t, Initial, CurrentVersion(NetworkChange))
+
+	// Both unversioned and version 1 records are migrated when read
+	for _, record := range [][]byte{legacy, initial} {
+		payload, stored, err := Decode(testKind, record)
+		assert.NoError(t, err)
+		assert.True(t, stored < CurrentVersion(testKind))
+		value := &types.StringValue{}
+		assert.NoError(t, proto.Unmarshal(payload, value))
+		assert.Equal(t, "legacy-migrated", value.Value)
+	}
+
+	// Records at the current version are read as is
+	current, err := Marshal(testKind, &types.StringValue{Value: "current"})
+	assert.NoError(t, err)
+	value := &types.StringValue{}
+	assert.NoError(t, Unmarshal(testKind, current, value))
+	assert.Equal(t, "current", value.Value)
+
+	// Records written by a newer release are rejected
+	newer := append([]byte{marker, 3}, legacy...)
+	_, _, err = Decode(testKind, newer)
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestWriteVersion(t *testing.T) {
+	const kind Kind = "TestWriteVersion"
+	suffix := func(payload []byte) ([]byte, error) {
+		value := &types.StringValue{}
+		if err := proto.Unmarshal(payload, value); err != nil {
+			return nil, err
+		}
+		value.Value += "-v2"
+		return proto.Marshal(value)
+	}
+	unsuffix := func(payload []byte) ([]byte, error) {
+		value := &types.StringValue{}
+		if err := proto.Unmarshal(payload, value); err != nil {
+			return nil, err
+		}
+		value.Value = value.Value[:len(value.Value)-len("-v2")]
+		return proto.Marshal(value)
+	}
+	assert.NoError(t, Register(Migration{Kind: kind, From: Initial, Migrate: suffix, Downgrade: unsuffix}))
+	defer SetWriteVersions(nil)
+
+	// Records are written at the current version unless a lower version is negotiated
+	SetWriteVersions(map[Kind]Version{kind: 5})
+	assert.Equal(t, Version(2), WriteVersion(kind))
+	current, err := Marshal(kind, &types.StringValue{Value: "value-v2"})
+	assert.NoError(t, err)
+	version, err := VersionOf(current)
+	assert.NoError(t, err)
+	assert.Equal(t, Version(2), version)
+
+	// A node that reads version 1 reads the downgraded record
+	SetWriteVersions(map[Kind]Version{kind: Initial})
+	assert.Equal(t, Initial, WriteVersion(kind))
+	assert.Equal(t, Initial, WriteVersion(NetworkChange))
+	downgraded, err := Marshal(kind, &types.StringValue{Value: "value-v2"})
+	assert.NoError(t, err)
+	payload, version, err := Decode(kind, downgraded)
+	assert.NoError(t, err)
+	assert.Equal(t, Initial, version)
+	value := &types.StringValue{}
+	assert.NoError(t, proto.Unmarshal(payload, value))
+	assert.Equal(t, "value-v2", value.Value)
+
+	// A node older than schema versioning reads a plain proto
+	SetWriteVersions(map[Kind]Version{kind: Unversioned})
+	legacy, err := Marshal(kind, &types.StringValue{Value: "value-v2"})
+	assert.NoError(t, err)
+	value = &types.StringValue{}
+	assert.NoError(t, proto.Unmarshal(legacy, value))
+	assert.Equal(t, "value", value.Value)
+
+	// A record cannot be written at a version it cannot be downgraded to
+	const lossy Kind = "TestWriteVersionLossy"
+	assert.NoError(t, Register(Migration{Kind: lossy, From: Initial, Migrate: suffix}))
+	SetWriteVersions(map[Kind]Version{lossy: Initial})
+	_, err = Marshal(lossy, &types.StringValue{Value: "value-v2"})
+	assert.True(t, errors.IsUnavailable(err))
+	SetWriteVersions(nil)
+	_, err = Marshal(lossy, &types.StringValue{Value: "value-v2"})
+	assert.NoError(t, err)
+}
@@ -16,20 +16,38 @@ package device
 
 import (
 	"context"
+	"fmt"
 	"github.com/atomix/atomix-go-client/pkg/atomix"
 	"github.com/atomix/atomix-go-client/pkg/atomix/map"
 	"github.com/atomix/atomix-go-framework/pkg/atomix/meta"
 	"github.com/gogo/protobuf/proto"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
 	"github.com/onosproject/onos-api/go/onos/config/device"
 	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
+	"github.com/onosproject/onos-config/pkg/store/schema"
 	"github.com/onosproject/onos-config/pkg/store/stream"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"io"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
+// Option is an option of the Store
+type Option func(*atomixStore)
+
+// WithMaxDeltas makes the store keep differential snapshots: after a full snapshot of a device,
+// up to maxDeltas later snapshots only store the values that changed since the previous one.
+// The next snapshot is stored in full again. With 0, the default, every snapshot is stored in full.
+func WithMaxDeltas(maxDeltas int) Option {
+	return func(store *atomixStore) {
+		store.maxDeltas = maxDeltas
+	}
+}
+
 // NewAtomixStore returns a new persistent Store
-func NewAtomixStore(client atomix.Client) (Store, error) {
+func NewAtomixStore(client atomix.Client, options ...Option) (Store, error) {
 	deviceSnapshots, err := client.GetMap(context.Background(), "onos-config-device-snapshots")
 	if err != nil {
 		return nil, errors.FromAtomix(err)
@@ -38,10 +56,24 @@ func NewAtomixStore(client atomix.Client) (Store, error) {
 	if err != nil {
 		return nil, errors.FromAtomix(err)
 	}
-	return &atomixStore{
+	deltas, err := client.GetMap(context.Background(), "onos-config-snapshot-deltas")
+	if err != nil {
+		return nil, errors.FromAtomix(err)
+	}
+	archive, err := client.GetMap(context.Background(), "onos-config-snapshot-archive")
+	if err != nil {
+		return nil, errors.FromAtomix(err)
+	}
+	store := &atomixStore{
 		deviceSnapshots: deviceSnapshots,
 		snapshots:       snapshots,
-	}, nil
+		deltas:          deltas,
+		archive:         archive,
+	}
+	for _, option := range options {
+		option(store)
+	}
+	return store, nil
 }
 
 // Store stores DeviceChanges
@@ -72,6 +104,14 @@ type Store interface {
 	// Load loads a snapshot
 	Load(deviceID device.VersionedID) (*devicesnapshot.Snapshot, error)
 
+	// LoadChain loads the snapshots of a device as they are stored: the last full snapshot,
+	// followed by the deltas of the later snapshots in order. See ApplyDelta.
+	LoadChain(deviceID device.VersionedID) ([]*devicesnapshot.Snapshot, error)
+
+	// LoadArchived loads the values a device snapshot stored, once a later full snapshot of its
+	// device replaced them
+	LoadArchived(id devicesnapshot.ID) (*devicesnapshot.Snapshot, error)
+
 	// Load loads all snapshots
 	LoadAll(ch chan<- *devicesnapshot.Snapshot) (stream.Context, error)
 
@@ -80,9 +120,19 @@ type Store interface {
 }
 
 // atomixStore is the default implementation of the DeviceSnapshot store
+//
+// The full snapshot of a device is kept in the snapshots map, under the versioned ID of the
+// device. With differential snapshots, the deltas of the later snapshots are kept in the deltas
+// map, under the versioned ID, the change index of the full snapshot and their position in the
+// chain, so that the deltas of a replaced full snapshot are never applied to its successor.
+// When a full snapshot is replaced, the values of each snapshot of the replaced chain are kept in
+// full in the archive map, under the ID of the device snapshot, until the device snapshot is deleted.
 type atomixStore struct {
 	deviceSnapshots _map.Map
 	snapshots       _map.Map
+	deltas          _map.Map
+	archive         _map.Map
+	maxDeltas       int
 }
 
 func (s *atomixStore) Get(id devicesnapshot.ID) (*devicesnapshot.DeviceSnapshot, error) {
@@ -109,7 +159,7 @@ func (s *atomixStore) Create(snapshot *devicesnapshot.DeviceSnapshot) error {
 
 	snapshot.ID = devicesnapshot.GetSnapshotID(snapshot.NetworkSnapshot.ID, snapshot.DeviceID, snapshot.DeviceVersion)
 
-	bytes, err := proto.Marshal(snapshot)
+	bytes, err := schema.Marshal(schema.DeviceSnapshot, snapshot)
 	if err != nil {
 		return errors.NewInvalid("snapshot encoding failed: %v", err)
 	}
@@ -140,7 +190,7 @@ func (s *atomixStore) Update(snapshot *devicesnapshot.DeviceSnapshot) error {
 	defer cancel()
 
 	snapshot.Updated = time.Now()
-	bytes, err := proto.Marshal(snapshot)
+	bytes, err := schema.Marshal(schema.DeviceSnapshot, snapshot)
 	if err != nil {
 		return errors.NewInvalid("snapshot encoding failed: %v", err)
 	}
@@ -166,6 +216,9 @@ func (s *atomixStore) Delete(snapshot *devicesnapshot.DeviceSnapshot) error {
 	if err != nil {
 		return errors.FromAtomix(err)
 	}
+	if _, err := s.archive.Remove(ctx, string(snapshot.ID)); err != nil && !errors.IsNotFound(errors.FromAtomix(err)) {
+		return errors.FromAtomix(err)
+	}
 
 	snapshot.Revision = 0
 	return nil
@@ -243,27 +296,119 @@ func (s *atomixStore) Store(snapshot *devicesnapshot.Snapshot) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	bytes, err := proto.Marshal(snapshot)
+	deviceID := snapshot.GetVersionedDeviceID()
+	chain, err := s.loadChain(ctx, deviceID)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	// Only store the values that changed if the chain has room for another delta
+	if s.maxDeltas > 0 && len(chain) > 0 && len(chain) <= s.maxDeltas {
+		delta := diffSnapshots(applyChain(chain), snapshot)
+		bytes, err := schema.Marshal(schema.Snapshot, delta)
+		if err != nil {
+			return errors.NewInvalid("snapshot encoding failed: %v", err)
+		}
+		_, err = s.deltas.Put(ctx, deltaKey(deviceID, chain[0].ChangeIndex, len(chain)), bytes)
+		return errors.FromAtomix(err)
+	}
+
+	// The values of the snapshots of the replaced chain are archived, so that they can still be
+	// read once the chain no longer reaches back to them
+	var values *devicesnapshot.Snapshot
+	for _, entry := range chain {
+		values = ApplyDelta(values, entry)
+		if entry.SnapshotID == snapshot.SnapshotID {
+			continue
+		}
+		bytes, err := schema.Marshal(schema.Snapshot, values)
+		if err != nil {
+			return errors.NewInvalid("snapshot encoding failed: %v", err)
+		}
+		if _, err := s.archive.Put(ctx, string(entry.SnapshotID), bytes); err != nil {
+			return errors.FromAtomix(err)
+		}
+	}
+
+	bytes, err := schema.Marshal(schema.Snapshot, snapshot)
 	if err != nil {
 		return errors.NewInvalid("snapshot encoding failed: %v", err)
 	}
 
-	_, err = s.snapshots.Put(ctx, string(snapshot.GetVersionedDeviceID()), bytes)
+	_, err = s.snapshots.Put(ctx, string(deviceID), bytes)
 	if err != nil {
 		return errors.FromAtomix(err)
 	}
+
+	// The deltas of the replaced full snapshot are no longer loaded; remove them
+	for i := 1; i < len(chain); i++ {
+		if _, err := s.deltas.Remove(ctx, deltaKey(deviceID, chain[0].ChangeIndex, i)); err != nil && !errors.IsNotFound(errors.FromAtomix(err)) {
+			return errors.FromAtomix(err)
+		}
+	}
 	return nil
 }
 
 func (s *atomixStore) Load(deviceID device.VersionedID) (*devicesnapshot.Snapshot, error) {
+	chain, err := s.LoadChain(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	return applyChain(chain), nil
+}
+
+func (s *atomixStore) LoadChain(deviceID device.VersionedID) ([]*devicesnapshot.Snapshot, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
+	return s.loadChain(ctx, deviceID)
+}
 
+func (s *atomixStore) LoadArchived(id devicesnapshot.ID) (*devicesnapshot.Snapshot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	entry, err := s.archive.Get(ctx, string(id))
+	if err != nil {
+		return nil, errors.FromAtomix(err)
+	}
+	snapshot, err := decodeSnapshot(*entry)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.ID = devicesnapshot.ID(snapshot.DeviceID)
+	return snapshot, nil
+}
+
+// loadChain loads the full snapshot of a device followed by its deltas
+func (s *atomixStore) loadChain(ctx context.Context, deviceID device.VersionedID) ([]*devicesnapshot.Snapshot, error) {
 	entry, err := s.snapshots.Get(ctx, string(deviceID))
 	if err != nil {
 		return nil, errors.FromAtomix(err)
 	}
-	return decodeSnapshot(*entry)
+	snapshot, err := decodeSnapshot(*entry)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadDeltas(ctx, deviceID, snapshot)
+}
+
+// loadDeltas loads the deltas that follow a full snapshot
+func (s *atomixStore) loadDeltas(ctx context.Context, deviceID device.VersionedID, snapshot *devicesnapshot.Snapshot) ([]*devicesnapshot.Snapshot, error) {
+	chain := []*devicesnapshot.Snapshot{snapshot}
+	for {
+		entry, err := s.deltas.Get(ctx, deltaKey(deviceID, snapshot.ChangeIndex, len(chain)))
+		if err != nil {
+			if err = errors.FromAtomix(err); errors.IsNotFound(err) {
+				return chain, nil
+			}
+			return nil, err
+		}
+		delta, err := decodeSnapshot(*entry)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, delta)
+	}
 }
 
 func (s *atomixStore) LoadAll(ch chan<- *devicesnapshot.Snapshot) (stream.Context, error) {
@@ -278,7 +423,7 @@ func (s *atomixStore) LoadAll(ch chan<- *devicesnapshot.Snapshot) (stream.Contex
 	go func() {
 		defer close(ch)
 		for entry := range mapCh {
-			if snapshot, err := decodeSnapshot(entry); err == nil {
+			if snapshot, err := s.decodeChain(ctx, entry); err == nil {
 				ch <- snapshot
 			}
 		}
@@ -295,11 +440,18 @@ func (s *atomixStore) WatchAll(ch chan<- stream.Event) (stream.Context, error) {
 		cancel()
 		return nil, errors.FromAtomix(err)
 	}
+	deltaCh := make(chan _map.Event)
+	if err := s.deltas.Watch(ctx, deltaCh); err != nil {
+		cancel()
+		return nil, errors.FromAtomix(err)
+	}
 
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
 	go func() {
-		defer close(ch)
+		defer wg.Done()
 		for event := range mapCh {
-			if snapshot, err := decodeSnapshot(event.Entry); err == nil {
+			if snapshot, err := s.decodeChain(ctx, event.Entry); err == nil {
 				switch event.Type {
 				case _map.EventReplay:
 					ch <- stream.Event{
@@ -325,11 +477,53 @@ func (s *atomixStore) WatchAll(ch chan<- stream.Event) (stream.Context, error) {
 			}
 		}
 	}()
+	// A new delta updates the snapshot of its device
+	go func() {
+		defer wg.Done()
+		for event := range deltaCh {
+			if event.Type != _map.EventInsert && event.Type != _map.EventUpdate {
+				continue
+			}
+			if chain, err := s.loadChain(ctx, deltaDeviceID(event.Entry.Key)); err == nil {
+				ch <- stream.Event{
+					Type:   stream.Updated,
+					Object: applyChain(chain),
+				}
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(ch)
+	}()
 	return stream.NewCancelContext(cancel), nil
 }
 
+// Migrate rewrites the outdated device snapshots, full snapshots and deltas at the schema version written by the cluster
+func (s *atomixStore) Migrate(dryRun bool) ([]schema.Report, error) {
+	deviceSnapshots, err := schema.MigrateMap("onos-config-device-snapshots", schema.DeviceSnapshot, s.deviceSnapshots, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := schema.MigrateMap("onos-config-snapshots", schema.Snapshot, s.snapshots, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	deltas, err := schema.MigrateMap("onos-config-snapshot-deltas", schema.Snapshot, s.deltas, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := schema.MigrateMap("onos-config-snapshot-archive", schema.Snapshot, s.archive, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	return []schema.Report{deviceSnapshots, snapshots, deltas, archive}, nil
+}
+
 func (s *atomixStore) Close() error {
 	_ = s.deviceSnapshots.Close(context.Background())
+	_ = s.deltas.Close(context.Background())
+	_ = s.archive.Close(context.Background())
 	err := s.snapshots.Close(context.Background())
 	if err != nil {
 		return errors.FromAtomix(err)
@@ -339,7 +533,7 @@ func (s *atomixStore) Close() error {
 
 func decodeDeviceSnapshot(entry _map.Entry) (*devicesnapshot.DeviceSnapshot, error) {
 	snapshot := &devicesnapshot.DeviceSnapshot{}
-	if err := proto.Unmarshal(entry.Value, snapshot); err != nil {
+	if err := schema.Unmarshal(schema.DeviceSnapshot, entry.Value, snapshot); err != nil {
 		return nil, errors.NewInvalid("device snapshot decoding failed: %v", err)
 	}
 	snapshot.ID = devicesnapshot.ID(entry.Key)
@@ -349,9 +543,108 @@ func decodeDeviceSnapshot(entry _map.Entry) (*devicesnapshot.DeviceSnapshot, err
 
 func decodeSnapshot(entry _map.Entry) (*devicesnapshot.Snapshot, error) {
 	snapshot := &devicesnapshot.Snapshot{}
-	if err := proto.Unmarshal(entry.Value, snapshot); err != nil {
+	if err := schema.Unmarshal(schema.Snapshot, entry.Value, snapshot); err != nil {
 		return nil, errors.NewInvalid("snapshot decoding failed: %v", err)
 	}
 	snapshot.ID = devicesnapshot.ID(entry.Key)
 	return snapshot, nil
 }
+
+// decodeChain decodes a full snapshot and applies its deltas
+func (s *atomixStore) decodeChain(ctx context.Context, entry _map.Entry) (*devicesnapshot.Snapshot, error) {
+	snapshot, err := decodeSnapshot(entry)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := s.loadDeltas(ctx, device.VersionedID(entry.Key), snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return applyChain(chain), nil
+}
+
+// deltaKey returns the key of the n-th delta following the full snapshot of a device
+func deltaKey(deviceID device.VersionedID, index devicechange.Index, n int) string {
+	return fmt.Sprintf("%s/%d/%d", deviceID, index, n)
+}
+
+// deltaDeviceID returns the device of a delta key
+func deltaDeviceID(key string) device.VersionedID {
+	key = key[:strings.LastIndex(key, "/")]
+	return device.VersionedID(key[:strings.LastIndex(key, "/")])
+}
+
+// ApplyDelta returns the snapshot resulting from applying a delta to a snapshot, where the
+// values of the delta that have no value are removed. A nil snapshot stands for an empty one.
+func ApplyDelta(snapshot *devicesnapshot.Snapshot, delta *devicesnapshot.Snapshot) *devicesnapshot.Snapshot {
+	values := make(map[string]*devicechange.PathValue)
+	if snapshot != nil {
+		for _, value := range snapshot.Values {
+			values[value.Path] = value
+		}
+	}
+	for _, value := range delta.Values {
+		if value.Value == nil {
+			delete(values, value.Path)
+		} else {
+			values[value.Path] = value
+		}
+	}
+	result := &devicesnapshot.Snapshot{
+		ID:            delta.ID,
+		DeviceID:      delta.DeviceID,
+		DeviceVersion: delta.DeviceVersion,
+		DeviceType:    delta.DeviceType,
+		SnapshotID:    delta.SnapshotID,
+		ChangeIndex:   delta.ChangeIndex,
+		Values:        make([]*devicechange.PathValue, 0, len(values)),
+	}
+	if snapshot != nil {
+		result.ID = snapshot.ID
+	}
+	for _, value := range values {
+		result.Values = append(result.Values, value)
+	}
+	sort.Slice(result.Values, func(i, j int) bool {
+		return result.Values[i].Path < result.Values[j].Path
+	})
+	return result
+}
+
+// applyChain returns the snapshot a chain stands for
+func applyChain(chain []*devicesnapshot.Snapshot) *devicesnapshot.Snapshot {
+	var snapshot *devicesnapshot.Snapshot
+	for _, delta := range chain {
+		snapshot = ApplyDelta(snapshot, delta)
+	}
+	return snapshot
+}
+
+// diffSnapshots returns the delta from one snapshot to the next
+func diffSnapshots(prev *devicesnapshot.Snapshot, next *devicesnapshot.Snapshot) *devicesnapshot.Snapshot {
+	prevValues := make(map[string]*devicechange.PathValue)
+	for _, value := range prev.Values {
+		prevValues[value.Path] = value
+	}
+	delta := &devicesnapshot.Snapshot{
+		ID:            next.ID,
+		DeviceID:      next.DeviceID,
+		DeviceVersion: next.DeviceVersion,
+		DeviceType:    next.DeviceType,
+		SnapshotID:    next.SnapshotID,
+		ChangeIndex:   next.ChangeIndex,
+	}
+	nextPaths := make(map[string]bool)
+	for _, value := range next.Values {
+		nextPaths[value.Path] = true
+		if prevValue, ok := prevValues[value.Path]; !ok || !proto.Equal(prevValue.Value, value.Value) {
+			delta.Values = append(delta.Values, value)
+		}
+	}
+	for _, value := range prev.Values {
+		if !nextPaths[value.Path] {
+			delta.Values = append(delta.Values, &devicechange.PathValue{Path: value.Path})
+		}
+	}
+	return delta
+}
@@ -15,8 +15,10 @@
 package device
 
 import (
+	"fmt"
 	"github.com/atomix/atomix-go-client/pkg/atomix/test"
 	"github.com/atomix/atomix-go-client/pkg/atomix/test/rsm"
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
 	"github.com/onosproject/onos-api/go/onos/config/device"
 	"github.com/onosproject/onos-api/go/onos/config/snapshot"
 	devicesnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/device"
@@ -193,3 +195,93 @@ func nextEvent(t *testing.T, ch chan stream.Event) *devicesnapshot.DeviceSnapsho
 	}
 	return nil
 }
+
+func TestDifferentialSnapshots(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1),
+		test.WithDebugLogs())
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	client, err := test.NewClient("node-1")
+	assert.NoError(t, err)
+
+	store, err := NewAtomixStore(client, WithMaxDeltas(2))
+	assert.NoError(t, err)
+	defer store.Close()
+
+	deviceID := device.NewVersionedID("device-1", "1.0.0")
+	newSnapshot := func(index devicechange.Index, values ...*devicechange.PathValue) *devicesnapshot.Snapshot {
+		return &devicesnapshot.Snapshot{
+			ID:            "device-1",
+			DeviceID:      "device-1",
+			DeviceVersion: "1.0.0",
+			SnapshotID:    devicesnapshot.ID(fmt.Sprintf("snapshot-%d:device-1:1.0.0", index)),
+			ChangeIndex:   index,
+			Values:        values,
+		}
+	}
+	hostname := &devicechange.PathValue{Path: "/a/hostname", Value: devicechange.NewTypedValueString("device-1")}
+	banner := &devicechange.PathValue{Path: "/a/banner", Value: devicechange.NewTypedValueString("hello")}
+	banner2 := &devicechange.PathValue{Path: "/a/banner", Value: devicechange.NewTypedValueString("bye")}
+	clock := &devicechange.PathValue{Path: "/b/clock", Value: devicechange.NewTypedValueString("UTC")}
+
+	// The first snapshot is stored in full
+	err = store.Store(newSnapshot(1, hostname, banner))
+	assert.NoError(t, err)
+	chain, err := store.LoadChain(deviceID)
+	assert.NoError(t, err)
+	assert.Len(t, chain, 1)
+
+	// The next two only store what changed
+	err = store.Store(newSnapshot(2, hostname, banner2, clock))
+	assert.NoError(t, err)
+	err = store.Store(newSnapshot(3, hostname, clock))
+	assert.NoError(t, err)
+	chain, err = store.LoadChain(deviceID)
+	assert.NoError(t, err)
+	assert.Len(t, chain, 3)
+	assert.Len(t, chain[1].Values, 2)
+	assert.Len(t, chain[2].Values, 1)
+	assert.Nil(t, chain[2].Values[0].Value)
+
+	snapshot, err := store.Load(deviceID)
+	assert.NoError(t, err)
+	assert.Equal(t, devicechange.Index(3), snapshot.ChangeIndex)
+	assert.Equal(t, devicesnapshot.ID("snapshot-3:device-1:1.0.0"), snapshot.SnapshotID)
+	assert.Len(t, snapshot.Values, 2)
+	assert.Equal(t, "/a/hostname", snapshot.Values[0].Path)
+	assert.Equal(t, "/b/clock", snapshot.Values[1].Path)
+
+	snapshots := make(chan *devicesnapshot.Snapshot)
+	_, err = store.LoadAll(snapshots)
+	assert.NoError(t, err)
+	snapshot = <-snapshots
+	assert.Equal(t, devicechange.Index(3), snapshot.ChangeIndex)
+	assert.Len(t, snapshot.Values, 2)
+
+	// The chain is full so the next snapshot is stored in full again
+	err = store.Store(newSnapshot(4, hostname))
+	assert.NoError(t, err)
+	chain, err = store.LoadChain(deviceID)
+	assert.NoError(t, err)
+	assert.Len(t, chain, 1)
+	snapshot, err = store.Load(deviceID)
+	assert.NoError(t, err)
+	assert.Equal(t, devicechange.Index(4), snapshot.ChangeIndex)
+	assert.Len(t, snapshot.Values, 1)
+
+	// The values of the snapshots of the replaced chain are archived
+	snapshot, err = store.LoadArchived("snapshot-2:device-1:1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, devicechange.Index(2), snapshot.ChangeIndex)
+	assert.Len(t, snapshot.Values, 3)
+	snapshot, err = store.LoadArchived("snapshot-3:device-1:1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, devicechange.Index(3), snapshot.ChangeIndex)
+	assert.Len(t, snapshot.Values, 2)
+	_, err = store.LoadArchived("snapshot-4:device-1:1.0.0")
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -23,9 +23,10 @@ import (
 
 	"github.com/atomix/atomix-go-client/pkg/atomix"
 	"github.com/atomix/atomix-go-client/pkg/atomix/indexedmap"
-	"github.com/gogo/protobuf/proto"
 	"github.com/google/uuid"
 	networksnapshot "github.com/onosproject/onos-api/go/onos/config/snapshot/network"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-config/pkg/store/schema"
 	"github.com/onosproject/onos-config/pkg/store/stream"
 )
 
@@ -35,8 +36,13 @@ func NewAtomixStore(client atomix.Client) (Store, error) {
 	if err != nil {
 		return nil, errors.FromAtomix(err)
 	}
+	partitions, err := records.NewAtomixMap(client, "onos-config-network-snapshot-partitions", "snapshot partition")
+	if err != nil {
+		return nil, err
+	}
 	return &atomixStore{
-		snapshots: snapshots,
+		snapshots:  snapshots,
+		partitions: partitions,
 	}, nil
 }
 
@@ -53,6 +59,14 @@ type Store interface {
 	// Create creates a new network snapshot
 	Create(snapshot *networksnapshot.NetworkSnapshot) error
 
+	// CreatePartitioned creates a new network snapshot scoped to a partition of the devices, i.e.
+	// the name of a device group or an inline device selector
+	CreatePartitioned(snapshot *networksnapshot.NetworkSnapshot, partition string) error
+
+	// GetPartition gets the partition a network snapshot is scoped to, or "" for a snapshot of all
+	// the devices
+	GetPartition(id networksnapshot.ID) (string, error)
+
 	// Update updates an existing network snapshot
 	Update(snapshot *networksnapshot.NetworkSnapshot) error
 
@@ -71,9 +85,16 @@ func newSnapshotID() networksnapshot.ID {
 	return networksnapshot.ID(uuid.New().String())
 }
 
+// partition is the partition a network snapshot is scoped to
+type partition struct {
+	Partition string `json:"partition"`
+}
+
 // atomixStore is the default implementation of the NetworkSnapshot store
 type atomixStore struct {
 	snapshots indexedmap.IndexedMap
+	// partitions are the partitions of the partitioned snapshots, by snapshot ID
+	partitions records.Map
 }
 
 func (s *atomixStore) Get(id networksnapshot.ID) (*networksnapshot.NetworkSnapshot, error) {
@@ -110,7 +131,7 @@ func (s *atomixStore) Create(snapshot *networksnapshot.NetworkSnapshot) error {
 		return errors.NewInvalid("not a new object")
 	}
 
-	bytes, err := proto.Marshal(snapshot)
+	bytes, err := schema.Marshal(schema.NetworkSnapshot, snapshot)
 	if err != nil {
 		return errors.NewInvalid("snapshot encoding failed: %v", err)
 	}
@@ -128,6 +149,32 @@ func (s *atomixStore) Create(snapshot *networksnapshot.NetworkSnapshot) error {
 	return nil
 }
 
+func (s *atomixStore) CreatePartitioned(snapshot *networksnapshot.NetworkSnapshot, partitionName string) error {
+	if snapshot.ID == "" {
+		snapshot.ID = newSnapshotID()
+	}
+	// The partition is stored first, so that it is known by the time the snapshot is reconciled
+	if err := s.partitions.Create(string(snapshot.ID), &partition{Partition: partitionName}); err != nil {
+		return err
+	}
+	if err := s.Create(snapshot); err != nil {
+		_ = s.partitions.Delete(string(snapshot.ID))
+		return err
+	}
+	return nil
+}
+
+func (s *atomixStore) GetPartition(id networksnapshot.ID) (string, error) {
+	partition := &partition{}
+	if err := s.partitions.Get(string(id), partition); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return partition.Partition, nil
+}
+
 func (s *atomixStore) Update(snapshot *networksnapshot.NetworkSnapshot) error {
 	if snapshot.Revision == 0 {
 		return errors.NewInvalid("not a stored object")
@@ -136,7 +183,7 @@ func (s *atomixStore) Update(snapshot *networksnapshot.NetworkSnapshot) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	bytes, err := proto.Marshal(snapshot)
+	bytes, err := schema.Marshal(schema.NetworkSnapshot, snapshot)
 	if err != nil {
 		return errors.NewInvalid("snapshot encoding failed: %v", err)
 	}
@@ -162,6 +209,9 @@ func (s *atomixStore) Delete(snapshot *networksnapshot.NetworkSnapshot) error {
 	if err != nil {
 		return errors.FromAtomix(err)
 	}
+	if err := s.partitions.Delete(string(snapshot.ID)); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
 
 	snapshot.Revision = 0
 	return nil
@@ -228,17 +278,26 @@ func (s *atomixStore) Watch(ch chan<- stream.Event) (stream.Context, error) {
 	return stream.NewCancelContext(cancel), nil
 }
 
+// Migrate rewrites the outdated network snapshots at the schema version written by the cluster
+func (s *atomixStore) Migrate(dryRun bool) ([]schema.Report, error) {
+	report, err := schema.MigrateIndexedMap("onos-config-network-snapshots", schema.NetworkSnapshot, s.snapshots, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	return []schema.Report{report}, nil
+}
+
 func (s *atomixStore) Close() error {
 	err := s.snapshots.Close(context.Background())
 	if err != nil {
 		return errors.FromAtomix(err)
 	}
-	return nil
+	return s.partitions.Close()
 }
 
 func decodeSnapshot(entry indexedmap.Entry) (*networksnapshot.NetworkSnapshot, error) {
 	snapshot := &networksnapshot.NetworkSnapshot{}
-	if err := proto.Unmarshal(entry.Value, snapshot); err != nil {
+	if err := schema.Unmarshal(schema.NetworkSnapshot, entry.Value, snapshot); err != nil {
 		return nil, errors.NewInvalid("snapshot decoding failed: %v", err)
 	}
 	snapshot.ID = networksnapshot.ID(entry.Key)
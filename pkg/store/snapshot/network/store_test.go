@@ -154,6 +154,46 @@ func TestNetworkSnapshotStore(t *testing.T) {
 	assert.Nil(t, snapshot2)
 }
 
+func TestPartitionedSnapshot(t *testing.T) {
+	test := test.NewTest(
+		rsm.NewProtocol(),
+		test.WithReplicas(1),
+		test.WithPartitions(1))
+	assert.NoError(t, test.Start())
+	defer test.Stop()
+
+	client, err := test.NewClient("node-1")
+	assert.NoError(t, err)
+	store, err := NewAtomixStore(client)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	// The partition is kept apart from the ID, which is free to hold any character
+	snapshot1 := &networksnapshot.NetworkSnapshot{}
+	assert.NoError(t, store.CreatePartitioned(snapshot1, "type=Devicesim@lab,version=1.0.0"))
+	assert.NotEqual(t, networksnapshot.ID(""), snapshot1.ID)
+	partition, err := store.GetPartition(snapshot1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "type=Devicesim@lab,version=1.0.0", partition)
+
+	snapshot2 := &networksnapshot.NetworkSnapshot{ID: "snapshot@site-a"}
+	assert.NoError(t, store.Create(snapshot2))
+	partition, err = store.GetPartition(snapshot2.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "", partition)
+
+	// A snapshot that cannot be created leaves no partition behind
+	assert.Error(t, store.CreatePartitioned(&networksnapshot.NetworkSnapshot{ID: "snapshot-3", Revision: 1}, "site-a"))
+	partition, err = store.GetPartition("snapshot-3")
+	assert.NoError(t, err)
+	assert.Equal(t, "", partition)
+
+	assert.NoError(t, store.Delete(snapshot1))
+	partition, err = store.GetPartition(snapshot1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "", partition)
+}
+
 func nextEvent(t *testing.T, ch chan stream.Event) *networksnapshot.NetworkSnapshot {
 	select {
 	case c := <-ch:
@@ -0,0 +1,141 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package transform stores the rules that bind a transformation hook to the paths of the values
+// written by gNMI Set requests. The hooks themselves are in the transform package.
+package transform
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Rule applies a transformation hook to the values written to matching paths
+type Rule struct {
+	// Name identifies the rule; rules are applied in the order of their names
+	Name string `json:"name"`
+	// Path is the path of the values to transform; it may contain the '*' and '...' wildcards
+	Path string `json:"path"`
+	// DeviceType restricts the rule to the devices of a type of model, if not empty
+	DeviceType devicetype.Type `json:"deviceType,omitempty"`
+	// Hook is the name of the hook transforming the values
+	Hook string `json:"hook"`
+	// Args are the arguments of the hook
+	Args map[string]string `json:"args,omitempty"`
+}
+
+// Validate checks that the rule has a name, a path and a hook
+func (r *Rule) Validate() error {
+	if r.Name == "" || strings.ContainsAny(r.Name, "/ ") {
+		return errors.NewInvalid("invalid rule name '%s'", r.Name)
+	} else if !strings.HasPrefix(r.Path, "/") {
+		return errors.NewInvalid("rule '%s' has an invalid path '%s'", r.Name, r.Path)
+	} else if r.Hook == "" {
+		return errors.NewInvalid("rule '%s' has no hook", r.Name)
+	}
+	return nil
+}
+
+// Store stores transformation rules
+type Store interface {
+	io.Closer
+
+	// Get gets a rule by name
+	Get(name string) (*Rule, error)
+
+	// Put creates or replaces a rule
+	Put(rule *Rule) error
+
+	// Delete deletes a rule
+	Delete(name string) error
+
+	// List lists the rules, sorted by name
+	List() ([]*Rule, error)
+}
+
+// kind and notFound describe the rules in the errors of the store
+const kind = "transformation rule"
+
+var notFound = records.WithNotFound("transformation rule '%s' not found")
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	rules, err := records.NewAtomixMap(client, "onos-config-transform-rules", kind, notFound)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		rules: rules,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps rules in memory
+func NewLocalStore() Store {
+	return &store{
+		rules: records.NewLocalMap(kind, notFound),
+	}
+}
+
+// store keeps the rules by name
+type store struct {
+	rules records.Map
+}
+
+func (s *store) Get(name string) (*Rule, error) {
+	rule := &Rule{}
+	if err := s.rules.Get(name, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *store) Put(rule *Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	return s.rules.Put(rule.Name, rule)
+}
+
+func (s *store) Delete(name string) error {
+	return s.rules.Delete(name)
+}
+
+func (s *store) List() ([]*Rule, error) {
+	list, err := s.rules.List(func() interface{} { return &Rule{} })
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]*Rule, 0, len(list))
+	for _, record := range list {
+		rules = append(rules, record.(*Rule))
+	}
+	sortRules(rules)
+	return rules, nil
+}
+
+func (s *store) Close() error {
+	return s.rules.Close()
+}
+
+func sortRules(rules []*Rule) {
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Name < rules[j].Name
+	})
+}
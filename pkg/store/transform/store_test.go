@@ -0,0 +1,79 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Validate(t *testing.T) {
+	assert.NoError(t, (&Rule{Name: "mac", Path: "/interfaces/interface[name=*]/config/mac", Hook: "mac-address"}).Validate())
+	assert.True(t, errors.IsInvalid((&Rule{Path: "/a/b", Hook: "lowercase"}).Validate()))
+	assert.True(t, errors.IsInvalid((&Rule{Name: "a/b", Path: "/a/b", Hook: "lowercase"}).Validate()))
+	assert.True(t, errors.IsInvalid((&Rule{Name: "ab", Path: "a/b", Hook: "lowercase"}).Validate()))
+	assert.True(t, errors.IsInvalid((&Rule{Name: "ab", Path: "/a/b"}).Validate()))
+}
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	assert.NoError(t, store.Put(&Rule{
+		Name:       "mac",
+		Path:       "/interfaces/interface[name=*]/config/mac",
+		DeviceType: "Devicesim",
+		Hook:       "mac-address",
+	}))
+	assert.NoError(t, store.Put(&Rule{
+		Name: "address",
+		Path: "/interfaces/interface[name=*]/config/address",
+		Hook: "cidr",
+		Args: map[string]string{"prefix-length": "prefix-length"},
+	}))
+	assert.True(t, errors.IsInvalid(store.Put(&Rule{Name: "broken"})))
+
+	rules, err := store.List()
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "address", rules[0].Name)
+	assert.Equal(t, "mac", rules[1].Name)
+
+	rule, err := store.Get("address")
+	assert.NoError(t, err)
+	assert.Equal(t, "cidr", rule.Hook)
+	assert.Equal(t, map[string]string{"prefix-length": "prefix-length"}, rule.Args)
+
+	assert.NoError(t, store.Delete("address"))
+	_, err = store.Get("address")
+	assert.True(t, errors.IsNotFound(err))
+	assert.True(t, errors.IsNotFound(store.Delete("address")))
+
+	// A rule put again replaces the earlier one
+	assert.NoError(t, store.Put(&Rule{Name: "mac", Path: "/interfaces/interface[name=*]/config/mac", Hook: "mac-address"}))
+	rule, err = store.Get("mac")
+	assert.NoError(t, err)
+	assert.Equal(t, "", string(rule.DeviceType))
+
+	// The rules returned are copies
+	rule.Hook = "cidr"
+	rule, err = store.Get("mac")
+	assert.NoError(t, err)
+	assert.Equal(t, "mac-address", rule.Hook)
+
+	assert.EqualError(t, store.Delete("address"), "transformation rule 'address' not found")
+}
@@ -0,0 +1,134 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trust
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// BundlePrefix marks a device TLS field as the name of a stored bundle rather than a file path,
+// e.g. a device CaCert of "bundle:lab-ca"
+const BundlePrefix = "bundle:"
+
+// Kind is the kind of a bundle
+type Kind string
+
+const (
+	// KindCA is a bundle of CA certificates that device certificates are verified against
+	KindCA Kind = "ca"
+	// KindClient is a client certificate and its private key, presented to devices
+	KindClient Kind = "client"
+)
+
+// Bundle is a named set of certificates used for device connections
+type Bundle struct {
+	// Name is the name devices refer to the bundle by
+	Name string `json:"name"`
+	// Kind is the kind of bundle
+	Kind Kind `json:"kind"`
+	// Certs is the PEM encoded CA certificates of a CA bundle, or the certificate chain of a client bundle
+	Certs string `json:"certs"`
+	// Key is the PEM encoded private key of a client bundle. It is only stored encrypted.
+	Key string `json:"-"`
+	// SealedKey is the private key of a client bundle, encrypted by a KeyCipher
+	SealedKey []byte `json:"sealedKey,omitempty"`
+	// Created is when the bundle was uploaded
+	Created time.Time `json:"created"`
+}
+
+// BundleName returns the bundle name referenced by a device TLS field, if the field refers to one
+func BundleName(ref string) (string, bool) {
+	if !strings.HasPrefix(ref, BundlePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(ref, BundlePrefix), true
+}
+
+// Validate checks that the bundle holds well formed certificates and, for a client bundle,
+// a private key matching its certificate
+func (b *Bundle) Validate() error {
+	if b.Name == "" || strings.ContainsAny(b.Name, "/ ") {
+		return errors.NewInvalid("invalid bundle name '%s'", b.Name)
+	}
+	switch b.Kind {
+	case KindCA:
+		if b.Key != "" {
+			return errors.NewInvalid("CA bundle '%s' must not contain a private key", b.Name)
+		}
+		if _, err := b.Certificates(); err != nil {
+			return err
+		}
+	case KindClient:
+		if _, err := b.KeyPair(); err != nil {
+			return err
+		}
+	default:
+		return errors.NewInvalid("unknown kind '%s' of bundle '%s'", b.Kind, b.Name)
+	}
+	return nil
+}
+
+// Certificates parses the certificates of the bundle
+func (b *Bundle) Certificates() ([]*x509.Certificate, error) {
+	certificates := make([]*x509.Certificate, 0)
+	rest := []byte(b.Certs)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.NewInvalid("invalid certificate in bundle '%s': %v", b.Name, err)
+		}
+		certificates = append(certificates, certificate)
+	}
+	if len(certificates) == 0 {
+		return nil, errors.NewInvalid("no certificate in bundle '%s'", b.Name)
+	}
+	return certificates, nil
+}
+
+// CertPool returns the certificates of the bundle as a pool of roots
+func (b *Bundle) CertPool() (*x509.CertPool, error) {
+	certificates, err := b.Certificates()
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	for _, certificate := range certificates {
+		pool.AddCert(certificate)
+	}
+	return pool, nil
+}
+
+// KeyPair returns the client certificate and key of the bundle
+func (b *Bundle) KeyPair() (tls.Certificate, error) {
+	certificate, err := tls.X509KeyPair([]byte(b.Certs), []byte(b.Key))
+	if err != nil {
+		return tls.Certificate{}, errors.NewInvalid("invalid client certificate or key in bundle '%s': %v", b.Name, err)
+	}
+	return certificate, nil
+}
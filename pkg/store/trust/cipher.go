@@ -0,0 +1,86 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trust
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// KeyCipher encrypts the private keys of client bundles before they are stored, with AES-256-GCM
+// under a key encryption key that is never stored with them
+type KeyCipher struct {
+	aead cipher.AEAD
+}
+
+// NewKeyCipher returns a cipher for the given 32 byte key encryption key
+func NewKeyCipher(key []byte) (*KeyCipher, error) {
+	if len(key) != 32 {
+		return nil, errors.NewInvalid("the key encryption key must have 32 bytes, not %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.NewInvalid("invalid key encryption key: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.NewInvalid("invalid key encryption key: %v", err)
+	}
+	return &KeyCipher{aead: aead}, nil
+}
+
+// LoadKeyCipher returns a cipher for the key encryption key in a file, base64 encoded e.g. by
+// "openssl rand -base64 32"
+func LoadKeyCipher(path string) (*KeyCipher, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.NewInvalid("cannot read the key encryption key: %v", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, errors.NewInvalid("the key encryption key is not valid base64: %v", err)
+	}
+	return NewKeyCipher(key)
+}
+
+// Seal encrypts the private key of a bundle. The bundle name is authenticated along with it, so
+// that the key cannot be moved to another bundle.
+func (c *KeyCipher) Seal(name string, key string) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.NewInternal("cannot generate a nonce: %v", err)
+	}
+	return c.aead.Seal(nonce, nonce, []byte(key), []byte(name)), nil
+}
+
+// Open decrypts the private key of a bundle sealed by Seal
+func (c *KeyCipher) Open(name string, sealed []byte) (string, error) {
+	if len(sealed) < c.aead.NonceSize() {
+		return "", errors.NewInvalid("the private key of bundle '%s' is truncated", name)
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	key, err := c.aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", errors.NewForbidden("cannot decrypt the private key of bundle '%s': %v", name, err)
+	}
+	return string(key), nil
+}
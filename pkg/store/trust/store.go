@@ -0,0 +1,153 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trust stores the CA bundles and client certificates used to connect to devices,
+// so that they can be managed through the admin API rather than mounted into the pod.
+// Device records refer to a bundle by name with the BundlePrefix.
+//
+// The private keys of client bundles are encrypted with a KeyCipher before they are stored.
+// Stores without a KeyCipher only accept CA bundles.
+package trust
+
+import (
+	"io"
+	"sort"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Store stores trust bundles
+type Store interface {
+	io.Closer
+
+	// Get gets a bundle by name, with its private key decrypted
+	Get(name string) (*Bundle, error)
+
+	// Put creates or replaces a bundle
+	Put(bundle *Bundle) error
+
+	// Delete deletes a bundle
+	Delete(name string) error
+
+	// List lists the bundles, sorted by name, without their private keys
+	List() ([]*Bundle, error)
+}
+
+// NewAtomixStore returns a new persistent Store; keys may be nil
+func NewAtomixStore(client atomix.Client, keys *KeyCipher) (Store, error) {
+	bundles, err := records.NewAtomixMap(client, "onos-config-trust-bundles", "trust bundle")
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		bundles: bundles,
+		keys:    keys,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps bundles in memory; keys may be nil
+func NewLocalStore(keys *KeyCipher) Store {
+	return &store{
+		bundles: records.NewLocalMap("trust bundle"),
+		keys:    keys,
+	}
+}
+
+// store keeps the bundles by name, with their private keys encrypted
+type store struct {
+	bundles records.Map
+	keys    *KeyCipher
+}
+
+func (s *store) Get(name string) (*Bundle, error) {
+	bundle := &Bundle{}
+	if err := s.bundles.Get(name, bundle); err != nil {
+		return nil, err
+	}
+	return unseal(s.keys, bundle)
+}
+
+func (s *store) Put(bundle *Bundle) error {
+	sealed, err := seal(s.keys, bundle)
+	if err != nil {
+		return err
+	}
+	return s.bundles.Put(sealed.Name, sealed)
+}
+
+func (s *store) Delete(name string) error {
+	return s.bundles.Delete(name)
+}
+
+func (s *store) List() ([]*Bundle, error) {
+	list, err := s.bundles.List(func() interface{} { return &Bundle{} })
+	if err != nil {
+		return nil, err
+	}
+	bundles := make([]*Bundle, 0, len(list))
+	for _, record := range list {
+		bundle := record.(*Bundle)
+		bundle.SealedKey = nil
+		bundles = append(bundles, bundle)
+	}
+	sortBundles(bundles)
+	return bundles, nil
+}
+
+func (s *store) Close() error {
+	return s.bundles.Close()
+}
+
+// seal validates a bundle and returns a copy of it to store, with its private key encrypted
+func seal(keys *KeyCipher, bundle *Bundle) (*Bundle, error) {
+	if err := bundle.Validate(); err != nil {
+		return nil, err
+	}
+	sealed := *bundle
+	sealed.Key = ""
+	sealed.SealedKey = nil
+	if bundle.Key != "" {
+		if keys == nil {
+			return nil, errors.NewInvalid("client bundle '%s' cannot be stored: no key encryption key is configured", bundle.Name)
+		}
+		var err error
+		if sealed.SealedKey, err = keys.Seal(bundle.Name, bundle.Key); err != nil {
+			return nil, err
+		}
+	}
+	return &sealed, nil
+}
+
+// unseal returns a copy of a stored bundle with its private key decrypted
+func unseal(keys *KeyCipher, sealed *Bundle) (*Bundle, error) {
+	bundle := *sealed
+	if len(sealed.SealedKey) > 0 {
+		if keys == nil {
+			return nil, errors.NewUnavailable("the private key of bundle '%s' cannot be decrypted: no key encryption key is configured", sealed.Name)
+		}
+		var err error
+		if bundle.Key, err = keys.Open(sealed.Name, sealed.SealedKey); err != nil {
+			return nil, err
+		}
+	}
+	return &bundle, nil
+}
+
+func sortBundles(bundles []*Bundle) {
+	sort.Slice(bundles, func(i, j int) bool {
+		return bundles[i].Name < bundles[j].Name
+	})
+}
@@ -0,0 +1,165 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trust
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// newKeyPair generates a self signed certificate and its key, PEM encoded
+func newKeyPair(t *testing.T, commonName string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
+
+func Test_BundleName(t *testing.T) {
+	name, ok := BundleName("bundle:lab-ca")
+	assert.True(t, ok)
+	assert.Equal(t, "lab-ca", name)
+	_, ok = BundleName("/etc/ssl/certs/onfca.crt")
+	assert.False(t, ok)
+}
+
+func Test_Validate(t *testing.T) {
+	cert, key := newKeyPair(t, "lab-ca")
+	_, otherKey := newKeyPair(t, "other")
+
+	assert.NoError(t, (&Bundle{Name: "lab-ca", Kind: KindCA, Certs: cert}).Validate())
+	assert.NoError(t, (&Bundle{Name: "client", Kind: KindClient, Certs: cert, Key: key}).Validate())
+
+	assert.True(t, errors.IsInvalid((&Bundle{Name: "lab-ca", Kind: KindCA, Certs: cert, Key: key}).Validate()))
+	assert.True(t, errors.IsInvalid((&Bundle{Name: "lab-ca", Kind: KindCA, Certs: "garbage"}).Validate()))
+	assert.True(t, errors.IsInvalid((&Bundle{Name: "client", Kind: KindClient, Certs: cert, Key: otherKey}).Validate()))
+	assert.True(t, errors.IsInvalid((&Bundle{Name: "a/b", Kind: KindCA, Certs: cert}).Validate()))
+	assert.True(t, errors.IsInvalid((&Bundle{Name: "lab-ca", Kind: "pgp", Certs: cert}).Validate()))
+}
+
+func newKeyCipher(t *testing.T) *KeyCipher {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	assert.NoError(t, err)
+	keys, err := NewKeyCipher(key)
+	assert.NoError(t, err)
+	return keys
+}
+
+func Test_KeyCipher(t *testing.T) {
+	keys := newKeyCipher(t)
+	sealed, err := keys.Seal("lab-client", "secret")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(sealed), "secret")
+
+	key, err := keys.Open("lab-client", sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", key)
+
+	_, err = keys.Open("other-client", sealed)
+	assert.True(t, errors.IsForbidden(err), "a key must not be moved to another bundle")
+	_, err = newKeyCipher(t).Open("lab-client", sealed)
+	assert.True(t, errors.IsForbidden(err))
+
+	_, err = NewKeyCipher([]byte("short"))
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func Test_Store(t *testing.T) {
+	cert, key := newKeyPair(t, "lab-ca")
+	bundleStore := NewLocalStore(newKeyCipher(t))
+	defer bundleStore.Close()
+
+	assert.NoError(t, bundleStore.Put(&Bundle{Name: "lab-client", Kind: KindClient, Certs: cert, Key: key}))
+	assert.NoError(t, bundleStore.Put(&Bundle{Name: "lab-ca", Kind: KindCA, Certs: cert}))
+	assert.True(t, errors.IsInvalid(bundleStore.Put(&Bundle{Name: "broken", Kind: KindCA})))
+
+	// The private keys are not listed
+	bundles, err := bundleStore.List()
+	assert.NoError(t, err)
+	assert.Len(t, bundles, 2)
+	assert.Equal(t, "lab-ca", bundles[0].Name)
+	assert.Equal(t, "lab-client", bundles[1].Name)
+	assert.Equal(t, "", bundles[1].Key)
+	assert.Nil(t, bundles[1].SealedKey)
+
+	bundle, err := bundleStore.Get("lab-client")
+	assert.NoError(t, err)
+	assert.Equal(t, key, bundle.Key)
+	_, err = bundle.KeyPair()
+	assert.NoError(t, err)
+
+	bundle, err = bundleStore.Get("lab-ca")
+	assert.NoError(t, err)
+	pool, err := bundle.CertPool()
+	assert.NoError(t, err)
+	assert.NotNil(t, pool)
+
+	// The private keys are only kept encrypted, and the bundles given are left as they are
+	client := &Bundle{Name: "lab-client", Kind: KindClient, Certs: cert, Key: key}
+	assert.NoError(t, bundleStore.Put(client))
+	assert.Equal(t, key, client.Key)
+	assert.Nil(t, client.SealedKey)
+	sealed := &Bundle{}
+	assert.NoError(t, bundleStore.(*store).bundles.Get("lab-client", sealed))
+	assert.Equal(t, "", sealed.Key)
+	assert.NotEmpty(t, sealed.SealedKey)
+
+	// A bundle put again replaces the previous one
+	otherCert, _ := newKeyPair(t, "other-ca")
+	assert.NoError(t, bundleStore.Put(&Bundle{Name: "lab-ca", Kind: KindCA, Certs: otherCert}))
+	bundle, err = bundleStore.Get("lab-ca")
+	assert.NoError(t, err)
+	assert.Equal(t, otherCert, bundle.Certs)
+	bundles, err = bundleStore.List()
+	assert.NoError(t, err)
+	assert.Len(t, bundles, 2)
+
+	assert.NoError(t, bundleStore.Delete("lab-ca"))
+	_, err = bundleStore.Get("lab-ca")
+	assert.True(t, errors.IsNotFound(err))
+	assert.EqualError(t, bundleStore.Delete("lab-ca"), "trust bundle 'lab-ca' not found")
+}
+
+func Test_StoreWithoutKeys(t *testing.T) {
+	cert, key := newKeyPair(t, "lab-ca")
+	store := NewLocalStore(nil)
+	defer store.Close()
+
+	assert.NoError(t, store.Put(&Bundle{Name: "lab-ca", Kind: KindCA, Certs: cert}))
+	assert.True(t, errors.IsInvalid(store.Put(&Bundle{Name: "lab-client", Kind: KindClient, Certs: cert, Key: key})),
+		"private keys must not be stored in the clear")
+}
@@ -0,0 +1,132 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tuning stores the runtime-tunable parameters of the controllers, so that they can be
+// tuned by operators without redeploying and survive restarts.
+package tuning
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/atomix/atomix-go-client/pkg/atomix"
+	"github.com/onosproject/onos-config/pkg/store/records"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Tuning is the tuning of a controller. Zero parameters keep the defaults of the controller.
+type Tuning struct {
+	// Controller is the name of the tuned controller, e.g. DeviceChange
+	Controller string `json:"controller"`
+	// BatchWindow is how long a newly queued request is held before it is reconciled
+	BatchWindow time.Duration `json:"batchWindow"`
+	// Parallelism is how many requests the controller may reconcile at once
+	Parallelism int `json:"parallelism"`
+	// RetryStep is the backoff after a failed reconciliation, doubled after each further failure
+	RetryStep time.Duration `json:"retryStep"`
+	// MaxRetryDelay caps the backoff after a failed reconciliation
+	MaxRetryDelay time.Duration `json:"maxRetryDelay"`
+	// User is the user who last tuned the controller
+	User string `json:"user"`
+	// Updated is when the controller was last tuned
+	Updated time.Time `json:"updated"`
+}
+
+// Store stores the tuning of the controllers
+type Store interface {
+	io.Closer
+
+	// Get gets the tuning of a controller
+	Get(controller string) (*Tuning, error)
+
+	// Put tunes a controller, replacing any previous tuning of it
+	Put(tuning *Tuning) error
+
+	// Delete restores the defaults of a controller
+	Delete(controller string) error
+
+	// List lists the tuned controllers, sorted by name
+	List() ([]*Tuning, error)
+}
+
+// kind and notFound describe the tunings in the errors of the store
+const kind = "tuning"
+
+var notFound = records.WithNotFound("controller '%s' is not tuned")
+
+// NewAtomixStore returns a new persistent Store
+func NewAtomixStore(client atomix.Client) (Store, error) {
+	tunings, err := records.NewAtomixMap(client, "onos-config-controller-tuning", kind, notFound)
+	if err != nil {
+		return nil, err
+	}
+	return &store{
+		tunings: tunings,
+	}, nil
+}
+
+// NewLocalStore returns a new store that only keeps tunings in memory
+func NewLocalStore() Store {
+	return &store{
+		tunings: records.NewLocalMap(kind, notFound),
+	}
+}
+
+// store keeps the tunings by controller
+type store struct {
+	tunings records.Map
+}
+
+func (s *store) Get(controller string) (*Tuning, error) {
+	tuning := &Tuning{}
+	if err := s.tunings.Get(controller, tuning); err != nil {
+		return nil, err
+	}
+	return tuning, nil
+}
+
+func (s *store) Put(tuning *Tuning) error {
+	if tuning.Controller == "" {
+		return errors.NewInvalid("no controller given")
+	}
+	return s.tunings.Put(tuning.Controller, tuning)
+}
+
+func (s *store) Delete(controller string) error {
+	return s.tunings.Delete(controller)
+}
+
+func (s *store) List() ([]*Tuning, error) {
+	list, err := s.tunings.List(func() interface{} { return &Tuning{} })
+	if err != nil {
+		return nil, err
+	}
+	tunings := make([]*Tuning, 0, len(list))
+	for _, record := range list {
+		tunings = append(tunings, record.(*Tuning))
+	}
+	sortTunings(tunings)
+	return tunings, nil
+}
+
+func (s *store) Close() error {
+	return s.tunings.Close()
+}
+
+func sortTunings(tunings []*Tuning) {
+	sort.Slice(tunings, func(i, j int) bool {
+		return tunings[i].Controller < tunings[j].Controller
+	})
+}
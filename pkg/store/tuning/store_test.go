@@ -0,0 +1,71 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tuning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewLocalStore()
+	defer store.Close()
+
+	assert.NoError(t, store.Put(&Tuning{
+		Controller:    "NetworkChange",
+		BatchWindow:   100 * time.Millisecond,
+		MaxRetryDelay: time.Minute,
+		User:          "alice",
+		Updated:       time.Now(),
+	}))
+	assert.NoError(t, store.Put(&Tuning{Controller: "DeviceChange", Parallelism: 8}))
+	assert.True(t, errors.IsInvalid(store.Put(&Tuning{})))
+
+	tunings, err := store.List()
+	assert.NoError(t, err)
+	assert.Len(t, tunings, 2)
+	assert.Equal(t, "DeviceChange", tunings[0].Controller)
+	assert.Equal(t, 8, tunings[0].Parallelism)
+	assert.Equal(t, "NetworkChange", tunings[1].Controller)
+
+	tuning, err := store.Get("NetworkChange")
+	assert.NoError(t, err)
+	assert.Equal(t, 100*time.Millisecond, tuning.BatchWindow)
+	assert.Equal(t, time.Minute, tuning.MaxRetryDelay)
+	assert.Equal(t, "alice", tuning.User)
+
+	assert.NoError(t, store.Delete("NetworkChange"))
+	_, err = store.Get("NetworkChange")
+	assert.True(t, errors.IsNotFound(err))
+	assert.True(t, errors.IsNotFound(store.Delete("NetworkChange")))
+
+	// A controller tuned again keeps only its latest tuning
+	assert.NoError(t, store.Put(&Tuning{Controller: "DeviceChange", BatchWindow: time.Second}))
+	tuning, err = store.Get("DeviceChange")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, tuning.Parallelism)
+	assert.Equal(t, time.Second, tuning.BatchWindow)
+
+	// The tunings returned are copies
+	tuning.BatchWindow = time.Minute
+	tuning, err = store.Get("DeviceChange")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, tuning.BatchWindow)
+
+	assert.EqualError(t, store.Delete("NetworkChange"), "controller 'NetworkChange' is not tuned")
+}
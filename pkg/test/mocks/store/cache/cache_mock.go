@@ -119,3 +119,18 @@ func (mr *MockCacheMockRecorder) Watch(ch, replay interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockCache)(nil).Watch), ch, replay)
 }
+
+// Rebuild mocks base method
+func (m *MockCache) Rebuild() (*cache.Repair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rebuild")
+	ret0, _ := ret[0].(*cache.Repair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rebuild indicates an expected call of Rebuild
+func (mr *MockCacheMockRecorder) Rebuild() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebuild", reflect.TypeOf((*MockCache)(nil).Rebuild))
+}
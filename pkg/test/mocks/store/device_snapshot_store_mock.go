@@ -165,6 +165,36 @@ func (mr *MockDeviceSnapshotStoreMockRecorder) Load(deviceID interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockDeviceSnapshotStore)(nil).Load), deviceID)
 }
 
+// LoadChain mocks base method
+func (m *MockDeviceSnapshotStore) LoadChain(deviceID device.VersionedID) ([]*device0.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadChain", deviceID)
+	ret0, _ := ret[0].([]*device0.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadChain indicates an expected call of LoadChain
+func (mr *MockDeviceSnapshotStoreMockRecorder) LoadChain(deviceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadChain", reflect.TypeOf((*MockDeviceSnapshotStore)(nil).LoadChain), deviceID)
+}
+
+// LoadArchived mocks base method
+func (m *MockDeviceSnapshotStore) LoadArchived(id device0.ID) (*device0.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadArchived", id)
+	ret0, _ := ret[0].(*device0.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadArchived indicates an expected call of LoadArchived
+func (mr *MockDeviceSnapshotStoreMockRecorder) LoadArchived(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadArchived", reflect.TypeOf((*MockDeviceSnapshotStore)(nil).LoadArchived), id)
+}
+
 // LoadAll mocks base method
 func (m *MockDeviceSnapshotStore) LoadAll(ch chan<- *device0.Snapshot) (stream.Context, error) {
 	m.ctrl.T.Helper()
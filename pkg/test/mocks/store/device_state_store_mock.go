@@ -9,6 +9,7 @@ import (
 	device "github.com/onosproject/onos-api/go/onos/config/change/device"
 	network "github.com/onosproject/onos-api/go/onos/config/change/network"
 	device0 "github.com/onosproject/onos-api/go/onos/config/device"
+	state "github.com/onosproject/onos-config/pkg/store/change/device/state"
 	reflect "reflect"
 )
 
@@ -49,3 +50,18 @@ func (mr *MockDeviceStateStoreMockRecorder) Get(id, revision interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDeviceStateStore)(nil).Get), id, revision)
 }
+
+// Search mocks base method
+func (m *MockDeviceStateStore) Search(value string, regex bool) ([]*state.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", value, regex)
+	ret0, _ := ret[0].([]*state.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search
+func (mr *MockDeviceStateStoreMockRecorder) Search(value, regex interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockDeviceStateStore)(nil).Search), value, regex)
+}
@@ -77,6 +77,36 @@ func (mr *MockLeadershipStoreMockRecorder) IsLeader() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLeader", reflect.TypeOf((*MockLeadershipStore)(nil).IsLeader))
 }
 
+// GetLeadership mocks base method
+func (m *MockLeadershipStore) GetLeadership() (*leadership.Leadership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeadership")
+	ret0, _ := ret[0].(*leadership.Leadership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeadership indicates an expected call of GetLeadership
+func (mr *MockLeadershipStoreMockRecorder) GetLeadership() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeadership", reflect.TypeOf((*MockLeadershipStore)(nil).GetLeadership))
+}
+
+// Capabilities mocks base method
+func (m *MockLeadershipStore) Capabilities() (leadership.Capabilities, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capabilities")
+	ret0, _ := ret[0].(leadership.Capabilities)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capabilities indicates an expected call of Capabilities
+func (mr *MockLeadershipStoreMockRecorder) Capabilities() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capabilities", reflect.TypeOf((*MockLeadershipStore)(nil).Capabilities))
+}
+
 // Watch mocks base method
 func (m *MockLeadershipStore) Watch(arg0 chan<- leadership.Leadership) error {
 	m.ctrl.T.Helper()
@@ -78,6 +78,21 @@ func (mr *MockMastershipStoreMockRecorder) GetMastership(id interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMastership", reflect.TypeOf((*MockMastershipStore)(nil).GetMastership), id)
 }
 
+// ListMasterships mocks base method
+func (m *MockMastershipStore) ListMasterships() ([]*mastership.Mastership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMasterships")
+	ret0, _ := ret[0].([]*mastership.Mastership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMasterships indicates an expected call of ListMasterships
+func (mr *MockMastershipStoreMockRecorder) ListMasterships() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMasterships", reflect.TypeOf((*MockMastershipStore)(nil).ListMasterships))
+}
+
 // Watch mocks base method
 func (m *MockMastershipStore) Watch(arg0 device.ID, arg1 chan<- mastership.Mastership) error {
 	m.ctrl.T.Helper()
@@ -91,3 +106,31 @@ func (mr *MockMastershipStoreMockRecorder) Watch(arg0, arg1 interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockMastershipStore)(nil).Watch), arg0, arg1)
 }
+
+// WatchAll mocks base method
+func (m *MockMastershipStore) WatchAll(arg0 chan<- mastership.Mastership) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchAll", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchAll indicates an expected call of WatchAll
+func (mr *MockMastershipStoreMockRecorder) WatchAll(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchAll", reflect.TypeOf((*MockMastershipStore)(nil).WatchAll), arg0)
+}
+
+// SetPreference mocks base method
+func (m *MockMastershipStore) SetPreference(arg0 device.ID, arg1 mastership.Preference) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPreference", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPreference indicates an expected call of SetPreference
+func (mr *MockMastershipStoreMockRecorder) SetPreference(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPreference", reflect.TypeOf((*MockMastershipStore)(nil).SetPreference), arg0, arg1)
+}
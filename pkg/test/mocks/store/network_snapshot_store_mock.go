@@ -106,6 +106,35 @@ func (mr *MockNetworkSnapshotStoreMockRecorder) Update(snapshot interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNetworkSnapshotStore)(nil).Update), snapshot)
 }
 
+// CreatePartitioned mocks base method
+func (m *MockNetworkSnapshotStore) CreatePartitioned(snapshot *network.NetworkSnapshot, partition string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePartitioned", snapshot, partition)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePartitioned indicates an expected call of CreatePartitioned
+func (mr *MockNetworkSnapshotStoreMockRecorder) CreatePartitioned(snapshot, partition interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePartitioned", reflect.TypeOf((*MockNetworkSnapshotStore)(nil).CreatePartitioned), snapshot, partition)
+}
+
+// GetPartition mocks base method
+func (m *MockNetworkSnapshotStore) GetPartition(id network.ID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPartition", id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPartition indicates an expected call of GetPartition
+func (mr *MockNetworkSnapshotStoreMockRecorder) GetPartition(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPartition", reflect.TypeOf((*MockNetworkSnapshotStore)(nil).GetPartition), id)
+}
+
 // Delete mocks base method
 func (m *MockNetworkSnapshotStore) Delete(snapshot *network.NetworkSnapshot) error {
 	m.ctrl.T.Helper()
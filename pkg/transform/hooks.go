@@ -0,0 +1,125 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+)
+
+// The hooks available out of the box
+const (
+	// HookLowercase writes a string in lowercase
+	HookLowercase = "lowercase"
+	// HookUppercase writes a string in uppercase
+	HookUppercase = "uppercase"
+	// HookTrim removes the leading and trailing white space of a string
+	HookTrim = "trim"
+	// HookMACAddress writes a MAC address, given in any of the forms of net.ParseMAC, as
+	// colon separated lowercase hexadecimal, or uppercase if the "case" argument is "upper"
+	HookMACAddress = "mac-address"
+	// HookCIDR splits an address in CIDR notation, e.g. 10.0.0.1/24, into the address and the
+	// prefix length or netmask. The "address" argument names the sibling leaf the address is
+	// written to, by default the path itself. The "prefix-length" argument names the sibling leaf
+	// the prefix length is written to, as a uint8, and the "netmask" argument the sibling leaf the
+	// IPv4 netmask is written to, e.g. 255.255.255.0. At least one of them must be given.
+	HookCIDR = "cidr"
+)
+
+func init() {
+	RegisterHook(HookLowercase, stringHook(strings.ToLower))
+	RegisterHook(HookUppercase, stringHook(strings.ToUpper))
+	RegisterHook(HookTrim, stringHook(strings.TrimSpace))
+	RegisterHook(HookMACAddress, macAddress, "case")
+	RegisterHook(HookCIDR, cidr, "address", "prefix-length", "netmask")
+}
+
+// stringHook makes a hook of a function of string values
+func stringHook(transform func(string) string) Hook {
+	return func(path string, value *devicechange.TypedValue, args map[string]string) (devicechange.TypedValueMap, error) {
+		s, err := stringValue(value)
+		if err != nil {
+			return nil, err
+		}
+		return devicechange.TypedValueMap{path: devicechange.NewTypedValueString(transform(s))}, nil
+	}
+}
+
+func macAddress(path string, value *devicechange.TypedValue, args map[string]string) (devicechange.TypedValueMap, error) {
+	s, err := stringValue(value)
+	if err != nil {
+		return nil, err
+	}
+	mac, err := net.ParseMAC(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	address := mac.String()
+	switch args["case"] {
+	case "", "lower":
+	case "upper":
+		address = strings.ToUpper(address)
+	default:
+		return nil, fmt.Errorf("unknown case '%s'", args["case"])
+	}
+	return devicechange.TypedValueMap{path: devicechange.NewTypedValueString(address)}, nil
+}
+
+func cidr(path string, value *devicechange.TypedValue, args map[string]string) (devicechange.TypedValueMap, error) {
+	if args["prefix-length"] == "" && args["netmask"] == "" {
+		return nil, fmt.Errorf("neither a prefix-length nor a netmask leaf is given")
+	}
+	s, err := stringValue(value)
+	if err != nil {
+		return nil, err
+	}
+	ip, network, err := net.ParseCIDR(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(devicechange.TypedValueMap)
+	addressPath := path
+	if args["address"] != "" {
+		addressPath = siblingPath(path, args["address"])
+	}
+	values[addressPath] = devicechange.NewTypedValueString(ip.String())
+	ones, _ := network.Mask.Size()
+	if leaf := args["prefix-length"]; leaf != "" {
+		values[siblingPath(path, leaf)] = devicechange.NewTypedValueUint(uint(ones), devicechange.WidthEight)
+	}
+	if leaf := args["netmask"]; leaf != "" {
+		if ip.To4() == nil {
+			return nil, fmt.Errorf("%s is not an IPv4 address and has no netmask", s)
+		}
+		values[siblingPath(path, leaf)] = devicechange.NewTypedValueString(net.IP(network.Mask).String())
+	}
+	return values, nil
+}
+
+// siblingPath returns the path of a leaf next to the leaf of a path
+func siblingPath(path string, leaf string) string {
+	return path[:strings.LastIndex(path, "/")+1] + leaf
+}
+
+func stringValue(value *devicechange.TypedValue) (string, error) {
+	if value == nil || value.Type != devicechange.ValueType_STRING {
+		return "", fmt.Errorf("not a string value")
+	}
+	return (*devicechange.TypedString)(value).String(), nil
+}
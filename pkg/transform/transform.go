@@ -0,0 +1,168 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package transform normalizes the values written by gNMI Set requests before they are validated
+// and stored, so that clients need not normalize them themselves. Rules, kept in the transformation
+// rule store, bind a hook to the paths it applies to, e.g. to write MAC addresses in lowercase.
+package transform
+
+import (
+	"regexp"
+	"sort"
+	"sync"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	transformstore "github.com/onosproject/onos-config/pkg/store/transform"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+)
+
+// Hook transforms the value written to a path into the values to write instead. These may be
+// written to other paths, e.g. to sibling leaves of the path.
+type Hook func(path string, value *devicechange.TypedValue, args map[string]string) (devicechange.TypedValueMap, error)
+
+// registeredHook is a hook and the names of the arguments it accepts
+type registeredHook struct {
+	hook Hook
+	args []string
+}
+
+var hooks = make(map[string]registeredHook)
+var hooksMu = &sync.RWMutex{}
+
+// RegisterHook makes a hook available to the rules under a name, replacing any hook of the same
+// name. args are the names of the arguments the hook accepts.
+func RegisterHook(name string, hook Hook, args ...string) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks[name] = registeredHook{hook: hook, args: args}
+}
+
+// Hooks returns the names of the available hooks, sorted
+func Hooks() []string {
+	hooksMu.RLock()
+	defer hooksMu.RUnlock()
+	names := make([]string, 0, len(hooks))
+	for name := range hooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func getHook(name string) (registeredHook, bool) {
+	hooksMu.RLock()
+	defer hooksMu.RUnlock()
+	hook, ok := hooks[name]
+	return hook, ok
+}
+
+// Validate checks that a rule is well formed, and that it names an available hook and only
+// arguments the hook accepts
+func Validate(rule *transformstore.Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	if _, err := utils.CompileWildcardRegexp(rule.Path, true); err != nil {
+		return errors.NewInvalid("rule '%s' has an invalid path '%s': %v", rule.Name, rule.Path, err)
+	}
+	hook, ok := getHook(rule.Hook)
+	if !ok {
+		return errors.NewInvalid("rule '%s' names an unknown hook '%s'", rule.Name, rule.Hook)
+	}
+	for arg := range rule.Args {
+		if !contains(hook.args, arg) {
+			return errors.NewInvalid("rule '%s': hook '%s' has no argument '%s'", rule.Name, rule.Hook, arg)
+		}
+	}
+	return nil
+}
+
+// Apply transforms the values written to a device of the given type according to the rules.
+// The first rule, in name order, that matches the path of a value applies to it. The values the
+// hooks write may not differ from the values written to the same paths by the request, or by other
+// hooks. The given map is not modified.
+func Apply(rules []*transformstore.Rule, deviceType devicetype.Type,
+	updates devicechange.TypedValueMap) (devicechange.TypedValueMap, error) {
+
+	matchers := make([]*regexp.Regexp, 0, len(rules))
+	applicable := make([]*transformstore.Rule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.DeviceType != "" && rule.DeviceType != deviceType {
+			continue
+		}
+		matcher, err := utils.CompileWildcardRegexp(rule.Path, true)
+		if err != nil {
+			return nil, errors.NewInvalid("rule '%s' has an invalid path '%s': %v", rule.Name, rule.Path, err)
+		}
+		matchers = append(matchers, matcher)
+		applicable = append(applicable, rule)
+	}
+	if len(applicable) == 0 {
+		return updates, nil
+	}
+
+	paths := make([]string, 0, len(updates))
+	for path := range updates {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	transformed := make(devicechange.TypedValueMap)
+	writtenBy := make(map[string]string)
+	for _, path := range paths {
+		values := devicechange.TypedValueMap{path: updates[path]}
+		for i, matcher := range matchers {
+			if !matcher.MatchString(path) {
+				continue
+			}
+			rule := applicable[i]
+			hook, ok := getHook(rule.Hook)
+			if !ok {
+				return nil, errors.NewInvalid("rule '%s' names an unknown hook '%s'", rule.Name, rule.Hook)
+			}
+			var err error
+			if values, err = hook.hook(path, updates[path], rule.Args); err != nil {
+				return nil, errors.NewInvalid("rule '%s' cannot transform %s: %v", rule.Name, path, err)
+			}
+			break
+		}
+		for valuePath, value := range values {
+			if previous, ok := transformed[valuePath]; ok && !sameValue(previous, value) {
+				return nil, errors.NewInvalid("%s is written with different values by %s and %s",
+					valuePath, writtenBy[valuePath], path)
+			}
+			transformed[valuePath] = value
+			writtenBy[valuePath] = path
+		}
+	}
+	return transformed, nil
+}
+
+func sameValue(a *devicechange.TypedValue, b *devicechange.TypedValue) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Type == b.Type && a.ValueToString() == b.ValueToString()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	transformstore "github.com/onosproject/onos-config/pkg/store/transform"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	macPath     = "/interfaces/interface[name=eth1]/config/mac"
+	addressPath = "/interfaces/interface[name=eth1]/config/ip"
+	prefixPath  = "/interfaces/interface[name=eth1]/config/prefix-length"
+	netmaskPath = "/interfaces/interface[name=eth1]/config/netmask"
+)
+
+var rules = []*transformstore.Rule{
+	{Name: "address", Path: "/interfaces/interface[name=*]/config/ip", Hook: HookCIDR,
+		Args: map[string]string{"prefix-length": "prefix-length", "netmask": "netmask"}},
+	{Name: "mac", Path: "/interfaces/interface[name=*]/config/mac", DeviceType: "Devicesim", Hook: HookMACAddress},
+}
+
+func Test_Validate(t *testing.T) {
+	for _, rule := range rules {
+		assert.NoError(t, Validate(rule))
+	}
+	assert.True(t, errors.IsInvalid(Validate(&transformstore.Rule{Name: "x", Path: "/a", Hook: "rot13"})))
+	assert.True(t, errors.IsInvalid(Validate(&transformstore.Rule{Name: "x", Path: "/a", Hook: HookLowercase,
+		Args: map[string]string{"locale": "tr"}})))
+	assert.Contains(t, Hooks(), HookMACAddress)
+}
+
+func Test_Apply(t *testing.T) {
+	updates := devicechange.TypedValueMap{
+		macPath:            devicechange.NewTypedValueString("AA-BB-CC-DD-EE-0F"),
+		addressPath:        devicechange.NewTypedValueString("10.0.1.1/24"),
+		"/system/hostname": devicechange.NewTypedValueString("Leaf-1"),
+	}
+	transformed, err := Apply(rules, "Devicesim", updates)
+	assert.NoError(t, err)
+	assert.Len(t, transformed, 5)
+	assert.Equal(t, "aa:bb:cc:dd:ee:0f", transformed[macPath].ValueToString())
+	assert.Equal(t, "10.0.1.1", transformed[addressPath].ValueToString())
+	assert.Equal(t, "24", transformed[prefixPath].ValueToString())
+	assert.Equal(t, devicechange.ValueType_UINT, transformed[prefixPath].Type)
+	assert.Equal(t, "255.255.255.0", transformed[netmaskPath].ValueToString())
+	assert.Equal(t, "Leaf-1", transformed["/system/hostname"].ValueToString())
+	assert.Equal(t, "AA-BB-CC-DD-EE-0F", updates[macPath].ValueToString(), "the request must not be modified")
+
+	// The MAC address rule is restricted to Devicesim
+	transformed, err = Apply(rules, "Stratum", updates)
+	assert.NoError(t, err)
+	assert.Equal(t, "AA-BB-CC-DD-EE-0F", transformed[macPath].ValueToString())
+}
+
+func Test_ApplyInvalid(t *testing.T) {
+	_, err := Apply(rules, "Devicesim", devicechange.TypedValueMap{
+		macPath: devicechange.NewTypedValueString("not a mac"),
+	})
+	assert.True(t, errors.IsInvalid(err))
+
+	// A hook may not contradict a value of the request
+	_, err = Apply(rules, "Devicesim", devicechange.TypedValueMap{
+		addressPath: devicechange.NewTypedValueString("10.0.1.1/24"),
+		prefixPath:  devicechange.NewTypedValueUint(16, devicechange.WidthEight),
+	})
+	assert.True(t, errors.IsInvalid(err))
+
+	// A value agreeing with it is no conflict
+	_, err = Apply(rules, "Devicesim", devicechange.TypedValueMap{
+		addressPath: devicechange.NewTypedValueString("10.0.1.1/24"),
+		prefixPath:  devicechange.NewTypedValueUint(24, devicechange.WidthEight),
+	})
+	assert.NoError(t, err)
+}
+
+func Test_StringHooks(t *testing.T) {
+	trimRules := []*transformstore.Rule{
+		{Name: "hostname", Path: "/system/hostname", Hook: HookTrim},
+		{Name: "hostname-case", Path: "/system/hostname", Hook: HookLowercase},
+	}
+	transformed, err := Apply(trimRules, "Devicesim", devicechange.TypedValueMap{
+		"/system/hostname": devicechange.NewTypedValueString("  Leaf-1 "),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Leaf-1", transformed["/system/hostname"].ValueToString(), "only the first matching rule applies")
+
+	_, err = Apply(trimRules, "Devicesim", devicechange.TypedValueMap{
+		"/system/hostname": devicechange.NewTypedValueUint(1, devicechange.WidthEight),
+	})
+	assert.True(t, errors.IsInvalid(err))
+}
@@ -0,0 +1,239 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tree assembles the values of the leaves of a subtree into the nested JSON tree that gNMI
+// responses carry, encoded as JSON or as JSON_IETF (RFC 7951).
+package tree
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-config/pkg/utils"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// container is a container, or an entry of a list, by the names of its members
+type container map[string]interface{}
+
+// list is a list of entries, in the order their first leaf was added, indexed by their keys
+type list struct {
+	entries []container
+	index   map[string]container
+}
+
+// MarshalJSON encodes a list as the array of its entries
+func (l *list) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.entries)
+}
+
+// Build returns the JSON tree of the values of leaves. The entries of a list are the objects of
+// an array, each with its keys as members; the key values that are not among the values are
+// strings. With jsonIETF the values are encoded as RFC 7951 has them, e.g. 64 bit integers and
+// decimals as strings and empty leaves as [null].
+func Build(values []*devicechange.PathValue, jsonIETF bool) ([]byte, error) {
+	root := make(container)
+	for _, value := range values {
+		if err := root.add(value.Path, value.GetValue(), jsonIETF); err != nil {
+			return nil, err
+		}
+	}
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// TypedValue returns the JSON tree of the values of leaves as the gNMI value of an encoding,
+// JSON or JSON_IETF
+func TypedValue(values []*devicechange.PathValue, encoding gnmi.Encoding) (*gnmi.TypedValue, error) {
+	switch encoding {
+	case gnmi.Encoding_JSON:
+		tree, err := Build(values, false)
+		if err != nil {
+			return nil, err
+		}
+		return &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: tree}}, nil
+	case gnmi.Encoding_JSON_IETF:
+		tree, err := Build(values, true)
+		if err != nil {
+			return nil, err
+		}
+		return &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: tree}}, nil
+	default:
+		return nil, errors.NewInvalid("encoding %v is not JSON", encoding)
+	}
+}
+
+// add adds the value of a leaf under a container
+func (c container) add(path string, value *devicechange.TypedValue, jsonIETF bool) error {
+	parsed, err := utils.ParseGNMIElements(utils.SplitPath(path))
+	if err != nil {
+		return errors.NewInvalid("invalid path %s: %v", path, err)
+	}
+	if len(parsed.Elem) == 0 {
+		return errors.NewInvalid("the root %s is not a leaf", path)
+	}
+	node := c
+	for _, elem := range parsed.Elem[:len(parsed.Elem)-1] {
+		if node, err = node.child(elem, path); err != nil {
+			return err
+		}
+	}
+	leaf := parsed.Elem[len(parsed.Elem)-1]
+	if len(leaf.Key) > 0 {
+		// A path to a list entry has no value of its own
+		_, err = node.child(leaf, path)
+		return err
+	}
+	switch node[leaf.Name].(type) {
+	case container, *list:
+		return errors.NewInvalid("%s is not a leaf", path)
+	}
+	node[leaf.Name], err = leafValue(value, jsonIETF)
+	if err != nil {
+		return errors.NewInvalid("cannot encode %s: %v", path, err)
+	}
+	return nil
+}
+
+// child returns the container, or the list entry, of an element of a path under a container,
+// adding it if it is not there yet
+func (c container) child(elem *gnmi.PathElem, path string) (container, error) {
+	if len(elem.Key) == 0 {
+		switch member := c[elem.Name].(type) {
+		case container:
+			return member, nil
+		case nil:
+			child := make(container)
+			c[elem.Name] = child
+			return child, nil
+		default:
+			return nil, errors.NewInvalid("%s of %s is not a container", elem.Name, path)
+		}
+	}
+
+	var entries *list
+	switch member := c[elem.Name].(type) {
+	case *list:
+		entries = member
+	case nil:
+		entries = &list{index: make(map[string]container)}
+		c[elem.Name] = entries
+	default:
+		return nil, errors.NewInvalid("%s of %s is not a list", elem.Name, path)
+	}
+	// An entry is identified by all of its keys, in the order of their names
+	names := make([]string, 0, len(elem.Key))
+	for name := range elem.Key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		keys = append(keys, fmt.Sprintf("[%s=%s]", name, elem.Key[name]))
+	}
+	id := strings.Join(keys, "")
+	entry, ok := entries.index[id]
+	if !ok {
+		entry = make(container)
+		for name, value := range elem.Key {
+			entry[name] = value
+		}
+		entries.index[id] = entry
+		entries.entries = append(entries.entries, entry)
+	}
+	return entry, nil
+}
+
+// leafValue returns the JSON value of a leaf
+func leafValue(value *devicechange.TypedValue, jsonIETF bool) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	wide := len(value.TypeOpts) > 0 && value.TypeOpts[0] > int32(devicechange.WidthThirtyTwo)
+	switch value.Type {
+	case devicechange.ValueType_EMPTY:
+		if jsonIETF {
+			return []interface{}{nil}, nil
+		}
+		return nil, nil
+	case devicechange.ValueType_STRING:
+		return (*devicechange.TypedString)(value).String(), nil
+	case devicechange.ValueType_INT:
+		if jsonIETF && wide {
+			return (*devicechange.TypedInt)(value).String(), nil
+		}
+		return (*devicechange.TypedInt)(value).Int(), nil
+	case devicechange.ValueType_UINT:
+		if jsonIETF && wide {
+			return (*devicechange.TypedUint)(value).String(), nil
+		}
+		return (*devicechange.TypedUint)(value).Uint(), nil
+	case devicechange.ValueType_DECIMAL:
+		if jsonIETF {
+			return (*devicechange.TypedDecimal)(value).String(), nil
+		}
+		return (*devicechange.TypedDecimal)(value).Float(), nil
+	case devicechange.ValueType_FLOAT:
+		if jsonIETF {
+			return (*devicechange.TypedFloat)(value).String(), nil
+		}
+		return (*devicechange.TypedFloat)(value).Float32(), nil
+	case devicechange.ValueType_BOOL:
+		return (*devicechange.TypedBool)(value).Bool(), nil
+	case devicechange.ValueType_BYTES:
+		return (*devicechange.TypedBytes)(value).ByteArray(), nil
+	case devicechange.ValueType_LEAFLIST_STRING:
+		return (*devicechange.TypedLeafListString)(value).List(), nil
+	case devicechange.ValueType_LEAFLIST_INT:
+		leafList, width := (*devicechange.TypedLeafListInt)(value).List()
+		if jsonIETF && width > devicechange.WidthThirtyTwo {
+			return formatList(len(leafList), func(i int) string { return fmt.Sprintf("%d", leafList[i]) }), nil
+		}
+		return leafList, nil
+	case devicechange.ValueType_LEAFLIST_UINT:
+		leafList, width := (*devicechange.TypedLeafListUint)(value).List()
+		if jsonIETF && width > devicechange.WidthThirtyTwo {
+			return formatList(len(leafList), func(i int) string { return fmt.Sprintf("%d", leafList[i]) }), nil
+		}
+		return leafList, nil
+	case devicechange.ValueType_LEAFLIST_BOOL:
+		return (*devicechange.TypedLeafListBool)(value).List(), nil
+	case devicechange.ValueType_LEAFLIST_DECIMAL:
+		if jsonIETF {
+			digits, precision := (*devicechange.TypedLeafListDecimal)(value).List()
+			return formatList(len(digits), func(i int) string {
+				return devicechange.NewTypedValueDecimal(digits[i], precision).ValueToString()
+			}), nil
+		}
+		return (*devicechange.TypedLeafListDecimal)(value).ListFloat(), nil
+	case devicechange.ValueType_LEAFLIST_FLOAT:
+		return (*devicechange.TypedLeafListFloat)(value).List(), nil
+	case devicechange.ValueType_LEAFLIST_BYTES:
+		return (*devicechange.TypedLeafListBytes)(value).List(), nil
+	default:
+		return nil, fmt.Errorf("unexpected value type %v", value.Type)
+	}
+}
+
+// formatList returns the strings of the items of a leaf-list
+func formatList(n int, format func(i int) string) []string {
+	items := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		items = append(items, format(i))
+	}
+	return items
+}
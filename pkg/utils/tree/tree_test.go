@@ -0,0 +1,90 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"encoding/json"
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"gotest.tools/assert"
+)
+
+func Test_BuildMultiKeyList(t *testing.T) {
+	values := []*devicechange.PathValue{
+		{Path: "/a/l[x=1][y=b]/x", Value: devicechange.NewTypedValueUint(1, 8)},
+		{Path: "/a/l[x=1][y=b]/v", Value: devicechange.NewTypedValueString("1b")},
+		{Path: "/a/l[x=2][y=a]/v", Value: devicechange.NewTypedValueString("2a")},
+		{Path: "/a/l[y=a][x=1]/v", Value: devicechange.NewTypedValueString("1a")},
+		{Path: "/a/l[x=1][y=b]/e", Value: devicechange.NewTypedValueString("")},
+	}
+	tree, err := Build(values, true)
+	assert.NilError(t, err)
+
+	var decoded struct {
+		A struct {
+			L []map[string]interface{} `json:"l"`
+		} `json:"a"`
+	}
+	assert.NilError(t, json.Unmarshal(tree, &decoded))
+	assert.Equal(t, 3, len(decoded.A.L))
+	assert.DeepEqual(t, map[string]interface{}{"x": float64(1), "y": "b", "v": "1b", "e": ""}, decoded.A.L[0])
+	assert.DeepEqual(t, map[string]interface{}{"x": "2", "y": "a", "v": "2a"}, decoded.A.L[1])
+	assert.DeepEqual(t, map[string]interface{}{"x": "1", "y": "a", "v": "1a"}, decoded.A.L[2])
+}
+
+func Test_BuildEncodings(t *testing.T) {
+	values := []*devicechange.PathValue{
+		{Path: "/c/big", Value: devicechange.NewTypedValueInt(-5, 64)},
+		{Path: "/c/small", Value: devicechange.NewTypedValueUint(5, 16)},
+		{Path: "/c/dec", Value: devicechange.NewTypedValueDecimal(1234, 2)},
+		{Path: "/c/flag", Value: devicechange.NewTypedValueEmpty()},
+	}
+
+	ietf, err := TypedValue(values, gnmi.Encoding_JSON_IETF)
+	assert.NilError(t, err)
+	var decoded map[string]map[string]interface{}
+	assert.NilError(t, json.Unmarshal(ietf.GetJsonIetfVal(), &decoded))
+	assert.DeepEqual(t, map[string]interface{}{
+		"big": "-5", "small": float64(5), "dec": "12.34", "flag": []interface{}{nil},
+	}, decoded["c"])
+
+	plain, err := TypedValue(values, gnmi.Encoding_JSON)
+	assert.NilError(t, err)
+	assert.Assert(t, plain.GetJsonIetfVal() == nil)
+	decoded = nil
+	assert.NilError(t, json.Unmarshal(plain.GetJsonVal(), &decoded))
+	assert.DeepEqual(t, map[string]interface{}{
+		"big": float64(-5), "small": float64(5), "dec": 12.34, "flag": nil,
+	}, decoded["c"])
+
+	_, err = TypedValue(values, gnmi.Encoding_PROTO)
+	assert.ErrorContains(t, err, "not JSON")
+}
+
+func Test_BuildConflicts(t *testing.T) {
+	_, err := Build([]*devicechange.PathValue{
+		{Path: "/a/b", Value: devicechange.NewTypedValueString("leaf")},
+		{Path: "/a/b/c", Value: devicechange.NewTypedValueString("child")},
+	}, true)
+	assert.ErrorContains(t, err, "is not a container")
+
+	_, err = Build([]*devicechange.PathValue{
+		{Path: "/a/l[k=1]/v", Value: devicechange.NewTypedValueString("entry")},
+		{Path: "/a/l", Value: devicechange.NewTypedValueString("leaf")},
+	}, true)
+	assert.ErrorContains(t, err, "is not a leaf")
+}
@@ -0,0 +1,117 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package values
+
+import (
+	"fmt"
+	"sync"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/onosproject/onos-config/pkg/modelregistry"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// AnyCodec converts the values of a vendor type, that gNMI carries as a protobuf Any, to and from
+// the standard typed value onos-config stores and validates them as
+type AnyCodec interface {
+	// TypeURL is the type URL of the Any values the codec converts
+	TypeURL() string
+	// Decode converts an Any value of the type of the codec to a typed value
+	Decode(value *anypb.Any) (*devicechange.TypedValue, error)
+	// Encode converts a typed value back to an Any value of the type of the codec
+	Encode(value *devicechange.TypedValue) (*anypb.Any, error)
+}
+
+// anyCodecs are the codecs registered, by type URL, and the codecs of the paths of each model
+type anyCodecs struct {
+	mu     sync.RWMutex
+	types  map[string]AnyCodec
+	models map[string]map[string]AnyCodec
+}
+
+var codecs = &anyCodecs{
+	types:  make(map[string]AnyCodec),
+	models: make(map[string]map[string]AnyCodec),
+}
+
+// RegisterAnyCodec registers the codec of a vendor type for the leaves of a model whose values are
+// of that type, given as paths whose list keys are wildcards or values; the model is named as by
+// utils.ToModelName. The Any values of its type are then decoded wherever they are received, on
+// the northbound and from the devices, and the values of these leaves are encoded as Any values
+// wherever they are sent: in the Sets pushed to the devices and the PROTO encoded Get responses.
+func RegisterAnyCodec(modelName string, codec AnyCodec, paths ...string) {
+	codecs.mu.Lock()
+	defer codecs.mu.Unlock()
+	codecs.types[codec.TypeURL()] = codec
+	modelCodecs, ok := codecs.models[modelName]
+	if !ok {
+		modelCodecs = make(map[string]AnyCodec)
+		codecs.models[modelName] = modelCodecs
+	}
+	for _, path := range paths {
+		modelCodecs[modelregistry.AnonymizePathIndices(path)] = codec
+	}
+}
+
+// UnregisterAnyCodecs unregisters the codecs of the leaves of a model, and those of their types
+// no other model uses
+func UnregisterAnyCodecs(modelName string) {
+	codecs.mu.Lock()
+	defer codecs.mu.Unlock()
+	delete(codecs.models, modelName)
+	used := make(map[string]bool)
+	for _, modelCodecs := range codecs.models {
+		for _, codec := range modelCodecs {
+			used[codec.TypeURL()] = true
+		}
+	}
+	for typeURL := range codecs.types {
+		if !used[typeURL] {
+			delete(codecs.types, typeURL)
+		}
+	}
+}
+
+// decodeAny converts an Any value with the codec of its type
+func decodeAny(value *anypb.Any) (*devicechange.TypedValue, error) {
+	codecs.mu.RLock()
+	codec, ok := codecs.types[value.GetTypeUrl()]
+	codecs.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no codec registered for values of type %s", value.GetTypeUrl())
+	}
+	typedValue, err := codec.Decode(value)
+	if err != nil {
+		return nil, fmt.Errorf("decoding a value of type %s: %v", value.GetTypeUrl(), err)
+	}
+	return typedValue, nil
+}
+
+// EncodeAny converts the value of a path of a model to gNMI: as an Any value if a codec is
+// registered for the path, otherwise as by NativeTypeToGnmiTypedValue
+func EncodeAny(modelName string, path string, value *devicechange.TypedValue) (*gnmi.TypedValue, error) {
+	codecs.mu.RLock()
+	codec, ok := codecs.models[modelName][modelregistry.AnonymizePathIndices(path)]
+	codecs.mu.RUnlock()
+	if !ok || value.GetType() == devicechange.ValueType_EMPTY {
+		return NativeTypeToGnmiTypedValue(value)
+	}
+	anyValue, err := codec.Encode(value)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s as %s: %v", path, codec.TypeURL(), err)
+	}
+	return &gnmi.TypedValue{Value: &gnmi.TypedValue_AnyVal{AnyVal: anyValue}}, nil
+}
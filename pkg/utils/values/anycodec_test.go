@@ -0,0 +1,87 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package values
+
+import (
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const testModelName = "Vendor-1.0.0"
+
+// stringCodec converts the StringValue wrappers to strings
+type stringCodec struct{}
+
+func (c stringCodec) TypeURL() string {
+	return "type.googleapis.com/google.protobuf.StringValue"
+}
+
+func (c stringCodec) Decode(value *anypb.Any) (*devicechange.TypedValue, error) {
+	wrapper := &wrapperspb.StringValue{}
+	if err := value.UnmarshalTo(wrapper); err != nil {
+		return nil, err
+	}
+	return devicechange.NewTypedValueString(wrapper.Value), nil
+}
+
+func (c stringCodec) Encode(value *devicechange.TypedValue) (*anypb.Any, error) {
+	return anypb.New(wrapperspb.String(value.ValueToString()))
+}
+
+func Test_AnyCodec(t *testing.T) {
+	RegisterAnyCodec(testModelName, stringCodec{}, "/vendor/port[id=*]/label")
+	defer UnregisterAnyCodecs(testModelName)
+
+	anyValue, err := anypb.New(wrapperspb.String("uplink"))
+	assert.NoError(t, err)
+	native, err := GnmiTypedValueToNativeType(&gnmi.TypedValue{Value: &gnmi.TypedValue_AnyVal{AnyVal: anyValue}}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, devicechange.ValueType_STRING, native.Type)
+	assert.Equal(t, "uplink", native.ValueToString())
+
+	encoded, err := EncodeAny(testModelName, "/vendor/port[id=1]/label", native)
+	assert.NoError(t, err)
+	assert.Equal(t, stringCodec{}.TypeURL(), encoded.GetAnyVal().GetTypeUrl())
+	decoded, err := GnmiTypedValueToNativeType(encoded, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, native, decoded)
+
+	// The other paths and models are not encoded
+	encoded, err = EncodeAny(testModelName, "/vendor/port[id=1]/name", native)
+	assert.NoError(t, err)
+	assert.Equal(t, "uplink", encoded.GetStringVal())
+	encoded, err = EncodeAny("Other-1.0.0", "/vendor/port[id=1]/label", native)
+	assert.NoError(t, err)
+	assert.Equal(t, "uplink", encoded.GetStringVal())
+
+	setRequest, err := NativeChangeToGnmiChange(&devicechange.Change{
+		DeviceType:    "Vendor",
+		DeviceVersion: "1.0.0",
+		Values: []*devicechange.ChangeValue{
+			{Path: "/vendor/port[id=1]/label", Value: native},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, stringCodec{}.TypeURL(), setRequest.Update[0].Val.GetAnyVal().GetTypeUrl())
+
+	UnregisterAnyCodecs(testModelName)
+	_, err = GnmiTypedValueToNativeType(&gnmi.TypedValue{Value: &gnmi.TypedValue_AnyVal{AnyVal: anyValue}}, nil)
+	assert.Error(t, err)
+}
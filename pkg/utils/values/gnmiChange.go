@@ -21,8 +21,10 @@ import (
 	"github.com/openconfig/gnmi/proto/gnmi"
 )
 
-// NativeChangeToGnmiChange converts a Protobuf defined Change object to gNMI format
+// NativeChangeToGnmiChange converts a Protobuf defined Change object to gNMI format; the values of
+// the vendor types of the model of the device are encoded by their AnyCodec
 func NativeChangeToGnmiChange(c *devicechange.Change) (*gnmi.SetRequest, error) {
+	modelName := utils.ToModelName(c.DeviceType, c.DeviceVersion)
 	var deletePaths = []*gnmi.Path{}
 	var replacedPaths = []*gnmi.Update{}
 	var updatedPaths = []*gnmi.Update{}
@@ -38,7 +40,7 @@ func NativeChangeToGnmiChange(c *devicechange.Change) (*gnmi.SetRequest, error)
 		if changeValue.Removed {
 			deletePaths = append(deletePaths, &gnmi.Path{Elem: pathElemsRefs.Elem})
 		} else {
-			gnmiValue, err := NativeTypeToGnmiTypedValue(changeValue.GetValue())
+			gnmiValue, err := EncodeAny(modelName, changeValue.Path, changeValue.GetValue())
 			if err != nil {
 				return nil, fmt.Errorf("error converting %s: %s", changeValue.Path, err)
 			}
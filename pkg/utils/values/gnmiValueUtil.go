@@ -56,6 +56,11 @@ func GnmiTypedValueToNativeType(gnmiTv *gnmi.TypedValue, modelPath *modelregistr
 			typeOpt0 = modelPath.TypeOpts[0]
 		}
 		return handleLeafList(v, typeOpt0)
+	case *gnmi.TypedValue_AnyVal:
+		if v.AnyVal == nil {
+			return nil, fmt.Errorf("not yet supported %v", v)
+		}
+		return decodeAny(v.AnyVal)
 	default:
 		return nil, fmt.Errorf("not yet supported %v", v)
 	}
@@ -0,0 +1,187 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package values
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+)
+
+// normalizers give the canonical form of the string values of YANG types that several strings
+// stand for, by the name of the type; they return false if the string is not of the type
+var normalizers = map[string]func(string) (string, bool){
+	"ipv4-address":         normalizeIPv4Address,
+	"ipv4-address-no-zone": normalizeIPv4Address,
+	"ipv6-address":         normalizeIPv6Address,
+	"ipv6-address-no-zone": normalizeIPv6Address,
+	"ip-address":           normalizeIPAddress,
+	"ip-address-no-zone":   normalizeIPAddress,
+	"ipv4-prefix":          normalizeIPv4Prefix,
+	"ipv6-prefix":          normalizeIPv6Prefix,
+	"ip-prefix":            normalizeIPPrefix,
+	"mac-address":          normalizeMACAddress,
+	"phys-address":         normalizeMACAddress,
+}
+
+// NormalizeValue returns the canonical form of a string or string leaf-list value of a leaf of
+// the given YANG type, as defined by RFC 6991: lower case, no leading zeros, compressed IPv6
+// addresses, zero host bits in prefixes. The value is returned as is if its type has no canonical
+// form or if it is not valid for its type, which is left to the validation of the model.
+func NormalizeValue(typeName string, value *devicechange.TypedValue) *devicechange.TypedValue {
+	normalize, ok := normalizers[typeName]
+	if !ok || value == nil {
+		return value
+	}
+	switch value.Type {
+	case devicechange.ValueType_STRING:
+		if normalized, ok := normalize(value.ValueToString()); ok && normalized != value.ValueToString() {
+			return devicechange.NewTypedValueString(normalized)
+		}
+	case devicechange.ValueType_LEAFLIST_STRING:
+		list := (*devicechange.TypedLeafListString)(value).List()
+		normalizedList := make([]string, len(list))
+		changed := false
+		for i, s := range list {
+			normalizedList[i] = s
+			if normalized, ok := normalize(s); ok && normalized != s {
+				normalizedList[i] = normalized
+				changed = true
+			}
+		}
+		if changed {
+			return devicechange.NewLeafListStringTv(normalizedList)
+		}
+	}
+	return value
+}
+
+// splitZone splits the zone off an address, e.g. "%eth0"
+func splitZone(s string) (string, string) {
+	if i := strings.Index(s, "%"); i >= 0 {
+		return s[:i], s[i:]
+	}
+	return s, ""
+}
+
+// parseIPv4 parses an IPv4 address whose octets may have leading zeros, which net.ParseIP refuses
+func parseIPv4(s string) (net.IP, bool) {
+	octets := strings.Split(s, ".")
+	if len(octets) != 4 {
+		return nil, false
+	}
+	ip := make(net.IP, net.IPv4len)
+	for i, octet := range octets {
+		if octet == "" || len(octet) > 3 {
+			return nil, false
+		}
+		n, err := strconv.ParseUint(octet, 10, 8)
+		if err != nil {
+			return nil, false
+		}
+		ip[i] = byte(n)
+	}
+	return ip, true
+}
+
+func parseIPv6(s string) (net.IP, bool) {
+	if !strings.Contains(s, ":") {
+		return nil, false
+	}
+	ip := net.ParseIP(s)
+	return ip, ip != nil
+}
+
+func normalizeIPv4Address(s string) (string, bool) {
+	address, zone := splitZone(s)
+	ip, ok := parseIPv4(address)
+	if !ok {
+		return s, false
+	}
+	return ip.String() + zone, true
+}
+
+func normalizeIPv6Address(s string) (string, bool) {
+	address, zone := splitZone(s)
+	ip, ok := parseIPv6(address)
+	if !ok {
+		return s, false
+	}
+	return ip.String() + zone, true
+}
+
+func normalizeIPAddress(s string) (string, bool) {
+	if normalized, ok := normalizeIPv4Address(s); ok {
+		return normalized, true
+	}
+	return normalizeIPv6Address(s)
+}
+
+// normalizePrefix normalizes a prefix with the parser of its address, zeroing its host bits
+func normalizePrefix(s string, parse func(string) (net.IP, bool), bits int) (string, bool) {
+	i := strings.Index(s, "/")
+	if i < 0 {
+		return s, false
+	}
+	ip, ok := parse(s[:i])
+	if !ok {
+		return s, false
+	}
+	length, err := strconv.Atoi(s[i+1:])
+	if err != nil || length < 0 || length > bits {
+		return s, false
+	}
+	if bits == 8*net.IPv4len {
+		ip = ip.To4()
+	}
+	return fmt.Sprintf("%s/%d", ip.Mask(net.CIDRMask(length, bits)), length), true
+}
+
+func normalizeIPv4Prefix(s string) (string, bool) {
+	return normalizePrefix(s, parseIPv4, 8*net.IPv4len)
+}
+
+func normalizeIPv6Prefix(s string) (string, bool) {
+	return normalizePrefix(s, parseIPv6, 8*net.IPv6len)
+}
+
+func normalizeIPPrefix(s string) (string, bool) {
+	if normalized, ok := normalizeIPv4Prefix(s); ok {
+		return normalized, true
+	}
+	return normalizeIPv6Prefix(s)
+}
+
+// normalizeMACAddress gives a MAC address as lower case pairs of hex digits separated by colons;
+// dashes and single digits are accepted
+func normalizeMACAddress(s string) (string, bool) {
+	parts := strings.Split(strings.ReplaceAll(s, "-", ":"), ":")
+	for i, part := range parts {
+		if part == "" || len(part) > 2 {
+			return s, false
+		}
+		if _, err := strconv.ParseUint(part, 16, 8); err != nil {
+			return s, false
+		}
+		if len(part) == 1 {
+			part = "0" + part
+		}
+		parts[i] = strings.ToLower(part)
+	}
+	return strings.Join(parts, ":"), true
+}
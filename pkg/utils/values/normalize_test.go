@@ -0,0 +1,57 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package values
+
+import (
+	"testing"
+
+	devicechange "github.com/onosproject/onos-api/go/onos/config/change/device"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NormalizeValue(t *testing.T) {
+	tests := []struct {
+		typeName string
+		value    string
+		expected string
+	}{
+		{"ipv4-address", "192.168.001.010", "192.168.1.10"},
+		{"ipv4-address", "10.0.0.1%eth0", "10.0.0.1%eth0"},
+		{"ipv4-address", "10.0.0.256", "10.0.0.256"},
+		{"ipv6-address", "2001:DB8:0:0:0:0:0:1", "2001:db8::1"},
+		{"ipv6-address", "FE80::1%eth0", "fe80::1%eth0"},
+		{"ip-address", "010.1.2.3", "10.1.2.3"},
+		{"ip-address", "2001:0DB8::0001", "2001:db8::1"},
+		{"ip-address", "not-an-address", "not-an-address"},
+		{"ipv4-prefix", "10.1.2.3/08", "10.0.0.0/8"},
+		{"ipv4-prefix", "10.1.2.3/33", "10.1.2.3/33"},
+		{"ipv6-prefix", "2001:DB8::1/32", "2001:db8::/32"},
+		{"ip-prefix", "192.168.1.1/24", "192.168.1.0/24"},
+		{"ip-prefix", "2001:db8:0::/48", "2001:db8::/48"},
+		{"mac-address", "AA-BB-CC-0D-E-FF", "aa:bb:cc:0d:0e:ff"},
+		{"mac-address", "aa::cc:dd:ee:ff", "aa::cc:dd:ee:ff"},
+		{"string", "AA-BB-CC-0D-E-FF", "AA-BB-CC-0D-E-FF"},
+	}
+	for _, test := range tests {
+		normalized := NormalizeValue(test.typeName, devicechange.NewTypedValueString(test.value))
+		assert.Equal(t, test.expected, normalized.ValueToString(), "%s %s", test.typeName, test.value)
+	}
+
+	list := NormalizeValue("ipv4-address", devicechange.NewLeafListStringTv([]string{"10.0.0.01", "10.0.0.2"}))
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, (*devicechange.TypedLeafListString)(list).List())
+
+	value := devicechange.NewTypedValueUint(10, devicechange.WidthThirtyTwo)
+	assert.Equal(t, value, NormalizeValue("ipv4-address", value))
+}
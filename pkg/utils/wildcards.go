@@ -18,29 +18,114 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	pb "github.com/openconfig/gnmi/proto/gnmi"
 )
 
 // MatchWildcardRegexp creates a Regular Expression from a gNMI wild-carded path
 // This follows the gNMI wildcard syntax
 // https://github.com/openconfig/reference/blob/master/rpc/gnmi/gnmi-path-conventions.md#wildcards-in-paths
+// It panics if the query is not a valid expression; see CompileWildcardRegexp for user input.
 func MatchWildcardRegexp(query string, exact bool) *regexp.Regexp {
+	return regexp.MustCompile(wildcardRegexp(query, exact))
+}
+
+// CompileWildcardRegexp is MatchWildcardRegexp returning an error for an invalid query
+func CompileWildcardRegexp(query string, exact bool) (*regexp.Regexp, error) {
+	return regexp.Compile(wildcardRegexp(query, exact))
+}
+
+func wildcardRegexp(query string, exact bool) string {
 	const legalChars = `a-zA-Z0-9_:,\-\.`
 	regexpQuery := strings.ReplaceAll(query, `[`, `\[`)
 	regexpQuery = strings.ReplaceAll(regexpQuery, `*`, `[`+legalChars+`]*?`) // Not greedy
 	regexpQuery = strings.ReplaceAll(regexpQuery, `...`, `.*`)               // greedy
 	if exact {
-		return regexp.MustCompile(fmt.Sprintf("^%s$", regexpQuery))
+		return fmt.Sprintf("^%s$", regexpQuery)
 	}
-	return regexp.MustCompile(fmt.Sprintf("^%s", regexpQuery))
+	return fmt.Sprintf("^%s", regexpQuery)
 }
 
 // MatchWildcardChNameRegexp creates a Regular Expression from a wild-carded path
+// It panics if the query is not a valid expression; see CompileWildcardChNameRegexp for user input.
 func MatchWildcardChNameRegexp(query string, exact bool) *regexp.Regexp {
+	return regexp.MustCompile(wildcardChNameRegexp(query, exact))
+}
+
+// CompileWildcardChNameRegexp is MatchWildcardChNameRegexp returning an error for an invalid query
+func CompileWildcardChNameRegexp(query string, exact bool) (*regexp.Regexp, error) {
+	return regexp.Compile(wildcardChNameRegexp(query, exact))
+}
+
+func wildcardChNameRegexp(query string, exact bool) string {
 	const legalChars = `a-zA-Z0-9_:,\-\.`
 	regexpQuery := strings.ReplaceAll(query, `?`, `[`+legalChars+`]{1}`)     // greedy
 	regexpQuery = strings.ReplaceAll(regexpQuery, `*`, `[`+legalChars+`]*?`) // Not greedy
 	if exact {
-		return regexp.MustCompile(fmt.Sprintf("^%s$", regexpQuery))
+		return fmt.Sprintf("^%s$", regexpQuery)
+	}
+	return fmt.Sprintf("^%s", regexpQuery)
+}
+
+// SubtreeContains compares the elements of a path with those of a subtree, and returns true if
+// the path is the subtree or is under it. The '*' wildcard matches any element name or key value,
+// the '...' wildcard any number of elements, and an element of the subtree without keys stands
+// for all the entries of its list.
+func SubtreeContains(subtree []*pb.PathElem, elems []*pb.PathElem) bool {
+	for i, subtreeElem := range subtree {
+		if subtreeElem.Name == "..." {
+			for j := i; j <= len(elems); j++ {
+				if SubtreeContains(subtree[i+1:], elems[j:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if i >= len(elems) {
+			return false
+		}
+		elem := elems[i]
+		if subtreeElem.Name != "*" && subtreeElem.Name != elem.Name {
+			return false
+		}
+		for key, value := range subtreeElem.Key {
+			if value != "*" && elem.Key[key] != value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsWildcardPath returns whether a path has the '*' or the '...' wildcard
+func IsWildcardPath(path string) bool {
+	return strings.Contains(path, "*") || strings.Contains(path, "...")
+}
+
+// PathMatcher matches the paths at or under a path, see MatchPath
+type PathMatcher interface {
+	MatchString(path string) bool
+}
+
+// subtreeMatcher matches the paths in a subtree element by element, see SubtreeContains
+type subtreeMatcher struct {
+	subtree []*pb.PathElem
+}
+
+func (m subtreeMatcher) MatchString(path string) bool {
+	parsed, err := ParseGNMIElements(SplitPath(path))
+	return err == nil && SubtreeContains(m.subtree, parsed.Elem)
+}
+
+// MatchPath returns the matcher of the paths at or under a path. A path with wildcards is matched
+// element by element, see SubtreeContains, so that '*' matches a key value whatever its characters,
+// e.g. those of eth1/1, and a whole element; any other path is matched as a prefix, see
+// MatchWildcardRegexp.
+func MatchPath(path string) PathMatcher {
+	if IsWildcardPath(path) {
+		if parsed, err := ParseGNMIElements(SplitPath(path)); err == nil {
+			return subtreeMatcher{subtree: parsed.Elem}
+		}
 	}
-	return regexp.MustCompile(fmt.Sprintf("^%s", regexpQuery))
+	return MatchWildcardRegexp(path, false)
 }
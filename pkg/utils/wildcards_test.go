@@ -209,3 +209,78 @@ func Test_Wildcard_Change2(t *testing.T) {
 	const chID8 = "channge-.." // Dot is a legal character
 	assert.Assert(t, pathRegexpExact.MatchString(chID8), "Expect match "+chID8)
 }
+
+func Test_CompileWildcardInvalid(t *testing.T) {
+	_, err := CompileWildcardRegexp("/aa/(bb", true)
+	assert.Assert(t, err != nil, "Expect an error for an unbalanced parenthesis")
+	_, err = CompileWildcardChNameRegexp("change-[", false)
+	assert.Assert(t, err != nil, "Expect an error for an unterminated class")
+
+	pathRegexp, err := CompileWildcardRegexp("/aa/*/cc", true)
+	assert.NilError(t, err)
+	assert.Assert(t, pathRegexp.MatchString("/aa/bb/cc"), "Expect match /aa/bb/cc")
+}
+
+func Test_SubtreeContains(t *testing.T) {
+	subtree, err := ParseGNMIElements(SplitPath("/interfaces/interface[name=*]/*/mtu"))
+	assert.NilError(t, err)
+	list, err := ParseGNMIElements(SplitPath("/interfaces/interface"))
+	assert.NilError(t, err)
+
+	for _, path := range []string{
+		"/interfaces/interface[name=eth1]/config/mtu",
+		"/interfaces/interface[name=eth1]/state/mtu/value",
+	} {
+		elems, err := ParseGNMIElements(SplitPath(path))
+		assert.NilError(t, err)
+		assert.Assert(t, SubtreeContains(subtree.Elem, elems.Elem), "Expect match "+path)
+		assert.Assert(t, SubtreeContains(list.Elem, elems.Elem), "Expect match "+path)
+	}
+	for _, path := range []string{
+		"/interfaces/interface[name=eth1]/config",
+		"/interfaces/interface[name=eth1]/config/mtu-max",
+		"/system/config/hostname",
+	} {
+		elems, err := ParseGNMIElements(SplitPath(path))
+		assert.NilError(t, err)
+		assert.Assert(t, !SubtreeContains(subtree.Elem, elems.Elem), "Expect NO match "+path)
+	}
+}
+
+func Test_SubtreeContainsAnyDepth(t *testing.T) {
+	subtree, err := ParseGNMIElements(SplitPath("/interfaces/.../mtu"))
+	assert.NilError(t, err)
+
+	for _, path := range []string{
+		"/interfaces/mtu",
+		"/interfaces/interface[name=eth1]/config/mtu",
+		"/interfaces/interface[name=eth1]/subinterfaces/subinterface[index=0]/state/mtu",
+	} {
+		elems, err := ParseGNMIElements(SplitPath(path))
+		assert.NilError(t, err)
+		assert.Assert(t, SubtreeContains(subtree.Elem, elems.Elem), "Expect match "+path)
+	}
+	elems, err := ParseGNMIElements(SplitPath("/interfaces/interface[name=eth1]/config/mtu-max"))
+	assert.NilError(t, err)
+	assert.Assert(t, !SubtreeContains(subtree.Elem, elems.Elem), "Expect NO match")
+}
+
+func Test_MatchPath(t *testing.T) {
+	assert.Assert(t, IsWildcardPath("/interfaces/interface[name=*]"))
+	assert.Assert(t, IsWildcardPath("/interfaces/.../mtu"))
+	assert.Assert(t, !IsWildcardPath("/interfaces/interface[name=eth1/1]"))
+
+	// A key value with a '/' is matched by a wildcard key
+	matcher := MatchPath("/interfaces/interface[name=*]/*/mtu")
+	assert.Assert(t, matcher.MatchString("/interfaces/interface[name=eth1/1]/config/mtu"))
+	assert.Assert(t, matcher.MatchString("/interfaces/interface[name=eth1/1]/state/mtu"))
+	assert.Assert(t, !matcher.MatchString("/interfaces/interface[name=eth1/1]/config/description"))
+
+	matcher = MatchPath("/interfaces/*")
+	assert.Assert(t, matcher.MatchString("/interfaces/interface[name=eth1/1]/config/mtu"))
+	assert.Assert(t, !matcher.MatchString("/system/config/hostname"))
+
+	matcher = MatchPath("/system/config")
+	assert.Assert(t, matcher.MatchString("/system/config/hostname"))
+	assert.Assert(t, !matcher.MatchString("/system/state/hostname"))
+}
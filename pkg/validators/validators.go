@@ -0,0 +1,343 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package validators gates the configuration changes on the verdict of external validation
+// services, e.g. a Batfish-style analysis of the network, to which the proposed changes of each
+// device are POSTed before the change is created.
+package validators
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-config/pkg/audit"
+	"github.com/onosproject/onos-config/pkg/devicegroup"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"gopkg.in/yaml.v2"
+)
+
+var log = logging.GetLogger("validators")
+
+// Policy is what happens to a change when a validator gives no verdict, e.g. it times out
+type Policy string
+
+const (
+	// FailClosed rejects the change
+	FailClosed Policy = "fail-closed"
+	// FailOpen lets the change through
+	FailOpen Policy = "fail-open"
+)
+
+// DefaultTimeout bounds each call to a validator that is not given a timeout
+const DefaultTimeout = 10 * time.Second
+
+// maxReasonBytes bounds what is read of the body of a validator giving no verdict
+const maxReasonBytes = 1024
+
+// Config is the deployment of the external validators
+//
+//	timeout: 10s
+//	failurePolicy: fail-closed
+//	validators:
+//	  - name: batfish
+//	    url: http://batfish-gate:8080/validate
+//	    devices: [leaf-*, spine-*]
+//	  - name: lint
+//	    url: http://lint:8080/check
+//	    timeout: 2s
+//	    failurePolicy: fail-open
+type Config struct {
+	// Timeout and FailurePolicy apply to the validators that do not give theirs
+	Timeout       time.Duration `yaml:"timeout,omitempty"`
+	FailurePolicy Policy        `yaml:"failurePolicy,omitempty"`
+	Validators    []*Validator  `yaml:"validators"`
+}
+
+// Validator is an external validation service. It is POSTed the Proposal of a change as JSON, and
+// answers 200 with a Verdict.
+type Validator struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+	// Devices are the patterns of the IDs of the devices the validator is asked about, all if none;
+	// it is not called for the changes of none of them
+	Devices       []string      `yaml:"devices,omitempty"`
+	Timeout       time.Duration `yaml:"timeout,omitempty"`
+	FailurePolicy Policy        `yaml:"failurePolicy,omitempty"`
+	devices       *devicegroup.Group
+}
+
+// Proposal is the body POSTed to a validator: the changes of the devices it is asked about
+type Proposal struct {
+	// User is the name of the caller making the change, if known
+	User string `json:"user,omitempty"`
+	// DryRun is whether the change is only validated, and is not made whatever the verdict
+	DryRun  bool          `json:"dryRun,omitempty"`
+	Devices []*DeviceDiff `json:"devices"`
+}
+
+// DeviceDiff is the change of the configuration of a device
+type DeviceDiff struct {
+	DeviceID      devicetype.ID      `json:"deviceId"`
+	DeviceType    devicetype.Type    `json:"deviceType"`
+	DeviceVersion devicetype.Version `json:"deviceVersion"`
+	// Paths are the leaves changed, sorted by path
+	Paths []*PathDiff `json:"paths"`
+}
+
+// PathDiff is the change of a leaf. Before is absent for a leaf added, and After for a leaf deleted.
+type PathDiff struct {
+	Path   string  `json:"path"`
+	Type   string  `json:"type,omitempty"`
+	Before *string `json:"before,omitempty"`
+	After  *string `json:"after,omitempty"`
+}
+
+// Verdict is the answer of a validator
+type Verdict struct {
+	Allowed bool `json:"allowed"`
+	// Reasons explain a change not allowed, e.g. the routes it would black-hole
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// Registry is the set of the external validators of the deployment
+type Registry struct {
+	mu         sync.RWMutex
+	validators []*Validator
+	client     *http.Client
+}
+
+var registry = NewRegistry()
+
+// GetRegistry returns the external validators of onos-config
+func GetRegistry() *Registry {
+	return registry
+}
+
+// NewRegistry creates a registry without validators
+func NewRegistry() *Registry {
+	return &Registry{
+		validators: make([]*Validator, 0),
+		client:     &http.Client{},
+	}
+}
+
+// Set replaces the validators by those of a configuration, checking them
+func (r *Registry) Set(config *Config) error {
+	switch config.FailurePolicy {
+	case "":
+		config.FailurePolicy = FailClosed
+	case FailClosed, FailOpen:
+	default:
+		return errors.NewInvalid("unknown failure policy '%s': expected %s or %s", config.FailurePolicy, FailClosed, FailOpen)
+	}
+	if config.Timeout < 0 {
+		return errors.NewInvalid("negative validator timeout %s", config.Timeout)
+	} else if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	names := make(map[string]bool)
+	for _, validator := range config.Validators {
+		if validator.Name == "" {
+			return errors.NewInvalid("validator has no name")
+		} else if names[validator.Name] {
+			return errors.NewInvalid("duplicate validator '%s'", validator.Name)
+		}
+		names[validator.Name] = true
+		if !strings.HasPrefix(validator.URL, "http://") && !strings.HasPrefix(validator.URL, "https://") {
+			return errors.NewInvalid("validator '%s': invalid URL '%s'", validator.Name, validator.URL)
+		}
+		switch validator.FailurePolicy {
+		case "":
+			validator.FailurePolicy = config.FailurePolicy
+		case FailClosed, FailOpen:
+		default:
+			return errors.NewInvalid("validator '%s': unknown failure policy '%s'", validator.Name, validator.FailurePolicy)
+		}
+		if validator.Timeout < 0 {
+			return errors.NewInvalid("validator '%s': negative timeout %s", validator.Name, validator.Timeout)
+		} else if validator.Timeout == 0 {
+			validator.Timeout = config.Timeout
+		}
+		validator.devices = nil
+		if len(validator.Devices) > 0 {
+			group := &devicegroup.Group{Name: validator.Name, Devices: validator.Devices}
+			if err := group.Validate(); err != nil {
+				return err
+			}
+			validator.devices = group
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.validators = config.Validators
+	return nil
+}
+
+// Load sets the validators of a YAML file
+func (r *Registry) Load(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return errors.NewInvalid("cannot parse validators file %s: %v", path, err)
+	}
+	return r.Set(config)
+}
+
+// List returns the validators, in the order they were given
+func (r *Registry) List() []*Validator {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	validators := make([]*Validator, len(r.validators))
+	copy(validators, r.validators)
+	return validators
+}
+
+// Enabled returns whether there are validators to ask
+func (r *Registry) Enabled() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.validators) > 0
+}
+
+// Validate asks the validators concerned, concurrently, whether the changes of a proposal may be
+// made. It returns an invalid error if a validator does not allow them, and an unavailable error if
+// a validator that fails closed gives no verdict.
+func (r *Registry) Validate(ctx context.Context, proposal *Proposal) error {
+	validators := r.List()
+	errs := make([]error, len(validators))
+	wg := sync.WaitGroup{}
+	for i, validator := range validators {
+		asked := validator.filter(proposal)
+		if asked == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, validator *Validator) {
+			defer wg.Done()
+			errs[i] = r.validate(ctx, validator, asked)
+		}(i, validator)
+	}
+	wg.Wait()
+
+	// A rejection is reported ahead of a validator that is unavailable
+	var unavailable error
+	for _, err := range errs {
+		if errors.IsInvalid(err) {
+			return err
+		} else if err != nil && unavailable == nil {
+			unavailable = err
+		}
+	}
+	return unavailable
+}
+
+// filter returns the proposal restricted to the devices of a validator, nil if there are none
+func (v *Validator) filter(proposal *Proposal) *Proposal {
+	if v.devices == nil {
+		return proposal
+	}
+	filtered := &Proposal{
+		User:    proposal.User,
+		DryRun:  proposal.DryRun,
+		Devices: make([]*DeviceDiff, 0, len(proposal.Devices)),
+	}
+	for _, device := range proposal.Devices {
+		if v.devices.Contains(device.DeviceID, device.DeviceVersion, device.DeviceType) {
+			filtered.Devices = append(filtered.Devices, device)
+		}
+	}
+	if len(filtered.Devices) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+// validate asks a validator for its verdict on a proposal, applying its failure policy when it
+// gives none
+func (r *Registry) validate(ctx context.Context, validator *Validator, proposal *Proposal) error {
+	verdict, err := r.ask(ctx, validator, proposal)
+	if err != nil {
+		if validator.FailurePolicy == FailOpen {
+			log.Warnf("Validator %s gave no verdict, letting the change through: %v", validator.Name, err)
+			audit.Record(audit.Entry{
+				User:    proposal.User,
+				Action:  "validator-fail-open",
+				Target:  strings.Join(deviceIDs(proposal), ","),
+				Message: fmt.Sprintf("validator %s gave no verdict: %v", validator.Name, err),
+			})
+			return nil
+		}
+		log.Warnf("Validator %s gave no verdict, rejecting the change: %v", validator.Name, err)
+		return errors.NewUnavailable("validator %s gave no verdict: %v", validator.Name, err)
+	}
+	if !verdict.Allowed {
+		reasons := strings.Join(verdict.Reasons, "; ")
+		if reasons == "" {
+			reasons = "no reason given"
+		}
+		return errors.NewInvalid("change rejected by validator %s: %s", validator.Name, reasons)
+	}
+	return nil
+}
+
+// ask POSTs a proposal to a validator and returns its verdict
+func (r *Registry) ask(ctx context.Context, validator *Validator, proposal *Proposal) (*Verdict, error) {
+	body, err := json.Marshal(proposal)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, validator.Timeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, validator.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := r.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		text, _ := ioutil.ReadAll(http.MaxBytesReader(nil, response.Body, maxReasonBytes))
+		return nil, fmt.Errorf("answered %s %s", response.Status, strings.TrimSpace(string(text)))
+	}
+	verdict := &Verdict{}
+	if err := json.NewDecoder(response.Body).Decode(verdict); err != nil {
+		return nil, fmt.Errorf("invalid verdict: %v", err)
+	}
+	return verdict, nil
+}
+
+func deviceIDs(proposal *Proposal) []string {
+	ids := make([]string, 0, len(proposal.Devices))
+	for _, device := range proposal.Devices {
+		ids = append(ids, string(device.DeviceID))
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,197 @@
+// Copyright 2021-present Open Networking Foundation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validators
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	devicetype "github.com/onosproject/onos-api/go/onos/config/device"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newProposal(devices ...devicetype.ID) *Proposal {
+	after := "9000"
+	proposal := &Proposal{User: "alice"}
+	for _, device := range devices {
+		proposal.Devices = append(proposal.Devices, &DeviceDiff{
+			DeviceID:      device,
+			DeviceType:    "Devicesim",
+			DeviceVersion: "1.0.0",
+			Paths:         []*PathDiff{{Path: "/system/config/mtu", Type: "UINT", After: &after}},
+		})
+	}
+	return proposal
+}
+
+// newValidator starts a validator answering with a verdict, and recording the proposals asked
+func newValidator(t *testing.T, verdict *Verdict, asked *[]*Proposal) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		proposal := &Proposal{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(proposal))
+		*asked = append(*asked, proposal)
+		assert.NoError(t, json.NewEncoder(w).Encode(verdict))
+	}))
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	var asked []*Proposal
+	allowing := newValidator(t, &Verdict{Allowed: true}, &asked)
+	defer allowing.Close()
+
+	registry := NewRegistry()
+	assert.False(t, registry.Enabled())
+	assert.NoError(t, registry.Set(&Config{Validators: []*Validator{
+		{Name: "batfish", URL: allowing.URL},
+	}}))
+	assert.True(t, registry.Enabled())
+	assert.NoError(t, registry.Validate(context.Background(), newProposal("leaf-1")))
+	assert.Len(t, asked, 1)
+	assert.Equal(t, "alice", asked[0].User)
+	assert.Equal(t, devicetype.ID("leaf-1"), asked[0].Devices[0].DeviceID)
+	assert.Equal(t, "9000", *asked[0].Devices[0].Paths[0].After)
+	assert.Nil(t, asked[0].Devices[0].Paths[0].Before)
+
+	var rejectedAsked []*Proposal
+	rejecting := newValidator(t, &Verdict{Reasons: []string{"black-holes 10.0.0.0/8"}}, &rejectedAsked)
+	defer rejecting.Close()
+	assert.NoError(t, registry.Set(&Config{Validators: []*Validator{
+		{Name: "batfish", URL: allowing.URL},
+		{Name: "routes", URL: rejecting.URL, Devices: []string{"spine-*"}},
+	}}))
+
+	// A validator is only asked about its devices
+	assert.NoError(t, registry.Validate(context.Background(), newProposal("leaf-1")))
+	assert.Len(t, rejectedAsked, 0)
+
+	err := registry.Validate(context.Background(), newProposal("leaf-1", "spine-1"))
+	assert.True(t, errors.IsInvalid(err), "expected invalid, got %v", err)
+	assert.Contains(t, err.Error(), "black-holes 10.0.0.0/8")
+	assert.Len(t, rejectedAsked, 1)
+	assert.Len(t, rejectedAsked[0].Devices, 1)
+	assert.Equal(t, devicetype.ID("spine-1"), rejectedAsked[0].Devices[0].DeviceID)
+}
+
+func TestRegistry_ValidateFailurePolicy(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slow.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no snapshot", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	registry := NewRegistry()
+	assert.NoError(t, registry.Set(&Config{
+		Timeout: 50 * time.Millisecond,
+		Validators: []*Validator{
+			{Name: "slow", URL: slow.URL},
+		},
+	}))
+	err := registry.Validate(context.Background(), newProposal("leaf-1"))
+	assert.True(t, errors.IsUnavailable(err), "expected unavailable, got %v", err)
+
+	assert.NoError(t, registry.Set(&Config{Validators: []*Validator{
+		{Name: "failing", URL: failing.URL},
+	}}))
+	err = registry.Validate(context.Background(), newProposal("leaf-1"))
+	assert.True(t, errors.IsUnavailable(err), "expected unavailable, got %v", err)
+	assert.Contains(t, err.Error(), "no snapshot")
+
+	// Failing open lets the change through
+	assert.NoError(t, registry.Set(&Config{
+		Timeout:       50 * time.Millisecond,
+		FailurePolicy: FailOpen,
+		Validators: []*Validator{
+			{Name: "slow", URL: slow.URL},
+			{Name: "failing", URL: failing.URL},
+		},
+	}))
+	assert.NoError(t, registry.Validate(context.Background(), newProposal("leaf-1")))
+
+	// Unless a validator fails closed
+	assert.NoError(t, registry.Set(&Config{
+		FailurePolicy: FailOpen,
+		Validators: []*Validator{
+			{Name: "failing", URL: failing.URL, FailurePolicy: FailClosed},
+		},
+	}))
+	err = registry.Validate(context.Background(), newProposal("leaf-1"))
+	assert.True(t, errors.IsUnavailable(err), "expected unavailable, got %v", err)
+}
+
+func TestRegistry_Set(t *testing.T) {
+	registry := NewRegistry()
+	assert.True(t, errors.IsInvalid(registry.Set(&Config{FailurePolicy: "maybe"})))
+	assert.True(t, errors.IsInvalid(registry.Set(&Config{Validators: []*Validator{{URL: "http://batfish"}}})))
+	assert.True(t, errors.IsInvalid(registry.Set(&Config{Validators: []*Validator{{Name: "batfish", URL: "batfish:8080"}}})))
+	assert.True(t, errors.IsInvalid(registry.Set(&Config{Validators: []*Validator{
+		{Name: "batfish", URL: "http://batfish"},
+		{Name: "batfish", URL: "http://batfish"},
+	}})))
+	assert.True(t, errors.IsInvalid(registry.Set(&Config{Validators: []*Validator{
+		{Name: "batfish", URL: "http://batfish", Timeout: -time.Second},
+	}})))
+	assert.False(t, registry.Enabled())
+
+	assert.NoError(t, registry.Set(&Config{Timeout: time.Second, Validators: []*Validator{
+		{Name: "batfish", URL: "http://batfish"},
+		{Name: "lint", URL: "https://lint", Timeout: 2 * time.Second, FailurePolicy: FailOpen},
+	}}))
+	validators := registry.List()
+	assert.Len(t, validators, 2)
+	assert.Equal(t, time.Second, validators[0].Timeout)
+	assert.Equal(t, FailClosed, validators[0].FailurePolicy)
+	assert.Equal(t, 2*time.Second, validators[1].Timeout)
+	assert.Equal(t, FailOpen, validators[1].FailurePolicy)
+}
+
+func TestRegistry_Load(t *testing.T) {
+	dir, err := ioutil.TempDir("", "validators")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "validators.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`
+timeout: 5s
+failurePolicy: fail-open
+validators:
+  - name: batfish
+    url: http://batfish-gate:8080/validate
+    devices: [leaf-*]
+`), 0644))
+
+	registry := NewRegistry()
+	assert.NoError(t, registry.Load(path))
+	validators := registry.List()
+	assert.Len(t, validators, 1)
+	assert.Equal(t, "batfish", validators[0].Name)
+	assert.Equal(t, 5*time.Second, validators[0].Timeout)
+	assert.Equal(t, FailOpen, validators[0].FailurePolicy)
+	assert.Equal(t, []string{"leaf-*"}, validators[0].Devices)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("validators:\n  - name: batfish\n    endpoint: http://batfish\n"), 0644))
+	assert.True(t, errors.IsInvalid(registry.Load(path)))
+}